@@ -26,6 +26,8 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/agentlogstocloudwatch/cloudwatchlogspublisher"
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
 	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/crashdump"
+	"github.com/aws/amazon-ssm-agent/agent/fips"
 	"github.com/aws/amazon-ssm-agent/agent/framework/coremanager"
 	"github.com/aws/amazon-ssm-agent/agent/framework/coremodules"
 	"github.com/aws/amazon-ssm-agent/agent/health"
@@ -33,6 +35,7 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/ipc/messagebus"
 	"github.com/aws/amazon-ssm-agent/agent/log"
 	"github.com/aws/amazon-ssm-agent/agent/log/logger"
+	"github.com/aws/amazon-ssm-agent/agent/network"
 	"github.com/aws/amazon-ssm-agent/agent/rebooter"
 	"github.com/aws/amazon-ssm-agent/agent/session/utility"
 	"github.com/aws/amazon-ssm-agent/agent/ssm"
@@ -48,10 +51,15 @@ const (
 	fingerprintFlag         = "fingerprint"
 	similarityThresholdFlag = "similarityThreshold"
 	workerFlag              = "worker"
+	enrollmentEndpointFlag  = "enrollment-endpoint"
+	enrollmentCertFlag      = "enrollment-cert"
+	enrollmentKeyFlag       = "enrollment-key"
 )
 
 var (
 	activationCode, activationID, region     string
+	enrollmentEndpoint, enrollmentCert       string
+	enrollmentKey                            string
 	register, clear, force, fpFlag, isWorker bool
 	similarityThreshold                      int
 	registrationFile                         = filepath.Join(appconfig.DefaultDataStorePath, "registration")
@@ -65,6 +73,10 @@ func start(log log.T, shouldCheckHibernation bool) (ssmAgent agent.ISSMAgent, er
 		log.Debugf("appconfig could not be loaded - %v", err)
 		return nil, err
 	}
+	if err = fips.Check(log, config); err != nil {
+		return nil, err
+	}
+	network.ApplyNetworkStack(log, config)
 
 	selector := identity.NewRuntimeConfigIdentitySelector(log)
 	agentIdentity, err := identity.NewAgentIdentity(log, &config, selector)
@@ -183,12 +195,20 @@ func run(log log.T, shouldCheckHibernation bool) {
 		// recover in case the agent panics
 		// this should handle some kind of seg fault errors.
 		if msg := recover(); msg != nil {
+			stack := debug.Stack()
 			log.Errorf("SSM Agent Worker crashed with message %v!", msg)
-			log.Errorf("%s: %s", msg, debug.Stack())
+			log.Errorf("%s: %s", msg, stack)
+			crashdump.RecordPanic(log, appconfig.SSMAgentWorkerName, msg, stack)
 		}
 	}()
 
+	crashdump.CaptureStderr(log, appconfig.SSMAgentWorkerName)
+
 	log.Debugf("Current GoMaxProc value - %v", runtime.GOMAXPROCS(0))
+	if config, _ := appconfig.Config(false); config.Agent.MemoryLimitMB > 0 {
+		debug.SetMemoryLimit(int64(config.Agent.MemoryLimitMB) << 20)
+		log.Infof("Memory limit of ssm-agent-worker set to %v MB", config.Agent.MemoryLimitMB)
+	}
 	log.WriteEvent(logger.AgentTelemetryMessage, "", logger.AmazonAgentWorkerStartEvent)
 	// run ssm agent
 	agent, err := start(log, shouldCheckHibernation)
@@ -25,6 +25,7 @@ import (
 
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
 	logger "github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/managedInstances/enrollment"
 	"github.com/aws/amazon-ssm-agent/agent/managedInstances/fingerprint"
 	"github.com/aws/amazon-ssm-agent/agent/managedInstances/registration"
 	"github.com/aws/amazon-ssm-agent/agent/ssm/anonauth"
@@ -41,6 +42,11 @@ func parseFlags(log logger.T) {
 	flag.StringVar(&activationID, activationIDFlag, "", "")
 	flag.StringVar(&region, regionFlag, "", "")
 
+	// activation requested from an enrollment endpoint authenticating the device certificate
+	flag.StringVar(&enrollmentEndpoint, enrollmentEndpointFlag, "", "")
+	flag.StringVar(&enrollmentCert, enrollmentCertFlag, "", "")
+	flag.StringVar(&enrollmentKey, enrollmentKeyFlag, "", "")
+
 	// clear registration
 	flag.BoolVar(&clear, "clear", false, "")
 
@@ -79,12 +85,22 @@ func flagUsage() {
 	fmt.Fprintln(os.Stderr, "\t\t-id\tSSM activation ID    \t(REQUIRED)")
 	fmt.Fprintln(os.Stderr, "\t\t-code\tSSM activation code\t(REQUIRED)")
 	fmt.Fprintln(os.Stderr, "\t\t-region\tSSM region       \t(REQUIRED)")
+	fmt.Fprintln(os.Stderr, "\n\t\t-enrollment-endpoint\tHTTPS endpoint issuing the activation for the device certificate, replaces -id and -code")
+	fmt.Fprintln(os.Stderr, "\t\t-enrollment-cert\tPEM device certificate \t(defaults to the ClientCertificate in amazon-ssm-agent.json)")
+	fmt.Fprintln(os.Stderr, "\t\t-enrollment-key\tPEM device private key")
 	fmt.Fprintln(os.Stderr, "\n\t\t-clear\tClears the previously saved SSM registration")
 	fmt.Fprintln(os.Stderr, "\n\t-y\tAnswer yes for all questions")
 }
 
 // processRegistration handles flags related to the registration category
 func processRegistration(log logger.T) (exitCode int) {
+	if enrollmentEndpoint != "" && activationCode == "" && activationID == "" {
+		if err := requestEnrollmentActivation(log); err != nil {
+			log.Errorf("Registration failed due to %v", err)
+			return 1
+		}
+	}
+
 	if activationCode == "" || activationID == "" || region == "" {
 		// clear registration
 		if clear {
@@ -118,6 +134,26 @@ func processRegistration(log logger.T) (exitCode int) {
 	return 0
 }
 
+// requestEnrollmentActivation sets the activation credentials issued by the enrollment endpoint for the device certificate
+func requestEnrollmentActivation(log logger.T) error {
+	appConfig, err := appconfig.Config(true)
+	if err != nil {
+		log.Warnf("Failed to load the agent configuration, using the defaults: %v", err)
+	}
+	activation, err := enrollment.RequestActivation(log, appConfig, enrollment.Config{
+		Endpoint:        enrollmentEndpoint,
+		CertificateFile: enrollmentCert,
+		KeyFile:         enrollmentKey,
+		Region:          region,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get an activation from the enrollment endpoint: %v", err)
+	}
+	log.Infof("Received activation %v for region %v from the enrollment endpoint", activation.ActivationId, activation.Region)
+	activationCode, activationID, region = activation.ActivationCode, activation.ActivationId, activation.Region
+	return nil
+}
+
 // processFingerprint handles flags related to the fingerprint category
 func processFingerprint(log logger.T) (exitCode int) {
 	if err := fingerprint.SetSimilarityThreshold(log, similarityThreshold); err != nil {
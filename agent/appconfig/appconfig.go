@@ -19,6 +19,7 @@ import (
 	"log"
 	"os"
 	"runtime"
+	"strings"
 	"sync"
 
 	"github.com/aws/amazon-ssm-agent/agent/jsonutil"
@@ -53,6 +54,19 @@ func Config(reload bool) (SsmagentConfig, error) {
 			fmt.Println("Failed to unmarshal config override. Fall back to default.")
 			return agentConfig, err
 		}
+		if profile := strings.TrimSpace(agentConfig.Agent.ConfigurationProfile); profile != "" {
+			// the preset replaces the defaults, so the settings in the file still take precedence over it
+			presetConfig := DefaultConfig()
+			presetConfig.Os.Name = runtime.GOOS
+			presetConfig.Agent.Version = version.Version
+			if applyConfigurationProfile(&presetConfig, profile) {
+				if err := jsonutil.UnmarshalFile(path, &presetConfig); err != nil {
+					fmt.Println("Failed to unmarshal config override. Fall back to default.")
+					return presetConfig, err
+				}
+				agentConfig = presetConfig
+			}
+		}
 		parser(&agentConfig)
 		cache(agentConfig)
 	}
@@ -101,12 +115,25 @@ func DefaultConfig() SsmagentConfig {
 		StopTimeoutMillis:        DefaultStopTimeoutMillis,
 		CommandRetryLimit:        DefaultCommandRetryLimit,
 		CommandWorkerBufferLimit: DefaultCommandWorkerBufferLimit,
+
+		IdlePollBackoffAfterMinutes: DefaultMdsIdlePollBackoffAfterMinutes,
+		MaxIdlePollDelaySeconds:     DefaultMdsMaxIdlePollDelaySeconds,
+		QuietHours:                  []*MdsQuietHoursCfg{},
 	}
 	var mgs = MgsConfig{
 		SessionWorkersLimit:           DefaultSessionWorkersLimit,
 		StopTimeoutMillis:             DefaultStopTimeoutMillis,
 		SessionWorkerBufferLimit:      DefaultSessionWorkerBufferLimit,
 		DeniedPortForwardingRemoteIPs: DefaultDeniedPortForwardingRemoteIPs,
+		WebSocketCompressionLevel:     DefaultMgsWebSocketCompressionLevel,
+		IsolatedUsers: IsolatedUsersCfg{
+			UserPrefix: DefaultIsolatedUserPrefix,
+			PoolSize:   DefaultIsolatedUserPoolSize,
+			FirstUID:   DefaultIsolatedUserFirstUID,
+		},
+		SessionChallenge: SessionChallengeCfg{
+			TimeoutSeconds: DefaultSessionChallengeTimeoutSeconds,
+		},
 	}
 	var ssm = SsmCfg{
 		HealthFrequencyMinutes:                DefaultSsmHealthFrequencyMinutes,
@@ -119,6 +146,10 @@ func DefaultConfig() SsmagentConfig {
 		SessionLogsDestination:                SessionLogsDestinationNone,
 		PluginLocalOutputCleanup:              DefaultPluginOutputRetention,
 		OrchestrationDirectoryCleanup:         DefaultOrchestrationDirCleanup,
+		AssociationScheduleJitterSeconds:      DefaultSsmAssociationScheduleJitterSeconds,
+		AssociationBlackoutWindows:            []*DailyTimeWindowCfg{},
+		AssociationDetectOnly:                 []string{},
+		PluginOutputStripAnsi:                 false,
 	}
 	var agent = AgentInfo{
 		Name:                                    "amazon-ssm-agent",
@@ -133,6 +164,12 @@ func DefaultConfig() SsmagentConfig {
 		ShouldPurgeInstanceProfileRoleCreds:     false,
 		ForceFileIPC:                            false,
 		GoMaxProcForAgentWorker:                 0,
+		ComponentLogLevels:                      map[string]string{},
+		ArtifactDownloadConcurrencyLimit:        DefaultArtifactDownloadConcurrencyLimit,
+		NetworkStack:                            NetworkStackIPv4,
+		LogRedaction:                            true,
+		LogRedactionPatterns:                    []string{},
+		UpdateHealthCheckTimeoutSeconds:         DefaultUpdateHealthCheckTimeoutSeconds,
 	}
 
 	var os = OsInfo{
@@ -140,25 +177,177 @@ func DefaultConfig() SsmagentConfig {
 		Version: "1",
 	}
 	var identity = IdentityCfg{
-		ConsumptionOrder: DefaultIdentityConsumptionOrder,
-		CustomIdentities: []*CustomIdentity{},
+		ConsumptionOrder:                 DefaultIdentityConsumptionOrder,
+		CustomIdentities:                 []*CustomIdentity{},
+		ScopedCredentialsDurationSeconds: DefaultScopedCredentialsDurationSeconds,
+		RegionFailover: RegionFailoverCfg{
+			Regions:                    []string{},
+			HealthCheckIntervalSeconds: DefaultRegionFailoverHealthCheckIntervalSeconds,
+			FailureThreshold:           DefaultRegionFailoverThreshold,
+		},
 	}
 	var birdwatcher BirdwatcherCfg
 	var kms = KmsConfig{
 		RequireKMSChallengeResponse: DefaultRequireKMSChallengeResponse,
 	}
 
+	var inventory = InventoryCfg{
+		LanguagePackageManagers:  []string{},
+		ScriptGatherers:          []*ScriptGathererCfg{},
+		DeltaReporting:           false,
+		FullRefreshIntervalHours: DefaultInventoryFullRefreshIntervalHours,
+	}
+
+	var metrics = MetricsCfg{
+		Enabled:                 false,
+		Port:                    DefaultMetricsPort,
+		TextfilePath:            "",
+		TextfileIntervalSeconds: DefaultMetricsTextfileIntervalSeconds,
+		JSONFilePath:            "",
+		JSONFileIntervalSeconds: DefaultMetricsJSONFileIntervalSeconds,
+		JSONFileMaxSizeMB:       DefaultMetricsJSONFileMaxSizeMB,
+		JSONFileMaxBackups:      DefaultMetricsJSONFileMaxBackups,
+	}
+
+	var tracing = TracingCfg{
+		Enabled:               false,
+		OtlpEndpoint:          DefaultTracingOtlpEndpoint,
+		OtlpHeaders:           map[string]string{},
+		ServiceName:           DefaultTracingServiceName,
+		ExportIntervalSeconds: DefaultTracingExportIntervalSeconds,
+	}
+
+	var workerLimits = WorkerLimitsCfg{
+		DocumentWorkerCpuPercent: 0,
+		DocumentWorkerMemoryMB:   0,
+		SessionWorkerCpuPercent:  0,
+		SessionWorkerMemoryMB:    0,
+	}
+
+	var workerSandbox = WorkerSandboxCfg{
+		Enabled:                  false,
+		Seccomp:                  true,
+		AppArmor:                 true,
+		SELinuxType:              "",
+		AdditionalDeniedSyscalls: []string{},
+	}
+
+	var crashDump = CrashDumpCfg{
+		Enabled:            false,
+		PanicThreshold:     DefaultCrashDumpPanicThreshold,
+		PanicWindowMinutes: DefaultCrashDumpPanicWindowMinutes,
+		MaxDumps:           DefaultCrashDumpMaxDumps,
+		S3BucketName:       "",
+		S3KeyPrefix:        "",
+	}
+
+	var storeAndForward = StoreAndForwardCfg{
+		Enabled:              false,
+		MaxAgeHours:          DefaultStoreAndForwardMaxAgeHours,
+		MaxSizeMB:            DefaultStoreAndForwardMaxSizeMB,
+		DrainIntervalSeconds: DefaultStoreAndForwardDrainIntervalSeconds,
+	}
+
+	var auditLog = AuditLogCfg{
+		Enabled:       false,
+		MaxFileSizeMB: DefaultAuditLogMaxFileSizeMB,
+		MaxFiles:      DefaultAuditLogMaxFiles,
+		S3BucketName:  "",
+		S3KeyPrefix:   "",
+	}
+
+	var pluginPolicy = PluginPolicyCfg{
+		AllowedPlugins: []string{},
+		DeniedPlugins:  []string{},
+	}
+
+	var proxy = ProxyCfg{
+		AutoConfigURL:            "",
+		AutoConfigRefreshMinutes: DefaultProxyAutoConfigRefreshMinutes,
+		AuthScheme:               ProxyAuthSchemeBasic,
+	}
+
+	var clientCertificate = ClientCertificateCfg{
+		CertificateFile:  "",
+		KeyFile:          "",
+		CertificateStore: "",
+	}
+
+	var clockSkew = ClockSkewCfg{
+		WarnThresholdSeconds: DefaultClockSkewWarnThresholdSeconds,
+		CorrectSigning:       false,
+	}
+
+	var fips = FipsCfg{
+		Enabled:             DefaultFipsEnabled,
+		FailOnNonCompliance: true,
+	}
+
+	var shutdown = ShutdownCfg{
+		DrainPeriodSeconds: DefaultShutdownDrainPeriodSeconds,
+	}
+
+	var diskSpace = DiskSpaceCfg{
+		MinimumFreeMegabytes:          DefaultDiskSpaceMinimumFreeMegabytes,
+		OrchestrationMaxAgeHours:      DefaultDiskSpaceOrchestrationMaxAgeHours,
+		OrchestrationMaxSizeMegabytes: DefaultDiskSpaceOrchestrationMaxSizeMegabytes,
+	}
+
+	var customAttributes = CustomAttributesCfg{
+		Attributes:           map[string]string{},
+		ScriptTimeoutSeconds: DefaultCustomAttributesScriptTimeoutSeconds,
+	}
+
+	var eventLogForwarding = EventLogForwardingCfg{
+		Channels:            []*EventLogChannelCfg{},
+		LogGroupName:        DefaultEventLogForwardingLogGroupName,
+		PollIntervalSeconds: DefaultEventLogForwardingPollIntervalSeconds,
+	}
+
+	var reachability = ReachabilityCfg{
+		Enabled:               false,
+		ProbeIntervalSeconds:  DefaultReachabilityProbeIntervalSeconds,
+		ProbeTimeoutSeconds:   DefaultReachabilityProbeTimeoutSeconds,
+		ReportIntervalMinutes: DefaultReachabilityReportIntervalMinutes,
+	}
+
+	var logging = LoggingCfg{
+		Level:         DefaultLoggingLevel,
+		MaxFileSizeMB: DefaultLoggingMaxFileSizeMB,
+		MaxRolls:      DefaultLoggingMaxRolls,
+		Format:        LoggingFormatText,
+	}
+
 	var ssmagentCfg = SsmagentConfig{
-		Profile:     credsProfile,
-		Mds:         mds,
-		Ssm:         ssm,
-		Mgs:         mgs,
-		Agent:       agent,
-		Os:          os,
-		S3:          s3,
-		Birdwatcher: birdwatcher,
-		Kms:         kms,
-		Identity:    identity,
+		Profile:            credsProfile,
+		Mds:                mds,
+		Ssm:                ssm,
+		Mgs:                mgs,
+		Agent:              agent,
+		Os:                 os,
+		S3:                 s3,
+		Birdwatcher:        birdwatcher,
+		Kms:                kms,
+		Identity:           identity,
+		Inventory:          inventory,
+		Metrics:            metrics,
+		Tracing:            tracing,
+		WorkerLimits:       workerLimits,
+		WorkerSandbox:      workerSandbox,
+		CrashDump:          crashDump,
+		StoreAndForward:    storeAndForward,
+		AuditLog:           auditLog,
+		PluginPolicy:       pluginPolicy,
+		Proxy:              proxy,
+		ClientCertificate:  clientCertificate,
+		ClockSkew:          clockSkew,
+		Fips:               fips,
+		Shutdown:           shutdown,
+		DiskSpace:          diskSpace,
+		CustomAttributes:   customAttributes,
+		EventLogForwarding: eventLogForwarding,
+		Reachability:       reachability,
+		Logging:            logging,
 	}
 
 	return ssmagentCfg
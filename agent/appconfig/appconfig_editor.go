@@ -0,0 +1,256 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package appconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/aws/amazon-ssm-agent/agent/jsonutil"
+)
+
+// ConfigBackupSuffix is appended to the configuration file name for the copy kept before it is edited
+const ConfigBackupSuffix = ".bak"
+
+// LoadConfigFile loads the configuration file at path the way the agent does, without caching it.
+// A missing file yields the default configuration.
+func LoadConfigFile(path string) (SsmagentConfig, error) {
+	config := DefaultConfig()
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return config, nil
+	}
+	if err := jsonutil.UnmarshalFile(path, &config); err != nil {
+		return config, err
+	}
+	parser(&config)
+	return config, nil
+}
+
+// ConfigValue returns the value of key, the dot separated field names of the setting such as Agent.Region
+func ConfigValue(config SsmagentConfig, key string) (interface{}, error) {
+	value := reflect.ValueOf(config)
+	for _, name := range strings.Split(key, ".") {
+		if value.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("unknown configuration key %v", key)
+		}
+		field, found := configField(value.Type(), name)
+		if !found {
+			return nil, fmt.Errorf("unknown configuration key %v", key)
+		}
+		value = value.FieldByIndex(field.Index)
+	}
+	return value.Interface(), nil
+}
+
+// SetConfigFileValue validates value for key and writes it to the configuration file at path.
+// The value is rejected when it does not match the type of the setting or when the agent would
+// replace it by its default. Other settings of the file are preserved, the previous file is kept
+// with the ConfigBackupSuffix and the new content replaces the file atomically.
+func SetConfigFileValue(path, key, value string) (backupPath string, err error) {
+	names, fieldType, err := resolveConfigKey(key)
+	if err != nil {
+		return "", err
+	}
+	typedValue, err := parseConfigValue(fieldType, value)
+	if err != nil {
+		return "", fmt.Errorf("invalid value for %v: %v", strings.Join(names, "."), err)
+	}
+
+	content := map[string]interface{}{}
+	var original []byte
+	if original, err = os.ReadFile(path); err == nil {
+		if err = json.Unmarshal(original, &content); err != nil {
+			return "", fmt.Errorf("failed to parse %v: %v", path, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+	setConfigMapValue(content, names, typedValue)
+
+	updated, err := json.MarshalIndent(content, "", "    ")
+	if err != nil {
+		return "", err
+	}
+	if err = validateConfigValue(updated, names, typedValue); err != nil {
+		return "", err
+	}
+
+	if original != nil {
+		backupPath = path + ConfigBackupSuffix
+		if err = os.WriteFile(backupPath, original, ReadWriteAccess); err != nil {
+			return "", fmt.Errorf("failed to back up %v: %v", path, err)
+		}
+	}
+	if err = writeFileAtomic(path, append(updated, '\n')); err != nil {
+		return backupPath, err
+	}
+	return backupPath, nil
+}
+
+// configField finds the field of a configuration struct by name, ignoring case like the json decoder
+func configField(structType reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.IsExported() && strings.EqualFold(field.Name, name) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// resolveConfigKey returns the canonical field names of key and the type of the setting
+func resolveConfigKey(key string) (names []string, fieldType reflect.Type, err error) {
+	fieldType = reflect.TypeOf(SsmagentConfig{})
+	for _, name := range strings.Split(key, ".") {
+		if fieldType.Kind() != reflect.Struct {
+			return nil, nil, fmt.Errorf("unknown configuration key %v", key)
+		}
+		field, found := configField(fieldType, name)
+		if !found {
+			return nil, nil, fmt.Errorf("unknown configuration key %v", key)
+		}
+		names = append(names, field.Name)
+		fieldType = field.Type
+	}
+	return names, fieldType, nil
+}
+
+// parseConfigValue converts the command line value to the type of the setting.
+// Lists, maps and sections are given as JSON, lists of strings may also be comma separated.
+func parseConfigValue(fieldType reflect.Type, value string) (interface{}, error) {
+	switch fieldType.Kind() {
+	case reflect.String:
+		return value, nil
+	case reflect.Bool:
+		return strconv.ParseBool(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.ParseInt(value, 10, fieldType.Bits())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseUint(value, 10, fieldType.Bits())
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(value, fieldType.Bits())
+	}
+
+	if fieldType.Kind() == reflect.Slice && fieldType.Elem().Kind() == reflect.String && !strings.HasPrefix(strings.TrimSpace(value), "[") {
+		list := []string{}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		return list, nil
+	}
+
+	typed := reflect.New(fieldType)
+	decoder := json.NewDecoder(strings.NewReader(value))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(typed.Interface()); err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	if err := json.Unmarshal([]byte(value), &generic); err != nil {
+		return nil, err
+	}
+	return generic, nil
+}
+
+// setConfigMapValue sets the value in the decoded configuration file, reusing the spelling of existing keys
+func setConfigMapValue(content map[string]interface{}, names []string, value interface{}) {
+	section := content
+	for i, name := range names {
+		key := name
+		for existing := range section {
+			if strings.EqualFold(existing, name) {
+				key = existing
+				break
+			}
+		}
+		if i == len(names)-1 {
+			section[key] = value
+			return
+		}
+		next, ok := section[key].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			section[key] = next
+		}
+		section = next
+	}
+}
+
+// validateConfigValue checks that the updated file decodes into the configuration
+// and that the agent keeps the value instead of replacing it by its default
+func validateConfigValue(updated []byte, names []string, value interface{}) error {
+	key := strings.Join(names, ".")
+	config := DefaultConfig()
+	if err := json.Unmarshal(updated, &config); err != nil {
+		return fmt.Errorf("invalid value for %v: %v", key, err)
+	}
+	requested, err := ConfigValue(config, key)
+	if err != nil {
+		return err
+	}
+	parser(&config)
+	effective, err := ConfigValue(config, key)
+	if err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(requested, effective) {
+		effectiveJson, _ := json.Marshal(effective)
+		return fmt.Errorf("value %v is not valid for %v, the agent would use %s", value, key, effectiveJson)
+	}
+	return nil
+}
+
+// writeFileAtomic replaces the file at path through a temporary file in the same directory,
+// so the agent never reads a partially written configuration
+func writeFileAtomic(path string, content []byte) (err error) {
+	dir := filepath.Dir(path)
+	if err = os.MkdirAll(dir, ReadWriteExecuteAccess); err != nil {
+		return err
+	}
+	temp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(temp.Name())
+		}
+	}()
+
+	if _, err = temp.Write(content); err == nil {
+		err = temp.Sync()
+	}
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	mode := os.FileMode(ReadWriteAccess)
+	if info, statErr := os.Stat(path); statErr == nil {
+		mode = info.Mode().Perm()
+	}
+	if err = os.Chmod(temp.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), path)
+}
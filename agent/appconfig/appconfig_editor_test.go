@@ -0,0 +1,119 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package appconfig
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readConfigFileContent(t *testing.T, path string) map[string]interface{} {
+	content := map[string]interface{}{}
+	raw, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(raw, &content))
+	return content
+}
+
+func TestConfigValue(t *testing.T) {
+	config := DefaultConfig()
+	config.Agent.Region = "us-east-1"
+
+	value, err := ConfigValue(config, "agent.region")
+	assert.NoError(t, err)
+	assert.Equal(t, "us-east-1", value)
+
+	value, err = ConfigValue(config, "Shutdown")
+	assert.NoError(t, err)
+	assert.Equal(t, config.Shutdown, value)
+
+	_, err = ConfigValue(config, "Agent.Unknown")
+	assert.Error(t, err)
+	_, err = ConfigValue(config, "Agent.Region.Name")
+	assert.Error(t, err)
+}
+
+func TestSetConfigFileValue_PreservesOtherSettingsAndBacksUp(t *testing.T) {
+	path := filepath.Join(t.TempDir(), AppConfigFileName)
+	original := `{"agent": {"Region": "us-west-2", "Unknown": "kept"}, "Mds": {"CommandWorkersLimit": 3}}`
+	assert.NoError(t, os.WriteFile(path, []byte(original), 0600))
+
+	backupPath, err := SetConfigFileValue(path, "Agent.Region", "eu-west-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, path+ConfigBackupSuffix, backupPath)
+	backup, _ := os.ReadFile(backupPath)
+	assert.Equal(t, original, string(backup))
+
+	content := readConfigFileContent(t, path)
+	agent := content["agent"].(map[string]interface{})
+	assert.Equal(t, "eu-west-1", agent["Region"])
+	assert.Equal(t, "kept", agent["Unknown"])
+	assert.Equal(t, float64(3), content["Mds"].(map[string]interface{})["CommandWorkersLimit"])
+	assert.NotContains(t, content, "Agent")
+}
+
+func TestSetConfigFileValue_CreatesMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), AppConfigFileName)
+
+	backupPath, err := SetConfigFileValue(path, "shutdown.drainperiodseconds", "120")
+
+	assert.NoError(t, err)
+	assert.Empty(t, backupPath)
+	config, err := LoadConfigFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 120, config.Shutdown.DrainPeriodSeconds)
+}
+
+func TestSetConfigFileValue_ParsesTypes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), AppConfigFileName)
+
+	_, err := SetConfigFileValue(path, "CustomAttributes.Attributes", `{"team": "payments"}`)
+	assert.NoError(t, err)
+	_, err = SetConfigFileValue(path, "Agent.ContainerMode", "true")
+	assert.NoError(t, err)
+
+	config, err := LoadConfigFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "payments"}, config.CustomAttributes.Attributes)
+	assert.True(t, config.Agent.ContainerMode)
+}
+
+func TestSetConfigFileValue_RejectsInvalidValues(t *testing.T) {
+	path := filepath.Join(t.TempDir(), AppConfigFileName)
+	original := `{"Shutdown": {"DrainPeriodSeconds": 60}}`
+	assert.NoError(t, os.WriteFile(path, []byte(original), 0600))
+
+	for key, value := range map[string]string{
+		"Agent.Unknown":                  "value",
+		"Shutdown.DrainPeriodSeconds":    "ten",
+		"Shutdown":                       `{"Unknown": 1}`,
+		"CustomAttributes.ScriptTimeout": "10",
+	} {
+		_, err := SetConfigFileValue(path, key, value)
+		assert.Error(t, err, key)
+	}
+
+	// out of range values would be replaced by the default when the agent loads the file
+	_, err := SetConfigFileValue(path, "Shutdown.DrainPeriodSeconds", "100000")
+	assert.Error(t, err)
+
+	content, _ := os.ReadFile(path)
+	assert.Equal(t, original, string(content))
+	assert.NoFileExists(t, path+ConfigBackupSuffix)
+}
@@ -17,9 +17,17 @@ package appconfig
 
 import (
 	"log"
+	"regexp"
 	"runtime"
+	"strings"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/times"
 )
 
+// isolatedUserPrefixPattern keeps the pool user names, the prefix followed by two digits, valid Linux user names
+var isolatedUserPrefixPattern = regexp.MustCompile("^[a-z_][a-z0-9_-]{0,29}$")
+
 // func parser(config *T) {
 func parser(config *SsmagentConfig) {
 	log.Printf("processing appconfig overrides")
@@ -63,6 +71,51 @@ func parser(config *SsmagentConfig) {
 		DefaultAuditExpirationDayMax,
 		DefaultAuditExpirationDay)
 
+	LogComponentOptions := map[string]bool{
+		LogComponentMessageService: true,
+		LogComponentSession:        true,
+		LogComponentUpdater:        true,
+		LogComponentIdentity:       true,
+	}
+	LogLevelOptions := map[string]bool{
+		"trace":    true,
+		"debug":    true,
+		"info":     true,
+		"warn":     true,
+		"error":    true,
+		"critical": true,
+	}
+	config.Agent.ComponentLogLevels = getValidComponentLogLevels(config.Agent.ComponentLogLevels, LogComponentOptions, LogLevelOptions)
+	config.Agent.LogRedactionPatterns = getValidRegexps(config.Agent.LogRedactionPatterns)
+	config.Agent.ConfigurationProfile = getStringEnum(
+		strings.TrimSpace(config.Agent.ConfigurationProfile),
+		[]string{"", ConfigurationProfileMinimal, ConfigurationProfileInteractiveOnly, ConfigurationProfileBatchWorker},
+		"")
+	config.Agent.NetworkStack = getStringEnum(
+		strings.ToLower(strings.TrimSpace(config.Agent.NetworkStack)),
+		[]string{NetworkStackIPv4, NetworkStackDualStack, NetworkStackIPv6},
+		NetworkStackIPv4)
+	config.Agent.ResourceProfile = getStringEnum(strings.TrimSpace(config.Agent.ResourceProfile), []string{"", ResourceProfileLowMemory}, "")
+	config.Agent.MemoryLimitMB = getNumericValue(
+		config.Agent.MemoryLimitMB,
+		DefaultMemoryLimitMBMin,
+		DefaultMemoryLimitMBMax,
+		0)
+	config.Agent.ArtifactDownloadConcurrencyLimit = getNumericValue(
+		config.Agent.ArtifactDownloadConcurrencyLimit,
+		DefaultArtifactDownloadConcurrencyLimitMin,
+		DefaultArtifactDownloadConcurrencyLimitMax,
+		DefaultArtifactDownloadConcurrencyLimit)
+	config.Agent.UpdateHealthCheckTimeoutSeconds = getNumericValue(
+		config.Agent.UpdateHealthCheckTimeoutSeconds,
+		DefaultUpdateHealthCheckTimeoutSecondsMin,
+		DefaultUpdateHealthCheckTimeoutSecondsMax,
+		DefaultUpdateHealthCheckTimeoutSeconds)
+	config.Agent.FingerprintHardwareSource = getStringEnum(
+		strings.TrimSpace(config.Agent.FingerprintHardwareSource),
+		[]string{"", FingerprintHardwareSourceWQL, FingerprintHardwareSourceRegistry, FingerprintHardwareSourceWMIC},
+		"")
+
 	// MDS config
 	config.Mds.CommandWorkersLimit = getNumericValue(
 		config.Mds.CommandWorkersLimit,
@@ -81,6 +134,29 @@ func parser(config *SsmagentConfig) {
 		DefaultSessionWorkersBufferLimitMin,
 		config.Mgs.SessionWorkerBufferLimit, // we do not restrict max number of worker buffer limit here
 		DefaultSessionWorkerBufferLimit)
+	config.Mgs.WebSocketCompressionLevel = getNumericValue(
+		config.Mgs.WebSocketCompressionLevel,
+		DefaultMgsWebSocketCompressionLevelMin,
+		DefaultMgsWebSocketCompressionLevelMax,
+		DefaultMgsWebSocketCompressionLevel)
+	if !isolatedUserPrefixPattern.MatchString(config.Mgs.IsolatedUsers.UserPrefix) {
+		config.Mgs.IsolatedUsers.UserPrefix = DefaultIsolatedUserPrefix
+	}
+	config.Mgs.IsolatedUsers.PoolSize = getNumericValue(
+		config.Mgs.IsolatedUsers.PoolSize,
+		DefaultIsolatedUserPoolSizeMin,
+		DefaultIsolatedUserPoolSizeMax,
+		DefaultIsolatedUserPoolSize)
+	config.Mgs.IsolatedUsers.FirstUID = getNumericValue(
+		config.Mgs.IsolatedUsers.FirstUID,
+		DefaultIsolatedUserFirstUIDMin,
+		DefaultIsolatedUserFirstUIDMax,
+		DefaultIsolatedUserFirstUID)
+	config.Mgs.SessionChallenge.TimeoutSeconds = getNumericValue(
+		config.Mgs.SessionChallenge.TimeoutSeconds,
+		DefaultSessionChallengeTimeoutSecondsMin,
+		DefaultSessionChallengeTimeoutSecondsMax,
+		DefaultSessionChallengeTimeoutSeconds)
 
 	config.Mds.CommandRetryLimit = getNumericValue(
 		config.Mds.CommandRetryLimit,
@@ -93,6 +169,17 @@ func parser(config *SsmagentConfig) {
 		DefaultStopTimeoutMillisMax,
 		DefaultStopTimeoutMillis)
 	config.Mds.Endpoint = getStringValue(config.Mds.Endpoint, "")
+	config.Mds.IdlePollBackoffAfterMinutes = getNumericValue(
+		config.Mds.IdlePollBackoffAfterMinutes,
+		DefaultMdsIdlePollBackoffAfterMinutesMin,
+		DefaultMdsIdlePollBackoffAfterMinutesMax,
+		DefaultMdsIdlePollBackoffAfterMinutes)
+	config.Mds.MaxIdlePollDelaySeconds = getNumericValue(
+		config.Mds.MaxIdlePollDelaySeconds,
+		DefaultMdsMaxIdlePollDelaySecondsMin,
+		DefaultMdsMaxIdlePollDelaySecondsMax,
+		DefaultMdsMaxIdlePollDelaySeconds)
+	config.Mds.QuietHours = getValidQuietHours(config.Mds.QuietHours)
 
 	// SSM config
 	config.Ssm.Endpoint = getStringValue(config.Ssm.Endpoint, "")
@@ -106,6 +193,13 @@ func parser(config *SsmagentConfig) {
 		DefaultSsmAssociationFrequencyMinutesMin,
 		DefaultSsmAssociationFrequencyMinutesMax,
 		DefaultSsmAssociationFrequencyMinutes)
+	config.Ssm.AssociationScheduleJitterSeconds = getNumericValue(
+		config.Ssm.AssociationScheduleJitterSeconds,
+		DefaultSsmAssociationScheduleJitterSecondsMin,
+		DefaultSsmAssociationScheduleJitterSecondsMax,
+		DefaultSsmAssociationScheduleJitterSeconds)
+	config.Ssm.AssociationBlackoutWindows = getValidDailyTimeWindows(config.Ssm.AssociationBlackoutWindows)
+	config.Ssm.AssociationDetectOnly = getTrimmedStringList(config.Ssm.AssociationDetectOnly)
 	config.Ssm.AssociationLogsRetentionDurationHours = getNumericValueAboveMin(
 		config.Ssm.AssociationLogsRetentionDurationHours,
 		DefaultStateOrchestrationLogsRetentionDurationHoursMin,
@@ -151,6 +245,346 @@ func parser(config *SsmagentConfig) {
 	for _, customIdentity := range config.Identity.CustomIdentities {
 		customIdentity.CredentialsProvider = getStringEnumMap(customIdentity.CredentialsProvider, CredentialsProviderOptions, DefaultCustomIdentityCredentialsProvider)
 	}
+	config.Identity.ResourceAccessRoleArn = strings.TrimSpace(config.Identity.ResourceAccessRoleArn)
+	config.Identity.ResourceAccessRoleExternalId = strings.TrimSpace(config.Identity.ResourceAccessRoleExternalId)
+	config.Identity.ScopedCredentialsRoleArn = strings.TrimSpace(config.Identity.ScopedCredentialsRoleArn)
+	config.Identity.ScopedCredentialsDurationSeconds = getNumericValue(
+		config.Identity.ScopedCredentialsDurationSeconds,
+		DefaultScopedCredentialsDurationSecondsMin,
+		DefaultScopedCredentialsDurationSecondsMax,
+		DefaultScopedCredentialsDurationSeconds)
+	config.Identity.RegionFailover.Regions = getTrimmedStringList(config.Identity.RegionFailover.Regions)
+	config.Identity.RegionFailover.HealthCheckIntervalSeconds = getNumericValue(
+		config.Identity.RegionFailover.HealthCheckIntervalSeconds,
+		DefaultRegionFailoverHealthCheckIntervalSecondsMin,
+		DefaultRegionFailoverHealthCheckIntervalSecondsMax,
+		DefaultRegionFailoverHealthCheckIntervalSeconds)
+	config.Identity.RegionFailover.FailureThreshold = getNumericValue(
+		config.Identity.RegionFailover.FailureThreshold,
+		DefaultRegionFailoverThresholdMin,
+		DefaultRegionFailoverThresholdMax,
+		DefaultRegionFailoverThreshold)
+
+	// Inventory config
+	LanguagePackageManagerOptions := map[string]bool{
+		LanguagePackageManagerPip: true,
+		LanguagePackageManagerNpm: true,
+		LanguagePackageManagerGem: true,
+	}
+	config.Inventory.LanguagePackageManagers = getStringListEnum(
+		config.Inventory.LanguagePackageManagers,
+		LanguagePackageManagerOptions,
+		[]string{})
+	config.Inventory.FullRefreshIntervalHours = getNumericValue(
+		config.Inventory.FullRefreshIntervalHours,
+		DefaultInventoryFullRefreshIntervalHoursMin,
+		DefaultInventoryFullRefreshIntervalHoursMax,
+		DefaultInventoryFullRefreshIntervalHours)
+	for _, scriptGatherer := range config.Inventory.ScriptGatherers {
+		scriptGatherer.TimeoutSeconds = getNumericValue(
+			scriptGatherer.TimeoutSeconds,
+			DefaultScriptGathererTimeoutSecondsMin,
+			DefaultScriptGathererTimeoutSecondsMax,
+			DefaultScriptGathererTimeoutSeconds)
+		scriptGatherer.MaxOutputSizeKB = getNumericValue(
+			scriptGatherer.MaxOutputSizeKB,
+			DefaultScriptGathererMaxOutputSizeKBMin,
+			DefaultScriptGathererMaxOutputSizeKBMax,
+			DefaultScriptGathererMaxOutputSizeKB)
+	}
+
+	// Metrics config
+	config.Metrics.Port = getNumericValue(
+		config.Metrics.Port,
+		DefaultMetricsPortMin,
+		DefaultMetricsPortMax,
+		DefaultMetricsPort)
+	config.Metrics.TextfileIntervalSeconds = getNumericValue(
+		config.Metrics.TextfileIntervalSeconds,
+		DefaultMetricsTextfileIntervalSecondsMin,
+		DefaultMetricsTextfileIntervalSecondsMax,
+		DefaultMetricsTextfileIntervalSeconds)
+	config.Metrics.JSONFileIntervalSeconds = getNumericValue(
+		config.Metrics.JSONFileIntervalSeconds,
+		DefaultMetricsJSONFileIntervalSecondsMin,
+		DefaultMetricsJSONFileIntervalSecondsMax,
+		DefaultMetricsJSONFileIntervalSeconds)
+	config.Metrics.JSONFileMaxSizeMB = getNumericValue(
+		config.Metrics.JSONFileMaxSizeMB,
+		DefaultMetricsJSONFileMaxSizeMBMin,
+		DefaultMetricsJSONFileMaxSizeMBMax,
+		DefaultMetricsJSONFileMaxSizeMB)
+	config.Metrics.JSONFileMaxBackups = getNumericValue(
+		config.Metrics.JSONFileMaxBackups,
+		DefaultMetricsJSONFileMaxBackupsMin,
+		DefaultMetricsJSONFileMaxBackupsMax,
+		DefaultMetricsJSONFileMaxBackups)
+
+	// Tracing config
+	config.Tracing.OtlpEndpoint = getStringValue(config.Tracing.OtlpEndpoint, DefaultTracingOtlpEndpoint)
+	config.Tracing.ServiceName = getStringValue(config.Tracing.ServiceName, DefaultTracingServiceName)
+	config.Tracing.ExportIntervalSeconds = getNumericValue(
+		config.Tracing.ExportIntervalSeconds,
+		DefaultTracingExportIntervalSecondsMin,
+		DefaultTracingExportIntervalSecondsMax,
+		DefaultTracingExportIntervalSeconds)
+
+	// Worker limits config
+	config.WorkerLimits.DocumentWorkerCpuPercent = getNumericValue(config.WorkerLimits.DocumentWorkerCpuPercent, 0, WorkerCpuPercentMax, 0)
+	config.WorkerLimits.SessionWorkerCpuPercent = getNumericValue(config.WorkerLimits.SessionWorkerCpuPercent, 0, WorkerCpuPercentMax, 0)
+	config.WorkerLimits.DocumentWorkerMemoryMB = getNumericValue(config.WorkerLimits.DocumentWorkerMemoryMB, WorkerMemoryMBMin, WorkerMemoryMBMax, 0)
+	config.WorkerLimits.SessionWorkerMemoryMB = getNumericValue(config.WorkerLimits.SessionWorkerMemoryMB, WorkerMemoryMBMin, WorkerMemoryMBMax, 0)
+
+	// Worker sandbox config
+	config.WorkerSandbox.SELinuxType = strings.TrimSpace(config.WorkerSandbox.SELinuxType)
+	config.WorkerSandbox.AdditionalDeniedSyscalls = getValidSyscallNames(config.WorkerSandbox.AdditionalDeniedSyscalls)
+
+	// Crash dump config
+	config.CrashDump.PanicThreshold = getNumericValue(
+		config.CrashDump.PanicThreshold,
+		DefaultCrashDumpPanicThresholdMin,
+		DefaultCrashDumpPanicThresholdMax,
+		DefaultCrashDumpPanicThreshold)
+	config.CrashDump.PanicWindowMinutes = getNumericValue(
+		config.CrashDump.PanicWindowMinutes,
+		DefaultCrashDumpPanicWindowMinutesMin,
+		DefaultCrashDumpPanicWindowMinutesMax,
+		DefaultCrashDumpPanicWindowMinutes)
+	config.CrashDump.MaxDumps = getNumericValue(
+		config.CrashDump.MaxDumps,
+		DefaultCrashDumpMaxDumpsMin,
+		DefaultCrashDumpMaxDumpsMax,
+		DefaultCrashDumpMaxDumps)
+
+	// Windows Event Log forwarding config
+	config.EventLogForwarding.LogGroupName = getStringValue(
+		strings.TrimSpace(config.EventLogForwarding.LogGroupName),
+		DefaultEventLogForwardingLogGroupName)
+	config.EventLogForwarding.PollIntervalSeconds = getNumericValue(
+		config.EventLogForwarding.PollIntervalSeconds,
+		DefaultEventLogForwardingPollIntervalSecondsMin,
+		DefaultEventLogForwardingPollIntervalSecondsMax,
+		DefaultEventLogForwardingPollIntervalSeconds)
+	config.EventLogForwarding.Channels = getValidEventLogChannels(config.EventLogForwarding.Channels)
+
+	// Network reachability config
+	config.Reachability.ProbeIntervalSeconds = getNumericValue(
+		config.Reachability.ProbeIntervalSeconds,
+		DefaultReachabilityProbeIntervalSecondsMin,
+		DefaultReachabilityProbeIntervalSecondsMax,
+		DefaultReachabilityProbeIntervalSeconds)
+	config.Reachability.ProbeTimeoutSeconds = getNumericValue(
+		config.Reachability.ProbeTimeoutSeconds,
+		DefaultReachabilityProbeTimeoutSecondsMin,
+		DefaultReachabilityProbeTimeoutSecondsMax,
+		DefaultReachabilityProbeTimeoutSeconds)
+	config.Reachability.ReportIntervalMinutes = getNumericValue(
+		config.Reachability.ReportIntervalMinutes,
+		DefaultReachabilityReportIntervalMinutesMin,
+		DefaultReachabilityReportIntervalMinutesMax,
+		DefaultReachabilityReportIntervalMinutes)
+
+	// Logging config
+	config.Logging.Level = getStringEnum(
+		strings.ToLower(strings.TrimSpace(config.Logging.Level)),
+		[]string{"trace", "debug", "info", "warn", "error", "critical"},
+		DefaultLoggingLevel)
+	config.Logging.MaxFileSizeMB = getNumericValue(
+		config.Logging.MaxFileSizeMB,
+		DefaultLoggingMaxFileSizeMBMin,
+		DefaultLoggingMaxFileSizeMBMax,
+		DefaultLoggingMaxFileSizeMB)
+	config.Logging.MaxRolls = getNumericValue(
+		config.Logging.MaxRolls,
+		DefaultLoggingMaxRollsMin,
+		DefaultLoggingMaxRollsMax,
+		DefaultLoggingMaxRolls)
+	config.Logging.Format = getStringEnum(
+		strings.ToLower(strings.TrimSpace(config.Logging.Format)),
+		[]string{LoggingFormatText, LoggingFormatJSON},
+		LoggingFormatText)
+
+	// Store and forward config
+	config.StoreAndForward.MaxAgeHours = getNumericValue(
+		config.StoreAndForward.MaxAgeHours,
+		DefaultStoreAndForwardMaxAgeHoursMin,
+		DefaultStoreAndForwardMaxAgeHoursMax,
+		DefaultStoreAndForwardMaxAgeHours)
+	config.StoreAndForward.MaxSizeMB = getNumericValue(
+		config.StoreAndForward.MaxSizeMB,
+		DefaultStoreAndForwardMaxSizeMBMin,
+		DefaultStoreAndForwardMaxSizeMBMax,
+		DefaultStoreAndForwardMaxSizeMB)
+	config.StoreAndForward.DrainIntervalSeconds = getNumericValue(
+		config.StoreAndForward.DrainIntervalSeconds,
+		DefaultStoreAndForwardDrainIntervalSecondsMin,
+		DefaultStoreAndForwardDrainIntervalSecondsMax,
+		DefaultStoreAndForwardDrainIntervalSeconds)
+
+	// Audit log config
+	config.AuditLog.MaxFileSizeMB = getNumericValue(
+		config.AuditLog.MaxFileSizeMB,
+		DefaultAuditLogMaxFileSizeMBMin,
+		DefaultAuditLogMaxFileSizeMBMax,
+		DefaultAuditLogMaxFileSizeMB)
+	config.AuditLog.MaxFiles = getNumericValue(
+		config.AuditLog.MaxFiles,
+		DefaultAuditLogMaxFilesMin,
+		DefaultAuditLogMaxFilesMax,
+		DefaultAuditLogMaxFiles)
+	config.AuditLog.S3BucketName = strings.TrimSpace(config.AuditLog.S3BucketName)
+
+	// Plugin policy config
+	config.PluginPolicy.AllowedPlugins = getTrimmedStringList(config.PluginPolicy.AllowedPlugins)
+	config.PluginPolicy.DeniedPlugins = getTrimmedStringList(config.PluginPolicy.DeniedPlugins)
+
+	// S3 config
+	config.S3.OutputKmsKeyId = strings.TrimSpace(config.S3.OutputKmsKeyId)
+
+	// Proxy config
+	config.Proxy.AutoConfigURL = strings.TrimSpace(config.Proxy.AutoConfigURL)
+	config.Proxy.AutoConfigRefreshMinutes = getNumericValue(
+		config.Proxy.AutoConfigRefreshMinutes,
+		DefaultProxyAutoConfigRefreshMinutesMin,
+		DefaultProxyAutoConfigRefreshMinutesMax,
+		DefaultProxyAutoConfigRefreshMinutes)
+	ProxyAuthSchemeOptions := map[string]bool{
+		ProxyAuthSchemeBasic:     true,
+		ProxyAuthSchemeNTLM:      true,
+		ProxyAuthSchemeNegotiate: true,
+	}
+	config.Proxy.AuthScheme = getStringEnumMap(config.Proxy.AuthScheme, ProxyAuthSchemeOptions, ProxyAuthSchemeBasic)
+
+	// Client certificate config
+	config.ClientCertificate.CertificateFile = strings.TrimSpace(config.ClientCertificate.CertificateFile)
+	config.ClientCertificate.KeyFile = strings.TrimSpace(config.ClientCertificate.KeyFile)
+	config.ClientCertificate.CertificateStore = strings.TrimSpace(config.ClientCertificate.CertificateStore)
+	if config.ClientCertificate.KeyFile == "" {
+		// the key may be bundled with the certificate
+		config.ClientCertificate.KeyFile = config.ClientCertificate.CertificateFile
+	}
+
+	// Clock skew config
+	config.ClockSkew.WarnThresholdSeconds = getNumericValue(
+		config.ClockSkew.WarnThresholdSeconds,
+		DefaultClockSkewWarnThresholdSecondsMin,
+		DefaultClockSkewWarnThresholdSecondsMax,
+		DefaultClockSkewWarnThresholdSeconds)
+
+	// Fips config, agents built for FIPS can not run outside of the FIPS mode
+	if DefaultFipsEnabled {
+		config.Fips.Enabled = true
+	}
+
+	// Shutdown config
+	config.Shutdown.DrainPeriodSeconds = getNumericValue(
+		config.Shutdown.DrainPeriodSeconds,
+		DefaultShutdownDrainPeriodSecondsMin,
+		DefaultShutdownDrainPeriodSecondsMax,
+		DefaultShutdownDrainPeriodSeconds)
+
+	// Disk space config
+	config.DiskSpace.MinimumFreeMegabytes = getNumericValue(
+		config.DiskSpace.MinimumFreeMegabytes,
+		DefaultDiskSpaceMinimumFreeMegabytesMin,
+		DefaultDiskSpaceMinimumFreeMegabytesMax,
+		DefaultDiskSpaceMinimumFreeMegabytes)
+	config.DiskSpace.OrchestrationMaxAgeHours = getNumericValue(
+		config.DiskSpace.OrchestrationMaxAgeHours,
+		DefaultDiskSpaceOrchestrationMaxAgeHoursMin,
+		DefaultDiskSpaceOrchestrationMaxAgeHoursMax,
+		DefaultDiskSpaceOrchestrationMaxAgeHours)
+	config.DiskSpace.OrchestrationMaxSizeMegabytes = getNumericValue(
+		config.DiskSpace.OrchestrationMaxSizeMegabytes,
+		DefaultDiskSpaceOrchestrationMaxSizeMegabytesMin,
+		DefaultDiskSpaceOrchestrationMaxSizeMegabytesMax,
+		DefaultDiskSpaceOrchestrationMaxSizeMegabytes)
+
+	// Custom attributes config
+	config.CustomAttributes.ScriptTimeoutSeconds = getNumericValue(
+		config.CustomAttributes.ScriptTimeoutSeconds,
+		DefaultCustomAttributesScriptTimeoutSecondsMin,
+		DefaultCustomAttributesScriptTimeoutSecondsMax,
+		DefaultCustomAttributesScriptTimeoutSeconds)
+
+	applyResourceProfile(config)
+}
+
+// applyResourceProfile overrides the settings tuned by the resource profile
+func applyResourceProfile(config *SsmagentConfig) {
+	if config.Agent.ResourceProfile != ResourceProfileLowMemory {
+		return
+	}
+	log.Printf("applying %v resource profile", ResourceProfileLowMemory)
+	config.Metrics.Enabled = false
+	config.Metrics.TextfilePath = ""
+	config.Metrics.JSONFilePath = ""
+	config.Tracing.Enabled = false
+	config.CrashDump.Enabled = false
+	config.StoreAndForward.Enabled = false
+	config.EventLogForwarding.Enabled = false
+	config.Reachability.Enabled = false
+	config.Mds.CommandWorkersLimit = LowMemoryProfileCommandWorkersLimit
+	if config.Agent.MemoryLimitMB == 0 {
+		config.Agent.MemoryLimitMB = LowMemoryProfileMemoryLimitMB
+	}
+}
+
+// applyConfigurationProfile applies the preset of the configuration profile, returns false for unknown profiles
+func applyConfigurationProfile(config *SsmagentConfig, profile string) bool {
+	switch profile {
+	case ConfigurationProfileMinimal:
+		// only the features needed to manage the instance
+		config.Modules.DisableOfflineCommands = true
+		config.Modules.DisableLongRunningPlugins = true
+		config.Modules.DisableFacts = true
+		config.Metrics.Enabled = false
+		config.Tracing.Enabled = false
+		config.CrashDump.Enabled = false
+		config.StoreAndForward.Enabled = false
+		config.EventLogForwarding.Enabled = false
+		config.Reachability.Enabled = false
+	case ConfigurationProfileInteractiveOnly:
+		// hosts only reached through Session Manager, e.g. bastion replacements
+		config.Modules.DisableRunCommand = true
+		config.Modules.DisableAssociations = true
+		config.Modules.DisableOfflineCommands = true
+		config.Modules.DisableLongRunningPlugins = true
+		config.Modules.DisableFacts = true
+	case ConfigurationProfileBatchWorker:
+		// hosts running documents, not reached interactively
+		config.Modules.DisableSessions = true
+		config.Mds.CommandWorkersLimit = BatchWorkerProfileCommandWorkersLimit
+	default:
+		log.Printf("unknown configuration profile %v, ignoring it", profile)
+		return false
+	}
+	log.Printf("applying %v configuration profile", profile)
+	return true
+}
+
+// getValidEventLogChannels drops the channels without a name and the unknown event levels
+func getValidEventLogChannels(channels []*EventLogChannelCfg) []*EventLogChannelCfg {
+	validLevels := []string{"Critical", "Error", "Warning", "Information", "Verbose"}
+	validChannels := make([]*EventLogChannelCfg, 0, len(channels))
+	for _, channel := range channels {
+		if channel == nil || strings.TrimSpace(channel.Name) == "" {
+			log.Printf("event log channel without a name, ignoring it")
+			continue
+		}
+		channel.Name = strings.TrimSpace(channel.Name)
+		levels := make([]string, 0, len(channel.Levels))
+		for _, level := range channel.Levels {
+			if !stringInList(strings.TrimSpace(level), validLevels) {
+				log.Printf("unknown event level %v of event log channel %v, ignoring it", level, channel.Name)
+				continue
+			}
+			levels = append(levels, strings.TrimSpace(level))
+		}
+		channel.Levels = levels
+		validChannels = append(validChannels, channel)
+	}
+	return validChannels
 }
 
 // getStringValue returns the default value if config is empty, else the config value
@@ -185,6 +619,18 @@ func getNumeric64Value(configValue int64, minValue int64, maxValue int64, defaul
 	return configValue
 }
 
+// getValidSyscallNames lower cases the syscall names and removes empty and duplicate names
+func getValidSyscallNames(names []string) []string {
+	result := []string{}
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "" && !stringInList(name, result) {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
 func getStringEnum(configValue string, possibleValues []string, defaultValue string) string {
 	if stringInList(configValue, possibleValues) {
 		return configValue
@@ -217,6 +663,84 @@ func getStringListEnum(configValue []string, possibleValues map[string]bool, def
 	return result
 }
 
+// getTrimmedStringList returns the config values without surrounding whitespace, dropping empty values
+func getTrimmedStringList(configValue []string) []string {
+	result := []string{}
+	for _, val := range configValue {
+		if trimmed := strings.TrimSpace(val); trimmed != "" {
+			result = append(result, trimmed)
+		}
+	}
+	return result
+}
+
+// getValidRegexps returns the trimmed config values, dropping empty values and invalid regular expressions
+func getValidRegexps(configValue []string) []string {
+	result := []string{}
+	for _, val := range getTrimmedStringList(configValue) {
+		if _, err := regexp.Compile(val); err != nil {
+			log.Printf("ignoring invalid regular expression %v: %v", val, err)
+			continue
+		}
+		result = append(result, val)
+	}
+	return result
+}
+
+// getValidComponentLogLevels drops unknown components and invalid levels, names and levels are case insensitive
+func getValidComponentLogLevels(configValue map[string]string, components map[string]bool, levels map[string]bool) map[string]string {
+	result := map[string]string{}
+	for component, level := range configValue {
+		component = strings.ToLower(strings.TrimSpace(component))
+		level = strings.ToLower(strings.TrimSpace(level))
+		if components[component] && levels[level] {
+			result[component] = level
+		}
+	}
+	return result
+}
+
+// getValidQuietHours drops quiet hours without a valid start and end time and applies the defaults to the rest
+func getValidQuietHours(quietHours []*MdsQuietHoursCfg) []*MdsQuietHoursCfg {
+	result := []*MdsQuietHoursCfg{}
+	for _, window := range quietHours {
+		if window == nil || !isValidDailyTimeWindow(&window.DailyTimeWindowCfg) {
+			continue
+		}
+		window.PollDelaySeconds = getNumericValue(
+			window.PollDelaySeconds,
+			DefaultMdsQuietHoursPollDelaySecondsMin,
+			DefaultMdsQuietHoursPollDelaySecondsMax,
+			DefaultMdsQuietHoursPollDelaySeconds)
+		result = append(result, window)
+	}
+	return result
+}
+
+// getValidDailyTimeWindows drops windows without a valid start and end time
+func getValidDailyTimeWindows(windows []*DailyTimeWindowCfg) []*DailyTimeWindowCfg {
+	result := []*DailyTimeWindowCfg{}
+	for _, window := range windows {
+		if window != nil && isValidDailyTimeWindow(window) {
+			result = append(result, window)
+		}
+	}
+	return result
+}
+
+// isValidDailyTimeWindow checks the start and end time of the window and drops unknown days
+func isValidDailyTimeWindow(window *DailyTimeWindowCfg) bool {
+	if _, err := time.Parse(times.DailyTimeLayout, window.Start); err != nil {
+		return false
+	}
+	if _, err := time.Parse(times.DailyTimeLayout, window.End); err != nil {
+		return false
+	}
+	weekdays := map[string]bool{"Mon": true, "Tue": true, "Wed": true, "Thu": true, "Fri": true, "Sat": true, "Sun": true}
+	window.Days = getStringListEnum(window.Days, weekdays, []string{})
+	return true
+}
+
 // getStringEnumMap returns default if config value is not in possible values
 func getStringEnumMap(configValue string, possibleValues map[string]bool, defaultValue string) string {
 	if _, ok := possibleValues[configValue]; ok {
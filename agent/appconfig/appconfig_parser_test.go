@@ -161,3 +161,641 @@ func TestIdentityCredentialsValue_InvalidCredentialsProviderToDefault(t *testing
 	parser(&agentConfig)
 	assert.Equal(t, agentConfig.Identity.CustomIdentities[0].CredentialsProvider, DefaultCustomIdentityCredentialsProvider)
 }
+
+func TestInventoryLanguagePackageManagers_InvalidValueRemoved(t *testing.T) {
+	agentConfig := DefaultConfig()
+	agentConfig.Inventory.LanguagePackageManagers = []string{LanguagePackageManagerPip, "cargo", LanguagePackageManagerGem}
+	parser(&agentConfig)
+
+	assert.Equal(t, []string{LanguagePackageManagerPip, LanguagePackageManagerGem}, agentConfig.Inventory.LanguagePackageManagers)
+}
+
+func TestInventoryScriptGatherers_LimitsToDefault(t *testing.T) {
+	agentConfig := DefaultConfig()
+	agentConfig.Inventory.ScriptGatherers = append(agentConfig.Inventory.ScriptGatherers,
+		&ScriptGathererCfg{Path: "/opt/inventory/gather", TimeoutSeconds: 0, MaxOutputSizeKB: 10000},
+		&ScriptGathererCfg{Path: "/opt/inventory/other", TimeoutSeconds: 30, MaxOutputSizeKB: 64})
+	parser(&agentConfig)
+
+	assert.Equal(t, DefaultScriptGathererTimeoutSeconds, agentConfig.Inventory.ScriptGatherers[0].TimeoutSeconds)
+	assert.Equal(t, DefaultScriptGathererMaxOutputSizeKB, agentConfig.Inventory.ScriptGatherers[0].MaxOutputSizeKB)
+	assert.Equal(t, 30, agentConfig.Inventory.ScriptGatherers[1].TimeoutSeconds)
+	assert.Equal(t, 64, agentConfig.Inventory.ScriptGatherers[1].MaxOutputSizeKB)
+}
+
+func TestInventoryFullRefreshIntervalHours(t *testing.T) {
+	agentConfig := DefaultConfig()
+	agentConfig.Inventory.FullRefreshIntervalHours = 0
+	parser(&agentConfig)
+	assert.Equal(t, DefaultInventoryFullRefreshIntervalHours, agentConfig.Inventory.FullRefreshIntervalHours)
+
+	agentConfig.Inventory.FullRefreshIntervalHours = 6
+	parser(&agentConfig)
+	assert.Equal(t, 6, agentConfig.Inventory.FullRefreshIntervalHours)
+}
+
+func TestMetricsConfig(t *testing.T) {
+	agentConfig := DefaultConfig()
+	agentConfig.Metrics.Port = 80
+	agentConfig.Metrics.TextfileIntervalSeconds = 0
+	parser(&agentConfig)
+	assert.False(t, agentConfig.Metrics.Enabled)
+	assert.Equal(t, DefaultMetricsPort, agentConfig.Metrics.Port)
+	assert.Equal(t, DefaultMetricsTextfileIntervalSeconds, agentConfig.Metrics.TextfileIntervalSeconds)
+
+	agentConfig.Metrics.Port = 9100
+	agentConfig.Metrics.TextfileIntervalSeconds = 15
+	parser(&agentConfig)
+	assert.Equal(t, 9100, agentConfig.Metrics.Port)
+	assert.Equal(t, 15, agentConfig.Metrics.TextfileIntervalSeconds)
+}
+
+func TestMetricsJSONFileConfig(t *testing.T) {
+	agentConfig := DefaultConfig()
+	agentConfig.Metrics.JSONFileIntervalSeconds = 1
+	agentConfig.Metrics.JSONFileMaxSizeMB = 0
+	agentConfig.Metrics.JSONFileMaxBackups = -1
+	parser(&agentConfig)
+	assert.Equal(t, DefaultMetricsJSONFileIntervalSeconds, agentConfig.Metrics.JSONFileIntervalSeconds)
+	assert.Equal(t, DefaultMetricsJSONFileMaxSizeMB, agentConfig.Metrics.JSONFileMaxSizeMB)
+	assert.Equal(t, DefaultMetricsJSONFileMaxBackups, agentConfig.Metrics.JSONFileMaxBackups)
+
+	agentConfig.Metrics.JSONFilePath = "/var/lib/metrics/ssm-agent.json"
+	agentConfig.Metrics.JSONFileIntervalSeconds = 30
+	agentConfig.Metrics.JSONFileMaxSizeMB = 50
+	agentConfig.Metrics.JSONFileMaxBackups = 0
+	parser(&agentConfig)
+	assert.Equal(t, "/var/lib/metrics/ssm-agent.json", agentConfig.Metrics.JSONFilePath)
+	assert.Equal(t, 30, agentConfig.Metrics.JSONFileIntervalSeconds)
+	assert.Equal(t, 50, agentConfig.Metrics.JSONFileMaxSizeMB)
+	assert.Equal(t, 0, agentConfig.Metrics.JSONFileMaxBackups)
+
+	// the low memory profile does not write metrics files
+	agentConfig.Agent.ResourceProfile = ResourceProfileLowMemory
+	parser(&agentConfig)
+	assert.Equal(t, "", agentConfig.Metrics.JSONFilePath)
+}
+
+func TestTracingConfig(t *testing.T) {
+	agentConfig := DefaultConfig()
+	agentConfig.Tracing.OtlpEndpoint = ""
+	agentConfig.Tracing.ServiceName = ""
+	agentConfig.Tracing.ExportIntervalSeconds = 1000
+	parser(&agentConfig)
+	assert.False(t, agentConfig.Tracing.Enabled)
+	assert.Equal(t, DefaultTracingOtlpEndpoint, agentConfig.Tracing.OtlpEndpoint)
+	assert.Equal(t, DefaultTracingServiceName, agentConfig.Tracing.ServiceName)
+	assert.Equal(t, DefaultTracingExportIntervalSeconds, agentConfig.Tracing.ExportIntervalSeconds)
+
+	agentConfig.Tracing.OtlpEndpoint = "https://collector.example.com/v1/traces"
+	agentConfig.Tracing.ExportIntervalSeconds = 30
+	parser(&agentConfig)
+	assert.Equal(t, "https://collector.example.com/v1/traces", agentConfig.Tracing.OtlpEndpoint)
+	assert.Equal(t, 30, agentConfig.Tracing.ExportIntervalSeconds)
+}
+
+func TestWorkerLimitsConfig(t *testing.T) {
+	agentConfig := DefaultConfig()
+	parser(&agentConfig)
+	assert.Equal(t, WorkerLimitsCfg{}, agentConfig.WorkerLimits)
+
+	agentConfig.WorkerLimits = WorkerLimitsCfg{
+		DocumentWorkerCpuPercent: 150,
+		DocumentWorkerMemoryMB:   16,
+		SessionWorkerCpuPercent:  25,
+		SessionWorkerMemoryMB:    512,
+	}
+	parser(&agentConfig)
+	assert.Equal(t, 0, agentConfig.WorkerLimits.DocumentWorkerCpuPercent)
+	assert.Equal(t, 0, agentConfig.WorkerLimits.DocumentWorkerMemoryMB)
+	assert.Equal(t, 25, agentConfig.WorkerLimits.SessionWorkerCpuPercent)
+	assert.Equal(t, 512, agentConfig.WorkerLimits.SessionWorkerMemoryMB)
+}
+
+func TestCrashDumpConfig(t *testing.T) {
+	agentConfig := DefaultConfig()
+	agentConfig.CrashDump.PanicThreshold = 0
+	agentConfig.CrashDump.PanicWindowMinutes = 5000
+	agentConfig.CrashDump.MaxDumps = 100
+	parser(&agentConfig)
+	assert.False(t, agentConfig.CrashDump.Enabled)
+	assert.Equal(t, DefaultCrashDumpPanicThreshold, agentConfig.CrashDump.PanicThreshold)
+	assert.Equal(t, DefaultCrashDumpPanicWindowMinutes, agentConfig.CrashDump.PanicWindowMinutes)
+	assert.Equal(t, DefaultCrashDumpMaxDumps, agentConfig.CrashDump.MaxDumps)
+
+	agentConfig.CrashDump.PanicThreshold = 1
+	agentConfig.CrashDump.PanicWindowMinutes = 10
+	agentConfig.CrashDump.MaxDumps = 2
+	parser(&agentConfig)
+	assert.Equal(t, 1, agentConfig.CrashDump.PanicThreshold)
+	assert.Equal(t, 10, agentConfig.CrashDump.PanicWindowMinutes)
+	assert.Equal(t, 2, agentConfig.CrashDump.MaxDumps)
+}
+
+func TestAuditLogConfig(t *testing.T) {
+	agentConfig := DefaultConfig()
+	agentConfig.AuditLog.MaxFileSizeMB = 0
+	agentConfig.AuditLog.MaxFiles = 5000
+	agentConfig.AuditLog.S3BucketName = " audit-bucket "
+	parser(&agentConfig)
+	assert.False(t, agentConfig.AuditLog.Enabled)
+	assert.Equal(t, DefaultAuditLogMaxFileSizeMB, agentConfig.AuditLog.MaxFileSizeMB)
+	assert.Equal(t, DefaultAuditLogMaxFiles, agentConfig.AuditLog.MaxFiles)
+	assert.Equal(t, "audit-bucket", agentConfig.AuditLog.S3BucketName)
+
+	agentConfig.AuditLog.MaxFileSizeMB = 50
+	agentConfig.AuditLog.MaxFiles = 3
+	parser(&agentConfig)
+	assert.Equal(t, 50, agentConfig.AuditLog.MaxFileSizeMB)
+	assert.Equal(t, 3, agentConfig.AuditLog.MaxFiles)
+}
+
+func TestStoreAndForwardConfig(t *testing.T) {
+	agentConfig := DefaultConfig()
+	agentConfig.StoreAndForward.MaxAgeHours = 0
+	agentConfig.StoreAndForward.MaxSizeMB = 20000
+	agentConfig.StoreAndForward.DrainIntervalSeconds = 1
+	parser(&agentConfig)
+	assert.False(t, agentConfig.StoreAndForward.Enabled)
+	assert.Equal(t, DefaultStoreAndForwardMaxAgeHours, agentConfig.StoreAndForward.MaxAgeHours)
+	assert.Equal(t, DefaultStoreAndForwardMaxSizeMB, agentConfig.StoreAndForward.MaxSizeMB)
+	assert.Equal(t, DefaultStoreAndForwardDrainIntervalSeconds, agentConfig.StoreAndForward.DrainIntervalSeconds)
+
+	agentConfig.StoreAndForward.MaxAgeHours = 24
+	agentConfig.StoreAndForward.MaxSizeMB = 10
+	agentConfig.StoreAndForward.DrainIntervalSeconds = 300
+	parser(&agentConfig)
+	assert.Equal(t, 24, agentConfig.StoreAndForward.MaxAgeHours)
+	assert.Equal(t, 10, agentConfig.StoreAndForward.MaxSizeMB)
+	assert.Equal(t, 300, agentConfig.StoreAndForward.DrainIntervalSeconds)
+}
+
+func TestPluginPolicyConfig(t *testing.T) {
+	agentConfig := DefaultConfig()
+	parser(&agentConfig)
+	assert.Equal(t, []string{}, agentConfig.PluginPolicy.AllowedPlugins)
+	assert.Equal(t, []string{}, agentConfig.PluginPolicy.DeniedPlugins)
+
+	agentConfig.PluginPolicy.AllowedPlugins = []string{" aws:runShellScript ", "", "aws:runPowerShellScript"}
+	agentConfig.PluginPolicy.DeniedPlugins = []string{"aws:runDockerAction", "  "}
+	parser(&agentConfig)
+	assert.Equal(t, []string{"aws:runShellScript", "aws:runPowerShellScript"}, agentConfig.PluginPolicy.AllowedPlugins)
+	assert.Equal(t, []string{"aws:runDockerAction"}, agentConfig.PluginPolicy.DeniedPlugins)
+}
+
+func TestProxyConfig(t *testing.T) {
+	agentConfig := DefaultConfig()
+	agentConfig.Proxy.AutoConfigURL = " http://wpad.example.com/proxy.pac "
+	agentConfig.Proxy.AutoConfigRefreshMinutes = 1
+	agentConfig.Proxy.AuthScheme = "Digest"
+	parser(&agentConfig)
+	assert.Equal(t, "http://wpad.example.com/proxy.pac", agentConfig.Proxy.AutoConfigURL)
+	assert.Equal(t, DefaultProxyAutoConfigRefreshMinutes, agentConfig.Proxy.AutoConfigRefreshMinutes)
+	assert.Equal(t, ProxyAuthSchemeBasic, agentConfig.Proxy.AuthScheme)
+
+	agentConfig.Proxy.AutoConfigRefreshMinutes = 15
+	agentConfig.Proxy.AuthScheme = ProxyAuthSchemeNTLM
+	parser(&agentConfig)
+	assert.Equal(t, 15, agentConfig.Proxy.AutoConfigRefreshMinutes)
+	assert.Equal(t, ProxyAuthSchemeNTLM, agentConfig.Proxy.AuthScheme)
+}
+
+func TestClientCertificateConfig(t *testing.T) {
+	agentConfig := DefaultConfig()
+	parser(&agentConfig)
+	assert.Equal(t, "", agentConfig.ClientCertificate.CertificateFile)
+	assert.Equal(t, "", agentConfig.ClientCertificate.KeyFile)
+
+	agentConfig.ClientCertificate.CertificateFile = " /etc/amazon/ssm/client.pem "
+	parser(&agentConfig)
+	assert.Equal(t, "/etc/amazon/ssm/client.pem", agentConfig.ClientCertificate.CertificateFile)
+	assert.Equal(t, "/etc/amazon/ssm/client.pem", agentConfig.ClientCertificate.KeyFile)
+
+	agentConfig.ClientCertificate.KeyFile = "/etc/amazon/ssm/client.key"
+	parser(&agentConfig)
+	assert.Equal(t, "/etc/amazon/ssm/client.key", agentConfig.ClientCertificate.KeyFile)
+}
+
+func TestClockSkewConfig(t *testing.T) {
+	agentConfig := DefaultConfig()
+	parser(&agentConfig)
+	assert.Equal(t, DefaultClockSkewWarnThresholdSeconds, agentConfig.ClockSkew.WarnThresholdSeconds)
+	assert.False(t, agentConfig.ClockSkew.CorrectSigning)
+
+	agentConfig.ClockSkew.WarnThresholdSeconds = 1
+	parser(&agentConfig)
+	assert.Equal(t, DefaultClockSkewWarnThresholdSeconds, agentConfig.ClockSkew.WarnThresholdSeconds)
+
+	agentConfig.ClockSkew.WarnThresholdSeconds = 300
+	parser(&agentConfig)
+	assert.Equal(t, 300, agentConfig.ClockSkew.WarnThresholdSeconds)
+}
+
+func TestShutdownConfig(t *testing.T) {
+	agentConfig := DefaultConfig()
+	parser(&agentConfig)
+	assert.Equal(t, 0, agentConfig.Shutdown.DrainPeriodSeconds)
+
+	agentConfig.Shutdown.DrainPeriodSeconds = 3600
+	parser(&agentConfig)
+	assert.Equal(t, DefaultShutdownDrainPeriodSeconds, agentConfig.Shutdown.DrainPeriodSeconds)
+
+	agentConfig.Shutdown.DrainPeriodSeconds = 60
+	parser(&agentConfig)
+	assert.Equal(t, 60, agentConfig.Shutdown.DrainPeriodSeconds)
+}
+
+func TestDiskSpaceConfig(t *testing.T) {
+	agentConfig := DefaultConfig()
+	parser(&agentConfig)
+	assert.Equal(t, 0, agentConfig.DiskSpace.MinimumFreeMegabytes)
+	assert.Equal(t, 0, agentConfig.DiskSpace.OrchestrationMaxAgeHours)
+	assert.Equal(t, 0, agentConfig.DiskSpace.OrchestrationMaxSizeMegabytes)
+
+	agentConfig.DiskSpace.MinimumFreeMegabytes = -1
+	agentConfig.DiskSpace.OrchestrationMaxAgeHours = 10000
+	agentConfig.DiskSpace.OrchestrationMaxSizeMegabytes = 2048
+	parser(&agentConfig)
+	assert.Equal(t, DefaultDiskSpaceMinimumFreeMegabytes, agentConfig.DiskSpace.MinimumFreeMegabytes)
+	assert.Equal(t, DefaultDiskSpaceOrchestrationMaxAgeHours, agentConfig.DiskSpace.OrchestrationMaxAgeHours)
+	assert.Equal(t, 2048, agentConfig.DiskSpace.OrchestrationMaxSizeMegabytes)
+}
+
+func TestCustomAttributesConfig(t *testing.T) {
+	agentConfig := DefaultConfig()
+	parser(&agentConfig)
+	assert.Empty(t, agentConfig.CustomAttributes.Attributes)
+	assert.Equal(t, DefaultCustomAttributesScriptTimeoutSeconds, agentConfig.CustomAttributes.ScriptTimeoutSeconds)
+
+	agentConfig.CustomAttributes.ScriptTimeoutSeconds = 0
+	parser(&agentConfig)
+	assert.Equal(t, DefaultCustomAttributesScriptTimeoutSeconds, agentConfig.CustomAttributes.ScriptTimeoutSeconds)
+
+	agentConfig.CustomAttributes.ScriptTimeoutSeconds = 120
+	parser(&agentConfig)
+	assert.Equal(t, 120, agentConfig.CustomAttributes.ScriptTimeoutSeconds)
+}
+
+func TestMgsWebSocketCompressionConfig(t *testing.T) {
+	agentConfig := DefaultConfig()
+	parser(&agentConfig)
+	assert.False(t, agentConfig.Mgs.WebSocketCompression)
+	assert.Equal(t, DefaultMgsWebSocketCompressionLevel, agentConfig.Mgs.WebSocketCompressionLevel)
+
+	agentConfig.Mgs.WebSocketCompressionLevel = 12
+	parser(&agentConfig)
+	assert.Equal(t, DefaultMgsWebSocketCompressionLevel, agentConfig.Mgs.WebSocketCompressionLevel)
+
+	agentConfig.Mgs.WebSocketCompressionLevel = 6
+	parser(&agentConfig)
+	assert.Equal(t, 6, agentConfig.Mgs.WebSocketCompressionLevel)
+}
+
+func TestMgsIsolatedUsersConfig(t *testing.T) {
+	agentConfig := DefaultConfig()
+	parser(&agentConfig)
+	assert.False(t, agentConfig.Mgs.IsolatedUsers.Enabled)
+	assert.Equal(t, DefaultIsolatedUserPrefix, agentConfig.Mgs.IsolatedUsers.UserPrefix)
+	assert.Equal(t, DefaultIsolatedUserPoolSize, agentConfig.Mgs.IsolatedUsers.PoolSize)
+	assert.Equal(t, DefaultIsolatedUserFirstUID, agentConfig.Mgs.IsolatedUsers.FirstUID)
+	assert.False(t, agentConfig.Mgs.IsolatedUsers.RunCommand)
+
+	agentConfig.Mgs.IsolatedUsers.UserPrefix = "bastion-"
+	agentConfig.Mgs.IsolatedUsers.PoolSize = 4
+	agentConfig.Mgs.IsolatedUsers.FirstUID = 70000
+	parser(&agentConfig)
+	assert.Equal(t, "bastion-", agentConfig.Mgs.IsolatedUsers.UserPrefix)
+	assert.Equal(t, 4, agentConfig.Mgs.IsolatedUsers.PoolSize)
+	assert.Equal(t, 70000, agentConfig.Mgs.IsolatedUsers.FirstUID)
+
+	// the UID range of the pool must not include system users
+	agentConfig.Mgs.IsolatedUsers.FirstUID = 0
+	parser(&agentConfig)
+	assert.Equal(t, DefaultIsolatedUserFirstUID, agentConfig.Mgs.IsolatedUsers.FirstUID)
+
+	// the prefix must result in valid user names of at most 32 characters
+	for _, prefix := range []string{"", "Root", "ssm user", "1ssm", "a-very-long-prefix-for-pool-users-"} {
+		agentConfig.Mgs.IsolatedUsers.UserPrefix = prefix
+		parser(&agentConfig)
+		assert.Equal(t, DefaultIsolatedUserPrefix, agentConfig.Mgs.IsolatedUsers.UserPrefix, prefix)
+	}
+
+	agentConfig.Mgs.IsolatedUsers.PoolSize = 100
+	parser(&agentConfig)
+	assert.Equal(t, DefaultIsolatedUserPoolSize, agentConfig.Mgs.IsolatedUsers.PoolSize)
+}
+
+func TestMgsSessionChallengeConfig(t *testing.T) {
+	agentConfig := DefaultConfig()
+	parser(&agentConfig)
+	assert.Equal(t, "", agentConfig.Mgs.SessionChallenge.Executable)
+	assert.Equal(t, DefaultSessionChallengeTimeoutSeconds, agentConfig.Mgs.SessionChallenge.TimeoutSeconds)
+
+	agentConfig.Mgs.SessionChallenge.Executable = "/usr/local/bin/otp-challenge"
+	agentConfig.Mgs.SessionChallenge.TimeoutSeconds = 60
+	parser(&agentConfig)
+	assert.Equal(t, "/usr/local/bin/otp-challenge", agentConfig.Mgs.SessionChallenge.Executable)
+	assert.Equal(t, 60, agentConfig.Mgs.SessionChallenge.TimeoutSeconds)
+
+	agentConfig.Mgs.SessionChallenge.TimeoutSeconds = 5
+	parser(&agentConfig)
+	assert.Equal(t, DefaultSessionChallengeTimeoutSeconds, agentConfig.Mgs.SessionChallenge.TimeoutSeconds)
+}
+
+func TestMdsPollingConfig(t *testing.T) {
+	agentConfig := DefaultConfig()
+	parser(&agentConfig)
+	assert.Equal(t, DefaultMdsIdlePollBackoffAfterMinutes, agentConfig.Mds.IdlePollBackoffAfterMinutes)
+	assert.Equal(t, DefaultMdsMaxIdlePollDelaySeconds, agentConfig.Mds.MaxIdlePollDelaySeconds)
+	assert.Empty(t, agentConfig.Mds.QuietHours)
+
+	agentConfig.Mds.IdlePollBackoffAfterMinutes = 60
+	agentConfig.Mds.MaxIdlePollDelaySeconds = 3600
+	agentConfig.Mds.QuietHours = []*MdsQuietHoursCfg{
+		{DailyTimeWindowCfg: DailyTimeWindowCfg{Start: "22:00", End: "06:00", Days: []string{"Mon", "Funday", "Sat"}}},
+		{DailyTimeWindowCfg: DailyTimeWindowCfg{Start: "10pm", End: "06:00"}, PollDelaySeconds: 60},
+		nil,
+		{DailyTimeWindowCfg: DailyTimeWindowCfg{Start: "12:00", End: "13:30"}, PollDelaySeconds: 120},
+	}
+	parser(&agentConfig)
+	assert.Equal(t, 60, agentConfig.Mds.IdlePollBackoffAfterMinutes)
+	assert.Equal(t, DefaultMdsMaxIdlePollDelaySeconds, agentConfig.Mds.MaxIdlePollDelaySeconds)
+	assert.Equal(t, []*MdsQuietHoursCfg{
+		{DailyTimeWindowCfg: DailyTimeWindowCfg{Start: "22:00", End: "06:00", Days: []string{"Mon", "Sat"}}, PollDelaySeconds: DefaultMdsQuietHoursPollDelaySeconds},
+		{DailyTimeWindowCfg: DailyTimeWindowCfg{Start: "12:00", End: "13:30", Days: []string{}}, PollDelaySeconds: 120},
+	}, agentConfig.Mds.QuietHours)
+}
+
+func TestAssociationScheduleConfig(t *testing.T) {
+	agentConfig := DefaultConfig()
+	parser(&agentConfig)
+	assert.Equal(t, DefaultSsmAssociationScheduleJitterSeconds, agentConfig.Ssm.AssociationScheduleJitterSeconds)
+	assert.Empty(t, agentConfig.Ssm.AssociationBlackoutWindows)
+
+	agentConfig.Ssm.AssociationScheduleJitterSeconds = 7200
+	agentConfig.Ssm.AssociationBlackoutWindows = []*DailyTimeWindowCfg{
+		{Start: "08:00", End: "18:00", Days: []string{"Mon", "Tue"}},
+		{Start: "08:00", End: "6pm"},
+	}
+	parser(&agentConfig)
+	assert.Equal(t, DefaultSsmAssociationScheduleJitterSeconds, agentConfig.Ssm.AssociationScheduleJitterSeconds)
+	assert.Equal(t, []*DailyTimeWindowCfg{{Start: "08:00", End: "18:00", Days: []string{"Mon", "Tue"}}}, agentConfig.Ssm.AssociationBlackoutWindows)
+
+	agentConfig.Ssm.AssociationScheduleJitterSeconds = 300
+	parser(&agentConfig)
+	assert.Equal(t, 300, agentConfig.Ssm.AssociationScheduleJitterSeconds)
+}
+
+func TestAssociationDetectOnlyConfig(t *testing.T) {
+	agentConfig := DefaultConfig()
+	parser(&agentConfig)
+	assert.Empty(t, agentConfig.Ssm.AssociationDetectOnly)
+
+	agentConfig.Ssm.AssociationDetectOnly = []string{" assoc-id ", "", "*"}
+	parser(&agentConfig)
+	assert.Equal(t, []string{"assoc-id", "*"}, agentConfig.Ssm.AssociationDetectOnly)
+}
+
+func TestComponentLogLevelsConfig(t *testing.T) {
+	agentConfig := DefaultConfig()
+	parser(&agentConfig)
+	assert.Empty(t, agentConfig.Agent.ComponentLogLevels)
+
+	agentConfig.Agent.ComponentLogLevels = map[string]string{
+		"Session":        " DEBUG ",
+		"messageservice": "info",
+		"identity":       "verbose",
+		"unknown":        "debug",
+	}
+	parser(&agentConfig)
+	assert.Equal(t, map[string]string{"session": "debug", "messageservice": "info"}, agentConfig.Agent.ComponentLogLevels)
+}
+
+func TestRegionFailoverConfig(t *testing.T) {
+	agentConfig := DefaultConfig()
+	parser(&agentConfig)
+	assert.Empty(t, agentConfig.Identity.RegionFailover.Regions)
+	assert.Equal(t, DefaultRegionFailoverHealthCheckIntervalSeconds, agentConfig.Identity.RegionFailover.HealthCheckIntervalSeconds)
+	assert.Equal(t, DefaultRegionFailoverThreshold, agentConfig.Identity.RegionFailover.FailureThreshold)
+
+	agentConfig.Identity.RegionFailover.Regions = []string{" us-east-1", "", "eu-west-1 "}
+	agentConfig.Identity.RegionFailover.HealthCheckIntervalSeconds = 10
+	agentConfig.Identity.RegionFailover.FailureThreshold = 100
+	parser(&agentConfig)
+	assert.Equal(t, []string{"us-east-1", "eu-west-1"}, agentConfig.Identity.RegionFailover.Regions)
+	assert.Equal(t, DefaultRegionFailoverHealthCheckIntervalSeconds, agentConfig.Identity.RegionFailover.HealthCheckIntervalSeconds)
+	assert.Equal(t, DefaultRegionFailoverThreshold, agentConfig.Identity.RegionFailover.FailureThreshold)
+}
+
+func TestNetworkStackConfig(t *testing.T) {
+	agentConfig := DefaultConfig()
+	parser(&agentConfig)
+	assert.Equal(t, NetworkStackIPv4, agentConfig.Agent.NetworkStack)
+
+	agentConfig.Agent.NetworkStack = " IPv6 "
+	parser(&agentConfig)
+	assert.Equal(t, NetworkStackIPv6, agentConfig.Agent.NetworkStack)
+
+	agentConfig.Agent.NetworkStack = "ipv5"
+	parser(&agentConfig)
+	assert.Equal(t, NetworkStackIPv4, agentConfig.Agent.NetworkStack)
+}
+
+func TestLogRedactionConfig(t *testing.T) {
+	agentConfig := DefaultConfig()
+	parser(&agentConfig)
+	assert.True(t, agentConfig.Agent.LogRedaction)
+	assert.Empty(t, agentConfig.Agent.LogRedactionPatterns)
+
+	agentConfig.Agent.LogRedactionPatterns = []string{" ghp_[0-9a-zA-Z]{36} ", "", "invalid[", `\bxox[bp]-\S+`}
+	parser(&agentConfig)
+	assert.Equal(t, []string{"ghp_[0-9a-zA-Z]{36}", `\bxox[bp]-\S+`}, agentConfig.Agent.LogRedactionPatterns)
+}
+
+func TestS3OutputConfig(t *testing.T) {
+	agentConfig := DefaultConfig()
+	parser(&agentConfig)
+	assert.False(t, agentConfig.S3.OutputCompression)
+	assert.Empty(t, agentConfig.S3.OutputKmsKeyId)
+
+	agentConfig.S3.OutputKmsKeyId = " alias/ssm-output "
+	parser(&agentConfig)
+	assert.Equal(t, "alias/ssm-output", agentConfig.S3.OutputKmsKeyId)
+}
+
+func TestResourceProfileConfig(t *testing.T) {
+	agentConfig := DefaultConfig()
+	agentConfig.Agent.ResourceProfile = "unknown"
+	agentConfig.Agent.MemoryLimitMB = 1
+	agentConfig.Metrics.Enabled = true
+	parser(&agentConfig)
+	assert.Equal(t, "", agentConfig.Agent.ResourceProfile)
+	assert.Equal(t, 0, agentConfig.Agent.MemoryLimitMB)
+	assert.True(t, agentConfig.Metrics.Enabled)
+
+	agentConfig.Agent.ResourceProfile = " low-memory "
+	agentConfig.Tracing.Enabled = true
+	agentConfig.CrashDump.Enabled = true
+	agentConfig.StoreAndForward.Enabled = true
+	parser(&agentConfig)
+	assert.Equal(t, ResourceProfileLowMemory, agentConfig.Agent.ResourceProfile)
+	assert.Equal(t, LowMemoryProfileMemoryLimitMB, agentConfig.Agent.MemoryLimitMB)
+	assert.Equal(t, LowMemoryProfileCommandWorkersLimit, agentConfig.Mds.CommandWorkersLimit)
+	assert.False(t, agentConfig.Metrics.Enabled)
+	assert.False(t, agentConfig.Tracing.Enabled)
+	assert.False(t, agentConfig.CrashDump.Enabled)
+	assert.False(t, agentConfig.StoreAndForward.Enabled)
+
+	agentConfig.Agent.MemoryLimitMB = 64
+	parser(&agentConfig)
+	assert.Equal(t, 64, agentConfig.Agent.MemoryLimitMB)
+}
+
+func TestWorkerSandboxConfig(t *testing.T) {
+	agentConfig := DefaultConfig()
+	agentConfig.WorkerSandbox.SELinuxType = " ssm_worker_t "
+	agentConfig.WorkerSandbox.AdditionalDeniedSyscalls = []string{" Ptrace", "", "ptrace", "bpf"}
+	parser(&agentConfig)
+	assert.Equal(t, "ssm_worker_t", agentConfig.WorkerSandbox.SELinuxType)
+	assert.Equal(t, []string{"ptrace", "bpf"}, agentConfig.WorkerSandbox.AdditionalDeniedSyscalls)
+}
+
+func TestFingerprintHardwareSourceConfig(t *testing.T) {
+	agentConfig := DefaultConfig()
+	agentConfig.Agent.FingerprintHardwareSource = " Registry "
+	parser(&agentConfig)
+	assert.Equal(t, FingerprintHardwareSourceRegistry, agentConfig.Agent.FingerprintHardwareSource)
+
+	agentConfig.Agent.FingerprintHardwareSource = "smbios"
+	parser(&agentConfig)
+	assert.Equal(t, "", agentConfig.Agent.FingerprintHardwareSource)
+}
+
+func TestConfigurationProfileConfig(t *testing.T) {
+	sampleJsonPath := filepath.Join(t.TempDir(), "amazon-ssm-agent.json")
+	originalFunc := retrieveAppConfigPath
+	defer func() { retrieveAppConfigPath = originalFunc }()
+	retrieveAppConfigPath = func() (string, error) {
+		return sampleJsonPath, nil
+	}
+
+	// settings in the file take precedence over the preset
+	tempJson := []byte(`{"Agent": {"ConfigurationProfile": "interactive-only"}, "Modules": {"DisableAssociations": false}}`)
+	assert.NoError(t, ioutil.WriteFile(sampleJsonPath, tempJson, ReadWriteAccess))
+	agentConfig, err := Config(true)
+	assert.NoError(t, err)
+	assert.Equal(t, ConfigurationProfileInteractiveOnly, agentConfig.Agent.ConfigurationProfile)
+	assert.False(t, agentConfig.Modules.DisableSessions)
+	assert.True(t, agentConfig.Modules.DisableRunCommand)
+	assert.False(t, agentConfig.Modules.DisableAssociations)
+	assert.True(t, agentConfig.Modules.DisableFacts)
+
+	tempJson = []byte(`{"Agent": {"ConfigurationProfile": "batch-worker"}}`)
+	assert.NoError(t, ioutil.WriteFile(sampleJsonPath, tempJson, ReadWriteAccess))
+	agentConfig, err = Config(true)
+	assert.NoError(t, err)
+	assert.True(t, agentConfig.Modules.DisableSessions)
+	assert.False(t, agentConfig.Modules.DisableRunCommand)
+	assert.Equal(t, BatchWorkerProfileCommandWorkersLimit, agentConfig.Mds.CommandWorkersLimit)
+
+	tempJson = []byte(`{"Agent": {"ConfigurationProfile": "minimal"}, "Metrics": {"Enabled": true}}`)
+	assert.NoError(t, ioutil.WriteFile(sampleJsonPath, tempJson, ReadWriteAccess))
+	agentConfig, err = Config(true)
+	assert.NoError(t, err)
+	assert.False(t, agentConfig.Modules.DisableRunCommand)
+	assert.True(t, agentConfig.Modules.DisableLongRunningPlugins)
+	assert.True(t, agentConfig.Metrics.Enabled)
+
+	tempJson = []byte(`{"Agent": {"ConfigurationProfile": "unknown"}}`)
+	assert.NoError(t, ioutil.WriteFile(sampleJsonPath, tempJson, ReadWriteAccess))
+	agentConfig, err = Config(true)
+	assert.NoError(t, err)
+	assert.Equal(t, "", agentConfig.Agent.ConfigurationProfile)
+	assert.Equal(t, DefaultConfig().Modules, agentConfig.Modules)
+}
+
+func TestEventLogForwardingConfig(t *testing.T) {
+	agentConfig := DefaultConfig()
+	agentConfig.EventLogForwarding.LogGroupName = " "
+	agentConfig.EventLogForwarding.PollIntervalSeconds = 1
+	agentConfig.EventLogForwarding.Channels = []*EventLogChannelCfg{
+		{Name: " System ", Levels: []string{"Error", "Fatal", " Warning"}},
+		{Name: ""},
+		nil,
+	}
+	parser(&agentConfig)
+
+	assert.Equal(t, DefaultEventLogForwardingLogGroupName, agentConfig.EventLogForwarding.LogGroupName)
+	assert.Equal(t, DefaultEventLogForwardingPollIntervalSeconds, agentConfig.EventLogForwarding.PollIntervalSeconds)
+	assert.Len(t, agentConfig.EventLogForwarding.Channels, 1)
+	assert.Equal(t, "System", agentConfig.EventLogForwarding.Channels[0].Name)
+	assert.Equal(t, []string{"Error", "Warning"}, agentConfig.EventLogForwarding.Channels[0].Levels)
+}
+
+func TestReachabilityConfig(t *testing.T) {
+	agentConfig := DefaultConfig()
+	agentConfig.Reachability.ProbeIntervalSeconds = 10
+	agentConfig.Reachability.ProbeTimeoutSeconds = 30
+	agentConfig.Reachability.ReportIntervalMinutes = 2000
+	parser(&agentConfig)
+
+	assert.Equal(t, DefaultReachabilityProbeIntervalSeconds, agentConfig.Reachability.ProbeIntervalSeconds)
+	assert.Equal(t, 30, agentConfig.Reachability.ProbeTimeoutSeconds)
+	assert.Equal(t, DefaultReachabilityReportIntervalMinutes, agentConfig.Reachability.ReportIntervalMinutes)
+}
+
+func TestResourceAccessRoleConfig(t *testing.T) {
+	agentConfig := DefaultConfig()
+	agentConfig.Identity.ResourceAccessRoleArn = " arn:aws:iam::123456789012:role/resources "
+	agentConfig.Identity.ResourceAccessRoleExternalId = " external "
+	parser(&agentConfig)
+
+	assert.Equal(t, "arn:aws:iam::123456789012:role/resources", agentConfig.Identity.ResourceAccessRoleArn)
+	assert.Equal(t, "external", agentConfig.Identity.ResourceAccessRoleExternalId)
+}
+
+func TestScopedCredentialsConfig(t *testing.T) {
+	agentConfig := DefaultConfig()
+	parser(&agentConfig)
+	assert.Equal(t, "", agentConfig.Identity.ScopedCredentialsRoleArn)
+	assert.Equal(t, DefaultScopedCredentialsDurationSeconds, agentConfig.Identity.ScopedCredentialsDurationSeconds)
+
+	agentConfig.Identity.ScopedCredentialsRoleArn = " arn:aws:iam::123456789012:role/documents "
+	agentConfig.Identity.ScopedCredentialsDurationSeconds = 900
+	parser(&agentConfig)
+	assert.Equal(t, "arn:aws:iam::123456789012:role/documents", agentConfig.Identity.ScopedCredentialsRoleArn)
+	assert.Equal(t, 900, agentConfig.Identity.ScopedCredentialsDurationSeconds)
+
+	agentConfig.Identity.ScopedCredentialsDurationSeconds = 60
+	parser(&agentConfig)
+	assert.Equal(t, DefaultScopedCredentialsDurationSeconds, agentConfig.Identity.ScopedCredentialsDurationSeconds)
+}
+
+func TestLoggingConfig(t *testing.T) {
+	agentConfig := DefaultConfig()
+	parser(&agentConfig)
+	assert.Equal(t, LoggingCfg{Level: "info", MaxFileSizeMB: 30, MaxRolls: 5, Format: LoggingFormatText}, agentConfig.Logging)
+
+	agentConfig.Logging = LoggingCfg{Level: " DEBUG ", MaxFileSizeMB: 100, MaxRolls: 10, Format: "JSON"}
+	parser(&agentConfig)
+	assert.Equal(t, LoggingCfg{Level: "debug", MaxFileSizeMB: 100, MaxRolls: 10, Format: LoggingFormatJSON}, agentConfig.Logging)
+
+	agentConfig.Logging = LoggingCfg{Level: "verbose", MaxFileSizeMB: 0, MaxRolls: 1000, Format: "xml"}
+	parser(&agentConfig)
+	assert.Equal(t, LoggingCfg{Level: "info", MaxFileSizeMB: 30, MaxRolls: 5, Format: LoggingFormatText}, agentConfig.Logging)
+}
+
+func TestUpdateHealthCheckConfig(t *testing.T) {
+	agentConfig := DefaultConfig()
+	parser(&agentConfig)
+	assert.Equal(t, DefaultUpdateHealthCheckTimeoutSeconds, agentConfig.Agent.UpdateHealthCheckTimeoutSeconds)
+	assert.False(t, agentConfig.Agent.UpdateHealthCheckRequireControlChannel)
+
+	agentConfig.Agent.UpdateHealthCheckTimeoutSeconds = 5
+	parser(&agentConfig)
+	assert.Equal(t, DefaultUpdateHealthCheckTimeoutSeconds, agentConfig.Agent.UpdateHealthCheckTimeoutSeconds)
+
+	agentConfig.Agent.UpdateHealthCheckTimeoutSeconds = 300
+	parser(&agentConfig)
+	assert.Equal(t, 300, agentConfig.Agent.UpdateHealthCheckTimeoutSeconds)
+}
@@ -48,6 +48,19 @@ const (
 	// DefaultCancelWorkersLimit represents default cancel worker limit
 	DefaultCancelWorkersLimit = 3
 
+	// DefaultMdsIdlePollBackoffAfterMinutes disables the idle back off of MDS long polling
+	DefaultMdsIdlePollBackoffAfterMinutes    = 0
+	DefaultMdsIdlePollBackoffAfterMinutesMin = 0
+	DefaultMdsIdlePollBackoffAfterMinutesMax = 1440
+
+	DefaultMdsMaxIdlePollDelaySeconds    = 300
+	DefaultMdsMaxIdlePollDelaySecondsMin = 10
+	DefaultMdsMaxIdlePollDelaySecondsMax = 600
+
+	DefaultMdsQuietHoursPollDelaySeconds    = 300
+	DefaultMdsQuietHoursPollDelaySecondsMin = 1
+	DefaultMdsQuietHoursPollDelaySecondsMax = 600
+
 	DefaultStopTimeoutMillis    = 20000
 	DefaultStopTimeoutMillisMin = 10000
 	DefaultStopTimeoutMillisMax = 1000000
@@ -61,10 +74,157 @@ const (
 	DefaultSsmAssociationFrequencyMinutesMin = 5
 	DefaultSsmAssociationFrequencyMinutesMax = 60
 
+	// DefaultSsmAssociationScheduleJitterSeconds disables the jitter of scheduled association runs
+	DefaultSsmAssociationScheduleJitterSeconds    = 0
+	DefaultSsmAssociationScheduleJitterSecondsMin = 0
+	DefaultSsmAssociationScheduleJitterSecondsMax = 3600
+
 	DefaultSsmSelfUpdateFrequencyDays    = 7
 	DefaultSsmSelfUpdateFrequencyDaysMin = 1 //Minimum frequency is 1 day
 	DefaultSsmSelfUpdateFrequencyDaysMax = 7 //Maximum frequency is 7 day
 
+	// Language package managers supported by the application inventory gatherer
+	LanguagePackageManagerPip = "pip"
+	LanguagePackageManagerNpm = "npm"
+	LanguagePackageManagerGem = "gem"
+
+	// Inventory delta reporting full refresh interval
+	DefaultInventoryFullRefreshIntervalHours    = 24
+	DefaultInventoryFullRefreshIntervalHoursMin = 1
+	DefaultInventoryFullRefreshIntervalHoursMax = 720
+
+	// Script inventory gatherer limits
+	DefaultScriptGathererTimeoutSeconds    = 60
+	DefaultScriptGathererTimeoutSecondsMin = 1
+	DefaultScriptGathererTimeoutSecondsMax = 600
+
+	DefaultScriptGathererMaxOutputSizeKB    = 1024
+	DefaultScriptGathererMaxOutputSizeKBMin = 1
+	DefaultScriptGathererMaxOutputSizeKBMax = 3072
+
+	// Metrics endpoint port and textfile exporter interval
+	DefaultMetricsPort    = 9465
+	DefaultMetricsPortMin = 1024
+	DefaultMetricsPortMax = 65535
+
+	DefaultMetricsTextfileIntervalSeconds    = 60
+	DefaultMetricsTextfileIntervalSecondsMin = 10
+	DefaultMetricsTextfileIntervalSecondsMax = 3600
+
+	// Metrics JSON file interval and rotation
+	DefaultMetricsJSONFileIntervalSeconds    = 60
+	DefaultMetricsJSONFileIntervalSecondsMin = 10
+	DefaultMetricsJSONFileIntervalSecondsMax = 3600
+
+	DefaultMetricsJSONFileMaxSizeMB    = 10
+	DefaultMetricsJSONFileMaxSizeMBMin = 1
+	DefaultMetricsJSONFileMaxSizeMBMax = 1024
+
+	DefaultMetricsJSONFileMaxBackups    = 5
+	DefaultMetricsJSONFileMaxBackupsMin = 0
+	DefaultMetricsJSONFileMaxBackupsMax = 100
+
+	// Tracing exporter defaults
+	DefaultTracingOtlpEndpoint = "http://localhost:4318/v1/traces"
+	DefaultTracingServiceName  = "amazon-ssm-agent"
+
+	DefaultTracingExportIntervalSeconds    = 10
+	DefaultTracingExportIntervalSecondsMin = 1
+	DefaultTracingExportIntervalSecondsMax = 300
+
+	// Worker process resource limits, 0 means unlimited
+	WorkerCpuPercentMax = 100
+	WorkerMemoryMBMin   = 64
+	WorkerMemoryMBMax   = 1048576
+
+	// Crash dump defaults
+	DefaultCrashDumpPanicThreshold    = 3
+	DefaultCrashDumpPanicThresholdMin = 1
+	DefaultCrashDumpPanicThresholdMax = 100
+
+	DefaultCrashDumpPanicWindowMinutes    = 60
+	DefaultCrashDumpPanicWindowMinutesMin = 1
+	DefaultCrashDumpPanicWindowMinutesMax = 1440
+
+	DefaultCrashDumpMaxDumps    = 5
+	DefaultCrashDumpMaxDumpsMin = 1
+	DefaultCrashDumpMaxDumpsMax = 50
+
+	// Store and forward defaults
+	DefaultStoreAndForwardMaxAgeHours    = 72
+	DefaultStoreAndForwardMaxAgeHoursMin = 1
+	DefaultStoreAndForwardMaxAgeHoursMax = 720
+
+	DefaultStoreAndForwardMaxSizeMB    = 100
+	DefaultStoreAndForwardMaxSizeMBMin = 1
+	DefaultStoreAndForwardMaxSizeMBMax = 10240
+
+	DefaultStoreAndForwardDrainIntervalSeconds    = 60
+	DefaultStoreAndForwardDrainIntervalSecondsMin = 10
+	DefaultStoreAndForwardDrainIntervalSecondsMax = 3600
+
+	// Audit log defaults
+	DefaultAuditLogMaxFileSizeMB    = 10
+	DefaultAuditLogMaxFileSizeMBMin = 1
+	DefaultAuditLogMaxFileSizeMBMax = 1024
+
+	DefaultAuditLogMaxFiles    = 10
+	DefaultAuditLogMaxFilesMin = 1
+	DefaultAuditLogMaxFilesMax = 1000
+
+	// Windows Event Log forwarding defaults
+	DefaultEventLogForwardingLogGroupName           = "/aws/ssm/windows-eventlog"
+	DefaultEventLogForwardingPollIntervalSeconds    = 30
+	DefaultEventLogForwardingPollIntervalSecondsMin = 5
+	DefaultEventLogForwardingPollIntervalSecondsMax = 3600
+
+	// Network reachability probe defaults
+	DefaultReachabilityProbeIntervalSeconds     = 300
+	DefaultReachabilityProbeIntervalSecondsMin  = 60
+	DefaultReachabilityProbeIntervalSecondsMax  = 3600
+	DefaultReachabilityProbeTimeoutSeconds      = 10
+	DefaultReachabilityProbeTimeoutSecondsMin   = 1
+	DefaultReachabilityProbeTimeoutSecondsMax   = 60
+	DefaultReachabilityReportIntervalMinutes    = 60
+	DefaultReachabilityReportIntervalMinutesMin = 5
+	DefaultReachabilityReportIntervalMinutesMax = 1440
+
+	// Proxy defaults
+	DefaultProxyAutoConfigRefreshMinutes    = 60
+	DefaultProxyAutoConfigRefreshMinutesMin = 5
+	DefaultProxyAutoConfigRefreshMinutesMax = 1440
+
+	// Proxy authentication schemes
+	ProxyAuthSchemeBasic     = "Basic"
+	ProxyAuthSchemeNTLM      = "NTLM"
+	ProxyAuthSchemeNegotiate = "Negotiate"
+
+	// Clock skew defaults
+	DefaultClockSkewWarnThresholdSeconds    = 60
+	DefaultClockSkewWarnThresholdSecondsMin = 5
+	DefaultClockSkewWarnThresholdSecondsMax = 900
+
+	// Shutdown defaults
+	DefaultShutdownDrainPeriodSeconds    = 0
+	DefaultShutdownDrainPeriodSecondsMin = 0
+	DefaultShutdownDrainPeriodSecondsMax = 600
+
+	// Disk space defaults
+	DefaultDiskSpaceMinimumFreeMegabytes             = 0
+	DefaultDiskSpaceMinimumFreeMegabytesMin          = 0
+	DefaultDiskSpaceMinimumFreeMegabytesMax          = 1048576
+	DefaultDiskSpaceOrchestrationMaxAgeHours         = 0
+	DefaultDiskSpaceOrchestrationMaxAgeHoursMin      = 0
+	DefaultDiskSpaceOrchestrationMaxAgeHoursMax      = 8760
+	DefaultDiskSpaceOrchestrationMaxSizeMegabytes    = 0
+	DefaultDiskSpaceOrchestrationMaxSizeMegabytesMin = 0
+	DefaultDiskSpaceOrchestrationMaxSizeMegabytesMax = 1048576
+
+	// Custom attributes defaults
+	DefaultCustomAttributesScriptTimeoutSeconds    = 30
+	DefaultCustomAttributesScriptTimeoutSecondsMin = 1
+	DefaultCustomAttributesScriptTimeoutSecondsMax = 300
+
 	//aws-ssm-agent bookkeeping constants
 	DefaultLocationOfPending     = "pending"
 	DefaultLocationOfCurrent     = "current"
@@ -101,6 +261,72 @@ const (
 	DefaultAuditExpirationDayMax = 30 // 30 days max audit files count
 	DefaultAuditExpirationDayMin = 3  // 3 days min audit files count
 
+	// Presets selected by Agent.ConfigurationProfile
+	ConfigurationProfileMinimal           = "minimal"
+	ConfigurationProfileInteractiveOnly   = "interactive-only"
+	ConfigurationProfileBatchWorker       = "batch-worker"
+	BatchWorkerProfileCommandWorkersLimit = 10
+
+	// ResourceProfileLowMemory disables the optional subsystems of the agent for instances with little memory
+	ResourceProfileLowMemory = "low-memory"
+
+	// Region failover of hybrid instances registered in several regions
+	DefaultRegionFailoverHealthCheckIntervalSeconds    = 300
+	DefaultRegionFailoverHealthCheckIntervalSecondsMin = 60
+	DefaultRegionFailoverHealthCheckIntervalSecondsMax = 3600
+	DefaultRegionFailoverThreshold                     = 3
+	DefaultRegionFailoverThresholdMin                  = 1
+	DefaultRegionFailoverThresholdMax                  = 20
+
+	// Lifetime of the scoped credentials vended to document steps, STS allows 15 minutes up to 12 hours
+	DefaultScopedCredentialsDurationSeconds    = 3600
+	DefaultScopedCredentialsDurationSecondsMin = 900
+	DefaultScopedCredentialsDurationSecondsMax = 43200
+
+	// Logging options of the generated seelog.xml, the defaults match the seelog.xml shipped with the agent
+	DefaultLoggingLevel            = "info"
+	DefaultLoggingMaxFileSizeMB    = 30
+	DefaultLoggingMaxFileSizeMBMin = 1
+	DefaultLoggingMaxFileSizeMBMax = 1024
+	DefaultLoggingMaxRolls         = 5
+	DefaultLoggingMaxRollsMin      = 1
+	DefaultLoggingMaxRollsMax      = 100
+	LoggingFormatText              = "text"
+	LoggingFormatJSON              = "json"
+
+	// Network stacks selected by Agent.NetworkStack, dualstack and ipv6 use the dual-stack service endpoints
+	// and ipv6 reaches the instance metadata service over IPv6
+	NetworkStackIPv4      = "ipv4"
+	NetworkStackDualStack = "dualstack"
+	NetworkStackIPv6      = "ipv6"
+
+	// Soft memory limit of the agent processes, zero leaves the Go runtime default
+	DefaultMemoryLimitMBMin             = 16
+	DefaultMemoryLimitMBMax             = 4096
+	LowMemoryProfileMemoryLimitMB       = 24
+	LowMemoryProfileCommandWorkersLimit = 1
+
+	// Number of artifact downloads running at the same time across plugin executions
+	DefaultArtifactDownloadConcurrencyLimit    = 4
+	DefaultArtifactDownloadConcurrencyLimitMin = 1
+	DefaultArtifactDownloadConcurrencyLimitMax = 32
+
+	// Time the updater waits for the updated agent to become ready
+	DefaultUpdateHealthCheckTimeoutSeconds    = 180
+	DefaultUpdateHealthCheckTimeoutSecondsMin = 30
+	DefaultUpdateHealthCheckTimeoutSecondsMax = 900
+
+	// Sources of the Windows hardware values in the instance fingerprint, selected by Agent.FingerprintHardwareSource
+	FingerprintHardwareSourceWQL      = "WQL"
+	FingerprintHardwareSourceRegistry = "Registry"
+	FingerprintHardwareSourceWMIC     = "WMIC"
+
+	// Components that can have their own log level in Agent.ComponentLogLevels
+	LogComponentMessageService = "messageservice"
+	LogComponentSession        = "session"
+	LogComponentUpdater        = "updater"
+	LogComponentIdentity       = "identity"
+
 	// log destination for session manager
 	SessionLogsDestinationDisk = "disk"
 	SessionLogsDestinationNone = "none"
@@ -118,6 +344,7 @@ const (
 	FileInventoryRootDirName     = "file"
 	RoleInventoryRootDirName     = "role"
 	InventoryContentHashFileName = "contentHash"
+	InventoryFullUploadFileName  = "lastFullUpload"
 
 	//aws-ssm-agent bookkeeping constants for failed sent replies
 	RepliesRootDirName = "replies"
@@ -125,10 +352,13 @@ const (
 	RepliesMGSRootDirName = "replies_mgs"
 	//amazon-ssm-agent bookkeeping constants for storing received commands
 	IdempotencyDirName = "idempotency"
+	//amazon-ssm-agent bookkeeping constants for results queued while the service cannot be reached
+	StoreAndForwardRootDirName = "storeforward"
 
 	//aws-ssm-agent bookkeeping constants for compliance
 	ComplianceRootDirName         = "compliance"
 	ComplianceContentHashFileName = "contentHash"
+	ComplianceCacheFileName       = "associationCompliance"
 
 	// DefaultDocumentRootDirName is the root directory for storing command states
 	DefaultDocumentRootDirName = "document"
@@ -193,6 +423,15 @@ const (
 	// PluginDownloadContent is the name for downloadContent plugin
 	PluginDownloadContent = "aws:downloadContent"
 
+	// PluginCopyFile is the name for copyFile plugin
+	PluginCopyFile = "aws:copyFile"
+
+	// PluginManageService is the name for manageService plugin
+	PluginManageService = "aws:manageService"
+
+	// PluginRenderTemplate is the name for renderTemplate plugin
+	PluginRenderTemplate = "aws:renderTemplate"
+
 	// PluginRunDocument is the name of the run document plugin
 	PluginRunDocument = "aws:runDocument"
 
@@ -232,6 +471,25 @@ const (
 	DefaultSessionWorkersLimit    = 1000
 	DefaultSessionWorkersLimitMin = 1
 
+	// WebSocket permessage-deflate compression level defaults for the ssmmessages channels
+	DefaultMgsWebSocketCompressionLevel    = 1
+	DefaultMgsWebSocketCompressionLevelMin = 1
+	DefaultMgsWebSocketCompressionLevelMax = 9
+
+	// Isolated session user pool defaults
+	DefaultIsolatedUserPrefix      = "ssm-session-"
+	DefaultIsolatedUserPoolSize    = 16
+	DefaultIsolatedUserPoolSizeMin = 1
+	DefaultIsolatedUserPoolSizeMax = 99
+	DefaultIsolatedUserFirstUID    = 61000
+	DefaultIsolatedUserFirstUIDMin = 1000
+	DefaultIsolatedUserFirstUIDMax = 2000000000
+
+	// Session challenge timeout defaults
+	DefaultSessionChallengeTimeoutSeconds    = 120
+	DefaultSessionChallengeTimeoutSecondsMin = 10
+	DefaultSessionChallengeTimeoutSecondsMax = 900
+
 	// PluginNameStandardStream is the name for session manager standard stream plugin aka shell.
 	PluginNameStandardStream = "Standard_Stream"
 
@@ -61,6 +61,9 @@ var (
 	// DefaultDataStorePath represents the directory for storing system data
 	DefaultDataStorePath = AgentData
 
+	// PlatformFactsFilePath is the file the agent publishes the platform facts to for other tooling
+	PlatformFactsFilePath = AgentData + "facts.json"
+
 	// EC2ConfigDataStorePath represents the directory for storing ec2 config data
 	EC2ConfigDataStorePath = "/var/lib/amazon/ec2config/"
 
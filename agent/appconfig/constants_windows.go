@@ -99,6 +99,9 @@ var SeelogFilePath string
 // DefaultDataStorePath represents the directory for storing system data
 var DefaultDataStorePath string
 
+// PlatformFactsFilePath is the file the agent publishes the platform facts to for other tooling
+var PlatformFactsFilePath string
+
 // DefaultEC2SharedCredentialsFilePath represents the filepath for storing credentials for ec2 identity
 var DefaultEC2SharedCredentialsFilePath string
 
@@ -204,6 +207,7 @@ func init() {
 	AppConfigPath = filepath.Join(DefaultProgramFolder, AppConfigFileName)
 	SeelogFilePath = filepath.Join(DefaultProgramFolder, SeelogConfigFileName)
 	DefaultDataStorePath = filepath.Join(SSMDataPath, "InstanceData")
+	PlatformFactsFilePath = filepath.Join(SSMDataPath, "facts.json")
 	DefaultEC2SharedCredentialsFilePath = filepath.Join(DefaultProgramFolder, "credentials")
 	PackageRoot = filepath.Join(SSMDataPath, "Packages")
 	PackageLockRoot = filepath.Join(SSMDataPath, "Locks\\Packages")
@@ -29,6 +29,28 @@ type MdsCfg struct {
 	CommandWorkerBufferLimit int
 	StopTimeoutMillis        int64
 	CommandRetryLimit        int
+	// Minutes without any message after which the agent waits between long polls, 0 disables the idle back off
+	IdlePollBackoffAfterMinutes int
+	// Upper bound of the wait between long polls while idle
+	MaxIdlePollDelaySeconds int
+	// Time windows in which the agent waits between long polls
+	QuietHours []*MdsQuietHoursCfg
+}
+
+// DailyTimeWindowCfg represents a time window that recurs daily
+type DailyTimeWindowCfg struct {
+	// Local start and end time of the window as HH:MM, windows may span midnight
+	Start string
+	End   string
+	// Days the window starts on, Mon to Sun, every day when empty
+	Days []string
+}
+
+// MdsQuietHoursCfg represents a daily time window in which MDS is polled less frequently
+type MdsQuietHoursCfg struct {
+	DailyTimeWindowCfg
+	// Wait between long polls inside the window
+	PollDelaySeconds int
 }
 
 // SsmCfg represents configuration for Simple system manager (SSM)
@@ -50,6 +72,14 @@ type SsmCfg struct {
 	PluginLocalOutputCleanup string
 	// Configure only when it is safe to delete orchestration folder after document execution. This config overrides PluginLocalOutputCleanup when set.
 	OrchestrationDirectoryCleanup string
+	// Upper bound of the per instance delay added to scheduled association runs
+	AssociationScheduleJitterSeconds int
+	// Time windows in which scheduled associations are not started, runs falling inside are moved to the end of the window
+	AssociationBlackoutWindows []*DailyTimeWindowCfg
+	// Association ids or names, or * for all, that only report whether they would make changes without applying them
+	AssociationDetectOnly []string
+	// Strip ANSI escape sequences, like colors and progress bars, from the plugin console output unless a document opts in on its own
+	PluginOutputStripAnsi bool
 }
 
 // AgentInfo represents metadata for amazon-ssm-agent
@@ -73,6 +103,32 @@ type AgentInfo struct {
 	ForceFileIPC                        bool
 	// denotes GOMAXPROCS value for legacy agent worker
 	GoMaxProcForAgentWorker int
+	// ComponentLogLevels sets the minimum log level of a component, overriding the level from seelog.xml
+	ComponentLogLevels map[string]string
+	// ConfigurationProfile selects a preset (minimal, interactive-only or batch-worker) for common deployment shapes,
+	// settings in the configuration file are applied over the preset
+	ConfigurationProfile string
+	// ResourceProfile tunes the agent for constrained instances, low-memory disables the optional subsystems
+	ResourceProfile string
+	// MemoryLimitMB is the soft memory limit of the agent processes, the garbage collector runs more often near the limit
+	MemoryLimitMB int
+	// ArtifactDownloadConcurrencyLimit caps the number of artifact downloads running at the same time across plugin executions
+	ArtifactDownloadConcurrencyLimit int
+	// ArtifactCacheEnabled keeps downloaded artifacts with a known sha256 checksum on disk to serve repeat downloads
+	ArtifactCacheEnabled bool
+	// FingerprintHardwareSource overrides where the hardware values of the instance fingerprint are read from on Windows
+	// (WQL, Registry or WMIC), empty uses WQL and falls back to the registry when WMI is unavailable
+	FingerprintHardwareSource string
+	// NetworkStack selects the IP protocol of the service endpoints (ipv4, dualstack or ipv6), set ipv6 in IPv6-only subnets
+	NetworkStack string
+	// LogRedaction masks AWS access keys, secret keys, bearer tokens and passwords in URLs before log messages are written
+	LogRedaction bool
+	// LogRedactionPatterns are additional regular expressions whose matches are masked in log messages
+	LogRedactionPatterns []string
+	// UpdateHealthCheckTimeoutSeconds is how long the updater waits for the updated agent to become ready before rolling back
+	UpdateHealthCheckTimeoutSeconds int
+	// UpdateHealthCheckRequireControlChannel makes the updater also wait for the updated agent to open the control channel
+	UpdateHealthCheckRequireControlChannel bool
 }
 
 // MgsConfig represents configuration for Message Gateway service
@@ -83,6 +139,50 @@ type MgsConfig struct {
 	SessionWorkersLimit           int
 	SessionWorkerBufferLimit      int
 	DeniedPortForwardingRemoteIPs []string
+	// WebSocketCompression offers permessage-deflate on the control and data channels,
+	// messages are compressed only when the service accepts the extension
+	WebSocketCompression      bool
+	WebSocketCompressionLevel int
+	// ShellProfiles are login initialization scripts, like banners, environment setup or a forced region,
+	// run in Standard_Stream sessions before the shell is handed to the user, selected by name in the session document
+	ShellProfiles map[string]ShellProfileCfg
+	// DefaultShellProfile is run when the session document does not select a shell profile
+	DefaultShellProfile string
+	// IsolatedUsers runs each shell session as its own user instead of the shared ssm-user
+	IsolatedUsers IsolatedUsersCfg
+	// SessionChallenge must be passed interactively in shell sessions before the shell is started
+	SessionChallenge SessionChallengeCfg
+}
+
+// SessionChallengeCfg represents an on-host challenge, like a one-time password prompt, shell sessions must pass.
+// The executable runs as the agent user in a pseudo terminal connected to the session and the shell is only started
+// when it exits with 0. Sessions without a terminal, like NonInteractiveCommands, cannot pass the challenge.
+type SessionChallengeCfg struct {
+	// Absolute path of the challenge executable, no challenge is run when empty
+	Executable string
+	// Sessions are terminated when the challenge has not exited after TimeoutSeconds
+	TimeoutSeconds int
+}
+
+// IsolatedUsersCfg represents the pool of local users shell sessions and commands are isolated with on Linux.
+// A pool user is recreated with a fresh private home and temp directory for every execution it runs,
+// so concurrent executions on a shared host cannot read each other's files.
+type IsolatedUsersCfg struct {
+	Enabled bool
+	// Pool users are named UserPrefix followed by a two digit number
+	UserPrefix string
+	// Maximum number of concurrent isolated executions, further executions fail until a user is released
+	PoolSize int
+	// Pool user n is created with the UID FirstUID+n-1, the range must not be used by other users
+	FirstUID int
+	// RunCommand also runs the scripts of aws:runShellScript and aws:runPowerShellScript as pool users
+	RunCommand bool
+}
+
+// ShellProfileCfg represents the scripts of a shell profile per platform
+type ShellProfileCfg struct {
+	Linux   string
+	Windows string
 }
 
 // KmsConfig represents configuration for Key Management Service
@@ -104,6 +204,10 @@ type S3Cfg struct {
 	Region    string
 	LogBucket string
 	LogKey    string
+	// OutputCompression gzip compresses plugin output uploaded to S3 unless a document opts in on its own
+	OutputCompression bool
+	// OutputKmsKeyId encrypts plugin output uploaded to S3 with SSE-KMS when a document doesn't specify a key
+	OutputKmsKeyId string
 }
 
 // BirdwatcherCfg represents configuration related to ConfigurePackage Birdwatcher integration
@@ -111,18 +215,278 @@ type BirdwatcherCfg struct {
 	ForceEnable bool
 }
 
+// ScriptGathererCfg represents an operator provided executable whose JSON output is reported as a custom inventory type
+type ScriptGathererCfg struct {
+	// Absolute path of the executable
+	Path string
+	// Custom inventory type name reported for the output, must start with Custom:
+	TypeName string
+	// Schema version reported for the output, e.g. 1.0
+	SchemaVersion  string
+	TimeoutSeconds int
+	// Output larger than this limit is discarded
+	MaxOutputSizeKB int
+}
+
+// InventoryCfg represents configuration for the inventory plugin
+type InventoryCfg struct {
+	// Language package managers (pip, npm, gem) whose globally installed packages are reported as applications
+	LanguagePackageManagers []string
+	// Executables run by the custom inventory gatherer
+	ScriptGatherers []*ScriptGathererCfg
+	// Upload only inventory types whose content changed since the last successful upload
+	DeltaReporting bool
+	// Hours between uploads of all inventory types when DeltaReporting is enabled
+	FullRefreshIntervalHours int
+}
+
+// MetricsCfg represents configuration for exposing agent metrics in the Prometheus text format
+type MetricsCfg struct {
+	// Serve metrics at http://127.0.0.1:<Port>/metrics
+	Enabled bool
+	Port    int
+	// File the metrics are periodically written to for a node_exporter textfile collector, disabled when empty
+	TextfilePath            string
+	TextfileIntervalSeconds int
+	// File a JSON snapshot of the metrics is periodically appended to, for hosts where telemetry cannot leave the
+	// host and collectors scrape local files instead, disabled when empty
+	JSONFilePath            string
+	JSONFileIntervalSeconds int
+	// The JSON file is rotated to <JSONFilePath>.1 once it exceeds JSONFileMaxSizeMB, JSONFileMaxBackups files are kept
+	JSONFileMaxSizeMB  int
+	JSONFileMaxBackups int
+}
+
+// TracingCfg represents configuration for exporting document and session traces over OTLP/HTTP
+type TracingCfg struct {
+	Enabled bool
+	// OTLP/HTTP traces endpoint, e.g. http://localhost:4318/v1/traces
+	OtlpEndpoint string
+	// Headers added to export requests, e.g. for collector authentication
+	OtlpHeaders           map[string]string
+	ServiceName           string
+	ExportIntervalSeconds int
+}
+
+// WorkerLimitsCfg represents CPU and memory ceilings applied to document and session worker processes.
+// A value of 0 leaves the resource unlimited.
+type WorkerLimitsCfg struct {
+	// Percentage of the total CPU capacity of the host
+	DocumentWorkerCpuPercent int
+	DocumentWorkerMemoryMB   int
+	SessionWorkerCpuPercent  int
+	SessionWorkerMemoryMB    int
+}
+
+// WorkerSandboxCfg restricts the syscalls and file system access of document and session worker processes on Linux,
+// the restrictions depend on the plugins run by the worker
+type WorkerSandboxCfg struct {
+	Enabled bool
+	// Seccomp installs a filter denying kernel level syscalls in the worker process
+	Seccomp bool
+	// AppArmor generates and loads an AppArmor profile the worker is started under
+	AppArmor bool
+	// SELinuxType starts the worker in the given SELinux domain type instead of an AppArmor profile
+	SELinuxType string
+	// AdditionalDeniedSyscalls are denied by the seccomp filter on top of the syscalls denied for the plugins
+	AdditionalDeniedSyscalls []string
+}
+
+// CrashDumpCfg represents configuration for capturing diagnostics when agent processes panic repeatedly
+type CrashDumpCfg struct {
+	Enabled bool
+	// A dump is captured once a process panicked PanicThreshold times within PanicWindowMinutes
+	PanicThreshold     int
+	PanicWindowMinutes int
+	// Number of dumps kept on disk, the oldest are deleted first
+	MaxDumps int
+	// Dumps are uploaded to s3://S3BucketName/S3KeyPrefix/<instance id>/ when S3BucketName is set
+	S3BucketName string
+	S3KeyPrefix  string
+}
+
+// StoreAndForwardCfg represents configuration for keeping results on disk while the service cannot be reached
+type StoreAndForwardCfg struct {
+	Enabled bool
+	// Queued results and persisted replies older than MaxAgeHours are dropped
+	MaxAgeHours int
+	// Size limit of each queue, the oldest results are dropped first
+	MaxSizeMB            int
+	DrainIntervalSeconds int
+}
+
+// AuditLogCfg represents configuration for the local audit log of the documents received from the control plane
+type AuditLogCfg struct {
+	Enabled bool
+	// The current file is rotated once it would grow above MaxFileSizeMB
+	MaxFileSizeMB int
+	// Number of rotated files kept on disk, the oldest are deleted first
+	MaxFiles int
+	// Rotated files are archived to s3://S3BucketName/S3KeyPrefix/<instance id>/ when S3BucketName is set
+	S3BucketName string
+	S3KeyPrefix  string
+}
+
+// PluginPolicyCfg represents the host level policy on which plugins documents may run
+type PluginPolicyCfg struct {
+	// When not empty, only the listed plugins (e.g. aws:runShellScript) are allowed to run
+	AllowedPlugins []string
+	// Listed plugins are never run, even when they are also allowed
+	DeniedPlugins []string
+}
+
+// ProxyCfg represents configuration for reaching the service through enterprise proxies
+type ProxyCfg struct {
+	// http, https or file URL of a proxy auto-config (PAC) script choosing the proxy of each request
+	AutoConfigURL            string
+	AutoConfigRefreshMinutes int
+	// Basic, NTLM or Negotiate. Credentials are read from the user info of the http_proxy/https_proxy URL,
+	// without credentials NTLM and Negotiate authenticate as the agent account on Windows
+	AuthScheme string
+}
+
+// ClientCertificateCfg represents the client certificate presented to endpoints enforcing mutual TLS
+type ClientCertificateCfg struct {
+	// PEM encoded certificate chain and private key, reloaded when the files change
+	CertificateFile string
+	KeyFile         string
+	// Windows certificate store reference used instead of the files, e.g. LocalMachine\My\<thumbprint>
+	CertificateStore string
+}
+
+// ClockSkewCfg represents configuration for detecting drift between the local clock and the service clock
+type ClockSkewCfg struct {
+	// Skew reported in the logs once it exceeds this many seconds
+	WarnThresholdSeconds int
+	// Sign requests with the local time corrected by the observed skew
+	CorrectSigning bool
+}
+
+// FipsCfg represents configuration for running the agent with FIPS 140-3 compliant cryptography
+type FipsCfg struct {
+	// Enabled restricts TLS to FIPS approved algorithms and uses the FIPS endpoints of the services,
+	// it defaults to true for agents built with the fips build tag
+	Enabled bool
+	// FailOnNonCompliance stops the agent at startup when the Go cryptographic module does not run
+	// in FIPS mode or when a configured endpoint is not a FIPS endpoint, otherwise the issues are logged
+	FailOnNonCompliance bool
+}
+
+// ShutdownCfg represents configuration for stopping the agent
+type ShutdownCfg struct {
+	// Time given to in-flight documents to complete and their replies to be sent when the agent stops,
+	// no new documents are accepted meanwhile. 0 stops the agent right away
+	DrainPeriodSeconds int
+}
+
+// DiskSpaceCfg represents configuration for guarding the disk space used to run documents
+type DiskSpaceCfg struct {
+	// Documents fail right away when less space is free under the orchestration, download or log directories, 0 disables the check
+	MinimumFreeMegabytes int
+	// Orchestration directories older than this are deleted before documents run, 0 keeps them
+	OrchestrationMaxAgeHours int
+	// Oldest orchestration directories are deleted while their total size is above this, 0 does not limit the size
+	OrchestrationMaxSizeMegabytes int
+}
+
+// EventLogChannelCfg represents a Windows Event Log channel forwarded to CloudWatch Logs
+type EventLogChannelCfg struct {
+	// Channel name, e.g. System, Application or Microsoft-Windows-PowerShell/Operational
+	Name string
+	// When not empty, only events raised by the listed providers are forwarded
+	Providers []string
+	// When not empty, only events of the listed levels (Critical, Error, Warning, Information, Verbose) are forwarded
+	Levels []string
+}
+
+// EventLogForwardingCfg represents configuration for forwarding Windows Event Log entries to CloudWatch Logs
+type EventLogForwardingCfg struct {
+	Enabled  bool
+	Channels []*EventLogChannelCfg
+	// Log group the events are sent to, in one log stream per instance and channel
+	LogGroupName        string
+	PollIntervalSeconds int
+}
+
+// ReachabilityCfg represents configuration for probing the service endpoints the agent depends on
+type ReachabilityCfg struct {
+	Enabled              bool
+	ProbeIntervalSeconds int
+	ProbeTimeoutSeconds  int
+	// The summary is sent with the health update once per interval and whenever the reachability of an endpoint changes
+	ReportIntervalMinutes int
+}
+
+// LoggingCfg represents the logging options seelog.xml is generated from by ssm-setup-cli and ssm-cli
+type LoggingCfg struct {
+	// Level is the minimum level written to the logs (trace, debug, info, warn, error or critical)
+	Level string
+	// MaxFileSizeMB is the size the agent log is rotated at, the error log is rotated at a third of it
+	MaxFileSizeMB int
+	// MaxRolls is the number of rotated log files kept next to each log
+	MaxRolls int
+	// Format of the log files, text or json lines
+	Format string
+}
+
+// ModulesCfg represents which features of the agent are turned off, all of them run by default
+type ModulesCfg struct {
+	// Session Manager sessions
+	DisableSessions bool
+	// Run Command documents, received from MDS or over the control channel. Associations run in the same
+	// document worker and are turned off along with it
+	DisableRunCommand bool
+	// State Manager associations, including inventory
+	DisableAssociations bool
+	// Command documents dropped in the local offline folder
+	DisableOfflineCommands bool
+	// Long running plugins, e.g. CloudWatch
+	DisableLongRunningPlugins bool
+	// Periodic publishing of the platform facts
+	DisableFacts bool
+}
+
+// CustomAttributesCfg represents operator defined attributes reported for the instance along with its health
+type CustomAttributesCfg struct {
+	// Static attributes, e.g. {"RackId": "r12", "CostCenter": "cc-42"}
+	Attributes map[string]string
+	// Absolute path of an executable printing a JSON object of string attributes, which override the static ones
+	ScriptPath           string
+	ScriptTimeoutSeconds int
+}
+
 // SsmagentConfig stores agent configuration values.
 type SsmagentConfig struct {
-	Profile     CredentialProfile
-	Mds         MdsCfg
-	Ssm         SsmCfg
-	Mgs         MgsConfig
-	Agent       AgentInfo
-	Os          OsInfo
-	S3          S3Cfg
-	Birdwatcher BirdwatcherCfg
-	Kms         KmsConfig
-	Identity    IdentityCfg
+	Profile            CredentialProfile
+	Mds                MdsCfg
+	Ssm                SsmCfg
+	Mgs                MgsConfig
+	Agent              AgentInfo
+	Os                 OsInfo
+	S3                 S3Cfg
+	Birdwatcher        BirdwatcherCfg
+	Kms                KmsConfig
+	Identity           IdentityCfg
+	Inventory          InventoryCfg
+	Metrics            MetricsCfg
+	Tracing            TracingCfg
+	WorkerLimits       WorkerLimitsCfg
+	WorkerSandbox      WorkerSandboxCfg
+	CrashDump          CrashDumpCfg
+	StoreAndForward    StoreAndForwardCfg
+	AuditLog           AuditLogCfg
+	PluginPolicy       PluginPolicyCfg
+	Proxy              ProxyCfg
+	ClientCertificate  ClientCertificateCfg
+	ClockSkew          ClockSkewCfg
+	Fips               FipsCfg
+	Shutdown           ShutdownCfg
+	DiskSpace          DiskSpaceCfg
+	CustomAttributes   CustomAttributesCfg
+	Modules            ModulesCfg
+	EventLogForwarding EventLogForwardingCfg
+	Reachability       ReachabilityCfg
+	Logging            LoggingCfg
 }
 
 // AppConstants represents some run time constant variable for various module.
@@ -147,4 +511,26 @@ type IdentityCfg struct {
 	Ec2SystemInfoDetectionResponse string
 	ConsumptionOrder               []string
 	CustomIdentities               []*CustomIdentity
+	// ResourceAccessRoleArn is assumed with the agent identity for the S3, KMS and CloudWatch Logs resources accessed
+	// by plugins, separating the control plane identity from the data access identity
+	ResourceAccessRoleArn        string
+	ResourceAccessRoleExternalId string
+	// ScopedCredentialsRoleArn is assumed with the agent identity when a document step requests scoped credentials,
+	// tagged with the document name, command id and instance id, so the role policies can grant permissions per
+	// document with aws:PrincipalTag conditions. Documents cannot request scoped credentials when empty.
+	ScopedCredentialsRoleArn         string
+	ScopedCredentialsDurationSeconds int
+	// RegionFailover selects the region of a hybrid instance registered in several regions
+	RegionFailover RegionFailoverCfg
+}
+
+// RegionFailoverCfg represents the failover policy of a hybrid instance with registrations in several regions
+type RegionFailoverCfg struct {
+	// Regions in order of preference, the agent uses the first healthy region holding a registration
+	// and pins to the first region of the list whenever it is healthy
+	Regions []string
+	// HealthCheckIntervalSeconds is the interval of the reachability checks of the regions
+	HealthCheckIntervalSeconds int
+	// FailureThreshold is the number of consecutive checks preferring another region before the agent switches to it
+	FailureThreshold int
 }
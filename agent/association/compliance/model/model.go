@@ -14,11 +14,15 @@
 package model
 
 import (
+	"encoding/json"
+	"path/filepath"
 	"sync"
 	"time"
 
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
 	"github.com/aws/amazon-ssm-agent/agent/association/model"
 	"github.com/aws/amazon-ssm-agent/agent/contracts"
+	"github.com/aws/amazon-ssm-agent/agent/fileutil"
 	"github.com/aws/aws-sdk-go/service/ssm"
 )
 
@@ -125,3 +129,75 @@ func GetAssociationComplianceEntries() []*AssociationComplianceItem {
 
 	return associationComplianceItems
 }
+
+// ComplianceCachePath returns the file the association compliance items of the instance are persisted in
+func ComplianceCachePath(instanceID string) string {
+	return filepath.Join(appconfig.DefaultDataStorePath,
+		instanceID,
+		appconfig.ComplianceRootDirName,
+		appconfig.ComplianceCacheFileName)
+}
+
+// PersistAssociationComplianceItems writes the association compliance items to the cache file,
+// so the compliance of the associations executed while offline survives agent restarts
+func PersistAssociationComplianceItems(path string) error {
+	lock.RLock()
+	dataB, err := json.Marshal(associationComplianceItems)
+	lock.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	if err = fileutil.MakeDirs(filepath.Dir(path)); err != nil {
+		return err
+	}
+	_, err = fileutil.WriteIntoFileWithPermissions(path, string(dataB), appconfig.ReadWriteAccess)
+	return err
+}
+
+// ReadAssociationComplianceItems reads the association compliance items from the cache file
+func ReadAssociationComplianceItems(path string) (items []*AssociationComplianceItem, err error) {
+	var content string
+	if content, err = fileutil.ReadAllText(path); err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal([]byte(content), &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// LoadAssociationComplianceItems restores the association compliance items from the cache file,
+// items updated since the agent started are kept unless the cached execution is newer
+func LoadAssociationComplianceItems(path string) error {
+	if !fileutil.Exists(path) {
+		return nil
+	}
+	items, err := ReadAssociationComplianceItems(path)
+	if err != nil {
+		return err
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	for _, item := range items {
+		if item == nil {
+			continue
+		}
+		var statusFound = false
+		for i, status := range associationComplianceItems {
+			if status.AssociationId == item.AssociationId {
+				if status.ExecutionTime.Before(item.ExecutionTime) {
+					associationComplianceItems[i] = item
+				}
+				statusFound = true
+				break
+			}
+		}
+		if !statusFound {
+			associationComplianceItems = append(associationComplianceItems, item)
+		}
+	}
+	return nil
+}
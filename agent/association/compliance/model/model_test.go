@@ -14,6 +14,7 @@
 package model
 
 import (
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -156,3 +157,34 @@ func TestRefreshAssociationComplianceItems(t *testing.T) {
 	assert.Equal(t, 1, len(complianceItems))
 
 }
+
+func TestPersistAndLoadAssociationComplianceItems(t *testing.T) {
+	RefreshAssociationComplianceItems([]*model.InstanceAssociation{})
+	cachePath := filepath.Join(t.TempDir(), "compliance", "associationCompliance")
+
+	// nothing was cached yet
+	assert.NoError(t, LoadAssociationComplianceItems(cachePath))
+	assert.Equal(t, 0, len(GetAssociationComplianceEntries()))
+
+	offlineTime := time.Now().Add(-time.Hour)
+	UpdateAssociationComplianceItem("association_1", "testDoc", "1", contracts.AssociationStatusFailed, offlineTime)
+	UpdateAssociationComplianceItem("association_2", "testDoc2", "2", contracts.AssociationStatusSuccess, offlineTime)
+	assert.NoError(t, PersistAssociationComplianceItems(cachePath))
+
+	items, err := ReadAssociationComplianceItems(cachePath)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(items))
+	assert.Equal(t, NON_COMPLIANT, items[0].ComplianceStatus)
+
+	// the agent restarted and association_1 succeeded since
+	RefreshAssociationComplianceItems([]*model.InstanceAssociation{})
+	UpdateAssociationComplianceItem("association_1", "testDoc", "1", contracts.AssociationStatusSuccess, time.Now())
+	assert.NoError(t, LoadAssociationComplianceItems(cachePath))
+
+	complianceItems := GetAssociationComplianceEntries()
+	assert.Equal(t, 2, len(complianceItems))
+	assert.Equal(t, "association_1", complianceItems[0].AssociationId)
+	assert.Equal(t, COMPLIANT, complianceItems[0].ComplianceStatus)
+	assert.Equal(t, "association_2", complianceItems[1].AssociationId)
+	assert.Equal(t, COMPLIANT, complianceItems[1].ComplianceStatus)
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/datauploader"
 	"github.com/aws/amazon-ssm-agent/agent/sdkutil"
 	ssmSvc "github.com/aws/amazon-ssm-agent/agent/ssm"
+	"github.com/aws/amazon-ssm-agent/agent/storeforward"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ssm"
 )
@@ -58,6 +59,8 @@ type ComplianceUploader struct {
 	name       string
 	context    context.T
 	optimizer  datauploader.Optimizer
+	queue      *storeforward.Queue // PutComplianceItems calls that failed because the service could not be reached
+	cachePath  string              // file the association compliance items are persisted in
 }
 
 // NewComplianceService returns a new compliance service
@@ -73,6 +76,18 @@ func NewComplianceUploader(context context.T) *ComplianceUploader {
 		name:       Name,
 	}
 
+	if context.AppConfig().StoreAndForward.Enabled {
+		// only the latest compliance is kept, it replaces the compliance reported before
+		uploader.queue = storeforward.NewQueue(context.Identity(), storeforward.ComplianceQueueName, context.AppConfig().StoreAndForward, 1)
+	}
+
+	if instanceID, err := context.Identity().InstanceID(); err == nil {
+		uploader.cachePath = model.ComplianceCachePath(instanceID)
+		if err = model.LoadAssociationComplianceItems(uploader.cachePath); err != nil {
+			uploader.context.Log().Warnf("Unable to load cached association compliance - %v", err)
+		}
+	}
+
 	if uploader.optimizer, err = datauploader.NewOptimizerImplWithLocation(
 		uploader.context,
 		appconfig.ComplianceRootDirName,
@@ -119,6 +134,11 @@ func (u *ComplianceUploader) UpdateAssociationCompliance(associationID string, i
 	log := u.context.Log()
 
 	model.UpdateAssociationComplianceItem(associationID, documentName, documentVersion, associationStatus, executionTime)
+	if u.cachePath != "" {
+		if err := model.PersistAssociationComplianceItems(u.cachePath); err != nil {
+			log.Warnf("Unable to cache association compliance - %v", err)
+		}
+	}
 	var associationComplianceEntries = model.GetAssociationComplianceEntries()
 
 	oldHash := u.optimizer.GetContentHash(AssociationComplianceItemName)
@@ -137,6 +157,7 @@ func (u *ComplianceUploader) UpdateAssociationCompliance(associationID string, i
 		newComplianceItems)
 
 	if err != nil {
+		u.queueCompliance(instanceID, executionTime, itemContentHash, newComplianceItems, err)
 		err = fmt.Errorf("Unable to update association compliance %v", err)
 		return err
 	}
@@ -144,11 +165,61 @@ func (u *ComplianceUploader) UpdateAssociationCompliance(associationID string, i
 	if itemContentHash != oldHash {
 		u.optimizer.UpdateContentHash(AssociationComplianceItemName, itemContentHash)
 	}
+	if u.queue != nil {
+		// the compliance queued before is outdated now
+		u.queue.Clear(log)
+	}
 
 	log.Debugf("Put compliance item %v return response %v", newComplianceItems, response)
 	return nil
 }
 
+// queueCompliance keeps the compliance on disk if the service could not be reached
+func (u *ComplianceUploader) queueCompliance(instanceID string, executionTime time.Time, itemContentHash string, items []*ssm.ComplianceItemEntry, err error) {
+	if u.queue == nil || !storeforward.IsConnectivityError(err) {
+		return
+	}
+	log := u.context.Log()
+	params := &ssm.PutComplianceItemsInput{
+		ResourceId:       aws.String(instanceID),
+		ResourceType:     aws.String("ManagedInstance"),
+		ComplianceType:   aws.String(associationComplianceType),
+		ExecutionSummary: &ssm.ComplianceExecutionSummary{ExecutionTime: aws.Time(executionTime)},
+		ItemContentHash:  aws.String(itemContentHash),
+		Items:            items,
+	}
+	if err = u.queue.Enqueue(log, params); err != nil {
+		log.Warnf("Failed to queue association compliance: %v", err)
+		return
+	}
+	log.Infof("Association compliance will be reported once the service can be reached")
+}
+
+// NewQueuedComplianceSender returns the sender of the association compliance queued while the service could not be reached
+func NewQueuedComplianceSender(context context.T) storeforward.Sender {
+	var service ssmSvc.Service
+	return func(content []byte) (err error) {
+		var params ssm.PutComplianceItemsInput
+		if err = json.Unmarshal(content, &params); err != nil || params.ExecutionSummary == nil {
+			context.Log().Warnf("Dropping invalid queued association compliance: %v", err)
+			return nil
+		}
+		if service == nil {
+			service = ssmSvc.NewService(context)
+		}
+		_, err = service.PutComplianceItems(
+			context.Log(),
+			params.ExecutionSummary.ExecutionTime,
+			"",
+			"",
+			aws.StringValue(params.ResourceId),
+			aws.StringValue(params.ComplianceType),
+			aws.StringValue(params.ItemContentHash),
+			params.Items)
+		return err
+	}
+}
+
 // ConvertToSsmAssociationComplianceItems converts given array of complianceItem into an array of *ssm.ComplianceItemEntry. It returns 2 such arrays - one is optimized array
 // which contains only contentHash for those compliance types where the dataset hasn't changed from previous collection. The other array is non-optimized array
 // which contains both contentHash & content. This is done to avoid iterating over the compliance data twice. It throws error when it encounters error during
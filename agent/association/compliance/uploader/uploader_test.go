@@ -15,6 +15,8 @@ package compliance
 
 import (
 	"encoding/json"
+	"fmt"
+	"path/filepath"
 	"strconv"
 	"testing"
 	"time"
@@ -219,3 +221,36 @@ func TestChecksumSameForSameContent(t *testing.T) {
 
 	assert.Equal(t, calculateCheckSum(dataB1), calculateCheckSum(dataB2))
 }
+
+func TestUpdateAssociationComplianceCachesItems(t *testing.T) {
+	u := MockComplianceUploader()
+	u.cachePath = filepath.Join(t.TempDir(), "compliance", "associationCompliance")
+
+	serviceMock := ssmSvc.NewMockDefault()
+	u.ssmSvc = serviceMock
+	serviceMock.On(
+		"PutComplianceItems",
+		mock.AnythingOfType("*log.Mock"),
+		mock.AnythingOfType("*time.Time"),
+		mock.AnythingOfType("string"),
+		mock.AnythingOfType("string"),
+		mock.AnythingOfType("string"),
+		mock.AnythingOfType("string"),
+		mock.AnythingOfType("string"),
+		mock.AnythingOfType("[]*ssm.ComplianceItemEntry")).Return(&ssm.PutComplianceItemsOutput{}, fmt.Errorf("service unavailable"))
+
+	err := u.UpdateAssociationCompliance("association_cached", "i-123", "testDoc", "1", "Failed", time.Now())
+	assert.Error(t, err)
+
+	// the compliance is cached even though it could not be reported
+	items, err := model.ReadAssociationComplianceItems(u.cachePath)
+	assert.NoError(t, err)
+	found := false
+	for _, item := range items {
+		if item.AssociationId == "association_cached" {
+			found = true
+			assert.Equal(t, model.NON_COMPLIANT, item.ComplianceStatus)
+		}
+	}
+	assert.True(t, found)
+}
@@ -16,8 +16,10 @@ package model
 
 import (
 	"fmt"
+	"hash/fnv"
 	"time"
 
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
 	"github.com/aws/amazon-ssm-agent/agent/association/scheduleexpression"
 	"github.com/aws/amazon-ssm-agent/agent/contracts"
 	"github.com/aws/amazon-ssm-agent/agent/log"
@@ -37,6 +39,45 @@ type InstanceAssociation struct {
 	Errors            []error
 }
 
+// SchedulePolicy spreads and restricts the scheduled runs of associations on this instance
+type SchedulePolicy struct {
+	// InstanceID seeds the per instance delay, so it stays the same across agent restarts
+	InstanceID string
+	// MaxJitter is the upper bound of the delay added to scheduled runs
+	MaxJitter time.Duration
+	// BlackoutWindows are local time windows in which scheduled runs are not started
+	BlackoutWindows []*appconfig.DailyTimeWindowCfg
+}
+
+// offset returns the delay added to the scheduled runs of the association on this instance
+func (policy SchedulePolicy) offset(associationID string) time.Duration {
+	if policy.MaxJitter < time.Second {
+		return 0
+	}
+	hash := fnv.New32a()
+	hash.Write([]byte(policy.InstanceID + "/" + associationID))
+	return time.Duration(hash.Sum32()%uint32(policy.MaxJitter/time.Second+1)) * time.Second
+}
+
+// outsideBlackoutWindows moves a run scheduled inside a blackout window to the end of the window plus the offset
+func (policy SchedulePolicy) outsideBlackoutWindows(scheduled time.Time, offset time.Duration) time.Time {
+	local := scheduled.Local()
+	// windows may adjoin or overlap, bound the moves in case they cover every day completely
+	for moves := 0; moves <= 7*len(policy.BlackoutWindows); moves++ {
+		moved := false
+		for _, window := range policy.BlackoutWindows {
+			if end, ok := times.DailyWindowEnd(local, window.Start, window.End, window.Days); ok {
+				local = end.Add(offset)
+				moved = true
+			}
+		}
+		if !moved {
+			break
+		}
+	}
+	return local.UTC()
+}
+
 // ParseExpression parses the expression with the given association
 func (newAssoc *InstanceAssociation) ParseExpression(log log.T) error {
 
@@ -60,8 +101,9 @@ func (newAssoc *InstanceAssociation) RunNow() {
 	newAssoc.NextScheduledDate = aws.Time(time.Now().UTC())
 }
 
-// SetNextScheduledDate sets next scheduled date for the given association
-func (newAssoc *InstanceAssociation) SetNextScheduledDate(log log.T) {
+// SetNextScheduledDate sets next scheduled date for the given association.
+// Associations pending to be applied and run once associations are not subject to the schedule policy.
+func (newAssoc *InstanceAssociation) SetNextScheduledDate(log log.T, policy SchedulePolicy) {
 	// Run association immediately if DetailedStatus is Pending
 	if newAssoc.Association.DetailedStatus != nil &&
 		*newAssoc.Association.DetailedStatus == contracts.AssociationStatusPending {
@@ -81,9 +123,11 @@ func (newAssoc *InstanceAssociation) SetNextScheduledDate(log log.T) {
 		return
 	}
 
-	// Run association immediately if association has not been run before
+	offset := policy.offset(aws.StringValue(newAssoc.Association.AssociationId))
+
+	// Run association as soon as the policy allows if association has not been run before
 	if newAssoc.Association.LastExecutionDate == nil {
-		newAssoc.RunNow()
+		newAssoc.NextScheduledDate = aws.Time(policy.outsideBlackoutWindows(time.Now().UTC().Add(offset), offset))
 		return
 	}
 
@@ -98,9 +142,10 @@ func (newAssoc *InstanceAssociation) SetNextScheduledDate(log log.T) {
 		}
 	}
 
-	// Set next schedule date of association according to it's schedule
-	newAssoc.NextScheduledDate = aws.Time(
-		newAssoc.ParsedExpression.Next(newAssoc.Association.LastExecutionDate.UTC()).UTC())
+	// Set next schedule date of association according to it's schedule. The last execution ran no earlier than
+	// offset after its scheduled date, so the schedule is evaluated from the scheduled date to avoid drifting.
+	nextScheduledDate := newAssoc.ParsedExpression.Next(newAssoc.Association.LastExecutionDate.UTC().Add(-offset)).UTC()
+	newAssoc.NextScheduledDate = aws.Time(policy.outsideBlackoutWindows(nextScheduledDate.Add(offset), offset))
 	log.Infof("Based upon expression %v and last execution date %v, next scheduled date for association %v is %v",
 		*newAssoc.Association.ScheduleExpression, times.ToIsoDashUTC(*newAssoc.Association.LastExecutionDate),
 		*newAssoc.Association.AssociationId, times.ToIsoDashUTC(*newAssoc.NextScheduledDate))
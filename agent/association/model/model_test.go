@@ -17,6 +17,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
 	"github.com/aws/amazon-ssm-agent/agent/association/scheduleexpression"
 	"github.com/aws/amazon-ssm-agent/agent/log/logger"
 	"github.com/aws/aws-sdk-go/service/ssm"
@@ -137,7 +138,7 @@ func TestNextScheduledDateIsCorrectWhenParsedExpressionIsValidCronExpression(t *
 	expectedNextScheduledDateTime := time.Date(
 		2009, 11, 17, 21, 00, 00, 000000000, time.UTC)
 	// Act
-	assocRawData.SetNextScheduledDate(logger)
+	assocRawData.SetNextScheduledDate(logger, SchedulePolicy{})
 
 	// Assert
 	assert.Equal(t, expectedNextScheduledDateTime, *assocRawData.NextScheduledDate)
@@ -172,7 +173,7 @@ func TestNextScheduledDateIsCorrectWhenParsedExpressionIsValidUpperCasedCronExpr
 	expectedNextScheduledDateTime := time.Date(
 		2009, 11, 17, 21, 00, 00, 000000000, time.UTC)
 	// Act
-	assocRawData.SetNextScheduledDate(logger)
+	assocRawData.SetNextScheduledDate(logger, SchedulePolicy{})
 
 	// Assert
 	assert.Equal(t, expectedNextScheduledDateTime, *assocRawData.NextScheduledDate)
@@ -204,7 +205,7 @@ func TestNextScheduledDateIsCorrectWhenExpressionIsValidCronAndHasNotBeenParsedB
 		2009, 11, 17, 21, 00, 00, 000000000, time.UTC)
 
 	// Act
-	assocRawData.SetNextScheduledDate(logger)
+	assocRawData.SetNextScheduledDate(logger, SchedulePolicy{})
 
 	// Assert
 	assert.Equal(t, expectedNextScheduledDateTime, *assocRawData.NextScheduledDate)
@@ -233,7 +234,7 @@ func TestNextScheduledDateIsNilWhenCronExpressionIsInvalid(t *testing.T) {
 	assocRawData.Association.LastExecutionDate = &lastExecutionDateTime
 
 	// Act
-	assocRawData.SetNextScheduledDate(logger)
+	assocRawData.SetNextScheduledDate(logger, SchedulePolicy{})
 
 	// Assert
 	assert.Nil(t, assocRawData.NextScheduledDate)
@@ -264,7 +265,7 @@ func TestNextScheduledDateIsCorrectWhenParsedExpressionIsValidRateExpression(t *
 	expectedNextScheduledDateTime := time.Date(
 		2009, 11, 20, 20, 34, 58, 651387237, time.UTC)
 	// Act
-	testInstanceAssociation.SetNextScheduledDate(logger)
+	testInstanceAssociation.SetNextScheduledDate(logger, SchedulePolicy{})
 
 	// Assert
 	assert.Equal(t, expectedNextScheduledDateTime, *testInstanceAssociation.NextScheduledDate)
@@ -293,7 +294,7 @@ func TestNextScheduledDateIsCorrectWhenExpressionIsValidRateAndHasNoteBeenParsed
 	expectedNextScheduledDateTime := time.Date(
 		2009, 11, 20, 20, 34, 58, 651387237, time.UTC)
 	// Act
-	testInstanceAssociation.SetNextScheduledDate(logger)
+	testInstanceAssociation.SetNextScheduledDate(logger, SchedulePolicy{})
 
 	// Assert
 	assert.Equal(t, expectedNextScheduledDateTime, *testInstanceAssociation.NextScheduledDate)
@@ -321,7 +322,7 @@ func TestNextScheduleDateIsNilWhenRateExpressionIsInvalid(t *testing.T) {
 	assocRawData.Association.LastExecutionDate = &lastExecutionDateTime
 
 	// Act
-	assocRawData.SetNextScheduledDate(logger)
+	assocRawData.SetNextScheduledDate(logger, SchedulePolicy{})
 
 	// Assert
 	assert.Nil(t, assocRawData.NextScheduledDate)
@@ -350,7 +351,7 @@ func TestNextScheduleDateIsNilWhenExpressionTypeIsUnknownAndAssociationHasPrevio
 	assocRawData.Association.LastExecutionDate = &lastExecutionDateTime
 
 	// Act
-	assocRawData.SetNextScheduledDate(logger)
+	assocRawData.SetNextScheduledDate(logger, SchedulePolicy{})
 
 	// Assert
 	assert.Nil(t, assocRawData.NextScheduledDate)
@@ -379,8 +380,54 @@ func TestNextScheduledDateIsNilWhenAssociationExpressionTypeIsUnknown(t *testing
 	assocRawData.Association.LastExecutionDate = &lastExecutionDateTime
 
 	// Act
-	assocRawData.SetNextScheduledDate(logger)
+	assocRawData.SetNextScheduledDate(logger, SchedulePolicy{})
 
 	// Assert
 	assert.Nil(t, assocRawData.NextScheduledDate)
 }
+
+func TestNextScheduledDateKeepsPerInstanceJitter(t *testing.T) {
+	logger := logger.DefaultLogger()
+	policy := SchedulePolicy{InstanceID: "i-0123456789abcdef0", MaxJitter: 10 * time.Minute}
+	assocId := "b2f71a28-cbe1-4429-b848-26c7e1f5ad0d"
+	offset := policy.offset(assocId)
+	assert.True(t, offset >= 0 && offset <= 10*time.Minute)
+	assert.Equal(t, offset, policy.offset(assocId))
+	assert.NotEqual(t, offset, SchedulePolicy{InstanceID: "i-0123456789abcdef1", MaxJitter: 10 * time.Minute}.offset(assocId))
+
+	testCronExpression := "cron(0 0 0/1 * * ? *)" // hourly cron expression
+	assocRawData := InstanceAssociation{Association: &ssm.InstanceAssociationSummary{
+		Name:               &assocId,
+		AssociationId:      &assocId,
+		ScheduleExpression: &testCronExpression,
+	}}
+	// the previous run was delayed by the offset and took a few seconds
+	lastExecutionDateTime := time.Date(2009, 11, 17, 20, 0, 5, 0, time.UTC).Add(offset)
+	assocRawData.Association.LastExecutionDate = &lastExecutionDateTime
+
+	assocRawData.SetNextScheduledDate(logger, policy)
+
+	assert.Equal(t, time.Date(2009, 11, 17, 21, 0, 0, 0, time.UTC).Add(offset), *assocRawData.NextScheduledDate)
+}
+
+func TestNextScheduledDateSkipsBlackoutWindows(t *testing.T) {
+	logger := logger.DefaultLogger()
+	policy := SchedulePolicy{BlackoutWindows: []*appconfig.DailyTimeWindowCfg{
+		{Start: "08:00", End: "12:00"},
+		{Start: "12:00", End: "13:00"},
+	}}
+	assocId := "b2f71a28-cbe1-4429-b848-26c7e1f5ad0d"
+	testRateExpression := "rate(1 hour)"
+	assocRawData := InstanceAssociation{Association: &ssm.InstanceAssociationSummary{
+		Name:               &assocId,
+		AssociationId:      &assocId,
+		ScheduleExpression: &testRateExpression,
+	}}
+	lastExecutionDateTime := time.Date(2009, 11, 17, 7, 30, 0, 0, time.Local).UTC()
+	assocRawData.Association.LastExecutionDate = &lastExecutionDateTime
+
+	// the run due at 08:30 is moved past both adjoining windows
+	assocRawData.SetNextScheduledDate(logger, policy)
+
+	assert.Equal(t, time.Date(2009, 11, 17, 13, 0, 0, 0, time.Local).UTC(), *assocRawData.NextScheduledDate)
+}
@@ -82,6 +82,13 @@ func NewAssociationProcessor(context context.T) *Processor {
 	assocSvc := service.NewAssociationService(context, name)
 	uploader := complianceUploader.NewComplianceUploader(context)
 
+	instanceID, _ := assocContext.Identity().InstanceID()
+	schedulemanager.SetSchedulePolicy(model.SchedulePolicy{
+		InstanceID:      instanceID,
+		MaxJitter:       time.Duration(config.Ssm.AssociationScheduleJitterSeconds) * time.Second,
+		BlackoutWindows: config.Ssm.AssociationBlackoutWindows,
+	})
+
 	//TODO Rename everything to service and move package to framework
 	startWorker := processor.NewWorkerProcessorSpec(assocContext, documentWorkersLimit, contracts.Association, 0)
 	terminateWorker := processor.NewWorkerProcessorSpec(assocContext, documentWorkersLimit, "", 0) //association has no cancel worker
@@ -367,6 +374,10 @@ func (p *Processor) runScheduledAssociation(log log.T) {
 
 	updatePluginAssociationInstances(*scheduledAssociation.Association.AssociationId, docState)
 	log = p.context.With("[associationId=" + docState.DocumentInformation.AssociationID + "]").Log()
+	if isDetectOnly(p.context.AppConfig().Ssm.AssociationDetectOnly, scheduledAssociation) {
+		log.Infof("Running association %v in detect-only mode, changes are reported but not applied", *scheduledAssociation.Association.Name)
+		setDetectOnly(docState)
+	}
 	instanceID, _ := p.context.Identity().InstanceID()
 	p.assocSvc.UpdateInstanceAssociationStatus(
 		log,
@@ -680,3 +691,22 @@ func updatePluginAssociationInstances(associationID string, docState *contracts.
 	}
 	return
 }
+
+// isDetectOnly checks whether the association is configured to only report the changes it would make
+func isDetectOnly(detectOnly []string, assoc *model.InstanceAssociation) bool {
+	for _, value := range detectOnly {
+		if value == "*" ||
+			value == *assoc.Association.AssociationId ||
+			value == *assoc.Association.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// setDetectOnly asks every plugin of the document to report changes without applying them
+func setDetectOnly(docState *contracts.DocumentState) {
+	for i := range docState.InstancePluginsInformation {
+		docState.InstancePluginsInformation[i].Configuration.DetectOnly = true
+	}
+}
@@ -281,6 +281,33 @@ func TestRemovePluginAssociationInstances(t *testing.T) {
 	assert.Equal(t, resultMap, pluginAssociationInstances)
 }
 
+func TestIsDetectOnly(t *testing.T) {
+	assoc := &model.InstanceAssociation{
+		Association: &ssm.InstanceAssociationSummary{
+			AssociationId: aws.String("testAssociationID"),
+			Name:          aws.String("testName"),
+		},
+	}
+	assert.False(t, isDetectOnly([]string{}, assoc))
+	assert.False(t, isDetectOnly([]string{"otherID"}, assoc))
+	assert.True(t, isDetectOnly([]string{"otherID", "testAssociationID"}, assoc))
+	assert.True(t, isDetectOnly([]string{"testName"}, assoc))
+	assert.True(t, isDetectOnly([]string{"*"}, assoc))
+}
+
+func TestSetDetectOnly(t *testing.T) {
+	testDocState := contracts.DocumentState{
+		InstancePluginsInformation: []contracts.PluginState{
+			{Name: "aws:configurePackage"},
+			{Name: "aws:runShellScript"},
+		},
+	}
+	setDetectOnly(&testDocState)
+	for _, plugin := range testDocState.InstancePluginsInformation {
+		assert.True(t, plugin.Configuration.DetectOnly)
+	}
+}
+
 func mockParser(parserMock *processor2.ParserMock, payload *messageContracts.SendCommandPayload, docState contracts.DocumentState) {
 	parserMock.On(
 		"InitializeDocumentState",
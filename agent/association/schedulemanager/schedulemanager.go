@@ -30,8 +30,16 @@ import (
 )
 
 var associations = []*model.InstanceAssociation{}
+var schedulePolicy model.SchedulePolicy
 var lock sync.RWMutex
 
+// SetSchedulePolicy sets the jitter and blackout windows applied to scheduled associations from the next refresh on
+func SetSchedulePolicy(policy model.SchedulePolicy) {
+	lock.Lock()
+	defer lock.Unlock()
+	schedulePolicy = policy
+}
+
 // Refresh refreshes cached associationRawData
 func Refresh(log log.T, assocs []*model.InstanceAssociation) {
 	lock.Lock()
@@ -50,7 +58,7 @@ func Refresh(log log.T, assocs []*model.InstanceAssociation) {
 
 	numberOfNewAssoc := 0
 	for _, assoc := range associations {
-		assoc.SetNextScheduledDate(log)
+		assoc.SetNextScheduledDate(log, schedulePolicy)
 		if assoc.NextScheduledDate != nil {
 			log.Infof("Scheduling association %v, setting next ScheduledDate to %v", *assoc.Association.AssociationId, times.ToIsoDashUTC(*assoc.NextScheduledDate))
 		}
@@ -131,7 +139,7 @@ func UpdateNextScheduledDate(log log.T, associationID string) {
 	for _, assoc := range associations {
 		if *assoc.Association.AssociationId == associationID {
 			assoc.Association.LastExecutionDate = aws.Time(time.Now().UTC())
-			assoc.SetNextScheduledDate(log)
+			assoc.SetNextScheduledDate(log, schedulePolicy)
 			if assoc.NextScheduledDate != nil {
 				log.Infof("Scheduling association %v, setting next ScheduledDate to %v", *assoc.Association.AssociationId, times.ToIsoDashUTC(*assoc.NextScheduledDate))
 			}
@@ -0,0 +1,153 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package archiver implements the core module archiving rotated audit log files to S3
+package archiver
+
+import (
+	"path"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/auditlog"
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/s3util"
+)
+
+const (
+	// ModuleName is the name of the audit log archiver core module
+	ModuleName = "AuditLogArchiver"
+
+	archiveInterval = 15 * time.Minute
+)
+
+type s3Uploader interface {
+	S3Upload(log log.T, bucketName string, objectKey string, filePath string) error
+}
+
+var (
+	newS3Uploader = func(context context.T, bucketName string) (s3Uploader, error) {
+		return s3util.NewAmazonS3Util(context, bucketName)
+	}
+	listRotated  = auditlog.ListRotated
+	markUploaded = auditlog.MarkUploaded
+)
+
+// Archiver periodically uploads the rotated audit log files to the S3 bucket configured in appconfig
+type Archiver struct {
+	context context.T
+	config  appconfig.AuditLogCfg
+
+	mtx      sync.Mutex
+	stopChan chan struct{}
+	doneChan chan struct{}
+}
+
+// NewArchiver returns an audit log archiver core module
+func NewArchiver(context context.T) *Archiver {
+	return &Archiver{
+		context: context.With("[" + ModuleName + "]"),
+		config:  context.AppConfig().AuditLog,
+	}
+}
+
+// ModuleName returns the module name
+func (a *Archiver) ModuleName() string {
+	return ModuleName
+}
+
+// ModuleExecute starts the archive loop
+func (a *Archiver) ModuleExecute() (err error) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	a.stopChan = make(chan struct{})
+	a.doneChan = make(chan struct{})
+	go a.archiveLoop(archiveInterval, a.stopChan, a.doneChan)
+	return nil
+}
+
+// ModuleStop stops the archive loop
+func (a *Archiver) ModuleStop() (err error) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if a.stopChan != nil {
+		close(a.stopChan)
+		<-a.doneChan
+		a.stopChan = nil
+	}
+	return nil
+}
+
+func (a *Archiver) archiveLoop(interval time.Duration, stopChan chan struct{}, doneChan chan struct{}) {
+	log := a.context.Log()
+	defer func() {
+		if msg := recover(); msg != nil {
+			log.Errorf("Audit log archiver panic: %v", msg)
+			log.Errorf("Stacktrace:\n%s", debug.Stack())
+		}
+		close(doneChan)
+	}()
+
+	a.archive()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+			a.archive()
+		}
+	}
+}
+
+// archive uploads the rotated files that were not archived yet, the current file is archived once it is rotated
+func (a *Archiver) archive() {
+	log := a.context.Log()
+	files, err := listRotated()
+	if err != nil {
+		log.Warnf("Failed to list audit log files: %v", err)
+		return
+	}
+	var s3 s3Uploader
+	for _, file := range files {
+		if file.Uploaded {
+			continue
+		}
+		if s3 == nil {
+			if s3, err = newS3Uploader(a.context, a.config.S3BucketName); err != nil {
+				log.Warnf("Failed to create S3 client for bucket %v: %v", a.config.S3BucketName, err)
+				return
+			}
+		}
+		instanceID, err := a.context.Identity().InstanceID()
+		if err != nil {
+			log.Warnf("Failed to get the instance id to archive audit log files: %v", err)
+			return
+		}
+		objectKey := path.Join(a.config.S3KeyPrefix, instanceID, file.Name)
+		if err = s3.S3Upload(log, a.config.S3BucketName, objectKey, file.Path); err != nil {
+			log.Warnf("Failed to archive audit log file %v: %v", file.Name, err)
+			continue
+		}
+		log.Infof("Archived audit log file %v to s3://%v/%v", file.Name, a.config.S3BucketName, objectKey)
+		if err = markUploaded(file); err != nil {
+			log.Warnf("Failed to mark audit log file %v as archived: %v", file.Name, err)
+		}
+	}
+}
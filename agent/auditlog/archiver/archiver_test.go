@@ -0,0 +1,97 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package archiver
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/auditlog"
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	contextmocks "github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	identitymocks "github.com/aws/amazon-ssm-agent/common/identity/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeS3 struct {
+	uploaded map[string]string
+	err      error
+}
+
+func (f *fakeS3) S3Upload(log log.T, bucketName string, objectKey string, filePath string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.uploaded[bucketName+"/"+objectKey] = filePath
+	return nil
+}
+
+func setupTest(t *testing.T, s3 *fakeS3) (marked *[]string) {
+	originalNewS3Uploader, originalListRotated, originalMarkUploaded := newS3Uploader, listRotated, markUploaded
+	t.Cleanup(func() {
+		newS3Uploader, listRotated, markUploaded = originalNewS3Uploader, originalListRotated, originalMarkUploaded
+	})
+
+	marked = &[]string{}
+	newS3Uploader = func(context context.T, bucketName string) (s3Uploader, error) {
+		return s3, nil
+	}
+	listRotated = func() ([]auditlog.File, error) {
+		return []auditlog.File{
+			{Name: "audit-1.log", Path: "/var/lib/amazon/ssm/auditlog/audit-1.log", Uploaded: true},
+			{Name: "audit-2.log", Path: "/var/lib/amazon/ssm/auditlog/audit-2.log"},
+		}, nil
+	}
+	markUploaded = func(file auditlog.File) error {
+		*marked = append(*marked, file.Name)
+		return nil
+	}
+	return marked
+}
+
+func newTestArchiver() *Archiver {
+	return &Archiver{
+		context: contextmocks.NewMockDefault(),
+		config: appconfig.AuditLogCfg{
+			Enabled:      true,
+			S3BucketName: "bucket",
+			S3KeyPrefix:  "audit",
+		},
+	}
+}
+
+func TestArchiveRotatedFiles(t *testing.T) {
+	s3 := &fakeS3{uploaded: map[string]string{}}
+	marked := setupTest(t, s3)
+
+	archiver := newTestArchiver()
+	assert.Equal(t, ModuleName, archiver.ModuleName())
+	archiver.archive()
+
+	assert.Equal(t, map[string]string{
+		"bucket/audit/" + identitymocks.MockInstanceID + "/audit-2.log": "/var/lib/amazon/ssm/auditlog/audit-2.log",
+	}, s3.uploaded)
+	assert.Equal(t, []string{"audit-2.log"}, *marked)
+}
+
+func TestArchiveFailureDoesNotMarkFile(t *testing.T) {
+	s3 := &fakeS3{uploaded: map[string]string{}, err: errors.New("access denied")}
+	marked := setupTest(t, s3)
+
+	newTestArchiver().archive()
+
+	assert.Empty(t, *marked)
+}
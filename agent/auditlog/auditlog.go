@@ -0,0 +1,336 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package auditlog keeps an append-only, hash-chained local record of the commands, sessions and
+// associations received from the control plane, independent of CloudTrail
+package auditlog
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/contracts"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+)
+
+const (
+	// DirName is the name of the directory under the agent data folder holding the audit log files
+	DirName = "auditlog"
+	// UploadedMarkerSuffix is appended to the name of a rotated file to mark it as archived
+	UploadedMarkerSuffix = ".uploaded"
+
+	// ActionReceived is recorded when a document is accepted for execution
+	ActionReceived = "Received"
+	// ActionRejected is recorded when a document is not accepted for execution
+	ActionRejected = "Rejected"
+	// ActionCancelReceived is recorded when the cancellation of a document is received
+	ActionCancelReceived = "CancelReceived"
+	// ActionCompleted is recorded with the final status of a document
+	ActionCompleted = "Completed"
+
+	currentFileName        = "audit.log"
+	rotatedFilePrefix      = "audit-"
+	rotatedFileSuffix      = ".log"
+	rotatedTimestampLayout = "20060102T150405.000000000Z"
+)
+
+var (
+	auditDir = filepath.Join(appconfig.DefaultDataStorePath, DirName)
+	timeNow  = time.Now
+
+	// the chain is only written by the agent process, this serializes the document processors
+	chainLock sync.Mutex
+	chain     *chainState
+)
+
+// Entry is one line of the audit log
+type Entry struct {
+	Sequence         uint64    `json:"sequence"`
+	Time             time.Time `json:"time"`
+	Action           string    `json:"action"`
+	DocumentType     string    `json:"documentType"`
+	DocumentID       string    `json:"documentId"`
+	MessageID        string    `json:"messageId,omitempty"`
+	CommandID        string    `json:"commandId,omitempty"`
+	AssociationID    string    `json:"associationId,omitempty"`
+	DocumentName     string    `json:"documentName,omitempty"`
+	DocumentVersion  string    `json:"documentVersion,omitempty"`
+	Caller           string    `json:"caller,omitempty"`
+	RunAsUser        string    `json:"runAsUser,omitempty"`
+	ParametersDigest string    `json:"parametersDigest,omitempty"`
+	Outcome          string    `json:"outcome,omitempty"`
+	PreviousHash     string    `json:"previousHash"`
+	Hash             string    `json:"hash"`
+}
+
+// File describes a rotated audit log file
+type File struct {
+	Name     string
+	Path     string
+	Uploaded bool
+}
+
+type chainState struct {
+	sequence uint64
+	hash     string
+}
+
+// NewDocumentEntry returns the entry recording an action on a document, the document name is the ARN for shared documents
+func NewDocumentEntry(action string, docState *contracts.DocumentState, outcome string) Entry {
+	info := docState.DocumentInformation
+	caller := info.SessionOwner
+	if caller == "" {
+		caller = info.ClientId
+	}
+	return Entry{
+		Action:           action,
+		DocumentType:     string(docState.DocumentType),
+		DocumentID:       info.DocumentID,
+		MessageID:        info.MessageID,
+		CommandID:        info.CommandID,
+		AssociationID:    info.AssociationID,
+		DocumentName:     info.DocumentName,
+		DocumentVersion:  info.DocumentVersion,
+		Caller:           caller,
+		RunAsUser:        info.RunAsUser,
+		ParametersDigest: parametersDigest(docState),
+		Outcome:          outcome,
+	}
+}
+
+// Record appends the entry to the audit log, chaining it to the previous entry.
+// Failures are logged, auditing never blocks the execution of documents.
+func Record(log log.T, config appconfig.AuditLogCfg, entry Entry) {
+	if !config.Enabled {
+		return
+	}
+	chainLock.Lock()
+	defer chainLock.Unlock()
+
+	if err := record(config, entry); err != nil {
+		log.Errorf("Failed to record %v of document %v in the audit log: %v", entry.Action, entry.DocumentID, err)
+	}
+}
+
+func record(config appconfig.AuditLogCfg, entry Entry) error {
+	if err := os.MkdirAll(auditDir, appconfig.ReadWriteExecuteAccess); err != nil {
+		return err
+	}
+	if chain == nil {
+		state, err := loadChainState()
+		if err != nil {
+			return fmt.Errorf("failed to read the last audit log entry: %v", err)
+		}
+		chain = state
+	}
+
+	entry.Sequence = chain.sequence + 1
+	entry.Time = timeNow().UTC()
+	entry.PreviousHash = chain.hash
+	entry.Hash = ""
+	hash, err := hashEntry(entry)
+	if err != nil {
+		return err
+	}
+	entry.Hash = hash
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	currentPath := filepath.Join(auditDir, currentFileName)
+	if info, statErr := os.Stat(currentPath); statErr == nil && info.Size()+int64(len(line)) > int64(config.MaxFileSizeMB)*1024*1024 {
+		if err = rotate(config.MaxFiles); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.OpenFile(currentPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, appconfig.ReadWriteAccess)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if _, err = file.Write(line); err != nil {
+		return err
+	}
+	if err = file.Sync(); err != nil {
+		return err
+	}
+	chain = &chainState{sequence: entry.Sequence, hash: entry.Hash}
+	return nil
+}
+
+// rotate renames the current file with a timestamp and deletes the oldest rotated files above maxFiles
+func rotate(maxFiles int) error {
+	rotatedName := rotatedFilePrefix + timeNow().UTC().Format(rotatedTimestampLayout) + rotatedFileSuffix
+	if err := os.Rename(filepath.Join(auditDir, currentFileName), filepath.Join(auditDir, rotatedName)); err != nil {
+		return fmt.Errorf("failed to rotate audit log: %v", err)
+	}
+	files, err := ListRotated()
+	if err != nil {
+		return err
+	}
+	for len(files) > maxFiles {
+		os.Remove(files[0].Path)
+		os.Remove(files[0].Path + UploadedMarkerSuffix)
+		files = files[1:]
+	}
+	return nil
+}
+
+// ListRotated returns the rotated audit log files, oldest first
+func ListRotated() ([]File, error) {
+	paths, err := filepath.Glob(filepath.Join(auditDir, rotatedFilePrefix+"*"+rotatedFileSuffix))
+	if err != nil {
+		return nil, err
+	}
+	// the timestamp in the name sorts chronologically
+	sort.Strings(paths)
+	files := make([]File, 0, len(paths))
+	for _, path := range paths {
+		_, markerErr := os.Stat(path + UploadedMarkerSuffix)
+		files = append(files, File{Name: filepath.Base(path), Path: path, Uploaded: markerErr == nil})
+	}
+	return files, nil
+}
+
+// MarkUploaded records that the rotated file has been archived
+func MarkUploaded(file File) error {
+	return os.WriteFile(file.Path+UploadedMarkerSuffix, []byte(timeNow().UTC().Format(time.RFC3339)), appconfig.ReadWriteAccess)
+}
+
+// Verify checks the hashes and the chaining of every entry in the retained audit log files.
+// The first retained entry can not be linked to its predecessor once older files were deleted.
+func Verify() (entries int, err error) {
+	files, err := ListRotated()
+	if err != nil {
+		return 0, err
+	}
+	paths := make([]string, 0, len(files)+1)
+	for _, file := range files {
+		paths = append(paths, file.Path)
+	}
+	if _, statErr := os.Stat(filepath.Join(auditDir, currentFileName)); statErr == nil {
+		paths = append(paths, filepath.Join(auditDir, currentFileName))
+	}
+
+	var previous *Entry
+	for _, path := range paths {
+		err = readEntries(path, func(entry Entry) error {
+			hash, hashErr := hashEntry(entry)
+			if hashErr != nil {
+				return hashErr
+			}
+			if hash != entry.Hash {
+				return fmt.Errorf("entry %d in %v was modified", entry.Sequence, path)
+			}
+			if previous != nil && (entry.PreviousHash != previous.Hash || entry.Sequence != previous.Sequence+1) {
+				return fmt.Errorf("entry %d in %v does not follow entry %d", entry.Sequence, path, previous.Sequence)
+			}
+			previous = &entry
+			entries++
+			return nil
+		})
+		if err != nil {
+			return entries, err
+		}
+	}
+	return entries, nil
+}
+
+// loadChainState returns the sequence and hash of the last entry, which is in the current file or,
+// right after a rotation, in the newest rotated file
+func loadChainState() (*chainState, error) {
+	paths := []string{filepath.Join(auditDir, currentFileName)}
+	if files, err := ListRotated(); err == nil && len(files) > 0 {
+		paths = append(paths, files[len(files)-1].Path)
+	}
+	for _, path := range paths {
+		var last *Entry
+		err := readEntries(path, func(entry Entry) error {
+			last = &entry
+			return nil
+		})
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		if last != nil {
+			return &chainState{sequence: last.Sequence, hash: last.Hash}, nil
+		}
+	}
+	return &chainState{}, nil
+}
+
+func readEntries(path string, handle func(entry Entry) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var entry Entry
+		if err = json.Unmarshal([]byte(line), &entry); err != nil {
+			return fmt.Errorf("invalid entry in %v: %v", path, err)
+		}
+		if err = handle(entry); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// hashEntry returns the sha256 of the entry without its own hash, the previous hash links the entries
+func hashEntry(entry Entry) (string, error) {
+	entry.Hash = ""
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// parametersDigest returns the sha256 of the resolved step parameters, the parameters themselves may contain secrets
+func parametersDigest(docState *contracts.DocumentState) string {
+	if len(docState.InstancePluginsInformation) == 0 {
+		return ""
+	}
+	properties := make([]interface{}, 0, len(docState.InstancePluginsInformation))
+	for _, plugin := range docState.InstancePluginsInformation {
+		properties = append(properties, plugin.Configuration.Properties)
+	}
+	content, err := json.Marshal(properties)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,148 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package auditlog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/contracts"
+	logmocks "github.com/aws/amazon-ssm-agent/agent/mocks/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupTest(t *testing.T) (now *time.Time) {
+	originalAuditDir, originalTimeNow := auditDir, timeNow
+	t.Cleanup(func() {
+		auditDir, timeNow, chain = originalAuditDir, originalTimeNow, nil
+	})
+
+	auditDir = filepath.Join(t.TempDir(), DirName)
+	chain = nil
+	currentTime := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return currentTime }
+	return &currentTime
+}
+
+func testConfig() appconfig.AuditLogCfg {
+	return appconfig.AuditLogCfg{Enabled: true, MaxFileSizeMB: 1, MaxFiles: 2}
+}
+
+func testDocument() *contracts.DocumentState {
+	return &contracts.DocumentState{
+		DocumentType: contracts.SendCommand,
+		DocumentInformation: contracts.DocumentInfo{
+			DocumentID:   "command-id",
+			CommandID:    "command-id",
+			MessageID:    "aws.ssm.command-id.i-123",
+			DocumentName: "arn:aws:ssm:us-east-1:123456789012:document/Shared",
+			ClientId:     "client",
+		},
+		InstancePluginsInformation: []contracts.PluginState{
+			{Configuration: contracts.Configuration{Properties: map[string]interface{}{"commands": []string{"echo secret"}}}},
+		},
+	}
+}
+
+func TestNewDocumentEntry(t *testing.T) {
+	entry := NewDocumentEntry(ActionCompleted, testDocument(), "Success")
+
+	assert.Equal(t, ActionCompleted, entry.Action)
+	assert.Equal(t, "SendCommand", entry.DocumentType)
+	assert.Equal(t, "arn:aws:ssm:us-east-1:123456789012:document/Shared", entry.DocumentName)
+	assert.Equal(t, "client", entry.Caller)
+	assert.Equal(t, "Success", entry.Outcome)
+	assert.Len(t, entry.ParametersDigest, 64)
+	assert.NotContains(t, entry.ParametersDigest, "secret")
+}
+
+func TestRecordDisabled(t *testing.T) {
+	setupTest(t)
+
+	Record(logmocks.NewMockLog(), appconfig.AuditLogCfg{}, NewDocumentEntry(ActionReceived, testDocument(), ""))
+
+	_, err := os.Stat(auditDir)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRecordChainsEntries(t *testing.T) {
+	setupTest(t)
+	log := logmocks.NewMockLog()
+
+	Record(log, testConfig(), NewDocumentEntry(ActionReceived, testDocument(), ""))
+	Record(log, testConfig(), NewDocumentEntry(ActionCompleted, testDocument(), "Success"))
+	// a restarted agent continues the chain from the file
+	chain = nil
+	Record(log, testConfig(), NewDocumentEntry(ActionReceived, testDocument(), ""))
+
+	var entries []Entry
+	assert.NoError(t, readEntries(filepath.Join(auditDir, currentFileName), func(entry Entry) error {
+		entries = append(entries, entry)
+		return nil
+	}))
+	assert.Len(t, entries, 3)
+	assert.Equal(t, "", entries[0].PreviousHash)
+	assert.Equal(t, entries[0].Hash, entries[1].PreviousHash)
+	assert.Equal(t, entries[1].Hash, entries[2].PreviousHash)
+	assert.Equal(t, uint64(3), entries[2].Sequence)
+
+	count, err := Verify()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+}
+
+func TestVerifyDetectsModifiedEntry(t *testing.T) {
+	setupTest(t)
+	log := logmocks.NewMockLog()
+	Record(log, testConfig(), NewDocumentEntry(ActionReceived, testDocument(), ""))
+	Record(log, testConfig(), NewDocumentEntry(ActionCompleted, testDocument(), "Failed"))
+
+	path := filepath.Join(auditDir, currentFileName)
+	content, _ := os.ReadFile(path)
+	os.WriteFile(path, []byte(strings.Replace(string(content), `"outcome":"Failed"`, `"outcome":"Success"`, 1)), 0600)
+
+	_, err := Verify()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "entry 2")
+}
+
+func TestRecordRotatesAndPrunes(t *testing.T) {
+	now := setupTest(t)
+	log := logmocks.NewMockLog()
+	currentPath := filepath.Join(auditDir, currentFileName)
+
+	Record(log, testConfig(), NewDocumentEntry(ActionReceived, testDocument(), ""))
+	for i := 0; i < 3; i++ {
+		// grow the current file above the size limit so the next entry rotates it
+		file, _ := os.OpenFile(currentPath, os.O_APPEND|os.O_WRONLY, 0600)
+		file.Truncate(1024 * 1024)
+		file.Close()
+		*now = now.Add(time.Second)
+		chain = &chainState{sequence: uint64(i + 1), hash: "hash"}
+		Record(log, testConfig(), NewDocumentEntry(ActionReceived, testDocument(), ""))
+	}
+
+	files, err := ListRotated()
+	assert.NoError(t, err)
+	assert.Len(t, files, 2)
+	assert.False(t, files[0].Uploaded)
+
+	assert.NoError(t, MarkUploaded(files[0]))
+	files, _ = ListRotated()
+	assert.True(t, files[0].Uploaded)
+}
@@ -103,7 +103,8 @@ func parseCommand(args []string) (err error, options []string, command string, s
 		if cliutil.IsFlag(val) {
 			break
 		}
-		subcommands = append(options, strings.ToLower(val))
+		// subcommands keep their case as they may carry values, such as config set <key> <value>
+		subcommands = append(subcommands, val)
 		pos++
 	}
 
@@ -113,7 +114,7 @@ func parseCommand(args []string) (err error, options []string, command string, s
 	}
 	parameters = make(map[string][]string)
 	var parameterName string
-	for _, val := range args[2:] {
+	for _, val := range args[pos:] {
 		if cliutil.IsFlag(val) {
 			parameterName = cliutil.GetFlag(val)
 			if parameterName == "" {
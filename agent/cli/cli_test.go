@@ -16,11 +16,13 @@ package cli
 
 import (
 	"bytes"
+	"path/filepath"
 	"testing"
 
 	"github.com/aws/amazon-ssm-agent/agent/cli/clicommand"
 	"github.com/aws/amazon-ssm-agent/agent/cli/cliutil"
 	CliCommandMock "github.com/aws/amazon-ssm-agent/agent/cli/cliutil/mocks"
+	"github.com/aws/amazon-ssm-agent/agent/log/logger"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -68,3 +70,61 @@ func TestCliCmdExecSuccess(t *testing.T) {
 	assert.Equal(t, cliutil.CLI_SUCCESS_EXITCODE, exitCode, "command execution success return exit code 0")
 	cliCmdMock.AssertExpectations(t)
 }
+
+func TestCliCmdExecMultipleSubcommands(t *testing.T) {
+	var buffer bytes.Buffer
+	cliCmdMock := &CliCommandMock.CliCommand{}
+	cliCmdMock.On("Name").Return("cli-command-subcommands-mock").Once()
+	cliCmdMock.On("Execute", []string{"set", "Agent.Region", "us-east-1"}, map[string][]string{"no-restart": {}}).Return(nil, "success").Once()
+	cliutil.Register(cliCmdMock)
+
+	args := []string{"ssm-cli", "cli-command-subcommands-mock", "set", "Agent.Region", "us-east-1", "--no-restart"}
+	exitCode := RunCommand(args, &buffer)
+	assert.Equal(t, cliutil.CLI_SUCCESS_EXITCODE, exitCode, buffer.String())
+	cliCmdMock.AssertExpectations(t)
+}
+
+func TestCliSetLogLevel(t *testing.T) {
+	originalPath := logger.DefaultSeelogConfigFilePath
+	logger.DefaultSeelogConfigFilePath = filepath.Join(t.TempDir(), "seelog.xml")
+	defer func() { logger.DefaultSeelogConfigFilePath = originalPath }()
+
+	var buffer bytes.Buffer
+	exitCode := RunCommand([]string{"ssm-cli", "set-log-level", "debug", "--duration", "30m"}, &buffer)
+	assert.Equal(t, cliutil.CLI_SUCCESS_EXITCODE, exitCode, buffer.String())
+	assert.Contains(t, buffer.String(), "Log level set to debug until")
+
+	override, err := logger.ReadLogLevelOverride()
+	assert.NoError(t, err)
+	assert.Equal(t, "debug", override.Level)
+	assert.NotNil(t, override.ExpiresAt)
+
+	buffer.Reset()
+	exitCode = RunCommand([]string{"ssm-cli", "set-log-level", "reset"}, &buffer)
+	assert.Equal(t, cliutil.CLI_SUCCESS_EXITCODE, exitCode, buffer.String())
+	override, err = logger.ReadLogLevelOverride()
+	assert.NoError(t, err)
+	assert.Nil(t, override)
+
+	buffer.Reset()
+	exitCode = RunCommand([]string{"ssm-cli", "set-log-level", "verbose", "--duration", "-5m"}, &buffer)
+	assert.Equal(t, cliutil.CLI_COMMAND_FAIL_EXITCODE, exitCode)
+	assert.Contains(t, buffer.String(), "invalid log level verbose")
+	assert.Contains(t, buffer.String(), "invalid duration -5m")
+}
+
+func TestCliClearRegistrationInvalidInput(t *testing.T) {
+	var buffer bytes.Buffer
+	exitCode := RunCommand([]string{"ssm-cli", "clear-registration", "now", "--force"}, &buffer)
+	assert.Equal(t, cliutil.CLI_COMMAND_FAIL_EXITCODE, exitCode)
+	assert.Contains(t, buffer.String(), "clear-registration does not expect any subcommands")
+	assert.Contains(t, buffer.String(), "unknown parameter --force")
+}
+
+func TestCliGeneralizeInvalidInput(t *testing.T) {
+	var buffer bytes.Buffer
+	exitCode := RunCommand([]string{"ssm-cli", "generalize", "now", "--shutdown"}, &buffer)
+	assert.Equal(t, cliutil.CLI_COMMAND_FAIL_EXITCODE, exitCode)
+	assert.Contains(t, buffer.String(), "generalize does not expect any subcommands")
+	assert.Contains(t, buffer.String(), "unknown parameter --shutdown")
+}
@@ -0,0 +1,292 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package clicommand contains the implementation of all commands for the ssm agent cli
+package clicommand
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/cli/cliutil"
+	"github.com/aws/amazon-ssm-agent/agent/contracts"
+	"github.com/aws/amazon-ssm-agent/agent/framework/docmanager"
+	"github.com/aws/amazon-ssm-agent/agent/jsonutil"
+	"github.com/aws/amazon-ssm-agent/agent/log/logger"
+	"github.com/aws/amazon-ssm-agent/core/executor"
+)
+
+const (
+	agentProcessesCommand           = "agent-processes"
+	agentProcessesListSubcommand    = "list"
+	agentProcessesCleanupSubcommand = "cleanup"
+	agentProcessesMinAge            = "min-age"
+	agentProcessesDryRun            = "dry-run"
+	defaultOrphanMinAgeMinutes      = 10
+)
+
+const agentProcessesHelp = `NAME:
+    {{.AgentProcessesName}}
+
+DESCRIPTION
+    Lists and cleans up the processes spawned by the amazon-ssm-agent service: the document workers
+    running commands and associations, the session workers running Session Manager sessions, and the
+    scripts, shells and other processes started by these workers.
+
+    {{.ListName}} prints every worker followed by its descendants, with the command, association or
+    session id the worker was started for and the age of the process. A worker is orphaned when the
+    agent does not track a pending or in-progress document with its id, for example because the worker
+    was left behind by a crash of the agent. The descendants of an orphaned worker are orphaned too.
+    Workers of documents in progress survive agent restarts by design and are not orphaned.
+
+    {{.CleanupName}} terminates the orphaned processes older than {{.MinAgeFlag}} minutes, {{.DefaultMinAge}} by default,
+    descendants before their worker. Processes whose worker exited already can not be attributed to
+    the agent and are not listed.
+
+SYNOPSIS
+    {{.AgentProcessesName}} [{{.ListName}}]
+    {{.AgentProcessesName}} {{.CleanupName}} [{{.MinAgeFlag}} <minutes>] [{{.DryRunFlag}}]
+
+PARAMETERS
+    {{.MinAgeFlag}} (integer) Only terminate orphaned processes running for at least this many minutes.
+    {{.DryRunFlag}} (boolean) Print the processes that would be terminated without terminating them.
+
+EXAMPLES
+    Command:
+
+      {{.SsmCliName}} {{.AgentProcessesName}}
+
+    Output:
+      [
+        {
+          "Pid": 4210,
+          "ParentPid": 1,
+          "Kind": "DocumentWorker",
+          "CommandID": "01234567-890a-bcde-f012-34567890abcd",
+          "WorkerPid": 4210,
+          "AgeSeconds": 86400,
+          "Orphaned": true,
+          "CommandLine": "/usr/bin/ssm-document-worker 01234567-890a-bcde-f012-34567890abcd"
+        }
+      ]
+
+    Command:
+
+      {{.SsmCliName}} {{.AgentProcessesName}} {{.CleanupName}} {{.MinAgeFlag}} 60
+
+OUTPUT
+    List of processes in JSON format, for {{.CleanupName}} the orphaned processes that were terminated
+`
+
+type agentProcessesHelpParams struct {
+	SsmCliName         string
+	AgentProcessesName string
+	ListName           string
+	CleanupName        string
+	MinAgeFlag         string
+	DryRunFlag         string
+	DefaultMinAge      int
+}
+
+// agentProcessSummary is the entry printed for each process by agent-processes
+type agentProcessSummary struct {
+	Pid         int
+	ParentPid   int
+	Kind        executor.ProcessKind
+	CommandID   string
+	WorkerPid   int
+	AgeSeconds  int64
+	Orphaned    bool
+	CommandLine string
+}
+
+func init() {
+	cliutil.Register(&AgentProcessesCommand{})
+}
+
+type AgentProcessesCommand struct {
+	helpText string
+}
+
+// Execute validates and executes the agent-processes cli command
+func (c *AgentProcessesCommand) Execute(subcommands []string, parameters map[string][]string) (error, string) {
+	validation, minAge, dryRun := c.validateAgentProcessesInput(subcommands, parameters)
+	// return validation errors if any were found
+	if len(validation) > 0 {
+		return errors.New(strings.Join(validation, "\n")), ""
+	}
+
+	processExecutor := executor.NewProcessExecutor(logger.NewSilentLogger())
+	summaries, err := c.list(processExecutor)
+	if err != nil {
+		return err, ""
+	}
+
+	if len(subcommands) > 0 && strings.EqualFold(subcommands[0], agentProcessesCleanupSubcommand) {
+		return c.cleanup(processExecutor, summaries, minAge, dryRun)
+	}
+
+	result, err := jsonutil.MarshalIndent(summaries)
+	if err != nil {
+		return err, ""
+	}
+	return nil, result
+}
+
+// list returns the processes of the agent, marking the processes of workers without a pending or in-progress document as orphaned
+func (AgentProcessesCommand) list(processExecutor executor.IExecutor) ([]agentProcessSummary, error) {
+	agentIdentity, err := cliutil.GetAgentIdentity()
+	if err != nil {
+		return nil, err
+	}
+	instanceID, err := agentIdentity.ShortInstanceID()
+	if err != nil {
+		return nil, err
+	}
+
+	inFlight := make(map[string]bool)
+	for _, record := range docmanager.ListExecutionRecords(logger.NewSilentLogger(), instanceID) {
+		if record.Status == contracts.ResultStatusInProgress || record.Status == docmanager.ExecutionStatusPending {
+			inFlight[record.DocumentID] = true
+		}
+	}
+
+	processes, err := processExecutor.AgentProcesses()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes: %v", err)
+	}
+	summaries := make([]agentProcessSummary, 0, len(processes))
+	for _, process := range processes {
+		summaries = append(summaries, agentProcessSummary{
+			Pid:         process.Pid,
+			ParentPid:   process.PPid,
+			Kind:        process.Kind,
+			CommandID:   process.CommandID,
+			WorkerPid:   process.WorkerPid,
+			AgeSeconds:  int64(process.Age / time.Second),
+			Orphaned:    !inFlight[process.CommandID],
+			CommandLine: process.CommandLine,
+		})
+	}
+	return summaries, nil
+}
+
+// cleanup terminates the orphaned processes running for at least minAge, the descendants of a worker are listed
+// after the worker and terminated before it
+func (AgentProcessesCommand) cleanup(processExecutor executor.IExecutor, summaries []agentProcessSummary, minAge time.Duration, dryRun bool) (error, string) {
+	terminated := make([]agentProcessSummary, 0)
+	failures := make([]string, 0)
+	for i := len(summaries) - 1; i >= 0; i-- {
+		summary := summaries[i]
+		if !summary.Orphaned || time.Duration(summary.AgeSeconds)*time.Second < minAge {
+			continue
+		}
+		if !dryRun {
+			if err := processExecutor.Kill(summary.Pid); err != nil {
+				failures = append(failures, err.Error())
+				continue
+			}
+		}
+		terminated = append([]agentProcessSummary{summary}, terminated...)
+	}
+
+	result, err := jsonutil.MarshalIndent(terminated)
+	if err != nil {
+		return err, ""
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("failed to terminate orphaned processes: %v", strings.Join(failures, "; ")), result
+	}
+	return nil, result
+}
+
+// Help prints help for the agent-processes cli command
+func (c *AgentProcessesCommand) Help() string {
+	if len(c.helpText) == 0 {
+		t, _ := template.New("AgentProcessesHelp").Parse(agentProcessesHelp)
+		params := agentProcessesHelpParams{
+			cliutil.SsmCliName,
+			agentProcessesCommand,
+			agentProcessesListSubcommand,
+			agentProcessesCleanupSubcommand,
+			cliutil.FormatFlag(agentProcessesMinAge),
+			cliutil.FormatFlag(agentProcessesDryRun),
+			defaultOrphanMinAgeMinutes,
+		}
+		buf := new(bytes.Buffer)
+		t.Execute(buf, params)
+		c.helpText = buf.String()
+	}
+	return c.helpText
+}
+
+// Name is the command name used in the cli
+func (AgentProcessesCommand) Name() string {
+	return agentProcessesCommand
+}
+
+// validateAgentProcessesInput checks the subcommands and parameters for unsupported values
+func (AgentProcessesCommand) validateAgentProcessesInput(subcommands []string, parameters map[string][]string) (validation []string, minAge time.Duration, dryRun bool) {
+	validation = make([]string, 0)
+	minAge = defaultOrphanMinAgeMinutes * time.Minute
+
+	if len(subcommands) > 1 {
+		validation = append(validation, fmt.Sprintf("%v expects at most one subcommand", agentProcessesCommand))
+		return validation, minAge, dryRun
+	}
+	isCleanup := false
+	if len(subcommands) == 1 {
+		switch strings.ToLower(subcommands[0]) {
+		case agentProcessesListSubcommand:
+		case agentProcessesCleanupSubcommand:
+			isCleanup = true
+		default:
+			validation = append(validation, fmt.Sprintf("%v does not support subcommand %v", agentProcessesCommand, subcommands[0]))
+			return validation, minAge, dryRun
+		}
+	}
+
+	for key, values := range parameters {
+		switch key {
+		case agentProcessesMinAge:
+			if !isCleanup {
+				validation = append(validation, fmt.Sprintf("flag %v is only supported by %v", cliutil.FormatFlag(key), agentProcessesCleanupSubcommand))
+				continue
+			}
+			minutes := -1
+			if len(values) == 1 {
+				minutes, _ = strconv.Atoi(values[0])
+			}
+			if minutes < 0 {
+				validation = append(validation, fmt.Sprintf("flag %v expects a number of minutes", cliutil.FormatFlag(key)))
+				continue
+			}
+			minAge = time.Duration(minutes) * time.Minute
+		case agentProcessesDryRun:
+			if !isCleanup {
+				validation = append(validation, fmt.Sprintf("flag %v is only supported by %v", cliutil.FormatFlag(key), agentProcessesCleanupSubcommand))
+			} else if len(values) > 0 {
+				validation = append(validation, fmt.Sprintf("flag %v should not have any values", cliutil.FormatFlag(key)))
+			}
+			dryRun = true
+		default:
+			validation = append(validation, fmt.Sprintf("unknown parameter %v", cliutil.FormatFlag(key)))
+		}
+	}
+	return validation, minAge, dryRun
+}
@@ -0,0 +1,107 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package clicommand contains the implementation of all commands for the ssm agent cli
+package clicommand
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/aws/amazon-ssm-agent/agent/cli/cliutil"
+	"github.com/aws/amazon-ssm-agent/agent/log/logger"
+	"github.com/aws/amazon-ssm-agent/agent/managedInstances/registration"
+)
+
+const (
+	clearRegistrationCommand = "clear-registration"
+)
+
+const clearRegistrationHelp = `NAME:
+    {{.ClearRegistrationName}}
+
+DESCRIPTION
+    Removes the managed instance identity from the instance: the registration keys, the instance id,
+    the instance fingerprint and the credentials cached by the agent.
+    Run it before capturing a golden image so that the instances launched from the image
+    register as new managed instances. Stop the agent first, a running agent keeps using its identity.
+
+SYNOPSIS
+    {{.ClearRegistrationName}}
+
+EXAMPLES
+    This example removes the registration before an image is captured.
+
+    Command:
+
+      {{.SsmCliName}} {{.ClearRegistrationName}}
+
+    Output:
+
+      Registration information has been removed from the instance
+
+OUTPUT
+    A confirmation that the registration was removed
+`
+
+type clearRegistrationHelpParams struct {
+	SsmCliName            string
+	ClearRegistrationName string
+}
+
+func init() {
+	cliutil.Register(&ClearRegistrationCommand{})
+}
+
+type ClearRegistrationCommand struct {
+	helpText string
+}
+
+// Execute validates and executes the clear-registration cli command
+func (c *ClearRegistrationCommand) Execute(subcommands []string, parameters map[string][]string) (error, string) {
+	validation := make([]string, 0)
+	if len(subcommands) > 0 {
+		validation = append(validation, fmt.Sprintf("%v does not expect any subcommands", clearRegistrationCommand))
+	}
+	for key := range parameters {
+		validation = append(validation, fmt.Sprintf("unknown parameter %v", cliutil.FormatFlag(key)))
+	}
+	if len(validation) > 0 {
+		return errors.New(strings.Join(validation, "\n")), ""
+	}
+
+	if err := registration.Cleanup(logger.NewSilentLogger()); err != nil {
+		return fmt.Errorf("%v\nTry running as sudo/administrator.", err), ""
+	}
+	return nil, "Registration information has been removed from the instance"
+}
+
+// Help prints help for the clear-registration cli command
+func (c *ClearRegistrationCommand) Help() string {
+	if len(c.helpText) == 0 {
+		t, _ := template.New("ClearRegistrationHelp").Parse(clearRegistrationHelp)
+		params := clearRegistrationHelpParams{cliutil.SsmCliName, clearRegistrationCommand}
+		buf := new(bytes.Buffer)
+		t.Execute(buf, params)
+		c.helpText = buf.String()
+	}
+	return c.helpText
+}
+
+// Name is the command name used in the cli
+func (ClearRegistrationCommand) Name() string {
+	return clearRegistrationCommand
+}
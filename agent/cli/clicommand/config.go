@@ -0,0 +1,284 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package clicommand contains the implementation of all commands for the ssm agent cli
+package clicommand
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/cli/cliutil"
+	"github.com/aws/amazon-ssm-agent/agent/cli/diagnosticsutil"
+	"github.com/aws/amazon-ssm-agent/agent/jsonutil"
+	"github.com/aws/amazon-ssm-agent/agent/log/logger"
+)
+
+const (
+	configCommand          = "config"
+	configGetSubcommand    = "get"
+	configSetSubcommand    = "set"
+	configNoRestart        = "no-restart"
+	configLoggingSection   = "Logging"
+	configRestartTimeout   = 60 * time.Second
+	configRestartFailedMsg = "%v was updated but restarting amazon-ssm-agent failed, restart it to apply the change: %v"
+)
+
+const configHelp = `NAME:
+    {{.ConfigName}}
+
+DESCRIPTION
+    Reads and edits the amazon-ssm-agent configuration file {{.ConfigPath}}.
+
+    {{.GetName}} prints the value the agent uses for a setting, after defaults and validation are applied.
+    Without a key the complete configuration is printed.
+
+    {{.SetName}} validates the value against the type and the allowed range of the setting and writes
+    it to the configuration file. Other settings in the file are preserved, the previous file is kept
+    as {{.ConfigPath}}{{.BackupSuffix}} and the file is replaced atomically. The agent is then restarted
+    to load the new configuration, unless {{.NoRestartFlag}} is given. Do not restart the agent when
+    running this command through Run Command, as the restart interrupts the command itself.
+
+    Keys are the dot separated names of the settings, for example Agent.Region or Mds.CommandWorkersLimit.
+    Lists, maps and sections are given as JSON, lists of strings may also be comma separated.
+
+    Setting a {{.LoggingSection}} option (Level, MaxFileSizeMB, MaxRolls or Format) also generates {{.SeelogPath}}
+    from the {{.LoggingSection}} section, the previous file is kept as {{.SeelogPath}}{{.BackupSuffix}}. The agent
+    reloads its log configuration without a restart.
+
+SYNOPSIS
+    {{.ConfigName}} {{.GetName}} [<key>]
+    {{.ConfigName}} {{.SetName}} <key> <value> [{{.NoRestartFlag}}]
+
+PARAMETERS
+    {{.NoRestartFlag}} (boolean) Only write the configuration file, the change applies on the next agent restart.
+
+EXAMPLES
+    Command:
+
+      {{.SsmCliName}} {{.ConfigName}} {{.GetName}} Mds.CommandWorkersLimit
+
+    Output:
+
+      5
+
+    Command:
+
+      {{.SsmCliName}} {{.ConfigName}} {{.SetName}} Mds.CommandWorkersLimit 10
+
+    Output:
+
+      Mds.CommandWorkersLimit set to 10 in {{.ConfigPath}}, previous configuration saved to {{.ConfigPath}}{{.BackupSuffix}}
+      Restarted amazon-ssm-agent to apply the change
+
+    Command:
+
+      {{.SsmCliName}} {{.ConfigName}} {{.SetName}} Logging.Level debug
+
+    Output:
+
+      Logging.Level set to debug in {{.ConfigPath}}, previous configuration saved to {{.ConfigPath}}{{.BackupSuffix}}
+      Generated {{.SeelogPath}} from the Logging section, amazon-ssm-agent reloads it without a restart
+
+OUTPUT
+    The value of the setting in JSON format, or the outcome of the change
+`
+
+type configHelpParams struct {
+	SsmCliName     string
+	ConfigName     string
+	GetName        string
+	SetName        string
+	NoRestartFlag  string
+	ConfigPath     string
+	BackupSuffix   string
+	LoggingSection string
+	SeelogPath     string
+}
+
+func init() {
+	cliutil.Register(&ConfigCommand{})
+}
+
+type ConfigCommand struct {
+	helpText string
+}
+
+// Execute validates and executes the config cli command
+func (c *ConfigCommand) Execute(subcommands []string, parameters map[string][]string) (error, string) {
+	validation, restart := c.validateConfigInput(subcommands, parameters)
+	// return validation errors if any were found
+	if len(validation) > 0 {
+		return errors.New(strings.Join(validation, "\n")), ""
+	}
+
+	if strings.EqualFold(subcommands[0], configGetSubcommand) {
+		return c.get(subcommands[1:])
+	}
+	return c.set(subcommands[1], subcommands[2], restart)
+}
+
+// get prints the effective value of the setting, or the complete configuration without key
+func (ConfigCommand) get(keys []string) (error, string) {
+	config, err := appconfig.LoadConfigFile(appconfig.AppConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to load %v: %v", appconfig.AppConfigPath, err), ""
+	}
+
+	var value interface{} = config
+	if len(keys) == 1 {
+		if value, err = appconfig.ConfigValue(config, keys[0]); err != nil {
+			return err, ""
+		}
+	}
+
+	result, err := jsonutil.MarshalIndent(value)
+	if err != nil {
+		return err, ""
+	}
+	return nil, result
+}
+
+// set writes the setting to the configuration file and restarts the agent when requested
+func (ConfigCommand) set(key, value string, restart bool) (error, string) {
+	backupPath, err := appconfig.SetConfigFileValue(appconfig.AppConfigPath, key, value)
+	if err != nil {
+		return err, ""
+	}
+
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("%v set to %v in %v", key, value, appconfig.AppConfigPath))
+	if backupPath != "" {
+		result.WriteString(fmt.Sprintf(", previous configuration saved to %v", backupPath))
+	}
+
+	if section := strings.Split(key, ".")[0]; strings.EqualFold(section, configLoggingSection) {
+		// the config watcher of the agent processes reloads seelog.xml
+		if err = writeLoggingConfig(); err != nil {
+			return err, result.String()
+		}
+		result.WriteString(fmt.Sprintf("\nGenerated %v from the Logging section, amazon-ssm-agent reloads it without a restart", logger.DefaultSeelogConfigFilePath))
+		return nil, result.String()
+	}
+
+	if !restart {
+		result.WriteString("\nRestart amazon-ssm-agent to apply the change")
+		return nil, result.String()
+	}
+
+	// the agent may wait for in-flight documents before it stops
+	timeout := configRestartTimeout
+	if config, err := appconfig.LoadConfigFile(appconfig.AppConfigPath); err == nil {
+		timeout += time.Duration(config.Shutdown.DrainPeriodSeconds) * time.Second
+	}
+	if err = restartAgentService(timeout); err != nil {
+		return fmt.Errorf(configRestartFailedMsg, appconfig.AppConfigPath, err), result.String()
+	}
+	result.WriteString("\nRestarted amazon-ssm-agent to apply the change")
+	return nil, result.String()
+}
+
+// writeLoggingConfig generates seelog.xml from the Logging section of the configuration file
+func writeLoggingConfig() error {
+	config, err := appconfig.LoadConfigFile(appconfig.AppConfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to load %v: %v", appconfig.AppConfigPath, err)
+	}
+	if _, err = logger.WriteSeelogConfig(logger.DefaultSeelogConfigFilePath, config.Logging); err != nil {
+		return fmt.Errorf("failed to generate %v: %v", logger.DefaultSeelogConfigFilePath, err)
+	}
+	return nil
+}
+
+// runServiceCommand runs a service manager command, including its output in the error
+func runServiceCommand(timeout time.Duration, cmd string, args ...string) error {
+	output, err := diagnosticsutil.ExecuteCommandWithTimeout(timeout, cmd, args...)
+	if err != nil && output != "" {
+		return fmt.Errorf("%v: %v", err, output)
+	}
+	return err
+}
+
+// Help prints help for the config cli command
+func (c *ConfigCommand) Help() string {
+	if len(c.helpText) == 0 {
+		t, _ := template.New("ConfigHelp").Parse(configHelp)
+		params := configHelpParams{
+			cliutil.SsmCliName,
+			configCommand,
+			configGetSubcommand,
+			configSetSubcommand,
+			cliutil.FormatFlag(configNoRestart),
+			appconfig.AppConfigPath,
+			appconfig.ConfigBackupSuffix,
+			configLoggingSection,
+			logger.DefaultSeelogConfigFilePath,
+		}
+		buf := new(bytes.Buffer)
+		t.Execute(buf, params)
+		c.helpText = buf.String()
+	}
+	return c.helpText
+}
+
+// Name is the command name used in the cli
+func (ConfigCommand) Name() string {
+	return configCommand
+}
+
+// validateConfigInput checks the subcommands and parameters for required values and unsupported values
+func (ConfigCommand) validateConfigInput(subcommands []string, parameters map[string][]string) (validation []string, restart bool) {
+	validation = make([]string, 0)
+	restart = true
+
+	if len(subcommands) == 0 {
+		validation = append(validation, fmt.Sprintf("%v expects subcommand %v or %v", configCommand, configGetSubcommand, configSetSubcommand))
+		return validation, restart
+	}
+
+	switch strings.ToLower(subcommands[0]) {
+	case configGetSubcommand:
+		if len(subcommands) > 2 {
+			validation = append(validation, fmt.Sprintf("%v %v expects at most one key", configCommand, configGetSubcommand))
+		}
+	case configSetSubcommand:
+		if len(subcommands) != 3 {
+			validation = append(validation, fmt.Sprintf("%v %v expects a key and a value", configCommand, configSetSubcommand))
+		}
+	default:
+		validation = append(validation, fmt.Sprintf("%v does not support subcommand %v", configCommand, subcommands[0]))
+		return validation, restart
+	}
+
+	if values, exists := parameters[configNoRestart]; exists {
+		if !strings.EqualFold(subcommands[0], configSetSubcommand) {
+			validation = append(validation, fmt.Sprintf("flag %v is only supported by %v", cliutil.FormatFlag(configNoRestart), configSetSubcommand))
+		} else if len(values) > 0 {
+			validation = append(validation, fmt.Sprintf("flag %v should not have any values", cliutil.FormatFlag(configNoRestart)))
+		}
+		restart = false
+	}
+
+	// look for unsupported parameters
+	for key := range parameters {
+		if key != configNoRestart {
+			validation = append(validation, fmt.Sprintf("unknown parameter %v", cliutil.FormatFlag(key)))
+		}
+	}
+	return validation, restart
+}
@@ -0,0 +1,41 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build freebsd || linux || netbsd || openbsd
+// +build freebsd linux netbsd openbsd
+
+package clicommand
+
+import (
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/cli/diagnosticsutil"
+)
+
+// restartAgentService restarts the agent with the service manager it is installed with
+func restartAgentService(timeout time.Duration) error {
+	if diagnosticsutil.IsAgentInstalledSnap() {
+		return runServiceCommand(timeout, "snap", "restart", "amazon-ssm-agent")
+	}
+	if _, err := exec.LookPath("systemctl"); err == nil {
+		return runServiceCommand(timeout, "systemctl", "restart", "amazon-ssm-agent.service")
+	}
+	if _, err := exec.LookPath("initctl"); err == nil {
+		// upstart refuses to restart a stopped job, stop errors are expected in that case
+		runServiceCommand(timeout, "initctl", "stop", "amazon-ssm-agent")
+		return runServiceCommand(timeout, "initctl", "start", "amazon-ssm-agent")
+	}
+	return fmt.Errorf("no supported service manager found")
+}
@@ -0,0 +1,184 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package clicommand contains the implementation of all commands for the ssm agent cli
+package clicommand
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/cli/cliutil"
+	"github.com/aws/amazon-ssm-agent/agent/framework/coremanager/modulecontrol"
+	"github.com/aws/amazon-ssm-agent/agent/jsonutil"
+	"github.com/aws/amazon-ssm-agent/agent/log/logger"
+	"github.com/twinj/uuid"
+)
+
+const (
+	coreModulesCommand = "core-modules"
+	// coreModulesTimeout leaves the agent time to stop the module before it is restarted or disabled
+	coreModulesTimeout = 30 * time.Second
+)
+
+const coreModulesHelp = `NAME:
+    {{.CoreModulesName}}
+
+DESCRIPTION
+    Lists the core modules of the running amazon-ssm-agent service with their state, and restarts,
+    disables or enables a single core module without restarting the service. Sessions and commands
+    handled by the other core modules keep running.
+
+    The state of a module is Pending before the module is started, Running, Failed when the module
+    failed to start, Stopping, Stopped or Disabled. Only restartable modules can be restarted,
+    disabled and enabled, the other modules require a restart of amazon-ssm-agent.
+
+    A disabled module is enabled again when amazon-ssm-agent restarts. Use the Modules section of the
+    agent configuration to disable a module permanently.
+
+SYNOPSIS
+    {{.CoreModulesName}} [{{.ListName}}]
+    {{.CoreModulesName}} {{.RestartName}} <module>
+    {{.CoreModulesName}} {{.DisableName}} <module>
+    {{.CoreModulesName}} {{.EnableName}} <module>
+
+EXAMPLES
+    Command:
+
+      {{.SsmCliName}} {{.CoreModulesName}} {{.RestartName}} MessageService
+
+    Output:
+      [
+        {
+          "Name": "HealthCheck",
+          "State": "Running",
+          "Restartable": true
+        },
+        {
+          "Name": "MessageService",
+          "State": "Running",
+          "Restartable": true
+        }
+      ]
+
+OUTPUT
+    List of core modules in JSON format, after the requested action
+`
+
+type coreModulesHelpParams struct {
+	SsmCliName      string
+	CoreModulesName string
+	ListName        string
+	RestartName     string
+	DisableName     string
+	EnableName      string
+}
+
+func init() {
+	cliutil.Register(&CoreModulesCommand{})
+}
+
+type CoreModulesCommand struct {
+	helpText string
+}
+
+// Execute validates and executes the core-modules cli command
+func (c *CoreModulesCommand) Execute(subcommands []string, parameters map[string][]string) (error, string) {
+	validation := c.validateCoreModulesInput(subcommands, parameters)
+	// return validation errors if any were found
+	if len(validation) > 0 {
+		return errors.New(strings.Join(validation, "\n")), ""
+	}
+
+	request := modulecontrol.Request{
+		RequestId: uuid.NewV4().String(),
+		Action:    modulecontrol.ActionList,
+	}
+	if len(subcommands) > 0 {
+		request.Action = strings.ToLower(subcommands[0])
+	}
+	if len(subcommands) > 1 {
+		request.ModuleName = subcommands[1]
+	}
+
+	agentIdentity, err := cliutil.GetAgentIdentity()
+	if err != nil {
+		return err, ""
+	}
+	response, err := modulecontrol.SendRequest(logger.NewSilentLogger(), agentIdentity, request, coreModulesTimeout)
+	if err != nil {
+		return err, ""
+	}
+
+	result, err := jsonutil.MarshalIndent(response.Modules)
+	if err != nil {
+		return err, ""
+	}
+	if response.Error != "" {
+		return fmt.Errorf("failed to %v core module %v: %v", request.Action, request.ModuleName, response.Error), result
+	}
+	return nil, result
+}
+
+// Help prints help for the core-modules cli command
+func (c *CoreModulesCommand) Help() string {
+	if len(c.helpText) == 0 {
+		t, _ := template.New("CoreModulesHelp").Parse(coreModulesHelp)
+		params := coreModulesHelpParams{
+			cliutil.SsmCliName,
+			coreModulesCommand,
+			modulecontrol.ActionList,
+			modulecontrol.ActionRestart,
+			modulecontrol.ActionDisable,
+			modulecontrol.ActionEnable,
+		}
+		buf := new(bytes.Buffer)
+		t.Execute(buf, params)
+		c.helpText = buf.String()
+	}
+	return c.helpText
+}
+
+// Name is the command name used in the cli
+func (CoreModulesCommand) Name() string {
+	return coreModulesCommand
+}
+
+// validateCoreModulesInput checks the subcommands and parameters for unsupported values
+func (CoreModulesCommand) validateCoreModulesInput(subcommands []string, parameters map[string][]string) []string {
+	validation := make([]string, 0)
+	for key := range parameters {
+		validation = append(validation, fmt.Sprintf("unknown parameter %v", cliutil.FormatFlag(key)))
+	}
+	if len(subcommands) == 0 {
+		return validation
+	}
+
+	switch strings.ToLower(subcommands[0]) {
+	case modulecontrol.ActionList:
+		if len(subcommands) > 1 {
+			validation = append(validation, fmt.Sprintf("%v %v does not expect a module", coreModulesCommand, modulecontrol.ActionList))
+		}
+	case modulecontrol.ActionRestart, modulecontrol.ActionDisable, modulecontrol.ActionEnable:
+		if len(subcommands) != 2 {
+			validation = append(validation, fmt.Sprintf("%v %v expects the name of one module", coreModulesCommand, subcommands[0]))
+		}
+	default:
+		validation = append(validation, fmt.Sprintf("%v does not support subcommand %v", coreModulesCommand, subcommands[0]))
+	}
+	return validation
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package clicommand contains the implementation of all commands for the ssm agent cli
+package clicommand
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/aws/amazon-ssm-agent/agent/cli/cliutil"
+	"github.com/aws/amazon-ssm-agent/agent/log/logger"
+	"github.com/aws/amazon-ssm-agent/agent/managedInstances/registration"
+)
+
+const (
+	generalizeCommand = "generalize"
+)
+
+const generalizeHelp = `NAME:
+    {{.GeneralizeName}}
+
+DESCRIPTION
+    Prepares the instance for imaging, analogous to sysprep. Removes the managed instance identity
+    like clear-registration does, clears the instance fingerprint and the hardware hash it was generated
+    from, and arms a fingerprint regeneration on first boot. Instances launched from the image generate
+    their own fingerprint instead of colliding on the hardware hash of the build instance.
+    Stop the agent first, and shut the instance down after generalizing it without starting the agent again.
+
+SYNOPSIS
+    {{.GeneralizeName}}
+
+EXAMPLES
+    This example generalizes the instance before an image is captured.
+
+    Command:
+
+      {{.SsmCliName}} {{.GeneralizeName}}
+
+    Output:
+
+      Instance has been generalized, shut it down before capturing the image
+
+OUTPUT
+    A confirmation that the instance was generalized
+`
+
+type generalizeHelpParams struct {
+	SsmCliName     string
+	GeneralizeName string
+}
+
+func init() {
+	cliutil.Register(&GeneralizeCommand{})
+}
+
+type GeneralizeCommand struct {
+	helpText string
+}
+
+// Execute validates and executes the generalize cli command
+func (c *GeneralizeCommand) Execute(subcommands []string, parameters map[string][]string) (error, string) {
+	validation := make([]string, 0)
+	if len(subcommands) > 0 {
+		validation = append(validation, fmt.Sprintf("%v does not expect any subcommands", generalizeCommand))
+	}
+	for key := range parameters {
+		validation = append(validation, fmt.Sprintf("unknown parameter %v", cliutil.FormatFlag(key)))
+	}
+	if len(validation) > 0 {
+		return errors.New(strings.Join(validation, "\n")), ""
+	}
+
+	if err := registration.Generalize(logger.NewSilentLogger()); err != nil {
+		return fmt.Errorf("%v\nTry running as sudo/administrator.", err), ""
+	}
+	return nil, "Instance has been generalized, shut it down before capturing the image"
+}
+
+// Help prints help for the generalize cli command
+func (c *GeneralizeCommand) Help() string {
+	if len(c.helpText) == 0 {
+		t, _ := template.New("GeneralizeHelp").Parse(generalizeHelp)
+		params := generalizeHelpParams{cliutil.SsmCliName, generalizeCommand}
+		buf := new(bytes.Buffer)
+		t.Execute(buf, params)
+		c.helpText = buf.String()
+	}
+	return c.helpText
+}
+
+// Name is the command name used in the cli
+func (GeneralizeCommand) Name() string {
+	return generalizeCommand
+}
@@ -0,0 +1,190 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package clicommand contains the implementation of all commands for the ssm agent cli
+package clicommand
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/association/compliance/model"
+	"github.com/aws/amazon-ssm-agent/agent/cli/cliutil"
+	"github.com/aws/amazon-ssm-agent/agent/fileutil"
+	"github.com/aws/amazon-ssm-agent/agent/jsonutil"
+	"github.com/aws/amazon-ssm-agent/agent/storeforward"
+)
+
+const (
+	getComplianceCommand      = "get-compliance"
+	getComplianceNonCompliant = "non-compliant"
+)
+
+const getComplianceCommandHelp = `NAME:
+    {{.GetComplianceCommandName}}
+
+DESCRIPTION
+    Prints the association compliance of this instance as recorded by the amazon-ssm-agent service,
+    read from the local compliance cache. The cache holds the result of the latest execution of each
+    association, including the executions that completed while the service could not be reached.
+
+    ReportPending is true when the latest compliance could not be reported to Systems Manager yet.
+    With StoreAndForward enabled in the agent configuration, the pending compliance is reported once
+    the service can be reached again.
+
+SYNOPSIS
+    {{.GetComplianceCommandName}} [{{.NonCompliantFlag}}]
+
+PARAMETERS
+    {{.NonCompliantFlag}} (boolean) Only print the associations whose latest execution did not succeed.
+
+EXAMPLES
+    Command:
+
+      {{.SsmCliName}} {{.GetComplianceCommandName}}
+
+    Output:
+      {
+        "ReportPending": true,
+        "Associations": [
+          {
+            "AssociationId": "01234567-890a-bcde-f012-34567890abcd",
+            "ExecutionTime": "2024-05-02T10:15:04.123Z",
+            "DocumentName": "AWS-RunShellScript",
+            "DocumentVersion": "1",
+            "ComplianceStatus": "NON_COMPLIANT"
+          }
+        ]
+      }
+
+OUTPUT
+    Association compliance in JSON format
+`
+
+type getComplianceHelpParams struct {
+	SsmCliName               string
+	GetComplianceCommandName string
+	NonCompliantFlag         string
+}
+
+// complianceSummary is the output of get-compliance
+type complianceSummary struct {
+	ReportPending bool
+	Associations  []associationCompliance
+}
+
+// associationCompliance is the entry printed for each association by get-compliance
+type associationCompliance struct {
+	AssociationId    string
+	ExecutionTime    time.Time
+	DocumentName     string
+	DocumentVersion  string
+	ComplianceStatus string
+}
+
+func init() {
+	cliutil.Register(&GetComplianceCommand{})
+}
+
+type GetComplianceCommand struct {
+	helpText string
+}
+
+// Execute validates and executes the get-compliance cli command
+func (c *GetComplianceCommand) Execute(subcommands []string, parameters map[string][]string) (error, string) {
+	validation := c.validateGetComplianceCommandInput(subcommands, parameters)
+	// return validation errors if any were found
+	if len(validation) > 0 {
+		return errors.New(strings.Join(validation, "\n")), ""
+	}
+	_, nonCompliantOnly := parameters[getComplianceNonCompliant]
+
+	agentIdentity, err := cliutil.GetAgentIdentity()
+	if err != nil {
+		return err, ""
+	}
+
+	instanceID, err := agentIdentity.InstanceID()
+	if err != nil {
+		return err, ""
+	}
+
+	summary := complianceSummary{
+		ReportPending: storeforward.NewQueue(agentIdentity, storeforward.ComplianceQueueName, appconfig.StoreAndForwardCfg{}, 1).Len() > 0,
+		Associations:  make([]associationCompliance, 0),
+	}
+	if cachePath := model.ComplianceCachePath(instanceID); fileutil.Exists(cachePath) {
+		items, err := model.ReadAssociationComplianceItems(cachePath)
+		if err != nil {
+			return fmt.Errorf("failed to read the compliance cache %v: %v", cachePath, err), ""
+		}
+		for _, item := range items {
+			if item == nil || (nonCompliantOnly && item.ComplianceStatus == model.COMPLIANT) {
+				continue
+			}
+			summary.Associations = append(summary.Associations, associationCompliance{
+				AssociationId:    item.AssociationId,
+				ExecutionTime:    item.ExecutionTime,
+				DocumentName:     item.DocumentName,
+				DocumentVersion:  item.DocumentVersion,
+				ComplianceStatus: item.ComplianceStatus,
+			})
+		}
+	}
+
+	result, err := jsonutil.MarshalIndent(summary)
+	if err != nil {
+		return err, ""
+	}
+	return nil, result
+}
+
+// Help prints help for the get-compliance cli command
+func (c *GetComplianceCommand) Help() string {
+	if len(c.helpText) == 0 {
+		t, _ := template.New("GetComplianceCommandHelp").Parse(getComplianceCommandHelp)
+		params := getComplianceHelpParams{cliutil.SsmCliName, getComplianceCommand, cliutil.FormatFlag(getComplianceNonCompliant)}
+		buf := new(bytes.Buffer)
+		t.Execute(buf, params)
+		c.helpText = buf.String()
+	}
+	return c.helpText
+}
+
+// Name is the command name used in the cli
+func (GetComplianceCommand) Name() string {
+	return getComplianceCommand
+}
+
+// validateGetComplianceCommandInput checks the subcommands and parameters for unsupported values
+func (GetComplianceCommand) validateGetComplianceCommandInput(subcommands []string, parameters map[string][]string) []string {
+	validation := make([]string, 0)
+	if len(subcommands) > 0 {
+		validation = append(validation, fmt.Sprintf("%v does not support subcommand %v", getComplianceCommand, subcommands), "")
+		return validation
+	}
+
+	for key, values := range parameters {
+		if key != getComplianceNonCompliant {
+			validation = append(validation, fmt.Sprintf("unknown parameter %v", cliutil.FormatFlag(key)))
+		} else if len(values) > 0 {
+			validation = append(validation, fmt.Sprintf("flag %v should not have any values", cliutil.FormatFlag(key)))
+		}
+	}
+	return validation
+}
@@ -126,10 +126,20 @@ EXAMPLES
                     "Status": "Success",
                     "Note": "Windows image state value is at desired value IMAGE_STATE_COMPLETE"
                 },
+                {
+                    "Check": "Session Manager control channel",
+                    "Status": "Success",
+                    "Note": "WebSocket upgrade to ssmmessages.us-east-1.amazonaws.com without a proxy succeeded in 184ms, CreateControlChannel took 97ms"
+                },
                 {
                     "Check": "SSM Agent version",
                     "Status": "Success",
                     "Note": "SSM Agent version is 3.0.655.0, latest agent version in us-east-1 is 3.1.192.0"
+                },
+                {
+                    "Check": "Session Manager connection health",
+                    "Status": "Success",
+                    "Note": "Control channel connected since 2021-09-02T12:24:42Z, 2 reconnects, 0 failed reconnect attempts, ping round trip 31ms, average 33ms, 0 incoming and 0 outgoing messages dropped"
                 }
             ]
         }
@@ -173,8 +183,15 @@ EXAMPLES
         ├───────────────────────────────────────┼─────────┼─────────────────────────────────────────────────────────────────────┤
         │ Windows sysprep image state           │ Success │ Windows image state value is at desired value IMAGE_STATE_COMPLETE  │
         ├───────────────────────────────────────┼─────────┼─────────────────────────────────────────────────────────────────────┤
+        │ Session Manager control channel       │ Success │ WebSocket upgrade to ssmmessages.us-east-1.amazonaws.com without a  │
+        │                                       │         │ proxy succeeded in 184ms, CreateControlChannel took 97ms            │
+        ├───────────────────────────────────────┼─────────┼─────────────────────────────────────────────────────────────────────┤
         │ SSM Agent version                     │ Success │ SSM Agent version is 3.0.655.0, latest agent version in us-east-1   │
         │                                       │         │ is 3.1.192.0                                                        │
+        ├───────────────────────────────────────┼─────────┼─────────────────────────────────────────────────────────────────────┤
+        │ Session Manager connection health     │ Success │ Control channel connected since 2021-09-02T12:24:42Z, 2 reconnects, │
+        │                                       │         │ 0 failed reconnect attempts, ping round trip 31ms, average 33ms, 0  │
+        │                                       │         │ incoming and 0 outgoing messages dropped                            │
         └───────────────────────────────────────┴─────────┴─────────────────────────────────────────────────────────────────────┘
 OUTPUT
     Provide the status and a note of each check.
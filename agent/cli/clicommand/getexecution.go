@@ -0,0 +1,158 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package clicommand contains the implementation of all commands for the ssm agent cli
+package clicommand
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/aws/amazon-ssm-agent/agent/cli/cliutil"
+	"github.com/aws/amazon-ssm-agent/agent/framework/docmanager"
+	"github.com/aws/amazon-ssm-agent/agent/jsonutil"
+	"github.com/aws/amazon-ssm-agent/agent/log/logger"
+)
+
+const (
+	getExecutionCommand = "get-execution"
+)
+
+const getExecutionCommandHelp = `NAME:
+    {{.GetExecutionCommandName}}
+
+DESCRIPTION
+    Shows the status, exit code and output file paths of each plugin of a document executed by
+    the amazon-ssm-agent service on this instance, read from the local orchestration folders.
+    Accepts a command ID, or an association ID to show all retained runs of the association.
+
+SYNOPSIS
+    {{.GetExecutionCommandName}} <id>
+
+EXAMPLES
+    Command:
+
+      {{.SsmCliName}} {{.GetExecutionCommandName}} 01234567-890a-bcde-f012-34567890abcd
+
+    Output:
+      [
+        {
+          "DocumentID": "01234567-890a-bcde-f012-34567890abcd",
+          "CommandID": "01234567-890a-bcde-f012-34567890abcd",
+          "AssociationID": "",
+          "DocumentName": "AWS-RunShellScript",
+          "DocumentVersion": "1",
+          "CreatedDate": "2024-05-02T10:15:01.000Z",
+          "Status": "Failed",
+          "CompletedDateTime": "2024-05-02T10:15:04.123Z",
+          "OrchestrationDirectory": "/var/lib/amazon/ssm/i-0123456789abcdefa/document/orchestration/01234567-890a-bcde-f012-34567890abcd",
+          "Plugins": [
+            {
+              "Id": "runShellScript",
+              "Name": "aws:runShellScript",
+              "StepName": "runShellScript",
+              "Status": "Failed",
+              "Code": 2,
+              "StartDateTime": "2024-05-02T10:15:01.210Z",
+              "EndDateTime": "2024-05-02T10:15:04.101Z",
+              "Error": "failed to run commands: exit status 2",
+              "StandardOutput": "/var/lib/amazon/ssm/i-0123456789abcdefa/document/orchestration/01234567-890a-bcde-f012-34567890abcd/awsrunShellScript/runShellScript/stdout",
+              "StandardError": "/var/lib/amazon/ssm/i-0123456789abcdefa/document/orchestration/01234567-890a-bcde-f012-34567890abcd/awsrunShellScript/runShellScript/stderr"
+            }
+          ]
+        }
+      ]
+
+OUTPUT
+    Execution records in JSON format. StandardOutput and StandardError are the paths of the
+    complete, untruncated plugin output when it is retained on the instance.
+`
+
+type getExecutionHelpParams struct {
+	SsmCliName              string
+	GetExecutionCommandName string
+}
+
+func init() {
+	cliutil.Register(&GetExecutionCommand{})
+}
+
+type GetExecutionCommand struct {
+	helpText string
+}
+
+// Execute validates and executes the get-execution cli command
+func (c *GetExecutionCommand) Execute(subcommands []string, parameters map[string][]string) (error, string) {
+	validation, id := c.validateGetExecutionCommandInput(subcommands, parameters)
+	// return validation errors if any were found
+	if len(validation) > 0 {
+		return errors.New(strings.Join(validation, "\n")), ""
+	}
+
+	agentIdentity, err := cliutil.GetAgentIdentity()
+	if err != nil {
+		return err, ""
+	}
+
+	instanceID, err := agentIdentity.ShortInstanceID()
+	if err != nil {
+		return err, ""
+	}
+
+	records, err := docmanager.GetExecutionRecords(logger.NewSilentLogger(), instanceID, id)
+	if err != nil {
+		return err, ""
+	}
+
+	result, err := jsonutil.MarshalIndent(records)
+	if err != nil {
+		return err, ""
+	}
+	return nil, result
+}
+
+// Help prints help for the get-execution cli command
+func (c *GetExecutionCommand) Help() string {
+	if len(c.helpText) == 0 {
+		t, _ := template.New("GetExecutionCommandHelp").Parse(getExecutionCommandHelp)
+		params := getExecutionHelpParams{cliutil.SsmCliName, getExecutionCommand}
+		buf := new(bytes.Buffer)
+		t.Execute(buf, params)
+		c.helpText = buf.String()
+	}
+	return c.helpText
+}
+
+// Name is the command name used in the cli
+func (GetExecutionCommand) Name() string {
+	return getExecutionCommand
+}
+
+// validateGetExecutionCommandInput checks that exactly one id is given and no parameters
+func (GetExecutionCommand) validateGetExecutionCommandInput(subcommands []string, parameters map[string][]string) (validation []string, id string) {
+	validation = make([]string, 0)
+	if len(subcommands) != 1 {
+		validation = append(validation, fmt.Sprintf("%v expects exactly one command or association id", getExecutionCommand))
+	} else {
+		id = subcommands[0]
+	}
+
+	// look for unsupported parameters
+	for key := range parameters {
+		validation = append(validation, fmt.Sprintf("unknown parameter %v", cliutil.FormatFlag(key)))
+	}
+	return validation, id
+}
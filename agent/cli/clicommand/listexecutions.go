@@ -0,0 +1,167 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package clicommand contains the implementation of all commands for the ssm agent cli
+package clicommand
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/cli/cliutil"
+	"github.com/aws/amazon-ssm-agent/agent/contracts"
+	"github.com/aws/amazon-ssm-agent/agent/framework/docmanager"
+	"github.com/aws/amazon-ssm-agent/agent/jsonutil"
+	"github.com/aws/amazon-ssm-agent/agent/log/logger"
+)
+
+const (
+	listExecutionsCommand = "list-executions"
+)
+
+const listExecutionsCommandHelp = `NAME:
+    {{.ListExecutionsCommandName}}
+
+DESCRIPTION
+    Lists the documents executed by the amazon-ssm-agent service on this instance, read from the
+    local orchestration folders. Documents pending or in progress are listed first, followed by
+    completed documents, most recently completed first.
+
+    Completed documents are listed as long as their orchestration folder is retained on the
+    instance, see OrchestrationDirectoryCleanup and the orchestration retention settings of the
+    agent configuration. Use {{.GetExecutionCommandName}} for the plugin results of an execution.
+
+SYNOPSIS
+    {{.ListExecutionsCommandName}}
+
+EXAMPLES
+    Command:
+
+      {{.SsmCliName}} {{.ListExecutionsCommandName}}
+
+    Output:
+      [
+        {
+          "DocumentID": "01234567-890a-bcde-f012-34567890abcd",
+          "CommandID": "01234567-890a-bcde-f012-34567890abcd",
+          "AssociationID": "",
+          "DocumentName": "AWS-RunShellScript",
+          "Status": "Failed",
+          "CompletedDateTime": "2024-05-02T10:15:04.123Z"
+        }
+      ]
+
+OUTPUT
+    List of executions in JSON format
+`
+
+type listExecutionsHelpParams struct {
+	SsmCliName                string
+	ListExecutionsCommandName string
+	GetExecutionCommandName   string
+}
+
+// executionSummary is the entry printed for each execution by list-executions
+type executionSummary struct {
+	DocumentID        string
+	CommandID         string
+	AssociationID     string
+	DocumentName      string
+	Status            contracts.ResultStatus
+	CompletedDateTime *time.Time `json:",omitempty"`
+}
+
+func init() {
+	cliutil.Register(&ListExecutionsCommand{})
+}
+
+type ListExecutionsCommand struct {
+	helpText string
+}
+
+// Execute validates and executes the list-executions cli command
+func (c *ListExecutionsCommand) Execute(subcommands []string, parameters map[string][]string) (error, string) {
+	validation := c.validateListExecutionsCommandInput(subcommands, parameters)
+	// return validation errors if any were found
+	if len(validation) > 0 {
+		return errors.New(strings.Join(validation, "\n")), ""
+	}
+
+	agentIdentity, err := cliutil.GetAgentIdentity()
+	if err != nil {
+		return err, ""
+	}
+
+	instanceID, err := agentIdentity.ShortInstanceID()
+	if err != nil {
+		return err, ""
+	}
+
+	summaries := make([]executionSummary, 0)
+	for _, record := range docmanager.ListExecutionRecords(logger.NewSilentLogger(), instanceID) {
+		summary := executionSummary{
+			DocumentID:    record.DocumentID,
+			CommandID:     record.CommandID,
+			AssociationID: record.AssociationID,
+			DocumentName:  record.DocumentName,
+			Status:        record.Status,
+		}
+		if !record.CompletedDateTime.IsZero() {
+			completed := record.CompletedDateTime
+			summary.CompletedDateTime = &completed
+		}
+		summaries = append(summaries, summary)
+	}
+
+	result, err := jsonutil.MarshalIndent(summaries)
+	if err != nil {
+		return err, ""
+	}
+	return nil, result
+}
+
+// Help prints help for the list-executions cli command
+func (c *ListExecutionsCommand) Help() string {
+	if len(c.helpText) == 0 {
+		t, _ := template.New("ListExecutionsCommandHelp").Parse(listExecutionsCommandHelp)
+		params := listExecutionsHelpParams{cliutil.SsmCliName, listExecutionsCommand, getExecutionCommand}
+		buf := new(bytes.Buffer)
+		t.Execute(buf, params)
+		c.helpText = buf.String()
+	}
+	return c.helpText
+}
+
+// Name is the command name used in the cli
+func (ListExecutionsCommand) Name() string {
+	return listExecutionsCommand
+}
+
+// validateListExecutionsCommandInput checks the subcommands and parameters for unsupported values
+func (ListExecutionsCommand) validateListExecutionsCommandInput(subcommands []string, parameters map[string][]string) []string {
+	validation := make([]string, 0)
+	if len(subcommands) > 0 {
+		validation = append(validation, fmt.Sprintf("%v does not support subcommand %v", listExecutionsCommand, subcommands), "")
+		return validation
+	}
+
+	// look for unsupported parameters
+	for key := range parameters {
+		validation = append(validation, fmt.Sprintf("unknown parameter %v", cliutil.FormatFlag(key)))
+	}
+	return validation
+}
@@ -0,0 +1,160 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package clicommand contains the implementation of all commands for the ssm agent cli
+package clicommand
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/cli/cliutil"
+	"github.com/aws/amazon-ssm-agent/agent/log/logger"
+)
+
+const (
+	setLogLevelCommand  = "set-log-level"
+	setLogLevelDuration = "duration"
+	setLogLevelReset    = "reset"
+)
+
+const setLogLevelHelp = `NAME:
+    {{.SetLogLevelName}}
+
+DESCRIPTION
+    Changes the log level of the running amazon-ssm-agent processes without restarting them.
+    The level configured in seelog.xml is restored when the duration expires or with {{.ResetName}}.
+    On Linux and macOS, sending SIGUSR1 to the agent cycles the level through debug, trace and the configured level.
+
+SYNOPSIS
+    {{.SetLogLevelName}} <trace|debug|info|warn|error|critical|{{.ResetName}}>
+    [{{.DurationFlag}}]
+
+PARAMETERS
+    {{.DurationFlag}} (string) How long the level is kept, e.g. 30m or 2h. Without it the level is kept until reset.
+
+EXAMPLES
+    This example enables debug logs for 30 minutes.
+
+    Command:
+
+      {{.SsmCliName}} {{.SetLogLevelName}} debug {{.DurationFlag}} 30m
+
+    Output:
+
+      Log level set to debug until 2024-01-01T10:30:00Z
+
+OUTPUT
+    The log level now in effect
+`
+
+type setLogLevelHelpParams struct {
+	SsmCliName      string
+	SetLogLevelName string
+	ResetName       string
+	DurationFlag    string
+}
+
+func init() {
+	cliutil.Register(&SetLogLevelCommand{})
+}
+
+type SetLogLevelCommand struct {
+	helpText string
+}
+
+// Execute validates and executes the set-log-level cli command
+func (c *SetLogLevelCommand) Execute(subcommands []string, parameters map[string][]string) (error, string) {
+	validation, level, duration := c.validateSetLogLevelInput(subcommands, parameters)
+	// return validation errors if any were found
+	if len(validation) > 0 {
+		return errors.New(strings.Join(validation, "\n")), ""
+	}
+
+	if level == setLogLevelReset {
+		if err := logger.RemoveLogLevelOverride(); err != nil {
+			return err, ""
+		}
+		return nil, "Log level reset to the seelog configuration"
+	}
+
+	override := logger.LogLevelOverride{Level: level}
+	if duration > 0 {
+		expiresAt := time.Now().Add(duration).UTC().Truncate(time.Second)
+		override.ExpiresAt = &expiresAt
+	}
+	if err := logger.WriteLogLevelOverride(override); err != nil {
+		return err, ""
+	}
+	if override.ExpiresAt == nil {
+		return nil, fmt.Sprintf("Log level set to %v until reset", level)
+	}
+	return nil, fmt.Sprintf("Log level set to %v until %v", level, override.ExpiresAt.Format(time.RFC3339))
+}
+
+// Help prints help for the set-log-level cli command
+func (c *SetLogLevelCommand) Help() string {
+	if len(c.helpText) == 0 {
+		t, _ := template.New("SetLogLevelHelp").Parse(setLogLevelHelp)
+		params := setLogLevelHelpParams{cliutil.SsmCliName, setLogLevelCommand, setLogLevelReset, cliutil.FormatFlag(setLogLevelDuration)}
+		buf := new(bytes.Buffer)
+		t.Execute(buf, params)
+		c.helpText = buf.String()
+	}
+	return c.helpText
+}
+
+// Name is the command name used in the cli
+func (SetLogLevelCommand) Name() string {
+	return setLogLevelCommand
+}
+
+// validateSetLogLevelInput checks the subcommands and parameters for required values, format, and unsupported values
+func (SetLogLevelCommand) validateSetLogLevelInput(subcommands []string, parameters map[string][]string) (validation []string, level string, duration time.Duration) {
+	validation = make([]string, 0)
+
+	if len(subcommands) != 1 {
+		validation = append(validation, fmt.Sprintf("%v expects exactly one log level", setLogLevelCommand))
+		return validation, "", 0
+	}
+	level = strings.ToLower(subcommands[0])
+	if level != setLogLevelReset {
+		if err := logger.ValidateLogLevel(level); err != nil {
+			validation = append(validation, err.Error())
+		}
+	}
+
+	if values, exists := parameters[setLogLevelDuration]; exists {
+		if level == setLogLevelReset {
+			validation = append(validation, fmt.Sprintf("%v cannot be used with %v", cliutil.FormatFlag(setLogLevelDuration), setLogLevelReset))
+		} else if len(values) != 1 {
+			validation = append(validation, fmt.Sprintf("expected 1 value for parameter %v", cliutil.FormatFlag(setLogLevelDuration)))
+		} else if parsed, err := time.ParseDuration(values[0]); err != nil || parsed <= 0 {
+			validation = append(validation, fmt.Sprintf("invalid duration %v for parameter %v", values[0], cliutil.FormatFlag(setLogLevelDuration)))
+		} else {
+			duration = parsed
+		}
+	}
+
+	// look for unsupported parameters
+	for key := range parameters {
+		if key != setLogLevelDuration {
+			validation = append(validation, fmt.Sprintf("unknown parameter %v", cliutil.FormatFlag(key)))
+		}
+	}
+	return validation, level, duration
+}
@@ -85,7 +85,7 @@ func GetFlag(val string) string {
 // IsHelp determines if a subcommand or flag is a request for help
 func IsHelp(subcommands []string, parameters map[string][]string) bool {
 	for _, val := range subcommands {
-		if val == HelpFlag {
+		if strings.EqualFold(val, HelpFlag) {
 			return true
 		}
 	}
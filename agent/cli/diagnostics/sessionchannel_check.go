@@ -0,0 +1,180 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package diagnostics
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/cli/cliutil"
+	"github.com/aws/amazon-ssm-agent/agent/cli/diagnosticsutil"
+	"github.com/aws/amazon-ssm-agent/agent/clockskew"
+	agentContext "github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/log/logger"
+	"github.com/aws/amazon-ssm-agent/agent/network"
+	mgsconfig "github.com/aws/amazon-ssm-agent/agent/session/config"
+	"github.com/aws/amazon-ssm-agent/agent/session/service"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/gorilla/websocket"
+	"github.com/twinj/uuid"
+)
+
+const (
+	sessionChannelCheckTimeoutSeconds = 10
+
+	sessionChannelCheckStrName          = "Session Manager control channel"
+	sessionChannelCheckStrNoIdentity    = "No instance identity available"
+	sessionChannelCheckStrFailInstance  = "Failed to get instance id: %v"
+	sessionChannelCheckStrFailRegion    = "Unable to fetch AWS region details"
+	sessionChannelCheckStrFailEndpoint  = "No ssmmessages endpoint found for region %s"
+	sessionChannelCheckStrFailCreate    = "CreateControlChannel call to %s failed after %s: %v"
+	sessionChannelCheckStrFailSign      = "Failed to sign WebSocket upgrade request: %v"
+	sessionChannelCheckStrFailUpgrade   = "WebSocket upgrade to %s %s failed after %s: %v"
+	sessionChannelCheckStrFailStatus    = "WebSocket upgrade to %s %s failed after %s with status %s"
+	sessionChannelCheckStrSuccess       = "WebSocket upgrade to %s %s succeeded in %s, CreateControlChannel took %s"
+	sessionChannelCheckStrViaProxy      = "through proxy %s"
+	sessionChannelCheckStrWithoutProxy  = "without a proxy"
+	sessionChannelCheckStrFailProxyFunc = "could not resolve proxy: %v"
+)
+
+// sessionChannelCheckQuery creates a control channel with ssmmessages and performs the signed WebSocket
+// upgrade the agent uses for Session Manager, through the same proxy and TLS settings as the agent.
+// The connection is closed right after the upgrade without sending OpenControlChannel, so the control
+// channel of the running agent is left in place. Data channels are created by the service for a client
+// session and can not be opened without one, so a successful upgrade here is the closest on-host test.
+type sessionChannelCheckQuery struct{}
+
+func (q sessionChannelCheckQuery) GetName() string {
+	return sessionChannelCheckStrName
+}
+
+func (sessionChannelCheckQuery) GetPriority() int {
+	return 8
+}
+
+func (q sessionChannelCheckQuery) failed(note string) diagnosticsutil.DiagnosticOutput {
+	return diagnosticsutil.DiagnosticOutput{
+		Check:  q.GetName(),
+		Status: diagnosticsutil.DiagnosticsStatusFailed,
+		Note:   note,
+	}
+}
+
+func (q sessionChannelCheckQuery) Execute() diagnosticsutil.DiagnosticOutput {
+	agentIdentity, err := cliutil.GetAgentIdentity()
+	if err != nil {
+		return diagnosticsutil.DiagnosticOutput{
+			Check:  q.GetName(),
+			Status: diagnosticsutil.DiagnosticsStatusSkipped,
+			Note:   sessionChannelCheckStrNoIdentity,
+		}
+	}
+
+	instanceId, err := agentIdentity.InstanceID()
+	if err != nil {
+		return q.failed(fmt.Sprintf(sessionChannelCheckStrFailInstance, err))
+	}
+
+	config, err := appconfig.Config(false)
+	if err != nil {
+		config = appconfig.DefaultConfig()
+	}
+
+	region := config.Mgs.Region
+	if region == "" {
+		if region, err = agentIdentity.Region(); err != nil || region == "" {
+			return q.failed(sessionChannelCheckStrFailRegion)
+		}
+	}
+
+	log := logger.NewSilentLogger()
+	context := agentContext.Default(log, config, agentIdentity)
+
+	hostName := mgsconfig.GetMgsEndpoint(context, region)
+	if hostName == "" {
+		return q.failed(fmt.Sprintf(sessionChannelCheckStrFailEndpoint, region))
+	}
+
+	timeout := sessionChannelCheckTimeoutSeconds * time.Second
+	mgsService := service.NewService(context, config.Mgs, timeout)
+
+	uuid.SwitchFormat(uuid.CleanHyphen)
+	createStart := time.Now()
+	_, err = mgsService.CreateControlChannel(log, &service.CreateControlChannelInput{
+		MessageSchemaVersion: aws.String(mgsconfig.MessageSchemaVersion),
+		RequestId:            aws.String(uuid.NewV4().String()),
+	}, instanceId)
+	createLatency := time.Since(createStart).Round(time.Millisecond)
+	if err != nil {
+		return q.failed(fmt.Sprintf(sessionChannelCheckStrFailCreate, hostName, createLatency, err))
+	}
+
+	channelUrl := url.URL{
+		Scheme: "wss",
+		Host:   hostName,
+		Path:   path.Join("/", mgsconfig.APIVersion, mgsconfig.ControlChannel, instanceId),
+	}
+	query := channelUrl.Query()
+	query.Set(mgsconfig.StreamQueryParameter, "input")
+	query.Add(mgsconfig.RoleQueryParameter, mgsconfig.RoleSubscribe)
+	channelUrl.RawQuery = query.Encode()
+
+	request, err := http.NewRequest("GET", channelUrl.String(), nil)
+	if err != nil {
+		return q.failed(fmt.Sprintf(sessionChannelCheckStrFailSign, err))
+	}
+	if _, err = mgsService.GetV4Signer().Sign(request, nil, mgsconfig.ServiceName, mgsService.GetRegion(), clockskew.SigningTime(config.ClockSkew)); err != nil {
+		return q.failed(fmt.Sprintf(sessionChannelCheckStrFailSign, err))
+	}
+
+	proxyFunc := network.GetProxyFunc(log, config)
+	route := sessionChannelCheckStrWithoutProxy
+	if proxyUrl, err := proxyFunc(&http.Request{URL: &url.URL{Scheme: "https", Host: hostName}}); err != nil {
+		route = fmt.Sprintf(sessionChannelCheckStrFailProxyFunc, err)
+	} else if proxyUrl != nil {
+		route = fmt.Sprintf(sessionChannelCheckStrViaProxy, proxyUrl.Redacted())
+	}
+
+	dialer := &websocket.Dialer{
+		TLSClientConfig:  network.GetDefaultTLSConfig(log, config),
+		Proxy:            proxyFunc,
+		NetDialContext:   network.GetProxyDialer(log, config),
+		HandshakeTimeout: timeout,
+	}
+
+	upgradeStart := time.Now()
+	conn, resp, err := dialer.Dial(channelUrl.String(), request.Header)
+	upgradeLatency := time.Since(upgradeStart).Round(time.Millisecond)
+	if err != nil {
+		if resp != nil && resp.StatusCode != http.StatusSwitchingProtocols {
+			return q.failed(fmt.Sprintf(sessionChannelCheckStrFailStatus, hostName, route, upgradeLatency, resp.Status))
+		}
+		return q.failed(fmt.Sprintf(sessionChannelCheckStrFailUpgrade, hostName, route, upgradeLatency, err))
+	}
+	conn.Close()
+
+	return diagnosticsutil.DiagnosticOutput{
+		Check:  q.GetName(),
+		Status: diagnosticsutil.DiagnosticsStatusSuccess,
+		Note:   fmt.Sprintf(sessionChannelCheckStrSuccess, hostName, route, upgradeLatency, createLatency),
+	}
+}
+
+func init() {
+	diagnosticsutil.RegisterDiagnosticQuery(sessionChannelCheckQuery{})
+}
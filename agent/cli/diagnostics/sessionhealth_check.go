@@ -0,0 +1,92 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package diagnostics
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/cli/diagnosticsutil"
+	"github.com/aws/amazon-ssm-agent/agent/session/connectionhealth"
+)
+
+const (
+	sessionHealthCheckStrName         = "Session Manager connection health"
+	sessionHealthCheckStrNoStats      = "Agent has not recorded control channel statistics yet"
+	sessionHealthCheckStrFailRead     = "Failed to read control channel statistics: %v"
+	sessionHealthCheckStrConnected    = "Control channel connected since %s"
+	sessionHealthCheckStrDisconnected = "Control channel disconnected since %s"
+	sessionHealthCheckStrLastError    = "last error: %s"
+	sessionHealthCheckStrReconnects   = "%d reconnects, %d failed reconnect attempts"
+	sessionHealthCheckStrRoundTrip    = "ping round trip %dms, average %.0fms"
+	sessionHealthCheckStrDropped      = "%d incoming and %d outgoing messages dropped"
+)
+
+// sessionHealthCheckQuery reports the control channel statistics recorded by the running agent.
+type sessionHealthCheckQuery struct{}
+
+func (q sessionHealthCheckQuery) GetName() string {
+	return sessionHealthCheckStrName
+}
+
+func (sessionHealthCheckQuery) GetPriority() int {
+	return 10
+}
+
+func (q sessionHealthCheckQuery) Execute() diagnosticsutil.DiagnosticOutput {
+	stats, err := connectionhealth.Load()
+	if errors.Is(err, fs.ErrNotExist) {
+		return diagnosticsutil.DiagnosticOutput{
+			Check:  q.GetName(),
+			Status: diagnosticsutil.DiagnosticsStatusSkipped,
+			Note:   sessionHealthCheckStrNoStats,
+		}
+	} else if err != nil {
+		return diagnosticsutil.DiagnosticOutput{
+			Check:  q.GetName(),
+			Status: diagnosticsutil.DiagnosticsStatusFailed,
+			Note:   fmt.Sprintf(sessionHealthCheckStrFailRead, err),
+		}
+	}
+
+	status := diagnosticsutil.DiagnosticsStatusSuccess
+	var notes []string
+	if stats.Connected {
+		notes = append(notes, fmt.Sprintf(sessionHealthCheckStrConnected, stats.LastConnectedTime.Format(time.RFC3339)))
+	} else {
+		status = diagnosticsutil.DiagnosticsStatusFailed
+		notes = append(notes, fmt.Sprintf(sessionHealthCheckStrDisconnected, stats.LastDisconnectedTime.Format(time.RFC3339)))
+		if stats.LastError != "" {
+			notes = append(notes, fmt.Sprintf(sessionHealthCheckStrLastError, stats.LastError))
+		}
+	}
+	notes = append(notes, fmt.Sprintf(sessionHealthCheckStrReconnects, stats.Reconnects, stats.FailedReconnectAttempts))
+	if stats.RoundTripMillis > 0 {
+		notes = append(notes, fmt.Sprintf(sessionHealthCheckStrRoundTrip, stats.RoundTripMillis, stats.AverageRoundTripMillis))
+	}
+	notes = append(notes, fmt.Sprintf(sessionHealthCheckStrDropped, stats.DroppedIncomingMessages, stats.DroppedOutgoingMessages))
+
+	return diagnosticsutil.DiagnosticOutput{
+		Check:  q.GetName(),
+		Status: status,
+		Note:   strings.Join(notes, ", "),
+	}
+}
+
+func init() {
+	diagnosticsutil.RegisterDiagnosticQuery(sessionHealthCheckQuery{})
+}
@@ -0,0 +1,139 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package clockskew detects drift between the local clock and the clock of the services the agent calls.
+//
+// The skew is measured from the Date header of service responses. Significant skew is logged since it
+// makes SigV4 signature validation fail, and it can optionally be compensated for when signing requests.
+package clockskew
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+)
+
+const (
+	// dateHeaderPrecision is the resolution of the http Date header, the service time is assumed
+	// to be in the middle of the second it reports
+	dateHeaderPrecision = time.Second
+
+	// minCorrectableSkew is the skew below which the local time is considered accurate enough
+	minCorrectableSkew = 2 * time.Second
+
+	// warnInterval limits how often an unchanged skew is reported
+	warnInterval = time.Hour
+)
+
+var (
+	lock         sync.RWMutex
+	skew         time.Duration
+	observed     bool
+	warned       bool
+	lastWarnTime time.Time
+)
+
+// Assign functions to variables to allow unittest to override
+var timeNow = time.Now
+
+// Observe records the skew between the service clock, as reported by the Date header of a response,
+// and the local clock. sentAt is the local time the request was sent, the response is assumed to
+// have been generated halfway between sending the request and receiving the response.
+func Observe(log log.T, config appconfig.ClockSkewCfg, header http.Header, sentAt time.Time) {
+	dateHeader := header.Get("Date")
+	if dateHeader == "" {
+		return
+	}
+	serviceTime, err := http.ParseTime(dateHeader)
+	if err != nil {
+		log.Debugf("Ignoring unparsable Date header %q: %v", dateHeader, err)
+		return
+	}
+	receivedAt := timeNow()
+	localTime := sentAt.Add(receivedAt.Sub(sentAt) / 2)
+	observedSkew := serviceTime.Add(dateHeaderPrecision / 2).Sub(localTime).Round(time.Millisecond)
+
+	lock.Lock()
+	skew = observedSkew
+	observed = true
+	shouldWarn, recovered := false, false
+	if observedSkew.Abs() >= warnThreshold(config) {
+		shouldWarn = !warned || receivedAt.Sub(lastWarnTime) >= warnInterval
+		if shouldWarn {
+			warned = true
+			lastWarnTime = receivedAt
+		}
+	} else if warned {
+		warned = false
+		recovered = true
+	}
+	lock.Unlock()
+
+	if shouldWarn {
+		log.Warnf("Local clock is %s the service clock by %v, requests signed with the local time may be rejected. "+
+			"Synchronize the system time, e.g. with NTP, or enable ClockSkew.CorrectSigning in the agent configuration.",
+			direction(observedSkew), observedSkew.Abs())
+	} else if recovered {
+		log.Infof("Local clock is within %v of the service clock again", warnThreshold(config))
+	}
+}
+
+// Skew returns the last observed offset of the service clock from the local clock.
+// The skew is positive when the local clock is behind the service clock.
+func Skew() (time.Duration, bool) {
+	lock.RLock()
+	defer lock.RUnlock()
+	return skew, observed
+}
+
+// Now returns the local time corrected by the observed skew
+func Now() time.Time {
+	now := timeNow()
+	if observedSkew, ok := Skew(); ok && observedSkew.Abs() >= minCorrectableSkew {
+		return now.Add(observedSkew)
+	}
+	return now
+}
+
+// SigningTime returns the time requests should be signed with, which is corrected
+// by the observed skew only when enabled in the configuration
+func SigningTime(config appconfig.ClockSkewCfg) time.Time {
+	if config.CorrectSigning {
+		return Now()
+	}
+	return timeNow()
+}
+
+// reset forgets the observed skew
+func reset() {
+	lock.Lock()
+	defer lock.Unlock()
+	skew = 0
+	observed = false
+	warned = false
+	lastWarnTime = time.Time{}
+}
+
+func warnThreshold(config appconfig.ClockSkewCfg) time.Duration {
+	return time.Duration(config.WarnThresholdSeconds) * time.Second
+}
+
+func direction(skew time.Duration) string {
+	if skew > 0 {
+		return "behind"
+	}
+	return "ahead of"
+}
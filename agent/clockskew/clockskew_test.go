@@ -0,0 +1,213 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package clockskew
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	logmocks "github.com/aws/amazon-ssm-agent/agent/mocks/log"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setup(t *testing.T, now time.Time) {
+	reset()
+	timeNow = func() time.Time { return now }
+	t.Cleanup(func() {
+		reset()
+		timeNow = time.Now
+	})
+}
+
+func dateHeader(serviceTime time.Time) http.Header {
+	header := http.Header{}
+	header.Set("Date", serviceTime.UTC().Format(http.TimeFormat))
+	return header
+}
+
+func TestObserveRecordsSkew(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	setup(t, now)
+	log := logmocks.NewMockLog()
+	config := appconfig.DefaultConfig().ClockSkew
+
+	_, observed := Skew()
+	assert.False(t, observed)
+	assert.Equal(t, now, Now())
+
+	Observe(log, config, dateHeader(now.Add(10*time.Minute)), now)
+	skew, observed := Skew()
+	assert.True(t, observed)
+	assert.Equal(t, 10*time.Minute+dateHeaderPrecision/2, skew)
+	assert.Equal(t, now.Add(skew), Now())
+
+	Observe(log, config, dateHeader(now.Add(-10*time.Minute)), now)
+	skew, _ = Skew()
+	assert.Equal(t, -10*time.Minute+dateHeaderPrecision/2, skew)
+}
+
+func TestObserveIgnoresInvalidDate(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	setup(t, now)
+	config := appconfig.DefaultConfig().ClockSkew
+
+	Observe(logmocks.NewMockLog(), config, http.Header{}, now)
+	header := http.Header{}
+	header.Set("Date", "yesterday")
+	Observe(logmocks.NewMockLog(), config, header, now)
+
+	_, observed := Skew()
+	assert.False(t, observed)
+}
+
+func TestNowIgnoresSmallSkew(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	setup(t, now)
+
+	Observe(logmocks.NewMockLog(), appconfig.DefaultConfig().ClockSkew, dateHeader(now), now)
+	assert.Equal(t, now, Now())
+}
+
+func TestObserveWarnsOncePerInterval(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	setup(t, now)
+	config := appconfig.DefaultConfig().ClockSkew
+	log := logmocks.NewMockLog()
+
+	Observe(log, config, dateHeader(now.Add(-5*time.Minute)), now)
+	Observe(log, config, dateHeader(now.Add(-5*time.Minute)), now)
+	log.AssertNumberOfCalls(t, "Warnf", 1)
+
+	now = now.Add(warnInterval)
+	timeNow = func() time.Time { return now }
+	Observe(log, config, dateHeader(now.Add(-5*time.Minute)), now)
+	log.AssertNumberOfCalls(t, "Warnf", 2)
+
+	Observe(log, config, dateHeader(now), now)
+	log.AssertNumberOfCalls(t, "Infof", 1)
+}
+
+func TestSigningTime(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	setup(t, now)
+	config := appconfig.DefaultConfig().ClockSkew
+
+	Observe(logmocks.NewMockLog(), config, dateHeader(now.Add(time.Hour)), now)
+	assert.Equal(t, now, SigningTime(config))
+
+	config.CorrectSigning = true
+	assert.Equal(t, now.Add(time.Hour+dateHeaderPrecision/2), SigningTime(config))
+}
+
+func TestTransportObservesResponses(t *testing.T) {
+	setup(t, time.Now().Add(-time.Hour))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewTransport(logmocks.NewMockLog(), appconfig.DefaultConfig(), http.DefaultTransport)}
+	resp, err := client.Get(server.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	skew, observed := Skew()
+	assert.True(t, observed)
+	assert.InDelta(t, float64(time.Hour), float64(skew), float64(5*time.Second))
+}
+
+func newTestClient(t *testing.T, appConfig appconfig.SsmagentConfig, handler http.HandlerFunc) (*ssm.SSM, *logmocks.Mock) {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	log := logmocks.NewMockLog()
+	sess := session.Must(session.NewSession(&aws.Config{
+		Region:      aws.String("us-east-1"),
+		Endpoint:    aws.String(server.URL),
+		Credentials: credentials.NewStaticCredentials("AKID", "SECRET", ""),
+		MaxRetries:  aws.Int(0),
+	}))
+	client := ssm.New(sess)
+	AddHandlers(log, appConfig, &client.Handlers)
+	return client, log
+}
+
+func TestAddHandlersCorrectsSigningTime(t *testing.T) {
+	serviceTime := time.Now().Add(2 * time.Hour).UTC()
+	setup(t, time.Now())
+	appConfig := appconfig.DefaultConfig()
+	appConfig.ClockSkew.CorrectSigning = true
+
+	var amzDates []string
+	client, _ := newTestClient(t, appConfig, func(w http.ResponseWriter, r *http.Request) {
+		amzDates = append(amzDates, r.Header.Get("X-Amz-Date"))
+		w.Header().Set("Date", serviceTime.Format(http.TimeFormat))
+		w.Write([]byte("{}"))
+	})
+
+	_, err := client.DescribeDocument(&ssm.DescribeDocumentInput{Name: aws.String("doc")})
+	assert.NoError(t, err)
+	_, err = client.DescribeDocument(&ssm.DescribeDocumentInput{Name: aws.String("doc")})
+	assert.NoError(t, err)
+
+	assert.Len(t, amzDates, 2)
+	signedAt, err := time.Parse("20060102T150405Z", amzDates[1])
+	assert.NoError(t, err)
+	assert.WithinDuration(t, serviceTime, signedAt, 5*time.Second)
+}
+
+func TestAddHandlersLogsHintOnSignatureFailure(t *testing.T) {
+	setup(t, time.Now())
+	appConfig := appconfig.DefaultConfig()
+
+	var signedAt string
+	client, log := newTestClient(t, appConfig, func(w http.ResponseWriter, r *http.Request) {
+		signedAt = r.Header.Get("X-Amz-Date")
+		w.Header().Set("Date", time.Now().Add(-30*time.Minute).UTC().Format(http.TimeFormat))
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"__type":"InvalidSignatureException","message":"Signature expired"}`))
+	})
+
+	_, err := client.DescribeDocument(&ssm.DescribeDocumentInput{Name: aws.String("doc")})
+	assert.Error(t, err)
+	assert.Equal(t, "InvalidSignatureException", err.(awserr.Error).Code())
+	assert.NotEmpty(t, signedAt)
+	log.AssertCalled(t, "Warnf", mock.MatchedBy(func(format string) bool {
+		return strings.HasPrefix(format, "Request failed with")
+	}), mock.Anything)
+}
+
+func TestLogSkewHintIgnoresOtherErrors(t *testing.T) {
+	now := time.Now()
+	setup(t, now)
+	config := appconfig.DefaultConfig().ClockSkew
+	log := logmocks.NewMockLog()
+	Observe(log, config, dateHeader(now.Add(time.Hour)), now)
+
+	logSkewHint(log, config, nil)
+	logSkewHint(log, config, awserr.New("ThrottlingException", "slow down", nil))
+	log.AssertNumberOfCalls(t, "Warnf", 1)
+
+	logSkewHint(log, config, awserr.New("RequestExpired", "expired", nil))
+	log.AssertNumberOfCalls(t, "Warnf", 2)
+}
@@ -0,0 +1,117 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package clockskew
+
+import (
+	"net/http"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+)
+
+// skewErrorCodes are the service error codes returned when the signing time of a request is off
+var skewErrorCodes = map[string]bool{
+	"SignatureDoesNotMatch":     true,
+	"InvalidSignatureException": true,
+	"RequestExpired":            true,
+	"RequestTimeTooSkewed":      true,
+	"RequestInTheFuture":        true,
+}
+
+// observingTransport is an http.RoundTripper recording the clock skew of every response
+type observingTransport struct {
+	delegate http.RoundTripper
+	logger   log.T
+	config   appconfig.ClockSkewCfg
+}
+
+// NewTransport wraps the given transport to observe the clock skew of every response
+func NewTransport(log log.T, appConfig appconfig.SsmagentConfig, delegate http.RoundTripper) http.RoundTripper {
+	return &observingTransport{
+		delegate: delegate,
+		logger:   log,
+		config:   appConfig.ClockSkew,
+	}
+}
+
+// RoundTrip processes the request and observes the Date header of the response
+func (t *observingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	sentAt := timeNow()
+	response, err := t.delegate.RoundTrip(request)
+	if err == nil && response != nil {
+		Observe(t.logger, t.config, response.Header, sentAt)
+	}
+	return response, err
+}
+
+// AddHandlers installs the clock skew handlers on the handlers of an sdk client.
+// The skew is observed on every response, failures caused by a skewed clock are reported
+// with a hint, and when enabled in the configuration requests are signed with the skew corrected time.
+// Must be called on the client handlers, after the service client added its signer.
+func AddHandlers(log log.T, appConfig appconfig.SsmagentConfig, handlers *request.Handlers) {
+	config := appConfig.ClockSkew
+	// observed in a handler rather than a transport since the sdk requires an *http.Transport for custom CA bundles
+	handlers.Send.PushBackNamed(request.NamedHandler{
+		Name: "clockskew.ObserveHandler",
+		Fn: func(r *request.Request) {
+			if r.HTTPResponse != nil {
+				Observe(log, config, r.HTTPResponse.Header, r.AttemptTime)
+			}
+		},
+	})
+	if config.CorrectSigning {
+		// signers using the request time, like the RSA signer of on-prem instances
+		handlers.Sign.PushFrontNamed(request.NamedHandler{
+			Name: "clockskew.SigningTimeHandler",
+			Fn: func(r *request.Request) {
+				r.Time = Now()
+			},
+		})
+		handlers.Sign.Swap(v4.SignRequestHandler.Name, request.NamedHandler{
+			Name: v4.SignRequestHandler.Name,
+			Fn: func(r *request.Request) {
+				v4.SignSDKRequestWithCurrentTime(r, Now)
+			},
+		})
+	}
+	handlers.Complete.PushBackNamed(request.NamedHandler{
+		Name: "clockskew.ErrorHintHandler",
+		Fn: func(r *request.Request) {
+			logSkewHint(log, config, r.Error)
+		},
+	})
+}
+
+// logSkewHint explains a signature failure when it is likely caused by the skew of the local clock
+func logSkewHint(log log.T, config appconfig.ClockSkewCfg, err error) {
+	awsErr, ok := err.(awserr.Error)
+	if !ok || !skewErrorCodes[awsErr.Code()] {
+		return
+	}
+	observedSkew, observed := Skew()
+	if !observed || observedSkew.Abs() < warnThreshold(config) {
+		return
+	}
+	if config.CorrectSigning {
+		log.Warnf("Request failed with %s while the local clock is %s the service clock by %v, "+
+			"the skew correction applies from the next attempt.", awsErr.Code(), direction(observedSkew), observedSkew.Abs())
+		return
+	}
+	log.Warnf("Request failed with %s, most likely because the local clock is %s the service clock by %v. "+
+		"Synchronize the system time, e.g. with NTP, or enable ClockSkew.CorrectSigning in the agent configuration.",
+		awsErr.Code(), direction(observedSkew), observedSkew.Abs())
+}
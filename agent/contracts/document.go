@@ -100,6 +100,9 @@ type IOConfiguration struct {
 	OrchestrationDirectory string
 	OutputS3BucketName     string
 	OutputS3KeyPrefix      string
+	OutputS3Compression    bool
+	OutputS3KmsKeyId       string
+	OutputStripAnsi        bool
 	CloudWatchConfig       CloudWatchConfiguration
 }
 
@@ -112,6 +115,8 @@ type DocumentState struct {
 	CancelInformation          CancelCommandInfo
 	IOConfig                   IOConfiguration
 	UpstreamServiceName        UpstreamServiceName
+	// ResumeOnInterruption is set when the document state is checkpointed after every step
+	ResumeOnInterruption bool
 }
 
 // IsRebootRequired returns if reboot is needed
@@ -41,6 +41,20 @@ func (_m *ICoreModuleWrapper) ModuleName() string {
 	return r0
 }
 
+// ModuleDrain provides a mock function with given fields: waitTime
+func (_m *ICoreModuleWrapper) ModuleDrain(waitTime time.Duration) error {
+	ret := _m.Called(waitTime)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(time.Duration) error); ok {
+		r0 = rf(waitTime)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ModuleStop provides a mock function with given fields: waitTime
 func (_m *ICoreModuleWrapper) ModuleStop(waitTime time.Duration) error {
 	ret := _m.Called(waitTime)
@@ -221,6 +221,15 @@ type DocumentContent struct {
 	RuntimeConfig map[string]*PluginConfig `json:"runtimeConfig" yaml:"runtimeConfig"`
 	MainSteps     []*InstancePluginConfig  `json:"mainSteps" yaml:"mainSteps"`
 	Parameters    map[string]*Parameter    `json:"parameters" yaml:"parameters"`
+	// ResumeOnInterruption opts in to persisting the result of every step, so that a document interrupted by
+	// an unexpected reboot or agent crash resumes at the first step that has not completed
+	ResumeOnInterruption bool `json:"resumeOnInterruption" yaml:"resumeOnInterruption"`
+	// OutputS3Compression gzip compresses the plugin output uploaded to S3
+	OutputS3Compression bool `json:"outputS3Compression" yaml:"outputS3Compression"`
+	// OutputS3KmsKeyId encrypts the plugin output uploaded to S3 with SSE-KMS using the given key
+	OutputS3KmsKeyId string `json:"outputS3KmsKeyId" yaml:"outputS3KmsKeyId"`
+	// OutputStripAnsi strips ANSI escape sequences, like colors and progress bars, from the plugin console output
+	OutputStripAnsi bool `json:"outputStripAnsi" yaml:"outputStripAnsi"`
 
 	// InvokedPlugin field is set when document is invoked from any other plugin.
 	// Currently, InvokedPlugin is set only in runDocument Plugin
@@ -239,6 +248,8 @@ type SessionInputs struct {
 	RunAsEnabled                interface{}        `json:"runAsEnabled" yaml:"runAsEnabled"`
 	RunAsDefaultUser            string             `json:"runAsDefaultUser" yaml:"runAsDefaultUser"`
 	ShellProfile                ShellProfileConfig `json:"shellProfile" yaml:"shellProfile"`
+	// ShellProfileName selects a shell profile from the agent configuration, run before ShellProfile
+	ShellProfileName string `json:"shellProfileName" yaml:"shellProfileName"`
 }
 
 // ShellProfileConfig stores shell profile config
@@ -326,4 +337,6 @@ const (
 type StatusComm struct {
 	TerminationChan chan struct{}
 	DoneChan        chan struct{}
+	// RestartChan is notified by the ssmAgentCore go routine when the agent has to be restarted by the service manager
+	RestartChan chan struct{}
 }
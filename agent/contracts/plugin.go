@@ -28,19 +28,40 @@ const (
 
 // PluginResult represents a plugin execution result.
 type PluginResult struct {
-	PluginID           string       `json:"pluginID"`
-	PluginName         string       `json:"pluginName"`
-	Status             ResultStatus `json:"status"`
-	Code               int          `json:"code"`
-	Output             interface{}  `json:"output"`
-	StartDateTime      time.Time    `json:"startDateTime"`
-	EndDateTime        time.Time    `json:"endDateTime"`
-	OutputS3BucketName string       `json:"outputS3BucketName"`
-	OutputS3KeyPrefix  string       `json:"outputS3KeyPrefix"`
-	StepName           string       `json:"stepName"`
-	Error              string       `json:"error"`
-	StandardOutput     string       `json:"standardOutput"`
-	StandardError      string       `json:"standardError"`
+	PluginID           string             `json:"pluginID"`
+	PluginName         string             `json:"pluginName"`
+	Status             ResultStatus       `json:"status"`
+	Code               int                `json:"code"`
+	Output             interface{}        `json:"output"`
+	StartDateTime      time.Time          `json:"startDateTime"`
+	EndDateTime        time.Time          `json:"endDateTime"`
+	OutputS3BucketName string             `json:"outputS3BucketName"`
+	OutputS3KeyPrefix  string             `json:"outputS3KeyPrefix"`
+	StepName           string             `json:"stepName"`
+	Error              string             `json:"error"`
+	StandardOutput     string             `json:"standardOutput"`
+	StandardError      string             `json:"standardError"`
+	ProcessAccounting  *ProcessAccounting `json:"processAccounting,omitempty"`
+}
+
+// ProcessAccounting is the resource usage of the processes executed by a plugin
+type ProcessAccounting struct {
+	UserCPUSeconds   float64 `json:"userCpuSeconds"`
+	SystemCPUSeconds float64 `json:"systemCpuSeconds"`
+	MaxRSSBytes      int64   `json:"maxRssBytes"`
+	ReadBytes        int64   `json:"readBytes"`
+	WriteBytes       int64   `json:"writeBytes"`
+}
+
+// Add accumulates the usage of another execution, the peak memory is the maximum of both
+func (a *ProcessAccounting) Add(other ProcessAccounting) {
+	a.UserCPUSeconds += other.UserCPUSeconds
+	a.SystemCPUSeconds += other.SystemCPUSeconds
+	a.ReadBytes += other.ReadBytes
+	a.WriteBytes += other.WriteBytes
+	if other.MaxRSSBytes > a.MaxRSSBytes {
+		a.MaxRSSBytes = other.MaxRSSBytes
+	}
 }
 
 // IPlugin is interface for authoring a functionality of work.
@@ -60,11 +81,36 @@ type ICoreModule interface {
 	ModuleStop() error
 }
 
+// ICoreModuleDrainer is implemented by core modules that can finish their in-flight work
+// before being stopped. ModuleDrain stops the module from accepting new work and blocks
+// until the pending work is done or the timeout expires.
+type ICoreModuleDrainer interface {
+	ModuleDrain(timeout time.Duration) error
+}
+
+// ICoreModuleDeferrable is implemented by core module wrappers whose module is not needed for the agent
+// to become reachable. Deferred modules are executed once the control channel is set up.
+type ICoreModuleDeferrable interface {
+	ModuleDeferred() bool
+}
+
+// ICoreModuleController is implemented by core module wrappers whose module can be restarted, disabled
+// and enabled while the agent is running. Restarting or enabling a module replaces it with a new instance,
+// which the caller executes afterwards.
+type ICoreModuleController interface {
+	ModuleState() string
+	ModuleRestartable() bool
+	ModuleRestart(waitTime time.Duration) error
+	ModuleDisable(waitTime time.Duration) error
+	ModuleEnable() error
+}
+
 // ICoreModuleWrapper is the
 type ICoreModuleWrapper interface {
 	ModuleName() string
 	ModuleExecute() (err error)
 
+	ModuleDrain(waitTime time.Duration) error
 	ModuleStop(waitTime time.Duration) error
 }
 
@@ -105,8 +151,13 @@ type Configuration struct {
 	RunAsEnabled                bool
 	RunAsUser                   string
 	ShellProfile                ShellProfileConfig
+	ShellProfileName            string
 	SessionOwner                string
 	UpstreamServiceName         UpstreamServiceName
+	// DetectOnly asks the plugin to report whether it would make changes without applying them
+	DetectOnly bool
+	// DocumentName is the name of the document the plugin runs in, empty for nested documents
+	DocumentName string
 }
 
 // Plugin wraps the plugin configuration and plugin result.
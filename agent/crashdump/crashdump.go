@@ -0,0 +1,272 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package crashdump captures diagnostics when agent processes panic repeatedly
+package crashdump
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/log/logger"
+)
+
+const (
+	// DumpDirName is the name of the directory under the agent data folder holding the crash dumps
+	DumpDirName = "crashdumps"
+	// UploadedMarkerFileName is created in a dump directory once the dump has been uploaded
+	UploadedMarkerFileName = ".uploaded"
+
+	panicFileName       = "panic.txt"
+	goroutineFileName   = "goroutines.txt"
+	heapFileName        = "heap.pprof"
+	historyFileSuffix   = ".panics.json"
+	maxLogTailBytes     = 512 * 1024
+	dumpTimestampLayout = "20060102T150405Z"
+)
+
+var (
+	dumpRoot     = filepath.Join(appconfig.DefaultDataStorePath, DumpDirName)
+	logDir       = logger.DefaultLogDir
+	getAppConfig = appconfig.Config
+	timeNow      = time.Now
+
+	logFileNames = []string{logger.LogFile, logger.ErrorFile}
+
+	// history files are shared by all processes with the same name, this only serializes access within a process
+	historyLock sync.Mutex
+)
+
+// Dump describes a captured crash dump on disk
+type Dump struct {
+	Name     string
+	Path     string
+	Uploaded bool
+	modTime  time.Time
+}
+
+// RecordPanic records a panic of processName and captures a crash dump once the process panicked
+// more than the configured threshold within the configured window.
+// It returns the directory of the captured dump, or an empty string if no dump was captured.
+func RecordPanic(log log.T, processName string, panicMessage interface{}, stack []byte) string {
+	config, _ := getAppConfig(false)
+	cfg := config.CrashDump
+	if !cfg.Enabled {
+		return ""
+	}
+
+	historyLock.Lock()
+	defer historyLock.Unlock()
+
+	if err := os.MkdirAll(dumpRoot, appconfig.ReadWriteExecuteAccess); err != nil {
+		log.Warnf("Failed to create crash dump directory %v: %v", dumpRoot, err)
+		return ""
+	}
+
+	now := timeNow().UTC()
+	windowStart := now.Add(-time.Duration(cfg.PanicWindowMinutes) * time.Minute)
+	var panics []time.Time
+	for _, panicTime := range readHistory(processName) {
+		if panicTime.After(windowStart) {
+			panics = append(panics, panicTime)
+		}
+	}
+	panics = append(panics, now)
+
+	dumpDir := ""
+	if len(panics) >= cfg.PanicThreshold {
+		report := fmt.Sprintf("process: %v\npid: %v\ntime: %v\npanics in the last %d minutes: %d\npanic: %v\n\n%s",
+			processName, os.Getpid(), now.Format(time.RFC3339), cfg.PanicWindowMinutes, len(panics), panicMessage, stack)
+		var err error
+		if dumpDir, err = captureDump(processName, now, report); err != nil {
+			log.Warnf("Failed to capture crash dump for %v: %v", processName, err)
+		} else {
+			log.Infof("%v panicked %d times in the last %d minutes, captured crash dump in %v",
+				processName, len(panics), cfg.PanicWindowMinutes, dumpDir)
+			panics = nil
+		}
+		Prune(log, cfg.MaxDumps)
+	}
+
+	if err := writeHistory(processName, panics); err != nil {
+		log.Warnf("Failed to save panic history of %v: %v", processName, err)
+	}
+	return dumpDir
+}
+
+// ListDumps returns the captured crash dumps, oldest first
+func ListDumps() ([]Dump, error) {
+	entries, err := os.ReadDir(dumpRoot)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var dumps []Dump
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		dumpPath := filepath.Join(dumpRoot, entry.Name())
+		_, markerErr := os.Stat(filepath.Join(dumpPath, UploadedMarkerFileName))
+		dumps = append(dumps, Dump{
+			Name:     entry.Name(),
+			Path:     dumpPath,
+			Uploaded: markerErr == nil,
+			modTime:  info.ModTime(),
+		})
+	}
+	sort.SliceStable(dumps, func(i, j int) bool {
+		return dumps[i].modTime.Before(dumps[j].modTime)
+	})
+	return dumps, nil
+}
+
+// MarkUploaded records that the dump has been uploaded
+func MarkUploaded(dump Dump) error {
+	return os.WriteFile(filepath.Join(dump.Path, UploadedMarkerFileName), []byte(timeNow().UTC().Format(time.RFC3339)), appconfig.ReadWriteAccess)
+}
+
+// Prune deletes the oldest crash dumps so that at most maxDumps are kept
+func Prune(log log.T, maxDumps int) {
+	dumps, err := ListDumps()
+	if err != nil {
+		log.Warnf("Failed to list crash dumps: %v", err)
+		return
+	}
+	for len(dumps) > maxDumps {
+		log.Debugf("Deleting crash dump %v", dumps[0].Path)
+		if err = os.RemoveAll(dumps[0].Path); err != nil {
+			log.Warnf("Failed to delete crash dump %v: %v", dumps[0].Path, err)
+		}
+		dumps = dumps[1:]
+	}
+}
+
+// captureDump writes the panic report, the goroutine and heap profiles and the tails of the agent logs
+func captureDump(processName string, now time.Time, report string) (dumpDir string, err error) {
+	dumpDir = filepath.Join(dumpRoot, fmt.Sprintf("%v-%v-%d", processName, now.Format(dumpTimestampLayout), os.Getpid()))
+	if err = os.MkdirAll(dumpDir, appconfig.ReadWriteExecuteAccess); err != nil {
+		return "", err
+	}
+
+	if err = os.WriteFile(filepath.Join(dumpDir, panicFileName), []byte(logger.Redact(report)), appconfig.ReadWriteAccess); err != nil {
+		return dumpDir, err
+	}
+	if err = writeProfile(filepath.Join(dumpDir, goroutineFileName), func(w io.Writer) error {
+		return pprof.Lookup("goroutine").WriteTo(w, 2)
+	}); err != nil {
+		return dumpDir, err
+	}
+	if err = writeProfile(filepath.Join(dumpDir, heapFileName), pprof.WriteHeapProfile); err != nil {
+		return dumpDir, err
+	}
+	for _, logFileName := range logFileNames {
+		// logs are best effort, the process might not be allowed to read them or they might not exist yet
+		if tail, tailErr := readTail(filepath.Join(logDir, logFileName), maxLogTailBytes); tailErr == nil {
+			_ = os.WriteFile(filepath.Join(dumpDir, logFileName), []byte(logger.Redact(tail)), appconfig.ReadWriteAccess)
+		}
+	}
+	return dumpDir, nil
+}
+
+func writeProfile(filePath string, write func(w io.Writer) error) error {
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, appconfig.ReadWriteAccess)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	if err = write(writer); err != nil {
+		return err
+	}
+	return writer.Flush()
+}
+
+// readTail returns the last maxBytes of the file, starting at a line boundary
+func readTail(filePath string, maxBytes int64) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+	offset := info.Size() - maxBytes
+	if offset < 0 {
+		offset = 0
+	}
+	if _, err = file.Seek(offset, io.SeekStart); err != nil {
+		return "", err
+	}
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return "", err
+	}
+	tail := string(content)
+	if offset > 0 {
+		if index := strings.IndexByte(tail, '\n'); index >= 0 {
+			tail = tail[index+1:]
+		}
+	}
+	return tail, nil
+}
+
+func historyFilePath(processName string) string {
+	return filepath.Join(dumpRoot, processName+historyFileSuffix)
+}
+
+func readHistory(processName string) (panics []time.Time) {
+	content, err := os.ReadFile(historyFilePath(processName))
+	if err != nil {
+		return nil
+	}
+	if err = json.Unmarshal(content, &panics); err != nil {
+		return nil
+	}
+	return panics
+}
+
+func writeHistory(processName string, panics []time.Time) error {
+	content, err := json.Marshal(panics)
+	if err != nil {
+		return err
+	}
+	historyFile := historyFilePath(processName)
+	tempFile := fmt.Sprintf("%v.%d.tmp", historyFile, os.Getpid())
+	if err = os.WriteFile(tempFile, content, appconfig.ReadWriteAccess); err != nil {
+		return err
+	}
+	return os.Rename(tempFile, historyFile)
+}
@@ -0,0 +1,116 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package crashdump
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/core/executor"
+)
+
+const stderrFileSuffix = ".stderr"
+
+// markers written by the go runtime when a process dies from an unrecovered panic or a fatal error
+var crashMarkers = []string{"panic: ", "fatal error: "}
+
+var runningPids = func(log log.T) (map[int]bool, error) {
+	processes, err := executor.NewProcessExecutor(log).Processes()
+	if err != nil {
+		return nil, err
+	}
+	pids := make(map[int]bool, len(processes))
+	for _, process := range processes {
+		if process.State != "Z" {
+			pids[process.Pid] = true
+		}
+	}
+	return pids, nil
+}
+
+// CaptureStderr redirects the standard error of the current process to a file in the crash dump directory,
+// so that panics in goroutines without recover, which the go runtime only writes to stderr, are not lost.
+// Crashes found in the files of processName instances that are no longer running are recorded as panics.
+func CaptureStderr(log log.T, processName string) {
+	config, _ := getAppConfig(false)
+	if !config.CrashDump.Enabled {
+		return
+	}
+	if err := os.MkdirAll(dumpRoot, appconfig.ReadWriteExecuteAccess); err != nil {
+		log.Warnf("Failed to create crash dump directory %v: %v", dumpRoot, err)
+		return
+	}
+
+	collectCrashedProcesses(log, processName)
+
+	stderrPath := filepath.Join(dumpRoot, fmt.Sprintf("%v-%d%v", processName, os.Getpid(), stderrFileSuffix))
+	stderrFile, err := os.OpenFile(stderrPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND|os.O_TRUNC, appconfig.ReadWriteAccess)
+	if err != nil {
+		log.Warnf("Failed to create %v: %v", stderrPath, err)
+		return
+	}
+	if err = redirectStderr(stderrFile); err != nil {
+		log.Warnf("Failed to redirect stderr to %v: %v", stderrPath, err)
+		stderrFile.Close()
+		return
+	}
+	os.Stderr = stderrFile
+}
+
+// collectCrashedProcesses scans the stderr files left by processName instances that are no longer running
+func collectCrashedProcesses(log log.T, processName string) {
+	stderrFiles, err := filepath.Glob(filepath.Join(dumpRoot, processName+"-*"+stderrFileSuffix))
+	if err != nil || len(stderrFiles) == 0 {
+		return
+	}
+	pids, err := runningPids(log)
+	if err != nil {
+		log.Warnf("Failed to list running processes: %v", err)
+		return
+	}
+
+	for _, stderrPath := range stderrFiles {
+		pidText := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(stderrPath), processName+"-"), stderrFileSuffix)
+		pid, err := strconv.Atoi(pidText)
+		if err != nil || pid == os.Getpid() || pids[pid] {
+			continue
+		}
+		if content, err := os.ReadFile(stderrPath); err == nil {
+			if crashMessage, found := findCrash(string(content)); found {
+				log.Warnf("%v with pid %d crashed: %v", processName, pid, crashMessage)
+				RecordPanic(log, processName, fmt.Sprintf("pid %d crashed: %v", pid, crashMessage), content)
+			}
+		}
+		if err = os.Remove(stderrPath); err != nil {
+			log.Debugf("Failed to delete %v: %v", stderrPath, err)
+		}
+	}
+}
+
+// findCrash returns the first line written by the go runtime when the process crashed
+func findCrash(stderr string) (string, bool) {
+	for _, line := range strings.Split(stderr, "\n") {
+		for _, marker := range crashMarkers {
+			if strings.HasPrefix(line, marker) {
+				return strings.TrimSpace(line), true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,28 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build windows
+// +build windows
+
+package crashdump
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// the go runtime looks up the standard error handle whenever it writes a crash
+func redirectStderr(file *os.File) error {
+	return windows.SetStdHandle(windows.STD_ERROR_HANDLE, windows.Handle(file.Fd()))
+}
@@ -0,0 +1,177 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package uploader implements the core module uploading crash dumps to S3
+package uploader
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/crashdump"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/s3util"
+)
+
+const (
+	// ModuleName is the name of the crash dump uploader core module
+	ModuleName = "CrashDumpUploader"
+
+	uploadInterval = 15 * time.Minute
+)
+
+type s3Uploader interface {
+	S3Upload(log log.T, bucketName string, objectKey string, filePath string) error
+}
+
+var (
+	newS3Uploader = func(context context.T, bucketName string) (s3Uploader, error) {
+		return s3util.NewAmazonS3Util(context, bucketName)
+	}
+	listDumps    = crashdump.ListDumps
+	pruneDumps   = crashdump.Prune
+	markUploaded = crashdump.MarkUploaded
+)
+
+// Uploader periodically prunes the crash dumps and uploads the new ones to the S3 bucket configured in appconfig
+type Uploader struct {
+	context context.T
+	config  appconfig.CrashDumpCfg
+
+	mtx      sync.Mutex
+	stopChan chan struct{}
+	doneChan chan struct{}
+}
+
+// NewUploader returns a crash dump uploader core module
+func NewUploader(context context.T) *Uploader {
+	return &Uploader{
+		context: context.With("[" + ModuleName + "]"),
+		config:  context.AppConfig().CrashDump,
+	}
+}
+
+// ModuleName returns the module name
+func (u *Uploader) ModuleName() string {
+	return ModuleName
+}
+
+// ModuleExecute starts the upload loop
+func (u *Uploader) ModuleExecute() (err error) {
+	u.mtx.Lock()
+	defer u.mtx.Unlock()
+
+	u.stopChan = make(chan struct{})
+	u.doneChan = make(chan struct{})
+	go u.uploadLoop(uploadInterval, u.stopChan, u.doneChan)
+	return nil
+}
+
+// ModuleStop stops the upload loop
+func (u *Uploader) ModuleStop() (err error) {
+	u.mtx.Lock()
+	defer u.mtx.Unlock()
+
+	if u.stopChan != nil {
+		close(u.stopChan)
+		<-u.doneChan
+		u.stopChan = nil
+	}
+	return nil
+}
+
+func (u *Uploader) uploadLoop(interval time.Duration, stopChan chan struct{}, doneChan chan struct{}) {
+	log := u.context.Log()
+	defer func() {
+		if msg := recover(); msg != nil {
+			log.Errorf("Crash dump uploader panic: %v", msg)
+			log.Errorf("Stacktrace:\n%s", debug.Stack())
+		}
+		close(doneChan)
+	}()
+
+	u.upload()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+			u.upload()
+		}
+	}
+}
+
+// upload deletes the dumps above the configured maximum and uploads the dumps that were not uploaded yet
+func (u *Uploader) upload() {
+	log := u.context.Log()
+	pruneDumps(log, u.config.MaxDumps)
+	if u.config.S3BucketName == "" {
+		return
+	}
+
+	dumps, err := listDumps()
+	if err != nil {
+		log.Warnf("Failed to list crash dumps: %v", err)
+		return
+	}
+	var s3 s3Uploader
+	for _, dump := range dumps {
+		if dump.Uploaded {
+			continue
+		}
+		if s3 == nil {
+			if s3, err = newS3Uploader(u.context, u.config.S3BucketName); err != nil {
+				log.Warnf("Failed to create S3 client for bucket %v: %v", u.config.S3BucketName, err)
+				return
+			}
+		}
+		if err = u.uploadDump(s3, dump); err != nil {
+			log.Warnf("Failed to upload crash dump %v: %v", dump.Name, err)
+			continue
+		}
+		if err = markUploaded(dump); err != nil {
+			log.Warnf("Failed to mark crash dump %v as uploaded: %v", dump.Name, err)
+		}
+	}
+}
+
+func (u *Uploader) uploadDump(s3 s3Uploader, dump crashdump.Dump) error {
+	log := u.context.Log()
+	instanceID, err := u.context.Identity().InstanceID()
+	if err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(dump.Path)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || entry.Name() == crashdump.UploadedMarkerFileName {
+			continue
+		}
+		objectKey := path.Join(u.config.S3KeyPrefix, instanceID, dump.Name, entry.Name())
+		if err = s3.S3Upload(log, u.config.S3BucketName, objectKey, filepath.Join(dump.Path, entry.Name())); err != nil {
+			return err
+		}
+	}
+	log.Infof("Uploaded crash dump %v to s3://%v/%v", dump.Name, u.config.S3BucketName, path.Join(u.config.S3KeyPrefix, instanceID, dump.Name))
+	return nil
+}
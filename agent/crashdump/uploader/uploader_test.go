@@ -0,0 +1,123 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package uploader
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/crashdump"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	contextmocks "github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	identitymocks "github.com/aws/amazon-ssm-agent/common/identity/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeS3 struct {
+	uploaded map[string]string
+	err      error
+}
+
+func (f *fakeS3) S3Upload(log log.T, bucketName string, objectKey string, filePath string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.uploaded[bucketName+"/"+objectKey] = filePath
+	return nil
+}
+
+func setupTest(t *testing.T, s3 *fakeS3) (dumps []crashdump.Dump, marked *[]string, pruned *int) {
+	originalNewS3Uploader, originalListDumps, originalPruneDumps, originalMarkUploaded := newS3Uploader, listDumps, pruneDumps, markUploaded
+	t.Cleanup(func() {
+		newS3Uploader, listDumps, pruneDumps, markUploaded = originalNewS3Uploader, originalListDumps, originalPruneDumps, originalMarkUploaded
+	})
+
+	root := t.TempDir()
+	for _, name := range []string{"ssm-document-worker-1", "ssm-agent-worker-2"} {
+		dumpDir := filepath.Join(root, name)
+		os.MkdirAll(dumpDir, 0700)
+		os.WriteFile(filepath.Join(dumpDir, "panic.txt"), []byte("panic"), 0600)
+		dumps = append(dumps, crashdump.Dump{Name: name, Path: dumpDir})
+	}
+	dumps[1].Uploaded = true
+
+	marked = &[]string{}
+	pruned = new(int)
+	newS3Uploader = func(context context.T, bucketName string) (s3Uploader, error) {
+		return s3, nil
+	}
+	listDumps = func() ([]crashdump.Dump, error) {
+		return dumps, nil
+	}
+	pruneDumps = func(log log.T, maxDumps int) {
+		*pruned = maxDumps
+	}
+	markUploaded = func(dump crashdump.Dump) error {
+		*marked = append(*marked, dump.Name)
+		return nil
+	}
+	return dumps, marked, pruned
+}
+
+func newTestUploader(bucket string) *Uploader {
+	return &Uploader{
+		context: contextmocks.NewMockDefault(),
+		config: appconfig.CrashDumpCfg{
+			Enabled:      true,
+			MaxDumps:     3,
+			S3BucketName: bucket,
+			S3KeyPrefix:  "crashes",
+		},
+	}
+}
+
+func TestUploadNewDumps(t *testing.T) {
+	s3 := &fakeS3{uploaded: map[string]string{}}
+	dumps, marked, pruned := setupTest(t, s3)
+
+	newTestUploader("bucket").upload()
+
+	assert.Equal(t, 3, *pruned)
+	assert.Equal(t, map[string]string{
+		"bucket/crashes/" + identitymocks.MockInstanceID + "/ssm-document-worker-1/panic.txt": filepath.Join(dumps[0].Path, "panic.txt"),
+	}, s3.uploaded)
+	assert.Equal(t, []string{"ssm-document-worker-1"}, *marked)
+}
+
+func TestUploadFailureDoesNotMarkDump(t *testing.T) {
+	s3 := &fakeS3{uploaded: map[string]string{}, err: errors.New("access denied")}
+	_, marked, _ := setupTest(t, s3)
+
+	newTestUploader("bucket").upload()
+
+	assert.Empty(t, *marked)
+}
+
+func TestUploadWithoutBucketOnlyPrunes(t *testing.T) {
+	s3 := &fakeS3{uploaded: map[string]string{}}
+	_, marked, pruned := setupTest(t, s3)
+
+	uploader := newTestUploader("")
+	assert.Equal(t, ModuleName, uploader.ModuleName())
+	assert.Nil(t, uploader.ModuleExecute())
+	assert.Nil(t, uploader.ModuleStop())
+
+	assert.Equal(t, 3, *pruned)
+	assert.Empty(t, s3.uploaded)
+	assert.Empty(t, *marked)
+}
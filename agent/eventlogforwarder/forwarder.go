@@ -0,0 +1,367 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package eventlogforwarder implements the core module forwarding Windows Event Log entries to CloudWatch Logs
+package eventlogforwarder
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/agentlogstocloudwatch/cloudwatchlogspublisher"
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/fileutil"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+)
+
+const (
+	// ModuleName is the name of the event log forwarder core module
+	ModuleName = "EventLogForwarder"
+
+	bookmarkDirName = "eventlog"
+
+	// maxEventsPerPoll bounds the events read from a channel in one poll, the remaining ones are read by the next polls
+	maxEventsPerPoll = 500
+
+	// PutLogEvents limits - https://docs.aws.amazon.com/AmazonCloudWatch/latest/logs/cloudwatch_limits_cwl.html
+	maxBatchSizeInBytes = 1048576
+	eventOverheadBytes  = 26
+	maxBatchTimeSpan    = 24 * time.Hour
+)
+
+// levelNumbers maps the event level names of the configuration to the levels of the System/Level element
+var levelNumbers = map[string][]int{
+	"Critical": {1},
+	"Error":    {2},
+	"Warning":  {3},
+	// events logged with level LogAlways are shown as Information
+	"Information": {0, 4},
+	"Verbose":     {5},
+}
+
+// Event is an entry read from a Windows Event Log channel
+type Event struct {
+	RecordId     int64
+	TimeCreated  time.Time
+	ProviderName string
+	Id           int
+	Level        int
+	Message      string
+}
+
+// forwardedEvent is the message of the log event sent to CloudWatch Logs
+type forwardedEvent struct {
+	Channel  string
+	RecordId int64
+	Provider string
+	EventId  int
+	Level    string
+	Message  string
+}
+
+type logsService interface {
+	CreateLogGroup(logGroup string) error
+	CreateLogStream(logGroup, logStream string) error
+	PutLogEvents(messages []*cloudwatchlogs.InputLogEvent, logGroup, logStream string, sequenceToken *string) (*string, error)
+}
+
+var (
+	newLogsService = func(context context.T) logsService {
+		return cloudwatchlogspublisher.NewCloudWatchLogsService(context)
+	}
+	queryEvents    = queryChannelEvents
+	latestRecordID = latestChannelRecordID
+	bookmarkDir    = func(context context.T) string {
+		shortInstanceID, _ := context.Identity().ShortInstanceID()
+		return filepath.Join(appconfig.DefaultDataStorePath, shortInstanceID, bookmarkDirName)
+	}
+)
+
+// Forwarder periodically sends the new entries of the configured event log channels to CloudWatch Logs
+type Forwarder struct {
+	context context.T
+	config  appconfig.EventLogForwardingCfg
+	service logsService
+	// log streams created since the agent started
+	streams map[string]bool
+
+	mtx      sync.Mutex
+	stopChan chan struct{}
+	doneChan chan struct{}
+}
+
+// NewForwarder returns an event log forwarder core module
+func NewForwarder(context context.T) *Forwarder {
+	return &Forwarder{
+		context: context.With("[" + ModuleName + "]"),
+		config:  context.AppConfig().EventLogForwarding,
+		streams: make(map[string]bool),
+	}
+}
+
+// ModuleName returns the module name
+func (f *Forwarder) ModuleName() string {
+	return ModuleName
+}
+
+// ModuleExecute starts the forward loop
+func (f *Forwarder) ModuleExecute() (err error) {
+	if err = checkEventLogSupported(); err != nil {
+		return err
+	}
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	if f.service == nil {
+		f.service = newLogsService(f.context)
+	}
+	f.stopChan = make(chan struct{})
+	f.doneChan = make(chan struct{})
+	go f.forwardLoop(time.Duration(f.config.PollIntervalSeconds)*time.Second, f.stopChan, f.doneChan)
+	return nil
+}
+
+// ModuleStop stops the forward loop
+func (f *Forwarder) ModuleStop() (err error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	if f.stopChan != nil {
+		close(f.stopChan)
+		<-f.doneChan
+		f.stopChan = nil
+	}
+	return nil
+}
+
+func (f *Forwarder) forwardLoop(interval time.Duration, stopChan chan struct{}, doneChan chan struct{}) {
+	log := f.context.Log()
+	defer func() {
+		if msg := recover(); msg != nil {
+			log.Errorf("Event log forwarder panic: %v", msg)
+			log.Errorf("Stacktrace:\n%s", debug.Stack())
+		}
+		close(doneChan)
+	}()
+
+	f.forward()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+			f.forward()
+		}
+	}
+}
+
+// forward sends the new entries of every configured channel
+func (f *Forwarder) forward() {
+	log := f.context.Log()
+	for _, channel := range f.config.Channels {
+		if err := f.forwardChannel(channel); err != nil {
+			log.Warnf("Failed to forward events of channel %v: %v", channel.Name, err)
+		}
+	}
+}
+
+// forwardChannel sends the entries raised after the bookmark of the channel, the first poll of a channel only
+// bookmarks its latest entry so the history of the channel is not sent
+func (f *Forwarder) forwardChannel(channel *appconfig.EventLogChannelCfg) error {
+	log := f.context.Log()
+	bookmarkPath := filepath.Join(bookmarkDir(f.context), bookmarkFileName(channel.Name))
+	bookmark, found := readBookmark(bookmarkPath)
+	if !found {
+		latest, err := latestRecordID(log, channel.Name)
+		if err != nil {
+			return err
+		}
+		log.Infof("Forwarding events of channel %v raised after record %v", channel.Name, latest)
+		return writeBookmark(bookmarkPath, latest)
+	}
+
+	events, err := queryEvents(log, channel.Name, buildEventQuery(log, channel, bookmark), maxEventsPerPoll)
+	if err != nil || len(events) == 0 {
+		return err
+	}
+
+	logStream, err := f.ensureLogStream(channel.Name)
+	if err != nil {
+		return err
+	}
+	for _, batch := range buildBatches(channel.Name, events) {
+		if _, err = f.service.PutLogEvents(batch.logEvents, f.config.LogGroupName, logStream, nil); err != nil {
+			return err
+		}
+		if err = writeBookmark(bookmarkPath, batch.lastRecordId); err != nil {
+			return err
+		}
+	}
+	log.Debugf("Forwarded %v events of channel %v", len(events), channel.Name)
+	return nil
+}
+
+// ensureLogStream creates the log group and the log stream of the channel when they were not created since the agent started
+func (f *Forwarder) ensureLogStream(channelName string) (string, error) {
+	instanceID, err := f.context.Identity().InstanceID()
+	if err != nil {
+		return "", err
+	}
+	// log stream names must not contain colons or asterisks
+	logStream := strings.NewReplacer(":", "_", "*", "_").Replace(instanceID + "/" + channelName)
+	if f.streams[logStream] {
+		return logStream, nil
+	}
+	if err = f.service.CreateLogGroup(f.config.LogGroupName); err != nil {
+		return "", err
+	}
+	if err = f.service.CreateLogStream(f.config.LogGroupName, logStream); err != nil {
+		return "", err
+	}
+	f.streams[logStream] = true
+	return logStream, nil
+}
+
+// buildEventQuery returns the XPath query selecting the entries of the channel raised after the bookmark
+func buildEventQuery(log log.T, channel *appconfig.EventLogChannelCfg, bookmark int64) string {
+	conditions := []string{"EventRecordID > " + strconv.FormatInt(bookmark, 10)}
+
+	var levels []string
+	for _, level := range channel.Levels {
+		for _, number := range levelNumbers[level] {
+			levels = append(levels, "Level="+strconv.Itoa(number))
+		}
+	}
+	if len(levels) > 0 {
+		conditions = append(conditions, "("+strings.Join(levels, " or ")+")")
+	}
+
+	var providers []string
+	for _, provider := range channel.Providers {
+		provider = strings.TrimSpace(provider)
+		if provider == "" || strings.ContainsAny(provider, `'"`) {
+			log.Warnf("Ignoring invalid provider name %q of channel %v", provider, channel.Name)
+			continue
+		}
+		providers = append(providers, "@Name='"+provider+"'")
+	}
+	if len(providers) > 0 {
+		conditions = append(conditions, "Provider["+strings.Join(providers, " or ")+"]")
+	}
+	return "*[System[" + strings.Join(conditions, " and ") + "]]"
+}
+
+type eventBatch struct {
+	logEvents    []*cloudwatchlogs.InputLogEvent
+	lastRecordId int64
+}
+
+// buildBatches splits the events in batches accepted by PutLogEvents, the events of a batch are in chronological order
+func buildBatches(channelName string, events []Event) []eventBatch {
+	sort.SliceStable(events, func(i, j int) bool { return events[i].RecordId < events[j].RecordId })
+
+	var batches []eventBatch
+	var current eventBatch
+	var currentSize int
+	var currentStart, currentEnd time.Time
+	for _, event := range events {
+		message := formatEvent(channelName, event)
+		size := len(message) + eventOverheadBytes
+		if len(current.logEvents) > 0 &&
+			(currentSize+size > maxBatchSizeInBytes || event.TimeCreated.Sub(currentStart) > maxBatchTimeSpan || event.TimeCreated.Before(currentEnd)) {
+			batches = append(batches, current)
+			current = eventBatch{}
+			currentSize = 0
+		}
+		if len(current.logEvents) == 0 {
+			currentStart = event.TimeCreated
+		}
+		current.logEvents = append(current.logEvents, &cloudwatchlogs.InputLogEvent{
+			Message:   aws.String(message),
+			Timestamp: aws.Int64(event.TimeCreated.UnixNano() / int64(time.Millisecond)),
+		})
+		current.lastRecordId = event.RecordId
+		currentSize += size
+		currentEnd = event.TimeCreated
+	}
+	if len(current.logEvents) > 0 {
+		batches = append(batches, current)
+	}
+	return batches
+}
+
+// formatEvent returns the JSON message of the event, messages above the size limit of CloudWatch Logs are truncated
+func formatEvent(channelName string, event Event) string {
+	entry := forwardedEvent{
+		Channel:  channelName,
+		RecordId: event.RecordId,
+		Provider: event.ProviderName,
+		EventId:  event.Id,
+		Level:    levelName(event.Level),
+		Message:  event.Message,
+	}
+	if len(entry.Message) > cloudwatchlogspublisher.StreamMessageLengthThresholdInBytes {
+		entry.Message = entry.Message[:cloudwatchlogspublisher.StreamMessageLengthThresholdInBytes]
+	}
+	content, _ := json.Marshal(entry)
+	return string(content)
+}
+
+func levelName(level int) string {
+	for name, numbers := range levelNumbers {
+		for _, number := range numbers {
+			if number == level {
+				return name
+			}
+		}
+	}
+	return strconv.Itoa(level)
+}
+
+// bookmarkFileName returns a file name for the channel, channel names may contain path separators
+func bookmarkFileName(channelName string) string {
+	return strings.NewReplacer(`/`, "_", `\`, "_", ":", "_", "*", "_", "?", "_", `"`, "_", "<", "_", ">", "_", "|", "_").Replace(channelName)
+}
+
+// readBookmark returns the record id of the last entry forwarded from the channel
+func readBookmark(path string) (int64, bool) {
+	if !fileutil.Exists(path) {
+		return 0, false
+	}
+	content, err := fileutil.ReadAllText(path)
+	if err != nil {
+		return 0, false
+	}
+	bookmark, err := strconv.ParseInt(strings.TrimSpace(content), 10, 64)
+	return bookmark, err == nil
+}
+
+// writeBookmark saves the record id of the last entry forwarded from the channel
+func writeBookmark(path string, recordId int64) error {
+	if err := fileutil.MakeDirs(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("failed to create bookmark directory: %v", err)
+	}
+	return fileutil.WriteAllText(path, strconv.FormatInt(recordId, 10))
+}
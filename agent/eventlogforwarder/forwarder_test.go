@@ -0,0 +1,170 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package eventlogforwarder
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	contextmocks "github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	logmocks "github.com/aws/amazon-ssm-agent/agent/mocks/log"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeLogsService struct {
+	streams []string
+	puts    [][]*cloudwatchlogs.InputLogEvent
+	putErr  error
+}
+
+func (s *fakeLogsService) CreateLogGroup(logGroup string) error {
+	return nil
+}
+
+func (s *fakeLogsService) CreateLogStream(logGroup, logStream string) error {
+	s.streams = append(s.streams, logGroup+" "+logStream)
+	return nil
+}
+
+func (s *fakeLogsService) PutLogEvents(messages []*cloudwatchlogs.InputLogEvent, logGroup, logStream string, sequenceToken *string) (*string, error) {
+	if s.putErr != nil {
+		return nil, s.putErr
+	}
+	s.puts = append(s.puts, messages)
+	return nil, nil
+}
+
+func newTestForwarder(t *testing.T) (*Forwarder, *fakeLogsService) {
+	dir := t.TempDir()
+	originalBookmarkDir, originalQueryEvents, originalLatestRecordID := bookmarkDir, queryEvents, latestRecordID
+	bookmarkDir = func(context.T) string { return dir }
+	t.Cleanup(func() {
+		bookmarkDir, queryEvents, latestRecordID = originalBookmarkDir, originalQueryEvents, originalLatestRecordID
+	})
+
+	service := &fakeLogsService{}
+	forwarder := NewForwarder(contextmocks.NewMockDefault())
+	forwarder.config.LogGroupName = appconfig.DefaultEventLogForwardingLogGroupName
+	forwarder.service = service
+	return forwarder, service
+}
+
+func TestBuildEventQuery(t *testing.T) {
+	channel := &appconfig.EventLogChannelCfg{Name: "System"}
+	assert.Equal(t, "*[System[EventRecordID > 42]]", buildEventQuery(logmocks.NewMockLog(), channel, 42))
+
+	channel.Levels = []string{"Critical", "Information"}
+	channel.Providers = []string{"Service Control Manager", "bad'name", "EventLog"}
+	assert.Equal(t,
+		"*[System[EventRecordID > 0 and (Level=1 or Level=0 or Level=4) and Provider[@Name='Service Control Manager' or @Name='EventLog']]]",
+		buildEventQuery(logmocks.NewMockLog(), channel, 0))
+}
+
+func TestForwardChannelBookmarksLatestRecordOnFirstPoll(t *testing.T) {
+	forwarder, service := newTestForwarder(t)
+	latestRecordID = func(log.T, string) (int64, error) { return 120, nil }
+	queryEvents = func(log.T, string, string, int) ([]Event, error) {
+		t.Fatal("the history of the channel must not be queried")
+		return nil, nil
+	}
+
+	channel := &appconfig.EventLogChannelCfg{Name: "Microsoft-Windows-PowerShell/Operational"}
+	assert.NoError(t, forwarder.forwardChannel(channel))
+
+	bookmark, found := readBookmark(filepath.Join(bookmarkDir(nil), bookmarkFileName(channel.Name)))
+	assert.True(t, found)
+	assert.Equal(t, int64(120), bookmark)
+	assert.Empty(t, service.puts)
+}
+
+func TestForwardChannelSendsNewEvents(t *testing.T) {
+	forwarder, service := newTestForwarder(t)
+	channel := &appconfig.EventLogChannelCfg{Name: "System"}
+	bookmarkPath := filepath.Join(bookmarkDir(nil), bookmarkFileName(channel.Name))
+	assert.NoError(t, writeBookmark(bookmarkPath, 10))
+
+	var queries []string
+	now := time.Now()
+	queryEvents = func(_ log.T, _ string, query string, _ int) ([]Event, error) {
+		queries = append(queries, query)
+		if len(queries) > 1 {
+			return nil, nil
+		}
+		return []Event{
+			{RecordId: 12, TimeCreated: now, ProviderName: "EventLog", Id: 6005, Level: 4, Message: "started"},
+			{RecordId: 11, TimeCreated: now.Add(-time.Second), ProviderName: "Disk", Id: 7, Level: 2, Message: "bad block"},
+		}, nil
+	}
+
+	assert.NoError(t, forwarder.forwardChannel(channel))
+	assert.NoError(t, forwarder.forwardChannel(channel))
+
+	assert.Equal(t, "*[System[EventRecordID > 10]]", queries[0])
+	assert.Equal(t, "*[System[EventRecordID > 12]]", queries[1])
+	assert.Len(t, service.streams, 1)
+	assert.Equal(t, appconfig.DefaultEventLogForwardingLogGroupName+" i-123123123/System", service.streams[0])
+	assert.Len(t, service.puts, 1)
+	assert.Len(t, service.puts[0], 2)
+
+	var first forwardedEvent
+	assert.NoError(t, json.Unmarshal([]byte(*service.puts[0][0].Message), &first))
+	assert.Equal(t, forwardedEvent{Channel: "System", RecordId: 11, Provider: "Disk", EventId: 7, Level: "Error", Message: "bad block"}, first)
+}
+
+func TestForwardChannelKeepsBookmarkWhenSendFails(t *testing.T) {
+	forwarder, service := newTestForwarder(t)
+	service.putErr = fmt.Errorf("AccessDeniedException")
+	channel := &appconfig.EventLogChannelCfg{Name: "System"}
+	bookmarkPath := filepath.Join(bookmarkDir(nil), bookmarkFileName(channel.Name))
+	assert.NoError(t, writeBookmark(bookmarkPath, 10))
+	queryEvents = func(log.T, string, string, int) ([]Event, error) {
+		return []Event{{RecordId: 11, TimeCreated: time.Now(), Message: "started"}}, nil
+	}
+
+	assert.Error(t, forwarder.forwardChannel(channel))
+
+	bookmark, _ := readBookmark(bookmarkPath)
+	assert.Equal(t, int64(10), bookmark)
+}
+
+func TestBuildBatchesSplitsOnSizeAndTimeSpan(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	largeMessage := strings.Repeat("x", 30000)
+	var events []Event
+	for i := 0; i < 40; i++ {
+		events = append(events, Event{RecordId: int64(i), TimeCreated: start, Message: largeMessage})
+	}
+	events = append(events, Event{RecordId: 40, TimeCreated: start.Add(25 * time.Hour), Message: "later"})
+
+	batches := buildBatches("System", events)
+
+	assert.Len(t, batches, 3)
+	assert.Equal(t, int64(39), batches[1].lastRecordId)
+	assert.Equal(t, int64(40), batches[2].lastRecordId)
+	for _, batch := range batches {
+		size := 0
+		for _, event := range batch.logEvents {
+			size += len(*event.Message) + eventOverheadBytes
+		}
+		assert.LessOrEqual(t, size, maxBatchSizeInBytes)
+	}
+}
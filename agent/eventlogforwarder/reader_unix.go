@@ -0,0 +1,37 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build darwin || freebsd || linux || netbsd || openbsd
+// +build darwin freebsd linux netbsd openbsd
+
+package eventlogforwarder
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-ssm-agent/agent/log"
+)
+
+var errEventLogNotSupported = fmt.Errorf("the Windows Event Log is not available on this platform")
+
+func checkEventLogSupported() error {
+	return errEventLogNotSupported
+}
+
+func queryChannelEvents(log log.T, channelName string, query string, maxEvents int) ([]Event, error) {
+	return nil, errEventLogNotSupported
+}
+
+func latestChannelRecordID(log log.T, channelName string) (int64, error) {
+	return 0, errEventLogNotSupported
+}
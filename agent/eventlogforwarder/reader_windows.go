@@ -0,0 +1,111 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build windows
+// +build windows
+
+package eventlogforwarder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+)
+
+const queryTimeout = 2 * time.Minute
+
+// queryResult is an event as printed by the query script
+type queryResult struct {
+	RecordId     int64
+	TimeCreated  string
+	ProviderName string
+	Id           int
+	Level        int
+	Message      string
+}
+
+var runPowerShell = func(script string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+	args := append(strings.Split(appconfig.PowerShellCommandArgs, " "), "-Command", script)
+	return exec.CommandContext(ctx, appconfig.PowerShellPluginCommandName, args...).Output()
+}
+
+func checkEventLogSupported() error {
+	return nil
+}
+
+// queryChannelEvents returns the oldest entries of the channel matching the XPath query
+func queryChannelEvents(log log.T, channelName string, query string, maxEvents int) ([]Event, error) {
+	script := fmt.Sprintf(`$events = Get-WinEvent -LogName %v -FilterXPath %v -MaxEvents %v -Oldest -ErrorAction SilentlyContinue
+ConvertTo-Json -Compress -InputObject @($events | ForEach-Object {
+	[PSCustomObject]@{
+		RecordId = $_.RecordId
+		TimeCreated = $_.TimeCreated.ToUniversalTime().ToString('o')
+		ProviderName = $_.ProviderName
+		Id = $_.Id
+		Level = [int]$_.Level
+		Message = $_.Message
+	}
+})`, quotePowerShell(channelName), quotePowerShell(query), maxEvents)
+	output, err := runPowerShell(script)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query events: %v", err)
+	}
+
+	var results []queryResult
+	if err = json.Unmarshal(output, &results); err != nil {
+		return nil, fmt.Errorf("failed to parse events: %v", err)
+	}
+	events := make([]Event, 0, len(results))
+	for _, result := range results {
+		timeCreated, err := time.Parse(time.RFC3339Nano, result.TimeCreated)
+		if err != nil {
+			log.Debugf("event %v of channel %v has an invalid time %v", result.RecordId, channelName, result.TimeCreated)
+			timeCreated = time.Now()
+		}
+		events = append(events, Event{
+			RecordId:     result.RecordId,
+			TimeCreated:  timeCreated,
+			ProviderName: result.ProviderName,
+			Id:           result.Id,
+			Level:        result.Level,
+			Message:      result.Message,
+		})
+	}
+	return events, nil
+}
+
+// latestChannelRecordID returns the record id of the newest entry of the channel, 0 when the channel is empty
+func latestChannelRecordID(log log.T, channelName string) (int64, error) {
+	script := fmt.Sprintf(`Get-WinEvent -ListLog %v -ErrorAction Stop | Out-Null
+$latest = Get-WinEvent -LogName %v -MaxEvents 1 -ErrorAction SilentlyContinue
+if ($latest) { $latest.RecordId } else { 0 }`, quotePowerShell(channelName), quotePowerShell(channelName))
+	output, err := runPowerShell(script)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read channel %v: %v", channelName, err)
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(output)), 10, 64)
+}
+
+// quotePowerShell returns the value as a single quoted PowerShell string
+func quotePowerShell(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
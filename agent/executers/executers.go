@@ -29,6 +29,7 @@ import (
 
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
 	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/contracts"
 	"github.com/aws/amazon-ssm-agent/agent/fileutil"
 	"github.com/aws/amazon-ssm-agent/agent/log"
 	"github.com/aws/amazon-ssm-agent/agent/platform"
@@ -47,7 +48,7 @@ const (
 type T interface {
 	//TODO: Remove Execute and rename NewExecute to Execute.
 	Execute(context.T, string, string, string, task.CancelFlag, int, string, []string, map[string]string) (io.Reader, io.Reader, int, []error)
-	NewExecute(context.T, string, io.Writer, io.Writer, task.CancelFlag, int, string, []string, map[string]string) (int, error)
+	NewExecute(context.T, string, io.Writer, io.Writer, task.CancelFlag, int, string, []string, map[string]string) (int, *contracts.ProcessAccounting, error)
 	StartExe(context.T, string, io.Writer, io.Writer, task.CancelFlag, string, []string) (*os.Process, int, error)
 }
 
@@ -55,6 +56,12 @@ type T interface {
 type ShellCommandExecuter struct {
 }
 
+// processUser is the user a command runs as instead of the agent user
+type processUser struct {
+	uid uint32
+	gid uint32
+}
+
 type timeoutSignal struct {
 	// process kill doesn't send proper signal to the process status
 	// Setting the execInterruptedOnWindows to indicate execution was interrupted
@@ -165,6 +172,7 @@ func (ShellCommandExecuter) Execute(
 }
 
 // NewExecute executes a list of shell commands in the given working directory and provides the stdout and stderr writers.
+// The process accounting is nil if the command did not exit on its own or the usage could not be collected.
 func (ShellCommandExecuter) NewExecute(
 	context context.T,
 	workingDir string,
@@ -175,9 +183,30 @@ func (ShellCommandExecuter) NewExecute(
 	commandName string,
 	commandArguments []string,
 	envVars map[string]string,
-) (exitCode int, err error) {
-	exitCode, err = ExecuteCommand(context, cancelFlag, workingDir, stdoutWriter, stderrWriter, executionTimeout, commandName, commandArguments, envVars)
-	return
+) (exitCode int, accounting *contracts.ProcessAccounting, err error) {
+	return executeCommand(context, cancelFlag, workingDir, stdoutWriter, stderrWriter, executionTimeout, commandName, commandArguments, envVars, false, nil)
+}
+
+// PseudoTerminalCommandExecuter executes commands attached to a pseudo-terminal so that tools which detect
+// whether they run interactively (progress bars, prompts) behave like they do for a human operator.
+type PseudoTerminalCommandExecuter struct {
+	ShellCommandExecuter
+}
+
+// NewExecute executes a list of shell commands attached to a pseudo-terminal in the given working directory.
+// The terminal combines standard output and standard error, so all output is sent to the stdout writer.
+func (PseudoTerminalCommandExecuter) NewExecute(
+	context context.T,
+	workingDir string,
+	stdoutWriter io.Writer,
+	stderrWriter io.Writer,
+	cancelFlag task.CancelFlag,
+	executionTimeout int,
+	commandName string,
+	commandArguments []string,
+	envVars map[string]string,
+) (exitCode int, accounting *contracts.ProcessAccounting, err error) {
+	return executeCommand(context, cancelFlag, workingDir, stdoutWriter, stderrWriter, executionTimeout, commandName, commandArguments, envVars, true, nil)
 }
 
 // StartExe starts a list of shell commands in the given working directory.
@@ -250,6 +279,50 @@ func (r *cancellableWriter) Write(p []byte) (n int, err error) {
 	}
 }
 
+// terminalOutputWriter translates the CRLF line endings a terminal produces back to LF,
+// so output captured from a pseudo-terminal matches the output captured from pipes.
+// A lone carriage return, as used by progress bars, is kept.
+type terminalOutputWriter struct {
+	baseWriter    io.Writer
+	pendingReturn bool
+}
+
+func (w *terminalOutputWriter) Write(p []byte) (n int, err error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	converted := make([]byte, 0, len(p)+1)
+	if w.pendingReturn && p[0] != '\n' {
+		converted = append(converted, '\r')
+	}
+	w.pendingReturn = false
+	for i, b := range p {
+		if b == '\r' {
+			if i == len(p)-1 {
+				// the next write decides whether this is a line ending
+				w.pendingReturn = true
+				continue
+			}
+			if p[i+1] == '\n' {
+				continue
+			}
+		}
+		converted = append(converted, b)
+	}
+	if _, err = w.baseWriter.Write(converted); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush writes a carriage return held back at the end of the output
+func (w *terminalOutputWriter) Flush() {
+	if w.pendingReturn {
+		w.pendingReturn = false
+		w.baseWriter.Write([]byte{'\r'})
+	}
+}
+
 // ExecuteCommand executes the given commands using the given working directory.
 // Standard output and standard error are sent to the given writers.
 func ExecuteCommand(
@@ -263,6 +336,40 @@ func ExecuteCommand(
 	commandArguments []string,
 	envVars map[string]string,
 ) (exitCode int, err error) {
+	exitCode, _, err = executeCommand(context, cancelFlag, workingDir, stdoutWriter, stderrWriter, executionTimeout, commandName, commandArguments, envVars, false, nil)
+	return
+}
+
+// ExecuteCommandWithPseudoTerminal executes the given commands attached to a pseudo-terminal.
+// Standard output and standard error of the command are combined by the terminal and sent to the stdout writer.
+func ExecuteCommandWithPseudoTerminal(
+	context context.T,
+	cancelFlag task.CancelFlag,
+	workingDir string,
+	stdoutWriter io.Writer,
+	stderrWriter io.Writer,
+	executionTimeout int,
+	commandName string,
+	commandArguments []string,
+	envVars map[string]string,
+) (exitCode int, err error) {
+	exitCode, _, err = executeCommand(context, cancelFlag, workingDir, stdoutWriter, stderrWriter, executionTimeout, commandName, commandArguments, envVars, true, nil)
+	return
+}
+
+func executeCommand(
+	context context.T,
+	cancelFlag task.CancelFlag,
+	workingDir string,
+	stdoutWriter io.Writer,
+	stderrWriter io.Writer,
+	executionTimeout int,
+	commandName string,
+	commandArguments []string,
+	envVars map[string]string,
+	pseudoTerminal bool,
+	runAs *processUser,
+) (exitCode int, accounting *contracts.ProcessAccounting, err error) {
 	log := context.Log()
 
 	stdoutInterruptable, stopStdout := newWriter(stdoutWriter)
@@ -291,10 +398,28 @@ func ExecuteCommand(
 
 	// configure OS-specific process settings
 	prepareProcess(command)
+	if runAs != nil {
+		setProcessUser(command, runAs)
+	}
 
 	// configure environment variables
 	prepareEnvironment(context, command, envVars)
 
+	if pseudoTerminal {
+		var closeTerminal func()
+		if closeTerminal, err = attachPseudoTerminal(log, command, stdoutInterruptable); err != nil {
+			log.Error("error occurred allocating a pseudo-terminal", err)
+			exitCode = 1
+			return
+		}
+		// runs after the command exited so the remaining terminal output is captured
+		defer closeTerminal()
+	}
+
+	// track every process spawned by the command so cancel and timeout can kill the whole tree
+	scope := newProcessScope(log, command)
+	defer scope.release()
+
 	log.Debugf("Running in directory %v, command: %v %v", workingDir, commandName, commandArguments)
 
 	quiesce()
@@ -303,6 +428,7 @@ func ExecuteCommand(
 		exitCode = 1
 		return
 	}
+	scope.attach(command.Process)
 
 	signal := timeoutSignal{}
 
@@ -325,7 +451,7 @@ func ExecuteCommand(
 	case <-time.After(time.Duration(executionTimeout) * time.Second):
 		stopStdout <- true
 		stopStderr <- true
-		if err = killProcess(command.Process, &signal); err != nil {
+		if err = killProcessTree(log, scope, command.Process, &signal, stderrWriter); err != nil {
 			exitCode = 1
 			log.Error(err)
 		} else {
@@ -339,7 +465,7 @@ func ExecuteCommand(
 		log.Debug("Process cancelled. Attempting to stop process.")
 		stopStdout <- true
 		stopStderr <- true
-		if err = killProcess(command.Process, &signal); err != nil {
+		if err = killProcessTree(log, scope, command.Process, &signal, stderrWriter); err != nil {
 			exitCode = 1
 			log.Error(err)
 		} else {
@@ -350,6 +476,8 @@ func ExecuteCommand(
 		}
 	case err = <-done:
 		log.Debug("Process completed.")
+		// collected before the deferred release of the scope, which holds the job object accounting on windows
+		accounting = collectProcessAccounting(log, scope, command.ProcessState)
 		if err != nil {
 			exitCode = 1
 			log.Debugf("command returned error %v", err)
@@ -461,6 +589,22 @@ func killProcessOnCancel(log log.T, command *exec.Cmd, cancelStdout chan bool, c
 	}
 }
 
+// killProcessTree kills the command process and then every process remaining in its scope.
+// How the process tree was terminated is reported in the standard error of the command.
+func killProcessTree(log log.T, scope processScope, process *os.Process, signal *timeoutSignal, stderrWriter io.Writer) error {
+	if err := killProcess(process, signal); err != nil {
+		return err
+	}
+	if err := scope.kill(); err != nil {
+		log.Warnf("Failed to terminate all processes in %v: %v", scope, err)
+		fmt.Fprintf(stderrWriter, "\nTerminated the command process, but not all processes it started: %v\n", err)
+		return nil
+	}
+	log.Infof("Terminated all processes in %v", scope)
+	fmt.Fprintf(stderrWriter, "\nTerminated the command and all processes it started (%v).\n", scope)
+	return nil
+}
+
 // prepareEnvironment adds ssm agent standard environment variables or environment variables defined by customer/other plugins to the command
 func prepareEnvironment(context context.T, command *exec.Cmd, envVars map[string]string) {
 	log := context.Log()
@@ -26,6 +26,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -306,7 +307,12 @@ func testCommandInvokerCancel(t *testing.T, invoke CommandInvoker, cancelFlag ta
 	assert.IsType(t, &exec.ExitError{}, errs[0])
 
 	assertReaderEquals(t, testCase.ExpectedStdout, stdout)
-	assertReaderEquals(t, testCase.ExpectedStderr, stderr)
+
+	// the way the process tree was terminated is reported after the output of the command
+	actualStderr, err := ioutil.ReadAll(stderr)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(string(actualStderr), testCase.ExpectedStderr), "unexpected stderr %q", actualStderr)
+	assert.Contains(t, string(actualStderr), "Terminated the command")
 
 	assert.Equal(t, exitCode, testCase.ExpectedExitCode)
 }
@@ -380,6 +386,36 @@ func prepareTestRunCommand(t *testing.T, envVars map[string]string) (commandInvo
 	return
 }
 
+// TestRunCommand_pseudoTerminal tests that commands run with a pseudo-terminal are attached to a terminal
+// and that the combined output is captured with LF line endings.
+func TestRunCommand_pseudoTerminal(t *testing.T) {
+	var stdoutBuf bytes.Buffer
+	var stderrBuf bytes.Buffer
+	script := fmt.Sprintf(`if [ -t 1 ]; then echo "tty"; fi; %v; %v`, echoToStdout(stdoutMsg), echoToStderr(stderrMsg))
+
+	exitCode, err := ExecuteCommandWithPseudoTerminal(getTestContext(), task.NewChanneledCancelFlag(), ".", &stdoutBuf, &stderrBuf, defaultExecutionTimeout, "sh", []string{"-c", script}, envVars)
+
+	assert.NoError(t, err)
+	assert.Equal(t, successExitCode, exitCode)
+	assert.Equal(t, "tty\n"+stdoutMsg+"\n"+stderrMsg+"\n", stdoutBuf.String())
+	assert.Equal(t, "", stderrBuf.String())
+}
+
+// TestNewExecute_processAccounting tests that the resource usage of an exited command is returned.
+func TestNewExecute_processAccounting(t *testing.T) {
+	var stdoutBuf bytes.Buffer
+	var stderrBuf bytes.Buffer
+	script := "i=0; while [ $i -lt 20000 ]; do i=$((i+1)); done"
+
+	exitCode, accounting, err := ShellCommandExecuter{}.NewExecute(getTestContext(), ".", &stdoutBuf, &stderrBuf, task.NewChanneledCancelFlag(), defaultExecutionTimeout, "sh", []string{"-c", script}, envVars)
+
+	assert.NoError(t, err)
+	assert.Equal(t, successExitCode, exitCode)
+	assert.NotNil(t, accounting)
+	assert.True(t, accounting.UserCPUSeconds+accounting.SystemCPUSeconds > 0)
+	assert.True(t, accounting.MaxRSSBytes > 0)
+}
+
 // prepareTestStartCommand contains boiler plate code for testing start command, to avoid duplication.
 func prepareTestStartCommand(t *testing.T) (commandInvoker CommandInvoker, cancelFlag task.CancelFlag) {
 	cancelFlag = task.NewChanneledCancelFlag()
@@ -15,6 +15,7 @@
 package executers
 
 import (
+	"bytes"
 	"os"
 	"os/exec"
 	"strings"
@@ -117,3 +118,28 @@ func TestQuotePsStringWithQuotes(t *testing.T) {
 	result = QuotePsString("`abc`")
 	assert.Equal(t, "\"``abc``\"", result)
 }
+
+func TestTerminalOutputWriter(t *testing.T) {
+	testCases := []struct {
+		writes   []string
+		expected string
+	}{
+		{[]string{"line1\r\nline2\r\n"}, "line1\nline2\n"},
+		{[]string{"line1\r", "\nline2\r\n"}, "line1\nline2\n"},
+		{[]string{"10%\r20%\r", "30%\r\n"}, "10%\r20%\r30%\n"},
+		{[]string{"prompt\r", "x"}, "prompt\rx"},
+		{[]string{"done\r"}, "done\r"},
+	}
+
+	for _, testCase := range testCases {
+		var buffer bytes.Buffer
+		writer := &terminalOutputWriter{baseWriter: &buffer}
+		for _, write := range testCase.writes {
+			n, err := writer.Write([]byte(write))
+			assert.NoError(t, err)
+			assert.Equal(t, len(write), n)
+		}
+		writer.Flush()
+		assert.Equal(t, testCase.expected, buffer.String())
+	}
+}
@@ -17,6 +17,7 @@
 package executers
 
 import (
+	"io"
 	"os"
 	"os/exec"
 	"runtime"
@@ -24,14 +25,50 @@ import (
 	"syscall"
 
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/contracts"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/task"
 )
 
+// rusageBlockSize is the unit of the rusage block input and output counters
+const rusageBlockSize = 512
+
 func prepareProcess(command *exec.Cmd) {
 	// make the process the leader of its process group
 	// (otherwise we cannot kill it properly)
 	command.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 }
 
+// UserCommandExecuter executes commands as the user with the given ids, the agent must run as root
+type UserCommandExecuter struct {
+	ShellCommandExecuter
+	Uid uint32
+	Gid uint32
+	// PseudoTerminal attaches the commands to a pseudo-terminal like PseudoTerminalCommandExecuter
+	PseudoTerminal bool
+}
+
+// NewExecute executes a list of shell commands as the user of the executer in the given working directory
+func (e UserCommandExecuter) NewExecute(
+	context context.T,
+	workingDir string,
+	stdoutWriter io.Writer,
+	stderrWriter io.Writer,
+	cancelFlag task.CancelFlag,
+	executionTimeout int,
+	commandName string,
+	commandArguments []string,
+	envVars map[string]string,
+) (exitCode int, accounting *contracts.ProcessAccounting, err error) {
+	return executeCommand(context, cancelFlag, workingDir, stdoutWriter, stderrWriter, executionTimeout, commandName, commandArguments, envVars, e.PseudoTerminal, &processUser{uid: e.Uid, gid: e.Gid})
+}
+
+// setProcessUser runs the process as the user without supplementary groups
+func setProcessUser(command *exec.Cmd, user *processUser) {
+	command.SysProcAttr.Credential = &syscall.Credential{Uid: user.uid, Gid: user.gid, Groups: []uint32{}}
+}
+
 func quiesce() {
 	if runtime.GOOS != "darwin" {
 		return
@@ -73,3 +110,28 @@ func validateEnvironmentVariables(command *exec.Cmd) {
 		command.Env = env
 	}
 }
+
+// collectProcessAccounting reads the resource usage of the exited command from its rusage,
+// which includes the descendants the command waited for
+func collectProcessAccounting(log log.T, scope processScope, state *os.ProcessState) *contracts.ProcessAccounting {
+	if state == nil {
+		return nil
+	}
+	usage, ok := state.SysUsage().(*syscall.Rusage)
+	if !ok || usage == nil {
+		log.Debugf("No resource usage is available for the command process")
+		return nil
+	}
+	maxRSS := int64(usage.Maxrss)
+	if runtime.GOOS != "darwin" {
+		// reported in kilobytes everywhere but darwin
+		maxRSS *= 1024
+	}
+	return &contracts.ProcessAccounting{
+		UserCPUSeconds:   state.UserTime().Seconds(),
+		SystemCPUSeconds: state.SystemTime().Seconds(),
+		MaxRSSBytes:      maxRSS,
+		ReadBytes:        int64(usage.Inblock) * rusageBlockSize,
+		WriteBytes:       int64(usage.Oublock) * rusageBlockSize,
+	}
+}
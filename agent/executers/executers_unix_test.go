@@ -0,0 +1,54 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build darwin || freebsd || linux || netbsd || openbsd
+// +build darwin freebsd linux netbsd openbsd
+
+package executers
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	"github.com/aws/amazon-ssm-agent/agent/task"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetProcessUser(t *testing.T) {
+	command := exec.Command("true")
+	prepareProcess(command)
+	setProcessUser(command, &processUser{uid: 61000, gid: 61001})
+
+	assert.True(t, command.SysProcAttr.Setpgid)
+	assert.Equal(t, uint32(61000), command.SysProcAttr.Credential.Uid)
+	assert.Equal(t, uint32(61001), command.SysProcAttr.Credential.Gid)
+	assert.Empty(t, command.SysProcAttr.Credential.Groups)
+}
+
+func TestUserCommandExecuter(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("running commands as another user requires root")
+	}
+	var stdout, stderr bytes.Buffer
+	executer := UserCommandExecuter{Uid: 65534, Gid: 65534}
+
+	exitCode, _, err := executer.NewExecute(context.NewMockDefault(), "/", &stdout, &stderr, task.NewChanneledCancelFlag(), 10, "/bin/sh", []string{"-c", "echo $(id -u) $(id -g)"}, map[string]string{})
+
+	assert.NoError(t, err, stderr.String())
+	assert.Equal(t, 0, exitCode)
+	assert.Equal(t, []string{"65534", "65534"}, strings.Fields(stdout.String()))
+}
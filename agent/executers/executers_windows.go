@@ -19,6 +19,9 @@ package executers
 import (
 	"os"
 	"os/exec"
+
+	"github.com/aws/amazon-ssm-agent/agent/contracts"
+	"github.com/aws/amazon-ssm-agent/agent/log"
 )
 
 const (
@@ -29,6 +32,10 @@ func prepareProcess(command *exec.Cmd) {
 	// nothing to do on windows
 }
 
+func setProcessUser(command *exec.Cmd, user *processUser) {
+	// commands run as the agent user on windows
+}
+
 func quiesce() {
 	// not needed for Darwin workaround
 }
@@ -43,3 +50,22 @@ func killProcess(process *os.Process, signal *timeoutSignal) error {
 // Running powershell on linux required the HOME env variable to be set and to remove the TERM env variable
 func validateEnvironmentVariables(command *exec.Cmd) {
 }
+
+// collectProcessAccounting reads the resource usage of the whole command process tree from its job object,
+// falling back to the cpu times of the command process when the command does not run in a job object
+func collectProcessAccounting(log log.T, scope processScope, state *os.ProcessState) *contracts.ProcessAccounting {
+	if job, ok := scope.(*jobObjectScope); ok && job.attached {
+		accounting, err := job.accounting()
+		if err == nil {
+			return accounting
+		}
+		log.Debugf("Failed to query command job object accounting, using process times: %v", err)
+	}
+	if state == nil {
+		return nil
+	}
+	return &contracts.ProcessAccounting{
+		UserCPUSeconds:   state.UserTime().Seconds(),
+		SystemCPUSeconds: state.SystemTime().Seconds(),
+	}
+}
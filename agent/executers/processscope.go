@@ -0,0 +1,48 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package executers
+
+import (
+	"errors"
+	"os"
+)
+
+// errNoProcessScope is returned by kill when descendants of the command are not tracked
+var errNoProcessScope = errors.New("no dedicated process scope is available, processes detached from the command may still be running")
+
+// processScope groups a command with every process it spawns so that the whole
+// process tree can be terminated on cancel or timeout, including descendants that
+// detached from the process group via nohup or setsid.
+type processScope interface {
+	// attach places the started process in the scope, if that cannot be done at creation time
+	attach(process *os.Process)
+	// kill terminates every process that is still running in the scope
+	kill() error
+	// release frees the resources held by the scope
+	release()
+	// String describes the scope for logs and command output
+	String() string
+}
+
+// processGroupScope is used when a dedicated scope cannot be created.
+// Only the process (group) of the command is killed, so detached descendants survive.
+type processGroupScope struct{}
+
+func (processGroupScope) attach(process *os.Process) {}
+
+func (processGroupScope) kill() error { return errNoProcessScope }
+
+func (processGroupScope) release() {}
+
+func (processGroupScope) String() string { return "process group" }
@@ -0,0 +1,145 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build linux
+// +build linux
+
+package executers
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/log"
+)
+
+const (
+	cgroupScopePrefix     = "ssm-command-"
+	cgroupControllersFile = "cgroup.controllers"
+	cgroupKillFile        = "cgroup.kill"
+
+	// staleCgroupScopeAge is the minimum age of a leftover command cgroup before it is removed
+	staleCgroupScopeAge = time.Minute
+)
+
+var cgroupRoot = "/sys/fs/cgroup"
+var selfCgroupFile = "/proc/self/cgroup"
+
+// cgroupScope runs a command in its own cgroup v2 so that every process it spawns can be killed together
+type cgroupScope struct {
+	log  log.T
+	path string
+	dir  *os.File
+}
+
+// newProcessScope creates a child cgroup of the agent cgroup and configures the command to start inside it.
+// Falls back to the process group when cgroup v2 with cgroup.kill support (kernel 5.14+) is not available.
+func newProcessScope(log log.T, command *exec.Cmd) processScope {
+	parent, err := agentCgroupPath()
+	if err != nil {
+		log.Debugf("Command cgroup is not available, using process group: %v", err)
+		return processGroupScope{}
+	}
+	removeStaleCgroupScopes(log, parent)
+
+	path, err := os.MkdirTemp(parent, cgroupScopePrefix)
+	if err != nil {
+		log.Debugf("Failed to create command cgroup, using process group: %v", err)
+		return processGroupScope{}
+	}
+	dir, err := os.Open(path)
+	if err != nil {
+		log.Debugf("Failed to open command cgroup %s, using process group: %v", path, err)
+		os.Remove(path)
+		return processGroupScope{}
+	}
+
+	if command.SysProcAttr == nil {
+		command.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	command.SysProcAttr.UseCgroupFD = true
+	command.SysProcAttr.CgroupFD = int(dir.Fd())
+	return &cgroupScope{log: log, path: path, dir: dir}
+}
+
+// agentCgroupPath returns the cgroup v2 directory of the agent process
+func agentCgroupPath() (string, error) {
+	// the unified hierarchy is mounted at the root, or below it on hybrid hierarchy hosts
+	unifiedRoot := cgroupRoot
+	if _, err := os.Stat(filepath.Join(unifiedRoot, cgroupControllersFile)); err != nil {
+		unifiedRoot = filepath.Join(cgroupRoot, "unified")
+		if _, err = os.Stat(filepath.Join(unifiedRoot, cgroupControllersFile)); err != nil {
+			return "", fmt.Errorf("cgroup v2 is not mounted at %s", cgroupRoot)
+		}
+	}
+	file, err := os.Open(selfCgroupFile)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// the unified hierarchy is reported as "0::<path>"
+		if relativePath, found := strings.CutPrefix(scanner.Text(), "0::"); found {
+			path := filepath.Join(unifiedRoot, relativePath)
+			if _, err = os.Stat(filepath.Join(path, cgroupKillFile)); err != nil {
+				return "", fmt.Errorf("%s does not support %s", path, cgroupKillFile)
+			}
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no cgroup v2 entry found in %s", selfCgroupFile)
+}
+
+// removeStaleCgroupScopes removes command cgroups left behind by commands whose processes have since exited.
+// Cgroups that still contain processes cannot be removed and are kept.
+func removeStaleCgroupScopes(log log.T, parent string) {
+	paths, _ := filepath.Glob(filepath.Join(parent, cgroupScopePrefix+"*"))
+	for _, path := range paths {
+		if info, err := os.Stat(path); err != nil || time.Since(info.ModTime()) < staleCgroupScopeAge {
+			continue
+		}
+		if err := os.Remove(path); err == nil {
+			log.Debugf("Removed stale command cgroup %s", path)
+		}
+	}
+}
+
+func (s *cgroupScope) attach(process *os.Process) {
+	// the process is started inside the cgroup through CgroupFD
+}
+
+// kill sends SIGKILL to every process in the cgroup, including processes that left the process group
+func (s *cgroupScope) kill() error {
+	return os.WriteFile(filepath.Join(s.path, cgroupKillFile), []byte("1"), 0)
+}
+
+// release removes the cgroup once it is empty.
+// Processes the command left running on purpose keep the cgroup until they exit.
+func (s *cgroupScope) release() {
+	s.dir.Close()
+	if err := os.Remove(s.path); err != nil {
+		s.log.Debugf("Command cgroup %s still has running processes, keeping it: %v", s.path, err)
+	}
+}
+
+func (s *cgroupScope) String() string {
+	return "cgroup " + s.path
+}
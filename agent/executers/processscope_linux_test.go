@@ -0,0 +1,129 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build linux
+// +build linux
+
+package executers
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	logmocks "github.com/aws/amazon-ssm-agent/agent/mocks/log"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupFakeCgroup creates a fake cgroup v2 hierarchy with the agent in the given cgroup
+func setupFakeCgroup(t *testing.T, root string, agentCgroup string, supportsKill bool) {
+	tempDir := t.TempDir()
+	cgroupRoot = filepath.Join(tempDir, "cgroup")
+	selfCgroupFile = filepath.Join(tempDir, "self-cgroup")
+	t.Cleanup(func() {
+		cgroupRoot = "/sys/fs/cgroup"
+		selfCgroupFile = "/proc/self/cgroup"
+	})
+
+	unifiedRoot := filepath.Join(cgroupRoot, root)
+	agentPath := filepath.Join(unifiedRoot, agentCgroup)
+	assert.NoError(t, os.MkdirAll(agentPath, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(unifiedRoot, cgroupControllersFile), []byte(""), 0644))
+	if supportsKill {
+		assert.NoError(t, os.WriteFile(filepath.Join(agentPath, cgroupKillFile), []byte(""), 0644))
+	}
+	assert.NoError(t, os.WriteFile(selfCgroupFile, []byte("1:name=systemd:"+agentCgroup+"\n0::"+agentCgroup+"\n"), 0644))
+}
+
+func TestAgentCgroupPath(t *testing.T) {
+	for _, root := range []string{"", "unified"} {
+		setupFakeCgroup(t, root, "/system.slice/amazon-ssm-agent.service", true)
+
+		path, err := agentCgroupPath()
+		assert.NoError(t, err)
+		assert.Equal(t, filepath.Join(cgroupRoot, root, "system.slice", "amazon-ssm-agent.service"), path)
+	}
+}
+
+func TestAgentCgroupPathWithoutKillSupport(t *testing.T) {
+	setupFakeCgroup(t, "", "/system.slice/amazon-ssm-agent.service", false)
+
+	_, err := agentCgroupPath()
+	assert.Error(t, err)
+}
+
+func TestNewProcessScopeFallsBackToProcessGroup(t *testing.T) {
+	setupFakeCgroup(t, "", "/system.slice/amazon-ssm-agent.service", false)
+	command := exec.Command("true")
+	prepareProcess(command)
+
+	scope := newProcessScope(logmocks.NewMockLog(), command)
+
+	assert.IsType(t, processGroupScope{}, scope)
+	assert.False(t, command.SysProcAttr.UseCgroupFD)
+	assert.Equal(t, errNoProcessScope, scope.kill())
+}
+
+func TestCgroupScope(t *testing.T) {
+	setupFakeCgroup(t, "", "/system.slice/amazon-ssm-agent.service", true)
+	agentPath := filepath.Join(cgroupRoot, "system.slice", "amazon-ssm-agent.service")
+	command := exec.Command("true")
+	prepareProcess(command)
+
+	scope := newProcessScope(logmocks.NewMockLog(), command)
+
+	cgroup, ok := scope.(*cgroupScope)
+	assert.True(t, ok)
+	assert.Equal(t, agentPath, filepath.Dir(cgroup.path))
+	assert.True(t, command.SysProcAttr.Setpgid)
+	assert.True(t, command.SysProcAttr.UseCgroupFD)
+	assert.Equal(t, int(cgroup.dir.Fd()), command.SysProcAttr.CgroupFD)
+
+	assert.NoError(t, scope.kill())
+	content, err := os.ReadFile(filepath.Join(cgroup.path, cgroupKillFile))
+	assert.NoError(t, err)
+	assert.Equal(t, "1", string(content))
+
+	// a real cgroup has no regular files, remove the one written by kill so the directory can be removed
+	assert.NoError(t, os.Remove(filepath.Join(cgroup.path, cgroupKillFile)))
+	scope.release()
+	_, err = os.Stat(cgroup.path)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestRemoveStaleCgroupScopes(t *testing.T) {
+	parent := t.TempDir()
+	stale := filepath.Join(parent, cgroupScopePrefix+"stale")
+	recent := filepath.Join(parent, cgroupScopePrefix+"recent")
+	populated := filepath.Join(parent, cgroupScopePrefix+"populated")
+	other := filepath.Join(parent, "other")
+	for _, path := range []string{stale, recent, populated, other} {
+		assert.NoError(t, os.Mkdir(path, 0755))
+	}
+	assert.NoError(t, os.WriteFile(filepath.Join(populated, "cgroup.procs"), []byte("42"), 0644))
+	old := time.Now().Add(-2 * staleCgroupScopeAge)
+	for _, path := range []string{stale, populated, other} {
+		assert.NoError(t, os.Chtimes(path, old, old))
+	}
+
+	removeStaleCgroupScopes(logmocks.NewMockLog(), parent)
+
+	_, err := os.Stat(stale)
+	assert.True(t, os.IsNotExist(err))
+	for _, path := range []string{recent, populated, other} {
+		_, err = os.Stat(path)
+		assert.NoError(t, err, path)
+	}
+}
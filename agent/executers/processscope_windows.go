@@ -0,0 +1,115 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build windows
+// +build windows
+
+package executers
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+
+	"github.com/aws/amazon-ssm-agent/agent/contracts"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+)
+
+// jobObjectBasicAndIoAccountingInformation is JOBOBJECT_BASIC_AND_IO_ACCOUNTING_INFORMATION, times are in 100ns units
+type jobObjectBasicAndIoAccountingInformation struct {
+	TotalUserTime             int64
+	TotalKernelTime           int64
+	ThisPeriodTotalUserTime   int64
+	ThisPeriodTotalKernelTime int64
+	TotalPageFaultCount       uint32
+	TotalProcesses            uint32
+	ActiveProcesses           uint32
+	TotalTerminatedProcesses  uint32
+	IoInfo                    windows.IO_COUNTERS
+}
+
+// jobObjectScope assigns a command to its own Job Object so that every process it spawns can be killed together
+type jobObjectScope struct {
+	log      log.T
+	job      windows.Handle
+	attached bool
+}
+
+// newProcessScope creates a Job Object for the command, falling back to killing the command process only
+func newProcessScope(log log.T, command *exec.Cmd) processScope {
+	job, err := windows.CreateJobObject(nil, nil)
+	if err != nil {
+		log.Debugf("Failed to create command job object, killing command process only: %v", err)
+		return processGroupScope{}
+	}
+	return &jobObjectScope{log: log, job: job}
+}
+
+// attach assigns the started process to the job object, child processes created afterwards join it automatically
+func (s *jobObjectScope) attach(process *os.Process) {
+	handle, err := windows.OpenProcess(windows.PROCESS_SET_QUOTA|windows.PROCESS_TERMINATE, false, uint32(process.Pid))
+	if err != nil {
+		s.log.Warnf("Failed to open process %d to assign it to the command job object: %v", process.Pid, err)
+		return
+	}
+	defer windows.CloseHandle(handle)
+
+	if err = windows.AssignProcessToJobObject(s.job, handle); err != nil {
+		s.log.Warnf("Failed to assign process %d to the command job object: %v", process.Pid, err)
+		return
+	}
+	s.attached = true
+}
+
+// kill terminates every process associated with the job object
+func (s *jobObjectScope) kill() error {
+	if !s.attached {
+		return errNoProcessScope
+	}
+	if err := windows.TerminateJobObject(s.job, 1); err != nil {
+		return fmt.Errorf("failed to terminate job object: %v", err)
+	}
+	return nil
+}
+
+// accounting returns the cpu time, io and peak committed memory of every process that ran in the job object
+func (s *jobObjectScope) accounting() (*contracts.ProcessAccounting, error) {
+	var basic jobObjectBasicAndIoAccountingInformation
+	if err := windows.QueryInformationJobObject(s.job, windows.JobObjectBasicAndIoAccountingInformation,
+		uintptr(unsafe.Pointer(&basic)), uint32(unsafe.Sizeof(basic)), nil); err != nil {
+		return nil, fmt.Errorf("failed to query job object accounting: %v", err)
+	}
+	var extended windows.JOBOBJECT_EXTENDED_LIMIT_INFORMATION
+	if err := windows.QueryInformationJobObject(s.job, windows.JobObjectExtendedLimitInformation,
+		uintptr(unsafe.Pointer(&extended)), uint32(unsafe.Sizeof(extended)), nil); err != nil {
+		return nil, fmt.Errorf("failed to query job object memory usage: %v", err)
+	}
+	return &contracts.ProcessAccounting{
+		UserCPUSeconds:   float64(basic.TotalUserTime) / 1e7,
+		SystemCPUSeconds: float64(basic.TotalKernelTime) / 1e7,
+		MaxRSSBytes:      int64(extended.PeakJobMemoryUsed),
+		ReadBytes:        int64(basic.IoInfo.ReadTransferCount),
+		WriteBytes:       int64(basic.IoInfo.WriteTransferCount),
+	}, nil
+}
+
+func (s *jobObjectScope) release() {
+	windows.CloseHandle(s.job)
+}
+
+func (s *jobObjectScope) String() string {
+	return "job object"
+}
@@ -0,0 +1,82 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build darwin || freebsd || linux || netbsd || openbsd
+// +build darwin freebsd linux netbsd openbsd
+
+package executers
+
+import (
+	"io"
+	"os/exec"
+	"syscall"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/creack/pty"
+)
+
+const (
+	pseudoTerminalRows    = 24
+	pseudoTerminalColumns = 200
+)
+
+// pseudoTerminalDrainTimeout bounds how long output is read after the command exited,
+// as processes left running in the background can keep the terminal open.
+var pseudoTerminalDrainTimeout = 2 * time.Second
+
+// attachPseudoTerminal connects the standard streams of the command to a new pseudo-terminal and copies
+// the terminal output to the given writer. The returned function must be called once the command exited.
+func attachPseudoTerminal(log log.T, command *exec.Cmd, writer io.Writer) (closeTerminal func(), err error) {
+	terminal, tty, err := pty.Open()
+	if err != nil {
+		return nil, err
+	}
+	if err = pty.Setsize(terminal, &pty.Winsize{Rows: pseudoTerminalRows, Cols: pseudoTerminalColumns}); err != nil {
+		log.Warnf("Failed to set pseudo-terminal size: %v", err)
+	}
+
+	command.Stdin = tty
+	command.Stdout = tty
+	command.Stderr = tty
+	if command.SysProcAttr == nil {
+		command.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	// the command leads a new session with the terminal as its controlling terminal,
+	// which also makes it the leader of its process group
+	command.SysProcAttr.Setpgid = false
+	command.SysProcAttr.Setsid = true
+	command.SysProcAttr.Setctty = true
+	command.SysProcAttr.Ctty = 0
+
+	outputWriter := &terminalOutputWriter{baseWriter: writer}
+	copied := make(chan struct{})
+	go func() {
+		defer close(copied)
+		// reading fails once every process closed the terminal, which is how the end of output is reported
+		io.Copy(outputWriter, terminal)
+	}()
+
+	return func() {
+		// only the command should hold the terminal open
+		tty.Close()
+		select {
+		case <-copied:
+		case <-time.After(pseudoTerminalDrainTimeout):
+			log.Debug("Pseudo-terminal is still open after the command exited, stop reading output")
+		}
+		terminal.Close()
+		<-copied
+		outputWriter.Flush()
+	}, nil
+}
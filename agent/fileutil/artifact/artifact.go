@@ -15,6 +15,7 @@
 package artifact
 
 import (
+	reqContext "context"
 	"crypto/md5"
 	"crypto/sha1"
 	"crypto/sha256"
@@ -54,10 +55,20 @@ type DownloadInput struct {
 	DestinationDirectory string
 	SourceChecksums      map[string]string
 	ExpectedBucketOwner  string
+	// RequestContext interrupts the download once it is cancelled, the download runs to completion when nil
+	RequestContext reqContext.Context
+}
+
+// requestContext returns the context the download of input runs in.
+func (input DownloadInput) requestContext() reqContext.Context {
+	if input.RequestContext == nil {
+		return reqContext.Background()
+	}
+	return input.RequestContext
 }
 
 // httpDownload attempts to download a file via http/s call
-func httpDownload(ctx context.T, fileURL string, destFile string, expectedBucketOwner string) (output DownloadOutput, err error) {
+func httpDownload(ctx context.T, requestCtx reqContext.Context, fileURL string, destFile string, expectedBucketOwner string) (output DownloadOutput, err error) {
 	log := ctx.Log()
 	log.Debugf("attempting to download as http/https download from %v to %v", fileURL, destFile)
 
@@ -70,7 +81,7 @@ func httpDownload(ctx context.T, fileURL string, destFile string, expectedBucket
 		eTagFile := destFile + ".etag"
 		var check http.Client
 		var httpRequest *http.Request
-		httpRequest, err = http.NewRequest("GET", fileURL, nil)
+		httpRequest, err = http.NewRequestWithContext(requestCtx, "GET", fileURL, nil)
 		if err != nil {
 			return
 		}
@@ -142,7 +153,7 @@ func httpDownload(ctx context.T, fileURL string, destFile string, expectedBucket
 		return
 	}
 
-	err = backoff.Retry(download, exponentialBackoff)
+	err = backoff.Retry(download, backoff.WithContext(exponentialBackoff, requestCtx))
 	return
 }
 
@@ -173,6 +184,59 @@ func CanGetS3Object(context context.T, amazonS3URL s3util.AmazonS3URL) bool {
 	return res.DeleteMarker == nil || !*(res.DeleteMarker)
 }
 
+// S3ObjectMetadata is the metadata of an S3 object
+type S3ObjectMetadata struct {
+	ContentType string
+	Metadata    map[string]string
+	Tags        map[string]string
+}
+
+// GetS3ObjectMetadata returns the content type, the user metadata and the tags of an S3 object.
+// User metadata keys are lower cased the way they are stored in S3.
+func GetS3ObjectMetadata(context context.T, amazonS3URL s3util.AmazonS3URL, expectedBucketOwner string) (metadata S3ObjectMetadata, err error) {
+	log := context.Log()
+	headParams := &s3.HeadObjectInput{
+		Bucket: aws.String(amazonS3URL.Bucket),
+		Key:    aws.String(amazonS3URL.Key),
+	}
+	taggingParams := &s3.GetObjectTaggingInput{
+		Bucket: aws.String(amazonS3URL.Bucket),
+		Key:    aws.String(amazonS3URL.Key),
+	}
+	if expectedBucketOwner = strings.TrimSpace(expectedBucketOwner); expectedBucketOwner != "" {
+		headParams.ExpectedBucketOwner = aws.String(expectedBucketOwner)
+		taggingParams.ExpectedBucketOwner = aws.String(expectedBucketOwner)
+	}
+
+	sess, err := s3util.GetS3CrossRegionCapableSession(context, amazonS3URL.Bucket)
+	if err != nil {
+		return metadata, fmt.Errorf("failed to get S3 session: %v", err)
+	}
+
+	s3client := s3.New(sess)
+	head, err := s3client.HeadObject(headParams)
+	if err != nil {
+		return metadata, fmt.Errorf("failed to get metadata of %v: %v", amazonS3URL.Key, err)
+	}
+	metadata.ContentType = aws.StringValue(head.ContentType)
+	metadata.Metadata = make(map[string]string, len(head.Metadata))
+	for key, value := range head.Metadata {
+		metadata.Metadata[strings.ToLower(key)] = aws.StringValue(value)
+	}
+
+	// reading tags requires s3:GetObjectTagging in addition to s3:GetObject, the metadata is returned without them
+	tagging, err := s3client.GetObjectTagging(taggingParams)
+	if err != nil {
+		log.Warnf("Failed to get tags of %v: %v", amazonS3URL.Key, err)
+		return metadata, nil
+	}
+	metadata.Tags = make(map[string]string, len(tagging.TagSet))
+	for _, tag := range tagging.TagSet {
+		metadata.Tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return metadata, nil
+}
+
 // ListS3Folders returns the folders under a given S3 URL where folders are keys whose prefix is the URL key
 // and contain a / after the prefix.  The folder name is the part between the prefix and the /.
 func ListS3Folders(context context.T, amazonS3URL s3util.AmazonS3URL) (folderNames []string, err error) {
@@ -213,6 +277,16 @@ func ListS3Folders(context context.T, amazonS3URL s3util.AmazonS3URL) (folderNam
 // ListS3Directory returns all the objects (files and folders) under a given S3 URL where folders are keys whose prefix
 // is the URL key and contain a / after the prefix.
 func ListS3Directory(context context.T, amazonS3URL s3util.AmazonS3URL) (folderNames []string, err error) {
+	err = ListS3DirectoryPages(context, amazonS3URL, func(keys []string) bool {
+		folderNames = append(folderNames, keys...)
+		return true
+	})
+	return
+}
+
+// ListS3DirectoryPages lists the same objects as ListS3Directory, but passes the keys to fn one page at a time as soon
+// as the page is received, so the caller does not have to hold the whole listing. The listing stops when fn returns false.
+func ListS3DirectoryPages(context context.T, amazonS3URL s3util.AmazonS3URL, fn func(keys []string) bool) (err error) {
 	log := context.Log()
 	var params *s3.ListObjectsInput
 	prefix := amazonS3URL.Key
@@ -234,29 +308,29 @@ func ListS3Directory(context context.T, amazonS3URL s3util.AmazonS3URL) (folderN
 
 	sess, err := s3util.GetS3CrossRegionCapableSession(context, amazonS3URL.Bucket)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get S3 session: %v", err)
+		return fmt.Errorf("failed to get S3 session: %v", err)
 	}
 
 	s3client := s3.New(sess)
 	err = s3client.ListObjectsPages(params, func(page *s3.ListObjectsOutput, lastPage bool) bool {
-		log.Debugf("Contents %v ", page.Contents)
-		for i, contents := range page.Contents {
-			folderNames = append(folderNames, *contents.Key)
-			log.Debug("Name of file/folder - ", folderNames[i])
+		keys := make([]string, 0, len(page.Contents))
+		for _, contents := range page.Contents {
+			keys = append(keys, *contents.Key)
+			log.Debug("Name of file/folder - ", *contents.Key)
 		}
-		return true
+		return fn(keys)
 	})
 
 	if err != nil {
 		log.Warnf("ListS3Directory error %v", err.Error())
-		return folderNames, err
+		return err
 	}
 
 	return
 }
 
 // s3Download attempts to download a file via the aws sdk.
-func s3Download(context context.T, amazonS3URL s3util.AmazonS3URL, destFile string, expectedBucketOwner string) (output DownloadOutput, err error) {
+func s3Download(context context.T, requestCtx reqContext.Context, amazonS3URL s3util.AmazonS3URL, destFile string, expectedBucketOwner string) (output DownloadOutput, err error) {
 	log := context.Log()
 	log.Debugf("attempting to download as s3 download %v", destFile)
 	eTagFile := destFile + ".etag"
@@ -289,6 +363,7 @@ func s3Download(context context.T, amazonS3URL s3util.AmazonS3URL, destFile stri
 	s3client := s3.New(sess)
 
 	req, resp := s3client.GetObjectRequest(params)
+	req.SetContext(requestCtx)
 	err = req.Send()
 	if err != nil {
 		if req.HTTPResponse == nil || req.HTTPResponse.StatusCode != http.StatusNotModified {
@@ -417,19 +492,7 @@ func Download(context context.T, input DownloadInput) (output DownloadOutput, er
 		urlHash := sha1.Sum([]byte(fileURL.String()))
 		output.LocalFilePath = filepath.Join(destinationDir, fmt.Sprintf("%x", urlHash))
 
-		amazonS3URL := s3util.ParseAmazonS3URL(log, fileURL)
-		if amazonS3URL.IsBucketAndKeyPresent() {
-			var tempOutput DownloadOutput
-			tempOutput, err = s3Download(context, amazonS3URL, output.LocalFilePath, input.ExpectedBucketOwner)
-			if err != nil {
-				log.Info("An error occurred when attempting s3 download. Attempting http/https download as fallback.")
-				tempOutput, err = httpDownload(context, input.SourceURL, output.LocalFilePath, input.ExpectedBucketOwner)
-			}
-			output = tempOutput
-		} else {
-			output, err = httpDownload(context, input.SourceURL, output.LocalFilePath, "")
-		}
-
+		output, err = getDownloadManager(context).download(context, input, fileURL, output.LocalFilePath)
 		if err != nil {
 			return
 		}
@@ -443,6 +506,25 @@ func Download(context context.T, input DownloadInput) (output DownloadOutput, er
 	return
 }
 
+// fetchFromSource downloads the file from s3, falling back to http/https, or from http/https directly.
+func fetchFromSource(context context.T, input DownloadInput, fileURL *url.URL, destFile string) (output DownloadOutput, err error) {
+	log := context.Log()
+	requestCtx := input.requestContext()
+	amazonS3URL := s3util.ParseAmazonS3URL(log, fileURL)
+	if amazonS3URL.IsBucketAndKeyPresent() {
+		output, err = s3Download(context, requestCtx, amazonS3URL, destFile, input.ExpectedBucketOwner)
+		if err != nil {
+			if requestCtx.Err() != nil {
+				return output, requestCtx.Err()
+			}
+			log.Info("An error occurred when attempting s3 download. Attempting http/https download as fallback.")
+			output, err = httpDownload(context, requestCtx, input.SourceURL, destFile, input.ExpectedBucketOwner)
+		}
+		return
+	}
+	return httpDownload(context, requestCtx, input.SourceURL, destFile, "")
+}
+
 // VerifyHash verifies the hash of the url file as per specified hash algorithm type and its value
 func VerifyHash(log log.T, input DownloadInput, output DownloadOutput) (bool, error) {
 	hasMatchingHash := false
@@ -0,0 +1,238 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package artifact
+
+import (
+	reqContext "context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/fileutil"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+)
+
+const artifactCacheDirName = "artifactcache"
+
+var (
+	downloadManagerOnce     sync.Once
+	downloadManagerInstance *downloadManager
+
+	// fetchArtifact downloads the artifact from its source, replaced in tests
+	fetchArtifact = fetchFromSource
+	// artifactCacheDir returns the directory of the content addressed artifact cache, replaced in tests
+	artifactCacheDir = func() string {
+		return filepath.Join(appconfig.DownloadRoot, artifactCacheDirName)
+	}
+)
+
+// downloadManager dedupes downloads of the same source requested by concurrent plugin executions
+// and caps the number of downloads running at the same time across the agent.
+type downloadManager struct {
+	mutex    sync.Mutex
+	inFlight map[string]*downloadCall
+	slots    chan struct{}
+}
+
+// downloadCall is a download in progress, callers asking for the same source wait for its result.
+type downloadCall struct {
+	done   chan struct{}
+	output DownloadOutput
+	err    error
+}
+
+// getDownloadManager returns the download manager shared by all plugin executions of the agent process.
+func getDownloadManager(context context.T) *downloadManager {
+	downloadManagerOnce.Do(func() {
+		downloadManagerInstance = newDownloadManager(context.AppConfig().Agent.ArtifactDownloadConcurrencyLimit)
+	})
+	return downloadManagerInstance
+}
+
+// newDownloadManager creates a download manager running at most limit downloads at the same time.
+func newDownloadManager(limit int) *downloadManager {
+	if limit < 1 {
+		limit = appconfig.DefaultArtifactDownloadConcurrencyLimit
+	}
+	return &downloadManager{
+		inFlight: make(map[string]*downloadCall),
+		slots:    make(chan struct{}, limit),
+	}
+}
+
+// download fetches the source of input to destFile, joining the download of the same source when one is already in progress.
+func (m *downloadManager) download(context context.T, input DownloadInput, fileURL *url.URL, destFile string) (output DownloadOutput, err error) {
+	log := context.Log()
+	key := input.SourceURL + "|" + strings.TrimSpace(input.ExpectedBucketOwner)
+	requestCtx := input.requestContext()
+
+	m.mutex.Lock()
+	for {
+		call, ok := m.inFlight[key]
+		if !ok {
+			break
+		}
+		m.mutex.Unlock()
+		log.Debugf("download of %v is already in progress, waiting for it", input.SourceURL)
+		select {
+		case <-call.done:
+		case <-requestCtx.Done():
+			return DownloadOutput{}, requestCtx.Err()
+		}
+		// the caller that started the download was cancelled, start over unless this caller was cancelled too
+		if call.err != nil && errors.Is(call.err, reqContext.Canceled) && requestCtx.Err() == nil {
+			m.mutex.Lock()
+			continue
+		}
+		if call.err != nil {
+			return call.output, call.err
+		}
+		return shareDownload(log, call.output, destFile)
+	}
+	call := &downloadCall{done: make(chan struct{})}
+	m.inFlight[key] = call
+	m.mutex.Unlock()
+
+	defer func() {
+		m.mutex.Lock()
+		delete(m.inFlight, key)
+		m.mutex.Unlock()
+		close(call.done)
+	}()
+
+	call.output, call.err = m.fetch(context, input, fileURL, destFile)
+	return call.output, call.err
+}
+
+// fetch downloads the artifact once a download slot is free, serving it from the artifact cache when enabled.
+func (m *downloadManager) fetch(context context.T, input DownloadInput, fileURL *url.URL, destFile string) (output DownloadOutput, err error) {
+	log := context.Log()
+	cacheEnabled := context.AppConfig().Agent.ArtifactCacheEnabled
+	checksum := expectedSha256(input)
+
+	if cacheEnabled && checksum != "" {
+		var found bool
+		if output, found = loadFromArtifactCache(log, checksum, destFile); found {
+			return output, nil
+		}
+	}
+
+	requestCtx := input.requestContext()
+	select {
+	case m.slots <- struct{}{}:
+	case <-requestCtx.Done():
+		return output, requestCtx.Err()
+	}
+	defer func() { <-m.slots }()
+
+	if output, err = fetchArtifact(context, input, fileURL, destFile); err != nil {
+		return
+	}
+
+	if cacheEnabled && checksum != "" {
+		storeInArtifactCache(log, checksum, output.LocalFilePath)
+	}
+	return
+}
+
+// shareDownload copies the file downloaded for another caller to destFile when the callers use different directories.
+func shareDownload(log log.T, output DownloadOutput, destFile string) (DownloadOutput, error) {
+	if output.LocalFilePath == destFile {
+		return output, nil
+	}
+	if err := copyFile(log, output.LocalFilePath, destFile); err != nil {
+		return DownloadOutput{}, fmt.Errorf("failed to copy downloaded file %v to %v: %v", output.LocalFilePath, destFile, err)
+	}
+	return DownloadOutput{LocalFilePath: destFile, IsUpdated: true}, nil
+}
+
+// expectedSha256 returns the sha256 checksum the artifact is verified against, or empty when there is none.
+func expectedSha256(input DownloadInput) string {
+	for hashAlgorithm, hashValue := range input.SourceChecksums {
+		if hashAlgorithm == "" || strings.EqualFold(hashAlgorithm, "sha256") {
+			return strings.ToLower(strings.TrimSpace(hashValue))
+		}
+	}
+	return ""
+}
+
+// loadFromArtifactCache copies the cached artifact with the checksum to destFile.
+func loadFromArtifactCache(log log.T, checksum string, destFile string) (output DownloadOutput, found bool) {
+	cachedFile := filepath.Join(artifactCacheDir(), checksum)
+	if !fileutil.Exists(cachedFile) {
+		return output, false
+	}
+	if hash, err := Sha256HashValue(log, cachedFile); err != nil || hash != checksum {
+		log.Warnf("removing cached artifact %v with mismatched checksum", cachedFile)
+		os.Remove(cachedFile)
+		return output, false
+	}
+
+	output.LocalFilePath = destFile
+	if hash, err := Sha256HashValue(log, destFile); err == nil && hash == checksum {
+		log.Debugf("%v is already up to date with cached artifact %v", destFile, checksum)
+		return output, true
+	}
+	if err := copyFile(log, cachedFile, destFile); err != nil {
+		log.Warnf("failed to copy cached artifact %v to %v: %v", cachedFile, destFile, err)
+		return output, false
+	}
+	log.Infof("served %v from the artifact cache", destFile)
+	output.IsUpdated = true
+	return output, true
+}
+
+// storeInArtifactCache keeps a copy of the downloaded file when it matches the expected checksum.
+func storeInArtifactCache(log log.T, checksum string, downloadedFile string) {
+	if hash, err := Sha256HashValue(log, downloadedFile); err != nil || hash != checksum {
+		return
+	}
+	cacheDir := artifactCacheDir()
+	cachedFile := filepath.Join(cacheDir, checksum)
+	if fileutil.Exists(cachedFile) {
+		return
+	}
+	if err := fileutil.MakeDirs(cacheDir); err != nil {
+		log.Warnf("failed to create artifact cache directory %v: %v", cacheDir, err)
+		return
+	}
+	// copy to a temporary name first so a concurrent reader never sees a partial file
+	tempFile := cachedFile + ".tmp"
+	if err := copyFile(log, downloadedFile, tempFile); err != nil {
+		log.Warnf("failed to cache artifact %v: %v", downloadedFile, err)
+		os.Remove(tempFile)
+		return
+	}
+	if err := os.Rename(tempFile, cachedFile); err != nil {
+		log.Warnf("failed to cache artifact %v: %v", downloadedFile, err)
+		os.Remove(tempFile)
+	}
+}
+
+// copyFile copies the content of srcFile to destFile
+func copyFile(log log.T, srcFile string, destFile string) error {
+	src, err := os.Open(srcFile)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	_, err = FileCopy(log, destFile, src)
+	return err
+}
@@ -0,0 +1,251 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package artifact
+
+import (
+	reqContext "context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	contextmocks "github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	"github.com/stretchr/testify/assert"
+)
+
+const testArtifactContent = "artifact content"
+
+func setFetchArtifact(t *testing.T, fetch func(context.T, DownloadInput, *url.URL, string) (DownloadOutput, error)) {
+	original := fetchArtifact
+	fetchArtifact = fetch
+	t.Cleanup(func() { fetchArtifact = original })
+}
+
+func writeArtifact(destFile string) (DownloadOutput, error) {
+	if err := os.WriteFile(destFile, []byte(testArtifactContent), 0600); err != nil {
+		return DownloadOutput{}, err
+	}
+	return DownloadOutput{LocalFilePath: destFile, IsUpdated: true}, nil
+}
+
+func TestDownloadManager_DedupesConcurrentDownloadsOfSameSource(t *testing.T) {
+	ctx := contextmocks.NewMockDefault()
+	release := make(chan struct{})
+	var fetchCount int32
+	setFetchArtifact(t, func(_ context.T, _ DownloadInput, _ *url.URL, destFile string) (DownloadOutput, error) {
+		atomic.AddInt32(&fetchCount, 1)
+		<-release
+		return writeArtifact(destFile)
+	})
+
+	manager := newDownloadManager(2)
+	input := DownloadInput{SourceURL: "https://example.com/artifact"}
+	fileURL, _ := url.Parse(input.SourceURL)
+	destFiles := []string{filepath.Join(t.TempDir(), "artifact"), filepath.Join(t.TempDir(), "artifact")}
+
+	var wg sync.WaitGroup
+	outputs := make([]DownloadOutput, len(destFiles))
+	errs := make([]error, len(destFiles))
+	for i := range destFiles {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			outputs[i], errs[i] = manager.download(ctx, input, fileURL, destFiles[i])
+		}(i)
+	}
+	assert.Eventually(t, func() bool {
+		manager.mutex.Lock()
+		defer manager.mutex.Unlock()
+		return len(manager.inFlight) == 1 && atomic.LoadInt32(&fetchCount) == 1
+	}, time.Second, 10*time.Millisecond)
+	// give the second caller time to join the download in progress
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetchCount))
+	for i, destFile := range destFiles {
+		assert.NoError(t, errs[i])
+		assert.Equal(t, destFile, outputs[i].LocalFilePath)
+		content, err := os.ReadFile(destFile)
+		assert.NoError(t, err)
+		assert.Equal(t, testArtifactContent, string(content))
+	}
+	assert.Empty(t, manager.inFlight)
+}
+
+func TestDownloadManager_LimitsConcurrentDownloads(t *testing.T) {
+	ctx := contextmocks.NewMockDefault()
+	var running, maxRunning int32
+	setFetchArtifact(t, func(_ context.T, _ DownloadInput, _ *url.URL, destFile string) (DownloadOutput, error) {
+		current := atomic.AddInt32(&running, 1)
+		for {
+			observed := atomic.LoadInt32(&maxRunning)
+			if current <= observed || atomic.CompareAndSwapInt32(&maxRunning, observed, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return writeArtifact(destFile)
+	})
+
+	manager := newDownloadManager(2)
+	dir := t.TempDir()
+	var wg sync.WaitGroup
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			input := DownloadInput{SourceURL: "https://example.com/" + name}
+			fileURL, _ := url.Parse(input.SourceURL)
+			_, err := manager.download(ctx, input, fileURL, filepath.Join(dir, name))
+			assert.NoError(t, err)
+		}(name)
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxRunning), int32(2))
+}
+
+func TestDownloadManager_StopsWaitingForSlotWhenCancelled(t *testing.T) {
+	ctx := contextmocks.NewMockDefault()
+	release := make(chan struct{})
+	started := make(chan struct{})
+	setFetchArtifact(t, func(_ context.T, _ DownloadInput, _ *url.URL, destFile string) (DownloadOutput, error) {
+		close(started)
+		<-release
+		return writeArtifact(destFile)
+	})
+	defer close(release)
+
+	manager := newDownloadManager(1)
+	dir := t.TempDir()
+	first := DownloadInput{SourceURL: "https://example.com/first"}
+	firstURL, _ := url.Parse(first.SourceURL)
+	go manager.download(ctx, first, firstURL, filepath.Join(dir, "first"))
+	<-started
+
+	requestCtx, cancel := reqContext.WithCancel(reqContext.Background())
+	cancel()
+	second := DownloadInput{SourceURL: "https://example.com/second", RequestContext: requestCtx}
+	secondURL, _ := url.Parse(second.SourceURL)
+	_, err := manager.download(ctx, second, secondURL, filepath.Join(dir, "second"))
+	assert.ErrorIs(t, err, reqContext.Canceled)
+}
+
+func TestDownloadManager_RestartsDownloadCancelledByOtherCaller(t *testing.T) {
+	ctx := contextmocks.NewMockDefault()
+	release := make(chan struct{})
+	var fetchCount int32
+	setFetchArtifact(t, func(_ context.T, input DownloadInput, _ *url.URL, destFile string) (DownloadOutput, error) {
+		if atomic.AddInt32(&fetchCount, 1) == 1 {
+			<-release
+			return DownloadOutput{}, input.RequestContext.Err()
+		}
+		return writeArtifact(destFile)
+	})
+
+	manager := newDownloadManager(2)
+	dir := t.TempDir()
+	requestCtx, cancel := reqContext.WithCancel(reqContext.Background())
+	first := DownloadInput{SourceURL: "https://example.com/artifact", RequestContext: requestCtx}
+	fileURL, _ := url.Parse(first.SourceURL)
+	firstDone := make(chan error)
+	go func() {
+		_, err := manager.download(ctx, first, fileURL, filepath.Join(dir, "first"))
+		firstDone <- err
+	}()
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&fetchCount) == 1 }, time.Second, time.Millisecond)
+
+	secondDone := make(chan error)
+	go func() {
+		output, err := manager.download(ctx, DownloadInput{SourceURL: first.SourceURL}, fileURL, filepath.Join(dir, "second"))
+		assert.Equal(t, filepath.Join(dir, "second"), output.LocalFilePath)
+		secondDone <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	close(release)
+
+	assert.ErrorIs(t, <-firstDone, reqContext.Canceled)
+	assert.NoError(t, <-secondDone)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&fetchCount))
+}
+
+func TestDownloadManager_ServesRepeatDownloadsFromArtifactCache(t *testing.T) {
+	config := appconfig.SsmagentConfig{}
+	config.Agent.ArtifactCacheEnabled = true
+	ctx := contextmocks.NewMockDefaultWithConfig(config)
+	cacheDir := t.TempDir()
+	originalCacheDir := artifactCacheDir
+	artifactCacheDir = func() string { return cacheDir }
+	defer func() { artifactCacheDir = originalCacheDir }()
+
+	var fetchCount int32
+	setFetchArtifact(t, func(_ context.T, _ DownloadInput, _ *url.URL, destFile string) (DownloadOutput, error) {
+		atomic.AddInt32(&fetchCount, 1)
+		return writeArtifact(destFile)
+	})
+
+	hash := sha256.Sum256([]byte(testArtifactContent))
+	checksum := hex.EncodeToString(hash[:])
+	manager := newDownloadManager(1)
+	fileURL, _ := url.Parse("https://example.com/artifact")
+
+	first := filepath.Join(t.TempDir(), "artifact")
+	_, err := manager.download(ctx, DownloadInput{SourceURL: fileURL.String(), SourceChecksums: map[string]string{"sha256": checksum}}, fileURL, first)
+	assert.NoError(t, err)
+	assert.FileExists(t, filepath.Join(cacheDir, checksum))
+
+	// another association referencing the same artifact from a different location
+	otherURL, _ := url.Parse("https://example.com/mirror/artifact")
+	second := filepath.Join(t.TempDir(), "artifact")
+	output, err := manager.download(ctx, DownloadInput{SourceURL: otherURL.String(), SourceChecksums: map[string]string{"SHA256": checksum}}, otherURL, second)
+	assert.NoError(t, err)
+	assert.Equal(t, second, output.LocalFilePath)
+	assert.True(t, output.IsUpdated)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetchCount))
+	content, err := os.ReadFile(second)
+	assert.NoError(t, err)
+	assert.Equal(t, testArtifactContent, string(content))
+}
+
+func TestDownloadManager_SkipsArtifactCacheWithoutChecksum(t *testing.T) {
+	config := appconfig.SsmagentConfig{}
+	config.Agent.ArtifactCacheEnabled = true
+	ctx := contextmocks.NewMockDefaultWithConfig(config)
+	cacheDir := t.TempDir()
+	originalCacheDir := artifactCacheDir
+	artifactCacheDir = func() string { return cacheDir }
+	defer func() { artifactCacheDir = originalCacheDir }()
+	setFetchArtifact(t, func(_ context.T, _ DownloadInput, _ *url.URL, destFile string) (DownloadOutput, error) {
+		return writeArtifact(destFile)
+	})
+
+	fileURL, _ := url.Parse("https://example.com/artifact")
+	_, err := newDownloadManager(1).download(ctx, DownloadInput{SourceURL: fileURL.String()}, fileURL, filepath.Join(t.TempDir(), "artifact"))
+	assert.NoError(t, err)
+
+	entries, err := os.ReadDir(cacheDir)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -323,6 +323,20 @@ func GetFileNamesUnsortedLaterThan(srcPath string, laterThan *time.Time) ([]stri
 	return fileNames, nil
 }
 
+// GetDirectorySize returns the total size in bytes of the regular files under srcPath
+func GetDirectorySize(srcPath string) (size int64, err error) {
+	err = filepath.Walk(srcPath, func(_ string, info os.FileInfo, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return
+}
+
 // GetDirectoryNames returns the names of all directories under a give srcPath
 func GetDirectoryNames(srcPath string) (directories []string, err error) {
 	if list, err := ioutil.ReadDir(srcPath); err == nil {
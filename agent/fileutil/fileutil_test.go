@@ -281,12 +281,17 @@ func (a ioUtilStub) WriteFile(filename string, data []byte, perm os.FileMode) er
 }
 
 func TestAppendToFile(t *testing.T) {
-	// Valid file
-	var file = "testdata/file.txt"
+	// Valid file, copied so the fixture is not modified
+	content, err := ioutil.ReadFile("testdata/file.txt")
+	assert.NoError(t, err)
+	dir := t.TempDir()
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "file.txt"), content, 0600))
 	// call method
-	filePath, err := AppendToFile("", file, " This is a sample text")
+	filePath, err := AppendToFile(dir, "file.txt", " This is a sample text")
 	assert.NoError(t, err, "expected no error")
-	fmt.Println(filePath)
+	appended, err := ioutil.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello World. This is a sample text", string(appended))
 }
 
 func TestIOHelperMock_MoveFiles(t *testing.T) {
@@ -88,7 +88,6 @@ func Uncompress(log log.T, src, dest string) error {
 
 // GetDiskSpaceInfo returns DiskSpaceInfo with available, free, and total bytes from system disk space
 func GetDiskSpaceInfo() (diskSpaceInfo DiskSpaceInfo, err error) {
-	var stat syscall.Statfs_t
 	var wd string
 
 	// get a rooted path name
@@ -96,8 +95,17 @@ func GetDiskSpaceInfo() (diskSpaceInfo DiskSpaceInfo, err error) {
 		return
 	}
 
+	return GetDiskSpaceInfoForPath(wd)
+}
+
+// GetDiskSpaceInfoForPath returns DiskSpaceInfo with available, free, and total bytes of the file system containing path
+func GetDiskSpaceInfoForPath(path string) (diskSpaceInfo DiskSpaceInfo, err error) {
+	var stat syscall.Statfs_t
+
 	// get filesystem statistics
-	syscall.Statfs(wd, &stat)
+	if err = syscall.Statfs(path, &stat); err != nil {
+		return
+	}
 
 	// get block size
 	bSize := uint64(stat.Bsize)
@@ -40,19 +40,25 @@ func Uncompress(log log.T, src, dest string) error {
 // GetDiskSpaceInfo returns available, free, and total bytes respectively from system disk space
 func GetDiskSpaceInfo() (diskSpaceInfo DiskSpaceInfo, err error) {
 	var wd string
-	var availBytes, totalBytes, freeBytes int64
 
 	// Get a rooted path name
 	if wd, err = os.Getwd(); err != nil {
 		return
 	}
 
+	return GetDiskSpaceInfoForPath(wd)
+}
+
+// GetDiskSpaceInfoForPath returns available, free, and total bytes of the volume containing path
+func GetDiskSpaceInfoForPath(path string) (diskSpaceInfo DiskSpaceInfo, err error) {
+	var availBytes, totalBytes, freeBytes int64
+
 	// Load kernel32.dll and find GetDiskFreeSpaceEX function
 	getDiskFreeSpace := windows.NewLazySystemDLL("kernel32.dll").NewProc("GetDiskFreeSpaceExW")
 
 	// Get the available bytes (for arguments, GetDiskFreeSpace function takes dir name, avail, total, and free respectively)
 	_, _, err = getDiskFreeSpace.Call(
-		uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr(wd))),
+		uintptr(unsafe.Pointer(syscall.StringToUTF16Ptr(path))),
 		uintptr(unsafe.Pointer(&availBytes)),
 		uintptr(unsafe.Pointer(&totalBytes)),
 		uintptr(unsafe.Pointer(&freeBytes)))
@@ -0,0 +1,118 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package fips implements the FIPS 140-3 mode of the agent, which restricts TLS to approved algorithms,
+// uses the FIPS endpoints of the services and checks the compliance of the configuration at startup
+package fips
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+)
+
+const fipsEndpointSuffix = "-fips"
+
+// endpointServices are the services the agent calls which have FIPS endpoints
+var endpointServices = map[string]bool{
+	"ssm":         true,
+	"ec2messages": true,
+	"ssmmessages": true,
+	"s3":          true,
+	"kms":         true,
+	"logs":        true,
+	"monitoring":  true,
+	"sts":         true,
+}
+
+// endpointRegionPrefixes are the partitions and regions in which the services have FIPS endpoints
+var endpointRegionPrefixes = []string{"us-east-", "us-west-", "us-gov-", "ca-"}
+
+// cipherSuites are the FIPS approved TLS 1.2 cipher suites, TLS 1.3 only offers AES-GCM suites in FIPS mode
+var cipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+}
+
+// curves are the FIPS approved key exchange curves
+var curves = []tls.CurveID{tls.CurveP256, tls.CurveP384, tls.CurveP521}
+
+// moduleEnabled reports whether the Go cryptographic module runs in FIPS mode
+var moduleEnabled = isModuleEnabled
+
+// Enabled returns true when the agent runs in FIPS mode
+func Enabled(config appconfig.SsmagentConfig) bool {
+	return config.Fips.Enabled
+}
+
+// Check returns an error when the agent runs in FIPS mode with FailOnNonCompliance and would use
+// non-compliant cryptography or endpoints, the issues are only logged without FailOnNonCompliance
+func Check(log log.T, config appconfig.SsmagentConfig) error {
+	if !Enabled(config) {
+		return nil
+	}
+
+	var issues []string
+	if !moduleEnabled() {
+		issues = append(issues, "the Go cryptographic module is not in FIPS 140-3 mode, build the agent with GOFIPS140 or run it with GODEBUG=fips140=on")
+	}
+	for name, endpoint := range map[string]string{
+		"Mds.Endpoint": config.Mds.Endpoint,
+		"Ssm.Endpoint": config.Ssm.Endpoint,
+		"Mgs.Endpoint": config.Mgs.Endpoint,
+		"Kms.Endpoint": config.Kms.Endpoint,
+		"S3.Endpoint":  config.S3.Endpoint,
+	} {
+		if endpoint != "" && !strings.Contains(endpoint, fipsEndpointSuffix) {
+			issues = append(issues, fmt.Sprintf("%v %v is not a FIPS endpoint", name, endpoint))
+		}
+	}
+
+	if len(issues) == 0 {
+		log.Info("Agent is running in FIPS mode")
+		return nil
+	}
+	if config.Fips.FailOnNonCompliance {
+		return fmt.Errorf("agent is not FIPS compliant: %v", strings.Join(issues, "; "))
+	}
+	for _, issue := range issues {
+		log.Warnf("FIPS mode: %v", issue)
+	}
+	return nil
+}
+
+// ApplyTLSConfig restricts the TLS config to the FIPS approved protocol versions, cipher suites and curves
+func ApplyTLSConfig(tlsConfig *tls.Config) {
+	tlsConfig.MinVersion = tls.VersionTLS12
+	tlsConfig.CipherSuites = cipherSuites
+	tlsConfig.CurvePreferences = curves
+}
+
+// EndpointService returns the name of the FIPS endpoint of the service in the region,
+// services or regions without FIPS endpoints keep the standard endpoint
+func EndpointService(service string, region string) string {
+	if !endpointServices[service] {
+		return service
+	}
+	for _, prefix := range endpointRegionPrefixes {
+		if strings.HasPrefix(region, prefix) {
+			return service + fipsEndpointSuffix
+		}
+	}
+	return service
+}
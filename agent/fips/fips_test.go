@@ -0,0 +1,82 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package fips
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	logmocks "github.com/aws/amazon-ssm-agent/agent/mocks/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func setModuleEnabled(t *testing.T, enabled bool) {
+	moduleEnabled = func() bool { return enabled }
+	t.Cleanup(func() { moduleEnabled = isModuleEnabled })
+}
+
+func TestCheck_Disabled(t *testing.T) {
+	setModuleEnabled(t, false)
+	config := appconfig.SsmagentConfig{}
+	config.Ssm.Endpoint = "ssm.us-east-1.amazonaws.com"
+
+	assert.NoError(t, Check(logmocks.NewMockLog(), config))
+}
+
+func TestCheck_Compliant(t *testing.T) {
+	setModuleEnabled(t, true)
+	config := appconfig.SsmagentConfig{}
+	config.Fips = appconfig.FipsCfg{Enabled: true, FailOnNonCompliance: true}
+	config.Ssm.Endpoint = "ssm-fips.us-east-1.amazonaws.com"
+
+	assert.NoError(t, Check(logmocks.NewMockLog(), config))
+}
+
+func TestCheck_NonCompliant(t *testing.T) {
+	setModuleEnabled(t, false)
+	config := appconfig.SsmagentConfig{}
+	config.Fips = appconfig.FipsCfg{Enabled: true, FailOnNonCompliance: true}
+	config.Kms.Endpoint = "kms.us-east-1.amazonaws.com"
+
+	err := Check(logmocks.NewMockLog(), config)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not in FIPS 140-3 mode")
+	assert.Contains(t, err.Error(), "Kms.Endpoint kms.us-east-1.amazonaws.com is not a FIPS endpoint")
+
+	// the issues are only logged without FailOnNonCompliance
+	config.Fips.FailOnNonCompliance = false
+	logMock := logmocks.NewMockLog()
+	assert.NoError(t, Check(logMock, config))
+	logMock.AssertCalled(t, "Warnf", "FIPS mode: %v", []interface{}{"Kms.Endpoint kms.us-east-1.amazonaws.com is not a FIPS endpoint"})
+}
+
+func TestApplyTLSConfig(t *testing.T) {
+	tlsConfig := &tls.Config{}
+	ApplyTLSConfig(tlsConfig)
+
+	assert.Equal(t, uint16(tls.VersionTLS12), tlsConfig.MinVersion)
+	assert.NotContains(t, tlsConfig.CipherSuites, tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256)
+	assert.NotContains(t, tlsConfig.CurvePreferences, tls.X25519)
+	assert.NotEmpty(t, tlsConfig.CipherSuites)
+}
+
+func TestEndpointService(t *testing.T) {
+	assert.Equal(t, "ssm-fips", EndpointService("ssm", "us-east-1"))
+	assert.Equal(t, "ssmmessages-fips", EndpointService("ssmmessages", "us-gov-west-1"))
+	assert.Equal(t, "s3-fips", EndpointService("s3", "ca-central-1"))
+	assert.Equal(t, "ssm", EndpointService("ssm", "eu-west-1"), "no FIPS endpoints outside of North America")
+	assert.Equal(t, "ssm", EndpointService("ssm", "us-iso-east-1"))
+	assert.Equal(t, "ec2", EndpointService("ec2", "us-east-1"), "service without FIPS endpoint")
+}
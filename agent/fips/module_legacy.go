@@ -0,0 +1,22 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build !go1.24
+// +build !go1.24
+
+package fips
+
+// isModuleEnabled returns false, Go releases before 1.24 do not include a FIPS 140-3 validated cryptographic module
+func isModuleEnabled() bool {
+	return false
+}
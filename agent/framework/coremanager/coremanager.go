@@ -15,6 +15,8 @@
 package coremanager
 
 import (
+	"encoding/json"
+	"fmt"
 	"path/filepath"
 	"runtime/debug"
 	"sync"
@@ -23,12 +25,17 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/agentlogstocloudwatch/cloudwatchlogspublisher"
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
 	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/contracts"
 	"github.com/aws/amazon-ssm-agent/agent/fileutil"
+	"github.com/aws/amazon-ssm-agent/agent/framework/coremanager/modulecontrol"
 	"github.com/aws/amazon-ssm-agent/agent/framework/coremodules"
 	"github.com/aws/amazon-ssm-agent/agent/framework/processor/executer/plugin"
 	"github.com/aws/amazon-ssm-agent/agent/framework/runpluginutil"
 	logger "github.com/aws/amazon-ssm-agent/agent/log"
 	"github.com/aws/amazon-ssm-agent/agent/rebooter"
+	"github.com/aws/amazon-ssm-agent/agent/ssmconnectionchannel"
+	"github.com/aws/amazon-ssm-agent/common/filewatcherbasedipc"
+	"github.com/aws/amazon-ssm-agent/common/identity"
 )
 
 const (
@@ -49,6 +56,18 @@ type CoreManager struct {
 	coreModules         coremodules.ModuleRegistry
 	cloudwatchPublisher *cloudwatchlogspublisher.CloudWatchPublisher
 	rebooter            rebooter.IRebootType
+	stopMutex           sync.Mutex
+	stopRequested       bool
+	deferredLaunched    bool
+	controlChannel      filewatcherbasedipc.IPCChannel
+}
+
+var waitForControlChannel = ssmconnectionchannel.WaitForControlChannel
+
+// createModuleControlChannel creates the channel ssm-cli sends the core module requests on
+var createModuleControlChannel = func(log logger.T, identity identity.IAgentIdentity) (filewatcherbasedipc.IPCChannel, error) {
+	channel, err, _ := filewatcherbasedipc.CreateFileWatcherChannel(log, identity, filewatcherbasedipc.ModeMaster, modulecontrol.ChannelName, false)
+	return channel, err
 }
 
 // NewCoreManager creates a new core module manager.
@@ -212,34 +231,104 @@ func initializeBookkeepingLocations(log logger.T, shortInstanceID string) bool {
 func (c *CoreManager) Start() {
 	go c.watchForReboot()
 	c.executeCoreModules()
+	c.startModuleControl()
 }
 
 // Stop requests the core modules to stop executing
 // Stop would be called by the agent and should be treated as hard stop
 func (c *CoreManager) Stop() {
+	c.stopMutex.Lock()
+	c.stopRequested = true
+	if c.controlChannel != nil {
+		c.controlChannel.Destroy()
+		c.controlChannel = nil
+	}
+	c.stopMutex.Unlock()
+	if drainPeriod := time.Duration(c.context.AppConfig().Shutdown.DrainPeriodSeconds) * time.Second; drainPeriod > 0 {
+		c.drainCoreModules(drainPeriod)
+	}
 	c.stopCoreModules(hardStopTimeout)
 }
 
-// executeCoreModules launches all the core modules
-func (c *CoreManager) executeCoreModules() {
+// drainCoreModules gives the core modules time to finish their in-flight work before they are stopped
+func (c *CoreManager) drainCoreModules(timeout time.Duration) {
+	log := c.context.Log()
+	log.Infof("core manager drain requested. timeout: %v", timeout)
+	var wg sync.WaitGroup
 	l := len(c.coreModules)
+
 	for i := 0; i < l; i++ {
+		wg.Add(1)
 		go func(i int) {
+			defer wg.Done()
 			defer func() {
 				if r := recover(); r != nil {
-					c.context.Log().Errorf("Execute core modules panic: %v", r)
-					c.context.Log().Errorf("Stacktrace:\n%s", debug.Stack())
+					log.Errorf("Core module drain request panic: %v", r)
+					log.Errorf("Stacktrace:\n%s", debug.Stack())
 				}
 			}()
+
 			module := c.coreModules[i]
-			var err error
-			if err = module.ModuleExecute(); err != nil {
-				c.context.Log().Errorf("error occurred trying to start core module. Plugin name: %v. Error: %v",
+			if err := module.ModuleDrain(timeout); err != nil {
+				log.Warnf("Plugin (%v) failed to drain with error: %v",
 					module.ModuleName(),
 					err)
 			}
 		}(i)
 	}
+
+	wg.Wait()
+}
+
+// executeCoreModules launches all the core modules, the deferred modules are launched once the control channel is set up
+func (c *CoreManager) executeCoreModules() {
+	startTime := time.Now()
+	var deferredModules []contracts.ICoreModuleWrapper
+	for _, module := range c.coreModules {
+		if deferrable, ok := module.(contracts.ICoreModuleDeferrable); ok && deferrable.ModuleDeferred() {
+			deferredModules = append(deferredModules, module)
+			continue
+		}
+		go c.executeCoreModule(module)
+	}
+	if len(deferredModules) > 0 {
+		go c.executeDeferredCoreModules(deferredModules, startTime)
+	}
+}
+
+// executeDeferredCoreModules waits for the control channel setup before launching the deferred core modules
+func (c *CoreManager) executeDeferredCoreModules(modules []contracts.ICoreModuleWrapper, startTime time.Time) {
+	log := c.context.Log()
+	if !waitForControlChannel(ssmconnectionchannel.ControlChannelWaitTimeout) {
+		log.Warnf("control channel is not set up after %v, launching the deferred core modules", ssmconnectionchannel.ControlChannelWaitTimeout)
+	}
+
+	c.stopMutex.Lock()
+	defer c.stopMutex.Unlock()
+	if c.stopRequested {
+		log.Info("core manager stop requested, skipping the deferred core modules")
+		return
+	}
+	c.deferredLaunched = true
+	log.Infof("launching %v deferred core modules %v after the core modules", len(modules), time.Since(startTime))
+	for _, module := range modules {
+		go c.executeCoreModule(module)
+	}
+}
+
+// executeCoreModule launches a core module
+func (c *CoreManager) executeCoreModule(module contracts.ICoreModuleWrapper) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.context.Log().Errorf("Execute core modules panic: %v", r)
+			c.context.Log().Errorf("Stacktrace:\n%s", debug.Stack())
+		}
+	}()
+	if err := module.ModuleExecute(); err != nil {
+		c.context.Log().Errorf("error occurred trying to start core module. Plugin name: %v. Error: %v",
+			module.ModuleName(),
+			err)
+	}
 }
 
 // stopCoreModules requests the core modules to stop
@@ -272,6 +361,124 @@ func (c *CoreManager) stopCoreModules(timeout time.Duration) {
 	wg.Wait()
 }
 
+// startModuleControl starts receiving the requests to list and control the core modules at runtime
+func (c *CoreManager) startModuleControl() {
+	log := c.context.Log()
+	channel, err := createModuleControlChannel(log, c.context.Identity())
+	if err != nil {
+		log.Warnf("failed to create the core module control channel, core modules can not be controlled at runtime: %v", err)
+		return
+	}
+
+	c.stopMutex.Lock()
+	defer c.stopMutex.Unlock()
+	if c.stopRequested {
+		channel.Destroy()
+		return
+	}
+	c.controlChannel = channel
+	go c.serveModuleControl(channel)
+}
+
+// serveModuleControl processes the core module requests until the channel is closed
+func (c *CoreManager) serveModuleControl(channel filewatcherbasedipc.IPCChannel) {
+	log := c.context.Log()
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf("Serve core module control panic: %v", r)
+			log.Errorf("Stacktrace:\n%s", debug.Stack())
+		}
+	}()
+
+	for message := range channel.GetMessage() {
+		var request modulecontrol.Request
+		if err := json.Unmarshal([]byte(message), &request); err != nil {
+			log.Warnf("ignoring malformed core module request: %v", err)
+			continue
+		}
+		response, err := json.Marshal(c.processModuleControlRequest(request))
+		if err != nil {
+			log.Errorf("failed to marshal the core module response: %v", err)
+			continue
+		}
+		if err = channel.Send(string(response)); err != nil {
+			log.Warnf("failed to send the core module response: %v", err)
+		}
+	}
+	log.Debug("core module control channel closed")
+}
+
+// processModuleControlRequest performs the requested action and returns the status of the core modules
+func (c *CoreManager) processModuleControlRequest(request modulecontrol.Request) modulecontrol.Response {
+	response := modulecontrol.Response{RequestId: request.RequestId}
+
+	c.stopMutex.Lock()
+	defer c.stopMutex.Unlock()
+	if c.stopRequested {
+		response.Error = "amazon-ssm-agent is stopping"
+		return response
+	}
+	if request.Action != modulecontrol.ActionList {
+		if err := c.controlCoreModule(request.Action, request.ModuleName); err != nil {
+			c.context.Log().Warnf("failed to %v core module %v: %v", request.Action, request.ModuleName, err)
+			response.Error = err.Error()
+		}
+	}
+
+	response.Modules = make([]modulecontrol.ModuleStatus, 0, len(c.coreModules))
+	for _, module := range c.coreModules {
+		status := modulecontrol.ModuleStatus{Name: module.ModuleName(), State: "Unknown"}
+		if controller, ok := module.(contracts.ICoreModuleController); ok {
+			status.State = controller.ModuleState()
+			status.Restartable = controller.ModuleRestartable()
+		}
+		response.Modules = append(response.Modules, status)
+	}
+	return response
+}
+
+// controlCoreModule restarts, disables or enables the core module, the caller holds the stop lock
+func (c *CoreManager) controlCoreModule(action string, moduleName string) (err error) {
+	log := c.context.Log()
+	var module contracts.ICoreModuleWrapper
+	for _, coreModule := range c.coreModules {
+		if coreModule.ModuleName() == moduleName {
+			module = coreModule
+			break
+		}
+	}
+	if module == nil {
+		return fmt.Errorf("unknown core module %v", moduleName)
+	}
+	controller, ok := module.(contracts.ICoreModuleController)
+	if !ok {
+		return fmt.Errorf("core module %v can not be controlled at runtime", moduleName)
+	}
+
+	log.Infof("%v of core module %v requested", action, moduleName)
+	switch action {
+	case modulecontrol.ActionRestart:
+		err = controller.ModuleRestart(softStopTimeout)
+	case modulecontrol.ActionEnable:
+		err = controller.ModuleEnable()
+	case modulecontrol.ActionDisable:
+		return controller.ModuleDisable(softStopTimeout)
+	default:
+		return fmt.Errorf("unsupported action %v", action)
+	}
+	if err != nil {
+		return err
+	}
+
+	// deferred modules that were not launched yet are executed with the other deferred modules
+	if deferrable, ok := module.(contracts.ICoreModuleDeferrable); ok && deferrable.ModuleDeferred() && !c.deferredLaunched {
+		return nil
+	}
+	go c.executeCoreModule(module)
+	return nil
+}
+
 // watchForReboot watches for reboot events and request core modules to stop when necessary
 func (c *CoreManager) watchForReboot() {
 	log := c.context.Log()
@@ -13,17 +13,27 @@
 package coremanager
 
 import (
+	"encoding/json"
+	"fmt"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/aws/amazon-ssm-agent/agent/agentlogstocloudwatch/cloudwatchlogspublisher"
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/contracts"
 	moduleMock "github.com/aws/amazon-ssm-agent/agent/contracts/mocks"
+	"github.com/aws/amazon-ssm-agent/agent/framework/coremanager/modulecontrol"
 	"github.com/aws/amazon-ssm-agent/agent/framework/coremodules"
+	logger "github.com/aws/amazon-ssm-agent/agent/log"
 	"github.com/aws/amazon-ssm-agent/agent/mocks/context"
 	"github.com/aws/amazon-ssm-agent/agent/mocks/log"
 	"github.com/aws/amazon-ssm-agent/agent/rebooter"
 	rebootMock "github.com/aws/amazon-ssm-agent/agent/rebooter/mocks"
+	"github.com/aws/amazon-ssm-agent/agent/ssmconnectionchannel"
+	"github.com/aws/amazon-ssm-agent/common/filewatcherbasedipc"
+	channelMock "github.com/aws/amazon-ssm-agent/common/filewatcherbasedipc/mocks"
+	"github.com/aws/amazon-ssm-agent/common/identity"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
@@ -38,6 +48,14 @@ type CoreManagerTestSuite struct {
 	rebootMock  *rebootMock.IRebootType
 }
 
+// The core managers started by the tests do not create a core module control channel, the tests that
+// need one create it themselves
+func (suite *CoreManagerTestSuite) SetupSuite() {
+	createModuleControlChannel = func(log logger.T, identity identity.IAgentIdentity) (filewatcherbasedipc.IPCChannel, error) {
+		return nil, fmt.Errorf("no core module control channel in tests")
+	}
+}
+
 // Initialize mock struct and objects in test suite.
 func (suite *CoreManagerTestSuite) SetupTest() {
 	logMock := log.NewMockLog()
@@ -129,6 +147,167 @@ func (suite *CoreManagerTestSuite) TestCoreManager_Stop() {
 	suite.coreManager.Stop()
 	suite.moduleMock.AssertCalled(suite.T(), "ModuleStop", mock.Anything)
 	suite.moduleMock.AssertNotCalled(suite.T(), "ModuleName")
+	suite.moduleMock.AssertNotCalled(suite.T(), "ModuleDrain", mock.Anything)
+}
+
+// Unit testing function for Stop() method with a drain period configured
+func (suite *CoreManagerTestSuite) TestCoreManager_Stop_WithDrainPeriod() {
+	config := appconfig.DefaultConfig()
+	config.Shutdown.DrainPeriodSeconds = 30
+	suite.coreManager.(*CoreManager).context = context.NewMockDefaultWithConfig(config)
+	suite.moduleMock.On("ModuleDrain", 30*time.Second).Return(nil).Once()
+
+	suite.coreManager.Stop()
+	suite.moduleMock.AssertCalled(suite.T(), "ModuleDrain", 30*time.Second)
+	suite.moduleMock.AssertCalled(suite.T(), "ModuleStop", mock.Anything)
+}
+
+// deferredModule is a core module the core manager launches once the control channel is set up
+type deferredModule struct {
+	*moduleMock.ICoreModuleWrapper
+}
+
+func (deferredModule) ModuleDeferred() bool {
+	return true
+}
+
+// Deferred core modules are launched once the control channel is set up
+func (suite *CoreManagerTestSuite) TestCoreManager_ExecuteDeferredCoreModules() {
+	controlChannelSettled := make(chan struct{})
+	waitForControlChannel = func(timeout time.Duration) bool {
+		<-controlChannelSettled
+		return true
+	}
+	defer func() { waitForControlChannel = ssmconnectionchannel.WaitForControlChannel }()
+	deferredMock := new(moduleMock.ICoreModuleWrapper)
+	executed := make(chan struct{})
+	deferredMock.On("ModuleExecute").Return(nil).Run(func(mock.Arguments) { close(executed) })
+	cm := suite.coreManager.(*CoreManager)
+	cm.coreModules = append(cm.coreModules, deferredModule{deferredMock})
+
+	cm.executeCoreModules()
+	time.Sleep(100 * time.Millisecond)
+	suite.moduleMock.AssertCalled(suite.T(), "ModuleExecute", mock.Anything)
+	deferredMock.AssertNotCalled(suite.T(), "ModuleExecute")
+
+	close(controlChannelSettled)
+	select {
+	case <-executed:
+	case <-time.After(time.Second):
+		suite.Fail("deferred core module should have been launched")
+	}
+}
+
+// Deferred core modules are not launched once the core manager is stopped
+func (suite *CoreManagerTestSuite) TestCoreManager_ExecuteDeferredCoreModules_Stopped() {
+	waitForControlChannel = func(timeout time.Duration) bool {
+		return false
+	}
+	defer func() { waitForControlChannel = ssmconnectionchannel.WaitForControlChannel }()
+	deferredMock := new(moduleMock.ICoreModuleWrapper)
+	cm := suite.coreManager.(*CoreManager)
+
+	cm.Stop()
+	cm.executeDeferredCoreModules([]contracts.ICoreModuleWrapper{deferredModule{deferredMock}}, time.Now())
+	deferredMock.AssertNotCalled(suite.T(), "ModuleExecute")
+}
+
+// newRestartableModule returns a core module wrapper whose module instances are mocks that execute and stop
+func newRestartableModule(name string, executed chan string) contracts.ICoreModuleWrapper {
+	instances := 0
+	newModule := func() contracts.ICoreModule {
+		instances++
+		instance := instances
+		module := new(moduleMock.ICoreModule)
+		module.On("ModuleName").Return(name)
+		module.On("ModuleExecute").Return(nil).Run(func(mock.Arguments) {
+			executed <- fmt.Sprintf("%v-%v", name, instance)
+		})
+		module.On("ModuleStop").Return(nil)
+		return module
+	}
+	return coremodules.NewRestartableCoreModuleWrapper(log.NewMockLog(), newModule(), newModule)
+}
+
+// Core modules are listed, restarted, disabled and enabled through the control channel
+func (suite *CoreManagerTestSuite) TestCoreManager_ModuleControl() {
+	ch := make(chan rebooter.RebootType)
+	defer close(ch)
+	suite.rebootMock.On("GetChannel").Return(ch)
+	executed := make(chan string, 10)
+	cm := suite.coreManager.(*CoreManager)
+	cm.coreModules = append(cm.coreModules, newRestartableModule("RestartableModule", executed))
+
+	waitExecuted := func(expected string) {
+		select {
+		case instance := <-executed:
+			suite.Equal(expected, instance)
+		case <-time.After(2 * time.Second):
+			suite.Fail(expected + " should have been executed")
+		}
+	}
+
+	cm.Start()
+	defer cm.Stop()
+	waitExecuted("RestartableModule-1")
+
+	// the fake channels of a name share their queues, the name is unique to the test
+	master := channelMock.NewFakeChannel(suite.logMock, filewatcherbasedipc.ModeMaster, suite.T().Name())
+	cm.stopMutex.Lock()
+	cm.controlChannel = master
+	cm.stopMutex.Unlock()
+	go cm.serveModuleControl(master)
+	client := channelMock.NewFakeChannel(suite.logMock, filewatcherbasedipc.ModeWorker, suite.T().Name())
+	defer client.Close()
+	send := func(action string, moduleName string) modulecontrol.Response {
+		request, _ := json.Marshal(modulecontrol.Request{RequestId: action, Action: action, ModuleName: moduleName})
+		suite.NoError(client.Send(string(request)))
+		var response modulecontrol.Response
+		select {
+		case message := <-client.GetMessage():
+			suite.NoError(json.Unmarshal([]byte(message), &response))
+		case <-time.After(2 * time.Second):
+			suite.Fail("no response received for " + action)
+		}
+		suite.Equal(action, response.RequestId)
+		return response
+	}
+
+	response := send(modulecontrol.ActionList, "")
+	suite.Empty(response.Error)
+	suite.Equal([]modulecontrol.ModuleStatus{
+		{Name: "TestExecuteModule", State: "Unknown"},
+		{Name: "RestartableModule", State: coremodules.ModuleStateRunning, Restartable: true},
+	}, response.Modules)
+
+	response = send(modulecontrol.ActionRestart, "RestartableModule")
+	suite.Empty(response.Error)
+	waitExecuted("RestartableModule-2")
+
+	response = send(modulecontrol.ActionDisable, "RestartableModule")
+	suite.Empty(response.Error)
+	suite.Equal(coremodules.ModuleStateDisabled, response.Modules[1].State)
+
+	response = send(modulecontrol.ActionEnable, "RestartableModule")
+	suite.Empty(response.Error)
+	waitExecuted("RestartableModule-3")
+
+	response = send(modulecontrol.ActionRestart, "TestExecuteModule")
+	suite.Equal("core module TestExecuteModule can not be controlled at runtime", response.Error)
+	response = send(modulecontrol.ActionRestart, "UnknownModule")
+	suite.Equal("unknown core module UnknownModule", response.Error)
+}
+
+// Core modules are not controlled once the core manager is stopped
+func (suite *CoreManagerTestSuite) TestCoreManager_ModuleControl_Stopped() {
+	executed := make(chan string, 10)
+	cm := suite.coreManager.(*CoreManager)
+	cm.coreModules = append(cm.coreModules, newRestartableModule("RestartableModule", executed))
+
+	cm.Stop()
+	response := cm.processModuleControlRequest(modulecontrol.Request{RequestId: "id", Action: modulecontrol.ActionEnable, ModuleName: "RestartableModule"})
+	suite.Equal(modulecontrol.Response{RequestId: "id", Error: "amazon-ssm-agent is stopping"}, response)
+	suite.Empty(executed)
 }
 
 func TestCoreManagerTestSuite(t *testing.T) {
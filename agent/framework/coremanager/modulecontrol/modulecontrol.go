@@ -0,0 +1,114 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package modulecontrol defines the requests sent to the core manager of the running agent to list,
+// restart, disable and enable its core modules
+package modulecontrol
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/common/filewatcherbasedipc"
+	"github.com/aws/amazon-ssm-agent/common/identity"
+)
+
+// ChannelName is the name of the IPC channel the core manager receives the requests on
+const ChannelName = "coremodules"
+
+// Actions of a request
+const (
+	ActionList    = "list"
+	ActionRestart = "restart"
+	ActionDisable = "disable"
+	ActionEnable  = "enable"
+)
+
+// Request asks the core manager to perform an action on a core module
+type Request struct {
+	RequestId  string
+	Action     string
+	ModuleName string
+}
+
+// ModuleStatus is the status of a core module
+type ModuleStatus struct {
+	Name        string
+	State       string
+	Restartable bool
+}
+
+// Response is sent by the core manager once the request is processed, Modules holds the status of the core modules
+// after the action
+type Response struct {
+	RequestId string
+	Modules   []ModuleStatus
+	Error     string
+}
+
+// createChannel is the delegate creating the requesting end of the channel, overridden in tests
+var createChannel = func(log log.T, identity identity.IAgentIdentity) (filewatcherbasedipc.IPCChannel, error) {
+	if found, err := filewatcherbasedipc.IsFileWatcherChannelPresent(identity, ChannelName); err != nil {
+		return nil, err
+	} else if !found {
+		return nil, fmt.Errorf("core module channel not found, make sure amazon-ssm-agent is running")
+	}
+	channel, err, _ := filewatcherbasedipc.CreateFileWatcherChannel(log, identity, filewatcherbasedipc.ModeWorker, ChannelName, false)
+	return channel, err
+}
+
+// SendRequest sends the request to the core manager of the running agent and waits for its response
+func SendRequest(log log.T, identity identity.IAgentIdentity, request Request, timeout time.Duration) (response Response, err error) {
+	channel, err := createChannel(log, identity)
+	if err != nil {
+		return response, err
+	}
+	defer func() {
+		// a request the agent did not pick up must not be processed once the agent starts
+		channel.CleanupOwnModeFiles()
+		channel.Close()
+	}()
+
+	message, err := json.Marshal(request)
+	if err != nil {
+		return response, err
+	}
+	if err = channel.Send(string(message)); err != nil {
+		return response, fmt.Errorf("failed to send the request to amazon-ssm-agent: %v", err)
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case message, ok := <-channel.GetMessage():
+			if !ok {
+				return response, fmt.Errorf("core module channel closed before amazon-ssm-agent responded")
+			}
+			var received Response
+			if err = json.Unmarshal([]byte(message), &received); err != nil {
+				log.Debugf("Ignoring malformed core module response: %v", err)
+				continue
+			}
+			// responses to requests of earlier or concurrent invocations are skipped
+			if received.RequestId != request.RequestId {
+				continue
+			}
+			return received, nil
+		case <-timer.C:
+			return response, fmt.Errorf("amazon-ssm-agent did not respond within %v", timeout)
+		}
+	}
+}
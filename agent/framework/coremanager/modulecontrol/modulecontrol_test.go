@@ -0,0 +1,66 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package modulecontrol
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	logger "github.com/aws/amazon-ssm-agent/agent/mocks/log"
+	"github.com/aws/amazon-ssm-agent/common/filewatcherbasedipc"
+	channelMock "github.com/aws/amazon-ssm-agent/common/filewatcherbasedipc/mocks"
+	"github.com/aws/amazon-ssm-agent/common/identity"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubChannel makes SendRequest use a fake channel named after the test and returns the agent end of it
+func stubChannel(t *testing.T) filewatcherbasedipc.IPCChannel {
+	createChannelOrig := createChannel
+	t.Cleanup(func() { createChannel = createChannelOrig })
+
+	agent := channelMock.NewFakeChannel(logger.NewMockLog(), filewatcherbasedipc.ModeMaster, t.Name())
+	t.Cleanup(agent.Destroy)
+	createChannel = func(log log.T, identity identity.IAgentIdentity) (filewatcherbasedipc.IPCChannel, error) {
+		return channelMock.NewFakeChannel(log, filewatcherbasedipc.ModeWorker, t.Name()), nil
+	}
+	return agent
+}
+
+func TestSendRequest(t *testing.T) {
+	agent := stubChannel(t)
+	go func() {
+		message := <-agent.GetMessage()
+		var request Request
+		json.Unmarshal([]byte(message), &request)
+
+		// the response to an earlier request is skipped
+		stale, _ := json.Marshal(Response{RequestId: "earlier", Error: "stale"})
+		agent.Send(string(stale))
+		response, _ := json.Marshal(Response{RequestId: request.RequestId, Modules: []ModuleStatus{{Name: request.ModuleName, State: "Running", Restartable: true}}})
+		agent.Send(string(response))
+	}()
+
+	response, err := SendRequest(logger.NewMockLog(), nil, Request{RequestId: "id", Action: ActionRestart, ModuleName: "HealthCheck"}, 5*time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, Response{RequestId: "id", Modules: []ModuleStatus{{Name: "HealthCheck", State: "Running", Restartable: true}}}, response)
+}
+
+func TestSendRequest_Timeout(t *testing.T) {
+	stubChannel(t)
+
+	_, err := SendRequest(logger.NewMockLog(), nil, Request{RequestId: "id", Action: ActionList}, 200*time.Millisecond)
+	assert.EqualError(t, err, "amazon-ssm-agent did not respond within 200ms")
+}
@@ -15,13 +15,21 @@
 package coremodules
 
 import (
+	"github.com/aws/amazon-ssm-agent/agent/auditlog/archiver"
 	"github.com/aws/amazon-ssm-agent/agent/context"
 	"github.com/aws/amazon-ssm-agent/agent/contracts"
+	"github.com/aws/amazon-ssm-agent/agent/crashdump/uploader"
+	"github.com/aws/amazon-ssm-agent/agent/eventlogforwarder"
 	"github.com/aws/amazon-ssm-agent/agent/health"
 	"github.com/aws/amazon-ssm-agent/agent/longrunning/manager"
 	"github.com/aws/amazon-ssm-agent/agent/messageservice"
+	"github.com/aws/amazon-ssm-agent/agent/metrics/exporter"
+	"github.com/aws/amazon-ssm-agent/agent/platform/facts"
+	"github.com/aws/amazon-ssm-agent/agent/reachability"
 	"github.com/aws/amazon-ssm-agent/agent/runcommand"
 	"github.com/aws/amazon-ssm-agent/agent/ssm"
+	"github.com/aws/amazon-ssm-agent/agent/storeforward/forwarder"
+	"github.com/aws/amazon-ssm-agent/agent/tracing"
 )
 
 // ModuleRegistry stores a set of core modules.
@@ -39,29 +47,88 @@ func RegisteredCoreModules(context context.T) *ModuleRegistry {
 }
 
 // register core modules here
+// modules not needed for the agent to become reachable are deferred so they do not compete with the control channel setup
+// modules that can be recreated are restartable, ssm-cli restarts, disables and enables them at runtime
 func loadCoreModules(context context.T) {
 	if !context.AppConfig().Agent.ContainerMode {
-		registeredCoreModules = append(registeredCoreModules, NewCoreModuleWrapper(context.Log(), health.NewHealthCheck(context, ssm.NewService(context))))
+		registeredCoreModules = append(registeredCoreModules, NewRestartableCoreModuleWrapper(context.Log(), health.NewHealthCheck(context, ssm.NewService(context)), func() contracts.ICoreModule {
+			return health.NewHealthCheck(context, ssm.NewService(context))
+		}))
+	}
+
+	modules := context.AppConfig().Modules
+	if !modules.DisableFacts {
+		registeredCoreModules = append(registeredCoreModules, NewDeferredRestartableCoreModuleWrapper(context.Log(), facts.NewPublisher(context), func() contracts.ICoreModule {
+			return facts.NewPublisher(context)
+		}))
+	}
+
+	if metricsConfig := context.AppConfig().Metrics; metricsConfig.Enabled || metricsConfig.TextfilePath != "" || metricsConfig.JSONFilePath != "" {
+		registeredCoreModules = append(registeredCoreModules, NewDeferredRestartableCoreModuleWrapper(context.Log(), exporter.NewServer(context), func() contracts.ICoreModule {
+			return exporter.NewServer(context)
+		}))
+	}
+
+	if context.AppConfig().Tracing.Enabled {
+		registeredCoreModules = append(registeredCoreModules, NewDeferredRestartableCoreModuleWrapper(context.Log(), tracing.NewExporter(context), func() contracts.ICoreModule {
+			return tracing.NewExporter(context)
+		}))
+	}
+
+	if context.AppConfig().CrashDump.Enabled {
+		registeredCoreModules = append(registeredCoreModules, NewDeferredRestartableCoreModuleWrapper(context.Log(), uploader.NewUploader(context), func() contracts.ICoreModule {
+			return uploader.NewUploader(context)
+		}))
+	}
+
+	if auditLogConfig := context.AppConfig().AuditLog; auditLogConfig.Enabled && auditLogConfig.S3BucketName != "" {
+		registeredCoreModules = append(registeredCoreModules, NewDeferredRestartableCoreModuleWrapper(context.Log(), archiver.NewArchiver(context), func() contracts.ICoreModule {
+			return archiver.NewArchiver(context)
+		}))
+	}
+
+	if context.AppConfig().StoreAndForward.Enabled {
+		registeredCoreModules = append(registeredCoreModules, NewDeferredRestartableCoreModuleWrapper(context.Log(), forwarder.NewForwarder(context), func() contracts.ICoreModule {
+			return forwarder.NewForwarder(context)
+		}))
+	}
+
+	if context.AppConfig().EventLogForwarding.Enabled {
+		registeredCoreModules = append(registeredCoreModules, NewDeferredRestartableCoreModuleWrapper(context.Log(), eventlogforwarder.NewForwarder(context), func() contracts.ICoreModule {
+			return eventlogforwarder.NewForwarder(context)
+		}))
+	}
+
+	if context.AppConfig().Reachability.Enabled {
+		registeredCoreModules = append(registeredCoreModules, NewDeferredRestartableCoreModuleWrapper(context.Log(), reachability.NewProber(context), func() contracts.ICoreModule {
+			return reachability.NewProber(context)
+		}))
 	}
 
 	messageServiceCoreModule := messageservice.NewService(context)
 	if messageServiceCoreModule != nil {
-		registeredCoreModules = append(registeredCoreModules, NewCoreModuleWrapper(context.Log(), messageServiceCoreModule))
+		registeredCoreModules = append(registeredCoreModules, NewRestartableCoreModuleWrapper(context.Log(), messageServiceCoreModule, func() contracts.ICoreModule {
+			return messageservice.NewService(context)
+		}))
 	}
 
 	if !context.AppConfig().Agent.ContainerMode {
-		if offlineProcessor, err := runcommand.NewOfflineService(context); err == nil {
-			registeredCoreModules = append(registeredCoreModules, NewCoreModuleWrapper(context.Log(), offlineProcessor))
-		} else {
-			context.Log().Errorf("Failed to start offline command document processor")
+		if !modules.DisableOfflineCommands {
+			if offlineProcessor, err := runcommand.NewOfflineService(context); err == nil {
+				registeredCoreModules = append(registeredCoreModules, NewDeferredCoreModuleWrapper(context.Log(), offlineProcessor))
+			} else {
+				context.Log().Errorf("Failed to start offline command document processor")
+			}
 		}
 
 		// registering the long running plugin manager as a core module
-		manager.EnsureInitialization(context)
-		if lrpm, err := manager.GetInstance(); err == nil {
-			registeredCoreModules = append(registeredCoreModules, NewCoreModuleWrapper(context.Log(), lrpm))
-		} else {
-			context.Log().Errorf("Something went wrong during initialization of long running plugin manager")
+		if !modules.DisableLongRunningPlugins {
+			manager.EnsureInitialization(context)
+			if lrpm, err := manager.GetInstance(); err == nil {
+				registeredCoreModules = append(registeredCoreModules, NewDeferredCoreModuleWrapper(context.Log(), lrpm))
+			} else {
+				context.Log().Errorf("Something went wrong during initialization of long running plugin manager")
+			}
 		}
 	}
 }
@@ -10,6 +10,16 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/log"
 )
 
+// Core module states reported by ModuleState
+const (
+	ModuleStatePending  = "Pending"
+	ModuleStateRunning  = "Running"
+	ModuleStateFailed   = "Failed"
+	ModuleStateStopping = "Stopping"
+	ModuleStateStopped  = "Stopped"
+	ModuleStateDisabled = "Disabled"
+)
+
 type CoreModuleWrapper struct {
 	module      contracts.ICoreModule
 	log         log.T
@@ -19,6 +29,12 @@ type CoreModuleWrapper struct {
 	started     bool
 	stopStarted bool
 	stopErr     error
+	executeErr  error
+	deferred    bool
+	disabled    bool
+
+	// newModule creates the instance replacing the module when it is restarted, nil if the module can not be restarted
+	newModule func() contracts.ICoreModule
 }
 
 func (c *CoreModuleWrapper) stop() {
@@ -47,9 +63,16 @@ func (c *CoreModuleWrapper) ModuleStop(waitTime time.Duration) (err error) {
 
 	// No need to stop if module never started
 	if !c.started {
+		if c.disabled {
+			return nil
+		}
 		return fmt.Errorf("cant stop module %s, module has never been started", c.module.ModuleName())
 	}
+	return c.stopModule(waitTime)
+}
 
+// stopModule stops the started module, the caller holds the lock
+func (c *CoreModuleWrapper) stopModule(waitTime time.Duration) error {
 	// If we have not started the stop processes, attempt to stop the module
 	if !c.stopStarted {
 		c.stopStarted = true
@@ -75,13 +98,200 @@ func (c *CoreModuleWrapper) ModuleStop(waitTime time.Duration) (err error) {
 	}
 }
 
+// ModuleDrain lets the module finish its in-flight work if it supports draining,
+// modules that are not running or already stopping are left untouched
+func (c *CoreModuleWrapper) ModuleDrain(waitTime time.Duration) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.log.Errorf("moduleDrain on %s panic with error: %v", c.module.ModuleName(), r)
+			c.log.Errorf("Stacktrace:\n%s", debug.Stack())
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	c.mtx.Lock()
+	module := c.module
+	skip := !c.started || c.stopStarted
+	c.mtx.Unlock()
+
+	drainer, ok := module.(contracts.ICoreModuleDrainer)
+	if !ok || skip {
+		return nil
+	}
+
+	c.log.Infof("Draining module %s", module.ModuleName())
+	return drainer.ModuleDrain(waitTime)
+}
+
 func (c *CoreModuleWrapper) ModuleName() string {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
 	return c.module.ModuleName()
 }
 
+func (c *CoreModuleWrapper) ModuleDeferred() bool {
+	return c.deferred
+}
+
 func (c *CoreModuleWrapper) ModuleExecute() error {
+	c.mtx.Lock()
+	if c.disabled {
+		c.mtx.Unlock()
+		c.log.Infof("Module %s is disabled, skipping execution", c.module.ModuleName())
+		return nil
+	}
+	if c.started {
+		c.mtx.Unlock()
+		return fmt.Errorf("module %s has already been started", c.module.ModuleName())
+	}
 	c.started = true
-	return c.module.ModuleExecute()
+	module := c.module
+	c.mtx.Unlock()
+
+	err := module.ModuleExecute()
+
+	c.mtx.Lock()
+	if c.module == module {
+		c.executeErr = err
+	}
+	c.mtx.Unlock()
+	return err
+}
+
+// ModuleState returns the state of the module, one of the ModuleState constants
+func (c *CoreModuleWrapper) ModuleState() string {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	switch {
+	case c.disabled:
+		return ModuleStateDisabled
+	case !c.started:
+		return ModuleStatePending
+	case c.stopStarted && c.isStopped():
+		return ModuleStateStopped
+	case c.stopStarted:
+		return ModuleStateStopping
+	case c.executeErr != nil:
+		return ModuleStateFailed
+	default:
+		return ModuleStateRunning
+	}
+}
+
+// ModuleRestartable returns whether the module can be restarted, disabled and enabled at runtime
+func (c *CoreModuleWrapper) ModuleRestartable() bool {
+	return c.newModule != nil
+}
+
+// ModuleRestart stops the module and replaces it with a new instance, the caller executes the new instance
+func (c *CoreModuleWrapper) ModuleRestart(waitTime time.Duration) (err error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	defer func() {
+		if r := recover(); r != nil {
+			c.log.Errorf("moduleRestart on %s panic with error: %v", c.module.ModuleName(), r)
+			c.log.Errorf("Stacktrace:\n%s", debug.Stack())
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	if c.newModule == nil {
+		return c.notRestartableError()
+	}
+	if c.disabled {
+		return fmt.Errorf("module %s is disabled, enable it instead", c.module.ModuleName())
+	}
+	if !c.started {
+		return fmt.Errorf("module %s has not been started yet", c.module.ModuleName())
+	}
+	if err = c.stopModule(waitTime); !c.isStopped() {
+		return err
+	} else if err != nil {
+		c.log.Warnf("Module %s stopped with error before restart: %v", c.module.ModuleName(), err)
+	}
+	return c.renewModule()
+}
+
+// ModuleDisable stops the module, a disabled module is not executed until it is enabled again
+func (c *CoreModuleWrapper) ModuleDisable(waitTime time.Duration) (err error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	defer func() {
+		if r := recover(); r != nil {
+			c.log.Errorf("moduleDisable on %s panic with error: %v", c.module.ModuleName(), r)
+			c.log.Errorf("Stacktrace:\n%s", debug.Stack())
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	// a stopped module can not be executed again, disabling is only reversible when the module can be recreated
+	if c.newModule == nil {
+		return c.notRestartableError()
+	}
+	if c.disabled {
+		return nil
+	}
+	if c.started {
+		if err = c.stopModule(waitTime); !c.isStopped() {
+			return err
+		} else if err != nil {
+			c.log.Warnf("Module %s stopped with error: %v", c.module.ModuleName(), err)
+		}
+	}
+	c.disabled = true
+	return nil
+}
+
+// ModuleEnable enables the disabled module, a module that ran before is replaced with a new instance
+// the caller executes
+func (c *CoreModuleWrapper) ModuleEnable() error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.newModule == nil {
+		return c.notRestartableError()
+	}
+	if !c.disabled {
+		return fmt.Errorf("module %s is not disabled", c.module.ModuleName())
+	}
+	if !c.started {
+		c.disabled = false
+		return nil
+	}
+	return c.renewModule()
+}
+
+// renewModule replaces the stopped module with a new instance, the caller holds the lock
+func (c *CoreModuleWrapper) renewModule() error {
+	module := c.newModule()
+	if module == nil {
+		return fmt.Errorf("failed to create a new instance of module %s", c.module.ModuleName())
+	}
+	c.module = module
+	c.started = false
+	c.stopStarted = false
+	c.stoppedChan = make(chan struct{})
+	c.stopErr = nil
+	c.executeErr = nil
+	c.disabled = false
+	return nil
+}
+
+// isStopped returns whether the module stop completed, the caller holds the lock
+func (c *CoreModuleWrapper) isStopped() bool {
+	select {
+	case <-c.stoppedChan:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *CoreModuleWrapper) notRestartableError() error {
+	return fmt.Errorf("module %s can not be controlled at runtime, restart amazon-ssm-agent instead", c.module.ModuleName())
 }
 
 func NewCoreModuleWrapper(log log.T, module contracts.ICoreModule) contracts.ICoreModuleWrapper {
@@ -95,3 +305,25 @@ func NewCoreModuleWrapper(log log.T, module contracts.ICoreModule) contracts.ICo
 		stopErr:     nil,
 	}
 }
+
+// NewDeferredCoreModuleWrapper wraps a module the core manager executes once the control channel is set up
+func NewDeferredCoreModuleWrapper(log log.T, module contracts.ICoreModule) contracts.ICoreModuleWrapper {
+	wrapper := NewCoreModuleWrapper(log, module).(*CoreModuleWrapper)
+	wrapper.deferred = true
+	return wrapper
+}
+
+// NewRestartableCoreModuleWrapper wraps a module that can be restarted at runtime, newModule creates the
+// instance replacing the module on restart
+func NewRestartableCoreModuleWrapper(log log.T, module contracts.ICoreModule, newModule func() contracts.ICoreModule) contracts.ICoreModuleWrapper {
+	wrapper := NewCoreModuleWrapper(log, module).(*CoreModuleWrapper)
+	wrapper.newModule = newModule
+	return wrapper
+}
+
+// NewDeferredRestartableCoreModuleWrapper wraps a deferred module that can be restarted at runtime
+func NewDeferredRestartableCoreModuleWrapper(log log.T, module contracts.ICoreModule, newModule func() contracts.ICoreModule) contracts.ICoreModuleWrapper {
+	wrapper := NewRestartableCoreModuleWrapper(log, module, newModule).(*CoreModuleWrapper)
+	wrapper.deferred = true
+	return wrapper
+}
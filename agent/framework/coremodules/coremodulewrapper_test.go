@@ -212,3 +212,154 @@ func TestCoreModuleWrapper_ModuleName(t *testing.T) {
 	assert.Equal(t, "SomeName", name)
 	module.AssertExpectations(t)
 }
+
+type drainableModule struct {
+	mocks.ICoreModule
+	drainTimeout time.Duration
+	drainErr     error
+}
+
+func (m *drainableModule) ModuleDrain(timeout time.Duration) error {
+	m.drainTimeout = timeout
+	return m.drainErr
+}
+
+func TestCoreModuleWrapper_ModuleDrain_NotDrainable(t *testing.T) {
+	module := &mocks.ICoreModule{}
+
+	wrapper := createTestModuleWrapper(module)
+	wrapper.started = true
+
+	assert.NoError(t, wrapper.ModuleDrain(time.Second))
+	module.AssertExpectations(t)
+}
+
+func TestCoreModuleWrapper_ModuleDrain_Started(t *testing.T) {
+	expErr := fmt.Errorf("SomeDrainError")
+
+	module := &drainableModule{drainErr: expErr}
+	module.On("ModuleName").Return("SomeModuleName")
+
+	wrapper := createTestModuleWrapper(module)
+	wrapper.started = true
+
+	err := wrapper.ModuleDrain(5 * time.Second)
+	assert.Equal(t, expErr, err)
+	assert.Equal(t, 5*time.Second, module.drainTimeout)
+}
+
+func TestCoreModuleWrapper_ModuleDrain_NeverStartedOrStopping(t *testing.T) {
+	module := &drainableModule{drainErr: fmt.Errorf("SomeDrainError")}
+
+	wrapper := createTestModuleWrapper(module)
+	assert.NoError(t, wrapper.ModuleDrain(time.Second))
+
+	wrapper.started = true
+	wrapper.stopStarted = true
+	assert.NoError(t, wrapper.ModuleDrain(time.Second))
+	assert.Equal(t, time.Duration(0), module.drainTimeout)
+}
+
+func TestCoreModuleWrapper_ModuleRestart(t *testing.T) {
+	log := logger.NewMockLog()
+
+	first := &mocks.ICoreModule{}
+	first.On("ModuleName").Return("SomeModuleName")
+	first.On("ModuleExecute").Return(nil).Once()
+	first.On("ModuleStop").Return(nil).Once()
+	second := &mocks.ICoreModule{}
+	second.On("ModuleExecute").Return(nil).Once()
+
+	wrapper := NewRestartableCoreModuleWrapper(log, first, func() contracts.ICoreModule { return second }).(*CoreModuleWrapper)
+	assert.True(t, wrapper.ModuleRestartable())
+	assert.Contains(t, wrapper.ModuleRestart(time.Second).Error(), "has not been started yet")
+
+	assert.NoError(t, wrapper.ModuleExecute())
+	assert.Equal(t, ModuleStateRunning, wrapper.ModuleState())
+
+	assert.NoError(t, wrapper.ModuleRestart(time.Second))
+	assert.Equal(t, ModuleStatePending, wrapper.ModuleState())
+	assert.NoError(t, wrapper.ModuleExecute())
+	assert.Equal(t, ModuleStateRunning, wrapper.ModuleState())
+	first.AssertExpectations(t)
+	second.AssertExpectations(t)
+}
+
+func TestCoreModuleWrapper_ModuleDisableEnable(t *testing.T) {
+	log := logger.NewMockLog()
+
+	first := &mocks.ICoreModule{}
+	first.On("ModuleName").Return("SomeModuleName")
+	first.On("ModuleExecute").Return(nil).Once()
+	first.On("ModuleStop").Return(nil).Once()
+	second := &mocks.ICoreModule{}
+
+	wrapper := NewDeferredRestartableCoreModuleWrapper(log, first, func() contracts.ICoreModule { return second }).(*CoreModuleWrapper)
+	assert.True(t, wrapper.ModuleDeferred())
+	assert.Contains(t, wrapper.ModuleEnable().Error(), "is not disabled")
+
+	assert.NoError(t, wrapper.ModuleExecute())
+	assert.NoError(t, wrapper.ModuleDisable(time.Second))
+	assert.Equal(t, ModuleStateDisabled, wrapper.ModuleState())
+	assert.Contains(t, wrapper.ModuleRestart(time.Second).Error(), "enable it instead")
+
+	// a disabled module is neither executed nor stopped again
+	assert.NoError(t, wrapper.ModuleExecute())
+	assert.NoError(t, wrapper.ModuleStop(time.Second))
+
+	assert.NoError(t, wrapper.ModuleEnable())
+	assert.Equal(t, ModuleStatePending, wrapper.ModuleState())
+	assert.Equal(t, second, wrapper.module)
+	first.AssertExpectations(t)
+	second.AssertNotCalled(t, "ModuleExecute")
+}
+
+func TestCoreModuleWrapper_ModuleDisable_NeverStarted(t *testing.T) {
+	module := &mocks.ICoreModule{}
+	module.On("ModuleName").Return("SomeModuleName")
+
+	wrapper := NewRestartableCoreModuleWrapper(logger.NewMockLog(), module, func() contracts.ICoreModule {
+		t.Fatal("module that never ran should not be recreated")
+		return nil
+	}).(*CoreModuleWrapper)
+
+	assert.NoError(t, wrapper.ModuleDisable(time.Second))
+	assert.NoError(t, wrapper.ModuleStop(time.Second))
+	assert.NoError(t, wrapper.ModuleEnable())
+	assert.Equal(t, ModuleStatePending, wrapper.ModuleState())
+	module.AssertNotCalled(t, "ModuleStop")
+}
+
+func TestCoreModuleWrapper_ModuleRestart_StopTimeout(t *testing.T) {
+	module := &mocks.ICoreModule{}
+	module.On("ModuleName").Return("SomeModuleName")
+	module.On("ModuleExecute").Return(nil).Once()
+	module.On("ModuleStop").Return(func() error {
+		time.Sleep(time.Second)
+		return nil
+	}).Once()
+
+	wrapper := NewRestartableCoreModuleWrapper(logger.NewMockLog(), module, func() contracts.ICoreModule { return module }).(*CoreModuleWrapper)
+	assert.NoError(t, wrapper.ModuleExecute())
+
+	err := wrapper.ModuleRestart(10 * time.Millisecond)
+	assert.Contains(t, err.Error(), "timeout stopping module")
+	assert.Equal(t, ModuleStateStopping, wrapper.ModuleState())
+}
+
+func TestCoreModuleWrapper_NotRestartable(t *testing.T) {
+	module := &mocks.ICoreModule{}
+	module.On("ModuleName").Return("SomeModuleName")
+	module.On("ModuleExecute").Return(fmt.Errorf("SomeError")).Once()
+
+	wrapper := NewCoreModuleWrapper(logger.NewMockLog(), module).(*CoreModuleWrapper)
+	assert.False(t, wrapper.ModuleRestartable())
+	assert.Error(t, wrapper.ModuleExecute())
+	assert.Equal(t, ModuleStateFailed, wrapper.ModuleState())
+	assert.Contains(t, wrapper.ModuleExecute().Error(), "has already been started")
+
+	for _, err := range []error{wrapper.ModuleRestart(time.Second), wrapper.ModuleDisable(time.Second), wrapper.ModuleEnable()} {
+		assert.Contains(t, err.Error(), "restart amazon-ssm-agent instead")
+	}
+	module.AssertNotCalled(t, "ModuleStop")
+}
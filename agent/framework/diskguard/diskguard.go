@@ -0,0 +1,90 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package diskguard verifies that enough disk space is free to run documents
+// and prunes old orchestration directories to keep it that way.
+package diskguard
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/fileutil"
+	"github.com/aws/amazon-ssm-agent/agent/framework/docmanager"
+	"github.com/aws/amazon-ssm-agent/agent/log/logger"
+)
+
+const megabyte = 1024 * 1024
+
+var (
+	getDiskSpaceInfo              = fileutil.GetDiskSpaceInfoForPath
+	pruneOrchestrationDirectories = docmanager.PruneOrchestrationDirectories
+)
+
+// Check prunes old orchestration directories when a pruning policy is configured, then returns an error
+// if less than the configured minimum space is free under the orchestration, download or log directories
+func Check(context context.T) error {
+	log := context.Log()
+	appConfig := context.AppConfig()
+	config := appConfig.DiskSpace
+
+	if config.OrchestrationMaxAgeHours > 0 || config.OrchestrationMaxSizeMegabytes > 0 {
+		shortInstanceID, err := context.Identity().ShortInstanceID()
+		if err != nil {
+			log.Warnf("Skipping orchestration directory pruning, failed to get instance id: %v", err)
+		} else {
+			pruneOrchestrationDirectories(log,
+				shortInstanceID,
+				appConfig.Agent.OrchestrationRootDir,
+				config.OrchestrationMaxAgeHours,
+				config.OrchestrationMaxSizeMegabytes)
+		}
+	}
+
+	if config.MinimumFreeMegabytes <= 0 {
+		return nil
+	}
+
+	for _, dir := range []string{appconfig.DefaultDataStorePath, appconfig.DownloadRoot, logger.DefaultLogDir} {
+		dir = existingAncestor(dir)
+		diskSpaceInfo, err := getDiskSpaceInfo(dir)
+		if err != nil {
+			log.Warnf("Failed to get free disk space under %v: %v", dir, err)
+			continue
+		}
+		if availableMB := diskSpaceInfo.AvailBytes / megabyte; availableMB < int64(config.MinimumFreeMegabytes) {
+			return fmt.Errorf("insufficient disk space under %v: %d MB available, at least %d MB required by the agent configuration (DiskSpace.MinimumFreeMegabytes)",
+				dir,
+				availableMB,
+				config.MinimumFreeMegabytes)
+		}
+	}
+	return nil
+}
+
+// existingAncestor returns the closest existing directory of path, as the agent only creates its directories on first use
+func existingAncestor(path string) string {
+	for {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path
+		}
+		path = parent
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package diskguard
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/fileutil"
+	"github.com/aws/amazon-ssm-agent/agent/framework/docmanager"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	contextmocks "github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	"github.com/stretchr/testify/assert"
+)
+
+func setup(t *testing.T, availableMB int64, err error) (pruned *[]int) {
+	pruned = &[]int{}
+	getDiskSpaceInfo = func(path string) (fileutil.DiskSpaceInfo, error) {
+		return fileutil.DiskSpaceInfo{AvailBytes: availableMB * megabyte}, err
+	}
+	pruneOrchestrationDirectories = func(_ log.T, _, _ string, maxAgeHours int, maxSizeMegabytes int) {
+		*pruned = append(*pruned, maxAgeHours, maxSizeMegabytes)
+	}
+	t.Cleanup(func() {
+		getDiskSpaceInfo = fileutil.GetDiskSpaceInfoForPath
+		pruneOrchestrationDirectories = docmanager.PruneOrchestrationDirectories
+	})
+	return pruned
+}
+
+func newContext(config appconfig.DiskSpaceCfg) *contextmocks.Mock {
+	appConfig := appconfig.DefaultConfig()
+	appConfig.DiskSpace = config
+	return contextmocks.NewMockDefaultWithConfig(appConfig)
+}
+
+func TestCheckDisabled(t *testing.T) {
+	pruned := setup(t, 0, nil)
+
+	assert.NoError(t, Check(newContext(appconfig.DiskSpaceCfg{})))
+	assert.Empty(t, *pruned)
+}
+
+func TestCheckEnoughSpace(t *testing.T) {
+	setup(t, 500, nil)
+
+	assert.NoError(t, Check(newContext(appconfig.DiskSpaceCfg{MinimumFreeMegabytes: 100})))
+}
+
+func TestCheckInsufficientSpace(t *testing.T) {
+	setup(t, 50, nil)
+
+	err := Check(newContext(appconfig.DiskSpaceCfg{MinimumFreeMegabytes: 100}))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "insufficient disk space")
+	assert.Contains(t, err.Error(), "50 MB available, at least 100 MB required")
+}
+
+func TestCheckIgnoresDiskSpaceErrors(t *testing.T) {
+	setup(t, 0, fmt.Errorf("statfs failed"))
+
+	assert.NoError(t, Check(newContext(appconfig.DiskSpaceCfg{MinimumFreeMegabytes: 100})))
+}
+
+func TestCheckPrunesOrchestrationDirectories(t *testing.T) {
+	pruned := setup(t, 500, nil)
+
+	assert.NoError(t, Check(newContext(appconfig.DiskSpaceCfg{OrchestrationMaxAgeHours: 24, OrchestrationMaxSizeMegabytes: 1024})))
+	assert.Equal(t, []int{24, 1024}, *pruned)
+}
@@ -20,6 +20,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime/debug"
+	"sort"
 	"sync"
 	"time"
 
@@ -460,6 +461,88 @@ func DeleteSessionOrchestrationDirectories(log log.T, instanceID, orchestrationR
 	log.Debugf("Completed session orchestration directory clean up of %v items", deletedCount)
 }
 
+// PruneOrchestrationDirectories deletes document orchestration directories older than maxAgeHours,
+// then the oldest remaining ones while their total size is above maxSizeMegabytes. 0 disables either policy.
+func PruneOrchestrationDirectories(log log.T, instanceID, orchestrationRootDirName string, maxAgeHours int, maxSizeMegabytes int) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Errorf("Prune orchestration directories panic: %v", r)
+			log.Errorf("Stacktrace:\n%s", debug.Stack())
+		}
+	}()
+
+	pruneLockName := orchestrationRootDirName + "prune"
+	if !getLock(pruneLockName) {
+		return
+	}
+	defer releaseLock(pruneLockName)
+
+	orchestrationRootDir := orchestrationDir(instanceID, orchestrationRootDirName, appconfig.DefaultDocumentRootDirName)
+	deletedCount := pruneDirectories(log, orchestrationRootDir, maxAgeHours, int64(maxSizeMegabytes)*1024*1024)
+
+	updateTime(pruneLockName)
+	log.Debugf("Completed orchestration directory pruning of %v items", deletedCount)
+}
+
+// pruneDirectories applies the age and size policies to the directories under rootDir, oldest first
+func pruneDirectories(log log.T, rootDir string, maxAgeHours int, maxSizeBytes int64) (deletedCount int) {
+	if !fileutil.Exists(rootDir) {
+		return 0
+	}
+
+	type orchestrationDirInfo struct {
+		path    string
+		modTime time.Time
+		size    int64
+	}
+
+	fileInfos, err := fileutil.ReadDir(rootDir)
+	if err != nil {
+		log.Warnf("Failed to list orchestration directories under %v: %v", rootDir, err)
+		return 0
+	}
+
+	var dirs []orchestrationDirInfo
+	var totalSize int64
+	for _, fileInfo := range fileInfos {
+		if !fileInfo.IsDir() {
+			continue
+		}
+		dirPath := filepath.Join(rootDir, fileInfo.Name())
+		size, err := fileutil.GetDirectorySize(dirPath)
+		if err != nil {
+			log.Debugf("Failed to get size of %v: %v", dirPath, err)
+		}
+		dirs = append(dirs, orchestrationDirInfo{path: dirPath, modTime: fileInfo.ModTime(), size: size})
+		totalSize += size
+	}
+	sort.Slice(dirs, func(i, j int) bool { return dirs[i].modTime.Before(dirs[j].modTime) })
+
+	maxAge := time.Duration(maxAgeHours) * time.Hour
+	for _, dir := range dirs {
+		if deletedCount >= maxOrchestrationDirectoryDeletions {
+			log.Warnf("Reached max number of deletions for orchestration directories: %v", deletedCount)
+			break
+		}
+
+		expired := maxAgeHours > 0 && time.Since(dir.modTime) > maxAge
+		oversized := maxSizeBytes > 0 && totalSize > maxSizeBytes
+		if !expired && !oversized {
+			// directories are sorted oldest first, the remaining ones are newer and within the size limit
+			break
+		}
+
+		log.Debugf("Pruning orchestration directory: %v", dir.path)
+		if err := fileutil.DeleteDirectory(dir.path); err != nil {
+			log.Debugf("Error deleting directory %v: %v", dir.path, err)
+			continue
+		}
+		totalSize -= dir.size
+		deletedCount += 1
+	}
+	return deletedCount
+}
+
 // isOlderThan checks whether the file is older than the retention duration
 func isOlderThan(log log.T, fileFullPath string, retentionDurationHours int) bool {
 	modificationTime, err := fileutil.GetFileModificationTime(fileFullPath)
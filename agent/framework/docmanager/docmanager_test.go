@@ -13,8 +13,12 @@
 package docmanager
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	logmocks "github.com/aws/amazon-ssm-agent/agent/mocks/log"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -65,3 +69,47 @@ func TestDocManagerLockMultipleFolders(t *testing.T) {
 	assert.False(t, getLock(TEST_DOC_DIR))
 	assert.False(t, getLock(TEST_SEC_DIR))
 }
+
+func createOrchestrationDir(t *testing.T, rootDir, name string, size int, age time.Duration) string {
+	dir := filepath.Join(rootDir, name)
+	assert.NoError(t, os.MkdirAll(dir, 0700))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "stdout"), make([]byte, size), 0600))
+	modTime := time.Now().Add(-age)
+	assert.NoError(t, os.Chtimes(dir, modTime, modTime))
+	return dir
+}
+
+func TestPruneDirectoriesByAge(t *testing.T) {
+	rootDir := t.TempDir()
+	oldDir := createOrchestrationDir(t, rootDir, "old", 10, 48*time.Hour)
+	newDir := createOrchestrationDir(t, rootDir, "new", 10, time.Hour)
+
+	deleted := pruneDirectories(logmocks.NewMockLog(), rootDir, 24, 0)
+
+	assert.Equal(t, 1, deleted)
+	assert.NoDirExists(t, oldDir)
+	assert.DirExists(t, newDir)
+}
+
+func TestPruneDirectoriesBySize(t *testing.T) {
+	rootDir := t.TempDir()
+	oldestDir := createOrchestrationDir(t, rootDir, "oldest", 600, 3*time.Hour)
+	olderDir := createOrchestrationDir(t, rootDir, "older", 600, 2*time.Hour)
+	newDir := createOrchestrationDir(t, rootDir, "new", 600, time.Hour)
+
+	deleted := pruneDirectories(logmocks.NewMockLog(), rootDir, 0, 1000)
+
+	assert.Equal(t, 2, deleted)
+	assert.NoDirExists(t, oldestDir)
+	assert.NoDirExists(t, olderDir)
+	assert.DirExists(t, newDir)
+}
+
+func TestPruneDirectoriesDisabled(t *testing.T) {
+	rootDir := t.TempDir()
+	oldDir := createOrchestrationDir(t, rootDir, "old", 10, 48*time.Hour)
+
+	assert.Equal(t, 0, pruneDirectories(logmocks.NewMockLog(), rootDir, 0, 0))
+	assert.Equal(t, 0, pruneDirectories(logmocks.NewMockLog(), filepath.Join(rootDir, "missing"), 24, 0))
+	assert.DirExists(t, oldDir)
+}
@@ -0,0 +1,228 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package docmanager
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/contracts"
+	"github.com/aws/amazon-ssm-agent/agent/fileutil"
+	"github.com/aws/amazon-ssm-agent/agent/jsonutil"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+)
+
+const (
+	// ExecutionRecordFileName is the file in the orchestration directory of a document holding its final result
+	ExecutionRecordFileName = "executionRecord.json"
+
+	// ExecutionStatusPending is reported for documents which are persisted but not started yet
+	ExecutionStatusPending contracts.ResultStatus = "Pending"
+
+	stdoutFileName = "stdout"
+	stderrFileName = "stderr"
+)
+
+// ExecutionRecord summarizes the execution of a document on this instance
+type ExecutionRecord struct {
+	DocumentID             string
+	CommandID              string
+	AssociationID          string
+	DocumentName           string
+	DocumentVersion        string
+	CreatedDate            string
+	Status                 contracts.ResultStatus
+	CompletedDateTime      time.Time
+	OrchestrationDirectory string
+	Plugins                []PluginExecutionRecord
+}
+
+// PluginExecutionRecord summarizes the execution of a single plugin of a document
+type PluginExecutionRecord struct {
+	Id             string
+	Name           string
+	StepName       string
+	Status         contracts.ResultStatus
+	Code           int
+	StartDateTime  time.Time
+	EndDateTime    time.Time
+	Error          string
+	StandardOutput string
+	StandardError  string
+}
+
+// NewExecutionRecord builds the execution record of a document from its state
+func NewExecutionRecord(docState contracts.DocumentState, status contracts.ResultStatus) ExecutionRecord {
+	info := docState.DocumentInformation
+	record := ExecutionRecord{
+		DocumentID:             info.DocumentID,
+		CommandID:              info.CommandID,
+		AssociationID:          info.AssociationID,
+		DocumentName:           info.DocumentName,
+		DocumentVersion:        info.DocumentVersion,
+		CreatedDate:            info.CreatedDate,
+		Status:                 status,
+		OrchestrationDirectory: docState.IOConfig.OrchestrationDirectory,
+		Plugins:                []PluginExecutionRecord{},
+	}
+	for _, plugin := range docState.InstancePluginsInformation {
+		pluginRecord := PluginExecutionRecord{
+			Id:            plugin.Id,
+			Name:          plugin.Name,
+			StepName:      plugin.Result.StepName,
+			Status:        plugin.Result.Status,
+			Code:          plugin.Result.Code,
+			StartDateTime: plugin.Result.StartDateTime,
+			EndDateTime:   plugin.Result.EndDateTime,
+			Error:         plugin.Result.Error,
+		}
+		if pluginRecord.Status == "" {
+			pluginRecord.Status = contracts.ResultStatusNotStarted
+		}
+		if record.OrchestrationDirectory != "" && pluginRecord.StepName != "" {
+			// plugin output is written to <orchestration directory>/<plugin name>/<step name>, see iohandler
+			outputDir := fileutil.BuildPath(record.OrchestrationDirectory, plugin.Name, pluginRecord.StepName)
+			if stdout := filepath.Join(outputDir, stdoutFileName); fileutil.Exists(stdout) {
+				pluginRecord.StandardOutput = stdout
+			}
+			if stderr := filepath.Join(outputDir, stderrFileName); fileutil.Exists(stderr) {
+				pluginRecord.StandardError = stderr
+			}
+		}
+		record.Plugins = append(record.Plugins, pluginRecord)
+	}
+	return record
+}
+
+// PersistExecutionRecord writes the record to the orchestration directory of the document.
+// Nothing is written when the orchestration directory was already cleaned up.
+func PersistExecutionRecord(record ExecutionRecord) error {
+	if record.OrchestrationDirectory == "" || !fileutil.Exists(record.OrchestrationDirectory) {
+		return nil
+	}
+	content, err := jsonutil.MarshalIndent(record)
+	if err != nil {
+		return err
+	}
+	return fileutil.WriteAllText(filepath.Join(record.OrchestrationDirectory, ExecutionRecordFileName), content)
+}
+
+// ListExecutionRecords returns the records of the documents pending or in progress on this instance,
+// followed by the records of the completed documents, most recently completed first
+func ListExecutionRecords(log log.T, instanceID string) []ExecutionRecord {
+	records := inFlightExecutionRecords(log, instanceID)
+
+	completed := make([]ExecutionRecord, 0)
+	rootDir := orchestrationDir(instanceID, appconfig.DefaultDocumentRootDirName, "")
+	for _, dir := range executionRecordDirectories(log, rootDir) {
+		var record ExecutionRecord
+		if err := jsonutil.UnmarshalFile(filepath.Join(dir, ExecutionRecordFileName), &record); err != nil {
+			log.Debugf("skipping execution record in %v: %v", dir, err)
+			continue
+		}
+		completed = append(completed, record)
+	}
+	sort.SliceStable(completed, func(i, j int) bool {
+		return completed[i].CompletedDateTime.After(completed[j].CompletedDateTime)
+	})
+
+	return append(records, completed...)
+}
+
+// GetExecutionRecords returns the records matching the given command, document or association id
+func GetExecutionRecords(log log.T, instanceID, id string) ([]ExecutionRecord, error) {
+	matches := make([]ExecutionRecord, 0)
+	for _, record := range ListExecutionRecords(log, instanceID) {
+		if record.CommandID == id || record.DocumentID == id || record.AssociationID == id {
+			matches = append(matches, record)
+		}
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no execution found for %v", id)
+	}
+	return matches, nil
+}
+
+// inFlightExecutionRecords builds the records of the documents persisted in the pending and current state folders
+func inFlightExecutionRecords(log log.T, instanceID string) []ExecutionRecord {
+	records := make([]ExecutionRecord, 0)
+	locations := []struct {
+		folder string
+		status contracts.ResultStatus
+	}{
+		{appconfig.DefaultLocationOfCurrent, contracts.ResultStatusInProgress},
+		{appconfig.DefaultLocationOfPending, ExecutionStatusPending},
+	}
+	for _, location := range locations {
+		stateDir := DocumentStateDir(instanceID, location.folder)
+		if !fileutil.Exists(stateDir) {
+			continue
+		}
+		fileNames, err := fileutil.GetFileNames(stateDir)
+		if err != nil {
+			log.Debugf("failed to read document state directory %v: %v", stateDir, err)
+			continue
+		}
+		for _, fileName := range fileNames {
+			var docState contracts.DocumentState
+			if err := jsonutil.UnmarshalFile(filepath.Join(stateDir, fileName), &docState); err != nil {
+				log.Debugf("skipping document state %v: %v", fileName, err)
+				continue
+			}
+			if docState.DocumentType == contracts.CancelCommand {
+				continue
+			}
+			records = append(records, NewExecutionRecord(docState, location.status))
+		}
+	}
+	return records
+}
+
+// executionRecordDirectories returns the orchestration directories holding an execution record.
+// Run command directories hold the record directly, association runs are nested under the association id.
+func executionRecordDirectories(log log.T, rootDir string) []string {
+	dirs := make([]string, 0)
+	if !fileutil.Exists(rootDir) {
+		return dirs
+	}
+	dirNames, err := fileutil.GetDirectoryNames(rootDir)
+	if err != nil {
+		log.Debugf("failed to read orchestration directory %v: %v", rootDir, err)
+		return dirs
+	}
+	for _, dirName := range dirNames {
+		dir := filepath.Join(rootDir, dirName)
+		if fileutil.Exists(filepath.Join(dir, ExecutionRecordFileName)) {
+			dirs = append(dirs, dir)
+			continue
+		}
+		runDirNames, err := fileutil.GetDirectoryNames(dir)
+		if err != nil {
+			continue
+		}
+		for _, runDirName := range runDirNames {
+			if !isAssociationRunDirName(runDirName) {
+				continue
+			}
+			runDir := filepath.Join(dir, runDirName)
+			if fileutil.Exists(filepath.Join(runDir, ExecutionRecordFileName)) {
+				dirs = append(dirs, runDir)
+			}
+		}
+	}
+	return dirs
+}
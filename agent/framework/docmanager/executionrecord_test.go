@@ -0,0 +1,107 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package docmanager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/contracts"
+	"github.com/aws/amazon-ssm-agent/agent/jsonutil"
+	"github.com/aws/amazon-ssm-agent/agent/mocks/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func testExecutionDocState(orchestrationDir string) contracts.DocumentState {
+	return contracts.DocumentState{
+		DocumentInformation: contracts.DocumentInfo{
+			DocumentID:   "01234567-890a-bcde-f012-34567890abcd",
+			CommandID:    "01234567-890a-bcde-f012-34567890abcd",
+			DocumentName: "AWS-RunShellScript",
+		},
+		IOConfig: contracts.IOConfiguration{OrchestrationDirectory: orchestrationDir},
+		InstancePluginsInformation: []contracts.PluginState{
+			{
+				Id:   "runShellScript",
+				Name: "aws:runShellScript",
+				Result: contracts.PluginResult{
+					StepName: "runShellScript",
+					Status:   contracts.ResultStatusFailed,
+					Code:     2,
+				},
+			},
+			{
+				Id:   "second",
+				Name: "aws:runShellScript",
+			},
+		},
+	}
+}
+
+func TestNewExecutionRecord(t *testing.T) {
+	orchestrationDir := t.TempDir()
+	outputDir := filepath.Join(orchestrationDir, "awsrunShellScript", "runShellScript")
+	assert.NoError(t, os.MkdirAll(outputDir, 0700))
+	assert.NoError(t, os.WriteFile(filepath.Join(outputDir, "stdout"), []byte("out"), 0600))
+
+	record := NewExecutionRecord(testExecutionDocState(orchestrationDir), contracts.ResultStatusFailed)
+
+	assert.Equal(t, "AWS-RunShellScript", record.DocumentName)
+	assert.Equal(t, contracts.ResultStatusFailed, record.Status)
+	assert.Len(t, record.Plugins, 2)
+	assert.Equal(t, 2, record.Plugins[0].Code)
+	assert.Equal(t, filepath.Join(outputDir, "stdout"), record.Plugins[0].StandardOutput)
+	assert.Empty(t, record.Plugins[0].StandardError)
+	assert.Equal(t, contracts.ResultStatusNotStarted, record.Plugins[1].Status)
+}
+
+func TestPersistExecutionRecord(t *testing.T) {
+	orchestrationDir := t.TempDir()
+	record := NewExecutionRecord(testExecutionDocState(orchestrationDir), contracts.ResultStatusSuccess)
+	record.CompletedDateTime = time.Now().UTC().Truncate(time.Second)
+
+	assert.NoError(t, PersistExecutionRecord(record))
+
+	var persisted ExecutionRecord
+	assert.NoError(t, jsonutil.UnmarshalFile(filepath.Join(orchestrationDir, ExecutionRecordFileName), &persisted))
+	assert.Equal(t, record, persisted)
+}
+
+func TestPersistExecutionRecord_SkipsRemovedDirectory(t *testing.T) {
+	orchestrationDir := filepath.Join(t.TempDir(), "removed")
+	record := NewExecutionRecord(testExecutionDocState(orchestrationDir), contracts.ResultStatusSuccess)
+
+	assert.NoError(t, PersistExecutionRecord(record))
+	_, err := os.Stat(orchestrationDir)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestExecutionRecordDirectories(t *testing.T) {
+	rootDir := t.TempDir()
+	commandDir := filepath.Join(rootDir, "01234567-890a-bcde-f012-34567890abcd")
+	associationRunDir := filepath.Join(rootDir, "b2f3c4d5-890a-bcde-f012-34567890abcd", "2024-05-02T10-15-01.000Z")
+	withoutRecordDir := filepath.Join(rootDir, "11111111-890a-bcde-f012-34567890abcd")
+	for _, dir := range []string{commandDir, associationRunDir, withoutRecordDir} {
+		assert.NoError(t, os.MkdirAll(dir, 0700))
+	}
+	for _, dir := range []string{commandDir, associationRunDir} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, ExecutionRecordFileName), []byte("{}"), 0600))
+	}
+
+	dirs := executionRecordDirectories(log.NewMockLog(), rootDir)
+
+	assert.ElementsMatch(t, []string{commandDir, associationRunDir}, dirs)
+}
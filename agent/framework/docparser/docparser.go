@@ -66,6 +66,9 @@ func InitializeDocState(
 		return
 	}
 	docState.InstancePluginsInformation = pluginInfo
+	if content, ok := docContent.(*DocContent); ok {
+		docState.ResumeOnInterruption = content.ResumeOnInterruption
+	}
 	return docState, nil
 }
 
@@ -90,6 +93,9 @@ func (docContent *DocContent) GetIOConfiguration(parserInfo DocumentParserInfo)
 		OrchestrationDirectory: parserInfo.OrchestrationDir,
 		OutputS3BucketName:     parserInfo.S3Bucket,
 		OutputS3KeyPrefix:      parserInfo.S3Prefix,
+		OutputS3Compression:    docContent.OutputS3Compression,
+		OutputS3KmsKeyId:       strings.TrimSpace(docContent.OutputS3KmsKeyId),
+		OutputStripAnsi:        docContent.OutputStripAnsi,
 		CloudWatchConfig:       parserInfo.CloudWatchConfig,
 	}
 }
@@ -107,7 +113,13 @@ func (docContent *DocContent) ParseDocument(context context.T,
 		return
 	}
 
-	return parseDocumentContent(*docContent, parserInfo, context.Log(), params)
+	if pluginsInfo, err = parseDocumentContent(*docContent, parserInfo, context.Log(), params); err != nil {
+		return
+	}
+	for i := range pluginsInfo {
+		pluginsInfo[i].Configuration.DocumentName = docInfo.DocumentName
+	}
+	return
 }
 
 // GetSchemaVersion is a method used to get document schema version
@@ -416,6 +428,7 @@ func (sessionDocContent *SessionDocContent) parsePluginStateForStartSession(
 		RunAsEnabled:                runAsEnabled,
 		RunAsUser:                   runAsUser,
 		ShellProfile:                sessionDocContent.Inputs.ShellProfile,
+		ShellProfileName:            strings.TrimSpace(sessionDocContent.Inputs.ShellProfileName),
 		SessionOwner:                docInfo.SessionOwner,
 	}
 
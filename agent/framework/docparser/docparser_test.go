@@ -175,6 +175,28 @@ func TestInitializeDocState_Valid(t *testing.T) {
 	assert.Equal(t, testLogStreamPrefix, docState.IOConfig.CloudWatchConfig.LogStreamPrefix)
 }
 
+func TestInitializeDocState_ResumeOnInterruption(t *testing.T) {
+	context := context.NewMockDefault()
+	testParserInfo := DocumentParserInfo{
+		OrchestrationDir: testOrchDir,
+		MessageId:        testMessageID,
+		DocumentId:       testDocumentID,
+	}
+
+	var testDocContent DocContent
+	validdocumentruntimeconfig := loadFile(t, filepath.Join("..", "..", "runcommand", "mds", "testdata", "validcommand12.json"))
+	assert.NoError(t, json.Unmarshal(validdocumentruntimeconfig, &testDocContent))
+
+	docState, err := InitializeDocState(context, contracts.SendCommand, &testDocContent, contracts.DocumentInfo{}, testParserInfo, nil)
+	assert.Nil(t, err)
+	assert.False(t, docState.ResumeOnInterruption)
+
+	testDocContent.ResumeOnInterruption = true
+	docState, err = InitializeDocState(context, contracts.SendCommand, &testDocContent, contracts.DocumentInfo{}, testParserInfo, nil)
+	assert.Nil(t, err)
+	assert.True(t, docState.ResumeOnInterruption)
+}
+
 func TestInitializeDocStateForStartSessionDocument_Valid(t *testing.T) {
 	context := context.NewMockDefault()
 
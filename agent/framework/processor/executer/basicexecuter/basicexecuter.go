@@ -88,6 +88,10 @@ func run(context context.T,
 			}
 			resChan <- docResult
 			contracts.UpdateDocState(&docResult, state)
+			if state.ResumeOnInterruption {
+				// checkpoint the completed step so the document resumes after it if interrupted
+				docStore.Save(*state)
+			}
 		}
 	}(&docState)
 
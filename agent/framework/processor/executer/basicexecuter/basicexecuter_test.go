@@ -24,6 +24,7 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/mocks/log"
 	"github.com/aws/amazon-ssm-agent/agent/task"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 var logger = log.NewMockLog()
@@ -83,6 +84,54 @@ func TestBasicExecuter(t *testing.T) {
 	testBasicExecuter(t, testCase)
 }
 
+// TestBasicExecuterCheckpointsSteps tests that the state is persisted after every step of a document
+// that opted in to resume on interruption.
+func TestBasicExecuterCheckpointsSteps(t *testing.T) {
+	docState := contracts.DocumentState{
+		DocumentInformation: contracts.DocumentInfo{MessageID: "MessageID"},
+		DocumentType:        "SendCommand",
+		InstancePluginsInformation: []contracts.PluginState{
+			{Name: "aws:runShellScript", Id: "step1"},
+			{Name: "aws:runShellScript", Id: "step2"},
+		},
+		ResumeOnInterruption: true,
+	}
+	dataStoreMock := new(executermock.MockDocumentStore)
+	dataStoreMock.On("Load").Return(docState)
+	// the step statuses are copied as the saved state shares its plugin slice with the executer
+	var checkpoints [][]contracts.ResultStatus
+	dataStoreMock.On("Save", mock.Anything).Return().Run(func(args mock.Arguments) {
+		var statuses []contracts.ResultStatus
+		for _, plugin := range args.Get(0).(contracts.DocumentState).InstancePluginsInformation {
+			statuses = append(statuses, plugin.Result.Status)
+		}
+		checkpoints = append(checkpoints, statuses)
+	})
+	pluginRunner = func(context context.T,
+		docState contracts.DocumentState,
+		resChan chan contracts.PluginResult,
+		cancelFlag task.CancelFlag) map[string]*contracts.PluginResult {
+		outputs := make(map[string]*contracts.PluginResult)
+		for _, pluginState := range docState.InstancePluginsInformation {
+			result := contracts.PluginResult{PluginID: pluginState.Id, PluginName: pluginState.Name, Status: contracts.ResultStatusSuccess}
+			resChan <- result
+			outputs[pluginState.Id] = &result
+		}
+		return outputs
+	}
+
+	resChan := NewBasicExecuter(contextmocks.NewMockDefault()).Run(task.NewChanneledCancelFlag(), dataStoreMock)
+	for range resChan {
+	}
+
+	// one checkpoint per step and the final state
+	assert.Equal(t, [][]contracts.ResultStatus{
+		{contracts.ResultStatusSuccess, ""},
+		{contracts.ResultStatusSuccess, contracts.ResultStatusSuccess},
+		{contracts.ResultStatusSuccess, contracts.ResultStatusSuccess},
+	}, checkpoints)
+}
+
 func testBasicExecuter(t *testing.T, testCase TestCase) {
 
 	cancelFlag := task.NewChanneledCancelFlag()
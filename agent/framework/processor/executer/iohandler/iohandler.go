@@ -89,12 +89,14 @@ type IOHandler interface {
 	GetStdoutWriter() multiwriter.DocumentIOMultiWriter
 	GetStderrWriter() multiwriter.DocumentIOMultiWriter
 	GetIOConfig() contracts.IOConfiguration
+	GetProcessAccounting() *contracts.ProcessAccounting
 
 	SetStatus(contracts.ResultStatus)
 	SetExitCode(int)
 	SetOutput(interface{})
 	SetStdout(string)
 	SetStderr(string)
+	AddProcessAccounting(*contracts.ProcessAccounting)
 }
 
 // DefaultIOHandler is used for writing output by the plugins
@@ -108,6 +110,8 @@ type DefaultIOHandler struct {
 	ioConfig contracts.IOConfiguration
 	//refreshassociation and invoker write a different output rather than merging stdout and stderr
 	output interface{}
+	//resource usage of the processes executed by the plugin
+	processAccounting *contracts.ProcessAccounting
 
 	// List of Writers attached to the IOHandler instance
 	StdoutWriter multiwriter.DocumentIOMultiWriter
@@ -150,12 +154,22 @@ func (out *DefaultIOHandler) Init(filePath ...string) {
 		stdErrLogStreamName = fmt.Sprintf("%s/%s", out.ioConfig.CloudWatchConfig.LogStreamPrefix, pluginConfig.StderrFileName)
 	}
 
+	// Document settings for the S3 output take precedence over the agent-wide defaults
+	s3Compression := out.ioConfig.OutputS3Compression || out.context.AppConfig().S3.OutputCompression
+	s3KmsKeyId := out.ioConfig.OutputS3KmsKeyId
+	if s3KmsKeyId == "" {
+		s3KmsKeyId = out.context.AppConfig().S3.OutputKmsKeyId
+	}
+	stripAnsi := out.ioConfig.OutputStripAnsi || out.context.AppConfig().Ssm.PluginOutputStripAnsi
+
 	// Initialize file output module
 	stdoutFile := iomodule.File{
 		FileName:               pluginConfig.StdoutFileName,
 		OrchestrationDirectory: fullPath,
 		OutputS3BucketName:     out.ioConfig.OutputS3BucketName,
 		OutputS3KeyPrefix:      s3KeyPrefix,
+		OutputS3Compression:    s3Compression,
+		OutputS3KmsKeyId:       s3KmsKeyId,
 		LogGroupName:           out.ioConfig.CloudWatchConfig.LogGroupName,
 		LogStreamName:          stdOutLogStreamName,
 	}
@@ -165,6 +179,7 @@ func (out *DefaultIOHandler) Init(filePath ...string) {
 		OutputString:           &out.stdout,
 		FileName:               pluginConfig.StdoutConsoleFileName,
 		OrchestrationDirectory: fullPath,
+		StripAnsi:              stripAnsi,
 	}
 
 	log.Debug("Initializing the Stdout Multi-writer with file and console listeners")
@@ -178,6 +193,8 @@ func (out *DefaultIOHandler) Init(filePath ...string) {
 		OrchestrationDirectory: fullPath,
 		OutputS3BucketName:     out.ioConfig.OutputS3BucketName,
 		OutputS3KeyPrefix:      s3KeyPrefix,
+		OutputS3Compression:    s3Compression,
+		OutputS3KmsKeyId:       s3KmsKeyId,
 		LogGroupName:           out.ioConfig.CloudWatchConfig.LogGroupName,
 		LogStreamName:          stdErrLogStreamName,
 	}
@@ -187,6 +204,7 @@ func (out *DefaultIOHandler) Init(filePath ...string) {
 		OutputString:           &out.stderr,
 		FileName:               pluginConfig.StderrConsoleFileName,
 		OrchestrationDirectory: fullPath,
+		StripAnsi:              stripAnsi,
 	}
 
 	log.Debug("Initializing the Stderr Multi-writer with file and console listeners")
@@ -316,6 +334,22 @@ func (out *DefaultIOHandler) SetOutput(output interface{}) {
 	out.output = output
 }
 
+// GetProcessAccounting returns the resource usage of the processes executed by the plugin, nil if none was recorded
+func (out DefaultIOHandler) GetProcessAccounting() *contracts.ProcessAccounting {
+	return out.processAccounting
+}
+
+// AddProcessAccounting accumulates the resource usage of a process executed by the plugin
+func (out *DefaultIOHandler) AddProcessAccounting(accounting *contracts.ProcessAccounting) {
+	if accounting == nil {
+		return
+	}
+	if out.processAccounting == nil {
+		out.processAccounting = &contracts.ProcessAccounting{}
+	}
+	out.processAccounting.Add(*accounting)
+}
+
 // Merge plugin output objects
 func (out *DefaultIOHandler) Merge(mergeOutput *DefaultIOHandler) {
 
@@ -339,6 +373,7 @@ func (out *DefaultIOHandler) Merge(mergeOutput *DefaultIOHandler) {
 		out.ExitCode = mergeOutput.GetExitCode()
 	}
 	out.Status = contracts.MergeResultStatus(out.Status, mergeOutput.GetStatus())
+	out.AddProcessAccounting(mergeOutput.GetProcessAccounting())
 }
 
 // MarkAsFailed Failed marks plugin as Failed
@@ -125,6 +125,27 @@ func TestFailed(t *testing.T) {
 	assert.False(t, output.Status.IsReboot())
 }
 
+func TestProcessAccounting(t *testing.T) {
+	output := DefaultIOHandler{}
+	assert.Nil(t, output.GetProcessAccounting())
+
+	output.AddProcessAccounting(nil)
+	assert.Nil(t, output.GetProcessAccounting())
+
+	output.AddProcessAccounting(&contracts.ProcessAccounting{UserCPUSeconds: 1.5, MaxRSSBytes: 2048, ReadBytes: 10})
+	propOutput := DefaultIOHandler{}
+	propOutput.AddProcessAccounting(&contracts.ProcessAccounting{UserCPUSeconds: 0.5, SystemCPUSeconds: 1, MaxRSSBytes: 1024, WriteBytes: 20})
+	output.Merge(&propOutput)
+
+	assert.Equal(t, &contracts.ProcessAccounting{
+		UserCPUSeconds:   2,
+		SystemCPUSeconds: 1,
+		MaxRSSBytes:      2048,
+		ReadBytes:        10,
+		WriteBytes:       20,
+	}, output.GetProcessAccounting())
+}
+
 func TestMarkAsInProgress(t *testing.T) {
 	output := DefaultIOHandler{}
 
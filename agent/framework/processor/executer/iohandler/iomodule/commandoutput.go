@@ -29,6 +29,7 @@ type CommandOutput struct {
 	OutputString           *string
 	FileName               string
 	OrchestrationDirectory string
+	StripAnsi              bool
 }
 
 // CleanUp cleans up local files according to PluginLocalOutputCleanup app config
@@ -85,5 +86,6 @@ func (c CommandOutput) Read(context context.T, reader *io.PipeReader, exitCode i
 		if err != nil {
 			log.Errorf("Error reading %v at path %v", c.FileName, filePath)
 		}
+		*c.OutputString = FilterConsoleOutput(*c.OutputString, c.StripAnsi)
 	}
 }
@@ -23,6 +23,7 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
 	"github.com/aws/amazon-ssm-agent/agent/context"
 	"github.com/aws/amazon-ssm-agent/agent/fileutil"
+	"github.com/aws/amazon-ssm-agent/agent/s3util"
 )
 
 const (
@@ -35,6 +36,8 @@ type File struct {
 	OrchestrationDirectory string
 	OutputS3BucketName     string
 	OutputS3KeyPrefix      string
+	OutputS3Compression    bool
+	OutputS3KmsKeyId       string
 	LogGroupName           string
 	LogStreamName          string
 }
@@ -123,7 +126,11 @@ func (file File) Read(context context.T, reader *io.PipeReader, exitCode int) {
 	if file.OutputS3BucketName != "" && fi.Size() > 0 {
 		s3Key := fileutil.BuildS3Path(file.OutputS3KeyPrefix, file.FileName)
 		if s3, err := s3ServiceRetriever.NewAmazonS3Util(context, file.OutputS3BucketName); err == nil {
-			if err := s3.S3Upload(log, file.OutputS3BucketName, s3Key, filePath); err != nil {
+			uploadOptions := s3util.UploadOptions{
+				Compress: file.OutputS3Compression,
+				KmsKeyId: file.OutputS3KmsKeyId,
+			}
+			if err := s3.S3UploadWithOptions(log, file.OutputS3BucketName, s3Key, filePath, uploadOptions); err != nil {
 				log.Errorf("Failed to upload the output to s3: %v", err)
 			} else {
 				uploadComplete = true
@@ -34,7 +34,7 @@ type ICloudWatchLoggingService interface {
 }
 
 type IS3Util interface {
-	S3Upload(logger log.T, outputS3BucketName string, s3Key string, filePath string) error
+	S3UploadWithOptions(logger log.T, outputS3BucketName string, s3Key string, filePath string, options s3util.UploadOptions) error
 }
 
 type cwServiceRetriever struct{}
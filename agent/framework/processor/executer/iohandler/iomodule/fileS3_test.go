@@ -12,6 +12,7 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
 	"github.com/aws/amazon-ssm-agent/agent/fileutil"
 	contextmocks "github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	"github.com/aws/amazon-ssm-agent/agent/s3util"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -83,7 +84,7 @@ func TestFileS3DoesntCleanUpAfterS3Upload(t *testing.T) {
 	var context = contextmocks.NewMockDefaultWithConfig(config)
 	s3Key := fileutil.BuildS3Path(file.OutputS3KeyPrefix, file.FileName)
 	filePath := filepath.Join(file.OrchestrationDirectory, file.FileName)
-	mockS3Util.On("S3Upload", mock.AnythingOfType("*log.Mock"), file.OutputS3BucketName, s3Key, filePath).Return(fmt.Errorf("upload error"))
+	mockS3Util.On("S3UploadWithOptions", mock.AnythingOfType("*log.Mock"), file.OutputS3BucketName, s3Key, filePath, s3util.UploadOptions{}).Return(fmt.Errorf("upload error"))
 
 	var s3RetrieverMock = &s3LogsServiceRetrieverMock{}
 	s3RetrieverMock.On("NewAmazonS3Util", mock.AnythingOfType("*context.Mock"), file.OutputS3BucketName).Return(mockS3Util, nil)
@@ -132,7 +133,7 @@ func TestFileS3DoesntCleanUpAfterRebootExitCode(t *testing.T) {
 	var mockS3Util = &s3UtilMock{}
 	s3Key := fileutil.BuildS3Path(file.OutputS3KeyPrefix, file.FileName)
 	filePath := filepath.Join(file.OrchestrationDirectory, file.FileName)
-	mockS3Util.On("S3Upload", mock.AnythingOfType("*log.Mock"), file.OutputS3BucketName, s3Key, filePath).Return(nil)
+	mockS3Util.On("S3UploadWithOptions", mock.AnythingOfType("*log.Mock"), file.OutputS3BucketName, s3Key, filePath, s3util.UploadOptions{}).Return(nil)
 
 	var s3RetrieverMock = &s3LogsServiceRetrieverMock{}
 	s3RetrieverMock.On("NewAmazonS3Util", mock.AnythingOfType("*context.Mock"), file.OutputS3BucketName).Return(mockS3Util, nil)
@@ -162,6 +163,55 @@ func TestFileS3DoesntCleanUpAfterRebootExitCode(t *testing.T) {
 	assert.True(t, outputFileExists)
 }
 
+func TestFileS3UploadWithOptions(t *testing.T) {
+	file := File{
+		FileName:               "TestFileS3UploadWithOptions",
+		OrchestrationDirectory: "testdata",
+		OutputS3BucketName:     "bucket-to-upload-to",
+		OutputS3KeyPrefix:      "s3KeyPrefix",
+		OutputS3Compression:    true,
+		OutputS3KmsKeyId:       "alias/ssm-output",
+	}
+
+	config := appconfig.SsmagentConfig{}
+	config.Ssm.PluginLocalOutputCleanup = appconfig.PluginLocalOutputCleanupAfterUpload
+	var context = contextmocks.NewMockDefaultWithConfig(config)
+
+	r, w := io.Pipe()
+	wg := new(sync.WaitGroup)
+	var mockS3Util = &s3UtilMock{}
+	s3Key := fileutil.BuildS3Path(file.OutputS3KeyPrefix, file.FileName)
+	filePath := filepath.Join(file.OrchestrationDirectory, file.FileName)
+	uploadOptions := s3util.UploadOptions{Compress: true, KmsKeyId: "alias/ssm-output"}
+	mockS3Util.On("S3UploadWithOptions", mock.AnythingOfType("*log.Mock"), file.OutputS3BucketName, s3Key, filePath, uploadOptions).Return(nil)
+
+	var s3RetrieverMock = &s3LogsServiceRetrieverMock{}
+	s3RetrieverMock.On("NewAmazonS3Util", mock.AnythingOfType("*context.Mock"), file.OutputS3BucketName).Return(mockS3Util, nil)
+	s3ServiceRetriever = s3RetrieverMock
+
+	var cwRetrieverMock = &cloudWatchServiceRetrieverMock{}
+	cwRetrieverMock.On("NewCloudWatchLogsService", mock.AnythingOfType("*context.Mock")).Return(&cloudWatchLoggingServiceMock{})
+	cloudWatchServiceRetriever = cwRetrieverMock
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		file.Read(context, r, 0)
+	}()
+
+	w.Write([]byte("Test input text."))
+	w.Close()
+	wg.Wait()
+	outputFileExists, _ := fileutil.LocalFileExist(filePath)
+	if outputFileExists {
+		os.Remove(filePath)
+	}
+
+	mockS3Util.AssertExpectations(t)
+	assert.False(t, outputFileExists)
+}
+
 func TestFileS3CleanUpAfterCloudWatchUpload(t *testing.T) {
 	file := File{
 		FileName:               "TestFileS3CleanUpAfterS3CloudwatchUpload",
@@ -270,7 +320,7 @@ func testFileS3Read(pluginLocalOutputCleanupPref string, pipeTestCase string, fi
 	var mockS3Util = &s3UtilMock{}
 	s3Key := fileutil.BuildS3Path(file.OutputS3KeyPrefix, file.FileName)
 	filePath := filepath.Join(file.OrchestrationDirectory, file.FileName)
-	mockS3Util.On("S3Upload", mock.AnythingOfType("*log.Mock"), file.OutputS3BucketName, s3Key, filePath).Return(nil)
+	mockS3Util.On("S3UploadWithOptions", mock.AnythingOfType("*log.Mock"), file.OutputS3BucketName, s3Key, filePath, s3util.UploadOptions{}).Return(nil)
 
 	var s3RetrieverMock = &s3LogsServiceRetrieverMock{}
 	s3RetrieverMock.On("NewAmazonS3Util", mock.AnythingOfType("*context.Mock"), file.OutputS3BucketName).Return(mockS3Util, nil)
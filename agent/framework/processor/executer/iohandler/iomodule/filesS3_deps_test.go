@@ -3,6 +3,7 @@ package iomodule
 import (
 	"github.com/aws/amazon-ssm-agent/agent/context"
 	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/s3util"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -56,7 +57,7 @@ type s3UtilMock struct {
 	mock.Mock
 }
 
-func (m *s3UtilMock) S3Upload(log log.T, outputS3BucketName string, s3Key string, filePath string) error {
-	args := m.Called(log, outputS3BucketName, s3Key, filePath)
+func (m *s3UtilMock) S3UploadWithOptions(log log.T, outputS3BucketName string, s3Key string, filePath string, options s3util.UploadOptions) error {
+	args := m.Called(log, outputS3BucketName, s3Key, filePath, options)
 	return args.Error(0)
 }
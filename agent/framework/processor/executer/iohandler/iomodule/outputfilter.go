@@ -0,0 +1,70 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package iomodule
+
+import (
+	"encoding/hex"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// BinaryOutputMarker precedes the hex encoded console output of a plugin that wrote binary data
+	BinaryOutputMarker = "--binary output, hex encoded--\n"
+)
+
+// ansiEscapeSequence matches OSC sequences (window titles, hyperlinks), CSI sequences (colors, cursor movement)
+// and the remaining two character escape sequences
+var ansiEscapeSequence = regexp.MustCompile(`\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)|\x1b\[[0-?]*[ -/]*[@-~]|\x1b[0-Z\\-_]`)
+
+// FilterConsoleOutput prepares the console output of a plugin for the reply payload.
+// Binary output is hex encoded behind BinaryOutputMarker since it can not be carried in the reply as is,
+// otherwise ANSI escape sequences and the lines overwritten by carriage returns are optionally removed.
+func FilterConsoleOutput(output string, stripAnsi bool) string {
+	if isBinary(output) {
+		return BinaryOutputMarker + hex.EncodeToString([]byte(output))
+	}
+	if stripAnsi {
+		return StripAnsi(output)
+	}
+	return output
+}
+
+// StripAnsi removes ANSI escape sequences and keeps only the final state of lines redrawn with carriage returns,
+// which is what progress bars and spinners leave on a terminal
+func StripAnsi(output string) string {
+	output = ansiEscapeSequence.ReplaceAllString(output, "")
+	if !strings.Contains(output, "\r") {
+		return output
+	}
+	lines := strings.Split(output, "\n")
+	for i, line := range lines {
+		crlf := strings.HasSuffix(line, "\r")
+		line = strings.TrimSuffix(line, "\r")
+		if index := strings.LastIndex(line, "\r"); index >= 0 {
+			line = line[index+1:]
+		}
+		if crlf {
+			line += "\r"
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// isBinary reports whether the output is not valid UTF-8 text or contains NUL characters
+func isBinary(output string) bool {
+	return !utf8.ValidString(output) || strings.ContainsRune(output, 0)
+}
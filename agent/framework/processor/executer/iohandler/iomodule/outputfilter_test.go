@@ -0,0 +1,48 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package iomodule
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterConsoleOutput_Text(t *testing.T) {
+	output := "\x1b[32mok\x1b[0m\n"
+	assert.Equal(t, output, FilterConsoleOutput(output, false))
+	assert.Equal(t, "ok\n", FilterConsoleOutput(output, true))
+	assert.Equal(t, "héllo wörld\ttab\r\n", FilterConsoleOutput("héllo wörld\ttab\r\n", true))
+}
+
+func TestFilterConsoleOutput_Binary(t *testing.T) {
+	assert.Equal(t, BinaryOutputMarker+"ff00d8", FilterConsoleOutput("\xff\x00\xd8", false))
+	assert.Equal(t, BinaryOutputMarker+"610062", FilterConsoleOutput("a\x00b", true), "NUL characters are binary")
+}
+
+func TestStripAnsi(t *testing.T) {
+	testCases := map[string]string{
+		"\x1b[1;31merror\x1b[0m: failed":           "error: failed",
+		"\x1b]0;window title\x07prompt":            "prompt",
+		"\x1b]8;;https://aws.amazon.com\x1b\\link": "link",
+		"\x1b[2K\x1b[1Gdone":                       "done",
+		"\x1b=keypad\x1b>":                         "keypad",
+		"  10%\r  50%\r 100%\ncomplete\n":          " 100%\ncomplete\n",
+		"line one\r\nline two\r\n":                 "line one\r\nline two\r\n",
+		"[###   ]\r[######]\r\nnext":               "[######]\r\nnext",
+	}
+	for input, expected := range testCases {
+		assert.Equal(t, expected, StripAnsi(input), "%q", input)
+	}
+}
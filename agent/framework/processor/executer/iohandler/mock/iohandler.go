@@ -139,6 +139,17 @@ func (m *MockIOHandler) GetIOConfig() contracts.IOConfiguration {
 	return args.Get(0).(contracts.IOConfiguration)
 }
 
+// GetProcessAccounting is a mocked method that just returns what mock tells it to.
+func (m *MockIOHandler) GetProcessAccounting() *contracts.ProcessAccounting {
+	args := m.Called()
+	return args.Get(0).(*contracts.ProcessAccounting)
+}
+
+// AddProcessAccounting is a mocked method that acknowledges that the function has been called.
+func (m *MockIOHandler) AddProcessAccounting(accounting *contracts.ProcessAccounting) {
+	m.Called(accounting)
+}
+
 // SetStatus is a mocked method that acknowledges that the function has been called.
 func (m *MockIOHandler) SetStatus(status contracts.ResultStatus) {
 	m.Called(status)
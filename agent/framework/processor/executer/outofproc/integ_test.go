@@ -27,6 +27,8 @@ import (
 	executermocks "github.com/aws/amazon-ssm-agent/agent/framework/processor/executer/mock"
 	"github.com/aws/amazon-ssm-agent/agent/framework/processor/executer/outofproc/messaging"
 	"github.com/aws/amazon-ssm-agent/agent/framework/processor/executer/outofproc/proc"
+	"github.com/aws/amazon-ssm-agent/agent/framework/workerlimits"
+	"github.com/aws/amazon-ssm-agent/agent/framework/workersandbox"
 	"github.com/aws/amazon-ssm-agent/agent/log"
 	contextmocks "github.com/aws/amazon-ssm-agent/agent/mocks/context"
 	"github.com/aws/amazon-ssm-agent/agent/task"
@@ -59,7 +61,7 @@ func setup(t *testing.T) *TestCase {
 		t.Fatalf("process already exists: %v", fakeProcess)
 	}
 	fakeProcess = NewFakeProcess(t)
-	processCreator = func(name string, argv []string) (proc.OSProcess, error) {
+	processCreator = func(log log.T, name string, argv []string, limits workerlimits.Limits, sandbox workersandbox.Sandbox) (proc.OSProcess, error) {
 		//fakeProcess is imposed as singleton here
 		if fakeProcess.live {
 			t.Fatalf("start process repeatedly, already exists: %v", fakeProcess)
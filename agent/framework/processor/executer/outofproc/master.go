@@ -12,6 +12,8 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/framework/processor/executer/basicexecuter"
 	"github.com/aws/amazon-ssm-agent/agent/framework/processor/executer/outofproc/messaging"
 	"github.com/aws/amazon-ssm-agent/agent/framework/processor/executer/outofproc/proc"
+	"github.com/aws/amazon-ssm-agent/agent/framework/workerlimits"
+	"github.com/aws/amazon-ssm-agent/agent/framework/workersandbox"
 	"github.com/aws/amazon-ssm-agent/agent/log"
 	logpkg "github.com/aws/amazon-ssm-agent/agent/log/logger"
 	"github.com/aws/amazon-ssm-agent/agent/task"
@@ -40,6 +42,8 @@ type OutOfProcExecuter struct {
 	ctx        context.T
 	cancelFlag task.CancelFlag
 	executor   executor.IExecutor
+	// resource limits applied to the worker process started by this executer
+	workerLimits workerlimits.Limits
 }
 
 var channelCreator = func(log log.T, identity identity.IAgentIdentity, mode filewatcherbasedipc.Mode, documentID string) (filewatcherbasedipc.IPCChannel, error, bool) {
@@ -63,8 +67,14 @@ var processFinder = func(log log.T, procinfo contracts.OSProcInfo, executor exec
 	return isRunning
 }
 
-var processCreator = func(name string, argv []string) (proc.OSProcess, error) {
-	return proc.StartProcess(name, argv)
+// IsWorkerRunning reports whether the worker process recorded for a document is still running,
+// the executer reattaches to such a worker instead of starting a new one when the document is resumed
+func IsWorkerRunning(log log.T, procInfo contracts.OSProcInfo) bool {
+	return processFinder(log, procInfo, executor.NewProcessExecutor(log))
+}
+
+var processCreator = func(log log.T, name string, argv []string, limits workerlimits.Limits, sandbox workersandbox.Sandbox) (proc.OSProcess, error) {
+	return proc.StartWorkerProcess(log, name, argv, argv[0], limits, sandbox)
 }
 
 func NewOutOfProcExecuter(ctx context.T) *OutOfProcExecuter {
@@ -146,6 +156,9 @@ func (e *OutOfProcExecuter) messaging(log log.T, ipc filewatcherbasedipc.IPCChan
 			e.docState.DocumentInformation.DocumentStatus = contracts.ResultStatusFailed
 			log.Info("document failed half way, sending fail message...")
 			errMsg := fmt.Sprintf("document process failed unexpectedly: %s , check [ssm-document-worker]/[ssm-session-worker] log for crash reason", err)
+			if e.workerLimits.IsSet() {
+				errMsg += fmt.Sprintf(". The worker was running with resource limits %v and may have been stopped after reaching them", e.workerLimits)
+			}
 			resChan <- e.generateUnexpectedFailResult(errMsg, backupStartTime)
 		}
 		//destroy the channel
@@ -217,7 +230,13 @@ func (e *OutOfProcExecuter) initialize(stopTimer chan bool) (ipc filewatcherbase
 			workerName = appconfig.DefaultDocumentWorker
 		}
 		var process proc.OSProcess
-		if process, err = processCreator(workerName, []string{documentID}); err != nil {
+		e.workerLimits = workerlimits.ForWorker(e.ctx.AppConfig().WorkerLimits, workerName)
+		pluginNames := make([]string, 0, len(e.docState.InstancePluginsInformation))
+		for _, pluginState := range e.docState.InstancePluginsInformation {
+			pluginNames = append(pluginNames, pluginState.Name)
+		}
+		sandbox := workersandbox.ForWorker(e.ctx.AppConfig().WorkerSandbox, workerName, pluginNames)
+		if process, err = processCreator(log, workerName, []string{documentID}, e.workerLimits, sandbox); err != nil {
 			log.Errorf("start process: %v error: %v", workerName, err)
 			//make sure close the channel
 			ipc.Destroy()
@@ -10,6 +10,8 @@ import (
 	executermocks "github.com/aws/amazon-ssm-agent/agent/framework/processor/executer/mock"
 	"github.com/aws/amazon-ssm-agent/agent/framework/processor/executer/outofproc/proc"
 	procmock "github.com/aws/amazon-ssm-agent/agent/framework/processor/executer/outofproc/proc/mock"
+	"github.com/aws/amazon-ssm-agent/agent/framework/workerlimits"
+	"github.com/aws/amazon-ssm-agent/agent/framework/workersandbox"
 	"github.com/aws/amazon-ssm-agent/agent/log"
 	"github.com/aws/amazon-ssm-agent/agent/mocks/context"
 	logmocks "github.com/aws/amazon-ssm-agent/agent/mocks/log"
@@ -108,7 +110,7 @@ func TestInitializeNewProcess(t *testing.T) {
 		assert.Equal(t, testDocumentID, documentID)
 		return channelMock, nil, false
 	}
-	processCreator = func(name string, argv []string) (proc.OSProcess, error) {
+	processCreator = func(log log.T, name string, argv []string, limits workerlimits.Limits, sandbox workersandbox.Sandbox) (proc.OSProcess, error) {
 		assert.Equal(t, name, appconfig.DefaultDocumentWorker)
 		assert.Equal(t, argv, []string{testDocumentID})
 		return testCase.processMock, nil
@@ -142,7 +144,7 @@ func TestInitializeNewProcessForSession(t *testing.T) {
 		assert.Equal(t, testDocumentID, documentID)
 		return channelMock, nil, false
 	}
-	processCreator = func(name string, argv []string) (proc.OSProcess, error) {
+	processCreator = func(log log.T, name string, argv []string, limits workerlimits.Limits, sandbox workersandbox.Sandbox) (proc.OSProcess, error) {
 		assert.Equal(t, name, appconfig.DefaultSessionWorker)
 		assert.Equal(t, argv, []string{testDocumentID})
 		return testCase.processMock, nil
@@ -213,7 +215,7 @@ func TestCreateProcessFailed(t *testing.T) {
 		return channelMock, nil, false
 	}
 	var err = errors.New("failed to create process")
-	processCreator = func(name string, argv []string) (proc.OSProcess, error) {
+	processCreator = func(log log.T, name string, argv []string, limits workerlimits.Limits, sandbox workersandbox.Sandbox) (proc.OSProcess, error) {
 		assert.Equal(t, name, appconfig.DefaultDocumentWorker)
 		assert.Equal(t, argv, []string{testDocumentID})
 		return nil, err
@@ -238,7 +240,7 @@ func TestInitializeProcessUnexpectedExited(t *testing.T) {
 		assert.Equal(t, testDocumentID, documentID)
 		return channelMock, nil, false
 	}
-	processCreator = func(name string, argv []string) (proc.OSProcess, error) {
+	processCreator = func(log log.T, name string, argv []string, limits workerlimits.Limits, sandbox workersandbox.Sandbox) (proc.OSProcess, error) {
 		assert.Equal(t, name, appconfig.DefaultDocumentWorker)
 		assert.Equal(t, argv, []string{testDocumentID})
 		return testCase.processMock, nil
@@ -301,7 +303,7 @@ func TestInitializeConnectOldOrphan(t *testing.T) {
 	}
 	//make sure not create new process
 	isCreateCalled := false
-	processCreator = func(name string, argv []string) (proc.OSProcess, error) {
+	processCreator = func(log log.T, name string, argv []string, limits workerlimits.Limits, sandbox workersandbox.Sandbox) (proc.OSProcess, error) {
 		isCreateCalled = true
 		return testCase.processMock, nil
 	}
@@ -258,6 +258,38 @@ func TestWorkerBackend_ProcessCancelV1(t *testing.T) {
 
 }
 
+// TestWorkerBackend_ProcessPluginConfigAfterReattach covers a master that restarted and reattached to a running worker,
+// it sends the plugin config again and the worker must not start the plugins a second time
+func TestWorkerBackend_ProcessPluginConfigAfterReattach(t *testing.T) {
+	_ = CreateTestCase()
+	inputChan := make(chan string, 10)
+	runnerCalls := 0
+	release := make(chan bool)
+	pluginRunner := func(
+		context context.T,
+		docState contracts.DocumentState,
+		resChan chan contracts.PluginResult,
+		cancelFlag task.CancelFlag,
+	) {
+		runnerCalls++
+		<-release
+		close(resChan)
+	}
+	stopChan := make(chan int, 1)
+	backend := WorkerBackend{
+		ctx:        contextMock,
+		input:      inputChan,
+		cancelFlag: task.NewChanneledCancelFlag(),
+		runner:     pluginRunner,
+		stopChan:   stopChan,
+	}
+	assert.NoError(t, backend.Process(testPluginsRawJSON))
+	assert.NoError(t, backend.Process(testPluginsRawJSON))
+	close(release)
+	assert.Equal(t, stopTypeShutdown, <-stopChan)
+	assert.Equal(t, 1, runnerCalls)
+}
+
 func TestWorkerBackendPluginListener(t *testing.T) {
 	testCase := CreateTestCase()
 	statusChan := make(chan contracts.PluginResult)
@@ -17,10 +17,13 @@ package proc
 import (
 	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 	"time"
 
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/framework/workerlimits"
+	"github.com/aws/amazon-ssm-agent/agent/framework/workersandbox"
 	"github.com/aws/amazon-ssm-agent/agent/log"
 	"github.com/aws/amazon-ssm-agent/common/identity"
 	identity2 "github.com/aws/amazon-ssm-agent/common/identity/identity"
@@ -52,7 +55,8 @@ type OSProcess interface {
 // impl of OSProcess with os.Process embed
 type WorkerProcess struct {
 	*exec.Cmd
-	startTime time.Time
+	startTime  time.Time
+	limitGroup workerlimits.Group
 }
 
 func (p *WorkerProcess) Pid() int {
@@ -69,23 +73,86 @@ func (p *WorkerProcess) Kill() error {
 }
 
 func (p *WorkerProcess) Wait() error {
-	return p.Cmd.Wait()
+	err := p.Cmd.Wait()
+	if p.limitGroup != nil {
+		p.limitGroup.Close()
+	}
+	return err
 }
 
 // start a child process, with the resources attached to its parent
 func StartProcess(name string, argv []string) (OSProcess, error) {
+	return startProcess(name, argv, nil)
+}
+
+func startProcess(name string, argv []string, env []string) (OSProcess, error) {
 	//TODO connect stdin and stdout to avoid seelog error
 	cmd := exec.Command(name, argv...)
 	prepareProcess(cmd)
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
 	err := cmd.Start()
 	p := WorkerProcess{
-		cmd,
-		time.Now().UTC(),
+		Cmd:       cmd,
+		startTime: time.Now().UTC(),
 	}
 
 	return &p, err
 }
 
+// StartProcessWithLimits starts a child process inside a group enforcing the resource limits.
+// If the limits cannot be applied, the process is started without them.
+func StartProcessWithLimits(log log.T, name string, argv []string, groupName string, limits workerlimits.Limits) (OSProcess, error) {
+	return startProcessWithLimits(log, name, argv, groupName, limits, nil)
+}
+
+// StartWorkerProcess starts a worker process inside the sandbox and a group enforcing the resource limits.
+// If the sandbox cannot be set up, the worker is started without it.
+func StartWorkerProcess(log log.T, name string, argv []string, groupName string, limits workerlimits.Limits, sandbox workersandbox.Sandbox) (OSProcess, error) {
+	if sandbox.IsSet() {
+		if sandboxName, sandboxArgv, err := workersandbox.Wrap(log, sandbox, name, argv); err != nil {
+			log.Warnf("failed to set up worker sandbox %v, starting %v without it: %v", sandbox, name, err)
+		} else {
+			log.Infof("starting %v in sandbox %v", name, sandbox)
+			return startProcessWithLimits(log, sandboxName, sandboxArgv, groupName, limits, []string{sandbox.Environment()})
+		}
+	}
+	return StartProcessWithLimits(log, name, argv, groupName, limits)
+}
+
+func startProcessWithLimits(log log.T, name string, argv []string, groupName string, limits workerlimits.Limits, env []string) (OSProcess, error) {
+	if !limits.IsSet() {
+		return startProcess(name, argv, env)
+	}
+
+	cmd := exec.Command(name, argv...)
+	prepareProcess(cmd)
+	group, err := workerlimits.Attach(cmd, groupName, limits)
+	if err != nil {
+		log.Warnf("failed to apply worker resource limits %v, starting %v without them: %v", limits, name, err)
+		return startProcess(name, argv, env)
+	}
+	cmd.Env = append(append(os.Environ(), env...), limits.Environment())
+	if err = cmd.Start(); err != nil {
+		group.Close()
+		log.Warnf("failed to start %v with resource limits %v, starting it without them: %v", name, limits, err)
+		return startProcess(name, argv, env)
+	}
+	if err = group.AfterStart(cmd.Process.Pid); err != nil {
+		log.Warnf("failed to apply worker resource limits %v to process %v: %v", limits, cmd.Process.Pid, err)
+	} else {
+		log.Infof("started %v with resource limits %v", name, limits)
+	}
+
+	p := WorkerProcess{
+		Cmd:        cmd,
+		startTime:  time.Now().UTC(),
+		limitGroup: group,
+	}
+	return &p, nil
+}
+
 // TODO figure out why sometimes argv does not contain program name
 func parseArgv(argv []string) (channelName string, err error) {
 	if len(argv) == 1 {
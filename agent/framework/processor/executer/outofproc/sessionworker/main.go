@@ -23,10 +23,12 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
 	"github.com/aws/amazon-ssm-agent/agent/context"
 	"github.com/aws/amazon-ssm-agent/agent/contracts"
+	"github.com/aws/amazon-ssm-agent/agent/crashdump"
 	"github.com/aws/amazon-ssm-agent/agent/framework/processor/executer/outofproc/messaging"
 	"github.com/aws/amazon-ssm-agent/agent/framework/processor/executer/outofproc/proc"
 	"github.com/aws/amazon-ssm-agent/agent/framework/processor/executer/plugin"
 	"github.com/aws/amazon-ssm-agent/agent/framework/runpluginutil"
+	"github.com/aws/amazon-ssm-agent/agent/framework/workersandbox"
 	"github.com/aws/amazon-ssm-agent/agent/log/ssmlog"
 	"github.com/aws/amazon-ssm-agent/agent/task"
 	"github.com/aws/amazon-ssm-agent/agent/version"
@@ -60,15 +62,19 @@ func main() {
 	logger := ssmlog.SSMLogger(false)
 	defer func() {
 		if err := recover(); err != nil {
+			stack := debug.Stack()
 			logger.Errorf("session worker panic: %v", err)
-			logger.Errorf("Stacktrace:\n%s", debug.Stack())
+			logger.Errorf("Stacktrace:\n%s", stack)
+			crashdump.RecordPanic(logger, appconfig.SSMSessionWorkerName, err, stack)
 		}
 
 		logger.Flush()
 		logger.Close()
 	}()
 
+	crashdump.CaptureStderr(logger, appconfig.SSMSessionWorkerName)
 	logger.Infof("ssm-session-worker - %v", version.String())
+	workersandbox.ApplyFromEnvironment(logger)
 	cfg, agentIdentity, channelName, err := proc.InitializeWorkerDependencies(logger, os.Args)
 	if err != nil {
 		logger.Errorf("session worker failed to initialize with error %v", err)
@@ -22,13 +22,17 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
 	"github.com/aws/amazon-ssm-agent/agent/context"
 	"github.com/aws/amazon-ssm-agent/agent/framework/runpluginutil"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/cloudwatchagent"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/configurecontainers"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/configurepackage"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/copyfile"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/dockercontainer"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/downloadcontent"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/lrpminvoker"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/manageservice"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/refreshassociation"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/rendertemplate"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/rundocument"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/runscript"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/updatessmagent"
@@ -58,6 +62,9 @@ var allPlugins = map[string]struct{}{
 	appconfig.PluginEC2ConfigUpdate:            {},
 	appconfig.PluginNameRefreshAssociation:     {},
 	appconfig.PluginDownloadContent:            {},
+	appconfig.PluginCopyFile:                   {},
+	appconfig.PluginManageService:              {},
+	appconfig.PluginRenderTemplate:             {},
 	appconfig.PluginRunDocument:                {},
 }
 
@@ -70,7 +77,15 @@ type CloudWatchFactory struct {
 }
 
 func (f CloudWatchFactory) Create(context context.T) (runpluginutil.T, error) {
-	return lrpminvoker.NewPlugin(context, appconfig.PluginNameCloudWatch)
+	legacy, err := lrpminvoker.NewPlugin(context, appconfig.PluginNameCloudWatch)
+	if err != nil {
+		return nil, err
+	}
+	packageInstaller, err := configurepackage.NewPlugin(context)
+	if err != nil {
+		return nil, err
+	}
+	return cloudwatchagent.NewPlugin(context, legacy, packageInstaller)
 }
 
 type InventoryGathererFactory struct {
@@ -129,6 +144,27 @@ func (d DownloadContentFactory) Create(context context.T) (runpluginutil.T, erro
 	return downloadcontent.NewPlugin(context)
 }
 
+type CopyFileFactory struct {
+}
+
+func (c CopyFileFactory) Create(context context.T) (runpluginutil.T, error) {
+	return copyfile.NewPlugin(context)
+}
+
+type ManageServiceFactory struct {
+}
+
+func (m ManageServiceFactory) Create(context context.T) (runpluginutil.T, error) {
+	return manageservice.NewPlugin(context)
+}
+
+type RenderTemplateFactory struct {
+}
+
+func (r RenderTemplateFactory) Create(context context.T) (runpluginutil.T, error) {
+	return rendertemplate.NewPlugin(context)
+}
+
 type RunDocumentFactory struct {
 }
 
@@ -244,6 +280,18 @@ func loadPlatformIndependentPlugins(context context.T) runpluginutil.PluginRegis
 	downloadContentPluginName := downloadcontent.Name()
 	workerPlugins[downloadContentPluginName] = DownloadContentFactory{}
 
+	//registering aws:copyFile
+	copyFilePluginName := copyfile.Name()
+	workerPlugins[copyFilePluginName] = CopyFileFactory{}
+
+	//registering aws:manageService
+	manageServicePluginName := manageservice.Name()
+	workerPlugins[manageServicePluginName] = ManageServiceFactory{}
+
+	//registering aws:renderTemplate
+	renderTemplatePluginName := rendertemplate.Name()
+	workerPlugins[renderTemplatePluginName] = RenderTemplateFactory{}
+
 	//registering aws:runDocument
 	runDocumentPluginName := rundocument.Name()
 	workerPlugins[runDocumentPluginName] = RunDocumentFactory{}
@@ -1,6 +1,8 @@
 package processormock
 
 import (
+	"time"
+
 	"github.com/aws/amazon-ssm-agent/agent/contracts"
 	"github.com/aws/amazon-ssm-agent/agent/framework/processor"
 	"github.com/stretchr/testify/mock"
@@ -25,6 +27,11 @@ func (m *MockedProcessor) Stop() {
 	return
 }
 
+func (m *MockedProcessor) Drain(timeout time.Duration) bool {
+	args := m.Called(timeout)
+	return args.Bool(0)
+}
+
 func (m *MockedProcessor) Submit(docState contracts.DocumentState) processor.ErrorCode {
 	args := m.Called(docState)
 	return args.Get(0).(processor.ErrorCode)
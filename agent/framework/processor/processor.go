@@ -23,6 +23,7 @@ import (
 	"time"
 
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/auditlog"
 	"github.com/aws/amazon-ssm-agent/agent/context"
 	"github.com/aws/amazon-ssm-agent/agent/contracts"
 	"github.com/aws/amazon-ssm-agent/agent/fileutil"
@@ -31,13 +32,19 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/framework/processor/executer/outofproc"
 	"github.com/aws/amazon-ssm-agent/agent/log"
 	"github.com/aws/amazon-ssm-agent/agent/longrunning/manager"
+	"github.com/aws/amazon-ssm-agent/agent/metrics"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/cloudwatchagent"
 	"github.com/aws/amazon-ssm-agent/agent/rebooter"
 	"github.com/aws/amazon-ssm-agent/agent/task"
 	"github.com/aws/amazon-ssm-agent/agent/times"
+	"github.com/aws/amazon-ssm-agent/agent/tracing"
 )
 
 type ExecuterCreator func(ctx context.T) executer.Executer
 
+// isWorkerRunning reports whether the worker process of an in-progress document survived the agent restart
+var isWorkerRunning = outofproc.IsWorkerRunning
+
 // ErrorCode represents processor related error codes
 type ErrorCode string
 
@@ -48,6 +55,9 @@ const (
 
 	maxDocumentTimeOutHour = time.Hour * 48
 
+	// drainPollInterval is how often the documents in progress are checked while draining
+	drainPollInterval = time.Second
+
 	// CommandBufferFull denotes that the cancel command buffer is full
 	CommandBufferFull ErrorCode = "CommandBufferFull"
 
@@ -77,6 +87,8 @@ type Processor interface {
 	InitialProcessing(skipDocumentIfExpired bool) error
 	//Stop the processor, save the current state to resume later
 	Stop()
+	//Drain waits for the submitted documents to complete without interrupting them, returns false if some are still in progress at the timeout
+	Drain(timeout time.Duration) bool
 	//Submit to the pool a document in form of docState object, results will be streamed back from the central channel returned by Start()
 	Submit(docState contracts.DocumentState) ErrorCode
 	//Cancel cancels processing of the given document
@@ -289,8 +301,12 @@ func (p *EngineProcessor) submit(docState *contracts.DocumentState, isInProgress
 	// when buffer limit is zero, we return success("") always which means the pool submit will be blocking if it is full already
 	errorCode = p.checkProcessorSubmissionAllowed(docState)
 	if errorCode != "" {
+		auditlog.Record(log, p.context.AppConfig().AuditLog, auditlog.NewDocumentEntry(auditlog.ActionRejected, docState, string(errorCode)))
 		return errorCode
 	}
+	if !isInProgressDocument {
+		auditlog.Record(log, p.context.AppConfig().AuditLog, auditlog.NewDocumentEntry(auditlog.ActionReceived, docState, ""))
+	}
 	log.Infof("document %v submission started", jobID)
 	defer log.Infof("document %v submission ended", jobID)
 	defer func() {
@@ -370,6 +386,9 @@ func (p *EngineProcessor) cancel(docState *contracts.DocumentState, isInProgress
 	if errorCode != "" {
 		return errorCode
 	}
+	if !isInProgressDocument {
+		auditlog.Record(log, p.context.AppConfig().AuditLog, auditlog.NewDocumentEntry(auditlog.ActionCancelReceived, docState, ""))
+	}
 
 	defer func() {
 		if r := recover(); r != nil {
@@ -416,6 +435,26 @@ func (p *EngineProcessor) hasProcessorStopCalledAlready() bool {
 	return false
 }
 
+// Drain waits until the submitted documents complete or the timeout elapses, whichever comes first.
+// Documents are not interrupted, the caller is expected to stop accepting new documents beforehand.
+func (p *EngineProcessor) Drain(timeout time.Duration) bool {
+	log := p.context.Log()
+	deadline := time.After(timeout)
+	for {
+		inProgress := p.sendCommandPool.JobCount()
+		if inProgress == 0 {
+			log.Info("processor drained")
+			return true
+		}
+		select {
+		case <-deadline:
+			log.Warnf("%v documents still in progress after draining for %v", inProgress, timeout)
+			return false
+		case <-time.After(drainPollInterval):
+		}
+	}
+}
+
 // Stop set the cancel flags of all the running jobs, which are to be captured by the command worker and shutdown gracefully
 func (p *EngineProcessor) Stop() {
 	if p.hasProcessorStopCalledAlready() {
@@ -512,18 +551,17 @@ func (p *EngineProcessor) processInProgressDocuments(skipDocumentIfExpired bool)
 		docState := p.documentMgr.GetDocumentState(f.Name(), appconfig.DefaultLocationOfCurrent)
 
 		if p.isSupportedDocumentType(docState.DocumentType) {
-			retryLimit := config.Mds.CommandRetryLimit
-			if docState.DocumentInformation.RunCount >= retryLimit {
+			if !countInProgressRun(log, &docState, config.Mds.CommandRetryLimit) {
 				p.documentMgr.MoveDocumentState(f.Name(), appconfig.DefaultLocationOfCurrent, appconfig.DefaultLocationOfCorrupt)
 				continue
 			}
 
-			// increment the command run count
-			docState.DocumentInformation.RunCount++
-
 			p.documentMgr.PersistDocumentState(docState.DocumentInformation.DocumentID, appconfig.DefaultLocationOfCurrent, docState)
 
 			log.Infof("Processing in-progress document %v", docState.DocumentInformation.DocumentID)
+			if docState.ResumeOnInterruption {
+				log.Infof("Document %v resumes at its first incomplete step", docState.DocumentInformation.DocumentID)
+			}
 			if skipDocumentIfExpired && docState.DocumentInformation.CreatedDate != "" {
 				createDate := times.ParseIso8601UTC(docState.DocumentInformation.CreatedDate)
 
@@ -541,6 +579,23 @@ func (p *EngineProcessor) processInProgressDocuments(skipDocumentIfExpired bool)
 	}
 }
 
+// countInProgressRun increments the run count of an in-progress document and returns false when the retry limit is reached.
+// A document whose worker process outlived the agent restart, like a live session, is reattached to instead of run again
+// and the restart does not count towards the limit.
+func countInProgressRun(log log.T, docState *contracts.DocumentState, retryLimit int) bool {
+	procInfo := docState.DocumentInformation.ProcInfo
+	if isWorkerRunning(log, procInfo) {
+		log.Infof("Worker process %v of document %v is still running, reattaching without counting a retry",
+			procInfo.Pid, docState.DocumentInformation.DocumentID)
+		return true
+	}
+	if docState.DocumentInformation.RunCount >= retryLimit {
+		return false
+	}
+	docState.DocumentInformation.RunCount++
+	return true
+}
+
 // pushPersistedDocToJobPool pushes in-progress and pending documents to job pool during restart
 func (p *EngineProcessor) pushPersistedDocToJobPool(docState contracts.DocumentState, docStateDir string, isInProgress bool) {
 	logger := p.context.Log()
@@ -584,6 +639,7 @@ func (p *EngineProcessor) isSupportedDocumentType(documentType contracts.Documen
 
 func processCommand(context context.T, executerCreator ExecuterCreator, cancelFlag task.CancelFlag, resChan chan contracts.DocumentResult, docState *contracts.DocumentState, docMgr docmanager.DocumentMgr) {
 	log := context.Log()
+	executeStart := time.Now()
 	//persist the current running document
 	docMgr.MoveDocumentState(
 		docState.DocumentInformation.DocumentID,
@@ -611,6 +667,8 @@ func processCommand(context context.T, executerCreator ExecuterCreator, cancelFl
 
 			if res.LastPlugin == "" {
 				log.Infof("sending document: %v complete response", documentID)
+				// recorded before the reply is handed off as the reply ends the trace
+				tracing.RecordExecution(messageID, executeStart, &res)
 			} else {
 				log.Debugf("sending reply for plugin update: %v", res.LastPlugin)
 			}
@@ -640,6 +698,11 @@ func processCommand(context context.T, executerCreator ExecuterCreator, cancelFl
 		return
 	}
 
+	recordDocumentResult(final)
+	docResultState := docStore.Load()
+	auditlog.Record(log, context.AppConfig().AuditLog, auditlog.NewDocumentEntry(auditlog.ActionCompleted, &docResultState, string(final.Status)))
+	persistExecutionRecord(log, docResultState, final)
+
 	//persist : commands execution in completed folder (terminal state folder)
 	log.Infof("execution of %v is over. Removing interimState from current folder", messageID)
 
@@ -649,6 +712,42 @@ func processCommand(context context.T, executerCreator ExecuterCreator, cancelFl
 
 }
 
+// recordDocumentResult updates the command execution, plugin failure and plugin resource usage metrics with the final document result
+func recordDocumentResult(result *contracts.DocumentResult) {
+	metrics.CommandExecutions.Inc(string(result.Status))
+	for _, pluginResult := range result.PluginResults {
+		if pluginResult == nil {
+			continue
+		}
+		if pluginResult.Status == contracts.ResultStatusFailed {
+			metrics.PluginFailures.Inc(pluginResult.PluginName)
+		}
+		if accounting := pluginResult.ProcessAccounting; accounting != nil {
+			metrics.PluginCPUSeconds.Add(pluginResult.PluginName, accounting.UserCPUSeconds+accounting.SystemCPUSeconds)
+			metrics.PluginIOBytes.Add(pluginResult.PluginName, float64(accounting.ReadBytes+accounting.WriteBytes))
+		}
+	}
+}
+
+// persistExecutionRecord records the final result of the document in its orchestration directory,
+// so ssm-cli can present it after the document state is removed
+func persistExecutionRecord(log log.T, docState contracts.DocumentState, result *contracts.DocumentResult) {
+	plugins := make([]contracts.PluginState, len(docState.InstancePluginsInformation))
+	copy(plugins, docState.InstancePluginsInformation)
+	for i, plugin := range plugins {
+		if pluginResult, ok := result.PluginResults[plugin.Id]; ok && pluginResult != nil {
+			plugins[i].Result = *pluginResult
+		}
+	}
+	docState.InstancePluginsInformation = plugins
+
+	record := docmanager.NewExecutionRecord(docState, result.Status)
+	record.CompletedDateTime = time.Now()
+	if err := docmanager.PersistExecutionRecord(record); err != nil {
+		log.Warnf("failed to persist execution record of document %v: %v", docState.DocumentInformation.DocumentID, err)
+	}
+}
+
 // TODO CancelCommand is currently treated as a special type of Command by the Processor, but in general Cancel operation should be seen as a probe to existing commands
 func processCancelCommand(context context.T, sendCommandPool task.Pool, docState *contracts.DocumentState, docMgr docmanager.DocumentMgr) {
 
@@ -684,7 +783,8 @@ func handleCloudwatchPlugin(context context.T, pluginResults map[string]*contrac
 	instanceID, _ := context.Identity().InstanceID()
 	//TODO once association service switches to use RC and CW goes away, remove this block
 	for ID, pluginRes := range pluginResults {
-		if pluginRes.PluginName == appconfig.PluginNameCloudWatch {
+		// the unified agent is managed by the plugin itself
+		if pluginRes.PluginName == appconfig.PluginNameCloudWatch && !cloudwatchagent.IsAgentResult(pluginRes) {
 			log.Infof("Found %v to invoke lrpm invoker", pluginRes.PluginName)
 			orchestrationRootDir := filepath.Join(
 				appconfig.DefaultDataStorePath,
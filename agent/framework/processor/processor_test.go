@@ -17,12 +17,15 @@ package processor
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
 	"github.com/aws/amazon-ssm-agent/agent/context"
 	"github.com/aws/amazon-ssm-agent/agent/contracts"
 	"github.com/aws/amazon-ssm-agent/agent/framework/processor/executer"
 	executermocks "github.com/aws/amazon-ssm-agent/agent/framework/processor/executer/mock"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/metrics"
 	contextmocks "github.com/aws/amazon-ssm-agent/agent/mocks/context"
 	taskmocks "github.com/aws/amazon-ssm-agent/agent/mocks/task"
 	"github.com/aws/amazon-ssm-agent/agent/task"
@@ -108,6 +111,28 @@ func TestEngineProcessor_Stop(t *testing.T) {
 	cancelCommandPoolMock.AssertNotCalled(t, "ShutdownAndWait", mock.AnythingOfType("time.Duration"))
 }
 
+func TestEngineProcessor_Drain(t *testing.T) {
+	sendCommandPoolMock := new(taskmocks.MockedPool)
+	processor := EngineProcessor{
+		sendCommandPool: sendCommandPoolMock,
+		context:         contextmocks.NewMockDefault(),
+	}
+	sendCommandPoolMock.On("JobCount").Return(1).Once()
+	sendCommandPoolMock.On("JobCount").Return(0).Once()
+	assert.True(t, processor.Drain(5*time.Second))
+	sendCommandPoolMock.AssertExpectations(t)
+}
+
+func TestEngineProcessor_DrainTimeout(t *testing.T) {
+	sendCommandPoolMock := new(taskmocks.MockedPool)
+	processor := EngineProcessor{
+		sendCommandPool: sendCommandPoolMock,
+		context:         contextmocks.NewMockDefault(),
+	}
+	sendCommandPoolMock.On("JobCount").Return(2)
+	assert.False(t, processor.Drain(10*time.Millisecond))
+}
+
 // TODO add shutdown and reboot test once we encapsulate docmanager
 func TestProcessCommand(t *testing.T) {
 	ctx := contextmocks.NewMockDefault()
@@ -364,3 +389,50 @@ func (m *DocumentMgrMock) RemoveDocumentState(documentID, location string) {
 	m.Called(documentID, location)
 	return
 }
+
+func TestRecordDocumentResult(t *testing.T) {
+	executions := metrics.CommandExecutions.Value(string(contracts.ResultStatusFailed))
+	failures := metrics.PluginFailures.Value("aws:runShellScript")
+	cpuSeconds := metrics.PluginCPUSeconds.Value("aws:runShellScript")
+	ioBytes := metrics.PluginIOBytes.Value("aws:runShellScript")
+	result := &contracts.DocumentResult{
+		Status: contracts.ResultStatusFailed,
+		PluginResults: map[string]*contracts.PluginResult{
+			"step1": {
+				PluginName:        "aws:runShellScript",
+				Status:            contracts.ResultStatusFailed,
+				ProcessAccounting: &contracts.ProcessAccounting{UserCPUSeconds: 1.5, SystemCPUSeconds: 0.5, ReadBytes: 512, WriteBytes: 1024},
+			},
+			"step2": {PluginName: "aws:downloadContent", Status: contracts.ResultStatusSuccess},
+		},
+	}
+
+	recordDocumentResult(result)
+
+	assert.Equal(t, executions+1, metrics.CommandExecutions.Value(string(contracts.ResultStatusFailed)))
+	assert.Equal(t, failures+1, metrics.PluginFailures.Value("aws:runShellScript"))
+	assert.Equal(t, float64(0), metrics.PluginFailures.Value("aws:downloadContent"))
+	assert.Equal(t, cpuSeconds+2, metrics.PluginCPUSeconds.Value("aws:runShellScript"))
+	assert.Equal(t, ioBytes+1536, metrics.PluginIOBytes.Value("aws:runShellScript"))
+}
+
+func TestCountInProgressRun(t *testing.T) {
+	running := map[int]bool{42: true}
+	original := isWorkerRunning
+	isWorkerRunning = func(log log.T, procInfo contracts.OSProcInfo) bool {
+		return running[procInfo.Pid]
+	}
+	defer func() { isWorkerRunning = original }()
+	log := contextmocks.NewMockDefault().Log()
+
+	var docState contracts.DocumentState
+	docState.DocumentInformation.RunCount = 1
+	assert.True(t, countInProgressRun(log, &docState, 2))
+	assert.Equal(t, 2, docState.DocumentInformation.RunCount)
+	assert.False(t, countInProgressRun(log, &docState, 2))
+
+	// the worker of a live session outlived the restart, reattaching is not a retry
+	docState.DocumentInformation.ProcInfo = contracts.OSProcInfo{Pid: 42}
+	assert.True(t, countInProgressRun(log, &docState, 2))
+	assert.Equal(t, 2, docState.DocumentInformation.RunCount)
+}
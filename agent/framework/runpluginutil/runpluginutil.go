@@ -25,7 +25,9 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/context"
 	"github.com/aws/amazon-ssm-agent/agent/contracts"
 	"github.com/aws/amazon-ssm-agent/agent/fileutil"
+	"github.com/aws/amazon-ssm-agent/agent/framework/diskguard"
 	"github.com/aws/amazon-ssm-agent/agent/framework/processor/executer/iohandler"
+	"github.com/aws/amazon-ssm-agent/agent/framework/workerlimits"
 	"github.com/aws/amazon-ssm-agent/agent/jsonutil"
 	"github.com/aws/amazon-ssm-agent/agent/log"
 	"github.com/aws/amazon-ssm-agent/agent/platform"
@@ -56,6 +58,7 @@ var (
 	SSMPluginRegistry PluginRegistry
 
 	deleteDirectoryRef = fileutil.DeleteDirectory
+	checkDiskSpace     = diskguard.Check
 )
 
 // allPlugins is the list of all known plugins.
@@ -77,6 +80,9 @@ var allPlugins = map[string]struct{}{
 	appconfig.PluginEC2ConfigUpdate:            {},
 	appconfig.PluginNameRefreshAssociation:     {},
 	appconfig.PluginDownloadContent:            {},
+	appconfig.PluginCopyFile:                   {},
+	appconfig.PluginManageService:              {},
+	appconfig.PluginRenderTemplate:             {},
 	appconfig.PluginRunDocument:                {},
 }
 
@@ -118,6 +124,9 @@ func RunPlugins(
 		}
 	}()
 
+	// checked once per document, steps that have not run yet fail fast rather than halfway through writing their output
+	diskSpaceErr := checkDiskSpace(context)
+
 	for pluginIndex, pluginState := range plugins {
 		pluginID := pluginState.Id     // the identifier of the plugin
 		pluginName := pluginState.Name // the name of the plugin
@@ -205,9 +214,31 @@ func RunPlugins(
 			configuration.Preconditions,
 			shouldSkipStepDueToPriorFailedStep)
 
+		if operation == executeStep && isBlockedByHostPolicy(context.AppConfig().PluginPolicy, pluginName) {
+			operation = failStep
+			logMessage = fmt.Sprintf(
+				"Plugin with name %s is blocked by host policy. Step name: %s",
+				pluginName,
+				pluginID)
+		}
+
+		if operation == executeStep && configuration.DetectOnly && !supportsDetectOnly(pluginName) {
+			operation = skipStep
+			logMessage = fmt.Sprintf(
+				"Step skipped as plugin %s does not support detect-only mode. Step name: %s",
+				pluginName,
+				pluginID)
+		}
+
+		if operation == executeStep && diskSpaceErr != nil {
+			operation = failStep
+			logMessage = fmt.Sprintf("Plugin with name %s was not run: %v. Step name: %s", pluginName, diskSpaceErr, pluginID)
+		}
+
 		switch operation {
 		case executeStep:
 			log.Infof("Running plugin %s %s", pluginName, pluginID)
+			usageBefore, limits, limited := workerlimits.Snapshot()
 			r = runPlugin(context, pluginFactory, pluginName, configuration, cancelFlag, ioConfig)
 			pluginOutputs[pluginID].Code = r.Code
 			pluginOutputs[pluginID].Status = r.Status
@@ -239,6 +270,9 @@ func RunPlugins(
 					pluginOutputs[pluginID].Code = contracts.ExitWithSuccess
 				}
 			}
+			if limited {
+				appendLimitBreaches(log, pluginOutputs[pluginID], limits, usageBefore)
+			}
 
 		case skipStep:
 			log.Info(logMessage)
@@ -280,6 +314,19 @@ func RunPlugins(
 	return
 }
 
+// appendLimitBreaches reports in the plugin output the worker resource limits reached while the plugin ran
+func appendLimitBreaches(log log.T, result *contracts.PluginResult, limits workerlimits.Limits, usageBefore workerlimits.Usage) {
+	usageAfter, _, found := workerlimits.Snapshot()
+	if !found {
+		return
+	}
+	for _, message := range workerlimits.BreachMessages(limits, usageBefore, usageAfter) {
+		log.Warnf("Worker resource limit reached: %v", message)
+		result.StandardError += "\n" + message
+		result.StandardOutput += "\n" + message
+	}
+}
+
 // orchestrationDirCleanup will clean orchestration folder for the successful and failed document executions. Cleaned only when the agent is configured to do so
 func orchestrationDirCleanup(context context.T, pluginsCount int, pluginOutputs map[string]*contracts.PluginResult, orchestrationDir string) {
 	log := context.Log()
@@ -390,6 +437,7 @@ var runPlugin = func(
 	res.Output = output.GetOutput()
 	res.StandardOutput = output.GetStdout()
 	res.StandardError = output.GetStderr()
+	res.ProcessAccounting = output.GetProcessAccounting()
 
 	return
 }
@@ -409,6 +457,29 @@ func executePlugin(
 	plugin.Execute(config, cancelFlag, output)
 }
 
+// isBlockedByHostPolicy returns true if the agent configuration denies the plugin, or allows a list of plugins the plugin is not part of
+func isBlockedByHostPolicy(policy appconfig.PluginPolicyCfg, pluginName string) bool {
+	for _, denied := range policy.DeniedPlugins {
+		if strings.EqualFold(denied, pluginName) {
+			return true
+		}
+	}
+	if len(policy.AllowedPlugins) == 0 {
+		return false
+	}
+	for _, allowed := range policy.AllowedPlugins {
+		if strings.EqualFold(allowed, pluginName) {
+			return false
+		}
+	}
+	return true
+}
+
+// supportsDetectOnly checks whether the plugin can report the changes it would make without applying them
+func supportsDetectOnly(pluginName string) bool {
+	return pluginName == appconfig.PluginNameAwsConfigurePackage || pluginName == appconfig.PluginRunDocument
+}
+
 // GetPropertyName returns the ID field of property in a v1.2 SSM Document
 func GetPropertyName(rawPluginInput interface{}) (propertyName string, err error) {
 	pluginInput := struct{ ID string }{}
@@ -32,6 +32,9 @@ var supportedPlugins = map[string]struct{}{
 	appconfig.PluginNameAwsSoftwareInventory:   {},
 	appconfig.PluginNameRefreshAssociation:     {},
 	appconfig.PluginNameAwsConfigurePackage:    {},
+	appconfig.PluginCopyFile:                   {},
+	appconfig.PluginManageService:              {},
+	appconfig.PluginRenderTemplate:             {},
 }
 
 // IsPluginSupportedForCurrentPlatform always returns true for plugins that exist for linux because currently there
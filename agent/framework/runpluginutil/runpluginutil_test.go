@@ -25,6 +25,7 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
 	"github.com/aws/amazon-ssm-agent/agent/context"
 	"github.com/aws/amazon-ssm-agent/agent/contracts"
+	"github.com/aws/amazon-ssm-agent/agent/framework/diskguard"
 	"github.com/aws/amazon-ssm-agent/agent/log"
 	contextmocks "github.com/aws/amazon-ssm-agent/agent/mocks/context"
 	"github.com/aws/amazon-ssm-agent/agent/task"
@@ -74,7 +75,7 @@ func TestRunPluginsWithNewDocument(t *testing.T) {
 	ctx := contextmocks.NewMockDefault()
 	defaultTime := time.Now()
 	pluginConfigs2 := make([]contracts.PluginState, len(pluginNames))
-	ioConfig := contracts.IOConfiguration{}
+	ioConfig := contracts.IOConfiguration{OrchestrationDirectory: t.TempDir()}
 
 	for index, name := range pluginNames {
 
@@ -165,7 +166,7 @@ func TestRunPluginsWithMissingPluginHandler(t *testing.T) {
 	defaultTime := time.Now()
 	defaultOutput := ""
 	pluginConfigs2 := make([]contracts.PluginState, len(pluginNames))
-	ioConfig := contracts.IOConfiguration{}
+	ioConfig := contracts.IOConfiguration{OrchestrationDirectory: t.TempDir()}
 
 	for index, name := range pluginNames {
 
@@ -252,7 +253,7 @@ func TestRunPluginsWithCancelFlagShutdown(t *testing.T) {
 	var cancelFlag task.CancelFlag = task.NewChanneledCancelFlag()
 	ctx := contextmocks.NewMockDefault()
 	defaultTime := time.Now()
-	ioConfig := contracts.IOConfiguration{}
+	ioConfig := contracts.IOConfiguration{OrchestrationDirectory: t.TempDir()}
 
 	for index, name := range pluginNames {
 		plugins[name] = new(PluginMock)
@@ -318,7 +319,7 @@ func TestRunPluginsWithInProgressDocuments(t *testing.T) {
 	plugins := make(map[string]*PluginMock)
 	pluginRegistry := PluginRegistry{}
 	ioConfig := contracts.IOConfiguration{
-		OrchestrationDirectory: "test",
+		OrchestrationDirectory: t.TempDir(),
 	}
 
 	var cancelFlag task.CancelFlag = task.NewChanneledCancelFlag()
@@ -428,7 +429,7 @@ func TestRunPluginsWithDuplicatePluginType(t *testing.T) {
 	// create an instance of our test object
 	plugin := new(PluginMock)
 	pluginRegistry := PluginRegistry{}
-	ioConfig := contracts.IOConfiguration{}
+	ioConfig := contracts.IOConfiguration{OrchestrationDirectory: t.TempDir()}
 
 	var cancelFlag task.CancelFlag
 	ctx := contextmocks.NewMockDefault()
@@ -514,7 +515,7 @@ func TestRunPluginsWithCompatiblePlatformPrecondition(t *testing.T) {
 	pluginResults := make(map[string]*contracts.PluginResult)
 	pluginInstances := make(map[string]*PluginMock)
 	pluginRegistry := PluginRegistry{}
-	ioConfig := contracts.IOConfiguration{}
+	ioConfig := contracts.IOConfiguration{OrchestrationDirectory: t.TempDir()}
 
 	var cancelFlag task.CancelFlag = task.NewChanneledCancelFlag()
 
@@ -617,7 +618,7 @@ func TestRunPluginsWithCompatiblePlatformPreconditionWithValueFirst(t *testing.T
 	pluginResults := make(map[string]*contracts.PluginResult)
 	pluginInstances := make(map[string]*PluginMock)
 	pluginRegistry := PluginRegistry{}
-	ioConfig := contracts.IOConfiguration{}
+	ioConfig := contracts.IOConfiguration{OrchestrationDirectory: t.TempDir()}
 
 	var cancelFlag task.CancelFlag = task.NewChanneledCancelFlag()
 
@@ -720,7 +721,7 @@ func TestRunPluginsWithIncompatiblePlatformPrecondition(t *testing.T) {
 	pluginInstances := make(map[string]*PluginMock)
 	pluginRegistry := PluginRegistry{}
 	ioConfig := contracts.IOConfiguration{
-		OrchestrationDirectory: "test",
+		OrchestrationDirectory: t.TempDir(),
 	}
 
 	var cancelFlag task.CancelFlag = task.NewChanneledCancelFlag()
@@ -837,7 +838,7 @@ func TestRunPluginsWithFuturePlatformPrecondition(t *testing.T) {
 	pluginResults := make(map[string]*contracts.PluginResult)
 	pluginInstances := make(map[string]*PluginMock)
 	pluginRegistry := PluginRegistry{}
-	ioConfig := contracts.IOConfiguration{}
+	ioConfig := contracts.IOConfiguration{OrchestrationDirectory: t.TempDir()}
 
 	var cancelFlag task.CancelFlag = task.NewChanneledCancelFlag()
 
@@ -939,7 +940,7 @@ func TestRunPluginsWithCompatiblePreconditionButMissingPluginHandler(t *testing.
 	pluginResults := make(map[string]*contracts.PluginResult)
 	pluginInstances := make(map[string]*PluginMock)
 	pluginRegistry := PluginRegistry{}
-	ioConfig := contracts.IOConfiguration{}
+	ioConfig := contracts.IOConfiguration{OrchestrationDirectory: t.TempDir()}
 
 	var cancelFlag task.CancelFlag = task.NewChanneledCancelFlag()
 
@@ -1052,7 +1053,7 @@ func TestRunPluginsWithMoreThanOnePrecondition(t *testing.T) {
 	pluginInstances := make(map[string]*PluginMock)
 	pluginRegistry := PluginRegistry{}
 	ioConfig := contracts.IOConfiguration{
-		OrchestrationDirectory: "test",
+		OrchestrationDirectory: t.TempDir(),
 	}
 
 	var cancelFlag task.CancelFlag = task.NewChanneledCancelFlag()
@@ -1182,7 +1183,7 @@ func TestRunPluginsWithUnrecognizedPreconditionOperator(t *testing.T) {
 	pluginResults := make(map[string]*contracts.PluginResult)
 	pluginInstances := make(map[string]*PluginMock)
 	pluginRegistry := PluginRegistry{}
-	ioConfig := contracts.IOConfiguration{}
+	ioConfig := contracts.IOConfiguration{OrchestrationDirectory: t.TempDir()}
 
 	var cancelFlag task.CancelFlag = task.NewChanneledCancelFlag()
 
@@ -1292,7 +1293,7 @@ func TestRunPluginsWithUnrecognizedPreconditionDuplicateVariable(t *testing.T) {
 	pluginResults := make(map[string]*contracts.PluginResult)
 	pluginInstances := make(map[string]*PluginMock)
 	pluginRegistry := PluginRegistry{}
-	ioConfig := contracts.IOConfiguration{}
+	ioConfig := contracts.IOConfiguration{OrchestrationDirectory: t.TempDir()}
 
 	var cancelFlag task.CancelFlag = task.NewChanneledCancelFlag()
 
@@ -1403,7 +1404,7 @@ func TestRunPluginsWithUnrecognizedPreconditionDuplicateParameter(t *testing.T)
 	pluginResults := make(map[string]*contracts.PluginResult)
 	pluginInstances := make(map[string]*PluginMock)
 	pluginRegistry := PluginRegistry{}
-	ioConfig := contracts.IOConfiguration{}
+	ioConfig := contracts.IOConfiguration{OrchestrationDirectory: t.TempDir()}
 
 	var cancelFlag task.CancelFlag = task.NewChanneledCancelFlag()
 
@@ -1514,7 +1515,7 @@ func TestRunPluginsWithUnrecognizedPreconditionDuplicateConstant(t *testing.T) {
 	pluginResults := make(map[string]*contracts.PluginResult)
 	pluginInstances := make(map[string]*PluginMock)
 	pluginRegistry := PluginRegistry{}
-	ioConfig := contracts.IOConfiguration{}
+	ioConfig := contracts.IOConfiguration{OrchestrationDirectory: t.TempDir()}
 
 	var cancelFlag task.CancelFlag = task.NewChanneledCancelFlag()
 
@@ -1625,7 +1626,7 @@ func TestRunPluginsWithUnrecognizedPreconditionSSMParameter(t *testing.T) {
 	pluginResults := make(map[string]*contracts.PluginResult)
 	pluginInstances := make(map[string]*PluginMock)
 	pluginRegistry := PluginRegistry{}
-	ioConfig := contracts.IOConfiguration{}
+	ioConfig := contracts.IOConfiguration{OrchestrationDirectory: t.TempDir()}
 
 	var cancelFlag task.CancelFlag = task.NewChanneledCancelFlag()
 
@@ -1736,7 +1737,7 @@ func TestRunPluginsWithUnrecognizedPreconditionSecureSSMParameter(t *testing.T)
 	pluginResults := make(map[string]*contracts.PluginResult)
 	pluginInstances := make(map[string]*PluginMock)
 	pluginRegistry := PluginRegistry{}
-	ioConfig := contracts.IOConfiguration{}
+	ioConfig := contracts.IOConfiguration{OrchestrationDirectory: t.TempDir()}
 
 	var cancelFlag task.CancelFlag = task.NewChanneledCancelFlag()
 
@@ -1847,7 +1848,7 @@ func TestRunPluginsWithUnrecognizedPreconditionNoDocumentParameters(t *testing.T
 	pluginResults := make(map[string]*contracts.PluginResult)
 	pluginInstances := make(map[string]*PluginMock)
 	pluginRegistry := PluginRegistry{}
-	ioConfig := contracts.IOConfiguration{}
+	ioConfig := contracts.IOConfiguration{OrchestrationDirectory: t.TempDir()}
 
 	var cancelFlag task.CancelFlag = task.NewChanneledCancelFlag()
 
@@ -1958,7 +1959,7 @@ func TestRunPluginsWithUnrecognizedPreconditionUnrecognizedParameter(t *testing.
 	pluginResults := make(map[string]*contracts.PluginResult)
 	pluginInstances := make(map[string]*PluginMock)
 	pluginRegistry := PluginRegistry{}
-	ioConfig := contracts.IOConfiguration{}
+	ioConfig := contracts.IOConfiguration{OrchestrationDirectory: t.TempDir()}
 
 	var cancelFlag task.CancelFlag = task.NewChanneledCancelFlag()
 
@@ -2069,7 +2070,7 @@ func TestRunPluginsPlatformPreconditionWithDocumentParameters(t *testing.T) {
 	pluginResults := make(map[string]*contracts.PluginResult)
 	pluginInstances := make(map[string]*PluginMock)
 	pluginRegistry := PluginRegistry{}
-	ioConfig := contracts.IOConfiguration{}
+	ioConfig := contracts.IOConfiguration{OrchestrationDirectory: t.TempDir()}
 
 	var cancelFlag task.CancelFlag = task.NewChanneledCancelFlag()
 
@@ -2180,7 +2181,7 @@ func TestRunPluginsWithCompatibleParamParamPrecondition(t *testing.T) {
 	pluginResults := make(map[string]*contracts.PluginResult)
 	pluginInstances := make(map[string]*PluginMock)
 	pluginRegistry := PluginRegistry{}
-	ioConfig := contracts.IOConfiguration{}
+	ioConfig := contracts.IOConfiguration{OrchestrationDirectory: t.TempDir()}
 
 	var cancelFlag task.CancelFlag = task.NewChanneledCancelFlag()
 
@@ -2284,7 +2285,7 @@ func TestRunPluginsWithIncompatibleParamParamPrecondition(t *testing.T) {
 	pluginInstances := make(map[string]*PluginMock)
 	pluginRegistry := PluginRegistry{}
 	ioConfig := contracts.IOConfiguration{
-		OrchestrationDirectory: "test",
+		OrchestrationDirectory: t.TempDir(),
 	}
 
 	var cancelFlag task.CancelFlag = task.NewChanneledCancelFlag()
@@ -2397,7 +2398,7 @@ func TestRunPluginsWithCompatibleParamValuePrecondition(t *testing.T) {
 	pluginResults := make(map[string]*contracts.PluginResult)
 	pluginInstances := make(map[string]*PluginMock)
 	pluginRegistry := PluginRegistry{}
-	ioConfig := contracts.IOConfiguration{}
+	ioConfig := contracts.IOConfiguration{OrchestrationDirectory: t.TempDir()}
 
 	var cancelFlag task.CancelFlag = task.NewChanneledCancelFlag()
 
@@ -2500,7 +2501,7 @@ func TestRunPluginsWithCompatibleValueParamPrecondition(t *testing.T) {
 	pluginResults := make(map[string]*contracts.PluginResult)
 	pluginInstances := make(map[string]*PluginMock)
 	pluginRegistry := PluginRegistry{}
-	ioConfig := contracts.IOConfiguration{}
+	ioConfig := contracts.IOConfiguration{OrchestrationDirectory: t.TempDir()}
 
 	var cancelFlag task.CancelFlag = task.NewChanneledCancelFlag()
 
@@ -2603,7 +2604,7 @@ func TestRunPluginsWithIncompatibleParamValuePrecondition(t *testing.T) {
 	pluginResults := make(map[string]*contracts.PluginResult)
 	pluginInstances := make(map[string]*PluginMock)
 	pluginRegistry := PluginRegistry{}
-	ioConfig := contracts.IOConfiguration{}
+	ioConfig := contracts.IOConfiguration{OrchestrationDirectory: t.TempDir()}
 
 	var cancelFlag task.CancelFlag = task.NewChanneledCancelFlag()
 
@@ -2706,7 +2707,7 @@ func TestRunPluginsWithCompatibleMixedPrecondition(t *testing.T) {
 	pluginResults := make(map[string]*contracts.PluginResult)
 	pluginInstances := make(map[string]*PluginMock)
 	pluginRegistry := PluginRegistry{}
-	ioConfig := contracts.IOConfiguration{}
+	ioConfig := contracts.IOConfiguration{OrchestrationDirectory: t.TempDir()}
 
 	var cancelFlag task.CancelFlag = task.NewChanneledCancelFlag()
 
@@ -2809,7 +2810,7 @@ func TestRunPluginsWithIncompatibleMixedPrecondition(t *testing.T) {
 	pluginResults := make(map[string]*contracts.PluginResult)
 	pluginInstances := make(map[string]*PluginMock)
 	pluginRegistry := PluginRegistry{}
-	ioConfig := contracts.IOConfiguration{}
+	ioConfig := contracts.IOConfiguration{OrchestrationDirectory: t.TempDir()}
 
 	var cancelFlag task.CancelFlag = task.NewChanneledCancelFlag()
 
@@ -2911,7 +2912,7 @@ func TestRunPluginsWithMoreThanTwoPreconditionOperands(t *testing.T) {
 	pluginResults := make(map[string]*contracts.PluginResult)
 	pluginInstances := make(map[string]*PluginMock)
 	pluginRegistry := PluginRegistry{}
-	ioConfig := contracts.IOConfiguration{}
+	ioConfig := contracts.IOConfiguration{OrchestrationDirectory: t.TempDir()}
 
 	var cancelFlag task.CancelFlag = task.NewChanneledCancelFlag()
 
@@ -3025,7 +3026,7 @@ func TestRunPluginsWithUnknownPlugin(t *testing.T) {
 	pluginResults := make(map[string]*contracts.PluginResult)
 	pluginInstances := make(map[string]*PluginMock)
 	pluginRegistry := PluginRegistry{}
-	ioConfig := contracts.IOConfiguration{}
+	ioConfig := contracts.IOConfiguration{OrchestrationDirectory: t.TempDir()}
 
 	var cancelFlag task.CancelFlag = task.NewChanneledCancelFlag()
 
@@ -3149,7 +3150,7 @@ func TestRunPluginSuccessWithNonTruncatedResult(t *testing.T) {
 	ctx := contextmocks.NewMockDefault()
 	defaultTime := time.Now()
 	pluginConfigs2 := make([]contracts.PluginState, len(pluginNames))
-	ioConfig := contracts.IOConfiguration{}
+	ioConfig := contracts.IOConfiguration{OrchestrationDirectory: t.TempDir()}
 
 	for index, name := range pluginNames {
 
@@ -3213,6 +3214,124 @@ func TestRunPluginSuccessWithNonTruncatedResult(t *testing.T) {
 	}
 }
 
+func TestRunPluginsBlockedByHostPolicy(t *testing.T) {
+	setIsSupportedMock()
+	defer restoreIsSupported()
+	var cancelFlag task.CancelFlag = task.NewChanneledCancelFlag()
+
+	config := appconfig.SsmagentConfig{}
+	config.PluginPolicy.DeniedPlugins = []string{testPlugin2}
+	ctx := contextmocks.NewMockDefaultWithConfig(config)
+
+	pluginRegistry := PluginRegistry{}
+	pluginStates := []contracts.PluginState{}
+	pluginInstances := make(map[string]*PluginMock)
+	for _, name := range []string{testPlugin1, testPlugin2} {
+		configuration := contracts.Configuration{
+			PluginID:   name,
+			PluginName: name,
+		}
+		pluginInstances[name] = new(PluginMock)
+		pluginFactory := new(PluginFactoryMock)
+		pluginFactory.On("Create", mock.Anything).Return(pluginInstances[name], nil)
+		pluginRegistry[name] = pluginFactory
+		pluginStates = append(pluginStates, contracts.PluginState{
+			Name:          name,
+			Id:            name,
+			Configuration: configuration,
+		})
+	}
+	pluginInstances[testPlugin1].On("Execute", mock.Anything, cancelFlag, mock.Anything).Return()
+
+	ch := make(chan contracts.PluginResult, len(pluginStates))
+	outputs := RunPlugins(ctx, pluginStates, contracts.IOConfiguration{OrchestrationDirectory: t.TempDir()}, contracts.MessageGatewayService, pluginRegistry, ch, cancelFlag)
+	close(ch)
+
+	pluginInstances[testPlugin1].AssertExpectations(t)
+	pluginInstances[testPlugin2].AssertNotCalled(t, "Execute", mock.Anything, mock.Anything, mock.Anything)
+	assert.Equal(t, contracts.ResultStatusFailed, outputs[testPlugin2].Status)
+	assert.Contains(t, outputs[testPlugin2].Error, "blocked by host policy")
+}
+
+func TestRunPluginsFailFastOnInsufficientDiskSpace(t *testing.T) {
+	setIsSupportedMock()
+	defer restoreIsSupported()
+	checkDiskSpace = func(context.T) error { return fmt.Errorf("insufficient disk space under /var/lib/amazon/ssm") }
+	defer func() { checkDiskSpace = diskguard.Check }()
+	var cancelFlag task.CancelFlag = task.NewChanneledCancelFlag()
+
+	ctx := contextmocks.NewMockDefault()
+	pluginInstance := new(PluginMock)
+	pluginFactory := new(PluginFactoryMock)
+	pluginFactory.On("Create", mock.Anything).Return(pluginInstance, nil)
+	pluginRegistry := PluginRegistry{testPlugin1: pluginFactory}
+	pluginStates := []contracts.PluginState{{
+		Name:          testPlugin1,
+		Id:            testPlugin1,
+		Configuration: contracts.Configuration{PluginID: testPlugin1, PluginName: testPlugin1},
+	}}
+
+	ch := make(chan contracts.PluginResult, len(pluginStates))
+	outputs := RunPlugins(ctx, pluginStates, contracts.IOConfiguration{OrchestrationDirectory: t.TempDir()}, contracts.MessageGatewayService, pluginRegistry, ch, cancelFlag)
+	close(ch)
+
+	pluginInstance.AssertNotCalled(t, "Execute", mock.Anything, mock.Anything, mock.Anything)
+	assert.Equal(t, contracts.ResultStatusFailed, outputs[testPlugin1].Status)
+	assert.Contains(t, outputs[testPlugin1].Error, "insufficient disk space")
+}
+
+func TestRunPluginsSkipsUnsupportedPluginsInDetectOnlyMode(t *testing.T) {
+	setIsSupportedMock()
+	defer restoreIsSupported()
+	var cancelFlag task.CancelFlag = task.NewChanneledCancelFlag()
+
+	ctx := contextmocks.NewMockDefault()
+	pluginRegistry := PluginRegistry{}
+	pluginStates := []contracts.PluginState{}
+	pluginInstances := make(map[string]*PluginMock)
+	for _, name := range []string{appconfig.PluginNameAwsConfigurePackage, testPlugin1} {
+		pluginInstances[name] = new(PluginMock)
+		pluginFactory := new(PluginFactoryMock)
+		pluginFactory.On("Create", mock.Anything).Return(pluginInstances[name], nil)
+		pluginRegistry[name] = pluginFactory
+		pluginStates = append(pluginStates, contracts.PluginState{
+			Name:          name,
+			Id:            name,
+			Configuration: contracts.Configuration{PluginID: name, PluginName: name, DetectOnly: true},
+		})
+	}
+	pluginInstances[appconfig.PluginNameAwsConfigurePackage].On("Execute", mock.Anything, cancelFlag, mock.Anything).Return()
+
+	ch := make(chan contracts.PluginResult, len(pluginStates))
+	ioConfig := contracts.IOConfiguration{OrchestrationDirectory: t.TempDir()}
+	outputs := RunPlugins(ctx, pluginStates, ioConfig, contracts.MessageGatewayService, pluginRegistry, ch, cancelFlag)
+	close(ch)
+
+	pluginInstances[appconfig.PluginNameAwsConfigurePackage].AssertExpectations(t)
+	pluginInstances[testPlugin1].AssertNotCalled(t, "Execute", mock.Anything, mock.Anything, mock.Anything)
+	assert.Equal(t, contracts.ResultStatusSkipped, outputs[testPlugin1].Status)
+}
+
+func TestIsBlockedByHostPolicy(t *testing.T) {
+	assert.False(t, isBlockedByHostPolicy(appconfig.PluginPolicyCfg{}, "aws:runShellScript"))
+
+	denied := appconfig.PluginPolicyCfg{DeniedPlugins: []string{"aws:runDockerAction"}}
+	assert.True(t, isBlockedByHostPolicy(denied, "aws:runDockerAction"))
+	assert.True(t, isBlockedByHostPolicy(denied, "AWS:RunDockerAction"))
+	assert.False(t, isBlockedByHostPolicy(denied, "aws:runShellScript"))
+
+	allowed := appconfig.PluginPolicyCfg{AllowedPlugins: []string{"aws:runShellScript"}}
+	assert.False(t, isBlockedByHostPolicy(allowed, "aws:runShellScript"))
+	assert.True(t, isBlockedByHostPolicy(allowed, "aws:softwareInventory"))
+
+	// deny wins over allow
+	both := appconfig.PluginPolicyCfg{
+		AllowedPlugins: []string{"aws:runShellScript"},
+		DeniedPlugins:  []string{"aws:runShellScript"},
+	}
+	assert.True(t, isBlockedByHostPolicy(both, "aws:runShellScript"))
+}
+
 func TestRunPluginSetsUpstreamServiceNameInEachPlugin(t *testing.T) {
 	setIsSupportedMock()
 	defer restoreIsSupported()
@@ -3225,7 +3344,7 @@ func TestRunPluginSetsUpstreamServiceNameInEachPlugin(t *testing.T) {
 	ctx := contextmocks.NewMockDefault()
 	defaultTime := time.Now()
 	pluginConfigs2 := make([]contracts.PluginState, len(pluginNames))
-	ioConfig := contracts.IOConfiguration{}
+	ioConfig := contracts.IOConfiguration{OrchestrationDirectory: t.TempDir()}
 
 	for index, name := range pluginNames {
 
@@ -3457,7 +3576,7 @@ func TestRunPluginWithOnFailureProperty168(t *testing.T) {
 	var cancelFlag task.CancelFlag
 	ctx := contextmocks.NewMockDefault()
 	defaultTime := time.Now()
-	ioConfig := contracts.IOConfiguration{}
+	ioConfig := contracts.IOConfiguration{OrchestrationDirectory: t.TempDir()}
 	pluginConfigs2 := make([]contracts.PluginState, len(pluginNames))
 
 	for index, name := range pluginNames {
@@ -3553,7 +3672,7 @@ func TestRunPluginWithOnFailureProperty169(t *testing.T) {
 	var cancelFlag task.CancelFlag
 	ctx := contextmocks.NewMockDefault()
 	defaultTime := time.Now()
-	ioConfig := contracts.IOConfiguration{}
+	ioConfig := contracts.IOConfiguration{OrchestrationDirectory: t.TempDir()}
 	pluginConfigs2 := make([]contracts.PluginState, len(pluginNames))
 
 	for index, name := range pluginNames {
@@ -3649,7 +3768,7 @@ func TestRunPluginWithOnFailureProperty1(t *testing.T) {
 	var cancelFlag task.CancelFlag
 	ctx := contextmocks.NewMockDefault()
 	defaultTime := time.Now()
-	ioConfig := contracts.IOConfiguration{}
+	ioConfig := contracts.IOConfiguration{OrchestrationDirectory: t.TempDir()}
 	pluginConfigs2 := make([]contracts.PluginState, len(pluginNames))
 
 	for index, name := range pluginNames {
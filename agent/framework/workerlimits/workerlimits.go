@@ -0,0 +1,133 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package workerlimits applies CPU and memory ceilings to document and session worker processes
+// and detects when a worker reached them.
+package workerlimits
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+)
+
+// EnvironmentVariable passes the limits applied to a worker process from the agent to the worker
+const EnvironmentVariable = "SSM_WORKER_RESOURCE_LIMITS"
+
+const megabyte = 1024 * 1024
+
+// Limits are the resource ceilings of a worker process, 0 means unlimited
+type Limits struct {
+	// CpuPercent is the percentage of the total CPU capacity of the host
+	CpuPercent int
+	MemoryMB   int
+}
+
+// Group holds the OS resources enforcing the limits on a single worker process
+type Group interface {
+	// AfterStart is called once the worker process is started
+	AfterStart(pid int) error
+	// Close releases the OS resources once the worker process has exited
+	Close() error
+}
+
+// Usage holds the counters used to detect that a worker reached its limits
+type Usage struct {
+	OOMKills        uint64
+	CpuThrottled    uint64
+	PeakMemoryBytes uint64
+}
+
+var readUsage = readCurrentUsage
+
+// ForWorker returns the limits configured for the given worker executable
+func ForWorker(config appconfig.WorkerLimitsCfg, workerName string) Limits {
+	if workerName == appconfig.DefaultSessionWorker {
+		return Limits{CpuPercent: config.SessionWorkerCpuPercent, MemoryMB: config.SessionWorkerMemoryMB}
+	}
+	return Limits{CpuPercent: config.DocumentWorkerCpuPercent, MemoryMB: config.DocumentWorkerMemoryMB}
+}
+
+// IsSet returns true if at least one resource is limited
+func (limits Limits) IsSet() bool {
+	return limits.CpuPercent > 0 || limits.MemoryMB > 0
+}
+
+// String describes the limits for logs and command output
+func (limits Limits) String() string {
+	var parts []string
+	if limits.CpuPercent > 0 {
+		parts = append(parts, fmt.Sprintf("cpu=%d%%", limits.CpuPercent))
+	}
+	if limits.MemoryMB > 0 {
+		parts = append(parts, fmt.Sprintf("memory=%dMB", limits.MemoryMB))
+	}
+	if len(parts) == 0 {
+		return "unlimited"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Environment returns the environment variable telling the worker which limits are applied
+func (limits Limits) Environment() string {
+	content, _ := json.Marshal(limits)
+	return EnvironmentVariable + "=" + string(content)
+}
+
+// FromEnvironment returns the limits applied to the current worker process
+func FromEnvironment() (limits Limits, found bool) {
+	value := os.Getenv(EnvironmentVariable)
+	if value == "" {
+		return limits, false
+	}
+	if err := json.Unmarshal([]byte(value), &limits); err != nil {
+		return limits, false
+	}
+	return limits, limits.IsSet()
+}
+
+// Snapshot returns the usage counters of the current worker process, found is false when no limits are applied
+func Snapshot() (usage Usage, limits Limits, found bool) {
+	if limits, found = FromEnvironment(); !found {
+		return
+	}
+	var err error
+	if usage, err = readUsage(); err != nil {
+		return usage, limits, false
+	}
+	return usage, limits, true
+}
+
+// BreachMessages describes the limits reached between the two usage snapshots
+func BreachMessages(limits Limits, before Usage, after Usage) (messages []string) {
+	if after.OOMKills > before.OOMKills {
+		messages = append(messages, fmt.Sprintf("%d process(es) were killed after reaching the worker memory limit of %dMB.",
+			after.OOMKills-before.OOMKills, limits.MemoryMB))
+	} else if memoryLimitReached(limits, after) && !memoryLimitReached(limits, before) {
+		messages = append(messages, fmt.Sprintf("Memory usage reached the worker memory limit of %dMB, allocations beyond it failed.", limits.MemoryMB))
+	}
+	if after.CpuThrottled > before.CpuThrottled {
+		messages = append(messages, fmt.Sprintf("CPU usage was throttled %d time(s) by the worker CPU limit of %d%%.",
+			after.CpuThrottled-before.CpuThrottled, limits.CpuPercent))
+	}
+	return messages
+}
+
+// memoryLimitReached returns true if the peak memory usage got within a megabyte of the limit
+func memoryLimitReached(limits Limits, usage Usage) bool {
+	return limits.MemoryMB > 0 && usage.PeakMemoryBytes > 0 &&
+		usage.PeakMemoryBytes+megabyte >= uint64(limits.MemoryMB)*megabyte
+}
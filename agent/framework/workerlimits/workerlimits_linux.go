@@ -0,0 +1,212 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build linux
+// +build linux
+
+package workerlimits
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+const (
+	cpuPeriodMicroseconds = 100000
+	workersCgroupName     = "amazon-ssm-agent-workers"
+)
+
+var (
+	cgroupRoot     = "/sys/fs/cgroup"
+	procSelfCgroup = "/proc/self/cgroup"
+	numCPU         = runtime.NumCPU
+)
+
+// cgroup is a cgroup v2 group created for a single worker process
+type cgroup struct {
+	path string
+	dir  *os.File
+}
+
+// Attach creates a cgroup v2 group enforcing the limits and makes the command start inside it
+func Attach(cmd *exec.Cmd, name string, limits Limits) (Group, error) {
+	if _, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers")); err != nil {
+		return nil, fmt.Errorf("cgroup v2 is not available: %v", err)
+	}
+	parent := filepath.Join(cgroupRoot, workersCgroupName)
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return nil, err
+	}
+	if err := enableControllers(cgroupRoot); err != nil {
+		return nil, err
+	}
+	if err := enableControllers(parent); err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(parent, strings.ReplaceAll(name, string(os.PathSeparator), "_"))
+	// a group left by a previous run of the same document is empty once its worker exited
+	_ = os.Remove(path)
+	if err := os.Mkdir(path, 0755); err != nil {
+		return nil, err
+	}
+	group := &cgroup{path: path}
+	if err := group.apply(limits); err != nil {
+		group.Close()
+		return nil, err
+	}
+
+	dir, err := os.Open(path)
+	if err != nil {
+		group.Close()
+		return nil, err
+	}
+	group.dir = dir
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.UseCgroupFD = true
+	cmd.SysProcAttr.CgroupFD = int(dir.Fd())
+	return group, nil
+}
+
+// apply writes the limits to the cgroup interface files
+func (group *cgroup) apply(limits Limits) error {
+	if limits.CpuPercent > 0 {
+		quota := cpuPeriodMicroseconds * numCPU() * limits.CpuPercent / 100
+		if err := writeCgroupFile(group.path, "cpu.max", fmt.Sprintf("%d %d", quota, cpuPeriodMicroseconds)); err != nil {
+			return err
+		}
+	}
+	if limits.MemoryMB > 0 {
+		if err := writeCgroupFile(group.path, "memory.max", strconv.FormatUint(uint64(limits.MemoryMB)*megabyte, 10)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AfterStart releases the cgroup directory handle, the process was started inside the group
+func (group *cgroup) AfterStart(pid int) error {
+	if group.dir == nil {
+		return nil
+	}
+	err := group.dir.Close()
+	group.dir = nil
+	return err
+}
+
+// Close removes the cgroup, which only succeeds once every process in it exited
+func (group *cgroup) Close() error {
+	if group.dir != nil {
+		group.dir.Close()
+		group.dir = nil
+	}
+	return os.Remove(group.path)
+}
+
+// enableControllers delegates the cpu and memory controllers to the child groups
+func enableControllers(path string) error {
+	content, err := os.ReadFile(filepath.Join(path, "cgroup.subtree_control"))
+	if err != nil {
+		return err
+	}
+	enabled := strings.Fields(string(content))
+	var missing []string
+	for _, controller := range []string{"cpu", "memory"} {
+		if !contains(enabled, controller) {
+			missing = append(missing, "+"+controller)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return writeCgroupFile(path, "cgroup.subtree_control", strings.Join(missing, " "))
+}
+
+func writeCgroupFile(path string, name string, value string) error {
+	if err := os.WriteFile(filepath.Join(path, name), []byte(value), 0644); err != nil {
+		return fmt.Errorf("failed to write %v to %v: %v", value, name, err)
+	}
+	return nil
+}
+
+// readCurrentUsage reads the counters of the cgroup the current process belongs to
+func readCurrentUsage() (usage Usage, err error) {
+	content, err := os.ReadFile(procSelfCgroup)
+	if err != nil {
+		return usage, err
+	}
+	var relativePath string
+	for _, line := range strings.Split(string(content), "\n") {
+		// the cgroup v2 hierarchy is the line with the id 0 and no controllers, e.g. 0::/amazon-ssm-agent-workers/<name>
+		if strings.HasPrefix(line, "0::") {
+			relativePath = strings.TrimPrefix(line, "0::")
+			break
+		}
+	}
+	if relativePath == "" {
+		return usage, fmt.Errorf("process is not in a cgroup v2 group")
+	}
+	path := filepath.Join(cgroupRoot, relativePath)
+
+	memoryEvents, err := readKeyValues(filepath.Join(path, "memory.events"))
+	if err != nil {
+		return usage, err
+	}
+	cpuStat, err := readKeyValues(filepath.Join(path, "cpu.stat"))
+	if err != nil {
+		return usage, err
+	}
+	usage.OOMKills = memoryEvents["oom_kill"]
+	usage.CpuThrottled = cpuStat["nr_throttled"]
+	return usage, nil
+}
+
+// readKeyValues parses the flat keyed files of the cgroup interface
+func readKeyValues(path string) (map[string]uint64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	values := make(map[string]uint64)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		if value, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+			values[fields[0]] = value
+		}
+	}
+	return values, scanner.Err()
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build linux
+// +build linux
+
+package workerlimits
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func useFakeCgroupRoot(t *testing.T) string {
+	root := t.TempDir()
+	originalRoot, originalNumCPU := cgroupRoot, numCPU
+	cgroupRoot = root
+	numCPU = func() int { return 4 }
+	t.Cleanup(func() {
+		cgroupRoot, numCPU = originalRoot, originalNumCPU
+	})
+	return root
+}
+
+func TestAttach_NoCgroupV2(t *testing.T) {
+	useFakeCgroupRoot(t)
+
+	_, err := Attach(exec.Command("true"), "document", Limits{CpuPercent: 50})
+	assert.Error(t, err)
+}
+
+func TestAttach_WritesLimits(t *testing.T) {
+	root := useFakeCgroupRoot(t)
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "cgroup.controllers"), []byte("cpu io memory pids"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "cgroup.subtree_control"), []byte("cpu memory"), 0644))
+	parent := filepath.Join(root, workersCgroupName)
+	assert.NoError(t, os.MkdirAll(parent, 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(parent, "cgroup.subtree_control"), []byte(""), 0644))
+
+	cmd := exec.Command("true")
+	group, err := Attach(cmd, "command/1", Limits{CpuPercent: 50, MemoryMB: 256})
+	assert.NoError(t, err)
+
+	path := filepath.Join(parent, "command_1")
+	cpuMax, _ := os.ReadFile(filepath.Join(path, "cpu.max"))
+	memoryMax, _ := os.ReadFile(filepath.Join(path, "memory.max"))
+	subtreeControl, _ := os.ReadFile(filepath.Join(parent, "cgroup.subtree_control"))
+	assert.Equal(t, "200000 100000", string(cpuMax))
+	assert.Equal(t, "268435456", string(memoryMax))
+	assert.Equal(t, "+cpu +memory", string(subtreeControl))
+	assert.True(t, cmd.SysProcAttr.UseCgroupFD)
+
+	assert.NoError(t, group.AfterStart(0))
+	// a real cgroup directory only contains interface files that can not be deleted
+	os.Remove(filepath.Join(path, "cpu.max"))
+	os.Remove(filepath.Join(path, "memory.max"))
+	assert.NoError(t, group.Close())
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestReadCurrentUsage(t *testing.T) {
+	root := useFakeCgroupRoot(t)
+	originalProcSelfCgroup := procSelfCgroup
+	defer func() { procSelfCgroup = originalProcSelfCgroup }()
+	procSelfCgroup = filepath.Join(root, "self-cgroup")
+
+	path := filepath.Join(root, workersCgroupName, "document")
+	assert.NoError(t, os.MkdirAll(path, 0755))
+	assert.NoError(t, os.WriteFile(procSelfCgroup, []byte("0::/"+workersCgroupName+"/document\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(path, "memory.events"), []byte("low 0\nhigh 0\nmax 12\noom 3\noom_kill 2\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(path, "cpu.stat"), []byte("usage_usec 1000\nnr_periods 40\nnr_throttled 7\nthrottled_usec 500\n"), 0644))
+
+	usage, err := readCurrentUsage()
+	assert.NoError(t, err)
+	assert.Equal(t, Usage{OOMKills: 2, CpuThrottled: 7}, usage)
+
+	assert.NoError(t, os.WriteFile(procSelfCgroup, []byte("1:name=systemd:/\n"), 0644))
+	_, err = readCurrentUsage()
+	assert.Error(t, err)
+}
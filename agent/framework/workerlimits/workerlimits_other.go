@@ -0,0 +1,32 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build darwin || freebsd || netbsd || openbsd
+// +build darwin freebsd netbsd openbsd
+
+package workerlimits
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// Attach is not supported on this platform
+func Attach(cmd *exec.Cmd, name string, limits Limits) (Group, error) {
+	return nil, fmt.Errorf("worker resource limits are not supported on %v", runtime.GOOS)
+}
+
+func readCurrentUsage() (Usage, error) {
+	return Usage{}, fmt.Errorf("worker resource limits are not supported on %v", runtime.GOOS)
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package workerlimits
+
+import (
+	"os"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForWorker(t *testing.T) {
+	config := appconfig.WorkerLimitsCfg{
+		DocumentWorkerCpuPercent: 50,
+		DocumentWorkerMemoryMB:   512,
+		SessionWorkerCpuPercent:  0,
+		SessionWorkerMemoryMB:    256,
+	}
+
+	assert.Equal(t, Limits{CpuPercent: 50, MemoryMB: 512}, ForWorker(config, appconfig.DefaultDocumentWorker))
+	assert.Equal(t, Limits{CpuPercent: 0, MemoryMB: 256}, ForWorker(config, appconfig.DefaultSessionWorker))
+	assert.False(t, ForWorker(appconfig.WorkerLimitsCfg{}, appconfig.DefaultDocumentWorker).IsSet())
+}
+
+func TestLimitsString(t *testing.T) {
+	assert.Equal(t, "unlimited", Limits{}.String())
+	assert.Equal(t, "cpu=25%", Limits{CpuPercent: 25}.String())
+	assert.Equal(t, "cpu=25%, memory=128MB", Limits{CpuPercent: 25, MemoryMB: 128}.String())
+}
+
+func TestEnvironmentRoundTrip(t *testing.T) {
+	limits := Limits{CpuPercent: 10, MemoryMB: 64}
+	environment := limits.Environment()
+	assert.Equal(t, EnvironmentVariable+`={"CpuPercent":10,"MemoryMB":64}`, environment)
+
+	t.Setenv(EnvironmentVariable, environment[len(EnvironmentVariable)+1:])
+	parsed, found := FromEnvironment()
+	assert.True(t, found)
+	assert.Equal(t, limits, parsed)
+
+	t.Setenv(EnvironmentVariable, "invalid")
+	_, found = FromEnvironment()
+	assert.False(t, found)
+}
+
+func TestSnapshot(t *testing.T) {
+	originalReadUsage := readUsage
+	defer func() { readUsage = originalReadUsage }()
+	readUsage = func() (Usage, error) {
+		return Usage{OOMKills: 2}, nil
+	}
+
+	os.Unsetenv(EnvironmentVariable)
+	_, _, found := Snapshot()
+	assert.False(t, found)
+
+	t.Setenv(EnvironmentVariable, `{"MemoryMB":128}`)
+	usage, limits, found := Snapshot()
+	assert.True(t, found)
+	assert.Equal(t, uint64(2), usage.OOMKills)
+	assert.Equal(t, 128, limits.MemoryMB)
+}
+
+func TestBreachMessages(t *testing.T) {
+	limits := Limits{CpuPercent: 20, MemoryMB: 100}
+
+	assert.Empty(t, BreachMessages(limits, Usage{OOMKills: 1, CpuThrottled: 5}, Usage{OOMKills: 1, CpuThrottled: 5}))
+
+	messages := BreachMessages(limits, Usage{OOMKills: 1, CpuThrottled: 5}, Usage{OOMKills: 2, CpuThrottled: 9})
+	assert.Equal(t, []string{
+		"1 process(es) were killed after reaching the worker memory limit of 100MB.",
+		"CPU usage was throttled 4 time(s) by the worker CPU limit of 20%.",
+	}, messages)
+}
+
+func TestBreachMessages_PeakMemory(t *testing.T) {
+	limits := Limits{MemoryMB: 100}
+	belowLimit := Usage{PeakMemoryBytes: 50 * megabyte}
+	atLimit := Usage{PeakMemoryBytes: 100*megabyte - 1024}
+
+	assert.Empty(t, BreachMessages(limits, belowLimit, belowLimit))
+	assert.Equal(t, []string{"Memory usage reached the worker memory limit of 100MB, allocations beyond it failed."},
+		BreachMessages(limits, belowLimit, atLimit))
+	// the peak is kept for the lifetime of the job, only the plugin reaching it reports the breach
+	assert.Empty(t, BreachMessages(limits, atLimit, atLimit))
+}
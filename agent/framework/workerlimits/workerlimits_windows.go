@@ -0,0 +1,100 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build windows
+// +build windows
+
+package workerlimits
+
+import (
+	"os/exec"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	jobObjectCpuRateControlEnable  = 0x1
+	jobObjectCpuRateControlHardCap = 0x4
+)
+
+// jobObjectCpuRateControlInformation is JOBOBJECT_CPU_RATE_CONTROL_INFORMATION with the CpuRate member of the union
+type jobObjectCpuRateControlInformation struct {
+	ControlFlags uint32
+	CpuRate      uint32
+}
+
+// jobObject is a job object created for a single worker process
+type jobObject struct {
+	handle windows.Handle
+}
+
+// Attach creates a job object enforcing the limits, the process is assigned to it once started
+func Attach(cmd *exec.Cmd, name string, limits Limits) (Group, error) {
+	handle, err := windows.CreateJobObject(nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	job := &jobObject{handle: handle}
+
+	if limits.MemoryMB > 0 {
+		var info windows.JOBOBJECT_EXTENDED_LIMIT_INFORMATION
+		info.BasicLimitInformation.LimitFlags = windows.JOB_OBJECT_LIMIT_JOB_MEMORY
+		info.JobMemoryLimit = uintptr(limits.MemoryMB) * megabyte
+		if _, err = windows.SetInformationJobObject(handle, windows.JobObjectExtendedLimitInformation,
+			uintptr(unsafe.Pointer(&info)), uint32(unsafe.Sizeof(info))); err != nil {
+			job.Close()
+			return nil, err
+		}
+	}
+	if limits.CpuPercent > 0 {
+		// CpuRate is the percentage of the processor cycles multiplied by 100
+		info := jobObjectCpuRateControlInformation{
+			ControlFlags: jobObjectCpuRateControlEnable | jobObjectCpuRateControlHardCap,
+			CpuRate:      uint32(limits.CpuPercent * 100),
+		}
+		if _, err = windows.SetInformationJobObject(handle, windows.JobObjectCpuRateControlInformation,
+			uintptr(unsafe.Pointer(&info)), uint32(unsafe.Sizeof(info))); err != nil {
+			job.Close()
+			return nil, err
+		}
+	}
+	return job, nil
+}
+
+// AfterStart assigns the started worker process to the job object
+func (job *jobObject) AfterStart(pid int) error {
+	process, err := windows.OpenProcess(windows.PROCESS_SET_QUOTA|windows.PROCESS_TERMINATE, false, uint32(pid))
+	if err != nil {
+		return err
+	}
+	defer windows.CloseHandle(process)
+	return windows.AssignProcessToJobObject(job.handle, process)
+}
+
+// Close releases the job object handle, the job does not kill its processes on close
+func (job *jobObject) Close() error {
+	return windows.CloseHandle(job.handle)
+}
+
+// readCurrentUsage reads the peak memory of the job object the current process belongs to
+func readCurrentUsage() (usage Usage, err error) {
+	var info windows.JOBOBJECT_EXTENDED_LIMIT_INFORMATION
+	// a null handle queries the job of the calling process
+	if err = windows.QueryInformationJobObject(0, windows.JobObjectExtendedLimitInformation,
+		uintptr(unsafe.Pointer(&info)), uint32(unsafe.Sizeof(info)), nil); err != nil {
+		return usage, err
+	}
+	usage.PeakMemoryBytes = uint64(info.PeakJobMemoryUsed)
+	return usage, nil
+}
@@ -0,0 +1,178 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package workersandbox restricts the syscalls and file system access of document and session worker processes
+// according to the plugins they run.
+package workersandbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+)
+
+// EnvironmentVariable passes the sandbox of a worker process from the agent to the worker
+const EnvironmentVariable = "SSM_WORKER_SANDBOX"
+
+// Profiles ordered from the most to the least restrictive
+const (
+	// ProfileRestricted is used by workers which only read the system and write to the agent directories
+	ProfileRestricted = "restricted"
+	// ProfileShell is used by workers running commands and sessions
+	ProfileShell = "shell"
+	// ProfileSystem is used by workers installing software or changing the system configuration
+	ProfileSystem = "system"
+)
+
+// Sandbox describes the restrictions applied to a worker process
+type Sandbox struct {
+	Profile string
+	// Seccomp makes the worker install a filter denying DeniedSyscalls
+	Seccomp        bool
+	DeniedSyscalls []string
+	AppArmor       bool
+	SELinuxType    string
+}
+
+var restrictedPlugins = map[string]bool{
+	appconfig.PluginNameAwsSoftwareInventory: true,
+	appconfig.PluginNameRefreshAssociation:   true,
+}
+
+var shellPlugins = map[string]bool{
+	appconfig.PluginNameAwsRunShellScript:      true,
+	appconfig.PluginNameAwsRunPowerShellScript: true,
+	// the destination of downloaded content can be anywhere on the file system
+	appconfig.PluginDownloadContent: true,
+}
+
+// syscalls denied by profile, the syscalls of a profile are also denied by the more restrictive profiles
+var (
+	systemDeniedSyscalls = []string{
+		"kexec_load", "kexec_file_load", "open_by_handle_at",
+	}
+	shellDeniedSyscalls = []string{
+		"init_module", "finit_module", "delete_module", "mount", "umount2", "pivot_root",
+		"swapon", "swapoff", "acct", "bpf", "userfaultfd",
+	}
+	restrictedDeniedSyscalls = []string{
+		"ptrace", "process_vm_readv", "process_vm_writev", "perf_event_open", "setns", "unshare",
+		"settimeofday", "clock_settime", "add_key", "request_key", "keyctl",
+	}
+)
+
+// ForWorker returns the sandbox of a worker running the given plugins
+func ForWorker(config appconfig.WorkerSandboxCfg, workerName string, pluginNames []string) Sandbox {
+	if !config.Enabled {
+		return Sandbox{}
+	}
+	profile := profileForPlugins(workerName, pluginNames)
+	return Sandbox{
+		Profile:        profile,
+		Seccomp:        config.Seccomp,
+		DeniedSyscalls: deniedSyscalls(profile, config.AdditionalDeniedSyscalls),
+		AppArmor:       config.AppArmor,
+		SELinuxType:    config.SELinuxType,
+	}
+}
+
+// profileForPlugins returns the most restrictive profile allowing all plugins, unknown plugins use the system profile
+func profileForPlugins(workerName string, pluginNames []string) string {
+	if workerName == appconfig.DefaultSessionWorker {
+		return ProfileShell
+	}
+	profile := ProfileRestricted
+	for _, pluginName := range pluginNames {
+		switch {
+		case restrictedPlugins[pluginName]:
+		case shellPlugins[pluginName]:
+			profile = ProfileShell
+		default:
+			return ProfileSystem
+		}
+	}
+	return profile
+}
+
+func deniedSyscalls(profile string, additional []string) []string {
+	denied := map[string]bool{}
+	add := func(names []string) {
+		for _, name := range names {
+			denied[name] = true
+		}
+	}
+	add(systemDeniedSyscalls)
+	if profile == ProfileShell || profile == ProfileRestricted {
+		add(shellDeniedSyscalls)
+	}
+	if profile == ProfileRestricted {
+		add(restrictedDeniedSyscalls)
+	}
+	add(additional)
+
+	result := make([]string, 0, len(denied))
+	for name := range denied {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// IsSet returns true if the worker runs in a sandbox
+func (sandbox Sandbox) IsSet() bool {
+	return sandbox.Profile != ""
+}
+
+// String describes the sandbox for logs
+func (sandbox Sandbox) String() string {
+	if !sandbox.IsSet() {
+		return "none"
+	}
+	parts := []string{"profile=" + sandbox.Profile}
+	if sandbox.Seccomp {
+		parts = append(parts, fmt.Sprintf("seccomp=%d syscalls", len(sandbox.DeniedSyscalls)))
+	}
+	if sandbox.SELinuxType != "" {
+		parts = append(parts, "selinux="+sandbox.SELinuxType)
+	} else if sandbox.AppArmor {
+		parts = append(parts, "apparmor="+AppArmorProfileName(sandbox.Profile))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Environment returns the environment variable telling the worker which sandbox to apply
+func (sandbox Sandbox) Environment() string {
+	content, _ := json.Marshal(sandbox)
+	return EnvironmentVariable + "=" + string(content)
+}
+
+// FromEnvironment returns the sandbox the current worker process was started in
+func FromEnvironment() (sandbox Sandbox, found bool) {
+	value := os.Getenv(EnvironmentVariable)
+	if value == "" {
+		return sandbox, false
+	}
+	if err := json.Unmarshal([]byte(value), &sandbox); err != nil {
+		return sandbox, false
+	}
+	return sandbox, sandbox.IsSet()
+}
+
+// AppArmorProfileName returns the name of the AppArmor profile generated for a sandbox profile
+func AppArmorProfileName(profile string) string {
+	return "amazon-ssm-agent-worker-" + profile
+}
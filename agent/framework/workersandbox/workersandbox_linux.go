@@ -0,0 +1,288 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build linux
+// +build linux
+
+package workersandbox
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/log/logger"
+	"golang.org/x/sys/unix"
+)
+
+// offsets in struct seccomp_data of linux/seccomp.h
+const (
+	seccompDataNrOffset   = 0
+	seccompDataArchOffset = 4
+
+	// syscalls of the x32 ABI have this bit set on amd64
+	x32SyscallBit = 0x40000000
+)
+
+var (
+	appArmorEnabledPath = "/sys/module/apparmor/parameters/enabled"
+	appArmorProfileDir  = func() string {
+		return filepath.Join(appconfig.DefaultDataStorePath, "sandbox")
+	}
+	lookPath   = exec.LookPath
+	runCommand = func(name string, args ...string) ([]byte, error) {
+		return exec.Command(name, args...).CombinedOutput()
+	}
+
+	appArmorLock           sync.Mutex
+	loadedAppArmorProfiles = map[string]string{}
+)
+
+var auditArchitectures = map[string]uint32{
+	"386":   unix.AUDIT_ARCH_I386,
+	"amd64": unix.AUDIT_ARCH_X86_64,
+	"arm":   unix.AUDIT_ARCH_ARM,
+	"arm64": unix.AUDIT_ARCH_AARCH64,
+}
+
+var syscallNumbers = map[string]uint32{
+	"acct":              unix.SYS_ACCT,
+	"add_key":           unix.SYS_ADD_KEY,
+	"bpf":               unix.SYS_BPF,
+	"clock_settime":     unix.SYS_CLOCK_SETTIME,
+	"delete_module":     unix.SYS_DELETE_MODULE,
+	"finit_module":      unix.SYS_FINIT_MODULE,
+	"init_module":       unix.SYS_INIT_MODULE,
+	"kexec_load":        unix.SYS_KEXEC_LOAD,
+	"keyctl":            unix.SYS_KEYCTL,
+	"mount":             unix.SYS_MOUNT,
+	"open_by_handle_at": unix.SYS_OPEN_BY_HANDLE_AT,
+	"perf_event_open":   unix.SYS_PERF_EVENT_OPEN,
+	"pivot_root":        unix.SYS_PIVOT_ROOT,
+	"process_vm_readv":  unix.SYS_PROCESS_VM_READV,
+	"process_vm_writev": unix.SYS_PROCESS_VM_WRITEV,
+	"ptrace":            unix.SYS_PTRACE,
+	"reboot":            unix.SYS_REBOOT,
+	"request_key":       unix.SYS_REQUEST_KEY,
+	"setns":             unix.SYS_SETNS,
+	"settimeofday":      unix.SYS_SETTIMEOFDAY,
+	"swapoff":           unix.SYS_SWAPOFF,
+	"swapon":            unix.SYS_SWAPON,
+	"umount2":           unix.SYS_UMOUNT2,
+	"unshare":           unix.SYS_UNSHARE,
+	"userfaultfd":       unix.SYS_USERFAULTFD,
+}
+
+// Wrap returns the command starting the worker under the SELinux type or the AppArmor profile of the sandbox
+func Wrap(log log.T, sandbox Sandbox, name string, argv []string) (string, []string, error) {
+	if sandbox.SELinuxType != "" {
+		runcon, err := lookPath("runcon")
+		if err != nil {
+			return name, argv, fmt.Errorf("runcon is not available: %v", err)
+		}
+		return runcon, append([]string{"-t", sandbox.SELinuxType, "--", name}, argv...), nil
+	}
+	// the system profile would allow all file access, there is nothing for AppArmor to restrict
+	if !sandbox.AppArmor || sandbox.Profile == ProfileSystem {
+		return name, argv, nil
+	}
+
+	profileName, err := loadAppArmorProfile(log, sandbox.Profile)
+	if err != nil {
+		return name, argv, err
+	}
+	aaExec, err := lookPath("aa-exec")
+	if err != nil {
+		return name, argv, fmt.Errorf("aa-exec is not available: %v", err)
+	}
+	return aaExec, append([]string{"-p", profileName, "--", name}, argv...), nil
+}
+
+// loadAppArmorProfile generates the AppArmor profile of a sandbox profile and loads it into the kernel,
+// the profile is loaded once per agent process
+func loadAppArmorProfile(log log.T, profile string) (string, error) {
+	name := AppArmorProfileName(profile)
+	content := appArmorProfile(name, profile)
+
+	appArmorLock.Lock()
+	defer appArmorLock.Unlock()
+	if loadedAppArmorProfiles[name] == content {
+		return name, nil
+	}
+
+	if enabled, err := os.ReadFile(appArmorEnabledPath); err != nil || strings.TrimSpace(string(enabled)) != "Y" {
+		return name, fmt.Errorf("AppArmor is not enabled")
+	}
+	parser, err := lookPath("apparmor_parser")
+	if err != nil {
+		return name, fmt.Errorf("apparmor_parser is not available: %v", err)
+	}
+
+	path := filepath.Join(appArmorProfileDir(), name)
+	if err = os.MkdirAll(filepath.Dir(path), appconfig.ReadWriteExecuteAccess); err != nil {
+		return name, err
+	}
+	if err = os.WriteFile(path, []byte(content), appconfig.ReadWriteAccess); err != nil {
+		return name, err
+	}
+	if output, err := runCommand(parser, "--replace", path); err != nil {
+		return name, fmt.Errorf("failed to load AppArmor profile %v: %v %s", name, err, strings.TrimSpace(string(output)))
+	}
+
+	loadedAppArmorProfiles[name] = content
+	log.Infof("Loaded AppArmor profile %v", name)
+	return name, nil
+}
+
+// appArmorProfile generates the AppArmor profile of a sandbox profile,
+// all profiles keep the worker from changing the agent and from loading kernel modules
+func appArmorProfile(name string, profile string) string {
+	rules := []string{
+		"capability,",
+		"network,",
+		"signal,",
+		"unix,",
+	}
+	if profile == ProfileRestricted {
+		rules = append(rules,
+			"/** rmix,",
+			"/dev/null rw,",
+			"/dev/zero rw,",
+			"/dev/tty rw,",
+			"/dev/pts/* rw,",
+			"@{PROC}/** r,",
+			"/tmp/** rwlk,",
+			"/var/tmp/** rwlk,",
+			filepath.Join(appconfig.DefaultDataStorePath, "**")+" rwlk,",
+			filepath.Join(logger.DefaultLogDir, "**")+" rwlk,",
+			"deny ptrace,",
+			"deny capability sys_boot,",
+		)
+	} else {
+		rules = append(rules, "file,", "ptrace,")
+	}
+	rules = append(rules,
+		"deny mount,",
+		"deny umount,",
+		"deny pivot_root,",
+		"deny capability sys_module,",
+		"deny @{PROC}/sysrq-trigger w,",
+		"deny /sys/kernel/security/** w,",
+		"deny "+filepath.Join(appconfig.DefaultProgramFolder, "**")+" wl,",
+		"deny "+appconfig.DefaultSSMAgentBinaryPath+" wl,",
+		"deny "+appconfig.DefaultSSMAgentWorker+" wl,",
+		"deny "+appconfig.DefaultDocumentWorker+" wl,",
+		"deny "+appconfig.DefaultSessionWorker+" wl,",
+	)
+
+	var content strings.Builder
+	content.WriteString("# Generated by the Amazon SSM Agent, changes are overwritten\n")
+	content.WriteString("#include <tunables/global>\n\n")
+	content.WriteString("profile " + name + " flags=(attach_disconnected) {\n")
+	content.WriteString("  #include <abstractions/base>\n\n")
+	for _, rule := range rules {
+		content.WriteString("  " + rule + "\n")
+	}
+	content.WriteString("}\n")
+	return content.String()
+}
+
+// ApplyFromEnvironment installs the seccomp filter of the sandbox the current worker process was started in
+func ApplyFromEnvironment(log log.T) {
+	sandbox, found := FromEnvironment()
+	if !found || !sandbox.Seccomp {
+		return
+	}
+	filter, err := seccompFilter(log, runtime.GOARCH, sandbox.DeniedSyscalls)
+	if err == nil {
+		err = installSeccompFilter(filter)
+	}
+	if err != nil {
+		log.Warnf("Failed to install seccomp filter of worker sandbox %v: %v", sandbox, err)
+		return
+	}
+	log.Infof("Installed seccomp filter of worker sandbox %v", sandbox)
+}
+
+// seccompFilter builds a BPF program returning EPERM for the denied syscalls and for syscalls of other architectures
+func seccompFilter(log log.T, goArch string, denied []string) ([]unix.SockFilter, error) {
+	arch, ok := auditArchitectures[goArch]
+	if !ok {
+		return nil, fmt.Errorf("seccomp filters are not supported on %v", goArch)
+	}
+
+	var numbers []uint32
+	for _, name := range denied {
+		if number, found := syscallNumbers[name]; found {
+			numbers = append(numbers, number)
+		} else if number, found = archSyscallNumbers[name]; found {
+			numbers = append(numbers, number)
+		} else {
+			log.Warnf("Unknown syscall %v is not denied by the seccomp filter", name)
+		}
+	}
+	if len(numbers) > 250 {
+		return nil, fmt.Errorf("too many denied syscalls: %v", len(numbers))
+	}
+
+	deny := bpfStatement(unix.BPF_RET|unix.BPF_K, unix.SECCOMP_RET_ERRNO|uint32(unix.EPERM))
+	filter := []unix.SockFilter{
+		bpfStatement(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, seccompDataArchOffset),
+		bpfJump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, arch, 1, 0),
+		deny,
+		bpfStatement(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, seccompDataNrOffset),
+	}
+	if goArch == "amd64" {
+		filter = append(filter, bpfJump(unix.BPF_JMP|unix.BPF_JGE|unix.BPF_K, x32SyscallBit, uint8(len(numbers)+1), 0))
+	}
+	for i, number := range numbers {
+		// jump over the remaining checks and the allow statement to the deny statement
+		filter = append(filter, bpfJump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, number, uint8(len(numbers)-i), 0))
+	}
+	filter = append(filter, bpfStatement(unix.BPF_RET|unix.BPF_K, unix.SECCOMP_RET_ALLOW), deny)
+	return filter, nil
+}
+
+func bpfStatement(code uint16, k uint32) unix.SockFilter {
+	return unix.SockFilter{Code: code, K: k}
+}
+
+func bpfJump(code uint16, k uint32, jumpTrue uint8, jumpFalse uint8) unix.SockFilter {
+	return unix.SockFilter{Code: code, Jt: jumpTrue, Jf: jumpFalse, K: k}
+}
+
+// installSeccompFilter applies the filter to all threads of the process and the processes it starts
+func installSeccompFilter(filter []unix.SockFilter) error {
+	// root may install filters without giving up privileges, which keeps setuid programs such as sudo working
+	if os.Geteuid() != 0 {
+		if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+			return fmt.Errorf("failed to set no_new_privs: %v", err)
+		}
+	}
+	program := unix.SockFprog{Len: uint16(len(filter)), Filter: &filter[0]}
+	thread, _, errno := unix.Syscall(unix.SYS_SECCOMP, unix.SECCOMP_SET_MODE_FILTER, unix.SECCOMP_FILTER_FLAG_TSYNC, uintptr(unsafe.Pointer(&program)))
+	if errno != 0 {
+		return errno
+	}
+	if thread != 0 {
+		return fmt.Errorf("thread %v could not be synchronized", thread)
+	}
+	return nil
+}
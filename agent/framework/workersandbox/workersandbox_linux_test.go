@@ -0,0 +1,126 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build linux
+// +build linux
+
+package workersandbox
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	logmocks "github.com/aws/amazon-ssm-agent/agent/mocks/log"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sys/unix"
+)
+
+func setupAppArmor(t *testing.T, enabled string) *[][]string {
+	dir := t.TempDir()
+	originalEnabledPath, originalProfileDir, originalLookPath, originalRunCommand := appArmorEnabledPath, appArmorProfileDir, lookPath, runCommand
+	t.Cleanup(func() {
+		appArmorEnabledPath, appArmorProfileDir, lookPath, runCommand = originalEnabledPath, originalProfileDir, originalLookPath, originalRunCommand
+		loadedAppArmorProfiles = map[string]string{}
+	})
+
+	appArmorEnabledPath = filepath.Join(dir, "enabled")
+	os.WriteFile(appArmorEnabledPath, []byte(enabled+"\n"), 0600)
+	appArmorProfileDir = func() string { return filepath.Join(dir, "profiles") }
+	lookPath = func(file string) (string, error) { return "/sbin/" + file, nil }
+	var commands [][]string
+	runCommand = func(name string, args ...string) ([]byte, error) {
+		commands = append(commands, append([]string{name}, args...))
+		return nil, nil
+	}
+	return &commands
+}
+
+func TestWrap_AppArmor(t *testing.T) {
+	commands := setupAppArmor(t, "Y")
+	sandbox := Sandbox{Profile: ProfileShell, AppArmor: true}
+
+	name, argv, err := Wrap(logmocks.NewMockLog(), sandbox, "/usr/bin/ssm-document-worker", []string{"document-id"})
+	assert.NoError(t, err)
+	assert.Equal(t, "/sbin/aa-exec", name)
+	assert.Equal(t, []string{"-p", "amazon-ssm-agent-worker-shell", "--", "/usr/bin/ssm-document-worker", "document-id"}, argv)
+
+	// the profile is loaded once
+	_, _, err = Wrap(logmocks.NewMockLog(), sandbox, "/usr/bin/ssm-document-worker", []string{"document-id"})
+	assert.NoError(t, err)
+	profilePath := filepath.Join(appArmorProfileDir(), "amazon-ssm-agent-worker-shell")
+	assert.Equal(t, [][]string{{"/sbin/apparmor_parser", "--replace", profilePath}}, *commands)
+	content, err := os.ReadFile(profilePath)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "profile amazon-ssm-agent-worker-shell flags=(attach_disconnected) {")
+}
+
+func TestWrap_AppArmorDisabled(t *testing.T) {
+	setupAppArmor(t, "N")
+	_, _, err := Wrap(logmocks.NewMockLog(), Sandbox{Profile: ProfileRestricted, AppArmor: true}, "worker", nil)
+	assert.Error(t, err)
+}
+
+func TestWrap_SystemProfileAndSELinux(t *testing.T) {
+	commands := setupAppArmor(t, "Y")
+
+	name, argv, err := Wrap(logmocks.NewMockLog(), Sandbox{Profile: ProfileSystem, AppArmor: true}, "worker", []string{"id"})
+	assert.NoError(t, err)
+	assert.Equal(t, "worker", name)
+	assert.Equal(t, []string{"id"}, argv)
+
+	name, argv, err = Wrap(logmocks.NewMockLog(), Sandbox{Profile: ProfileShell, AppArmor: true, SELinuxType: "ssm_worker_t"}, "worker", []string{"id"})
+	assert.NoError(t, err)
+	assert.Equal(t, "/sbin/runcon", name)
+	assert.Equal(t, []string{"-t", "ssm_worker_t", "--", "worker", "id"}, argv)
+	assert.Empty(t, *commands)
+
+	lookPath = func(file string) (string, error) { return "", fmt.Errorf("not found") }
+	_, _, err = Wrap(logmocks.NewMockLog(), Sandbox{Profile: ProfileShell, SELinuxType: "ssm_worker_t"}, "worker", nil)
+	assert.Error(t, err)
+}
+
+func TestAppArmorProfile_Restricted(t *testing.T) {
+	content := appArmorProfile("amazon-ssm-agent-worker-restricted", ProfileRestricted)
+	assert.Contains(t, content, "  /** rmix,\n")
+	assert.Contains(t, content, "  "+filepath.Join(appconfig.DefaultDataStorePath, "**")+" rwlk,\n")
+	assert.Contains(t, content, "  deny ptrace,\n")
+	assert.NotContains(t, content, "  file,\n")
+	assert.Contains(t, content, "  deny "+appconfig.DefaultSSMAgentBinaryPath+" wl,\n")
+}
+
+func TestSeccompFilter(t *testing.T) {
+	log := logmocks.NewMockLog()
+
+	_, err := seccompFilter(log, "s390x", []string{"mount"})
+	assert.Error(t, err)
+
+	filter, err := seccompFilter(log, "arm64", []string{"mount", "unknown_syscall", "bpf"})
+	assert.NoError(t, err)
+	assert.Len(t, filter, 8)
+	assert.Equal(t, uint32(unix.AUDIT_ARCH_AARCH64), filter[1].K)
+	// each denied syscall jumps to the deny statement at the end
+	assert.Equal(t, uint8(2), filter[4].Jt)
+	assert.Equal(t, uint8(1), filter[5].Jt)
+	assert.Equal(t, uint32(unix.SECCOMP_RET_ALLOW), filter[6].K)
+	assert.Equal(t, uint32(unix.SECCOMP_RET_ERRNO|uint32(unix.EPERM)), filter[7].K)
+
+	// amd64 also denies the syscalls of the x32 ABI
+	filter, err = seccompFilter(log, "amd64", []string{"mount"})
+	assert.NoError(t, err)
+	assert.Len(t, filter, 8)
+	assert.Equal(t, uint32(x32SyscallBit), filter[4].K)
+	assert.Equal(t, uint8(2), filter[4].Jt)
+}
@@ -0,0 +1,32 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build !linux
+// +build !linux
+
+package workersandbox
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-ssm-agent/agent/log"
+)
+
+// Wrap fails, sandboxes are only supported on Linux
+func Wrap(log log.T, sandbox Sandbox, name string, argv []string) (string, []string, error) {
+	return name, argv, fmt.Errorf("worker sandboxes are only supported on Linux")
+}
+
+// ApplyFromEnvironment does nothing, sandboxes are only supported on Linux
+func ApplyFromEnvironment(log log.T) {
+}
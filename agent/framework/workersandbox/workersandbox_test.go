@@ -0,0 +1,74 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package workersandbox
+
+import (
+	"os"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForWorker_Disabled(t *testing.T) {
+	sandbox := ForWorker(appconfig.WorkerSandboxCfg{Seccomp: true}, appconfig.DefaultDocumentWorker, nil)
+	assert.False(t, sandbox.IsSet())
+	assert.Equal(t, "none", sandbox.String())
+}
+
+func TestForWorker_Profiles(t *testing.T) {
+	config := appconfig.WorkerSandboxCfg{Enabled: true, Seccomp: true, AppArmor: true}
+
+	sandbox := ForWorker(config, appconfig.DefaultSessionWorker, nil)
+	assert.Equal(t, ProfileShell, sandbox.Profile)
+
+	sandbox = ForWorker(config, appconfig.DefaultDocumentWorker, []string{appconfig.PluginNameAwsSoftwareInventory})
+	assert.Equal(t, ProfileRestricted, sandbox.Profile)
+	assert.Contains(t, sandbox.DeniedSyscalls, "ptrace")
+	assert.Contains(t, sandbox.DeniedSyscalls, "init_module")
+
+	sandbox = ForWorker(config, appconfig.DefaultDocumentWorker,
+		[]string{appconfig.PluginNameAwsSoftwareInventory, appconfig.PluginNameAwsRunShellScript})
+	assert.Equal(t, ProfileShell, sandbox.Profile)
+	assert.NotContains(t, sandbox.DeniedSyscalls, "ptrace")
+	assert.Contains(t, sandbox.DeniedSyscalls, "mount")
+
+	sandbox = ForWorker(config, appconfig.DefaultDocumentWorker,
+		[]string{appconfig.PluginNameAwsRunShellScript, appconfig.PluginNameAwsConfigurePackage})
+	assert.Equal(t, ProfileSystem, sandbox.Profile)
+	assert.NotContains(t, sandbox.DeniedSyscalls, "mount")
+	assert.Contains(t, sandbox.DeniedSyscalls, "kexec_load")
+}
+
+func TestForWorker_AdditionalDeniedSyscalls(t *testing.T) {
+	config := appconfig.WorkerSandboxCfg{Enabled: true, Seccomp: true, AdditionalDeniedSyscalls: []string{"reboot", "kexec_load"}}
+	sandbox := ForWorker(config, appconfig.DefaultDocumentWorker, []string{appconfig.PluginNameAwsConfigurePackage})
+	assert.Equal(t, []string{"kexec_file_load", "kexec_load", "open_by_handle_at", "reboot"}, sandbox.DeniedSyscalls)
+}
+
+func TestEnvironmentRoundTrip(t *testing.T) {
+	_, found := FromEnvironment()
+	assert.False(t, found)
+
+	sandbox := ForWorker(appconfig.WorkerSandboxCfg{Enabled: true, Seccomp: true, SELinuxType: "ssm_worker_t"},
+		appconfig.DefaultSessionWorker, nil)
+	value := sandbox.Environment()[len(EnvironmentVariable)+1:]
+	os.Setenv(EnvironmentVariable, value)
+	defer os.Unsetenv(EnvironmentVariable)
+
+	actual, found := FromEnvironment()
+	assert.True(t, found)
+	assert.Equal(t, sandbox, actual)
+	assert.Contains(t, actual.String(), "selinux=ssm_worker_t")
+}
@@ -0,0 +1,76 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package health
+
+import (
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/platform"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ssm"
+)
+
+const (
+	// CloudProviderTypeName is the inventory type the cloud provider of hybrid instances is reported as
+	CloudProviderTypeName = "Custom:CloudProvider"
+	// cloudProviderSchemaVersion is the schema version of the cloud provider inventory type
+	cloudProviderSchemaVersion = "1.0"
+)
+
+var detectCloudProvider = platform.DetectCloudProvider
+
+// reportCloudProvider sends the cloud provider of a hybrid instance running outside AWS as inventory.
+// The provider is detected on the first health update, instances outside any known cloud report nothing
+func (h *HealthCheck) reportCloudProvider(log log.T) {
+	if !h.cloudProviderDetected {
+		if info, found := detectCloudProvider(log); found {
+			log.Infof("Instance runs in %v", info.Provider)
+			h.cloudProvider = &info
+		}
+		h.cloudProviderDetected = true
+	}
+	if h.cloudProvider == nil || h.cloudProviderReported {
+		return
+	}
+
+	instanceID, err := h.context.Identity().InstanceID()
+	if err != nil {
+		log.Warnf("Failed to get instance id, cloud provider is not reported: %v", err)
+		return
+	}
+
+	content := map[string]string{"Provider": h.cloudProvider.Provider}
+	for name, value := range map[string]string{
+		"InstanceId":       h.cloudProvider.InstanceId,
+		"InstanceType":     h.cloudProvider.InstanceType,
+		"Region":           h.cloudProvider.Region,
+		"AvailabilityZone": h.cloudProvider.AvailabilityZone,
+	} {
+		if value != "" {
+			content[name] = value
+		}
+	}
+	item := &ssm.InventoryItem{
+		TypeName:      aws.String(CloudProviderTypeName),
+		SchemaVersion: aws.String(cloudProviderSchemaVersion),
+		CaptureTime:   aws.String(time.Now().UTC().Format(time.RFC3339)),
+		Content:       []map[string]*string{aws.StringMap(content)},
+	}
+	if _, err = h.service.PutInventory(log, instanceID, []*ssm.InventoryItem{item}); err != nil {
+		log.Warnf("Failed to report cloud provider: %v", err)
+		return
+	}
+	h.cloudProviderReported = true
+}
@@ -0,0 +1,74 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package health
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	logmocks "github.com/aws/amazon-ssm-agent/agent/mocks/log"
+	"github.com/aws/amazon-ssm-agent/agent/platform"
+	ssmMock "github.com/aws/amazon-ssm-agent/agent/ssm/mocks"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setDetectedCloudProvider(t *testing.T, info platform.CloudProviderInfo, found bool) *int {
+	calls := 0
+	detectCloudProvider = func(log log.T) (platform.CloudProviderInfo, bool) {
+		calls++
+		return info, found
+	}
+	t.Cleanup(func() { detectCloudProvider = platform.DetectCloudProvider })
+	return &calls
+}
+
+func TestReportCloudProviderOnce(t *testing.T) {
+	detections := setDetectedCloudProvider(t, platform.CloudProviderInfo{Provider: platform.CloudProviderAzure, Region: "westeurope"}, true)
+	serviceMock := new(ssmMock.Service)
+	healthCheck := &HealthCheck{context: context.NewMockDefault(), service: serviceMock}
+
+	var reported []*ssm.InventoryItem
+	serviceMock.On("PutInventory", mock.Anything, mock.Anything, mock.Anything).Return(nil, fmt.Errorf("ThrottlingException")).Once()
+	serviceMock.On("PutInventory", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		reported = args.Get(2).([]*ssm.InventoryItem)
+	}).Return(&ssm.PutInventoryOutput{}, nil).Once()
+
+	healthCheck.reportCloudProvider(logmocks.NewMockLog())
+	healthCheck.reportCloudProvider(logmocks.NewMockLog())
+	healthCheck.reportCloudProvider(logmocks.NewMockLog())
+
+	assert.Equal(t, 1, *detections)
+	serviceMock.AssertNumberOfCalls(t, "PutInventory", 2)
+	assert.Len(t, reported, 1)
+	assert.Equal(t, CloudProviderTypeName, *reported[0].TypeName)
+	assert.Equal(t, platform.CloudProviderAzure, *reported[0].Content[0]["Provider"])
+	assert.Equal(t, "westeurope", *reported[0].Content[0]["Region"])
+	assert.NotContains(t, reported[0].Content[0], "InstanceType")
+}
+
+func TestReportCloudProviderNotDetected(t *testing.T) {
+	detections := setDetectedCloudProvider(t, platform.CloudProviderInfo{}, false)
+	serviceMock := new(ssmMock.Service)
+	healthCheck := &HealthCheck{context: context.NewMockDefault(), service: serviceMock}
+
+	healthCheck.reportCloudProvider(logmocks.NewMockLog())
+	healthCheck.reportCloudProvider(logmocks.NewMockLog())
+
+	assert.Equal(t, 1, *detections)
+	serviceMock.AssertNotCalled(t, "PutInventory", mock.Anything, mock.Anything, mock.Anything)
+}
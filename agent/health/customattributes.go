@@ -0,0 +1,129 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package health
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/custom"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ssm"
+)
+
+const (
+	// CustomAttributesTypeName is the inventory type the custom attributes of the instance are reported as
+	CustomAttributesTypeName = "Custom:InstanceAttributes"
+	// customAttributesSchemaVersion is the schema version of the custom attributes inventory type
+	customAttributesSchemaVersion = "1.0"
+	// customAttributesScriptMaxOutputSizeKB limits the output of the custom attributes script
+	customAttributesScriptMaxOutputSizeKB = 64
+)
+
+var runCustomAttributesScript = custom.RunScript
+
+// getCustomAttributes returns the static custom attributes overridden by the ones printed by the configured script,
+// attributes exceeding the custom inventory limits are dropped
+func getCustomAttributes(log log.T, config appconfig.CustomAttributesCfg) map[string]string {
+	attributes := make(map[string]string)
+	for name, value := range config.Attributes {
+		attributes[name] = value
+	}
+
+	if config.ScriptPath != "" {
+		output, err := runCustomAttributesScript(log, &appconfig.ScriptGathererCfg{
+			Path:            config.ScriptPath,
+			TypeName:        CustomAttributesTypeName,
+			SchemaVersion:   customAttributesSchemaVersion,
+			TimeoutSeconds:  config.ScriptTimeoutSeconds,
+			MaxOutputSizeKB: customAttributesScriptMaxOutputSizeKB,
+		})
+		var scriptAttributes map[string]string
+		if err == nil {
+			err = json.Unmarshal(output, &scriptAttributes)
+		}
+		if err != nil {
+			log.Warnf("Failed to get custom attributes from script %v: %v", config.ScriptPath, err)
+		}
+		for name, value := range scriptAttributes {
+			attributes[name] = value
+		}
+	}
+
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		switch {
+		case i >= custom.AttributeCountLimit:
+			log.Warnf("Dropping custom attribute %v, at most %v attributes are reported", name, custom.AttributeCountLimit)
+		case name == "" || len(name) > custom.AttributeNameLengthLimit:
+			log.Warnf("Dropping custom attribute %q, names must have 1 to %v characters", name, custom.AttributeNameLengthLimit)
+		case len(attributes[name]) > custom.AttributeValueLengthLimit:
+			log.Warnf("Dropping custom attribute %v, values must not exceed %v characters", name, custom.AttributeValueLengthLimit)
+		default:
+			continue
+		}
+		delete(attributes, name)
+	}
+	return attributes
+}
+
+// reportCustomAttributes sends the custom attributes of the instance as inventory when they changed since the last report,
+// the UpdateInstanceInformation API does not accept attributes of its own
+func (h *HealthCheck) reportCustomAttributes(log log.T, config appconfig.CustomAttributesCfg) {
+	if len(config.Attributes) == 0 && config.ScriptPath == "" {
+		return
+	}
+
+	attributes := getCustomAttributes(log, config)
+	content, err := json.Marshal([]map[string]string{attributes})
+	if err != nil {
+		log.Warnf("Failed to serialize custom attributes: %v", err)
+		return
+	}
+	checksum := md5.Sum(content)
+	contentHash := base64.StdEncoding.EncodeToString(checksum[:])
+	if contentHash == h.customAttributesHash {
+		log.Debugf("Custom attributes did not change since the last report")
+		return
+	}
+
+	instanceID, err := h.context.Identity().InstanceID()
+	if err != nil {
+		log.Warnf("Failed to get instance id, custom attributes are not reported: %v", err)
+		return
+	}
+
+	item := &ssm.InventoryItem{
+		TypeName:      aws.String(CustomAttributesTypeName),
+		SchemaVersion: aws.String(customAttributesSchemaVersion),
+		CaptureTime:   aws.String(time.Now().UTC().Format(time.RFC3339)),
+		Content:       []map[string]*string{aws.StringMap(attributes)},
+		ContentHash:   aws.String(contentHash),
+	}
+	if _, err = h.service.PutInventory(log, instanceID, []*ssm.InventoryItem{item}); err != nil {
+		log.Warnf("Failed to report custom attributes: %v", err)
+		return
+	}
+	log.Infof("Reported %v custom attributes", len(attributes))
+	h.customAttributesHash = contentHash
+}
@@ -0,0 +1,135 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package health
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	logmocks "github.com/aws/amazon-ssm-agent/agent/mocks/log"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/custom"
+	ssmMock "github.com/aws/amazon-ssm-agent/agent/ssm/mocks"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setScriptOutput(t *testing.T, output string, err error) {
+	runCustomAttributesScript = func(log log.T, config *appconfig.ScriptGathererCfg) ([]byte, error) {
+		return []byte(output), err
+	}
+	t.Cleanup(func() { runCustomAttributesScript = custom.RunScript })
+}
+
+func TestGetCustomAttributesStatic(t *testing.T) {
+	config := appconfig.CustomAttributesCfg{Attributes: map[string]string{"RackId": "r12", "CostCenter": "cc-42"}}
+
+	attributes := getCustomAttributes(logmocks.NewMockLog(), config)
+
+	assert.Equal(t, map[string]string{"RackId": "r12", "CostCenter": "cc-42"}, attributes)
+}
+
+func TestGetCustomAttributesScriptOverridesStatic(t *testing.T) {
+	setScriptOutput(t, `{"RackId": "r7", "PatchGroup": "canary"}`, nil)
+	config := appconfig.CustomAttributesCfg{
+		Attributes: map[string]string{"RackId": "r12", "CostCenter": "cc-42"},
+		ScriptPath: "/usr/local/bin/attributes",
+	}
+
+	attributes := getCustomAttributes(logmocks.NewMockLog(), config)
+
+	assert.Equal(t, map[string]string{"RackId": "r7", "CostCenter": "cc-42", "PatchGroup": "canary"}, attributes)
+}
+
+func TestGetCustomAttributesScriptFailureKeepsStatic(t *testing.T) {
+	for _, output := range []struct {
+		stdout string
+		err    error
+	}{
+		{"", fmt.Errorf("script timed out after 30 seconds")},
+		{"not json", nil},
+	} {
+		setScriptOutput(t, output.stdout, output.err)
+		config := appconfig.CustomAttributesCfg{
+			Attributes: map[string]string{"RackId": "r12"},
+			ScriptPath: "/usr/local/bin/attributes",
+		}
+
+		attributes := getCustomAttributes(logmocks.NewMockLog(), config)
+
+		assert.Equal(t, map[string]string{"RackId": "r12"}, attributes)
+	}
+}
+
+func TestGetCustomAttributesDropsInvalidAttributes(t *testing.T) {
+	config := appconfig.CustomAttributesCfg{Attributes: map[string]string{
+		"RackId":                "r12",
+		"":                      "empty",
+		strings.Repeat("n", 65): "long name",
+		"Description":           strings.Repeat("v", custom.AttributeValueLengthLimit+1),
+	}}
+
+	attributes := getCustomAttributes(logmocks.NewMockLog(), config)
+
+	assert.Equal(t, map[string]string{"RackId": "r12"}, attributes)
+}
+
+func TestReportCustomAttributesOnlyWhenChanged(t *testing.T) {
+	serviceMock := new(ssmMock.Service)
+	healthCheck := &HealthCheck{context: context.NewMockDefault(), service: serviceMock}
+	config := appconfig.CustomAttributesCfg{Attributes: map[string]string{"RackId": "r12"}}
+
+	var reported []*ssm.InventoryItem
+	serviceMock.On("PutInventory", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		reported = args.Get(2).([]*ssm.InventoryItem)
+	}).Return(&ssm.PutInventoryOutput{}, nil).Once()
+
+	healthCheck.reportCustomAttributes(logmocks.NewMockLog(), config)
+	healthCheck.reportCustomAttributes(logmocks.NewMockLog(), config)
+
+	serviceMock.AssertNumberOfCalls(t, "PutInventory", 1)
+	assert.Len(t, reported, 1)
+	assert.Equal(t, CustomAttributesTypeName, *reported[0].TypeName)
+	assert.Equal(t, "r12", *reported[0].Content[0]["RackId"])
+	assert.NotEmpty(t, healthCheck.customAttributesHash)
+}
+
+func TestReportCustomAttributesRetriesAfterFailure(t *testing.T) {
+	serviceMock := new(ssmMock.Service)
+	healthCheck := &HealthCheck{context: context.NewMockDefault(), service: serviceMock}
+	config := appconfig.CustomAttributesCfg{Attributes: map[string]string{"RackId": "r12"}}
+
+	serviceMock.On("PutInventory", mock.Anything, mock.Anything, mock.Anything).Return(nil, fmt.Errorf("AccessDeniedException")).Once()
+	serviceMock.On("PutInventory", mock.Anything, mock.Anything, mock.Anything).Return(&ssm.PutInventoryOutput{}, nil).Once()
+
+	healthCheck.reportCustomAttributes(logmocks.NewMockLog(), config)
+	assert.Empty(t, healthCheck.customAttributesHash)
+	healthCheck.reportCustomAttributes(logmocks.NewMockLog(), config)
+
+	serviceMock.AssertNumberOfCalls(t, "PutInventory", 2)
+	assert.NotEmpty(t, healthCheck.customAttributesHash)
+}
+
+func TestReportCustomAttributesNotConfigured(t *testing.T) {
+	serviceMock := new(ssmMock.Service)
+	healthCheck := &HealthCheck{context: context.NewMockDefault(), service: serviceMock}
+
+	healthCheck.reportCustomAttributes(logmocks.NewMockLog(), appconfig.CustomAttributesCfg{})
+
+	serviceMock.AssertNotCalled(t, "PutInventory", mock.Anything, mock.Anything, mock.Anything)
+}
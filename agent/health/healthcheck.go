@@ -15,6 +15,7 @@
 package health
 
 import (
+	"encoding/json"
 	"math/rand"
 	"runtime/debug"
 	"time"
@@ -22,9 +23,12 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
 	"github.com/aws/amazon-ssm-agent/agent/context"
 	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/metrics"
+	"github.com/aws/amazon-ssm-agent/agent/platform"
 	"github.com/aws/amazon-ssm-agent/agent/sdkutil"
 	"github.com/aws/amazon-ssm-agent/agent/ssm"
 	"github.com/aws/amazon-ssm-agent/agent/ssmconnectionchannel"
+	"github.com/aws/amazon-ssm-agent/agent/storeforward"
 	"github.com/aws/amazon-ssm-agent/agent/version"
 	"github.com/aws/amazon-ssm-agent/common/identity"
 	"github.com/aws/amazon-ssm-agent/common/identity/availableidentities/ec2"
@@ -47,6 +51,27 @@ type HealthCheck struct {
 	healthCheckStopPolicy *sdkutil.StopPolicy
 	healthJob             *scheduler.Job
 	service               ssm.Service
+	// pings that failed because the service could not be reached, nil if store and forward is disabled
+	pingQueue *storeforward.Queue
+	// content hash of the custom attributes last reported
+	customAttributesHash string
+	// cloud provider of hybrid instances, nil when the instance is not in a known cloud
+	cloudProvider         *platform.CloudProviderInfo
+	cloudProviderDetected bool
+	cloudProviderReported bool
+	// reachability of the service endpoints last reported and when it was reported
+	reachabilityState      string
+	reachabilityReportTime time.Time
+}
+
+// QueuedPing is a health ping that is sent once the service can be reached again
+type QueuedPing struct {
+	AgentVersion         string
+	AgentStatus          string
+	AgentName            string
+	AvailabilityZone     string
+	AvailabilityZoneId   string
+	SSMConnectionChannel string
 }
 
 const (
@@ -101,6 +126,10 @@ func NewHealthCheck(context context.T, svc ssm.Service) *HealthCheck {
 		healthCheckStopPolicy: healthCheckStopPolicy,
 		service:               svc,
 	}
+	if storeAndForward := context.AppConfig().StoreAndForward; storeAndForward.Enabled {
+		// only the latest ping is relevant
+		healthModule.pingQueue = storeforward.NewQueue(context.Identity(), storeforward.HealthQueueName, storeAndForward, 1)
+	}
 	return healthModule
 }
 
@@ -151,8 +180,33 @@ func (h *HealthCheck) updateHealth() {
 	var err error
 	//TODO when will status become inactive?
 	// If both ssm config and command is inactive => agent is inactive.
-	if _, err = h.service.UpdateInstanceInformation(log, version.Version, "Active", AgentName, availabilityZone, availabilityZoneId, ssmConnectionChannel); err != nil {
+	_, err = h.service.UpdateInstanceInformation(log, version.Version, "Active", AgentName, availabilityZone, availabilityZoneId, ssmConnectionChannel)
+	recordHealthPing(err)
+	if err != nil {
 		sdkutil.HandleAwsError(log, err, h.healthCheckStopPolicy)
+	} else {
+		h.reportCustomAttributes(log, appConfig.CustomAttributes)
+		h.reportReachability(log, appConfig.Reachability)
+		if isOnPrem {
+			h.reportCloudProvider(log)
+		}
+	}
+	if h.pingQueue != nil {
+		if err == nil {
+			h.pingQueue.Clear(log)
+		} else if storeforward.IsConnectivityError(err) {
+			ping := QueuedPing{
+				AgentVersion:         version.Version,
+				AgentStatus:          "Active",
+				AgentName:            AgentName,
+				AvailabilityZone:     availabilityZone,
+				AvailabilityZoneId:   availabilityZoneId,
+				SSMConnectionChannel: ssmConnectionChannel,
+			}
+			if queueErr := h.pingQueue.Enqueue(log, ping); queueErr != nil {
+				log.Warnf("Failed to queue health ping: %v", queueErr)
+			}
+		}
 	}
 
 	if !h.healthCheckStopPolicy.IsHealthy() {
@@ -232,12 +286,39 @@ func (h *HealthCheck) ping() (err error) {
 	}
 
 	_, err = h.service.UpdateEmptyInstanceInformation(h.context.Log(), version.Version, AgentName)
+	recordHealthPing(err)
 	if err != nil {
 		h.healthCheckStopPolicy.AddErrorCount(1)
 	}
 	return err
 }
 
+// NewQueuedPingSender returns the sender of the health pings queued while the service could not be reached
+func NewQueuedPingSender(context context.T) storeforward.Sender {
+	var service ssm.Service
+	return func(content []byte) (err error) {
+		var ping QueuedPing
+		if err = json.Unmarshal(content, &ping); err != nil {
+			context.Log().Warnf("Dropping invalid queued health ping: %v", err)
+			return nil
+		}
+		if service == nil {
+			service = ssm.NewService(context)
+		}
+		_, err = service.UpdateInstanceInformation(context.Log(), ping.AgentVersion, ping.AgentStatus, ping.AgentName, ping.AvailabilityZone, ping.AvailabilityZoneId, ping.SSMConnectionChannel)
+		recordHealthPing(err)
+		return err
+	}
+}
+
+// recordHealthPing updates the health ping metrics with the result of a ping
+func recordHealthPing(err error) {
+	metrics.HealthPings.Inc(metrics.ResultLabel(err))
+	if err == nil {
+		metrics.LastHealthPing.SetToCurrentTime()
+	}
+}
+
 // GetAgentState returns the state of the agent. It is the caller's responsibility to log the error
 func (h *HealthCheck) GetAgentState() (a AgentState, err error) {
 	if err = h.ping(); err != nil {
@@ -0,0 +1,90 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package health
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/reachability"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ssm"
+)
+
+const (
+	// ReachabilityTypeName is the inventory type the reachability of the service endpoints is reported as
+	ReachabilityTypeName = "Custom:NetworkReachability"
+	// reachabilitySchemaVersion is the schema version of the reachability inventory type
+	reachabilitySchemaVersion = "1.0"
+)
+
+var latestReachability = reachability.Latest
+
+// reportReachability sends the last probe results of the service endpoints as inventory. The summary is
+// sent once per report interval and as soon as an endpoint becomes reachable or unreachable
+func (h *HealthCheck) reportReachability(log log.T, config appconfig.ReachabilityCfg) {
+	if !config.Enabled {
+		return
+	}
+	results := latestReachability()
+	if len(results) == 0 {
+		return
+	}
+
+	states := make([]string, 0, len(results))
+	for _, result := range results {
+		states = append(states, result.Service+"="+strconv.FormatBool(result.Reachable))
+	}
+	sort.Strings(states)
+	state := strings.Join(states, ",")
+	reportInterval := time.Duration(config.ReportIntervalMinutes) * time.Minute
+	if state == h.reachabilityState && time.Since(h.reachabilityReportTime) < reportInterval {
+		return
+	}
+
+	instanceID, err := h.context.Identity().InstanceID()
+	if err != nil {
+		log.Warnf("Failed to get instance id, network reachability is not reported: %v", err)
+		return
+	}
+
+	content := make([]map[string]*string, 0, len(results))
+	for _, result := range results {
+		content = append(content, aws.StringMap(map[string]string{
+			"Service":             result.Service,
+			"Endpoint":            result.Endpoint,
+			"Reachable":           strconv.FormatBool(result.Reachable),
+			"LatencyMs":           strconv.FormatInt(result.LatencyMs, 10),
+			"ConsecutiveFailures": strconv.Itoa(result.ConsecutiveFailures),
+			"Error":               result.Error,
+			"LastProbe":           result.LastProbe.UTC().Format(time.RFC3339),
+		}))
+	}
+	item := &ssm.InventoryItem{
+		TypeName:      aws.String(ReachabilityTypeName),
+		SchemaVersion: aws.String(reachabilitySchemaVersion),
+		CaptureTime:   aws.String(time.Now().UTC().Format(time.RFC3339)),
+		Content:       content,
+	}
+	if _, err = h.service.PutInventory(log, instanceID, []*ssm.InventoryItem{item}); err != nil {
+		log.Warnf("Failed to report network reachability: %v", err)
+		return
+	}
+	h.reachabilityState = state
+	h.reachabilityReportTime = time.Now()
+}
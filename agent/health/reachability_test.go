@@ -0,0 +1,80 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package health
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	logmocks "github.com/aws/amazon-ssm-agent/agent/mocks/log"
+	"github.com/aws/amazon-ssm-agent/agent/reachability"
+	ssmMock "github.com/aws/amazon-ssm-agent/agent/ssm/mocks"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setLatestReachability(t *testing.T, results *[]reachability.Result) {
+	latestReachability = func() []reachability.Result { return *results }
+	t.Cleanup(func() { latestReachability = reachability.Latest })
+}
+
+func TestReportReachabilityOnChange(t *testing.T) {
+	results := []reachability.Result{
+		{Service: "kms", Endpoint: "kms.us-east-1.amazonaws.com", Reachable: false, ConsecutiveFailures: 2, Error: "i/o timeout", LastProbe: time.Now()},
+		{Service: "ssm", Endpoint: "ssm.us-east-1.amazonaws.com", Reachable: true, LatencyMs: 42, LastProbe: time.Now()},
+	}
+	setLatestReachability(t, &results)
+	config := appconfig.ReachabilityCfg{Enabled: true, ReportIntervalMinutes: 60}
+	serviceMock := new(ssmMock.Service)
+	healthCheck := &HealthCheck{context: context.NewMockDefault(), service: serviceMock}
+
+	var reported []*ssm.InventoryItem
+	serviceMock.On("PutInventory", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		reported = args.Get(2).([]*ssm.InventoryItem)
+	}).Return(&ssm.PutInventoryOutput{}, nil)
+
+	healthCheck.reportReachability(logmocks.NewMockLog(), config)
+	healthCheck.reportReachability(logmocks.NewMockLog(), config)
+	serviceMock.AssertNumberOfCalls(t, "PutInventory", 1)
+	assert.Equal(t, ReachabilityTypeName, *reported[0].TypeName)
+	assert.Len(t, reported[0].Content, 2)
+	assert.Equal(t, "false", *reported[0].Content[0]["Reachable"])
+	assert.Equal(t, "2", *reported[0].Content[0]["ConsecutiveFailures"])
+	assert.Equal(t, "42", *reported[0].Content[1]["LatencyMs"])
+
+	results[0].Reachable = true
+	healthCheck.reportReachability(logmocks.NewMockLog(), config)
+	serviceMock.AssertNumberOfCalls(t, "PutInventory", 2)
+	assert.Equal(t, "true", *reported[0].Content[0]["Reachable"])
+
+	healthCheck.reachabilityReportTime = time.Now().Add(-61 * time.Minute)
+	healthCheck.reportReachability(logmocks.NewMockLog(), config)
+	serviceMock.AssertNumberOfCalls(t, "PutInventory", 3)
+}
+
+func TestReportReachabilityDisabledOrNotProbed(t *testing.T) {
+	var results []reachability.Result
+	setLatestReachability(t, &results)
+	serviceMock := new(ssmMock.Service)
+	healthCheck := &HealthCheck{context: context.NewMockDefault(), service: serviceMock}
+
+	healthCheck.reportReachability(logmocks.NewMockLog(), appconfig.ReachabilityCfg{Enabled: true, ReportIntervalMinutes: 60})
+	results = []reachability.Result{{Service: "ssm", Reachable: true}}
+	healthCheck.reportReachability(logmocks.NewMockLog(), appconfig.ReachabilityCfg{Enabled: false, ReportIntervalMinutes: 60})
+
+	serviceMock.AssertNotCalled(t, "PutInventory", mock.Anything, mock.Anything, mock.Anything)
+}
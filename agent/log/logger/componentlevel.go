@@ -0,0 +1,91 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+)
+
+var exceptionsEndPattern = regexp.MustCompile(`</exceptions\s*>`)
+
+// componentFilePatterns maps the components of Agent.ComponentLogLevels to the source files logging for them
+var componentFilePatterns = map[string][]string{
+	appconfig.LogComponentMessageService: {"*/agent/messageservice/*"},
+	appconfig.LogComponentSession:        {"*/agent/session/*"},
+	appconfig.LogComponentUpdater:        {"*/agent/update/*", "*/agent/updateutil/*"},
+	appconfig.LogComponentIdentity:       {"*/common/identity/*"},
+}
+
+// getComponentLogLevels returns the component log levels from the agent configuration
+var getComponentLogLevels = func() map[string]string {
+	config, err := appconfig.Config(true)
+	if err != nil {
+		fmt.Println("Error occurred reading the component log levels: ", err)
+		return nil
+	}
+	return config.Agent.ComponentLogLevels
+}
+
+// ApplyComponentLogLevels adds a seelog exception per component so its source files log at the component level
+// instead of the level of the root seelog element. Exceptions already in the configuration take precedence.
+func ApplyComponentLogLevels(logConfigBytes []byte, componentLevels map[string]string) []byte {
+	components := make([]string, 0, len(componentLevels))
+	for component := range componentLevels {
+		if _, found := componentFilePatterns[component]; found && ValidateLogLevel(componentLevels[component]) == nil {
+			components = append(components, component)
+		}
+	}
+	if len(components) == 0 {
+		return logConfigBytes
+	}
+	sort.Strings(components)
+
+	var exceptions bytes.Buffer
+	for _, component := range components {
+		for _, filePattern := range componentFilePatterns[component] {
+			exceptions.WriteString(`<exception filepattern="` + filePattern + `" minlevel="` + componentLevels[component] + `"/>`)
+		}
+	}
+
+	// seelog uses the first matching exception, so component exceptions go after the configured ones
+	if location := exceptionsEndPattern.FindIndex(logConfigBytes); location != nil {
+		updated := append([]byte{}, logConfigBytes[:location[0]]...)
+		updated = append(updated, exceptions.Bytes()...)
+		return append(updated, logConfigBytes[location[0]:]...)
+	}
+	if location := seelogElementPattern.FindIndex(logConfigBytes); location != nil {
+		elementEnd := location[1]
+		selfClosing := bytes.HasSuffix(logConfigBytes[location[0]:elementEnd], []byte("/>"))
+		if selfClosing {
+			elementEnd -= len("/>")
+		}
+		updated := append([]byte{}, logConfigBytes[:elementEnd]...)
+		if selfClosing {
+			updated = append(updated, '>')
+		}
+		updated = append(updated, "<exceptions>"...)
+		updated = append(updated, exceptions.Bytes()...)
+		updated = append(updated, "</exceptions>"...)
+		if selfClosing {
+			updated = append(updated, "</seelog>"...)
+		}
+		return append(updated, logConfigBytes[location[1]:]...)
+	}
+	return logConfigBytes
+}
@@ -0,0 +1,101 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/cihub/seelog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyComponentLogLevels(t *testing.T) {
+	config := `<seelog minlevel="info">
+    <exceptions>
+        <exception filepattern="test*" minlevel="error"/>
+    </exceptions>
+</seelog>`
+	updated := string(ApplyComponentLogLevels([]byte(config), map[string]string{
+		appconfig.LogComponentUpdater:  seelog.TraceStr,
+		appconfig.LogComponentSession:  seelog.DebugStr,
+		"unknown":                      seelog.DebugStr,
+		appconfig.LogComponentIdentity: "verbose",
+	}))
+
+	assert.Equal(t, `<seelog minlevel="info">
+    <exceptions>
+        <exception filepattern="test*" minlevel="error"/>
+    <exception filepattern="*/agent/session/*" minlevel="debug"/>`+
+		`<exception filepattern="*/agent/update/*" minlevel="trace"/>`+
+		`<exception filepattern="*/agent/updateutil/*" minlevel="trace"/></exceptions>
+</seelog>`, updated)
+}
+
+func TestApplyComponentLogLevels_WithoutExceptions(t *testing.T) {
+	levels := map[string]string{appconfig.LogComponentMessageService: seelog.DebugStr}
+	exceptions := `<exceptions><exception filepattern="*/agent/messageservice/*" minlevel="debug"/></exceptions>`
+
+	updated := string(ApplyComponentLogLevels([]byte(`<seelog minlevel="info"><outputs><console/></outputs></seelog>`), levels))
+	assert.Equal(t, `<seelog minlevel="info">`+exceptions+`<outputs><console/></outputs></seelog>`, updated)
+
+	updated = string(ApplyComponentLogLevels([]byte(`<seelog minlevel="info"/>`), levels))
+	assert.Equal(t, `<seelog minlevel="info">`+exceptions+`</seelog>`, updated)
+
+	assert.Equal(t, "<seelog/>", string(ApplyComponentLogLevels([]byte("<seelog/>"), nil)))
+}
+
+func TestApplyComponentLogLevels_DefaultConfigParses(t *testing.T) {
+	levels := map[string]string{appconfig.LogComponentSession: seelog.DebugStr, appconfig.LogComponentIdentity: seelog.WarnStr}
+	_, err := seelog.LoggerFromConfigAsBytes(ApplyComponentLogLevels(DefaultConfig(), levels))
+	assert.NoError(t, err)
+}
+
+func TestApplyComponentLogLevels_LogsComponentAtItsLevel(t *testing.T) {
+	componentFilePatterns["logger"] = []string{"*/agent/log/logger/*"}
+	defer delete(componentFilePatterns, "logger")
+
+	logPath := filepath.Join(t.TempDir(), "component.log")
+	config := `<seelog minlevel="info"><outputs formatid="msg"><file path="` + logPath + `"/></outputs>` +
+		`<formats><format id="msg" format="%Msg%n"/></formats></seelog>`
+
+	logger, err := seelog.LoggerFromConfigAsBytes(ApplyComponentLogLevels([]byte(config), map[string]string{"logger": seelog.DebugStr}))
+	assert.NoError(t, err)
+	logger.Debug("component debug")
+	logger.Trace("component trace")
+	logger.Close()
+
+	content, err := os.ReadFile(logPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "component debug\n", string(content))
+}
+
+func TestGetLogConfigBytes_OverrideTakesPrecedence(t *testing.T) {
+	useTempSeelogConfigPath(t)
+	originalGetComponentLogLevels := getComponentLogLevels
+	getComponentLogLevels = func() map[string]string {
+		return map[string]string{appconfig.LogComponentSession: seelog.DebugStr}
+	}
+	defer func() { getComponentLogLevels = originalGetComponentLogLevels }()
+
+	config := []byte(`<seelog minlevel="info"><outputs><console/></outputs></seelog>`)
+	assert.Contains(t, string(applyLogLevelOverride(config)), `<exception filepattern="*/agent/session/*" minlevel="debug"/>`)
+
+	assert.NoError(t, WriteLogLevelOverride(LogLevelOverride{Level: seelog.TraceStr}))
+	updated := string(applyLogLevelOverride(config))
+	assert.Contains(t, updated, `<seelog minlevel="trace">`)
+	assert.NotContains(t, updated, "exception")
+}
@@ -17,8 +17,7 @@
 package logger
 
 import (
-	"path/filepath"
-
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
 	"github.com/cihub/seelog"
 )
 
@@ -27,34 +26,10 @@ func DefaultConfig() []byte {
 }
 
 func LoadLog(defaultLogDir string, logFile string, debugStatus string) []byte {
-	var logFilePath, errorFilePath string
-
-	logFilePath = filepath.Join(defaultLogDir, logFile)
-	errorFilePath = filepath.Join(defaultLogDir, ErrorFile)
-
-	logConfig := `
-<seelog type="adaptive" mininterval="2000000" maxinterval="100000000" critmsgcount="500" minlevel="` + debugStatus + `">
-    <exceptions>
-        <exception filepattern="test*" minlevel="error"/>
-    </exceptions>
-    <outputs formatid="fmtinfo">
-        <console formatid="fmtinfo"/>
-
-        `
-	logConfig += `<rollingfile type="size" filename="` + logFilePath + `" maxsize="30000000" maxrolls="5"/>`
-	logConfig += `
-		<filter levels="error,critical" formatid="fmterror">
-		`
-	logConfig += `<rollingfile type="size" filename="` + errorFilePath + `" maxsize="10000000" maxrolls="5"/>`
-	logConfig += `
-        </filter>
-    </outputs>
-    <formats>
-        <format id="fmterror" format="%Date(2006-01-02 15:04:05.0000) %LEVEL [%FuncShort @ %File.%Line] %Msg%n"/>
-        <format id="fmtdebug" format="%Date(2006-01-02 15:04:05.0000) %LEVEL [%FuncShort @ %File.%Line] %Msg%n"/>
-        <format id="fmtinfo" format="%Date(2006-01-02 15:04:05.0000) %LEVEL %Msg%n"/>
-    </formats>
-</seelog>
-`
-	return []byte(logConfig)
+	return GenerateSeelogConfig(defaultLogDir, logFile, appconfig.LoggingCfg{
+		Level:         debugStatus,
+		MaxFileSizeMB: appconfig.DefaultLoggingMaxFileSizeMB,
+		MaxRolls:      appconfig.DefaultLoggingMaxRolls,
+		Format:        appconfig.LoggingFormatText,
+	})
 }
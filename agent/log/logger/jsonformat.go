@@ -0,0 +1,151 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package logger
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cihub/seelog"
+)
+
+const (
+	// JSONFormatterName is the seelog custom formatter that renders a log message as a single JSON line.
+	// It can be referenced from a seelog format as %SSMJson.
+	JSONFormatterName = "SSMJson"
+
+	// JSONFormatID is the id of the JSON format defined in the default seelog configuration.
+	JSONFormatID = "fmtjson"
+
+	jsonTimestampFormat = "2006-01-02T15:04:05.000Z07:00"
+	sessionWorkerName   = "ssm-session-worker"
+)
+
+// commandIdPattern matches the uuid used for run command ids
+var commandIdPattern = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+
+func init() {
+	// registration only fails when the name is already taken, which is fine for repeated package loads
+	_ = seelog.RegisterCustomFormatter(JSONFormatterName, createJSONFormatterFunc)
+}
+
+// JSONLogEntry is the structure written for every log message when the JSON format is used.
+type JSONLogEntry struct {
+	Timestamp     string   `json:"timestamp"`
+	Level         string   `json:"level"`
+	Component     string   `json:"component,omitempty"`
+	Context       []string `json:"context,omitempty"`
+	CommandId     string   `json:"commandId,omitempty"`
+	SessionId     string   `json:"sessionId,omitempty"`
+	AssociationId string   `json:"associationId,omitempty"`
+	PluginName    string   `json:"pluginName,omitempty"`
+	InstanceId    string   `json:"instanceId,omitempty"`
+	Message       string   `json:"message"`
+}
+
+// createJSONFormatterFunc creates the seelog formatter function for the JSON format.
+func createJSONFormatterFunc(param string) seelog.FormatterFunc {
+	return func(message string, level seelog.LogLevel, context seelog.LogContextInterface) interface{} {
+		return FormatJSON(message, level, context.CallTime())
+	}
+}
+
+// NewJSONLogEntry parses a log message into a structured entry.
+// The context prefixes added by ContextFormatFilter ([component] [key=value] ...) are lifted into their own fields.
+func NewJSONLogEntry(message string, level seelog.LogLevel, callTime time.Time) JSONLogEntry {
+	entry := JSONLogEntry{
+		Timestamp: callTime.UTC().Format(jsonTimestampFormat),
+		Level:     strings.ToUpper(level.String()),
+	}
+	contexts, remaining := splitContext(message)
+	for _, ctx := range contexts {
+		entry.addContext(ctx)
+	}
+	entry.Message = remaining
+	return entry
+}
+
+// FormatJSON converts a log message into a JSON line.
+func FormatJSON(message string, level seelog.LogLevel, callTime time.Time) string {
+	entry := NewJSONLogEntry(message, level, callTime)
+	bytes, err := json.Marshal(entry)
+	if err != nil {
+		// all fields are plain strings, this should never happen
+		return message
+	}
+	return string(bytes)
+}
+
+// addContext assigns one context prefix to the matching field of the entry
+func (entry *JSONLogEntry) addContext(ctx string) {
+	if key, value, found := strings.Cut(ctx, "="); found {
+		switch key {
+		case "messageID":
+			entry.CommandId = getCommandId(value)
+			return
+		case "associationId":
+			entry.AssociationId = value
+			return
+		case "pluginName":
+			entry.PluginName = value
+			return
+		case "instanceID":
+			entry.InstanceId = value
+			return
+		case "sessionId":
+			entry.SessionId = value
+			return
+		}
+	}
+
+	switch {
+	case entry.Component == "":
+		entry.Component = ctx
+	case entry.Component == sessionWorkerName && entry.SessionId == "":
+		// the session worker uses the session id as its second context
+		entry.SessionId = ctx
+	case entry.CommandId == "" && commandIdPattern.MatchString(ctx):
+		entry.CommandId = commandIdPattern.FindString(ctx)
+	default:
+		entry.Context = append(entry.Context, ctx)
+	}
+}
+
+// getCommandId extracts the command id from a message id (aws.ssm.<commandId>.<instanceId>)
+func getCommandId(messageID string) string {
+	if commandId := commandIdPattern.FindString(messageID); commandId != "" {
+		return commandId
+	}
+	return messageID
+}
+
+// splitContext separates the leading [context] tokens from the rest of the message
+func splitContext(message string) (contexts []string, remaining string) {
+	remaining = message
+	for strings.HasPrefix(remaining, "[") {
+		end := strings.Index(remaining, "]")
+		if end < 0 {
+			break
+		}
+		ctx := remaining[1:end]
+		if ctx == "" || strings.ContainsAny(ctx, " \n") {
+			break
+		}
+		contexts = append(contexts, ctx)
+		remaining = strings.TrimLeft(remaining[end+1:], " ")
+	}
+	return contexts, strings.TrimRight(remaining, "\n")
+}
@@ -0,0 +1,96 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/cihub/seelog"
+	"github.com/stretchr/testify/assert"
+)
+
+var testCallTime = time.Date(2024, 3, 1, 10, 20, 30, 123000000, time.UTC)
+
+func TestFormatJSON_CommandContext(t *testing.T) {
+	message := "[ssm-agent-worker] [MessageService] [MDSInteractor] [messageID=aws.ssm.1c2b3a4d-1111-2222-3333-444455556666.i-0123456789abcdef0] Processing message"
+	output := FormatJSON(message, seelog.InfoLvl, testCallTime)
+
+	var entry JSONLogEntry
+	assert.NoError(t, json.Unmarshal([]byte(output), &entry))
+	assert.Equal(t, "2024-03-01T10:20:30.123Z", entry.Timestamp)
+	assert.Equal(t, "INFO", entry.Level)
+	assert.Equal(t, "ssm-agent-worker", entry.Component)
+	assert.Equal(t, []string{"MessageService", "MDSInteractor"}, entry.Context)
+	assert.Equal(t, "1c2b3a4d-1111-2222-3333-444455556666", entry.CommandId)
+	assert.Equal(t, "Processing message", entry.Message)
+}
+
+func TestFormatJSON_DocumentWorker(t *testing.T) {
+	message := "[ssm-document-worker] [1c2b3a4d-1111-2222-3333-444455556666] [pluginName=aws:runShellScript] done"
+	output := FormatJSON(message, seelog.ErrorLvl, testCallTime)
+
+	var entry JSONLogEntry
+	assert.NoError(t, json.Unmarshal([]byte(output), &entry))
+	assert.Equal(t, "ERROR", entry.Level)
+	assert.Equal(t, "ssm-document-worker", entry.Component)
+	assert.Equal(t, "1c2b3a4d-1111-2222-3333-444455556666", entry.CommandId)
+	assert.Equal(t, "aws:runShellScript", entry.PluginName)
+	assert.Empty(t, entry.Context)
+}
+
+func TestFormatJSON_SessionWorker(t *testing.T) {
+	message := "[ssm-session-worker] [user-0a1b2c3d4e5f67890] [DataBackend] channel opened"
+	output := FormatJSON(message, seelog.DebugLvl, testCallTime)
+
+	var entry JSONLogEntry
+	assert.NoError(t, json.Unmarshal([]byte(output), &entry))
+	assert.Equal(t, "ssm-session-worker", entry.Component)
+	assert.Equal(t, "user-0a1b2c3d4e5f67890", entry.SessionId)
+	assert.Equal(t, []string{"DataBackend"}, entry.Context)
+	assert.Empty(t, entry.CommandId)
+}
+
+func TestFormatJSON_NoContext(t *testing.T) {
+	message := "[not a context] quote \" and\nnewline\n"
+	output := FormatJSON(message, seelog.WarnLvl, testCallTime)
+
+	var entry JSONLogEntry
+	assert.NoError(t, json.Unmarshal([]byte(output), &entry))
+	assert.Empty(t, entry.Component)
+	assert.Equal(t, "[not a context] quote \" and\nnewline", entry.Message)
+	assert.NotContains(t, output, "\n")
+}
+
+func TestJSONFormatterRegistered(t *testing.T) {
+	var buffer bytes.Buffer
+	seelogger, err := seelog.LoggerFromWriterWithMinLevelAndFormat(&buffer, seelog.InfoLvl, "%"+JSONFormatterName+"%n")
+	assert.NoError(t, err)
+
+	seelogger.Info("[Registrar] registered")
+	seelogger.Flush()
+
+	var entry JSONLogEntry
+	assert.NoError(t, json.Unmarshal(buffer.Bytes(), &entry))
+	assert.Equal(t, "Registrar", entry.Component)
+	assert.Equal(t, "registered", entry.Message)
+}
+
+func TestDefaultConfigDefinesJSONFormat(t *testing.T) {
+	_, err := seelog.LoggerFromConfigAsBytes(DefaultConfig())
+	assert.NoError(t, err)
+	assert.Contains(t, string(DefaultConfig()), `id="`+JSONFormatID+`"`)
+}
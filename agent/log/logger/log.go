@@ -89,6 +89,7 @@ func (f ContextFormatFilter) Filterf(format string, params ...interface{}) (newF
 	return
 }
 
+// GetLogConfigBytes returns the seelog configuration with the runtime log level override applied
 func GetLogConfigBytes() []byte {
-	return getLogConfigBytes()
+	return applyLogLevelOverride(getLogConfigBytes())
 }
@@ -30,6 +30,9 @@ var (
 	DefaultSeelogConfigFilePath = appconfig.DefaultProgramFolder + appconfig.SeelogConfigFileName
 
 	DefaultLogDir = "/var/log/amazon/ssm"
+
+	// generatedLogFileName is the agent log of the seelog configuration generated from the logging options
+	generatedLogFileName = LogFile
 )
 
 // getLogConfigBytes reads and returns the seelog configs from the config file path if present
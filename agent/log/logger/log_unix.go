@@ -25,6 +25,9 @@ import (
 
 var (
 	DefaultLogDir = "/var/log/amazon/ssm"
+
+	// generatedLogFileName is the agent log of the seelog configuration generated from the logging options
+	generatedLogFileName = LogFile
 )
 
 // DefaultSeelogConfigFilePath specifies the default seelog location
@@ -30,6 +30,10 @@ import (
 var DefaultLogDir = filepath.Join(appconfig.SSMDataPath, "Logs")
 var ExeNamePlaceHolder = "{{EXECUTABLENAME}}"
 
+// generatedLogFileName is the agent log of the seelog configuration generated from the logging options,
+// each executable writes its own log to avoid file contention
+var generatedLogFileName = ExeNamePlaceHolder + ".log"
+
 // The underlying logger is based of https://github.com/cihub/seelog
 // See Seelog documentation to customize the logger
 var DefaultSeelogConfigFilePath = appconfig.SeelogFilePath
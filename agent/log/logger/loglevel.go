@@ -0,0 +1,126 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/cihub/seelog"
+)
+
+// LogLevelOverrideFileName is the file, next to seelog.xml, holding a temporary log level set at runtime
+const LogLevelOverrideFileName = "seelog_level_override.json"
+
+var (
+	seelogElementPattern   = regexp.MustCompile(`<seelog\b[^>]*>`)
+	levelAttributesPattern = regexp.MustCompile(`\s+(minlevel|maxlevel|levels)="[^"]*"`)
+)
+
+// LogLevelOverride overrides the minimum level configured in seelog.xml until it expires
+type LogLevelOverride struct {
+	Level     string     `json:"Level"`
+	ExpiresAt *time.Time `json:"ExpiresAt,omitempty"`
+}
+
+// IsExpired returns true if the override has an expiration time in the past
+func (override *LogLevelOverride) IsExpired(now time.Time) bool {
+	return override.ExpiresAt != nil && !now.Before(*override.ExpiresAt)
+}
+
+// LogLevelOverrideFilePath returns the path of the log level override file
+func LogLevelOverrideFilePath() string {
+	return filepath.Join(filepath.Dir(DefaultSeelogConfigFilePath), LogLevelOverrideFileName)
+}
+
+// ValidateLogLevel returns an error if the level is not a seelog level
+func ValidateLogLevel(level string) error {
+	if _, found := seelog.LogLevelFromString(level); !found || level == seelog.OffStr {
+		return fmt.Errorf("invalid log level %v, expected one of trace, debug, info, warn, error, critical", level)
+	}
+	return nil
+}
+
+// ReadLogLevelOverride returns the active log level override, or nil if there is none or it has expired
+func ReadLogLevelOverride() (*LogLevelOverride, error) {
+	content, err := os.ReadFile(LogLevelOverrideFilePath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var override LogLevelOverride
+	if err = json.Unmarshal(content, &override); err != nil {
+		return nil, fmt.Errorf("invalid log level override: %v", err)
+	}
+	if err = ValidateLogLevel(override.Level); err != nil {
+		return nil, err
+	}
+	if override.IsExpired(time.Now()) {
+		return nil, nil
+	}
+	return &override, nil
+}
+
+// WriteLogLevelOverride saves the log level override, running agent processes pick it up from the config watcher
+func WriteLogLevelOverride(override LogLevelOverride) error {
+	if err := ValidateLogLevel(override.Level); err != nil {
+		return err
+	}
+	content, err := json.Marshal(override)
+	if err != nil {
+		return err
+	}
+
+	overridePath := LogLevelOverrideFilePath()
+	tempPath := overridePath + ".tmp"
+	if err = os.WriteFile(tempPath, content, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tempPath, overridePath)
+}
+
+// RemoveLogLevelOverride deletes the log level override, restoring the level from seelog.xml
+func RemoveLogLevelOverride() error {
+	if err := os.Remove(LogLevelOverrideFilePath()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// ApplyLogLevel replaces the level constraints of the root seelog element with the given minimum level
+func ApplyLogLevel(logConfigBytes []byte, level string) []byte {
+	return seelogElementPattern.ReplaceAllFunc(logConfigBytes, func(element []byte) []byte {
+		element = levelAttributesPattern.ReplaceAll(element, nil)
+		return append([]byte(`<seelog minlevel="`+level+`"`), element[len("<seelog"):]...)
+	})
+}
+
+// applyLogLevelOverride applies the active log level override, if any, to the seelog configuration.
+// Without an override the component log levels of the agent configuration are applied.
+func applyLogLevelOverride(logConfigBytes []byte) []byte {
+	override, err := ReadLogLevelOverride()
+	if err != nil {
+		fmt.Println("Error occurred reading the log level override: ", err)
+	}
+	if override == nil {
+		return ApplyComponentLogLevels(logConfigBytes, getComponentLogLevels())
+	}
+	return ApplyLogLevel(logConfigBytes, override.Level)
+}
@@ -0,0 +1,106 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cihub/seelog"
+	"github.com/stretchr/testify/assert"
+)
+
+func useTempSeelogConfigPath(t *testing.T) {
+	originalPath := DefaultSeelogConfigFilePath
+	DefaultSeelogConfigFilePath = filepath.Join(t.TempDir(), "seelog.xml")
+	t.Cleanup(func() { DefaultSeelogConfigFilePath = originalPath })
+}
+
+func TestApplyLogLevel(t *testing.T) {
+	config := `<seelog type="adaptive" minlevel="info" maxlevel="critical">
+    <exceptions>
+        <exception filepattern="test*" minlevel="error"/>
+    </exceptions>
+</seelog>`
+	updated := string(ApplyLogLevel([]byte(config), seelog.DebugStr))
+
+	assert.Contains(t, updated, `<seelog minlevel="debug" type="adaptive">`)
+	assert.Contains(t, updated, `<exception filepattern="test*" minlevel="error"/>`)
+	assert.NotContains(t, updated, "maxlevel")
+
+	updated = string(ApplyLogLevel([]byte(`<seelog levels="error,critical"/>`), seelog.TraceStr))
+	assert.Equal(t, `<seelog minlevel="trace"/>`, updated)
+}
+
+func TestApplyLogLevel_DefaultConfigParses(t *testing.T) {
+	_, err := seelog.LoggerFromConfigAsBytes(ApplyLogLevel(DefaultConfig(), seelog.TraceStr))
+	assert.NoError(t, err)
+}
+
+func TestLogLevelOverride_WriteReadRemove(t *testing.T) {
+	useTempSeelogConfigPath(t)
+
+	override, err := ReadLogLevelOverride()
+	assert.NoError(t, err)
+	assert.Nil(t, override)
+
+	expiresAt := time.Now().Add(time.Hour).UTC()
+	assert.NoError(t, WriteLogLevelOverride(LogLevelOverride{Level: seelog.DebugStr, ExpiresAt: &expiresAt}))
+	override, err = ReadLogLevelOverride()
+	assert.NoError(t, err)
+	assert.Equal(t, seelog.DebugStr, override.Level)
+	assert.True(t, expiresAt.Equal(*override.ExpiresAt))
+
+	assert.NoError(t, RemoveLogLevelOverride())
+	assert.NoError(t, RemoveLogLevelOverride())
+	override, err = ReadLogLevelOverride()
+	assert.NoError(t, err)
+	assert.Nil(t, override)
+}
+
+func TestLogLevelOverride_Expired(t *testing.T) {
+	useTempSeelogConfigPath(t)
+
+	expiresAt := time.Now().Add(-time.Minute)
+	assert.NoError(t, WriteLogLevelOverride(LogLevelOverride{Level: seelog.TraceStr, ExpiresAt: &expiresAt}))
+	override, err := ReadLogLevelOverride()
+	assert.NoError(t, err)
+	assert.Nil(t, override)
+
+	config := []byte(`<seelog minlevel="info"/>`)
+	assert.Equal(t, config, applyLogLevelOverride(config))
+}
+
+func TestLogLevelOverride_Invalid(t *testing.T) {
+	useTempSeelogConfigPath(t)
+
+	assert.Error(t, WriteLogLevelOverride(LogLevelOverride{Level: "verbose"}))
+	assert.Error(t, WriteLogLevelOverride(LogLevelOverride{Level: seelog.OffStr}))
+
+	assert.NoError(t, os.WriteFile(LogLevelOverrideFilePath(), []byte("not json"), 0600))
+	_, err := ReadLogLevelOverride()
+	assert.Error(t, err)
+
+	config := []byte(`<seelog minlevel="info"/>`)
+	assert.Equal(t, config, applyLogLevelOverride(config))
+}
+
+func TestApplyLogLevelOverride(t *testing.T) {
+	useTempSeelogConfigPath(t)
+
+	assert.NoError(t, WriteLogLevelOverride(LogLevelOverride{Level: seelog.DebugStr}))
+	assert.Equal(t, `<seelog minlevel="debug"/>`, string(applyLogLevelOverride([]byte(`<seelog minlevel="info"/>`))))
+}
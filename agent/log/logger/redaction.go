@@ -0,0 +1,133 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package logger
+
+import (
+	"fmt"
+	"regexp"
+	"sync/atomic"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+)
+
+const redactedValue = "<redacted>"
+
+type redactPattern struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// redactor masks the matches of its patterns in log messages
+type redactor struct {
+	patterns []redactPattern
+}
+
+// defaultRedactPatterns match AWS access keys, secret keys, tokens, bearer tokens and passwords, also in URLs.
+// Crash dumps are redacted with the same patterns.
+var defaultRedactPatterns = []redactPattern{
+	{regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`), "${1}XXXXXXXXXXXXXXXX"},
+	{regexp.MustCompile(`(?i)((?:aws_?)?secret_?access_?key|secret_?key|session_?token|token|password|passwd|pwd)(\\?"?\s*[:=]\s*\\?"?)[^\s"\\,}]+`), "${1}${2}" + redactedValue},
+	{regexp.MustCompile(`(?i)\b(bearer\s+)[a-z0-9\-._~+/]+=*`), "${1}" + redactedValue},
+	{regexp.MustCompile(`(?i)\b([a-z][a-z0-9+.\-]*://[^\s:/@]+:)[^\s/@]+@`), "${1}" + redactedValue + "@"},
+}
+
+// activeRedactor is applied to all log messages, redaction is disabled when it is nil
+var activeRedactor atomic.Pointer[redactor]
+
+// getRedactionConfig returns whether log redaction is enabled and the additional patterns from the agent configuration
+var getRedactionConfig = func() (bool, []string) {
+	config, err := appconfig.Config(true)
+	if err != nil {
+		fmt.Println("Error occurred reading the log redaction configuration: ", err)
+		return true, nil
+	}
+	return config.Agent.LogRedaction, config.Agent.LogRedactionPatterns
+}
+
+func init() {
+	SetRedaction(true, nil)
+}
+
+// LoadRedaction configures the redaction of log messages from the agent configuration
+func LoadRedaction() {
+	SetRedaction(getRedactionConfig())
+}
+
+// SetRedaction enables or disables the redaction of log messages.
+// The matches of the additional patterns are masked on top of the default patterns, invalid patterns are ignored.
+func SetRedaction(enabled bool, additionalPatterns []string) {
+	if !enabled {
+		activeRedactor.Store(nil)
+		return
+	}
+	patterns := append([]redactPattern{}, defaultRedactPatterns...)
+	for _, additionalPattern := range additionalPatterns {
+		pattern, err := regexp.Compile(additionalPattern)
+		if err != nil {
+			fmt.Println("Ignoring invalid log redaction pattern: ", err)
+			continue
+		}
+		patterns = append(patterns, redactPattern{pattern, redactedValue})
+	}
+	activeRedactor.Store(&redactor{patterns: patterns})
+}
+
+// Redact masks sensitive values in the message with the active redaction patterns
+func Redact(message string) string {
+	if r := activeRedactor.Load(); r != nil {
+		return r.redact(message)
+	}
+	return message
+}
+
+func (r *redactor) redact(message string) string {
+	for _, redact := range r.patterns {
+		message = redact.pattern.ReplaceAllString(message, redact.replacement)
+	}
+	return message
+}
+
+// redactedMessage formats and redacts a log message once the logger writes it,
+// so messages below the configured log level are never formatted
+type redactedMessage struct {
+	redactor  *redactor
+	format    string
+	formatted bool
+	params    []interface{}
+}
+
+func (m redactedMessage) String() string {
+	if m.formatted {
+		return m.redactor.redact(fmt.Sprintf(m.format, m.params...))
+	}
+	return m.redactor.redact(fmt.Sprint(m.params...))
+}
+
+// redactf replaces the format and parameters of a log message by a redacted message when redaction is enabled
+func redactf(format string, params []interface{}) (string, []interface{}) {
+	r := activeRedactor.Load()
+	if r == nil {
+		return format, params
+	}
+	return "%v", []interface{}{redactedMessage{redactor: r, format: format, formatted: true, params: params}}
+}
+
+// redact replaces the parameters of a log message by a redacted message when redaction is enabled
+func redact(params []interface{}) []interface{} {
+	r := activeRedactor.Load()
+	if r == nil {
+		return params
+	}
+	return []interface{}{redactedMessage{redactor: r, params: params}}
+}
@@ -0,0 +1,95 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+)
+
+// generatedSeelogHeader tells operators the file is generated from the agent configuration
+const generatedSeelogHeader = `<!--Generated from the Logging section of amazon-ssm-agent.json, run "ssm-cli config set Logging.<option> <value>" to change it-->
+`
+
+// GenerateSeelogConfig builds the seelog configuration of the logging options, the agent log is written to logFile
+// and the errors to the error log in logDir, both rotated by size
+func GenerateSeelogConfig(logDir string, logFile string, config appconfig.LoggingCfg) []byte {
+	maxSize := int64(config.MaxFileSizeMB) * 1000 * 1000
+	maxRolls := strconv.Itoa(config.MaxRolls)
+	outputFormatID, errorFormatID := "fmtinfo", "fmterror"
+	if config.Format == appconfig.LoggingFormatJSON {
+		outputFormatID, errorFormatID = JSONFormatID, JSONFormatID
+	}
+
+	var logConfig bytes.Buffer
+	logConfig.WriteString(`
+<seelog type="adaptive" mininterval="2000000" maxinterval="100000000" critmsgcount="500" minlevel="` + config.Level + `">
+    <exceptions>
+        <exception filepattern="test*" minlevel="error"/>
+    </exceptions>
+    <outputs formatid="` + outputFormatID + `">
+        <console formatid="fmtinfo"/>
+        <rollingfile type="size" filename="` + html.EscapeString(filepath.Join(logDir, logFile)) + `" maxsize="` + strconv.FormatInt(maxSize, 10) + `" maxrolls="` + maxRolls + `"/>
+        <filter levels="error,critical" formatid="` + errorFormatID + `">
+            <rollingfile type="size" filename="` + html.EscapeString(filepath.Join(logDir, ErrorFile)) + `" maxsize="` + strconv.FormatInt(maxSize/3, 10) + `" maxrolls="` + maxRolls + `"/>
+        </filter>
+    </outputs>
+    <formats>
+        <format id="fmterror" format="%Date(2006-01-02 15:04:05.0000) %LEVEL [%FuncShort @ %File.%Line] %Msg%n"/>
+        <format id="fmtdebug" format="%Date(2006-01-02 15:04:05.0000) %LEVEL [%FuncShort @ %File.%Line] %Msg%n"/>
+        <format id="fmtinfo" format="%Date(2006-01-02 15:04:05.0000) %LEVEL %Msg%n"/>
+        <format id="` + JSONFormatID + `" format="%` + JSONFormatterName + `%n"/>
+        <format id="fmtmsg" format="%Msg"/>
+    </formats>
+</seelog>
+`)
+	return logConfig.Bytes()
+}
+
+// WriteSeelogConfig replaces the seelog configuration at path with the configuration generated from the logging
+// options. The previous file is kept with the backup suffix of the agent configuration, running agent processes
+// pick up the new file from the config watcher.
+func WriteSeelogConfig(path string, config appconfig.LoggingCfg) (backupPath string, err error) {
+	generated := append([]byte(generatedSeelogHeader), GenerateSeelogConfig(DefaultLogDir, generatedLogFileName, config)...)
+
+	original, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	if bytes.Equal(original, generated) {
+		return "", nil
+	}
+	if original != nil {
+		backupPath = path + appconfig.ConfigBackupSuffix
+		if err = os.WriteFile(backupPath, original, appconfig.ReadWriteAccess); err != nil {
+			return "", fmt.Errorf("failed to back up %v: %v", path, err)
+		}
+	}
+
+	tempPath := path + ".tmp"
+	if err = os.WriteFile(tempPath, generated, appconfig.ReadWriteAccess); err != nil {
+		return backupPath, err
+	}
+	if err = os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return backupPath, err
+	}
+	return backupPath, nil
+}
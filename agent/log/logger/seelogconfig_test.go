@@ -0,0 +1,78 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/cihub/seelog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateSeelogConfig(t *testing.T) {
+	logDir := t.TempDir()
+	config := appconfig.LoggingCfg{Level: seelog.DebugStr, MaxFileSizeMB: 60, MaxRolls: 3, Format: appconfig.LoggingFormatText}
+
+	generated := GenerateSeelogConfig(logDir, LogFile, config)
+	assert.Contains(t, string(generated), `minlevel="debug"`)
+	assert.Contains(t, string(generated), `<outputs formatid="fmtinfo">`)
+	assert.Contains(t, string(generated), `filename="`+filepath.Join(logDir, LogFile)+`" maxsize="60000000" maxrolls="3"`)
+	assert.Contains(t, string(generated), `filename="`+filepath.Join(logDir, ErrorFile)+`" maxsize="20000000" maxrolls="3"`)
+
+	logger, err := seelog.LoggerFromConfigAsBytes(generated)
+	assert.NoError(t, err)
+	logger.Close()
+}
+
+func TestGenerateSeelogConfig_JSON(t *testing.T) {
+	config := appconfig.LoggingCfg{Level: seelog.InfoStr, MaxFileSizeMB: 30, MaxRolls: 5, Format: appconfig.LoggingFormatJSON}
+
+	generated := string(GenerateSeelogConfig(t.TempDir(), LogFile, config))
+	assert.Contains(t, generated, `<outputs formatid="`+JSONFormatID+`">`)
+	assert.Contains(t, generated, `<filter levels="error,critical" formatid="`+JSONFormatID+`">`)
+}
+
+func TestLoadLogMatchesDefaultLoggingConfig(t *testing.T) {
+	generated := string(LoadLog(DefaultLogDir, LogFile, seelog.InfoStr))
+	assert.Contains(t, generated, `maxsize="30000000" maxrolls="5"`)
+	assert.Contains(t, generated, `maxsize="10000000" maxrolls="5"`)
+}
+
+func TestWriteSeelogConfig(t *testing.T) {
+	seelogPath := filepath.Join(t.TempDir(), appconfig.SeelogConfigFileName)
+	assert.NoError(t, os.WriteFile(seelogPath, []byte("<seelog/>"), 0600))
+	config := appconfig.LoggingCfg{Level: seelog.WarnStr, MaxFileSizeMB: 10, MaxRolls: 2, Format: appconfig.LoggingFormatText}
+
+	backupPath, err := WriteSeelogConfig(seelogPath, config)
+	assert.NoError(t, err)
+	assert.Equal(t, seelogPath+appconfig.ConfigBackupSuffix, backupPath)
+	backup, _ := os.ReadFile(backupPath)
+	assert.Equal(t, "<seelog/>", string(backup))
+
+	written, err := os.ReadFile(seelogPath)
+	assert.NoError(t, err)
+	assert.Contains(t, string(written), generatedSeelogHeader)
+	assert.Contains(t, string(written), `minlevel="warn"`)
+	assert.Contains(t, string(written), `maxsize="10000000" maxrolls="2"`)
+
+	// writing the same options again keeps the backup of the hand-written file
+	backupPath, err = WriteSeelogConfig(seelogPath, config)
+	assert.NoError(t, err)
+	assert.Empty(t, backupPath)
+	backup, _ = os.ReadFile(seelogPath + appconfig.ConfigBackupSuffix)
+	assert.Equal(t, "<seelog/>", string(backup))
+}
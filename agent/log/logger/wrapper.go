@@ -62,6 +62,7 @@ func (w *Wrapper) WriteEvent(eventType string, agentVersion string, event string
 // and writes to log with level = Trace.
 func (w *Wrapper) Tracef(format string, params ...interface{}) {
 	format, params = w.Format.Filterf(format, params...)
+	format, params = redactf(format, params)
 	w.M.RLock()
 	defer w.M.RUnlock()
 	w.Delegate.BaseLoggerInstance.Tracef(format, params...)
@@ -71,6 +72,7 @@ func (w *Wrapper) Tracef(format string, params ...interface{}) {
 // and writes to log with level = Debug.
 func (w *Wrapper) Debugf(format string, params ...interface{}) {
 	format, params = w.Format.Filterf(format, params...)
+	format, params = redactf(format, params)
 
 	w.M.RLock()
 	defer w.M.RUnlock()
@@ -81,6 +83,7 @@ func (w *Wrapper) Debugf(format string, params ...interface{}) {
 // and writes to log with level = Info.
 func (w *Wrapper) Infof(format string, params ...interface{}) {
 	format, params = w.Format.Filterf(format, params...)
+	format, params = redactf(format, params)
 
 	w.M.RLock()
 	defer w.M.RUnlock()
@@ -91,6 +94,7 @@ func (w *Wrapper) Infof(format string, params ...interface{}) {
 // and writes to log with level = Warn.
 func (w *Wrapper) Warnf(format string, params ...interface{}) error {
 	format, params = w.Format.Filterf(format, params...)
+	format, params = redactf(format, params)
 
 	w.M.RLock()
 	defer w.M.RUnlock()
@@ -101,6 +105,7 @@ func (w *Wrapper) Warnf(format string, params ...interface{}) error {
 // and writes to log with level = Error.
 func (w *Wrapper) Errorf(format string, params ...interface{}) error {
 	format, params = w.Format.Filterf(format, params...)
+	format, params = redactf(format, params)
 
 	w.M.RLock()
 	defer w.M.RUnlock()
@@ -111,6 +116,7 @@ func (w *Wrapper) Errorf(format string, params ...interface{}) error {
 // and writes to log with level = Critical.
 func (w *Wrapper) Criticalf(format string, params ...interface{}) error {
 	format, params = w.Format.Filterf(format, params...)
+	format, params = redactf(format, params)
 
 	w.M.RLock()
 	defer w.M.RUnlock()
@@ -121,6 +127,7 @@ func (w *Wrapper) Criticalf(format string, params ...interface{}) error {
 // and writes to log with level = Trace
 func (w *Wrapper) Trace(v ...interface{}) {
 	v = w.Format.Filter(v...)
+	v = redact(v)
 	w.M.RLock()
 	defer w.M.RUnlock()
 	w.Delegate.BaseLoggerInstance.Trace(v...)
@@ -130,6 +137,7 @@ func (w *Wrapper) Trace(v ...interface{}) {
 // and writes to log with level = Debug
 func (w *Wrapper) Debug(v ...interface{}) {
 	v = w.Format.Filter(v...)
+	v = redact(v)
 
 	w.M.RLock()
 	defer w.M.RUnlock()
@@ -140,6 +148,7 @@ func (w *Wrapper) Debug(v ...interface{}) {
 // and writes to log with level = Info
 func (w *Wrapper) Info(v ...interface{}) {
 	v = w.Format.Filter(v...)
+	v = redact(v)
 
 	w.M.RLock()
 	defer w.M.RUnlock()
@@ -150,6 +159,7 @@ func (w *Wrapper) Info(v ...interface{}) {
 // and writes to log with level = Warn
 func (w *Wrapper) Warn(v ...interface{}) error {
 	v = w.Format.Filter(v...)
+	v = redact(v)
 
 	w.M.RLock()
 	defer w.M.RUnlock()
@@ -160,6 +170,7 @@ func (w *Wrapper) Warn(v ...interface{}) error {
 // and writes to log with level = Error
 func (w *Wrapper) Error(v ...interface{}) error {
 	v = w.Format.Filter(v...)
+	v = redact(v)
 
 	w.M.RLock()
 	defer w.M.RUnlock()
@@ -170,6 +181,7 @@ func (w *Wrapper) Error(v ...interface{}) error {
 // and writes to log with level = Critical
 func (w *Wrapper) Critical(v ...interface{}) error {
 	v = w.Format.Filter(v...)
+	v = redact(v)
 
 	w.M.RLock()
 	defer w.M.RUnlock()
@@ -0,0 +1,178 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build darwin || freebsd || linux || netbsd || openbsd
+// +build darwin freebsd linux netbsd openbsd
+
+package ssmlog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	logpkg "github.com/aws/amazon-ssm-agent/agent/log/logger"
+	"github.com/cihub/seelog"
+)
+
+const (
+	defaultJournaldSocketPath = "/run/systemd/journal/socket"
+
+	// journaldMaxMessageSize keeps a single datagram well below the default socket buffer size
+	journaldMaxMessageSize = 64 * 1024
+)
+
+// journald priorities, same values as syslog severities
+const (
+	journaldPriorityCrit    = 2
+	journaldPriorityErr     = 3
+	journaldPriorityWarning = 4
+	journaldPriorityInfo    = 6
+	journaldPriorityDebug   = 7
+)
+
+// JournaldCustomReceiver implements seelog.CustomReceiver and writes messages to the systemd journal
+// using the native journal protocol, adding the agent log context as structured fields
+// (SSM_COMPONENT, SSM_COMMAND_ID, SSM_SESSION_ID, ...).
+//
+// Usage in seelog.xml:
+//
+//	<custom name="journald_receiver" formatid="fmtmsg" data-identifier="amazon-ssm-agent"/>
+type JournaldCustomReceiver struct {
+	mutex      sync.Mutex
+	conn       *net.UnixConn
+	identifier string
+}
+
+// AfterParse connects to the journal socket
+func (logReceiver *JournaldCustomReceiver) AfterParse(initArgs seelog.CustomReceiverInitArgs) error {
+	socketPath := initArgs.XmlCustomAttrs["socket"]
+	if socketPath == "" {
+		socketPath = defaultJournaldSocketPath
+	}
+	identifier := initArgs.XmlCustomAttrs["identifier"]
+	if identifier == "" {
+		identifier = filepath.Base(os.Args[0])
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socketPath, Net: "unixgram"})
+	if err != nil {
+		// Do not fail the seelog parse, the other receivers should keep working
+		fmt.Printf("[ERROR] Failed to create journald custom receiver. Err: %v\n", err)
+		return nil
+	}
+
+	logReceiver.mutex.Lock()
+	defer logReceiver.mutex.Unlock()
+	logReceiver.conn = conn
+	logReceiver.identifier = identifier
+	return nil
+}
+
+// ReceiveMessage sends the message with its structured fields to the journal
+func (logReceiver *JournaldCustomReceiver) ReceiveMessage(message string, level seelog.LogLevel, context seelog.LogContextInterface) error {
+	logReceiver.mutex.Lock()
+	defer logReceiver.mutex.Unlock()
+	if logReceiver.conn == nil {
+		return nil
+	}
+	_, err := logReceiver.conn.Write(buildJournaldEntry(message, level, context, logReceiver.identifier))
+	return err
+}
+
+// Flush is a no-op, messages are sent to the journal immediately
+func (logReceiver *JournaldCustomReceiver) Flush() {
+}
+
+// Close closes the journal socket
+func (logReceiver *JournaldCustomReceiver) Close() error {
+	logReceiver.mutex.Lock()
+	defer logReceiver.mutex.Unlock()
+	if logReceiver.conn == nil {
+		return nil
+	}
+	err := logReceiver.conn.Close()
+	logReceiver.conn = nil
+	return err
+}
+
+// buildJournaldEntry serializes the message in the native journal protocol format
+func buildJournaldEntry(message string, level seelog.LogLevel, context seelog.LogContextInterface, identifier string) []byte {
+	message = strings.TrimRight(message, "\n")
+	if len(message) > journaldMaxMessageSize {
+		message = message[:journaldMaxMessageSize]
+	}
+
+	callTime := time.Now()
+	if context != nil {
+		callTime = context.CallTime()
+	}
+	entry := logpkg.NewJSONLogEntry(message, level, callTime)
+
+	var buffer bytes.Buffer
+	writeJournaldField(&buffer, "MESSAGE", message)
+	writeJournaldField(&buffer, "PRIORITY", strconv.Itoa(journaldPriority(level)))
+	writeJournaldField(&buffer, "SYSLOG_IDENTIFIER", identifier)
+	if context != nil {
+		writeJournaldField(&buffer, "CODE_FILE", context.FileName())
+		writeJournaldField(&buffer, "CODE_LINE", strconv.Itoa(context.Line()))
+		writeJournaldField(&buffer, "CODE_FUNC", context.Func())
+	}
+	writeJournaldField(&buffer, "SSM_COMPONENT", entry.Component)
+	writeJournaldField(&buffer, "SSM_COMMAND_ID", entry.CommandId)
+	writeJournaldField(&buffer, "SSM_SESSION_ID", entry.SessionId)
+	writeJournaldField(&buffer, "SSM_ASSOCIATION_ID", entry.AssociationId)
+	writeJournaldField(&buffer, "SSM_PLUGIN_NAME", entry.PluginName)
+	return buffer.Bytes()
+}
+
+// writeJournaldField appends a field, values containing new lines use the binary safe encoding
+func writeJournaldField(buffer *bytes.Buffer, name string, value string) {
+	if value == "" {
+		return
+	}
+	buffer.WriteString(name)
+	if !strings.Contains(value, "\n") {
+		buffer.WriteByte('=')
+		buffer.WriteString(value)
+		buffer.WriteByte('\n')
+		return
+	}
+	buffer.WriteByte('\n')
+	binary.Write(buffer, binary.LittleEndian, uint64(len(value)))
+	buffer.WriteString(value)
+	buffer.WriteByte('\n')
+}
+
+// journaldPriority maps the seelog level to the journal priority
+func journaldPriority(level seelog.LogLevel) int {
+	switch level {
+	case seelog.TraceLvl, seelog.DebugLvl:
+		return journaldPriorityDebug
+	case seelog.InfoLvl:
+		return journaldPriorityInfo
+	case seelog.WarnLvl:
+		return journaldPriorityWarning
+	case seelog.ErrorLvl:
+		return journaldPriorityErr
+	default:
+		return journaldPriorityCrit
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build darwin || freebsd || linux || netbsd || openbsd
+// +build darwin freebsd linux netbsd openbsd
+
+package ssmlog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cihub/seelog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJournaldReceiver_FailsToInitialize_NoError(t *testing.T) {
+	initArgs := seelog.CustomReceiverInitArgs{
+		XmlCustomAttrs: map[string]string{"socket": filepath.Join(t.TempDir(), "missing")},
+	}
+	receiver := JournaldCustomReceiver{}
+
+	assert.NoError(t, receiver.AfterParse(initArgs))
+	assert.NoError(t, receiver.ReceiveMessage("message", seelog.InfoLvl, nil))
+	assert.NoError(t, receiver.Close())
+}
+
+func TestJournaldReceiver_SendsStructuredFields(t *testing.T) {
+	dir, err := os.MkdirTemp("", "journal")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	socketPath := filepath.Join(dir, "socket")
+	listener, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socketPath, Net: "unixgram"})
+	assert.NoError(t, err)
+	defer listener.Close()
+
+	receiver := JournaldCustomReceiver{}
+	assert.NoError(t, receiver.AfterParse(seelog.CustomReceiverInitArgs{
+		XmlCustomAttrs: map[string]string{"socket": socketPath, "identifier": "amazon-ssm-agent"},
+	}))
+	defer receiver.Close()
+
+	message := "[ssm-agent-worker] [messageID=aws.ssm.1c2b3a4d-1111-2222-3333-444455556666.i-0123456789abcdef0] Sending reply"
+	assert.NoError(t, receiver.ReceiveMessage(message, seelog.WarnLvl, nil))
+
+	buffer := make([]byte, 4096)
+	n, err := listener.Read(buffer)
+	assert.NoError(t, err)
+	datagram := string(buffer[:n])
+	assert.Contains(t, datagram, "MESSAGE="+message+"\n")
+	assert.Contains(t, datagram, "PRIORITY=4\n")
+	assert.Contains(t, datagram, "SYSLOG_IDENTIFIER=amazon-ssm-agent\n")
+	assert.Contains(t, datagram, "SSM_COMPONENT=ssm-agent-worker\n")
+	assert.Contains(t, datagram, "SSM_COMMAND_ID=1c2b3a4d-1111-2222-3333-444455556666\n")
+	assert.NotContains(t, datagram, "SSM_SESSION_ID")
+}
+
+func TestWriteJournaldField_MultiLineValue(t *testing.T) {
+	var buffer bytes.Buffer
+	writeJournaldField(&buffer, "MESSAGE", "line1\nline2")
+
+	var expected bytes.Buffer
+	expected.WriteString("MESSAGE\n")
+	binary.Write(&expected, binary.LittleEndian, uint64(11))
+	expected.WriteString("line1\nline2\n")
+	assert.Equal(t, expected.Bytes(), buffer.Bytes())
+}
+
+func TestJournaldPriority(t *testing.T) {
+	assert.Equal(t, journaldPriorityDebug, journaldPriority(seelog.TraceLvl))
+	assert.Equal(t, journaldPriorityDebug, journaldPriority(seelog.DebugLvl))
+	assert.Equal(t, journaldPriorityInfo, journaldPriority(seelog.InfoLvl))
+	assert.Equal(t, journaldPriorityWarning, journaldPriority(seelog.WarnLvl))
+	assert.Equal(t, journaldPriorityErr, journaldPriority(seelog.ErrorLvl))
+	assert.Equal(t, journaldPriorityCrit, journaldPriority(seelog.CriticalLvl))
+}
@@ -0,0 +1,94 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package ssmlog
+
+import (
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	logpkg "github.com/aws/amazon-ssm-agent/agent/log/logger"
+	"github.com/cihub/seelog"
+)
+
+// levelResetTimer restores the seelog.xml level when the active override expires
+var levelResetTimer *time.Timer
+var levelResetLock sync.Mutex
+
+// startLogLevelOverrideWatcher starts the file watcher on the runtime log level override file
+func startLogLevelOverrideWatcher(logger log.T) {
+	defer func() {
+		if msg := recover(); msg != nil {
+			logger.Errorf("Log level override watcher initialization failed: %v", msg)
+		}
+	}()
+	fileWatcher := &FileWatcher{}
+	fileWatcher.Init(logger, logpkg.LogLevelOverrideFilePath(), replaceLogger)
+	fileWatcher.Start()
+	scheduleLogLevelReset()
+	startLogLevelSignalHandler(logger)
+}
+
+// scheduleLogLevelReset replaces the logger when the active log level override expires
+func scheduleLogLevelReset() {
+	levelResetLock.Lock()
+	defer levelResetLock.Unlock()
+	if levelResetTimer != nil {
+		levelResetTimer.Stop()
+		levelResetTimer = nil
+	}
+
+	override, err := logpkg.ReadLogLevelOverride()
+	if err != nil || override == nil || override.ExpiresAt == nil {
+		return
+	}
+	levelResetTimer = time.AfterFunc(time.Until(*override.ExpiresAt), replaceLogger)
+}
+
+// cycleLogLevel moves the override to the next level: none -> debug -> trace -> none
+func cycleLogLevel(logger log.T) {
+	var current string
+	if override, err := logpkg.ReadLogLevelOverride(); err != nil {
+		logger.Warnf("Ignoring invalid log level override: %v", err)
+	} else if override != nil {
+		current = override.Level
+	}
+
+	next := nextCycleLogLevel(current)
+	if next == "" {
+		logger.Infof("Removing log level override, restoring level from seelog configuration")
+		if err := logpkg.RemoveLogLevelOverride(); err != nil {
+			logger.Errorf("Failed to remove log level override: %v", err)
+		}
+		return
+	}
+	logger.Infof("Setting log level override to %v", next)
+	if err := logpkg.WriteLogLevelOverride(logpkg.LogLevelOverride{Level: next}); err != nil {
+		logger.Errorf("Failed to write log level override: %v", err)
+	}
+}
+
+// nextCycleLogLevel returns the level following the current override, empty meaning no override
+func nextCycleLogLevel(current string) string {
+	switch current {
+	case "":
+		return seelog.DebugStr
+	case seelog.DebugStr:
+		return seelog.TraceStr
+	case seelog.TraceStr:
+		return ""
+	default:
+		return seelog.DebugStr
+	}
+}
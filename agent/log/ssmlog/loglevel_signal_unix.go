@@ -0,0 +1,36 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build darwin || freebsd || linux || netbsd || openbsd
+// +build darwin freebsd linux netbsd openbsd
+
+package ssmlog
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/aws/amazon-ssm-agent/agent/log"
+)
+
+// startLogLevelSignalHandler cycles the log level override every time SIGUSR1 is received
+func startLogLevelSignalHandler(logger log.T) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGUSR1)
+	go func() {
+		for range signals {
+			cycleLogLevel(logger)
+		}
+	}()
+}
@@ -0,0 +1,48 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package ssmlog
+
+import (
+	"path/filepath"
+	"testing"
+
+	logpkg "github.com/aws/amazon-ssm-agent/agent/log/logger"
+	"github.com/cihub/seelog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextCycleLogLevel(t *testing.T) {
+	assert.Equal(t, seelog.DebugStr, nextCycleLogLevel(""))
+	assert.Equal(t, seelog.TraceStr, nextCycleLogLevel(seelog.DebugStr))
+	assert.Equal(t, "", nextCycleLogLevel(seelog.TraceStr))
+	assert.Equal(t, seelog.DebugStr, nextCycleLogLevel(seelog.ErrorStr))
+}
+
+func TestCycleLogLevel(t *testing.T) {
+	originalPath := logpkg.DefaultSeelogConfigFilePath
+	logpkg.DefaultSeelogConfigFilePath = filepath.Join(t.TempDir(), "seelog.xml")
+	defer func() { logpkg.DefaultSeelogConfigFilePath = originalPath }()
+	logger := logpkg.NewSilentLogger()
+
+	for _, expected := range []string{seelog.DebugStr, seelog.TraceStr, ""} {
+		cycleLogLevel(logger)
+		override, err := logpkg.ReadLogLevelOverride()
+		assert.NoError(t, err)
+		if expected == "" {
+			assert.Nil(t, override)
+		} else {
+			assert.Equal(t, expected, override.Level)
+		}
+	}
+}
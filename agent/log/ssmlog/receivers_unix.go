@@ -0,0 +1,32 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build darwin || freebsd || linux || netbsd || openbsd
+// +build darwin freebsd linux netbsd openbsd
+
+package ssmlog
+
+import "github.com/cihub/seelog"
+
+const (
+	// SyslogReceiverName is the name used to reference the syslog receiver in seelog.xml
+	SyslogReceiverName = "syslog_receiver"
+	// JournaldReceiverName is the name used to reference the journald receiver in seelog.xml
+	JournaldReceiverName = "journald_receiver"
+)
+
+// registerSystemReceivers registers the receivers writing to the system log facilities
+func registerSystemReceivers() {
+	seelog.RegisterReceiver(SyslogReceiverName, &SyslogCustomReceiver{})
+	seelog.RegisterReceiver(JournaldReceiverName, &JournaldCustomReceiver{})
+}
@@ -58,6 +58,8 @@ func initLogger(useWatcher bool) (logger log.T) {
 	if useWatcher {
 		// Start the config file watcher
 		startWatcher(logger)
+		// Start watching for runtime log level changes
+		startLogLevelOverrideWatcher(logger)
 	}
 	return
 }
@@ -166,6 +168,9 @@ func replaceLogger() {
 
 	// Replace the underlying base logger in wrapper
 	wrapper.ReplaceDelegate(baseLogger)
+
+	// Restore the configured level once the new log level override expires
+	scheduleLogLevelReset()
 }
 
 // initLoggerFromBytes creates a new wrapper logger from configurations passed
@@ -177,8 +182,10 @@ func initLoggerFromBytes(seelogConfig []byte) log.T {
 // initBaseLoggerFromBytes initializes the base logger using the specified configuration as bytes.
 func initBaseLoggerFromBytes(seelogConfig []byte) (seelogger seelog.LoggerInterface, err error) {
 	fmt.Println("Initializing new seelog logger")
+	logpkg.LoadRedaction()
 	logReceiver := &CloudWatchCustomReceiver{}
 	seelog.RegisterReceiver("cloudwatch_receiver", logReceiver)
+	registerSystemReceivers()
 	seelogger, err = seelog.LoggerFromConfigAsBytes(seelogConfig)
 	if err != nil {
 		fmt.Println("Error parsing logger config. Creating logger from default config:", err)
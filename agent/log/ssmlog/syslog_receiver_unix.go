@@ -0,0 +1,126 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build darwin || freebsd || linux || netbsd || openbsd
+// +build darwin freebsd linux netbsd openbsd
+
+package ssmlog
+
+import (
+	"fmt"
+	"log/syslog"
+	"strings"
+	"sync"
+
+	"github.com/cihub/seelog"
+)
+
+const (
+	defaultSyslogTag      = "amazon-ssm-agent"
+	defaultSyslogFacility = syslog.LOG_DAEMON
+)
+
+var syslogFacilities = map[string]syslog.Priority{
+	"kern":     syslog.LOG_KERN,
+	"user":     syslog.LOG_USER,
+	"daemon":   syslog.LOG_DAEMON,
+	"auth":     syslog.LOG_AUTH,
+	"syslog":   syslog.LOG_SYSLOG,
+	"authpriv": syslog.LOG_AUTHPRIV,
+	"local0":   syslog.LOG_LOCAL0,
+	"local1":   syslog.LOG_LOCAL1,
+	"local2":   syslog.LOG_LOCAL2,
+	"local3":   syslog.LOG_LOCAL3,
+	"local4":   syslog.LOG_LOCAL4,
+	"local5":   syslog.LOG_LOCAL5,
+	"local6":   syslog.LOG_LOCAL6,
+	"local7":   syslog.LOG_LOCAL7,
+}
+
+// SyslogCustomReceiver implements seelog.CustomReceiver and writes messages to the syslog daemon.
+//
+// Usage in seelog.xml:
+//
+//	<custom name="syslog_receiver" formatid="fmtmsg" data-facility="daemon" data-tag="amazon-ssm-agent"/>
+//
+// data-network and data-address can be used to log to a remote syslog server, e.g. data-network="udp" data-address="host:514".
+type SyslogCustomReceiver struct {
+	mutex  sync.Mutex
+	writer *syslog.Writer
+}
+
+// AfterParse connects to the syslog daemon with the settings from the XML args
+func (logReceiver *SyslogCustomReceiver) AfterParse(initArgs seelog.CustomReceiverInitArgs) error {
+	facility := defaultSyslogFacility
+	if name, ok := initArgs.XmlCustomAttrs["facility"]; ok {
+		if facility, ok = syslogFacilities[strings.ToLower(name)]; !ok {
+			fmt.Printf("[ERROR] Unknown syslog facility %v, using daemon\n", name)
+			facility = defaultSyslogFacility
+		}
+	}
+	tag := initArgs.XmlCustomAttrs["tag"]
+	if tag == "" {
+		tag = defaultSyslogTag
+	}
+
+	writer, err := syslog.Dial(initArgs.XmlCustomAttrs["network"], initArgs.XmlCustomAttrs["address"], facility|syslog.LOG_INFO, tag)
+	if err != nil {
+		// Do not fail the seelog parse, the other receivers should keep working
+		fmt.Printf("[ERROR] Failed to create syslog custom receiver. Err: %v\n", err)
+		return nil
+	}
+
+	logReceiver.mutex.Lock()
+	defer logReceiver.mutex.Unlock()
+	logReceiver.writer = writer
+	return nil
+}
+
+// ReceiveMessage writes the message to syslog with the severity matching the log level
+func (logReceiver *SyslogCustomReceiver) ReceiveMessage(message string, level seelog.LogLevel, context seelog.LogContextInterface) error {
+	logReceiver.mutex.Lock()
+	defer logReceiver.mutex.Unlock()
+	if logReceiver.writer == nil {
+		return nil
+	}
+
+	message = strings.TrimRight(message, "\n")
+	switch level {
+	case seelog.TraceLvl, seelog.DebugLvl:
+		return logReceiver.writer.Debug(message)
+	case seelog.InfoLvl:
+		return logReceiver.writer.Info(message)
+	case seelog.WarnLvl:
+		return logReceiver.writer.Warning(message)
+	case seelog.ErrorLvl:
+		return logReceiver.writer.Err(message)
+	default:
+		return logReceiver.writer.Crit(message)
+	}
+}
+
+// Flush is a no-op, messages are written to syslog immediately
+func (logReceiver *SyslogCustomReceiver) Flush() {
+}
+
+// Close closes the connection to the syslog daemon
+func (logReceiver *SyslogCustomReceiver) Close() error {
+	logReceiver.mutex.Lock()
+	defer logReceiver.mutex.Unlock()
+	if logReceiver.writer == nil {
+		return nil
+	}
+	err := logReceiver.writer.Close()
+	logReceiver.writer = nil
+	return err
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build darwin || freebsd || linux || netbsd || openbsd
+// +build darwin freebsd linux netbsd openbsd
+
+package ssmlog
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cihub/seelog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyslogReceiver_FailsToInitialize_NoError(t *testing.T) {
+	initArgs := seelog.CustomReceiverInitArgs{
+		XmlCustomAttrs: map[string]string{"network": "invalid", "address": "invalid"},
+	}
+	receiver := SyslogCustomReceiver{}
+
+	assert.NoError(t, receiver.AfterParse(initArgs))
+	assert.NoError(t, receiver.ReceiveMessage("message", seelog.InfoLvl, nil))
+	assert.NoError(t, receiver.Close())
+}
+
+func TestSyslogReceiver_WritesWithFacilityAndSeverity(t *testing.T) {
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+
+	receiver := SyslogCustomReceiver{}
+	assert.NoError(t, receiver.AfterParse(seelog.CustomReceiverInitArgs{
+		XmlCustomAttrs: map[string]string{
+			"network":  "udp",
+			"address":  listener.LocalAddr().String(),
+			"facility": "local3",
+			"tag":      "ssm-test",
+		},
+	}))
+	defer receiver.Close()
+
+	assert.NoError(t, receiver.ReceiveMessage("something failed\n", seelog.ErrorLvl, nil))
+
+	buffer := make([]byte, 1024)
+	listener.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := listener.ReadFrom(buffer)
+	assert.NoError(t, err)
+	packet := string(buffer[:n])
+	// local3 (19) * 8 + err (3) = 155
+	assert.True(t, strings.HasPrefix(packet, "<155>"), packet)
+	assert.Contains(t, packet, "ssm-test")
+	assert.True(t, strings.HasSuffix(packet, "something failed\n"), packet)
+}
+
+func TestSyslogReceiver_UnknownFacilityUsesDaemon(t *testing.T) {
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+
+	receiver := SyslogCustomReceiver{}
+	assert.NoError(t, receiver.AfterParse(seelog.CustomReceiverInitArgs{
+		XmlCustomAttrs: map[string]string{"network": "udp", "address": listener.LocalAddr().String(), "facility": "unknown"},
+	}))
+	defer receiver.Close()
+
+	assert.NoError(t, receiver.ReceiveMessage("hello", seelog.InfoLvl, nil))
+
+	buffer := make([]byte, 1024)
+	listener.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := listener.ReadFrom(buffer)
+	assert.NoError(t, err)
+	// daemon (3) * 8 + info (6) = 30
+	assert.True(t, strings.HasPrefix(string(buffer[:n]), "<30>"))
+}
@@ -0,0 +1,142 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package enrollment requests the activation of a managed instance from an enrollment endpoint which authenticates
+// the device by the X.509 certificate it presents, e.g. a certificate issued through EST or SCEP at manufacturing time
+package enrollment
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/network"
+	"github.com/aws/amazon-ssm-agent/agent/version"
+)
+
+const (
+	requestTimeout = 30 * time.Second
+	// responses larger than this are not activations
+	maxResponseBytes = 64 * 1024
+)
+
+var (
+	loadX509KeyPair = tls.LoadX509KeyPair
+	newHTTPClient   = func(log log.T, appConfig appconfig.SsmagentConfig) *http.Client {
+		return &http.Client{
+			Transport: network.GetDefaultTransport(log, appConfig),
+			Timeout:   requestTimeout,
+		}
+	}
+	hostname = os.Hostname
+)
+
+// Request is sent to the enrollment endpoint, the device is identified by its certificate
+type Request struct {
+	Hostname     string `json:"hostname,omitempty"`
+	AgentVersion string `json:"agentVersion"`
+	Region       string `json:"region,omitempty"`
+}
+
+// Activation is the activation issued by the enrollment endpoint for the device
+type Activation struct {
+	ActivationCode string `json:"activationCode"`
+	ActivationId   string `json:"activationId"`
+	Region         string `json:"region,omitempty"`
+}
+
+// Config describes the enrollment endpoint and the certificate authenticating the device
+type Config struct {
+	Endpoint        string
+	CertificateFile string
+	KeyFile         string
+	// Region requested for the activation, the endpoint may return the region of the activation it issued
+	Region string
+}
+
+// RequestActivation presents the device certificate to the enrollment endpoint over mutual TLS and returns the
+// activation it issues. The certificate files default to the client certificate configured in appconfig.
+func RequestActivation(log log.T, appConfig appconfig.SsmagentConfig, config Config) (*Activation, error) {
+	endpoint, err := url.Parse(strings.TrimSpace(config.Endpoint))
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid enrollment endpoint %v", config.Endpoint)
+	}
+	if endpoint.Scheme != "https" {
+		return nil, fmt.Errorf("enrollment endpoint %v must use https", config.Endpoint)
+	}
+
+	if config.CertificateFile != "" || config.KeyFile != "" {
+		appConfig.ClientCertificate = appconfig.ClientCertificateCfg{
+			CertificateFile: config.CertificateFile,
+			KeyFile:         config.KeyFile,
+		}
+	}
+	clientCertificate := appConfig.ClientCertificate
+	if clientCertificate.CertificateFile == "" && clientCertificate.CertificateStore == "" {
+		return nil, fmt.Errorf("no device certificate configured for enrollment")
+	}
+	// fail early instead of sending the request without a certificate
+	if clientCertificate.CertificateFile != "" {
+		if _, err = loadX509KeyPair(clientCertificate.CertificateFile, clientCertificate.KeyFile); err != nil {
+			return nil, fmt.Errorf("failed to read the device certificate %v: %v", clientCertificate.CertificateFile, err)
+		}
+	}
+
+	request := Request{AgentVersion: version.Version, Region: config.Region}
+	if name, err := hostname(); err == nil {
+		request.Hostname = name
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Requesting activation from enrollment endpoint %v", endpoint.Host)
+	resp, err := newHTTPClient(log, appConfig).Post(endpoint.String(), "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("enrollment request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read enrollment response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("enrollment endpoint returned %v: %s", resp.Status, strings.TrimSpace(string(content)))
+	}
+
+	var activation Activation
+	if err = json.Unmarshal(content, &activation); err != nil {
+		return nil, fmt.Errorf("invalid enrollment response: %v", err)
+	}
+	if activation.ActivationCode == "" || activation.ActivationId == "" {
+		return nil, fmt.Errorf("enrollment response does not contain an activation")
+	}
+	if activation.Region == "" {
+		activation.Region = config.Region
+	}
+	if activation.Region == "" {
+		return nil, fmt.Errorf("enrollment response does not contain the region of the activation")
+	}
+	return &activation, nil
+}
@@ -0,0 +1,122 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package enrollment
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	logmocks "github.com/aws/amazon-ssm-agent/agent/mocks/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func startEndpoint(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	server := httptest.NewTLSServer(handler)
+	originalHTTPClient, originalHostname := newHTTPClient, hostname
+	loadX509KeyPair = func(certFile, keyFile string) (tls.Certificate, error) {
+		return tls.Certificate{}, nil
+	}
+	newHTTPClient = func(log log.T, appConfig appconfig.SsmagentConfig) *http.Client {
+		assert.Equal(t, "device.crt", appConfig.ClientCertificate.CertificateFile)
+		assert.Equal(t, "device.key", appConfig.ClientCertificate.KeyFile)
+		return server.Client()
+	}
+	hostname = func() (string, error) { return "device-1", nil }
+	t.Cleanup(func() {
+		server.Close()
+		loadX509KeyPair = tls.LoadX509KeyPair
+		newHTTPClient, hostname = originalHTTPClient, originalHostname
+	})
+	return server
+}
+
+func TestRequestActivation(t *testing.T) {
+	server := startEndpoint(t, func(w http.ResponseWriter, r *http.Request) {
+		var request Request
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "device-1", request.Hostname)
+		assert.Equal(t, "us-east-1", request.Region)
+		w.Write([]byte(`{"activationCode":"code","activationId":"id"}`))
+	})
+
+	activation, err := RequestActivation(logmocks.NewMockLog(), appconfig.SsmagentConfig{}, Config{
+		Endpoint:        server.URL + "/enroll",
+		CertificateFile: "device.crt",
+		KeyFile:         "device.key",
+		Region:          "us-east-1",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &Activation{ActivationCode: "code", ActivationId: "id", Region: "us-east-1"}, activation)
+}
+
+func TestRequestActivation_ConfiguredClientCertificate(t *testing.T) {
+	server := startEndpoint(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"activationCode":"code","activationId":"id","region":"eu-west-1"}`))
+	})
+	appConfig := appconfig.SsmagentConfig{}
+	appConfig.ClientCertificate.CertificateFile = "device.crt"
+	appConfig.ClientCertificate.KeyFile = "device.key"
+
+	activation, err := RequestActivation(logmocks.NewMockLog(), appConfig, Config{Endpoint: server.URL})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "eu-west-1", activation.Region)
+}
+
+func TestRequestActivation_Rejected(t *testing.T) {
+	server := startEndpoint(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("certificate revoked"))
+	})
+
+	_, err := RequestActivation(logmocks.NewMockLog(), appconfig.SsmagentConfig{}, Config{
+		Endpoint: server.URL, CertificateFile: "device.crt", KeyFile: "device.key", Region: "us-east-1",
+	})
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "certificate revoked")
+}
+
+func TestRequestActivation_InvalidResponse(t *testing.T) {
+	for _, response := range []string{`not json`, `{"activationCode":"code"}`, `{"activationCode":"code","activationId":"id"}`} {
+		server := startEndpoint(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(response))
+		})
+
+		_, err := RequestActivation(logmocks.NewMockLog(), appconfig.SsmagentConfig{}, Config{
+			Endpoint: server.URL, CertificateFile: "device.crt", KeyFile: "device.key",
+		})
+
+		assert.Error(t, err, response)
+	}
+}
+
+func TestRequestActivation_InvalidConfig(t *testing.T) {
+	logMock := logmocks.NewMockLog()
+	_, err := RequestActivation(logMock, appconfig.SsmagentConfig{}, Config{Endpoint: "http://enroll.example.com", CertificateFile: "device.crt"})
+	assert.Error(t, err)
+
+	_, err = RequestActivation(logMock, appconfig.SsmagentConfig{}, Config{Endpoint: "enroll"})
+	assert.Error(t, err)
+
+	_, err = RequestActivation(logMock, appconfig.SsmagentConfig{}, Config{Endpoint: "https://enroll.example.com"})
+	assert.EqualError(t, err, "no device certificate configured for enrollment")
+}
@@ -37,12 +37,16 @@ type hwInfo struct {
 	Fingerprint         string            `json:"fingerprint"`
 	HardwareHash        map[string]string `json:"hardwareHash"`
 	SimilarityThreshold int               `json:"similarityThreshold"`
+	// HardwareSource is where the hardware values were read from, the hash values of different sources are not comparable
+	HardwareSource string `json:"hardwareSource,omitempty"`
 }
 
 const (
 	defaultMatchPercent = 40
 	vaultKey            = "InstanceFingerprint"
 	ipAddressID         = "ipaddress-info"
+	hostnameID          = "hostname-info"
+	macAddressID        = "macaddr-info"
 )
 
 var (
@@ -59,6 +63,8 @@ func InstanceFingerprint(log log.T) (string, error) {
 	lock.Lock()
 	defer lock.Unlock()
 
+	regenerateIfArmed(log)
+
 	var err error
 	fingerprint, err = generateFingerprint(log)
 	if err != nil {
@@ -98,11 +104,13 @@ func generateFingerprint(log log.T) (fingerprint string, err error) {
 	var hardwareHash map[string]string
 	var savedHwInfo hwInfo
 	var hwHashErr error
+	var source string
 
 	// retry getting the new hash and compare with the saved hash for 3 times
 	for attempt := 1; attempt <= 3; attempt++ {
 		// fetch current hardware hash values
 		hardwareHash, hwHashErr = currentHwHash()
+		source = currentHardwareSource(log)
 
 		if hwHashErr != nil || !isValidHardwareHash(hardwareHash) {
 			// sleep 5 seconds until the next retry
@@ -119,11 +127,18 @@ func generateFingerprint(log log.T) (fingerprint string, err error) {
 		// first time generation, breakout retry
 		if !hasFingerprint(savedHwInfo) {
 			log.Debugf("No initial fingerprint detected, skipping retry...")
-			// Set the default similarity threshold during first time generation
-			savedHwInfo.SimilarityThreshold = defaultMatchPercent
+			// Set the default similarity threshold during first time generation unless a generalized image kept one
+			if savedHwInfo.SimilarityThreshold == 0 {
+				savedHwInfo.SimilarityThreshold = defaultMatchPercent
+			}
 			break
 		}
 
+		// the saved hash was read from another source, e.g. after WMIC was removed from the instance
+		if savedHwInfo.HardwareSource != source && migrateHardwareSource(log, savedHwInfo, hardwareHash, source) {
+			return savedHwInfo.Fingerprint, nil
+		}
+
 		// stop retry if the hardware hashes are the same
 		if isSimilarHardwareHash(log, savedHwInfo.HardwareHash, hardwareHash, savedHwInfo.SimilarityThreshold) {
 			log.Debugf("Calculated hardware hash is same as saved one, returning fingerprint")
@@ -165,6 +180,7 @@ func generateFingerprint(log log.T) (fingerprint string, err error) {
 		Fingerprint:         fingerprint,
 		HardwareHash:        hardwareHash,
 		SimilarityThreshold: savedHwInfo.SimilarityThreshold,
+		HardwareSource:      source,
 	}
 
 	// save content in vault
@@ -174,6 +190,33 @@ func generateFingerprint(log log.T) (fingerprint string, err error) {
 	return fingerprint, err
 }
 
+// migrateHardwareSource keeps the saved fingerprint when the hardware values are read from a new source,
+// the saved hash is verified against the hardware read from its own source before the hash of the new source replaces it
+func migrateHardwareSource(log log.T, savedHwInfo hwInfo, hardwareHash map[string]string, source string) bool {
+	savedSourceHash, err := hwHashFromSource(log, savedHwInfo.HardwareSource)
+	if err == nil {
+		if !isSimilarHardwareHash(log, savedHwInfo.HardwareHash, savedSourceHash, savedHwInfo.SimilarityThreshold) {
+			return false
+		}
+	} else {
+		// the saved source is gone, only the values which do not depend on the source can be compared
+		log.Warnf("Failed to read hardware values from source '%s' of the saved fingerprint: %v", savedHwInfo.HardwareSource, err)
+		for _, key := range []string{hostnameID, ipAddressID, macAddressID} {
+			if savedHwInfo.HardwareHash[key] != hardwareHash[key] {
+				return false
+			}
+		}
+	}
+
+	log.Infof("Migrating fingerprint hardware values from source '%s' to '%s'", savedHwInfo.HardwareSource, source)
+	savedHwInfo.HardwareHash = hardwareHash
+	savedHwInfo.HardwareSource = source
+	if err = save(savedHwInfo); err != nil {
+		log.Errorf("Error while saving fingerprint data from vault: %s", err)
+	}
+	return true
+}
+
 func fetch(log log.T) (hwInfo, error) {
 	savedHwInfo := hwInfo{}
 
@@ -385,3 +428,9 @@ func ClearStoredHardwareInfo(log log.T) {
 		log.Errorf("Failed to store empty hardware info: %v", err)
 	}
 }
+
+// RemoveStoredHardwareInfo removes the fingerprint and the hardware info it was generated from,
+// the next fingerprint request generates a new fingerprint.
+func RemoveStoredHardwareInfo() error {
+	return vault.Remove("", vaultKey)
+}
@@ -22,6 +22,7 @@ var vault fpVault = &fpFsVault{}
 type fpVault interface {
 	Retrieve(manifestFileNamePrefix string, key string) (data []byte, err error)
 	Store(manifestFileNamePrefix string, key string, data []byte) (err error)
+	Remove(manifestFileNamePrefix string, key string) (err error)
 }
 
 type fpFsVault struct{}
@@ -32,3 +33,6 @@ func (fpFsVault) Retrieve(manifestFileNamePrefix string, key string) ([]byte, er
 func (fpFsVault) Store(manifestFileNamePrefix string, key string, data []byte) error {
 	return fsvault.Store(manifestFileNamePrefix, key, data)
 }
+func (fpFsVault) Remove(manifestFileNamePrefix string, key string) error {
+	return fsvault.Remove(manifestFileNamePrefix, key)
+}
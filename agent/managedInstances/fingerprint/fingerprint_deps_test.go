@@ -47,3 +47,8 @@ func (m *fpFsVaultMock) Store(manifestFileNamePrefix string, key string, data []
 	args := m.Called(key, data)
 	return args.Error(0)
 }
+
+func (m *fpFsVaultMock) Remove(manifestFileNamePrefix string, key string) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
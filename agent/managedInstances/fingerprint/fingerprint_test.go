@@ -23,6 +23,7 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/log"
 	logmocks "github.com/aws/amazon-ssm-agent/agent/mocks/log"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 const (
@@ -322,6 +323,88 @@ func TestGenerateFingerprint_DoesNotSave_WhenHardwareHashesMatch(t *testing.T) {
 	generateFingerprint(logmocks.NewMockLog())
 }
 
+func stubHardwareSources(t *testing.T, source string, savedSourceHash map[string]string, savedSourceErr error) {
+	originalCurrentHwHash, originalCurrentHardwareSource, originalHwHashFromSource := currentHwHash, currentHardwareSource, hwHashFromSource
+	t.Cleanup(func() {
+		currentHwHash, currentHardwareSource, hwHashFromSource = originalCurrentHwHash, originalCurrentHardwareSource, originalHwHashFromSource
+	})
+
+	currentHwHash = func() (map[string]string, error) {
+		return map[string]string{hardwareID: "wql-uuid", hostnameID: "host", macAddressID: "mac"}, nil
+	}
+	currentHardwareSource = func(log log.T) string { return source }
+	hwHashFromSource = func(log log.T, source string) (map[string]string, error) {
+		return savedSourceHash, savedSourceErr
+	}
+}
+
+func TestGenerateFingerprint_MigratesHardwareSource_WhenSavedSourceMatches(t *testing.T) {
+	// Arrange
+	savedHwHash := map[string]string{hardwareID: "wmic-uuid", hostnameID: "host", macAddressID: "mac"}
+	stubHardwareSources(t, "WQL", savedHwHash, nil)
+	savedHwData, _ := json.Marshal(hwInfo{
+		HardwareHash:        savedHwHash,
+		Fingerprint:         sampleFingerprint,
+		SimilarityThreshold: defaultMatchPercent,
+	})
+
+	vaultMock := &fpFsVaultMock{}
+	vaultMock.On("Retrieve", vaultKey).Return(savedHwData, nil).Once()
+	vaultMock.On("Store", vaultKey, mock.Anything).Return(nil).Once()
+	vault = vaultMock
+
+	// Act
+	actual, err := generateFingerprint(logmocks.NewMockLog())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, sampleFingerprint, actual)
+	var migrated hwInfo
+	assert.NoError(t, json.Unmarshal(vaultMock.Calls[1].Arguments.Get(1).([]byte), &migrated))
+	assert.Equal(t, "WQL", migrated.HardwareSource)
+	assert.Equal(t, "wql-uuid", migrated.HardwareHash[hardwareID])
+	assert.Equal(t, sampleFingerprint, migrated.Fingerprint)
+}
+
+func TestGenerateFingerprint_MigratesHardwareSource_WhenSavedSourceUnavailable(t *testing.T) {
+	// Arrange
+	savedHwHash := map[string]string{hardwareID: "wmic-uuid", hostnameID: "host", macAddressID: "mac"}
+	stubHardwareSources(t, "WQL", nil, fmt.Errorf("WMIC is not available"))
+	savedHwData, _ := json.Marshal(hwInfo{
+		HardwareHash:        savedHwHash,
+		Fingerprint:         sampleFingerprint,
+		SimilarityThreshold: defaultMatchPercent,
+	})
+
+	vaultMock := &fpFsVaultMock{}
+	vaultMock.On("Retrieve", vaultKey).Return(savedHwData, nil).Once()
+	vaultMock.On("Store", vaultKey, mock.Anything).Return(nil).Once()
+	vault = vaultMock
+
+	// Act
+	actual, err := generateFingerprint(logmocks.NewMockLog())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, sampleFingerprint, actual)
+	vaultMock.AssertExpectations(t)
+}
+
+func TestMigrateHardwareSource_RejectsChangedHardware(t *testing.T) {
+	savedHwInfo := hwInfo{
+		HardwareHash:        map[string]string{hardwareID: "wmic-uuid", hostnameID: "host", macAddressID: "mac"},
+		Fingerprint:         sampleFingerprint,
+		SimilarityThreshold: defaultMatchPercent,
+	}
+	currentHash := map[string]string{hardwareID: "wql-uuid", hostnameID: "other-host", macAddressID: "mac"}
+
+	stubHardwareSources(t, "WQL", map[string]string{hardwareID: "other-uuid"}, nil)
+	assert.False(t, migrateHardwareSource(logmocks.NewMockLog(), savedHwInfo, currentHash, "WQL"))
+
+	stubHardwareSources(t, "WQL", nil, fmt.Errorf("WMIC is not available"))
+	assert.False(t, migrateHardwareSource(logmocks.NewMockLog(), savedHwInfo, currentHash, "WQL"))
+}
+
 func TestSave_SavesNewFingerprint(t *testing.T) {
 	// Arrange
 	sampleHwHash := getHwHash("backup")
@@ -387,6 +470,10 @@ func (v vaultStub) Retrieve(manifestFileNamePrefix string, key string) ([]byte,
 	return v.data, v.retrieveErr
 }
 
+func (v vaultStub) Remove(manifestFileNamePrefix string, key string) error {
+	return v.storeErr
+}
+
 // fakeLog is a test double for the seelog logger to verify that particular strings were written into the log
 // during a test.
 type fakeLog struct {
@@ -0,0 +1,88 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package fingerprint contains functions that helps identify an instance
+// generalize contains funcs required to prepare an instance for imaging
+package fingerprint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+)
+
+// regenerateMarkerFileName is created by Generalize and makes the next fingerprint computation start over
+const regenerateMarkerFileName = "FingerprintRegenerate"
+
+var regenerateMarkerPath = func() string {
+	return filepath.Join(appconfig.DefaultDataStorePath, regenerateMarkerFileName)
+}
+
+// Generalize prepares the instance for imaging, analogous to sysprep: it clears the fingerprint and the
+// hardware hash it was generated from and arms a regeneration on first boot, so every instance launched
+// from the image generates its own fingerprint instead of matching the hardware hash of the build instance.
+// The similarity threshold is kept. Shut the instance down after generalizing it.
+func Generalize(log log.T) error {
+	lock.Lock()
+	defer lock.Unlock()
+
+	if err := resetStoredHardwareInfo(log); err != nil {
+		return err
+	}
+	if err := os.WriteFile(regenerateMarkerPath(), []byte{}, appconfig.ReadWriteAccess); err != nil {
+		return fmt.Errorf("failed to arm fingerprint regeneration: %v", err)
+	}
+
+	fingerprint = ""
+	loaded = false
+	log.Info("Instance fingerprint has been generalized, a new fingerprint is generated on first boot")
+	return nil
+}
+
+// regenerateIfArmed clears the fingerprint left behind on a generalized image before it is used
+func regenerateIfArmed(log log.T) {
+	markerPath := regenerateMarkerPath()
+	if _, err := os.Stat(markerPath); err != nil {
+		return
+	}
+
+	log.Info("Instance was generalized, regenerating fingerprint")
+	if err := resetStoredHardwareInfo(log); err != nil {
+		log.Errorf("Failed to clear the fingerprint of the generalized instance: %v", err)
+		return
+	}
+	if err := os.Remove(markerPath); err != nil {
+		log.Warnf("Failed to remove fingerprint regeneration marker: %v", err)
+	}
+}
+
+// resetStoredHardwareInfo removes the fingerprint and the hardware hash from the vault, keeping the similarity threshold
+func resetStoredHardwareInfo(log log.T) error {
+	savedHwInfo, err := fetch(log)
+	if err != nil {
+		savedHwInfo = hwInfo{}
+	}
+
+	data, err := json.Marshal(hwInfo{SimilarityThreshold: savedHwInfo.SimilarityThreshold})
+	if err != nil {
+		return fmt.Errorf("failed to create empty hardware info: %v", err)
+	}
+	if err = vault.Store("", vaultKey, data); err != nil {
+		return fmt.Errorf("failed to store empty hardware info: %v", err)
+	}
+	return nil
+}
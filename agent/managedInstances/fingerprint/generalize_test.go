@@ -0,0 +1,109 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package fingerprint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	logmocks "github.com/aws/amazon-ssm-agent/agent/mocks/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupGeneralizeTest(t *testing.T) (markerPath string) {
+	originalVault, originalRegenerateMarkerPath, originalCurrentHwHash := vault, regenerateMarkerPath, currentHwHash
+	t.Cleanup(func() {
+		vault, regenerateMarkerPath, currentHwHash = originalVault, originalRegenerateMarkerPath, originalCurrentHwHash
+		fingerprint, loaded = "", false
+	})
+
+	markerPath = filepath.Join(t.TempDir(), regenerateMarkerFileName)
+	regenerateMarkerPath = func() string { return markerPath }
+	return markerPath
+}
+
+func TestGeneralize_ClearsFingerprintAndKeepsThreshold(t *testing.T) {
+	markerPath := setupGeneralizeTest(t)
+	savedHwData, _ := json.Marshal(hwInfo{
+		Fingerprint:         sampleFingerprint,
+		HardwareHash:        getHwHash("original"),
+		SimilarityThreshold: 60,
+	})
+	generalizedHwData, _ := json.Marshal(hwInfo{SimilarityThreshold: 60})
+
+	vaultMock := &fpFsVaultMock{}
+	vaultMock.On("Retrieve", vaultKey).Return(savedHwData, nil).Once()
+	vaultMock.On("Store", vaultKey, generalizedHwData).Return(nil).Once()
+	vault = vaultMock
+	fingerprint, loaded = sampleFingerprint, true
+
+	err := Generalize(logmocks.NewMockLog())
+
+	assert.NoError(t, err)
+	vaultMock.AssertExpectations(t)
+	assert.FileExists(t, markerPath)
+	assert.Empty(t, fingerprint)
+	assert.False(t, loaded)
+}
+
+func TestGeneralize_ReturnsError_WhenStoreFails(t *testing.T) {
+	markerPath := setupGeneralizeTest(t)
+	vault = vaultStub{storeErr: fmt.Errorf("access denied")}
+
+	err := Generalize(logmocks.NewMockLog())
+
+	assert.Error(t, err)
+	assert.NoFileExists(t, markerPath)
+}
+
+func TestInstanceFingerprint_RegeneratesWhenGeneralized(t *testing.T) {
+	markerPath := setupGeneralizeTest(t)
+	os.WriteFile(markerPath, []byte{}, 0600)
+	hwHash := getHwHash("original")
+	currentHwHash = func() (map[string]string, error) {
+		return hwHash, nil
+	}
+	// the build instance fingerprint is still in the vault, e.g. because the image was captured before
+	// the generalization was persisted, it must not be reused
+	savedHwData, _ := json.Marshal(hwInfo{
+		Fingerprint:         sampleFingerprint,
+		HardwareHash:        hwHash,
+		SimilarityThreshold: 60,
+	})
+	generalizedHwData, _ := json.Marshal(hwInfo{SimilarityThreshold: 60})
+
+	vaultMock := &fpFsVaultMock{}
+	vaultMock.On("Retrieve", vaultKey).Return(savedHwData, nil).Once()
+	vaultMock.On("Store", vaultKey, generalizedHwData).Return(nil).Once()
+	vaultMock.On("Retrieve", vaultKey).Return(generalizedHwData, nil)
+	vaultMock.On("Store", vaultKey, mock.Anything).Return(nil)
+	vault = vaultMock
+
+	actual, err := InstanceFingerprint(logmocks.NewMockLog())
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, actual)
+	assert.NotEqual(t, sampleFingerprint, actual)
+	assert.NoFileExists(t, markerPath)
+
+	storedHwData := ByteArrayArg(vaultMock.Calls[len(vaultMock.Calls)-1].Arguments, 1)
+	var stored hwInfo
+	assert.NoError(t, json.Unmarshal(storedHwData, &stored))
+	assert.Equal(t, actual, stored.Fingerprint)
+	assert.Equal(t, 60, stored.SimilarityThreshold, "the threshold kept by the generalization is used")
+}
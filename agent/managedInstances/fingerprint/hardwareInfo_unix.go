@@ -22,6 +22,7 @@ import (
 	"fmt"
 
 	"github.com/aws/amazon-ssm-agent/agent/fileutil"
+	"github.com/aws/amazon-ssm-agent/agent/log"
 )
 
 const (
@@ -38,14 +39,23 @@ var currentHwHash = func() (map[string]string, error) {
 	hardwareHash["memory-hash"], _ = memoryInfoHash()
 	hardwareHash["bios-hash"], _ = biosInfoHash()
 	hardwareHash["system-hash"], _ = systemInfoHash()
-	hardwareHash["hostname-info"], _ = hostnameInfo()
+	hardwareHash[hostnameID], _ = hostnameInfo()
 	hardwareHash[ipAddressID], _ = primaryIpInfo()
-	hardwareHash["macaddr-info"], _ = macAddrInfo()
+	hardwareHash[macAddressID], _ = macAddrInfo()
 	hardwareHash["disk-info"], _ = diskInfoHash()
 
 	return hardwareHash, nil
 }
 
+// currentHardwareSource returns the source of the hardware values, there is a single source on this platform
+var currentHardwareSource = func(log log.T) string {
+	return ""
+}
+
+var hwHashFromSource = func(log log.T, source string) (map[string]string, error) {
+	return currentHwHash()
+}
+
 func machineID() (string, error) {
 	if fileutil.Exists(systemDMachineIDPath) {
 		return fileutil.ReadAllText(systemDMachineIDPath)
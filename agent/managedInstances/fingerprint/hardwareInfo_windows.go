@@ -25,13 +25,16 @@ import (
 	"encoding/gob"
 	"fmt"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/fileutil"
 	"github.com/aws/amazon-ssm-agent/agent/log"
 	"github.com/aws/amazon-ssm-agent/agent/log/ssmlog"
 	"github.com/aws/amazon-ssm-agent/agent/platform"
 
+	"golang.org/x/sys/windows/registry"
 	"golang.org/x/sys/windows/svc"
 	"golang.org/x/sys/windows/svc/mgr"
 )
@@ -45,8 +48,16 @@ const (
 	serviceRetryInterval = 15 // Seconds
 	serviceRetry         = 5
 
-	wmic WMIInterface = "WMIC"
-	wql  WMIInterface = "WQL"
+	wmic           WMIInterface = appconfig.FingerprintHardwareSourceWMIC
+	wql            WMIInterface = appconfig.FingerprintHardwareSourceWQL
+	registrySource WMIInterface = appconfig.FingerprintHardwareSourceRegistry
+
+	// registry keys below HKEY_LOCAL_MACHINE read when WMI is unavailable
+	biosRegistryKey           = `HARDWARE\DESCRIPTION\System\BIOS`
+	processorRegistryKey      = `HARDWARE\DESCRIPTION\System\CentralProcessor\0`
+	hardwareConfigRegistryKey = `SYSTEM\HardwareConfig`
+	smbiosRegistryKey         = `SYSTEM\CurrentControlSet\Services\mssmbios\Data`
+	diskEnumRegistryKey       = `SYSTEM\CurrentControlSet\Services\disk\Enum`
 )
 
 func waitForService(log log.T, service *mgr.Service) error {
@@ -73,50 +84,115 @@ func waitForService(log log.T, service *mgr.Service) error {
 
 var wmicCommand = filepath.Join(appconfig.EnvWinDir, "System32", "wbem", "wmic.exe")
 
+var (
+	hardwareSourceLock     sync.Mutex
+	resolvedHardwareSource string
+)
+
 var currentHwHash = func() (map[string]string, error) {
 	log := ssmlog.SSMLogger(true)
+	return hwHashFromSource(log, currentHardwareSource(log))
+}
+
+// currentHardwareSource returns the source of the hardware values, WQL unless the configuration overrides it,
+// the registry is used when WMI is unavailable
+var currentHardwareSource = func(log log.T) string {
+	hardwareSourceLock.Lock()
+	defer hardwareSourceLock.Unlock()
+
+	if resolvedHardwareSource != "" {
+		return resolvedHardwareSource
+	}
+
+	source := appconfig.FingerprintHardwareSourceWQL
+	if config, err := appconfig.Config(false); err == nil && config.Agent.FingerprintHardwareSource != "" {
+		source = config.Agent.FingerprintHardwareSource
+	}
+	if source == appconfig.FingerprintHardwareSourceWMIC {
+		if capabilities, err := platform.GetWindowsCapabilities(log); err == nil && !capabilities.WmicAvailable {
+			log.Warnf("WMIC is not available, reading fingerprint hardware values with WQL")
+			source = appconfig.FingerprintHardwareSourceWQL
+		}
+	}
+	if source != appconfig.FingerprintHardwareSourceRegistry {
+		if err := waitForWMI(log); err != nil {
+			log.Warnf("WMI is unavailable, reading fingerprint hardware values from the registry: %v", err)
+			source = appconfig.FingerprintHardwareSourceRegistry
+		}
+	}
+
+	resolvedHardwareSource = source
+	return source
+}
+
+// hwHashFromSource reads the hardware values from the given source,
+// an empty source selects the WMI interface used before the source was saved with the fingerprint
+var hwHashFromSource = func(log log.T, source string) (map[string]string, error) {
 	hardwareHash := make(map[string]string)
 
+	wmiInterface := WMIInterface(source)
+	if wmiInterface != registrySource {
+		if err := waitForWMI(log); err != nil {
+			log.Warn("WMI Service cannot be query for hardware hash.")
+			return hardwareHash, err
+		}
+		if wmiInterface == "" {
+			wmiInterface = getWMIInterface(log)
+		} else if wmiInterface == wmic && !fileutil.Exists(wmicCommand) {
+			return hardwareHash, fmt.Errorf("WMIC is not available")
+		}
+	}
+
+	hardwareHash[hardwareID], _ = csproductUuid(log, wmiInterface)
+	hardwareHash["processor-hash"], _ = processorInfoHash(log, wmiInterface)
+	hardwareHash["memory-hash"], _ = memoryInfoHash(log, wmiInterface)
+	hardwareHash["bios-hash"], _ = biosInfoHash(log, wmiInterface)
+	hardwareHash["system-hash"], _ = systemInfoHash(log, wmiInterface)
+	hardwareHash[hostnameID], _ = hostnameInfo()
+	hardwareHash[ipAddressID], _ = primaryIpInfo()
+	hardwareHash[macAddressID], _ = macAddrInfo()
+	hardwareHash["disk-info"], _ = diskInfoHash(log, wmiInterface)
+
+	return hardwareHash, nil
+}
+
+// waitForWMI waits until the WMI service is running
+func waitForWMI(log log.T) error {
 	// Wait for WMI Service
 	winManager, err := mgr.Connect()
 	log.Debug("Waiting for WMI Service to be ready.....")
 	if err != nil {
 		log.Warnf("Failed to connect to WMI: '%v'", err)
-		return hardwareHash, err
+		return err
 	}
+	defer winManager.Disconnect()
 
 	// Open WMI Service
 	var wmiService *mgr.Service
 	wmiService, err = winManager.OpenService(wmiServiceName)
 	if err != nil {
 		log.Warnf("Failed to open wmi service: '%v'", err)
-		return hardwareHash, err
+		return err
 	}
+	defer wmiService.Close()
 
 	// Wait for WMI Service to start
 	if err = waitForService(log, wmiService); err != nil {
-		log.Warn("WMI Service cannot be query for hardware hash.")
-		return hardwareHash, err
+		return err
 	}
 
 	log.Debug("WMI Service is ready to be queried....")
-
-	wmiInterface := getWMIInterface(log)
-	hardwareHash[hardwareID], _ = csproductUuid(log, wmiInterface)
-	hardwareHash["processor-hash"], _ = processorInfoHash(log, wmiInterface)
-	hardwareHash["memory-hash"], _ = memoryInfoHash(log, wmiInterface)
-	hardwareHash["bios-hash"], _ = biosInfoHash(log, wmiInterface)
-	hardwareHash["system-hash"], _ = systemInfoHash(log, wmiInterface)
-	hardwareHash["hostname-info"], _ = hostnameInfo()
-	hardwareHash[ipAddressID], _ = primaryIpInfo()
-	hardwareHash["macaddr-info"], _ = macAddrInfo()
-	hardwareHash["disk-info"], _ = diskInfoHash(log, wmiInterface)
-
-	return hardwareHash, nil
+	return nil
 }
 
-// getWMIInterface returns WMI interface which should be used to retrieve hardware info data
+// getWMIInterface returns WMI interface used to retrieve hardware info data before the source was saved with the fingerprint
 func getWMIInterface(logger log.T) (wmiInterface WMIInterface) {
+	// WMIC is removed from newer builds regardless of the Windows version
+	if capabilities, err := platform.GetWindowsCapabilities(logger); err == nil && !capabilities.WmicAvailable {
+		logger.Debugf("WMIC is not available, returning WQL as WMI interface...")
+		return wql
+	}
+
 	windows2025OrLater, err := platform.IsPlatformWindowsServer2025OrLater(logger)
 	// if we fail to determine Windows version, default to WMIC
 	if err != nil {
@@ -143,6 +219,8 @@ func csproductUuid(logger log.T, wmiInterface WMIInterface) (encodedData string,
 		var csProductData platform.Win32_ComputerSystemProduct
 		encodedData, csProductData, err = getWMIObject(logger, csProductData)
 		uuid = csProductData.UUID
+	case registrySource:
+		encodedData, err = registryValuesHash(hardwareConfigRegistryKey, "LastConfig")
 	default:
 		logger.Warnf("Unknown WMI interface: %v", wmiInterface)
 	}
@@ -157,6 +235,8 @@ func processorInfoHash(logger log.T, wmiInterface WMIInterface) (encodedData str
 		encodedData, _, err = commandOutputHash(wmicCommand, "cpu", "list", "brief")
 	case wql:
 		encodedData, _, err = getWMIObject(logger, platform.Win32_Processor{})
+	case registrySource:
+		encodedData, err = registryValuesHash(processorRegistryKey, "ProcessorNameString", "Identifier", "VendorIdentifier")
 	default:
 		logger.Warnf("Unknown WMI interface: %v", wmiInterface)
 	}
@@ -170,6 +250,9 @@ func memoryInfoHash(logger log.T, wmiInterface WMIInterface) (encodedData string
 		encodedData, _, err = commandOutputHash(wmicCommand, "memorychip", "list", "brief")
 	case wql:
 		encodedData, _, err = getWMIObject(logger, platform.Win32_PhysicalMemory{})
+	case registrySource:
+		// the memory devices are only described by the raw SMBIOS tables
+		encodedData, err = registryValuesHash(smbiosRegistryKey, "SMBiosData")
 	default:
 		logger.Warnf("Unknown WMI interface: %v", wmiInterface)
 	}
@@ -183,6 +266,8 @@ func biosInfoHash(logger log.T, wmiInterface WMIInterface) (encodedData string,
 		encodedData, _, err = commandOutputHash(wmicCommand, "bios", "list", "brief")
 	case wql:
 		encodedData, _, err = getWMIObject(logger, platform.Win32_BIOS{})
+	case registrySource:
+		encodedData, err = registryValuesHash(biosRegistryKey, "BIOSVendor", "BIOSVersion", "BIOSReleaseDate")
 	default:
 		logger.Warnf("Unknown WMI interface: %v", wmiInterface)
 	}
@@ -196,6 +281,8 @@ func systemInfoHash(logger log.T, wmiInterface WMIInterface) (encodedData string
 		encodedData, _, err = commandOutputHash(wmicCommand, "computersystem", "list", "brief")
 	case wql:
 		encodedData, _, err = getWMIObject(logger, platform.Win32_ComputerSystem{})
+	case registrySource:
+		encodedData, err = registryValuesHash(biosRegistryKey, "SystemManufacturer", "SystemProductName", "SystemFamily", "BaseBoardProduct")
 	default:
 		logger.Warnf("Unknown WMI interface: %v", wmiInterface)
 	}
@@ -209,6 +296,8 @@ func diskInfoHash(logger log.T, wmiInterface WMIInterface) (encodedData string,
 		encodedData, _, err = commandOutputHash(wmicCommand, "diskdrive", "list", "brief")
 	case wql:
 		encodedData, _, err = getWMIObject(logger, platform.Win32_DiskDrive{})
+	case registrySource:
+		encodedData, err = registryValuesHash(diskEnumRegistryKey, "Count", "0", "1", "2", "3")
 	default:
 		logger.Warnf("Unknown WMI interface: %v", wmiInterface)
 	}
@@ -230,3 +319,27 @@ func getWMIObject[T interface{}](logger log.T, _ T) (encodedWmiObject string, wm
 	}
 	return
 }
+
+// registryValuesHash returns the hash of the values of a registry key below HKEY_LOCAL_MACHINE, missing values are hashed as empty
+func registryValuesHash(path string, names ...string) (encodedData string, err error) {
+	var key registry.Key
+	if key, err = registry.OpenKey(registry.LOCAL_MACHINE, path, registry.QUERY_VALUE); err != nil {
+		return
+	}
+	defer key.Close()
+
+	var content bytes.Buffer
+	for _, name := range names {
+		if size, _, sizeErr := key.GetValue(name, nil); sizeErr == nil && size > 0 {
+			data := make([]byte, size)
+			if _, _, valueErr := key.GetValue(name, data); valueErr == nil {
+				content.Write(data)
+			}
+		}
+		content.WriteByte(0)
+	}
+
+	sum := md5.Sum(content.Bytes())
+	encodedData = base64.StdEncoding.EncodeToString(sum[:])
+	return
+}
@@ -0,0 +1,100 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// package registration provides managed instance information
+package registration
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/managedInstances/fingerprint"
+)
+
+// ec2ManifestFileNamePrefix is the vault manifest the ec2 identity stores its registration under
+const ec2ManifestFileNamePrefix = "EC2"
+
+var (
+	registrationFilePath      = filepath.Join(appconfig.DefaultDataStorePath, "registration")
+	cachedCredentialsFilePath = appconfig.DefaultEC2SharedCredentialsFilePath
+	removeStoredFingerprint   = fingerprint.RemoveStoredHardwareInfo
+	generalizeFingerprint     = fingerprint.Generalize
+)
+
+// Cleanup removes the managed instance identity from the instance: the registration keys and instance id
+// stored in the vault, the instance fingerprint, the registration file and the credentials cached by the agent.
+// An image cloned after the cleanup registers as a new managed instance instead of sharing the identity.
+func Cleanup(log log.T) error {
+	var errs []error
+	if err := removeStoredFingerprint(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to remove instance fingerprint: %w", err))
+	}
+	errs = append(errs, removeRegistration()...)
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	log.Info("Managed instance registration has been removed from the instance")
+	return nil
+}
+
+// Generalize prepares the instance for imaging, analogous to sysprep: it removes the managed instance
+// registration like Cleanup does and arms a fingerprint regeneration on first boot, so the instances
+// launched from the image neither share the identity nor collide on the hardware hash of the build instance.
+// Shut the instance down after generalizing it.
+func Generalize(log log.T) error {
+	var errs []error
+	if err := generalizeFingerprint(log); err != nil {
+		errs = append(errs, fmt.Errorf("failed to generalize instance fingerprint: %w", err))
+	}
+	errs = append(errs, removeRegistration()...)
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	log.Info("Instance has been generalized, shut it down before capturing the image")
+	return nil
+}
+
+// removeRegistration removes the registrations from the vault, the registration file and the cached credentials
+func removeRegistration() (errs []error) {
+	entries := []struct{ manifestFileNamePrefix, vaultKey string }{
+		{"", RegVaultKey},
+		{ec2ManifestFileNamePrefix, EC2RegistrationVaultKey},
+	}
+	for _, region := range getFailoverRegions() {
+		entries = append(entries, struct{ manifestFileNamePrefix, vaultKey string }{"", RegionalRegVaultKey(region)})
+	}
+
+	lock.Lock()
+	for _, entry := range entries {
+		if err := vault.Remove(entry.manifestFileNamePrefix, entry.vaultKey); err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove %s from vault: %w", entry.vaultKey, err))
+		}
+	}
+	loadedServerInfo = instanceInfo{}
+	loadedServerInfoKey = ""
+	loadedServerManifestPrefix = ""
+	lock.Unlock()
+
+	for _, path := range []string{registrationFilePath, cachedCredentialsFilePath} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, fmt.Errorf("failed to remove %s: %w", path, err))
+		}
+	}
+	return errs
+}
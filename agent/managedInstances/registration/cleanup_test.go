@@ -0,0 +1,148 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// package registration provides managed instance information
+package registration
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	logmocks "github.com/aws/amazon-ssm-agent/agent/mocks/log"
+	"github.com/stretchr/testify/assert"
+)
+
+type removingVaultStub struct {
+	vaultStub
+	removed   *[]string
+	removeErr error
+}
+
+func (v removingVaultStub) Remove(manifestFileNamePrefix string, key string) error {
+	*v.removed = append(*v.removed, manifestFileNamePrefix+"/"+key)
+	return v.removeErr
+}
+
+func setupCleanupTest(t *testing.T, removeErr error) (removed *[]string, fingerprintRemoved *bool) {
+	originalVault, originalRegistrationFilePath, originalCachedCredentialsFilePath, originalRemoveStoredFingerprint, originalGeneralizeFingerprint :=
+		vault, registrationFilePath, cachedCredentialsFilePath, removeStoredFingerprint, generalizeFingerprint
+	t.Cleanup(func() {
+		vault, registrationFilePath, cachedCredentialsFilePath, removeStoredFingerprint, generalizeFingerprint =
+			originalVault, originalRegistrationFilePath, originalCachedCredentialsFilePath, originalRemoveStoredFingerprint, originalGeneralizeFingerprint
+		loadedServerInfo = instanceInfo{}
+	})
+
+	dir := t.TempDir()
+	registrationFilePath = filepath.Join(dir, "registration")
+	cachedCredentialsFilePath = filepath.Join(dir, "credentials")
+	os.WriteFile(registrationFilePath, []byte("{}"), 0600)
+	os.WriteFile(cachedCredentialsFilePath, []byte("[default]"), 0600)
+
+	removed = &[]string{}
+	vault = removingVaultStub{removed: removed, removeErr: removeErr}
+	fingerprintRemoved = new(bool)
+	removeStoredFingerprint = func() error {
+		*fingerprintRemoved = true
+		return nil
+	}
+	generalizeFingerprint = func(log.T) error {
+		t.Fatal("unexpected fingerprint generalization")
+		return nil
+	}
+	loadedServerInfo = sampleDest
+	return removed, fingerprintRemoved
+}
+
+func TestCleanup(t *testing.T) {
+	removed, fingerprintRemoved := setupCleanupTest(t, nil)
+
+	assert.NoError(t, Cleanup(logmocks.NewMockLog()))
+
+	assert.True(t, *fingerprintRemoved)
+	assert.Equal(t, []string{"/" + RegVaultKey, "EC2/" + EC2RegistrationVaultKey}, *removed)
+	assert.Equal(t, instanceInfo{}, loadedServerInfo)
+	assert.NoFileExists(t, registrationFilePath)
+	assert.NoFileExists(t, cachedCredentialsFilePath)
+}
+
+func TestCleanupRegionalRegistrations(t *testing.T) {
+	removed, _ := setupCleanupTest(t, nil)
+	originalGetFailoverRegions := getFailoverRegions
+	defer func() { getFailoverRegions = originalGetFailoverRegions }()
+	getFailoverRegions = func() []string { return []string{"us-east-1", "eu-west-1"} }
+
+	assert.NoError(t, Cleanup(logmocks.NewMockLog()))
+
+	assert.Equal(t, []string{
+		"/" + RegVaultKey,
+		"EC2/" + EC2RegistrationVaultKey,
+		"/" + RegionalRegVaultKey("us-east-1"),
+		"/" + RegionalRegVaultKey("eu-west-1"),
+	}, *removed)
+}
+
+func TestCleanupNothingRegistered(t *testing.T) {
+	setupCleanupTest(t, nil)
+	os.Remove(registrationFilePath)
+	os.Remove(cachedCredentialsFilePath)
+
+	assert.NoError(t, Cleanup(logmocks.NewMockLog()))
+}
+
+func TestCleanupVaultError(t *testing.T) {
+	removed, _ := setupCleanupTest(t, fmt.Errorf("access denied"))
+
+	err := Cleanup(logmocks.NewMockLog())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "access denied")
+	// the remaining identity is still removed
+	assert.Len(t, *removed, 2)
+	assert.NoFileExists(t, registrationFilePath)
+}
+
+func TestGeneralize(t *testing.T) {
+	removed, fingerprintRemoved := setupCleanupTest(t, nil)
+	generalized := false
+	generalizeFingerprint = func(log.T) error {
+		generalized = true
+		return nil
+	}
+
+	assert.NoError(t, Generalize(logmocks.NewMockLog()))
+
+	assert.True(t, generalized)
+	assert.False(t, *fingerprintRemoved, "the fingerprint is regenerated on first boot instead of being removed")
+	assert.Equal(t, []string{"/" + RegVaultKey, "EC2/" + EC2RegistrationVaultKey}, *removed)
+	assert.Equal(t, instanceInfo{}, loadedServerInfo)
+	assert.NoFileExists(t, registrationFilePath)
+	assert.NoFileExists(t, cachedCredentialsFilePath)
+}
+
+func TestGeneralizeFingerprintError(t *testing.T) {
+	removed, _ := setupCleanupTest(t, nil)
+	generalizeFingerprint = func(log.T) error {
+		return fmt.Errorf("read-only file system")
+	}
+
+	err := Generalize(logmocks.NewMockLog())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "read-only file system")
+	// the registration is still removed
+	assert.Len(t, *removed, 2)
+	assert.NoFileExists(t, registrationFilePath)
+}
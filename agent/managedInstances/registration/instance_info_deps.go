@@ -40,6 +40,7 @@ type iiVault interface {
 	Retrieve(manifestFileNamePrefix string, key string) (data []byte, err error)
 	Store(manifestFileNamePrefix string, key string, data []byte) (err error)
 	IsManifestExists(manifestFileNamePrefix string) bool
+	Remove(manifestFileNamePrefix string, key string) (err error)
 }
 
 type iiFsVault struct{}
@@ -53,3 +54,6 @@ func (iiFsVault) Store(manifestFileNamePrefix string, key string, data []byte) e
 func (iiFsVault) IsManifestExists(manifestFileNamePrefix string) bool {
 	return fsvault.IsManifestExists(manifestFileNamePrefix)
 }
+func (iiFsVault) Remove(manifestFileNamePrefix string, key string) error {
+	return fsvault.Remove(manifestFileNamePrefix, key)
+}
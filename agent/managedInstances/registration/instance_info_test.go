@@ -186,6 +186,10 @@ func (v vaultStub) Retrieve(manifestFileNamePrefix string, key string) ([]byte,
 	return v.data, v.err
 }
 
+func (v vaultStub) Remove(manifestFileNamePrefix string, key string) error {
+	return v.err
+}
+
 func (v vaultStub) IsManifestExists(manifestFileNamePrefix string) bool {
 	if manifestFileNamePrefix != v.manifestFileNamePrefix {
 		panic(fmt.Errorf("incorrect manifestFileNamePrefix passed"))
@@ -0,0 +1,99 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// package registration provides managed instance information
+package registration
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+)
+
+// getFailoverRegions returns the regions the instance may hold secondary registrations for
+var getFailoverRegions = func() []string {
+	config, err := appconfig.Config(true)
+	if err != nil {
+		return nil
+	}
+	return config.Identity.RegionFailover.Regions
+}
+
+// RegionalRegVaultKey returns the vault key of the secondary registration of a hybrid instance in the region.
+// The active registration is always stored under RegVaultKey, whatever its region.
+func RegionalRegVaultKey(region string) string {
+	return RegVaultKey + "-" + region
+}
+
+// HasRegionalRegistration returns true when a valid secondary registration is stored for the region
+func HasRegionalRegistration(region string) bool {
+	info, err := readServerInfo("", RegionalRegVaultKey(region))
+	return err == nil && info.InstanceID != "" && info.PrivateKey != "" && info.Region == region
+}
+
+// ActivateRegionalRegistration makes the secondary registration of the region the active registration.
+// The registration active so far is kept as the secondary registration of its own region, so the agent can fail back.
+// The agent processes must be restarted to pick up the activated registration.
+func ActivateRegionalRegistration(log log.T, region string) error {
+	secondary, err := readServerInfo("", RegionalRegVaultKey(region))
+	if err != nil {
+		return fmt.Errorf("failed to load registration for region %v: %v", region, err)
+	}
+	if secondary.InstanceID == "" || secondary.PrivateKey == "" || secondary.Region != region {
+		return fmt.Errorf("no valid registration for region %v", region)
+	}
+
+	active, err := readServerInfo("", RegVaultKey)
+	if err != nil {
+		return fmt.Errorf("failed to load active registration: %v", err)
+	}
+	if active.InstanceID != "" && active.Region != "" && active.Region != region {
+		if err = updateServerInfo(active, "", RegionalRegVaultKey(active.Region)); err != nil {
+			return fmt.Errorf("failed to keep registration of region %v: %v", active.Region, err)
+		}
+	}
+	if err = updateServerInfo(secondary, "", RegVaultKey); err != nil {
+		return fmt.Errorf("failed to activate registration of region %v: %v", region, err)
+	}
+	if err = vault.Remove("", RegionalRegVaultKey(region)); err != nil {
+		log.Warnf("Failed to remove secondary registration of region %v: %v", region, err)
+	}
+
+	// the registration file mirrors the active registration for tooling reading it
+	if regData, err := json.Marshal(map[string]string{"ManagedInstanceID": secondary.InstanceID, "Region": secondary.Region}); err == nil {
+		if err = os.WriteFile(registrationFilePath, regData, appconfig.ReadWriteAccess); err != nil {
+			log.Warnf("Failed to update registration file: %v", err)
+		}
+	}
+	log.Infof("Activated registration %v of region %v", secondary.InstanceID, region)
+	return nil
+}
+
+// readServerInfo reads the instance info stored under the vault key without replacing the loaded instance info
+func readServerInfo(manifestFileNamePrefix, vaultKey string) (info instanceInfo, err error) {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	if !vault.IsManifestExists(manifestFileNamePrefix) {
+		return info, nil
+	}
+	data, err := vault.Retrieve(manifestFileNamePrefix, vaultKey)
+	if err != nil {
+		return info, err
+	}
+	err = json.Unmarshal(data, &info)
+	return info, err
+}
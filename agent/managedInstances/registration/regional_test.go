@@ -0,0 +1,115 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// package registration provides managed instance information
+package registration
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	logmocks "github.com/aws/amazon-ssm-agent/agent/mocks/log"
+	"github.com/stretchr/testify/assert"
+)
+
+// mapVaultStub keeps the stored values by key
+type mapVaultStub map[string][]byte
+
+func (v mapVaultStub) Store(manifestFileNamePrefix string, key string, data []byte) error {
+	v[key] = data
+	return nil
+}
+
+func (v mapVaultStub) Retrieve(manifestFileNamePrefix string, key string) ([]byte, error) {
+	if data, found := v[key]; found {
+		return data, nil
+	}
+	return nil, fmt.Errorf("%s does not exist", key)
+}
+
+func (v mapVaultStub) Remove(manifestFileNamePrefix string, key string) error {
+	delete(v, key)
+	return nil
+}
+
+func (v mapVaultStub) IsManifestExists(manifestFileNamePrefix string) bool {
+	return true
+}
+
+func setupRegionalTest(t *testing.T, infos map[string]instanceInfo) mapVaultStub {
+	originalVault, originalRegistrationFilePath := vault, registrationFilePath
+	t.Cleanup(func() {
+		vault, registrationFilePath = originalVault, originalRegistrationFilePath
+		loadedServerInfo = instanceInfo{}
+	})
+	registrationFilePath = filepath.Join(t.TempDir(), "registration")
+
+	stub := mapVaultStub{}
+	for key, info := range infos {
+		data, _ := json.Marshal(info)
+		stub[key] = data
+	}
+	vault = stub
+	return stub
+}
+
+func readStub(t *testing.T, stub mapVaultStub, key string) instanceInfo {
+	var info instanceInfo
+	assert.NoError(t, json.Unmarshal(stub[key], &info))
+	return info
+}
+
+func TestHasRegionalRegistration(t *testing.T) {
+	setupRegionalTest(t, map[string]instanceInfo{
+		RegionalRegVaultKey("eu-west-1"): {InstanceID: "mi-2", Region: "eu-west-1", PrivateKey: "key2"},
+		RegionalRegVaultKey("us-west-2"): {InstanceID: "mi-3", Region: "us-east-1", PrivateKey: "key3"},
+	})
+
+	assert.True(t, HasRegionalRegistration("eu-west-1"))
+	assert.False(t, HasRegionalRegistration("us-west-2"), "registration of another region")
+	assert.False(t, HasRegionalRegistration("ap-south-1"))
+}
+
+func TestActivateRegionalRegistration(t *testing.T) {
+	primary := instanceInfo{InstanceID: "mi-1", Region: "us-east-1", PrivateKey: "key1"}
+	secondary := instanceInfo{InstanceID: "mi-2", Region: "eu-west-1", PrivateKey: "key2"}
+	stub := setupRegionalTest(t, map[string]instanceInfo{
+		RegVaultKey:                      primary,
+		RegionalRegVaultKey("eu-west-1"): secondary,
+	})
+
+	assert.NoError(t, ActivateRegionalRegistration(logmocks.NewMockLog(), "eu-west-1"))
+
+	assert.Equal(t, secondary, readStub(t, stub, RegVaultKey))
+	assert.Equal(t, primary, readStub(t, stub, RegionalRegVaultKey("us-east-1")))
+	assert.NotContains(t, stub, RegionalRegVaultKey("eu-west-1"))
+	registrationFile, err := os.ReadFile(registrationFilePath)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"ManagedInstanceID":"mi-2","Region":"eu-west-1"}`, string(registrationFile))
+
+	// and back
+	assert.NoError(t, ActivateRegionalRegistration(logmocks.NewMockLog(), "us-east-1"))
+	assert.Equal(t, primary, readStub(t, stub, RegVaultKey))
+	assert.Equal(t, secondary, readStub(t, stub, RegionalRegVaultKey("eu-west-1")))
+}
+
+func TestActivateRegionalRegistration_Missing(t *testing.T) {
+	primary := instanceInfo{InstanceID: "mi-1", Region: "us-east-1", PrivateKey: "key1"}
+	stub := setupRegionalTest(t, map[string]instanceInfo{RegVaultKey: primary})
+
+	assert.Error(t, ActivateRegionalRegistration(logmocks.NewMockLog(), "eu-west-1"))
+	assert.Equal(t, primary, readStub(t, stub, RegVaultKey))
+}
@@ -32,6 +32,9 @@ type IInteractor interface {
 	// PreProcessorClose lists the actions to do before processor stop
 	// This should be called before processor close
 	PreProcessorClose()
+	// FlushReplies sends the replies which failed to reach the service earlier
+	// This should be called after processor drain, before processor close
+	FlushReplies()
 	// Close closes interactor
 	Close() error
 }
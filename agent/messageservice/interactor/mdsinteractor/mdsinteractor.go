@@ -33,11 +33,13 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/messageservice/interactor"
 	messageHandler "github.com/aws/amazon-ssm-agent/agent/messageservice/messagehandler"
 	"github.com/aws/amazon-ssm-agent/agent/messageservice/utils"
+	"github.com/aws/amazon-ssm-agent/agent/metrics"
 	"github.com/aws/amazon-ssm-agent/agent/platform"
 	messageContracts "github.com/aws/amazon-ssm-agent/agent/runcommand/contracts"
 	mdsService "github.com/aws/amazon-ssm-agent/agent/runcommand/mds"
 	"github.com/aws/amazon-ssm-agent/agent/sdkutil"
 	"github.com/aws/amazon-ssm-agent/agent/ssmconnectionchannel"
+	"github.com/aws/amazon-ssm-agent/agent/tracing"
 	"github.com/aws/aws-sdk-go/service/ssmmds"
 	"github.com/carlescere/scheduler"
 )
@@ -52,6 +54,9 @@ type MDSInteractor struct {
 	sendReplyJob            *scheduler.Job
 	messagePollWaitGroup    *sync.WaitGroup
 	lastPollTime            time.Time
+	lastMessageTime         time.Time
+	stopPollDelay           chan struct{}
+	stopPollDelayOnce       sync.Once
 	mutex                   sync.RWMutex
 	processorStopPolicy     *sdkutil.StopPolicy
 	messageHandler          messageHandler.IMessageHandler
@@ -89,6 +94,7 @@ const (
 var (
 	timeAfter                            = time.After
 	isPlatformWindowsServer2012OrEarlier = platform.IsPlatformWindowsServer2012OrEarlier
+	waitForControlChannel                = ssmconnectionchannel.WaitForControlChannel
 )
 
 // New initiates and returns MDS Interactor when needed
@@ -148,6 +154,8 @@ func New(context context.T, msgHandler messageHandler.IMessageHandler, service m
 		messageHandler:       msgHandler,
 		ackSkipCodes:         ackSkipCodes,
 		stopJobChannel:       make(chan interface{}, 1),
+		lastMessageTime:      time.Now(),
+		stopPollDelay:        make(chan struct{}),
 	}
 	// registers reply chan to message handler for receiving replies with UpstreamServiceName as MessageDeliveryService
 	msgHandler.RegisterReply(contracts.MessageDeliveryService, mdsInteract.replyChan)
@@ -206,6 +214,12 @@ func (mds *MDSInteractor) PreProcessorClose() {
 	return
 }
 
+// FlushReplies sends the replies which failed to reach MDS earlier, the send reply job is already closed at this point
+func (mds *MDSInteractor) FlushReplies() {
+	mds.context.Log().Infof("flushing failed replies in %v", Name)
+	mds.sendReplyLoop()
+}
+
 // Close closes connection. The closing operations for MDS interactor is done in BeforeClose itself.
 // Hence, this function does not operation now.
 func (mds *MDSInteractor) Close() error {
@@ -219,9 +233,14 @@ func (mds *MDSInteractor) Close() error {
 // postCommandProcessorInitialization is the post initialization handler which will get executed after the CommandProcessor is launched in the MessageHandler.
 // this function basically schedules messagePollLoop
 func (mds *MDSInteractor) postCommandProcessorInitialization() {
-	mds.startMDSPollingJob()
-	// This goroutine will be closed when the channel is closed in MGS Interactor
-	go mds.mdsSwitcher()
+	go func() {
+		// long polling is started once the control channel is set up so it does not delay Session Manager readiness,
+		// commands sent through MDS before switching to MGS are still pulled before MDS is switched off
+		waitForControlChannel(ssmconnectionchannel.ControlChannelWaitTimeout)
+		mds.startMDSPollingJob()
+		// This goroutine will be closed when the channel is closed in MGS Interactor
+		go mds.mdsSwitcher()
+	}()
 	return
 }
 
@@ -250,7 +269,11 @@ func (mds *MDSInteractor) listenReply() {
 			payloadDoc = utils.PrepareReplyPayloadFromIntermediatePluginResults(mds.context.Log(), pluginID, mds.config.AgentInfo, result.PluginResults, nil)
 		}
 
+		replyStart := time.Now()
 		mds.processSendReply(result.MessageID, payloadDoc)
+		if pluginID == "" {
+			tracing.EndDocumentTrace(result.MessageID, result.Status, replyStart, nil)
+		}
 		log.Debugf("ended processing reply: %v", result.MessageID)
 	}
 }
@@ -400,6 +423,13 @@ func (mds *MDSInteractor) preDocumentProcessorClose() {
 
 	mds.setMDSState(MDSShutDown)
 
+	// Cut any wait between long polls short
+	mds.stopPollDelayOnce.Do(func() {
+		if mds.stopPollDelay != nil {
+			close(mds.stopPollDelay)
+		}
+	})
+
 	// Stop polling job takes 1 min to complete. This signal preempts this job to stop the agent quickly
 	mds.sendStopSignalTsoStopJob()
 
@@ -456,6 +486,15 @@ func (mds *MDSInteractor) messagePollLoop() {
 		time.Sleep(time.Duration(2000+rand.Intn(500)) * time.Millisecond)
 	}
 
+	// Wait between long polls while idle or in quiet hours to reduce the number of GetMessages calls
+	if delay := pollDelay(mds.context.AppConfig().Mds, time.Now(), mds.getLastMessageTime()); delay > 0 {
+		log.Debugf("Waiting %v before the next message poll", delay)
+		select {
+		case <-timeAfter(delay):
+		case <-mds.stopPollDelay:
+		}
+	}
+
 	// check if any other poll loop has started in the meantime
 	// to prevent any possible race condition due to the scheduler
 	if pollStartTime.Equal(mds.getLastPollTime()) {
@@ -478,10 +517,23 @@ func (mds *MDSInteractor) updateLastPollTime(currentTime time.Time) {
 	mds.lastPollTime = currentTime
 }
 
+func (mds *MDSInteractor) getLastMessageTime() time.Time {
+	mds.mutex.RLock()
+	defer mds.mutex.RUnlock()
+	return mds.lastMessageTime
+}
+
+func (mds *MDSInteractor) updateLastMessageTime(currentTime time.Time) {
+	mds.mutex.Lock()
+	defer mds.mutex.Unlock()
+	mds.lastMessageTime = currentTime
+}
+
 func (mds *MDSInteractor) processMessage(msg *ssmmds.Message) {
 	var (
-		docState *contracts.DocumentState
-		err      error
+		docState    *contracts.DocumentState
+		err         error
+		receiveTime = time.Now()
 	)
 
 	// create separate logger that includes messageID with every log message
@@ -494,7 +546,9 @@ func (mds *MDSInteractor) processMessage(msg *ssmmds.Message) {
 	}
 
 	if strings.HasPrefix(*msg.Topic, string(utils.SendCommandTopicPrefix)) {
+		parseStart := time.Now()
 		docState, err = utils.ParseSendCommandMessage(mdsContext, toInstanceMessage(msg), mds.orchestrationRootDir, contracts.MessageDeliveryService)
+		tracing.StartDocumentTrace(*msg.MessageId, contracts.MessageDeliveryService, contracts.SendCommand, receiveTime, parseStart, err)
 		if err != nil {
 			log.Error(err)
 			mds.sendDocLevelResponse(*msg.MessageId, contracts.ResultStatusFailed, err.Error())
@@ -515,6 +569,8 @@ func (mds *MDSInteractor) processMessage(msg *ssmmds.Message) {
 		return
 	}
 
+	// recorded before submission as the document may complete before this function returns
+	tracing.RecordSpan(*msg.MessageId, tracing.SpanReceive, receiveTime, time.Now(), nil, nil)
 	errorCode := mds.messageHandler.Submit(docState)
 
 	// showLog is used to minimize warn log during ProcessorBufferFull error
@@ -536,6 +592,7 @@ func (mds *MDSInteractor) processMessage(msg *ssmmds.Message) {
 	// we skip for the following error codes
 	if _, ok := mds.ackSkipCodes[errorCode]; ok {
 		log.Warnf("skipped document %v due to the error: %v", docState.DocumentInformation.MessageID, errorCode)
+		tracing.EndTrace(*msg.MessageId, fmt.Errorf("skipped document due to the error: %v", errorCode), nil)
 		return
 	}
 	log.Debugf("Pushed document type %v to channel for processing", docState.DocumentType)
@@ -581,6 +638,7 @@ func (mds *MDSInteractor) pollOnce() {
 
 	if len(messages.Messages) > 0 {
 		log.Debugf("Got %v messages", len(messages.Messages))
+		mds.updateLastMessageTime(time.Now())
 	}
 
 	for _, msg := range messages.Messages {
@@ -622,7 +680,7 @@ func (mds *MDSInteractor) sendFailedReplies() {
 	log.Infof("Found document replies that need to be sent to the service")
 	for _, reply := range replies {
 		log.Debug("Loading reply ", reply)
-		if utils.IsValidReplyRequest(reply, contracts.MessageDeliveryService) == false {
+		if utils.IsValidReplyRequest(reply, contracts.MessageDeliveryService, utils.ReplyRetentionHours(mds.context.AppConfig())) == false {
 			log.Debug("Reply is old, document execution must have timed out. Deleting the reply")
 			mds.service.DeleteFailedReply(log, reply)
 			continue
@@ -665,6 +723,7 @@ func (mds *MDSInteractor) reset() {
 	// creating a new mds service object for the retry
 	// this is extra insurance to avoid service object getting corrupted - adding resiliency
 	mds.service = newMdsService(mds.context)
+	metrics.MDSReconnects.Inc("")
 }
 
 func (mds *MDSInteractor) processSendReply(messageID string, payloadDoc messageContracts.SendReplyPayload) {
@@ -0,0 +1,60 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package mdsinteractor
+
+import (
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/times"
+)
+
+// idlePollInitialDelay is the wait between long polls once the agent has been idle for the configured period.
+// It doubles for every further idle period up to MaxIdlePollDelaySeconds.
+const idlePollInitialDelay = 15 * time.Second
+
+// pollDelay returns how long to wait before the next long poll, the longer of the idle back off and any active quiet hours.
+func pollDelay(config appconfig.MdsCfg, now time.Time, lastMessageTime time.Time) time.Duration {
+	delay := idlePollDelay(config, now.Sub(lastMessageTime))
+	for _, window := range config.QuietHours {
+		windowDelay := time.Duration(window.PollDelaySeconds) * time.Second
+		if windowDelay <= delay {
+			continue
+		}
+		if _, ok := times.DailyWindowEnd(now, window.Start, window.End, window.Days); ok {
+			delay = windowDelay
+		}
+	}
+	return delay
+}
+
+// idlePollDelay returns the wait between long polls after no message has arrived for the given duration.
+func idlePollDelay(config appconfig.MdsCfg, idle time.Duration) time.Duration {
+	if config.IdlePollBackoffAfterMinutes <= 0 {
+		return 0
+	}
+	threshold := time.Duration(config.IdlePollBackoffAfterMinutes) * time.Minute
+	if idle < threshold {
+		return 0
+	}
+	maxDelay := time.Duration(config.MaxIdlePollDelaySeconds) * time.Second
+	delay := idlePollInitialDelay
+	for periods := idle / threshold; periods > 1 && delay < maxDelay; periods-- {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
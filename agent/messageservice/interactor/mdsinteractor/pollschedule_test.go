@@ -0,0 +1,52 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package mdsinteractor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdlePollDelay(t *testing.T) {
+	config := appconfig.MdsCfg{IdlePollBackoffAfterMinutes: 60, MaxIdlePollDelaySeconds: 300}
+
+	assert.Equal(t, time.Duration(0), idlePollDelay(config, 59*time.Minute))
+	assert.Equal(t, 15*time.Second, idlePollDelay(config, 61*time.Minute))
+	assert.Equal(t, 30*time.Second, idlePollDelay(config, 2*time.Hour))
+	assert.Equal(t, 60*time.Second, idlePollDelay(config, 3*time.Hour))
+	assert.Equal(t, 300*time.Second, idlePollDelay(config, 48*time.Hour))
+
+	config.IdlePollBackoffAfterMinutes = 0
+	assert.Equal(t, time.Duration(0), idlePollDelay(config, 48*time.Hour))
+}
+
+func TestPollDelay(t *testing.T) {
+	now := time.Date(2024, 1, 5, 23, 0, 0, 0, time.Local)
+	config := appconfig.MdsCfg{
+		IdlePollBackoffAfterMinutes: 60,
+		MaxIdlePollDelaySeconds:     300,
+		QuietHours: []*appconfig.MdsQuietHoursCfg{
+			{DailyTimeWindowCfg: appconfig.DailyTimeWindowCfg{Start: "22:00", End: "06:00"}, PollDelaySeconds: 120},
+			{DailyTimeWindowCfg: appconfig.DailyTimeWindowCfg{Start: "12:00", End: "13:30"}, PollDelaySeconds: 600},
+		},
+	}
+
+	assert.Equal(t, 120*time.Second, pollDelay(config, now, now.Add(-time.Minute)))
+	assert.Equal(t, 300*time.Second, pollDelay(config, now, now.Add(-24*time.Hour)))
+	assert.Equal(t, time.Duration(0), pollDelay(config, now.Add(-3*time.Hour), now.Add(-3*time.Hour)))
+	assert.Equal(t, time.Duration(0), pollDelay(appconfig.MdsCfg{}, now, time.Time{}))
+}
@@ -42,6 +42,8 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/session/retry"
 	"github.com/aws/amazon-ssm-agent/agent/session/service"
 	"github.com/aws/amazon-ssm-agent/agent/ssmconnectionchannel"
+	"github.com/aws/amazon-ssm-agent/agent/tracing"
+	"github.com/aws/amazon-ssm-agent/common/networkready"
 	"github.com/gorilla/websocket"
 	"github.com/twinj/uuid"
 )
@@ -152,10 +154,13 @@ func (mgs *MGSInteractor) GetName() string {
 func (mgs *MGSInteractor) GetSupportedWorkers() []utils.WorkerName {
 	workers := make([]utils.WorkerName, 0)
 	// This is added to block command processor wrapper load for containers
-	if !mgs.context.AppConfig().Agent.ContainerMode {
+	appConfig := mgs.context.AppConfig()
+	if !appConfig.Agent.ContainerMode && !appConfig.Modules.DisableRunCommand {
 		workers = append(workers, utils.DocumentWorkerName)
 	}
-	workers = append(workers, utils.SessionWorkerName)
+	if !appConfig.Modules.DisableSessions {
+		workers = append(workers, utils.SessionWorkerName)
+	}
 	return workers
 }
 
@@ -251,6 +256,12 @@ func (mgs *MGSInteractor) PreProcessorClose() {
 	mgs.stopUpdateReplyFileWatcher()
 }
 
+// FlushReplies sends the replies which failed to reach MGS earlier, the send failed reply job is already closed at this point
+func (mgs *MGSInteractor) FlushReplies() {
+	mgs.context.Log().Info("flushing failed replies in MGS interactor")
+	mgs.resendFailedReplies(func() bool { return true })
+}
+
 // Close closes the existing MGS connection
 func (mgs *MGSInteractor) Close() (err error) {
 	log := mgs.context.Log()
@@ -310,7 +321,11 @@ externalLoop:
 				log.Debugf("Could not parse result %v ", err)
 			}
 			log.Debugf("Processing reply message %v", jsonutil.Indent(replyStr))
+			replyStart := time.Now()
 			mgs.sendReplyProp.reply <- replyObjectLocalContract
+			if reply.LastPlugin == "" {
+				tracing.EndDocumentTrace(reply.MessageID, reply.Status, replyStart, nil)
+			}
 		}
 	}
 	close(mgs.listenReplyThreadEnded)
@@ -348,17 +363,23 @@ func (mgs *MGSInteractor) listenIncomingAgentMessages() {
 }
 
 func (mgs *MGSInteractor) processSessionRelatedMessages(agentMessage mgsContracts.AgentMessage) {
+	receiveTime := time.Now()
 	appConfig := mgs.context.AppConfig()
 	blockChan := make(chan struct{})
 	log := mgs.context.Log()
 	shortInstanceId, _ := mgs.context.Identity().ShortInstanceID()
 	sessionOrchestrationRootDir := filepath.Join(appconfig.DefaultDataStorePath, shortInstanceId, appconfig.DefaultSessionRootDirName, appConfig.Agent.OrchestrationRootDir)
+	parseStart := time.Now()
 	docState, err := agentMessage.ParseAgentMessage(mgs.context, sessionOrchestrationRootDir, mgs.agentConfig.InstanceID)
 	if err != nil {
 		log.Errorf("Cannot parse AgentTask message to documentState: %s, err: %v.", agentMessage.MessageId, err)
 		return
 	}
+	if docState.DocumentType == contracts.StartSession {
+		tracing.StartDocumentTrace(docState.DocumentInformation.MessageID, contracts.MessageGatewayService, docState.DocumentType, receiveTime, parseStart, nil)
+	}
 	log.Debugf("Pushing %s message %s to MessageHandler incoming message chan", agentMessage.MessageType, agentMessage.MessageId)
+	tracing.RecordSpan(docState.DocumentInformation.MessageID, tracing.SpanReceive, receiveTime, time.Now(), nil, nil)
 	var errorCode messagehandler.ErrorCode
 	retryLimit := 5
 	// 5 retries for ProcessorBufferFull. This should not happen most of the time as we have a higher default session limit.
@@ -375,6 +396,7 @@ func (mgs *MGSInteractor) processSessionRelatedMessages(agentMessage mgsContract
 		log.Debugf("pushed message %s with document id %s to processor", agentMessage.MessageId.String(), docState.DocumentInformation.DocumentID)
 		return
 	}
+	tracing.EndTrace(docState.DocumentInformation.MessageID, fmt.Errorf("dropped session message due to the error: %v", errorCode), nil)
 	if errorCode == messagehandler.ProcessorBufferFull {
 		log.Errorf("blocking control channel because of error code in session processor: %v", errorCode)
 		blockChan <- struct{}{}
@@ -401,6 +423,7 @@ func (mgs *MGSInteractor) processJobReplyAck(log log.T, agentMessage mgsContract
 }
 
 func (mgs *MGSInteractor) processAgentJobMessage(agentMessage mgsContracts.AgentMessage) {
+	receiveTime := time.Now()
 	appConfig := mgs.context.AppConfig()
 	log := mgs.context.Log()
 	if !mgs.isChannelOpenForAgentJobMsgs() {
@@ -410,12 +433,14 @@ func (mgs *MGSInteractor) processAgentJobMessage(agentMessage mgsContracts.Agent
 
 	shortInstanceId, _ := mgs.context.Identity().ShortInstanceID()
 	commandOrchestrationRootDir := filepath.Join(appconfig.DefaultDataStorePath, shortInstanceId, appconfig.DefaultDocumentRootDirName, appConfig.Agent.OrchestrationRootDir)
+	parseStart := time.Now()
 	docState, err := agentMessage.ParseAgentMessage(mgs.context, commandOrchestrationRootDir, mgs.agentConfig.InstanceID)
 	// just dropping all errors - MDS will take care of these messages
 	// we should handle few errors differently in future
 	if err != nil {
 		log.Errorf("dropping message because cannot parse AgentJob message %s to Document State, err: %v", agentMessage.MessageId.String(), err)
 		agentJobId, _ := agentMessage.GetAgentJobId(mgs.context)
+		tracing.StartDocumentTrace(agentJobId, contracts.MessageGatewayService, contracts.SendCommand, receiveTime, parseStart, err)
 		commandId, _ := messageContracts.GetCommandID(agentJobId)
 		mgs.buildAgentJobAckMessageAndSend(agentMessage.MessageId, agentJobId, agentMessage.CreatedDate, messagehandler.AgentJobMessageParseError)
 		docState = &contracts.DocumentState{
@@ -429,17 +454,21 @@ func (mgs *MGSInteractor) processAgentJobMessage(agentMessage mgsContracts.Agent
 		mgs.sendDocResponse(payloadDoc, docState)
 		return
 	} else {
+		tracing.StartDocumentTrace(docState.DocumentInformation.MessageID, contracts.MessageGatewayService, docState.DocumentType, receiveTime, parseStart, nil)
 		if mgs.context.AppConfig().Agent.ContainerMode {
+			tracing.EndTrace(docState.DocumentInformation.MessageID, fmt.Errorf("job messages are not supported for containers"), nil)
 			log.Errorf("dropping message because job messages are not supported for containers: %s", agentMessage.MessageId.String())
 			mgs.buildAgentJobAckMessageAndSend(agentMessage.MessageId, docState.DocumentInformation.MessageID, agentMessage.CreatedDate, messagehandler.ContainerNotSupported)
 			return
 		}
 		log.Debugf("pushing AgentJob message %s to MessageHandler incoming message chan", agentMessage.MessageId.String())
+		tracing.RecordSpan(docState.DocumentInformation.MessageID, tracing.SpanReceive, receiveTime, time.Now(), nil, nil)
 		errorCode := mgs.messageHandler.Submit(docState)
 		if errorCode != "" {
 			log.Warnf("dropping message %v because of error code %v", docState.DocumentInformation.DocumentID, errorCode)
 			if _, ok := mgs.ackSkipCodes[errorCode]; ok {
 				mgs.buildAgentJobAckMessageAndSend(agentMessage.MessageId, docState.DocumentInformation.MessageID, agentMessage.CreatedDate, errorCode)
+				tracing.EndTrace(docState.DocumentInformation.MessageID, fmt.Errorf("dropped message due to the error: %v", errorCode), nil)
 				return
 			}
 		}
@@ -564,6 +593,7 @@ var setupControlChannel = func(context context.T, mgsService service.Service, in
 		InitialDelayInMilli: rand.Intn(mgsConfig.ControlChannelRetryInitialDelayMillis) + mgsConfig.ControlChannelRetryInitialDelayMillis,
 		MaxDelayInMilli:     mgsConfig.ControlChannelRetryMaxIntervalMillis,
 		MaxAttempts:         mgsConfig.ControlChannelNumMaxRetries,
+		WakeUp:              func() <-chan struct{} { return networkready.Changed(context.Log()) },
 	}
 	retryer.Init()
 	channel, err := retryer.Call()
@@ -21,10 +21,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
 	"github.com/aws/amazon-ssm-agent/agent/context"
 	"github.com/aws/amazon-ssm-agent/agent/contracts"
 	messageHandler "github.com/aws/amazon-ssm-agent/agent/messageservice/messagehandler"
 	"github.com/aws/amazon-ssm-agent/agent/messageservice/messagehandler/mocks"
+	"github.com/aws/amazon-ssm-agent/agent/messageservice/utils"
 	contextmocks "github.com/aws/amazon-ssm-agent/agent/mocks/context"
 	mgsConfig "github.com/aws/amazon-ssm-agent/agent/session/config"
 	mgsContracts "github.com/aws/amazon-ssm-agent/agent/session/contracts"
@@ -281,6 +283,21 @@ func (suite *MGSInteractorTestSuite) TestModuleStopClosingAlreadyClosedChannel()
 	assert.True(suite.T(), true, "close connection test passed")
 }
 
+func (suite *MGSInteractorTestSuite) TestGetSupportedWorkersSkipsDisabledModules() {
+	mgsInteractor := &MGSInteractor{context: contextmocks.NewMockDefault()}
+	assert.Equal(suite.T(), []utils.WorkerName{utils.DocumentWorkerName, utils.SessionWorkerName}, mgsInteractor.GetSupportedWorkers())
+
+	config := appconfig.SsmagentConfig{}
+	config.Modules.DisableSessions = true
+	mgsInteractor.context = contextmocks.NewMockDefaultWithConfig(config)
+	assert.Equal(suite.T(), []utils.WorkerName{utils.DocumentWorkerName}, mgsInteractor.GetSupportedWorkers())
+
+	config.Modules.DisableSessions = false
+	config.Modules.DisableRunCommand = true
+	mgsInteractor.context = contextmocks.NewMockDefaultWithConfig(config)
+	assert.Equal(suite.T(), []utils.WorkerName{utils.SessionWorkerName}, mgsInteractor.GetSupportedWorkers())
+}
+
 func (suite *MGSInteractorTestSuite) TestAgentJobSendAcknowledgeWhenMessageHandlerError() {
 	mockContext := contextmocks.NewMockDefault()
 	messageHandlerMock := &mocks.IMessageHandler{}
@@ -75,6 +75,11 @@ func (mgs *MGSInteractor) deleteFailedReply(log log.T, fileName string) {
 
 // sendFailedReplies loads replies from local disk and send it again to the service, if it fails no action is needed
 func (mgs *MGSInteractor) sendFailedReplies() {
+	mgs.resendFailedReplies(mgs.isChannelOpenForAgentJobMsgs)
+}
+
+// resendFailedReplies queues the failed replies to the reply thread for as long as canQueue returns true
+func (mgs *MGSInteractor) resendFailedReplies(canQueue func() bool) {
 	log := mgs.context.Log()
 	log.Debug("send failed reply thread started")
 	defer func() {
@@ -104,7 +109,7 @@ func (mgs *MGSInteractor) sendFailedReplies() {
 			continue
 		}
 		// sending it at least once after the first failure
-		if utils.IsValidReplyRequest(reply, contracts.MessageGatewayService) == false && docPersistData.RetryNumber > 1 {
+		if utils.IsValidReplyRequest(reply, contracts.MessageGatewayService, utils.ReplyRetentionHours(mgs.context.AppConfig())) == false && docPersistData.RetryNumber > 1 {
 			log.Debug("Reply is old, document execution must have timed out. Deleting the reply")
 			mgs.deleteFailedReply(log, reply)
 			continue
@@ -126,7 +131,7 @@ func (mgs *MGSInteractor) sendFailedReplies() {
 			retryNumber:    docPersistData.RetryNumber,
 		}
 		// added to reduce the load on the reply thread
-		if !mgs.isChannelOpenForAgentJobMsgs() {
+		if !canQueue() {
 			break
 		}
 		mgs.sendReplyProp.reply <- agentReplyContract
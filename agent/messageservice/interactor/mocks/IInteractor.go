@@ -26,6 +26,11 @@ func (_m *IInteractor) Close() error {
 	return r0
 }
 
+// FlushReplies provides a mock function with given fields:
+func (_m *IInteractor) FlushReplies() {
+	_m.Called()
+}
+
 // GetName provides a mock function with given fields:
 func (_m *IInteractor) GetName() string {
 	ret := _m.Called()
@@ -33,18 +33,27 @@ const (
 
 	// Name represents idempotency name
 	Name = "Idempotency"
+
+	// statusFileName is the file in the command entry holding the terminal status of the command
+	statusFileName = "status"
 )
 
 var (
 	// persistence timeout
 	persistenceTimeoutMinutes = 30
 
+	// persistence timeout of entries without a terminal status, longer than the maximum execution timeout
+	// so a command still running or resumed after a restart is never executed a second time
+	inProgressPersistenceTimeoutHours = 50
+
 	getDirectoryUnsortedOlderThan = fileutil.GetDirectoryNamesUnsortedOlderThan
 	deleteDirectory               = fileutil.DeleteDirectory
 	makeDirs                      = fileutil.MakeDirs
 	stat                          = os.Stat
 	isNotExist                    = os.IsNotExist
 	getIdempotencyDir             = getIdempotencyDirectory
+	readAllText                   = fileutil.ReadAllText
+	writeAllText                  = fileutil.WriteAllText
 )
 
 // CleanupOldIdempotencyEntries deletes the commands in idempotency folder after persistenceTimeout minutes
@@ -58,6 +67,7 @@ func CleanupOldIdempotencyEntries(idemCtx context.T) {
 	}
 	for _, docTypeDir := range documentTypeDir {
 		olderTime := time.Now().Add(time.Duration(-persistenceTimeoutMinutes) * time.Minute)
+		inProgressOlderTime := time.Now().Add(time.Duration(-inProgressPersistenceTimeoutHours) * time.Hour)
 		tempDocTypeDir := filepath.Join(directoryPath, docTypeDir)
 		commandDirs, err := getDirectoryUnsortedOlderThan(tempDocTypeDir, &olderTime)
 		if err != nil {
@@ -65,8 +75,12 @@ func CleanupOldIdempotencyEntries(idemCtx context.T) {
 		}
 		decrementCount := deletionLimit
 		for _, commandDir := range commandDirs {
-			decrementCount--
 			tempCommandDir := filepath.Join(tempDocTypeDir, commandDir)
+			if !isEntryExpired(tempCommandDir, inProgressOlderTime) {
+				log.Debugf("keeping entry %v of a command without terminal status", tempCommandDir)
+				continue
+			}
+			decrementCount--
 			deleteErr := deleteDirectory(tempCommandDir)
 			if deleteErr != nil {
 				log.Warnf("encountered error %v while deleting entry %v", deleteErr, tempCommandDir)
@@ -80,6 +94,19 @@ func CleanupOldIdempotencyEntries(idemCtx context.T) {
 	}
 }
 
+// isEntryExpired checks whether an entry older than the persistence timeout can be deleted,
+// entries without a terminal status are kept until the in-progress persistence timeout
+func isEntryExpired(commandDirPath string, inProgressOlderTime time.Time) bool {
+	if _, err := stat(filepath.Join(commandDirPath, statusFileName)); err == nil {
+		return true
+	}
+	fileInfo, err := stat(commandDirPath)
+	if err != nil {
+		return true
+	}
+	return fileInfo.ModTime().Before(inProgressOlderTime)
+}
+
 // CreateIdempotencyEntry writes command id to the idempotency directory
 func CreateIdempotencyEntry(idemCtx context.T, message *contracts.DocumentState) error {
 	var err error
@@ -108,10 +135,51 @@ func IsDocumentAlreadyReceived(idemCtx context.T, message *contracts.DocumentSta
 		log.Debugf("command not found in the idempotency directory %v", commandID)
 		return false
 	}
+	if status, found := readTerminalStatus(commandDirPath); found {
+		log.Infof("command %v already completed with status %v, skipping the document", commandID, status)
+		return true
+	}
 	log.Infof("command found in the idempotency directory, skipping the document %v", commandID)
 	return true
 }
 
+// DeleteIdempotencyEntry removes the entry of a command the processor did not accept
+func DeleteIdempotencyEntry(idemCtx context.T, message *contracts.DocumentState) error {
+	context := idemCtx.With("[" + Name + "]")
+	commandID, _ := messageContracts.GetCommandID(message.DocumentInformation.MessageID)
+	commandDirPath := filepath.Join(getIdempotencyDir(context), string(message.DocumentType), commandID)
+	return deleteDirectory(commandDirPath)
+}
+
+// RecordTerminalStatus persists the terminal status of the command in its idempotency entry,
+// a redelivered command is then skipped until the entry expires after the persistence timeout
+func RecordTerminalStatus(idemCtx context.T, documentType contracts.DocumentType, messageID string, status contracts.ResultStatus) error {
+	var err error
+	context := idemCtx.With("[" + Name + "]")
+	log := context.Log()
+	commandID, _ := messageContracts.GetCommandID(messageID)
+	commandDirPath := filepath.Join(getIdempotencyDir(context), string(documentType), commandID)
+	if err = makeDirs(commandDirPath); err != nil {
+		log.Warnf("could not create command directory in %v for the command %v: err: %v", commandDirPath, commandID, err)
+		return err
+	}
+	if err = writeAllText(filepath.Join(commandDirPath, statusFileName), string(status)); err != nil {
+		log.Warnf("could not record terminal status of the command %v: err: %v", commandID, err)
+		return err
+	}
+	log.Debugf("recorded terminal status %v of the command %v", status, commandID)
+	return nil
+}
+
+// readTerminalStatus reads the terminal status recorded in the entry of a command
+func readTerminalStatus(commandDirPath string) (contracts.ResultStatus, bool) {
+	status, err := readAllText(filepath.Join(commandDirPath, statusFileName))
+	if err != nil || status == "" {
+		return "", false
+	}
+	return contracts.ResultStatus(status), true
+}
+
 // getIdempotencyDirectory returns the absolute path of idempotency directory
 func getIdempotencyDirectory(context context.T) string {
 	shortInstanceID, _ := context.Identity().ShortInstanceID()
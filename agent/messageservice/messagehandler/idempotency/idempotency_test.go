@@ -2,6 +2,7 @@ package idempotency
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"testing"
 	"time"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/aws/amazon-ssm-agent/agent/context"
 	"github.com/aws/amazon-ssm-agent/agent/contracts"
+	"github.com/aws/amazon-ssm-agent/agent/fileutil"
 	contextmocks "github.com/aws/amazon-ssm-agent/agent/mocks/context"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
@@ -113,10 +115,56 @@ func (suite *IdeopotencyTestSuite) TestIdempotency_CleanupOldIdempotencyEntries(
 		delete(files, dirName)
 		return nil
 	}
+	stat = func(name string) (os.FileInfo, error) {
+		return nil, nil
+	}
+	defer func() { stat = os.Stat }()
 	CleanupOldIdempotencyEntries(suite.mockContext)
 	assert.Equal(suite.T(), 0, len(files))
 }
 
+func (suite *IdeopotencyTestSuite) TestIdempotency_CleanupKeepsEntriesWithoutTerminalStatus() {
+	dir := suite.T().TempDir()
+	getIdempotencyDir = func(context context.T) string {
+		return dir
+	}
+	getDirectoryUnsortedOlderThan = fileutil.GetDirectoryNamesUnsortedOlderThan
+	deleteDirectory = fileutil.DeleteDirectory
+	makeDirs = fileutil.MakeDirs
+	isNotExist = os.IsNotExist
+	stat = os.Stat
+	running := filepath.Join(dir, string(contracts.SendCommand), "running")
+	completed := filepath.Join(dir, string(contracts.SendCommand), "completed")
+	assert.NoError(suite.T(), os.MkdirAll(running, 0700))
+	assert.NoError(suite.T(), os.MkdirAll(completed, 0700))
+	assert.NoError(suite.T(), RecordTerminalStatus(suite.mockContext, contracts.SendCommand, "completed", contracts.ResultStatusSuccess))
+
+	CleanupOldIdempotencyEntries(suite.mockContext)
+	assert.DirExists(suite.T(), running)
+	assert.NoDirExists(suite.T(), completed)
+}
+
+func (suite *IdeopotencyTestSuite) TestIdempotency_RecordTerminalStatus() {
+	dir := suite.T().TempDir()
+	getIdempotencyDir = func(context context.T) string {
+		return dir
+	}
+	makeDirs = fileutil.MakeDirs
+	deleteDirectory = fileutil.DeleteDirectory
+	isNotExist = os.IsNotExist
+	stat = os.Stat
+	assert.False(suite.T(), IsDocumentAlreadyReceived(suite.mockContext, docState))
+
+	assert.NoError(suite.T(), RecordTerminalStatus(suite.mockContext, docState.DocumentType, docState.DocumentInformation.MessageID, contracts.ResultStatusFailed))
+	assert.True(suite.T(), IsDocumentAlreadyReceived(suite.mockContext, docState))
+	status, found := readTerminalStatus(filepath.Join(dir, string(docState.DocumentType), docState.DocumentInformation.CommandID))
+	assert.True(suite.T(), found)
+	assert.Equal(suite.T(), contracts.ResultStatusFailed, status)
+
+	assert.NoError(suite.T(), DeleteIdempotencyEntry(suite.mockContext, docState))
+	assert.False(suite.T(), IsDocumentAlreadyReceived(suite.mockContext, docState))
+}
+
 // Execute the test suite
 func TestMessageHandlerTestSuite(t *testing.T) {
 	suite.Run(t, new(IdeopotencyTestSuite))
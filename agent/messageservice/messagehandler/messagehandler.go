@@ -18,6 +18,8 @@ package messagehandler
 import (
 	"runtime/debug"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/aws/amazon-ssm-agent/agent/context"
 	"github.com/aws/amazon-ssm-agent/agent/contracts"
@@ -34,6 +36,7 @@ type IMessageHandler interface {
 	InitializeAndRegisterProcessor(proc processorWrapperTypes.IProcessorWrapper) error
 	RegisterReply(name contracts.UpstreamServiceName, reply chan contracts.DocumentResult)
 	Submit(message *contracts.DocumentState) ErrorCode
+	Drain(timeout time.Duration) bool
 	Stop() (err error)
 }
 
@@ -206,6 +209,33 @@ func (mh *MessageHandler) RegisterReply(name contracts.UpstreamServiceName, repl
 	mh.replyMap[name] = reply
 }
 
+// Drain waits for the in-flight documents of all the processors to complete
+func (mh *MessageHandler) Drain(timeout time.Duration) bool {
+	log := mh.context.Log()
+	log.Infof("draining %s, timeout: %v", Name, timeout)
+	var wg sync.WaitGroup
+	var drained atomic.Bool
+	drained.Store(true)
+	for _, processorObj := range mh.processorsLoaded {
+		wg.Add(1)
+		go func(processorObj processorWrapperTypes.IProcessorWrapper) {
+			defer func() {
+				wg.Done()
+				if msg := recover(); msg != nil {
+					log.Errorf("message handler drain run panic: %v", msg)
+					log.Errorf("stacktrace:\n%s", debug.Stack())
+				}
+			}()
+
+			if !processorObj.Drain(timeout) {
+				drained.Store(false)
+			}
+		}(processorObj)
+	}
+	wg.Wait()
+	return drained.Load()
+}
+
 // Stop stops the message handlers
 func (mh *MessageHandler) Stop() (err error) {
 	log := mh.context.Log()
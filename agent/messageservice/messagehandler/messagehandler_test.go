@@ -16,9 +16,11 @@ package messagehandler
 
 import (
 	"testing"
+	"time"
 
 	"github.com/aws/amazon-ssm-agent/agent/contracts"
 	"github.com/aws/amazon-ssm-agent/agent/messageservice/messagehandler/processorwrappers"
+	processorWrapperMock "github.com/aws/amazon-ssm-agent/agent/messageservice/messagehandler/processorwrappers/mocks"
 	"github.com/aws/amazon-ssm-agent/agent/messageservice/utils"
 	"github.com/aws/amazon-ssm-agent/agent/mocks/context"
 	"github.com/stretchr/testify/assert"
@@ -139,6 +141,21 @@ func (suite *MessageHandlerTestSuite) TestStops() {
 	assert.Nil(suite.T(), err)
 }
 
+func (suite *MessageHandlerTestSuite) TestDrain() {
+	drainedProcessor := &processorWrapperMock.IProcessorWrapper{}
+	drainedProcessor.On("Drain", time.Second).Return(true)
+	busyProcessor := &processorWrapperMock.IProcessorWrapper{}
+	busyProcessor.On("Drain", time.Second).Return(false)
+
+	suite.mockProcessorsLoaded[utils.CommandProcessor] = drainedProcessor
+	assert.True(suite.T(), suite.messagehandler.Drain(time.Second))
+
+	suite.mockProcessorsLoaded[utils.SessionProcessor] = busyProcessor
+	assert.False(suite.T(), suite.messagehandler.Drain(time.Second))
+	drainedProcessor.AssertNumberOfCalls(suite.T(), "Drain", 2)
+	busyProcessor.AssertNumberOfCalls(suite.T(), "Drain", 1)
+}
+
 func TestMessageHandlerTestSuite(t *testing.T) {
 	suite.Run(t, new(MessageHandlerTestSuite))
 }
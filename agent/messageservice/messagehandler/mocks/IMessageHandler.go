@@ -7,6 +7,8 @@ import (
 	messagehandler "github.com/aws/amazon-ssm-agent/agent/messageservice/messagehandler"
 	processorwrappers "github.com/aws/amazon-ssm-agent/agent/messageservice/messagehandler/processorwrappers"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // IMessageHandler is an autogenerated mock type for the IMessageHandler type
@@ -14,6 +16,20 @@ type IMessageHandler struct {
 	mock.Mock
 }
 
+// Drain provides a mock function with given fields: timeout
+func (_m *IMessageHandler) Drain(timeout time.Duration) bool {
+	ret := _m.Called(timeout)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(time.Duration) bool); ok {
+		r0 = rf(timeout)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // GetName provides a mock function with given fields:
 func (_m *IMessageHandler) GetName() string {
 	ret := _m.Called()
@@ -30,10 +30,15 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/messageservice/messagehandler/idempotency"
 	"github.com/aws/amazon-ssm-agent/agent/messageservice/utils"
 	runCommandContracts "github.com/aws/amazon-ssm-agent/agent/runcommand/contracts"
+	"github.com/aws/amazon-ssm-agent/agent/ssmconnectionchannel"
 )
 
 var (
 	isDocumentAlreadyReceived = idempotency.IsDocumentAlreadyReceived
+	createIdempotencyEntry    = idempotency.CreateIdempotencyEntry
+	deleteIdempotencyEntry    = idempotency.DeleteIdempotencyEntry
+	recordTerminalStatus      = idempotency.RecordTerminalStatus
+	waitForControlChannel     = ssmconnectionchannel.WaitForControlChannel
 )
 
 // NewCommandWorkerProcessorWrapper initiates new processor wrapper which supports document workers
@@ -54,7 +59,9 @@ func NewCommandWorkerProcessorWrapper(context context.T, worker *utils.Processor
 	}
 	// MDS interactor will not be loaded for containers.
 	// Since mgs interactor also created this processor wrapper, we will stop loading the Command Processor Wrapper in MGS interactor itself
-	processorDoc.assocProcessor = associationProcessor.NewAssociationProcessor(context)
+	if !context.AppConfig().Modules.DisableAssociations {
+		processorDoc.assocProcessor = associationProcessor.NewAssociationProcessor(context)
+	}
 	processorDoc.startWorkerCmd = startWorker.GetAssignedDocType()
 	processorDoc.cancelWorkerCmd = terminateWorker.GetAssignedDocType()
 	return processorDoc
@@ -71,6 +78,9 @@ type CommandWorkerProcessorWrapper struct {
 	startWorkerCmd    contracts.DocumentType
 	cancelWorkerCmd   contracts.DocumentType
 	listenReplyEnded  chan struct{}
+	assocMutex        sync.Mutex
+	assocStarted      bool
+	assocStopped      bool
 }
 
 // Initialize initializes command processor and launches the reply thread
@@ -93,7 +103,7 @@ func (cpw *CommandWorkerProcessorWrapper) Initialize(outputChan map[contracts.Up
 		return err
 	}
 	if cpw.assocProcessor != nil {
-		cpw.assocProcessor.ModuleExecute()
+		go cpw.startAssociationProcessor()
 	}
 	cpw.listenReplyEnded = make(chan struct{}, 1)
 	return nil
@@ -123,20 +133,29 @@ func (cpw *CommandWorkerProcessorWrapper) PushToProcessor(message contracts.Docu
 	if commandPresent {
 		return processor.DuplicateCommand
 	}
+	// the entry is created before the submission so a command redelivered after the agent
+	// stopped in the middle of the submission is not executed a second time
+	if err := createIdempotencyEntry(cpw.context, &message); err != nil {
+		cpw.context.Log().Errorf("error while creating idempotency entry for command %v", message.DocumentInformation.DocumentID)
+	}
 	if cpw.startWorkerCmd == message.DocumentType {
 		errorCode = cpw.processor.Submit(message)
 	} else if cpw.cancelWorkerCmd == message.DocumentType {
 		errorCode = cpw.processor.Cancel(message)
 	}
-	if errorCode == "" {
-		err := idempotency.CreateIdempotencyEntry(cpw.context, &message)
-		if err != nil {
-			cpw.context.Log().Errorf("error while creating idempotency entry for command %v", message.DocumentInformation.DocumentID)
+	if errorCode != "" {
+		if err := deleteIdempotencyEntry(cpw.context, &message); err != nil {
+			cpw.context.Log().Warnf("error while deleting idempotency entry for command %v: %v", message.DocumentInformation.DocumentID, err)
 		}
 	}
 	return errorCode
 }
 
+// Drain waits for the in-flight commands to complete
+func (cpw *CommandWorkerProcessorWrapper) Drain(timeout time.Duration) bool {
+	return cpw.processor.Drain(timeout)
+}
+
 // Stop stops the processor
 func (cpw *CommandWorkerProcessorWrapper) Stop() {
 	log := cpw.context.Log()
@@ -144,9 +163,7 @@ func (cpw *CommandWorkerProcessorWrapper) Stop() {
 	// this closes the result chan too.
 	// should not expect many replies after this stop
 	cpw.processor.Stop()
-	if cpw.assocProcessor != nil {
-		cpw.assocProcessor.ModuleStop()
-	}
+	cpw.stopAssociationProcessor()
 	select {
 	case <-cpw.listenReplyEnded:
 		log.Info("listen reply thread ended")
@@ -155,6 +172,30 @@ func (cpw *CommandWorkerProcessorWrapper) Stop() {
 	}
 }
 
+// startAssociationProcessor starts the association processor, including inventory, once the control channel is set up
+// so scheduled associations do not delay Session Manager readiness after boot
+func (cpw *CommandWorkerProcessorWrapper) startAssociationProcessor() {
+	waitForControlChannel(ssmconnectionchannel.ControlChannelWaitTimeout)
+	cpw.assocMutex.Lock()
+	defer cpw.assocMutex.Unlock()
+	if cpw.assocStopped {
+		cpw.context.Log().Info("processor stopped, skipping the association processor start")
+		return
+	}
+	cpw.assocProcessor.ModuleExecute()
+	cpw.assocStarted = true
+}
+
+// stopAssociationProcessor stops the association processor when it was started
+func (cpw *CommandWorkerProcessorWrapper) stopAssociationProcessor() {
+	cpw.assocMutex.Lock()
+	defer cpw.assocMutex.Unlock()
+	cpw.assocStopped = true
+	if cpw.assocStarted {
+		cpw.assocProcessor.ModuleStop()
+	}
+}
+
 // listenReply listens to document result from the executor and pushes to outputChan.
 // outputChan is used by messageHandler to push it to the correct interactor
 func (cpw *CommandWorkerProcessorWrapper) listenReply(resChan chan contracts.DocumentResult, outputChan map[contracts.UpstreamServiceName]chan contracts.DocumentResult) {
@@ -190,6 +231,9 @@ externalLabel:
 				log.Infof("received plugin: %s result from Processor", res.LastPlugin)
 			} else {
 				log.Infof("command: %s complete", res.MessageID)
+				if res.Status != contracts.ResultStatusInProgress && !res.Status.IsReboot() {
+					recordTerminalStatus(cpw.context, res.RelatedDocumentType, res.MessageID, res.Status)
+				}
 
 				//Deleting Old Log Files
 				shortInstanceID, _ := cpw.context.Identity().ShortInstanceID()
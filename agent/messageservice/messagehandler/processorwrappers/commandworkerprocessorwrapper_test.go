@@ -7,8 +7,10 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/context"
 	"github.com/aws/amazon-ssm-agent/agent/contracts"
 	"github.com/aws/amazon-ssm-agent/agent/framework/processor"
+	"github.com/aws/amazon-ssm-agent/agent/messageservice/messagehandler/idempotency"
 	"github.com/aws/amazon-ssm-agent/agent/messageservice/utils"
 	contextmocks "github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	"github.com/aws/amazon-ssm-agent/agent/ssmconnectionchannel"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"github.com/twinj/uuid"
@@ -134,6 +136,68 @@ func (suite *CommandProcessorWrapperTestSuite) TestListenReply() {
 	}
 }
 
+func (suite *CommandProcessorWrapperTestSuite) TestPushToProcessorWithUnsupportedDocDeletesEntry() {
+	unsupportedDoc := docState
+	unsupportedDoc.DocumentType = contracts.StartSession
+	isDocumentAlreadyReceived = func(idemCtx context.T, message *contracts.DocumentState) bool {
+		return false
+	}
+	var created, deleted bool
+	createIdempotencyEntry = func(idemCtx context.T, message *contracts.DocumentState) error {
+		created = true
+		return nil
+	}
+	deleteIdempotencyEntry = func(idemCtx context.T, message *contracts.DocumentState) error {
+		deleted = true
+		return nil
+	}
+	defer func() {
+		createIdempotencyEntry = idempotency.CreateIdempotencyEntry
+		deleteIdempotencyEntry = idempotency.DeleteIdempotencyEntry
+	}()
+	errorCode := suite.commmandWorkerProcessorWrapper.PushToProcessor(unsupportedDoc)
+	assert.Equal(suite.T(), processor.UnsupportedDocType, errorCode)
+	assert.True(suite.T(), created)
+	assert.True(suite.T(), deleted)
+}
+
+func (suite *CommandProcessorWrapperTestSuite) TestListenReplyRecordsTerminalStatus() {
+	recorded := make(chan contracts.ResultStatus, 2)
+	recordTerminalStatus = func(idemCtx context.T, documentType contracts.DocumentType, messageID string, status contracts.ResultStatus) error {
+		assert.Equal(suite.T(), contracts.SendCommand, documentType)
+		recorded <- status
+		return nil
+	}
+	defer func() { recordTerminalStatus = idempotency.RecordTerminalStatus }()
+
+	resChan := make(chan contracts.DocumentResult)
+	go suite.commmandWorkerProcessorWrapper.listenReply(resChan, suite.outputMap)
+	for _, status := range []contracts.ResultStatus{contracts.ResultStatusSuccessAndReboot, contracts.ResultStatusFailed} {
+		resChan <- contracts.DocumentResult{
+			Status:              status,
+			MessageID:           uuid.NewV4().String(),
+			RelatedDocumentType: contracts.SendCommand,
+		}
+		<-suite.documentResultChan
+	}
+	close(resChan)
+
+	// a reboot is not terminal, the document is resumed after the reboot
+	assert.Equal(suite.T(), contracts.ResultStatusFailed, <-recorded)
+	assert.Len(suite.T(), recorded, 0)
+}
+
+func (suite *CommandProcessorWrapperTestSuite) TestAssociationProcessorNotStartedAfterStop() {
+	waitForControlChannel = func(timeout time.Duration) bool {
+		return true
+	}
+	defer func() { waitForControlChannel = ssmconnectionchannel.WaitForControlChannel }()
+
+	suite.commmandWorkerProcessorWrapper.stopAssociationProcessor()
+	suite.commmandWorkerProcessorWrapper.startAssociationProcessor()
+	assert.False(suite.T(), suite.commmandWorkerProcessorWrapper.assocStarted)
+}
+
 func TestCommandProcessorWrapperTestSuite(t *testing.T) {
 	suite.Run(t, new(CommandProcessorWrapperTestSuite))
 }
@@ -8,6 +8,8 @@ import (
 
 	processor "github.com/aws/amazon-ssm-agent/agent/framework/processor"
 
+	time "time"
+
 	utils "github.com/aws/amazon-ssm-agent/agent/messageservice/utils"
 )
 
@@ -16,6 +18,20 @@ type IProcessorWrapper struct {
 	mock.Mock
 }
 
+// Drain provides a mock function with given fields: timeout
+func (_m *IProcessorWrapper) Drain(timeout time.Duration) bool {
+	ret := _m.Called(timeout)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(time.Duration) bool); ok {
+		r0 = rf(timeout)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // GetName provides a mock function with given fields:
 func (_m *IProcessorWrapper) GetName() utils.ProcessorName {
 	ret := _m.Called()
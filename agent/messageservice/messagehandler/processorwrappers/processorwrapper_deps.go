@@ -16,6 +16,8 @@
 package processorwrappers
 
 import (
+	"time"
+
 	"github.com/aws/amazon-ssm-agent/agent/contracts"
 	"github.com/aws/amazon-ssm-agent/agent/framework/processor"
 	"github.com/aws/amazon-ssm-agent/agent/messageservice/utils"
@@ -28,5 +30,6 @@ type IProcessorWrapper interface {
 	GetStartWorker() contracts.DocumentType
 	GetTerminateWorker() contracts.DocumentType
 	PushToProcessor(contracts.DocumentState) processor.ErrorCode
+	Drain(timeout time.Duration) bool
 	Stop()
 }
@@ -17,6 +17,7 @@ package processorwrappers
 
 import (
 	"runtime/debug"
+	"time"
 
 	"github.com/aws/amazon-ssm-agent/agent/context"
 	"github.com/aws/amazon-ssm-agent/agent/contracts"
@@ -99,6 +100,12 @@ func (spw *SessionWorkerProcessorWrapper) GetTerminateWorker() contracts.Documen
 	return spw.cancelWorkerCmd
 }
 
+// Drain does not wait for the sessions, they run in their own worker process and outlive the agent worker.
+// Session clients are notified by the session worker when it is terminated itself.
+func (spw *SessionWorkerProcessorWrapper) Drain(timeout time.Duration) bool {
+	return true
+}
+
 // Stop stops the processor
 func (spw *SessionWorkerProcessorWrapper) Stop() {
 	spw.processor.Stop()
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"runtime/debug"
 	"sync"
+	"time"
 
 	"github.com/aws/amazon-ssm-agent/agent/context"
 	"github.com/aws/amazon-ssm-agent/agent/contracts"
@@ -30,6 +31,7 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/messageservice/messagehandler/processorwrappers"
 	"github.com/aws/amazon-ssm-agent/agent/messageservice/utils"
 	"github.com/aws/amazon-ssm-agent/agent/platform"
+	"github.com/aws/amazon-ssm-agent/agent/ssmconnectionchannel"
 )
 
 var (
@@ -51,6 +53,8 @@ type MessageService struct {
 	messageHandler  messagehandler.IMessageHandler
 	interactors     []interactor.IInteractor
 	msgServiceMutex sync.Mutex
+	// the interactors stop receiving messages once, either when draining or stopping the module
+	preProcessorCloseOnce sync.Once
 }
 
 // NewService instantiates MessageService object and assigns value if needed
@@ -70,15 +74,20 @@ func NewService(context context.T) contracts.ICoreModule {
 		messageHandler: messagehandler.NewMessageHandler(messageContext),
 	}
 
+	modules := messageContext.AppConfig().Modules
 	isNanoServer, _ := isPlatformNanoServer(log)
-	if !isNanoServer {
+	if !isNanoServer && !(modules.DisableSessions && modules.DisableRunCommand) {
 		log.Info("Appending MGSInteractor to MessageService interactors")
 		mgsRef, err := mgsinteractor.New(messageContext, messageService.messageHandler)
 		if err == nil {
 			messageService.interactors = append(messageService.interactors, mgsRef)
 		}
 	}
-	if !messageContext.AppConfig().Agent.ContainerMode {
+	if len(messageService.interactors) == 0 {
+		// no control channel is set up, the modules waiting for it are started right away
+		ssmconnectionchannel.SetControlChannelUnavailable()
+	}
+	if !messageContext.AppConfig().Agent.ContainerMode && !modules.DisableRunCommand {
 		log.Info("Appending MDSInteractor to MessageService interactors")
 		mdsRef, err := mdsinteractor.New(messageContext, messageService.messageHandler, nil)
 		if err == nil {
@@ -206,18 +215,43 @@ func (msgSvc *MessageService) initializeProcessor(interactorRef interactor.IInte
 	wg.Wait()
 }
 
+// ModuleDrain stops receiving new messages and lets the in-flight documents complete
+// and their replies reach the service before the MessageService module is stopped
+func (msgSvc *MessageService) ModuleDrain(timeout time.Duration) error {
+	log := msgSvc.context.Log()
+	log.Infof("Draining %v, timeout: %v", msgSvc.name, timeout)
+	msgSvc.preProcessorClose()
+
+	drained := msgSvc.messageHandler.Drain(timeout)
+	for _, interactRef := range msgSvc.interactors {
+		interactRef.FlushReplies()
+	}
+	if !drained {
+		return fmt.Errorf("documents still in progress after draining %v for %v", msgSvc.name, timeout)
+	}
+	log.Infof("Drained %v", msgSvc.name)
+	return nil
+}
+
+// preProcessorClose makes the interactors stop receiving messages
+func (msgSvc *MessageService) preProcessorClose() {
+	msgSvc.preProcessorCloseOnce.Do(func() {
+		for _, interactRef := range msgSvc.interactors {
+			// Perform actions to do by interactors before the message handler close
+			// This function performs following operations:
+			// close send failed reply job and message polling in MDS interactor
+			// drop incoming agent job messages in MGS interactor
+			interactRef.PreProcessorClose()
+		}
+	})
+}
+
 // ModuleStop stops the MessageService module
 func (msgSvc *MessageService) ModuleStop() error {
 	log := msgSvc.context.Log()
 	log.Infof("Stopping %v", msgSvc.name)
 	var err error
-	for _, interactRef := range msgSvc.interactors {
-		// Perform actions to do by interactors before the message handler close
-		// This function performs following operations:
-		// close send failed reply job and message polling in MDS interactor
-		// drop incoming agent job messages in MGS interactor
-		interactRef.PreProcessorClose()
-	}
+	msgSvc.preProcessorClose()
 	// close the launched processors
 	err = msgSvc.messageHandler.Stop()
 	if err != nil {
@@ -19,6 +19,7 @@ package messageservice
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
 	"github.com/aws/amazon-ssm-agent/agent/context"
@@ -210,3 +211,44 @@ func (suite *MessageServiceTestSuite) TestMessageService_ModuleStop() {
 	err = msgService.ModuleStop()
 	interactorMockObj.AssertNumberOfCalls(suite.T(), "Close", 2)
 }
+
+// TestMessageService_ModuleDrain tests module drain followed by module stop
+func (suite *MessageServiceTestSuite) TestMessageService_ModuleDrain() {
+	ctx := contextmocks.NewMockDefault()
+	interactorMockObj := &interactorMock.IInteractor{}
+	interactorMockObj.On("PreProcessorClose").Once()
+	interactorMockObj.On("FlushReplies").Once()
+	interactorMockObj.On("Close").Return(nil)
+	messageHandlerMock := &mocks.IMessageHandler{}
+	messageHandlerMock.On("Drain", 30*time.Second).Return(true)
+	messageHandlerMock.On("Stop").Return(nil)
+	msgService := &MessageService{
+		context:        ctx,
+		name:           ServiceName,
+		messageHandler: messageHandlerMock,
+		interactors:    []interactor.IInteractor{interactorMockObj},
+	}
+
+	err := msgService.ModuleDrain(30 * time.Second)
+	assert.Nil(suite.T(), err, "drain should not return error")
+	err = msgService.ModuleStop()
+	assert.Nil(suite.T(), err, "stop should not return error")
+	interactorMockObj.AssertExpectations(suite.T())
+	messageHandlerMock.AssertExpectations(suite.T())
+
+	// documents still in progress at the end of the drain
+	messageHandlerMock = &mocks.IMessageHandler{}
+	messageHandlerMock.On("Drain", time.Second).Return(false)
+	interactorMockObj = &interactorMock.IInteractor{}
+	interactorMockObj.On("PreProcessorClose").Once()
+	interactorMockObj.On("FlushReplies").Once()
+	msgService = &MessageService{
+		context:        ctx,
+		name:           ServiceName,
+		messageHandler: messageHandlerMock,
+		interactors:    []interactor.IInteractor{interactorMockObj},
+	}
+	err = msgService.ModuleDrain(time.Second)
+	assert.NotNil(suite.T(), err, "drain should report documents in progress")
+	interactorMockObj.AssertExpectations(suite.T())
+}
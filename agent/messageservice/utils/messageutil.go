@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/Jeffail/gabs"
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
 	"github.com/aws/amazon-ssm-agent/agent/context"
 	"github.com/aws/amazon-ssm-agent/agent/contracts"
 	"github.com/aws/amazon-ssm-agent/agent/framework/docparser"
@@ -283,8 +284,17 @@ func ParseSendCommandMessage(context context.T, msg model.InstanceMessage, messa
 	return &docState, nil
 }
 
+// ReplyRetentionHours returns how long failed replies are kept on disk to be sent again.
+// With store and forward enabled replies are kept as long as the other queued results.
+func ReplyRetentionHours(config appconfig.SsmagentConfig) int {
+	if config.StoreAndForward.Enabled {
+		return config.StoreAndForward.MaxAgeHours
+	}
+	return documentLevelTimeOutDurationHour
+}
+
 // IsValidReplyRequest checks whether the reply is valid and had timed or not
-func IsValidReplyRequest(filename string, name contracts.UpstreamServiceName) bool {
+func IsValidReplyRequest(filename string, name contracts.UpstreamServiceName, retentionHours int) bool {
 	splitFileName := strings.Split(filename, "_")
 	if len(splitFileName) < 2 {
 		return false
@@ -298,7 +308,7 @@ func IsValidReplyRequest(filename string, name contracts.UpstreamServiceName) bo
 	t, _ := time.Parse("2006-01-02T15-04-05", timeInFileName)
 	curTime := time.Now().UTC()
 	delta := curTime.Sub(t).Hours()
-	if delta > documentLevelTimeOutDurationHour {
+	if delta > float64(retentionHours) {
 		return false
 	} else {
 		return true
@@ -0,0 +1,106 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package exporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/metrics"
+)
+
+const bytesPerMB = 1024 * 1024
+
+// jsonSnapshot is a line of the metrics JSON file
+type jsonSnapshot struct {
+	Timestamp time.Time
+	Samples   []metrics.Sample
+}
+
+// timeNow is replaced in tests
+var timeNow = time.Now
+
+func (s *Server) exportJSONFile(interval time.Duration, stopChan chan struct{}) {
+	log := s.context.Log()
+	defer func() {
+		if msg := recover(); msg != nil {
+			log.Errorf("Metrics JSON file exporter panic: %v", msg)
+			log.Errorf("Stacktrace:\n%s", debug.Stack())
+		}
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := s.appendJSONFile(); err != nil {
+			log.Warnf("Failed to append metrics to %s: %v", s.config.JSONFilePath, err)
+		}
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// appendJSONFile appends one line with the current metrics to the JSON file, the file is rotated first when the line
+// would exceed the configured size. Collectors read complete lines only, so appending does not need to be atomic.
+func (s *Server) appendJSONFile() (err error) {
+	line, err := json.Marshal(jsonSnapshot{Timestamp: timeNow().UTC(), Samples: s.registry.Samples()})
+	if err != nil {
+		return
+	}
+	line = append(line, '\n')
+
+	if info, statErr := os.Stat(s.config.JSONFilePath); statErr == nil && info.Size()+int64(len(line)) > int64(s.config.JSONFileMaxSizeMB)*bytesPerMB {
+		if err = s.rotateJSONFile(); err != nil {
+			return
+		}
+	}
+
+	file, err := os.OpenFile(s.config.JSONFilePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, textfileAccess)
+	if err != nil {
+		return
+	}
+	if _, err = file.Write(line); err != nil {
+		file.Close()
+		return
+	}
+	return file.Close()
+}
+
+// rotateJSONFile shifts the JSON file to <path>.1 and the older files up by one, the oldest file is deleted
+func (s *Server) rotateJSONFile() (err error) {
+	path := s.config.JSONFilePath
+	backups := s.config.JSONFileMaxBackups
+	if backups == 0 {
+		return os.Remove(path)
+	}
+	if err = os.Remove(backupPath(path, backups)); err != nil && !os.IsNotExist(err) {
+		return
+	}
+	for i := backups - 1; i >= 1; i-- {
+		if err = os.Rename(backupPath(path, i), backupPath(path, i+1)); err != nil && !os.IsNotExist(err) {
+			return
+		}
+	}
+	return os.Rename(path, backupPath(path, 1))
+}
+
+func backupPath(path string, index int) string {
+	return fmt.Sprintf("%s.%d", path, index)
+}
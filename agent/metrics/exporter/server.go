@@ -0,0 +1,184 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package exporter serves the agent metrics on localhost and writes them to a file for the Prometheus textfile collector
+// and to a rotated JSON file for other local collectors.
+package exporter
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/metrics"
+)
+
+const (
+	// ModuleName is the name of the metrics core module
+	ModuleName = "MetricsServer"
+
+	metricsPath       = "/metrics"
+	listenHost        = "127.0.0.1"
+	readHeaderTimeout = 5 * time.Second
+	contentTypeText   = "text/plain; version=0.0.4; charset=utf-8"
+
+	// the textfile collector usually runs as another user than the agent
+	textfileAccess = 0644
+)
+
+// Server serves the agent metrics on localhost and writes them to the files configured in appconfig
+type Server struct {
+	context  context.T
+	registry *metrics.Registry
+	config   appconfig.MetricsCfg
+
+	mtx        sync.Mutex
+	httpServer *http.Server
+	stopChan   chan struct{}
+}
+
+// NewServer returns a metrics core module
+func NewServer(context context.T) *Server {
+	return &Server{
+		context:  context.With("[" + ModuleName + "]"),
+		registry: metrics.DefaultRegistry,
+		config:   context.AppConfig().Metrics,
+	}
+}
+
+// ModuleName returns the module name
+func (s *Server) ModuleName() string {
+	return ModuleName
+}
+
+// ModuleExecute starts the metrics endpoint and the file exporters
+func (s *Server) ModuleExecute() (err error) {
+	log := s.context.Log()
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.stopChan = make(chan struct{})
+	if s.config.Enabled {
+		address := net.JoinHostPort(listenHost, strconv.Itoa(s.config.Port))
+		var listener net.Listener
+		if listener, err = net.Listen("tcp", address); err != nil {
+			return fmt.Errorf("failed to listen on %s for metrics: %v", address, err)
+		}
+		s.httpServer = &http.Server{Handler: s.handler(), ReadHeaderTimeout: readHeaderTimeout}
+		go s.serve(listener)
+		log.Infof("Serving metrics at http://%s%s", address, metricsPath)
+	}
+
+	if s.config.TextfilePath != "" {
+		go s.exportTextfile(time.Duration(s.config.TextfileIntervalSeconds)*time.Second, s.stopChan)
+		log.Infof("Writing metrics to %s every %d seconds", s.config.TextfilePath, s.config.TextfileIntervalSeconds)
+	}
+
+	if s.config.JSONFilePath != "" {
+		go s.exportJSONFile(time.Duration(s.config.JSONFileIntervalSeconds)*time.Second, s.stopChan)
+		log.Infof("Appending metrics to %s every %d seconds", s.config.JSONFilePath, s.config.JSONFileIntervalSeconds)
+	}
+	return nil
+}
+
+// ModuleStop stops the metrics endpoint and the file exporters
+func (s *Server) ModuleStop() (err error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.stopChan != nil {
+		close(s.stopChan)
+		s.stopChan = nil
+	}
+	if s.httpServer != nil {
+		err = s.httpServer.Close()
+		s.httpServer = nil
+	}
+	return err
+}
+
+func (s *Server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(metricsPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", contentTypeText)
+		if err := s.registry.Write(w); err != nil {
+			s.context.Log().Debugf("Failed to write metrics response: %v", err)
+		}
+	})
+	return mux
+}
+
+func (s *Server) serve(listener net.Listener) {
+	log := s.context.Log()
+	defer func() {
+		if msg := recover(); msg != nil {
+			log.Errorf("Metrics server panic: %v", msg)
+			log.Errorf("Stacktrace:\n%s", debug.Stack())
+		}
+	}()
+	if err := s.httpServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+		log.Errorf("Metrics server stopped: %v", err)
+	}
+}
+
+func (s *Server) exportTextfile(interval time.Duration, stopChan chan struct{}) {
+	log := s.context.Log()
+	defer func() {
+		if msg := recover(); msg != nil {
+			log.Errorf("Metrics textfile exporter panic: %v", msg)
+			log.Errorf("Stacktrace:\n%s", debug.Stack())
+		}
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := s.writeTextfile(); err != nil {
+			log.Warnf("Failed to write metrics to %s: %v", s.config.TextfilePath, err)
+		}
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// writeTextfile replaces the textfile atomically so collectors never read a partially written file
+func (s *Server) writeTextfile() (err error) {
+	var buffer bytes.Buffer
+	if err = s.registry.Write(&buffer); err != nil {
+		return
+	}
+	tempFile := filepath.Join(filepath.Dir(s.config.TextfilePath), "."+filepath.Base(s.config.TextfilePath)+".tmp")
+	if err = os.WriteFile(tempFile, buffer.Bytes(), textfileAccess); err != nil {
+		return
+	}
+	if err = os.Rename(tempFile, s.config.TextfilePath); err != nil {
+		os.Remove(tempFile)
+	}
+	return
+}
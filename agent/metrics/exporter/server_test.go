@@ -0,0 +1,141 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package exporter
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/metrics"
+	contextmocks "github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestServer(config appconfig.MetricsCfg) *Server {
+	registry := &metrics.Registry{}
+	counter := registry.NewCounter("test_total", "Test.", "")
+	counter.Inc("")
+	return &Server{
+		context:  contextmocks.NewMockDefault(),
+		registry: registry,
+		config:   config,
+	}
+}
+
+func TestHandler(t *testing.T) {
+	server := newTestServer(appconfig.MetricsCfg{})
+
+	response := httptest.NewRecorder()
+	server.handler().ServeHTTP(response, httptest.NewRequest(http.MethodGet, metricsPath, nil))
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, contentTypeText, response.Header().Get("Content-Type"))
+	assert.Contains(t, response.Body.String(), "test_total 1\n")
+
+	response = httptest.NewRecorder()
+	server.handler().ServeHTTP(response, httptest.NewRequest(http.MethodPost, metricsPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, response.Code)
+
+	response = httptest.NewRecorder()
+	server.handler().ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
+
+func TestWriteTextfile(t *testing.T) {
+	textfile := filepath.Join(t.TempDir(), "ssm_agent.prom")
+	server := newTestServer(appconfig.MetricsCfg{TextfilePath: textfile})
+
+	assert.Nil(t, server.writeTextfile())
+	content, err := os.ReadFile(textfile)
+	assert.Nil(t, err)
+	assert.Contains(t, string(content), "test_total 1\n")
+
+	files, _ := os.ReadDir(filepath.Dir(textfile))
+	assert.Equal(t, 1, len(files))
+}
+
+func TestModuleExecuteAndStop(t *testing.T) {
+	textfile := filepath.Join(t.TempDir(), "ssm_agent.prom")
+	server := newTestServer(appconfig.MetricsCfg{TextfilePath: textfile, TextfileIntervalSeconds: 60})
+
+	assert.Equal(t, ModuleName, server.ModuleName())
+	assert.Nil(t, server.ModuleExecute())
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(textfile)
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+	assert.Nil(t, server.ModuleStop())
+	assert.Nil(t, server.ModuleStop())
+}
+
+func TestAppendJSONFile(t *testing.T) {
+	jsonFile := filepath.Join(t.TempDir(), "ssm-agent-metrics.json")
+	server := newTestServer(appconfig.MetricsCfg{JSONFilePath: jsonFile, JSONFileMaxSizeMB: 1, JSONFileMaxBackups: 2})
+	timeNow = func() time.Time { return time.Date(2024, 5, 2, 10, 15, 4, 0, time.UTC) }
+	defer func() { timeNow = time.Now }()
+
+	assert.Nil(t, server.appendJSONFile())
+	assert.Nil(t, server.appendJSONFile())
+
+	file, err := os.Open(jsonFile)
+	assert.Nil(t, err)
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	lines := 0
+	for scanner.Scan() {
+		var snapshot jsonSnapshot
+		assert.Nil(t, json.Unmarshal(scanner.Bytes(), &snapshot))
+		assert.Equal(t, timeNow(), snapshot.Timestamp)
+		assert.Equal(t, []metrics.Sample{{Name: "test_total", Type: "counter", Value: 1}}, snapshot.Samples)
+		lines++
+	}
+	assert.Equal(t, 2, lines)
+}
+
+func TestAppendJSONFileRotates(t *testing.T) {
+	jsonFile := filepath.Join(t.TempDir(), "ssm-agent-metrics.json")
+	server := newTestServer(appconfig.MetricsCfg{JSONFilePath: jsonFile, JSONFileMaxSizeMB: 1, JSONFileMaxBackups: 2})
+
+	// every append rotates a full file, only the configured number of backups is kept
+	assert.Nil(t, server.appendJSONFile())
+	for i := 0; i < 3; i++ {
+		assert.Nil(t, os.Truncate(jsonFile, bytesPerMB))
+		assert.Nil(t, server.appendJSONFile())
+	}
+
+	files, _ := os.ReadDir(filepath.Dir(jsonFile))
+	assert.Equal(t, 3, len(files))
+	for _, path := range []string{backupPath(jsonFile, 1), backupPath(jsonFile, 2)} {
+		info, err := os.Stat(path)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(bytesPerMB), info.Size())
+	}
+	info, err := os.Stat(jsonFile)
+	assert.Nil(t, err)
+	assert.True(t, info.Size() < bytesPerMB)
+
+	// without backups the full file is replaced
+	server.config.JSONFileMaxBackups = 0
+	assert.Nil(t, os.Truncate(jsonFile, bytesPerMB))
+	assert.Nil(t, server.appendJSONFile())
+	info, err = os.Stat(jsonFile)
+	assert.Nil(t, err)
+	assert.True(t, info.Size() < bytesPerMB)
+}
@@ -0,0 +1,252 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package metrics keeps counters and gauges about agent health and exposes them in the Prometheus text format.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	counterType = "counter"
+	gaugeType   = "gauge"
+
+	// ResultSuccess and ResultFailure are the values of result labels
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// Metric is a named series of values, optionally partitioned by one label
+type Metric struct {
+	name       string
+	help       string
+	metricType string
+	label      string
+
+	mtx    sync.Mutex
+	values map[string]float64
+}
+
+// Counter is a metric which only increases
+type Counter struct {
+	*Metric
+}
+
+// Gauge is a metric which can be set to any value
+type Gauge struct {
+	*Metric
+}
+
+// Sample is the value of a metric for one label value
+type Sample struct {
+	Name   string
+	Type   string
+	Labels map[string]string `json:",omitempty"`
+	Value  float64
+}
+
+// Registry holds the metrics written by Write
+type Registry struct {
+	mtx     sync.Mutex
+	metrics []*Metric
+}
+
+// DefaultRegistry holds the agent metrics
+var DefaultRegistry = &Registry{}
+
+// Agent metrics
+var (
+	CommandExecutions         = NewCounter("ssm_agent_command_executions_total", "Documents executed to completion by final status.", "status")
+	PluginFailures            = NewCounter("ssm_agent_plugin_failures_total", "Document steps that failed by plugin name.", "plugin")
+	MDSReconnects             = NewCounter("ssm_agent_mds_reconnects_total", "Times the message delivery service connection was reset.", "")
+	MGSReconnects             = NewCounter("ssm_agent_mgs_reconnects_total", "Times the message gateway service control channel was reconnected.", "")
+	MGSReconnectFailures      = NewCounter("ssm_agent_mgs_reconnect_failures_total", "Failed attempts to reconnect the message gateway service control channel.", "")
+	MGSRoundTrip              = NewGauge("ssm_agent_mgs_control_channel_rtt_seconds", "Round trip time of the last message gateway service control channel ping.")
+	MGSDroppedMessages        = NewCounter("ssm_agent_mgs_dropped_messages_total", "Control channel messages which could not be sent or processed by direction.", "direction")
+	CredentialRefreshes       = NewCounter("ssm_agent_credential_refreshes_total", "Credential loads from the shared credentials file by result.", "result")
+	HealthPings               = NewCounter("ssm_agent_health_pings_total", "Health pings sent to Systems Manager by result.", "result")
+	LastHealthPing            = NewGauge("ssm_agent_last_health_ping_timestamp_seconds", "Unix time of the last successful health ping.")
+	StartTime                 = NewGauge("ssm_agent_start_time_seconds", "Unix time the agent worker started.")
+	ReachabilityProbeFailures = NewCounter("ssm_agent_reachability_probe_failures_total", "Failed service endpoint reachability probes by service.", "service")
+	PluginCPUSeconds          = NewCounter("ssm_agent_plugin_cpu_seconds_total", "User and system CPU time of processes executed by document steps by plugin name.", "plugin")
+	PluginIOBytes             = NewCounter("ssm_agent_plugin_io_bytes_total", "Bytes read and written by processes executed by document steps by plugin name.", "plugin")
+)
+
+func init() {
+	StartTime.SetToCurrentTime()
+}
+
+// NewCounter creates a counter in the default registry, label is empty for counters without label
+func NewCounter(name, help, label string) *Counter {
+	return DefaultRegistry.NewCounter(name, help, label)
+}
+
+// NewGauge creates a gauge without label in the default registry
+func NewGauge(name, help string) *Gauge {
+	return DefaultRegistry.NewGauge(name, help)
+}
+
+// NewCounter creates a counter in the registry, label is empty for counters without label
+func (r *Registry) NewCounter(name, help, label string) *Counter {
+	return &Counter{r.register(name, help, counterType, label)}
+}
+
+// NewGauge creates a gauge without label in the registry
+func (r *Registry) NewGauge(name, help string) *Gauge {
+	return &Gauge{r.register(name, help, gaugeType, "")}
+}
+
+// ResultLabel returns the result label value for an error
+func ResultLabel(err error) string {
+	if err != nil {
+		return ResultFailure
+	}
+	return ResultSuccess
+}
+
+// Inc increments the counter, labelValue is ignored for counters without label
+func (c *Counter) Inc(labelValue string) {
+	c.Add(labelValue, 1)
+}
+
+// Add increases the counter by a non negative value
+func (c *Counter) Add(labelValue string, value float64) {
+	if value < 0 {
+		return
+	}
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.values[c.key(labelValue)] += value
+}
+
+// Set sets the gauge value
+func (g *Gauge) Set(value float64) {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+	g.values[""] = value
+}
+
+// SetToCurrentTime sets the gauge to the current unix time
+func (g *Gauge) SetToCurrentTime() {
+	g.Set(float64(time.Now().Unix()))
+}
+
+// Value returns the current value for a label value
+func (m *Metric) Value(labelValue string) float64 {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.values[m.key(labelValue)]
+}
+
+func (m *Metric) key(labelValue string) string {
+	if m.label == "" {
+		return ""
+	}
+	return labelValue
+}
+
+func (r *Registry) register(name, help, metricType, label string) *Metric {
+	metric := &Metric{
+		name:       name,
+		help:       help,
+		metricType: metricType,
+		label:      label,
+		values:     map[string]float64{},
+	}
+	if label == "" {
+		metric.values[""] = 0
+	}
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.metrics = append(r.metrics, metric)
+	return metric
+}
+
+// Write writes all metrics in the Prometheus text exposition format
+func (r *Registry) Write(w io.Writer) (err error) {
+	r.mtx.Lock()
+	metrics := append([]*Metric{}, r.metrics...)
+	r.mtx.Unlock()
+
+	buffered := bufio.NewWriter(w)
+	for _, metric := range metrics {
+		metric.write(buffered)
+	}
+	return buffered.Flush()
+}
+
+// Samples returns the current values of all metrics, ordered like Write
+func (r *Registry) Samples() (samples []Sample) {
+	r.mtx.Lock()
+	metrics := append([]*Metric{}, r.metrics...)
+	r.mtx.Unlock()
+
+	for _, metric := range metrics {
+		samples = append(samples, metric.samples()...)
+	}
+	return samples
+}
+
+func (m *Metric) samples() (samples []Sample) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	for _, labelValue := range m.sortedLabelValues() {
+		sample := Sample{Name: m.name, Type: m.metricType, Value: m.values[labelValue]}
+		if m.label != "" {
+			sample.Labels = map[string]string{m.label: labelValue}
+		}
+		samples = append(samples, sample)
+	}
+	return samples
+}
+
+// sortedLabelValues returns the label values in the order they are written, callers hold the metric lock
+func (m *Metric) sortedLabelValues() []string {
+	labelValues := make([]string, 0, len(m.values))
+	for labelValue := range m.values {
+		labelValues = append(labelValues, labelValue)
+	}
+	sort.Strings(labelValues)
+	return labelValues
+}
+
+func (m *Metric) write(w *bufio.Writer) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n", m.name, m.help)
+	fmt.Fprintf(w, "# TYPE %s %s\n", m.name, m.metricType)
+
+	for _, labelValue := range m.sortedLabelValues() {
+		value := strconv.FormatFloat(m.values[labelValue], 'g', -1, 64)
+		if m.label == "" {
+			fmt.Fprintf(w, "%s %s\n", m.name, value)
+		} else {
+			fmt.Fprintf(w, "%s{%s=\"%s\"} %s\n", m.name, m.label, escapeLabelValue(labelValue), value)
+		}
+	}
+}
+
+// escapeLabelValue escapes backslash, double quote and line feed as required by the text format
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
@@ -0,0 +1,73 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistryWrite(t *testing.T) {
+	registry := &Registry{}
+	executions := registry.NewCounter("test_executions_total", "Test executions.", "status")
+	reconnects := registry.NewCounter("test_reconnects_total", "Test reconnects.", "")
+	lastPing := registry.NewGauge("test_last_ping_seconds", "Test last ping.")
+
+	executions.Inc("Success")
+	executions.Inc("Success")
+	executions.Inc(`Fail"ed`)
+	executions.Add("Success", -1)
+	reconnects.Inc("ignored")
+	lastPing.Set(1700000000)
+
+	var buffer bytes.Buffer
+	assert.Nil(t, registry.Write(&buffer))
+	assert.Equal(t, `# HELP test_executions_total Test executions.
+# TYPE test_executions_total counter
+test_executions_total{status="Fail\"ed"} 1
+test_executions_total{status="Success"} 2
+# HELP test_reconnects_total Test reconnects.
+# TYPE test_reconnects_total counter
+test_reconnects_total 1
+# HELP test_last_ping_seconds Test last ping.
+# TYPE test_last_ping_seconds gauge
+test_last_ping_seconds 1.7e+09
+`, buffer.String())
+	assert.Equal(t, float64(2), executions.Value("Success"))
+	assert.Equal(t, float64(1), reconnects.Value(""))
+}
+
+func TestRegistrySamples(t *testing.T) {
+	registry := &Registry{}
+	executions := registry.NewCounter("test_executions_total", "Test executions.", "status")
+	lastPing := registry.NewGauge("test_last_ping_seconds", "Test last ping.")
+
+	executions.Inc("Success")
+	executions.Inc("Failed")
+	lastPing.Set(1700000000)
+
+	assert.Equal(t, []Sample{
+		{Name: "test_executions_total", Type: counterType, Labels: map[string]string{"status": "Failed"}, Value: 1},
+		{Name: "test_executions_total", Type: counterType, Labels: map[string]string{"status": "Success"}, Value: 1},
+		{Name: "test_last_ping_seconds", Type: gaugeType, Value: 1700000000},
+	}, registry.Samples())
+}
+
+func TestResultLabel(t *testing.T) {
+	assert.Equal(t, ResultSuccess, ResultLabel(nil))
+	assert.Equal(t, ResultFailure, ResultLabel(errors.New("failed")))
+}
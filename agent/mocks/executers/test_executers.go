@@ -18,6 +18,7 @@ import (
 	"os"
 
 	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/contracts"
 	"github.com/aws/amazon-ssm-agent/agent/task"
 	"github.com/stretchr/testify/mock"
 )
@@ -57,9 +58,9 @@ func (m *MockCommandExecuter) NewExecute(
 	commandName string,
 	commandArguments []string,
 	envVars map[string]string,
-) (exitCode int, err error) {
+) (exitCode int, accounting *contracts.ProcessAccounting, err error) {
 	args := m.Called(context, workingDir, stdoutWriter, stderrWriter, cancelFlag, executionTimeout, commandName, commandArguments, envVars)
-	return args.Get(0).(int), args.Error(1)
+	return args.Get(0).(int), args.Get(1).(*contracts.ProcessAccounting), args.Error(2)
 }
 
 // StartExe is a mocked method that just returns what mock tells it to.
@@ -56,6 +56,12 @@ func (mockPool *MockedPool) HasJob(jobID string) bool {
 	return args.Bool(0)
 }
 
+// JobCount mocks the method with the same name.
+func (mockPool *MockedPool) JobCount() int {
+	args := mockPool.Called()
+	return args.Int(0)
+}
+
 // BufferTokensIssued mocks the method with the same name.
 func (mockPool *MockedPool) BufferTokensIssued() int {
 	args := mockPool.Called()
@@ -0,0 +1,100 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package network
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+)
+
+type clientCertificateEntry struct {
+	certificate *tls.Certificate
+	certModTime time.Time
+	keyModTime  time.Time
+}
+
+var clientCertificateMutex = sync.Mutex{}
+var clientCertificates = map[appconfig.ClientCertificateCfg]*clientCertificateEntry{}
+
+// Assign functions to variables to allow unittest to override
+var loadX509KeyPair = tls.LoadX509KeyPair
+var loadStoreCertificate = loadPlatformStoreCertificate
+
+// hasClientCertificate returns true if a client certificate is configured
+func hasClientCertificate(appConfig appconfig.SsmagentConfig) bool {
+	return appConfig.ClientCertificate.CertificateFile != "" || appConfig.ClientCertificate.CertificateStore != ""
+}
+
+// getClientCertificateFunc returns the callback presenting the configured certificate to servers requesting one.
+// Errors are logged and no certificate is sent, so endpoints which do not enforce mutual TLS stay reachable.
+func getClientCertificateFunc(log log.T, config appconfig.ClientCertificateCfg) func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return func(info *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		certificate, err := loadClientCertificate(config)
+		if err != nil {
+			log.Errorf("Failed to load the client certificate: %v", err)
+			return &tls.Certificate{}, nil
+		}
+		if err = info.SupportsCertificate(certificate); err != nil {
+			log.Warnf("Client certificate is not accepted by the server: %v", err)
+		}
+		return certificate, nil
+	}
+}
+
+// loadClientCertificate returns the configured certificate, reloading certificate files once they changed
+func loadClientCertificate(config appconfig.ClientCertificateCfg) (*tls.Certificate, error) {
+	clientCertificateMutex.Lock()
+	defer clientCertificateMutex.Unlock()
+
+	entry, found := clientCertificates[config]
+	if config.CertificateStore != "" {
+		if found {
+			return entry.certificate, nil
+		}
+		certificate, err := loadStoreCertificate(config.CertificateStore)
+		if err != nil {
+			return nil, err
+		}
+		clientCertificates[config] = &clientCertificateEntry{certificate: certificate}
+		return certificate, nil
+	}
+
+	certInfo, err := os.Stat(config.CertificateFile)
+	if err != nil {
+		return nil, err
+	}
+	keyInfo, err := os.Stat(config.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	if found && certInfo.ModTime().Equal(entry.certModTime) && keyInfo.ModTime().Equal(entry.keyModTime) {
+		return entry.certificate, nil
+	}
+	certificate, err := loadX509KeyPair(config.CertificateFile, config.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", config.CertificateFile, err)
+	}
+	clientCertificates[config] = &clientCertificateEntry{
+		certificate: &certificate,
+		certModTime: certInfo.ModTime(),
+		keyModTime:  keyInfo.ModTime(),
+	}
+	return &certificate, nil
+}
@@ -0,0 +1,130 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package network
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	logmocks "github.com/aws/amazon-ssm-agent/agent/mocks/log"
+	"github.com/stretchr/testify/assert"
+)
+
+// writeClientCertificate writes a certificate signed by the CA and its key as PEM files
+func writeClientCertificate(t *testing.T, dir string, commonName string, ca *x509.Certificate, caKey *ecdsa.PrivateKey, modTime time.Time) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	assert.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+
+	certFile, keyFile := filepath.Join(dir, "client.crt"), filepath.Join(dir, "client.key")
+	assert.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	assert.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+	assert.NoError(t, os.Chtimes(certFile, modTime, modTime))
+	assert.NoError(t, os.Chtimes(keyFile, modTime, modTime))
+	return certFile, keyFile
+}
+
+func newCertificateAuthority(t *testing.T) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	ca, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	return ca, key
+}
+
+func TestClientCertificate(t *testing.T) {
+	t.Cleanup(func() { clientCertificates = map[appconfig.ClientCertificateCfg]*clientCertificateEntry{} })
+	ca, caKey := newCertificateAuthority(t)
+	dir := t.TempDir()
+	modTime := time.Now().Add(-time.Minute)
+	certFile, keyFile := writeClientCertificate(t, dir, "instance-1", ca, caKey, modTime)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	config := appconfig.SsmagentConfig{}
+	assert.False(t, hasClientCertificate(config))
+	config.ClientCertificate = appconfig.ClientCertificateCfg{CertificateFile: certFile, KeyFile: keyFile}
+	assert.True(t, hasClientCertificate(config))
+
+	get := func() (string, error) {
+		tlsConfig := GetDefaultTLSConfig(logmocks.NewMockLog(), config)
+		tlsConfig.RootCAs = x509.NewCertPool()
+		tlsConfig.RootCAs.AddCert(server.Certificate())
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		body := make([]byte, 64)
+		n, _ := resp.Body.Read(body)
+		return string(body[:n]), nil
+	}
+
+	commonName, err := get()
+	assert.NoError(t, err)
+	assert.Equal(t, "instance-1", commonName)
+
+	// rotated certificates are picked up without restarting the agent
+	writeClientCertificate(t, dir, "instance-2", ca, caKey, modTime.Add(time.Second))
+	commonName, err = get()
+	assert.NoError(t, err)
+	assert.Equal(t, "instance-2", commonName)
+
+	// without a usable certificate the handshake is attempted without one
+	assert.NoError(t, os.Remove(keyFile))
+	_, err = get()
+	assert.Error(t, err)
+}
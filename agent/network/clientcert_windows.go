@@ -0,0 +1,213 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package network
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	bcryptPadPKCS1 = 0x00000002
+	bcryptPadPSS   = 0x00000008
+)
+
+var (
+	ncrypt               = windows.NewLazySystemDLL("ncrypt.dll")
+	procNCryptSignHash   = ncrypt.NewProc("NCryptSignHash")
+	procNCryptFreeObject = ncrypt.NewProc("NCryptFreeObject")
+)
+
+type bcryptPKCS1PaddingInfo struct {
+	algorithmID *uint16
+}
+
+type bcryptPSSPaddingInfo struct {
+	algorithmID *uint16
+	saltSize    uint32
+}
+
+// storeSigner signs with a private key held by the Windows key storage providers, which may not be exportable
+type storeSigner struct {
+	key       uintptr
+	publicKey crypto.PublicKey
+}
+
+// loadPlatformStoreCertificate loads a certificate and its private key from a reference
+// like LocalMachine\My\<thumbprint> or CurrentUser\My\<thumbprint>
+func loadPlatformStoreCertificate(reference string) (*tls.Certificate, error) {
+	parts := strings.Split(reference, `\`)
+	if len(parts) != 3 {
+		return nil, fmt.Errorf(`invalid certificate store reference %s, expected <LocalMachine|CurrentUser>\<store>\<thumbprint>`, reference)
+	}
+	var location uint32
+	switch strings.ToLower(parts[0]) {
+	case "localmachine":
+		location = windows.CERT_SYSTEM_STORE_LOCAL_MACHINE
+	case "currentuser":
+		location = windows.CERT_SYSTEM_STORE_CURRENT_USER
+	default:
+		return nil, fmt.Errorf("unknown certificate store location %s", parts[0])
+	}
+	thumbprint, err := hex.DecodeString(strings.ReplaceAll(parts[2], " ", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate thumbprint %s: %v", parts[2], err)
+	}
+	storeName, err := syscall.UTF16PtrFromString(parts[1])
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := windows.CertOpenStore(
+		windows.CERT_STORE_PROV_SYSTEM,
+		0,
+		0,
+		location|windows.CERT_STORE_READONLY_FLAG|windows.CERT_STORE_OPEN_EXISTING_FLAG,
+		uintptr(unsafe.Pointer(storeName)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open certificate store %s: %v", reference, err)
+	}
+	defer windows.CertCloseStore(store, 0)
+
+	hash := windows.CryptHashBlob{Size: uint32(len(thumbprint)), Data: &thumbprint[0]}
+	certContext, err := windows.CertFindCertificateInStore(
+		store,
+		windows.X509_ASN_ENCODING|windows.PKCS_7_ASN_ENCODING,
+		0,
+		windows.CERT_FIND_HASH,
+		unsafe.Pointer(&hash),
+		nil)
+	if err != nil {
+		return nil, fmt.Errorf("certificate %s not found: %v", reference, err)
+	}
+	defer windows.CertFreeCertificateContext(certContext)
+
+	der := append([]byte{}, unsafe.Slice(certContext.EncodedCert, certContext.Length)...)
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	var key windows.Handle
+	var keySpec uint32
+	var callerFree bool
+	// without the cache flag the key handle is owned by the agent and outlives the certificate context
+	err = windows.CryptAcquireCertificatePrivateKey(
+		certContext,
+		windows.CRYPT_ACQUIRE_ONLY_NCRYPT_KEY_FLAG,
+		nil,
+		&key,
+		&keySpec,
+		&callerFree)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire the private key of certificate %s: %v", reference, err)
+	}
+	if callerFree {
+		// the handle is kept with the loaded certificate, only released if the certificate can not be used
+		defer func() {
+			if err != nil {
+				procNCryptFreeObject.Call(uintptr(key))
+			}
+		}()
+	}
+	switch certificate.PublicKey.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey:
+	default:
+		err = fmt.Errorf("unsupported public key type %T", certificate.PublicKey)
+		return nil, err
+	}
+
+	return &tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  &storeSigner{key: uintptr(key), publicKey: certificate.PublicKey},
+		Leaf:        certificate,
+	}, nil
+}
+
+// Public returns the public key of the certificate
+func (s *storeSigner) Public() crypto.PublicKey {
+	return s.publicKey
+}
+
+// Sign signs the digest with the key storage provider
+func (s *storeSigner) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if _, isECDSA := s.publicKey.(*ecdsa.PublicKey); isECDSA {
+		signature, err := s.signHash(nil, digest, 0)
+		if err != nil {
+			return nil, err
+		}
+		// the provider returns r and s concatenated, TLS expects an ASN.1 structure
+		half := len(signature) / 2
+		return asn1.Marshal(struct{ R, S *big.Int }{
+			new(big.Int).SetBytes(signature[:half]),
+			new(big.Int).SetBytes(signature[half:]),
+		})
+	}
+
+	algorithm, err := hashAlgorithmName(opts.HashFunc())
+	if err != nil {
+		return nil, err
+	}
+	if pssOptions, isPSS := opts.(*rsa.PSSOptions); isPSS {
+		saltLength := pssOptions.SaltLength
+		if saltLength == rsa.PSSSaltLengthEqualsHash || saltLength == rsa.PSSSaltLengthAuto {
+			saltLength = opts.HashFunc().Size()
+		}
+		padding := &bcryptPSSPaddingInfo{algorithmID: algorithm, saltSize: uint32(saltLength)}
+		return s.signHash(unsafe.Pointer(padding), digest, bcryptPadPSS)
+	}
+	padding := &bcryptPKCS1PaddingInfo{algorithmID: algorithm}
+	return s.signHash(unsafe.Pointer(padding), digest, bcryptPadPKCS1)
+}
+
+func (s *storeSigner) signHash(padding unsafe.Pointer, digest []byte, flags uint32) ([]byte, error) {
+	var size uint32
+	ret, _, _ := procNCryptSignHash.Call(s.key, uintptr(padding), uintptr(unsafe.Pointer(&digest[0])), uintptr(len(digest)), 0, 0, uintptr(unsafe.Pointer(&size)), uintptr(flags))
+	if ret != 0 {
+		return nil, fmt.Errorf("NCryptSignHash failed: 0x%x", uint32(ret))
+	}
+	signature := make([]byte, size)
+	ret, _, _ = procNCryptSignHash.Call(s.key, uintptr(padding), uintptr(unsafe.Pointer(&digest[0])), uintptr(len(digest)), uintptr(unsafe.Pointer(&signature[0])), uintptr(size), uintptr(unsafe.Pointer(&size)), uintptr(flags))
+	if ret != 0 {
+		return nil, fmt.Errorf("NCryptSignHash failed: 0x%x", uint32(ret))
+	}
+	return signature[:size], nil
+}
+
+func hashAlgorithmName(hash crypto.Hash) (*uint16, error) {
+	switch hash {
+	case crypto.SHA1:
+		return syscall.UTF16PtrFromString("SHA1")
+	case crypto.SHA256:
+		return syscall.UTF16PtrFromString("SHA256")
+	case crypto.SHA384:
+		return syscall.UTF16PtrFromString("SHA384")
+	case crypto.SHA512:
+		return syscall.UTF16PtrFromString("SHA512")
+	}
+	return nil, fmt.Errorf("unsupported hash algorithm %v", hash)
+}
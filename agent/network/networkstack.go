@@ -0,0 +1,62 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package network
+
+import (
+	"os"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+)
+
+const (
+	// imdsEndpointModeEnv selects whether the AWS SDK reaches the instance metadata service over IPv4 or IPv6
+	imdsEndpointModeEnv = "AWS_EC2_METADATA_SERVICE_ENDPOINT_MODE"
+	// dualStackEndpointEnv makes the AWS SDK resolve dual-stack endpoints for clients without a configured endpoint
+	dualStackEndpointEnv = "AWS_USE_DUALSTACK_ENDPOINT"
+
+	imdsEndpointModeIPv6 = "IPv6"
+)
+
+var (
+	osLookupEnv = os.LookupEnv
+	osSetenv    = os.Setenv
+)
+
+// ApplyNetworkStack exports the network stack of the agent configuration to the environment read by the AWS SDK,
+// the worker processes inherit it from the agent. Values already present in the environment are kept.
+func ApplyNetworkStack(log log.T, appConfig appconfig.SsmagentConfig) {
+	settings := map[string]string{}
+	switch appConfig.Agent.NetworkStack {
+	case appconfig.NetworkStackIPv6:
+		settings[imdsEndpointModeEnv] = imdsEndpointModeIPv6
+		settings[dualStackEndpointEnv] = "true"
+	case appconfig.NetworkStackDualStack:
+		settings[dualStackEndpointEnv] = "true"
+	default:
+		return
+	}
+
+	for key, value := range settings {
+		if current, found := osLookupEnv(key); found {
+			log.Infof("Keeping %v=%v from the environment for network stack %v", key, current, appConfig.Agent.NetworkStack)
+			continue
+		}
+		if err := osSetenv(key, value); err != nil {
+			log.Warnf("Failed to set %v for network stack %v: %v", key, appConfig.Agent.NetworkStack, err)
+			continue
+		}
+		log.Infof("Set %v=%v for network stack %v", key, value, appConfig.Agent.NetworkStack)
+	}
+}
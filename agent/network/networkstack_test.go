@@ -0,0 +1,65 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package network
+
+import (
+	"os"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	logmocks "github.com/aws/amazon-ssm-agent/agent/mocks/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockEnvironment(t *testing.T, environment map[string]string) {
+	osLookupEnv = func(key string) (string, bool) {
+		value, found := environment[key]
+		return value, found
+	}
+	osSetenv = func(key, value string) error {
+		environment[key] = value
+		return nil
+	}
+	t.Cleanup(func() {
+		osLookupEnv = os.LookupEnv
+		osSetenv = os.Setenv
+	})
+}
+
+func TestApplyNetworkStack(t *testing.T) {
+	testCases := map[string]map[string]string{
+		appconfig.NetworkStackIPv4:      {},
+		appconfig.NetworkStackDualStack: {dualStackEndpointEnv: "true"},
+		appconfig.NetworkStackIPv6:      {dualStackEndpointEnv: "true", imdsEndpointModeEnv: "IPv6"},
+	}
+	for networkStack, expected := range testCases {
+		environment := map[string]string{}
+		mockEnvironment(t, environment)
+		config := appconfig.SsmagentConfig{}
+		config.Agent.NetworkStack = networkStack
+
+		ApplyNetworkStack(logmocks.NewMockLog(), config)
+		assert.Equal(t, expected, environment, networkStack)
+	}
+}
+
+func TestApplyNetworkStack_KeepsEnvironment(t *testing.T) {
+	environment := map[string]string{imdsEndpointModeEnv: "IPv4"}
+	mockEnvironment(t, environment)
+	config := appconfig.SsmagentConfig{}
+	config.Agent.NetworkStack = appconfig.NetworkStackIPv6
+
+	ApplyNetworkStack(logmocks.NewMockLog(), config)
+	assert.Equal(t, map[string]string{imdsEndpointModeEnv: "IPv4", dualStackEndpointEnv: "true"}, environment)
+}
@@ -0,0 +1,357 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package network
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/network/proxyauth"
+	"github.com/aws/amazon-ssm-agent/agent/proxyconfig/pac"
+)
+
+const (
+	pacFetchTimeout        = 30 * time.Second
+	pacRetryInterval       = time.Minute
+	pacMaxScriptSize       = 1024 * 1024
+	proxyHandshakeTimeout  = 30 * time.Second
+	proxyDialTimeout       = 30 * time.Second
+	proxyKeepAliveInterval = 30 * time.Second
+)
+
+type pacEntry struct {
+	script  *pac.Script
+	fetched time.Time
+	err     error
+	// loading is closed once the running download of the script completed, it is nil when no download runs
+	loading chan struct{}
+}
+
+var pacMutex = sync.Mutex{}
+var pacScripts = map[string]*pacEntry{}
+
+// Assign functions to variables to allow unittest to override
+var fetchPACScript = fetchPAC
+var proxyFromEnvironment = http.ProxyFromEnvironment
+var dialContext = (&net.Dialer{Timeout: proxyDialTimeout, KeepAlive: proxyKeepAliveInterval}).DialContext
+var pacTimeNow = time.Now
+
+// usesProxyTunnel returns true when the proxy requires connection based authentication,
+// which the standard library does not support, so connections are tunnelled by the agent
+func usesProxyTunnel(appConfig appconfig.SsmagentConfig) bool {
+	return appConfig.Proxy.AuthScheme == appconfig.ProxyAuthSchemeNTLM || appConfig.Proxy.AuthScheme == appconfig.ProxyAuthSchemeNegotiate
+}
+
+// GetProxyFunc returns the function choosing the proxy of each request.
+// It returns nil when connections are tunnelled through the proxy by the dialer from GetProxyDialer.
+func GetProxyFunc(log log.T, appConfig appconfig.SsmagentConfig) func(*http.Request) (*url.URL, error) {
+	if usesProxyTunnel(appConfig) {
+		return nil
+	}
+	return selectProxy(log, appConfig)
+}
+
+// GetProxyDialer returns a dial function opening connections through a proxy with NTLM or Negotiate authentication.
+// It returns nil when the proxy is handled by the function from GetProxyFunc.
+func GetProxyDialer(log log.T, appConfig appconfig.SsmagentConfig) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	if !usesProxyTunnel(appConfig) {
+		return nil
+	}
+	proxyFunc := selectProxy(log, appConfig)
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		scheme := "https"
+		if _, port, err := net.SplitHostPort(addr); err == nil && port == "80" {
+			scheme = "http"
+		}
+		proxyURL, err := proxyFunc(&http.Request{URL: &url.URL{Scheme: scheme, Host: addr}})
+		if err != nil {
+			return nil, err
+		}
+		if proxyURL == nil {
+			return dialContext(ctx, network, addr)
+		}
+		return connectThroughProxy(ctx, log, appConfig, proxyURL, addr)
+	}
+}
+
+// selectProxy returns the proxy of a request from the auto-config script when configured, from the environment otherwise
+func selectProxy(log log.T, appConfig appconfig.SsmagentConfig) func(*http.Request) (*url.URL, error) {
+	if appConfig.Proxy.AutoConfigURL == "" {
+		return proxyFromEnvironment
+	}
+	return func(req *http.Request) (*url.URL, error) {
+		host := req.URL.Hostname()
+		// instance metadata and local endpoints are never proxied
+		if ip := net.ParseIP(host); host == "localhost" || (ip != nil && (ip.IsLoopback() || ip.IsLinkLocalUnicast())) {
+			return nil, nil
+		}
+		script, err := loadPACScript(log, appConfig)
+		if err != nil {
+			return proxyFromEnvironment(req)
+		}
+		// like browsers, only the origin of https URLs is passed to the script
+		target := req.URL.String()
+		if req.URL.Scheme == "https" {
+			target = "https://" + req.URL.Host + "/"
+		}
+		result, err := script.FindProxyForURL(target, host)
+		if err != nil {
+			log.Warnf("Failed to evaluate proxy auto-config script for %s, using proxy environment variables: %v", host, err)
+			return proxyFromEnvironment(req)
+		}
+		// the agent does not fail over, the first usable entry is used
+		proxies := pac.ParseResult(result)
+		if len(proxies) == 0 || proxies[0].Direct {
+			return nil, nil
+		}
+		proxyURL := &url.URL{Scheme: proxies[0].Scheme, Host: proxies[0].Host}
+		if envProxy, err := proxyFromEnvironment(req); err == nil && envProxy != nil {
+			proxyURL.User = envProxy.User
+		}
+		return proxyURL, nil
+	}
+}
+
+// loadPACScript returns the auto-config script, downloading it again once the refresh interval elapsed.
+// The script is downloaded without holding pacMutex, requests keep using the previous version during a refresh
+// and wait for the download only when there is no previous version.
+func loadPACScript(log log.T, appConfig appconfig.SsmagentConfig) (*pac.Script, error) {
+	scriptURL := appConfig.Proxy.AutoConfigURL
+	refreshInterval := time.Duration(appConfig.Proxy.AutoConfigRefreshMinutes) * time.Minute
+	if refreshInterval <= 0 {
+		refreshInterval = appconfig.DefaultProxyAutoConfigRefreshMinutes * time.Minute
+	}
+
+	pacMutex.Lock()
+	entry, found := pacScripts[scriptURL]
+	if !found {
+		entry = &pacEntry{}
+		pacScripts[scriptURL] = entry
+	}
+	if entry.loading != nil {
+		if entry.script != nil {
+			pacMutex.Unlock()
+			return entry.script, nil
+		}
+		loading := entry.loading
+		pacMutex.Unlock()
+		<-loading
+		pacMutex.Lock()
+		defer pacMutex.Unlock()
+		return entry.script, entry.err
+	}
+	if found {
+		age := pacTimeNow().Sub(entry.fetched)
+		if (entry.err == nil && age < refreshInterval) || (entry.err != nil && age < pacRetryInterval) {
+			pacMutex.Unlock()
+			return entry.script, entry.err
+		}
+	}
+	loading := make(chan struct{})
+	entry.loading = loading
+	pacMutex.Unlock()
+
+	source, err := fetchPACScript(log, appConfig, scriptURL)
+	var script *pac.Script
+	if err == nil {
+		script, err = pac.Parse(source)
+	}
+
+	pacMutex.Lock()
+	defer pacMutex.Unlock()
+	defer close(loading)
+	entry.loading = nil
+	entry.fetched = pacTimeNow()
+	if err != nil {
+		if entry.script != nil {
+			log.Warnf("Failed to refresh proxy auto-config script %s, keeping the previous version: %v", scriptURL, err)
+			return entry.script, nil
+		}
+		log.Warnf("Failed to load proxy auto-config script %s, using proxy environment variables: %v", scriptURL, err)
+	} else {
+		log.Infof("Loaded proxy auto-config script %s", scriptURL)
+	}
+	entry.script, entry.err = script, err
+	return script, err
+}
+
+// fetchPAC reads the auto-config script from a file or downloads it without going through a proxy
+func fetchPAC(log log.T, appConfig appconfig.SsmagentConfig, scriptURL string) (string, error) {
+	parsedURL, err := url.Parse(scriptURL)
+	if err != nil {
+		return "", err
+	}
+	var reader io.Reader
+	switch parsedURL.Scheme {
+	case "file":
+		path := parsedURL.Path
+		// file:///C:/path on Windows
+		if len(path) > 2 && path[0] == '/' && path[2] == ':' {
+			path = path[1:]
+		}
+		file, err := os.Open(filepath.FromSlash(path))
+		if err != nil {
+			return "", err
+		}
+		defer file.Close()
+		reader = file
+	case "http", "https":
+		client := &http.Client{
+			Transport: getSharedDirectTransport(log, appConfig),
+			Timeout:   pacFetchTimeout,
+		}
+		resp, err := client.Get(scriptURL)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("unexpected status %s", resp.Status)
+		}
+		reader = resp.Body
+	default:
+		return "", fmt.Errorf("unsupported proxy auto-config URL scheme %q", parsedURL.Scheme)
+	}
+	source, err := io.ReadAll(io.LimitReader(reader, pacMaxScriptSize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(source) > pacMaxScriptSize {
+		return "", fmt.Errorf("proxy auto-config script is larger than %d bytes", pacMaxScriptSize)
+	}
+	return string(source), nil
+}
+
+// bufferedConn returns the bytes the proxy sent after the CONNECT response before reading from the connection
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
+
+// connectThroughProxy opens a tunnel to addr, running the authentication handshake on the CONNECT requests
+func connectThroughProxy(ctx context.Context, log log.T, appConfig appconfig.SsmagentConfig, proxyURL *url.URL, addr string) (net.Conn, error) {
+	proxyAddr := proxyURL.Host
+	if proxyURL.Port() == "" {
+		if proxyURL.Scheme == "https" {
+			proxyAddr = net.JoinHostPort(proxyURL.Hostname(), "443")
+		} else {
+			proxyAddr = net.JoinHostPort(proxyURL.Hostname(), "80")
+		}
+	}
+	conn, err := dialContext(ctx, "tcp", proxyAddr)
+	if err != nil {
+		return nil, err
+	}
+	if proxyURL.Scheme == "https" {
+		tlsConfig := GetDefaultTLSConfig(log, appConfig)
+		tlsConfig.ServerName = proxyURL.Hostname()
+		tlsConn := tls.Client(conn, tlsConfig)
+		if err = tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		conn = tlsConn
+	}
+
+	tunnel, err := proxyHandshake(conn, appConfig.Proxy.AuthScheme, proxyURL, addr)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to connect to %s through proxy %s: %v", addr, proxyURL.Host, err)
+	}
+	log.Debugf("Connected to %s through proxy %s with %s authentication", addr, proxyURL.Host, appConfig.Proxy.AuthScheme)
+	return tunnel, nil
+}
+
+func proxyHandshake(conn net.Conn, scheme string, proxyURL *url.URL, addr string) (net.Conn, error) {
+	authenticator, err := proxyauth.New(scheme, proxyURL.Hostname(), proxyURL.User)
+	if err != nil {
+		return nil, err
+	}
+	defer authenticator.Close()
+
+	conn.SetDeadline(time.Now().Add(proxyHandshakeTimeout))
+	reader := bufio.NewReader(conn)
+	var challenge []byte
+	for {
+		token, err := authenticator.Next(challenge)
+		if err != nil {
+			return nil, err
+		}
+		req := &http.Request{
+			Method: http.MethodConnect,
+			URL:    &url.URL{Opaque: addr},
+			Host:   addr,
+			Header: http.Header{},
+		}
+		req.Header.Set("Proxy-Authorization", authenticator.Scheme()+" "+base64.StdEncoding.EncodeToString(token))
+		req.Header.Set("Proxy-Connection", "Keep-Alive")
+		if err = req.Write(conn); err != nil {
+			return nil, err
+		}
+		resp, err := http.ReadResponse(reader, req)
+		if err != nil {
+			return nil, err
+		}
+
+		switch resp.StatusCode {
+		case http.StatusOK:
+			conn.SetDeadline(time.Time{})
+			if reader.Buffered() > 0 {
+				return &bufferedConn{Conn: conn, reader: reader}, nil
+			}
+			return conn, nil
+		case http.StatusProxyAuthRequired:
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			if resp.Close {
+				return nil, fmt.Errorf("proxy closed the connection during %s authentication", authenticator.Scheme())
+			}
+			if challenge, err = proxyChallenge(resp.Header, authenticator.Scheme()); err != nil {
+				return nil, err
+			}
+		default:
+			resp.Body.Close()
+			return nil, fmt.Errorf("proxy responded %s", resp.Status)
+		}
+	}
+}
+
+// proxyChallenge returns the token of the Proxy-Authenticate header for the scheme
+func proxyChallenge(header http.Header, scheme string) ([]byte, error) {
+	for _, value := range header.Values("Proxy-Authenticate") {
+		fields := strings.Fields(value)
+		if len(fields) == 2 && strings.EqualFold(fields[0], scheme) {
+			return base64.StdEncoding.DecodeString(fields[1])
+		}
+	}
+	return nil, fmt.Errorf("proxy did not send a %s challenge", scheme)
+}
@@ -0,0 +1,70 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package proxyauth
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// md4 returns the RFC 1320 MD4 digest of data, which NTLM uses to hash passwords
+func md4(data []byte) []byte {
+	message := append([]byte{}, data...)
+	message = append(message, 0x80)
+	for len(message)%64 != 56 {
+		message = append(message, 0)
+	}
+	message = binary.LittleEndian.AppendUint64(message, uint64(len(data))*8)
+
+	a, b, c, d := uint32(0x67452301), uint32(0xefcdab89), uint32(0x98badcfe), uint32(0x10325476)
+	var x [16]uint32
+	for block := 0; block < len(message); block += 64 {
+		for i := range x {
+			x[i] = binary.LittleEndian.Uint32(message[block+4*i:])
+		}
+		aa, bb, cc, dd := a, b, c, d
+
+		f := func(x, y, z uint32) uint32 { return x&y | ^x&z }
+		for _, i := range []int{0, 4, 8, 12} {
+			a = bits.RotateLeft32(a+f(b, c, d)+x[i], 3)
+			d = bits.RotateLeft32(d+f(a, b, c)+x[i+1], 7)
+			c = bits.RotateLeft32(c+f(d, a, b)+x[i+2], 11)
+			b = bits.RotateLeft32(b+f(c, d, a)+x[i+3], 19)
+		}
+
+		g := func(x, y, z uint32) uint32 { return x&y | x&z | y&z }
+		for _, i := range []int{0, 1, 2, 3} {
+			a = bits.RotateLeft32(a+g(b, c, d)+x[i]+0x5a827999, 3)
+			d = bits.RotateLeft32(d+g(a, b, c)+x[i+4]+0x5a827999, 5)
+			c = bits.RotateLeft32(c+g(d, a, b)+x[i+8]+0x5a827999, 9)
+			b = bits.RotateLeft32(b+g(c, d, a)+x[i+12]+0x5a827999, 13)
+		}
+
+		h := func(x, y, z uint32) uint32 { return x ^ y ^ z }
+		for _, i := range []int{0, 2, 1, 3} {
+			a = bits.RotateLeft32(a+h(b, c, d)+x[i]+0x6ed9eba1, 3)
+			d = bits.RotateLeft32(d+h(a, b, c)+x[i+8]+0x6ed9eba1, 9)
+			c = bits.RotateLeft32(c+h(d, a, b)+x[i+4]+0x6ed9eba1, 11)
+			b = bits.RotateLeft32(b+h(c, d, a)+x[i+12]+0x6ed9eba1, 15)
+		}
+
+		a, b, c, d = a+aa, b+bb, c+cc, d+dd
+	}
+
+	digest := make([]byte, 0, 16)
+	for _, v := range []uint32{a, b, c, d} {
+		digest = binary.LittleEndian.AppendUint32(digest, v)
+	}
+	return digest
+}
@@ -0,0 +1,232 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package proxyauth
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+const (
+	ntlmNegotiateUnicode         = 0x00000001
+	ntlmRequestTarget            = 0x00000004
+	ntlmNegotiateNTLM            = 0x00000200
+	ntlmNegotiateAlwaysSign      = 0x00008000
+	ntlmNegotiateExtendedSession = 0x00080000
+	ntlmNegotiateTargetInfo      = 0x00800000
+	ntlmNegotiate128             = 0x20000000
+	ntlmNegotiate56              = 0x80000000
+
+	ntlmNegotiateFlags = ntlmNegotiateUnicode | ntlmRequestTarget | ntlmNegotiateNTLM | ntlmNegotiateAlwaysSign |
+		ntlmNegotiateExtendedSession | ntlmNegotiateTargetInfo | ntlmNegotiate128 | ntlmNegotiate56
+
+	ntlmAvTimestamp = 7
+
+	// windowsEpochOffset is the number of seconds between 1601-01-01 and 1970-01-01
+	windowsEpochOffset = 11644473600
+)
+
+var ntlmSignature = []byte("NTLMSSP\x00")
+
+// Assign functions to variables to allow unittest to override
+var ntlmTimeNow = time.Now
+var ntlmClientChallenge = func() ([]byte, error) {
+	challenge := make([]byte, 8)
+	_, err := rand.Read(challenge)
+	return challenge, err
+}
+
+// ntlmAuthenticator runs the client side of an NTLMv2 handshake with explicit credentials
+type ntlmAuthenticator struct {
+	scheme   string
+	domain   string
+	user     string
+	password string
+	step     int
+}
+
+// newNTLMAuthenticator splits DOMAIN\user names, user@domain names are passed to the proxy as is
+func newNTLMAuthenticator(scheme string, username string, password string) *ntlmAuthenticator {
+	domain, user := "", username
+	if i := strings.Index(username, `\`); i >= 0 {
+		domain, user = username[:i], username[i+1:]
+	}
+	return &ntlmAuthenticator{scheme: scheme, domain: domain, user: user, password: password}
+}
+
+// Scheme returns the scheme used in the Proxy-Authorization header
+func (a *ntlmAuthenticator) Scheme() string {
+	return a.scheme
+}
+
+// Next returns the negotiate message first, then the authenticate message answering the proxy challenge
+func (a *ntlmAuthenticator) Next(challenge []byte) ([]byte, error) {
+	a.step++
+	switch a.step {
+	case 1:
+		return ntlmNegotiateMessage(), nil
+	case 2:
+		clientChallenge, err := ntlmClientChallenge()
+		if err != nil {
+			return nil, err
+		}
+		return ntlmAuthenticateMessage(challenge, a.domain, a.user, a.password, clientChallenge, ntlmTimeNow())
+	}
+	return nil, errors.New("proxy rejected the NTLM credentials")
+}
+
+// Close releases the resources of the handshake
+func (a *ntlmAuthenticator) Close() {}
+
+func ntlmNegotiateMessage() []byte {
+	message := make([]byte, 32)
+	copy(message, ntlmSignature)
+	binary.LittleEndian.PutUint32(message[8:], 1)
+	binary.LittleEndian.PutUint32(message[12:], ntlmNegotiateFlags)
+	// empty domain and workstation fields
+	binary.LittleEndian.PutUint32(message[20:], 32)
+	binary.LittleEndian.PutUint32(message[28:], 32)
+	return message
+}
+
+type ntlmChallenge struct {
+	flags           uint32
+	serverChallenge []byte
+	targetInfo      []byte
+}
+
+func parseNTLMChallenge(message []byte) (*ntlmChallenge, error) {
+	if len(message) < 32 || !bytes.Equal(message[:8], ntlmSignature) || binary.LittleEndian.Uint32(message[8:]) != 2 {
+		return nil, errors.New("invalid NTLM challenge message")
+	}
+	challenge := &ntlmChallenge{
+		flags:           binary.LittleEndian.Uint32(message[20:]),
+		serverChallenge: message[24:32],
+	}
+	if len(message) >= 48 {
+		length := int(binary.LittleEndian.Uint16(message[40:]))
+		offset := int(binary.LittleEndian.Uint32(message[44:]))
+		if offset+length > len(message) {
+			return nil, errors.New("invalid NTLM challenge target information")
+		}
+		challenge.targetInfo = message[offset : offset+length]
+	}
+	return challenge, nil
+}
+
+// ntlmTimestamp returns the MsvAvTimestamp of the target information, if the server sent one
+func ntlmTimestamp(targetInfo []byte) ([]byte, bool) {
+	for i := 0; i+4 <= len(targetInfo); {
+		id := binary.LittleEndian.Uint16(targetInfo[i:])
+		length := int(binary.LittleEndian.Uint16(targetInfo[i+2:]))
+		if i+4+length > len(targetInfo) {
+			break
+		}
+		if id == ntlmAvTimestamp && length == 8 {
+			return targetInfo[i+4 : i+12], true
+		}
+		if id == 0 {
+			break
+		}
+		i += 4 + length
+	}
+	return nil, false
+}
+
+func utf16le(s string) []byte {
+	encoded := utf16.Encode([]rune(s))
+	result := make([]byte, 2*len(encoded))
+	for i, c := range encoded {
+		binary.LittleEndian.PutUint16(result[2*i:], c)
+	}
+	return result
+}
+
+func hmacMD5(key []byte, data ...[]byte) []byte {
+	mac := hmac.New(md5.New, key)
+	for _, d := range data {
+		mac.Write(d)
+	}
+	return mac.Sum(nil)
+}
+
+// ntowfv2 derives the NTLMv2 response key from the credentials
+func ntowfv2(domain, user, password string) []byte {
+	return hmacMD5(md4(utf16le(password)), utf16le(strings.ToUpper(user)+domain))
+}
+
+// ntlmv2Responses computes the LMv2 and NTLMv2 challenge responses
+func ntlmv2Responses(responseKey, serverChallenge, clientChallenge, timestamp, targetInfo []byte, serverTimestamp bool) (lmResponse []byte, ntResponse []byte) {
+	var temp bytes.Buffer
+	temp.Write([]byte{1, 1, 0, 0, 0, 0, 0, 0})
+	temp.Write(timestamp)
+	temp.Write(clientChallenge)
+	temp.Write([]byte{0, 0, 0, 0})
+	temp.Write(targetInfo)
+	temp.Write([]byte{0, 0, 0, 0})
+
+	ntProof := hmacMD5(responseKey, serverChallenge, temp.Bytes())
+	ntResponse = append(ntProof, temp.Bytes()...)
+
+	// the LMv2 response must be zeroed when the server provides a timestamp
+	if serverTimestamp {
+		lmResponse = make([]byte, 24)
+	} else {
+		lmResponse = append(hmacMD5(responseKey, serverChallenge, clientChallenge), clientChallenge...)
+	}
+	return lmResponse, ntResponse
+}
+
+func ntlmAuthenticateMessage(challengeMessage []byte, domain, user, password string, clientChallenge []byte, now time.Time) ([]byte, error) {
+	challenge, err := parseNTLMChallenge(challengeMessage)
+	if err != nil {
+		return nil, err
+	}
+	if challenge.flags&ntlmNegotiateUnicode == 0 {
+		return nil, fmt.Errorf("proxy does not support unicode NTLM messages")
+	}
+
+	timestamp, serverTimestamp := ntlmTimestamp(challenge.targetInfo)
+	if !serverTimestamp {
+		timestamp = make([]byte, 8)
+		binary.LittleEndian.PutUint64(timestamp, uint64((now.Unix()+windowsEpochOffset)*10000000+int64(now.Nanosecond()/100)))
+	}
+	lmResponse, ntResponse := ntlmv2Responses(ntowfv2(domain, user, password), challenge.serverChallenge, clientChallenge, timestamp, challenge.targetInfo, serverTimestamp)
+
+	fields := [][]byte{lmResponse, ntResponse, utf16le(domain), utf16le(user), nil, nil}
+	const headerLength = 64
+	message := make([]byte, headerLength)
+	copy(message, ntlmSignature)
+	binary.LittleEndian.PutUint32(message[8:], 3)
+	offset := headerLength
+	for i, field := range fields {
+		binary.LittleEndian.PutUint16(message[12+8*i:], uint16(len(field)))
+		binary.LittleEndian.PutUint16(message[14+8*i:], uint16(len(field)))
+		binary.LittleEndian.PutUint32(message[16+8*i:], uint32(offset))
+		offset += len(field)
+	}
+	binary.LittleEndian.PutUint32(message[60:], challenge.flags&ntlmNegotiateFlags)
+	for _, field := range fields {
+		message = append(message, field...)
+	}
+	return message, nil
+}
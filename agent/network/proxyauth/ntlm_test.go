@@ -0,0 +1,156 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package proxyauth
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"net/url"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func decodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	assert.NoError(t, err)
+	return b
+}
+
+// targetInfo of the MS-NLMP NTLMv2 example, NetBIOS domain "Domain" and computer "Server"
+func exampleTargetInfo() []byte {
+	var info []byte
+	for _, pair := range []struct {
+		id    uint16
+		value string
+	}{{2, "Domain"}, {1, "Server"}} {
+		encoded := utf16le(pair.value)
+		info = binary.LittleEndian.AppendUint16(info, pair.id)
+		info = binary.LittleEndian.AppendUint16(info, uint16(len(encoded)))
+		info = append(info, encoded...)
+	}
+	return append(info, 0, 0, 0, 0)
+}
+
+func TestMD4(t *testing.T) {
+	// test suite of RFC 1320 appendix A.5 and NTOWFv1 of the MS-NLMP examples (section 4.2.2.1.2)
+	testCases := []struct {
+		data     []byte
+		expected string
+	}{
+		{[]byte(""), "31d6cfe0d16ae931b73c59d7e0c089c0"},
+		{[]byte("a"), "bde52cb31de33e46245e05fbdbd6fb24"},
+		{[]byte("abc"), "a448017aaf21d8525fc10ae87aa6729d"},
+		{[]byte("message digest"), "d9130a8164549fe818874806e1c7014b"},
+		{[]byte("abcdefghijklmnopqrstuvwxyz"), "d79e1c308aa5bbcdeea8ed63df412da9"},
+		{[]byte("ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"), "043f8582f241db351ce627e153e7f0e4"},
+		{[]byte("12345678901234567890123456789012345678901234567890123456789012345678901234567890"), "e33b4ddc9c38f2199c3e7b164fcc0536"},
+		{utf16le("Password"), "a4f49c406510bdcab6824ee7c30fd852"},
+	}
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, hex.EncodeToString(md4(testCase.data)), string(testCase.data))
+	}
+}
+
+func TestNTLMv2Responses(t *testing.T) {
+	responseKey := ntowfv2("Domain", "User", "Password")
+	assert.Equal(t, "0c868a403bfd7a93a3001ef22ef02e3f", hex.EncodeToString(responseKey))
+
+	serverChallenge := decodeHex(t, "0123456789abcdef")
+	clientChallenge := decodeHex(t, "aaaaaaaaaaaaaaaa")
+	lmResponse, ntResponse := ntlmv2Responses(responseKey, serverChallenge, clientChallenge, make([]byte, 8), exampleTargetInfo(), false)
+
+	assert.Equal(t, "86c35097ac9cec102554764a57cccc19aaaaaaaaaaaaaaaa", hex.EncodeToString(lmResponse))
+	assert.Equal(t, "68cd0ab851e51c96aabc927bebef6a1c", hex.EncodeToString(ntResponse[:16]))
+	// temp of MS-NLMP section 4.2.4.1.3 follows the NTProofStr
+	assert.Equal(t, "0101000000000000"+"0000000000000000"+"aaaaaaaaaaaaaaaa"+"00000000"+hex.EncodeToString(exampleTargetInfo())+"00000000",
+		hex.EncodeToString(ntResponse[16:]))
+
+	lmResponse, _ = ntlmv2Responses(responseKey, serverChallenge, clientChallenge, make([]byte, 8), exampleTargetInfo(), true)
+	assert.Equal(t, make([]byte, 24), lmResponse)
+}
+
+func challengeMessage(targetInfo []byte) []byte {
+	message := make([]byte, 48)
+	copy(message, ntlmSignature)
+	binary.LittleEndian.PutUint32(message[8:], 2)
+	binary.LittleEndian.PutUint32(message[20:], ntlmNegotiateFlags)
+	copy(message[24:], []byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef})
+	binary.LittleEndian.PutUint16(message[40:], uint16(len(targetInfo)))
+	binary.LittleEndian.PutUint16(message[42:], uint16(len(targetInfo)))
+	binary.LittleEndian.PutUint32(message[44:], 48)
+	return append(message, targetInfo...)
+}
+
+// securityBuffer returns the payload referenced by the security buffer at offset
+func securityBuffer(message []byte, offset int) []byte {
+	length := int(binary.LittleEndian.Uint16(message[offset:]))
+	start := int(binary.LittleEndian.Uint32(message[offset+4:]))
+	return message[start : start+length]
+}
+
+func TestNTLMHandshake(t *testing.T) {
+	origTimeNow, origClientChallenge := ntlmTimeNow, ntlmClientChallenge
+	ntlmTimeNow = func() time.Time { return time.Unix(-windowsEpochOffset, 0) }
+	ntlmClientChallenge = func() ([]byte, error) { return decodeHex(t, "aaaaaaaaaaaaaaaa"), nil }
+	t.Cleanup(func() { ntlmTimeNow, ntlmClientChallenge = origTimeNow, origClientChallenge })
+
+	authenticator, err := New("ntlm", "proxy.example.com", url.UserPassword(`Domain\User`, "Password"))
+	assert.NoError(t, err)
+	defer authenticator.Close()
+	assert.Equal(t, SchemeNTLM, authenticator.Scheme())
+
+	negotiate, err := authenticator.Next(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, ntlmSignature, negotiate[:8])
+	assert.Equal(t, uint32(1), binary.LittleEndian.Uint32(negotiate[8:]))
+
+	authenticate, err := authenticator.Next(challengeMessage(exampleTargetInfo()))
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(3), binary.LittleEndian.Uint32(authenticate[8:]))
+	assert.Equal(t, "86c35097ac9cec102554764a57cccc19aaaaaaaaaaaaaaaa", hex.EncodeToString(securityBuffer(authenticate, 12)))
+	assert.Equal(t, "68cd0ab851e51c96aabc927bebef6a1c", hex.EncodeToString(securityBuffer(authenticate, 20)[:16]))
+	assert.Equal(t, utf16le("Domain"), securityBuffer(authenticate, 28))
+	assert.Equal(t, utf16le("User"), securityBuffer(authenticate, 36))
+
+	_, err = authenticator.Next(challengeMessage(exampleTargetInfo()))
+	assert.Error(t, err)
+}
+
+func TestNTLMInvalidChallenge(t *testing.T) {
+	authenticator := newNTLMAuthenticator(SchemeNegotiate, "user@example.com", "secret")
+	assert.Equal(t, "", authenticator.domain)
+	assert.Equal(t, "user@example.com", authenticator.user)
+
+	_, err := authenticator.Next(nil)
+	assert.NoError(t, err)
+	_, err = authenticator.Next([]byte("NTLMSSP\x00 not a challenge"))
+	assert.Error(t, err)
+}
+
+func TestNew(t *testing.T) {
+	_, err := New("Digest", "proxy.example.com", url.UserPassword("user", "secret"))
+	assert.Error(t, err)
+
+	authenticator, err := New("negotiate", "proxy.example.com", url.UserPassword(`CORP\user`, "secret"))
+	assert.NoError(t, err)
+	assert.Equal(t, SchemeNegotiate, authenticator.Scheme())
+
+	if runtime.GOOS != "windows" {
+		_, err = New(SchemeNegotiate, "proxy.example.com", nil)
+		assert.Error(t, err)
+	}
+}
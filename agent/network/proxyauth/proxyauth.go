@@ -0,0 +1,62 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package proxyauth implements the connection based NTLM and Negotiate proxy authentication schemes.
+package proxyauth
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	// SchemeNTLM is the NTLM proxy authentication scheme
+	SchemeNTLM = "NTLM"
+	// SchemeNegotiate is the SPNEGO proxy authentication scheme, Kerberos with NTLM fallback
+	SchemeNegotiate = "Negotiate"
+)
+
+// Authenticator produces the tokens of a proxy authentication handshake.
+// The handshake authenticates a single connection, a new Authenticator is needed for every connection.
+type Authenticator interface {
+	// Scheme returns the scheme used in the Proxy-Authorization header
+	Scheme() string
+	// Next returns the token answering the proxy challenge, the challenge is nil on the first call
+	Next(challenge []byte) ([]byte, error)
+	// Close releases the resources of the handshake
+	Close()
+}
+
+// Assign functions to variables to allow unittest to override
+var newSSPIAuthenticator = newPlatformAuthenticator
+
+// New returns an Authenticator for the given scheme.
+// Explicit credentials, like DOMAIN\user from the proxy URL, are used with the built in NTLM client.
+// Without credentials the agent authenticates as its own account, which is only supported on Windows.
+func New(scheme string, proxyHost string, user *url.Userinfo) (Authenticator, error) {
+	switch {
+	case strings.EqualFold(scheme, SchemeNTLM):
+		scheme = SchemeNTLM
+	case strings.EqualFold(scheme, SchemeNegotiate):
+		scheme = SchemeNegotiate
+	default:
+		return nil, fmt.Errorf("unsupported proxy authentication scheme %s", scheme)
+	}
+	if user != nil && user.Username() != "" {
+		password, _ := user.Password()
+		// Negotiate accepts raw NTLM tokens, which is what SPNEGO falls back to without Kerberos
+		return newNTLMAuthenticator(scheme, user.Username(), password), nil
+	}
+	return newSSPIAuthenticator(scheme, proxyHost)
+}
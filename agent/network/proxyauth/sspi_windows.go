@@ -0,0 +1,167 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package proxyauth
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	secpkgCredOutbound        = 2
+	securityNativeDrep        = 0x10
+	secbufferToken            = 2
+	secbufferVersion          = 0
+	iscReqConnection          = 0x00000800
+	iscReqAllocateMemory      = 0x00000100
+	secEOK                    = 0
+	secIContinueNeeded        = 0x00090312
+	secICompleteNeeded        = 0x00090313
+	secICompleteAndContinue   = 0x00090314
+	sspiMaxHandshakeRoundTrip = 3
+)
+
+var (
+	secur32                        = windows.NewLazySystemDLL("secur32.dll")
+	procAcquireCredentialsHandleW  = secur32.NewProc("AcquireCredentialsHandleW")
+	procInitializeSecurityContextW = secur32.NewProc("InitializeSecurityContextW")
+	procCompleteAuthToken          = secur32.NewProc("CompleteAuthToken")
+	procDeleteSecurityContext      = secur32.NewProc("DeleteSecurityContext")
+	procFreeCredentialsHandle      = secur32.NewProc("FreeCredentialsHandle")
+	procFreeContextBuffer          = secur32.NewProc("FreeContextBuffer")
+)
+
+type secHandle struct {
+	lower uintptr
+	upper uintptr
+}
+
+type secBuffer struct {
+	size       uint32
+	bufferType uint32
+	buffer     *byte
+}
+
+type secBufferDesc struct {
+	version uint32
+	count   uint32
+	buffers *secBuffer
+}
+
+// sspiAuthenticator authenticates as the account the agent runs as through the Windows security packages
+type sspiAuthenticator struct {
+	scheme     string
+	target     *uint16
+	credential secHandle
+	context    secHandle
+	hasContext bool
+	step       int
+}
+
+func newPlatformAuthenticator(scheme string, proxyHost string) (Authenticator, error) {
+	packageName, err := syscall.UTF16PtrFromString(scheme)
+	if err != nil {
+		return nil, err
+	}
+	target, err := syscall.UTF16PtrFromString("HTTP/" + proxyHost)
+	if err != nil {
+		return nil, err
+	}
+	a := &sspiAuthenticator{scheme: scheme, target: target}
+	var expiry int64
+	ret, _, _ := procAcquireCredentialsHandleW.Call(
+		0,
+		uintptr(unsafe.Pointer(packageName)),
+		secpkgCredOutbound,
+		0, 0, 0, 0,
+		uintptr(unsafe.Pointer(&a.credential)),
+		uintptr(unsafe.Pointer(&expiry)))
+	if ret != secEOK {
+		return nil, fmt.Errorf("failed to acquire %s credentials: 0x%x", scheme, uint32(ret))
+	}
+	return a, nil
+}
+
+// Scheme returns the scheme used in the Proxy-Authorization header
+func (a *sspiAuthenticator) Scheme() string {
+	return a.scheme
+}
+
+// Next passes the proxy challenge to the security package and returns the token it produces
+func (a *sspiAuthenticator) Next(challenge []byte) ([]byte, error) {
+	a.step++
+	if a.step > sspiMaxHandshakeRoundTrip {
+		return nil, fmt.Errorf("proxy rejected the %s credentials", a.scheme)
+	}
+
+	var input *secBufferDesc
+	if len(challenge) > 0 {
+		inputBuffer := secBuffer{size: uint32(len(challenge)), bufferType: secbufferToken, buffer: &challenge[0]}
+		input = &secBufferDesc{version: secbufferVersion, count: 1, buffers: &inputBuffer}
+	}
+	outputBuffer := secBuffer{bufferType: secbufferToken}
+	output := secBufferDesc{version: secbufferVersion, count: 1, buffers: &outputBuffer}
+
+	var contextIn *secHandle
+	if a.hasContext {
+		contextIn = &a.context
+	}
+	var attributes uint32
+	var expiry int64
+	ret, _, _ := procInitializeSecurityContextW.Call(
+		uintptr(unsafe.Pointer(&a.credential)),
+		uintptr(unsafe.Pointer(contextIn)),
+		uintptr(unsafe.Pointer(a.target)),
+		iscReqConnection|iscReqAllocateMemory,
+		0,
+		securityNativeDrep,
+		uintptr(unsafe.Pointer(input)),
+		0,
+		uintptr(unsafe.Pointer(&a.context)),
+		uintptr(unsafe.Pointer(&output)),
+		uintptr(unsafe.Pointer(&attributes)),
+		uintptr(unsafe.Pointer(&expiry)))
+	a.hasContext = true
+	if outputBuffer.buffer != nil {
+		defer procFreeContextBuffer.Call(uintptr(unsafe.Pointer(outputBuffer.buffer)))
+	}
+
+	switch ret {
+	case secICompleteNeeded, secICompleteAndContinue:
+		if complete, _, _ := procCompleteAuthToken.Call(uintptr(unsafe.Pointer(&a.context)), uintptr(unsafe.Pointer(&output))); complete != secEOK {
+			return nil, fmt.Errorf("failed to complete %s token: 0x%x", a.scheme, uint32(complete))
+		}
+	case secEOK, secIContinueNeeded:
+	default:
+		return nil, fmt.Errorf("failed to initialize %s security context: 0x%x", a.scheme, uint32(ret))
+	}
+
+	token := make([]byte, outputBuffer.size)
+	if outputBuffer.size > 0 {
+		copy(token, unsafe.Slice(outputBuffer.buffer, outputBuffer.size))
+	}
+	return token, nil
+}
+
+// Close releases the security context and credentials
+func (a *sspiAuthenticator) Close() {
+	if a.hasContext {
+		procDeleteSecurityContext.Call(uintptr(unsafe.Pointer(&a.context)))
+		a.hasContext = false
+	}
+	procFreeCredentialsHandle.Call(uintptr(unsafe.Pointer(&a.credential)))
+}
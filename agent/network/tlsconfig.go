@@ -19,6 +19,7 @@ import (
 	"sync"
 
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/fips"
 	"github.com/aws/amazon-ssm-agent/agent/log"
 )
 
@@ -80,5 +81,13 @@ func GetDefaultTLSConfig(log log.T, appConfig appconfig.SsmagentConfig) *tls.Con
 		tlsConfigCopy.RootCAs = certPool
 	}
 
+	if hasClientCertificate(appConfig) {
+		tlsConfigCopy.GetClientCertificate = getClientCertificateFunc(log, appConfig.ClientCertificate)
+	}
+
+	if fips.Enabled(appConfig) {
+		fips.ApplyTLSConfig(tlsConfigCopy)
+	}
+
 	return tlsConfigCopy
 }
@@ -1,14 +1,61 @@
 package network
 
 import (
+	"fmt"
 	"net/http"
+	"sync"
 
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
 	"github.com/aws/amazon-ssm-agent/agent/log"
 )
 
+var (
+	sharedTransports     = map[string]*http.Transport{}
+	sharedTransportMutex = sync.Mutex{}
+)
+
 func GetDefaultTransport(log log.T, appConfig appconfig.SsmagentConfig) *http.Transport {
 	result := http.DefaultTransport.(*http.Transport).Clone()
 	result.TLSClientConfig = GetDefaultTLSConfig(log, appConfig)
+	result.Proxy = GetProxyFunc(log, appConfig)
+	if dial := GetProxyDialer(log, appConfig); dial != nil {
+		result.DialContext = dial
+	}
 	return result
 }
+
+// GetSharedTransport returns the transport shared by the clients using the same network configuration,
+// sharing the certificate pool and the idle connections keeps the memory footprint of the agent low.
+// The returned transport must not be modified, use GetDefaultTransport for a transport with custom settings.
+func GetSharedTransport(log log.T, appConfig appconfig.SsmagentConfig) *http.Transport {
+	return getSharedTransport(transportKey(appConfig), func() *http.Transport {
+		return GetDefaultTransport(log, appConfig)
+	})
+}
+
+// getSharedDirectTransport returns the shared transport connecting without a proxy,
+// the proxy auto-config script is downloaded with it as the proxy of the default transport depends on the script
+func getSharedDirectTransport(log log.T, appConfig appconfig.SsmagentConfig) *http.Transport {
+	return getSharedTransport("direct|"+transportKey(appConfig), func() *http.Transport {
+		result := http.DefaultTransport.(*http.Transport).Clone()
+		result.TLSClientConfig = GetDefaultTLSConfig(log, appConfig)
+		result.Proxy = nil
+		return result
+	})
+}
+
+func getSharedTransport(key string, newTransport func() *http.Transport) *http.Transport {
+	sharedTransportMutex.Lock()
+	defer sharedTransportMutex.Unlock()
+	if transport, ok := sharedTransports[key]; ok {
+		return transport
+	}
+	transport := newTransport()
+	sharedTransports[key] = transport
+	return transport
+}
+
+// transportKey identifies the configuration the transport depends on
+func transportKey(appConfig appconfig.SsmagentConfig) string {
+	return fmt.Sprintf("%+v|%+v|%v|%v", appConfig.Proxy, appConfig.ClientCertificate, appConfig.Agent.ContainerMode, appConfig.Fips.Enabled)
+}
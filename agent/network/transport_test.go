@@ -0,0 +1,34 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package network
+
+import (
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	logmocks "github.com/aws/amazon-ssm-agent/agent/mocks/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSharedTransport(t *testing.T) {
+	log := logmocks.NewMockLog()
+	config := appconfig.DefaultConfig()
+
+	shared := GetSharedTransport(log, config)
+	assert.Same(t, shared, GetSharedTransport(log, config))
+	assert.NotSame(t, shared, GetDefaultTransport(log, config))
+
+	config.Proxy.AuthScheme = appconfig.ProxyAuthSchemeNTLM
+	assert.NotSame(t, shared, GetSharedTransport(log, config))
+}
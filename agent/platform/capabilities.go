@@ -0,0 +1,63 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package platform contains platform specific utilities.
+package platform
+
+import (
+	"sync"
+
+	"github.com/aws/amazon-ssm-agent/agent/log"
+)
+
+// Installation types reported by the InstallationType value of the Windows NT CurrentVersion registry key
+const (
+	InstallationTypeNanoServer = "Nano Server"
+	InstallationTypeServerCore = "Server Core"
+	InstallationTypeServer     = "Server"
+	InstallationTypeClient     = "Client"
+)
+
+// WindowsCapabilities describes the installation option of Windows and the tools available to plugins,
+// all fields are false on other platforms
+type WindowsCapabilities struct {
+	InstallationType    string
+	NanoServer          bool
+	ServerCore          bool
+	DesktopExperience   bool
+	PowerShellAvailable bool
+	WmicAvailable       bool
+}
+
+var (
+	capabilitiesLock   sync.Mutex
+	cachedCapabilities *WindowsCapabilities
+)
+
+// GetWindowsCapabilities returns the capabilities of the current Windows installation,
+// the result is cached after the first successful detection
+func GetWindowsCapabilities(log log.T) (WindowsCapabilities, error) {
+	capabilitiesLock.Lock()
+	defer capabilitiesLock.Unlock()
+
+	if cachedCapabilities != nil {
+		return *cachedCapabilities, nil
+	}
+	capabilities, err := getWindowsCapabilities(log)
+	if err != nil {
+		return capabilities, err
+	}
+	log.Debugf("Detected Windows capabilities: %+v", capabilities)
+	cachedCapabilities = &capabilities
+	return capabilities, nil
+}
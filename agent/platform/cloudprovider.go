@@ -0,0 +1,177 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package platform
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/log"
+)
+
+const (
+	// CloudProviderAzure is reported for virtual machines in Microsoft Azure
+	CloudProviderAzure = "Azure"
+	// CloudProviderGCP is reported for virtual machines in Google Cloud
+	CloudProviderGCP = "GCP"
+	// CloudProviderOCI is reported for virtual machines in Oracle Cloud Infrastructure
+	CloudProviderOCI = "OCI"
+
+	cloudMetadataTimeout         = 2 * time.Second
+	cloudMetadataMaxResponseSize = 64 * 1024
+)
+
+// CloudProviderInfo describes the cloud the instance runs in, as self-reported by the metadata service of the provider
+type CloudProviderInfo struct {
+	Provider         string
+	InstanceId       string
+	InstanceType     string
+	Region           string
+	AvailabilityZone string
+}
+
+type cloudProviderDetector func(log log.T) (CloudProviderInfo, error)
+
+var (
+	azureMetadataURL = "http://169.254.169.254/metadata/instance/compute?api-version=2021-02-01"
+	gcpMetadataURL   = "http://169.254.169.254/computeMetadata/v1/instance/?recursive=true"
+	ociMetadataURL   = "http://169.254.169.254/opc/v2/instance/"
+
+	// metadata services are link-local, requests never go through the proxy
+	cloudMetadataClient = &http.Client{
+		Timeout:   cloudMetadataTimeout,
+		Transport: &http.Transport{Proxy: nil},
+	}
+
+	cloudProviderDetectors = []cloudProviderDetector{detectAzure, detectGCP, detectOCI}
+)
+
+// DetectCloudProvider queries the metadata services of the known cloud providers other than AWS,
+// found is false when none of them answered
+func DetectCloudProvider(log log.T) (info CloudProviderInfo, found bool) {
+	for _, detect := range cloudProviderDetectors {
+		var err error
+		if info, err = detect(log); err == nil {
+			log.Debugf("detected cloud provider %v", info.Provider)
+			return info, true
+		}
+		log.Tracef("cloud provider not detected: %v", err)
+	}
+	return CloudProviderInfo{}, false
+}
+
+// detectAzure reads the compute metadata of the Azure instance metadata service
+func detectAzure(log log.T) (info CloudProviderInfo, err error) {
+	var compute struct {
+		VMId     string `json:"vmId"`
+		VMSize   string `json:"vmSize"`
+		Location string `json:"location"`
+		Zone     string `json:"zone"`
+	}
+	if _, err = getCloudMetadata(azureMetadataURL, map[string]string{"Metadata": "true"}, &compute); err != nil {
+		return info, fmt.Errorf("azure: %v", err)
+	}
+	if compute.VMId == "" {
+		return info, fmt.Errorf("azure: metadata without vm id")
+	}
+	return CloudProviderInfo{
+		Provider:         CloudProviderAzure,
+		InstanceId:       compute.VMId,
+		InstanceType:     compute.VMSize,
+		Region:           compute.Location,
+		AvailabilityZone: compute.Zone,
+	}, nil
+}
+
+// detectGCP reads the instance metadata of the Google Compute Engine metadata server
+func detectGCP(log log.T) (info CloudProviderInfo, err error) {
+	var instance struct {
+		Id          json.Number `json:"id"`
+		MachineType string      `json:"machineType"`
+		Zone        string      `json:"zone"`
+	}
+	header, err := getCloudMetadata(gcpMetadataURL, map[string]string{"Metadata-Flavor": "Google"}, &instance)
+	if err != nil {
+		return info, fmt.Errorf("gcp: %v", err)
+	}
+	if header.Get("Metadata-Flavor") != "Google" || instance.Id == "" {
+		return info, fmt.Errorf("gcp: response is not from the metadata server")
+	}
+	// machine type and zone are resource paths, e.g. projects/123/zones/us-central1-a
+	zone := lastPathSegment(instance.Zone)
+	info = CloudProviderInfo{
+		Provider:         CloudProviderGCP,
+		InstanceId:       instance.Id.String(),
+		InstanceType:     lastPathSegment(instance.MachineType),
+		AvailabilityZone: zone,
+	}
+	if i := strings.LastIndex(zone, "-"); i > 0 {
+		info.Region = zone[:i]
+	}
+	return info, nil
+}
+
+// detectOCI reads the instance metadata of the Oracle Cloud Infrastructure metadata service
+func detectOCI(log log.T) (info CloudProviderInfo, err error) {
+	var instance struct {
+		Id                  string `json:"id"`
+		Shape               string `json:"shape"`
+		CanonicalRegionName string `json:"canonicalRegionName"`
+		AvailabilityDomain  string `json:"availabilityDomain"`
+	}
+	if _, err = getCloudMetadata(ociMetadataURL, map[string]string{"Authorization": "Bearer Oracle"}, &instance); err != nil {
+		return info, fmt.Errorf("oci: %v", err)
+	}
+	if !strings.HasPrefix(instance.Id, "ocid1.instance.") {
+		return info, fmt.Errorf("oci: metadata without instance ocid")
+	}
+	return CloudProviderInfo{
+		Provider:         CloudProviderOCI,
+		InstanceId:       instance.Id,
+		InstanceType:     instance.Shape,
+		Region:           instance.CanonicalRegionName,
+		AvailabilityZone: instance.AvailabilityDomain,
+	}, nil
+}
+
+// getCloudMetadata sends a metadata request and decodes the JSON response into result
+func getCloudMetadata(url string, headers map[string]string, result interface{}) (http.Header, error) {
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range headers {
+		request.Header.Set(name, value)
+	}
+	response, err := cloudMetadataClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("metadata request returned status %v", response.StatusCode)
+	}
+	if err = json.NewDecoder(io.LimitReader(response.Body, cloudMetadataMaxResponseSize)).Decode(result); err != nil {
+		return nil, fmt.Errorf("failed to decode metadata: %v", err)
+	}
+	return response.Header, nil
+}
+
+func lastPathSegment(path string) string {
+	return path[strings.LastIndex(path, "/")+1:]
+}
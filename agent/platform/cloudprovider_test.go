@@ -0,0 +1,108 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package platform
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	logmocks "github.com/aws/amazon-ssm-agent/agent/mocks/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func setMetadataURL(t *testing.T, url *string, handler http.HandlerFunc) {
+	server := httptest.NewServer(handler)
+	original := *url
+	*url = server.URL
+	t.Cleanup(func() {
+		*url = original
+		server.Close()
+	})
+}
+
+func TestDetectAzure(t *testing.T) {
+	setMetadataURL(t, &azureMetadataURL, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata") != "true" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"vmId": "02aab8a4-74ef-476e-8182-f6d2ba4166a6", "vmSize": "Standard_D2s_v3", "location": "westeurope", "zone": "1"}`))
+	})
+
+	info, err := detectAzure(logmocks.NewMockLog())
+	assert.NoError(t, err)
+	assert.Equal(t, CloudProviderInfo{
+		Provider:         CloudProviderAzure,
+		InstanceId:       "02aab8a4-74ef-476e-8182-f6d2ba4166a6",
+		InstanceType:     "Standard_D2s_v3",
+		Region:           "westeurope",
+		AvailabilityZone: "1",
+	}, info)
+}
+
+func TestDetectGCP(t *testing.T) {
+	setMetadataURL(t, &gcpMetadataURL, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Metadata-Flavor", r.Header.Get("Metadata-Flavor"))
+		w.Write([]byte(`{"id": 4520031799277581759, "machineType": "projects/123/machineTypes/e2-medium", "zone": "projects/123/zones/us-central1-a"}`))
+	})
+
+	info, err := detectGCP(logmocks.NewMockLog())
+	assert.NoError(t, err)
+	assert.Equal(t, CloudProviderInfo{
+		Provider:         CloudProviderGCP,
+		InstanceId:       "4520031799277581759",
+		InstanceType:     "e2-medium",
+		Region:           "us-central1",
+		AvailabilityZone: "us-central1-a",
+	}, info)
+}
+
+func TestDetectGCPRejectsResponseWithoutMetadataFlavor(t *testing.T) {
+	setMetadataURL(t, &gcpMetadataURL, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": 1}`))
+	})
+
+	_, err := detectGCP(logmocks.NewMockLog())
+	assert.Error(t, err)
+}
+
+func TestDetectOCI(t *testing.T) {
+	setMetadataURL(t, &ociMetadataURL, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer Oracle" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"id": "ocid1.instance.oc1.phx.abc", "shape": "VM.Standard.E4.Flex", "canonicalRegionName": "us-phoenix-1", "availabilityDomain": "Uocm:PHX-AD-1"}`))
+	})
+
+	info, err := detectOCI(logmocks.NewMockLog())
+	assert.NoError(t, err)
+	assert.Equal(t, CloudProviderOCI, info.Provider)
+	assert.Equal(t, "ocid1.instance.oc1.phx.abc", info.InstanceId)
+	assert.Equal(t, "us-phoenix-1", info.Region)
+}
+
+func TestDetectCloudProviderNotFound(t *testing.T) {
+	notFound := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}
+	setMetadataURL(t, &azureMetadataURL, notFound)
+	setMetadataURL(t, &gcpMetadataURL, notFound)
+	setMetadataURL(t, &ociMetadataURL, notFound)
+
+	info, found := DetectCloudProvider(logmocks.NewMockLog())
+	assert.False(t, found)
+	assert.Equal(t, CloudProviderInfo{}, info)
+}
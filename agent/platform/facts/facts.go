@@ -0,0 +1,183 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package facts implements the core module publishing the platform facts to a file for other tooling
+package facts
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/platform"
+	"github.com/aws/amazon-ssm-agent/agent/version"
+)
+
+const (
+	// ModuleName is the name of the platform facts publisher core module
+	ModuleName = "PlatformFactsPublisher"
+
+	refreshInterval = 30 * time.Minute
+
+	// factsFileAccess lets tooling that doesn't run as root read the facts, none of them are secret
+	factsFileAccess = 0644
+)
+
+// Facts describes the platform the agent is running on
+type Facts struct {
+	PlatformName    string
+	PlatformVersion string
+	PlatformFamily  string
+	Architecture    string
+	AgentVersion    string
+	InstanceId      string
+	Region          string
+}
+
+var (
+	factsFilePath = func() string {
+		return appconfig.PlatformFactsFilePath
+	}
+	platformName    = platform.PlatformName
+	platformVersion = platform.PlatformVersion
+	platformType    = platform.PlatformType
+)
+
+// Publisher writes the platform facts at startup and rewrites them whenever they change
+type Publisher struct {
+	context context.T
+
+	mtx       sync.Mutex
+	stopChan  chan struct{}
+	doneChan  chan struct{}
+	published []byte
+}
+
+// NewPublisher returns a platform facts publisher core module
+func NewPublisher(context context.T) *Publisher {
+	return &Publisher{
+		context: context.With("[" + ModuleName + "]"),
+	}
+}
+
+// ModuleName returns the module name
+func (p *Publisher) ModuleName() string {
+	return ModuleName
+}
+
+// ModuleExecute publishes the facts and starts the refresh loop
+func (p *Publisher) ModuleExecute() (err error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.stopChan = make(chan struct{})
+	p.doneChan = make(chan struct{})
+	go p.refreshLoop(refreshInterval, p.stopChan, p.doneChan)
+	return nil
+}
+
+// ModuleStop stops the refresh loop
+func (p *Publisher) ModuleStop() (err error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if p.stopChan != nil {
+		close(p.stopChan)
+		<-p.doneChan
+		p.stopChan = nil
+	}
+	return nil
+}
+
+func (p *Publisher) refreshLoop(interval time.Duration, stopChan chan struct{}, doneChan chan struct{}) {
+	log := p.context.Log()
+	defer func() {
+		if msg := recover(); msg != nil {
+			log.Errorf("Platform facts publisher panic: %v", msg)
+			log.Errorf("Stacktrace:\n%s", debug.Stack())
+		}
+		close(doneChan)
+	}()
+
+	p.publish()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+			p.publish()
+		}
+	}
+}
+
+// collect gathers the facts, leaving out the ones that can't be determined
+func (p *Publisher) collect() Facts {
+	log := p.context.Log()
+	facts := Facts{
+		Architecture: runtime.GOARCH,
+		AgentVersion: version.Version,
+	}
+
+	var err error
+	if facts.PlatformName, err = platformName(log); err != nil {
+		log.Warnf("Failed to get platform name: %v", err)
+	}
+	if facts.PlatformVersion, err = platformVersion(log); err != nil {
+		log.Warnf("Failed to get platform version: %v", err)
+	}
+	if facts.PlatformFamily, err = platformType(log); err != nil {
+		log.Warnf("Failed to get platform family: %v", err)
+	}
+	if facts.InstanceId, err = p.context.Identity().InstanceID(); err != nil {
+		log.Warnf("Failed to get instance id: %v", err)
+	}
+	if facts.Region, err = p.context.Identity().Region(); err != nil {
+		log.Warnf("Failed to get region: %v", err)
+	}
+	return facts
+}
+
+// publish writes the facts file if the facts changed since they were last published
+func (p *Publisher) publish() {
+	log := p.context.Log()
+	content, err := json.MarshalIndent(p.collect(), "", "  ")
+	if err != nil {
+		log.Warnf("Failed to marshal platform facts: %v", err)
+		return
+	}
+	if bytes.Equal(content, p.published) {
+		return
+	}
+
+	path := factsFilePath()
+	tmpPath := path + ".tmp"
+	if err = os.WriteFile(tmpPath, content, factsFileAccess); err != nil {
+		log.Warnf("Failed to write platform facts to %v: %v", tmpPath, err)
+		return
+	}
+	if err = os.Rename(tmpPath, path); err != nil {
+		log.Warnf("Failed to publish platform facts to %v: %v", path, err)
+		_ = os.Remove(tmpPath)
+		return
+	}
+	p.published = content
+	log.Infof("Published platform facts to %v", path)
+}
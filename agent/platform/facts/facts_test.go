@@ -0,0 +1,118 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package facts
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	contextmocks "github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	"github.com/aws/amazon-ssm-agent/agent/version"
+	identitymocks "github.com/aws/amazon-ssm-agent/common/identity/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupTest(t *testing.T, name string) (path string) {
+	originalFactsFilePath, originalPlatformName, originalPlatformVersion, originalPlatformType := factsFilePath, platformName, platformVersion, platformType
+	t.Cleanup(func() {
+		factsFilePath, platformName, platformVersion, platformType = originalFactsFilePath, originalPlatformName, originalPlatformVersion, originalPlatformType
+	})
+
+	path = filepath.Join(t.TempDir(), "facts.json")
+	factsFilePath = func() string {
+		return path
+	}
+	platformName = func(log log.T) (string, error) {
+		return name, nil
+	}
+	platformVersion = func(log log.T) (string, error) {
+		return "2023", nil
+	}
+	platformType = func(log log.T) (string, error) {
+		return "linux", nil
+	}
+	return path
+}
+
+func readFacts(t *testing.T, path string) Facts {
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	var facts Facts
+	assert.NoError(t, json.Unmarshal(content, &facts))
+	return facts
+}
+
+func TestPublish(t *testing.T) {
+	path := setupTest(t, "Amazon Linux")
+	publisher := NewPublisher(contextmocks.NewMockDefault())
+
+	publisher.publish()
+
+	assert.Equal(t, Facts{
+		PlatformName:    "Amazon Linux",
+		PlatformVersion: "2023",
+		PlatformFamily:  "linux",
+		Architecture:    runtime.GOARCH,
+		AgentVersion:    version.Version,
+		InstanceId:      identitymocks.MockInstanceID,
+		Region:          identitymocks.MockRegion,
+	}, readFacts(t, path))
+}
+
+func TestPublishOnlyRewritesChangedFacts(t *testing.T) {
+	path := setupTest(t, "Amazon Linux")
+	publisher := NewPublisher(contextmocks.NewMockDefault())
+
+	publisher.publish()
+	assert.NoError(t, os.Remove(path))
+	publisher.publish()
+	_, err := os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
+	platformName = func(log log.T) (string, error) {
+		return "Ubuntu", nil
+	}
+	publisher.publish()
+	assert.Equal(t, "Ubuntu", readFacts(t, path).PlatformName)
+}
+
+func TestPublishSkipsUnavailableFacts(t *testing.T) {
+	path := setupTest(t, "Amazon Linux")
+	platformVersion = func(log log.T) (string, error) {
+		return "", errors.New("no os-release")
+	}
+	publisher := NewPublisher(contextmocks.NewMockDefault())
+
+	publisher.publish()
+
+	facts := readFacts(t, path)
+	assert.Empty(t, facts.PlatformVersion)
+	assert.Equal(t, "Amazon Linux", facts.PlatformName)
+}
+
+func TestModuleExecuteAndStop(t *testing.T) {
+	path := setupTest(t, "Amazon Linux")
+	publisher := NewPublisher(contextmocks.NewMockDefault())
+
+	assert.NoError(t, publisher.ModuleExecute())
+	assert.NoError(t, publisher.ModuleStop())
+
+	assert.Equal(t, "Amazon Linux", readFacts(t, path).PlatformName)
+	assert.Equal(t, ModuleName, publisher.ModuleName())
+}
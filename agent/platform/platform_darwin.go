@@ -151,3 +151,7 @@ func fullyQualifiedDomainName(log log.T) string {
 func isPlatformNanoServer(log log.T) (bool, error) {
 	return false, nil
 }
+
+func getWindowsCapabilities(log log.T) (WindowsCapabilities, error) {
+	return WindowsCapabilities{}, nil
+}
@@ -26,16 +26,19 @@ import (
 
 	"github.com/aws/amazon-ssm-agent/agent/fileutil"
 	"github.com/aws/amazon-ssm-agent/agent/log"
+	"golang.org/x/sys/unix"
 	"gopkg.in/ini.v1"
 )
 
 const (
 	osReleaseFile           = "/etc/os-release"
+	usrLibOsReleaseFile     = "/usr/lib/os-release"
+	lsbReleaseFile          = "/etc/lsb-release"
 	systemReleaseFile       = "/etc/system-release"
 	centosReleaseFile       = "/etc/centos-release"
 	redhatReleaseFile       = "/etc/redhat-release"
 	bottlerocketReleaseFile = "/etc/bottlerocket-release"
-	unameCommand            = "/usr/bin/uname"
+	unameSyscall            = "uname syscall"
 	lsbReleaseCommand       = "lsb_release"
 	fetchingDetailsMessage  = "fetching platform details from %v"
 	errorOccurredMessage    = "There was an error running %v, err: %v"
@@ -44,6 +47,8 @@ const (
 var (
 	readAllText = fileutil.ReadAllText
 	fileExists  = fileutil.Exists
+	lookPath    = exec.LookPath
+	uname       = unix.Uname
 )
 
 // this structure is similar to the /etc/os-release file
@@ -52,6 +57,12 @@ type osRelease struct {
 	VERSION_ID string
 }
 
+// this structure is similar to the /etc/lsb-release file read by lsb_release
+type lsbRelease struct {
+	DISTRIB_ID      string
+	DISTRIB_RELEASE string
+}
+
 func getPlatformName(log log.T) (value string, err error) {
 	value, _, err = getPlatformDetails(log)
 	return
@@ -128,14 +139,14 @@ func getPlatformDetails(log log.T) (name string, version string, err error) {
 
 		name = contents.NAME
 		version = contents.VERSION_ID
-	} else if fileExists(osReleaseFile) {
-
-		log.Debugf(fetchingDetailsMessage, osReleaseFile)
+	} else if osReleasePath, found := findOsReleaseFile(); found {
+		// /usr/lib/os-release is the fallback of the os-release specification for images without /etc/os-release
+		log.Debugf(fetchingDetailsMessage, osReleasePath)
 		contents := new(osRelease)
-		err = ini.MapTo(contents, osReleaseFile)
+		err = mapReleaseFile(contents, osReleasePath)
 		log.Debugf(commandOutputMessage, contents)
 		if err != nil {
-			log.Debugf(errorOccurredMessage, osReleaseFile, err)
+			log.Debugf(errorOccurredMessage, osReleasePath, err)
 			return
 		}
 
@@ -216,21 +227,20 @@ func getPlatformDetails(log log.T) (name string, version string, err error) {
 				version = strings.TrimSpace(versionData[0])
 			}
 		}
-	} else if runtime.GOOS == "freebsd" {
-		log.Debugf(fetchingDetailsMessage, unameCommand)
-
-		if contentsBytes, err = exec.Command(unameCommand, "-sr").Output(); err != nil {
-			log.Debugf(fetchingDetailsMessage, unameCommand, err)
+	} else if fileExists(lsbReleaseFile) {
+		// lsb_release reads the distribution from this file, reading it directly does not need the command
+		log.Debugf(fetchingDetailsMessage, lsbReleaseFile)
+		contents := new(lsbRelease)
+		err = mapReleaseFile(contents, lsbReleaseFile)
+		log.Debugf(commandOutputMessage, contents)
+		if err != nil {
+			log.Debugf(errorOccurredMessage, lsbReleaseFile, err)
 			return
 		}
-		log.Debugf(commandOutputMessage, contentsBytes)
 
-		data := strings.Split(string(contentsBytes), " ")
-		name = strings.TrimSpace(data[0])
-		if len(data) >= 2 {
-			version = strings.TrimSpace(data[1])
-		}
-	} else {
+		name = contents.DISTRIB_ID
+		version = contents.DISTRIB_RELEASE
+	} else if _, lookPathErr := lookPath(lsbReleaseCommand); runtime.GOOS != "freebsd" && lookPathErr == nil {
 		log.Debugf(fetchingDetailsMessage, lsbReleaseCommand)
 
 		// platform name
@@ -255,10 +265,41 @@ func getPlatformDetails(log log.T) (name string, version string, err error) {
 		version = strings.TrimLeft(version, "Release:")
 		version = strings.TrimSpace(version)
 		log.Debugf("platform version %v", version)
+	} else {
+		// FreeBSD and minimal images without release files or lsb_release, e.g. BusyBox based, report the kernel
+		log.Debugf(fetchingDetailsMessage, unameSyscall)
+
+		var utsname unix.Utsname
+		if err = uname(&utsname); err != nil {
+			log.Debugf(errorOccurredMessage, unameSyscall, err)
+			return
+		}
+		name = unix.ByteSliceToString(utsname.Sysname[:])
+		version = unix.ByteSliceToString(utsname.Release[:])
+		log.Debugf(commandOutputMessage, name+" "+version)
 	}
 	return
 }
 
+// findOsReleaseFile returns the os-release file of the system, /etc/os-release takes precedence over /usr/lib/os-release
+func findOsReleaseFile() (string, bool) {
+	for _, path := range []string{osReleaseFile, usrLibOsReleaseFile} {
+		if fileExists(path) {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// mapReleaseFile maps the KEY=value lines of a release file to the fields of the structure
+func mapReleaseFile(contents interface{}, path string) error {
+	text, err := readAllText(path)
+	if err != nil {
+		return err
+	}
+	return ini.MapTo(contents, []byte(text))
+}
+
 var hostNameCommand = filepath.Join("/bin", "hostname")
 
 // fullyQualifiedDomainName returns the Fully Qualified Domain Name of the instance, otherwise the hostname
@@ -271,7 +312,8 @@ func fullyQualifiedDomainName(log log.T) string {
 	}
 
 	var contentBytes []byte
-	if contentBytes, err = exec.Command(hostNameCommand, "--fqdn").Output(); err == nil {
+	// -f is the short form of --fqdn, BusyBox hostname only supports the short form
+	if contentBytes, err = exec.Command(hostNameCommand, "-f").Output(); err == nil {
 		fqdn = string(contentBytes)
 		//trim whitespaces - since by default above command appends '\n' at the end.
 		//e.g: 'ip-172-31-7-113.ec2.internal\n'
@@ -290,3 +332,7 @@ func fullyQualifiedDomainName(log log.T) string {
 func isPlatformNanoServer(log log.T) (bool, error) {
 	return false, nil
 }
+
+func getWindowsCapabilities(log log.T) (WindowsCapabilities, error) {
+	return WindowsCapabilities{}, nil
+}
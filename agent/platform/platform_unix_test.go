@@ -18,10 +18,12 @@
 package platform
 
 import (
+	"os/exec"
 	"testing"
 
 	logger "github.com/aws/amazon-ssm-agent/agent/mocks/log"
 	"github.com/stretchr/testify/assert"
+	"golang.org/x/sys/unix"
 )
 
 func TestVersion_PlatformWithBrackets(t *testing.T) {
@@ -57,3 +59,66 @@ func TestVersion_PlatformWithOutBrackets(t *testing.T) {
 	assert.Equal(t, "7", version)
 	assert.Nil(t, err)
 }
+
+// stubReleaseFiles fakes the release files of the system, lsb_release is not installed
+func stubReleaseFiles(t *testing.T, files map[string]string) {
+	fileExistsOrig, readAllTextOrig, lookPathOrig, unameOrig := fileExists, readAllText, lookPath, uname
+	t.Cleanup(func() {
+		fileExists, readAllText, lookPath, uname = fileExistsOrig, readAllTextOrig, lookPathOrig, unameOrig
+	})
+
+	fileExists = func(filePath string) bool {
+		_, found := files[filePath]
+		return found
+	}
+	readAllText = func(filePath string) (string, error) {
+		return files[filePath], nil
+	}
+	lookPath = func(file string) (string, error) {
+		return "", exec.ErrNotFound
+	}
+}
+
+func TestPlatformDetails_UsrLibOsRelease(t *testing.T) {
+	stubReleaseFiles(t, map[string]string{
+		usrLibOsReleaseFile: "NAME=\"Alpine Linux\"\nID=alpine\nVERSION_ID=3.19.1\nPRETTY_NAME=\"Alpine Linux v3.19\"\n",
+	})
+
+	name, version, err := getPlatformDetails(logger.NewMockLog())
+	assert.NoError(t, err)
+	assert.Equal(t, "Alpine Linux", name)
+	assert.Equal(t, "3.19.1", version)
+}
+
+func TestPlatformDetails_LsbReleaseFile(t *testing.T) {
+	stubReleaseFiles(t, map[string]string{
+		lsbReleaseFile: "DISTRIB_ID=Ubuntu\nDISTRIB_RELEASE=14.04\nDISTRIB_CODENAME=trusty\nDISTRIB_DESCRIPTION=\"Ubuntu 14.04.6 LTS\"\n",
+	})
+
+	name, version, err := getPlatformDetails(logger.NewMockLog())
+	assert.NoError(t, err)
+	assert.Equal(t, "Ubuntu", name)
+	assert.Equal(t, "14.04", version)
+}
+
+func TestPlatformDetails_UnameWithoutReleaseFiles(t *testing.T) {
+	stubReleaseFiles(t, map[string]string{})
+	uname = func(utsname *unix.Utsname) error {
+		copy(utsname.Sysname[:], "Linux")
+		copy(utsname.Release[:], "6.1.0-busybox")
+		return nil
+	}
+
+	name, version, err := getPlatformDetails(logger.NewMockLog())
+	assert.NoError(t, err)
+	assert.Equal(t, "Linux", name)
+	assert.Equal(t, "6.1.0-busybox", version)
+
+	uname = func(utsname *unix.Utsname) error {
+		return unix.EPERM
+	}
+	name, version, err = getPlatformDetails(logger.NewMockLog())
+	assert.Error(t, err)
+	assert.Equal(t, notAvailableMessage, name)
+	assert.Equal(t, notAvailableMessage, version)
+}
@@ -20,11 +20,15 @@ package platform
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/fileutil"
 	"github.com/aws/amazon-ssm-agent/agent/log"
 	"github.com/aws/amazon-ssm-agent/agent/versionutil"
+	"golang.org/x/sys/windows/registry"
 )
 
 // Win32_OperatingSystems https://msdn.microsoft.com/en-us/library/aa394239%28v=vs.85%29.aspx
@@ -43,6 +47,10 @@ const (
 
 var (
 	getPlatformVersionRef = getPlatformVersion
+	getPlatformSkuRef     = getPlatformSku
+
+	getInstallationTypeRef = getInstallationType
+	fileExistsRef          = fileutil.Exists
 )
 
 // isPlatformWindowsServer2012OrEarlier returns true if platform is Windows Server 2012 or earlier
@@ -84,16 +92,53 @@ func isWindowsServer2025OrLater(platformVersion string, log log.T) (bool, error)
 	}
 }
 
-// IsPlatformNanoServer returns true if SKU is 143 or 144
+// IsPlatformNanoServer returns true if SKU is 143 or 144 or the installation type is Nano Server
 func isPlatformNanoServer(log log.T) (bool, error) {
 	// Get platform sku information
-	if sku, err := getPlatformSku(log); err != nil {
+	sku, err := getPlatformSkuRef(log)
+	if err != nil {
 		log.Infof("Failed to fetch sku - %v", err)
+	} else if sku == ProductDataCenterNanoServer || sku == ProductStandardNanoServer {
+		return true, nil
+	}
+
+	// the installation type does not depend on WMI and also covers SKUs shared with other installation options
+	installationType, typeErr := getInstallationTypeRef()
+	if typeErr != nil {
+		if err == nil {
+			return false, nil
+		}
 		return false, err
-	} else {
-		// Return whether sku represents nano server
-		return sku == ProductDataCenterNanoServer || sku == ProductStandardNanoServer, nil
 	}
+	return installationType == InstallationTypeNanoServer, nil
+}
+
+// getInstallationType returns the installation option of Windows, e.g. Server Core or Server for Desktop Experience
+func getInstallationType() (string, error) {
+	key, err := registry.OpenKey(registry.LOCAL_MACHINE, `SOFTWARE\Microsoft\Windows NT\CurrentVersion`, registry.QUERY_VALUE)
+	if err != nil {
+		return "", err
+	}
+	defer key.Close()
+
+	installationType, _, err := key.GetStringValue("InstallationType")
+	return installationType, err
+}
+
+func getWindowsCapabilities(log log.T) (capabilities WindowsCapabilities, err error) {
+	if capabilities.InstallationType, err = getInstallationTypeRef(); err != nil {
+		log.Warnf("Failed to read Windows installation type: %v", err)
+		return capabilities, err
+	}
+	capabilities.NanoServer = capabilities.InstallationType == InstallationTypeNanoServer
+	capabilities.ServerCore = capabilities.InstallationType == InstallationTypeServerCore
+	capabilities.DesktopExperience = capabilities.InstallationType == InstallationTypeServer ||
+		capabilities.InstallationType == InstallationTypeClient
+
+	capabilities.PowerShellAvailable = fileExistsRef(appconfig.PowerShellPluginCommandName)
+	// WMIC is a feature on demand in recent builds and may be removed entirely
+	capabilities.WmicAvailable = fileExistsRef(filepath.Join(appconfig.EnvWinDir, "System32", "wbem", "wmic.exe"))
+	return capabilities, nil
 }
 
 func getPlatformName(log log.T) (value string, err error) {
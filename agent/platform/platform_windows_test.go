@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/fileutil"
 	"github.com/aws/amazon-ssm-agent/agent/log"
 	logger "github.com/aws/amazon-ssm-agent/agent/mocks/log"
 	"github.com/stretchr/testify/assert"
@@ -94,3 +96,74 @@ func TestVersion_Negative(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "test1")
 }
+
+func TestIsPlatformNanoServer_InstallationType(t *testing.T) {
+	logMock := logger.NewMockLog()
+	defer func() {
+		getPlatformSkuRef = getPlatformSku
+		getInstallationTypeRef = getInstallationType
+	}()
+
+	getPlatformSkuRef = func(log log.T) (string, error) {
+		return ProductStandardNanoServer, nil
+	}
+	isNano, err := isPlatformNanoServer(logMock)
+	assert.True(t, isNano)
+	assert.Nil(t, err)
+
+	getPlatformSkuRef = func(log log.T) (string, error) {
+		return "", fmt.Errorf("wmi unavailable")
+	}
+	getInstallationTypeRef = func() (string, error) {
+		return InstallationTypeNanoServer, nil
+	}
+	isNano, err = isPlatformNanoServer(logMock)
+	assert.True(t, isNano)
+	assert.Nil(t, err)
+
+	getInstallationTypeRef = func() (string, error) {
+		return "", fmt.Errorf("registry unavailable")
+	}
+	isNano, err = isPlatformNanoServer(logMock)
+	assert.False(t, isNano)
+	assert.NotNil(t, err)
+
+	getPlatformSkuRef = func(log log.T) (string, error) {
+		return "7", nil
+	}
+	isNano, err = isPlatformNanoServer(logMock)
+	assert.False(t, isNano)
+	assert.Nil(t, err)
+}
+
+func TestGetWindowsCapabilities(t *testing.T) {
+	logMock := logger.NewMockLog()
+	defer func() {
+		getInstallationTypeRef = getInstallationType
+		fileExistsRef = fileutil.Exists
+	}()
+
+	getInstallationTypeRef = func() (string, error) {
+		return InstallationTypeServerCore, nil
+	}
+	fileExistsRef = func(path string) bool {
+		return path == appconfig.PowerShellPluginCommandName
+	}
+	capabilities, err := getWindowsCapabilities(logMock)
+	assert.Nil(t, err)
+	assert.Equal(t, WindowsCapabilities{
+		InstallationType:    InstallationTypeServerCore,
+		ServerCore:          true,
+		PowerShellAvailable: true,
+	}, capabilities)
+
+	getInstallationTypeRef = func() (string, error) {
+		return InstallationTypeServer, nil
+	}
+	fileExistsRef = func(path string) bool { return true }
+	capabilities, err = getWindowsCapabilities(logMock)
+	assert.Nil(t, err)
+	assert.True(t, capabilities.DesktopExperience)
+	assert.True(t, capabilities.WmicAvailable)
+	assert.False(t, capabilities.NanoServer)
+}
@@ -163,10 +163,11 @@ func (p *Plugin) runCommands(pluginID string, pluginInput ApplicationPluginInput
 	}
 
 	// Execute Command
-	exitCode, err := p.CommandExecuter.NewExecute(p.context, defaultWorkingDirectory, output.GetStdoutWriter(), output.GetStderrWriter(), cancelFlag, defaultApplicationExecutionTimeoutInSeconds, commandName, commandArguments, make(map[string]string))
+	exitCode, accounting, err := p.CommandExecuter.NewExecute(p.context, defaultWorkingDirectory, output.GetStdoutWriter(), output.GetStderrWriter(), cancelFlag, defaultApplicationExecutionTimeoutInSeconds, commandName, commandArguments, make(map[string]string))
 
 	// Set output status
 	output.SetExitCode(exitCode)
+	output.AddProcessAccounting(accounting)
 	setMsiExecStatus(log, pluginInput, cancelFlag, output)
 
 	// Only delete the file if source is not a local path
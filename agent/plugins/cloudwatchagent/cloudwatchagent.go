@@ -0,0 +1,239 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package cloudwatchagent implements the lifecycle management of the unified amazon-cloudwatch-agent
+// for the aws:cloudWatch plugin. Input without an action is handed to the legacy long running plugin invoker.
+package cloudwatchagent
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/contracts"
+	"github.com/aws/amazon-ssm-agent/agent/fileutil"
+	"github.com/aws/amazon-ssm-agent/agent/framework/processor/executer/iohandler"
+	"github.com/aws/amazon-ssm-agent/agent/framework/runpluginutil"
+	"github.com/aws/amazon-ssm-agent/agent/jsonutil"
+	"github.com/aws/amazon-ssm-agent/agent/task"
+	"github.com/aws/amazon-ssm-agent/common/identity/identity"
+)
+
+const (
+	// AgentName identifies the results produced for the unified agent
+	AgentName = "amazon-cloudwatch-agent"
+	// PackageName is the name of the Distributor package of the unified agent
+	PackageName = "AmazonCloudWatchAgent"
+
+	// InstallAction installs the unified agent through Distributor
+	InstallAction = "Install"
+	// UninstallAction uninstalls the unified agent through Distributor
+	UninstallAction = "Uninstall"
+	// ConfigureAction pushes a new configuration to the unified agent and restarts it
+	ConfigureAction = "Configure"
+	// RestartAction restarts the unified agent with its current configuration
+	RestartAction = "Restart"
+	// StopAction stops the unified agent
+	StopAction = "Stop"
+	// StatusAction reports the status of the unified agent
+	StatusAction = "Status"
+
+	// ssmConfigPrefix marks configurations read by the unified agent from the parameter store
+	ssmConfigPrefix = "ssm:"
+	configFileName  = "amazon-cloudwatch-agent.json"
+)
+
+// Input represents the aws:cloudWatch input that manages the unified agent
+type Input struct {
+	Action string `json:"action"`
+	// Version of the Distributor package to install, empty for the latest version
+	Version string `json:"version"`
+	// Config is the agent configuration as JSON, or the name of a parameter prefixed with ssm:
+	Config string `json:"config"`
+}
+
+// Output is set as the plugin output so the result is not handed to the long running plugin manager
+type Output struct {
+	Agent  string `json:"agent"`
+	Action string `json:"action"`
+}
+
+// Plugin is the type for the aws:cloudWatch plugin.
+type Plugin struct {
+	context context.T
+	// legacy runs input for the EC2Config based cloudwatch long running plugin
+	legacy runpluginutil.T
+	// packageInstaller installs and uninstalls the unified agent package, usually aws:configurePackage
+	packageInstaller runpluginutil.T
+}
+
+// decoupling exec.Command for easy testability
+var ctlExecutor = executeCommand
+
+func executeCommand(command string, args ...string) ([]byte, error) {
+	return exec.Command(command, args...).CombinedOutput()
+}
+
+// NewPlugin returns a new instance of the aws:cloudWatch plugin
+func NewPlugin(context context.T, legacy runpluginutil.T, packageInstaller runpluginutil.T) (*Plugin, error) {
+	return &Plugin{
+		context:          context,
+		legacy:           legacy,
+		packageInstaller: packageInstaller,
+	}, nil
+}
+
+// IsAgentResult returns true if the plugin result was produced for the unified agent
+func IsAgentResult(res *contracts.PluginResult) bool {
+	if res == nil || res.Output == nil {
+		return false
+	}
+	if _, ok := res.Output.(string); ok {
+		return false
+	}
+	var output Output
+	if err := jsonutil.Remarshal(res.Output, &output); err != nil {
+		return false
+	}
+	return output.Agent == AgentName
+}
+
+// Execute runs the unified agent action, or the legacy plugin when the input has no action
+func (p *Plugin) Execute(config contracts.Configuration, cancelFlag task.CancelFlag, output iohandler.IOHandler) {
+	input, ok := parseInput(config.Properties)
+	if !ok {
+		p.legacy.Execute(config, cancelFlag, output)
+		return
+	}
+
+	log := p.context.Log()
+	log.Infof("Running %v action %v", AgentName, input.Action)
+	if cancelFlag.ShutDown() {
+		output.MarkAsShutdown()
+		return
+	} else if cancelFlag.Canceled() {
+		output.MarkAsCancelled()
+		return
+	}
+
+	switch input.Action {
+	case InstallAction, UninstallAction:
+		p.configurePackage(config, input, cancelFlag, output)
+	case ConfigureAction:
+		p.configure(config, input, output)
+	case RestartAction:
+		if p.runCtl(output, "-a", "stop") {
+			p.runCtl(output, "-a", "start")
+		}
+	case StopAction:
+		p.runCtl(output, "-a", "stop")
+	case StatusAction:
+		p.runCtl(output, "-a", "status")
+	default:
+		output.MarkAsFailed(fmt.Errorf("unsupported action %v, allowed values are %v", input.Action,
+			strings.Join([]string{InstallAction, UninstallAction, ConfigureAction, RestartAction, StopAction, StatusAction}, ", ")))
+		return
+	}
+	output.SetOutput(Output{Agent: AgentName, Action: input.Action})
+}
+
+// parseInput returns the unified agent input, or false when the properties are meant for the legacy plugin
+func parseInput(properties interface{}) (input Input, ok bool) {
+	switch properties.(type) {
+	case nil, string, *string:
+		// the legacy plugin receives its configuration as a string
+		return input, false
+	}
+	var fields map[string]interface{}
+	if err := jsonutil.Remarshal(properties, &fields); err != nil {
+		return input, false
+	}
+	if _, found := fields["action"]; !found {
+		return input, false
+	}
+	if err := jsonutil.Remarshal(properties, &input); err != nil {
+		return input, false
+	}
+	return input, true
+}
+
+// configurePackage installs or uninstalls the unified agent through the Distributor package
+func (p *Plugin) configurePackage(config contracts.Configuration, input Input, cancelFlag task.CancelFlag, output iohandler.IOHandler) {
+	packageConfig := config
+	packageConfig.Properties = map[string]interface{}{
+		"name":    PackageName,
+		"action":  input.Action,
+		"version": input.Version,
+	}
+	p.packageInstaller.Execute(packageConfig, cancelFlag, output)
+}
+
+// configure pushes the configuration to the unified agent, fetch-config restarts the agent when it is running
+func (p *Plugin) configure(config contracts.Configuration, input Input, output iohandler.IOHandler) {
+	configLocation, err := p.configLocation(config.OrchestrationDirectory, input.Config)
+	if err != nil {
+		output.MarkAsFailed(err)
+		return
+	}
+	p.runCtl(output, "-a", "fetch-config", "-m", p.mode(), "-c", configLocation, "-s")
+}
+
+// configLocation returns the -c argument of the agent control script for the given configuration
+func (p *Plugin) configLocation(orchestrationDirectory string, agentConfig string) (string, error) {
+	agentConfig = strings.TrimSpace(agentConfig)
+	if agentConfig == "" {
+		return "", fmt.Errorf("config is required to configure %v", AgentName)
+	}
+	if strings.HasPrefix(agentConfig, ssmConfigPrefix) {
+		return agentConfig, nil
+	}
+	if !json.Valid([]byte(agentConfig)) {
+		return "", fmt.Errorf("config is neither valid JSON nor a parameter name prefixed with %v", ssmConfigPrefix)
+	}
+	if err := fileutil.MakeDirs(orchestrationDirectory); err != nil {
+		return "", fmt.Errorf("failed to create directory %v: %v", orchestrationDirectory, err)
+	}
+	configPath := filepath.Join(orchestrationDirectory, configFileName)
+	if err := os.WriteFile(configPath, []byte(agentConfig), 0600); err != nil {
+		return "", fmt.Errorf("failed to write %v config: %v", AgentName, err)
+	}
+	return "file:" + configPath, nil
+}
+
+// mode returns the -m argument of the agent control script for this instance
+func (p *Plugin) mode() string {
+	if identity.IsOnPremInstance(p.context.Identity()) {
+		return "onPremise"
+	}
+	return "ec2"
+}
+
+// runCtl runs the agent control script and returns true if it succeeded
+func (p *Plugin) runCtl(output iohandler.IOHandler, args ...string) bool {
+	command, commandArgs := ctlCommand(args...)
+	p.context.Log().Debugf("Running %v %v", command, strings.Join(commandArgs, " "))
+	stdout, err := ctlExecutor(command, commandArgs...)
+	if len(stdout) > 0 {
+		output.AppendInfo(strings.TrimSpace(string(stdout)))
+	}
+	if err != nil {
+		output.MarkAsFailed(fmt.Errorf("%v %v failed: %v", AgentName, strings.Join(args, " "), err))
+		return false
+	}
+	output.MarkAsSucceeded()
+	return true
+}
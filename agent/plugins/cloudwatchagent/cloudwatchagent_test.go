@@ -0,0 +1,168 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package cloudwatchagent
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/contracts"
+	"github.com/aws/amazon-ssm-agent/agent/framework/processor/executer/iohandler"
+	"github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	"github.com/aws/amazon-ssm-agent/agent/task"
+	"github.com/stretchr/testify/assert"
+)
+
+type pluginStub struct {
+	configs []contracts.Configuration
+}
+
+func (p *pluginStub) Execute(config contracts.Configuration, cancelFlag task.CancelFlag, output iohandler.IOHandler) {
+	p.configs = append(p.configs, config)
+	output.MarkAsSucceeded()
+}
+
+func newTestPlugin(t *testing.T) (*Plugin, *pluginStub, *pluginStub, *[]string) {
+	legacy := &pluginStub{}
+	packageInstaller := &pluginStub{}
+	plugin, err := NewPlugin(context.NewMockDefault(), legacy, packageInstaller)
+	assert.NoError(t, err)
+
+	commands := []string{}
+	origCtlExecutor := ctlExecutor
+	ctlExecutor = func(command string, args ...string) ([]byte, error) {
+		commands = append(commands, strings.Join(args, " "))
+		return []byte("{\"status\": \"running\"}"), nil
+	}
+	t.Cleanup(func() { ctlExecutor = origCtlExecutor })
+	return plugin, legacy, packageInstaller, &commands
+}
+
+func newOutput() *iohandler.DefaultIOHandler {
+	return iohandler.NewDefaultIOHandler(context.NewMockDefault(), contracts.IOConfiguration{})
+}
+
+func TestExecuteLegacyInput(t *testing.T) {
+	plugin, legacy, packageInstaller, commands := newTestPlugin(t)
+	legacyProperties := []interface{}{
+		"{\"EngineConfiguration\": {}}",
+		map[string]interface{}{"id": "0.CloudWatch", "properties": "{}"},
+	}
+	for _, properties := range legacyProperties {
+		plugin.Execute(contracts.Configuration{Properties: properties}, task.NewChanneledCancelFlag(), newOutput())
+	}
+
+	assert.Len(t, legacy.configs, len(legacyProperties))
+	assert.Empty(t, packageInstaller.configs)
+	assert.Empty(t, *commands)
+}
+
+func TestExecuteInstall(t *testing.T) {
+	plugin, legacy, packageInstaller, _ := newTestPlugin(t)
+	output := newOutput()
+	properties := map[string]interface{}{"action": InstallAction, "version": "1.300.0"}
+
+	plugin.Execute(contracts.Configuration{Properties: properties}, task.NewChanneledCancelFlag(), output)
+
+	assert.Empty(t, legacy.configs)
+	assert.Len(t, packageInstaller.configs, 1)
+	assert.Equal(t, map[string]interface{}{"name": PackageName, "action": InstallAction, "version": "1.300.0"}, packageInstaller.configs[0].Properties)
+	assert.Equal(t, contracts.ResultStatusSuccess, output.GetStatus())
+}
+
+func TestExecuteConfigure(t *testing.T) {
+	plugin, _, _, commands := newTestPlugin(t)
+	output := newOutput()
+	orchestrationDir := t.TempDir()
+	properties := map[string]interface{}{"action": ConfigureAction, "config": "{\"metrics\": {}}"}
+
+	plugin.Execute(contracts.Configuration{Properties: properties, OrchestrationDirectory: orchestrationDir}, task.NewChanneledCancelFlag(), output)
+
+	configPath := filepath.Join(orchestrationDir, configFileName)
+	content, err := os.ReadFile(configPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "{\"metrics\": {}}", string(content))
+	assert.Len(t, *commands, 1)
+	assert.Contains(t, (*commands)[0], "-a fetch-config")
+	assert.Contains(t, (*commands)[0], "-c file:"+configPath)
+	assert.Equal(t, contracts.ResultStatusSuccess, output.GetStatus())
+	assert.True(t, IsAgentResult(&contracts.PluginResult{Output: output.GetOutput()}))
+}
+
+func TestExecuteConfigureFromParameter(t *testing.T) {
+	plugin, _, _, commands := newTestPlugin(t)
+	properties := map[string]interface{}{"action": ConfigureAction, "config": "ssm:AmazonCloudWatch-linux"}
+
+	plugin.Execute(contracts.Configuration{Properties: properties}, task.NewChanneledCancelFlag(), newOutput())
+
+	assert.Len(t, *commands, 1)
+	assert.Contains(t, (*commands)[0], "-c ssm:AmazonCloudWatch-linux")
+}
+
+func TestExecuteConfigureInvalidConfig(t *testing.T) {
+	plugin, _, _, commands := newTestPlugin(t)
+	output := newOutput()
+	properties := map[string]interface{}{"action": ConfigureAction, "config": "{not json"}
+
+	plugin.Execute(contracts.Configuration{Properties: properties, OrchestrationDirectory: t.TempDir()}, task.NewChanneledCancelFlag(), output)
+
+	assert.Empty(t, *commands)
+	assert.Equal(t, contracts.ResultStatusFailed, output.GetStatus())
+}
+
+func TestExecuteRestart(t *testing.T) {
+	plugin, _, _, commands := newTestPlugin(t)
+	output := newOutput()
+
+	plugin.Execute(contracts.Configuration{Properties: map[string]interface{}{"action": RestartAction}}, task.NewChanneledCancelFlag(), output)
+
+	assert.Len(t, *commands, 2)
+	assert.Contains(t, (*commands)[0], "-a stop")
+	assert.Contains(t, (*commands)[1], "-a start")
+	assert.Equal(t, contracts.ResultStatusSuccess, output.GetStatus())
+}
+
+func TestExecuteStatusFailure(t *testing.T) {
+	plugin, _, _, _ := newTestPlugin(t)
+	ctlExecutor = func(command string, args ...string) ([]byte, error) {
+		return []byte("agent is not installed"), fmt.Errorf("exit status 1")
+	}
+	output := newOutput()
+
+	plugin.Execute(contracts.Configuration{Properties: map[string]interface{}{"action": StatusAction}}, task.NewChanneledCancelFlag(), output)
+
+	assert.Equal(t, contracts.ResultStatusFailed, output.GetStatus())
+	assert.Contains(t, output.GetStdout(), "agent is not installed")
+}
+
+func TestExecuteUnsupportedAction(t *testing.T) {
+	plugin, _, _, commands := newTestPlugin(t)
+	output := newOutput()
+
+	plugin.Execute(contracts.Configuration{Properties: map[string]interface{}{"action": "Reinstall"}}, task.NewChanneledCancelFlag(), output)
+
+	assert.Empty(t, *commands)
+	assert.Equal(t, contracts.ResultStatusFailed, output.GetStatus())
+	assert.False(t, IsAgentResult(&contracts.PluginResult{Output: output.GetOutput()}))
+}
+
+func TestIsAgentResult(t *testing.T) {
+	assert.False(t, IsAgentResult(nil))
+	assert.False(t, IsAgentResult(&contracts.PluginResult{}))
+	assert.False(t, IsAgentResult(&contracts.PluginResult{Output: "{\"agent\": \"amazon-cloudwatch-agent\"}"}))
+	assert.True(t, IsAgentResult(&contracts.PluginResult{Output: map[string]interface{}{"agent": AgentName, "action": StatusAction}}))
+}
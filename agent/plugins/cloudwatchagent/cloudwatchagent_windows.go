@@ -0,0 +1,33 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build windows
+// +build windows
+
+package cloudwatchagent
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+)
+
+// ctlCommand returns the command that runs the agent control script with the given arguments
+func ctlCommand(args ...string) (string, []string) {
+	ctlPath := filepath.Join(os.Getenv("ProgramFiles"), "Amazon", "AmazonCloudWatchAgent", "amazon-cloudwatch-agent-ctl.ps1")
+	script := fmt.Sprintf("& '%s' %s", ctlPath, strings.Join(args, " "))
+	return appconfig.PowerShellPluginCommandName, []string{"-NoProfile", "-NonInteractive", "-ExecutionPolicy", "Bypass", "-Command", script}
+}
@@ -15,6 +15,7 @@
 package birdwatcherservice
 
 import (
+	reqContext "context"
 	"errors"
 	"fmt"
 	"reflect"
@@ -56,6 +57,7 @@ type PackageService struct {
 	collector      envdetect.Collector
 	timeProvider   NanoTime
 	packageArchive archive.IPackageArchive
+	requestContext reqContext.Context
 }
 
 func NewBirdwatcherArchive(ctx context.T, facadeClient facade.BirdwatcherFacade, manifestCache packageservice.ManifestCache, context map[string]string) packageservice.PackageService {
@@ -92,6 +94,11 @@ func (ds *PackageService) GetPackageArnAndVersion(packageName string, packageVer
 	return ds.packageArchive.GetResourceVersion(packageName, packageVersion)
 }
 
+// SetRequestContext sets the context whose cancellation interrupts the artifact download
+func (ds *PackageService) SetRequestContext(requestCtx reqContext.Context) {
+	ds.requestContext = requestCtx
+}
+
 // DownloadManifest downloads the manifest for a given version (or latest) and returns the agent version specified in manifest
 func (ds *PackageService) DownloadManifest(tracer trace.Tracer, packageName string, version string) (string, string, bool, error) {
 	manifest, isSameAsCache, err := downloadManifest(tracer, ds, packageName, version)
@@ -258,6 +265,7 @@ func downloadFile(ds *PackageService, tracer trace.Tracer, file *archive.File, p
 		DestinationDirectory: appconfig.DownloadRoot,
 		// TODO don't hardcode sha256 - use multiple checksums
 		SourceChecksums: file.Info.Checksums,
+		RequestContext:  ds.requestContext,
 	}
 
 	log := tracer.CurrentTrace().Logger
@@ -268,7 +276,8 @@ func downloadFile(ds *PackageService, tracer trace.Tracer, file *archive.File, p
 			errMessage = fmt.Sprintf("%v, %v", errMessage, downloadErr.Error())
 		}
 
-		if isRecursiveRetry {
+		// a cancelled download is not retried
+		if isRecursiveRetry || (ds.requestContext != nil && ds.requestContext.Err() != nil) {
 			// TODO: attempt to clean up failed download folder?
 			// return download error
 			return "", errors.New(errMessage)
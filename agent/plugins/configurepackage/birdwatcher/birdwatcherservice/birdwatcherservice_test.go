@@ -15,6 +15,7 @@
 package birdwatcherservice
 
 import (
+	reqContext "context"
 	"errors"
 	"fmt"
 	"testing"
@@ -99,7 +100,7 @@ func TestExtractPackageInfo(t *testing.T) {
 					{platformName, platformVersion, architecture, &birdwatcher.PackageInfo{FileName: "filename"}},
 				}),
 			},
-			&osdetect.OperatingSystem{platformName, platformVersion, "", architecture, "", ""},
+			&osdetect.OperatingSystem{platformName, platformVersion, "", architecture, "", "", nil},
 			&birdwatcher.PackageInfo{FileName: "filename"},
 			false,
 		},
@@ -110,7 +111,7 @@ func TestExtractPackageInfo(t *testing.T) {
 					{"nonexistname", platformVersion, architecture, &birdwatcher.PackageInfo{FileName: "filename"}},
 				}),
 			},
-			&osdetect.OperatingSystem{platformName, platformVersion, "", architecture, "", ""},
+			&osdetect.OperatingSystem{platformName, platformVersion, "", architecture, "", "", nil},
 			nil,
 			true,
 		},
@@ -121,7 +122,7 @@ func TestExtractPackageInfo(t *testing.T) {
 					{platformName, "nonexistversion", architecture, &birdwatcher.PackageInfo{FileName: "filename"}},
 				}),
 			},
-			&osdetect.OperatingSystem{platformName, platformVersion, "", architecture, "", ""},
+			&osdetect.OperatingSystem{platformName, platformVersion, "", architecture, "", "", nil},
 			nil,
 			true,
 		},
@@ -132,7 +133,7 @@ func TestExtractPackageInfo(t *testing.T) {
 					{platformName, platformVersion, "nonexistarch", &birdwatcher.PackageInfo{FileName: "filename"}},
 				}),
 			},
-			&osdetect.OperatingSystem{platformName, platformVersion, "", architecture, "", ""},
+			&osdetect.OperatingSystem{platformName, platformVersion, "", architecture, "", "", nil},
 			nil,
 			true,
 		},
@@ -144,7 +145,7 @@ func TestExtractPackageInfo(t *testing.T) {
 					{platformName, platformVersion, architecture, &birdwatcher.PackageInfo{FileName: "filename"}},
 				}),
 			},
-			&osdetect.OperatingSystem{platformName, platformVersion, "", architecture, "", ""},
+			&osdetect.OperatingSystem{platformName, platformVersion, "", architecture, "", "", nil},
 			&birdwatcher.PackageInfo{FileName: "filename"},
 			false,
 		},
@@ -155,7 +156,7 @@ func TestExtractPackageInfo(t *testing.T) {
 					{"_any", platformVersion, architecture, &birdwatcher.PackageInfo{FileName: "filename"}},
 				}),
 			},
-			&osdetect.OperatingSystem{platformName, platformVersion, "", architecture, "", ""},
+			&osdetect.OperatingSystem{platformName, platformVersion, "", architecture, "", "", nil},
 			&birdwatcher.PackageInfo{FileName: "filename"},
 			false,
 		},
@@ -166,7 +167,7 @@ func TestExtractPackageInfo(t *testing.T) {
 					{platformName, "_any", architecture, &birdwatcher.PackageInfo{FileName: "filename"}},
 				}),
 			},
-			&osdetect.OperatingSystem{platformName, platformVersion, "", architecture, "", ""},
+			&osdetect.OperatingSystem{platformName, platformVersion, "", architecture, "", "", nil},
 			&birdwatcher.PackageInfo{FileName: "filename"},
 			false,
 		},
@@ -177,7 +178,7 @@ func TestExtractPackageInfo(t *testing.T) {
 					{platformName, platformVersion, "_any", &birdwatcher.PackageInfo{FileName: "filename"}},
 				}),
 			},
-			&osdetect.OperatingSystem{platformName, platformVersion, "", architecture, "", ""},
+			&osdetect.OperatingSystem{platformName, platformVersion, "", architecture, "", "", nil},
 			&birdwatcher.PackageInfo{FileName: "filename"},
 			false,
 		},
@@ -189,7 +190,7 @@ func TestExtractPackageInfo(t *testing.T) {
 					{platformName, platformVersion, architecture, &birdwatcher.PackageInfo{FileName: "filename"}},
 				}),
 			},
-			&osdetect.OperatingSystem{platformName, platformVersion, "", architecture, "", ""},
+			&osdetect.OperatingSystem{platformName, platformVersion, "", architecture, "", "", nil},
 			&birdwatcher.PackageInfo{FileName: "filename"},
 			false,
 		},
@@ -200,7 +201,7 @@ func TestExtractPackageInfo(t *testing.T) {
 					{"_any", "_any", "_any", &birdwatcher.PackageInfo{FileName: "filename"}},
 				}),
 			},
-			&osdetect.OperatingSystem{platformName, platformVersion, "", architecture, "", ""},
+			&osdetect.OperatingSystem{platformName, platformVersion, "", architecture, "", "", nil},
 			&birdwatcher.PackageInfo{FileName: "filename"},
 			false,
 		},
@@ -212,7 +213,7 @@ func TestExtractPackageInfo(t *testing.T) {
 					{platformName, platformVersion, "nonexistarch", &birdwatcher.PackageInfo{FileName: "alsowrongfilename"}},
 				}),
 			},
-			&osdetect.OperatingSystem{platformName, platformVersion, "", architecture, "", ""},
+			&osdetect.OperatingSystem{platformName, platformVersion, "", architecture, "", "", nil},
 			nil,
 			true,
 		},
@@ -223,7 +224,7 @@ func TestExtractPackageInfo(t *testing.T) {
 					{platformName, "6.2", architecture, &birdwatcher.PackageInfo{FileName: "filename"}},
 				}),
 			},
-			&osdetect.OperatingSystem{platformName, "6.2.4", "", architecture, "", ""},
+			&osdetect.OperatingSystem{platformName, "6.2.4", "", architecture, "", "", nil},
 			nil,
 			true,
 		},
@@ -235,7 +236,7 @@ func TestExtractPackageInfo(t *testing.T) {
 					{platformName, "6.2.4.*", architecture, &birdwatcher.PackageInfo{FileName: "filename1"}},
 				}),
 			},
-			&osdetect.OperatingSystem{platformName, "6.2", "", architecture, "", ""},
+			&osdetect.OperatingSystem{platformName, "6.2", "", architecture, "", "", nil},
 			nil,
 			true,
 		},
@@ -246,7 +247,7 @@ func TestExtractPackageInfo(t *testing.T) {
 					{platformName, "6.*", architecture, &birdwatcher.PackageInfo{FileName: "filename"}},
 				}),
 			},
-			&osdetect.OperatingSystem{platformName, "6.2.4", "", architecture, "", ""},
+			&osdetect.OperatingSystem{platformName, "6.2.4", "", architecture, "", "", nil},
 			&birdwatcher.PackageInfo{FileName: "filename"},
 			false,
 		},
@@ -257,7 +258,7 @@ func TestExtractPackageInfo(t *testing.T) {
 					{platformName, "6.2.*", architecture, &birdwatcher.PackageInfo{FileName: "filename"}},
 				}),
 			},
-			&osdetect.OperatingSystem{platformName, "6.2.4", "", architecture, "", ""},
+			&osdetect.OperatingSystem{platformName, "6.2.4", "", architecture, "", "", nil},
 			&birdwatcher.PackageInfo{FileName: "filename"},
 			false,
 		},
@@ -268,7 +269,7 @@ func TestExtractPackageInfo(t *testing.T) {
 					{platformName, "6.2.4.*", architecture, &birdwatcher.PackageInfo{FileName: "filename"}},
 				}),
 			},
-			&osdetect.OperatingSystem{platformName, "6.2.4", "", architecture, "", ""},
+			&osdetect.OperatingSystem{platformName, "6.2.4", "", architecture, "", "", nil},
 			&birdwatcher.PackageInfo{FileName: "filename"},
 			false,
 		},
@@ -281,7 +282,7 @@ func TestExtractPackageInfo(t *testing.T) {
 					{platformName, "6.2.4.*", architecture, &birdwatcher.PackageInfo{FileName: "filename6.2.4.*"}},
 				}),
 			},
-			&osdetect.OperatingSystem{platformName, "6.2.4", "", architecture, "", ""},
+			&osdetect.OperatingSystem{platformName, "6.2.4", "", architecture, "", "", nil},
 			&birdwatcher.PackageInfo{FileName: "filename6.2.4.*"},
 			false,
 		},
@@ -292,7 +293,7 @@ func TestExtractPackageInfo(t *testing.T) {
 					{platformName, "*", architecture, &birdwatcher.PackageInfo{FileName: "filename"}},
 				}),
 			},
-			&osdetect.OperatingSystem{platformName, "6.2.4", "", architecture, "", ""},
+			&osdetect.OperatingSystem{platformName, "6.2.4", "", architecture, "", "", nil},
 			&birdwatcher.PackageInfo{FileName: "filename"},
 			false,
 		},
@@ -303,7 +304,7 @@ func TestExtractPackageInfo(t *testing.T) {
 					{platformName, ".*", architecture, &birdwatcher.PackageInfo{FileName: "filename"}},
 				}),
 			},
-			&osdetect.OperatingSystem{platformName, "6.2.4", "", architecture, "", ""},
+			&osdetect.OperatingSystem{platformName, "6.2.4", "", architecture, "", "", nil},
 			nil,
 			true,
 		},
@@ -314,7 +315,7 @@ func TestExtractPackageInfo(t *testing.T) {
 					{platformName, "6.1.*", architecture, &birdwatcher.PackageInfo{FileName: "filename"}},
 				}),
 			},
-			&osdetect.OperatingSystem{platformName, "6.13.4", "", architecture, "", ""},
+			&osdetect.OperatingSystem{platformName, "6.13.4", "", architecture, "", "", nil},
 			nil,
 			true,
 		},
@@ -325,7 +326,7 @@ func TestExtractPackageInfo(t *testing.T) {
 					{platformName, "6.*.4", architecture, &birdwatcher.PackageInfo{FileName: "filename"}},
 				}),
 			},
-			&osdetect.OperatingSystem{platformName, "6.2.4", "", architecture, "", ""},
+			&osdetect.OperatingSystem{platformName, "6.2.4", "", architecture, "", "", nil},
 			nil,
 			true,
 		},
@@ -336,7 +337,7 @@ func TestExtractPackageInfo(t *testing.T) {
 					{platformName, "2018nano", architecture, &birdwatcher.PackageInfo{FileName: "filename"}},
 				}),
 			},
-			&osdetect.OperatingSystem{platformName, "2018.04.11nano", "", architecture, "", ""},
+			&osdetect.OperatingSystem{platformName, "2018.04.11nano", "", architecture, "", "", nil},
 			nil,
 			true,
 		},
@@ -347,7 +348,7 @@ func TestExtractPackageInfo(t *testing.T) {
 					{platformName, "2018.05nano", architecture, &birdwatcher.PackageInfo{FileName: "filename"}},
 				}),
 			},
-			&osdetect.OperatingSystem{platformName, "2018.04.11nano", "", architecture, "", ""},
+			&osdetect.OperatingSystem{platformName, "2018.04.11nano", "", architecture, "", "", nil},
 			nil,
 			true,
 		},
@@ -358,7 +359,7 @@ func TestExtractPackageInfo(t *testing.T) {
 					{platformName, "2018.04.11", architecture, &birdwatcher.PackageInfo{FileName: "filename"}},
 				}),
 			},
-			&osdetect.OperatingSystem{platformName, "2018.04.11nano", "", architecture, "", ""},
+			&osdetect.OperatingSystem{platformName, "2018.04.11nano", "", architecture, "", "", nil},
 			nil,
 			true,
 		},
@@ -369,7 +370,7 @@ func TestExtractPackageInfo(t *testing.T) {
 					{platformName, "2018.04.11nano", architecture, &birdwatcher.PackageInfo{FileName: "filename"}},
 				}),
 			},
-			&osdetect.OperatingSystem{platformName, "2018.04.11nano", "", architecture, "", ""},
+			&osdetect.OperatingSystem{platformName, "2018.04.11nano", "", architecture, "", "", nil},
 			&birdwatcher.PackageInfo{FileName: "filename"},
 			false,
 		},
@@ -380,7 +381,7 @@ func TestExtractPackageInfo(t *testing.T) {
 					{platformName, "2018.*nano", architecture, &birdwatcher.PackageInfo{FileName: "filename"}},
 				}),
 			},
-			&osdetect.OperatingSystem{platformName, "2018.04.11nano", "", architecture, "", ""},
+			&osdetect.OperatingSystem{platformName, "2018.04.11nano", "", architecture, "", "", nil},
 			&birdwatcher.PackageInfo{FileName: "filename"},
 			false,
 		},
@@ -414,7 +415,7 @@ func TestExtractPackageInfo(t *testing.T) {
 
 func defaultCollectDataResponseObj() *envdetect.Environment {
 	return &envdetect.Environment{
-		&osdetect.OperatingSystem{"abc", "567", "", "xyz", "", ""},
+		&osdetect.OperatingSystem{"abc", "567", "", "xyz", "", "", nil},
 		&ec2infradetect.Ec2Infrastructure{"instanceIDX", "Reg1", "", "AZ1", "instanceTypeZ"},
 	}
 }
@@ -612,7 +613,7 @@ func TestDownloadManifest(t *testing.T) {
 			testArchive := birdwatcherarchive.New(&testdata.facadeClient, context)
 			mockedCollector := envdetect2.CollectorMock{}
 			envdata := &envdetect.Environment{
-				&osdetect.OperatingSystem{"abc", "567", "", "xyz", "", ""},
+				&osdetect.OperatingSystem{"abc", "567", "", "xyz", "", "", nil},
 				&ec2infradetect.Ec2Infrastructure{"instanceIDX", "Reg1", "", "AZ1", "instanceTypeZ"},
 			}
 
@@ -689,7 +690,7 @@ func TestDownloadDocument(t *testing.T) {
 		t.Run(testdata.name, func(t *testing.T) {
 			mockedCollector := envdetect2.CollectorMock{}
 			envdata := &envdetect.Environment{
-				&osdetect.OperatingSystem{"abc", "567", "", "xyz", "", ""},
+				&osdetect.OperatingSystem{"abc", "567", "", "xyz", "", "", nil},
 				&ec2infradetect.Ec2Infrastructure{"instanceIDX", "Reg1", "", "AZ1", "instanceTypeZ"},
 			}
 
@@ -863,7 +864,7 @@ func TestDownloadManifestDifferentFromCacheManifest(t *testing.T) {
 	testArchive := birdwatcherarchive.New(&testdata.facadeClient, context)
 	mockedCollector := envdetect2.CollectorMock{}
 	envdata := &envdetect.Environment{
-		&osdetect.OperatingSystem{"abc", "567", "", "xyz", "", ""},
+		&osdetect.OperatingSystem{"abc", "567", "", "xyz", "", "", nil},
 		&ec2infradetect.Ec2Infrastructure{"instanceIDX", "Reg1", "", "AZ1", "instanceTypeZ"},
 	}
 
@@ -956,7 +957,7 @@ func TestFindFileFromManifest(t *testing.T) {
 			mockedCollector := envdetect2.CollectorMock{}
 
 			mockedCollector.On("CollectData", mock.Anything).Return(&envdetect.Environment{
-				&osdetect.OperatingSystem{"platformName", "platformVersion", "", "architecture", "", ""},
+				&osdetect.OperatingSystem{"platformName", "platformVersion", "", "architecture", "", "", nil},
 				&ec2infradetect.Ec2Infrastructure{"instanceID", "region", "", "availabilityZone", "instanceType"},
 			}, nil).Once()
 
@@ -1074,6 +1075,34 @@ func TestDownloadFile(t *testing.T) {
 	}
 }
 
+func TestDownloadFileCancelled(t *testing.T) {
+	tracer := trace.NewTracer(log.NewMockLog())
+	tracer.BeginSection("test segment root")
+	network := networkMock{downloadError: reqContext.Canceled}
+	birdwatcher.Networkdep = &network
+
+	// the cache mock has no expectations, deleting the cached manifest to retry the download fails the test
+	cache := &cache_mock.ManifestCache{}
+	context := map[string]string{"packageName": "packagename", "packageVersion": "version", "manifest": "manifest"}
+	testArchive := birdwatcherarchive.New(&facade.FacadeStub{}, context)
+	testArchive.SetManifestCache(cache)
+
+	requestCtx, cancel := reqContext.WithCancel(reqContext.Background())
+	cancel()
+	ds := &PackageService{manifestCache: cache, collector: &envdetect2.CollectorMock{}, packageArchive: testArchive}
+	ds.SetRequestContext(requestCtx)
+
+	file := &archive.File{
+		Name: "fileName.zip",
+		Info: birdwatcher.FileInfo{DownloadLocation: "https://example.com/agent"},
+	}
+	_, err := downloadFile(ds, tracer, file, "packagename", "version", false)
+
+	assert.Error(t, err)
+	assert.Equal(t, requestCtx, network.downloadInput.RequestContext)
+	cache.AssertExpectations(t)
+}
+
 func TestDownloadFileFromDocumentArchive(t *testing.T) {
 	tracer := trace.NewTracer(log.NewMockLog())
 	tracer.BeginSection("test segment root")
@@ -1254,7 +1283,7 @@ func TestDownloadArtifact(t *testing.T) {
 			mockedCollector := envdetect2.CollectorMock{}
 
 			mockedCollector.On("CollectData", mock.Anything).Return(&envdetect.Environment{
-				&osdetect.OperatingSystem{"platformName", "platformVersion", "", "architecture", "", ""},
+				&osdetect.OperatingSystem{"platformName", "platformVersion", "", "architecture", "", "", nil},
 				&ec2infradetect.Ec2Infrastructure{"instanceID", "region", "", "availabilityZone", "instanceType"},
 			}, nil).Twice()
 			testArchive.SetManifestCache(cache)
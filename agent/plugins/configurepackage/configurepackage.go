@@ -28,6 +28,7 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/contracts"
 	"github.com/aws/amazon-ssm-agent/agent/framework/processor/executer/iohandler"
 	"github.com/aws/amazon-ssm-agent/agent/jsonutil"
+	"github.com/aws/amazon-ssm-agent/agent/log"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/configurepackage/birdwatcher/birdwatcherservice"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/configurepackage/birdwatcher/facade"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/configurepackage/installer"
@@ -35,6 +36,7 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/plugins/configurepackage/packageservice"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/configurepackage/trace"
 	"github.com/aws/amazon-ssm-agent/agent/task"
+	"github.com/aws/amazon-ssm-agent/agent/updateutil/updatelock"
 	"github.com/aws/aws-sdk-go/service/ssm"
 )
 
@@ -53,6 +55,10 @@ const birdwatcherVersionPattern = "^[A-Za-z0-9.]+$"
 const documentArnPattern = "^arn:[a-z0-9][-.a-z0-9]{0,62}:[a-z0-9][-.a-z0-9]{0,62}:([a-z0-9][-.a-z0-9]{0,62})?:([a-z0-9][-.a-z0-9]{0,62})?:document\\/[a-zA-Z0-9/:.\\-_]{1,128}$"
 
 var getConfig = appconfig.Config
+var acquireUpdateLock = updatelock.Acquire
+
+// agentPackageName is the normalized name of the packages installing the agent itself
+const agentPackageName = "amazonssmagent"
 
 // Plugin is the type for the configurepackage plugin.
 type Plugin struct {
@@ -442,6 +448,50 @@ func checkAlreadyInstalled(
 	return false
 }
 
+// detectPackageDrift marks the output as failed when the requested action would change the installed package
+// and as succeeded when the package is already in the requested state
+func detectPackageDrift(
+	tracer trace.Tracer,
+	repository localpackages.Repository,
+	input *ConfigurePackagePluginInput,
+	packageArn string,
+	version string,
+	isSameAsCache bool,
+	output contracts.PluginOutputter) {
+
+	detectTrace := tracer.BeginSection(fmt.Sprintf("detect drift for %s", input.Action))
+	defer detectTrace.End()
+
+	switch input.Action {
+	case InstallAction:
+		installedVersion, installState := getVersionToInstall(tracer, repository, packageArn)
+		if installedVersion == version && isSameAsCache &&
+			(installState == localpackages.Installed || installState == localpackages.Unknown) {
+			detectTrace.AppendInfof("%v %v is installed, no changes needed", input.Name, version)
+			output.MarkAsSucceeded()
+			return
+		}
+		detectTrace.AppendInfof("Drift detected: %v %v would be installed, installed version is %q in state %v", input.Name, version, installedVersion, installState)
+
+	case UninstallAction:
+		installedVersion, installState := getVersionToUninstall(tracer, repository, packageArn)
+		if input.Version == "" || packageservice.IsLatest(input.Version) {
+			version = installedVersion
+		}
+		if installedVersion == "" || version != installedVersion ||
+			installState == localpackages.None || installState == localpackages.Uninstalled {
+			detectTrace.AppendInfof("%v %v is not installed, no changes needed", input.Name, version)
+			output.MarkAsSucceeded()
+			return
+		}
+		detectTrace.AppendInfof("Drift detected: %v %v would be uninstalled", input.Name, installedVersion)
+
+	default:
+		detectTrace.AppendErrorf("unsupported action: %v", input.Action)
+	}
+	output.MarkAsFailed(nil, nil)
+}
+
 // selectService chooses the implementation of PackageService to use for a given execution of the plugin
 func selectService(context context.T, tracer trace.Tracer, input *ConfigurePackagePluginInput, localrepo localpackages.Repository, appCfg *appconfig.SsmagentConfig, birdwatcherFacade facade.BirdwatcherFacade, isDocumentArchive *bool) (packageservice.PackageService, error) {
 	response := &ssm.GetManifestOutput{}
@@ -526,6 +576,11 @@ func (p *Plugin) execute(config contracts.Configuration, cancelFlag task.CancelF
 		if err != nil {
 			tracer.CurrentTrace().WithError(err).End()
 			out.MarkAsFailed(nil, nil)
+		} else if cancelable, ok := packageService.(packageservice.CancelableService); ok {
+			// cancelling the command interrupts the artifact download instead of waiting for it to complete
+			requestCtx, cancel := task.NewCancelContext(cancelFlag)
+			defer cancel()
+			cancelable.SetRequestContext(requestCtx)
 		}
 		if out.GetStatus() != contracts.ResultStatusFailed {
 			//Return failure if the manifest cannot be accessed
@@ -542,13 +597,22 @@ func (p *Plugin) execute(config contracts.Configuration, cancelFlag task.CancelF
 			if err != nil {
 				tracer.CurrentTrace().WithError(err).End()
 				out.MarkAsFailed(nil, nil)
+			} else if config.DetectOnly {
+				// report whether the package would change without touching it or reporting a result to the package service
+				detectPackageDrift(tracer, p.localRepository, input, packageArn, manifestVersion, isSameAsCache, &out)
 			} else if err := p.localRepository.LockPackage(tracer, packageArn, input.Action); err != nil {
 				// do not allow multiple actions to be performed at the same time for the same package
 				// this is possible with multiple concurrent runcommand documents
 				tracer.CurrentTrace().WithError(err).End()
 				out.MarkAsFailed(nil, nil)
+			} else if updateLock, err := lockAgentUpdates(log, input.Name); err != nil {
+				// do not replace the agent while it is updated or installed by ssm-setup-cli
+				p.localRepository.UnlockPackage(tracer, packageArn)
+				tracer.CurrentTrace().WithError(err).End()
+				out.MarkAsFailed(nil, nil)
 			} else {
 				defer p.localRepository.UnlockPackage(tracer, packageArn)
+				defer updateLock.Release(log)
 
 				log.Debugf("Prepare for %v %v %v", input.Action, input.Name, input.Version)
 				inst, uninst, isUpdateInPlace, installState, installedVersion := prepareConfigurePackage(
@@ -563,6 +627,8 @@ func (p *Plugin) execute(config contracts.Configuration, cancelFlag task.CancelF
 					&out)
 				log.Debugf("HasInst %v, HasUninst %v, IsInplaceUpdate %v, InstallState %v, PackageName %v, InstalledVersion %v", inst != nil, uninst != nil, isUpdateInPlace, installState, packageArn, installedVersion)
 
+				setInstallerCancelFlag(cancelFlag, inst, uninst)
+
 				//if the status is already decided as failed or succeeded, do not execute anything
 				if out.GetStatus() != contracts.ResultStatusFailed && out.GetStatus() != contracts.ResultStatusSuccess {
 					alreadyInstalled := checkAlreadyInstalled(tracer, p.context, p.localRepository, installedVersion, installState, inst, uninst, &out)
@@ -585,6 +651,9 @@ func (p *Plugin) execute(config contracts.Configuration, cancelFlag task.CancelF
 							&out)
 					}
 				}
+				if cancelFlag.ShutDown() || cancelFlag.Canceled() {
+					markAsInterrupted(tracer, cancelFlag, &out)
+				}
 				if err := p.localRepository.LoadTraces(tracer, packageArn); err != nil {
 					log.Errorf("Error loading prior traces: %v", err.Error())
 				}
@@ -641,6 +710,47 @@ func (p *Plugin) execute(config contracts.Configuration, cancelFlag task.CancelF
 	return
 }
 
+// setInstallerCancelFlag lets the install and uninstall actions stop when the command is cancelled
+func setInstallerCancelFlag(cancelFlag task.CancelFlag, installers ...installer.Installer) {
+	for _, inst := range installers {
+		if cancelable, ok := inst.(installer.CancelableInstaller); ok {
+			cancelable.SetCancelFlag(cancelFlag)
+		}
+	}
+}
+
+// lockAgentUpdates takes the update lock for the packages installing the agent, such as AmazonSSMAgent or
+// amazon-ssm-agent, it returns no lock for other packages
+func lockAgentUpdates(log log.T, packageName string) (*updatelock.Lock, error) {
+	name := strings.ToLower(packageName[strings.LastIndex(packageName, "/")+1:])
+	name = strings.NewReplacer("-", "", "_", "", " ", "").Replace(name)
+	if name != agentPackageName {
+		return nil, nil
+	}
+	return acquireUpdateLock(log)
+}
+
+// markAsInterrupted reports the steps completed before the command was cancelled or the agent shut down
+func markAsInterrupted(tracer trace.Tracer, cancelFlag task.CancelFlag, out *trace.PluginOutputTrace) {
+	var completed []string
+	for _, step := range tracer.Traces() {
+		if step.Stop != 0 && step.Error == "" {
+			completed = append(completed, step.Operation)
+		}
+	}
+	if len(completed) > 0 {
+		tracer.CurrentTrace().AppendInfof("Interrupted after completing: %v", strings.Join(completed, ", "))
+	} else {
+		tracer.CurrentTrace().AppendInfo("Interrupted before any step completed")
+	}
+
+	if cancelFlag.ShutDown() {
+		out.MarkAsShutdown()
+	} else {
+		out.MarkAsCancelled()
+	}
+}
+
 // Name returns the name of the plugin.
 func Name() string {
 	return appconfig.PluginNameAwsConfigurePackage
@@ -18,11 +18,13 @@ import (
 	"testing"
 
 	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/contracts"
 	contextmocks "github.com/aws/amazon-ssm-agent/agent/mocks/context"
 	"github.com/aws/amazon-ssm-agent/agent/mocks/log"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/configurepackage/localpackages"
 	repository_mock "github.com/aws/amazon-ssm-agent/agent/plugins/configurepackage/localpackages/mock"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/configurepackage/trace"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -392,3 +394,48 @@ func TestUpdate_UpdateFailed_RollbackInstallFailed_DirectlyReinstallFailed(t *te
 	installerMock.AssertExpectations(t)
 	repoMock.AssertExpectations(t)
 }
+
+func detectDriftRepoMock(installedVersion string, installState localpackages.InstallState) *repository_mock.MockedRepository {
+	repoMock := &repository_mock.MockedRepository{}
+	repoMock.On("GetInstalledVersion", mock.Anything, "packageArn").Return(installedVersion)
+	repoMock.On("GetInstallState", mock.Anything, "packageArn").Return(installState, installedVersion)
+	return repoMock
+}
+
+func TestDetectPackageDrift(t *testing.T) {
+	testCases := []struct {
+		name             string
+		input            ConfigurePackagePluginInput
+		installedVersion string
+		installState     localpackages.InstallState
+		isSameAsCache    bool
+		expectedStatus   contracts.ResultStatus
+	}{
+		{"InstallAlreadyInstalled", ConfigurePackagePluginInput{Name: "SsmTest", Action: InstallAction}, "0.0.1", localpackages.Installed, true, contracts.ResultStatusSuccess},
+		{"InstallNotInstalled", ConfigurePackagePluginInput{Name: "SsmTest", Action: InstallAction}, "", localpackages.None, true, contracts.ResultStatusFailed},
+		{"InstallOtherVersion", ConfigurePackagePluginInput{Name: "SsmTest", Action: InstallAction}, "0.0.0", localpackages.Installed, true, contracts.ResultStatusFailed},
+		{"InstallManifestChanged", ConfigurePackagePluginInput{Name: "SsmTest", Action: InstallAction}, "0.0.1", localpackages.Installed, false, contracts.ResultStatusFailed},
+		{"UninstallInstalled", ConfigurePackagePluginInput{Name: "SsmTest", Action: UninstallAction}, "0.0.1", localpackages.Installed, true, contracts.ResultStatusFailed},
+		{"UninstallOtherVersion", ConfigurePackagePluginInput{Name: "SsmTest", Version: "0.0.2", Action: UninstallAction}, "0.0.1", localpackages.Installed, true, contracts.ResultStatusSuccess},
+		{"UninstallNotInstalled", ConfigurePackagePluginInput{Name: "SsmTest", Action: UninstallAction}, "", localpackages.None, true, contracts.ResultStatusSuccess},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repoMock := detectDriftRepoMock(tc.installedVersion, tc.installState)
+			tracer := trace.NewTracer(log.NewMockLog())
+			tracer.BeginSection("test segment root")
+			output := &trace.PluginOutputTrace{Tracer: tracer}
+
+			manifestVersion := "0.0.1"
+			if tc.input.Version != "" {
+				manifestVersion = tc.input.Version
+			}
+
+			detectPackageDrift(tracer, repoMock, &tc.input, "packageArn", manifestVersion, tc.isSameAsCache, output)
+
+			assert.Equal(t, tc.expectedStatus, output.GetStatus())
+			repoMock.AssertNotCalled(t, "LockPackage", mock.Anything, mock.Anything, mock.Anything)
+			repoMock.AssertNotCalled(t, "SetInstallState", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
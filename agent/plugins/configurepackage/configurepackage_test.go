@@ -26,13 +26,17 @@ import (
 
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
 	"github.com/aws/amazon-ssm-agent/agent/contracts"
+	agentLog "github.com/aws/amazon-ssm-agent/agent/log"
 	"github.com/aws/amazon-ssm-agent/agent/mocks/log"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/configurepackage/birdwatcher/facade"
 	facadeMock "github.com/aws/amazon-ssm-agent/agent/plugins/configurepackage/birdwatcher/facade/mocks"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/configurepackage/envdetect/ec2infradetect"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/configurepackage/localpackages"
+	repository_mock "github.com/aws/amazon-ssm-agent/agent/plugins/configurepackage/localpackages/mock"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/configurepackage/packageservice"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/configurepackage/trace"
+	"github.com/aws/amazon-ssm-agent/agent/task"
+	"github.com/aws/amazon-ssm-agent/agent/updateutil/updatelock"
 	"github.com/aws/amazon-ssm-agent/common/identity"
 	"github.com/aws/aws-sdk-go/service/ssm"
 	"github.com/stretchr/testify/assert"
@@ -616,6 +620,56 @@ func TestPrepareUpgrade_BirdwatcherService_InPlaceInstallationType(t *testing.T)
 	installerMock.AssertExpectations(t)
 }
 
+func TestExecute_AgentUpdateInProgress(t *testing.T) {
+	acquireUpdateLockOrig := acquireUpdateLock
+	defer func() { acquireUpdateLock = acquireUpdateLockOrig }()
+	acquireUpdateLock = func(agentLog.T) (*updatelock.Lock, error) {
+		return nil, updatelock.ErrUpdateInProgress
+	}
+
+	pluginInformation := createStubPluginInputInstall()
+	pluginInformation.Name = "AmazonSSMAgent"
+	installerMock := installerNotCalledMock()
+	repoMock := &repository_mock.MockedRepository{}
+	repoMock.On("LockPackage", mock.Anything, "packageArn", pluginInformation.Action).Return(nil)
+	repoMock.On("UnlockPackage", mock.Anything, "packageArn").Return()
+	serviceMock := serviceSuccessMock()
+	ioHandlerMock := createMockIOHandlerStruct(mock.Anything)
+
+	plugin := &Plugin{
+		context:                contextMock,
+		localRepository:        repoMock,
+		packageServiceSelector: selectMockService(serviceMock),
+	}
+	plugin.execute(buildConfigSimple(pluginInformation), createMockCancelFlag(), ioHandlerMock)
+
+	repoMock.AssertExpectations(t)
+	installerMock.AssertExpectations(t)
+	ioHandlerMock.AssertCalled(t, "SetStatus", contracts.ResultStatusFailed)
+}
+
+func TestLockAgentUpdates(t *testing.T) {
+	acquireUpdateLockOrig := acquireUpdateLock
+	defer func() { acquireUpdateLock = acquireUpdateLockOrig }()
+	acquired := 0
+	acquireUpdateLock = func(agentLog.T) (*updatelock.Lock, error) {
+		acquired++
+		return &updatelock.Lock{}, nil
+	}
+
+	for _, name := range []string{"AmazonSSMAgent", "amazon-ssm-agent", "arn:aws:ssm:us-east-1:123456789012:document/amazon_ssm_agent"} {
+		lock, err := lockAgentUpdates(log.NewMockLog(), name)
+		assert.NoError(t, err)
+		assert.NotNil(t, lock, name)
+	}
+	assert.Equal(t, 3, acquired)
+
+	lock, err := lockAgentUpdates(log.NewMockLog(), "PVDriver")
+	assert.NoError(t, err)
+	assert.Nil(t, lock)
+	assert.Equal(t, 3, acquired)
+}
+
 // Test that if Update is triggered but the requested package does not exist, use the install script to install
 func TestPrepareUpdate_PackageNotInstalled(t *testing.T) {
 	// file stubs are needed for ensurePackage because it handles the unzip
@@ -987,6 +1041,22 @@ func TestConfigurePackageForUpdate_BirdwatcherService_InPlaceInstallationType(t
 	serviceMock.AssertExpectations(t)
 }
 
+func TestMarkAsInterrupted(t *testing.T) {
+	tracer := trace.NewTracer(log.NewMockLog())
+	tracer.BeginSection("configurePackage")
+	tracer.BeginSection("download manifest").End()
+	tracer.BeginSection("download artifact").WithError(errors.New("context canceled")).End()
+	out := trace.PluginOutputTrace{Tracer: tracer}
+
+	cancelFlag := task.NewChanneledCancelFlag()
+	cancelFlag.Set(task.Canceled)
+	markAsInterrupted(tracer, cancelFlag, &out)
+
+	assert.Equal(t, contracts.ResultStatusCancelled, out.GetStatus())
+	assert.Equal(t, 1, out.GetExitCode())
+	assert.Contains(t, tracer.ToPluginOutput().GetStdout(), "Interrupted after completing: download manifest")
+}
+
 func TestParseAndValidateInputWithAdditionalArgument(t *testing.T) {
 	var inputMap = map[string]interface{}{"name": "PVDriver", "action": "Install", "additionalArguments": "{\"SSM_var1\":\"customVal1\",\"SSM_var2\":\"customVal2\"}"}
 	var rawPluginInput = interface{}(inputMap)
@@ -0,0 +1,135 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package osdetect
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/fileutil"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/version"
+)
+
+const cacheFileName = "osdetect.json"
+
+// fingerprint identifies the conditions the cached data was detected under, the cache is invalidated when
+// an os release file changes or the agent restarts (the parent process of the document worker changes)
+type fingerprint struct {
+	AgentVersion string
+	AgentPid     int
+	ReleaseFiles map[string]int64
+}
+
+// cacheEntry is the persisted form of the cache, shared by all document workers of the same agent run
+type cacheEntry struct {
+	Fingerprint fingerprint
+	Data        OperatingSystem
+}
+
+var (
+	cachePath = func() string {
+		return filepath.Join(appconfig.DefaultDataStorePath, cacheFileName)
+	}
+	agentPid = os.Getppid
+	detect   = DetectOSData
+
+	cacheLock sync.Mutex
+	cached    *cacheEntry
+)
+
+// CollectOSData returns the operating system type and capabilities, detected data is cached in memory and on
+// disk so bulk package operations do not repeat the detection for every package
+func CollectOSData(log log.T) (*OperatingSystem, error) {
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+
+	current := currentFingerprint()
+	if cached == nil {
+		cached = readCache(log)
+	}
+	if cached != nil && reflect.DeepEqual(cached.Fingerprint, current) {
+		data := cached.Data
+		return &data, nil
+	}
+
+	data, err := detect(log)
+	if err != nil {
+		return nil, err
+	}
+	cached = &cacheEntry{Fingerprint: current, Data: *data}
+	writeCache(log, cached)
+	result := *data
+	return &result, nil
+}
+
+// InvalidateOSDataCache drops the cached operating system data so the next CollectOSData detects it again
+func InvalidateOSDataCache(log log.T) {
+	cacheLock.Lock()
+	defer cacheLock.Unlock()
+
+	cached = nil
+	path := cachePath()
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		log.Warnf("Failed to remove os detection cache %v: %v", path, err)
+	}
+}
+
+func currentFingerprint() fingerprint {
+	files := make(map[string]int64)
+	for _, file := range releaseFiles() {
+		if info, err := os.Stat(file); err == nil {
+			files[file] = info.ModTime().UnixNano()
+		}
+	}
+	return fingerprint{
+		AgentVersion: version.Version,
+		AgentPid:     agentPid(),
+		ReleaseFiles: files,
+	}
+}
+
+func readCache(log log.T) *cacheEntry {
+	path := cachePath()
+	if !fileutil.Exists(path) {
+		return nil
+	}
+	var entry cacheEntry
+	content, err := fileutil.ReadAllText(path)
+	if err == nil {
+		err = json.Unmarshal([]byte(content), &entry)
+	}
+	if err != nil {
+		log.Warnf("Failed to read os detection cache %v: %v", path, err)
+		return nil
+	}
+	return &entry
+}
+
+func writeCache(log log.T, entry *cacheEntry) {
+	path := cachePath()
+	content, err := json.Marshal(entry)
+	if err == nil {
+		if err = fileutil.MakeDirs(filepath.Dir(path)); err == nil {
+			err = fileutil.WriteAllText(path, string(content))
+		}
+	}
+	if err != nil {
+		log.Warnf("Failed to write os detection cache %v: %v", path, err)
+	}
+}
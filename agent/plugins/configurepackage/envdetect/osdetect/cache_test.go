@@ -0,0 +1,143 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package osdetect
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	logmocks "github.com/aws/amazon-ssm-agent/agent/mocks/log"
+	"github.com/stretchr/testify/assert"
+)
+
+type cacheTestEnv struct {
+	releaseFile string
+	cacheFile   string
+	pid         int
+	detections  int
+	detectErr   error
+}
+
+func setupCache(t *testing.T) *cacheTestEnv {
+	dir := t.TempDir()
+	env := &cacheTestEnv{
+		releaseFile: filepath.Join(dir, "os-release"),
+		cacheFile:   filepath.Join(dir, "data", cacheFileName),
+		pid:         100,
+	}
+	assert.NoError(t, os.WriteFile(env.releaseFile, []byte("ID=amzn"), 0644))
+
+	origCachePath, origAgentPid, origDetect, origReleaseFiles := cachePath, agentPid, detect, releaseFiles
+	t.Cleanup(func() {
+		cachePath, agentPid, detect, releaseFiles = origCachePath, origAgentPid, origDetect, origReleaseFiles
+		cached = nil
+	})
+	cached = nil
+	cachePath = func() string { return env.cacheFile }
+	agentPid = func() int { return env.pid }
+	releaseFiles = func() []string { return []string{env.releaseFile, filepath.Join(dir, "missing-release")} }
+	detect = func(log.T) (*OperatingSystem, error) {
+		env.detections++
+		if env.detectErr != nil {
+			return nil, env.detectErr
+		}
+		return &OperatingSystem{"amazon", "2", "rhel", "x86_64", "systemd", "yum", nil}, nil
+	}
+	return env
+}
+
+func TestCollectOSData_UsesCache(t *testing.T) {
+	env := setupCache(t)
+	logger := logmocks.NewMockLog()
+
+	first, err := CollectOSData(logger)
+	assert.NoError(t, err)
+	first.Platform = "modified"
+
+	second, err := CollectOSData(logger)
+	assert.NoError(t, err)
+	assert.Equal(t, "amazon", second.Platform)
+	assert.Equal(t, 1, env.detections)
+	assert.FileExists(t, env.cacheFile)
+
+	// another document worker of the same agent run reads the persisted cache
+	cached = nil
+	third, err := CollectOSData(logger)
+	assert.NoError(t, err)
+	assert.Equal(t, second, third)
+	assert.Equal(t, 1, env.detections)
+}
+
+func TestCollectOSData_InvalidatedByReleaseFileChange(t *testing.T) {
+	env := setupCache(t)
+	logger := logmocks.NewMockLog()
+
+	_, err := CollectOSData(logger)
+	assert.NoError(t, err)
+
+	modified := time.Now().Add(time.Hour)
+	assert.NoError(t, os.Chtimes(env.releaseFile, modified, modified))
+	_, err = CollectOSData(logger)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, env.detections)
+}
+
+func TestCollectOSData_InvalidatedByAgentRestart(t *testing.T) {
+	env := setupCache(t)
+	logger := logmocks.NewMockLog()
+
+	_, err := CollectOSData(logger)
+	assert.NoError(t, err)
+
+	env.pid = 200
+	cached = nil
+	_, err = CollectOSData(logger)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, env.detections)
+}
+
+func TestInvalidateOSDataCache(t *testing.T) {
+	env := setupCache(t)
+	logger := logmocks.NewMockLog()
+
+	_, err := CollectOSData(logger)
+	assert.NoError(t, err)
+
+	InvalidateOSDataCache(logger)
+	assert.NoFileExists(t, env.cacheFile)
+
+	_, err = CollectOSData(logger)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, env.detections)
+}
+
+func TestCollectOSData_ErrorNotCached(t *testing.T) {
+	env := setupCache(t)
+	env.detectErr = errors.New("detection failed")
+	logger := logmocks.NewMockLog()
+
+	_, err := CollectOSData(logger)
+	assert.Error(t, err)
+	assert.NoFileExists(t, env.cacheFile)
+
+	env.detectErr = nil
+	result, err := CollectOSData(logger)
+	assert.NoError(t, err)
+	assert.Equal(t, "amazon", result.Platform)
+	assert.Equal(t, 2, env.detections)
+}
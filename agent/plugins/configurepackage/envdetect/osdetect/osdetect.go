@@ -15,10 +15,20 @@ type OperatingSystem struct {
 	Architecture    string
 	InitSystem      string
 	PackageManager  string
+	Darwin          *DarwinDetails `json:",omitempty"`
 }
 
-// CollectOSData quires the operating system for type and capabilities
-func CollectOSData(log log.T) (*OperatingSystem, error) {
+// DarwinDetails contains macOS product and processor information, it is only detected on darwin
+type DarwinDetails struct {
+	ProductName    string // product name reported by sw_vers, e.g. macOS
+	Codename       string // marketing name of the major release, e.g. Sonoma
+	ProcessorBrand string // e.g. Apple M2
+	AppleSilicon   bool   // the hardware has an arm64 Apple Silicon processor
+	Rosetta        bool   // the agent process runs translated by Rosetta 2
+}
+
+// DetectOSData queries the operating system for type and capabilities without consulting the cache
+func DetectOSData(log log.T) (*OperatingSystem, error) {
 	platform, platformVersion, platformFamily, err := DetectPlatform(log)
 	if err != nil {
 		return nil, err
@@ -46,6 +56,7 @@ func CollectOSData(log log.T) (*OperatingSystem, error) {
 		Architecture:    arch,
 		InitSystem:      init,
 		PackageManager:  pkg,
+		Darwin:          detectDarwinDetails(log, platformVersion),
 	}
 	return e, err
 }
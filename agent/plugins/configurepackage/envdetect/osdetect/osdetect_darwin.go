@@ -5,6 +5,7 @@ package osdetect
 
 import (
 	"os/exec"
+	"strconv"
 	"strings"
 
 	"github.com/aws/amazon-ssm-agent/agent/log"
@@ -12,6 +13,33 @@ import (
 	c "github.com/aws/amazon-ssm-agent/agent/plugins/configurepackage/envdetect/constants"
 )
 
+// releaseFiles lists the files the platform is detected from, changes to them invalidate the cached os data
+var releaseFiles = func() []string {
+	return []string{"/System/Library/CoreServices/SystemVersion.plist"}
+}
+
+// codenames maps macOS major releases to their marketing names, 10.x releases are keyed by major.minor
+var codenames = map[string]string{
+	"10.12": "Sierra",
+	"10.13": "High Sierra",
+	"10.14": "Mojave",
+	"10.15": "Catalina",
+	"11":    "Big Sur",
+	"12":    "Monterey",
+	"13":    "Ventura",
+	"14":    "Sonoma",
+	"15":    "Sequoia",
+	"26":    "Tahoe",
+}
+
+var swVers = func(flag string) ([]byte, error) {
+	return exec.Command("/usr/bin/sw_vers", flag).Output()
+}
+
+var sysctl = func(name string) ([]byte, error) {
+	return exec.Command("/usr/sbin/sysctl", "-n", name).Output()
+}
+
 func DetectPkgManager(platform string, version string, family string) (string, error) {
 	return c.PackageManagerMac, nil
 }
@@ -21,7 +49,7 @@ func DetectInitSystem() (string, error) {
 }
 
 func DetectPlatform(_ log.T) (string, string, string, error) {
-	cmdOut, err := exec.Command("/usr/bin/sw_vers", "-productVersion").Output()
+	cmdOut, err := swVers("-productVersion")
 	if err != nil {
 		return "", "", "", err
 	}
@@ -32,3 +60,42 @@ func DetectPlatform(_ log.T) (string, string, string, error) {
 func extractDarwinVersion(data []byte) string {
 	return strings.TrimSpace(string(data))
 }
+
+// detectDarwinDetails queries the macOS product name and whether the agent runs natively on Apple Silicon or
+// translated by Rosetta 2, failures are logged and leave the affected values empty
+func detectDarwinDetails(log log.T, version string) *DarwinDetails {
+	details := &DarwinDetails{Codename: extractDarwinCodename(version)}
+
+	if cmdOut, err := swVers("-productName"); err != nil {
+		log.Warnf("Failed to detect macOS product name: %v", err)
+	} else {
+		details.ProductName = strings.TrimSpace(string(cmdOut))
+	}
+
+	if cmdOut, err := sysctl("machdep.cpu.brand_string"); err == nil {
+		details.ProcessorBrand = strings.TrimSpace(string(cmdOut))
+	}
+
+	// both keys are missing on Intel hardware, which leaves the flags false
+	if cmdOut, err := sysctl("hw.optional.arm64"); err == nil {
+		details.AppleSilicon = parseSysctlFlag(cmdOut)
+	}
+	if cmdOut, err := sysctl("sysctl.proc_translated"); err == nil {
+		details.Rosetta = parseSysctlFlag(cmdOut)
+	}
+
+	return details
+}
+
+func extractDarwinCodename(version string) string {
+	parts := strings.Split(version, ".")
+	if parts[0] == "10" && len(parts) > 1 {
+		return codenames[parts[0]+"."+parts[1]]
+	}
+	return codenames[parts[0]]
+}
+
+func parseSysctlFlag(data []byte) bool {
+	value, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	return err == nil && value == 1
+}
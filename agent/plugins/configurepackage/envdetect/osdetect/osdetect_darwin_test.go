@@ -4,9 +4,12 @@
 package osdetect
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
+	"github.com/aws/amazon-ssm-agent/agent/mocks/log"
+
 	c "github.com/aws/amazon-ssm-agent/agent/plugins/configurepackage/envdetect/constants"
 	"github.com/stretchr/testify/assert"
 )
@@ -41,3 +44,70 @@ func TestDetectPkgManager(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, c.PackageManagerMac, result)
 }
+
+func TestExtractDarwinCodename(t *testing.T) {
+	data := []struct {
+		input    string
+		expected string
+	}{
+		{"10.15.7", "Catalina"},
+		{"10.9", ""},
+		{"14.4.1", "Sonoma"},
+		{"15.0", "Sequoia"},
+		{"99.1", ""},
+		{"", ""},
+	}
+	for _, m := range data {
+		t.Run(fmt.Sprintf("%s in %s", m.input, m.expected), func(t *testing.T) {
+			assert.Equal(t, m.expected, extractDarwinCodename(m.input))
+		})
+	}
+}
+
+func TestDetectDarwinDetails_RosettaOnAppleSilicon(t *testing.T) {
+	swVersOrig, sysctlOrig := swVers, sysctl
+	defer func() { swVers, sysctl = swVersOrig, sysctlOrig }()
+
+	swVers = func(flag string) ([]byte, error) {
+		return []byte("macOS\n"), nil
+	}
+	sysctl = func(name string) ([]byte, error) {
+		return map[string][]byte{
+			"machdep.cpu.brand_string": []byte("Apple M2\n"),
+			"hw.optional.arm64":        []byte("1\n"),
+			"sysctl.proc_translated":   []byte("1\n"),
+		}[name], nil
+	}
+
+	details := detectDarwinDetails(log.NewMockLog(), "14.4")
+
+	assert.Equal(t, &DarwinDetails{
+		ProductName:    "macOS",
+		Codename:       "Sonoma",
+		ProcessorBrand: "Apple M2",
+		AppleSilicon:   true,
+		Rosetta:        true,
+	}, details)
+}
+
+func TestDetectDarwinDetails_Intel(t *testing.T) {
+	swVersOrig, sysctlOrig := swVers, sysctl
+	defer func() { swVers, sysctl = swVersOrig, sysctlOrig }()
+
+	swVers = func(flag string) ([]byte, error) {
+		return nil, errors.New("sw_vers failed")
+	}
+	sysctl = func(name string) ([]byte, error) {
+		if name == "machdep.cpu.brand_string" {
+			return []byte("Intel(R) Core(TM) i7-8700B CPU @ 3.20GHz"), nil
+		}
+		return nil, errors.New("unknown oid")
+	}
+
+	details := detectDarwinDetails(log.NewMockLog(), "12.6")
+
+	assert.Equal(t, "", details.ProductName)
+	assert.Equal(t, "Monterey", details.Codename)
+	assert.False(t, details.AppleSilicon)
+	assert.False(t, details.Rosetta)
+}
@@ -17,6 +17,19 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/plugins/configurepackage/envdetect/utils"
 )
 
+// releaseFiles lists the files the platform is detected from, changes to them invalidate the cached os data
+var releaseFiles = func() []string {
+	return []string{
+		"/etc/os-release",
+		"/usr/lib/os-release",
+		"/etc/lsb-release",
+		"/etc/system-release",
+		"/etc/redhat-release",
+		"/etc/bottlerocket-release",
+		"/etc/alpine-release",
+	}
+}
+
 func DetectPkgManager(platform string, version string, family string) (string, error) {
 	switch family {
 	case c.PlatformFamilyDebian:
@@ -421,3 +434,7 @@ func platformFamilyForPlatform(platform string) (string, error) {
 		return "", fmt.Errorf("unknown platform: %s", platform)
 	}
 }
+
+func detectDarwinDetails(_ log.T, _ string) *DarwinDetails {
+	return nil
+}
@@ -5,7 +5,9 @@ package osdetect
 
 import (
 	"fmt"
+	"path/filepath"
 
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
 	"github.com/aws/amazon-ssm-agent/agent/log"
 	"github.com/aws/amazon-ssm-agent/agent/platform"
 
@@ -14,6 +16,11 @@ import (
 
 // https://msdn.microsoft.com/en-us/library/aa394239%28v=vs.85%29.aspx
 
+// releaseFiles lists files replaced by operating system upgrades, changes to them invalidate the cached os data
+var releaseFiles = func() []string {
+	return []string{filepath.Join(appconfig.EnvWinDir, "System32", "ntoskrnl.exe")}
+}
+
 var getOSInfo = func(osData platform.Win32_OperatingSystem) (platform.Win32_OperatingSystem, error) {
 	return platform.GetSingleWMIObject(osData)
 }
@@ -43,3 +50,7 @@ func isWindowsNano(operatingSystemSKU uint32) bool {
 	return operatingSystemSKU == c.SKUProductStandardNanoServer ||
 		operatingSystemSKU == c.SKUProductDatacenterNanoServer
 }
+
+func detectDarwinDetails(_ log.T, _ string) *DarwinDetails {
+	return nil
+}
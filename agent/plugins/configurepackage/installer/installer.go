@@ -19,6 +19,7 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/context"
 	"github.com/aws/amazon-ssm-agent/agent/contracts"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/configurepackage/trace"
+	"github.com/aws/amazon-ssm-agent/agent/task"
 )
 
 // Installer is used to install, uninstall, or update a package that exists in the local repository.
@@ -30,3 +31,8 @@ type Installer interface {
 	PackageName() string
 	Version() string
 }
+
+// CancelableInstaller is implemented by installers whose actions stop when the command is cancelled.
+type CancelableInstaller interface {
+	SetCancelFlag(cancelFlag task.CancelFlag)
+}
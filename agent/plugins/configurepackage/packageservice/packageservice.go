@@ -15,6 +15,7 @@
 package packageservice
 
 import (
+	"context"
 	"fmt"
 	"sort"
 
@@ -49,6 +50,11 @@ type PackageService interface {
 	ReportResult(tracer trace.Tracer, result PackageResult) error
 }
 
+// CancelableService is implemented by package services whose artifact downloads can be interrupted before they complete.
+type CancelableService interface {
+	SetRequestContext(requestCtx context.Context)
+}
+
 const (
 	PackageServiceName_ssms3       = "ssms3"
 	PackageServiceName_birdwatcher = "birdwatcherUsingBirdwatcherArchive"
@@ -0,0 +1,110 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package ssminstaller
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/contracts"
+	"github.com/aws/amazon-ssm-agent/agent/jsonutil"
+)
+
+// homebrewPrefixes are the default Homebrew locations on Apple silicon and on Intel Macs
+var homebrewPrefixes = []string{"/opt/homebrew", "/usr/local"}
+
+// homebrewName matches the names of taps, formulae and casks, which are passed to the shell unquoted
+var homebrewName = regexp.MustCompile(`^[A-Za-z0-9@+._/-]+$`)
+
+// BrewAction is the content of a brew action file listing the Homebrew taps, formulae and casks of a package
+type BrewAction struct {
+	Taps     []string `json:"taps"`
+	Formulae []string `json:"formulae"`
+	Casks    []string `json:"casks"`
+}
+
+// readBrewActionFile reads and validates the brew action file
+func (inst *Installer) readBrewActionFile(path string) (brewAction BrewAction, err error) {
+	content, err := inst.filesysdep.ReadFile(path)
+	if err != nil {
+		return brewAction, err
+	}
+	if err = jsonutil.Unmarshal(string(content), &brewAction); err != nil {
+		return brewAction, fmt.Errorf("invalid brew action %v: %v", filepath.Base(path), err)
+	}
+	if len(brewAction.Formulae) == 0 && len(brewAction.Casks) == 0 {
+		return brewAction, fmt.Errorf("brew action %v has no formulae or casks", filepath.Base(path))
+	}
+	for _, names := range [][]string{brewAction.Taps, brewAction.Formulae, brewAction.Casks} {
+		for _, name := range names {
+			if !homebrewName.MatchString(name) {
+				return brewAction, fmt.Errorf("%v is not a valid Homebrew name", name)
+			}
+		}
+	}
+	return brewAction, nil
+}
+
+// getBrewCommand returns the brew command that performs the action for the formulae or casks
+func getBrewCommand(actionName string, kind string, names []string) string {
+	var command string
+	switch actionName {
+	case ACTION_UPDATE:
+		command = "upgrade"
+	case ACTION_UNINSTALL:
+		command = "uninstall"
+	case ACTION_VALIDATE:
+		// listing fails for formulae and casks that are not installed
+		command = "list"
+	default:
+		command = "install"
+	}
+	return fmt.Sprintf("run_brew %v %v %v", command, kind, strings.Join(names, " "))
+}
+
+// readBrewAction turns a brew action into a shell plugin running Homebrew as the owner of the Homebrew installation,
+// because Homebrew refuses to run as root.
+func (inst *Installer) readBrewAction(context context.T, action *Action, workingDir string, orchestrationDir string, envVars map[string]string) (pluginsInfo []contracts.PluginState, err error) {
+	if action.actionType != ACTION_TYPE_BREW {
+		return nil, fmt.Errorf("Internal error")
+	}
+	brewAction, err := inst.readBrewActionFile(action.filepath)
+	if err != nil {
+		return nil, err
+	}
+
+	runCommand := []interface{}{}
+	runCommand = append(runCommand, fmt.Sprintf("echo Running brew %v from %v", action.actionName, filepath.Base(action.filepath)))
+	runCommand = append(runCommand, "brew_path=''")
+	runCommand = append(runCommand, fmt.Sprintf("for prefix in %v; do if [ -x \"$prefix/bin/brew\" ]; then brew_path=\"$prefix/bin/brew\"; break; fi; done", strings.Join(homebrewPrefixes, " ")))
+	runCommand = append(runCommand, "if [ -z \"$brew_path\" ]; then echo 'Homebrew is not installed' >&2; exit 1; fi")
+	runCommand = append(runCommand, "brew_user=$(stat -f %Su \"$brew_path\")")
+	runCommand = append(runCommand, "run_brew() { sudo -u \"$brew_user\" -H env HOMEBREW_NO_AUTO_UPDATE=1 HOMEBREW_NO_ANALYTICS=1 HOMEBREW_NO_ENV_HINTS=1 \"$brew_path\" \"$@\" || exit $?; }")
+	if action.actionName == ACTION_INSTALL || action.actionName == ACTION_UPDATE {
+		for _, tap := range brewAction.Taps {
+			runCommand = append(runCommand, fmt.Sprintf("run_brew tap %v", tap))
+		}
+	}
+	if len(brewAction.Formulae) > 0 {
+		runCommand = append(runCommand, getBrewCommand(action.actionName, "--formula", brewAction.Formulae))
+	}
+	if len(brewAction.Casks) > 0 {
+		runCommand = append(runCommand, getBrewCommand(action.actionName, "--cask", brewAction.Casks))
+	}
+
+	return inst.readScriptAction(action, workingDir, orchestrationDir, "runShellScript", runCommand, envVars)
+}
@@ -0,0 +1,107 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package ssminstaller
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/contracts"
+	"github.com/aws/amazon-ssm-agent/agent/mocks/log"
+	envdetectmocks "github.com/aws/amazon-ssm-agent/agent/plugins/configurepackage/mocks/envdetect"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/configurepackage/trace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// readBrewTestAction reads the action of an installer whose package contains the given brew action files
+func readBrewTestAction(t *testing.T, actionName string, files map[string]string) (bool, string, error) {
+	mockFileSys := MockedFileSys{}
+	for file, content := range files {
+		mockFileSys.On("Exists", filepath.Join(testPackagePath, file)).Return(true)
+		mockFileSys.On("ReadFile", filepath.Join(testPackagePath, file)).Return([]byte(content), nil)
+	}
+	mockFileSys.On("Exists", mock.Anything).Return(false)
+
+	mockEnvdetectCollector := &envdetectmocks.CollectorMock{}
+	mockEnvdetectCollector.On("CollectData", mock.Anything).Return(&environmentStub, nil)
+
+	tracer := trace.NewTracer(log.NewMockLog())
+	tracer.BeginSection("test segment root")
+
+	inst := Installer{
+		filesysdep:         &mockFileSys,
+		packagePath:        testPackagePath,
+		config:             contracts.Configuration{OrchestrationDirectory: "orchestration"},
+		envdetectCollector: mockEnvdetectCollector,
+	}
+	exists, pluginsInfo, _, _, err := inst.readAction(tracer, contextMock, actionName)
+	if err != nil || len(pluginsInfo) == 0 {
+		return exists, "", err
+	}
+
+	assert.Equal(t, "aws:runShellScript", pluginsInfo[0].Name)
+	var commands []string
+	for _, command := range pluginsInfo[0].Configuration.Properties.(map[string]interface{})["runCommand"].([]interface{}) {
+		commands = append(commands, command.(string))
+	}
+	return exists, strings.Join(commands, "\n"), nil
+}
+
+const testBrewAction = `{"taps": ["example/tools"], "formulae": ["wget", "example/tools/cli"], "casks": ["firefox"]}`
+
+func TestReadBrewAction_Install(t *testing.T) {
+	exists, script, err := readBrewTestAction(t, ACTION_INSTALL, map[string]string{"install.brew": testBrewAction})
+
+	assert.True(t, exists)
+	assert.NoError(t, err)
+	assert.Contains(t, script, "for prefix in /opt/homebrew /usr/local; do")
+	assert.Contains(t, script, `brew_user=$(stat -f %Su "$brew_path")`)
+	assert.Contains(t, script, "run_brew tap example/tools\nrun_brew install --formula wget example/tools/cli\nrun_brew install --cask firefox")
+}
+
+func TestReadBrewAction_Update(t *testing.T) {
+	exists, script, err := readBrewTestAction(t, ACTION_UPDATE, map[string]string{"update.brew": testBrewAction})
+
+	assert.True(t, exists)
+	assert.NoError(t, err)
+	assert.Contains(t, script, "run_brew tap example/tools\nrun_brew upgrade --formula wget example/tools/cli\nrun_brew upgrade --cask firefox")
+}
+
+func TestReadBrewAction_UninstallAndValidateWithInstallAction(t *testing.T) {
+	exists, script, err := readBrewTestAction(t, ACTION_UNINSTALL, map[string]string{"install.brew": testBrewAction})
+	assert.True(t, exists)
+	assert.NoError(t, err)
+	assert.NotContains(t, script, "run_brew tap")
+	assert.Contains(t, script, "run_brew uninstall --formula wget example/tools/cli\nrun_brew uninstall --cask firefox")
+
+	exists, script, err = readBrewTestAction(t, ACTION_VALIDATE, map[string]string{"install.brew": testBrewAction})
+	assert.True(t, exists)
+	assert.NoError(t, err)
+	assert.Contains(t, script, "run_brew list --formula wget example/tools/cli\nrun_brew list --cask firefox")
+}
+
+func TestReadBrewAction_Invalid(t *testing.T) {
+	for _, content := range []string{
+		`not json`,
+		`{"taps": ["example/tools"]}`,
+		`{"formulae": ["wget; rm -rf /"]}`,
+		`{"formulae": ["wget"], "casks": ["$(reboot)"]}`,
+	} {
+		exists, _, err := readBrewTestAction(t, ACTION_INSTALL, map[string]string{"install.brew": content})
+		assert.True(t, exists)
+		assert.Error(t, err, content)
+	}
+}
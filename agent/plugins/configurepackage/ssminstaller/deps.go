@@ -32,7 +32,7 @@ import (
 // dependency on action execution
 type execDep interface {
 	ParseDocument(context context.T, documentRaw []byte, orchestrationDir string, s3Bucket string, s3KeyPrefix string, messageID string, documentID string, defaultWorkingDirectory string) (pluginsInfo []contracts.PluginState, err error)
-	ExecuteDocument(context context.T, pluginInput []contracts.PluginState, documentID string, documentCreatedDate string, orchestrationDirectory string) (pluginOutputs map[string]*contracts.PluginResult)
+	ExecuteDocument(context context.T, cancelFlag task.CancelFlag, pluginInput []contracts.PluginState, documentID string, documentCreatedDate string, orchestrationDirectory string) (pluginOutputs map[string]*contracts.PluginResult)
 }
 
 type execDepImp struct {
@@ -57,7 +57,7 @@ func (m *execDepImp) ParseDocument(context context.T, documentRaw []byte, orches
 	return docContent.ParseDocument(context, contracts.DocumentInfo{}, parserInfo, nil)
 }
 
-func (m *execDepImp) ExecuteDocument(context context.T, pluginInput []contracts.PluginState, documentID string, documentCreatedDate string, orchestrationDirectory string) (pluginOutputs map[string]*contracts.PluginResult) {
+func (m *execDepImp) ExecuteDocument(context context.T, cancelFlag task.CancelFlag, pluginInput []contracts.PluginState, documentID string, documentCreatedDate string, orchestrationDirectory string) (pluginOutputs map[string]*contracts.PluginResult) {
 	log := context.Log()
 	log.Debugf("Running subcommand")
 	exe := basicexecuter.NewBasicExecuter(context)
@@ -75,7 +75,9 @@ func (m *execDepImp) ExecuteDocument(context context.T, pluginInput []contracts.
 		&docState,
 		docmanager.NewDocumentFileMgr(context, appconfig.DefaultDataStorePath, appconfig.DefaultDocumentRootDirName, appconfig.DefaultLocationOfState),
 		false)
-	cancelFlag := task.NewChanneledCancelFlag()
+	if cancelFlag == nil {
+		cancelFlag = task.NewChanneledCancelFlag()
+	}
 	resChan := exe.Run(cancelFlag, &docStore)
 
 	for res := range resChan {
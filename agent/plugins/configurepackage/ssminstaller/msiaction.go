@@ -0,0 +1,130 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package ssminstaller
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/contracts"
+	"github.com/aws/amazon-ssm-agent/agent/jsonutil"
+)
+
+const (
+	// msiPropertyPrefix marks the additional arguments passed to msiexec as public properties, e.g. SSM_MSI_INSTALLDIR
+	msiPropertyPrefix = "SSM_MSI_"
+
+	// msiErrorUnknownProduct is returned when uninstalling a product that is not installed
+	msiErrorUnknownProduct = 1605
+	// msiErrorSuccessRebootInitiated is returned when the installer restarted the instance itself
+	msiErrorSuccessRebootInitiated = 1641
+	// msiErrorSuccessRebootRequired is returned when a restart is required to complete the transaction
+	msiErrorSuccessRebootRequired = 3010
+)
+
+// msiPropertyName matches the names of public Windows Installer properties
+var msiPropertyName = regexp.MustCompile(`^[A-Z_][A-Z0-9_.]*$`)
+
+// getMsiExecMode returns the msiexec option that performs the action with the installer file
+func getMsiExecMode(action *Action) (string, error) {
+	switch action.actionType {
+	case ACTION_TYPE_MSI:
+		if action.actionName == ACTION_UNINSTALL {
+			return "/x", nil
+		}
+		return "/i", nil
+	case ACTION_TYPE_MSP:
+		if action.actionName == ACTION_UNINSTALL {
+			return "/uninstall", nil
+		}
+		return "/p", nil
+	default:
+		return "", fmt.Errorf("Internal error")
+	}
+}
+
+// getMsiProperties returns the msiexec property assignments from the additional arguments prefixed with SSM_MSI_
+func (inst *Installer) getMsiProperties() (properties []string, err error) {
+	if inst.additionalArguments == "" {
+		return properties, nil
+	}
+	var argumentMap map[string]string
+	if err = jsonutil.Unmarshal(inst.additionalArguments, &argumentMap); err != nil {
+		return nil, err
+	}
+	for key, value := range argumentMap {
+		if !strings.HasPrefix(key, msiPropertyPrefix) {
+			continue
+		}
+		name := strings.TrimPrefix(key, msiPropertyPrefix)
+		if !msiPropertyName.MatchString(name) {
+			return nil, fmt.Errorf("%v is not a valid public Windows Installer property name", name)
+		}
+		// msiexec escapes double quotes in property values by doubling them
+		properties = append(properties, fmt.Sprintf(`%v="%v"`, name, strings.Replace(value, `"`, `""`, -1)))
+	}
+	sort.Strings(properties)
+	return properties, nil
+}
+
+// readMsiAction turns an msi or msp action into a PowerShell plugin running msiexec.
+// Reboots requested by the installer are reported with the reboot exit code so the document reboot flow handles them.
+func (inst *Installer) readMsiAction(context context.T, action *Action, workingDir string, orchestrationDir string, envVars map[string]string) (pluginsInfo []contracts.PluginState, err error) {
+	mode, err := getMsiExecMode(action)
+	if err != nil {
+		return nil, err
+	}
+	properties, err := inst.getMsiProperties()
+	if err != nil {
+		return nil, err
+	}
+
+	logPath := filepath.Join(orchestrationDir, fmt.Sprintf("msiexec-%v.log", action.actionName))
+	msiArgs := []string{
+		quotePowerShell(mode),
+		quotePowerShell(fmt.Sprintf(`"%v"`, action.filepath)),
+		"'/qn'",
+		"'/norestart'",
+		"'/l*v'",
+		quotePowerShell(fmt.Sprintf(`"%v"`, logPath)),
+	}
+	for _, property := range properties {
+		msiArgs = append(msiArgs, quotePowerShell(property))
+	}
+
+	runCommand := []interface{}{}
+	runCommand = append(runCommand, fmt.Sprintf("echo 'Running msiexec %v %v'", mode, filepath.Base(action.filepath)))
+	runCommand = append(runCommand, fmt.Sprintf("New-Item -ItemType Directory -Force -Path %v | Out-Null", quotePowerShell(orchestrationDir)))
+	runCommand = append(runCommand, fmt.Sprintf("$msiProcess = Start-Process -FilePath msiexec.exe -ArgumentList @(%v) -Wait -PassThru", strings.Join(msiArgs, ", ")))
+	runCommand = append(runCommand, "$exitCode = $msiProcess.ExitCode")
+	runCommand = append(runCommand, fmt.Sprintf("if ($exitCode -eq %v) { $exitCode = %v }", msiErrorSuccessRebootInitiated, msiErrorSuccessRebootRequired))
+	if action.actionName == ACTION_UNINSTALL {
+		// uninstalling a product that is not installed succeeds, so that uninstall is idempotent
+		runCommand = append(runCommand, fmt.Sprintf("if ($exitCode -eq %v) { $exitCode = 0 }", msiErrorUnknownProduct))
+	}
+	runCommand = append(runCommand, fmt.Sprintf("echo \"msiexec exited with code $exitCode, log: %v\"", logPath))
+	runCommand = append(runCommand, fmt.Sprintf("if ($exitCode -ne 0 -and $exitCode -ne %v) { Get-Content -Path %v -Tail 50 -ErrorAction SilentlyContinue }", msiErrorSuccessRebootRequired, quotePowerShell(logPath)))
+	runCommand = append(runCommand, "exit $exitCode")
+
+	return inst.readScriptAction(action, workingDir, orchestrationDir, "runPowerShellScript", runCommand, envVars)
+}
+
+// quotePowerShell returns value as a single quoted PowerShell string
+func quotePowerShell(value string) string {
+	return "'" + strings.Replace(value, "'", "''", -1) + "'"
+}
@@ -0,0 +1,134 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package ssminstaller
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/contracts"
+	"github.com/aws/amazon-ssm-agent/agent/mocks/log"
+	envdetectmocks "github.com/aws/amazon-ssm-agent/agent/plugins/configurepackage/mocks/envdetect"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/configurepackage/trace"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// readMsiTestAction reads the action of an installer whose package contains the given files
+func readMsiTestAction(t *testing.T, actionName string, additionalArguments string, files ...string) (bool, string, error) {
+	mockFileSys := MockedFileSys{}
+	for _, file := range files {
+		mockFileSys.On("Exists", filepath.Join(testPackagePath, file)).Return(true)
+	}
+	mockFileSys.On("Exists", mock.Anything).Return(false)
+
+	mockEnvdetectCollector := &envdetectmocks.CollectorMock{}
+	mockEnvdetectCollector.On("CollectData", mock.Anything).Return(&environmentStub, nil)
+
+	tracer := trace.NewTracer(log.NewMockLog())
+	tracer.BeginSection("test segment root")
+
+	inst := Installer{
+		filesysdep:          &mockFileSys,
+		packagePath:         testPackagePath,
+		additionalArguments: additionalArguments,
+		config:              contracts.Configuration{OrchestrationDirectory: "orchestration"},
+		envdetectCollector:  mockEnvdetectCollector,
+	}
+	exists, pluginsInfo, _, _, err := inst.readAction(tracer, contextMock, actionName)
+	if err != nil || len(pluginsInfo) == 0 {
+		return exists, "", err
+	}
+
+	assert.Equal(t, "aws:runPowerShellScript", pluginsInfo[0].Name)
+	var commands []string
+	for _, command := range pluginsInfo[0].Configuration.Properties.(map[string]interface{})["runCommand"].([]interface{}) {
+		commands = append(commands, command.(string))
+	}
+	return exists, strings.Join(commands, "\n"), nil
+}
+
+func TestReadMsiAction_Install(t *testing.T) {
+	exists, script, err := readMsiTestAction(t, ACTION_INSTALL, "", "install.msi")
+
+	assert.True(t, exists)
+	assert.NoError(t, err)
+	assert.Contains(t, script, `-ArgumentList @('/i', '"`+filepath.Join(testPackagePath, "install.msi")+`"', '/qn', '/norestart', '/l*v', '"`+filepath.Join("orchestration", "install", "msiexec-install.log")+`"')`)
+	assert.Contains(t, script, "if ($exitCode -eq 1641) { $exitCode = 3010 }")
+	assert.NotContains(t, script, "1605")
+	assert.True(t, strings.HasSuffix(script, "exit $exitCode"))
+}
+
+func TestReadMsiAction_UpdateWithPatch(t *testing.T) {
+	exists, script, err := readMsiTestAction(t, ACTION_UPDATE, "", "update.msp")
+
+	assert.True(t, exists)
+	assert.NoError(t, err)
+	assert.Contains(t, script, `-ArgumentList @('/p', '"`+filepath.Join(testPackagePath, "update.msp")+`"'`)
+}
+
+func TestReadMsiAction_UninstallWithInstallPackage(t *testing.T) {
+	exists, script, err := readMsiTestAction(t, ACTION_UNINSTALL, "", "install.msi")
+
+	assert.True(t, exists)
+	assert.NoError(t, err)
+	assert.Contains(t, script, `-ArgumentList @('/x', '"`+filepath.Join(testPackagePath, "install.msi")+`"'`)
+	assert.Contains(t, script, "if ($exitCode -eq 1605) { $exitCode = 0 }")
+}
+
+func TestReadMsiAction_UninstallPrefersUninstallScript(t *testing.T) {
+	mockFileSys := MockedFileSys{}
+	mockFileSys.On("Exists", filepath.Join(testPackagePath, "uninstall.ps1")).Return(true)
+	mockFileSys.On("Exists", mock.Anything).Return(false)
+	inst := Installer{filesysdep: &mockFileSys, packagePath: testPackagePath}
+
+	tracer := trace.NewTracer(log.NewMockLog())
+	tracer.BeginSection("test segment root")
+	exists, action, err := inst.resolveAction(tracer, ACTION_UNINSTALL)
+
+	assert.True(t, exists)
+	assert.NoError(t, err)
+	assert.Equal(t, ACTION_TYPE_PS1, action.actionType)
+	mockFileSys.AssertNotCalled(t, "Exists", filepath.Join(testPackagePath, "install.msi"))
+}
+
+func TestReadMsiAction_Properties(t *testing.T) {
+	arguments := `{"SSM_MSI_INSTALLDIR":"C:\\Program Files\\It's \"Here\"","SSM_MSI_ADDLOCAL":"ALL","SSM_OTHER":"value"}`
+	_, script, err := readMsiTestAction(t, ACTION_INSTALL, arguments, "install.msi")
+
+	assert.NoError(t, err)
+	assert.Contains(t, script, `'/l*v', '"`+filepath.Join("orchestration", "install", "msiexec-install.log")+`"', 'ADDLOCAL="ALL"', 'INSTALLDIR="C:\Program Files\It''s ""Here"""')`)
+	assert.NotContains(t, script, "OTHER")
+}
+
+func TestReadMsiAction_InvalidPropertyName(t *testing.T) {
+	_, _, err := readMsiTestAction(t, ACTION_INSTALL, `{"SSM_MSI_INSTALL DIR":"x"}`, "install.msi")
+
+	assert.Error(t, err)
+}
+
+func TestReadMsiAction_TooManyImplementations(t *testing.T) {
+	exists, _, err := readMsiTestAction(t, ACTION_INSTALL, "", "install.msi", "install.ps1")
+
+	assert.True(t, exists)
+	assert.Error(t, err)
+}
+
+func TestReadMsiAction_ValidateIgnoresInstallerFiles(t *testing.T) {
+	exists, _, err := readMsiTestAction(t, ACTION_VALIDATE, "", "validate.msi")
+
+	assert.False(t, exists)
+	assert.NoError(t, err)
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"time"
 
 	"github.com/aws/amazon-ssm-agent/agent/context"
@@ -28,6 +29,7 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/jsonutil"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/configurepackage/envdetect"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/configurepackage/trace"
+	"github.com/aws/amazon-ssm-agent/agent/task"
 	"github.com/aws/amazon-ssm-agent/agent/times"
 )
 
@@ -40,6 +42,7 @@ type Installer struct {
 	packagePath         string
 	config              contracts.Configuration // TODO:MF: See if we can use a smaller struct that has just the things we need
 	envdetectCollector  envdetect.Collector
+	cancelFlag          task.CancelFlag
 }
 
 type ActionType uint8
@@ -47,6 +50,9 @@ type ActionType uint8
 const (
 	ACTION_TYPE_SH   ActionType = iota
 	ACTION_TYPE_PS1  ActionType = iota
+	ACTION_TYPE_MSI  ActionType = iota
+	ACTION_TYPE_MSP  ActionType = iota
+	ACTION_TYPE_BREW ActionType = iota
 	ACTION_INSTALL              = "install"
 	ACTION_UPDATE               = "update"
 	ACTION_VALIDATE             = "validate"
@@ -77,6 +83,11 @@ func New(packageName string,
 	}
 }
 
+// SetCancelFlag sets the flag of the command whose cancellation stops the running action
+func (inst *Installer) SetCancelFlag(cancelFlag task.CancelFlag) {
+	inst.cancelFlag = cancelFlag
+}
+
 func (inst *Installer) Install(tracer trace.Tracer, context context.T) contracts.PluginOutputter {
 	return inst.executeAction(tracer, context, ACTION_INSTALL)
 }
@@ -204,9 +215,16 @@ func (inst *Installer) readPs1Action(context context.T, action *Action, workingD
 func (inst *Installer) resolveAction(tracer trace.Tracer, actionName string) (exists bool, action *Action, err error) {
 	actionPathSh := inst.getActionPath(actionName, "sh")
 	actionPathPs1 := inst.getActionPath(actionName, "ps1")
+	actionPathMsi := inst.getActionPath(actionName, "msi")
+	actionPathMsp := inst.getActionPath(actionName, "msp")
+	actionPathBrew := inst.getActionPath(actionName, "brew")
 
 	actionPathExistsSh := inst.filesysdep.Exists(actionPathSh)
 	actionPathExistsPs1 := inst.filesysdep.Exists(actionPathPs1)
+	// Windows Installer files are only run for the actions msiexec can perform
+	actionPathExistsMsi := actionName != ACTION_VALIDATE && inst.filesysdep.Exists(actionPathMsi)
+	actionPathExistsMsp := actionName != ACTION_VALIDATE && inst.filesysdep.Exists(actionPathMsp)
+	actionPathExistsBrew := inst.filesysdep.Exists(actionPathBrew)
 	countExists := 0
 
 	actionTemp := &Action{}
@@ -223,15 +241,46 @@ func (inst *Installer) resolveAction(tracer trace.Tracer, actionName string) (ex
 		actionTemp.actionType = ACTION_TYPE_PS1
 		actionTemp.filepath = actionPathPs1
 	}
+	if actionPathExistsMsi {
+		countExists += 1
+		actionTemp.actionName = actionName
+		actionTemp.actionType = ACTION_TYPE_MSI
+		actionTemp.filepath = actionPathMsi
+	}
+	if actionPathExistsMsp {
+		countExists += 1
+		actionTemp.actionName = actionName
+		actionTemp.actionType = ACTION_TYPE_MSP
+		actionTemp.filepath = actionPathMsp
+	}
+	if actionPathExistsBrew {
+		countExists += 1
+		actionTemp.actionName = actionName
+		actionTemp.actionType = ACTION_TYPE_BREW
+		actionTemp.filepath = actionPathBrew
+	}
 
 	if countExists > 1 {
-		err = fmt.Errorf("%v has more than one implementation (sh, ps1, json)", actionName)
+		err = fmt.Errorf("%v has more than one implementation (sh, ps1, msi, msp, brew)", actionName)
 		tracer.CurrentTrace().WithError(err)
 		return true, nil, err
 	} else if countExists == 1 {
 		return true, actionTemp, nil
 	}
 
+	// a package installed from an msi is uninstalled with the same msi when it has no uninstall action
+	if actionName == ACTION_UNINSTALL {
+		if installPathMsi := inst.getActionPath(ACTION_INSTALL, "msi"); inst.filesysdep.Exists(installPathMsi) {
+			return true, &Action{actionName: actionName, actionType: ACTION_TYPE_MSI, filepath: installPathMsi}, nil
+		}
+	}
+	// a package installed with brew is validated and uninstalled with the same formulae and casks when it has no such action
+	if actionName == ACTION_UNINSTALL || actionName == ACTION_VALIDATE {
+		if installPathBrew := inst.getActionPath(ACTION_INSTALL, "brew"); inst.filesysdep.Exists(installPathBrew) {
+			return true, &Action{actionName: actionName, actionType: ACTION_TYPE_BREW, filepath: installPathBrew}, nil
+		}
+	}
+
 	return false, nil, nil
 }
 
@@ -262,6 +311,13 @@ func (inst *Installer) getEnvVars(actionName string, context context.T) (envVars
 	envVars["BWS_REGION"] = env.Ec2Infrastructure.Region
 	envVars["BWS_ACCOUNT_ID"] = env.Ec2Infrastructure.AccountID
 	envVars["BWS_AVAILABILITY_ZONE"] = env.Ec2Infrastructure.AvailabilityZone
+	if darwin := env.OperatingSystem.Darwin; darwin != nil {
+		// lets package scripts pick arm64 artifacts or refuse to install when running translated by Rosetta
+		envVars["BWS_PLATFORM_PRODUCT_NAME"] = darwin.ProductName
+		envVars["BWS_PLATFORM_CODENAME"] = darwin.Codename
+		envVars["BWS_APPLE_SILICON"] = strconv.FormatBool(darwin.AppleSilicon)
+		envVars["BWS_ROSETTA"] = strconv.FormatBool(darwin.Rosetta)
+	}
 
 	// Append validated additionalArguments map to environment variables in order to be passed to script execution
 	if inst.additionalArguments != "" {
@@ -291,7 +347,8 @@ func (inst *Installer) readAction(tracer trace.Tracer, context context.T, action
 	workingDir = inst.packagePath
 	orchestrationDir = filepath.Join(inst.config.OrchestrationDirectory, actionName)
 
-	if ACTION_TYPE_SH != action.actionType && ACTION_TYPE_PS1 != action.actionType {
+	if ACTION_TYPE_SH != action.actionType && ACTION_TYPE_PS1 != action.actionType &&
+		ACTION_TYPE_MSI != action.actionType && ACTION_TYPE_MSP != action.actionType && ACTION_TYPE_BREW != action.actionType {
 		return exists, nil, "", "", fmt.Errorf("Internal error. Unknown actionType %v", action.actionType)
 	}
 
@@ -304,6 +361,14 @@ func (inst *Installer) readAction(tracer trace.Tracer, context context.T, action
 		if pluginsInfo, err = inst.readShAction(context, action, workingDir, orchestrationDir, envVars); err != nil {
 			return exists, nil, "", "", err
 		}
+	} else if action.actionType == ACTION_TYPE_BREW {
+		if pluginsInfo, err = inst.readBrewAction(context, action, workingDir, orchestrationDir, envVars); err != nil {
+			return exists, nil, "", "", err
+		}
+	} else if action.actionType == ACTION_TYPE_MSI || action.actionType == ACTION_TYPE_MSP {
+		if pluginsInfo, err = inst.readMsiAction(context, action, workingDir, orchestrationDir, envVars); err != nil {
+			return exists, nil, "", "", err
+		}
 	} else {
 		if pluginsInfo, err = inst.readPs1Action(context, action, workingDir, orchestrationDir, envVars); err != nil {
 			return exists, nil, "", "", err
@@ -323,7 +388,7 @@ func (inst *Installer) executeDocument(
 
 	exectrace := tracer.CurrentTrace()
 
-	pluginOutputs := inst.execdep.ExecuteDocument(context, pluginsInfo, inst.config.BookKeepingFileName, times.ToIso8601UTC(time.Now()), orchestrationDir)
+	pluginOutputs := inst.execdep.ExecuteDocument(context, inst.cancelFlag, pluginsInfo, inst.config.BookKeepingFileName, times.ToIso8601UTC(time.Now()), orchestrationDir)
 	if pluginOutputs == nil {
 		exectrace.WithError(fmt.Errorf("No output from executing %s document", actionName))
 		output.MarkAsFailed(nil, nil)
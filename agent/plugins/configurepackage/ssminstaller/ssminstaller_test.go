@@ -30,6 +30,7 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/plugins/configurepackage/envdetect/osdetect"
 	envdetectmocks "github.com/aws/amazon-ssm-agent/agent/plugins/configurepackage/mocks/envdetect"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/configurepackage/trace"
+	"github.com/aws/amazon-ssm-agent/agent/task"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -56,6 +57,11 @@ func mockReadAction(t *testing.T, mockFileSys *MockedFileSys, actionPathNoExt st
 	// Setup mock with expectations
 	mockFileSys.On("Exists", actionPathNoExt+".sh").Return(len(contentSh) != 0).Once()
 	mockFileSys.On("Exists", actionPathNoExt+".ps1").Return(len(contentPs1) != 0).Once()
+	mockFileSys.On("Exists", actionPathNoExt+".msi").Return(false).Maybe()
+	mockFileSys.On("Exists", actionPathNoExt+".msp").Return(false).Maybe()
+	mockFileSys.On("Exists", filepath.Join(filepath.Dir(actionPathNoExt), "install.msi")).Return(false).Maybe()
+	mockFileSys.On("Exists", actionPathNoExt+".brew").Return(false).Maybe()
+	mockFileSys.On("Exists", filepath.Join(filepath.Dir(actionPathNoExt), "install.brew")).Return(false).Maybe()
 
 	if expectReads {
 		if len(contentSh) != 0 {
@@ -68,7 +74,7 @@ func mockReadAction(t *testing.T, mockFileSys *MockedFileSys, actionPathNoExt st
 }
 
 var environmentStub = envdetect.Environment{
-	&osdetect.OperatingSystem{"abc", "567", "", "xyz", "", ""},
+	&osdetect.OperatingSystem{"abc", "567", "", "xyz", "", "", nil},
 	&ec2infradetect.Ec2Infrastructure{"instanceIDX", "Reg1", "", "AZ1", "instanceTypeZ"},
 }
 
@@ -153,6 +159,9 @@ func testReadActionTooManyActionImplementations(t *testing.T, existSh bool, exis
 	actionPathNoExt := filepath.Join(testPackagePath, "Foo")
 	mockFileSys.On("Exists", actionPathNoExt+".sh").Return(existSh).Once()
 	mockFileSys.On("Exists", actionPathNoExt+".ps1").Return(existPs1).Once()
+	mockFileSys.On("Exists", actionPathNoExt+".msi").Return(false).Once()
+	mockFileSys.On("Exists", actionPathNoExt+".msp").Return(false).Once()
+	mockFileSys.On("Exists", actionPathNoExt+".brew").Return(false).Once()
 
 	mockEnvdetectCollector := &envdetectmocks.CollectorMock{}
 	mockEnvdetectCollector.On("CollectData", mock.Anything).Return(&environmentStub, nil).Once()
@@ -343,6 +352,35 @@ func TestUninstall_Success(t *testing.T) {
 	assert.Equal(t, contracts.ResultStatusSuccess, output.GetStatus())
 }
 
+func TestUninstall_UsesCommandCancelFlag(t *testing.T) {
+	// Setup mocks with expectations
+	mockFileSys := MockedFileSys{}
+	actionPathNoExt := filepath.Join(testPackagePath, "uninstall")
+	mockReadAction(t, &mockFileSys, actionPathNoExt, []byte("echo sh"), []byte{}, false)
+
+	cancelFlag := task.NewChanneledCancelFlag()
+	mockExec := MockedExec{}
+	mockExec.On("ExecuteDocument", mock.Anything, cancelFlag, mock.Anything, mock.Anything, mock.Anything).Return(map[string]*contracts.PluginResult{"Foo": {Status: contracts.ResultStatusCancelled}}).Once()
+
+	mockEnvdetectCollector := &envdetectmocks.CollectorMock{}
+	mockEnvdetectCollector.On("CollectData", mock.Anything).Return(&environmentStub, nil).Once()
+
+	tracer := trace.NewTracer(log.NewMockLog())
+
+	// Instantiate installer with mock
+	inst := Installer{filesysdep: &mockFileSys,
+		execdep:            &mockExec,
+		packagePath:        testPackagePath,
+		config:             contracts.Configuration{OutputS3BucketName: "foo", OutputS3KeyPrefix: "bar"},
+		envdetectCollector: mockEnvdetectCollector}
+	inst.SetCancelFlag(cancelFlag)
+
+	// Call and validate mock expectations and return value
+	output := inst.Uninstall(tracer, contextMock)
+	mockExec.AssertExpectations(t)
+	assert.Equal(t, contracts.ResultStatusCancelled, output.GetStatus())
+}
+
 func TestUpdate_Success(t *testing.T) {
 	// Setup mocks with expectations
 	mockFileSys := MockedFileSys{}
@@ -468,10 +506,11 @@ func (execMock *MockedExec) ParseDocument(
 
 func (execMock *MockedExec) ExecuteDocument(
 	context context.T,
+	cancelFlag task.CancelFlag,
 	pluginInput []contracts.PluginState,
 	documentID string,
 	documentCreatedDate string,
 	orchestrationDirectory string) (pluginOutputs map[string]*contracts.PluginResult) {
-	args := execMock.Called(context, pluginInput, documentID, documentCreatedDate)
+	args := execMock.Called(context, cancelFlag, pluginInput, documentID, documentCreatedDate)
 	return args.Get(0).(map[string]*contracts.PluginResult)
 }
@@ -0,0 +1,305 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package copyfile implements the aws:copyFile plugin
+package copyfile
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/contracts"
+	"github.com/aws/amazon-ssm-agent/agent/framework/processor/executer/iohandler"
+	"github.com/aws/amazon-ssm-agent/agent/jsonutil"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/task"
+)
+
+const (
+	// downloadsDir is the directory under the orchestration directory aws:downloadContent saves relative paths to
+	downloadsDir = "downloads"
+
+	sha256Prefix = "sha256:"
+
+	defaultDirMode os.FileMode = 0755
+)
+
+// Plugin is the type for the aws:copyFile plugin.
+type Plugin struct {
+	context context.T
+}
+
+// CopyFilePluginInput represents the input of the aws:copyFile plugin
+type CopyFilePluginInput struct {
+	contracts.PluginInput
+	// SourcePath is the file or directory to copy, relative paths are resolved against the aws:downloadContent downloads
+	SourcePath string `json:"sourcePath"`
+	// DestinationPath is the absolute path of the copied file or directory
+	DestinationPath string `json:"destinationPath"`
+	// Move removes the source once it is copied
+	Move bool `json:"move"`
+	// Overwrite replaces destination files with different content
+	Overwrite bool `json:"overwrite"`
+	// Checksum is the expected sha256 checksum of a source file, in hex optionally prefixed with sha256:
+	Checksum string `json:"checksum"`
+	// FileMode is the octal mode of the copied files, the source mode is kept when empty
+	FileMode string `json:"fileMode"`
+	// Owner and Group of the copied files and directories, not supported on Windows
+	Owner string `json:"owner"`
+	Group string `json:"group"`
+}
+
+// NewPlugin returns a new instance of the plugin.
+func NewPlugin(context context.T) (*Plugin, error) {
+	return &Plugin{
+		context: context,
+	}, nil
+}
+
+// Name returns the name of the plugin
+func Name() string {
+	return appconfig.PluginCopyFile
+}
+
+// Execute copies or moves the files described by the plugin input
+func (p *Plugin) Execute(config contracts.Configuration, cancelFlag task.CancelFlag, output iohandler.IOHandler) {
+	log := p.context.Log()
+	log.Infof("%v started with configuration %v", Name(), config)
+
+	if cancelFlag.ShutDown() {
+		output.MarkAsShutdown()
+	} else if cancelFlag.Canceled() {
+		output.MarkAsCancelled()
+	} else if input, err := parseAndValidateInput(config.Properties); err != nil {
+		output.MarkAsFailed(err)
+	} else if err = p.runCopyFile(log, input, config, cancelFlag, output); err != nil {
+		output.MarkAsFailed(err)
+	} else if cancelFlag.Canceled() {
+		output.MarkAsCancelled()
+	} else {
+		output.MarkAsSucceeded()
+	}
+}
+
+// parseAndValidateInput parses the plugin properties and validates them
+func parseAndValidateInput(rawPluginInput interface{}) (*CopyFilePluginInput, error) {
+	var input CopyFilePluginInput
+	if err := jsonutil.Remarshal(rawPluginInput, &input); err != nil {
+		return nil, fmt.Errorf("invalid format in plugin properties %v; \nerror %v", rawPluginInput, err)
+	}
+	input.SourcePath = strings.TrimSpace(input.SourcePath)
+	input.DestinationPath = strings.TrimSpace(input.DestinationPath)
+	input.Checksum = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(input.Checksum)), sha256Prefix)
+	input.FileMode = strings.TrimSpace(input.FileMode)
+	input.Owner = strings.TrimSpace(input.Owner)
+	input.Group = strings.TrimSpace(input.Group)
+
+	if input.SourcePath == "" {
+		return nil, errors.New("invalid input: SourcePath must be specified")
+	}
+	if input.DestinationPath == "" || !filepath.IsAbs(input.DestinationPath) {
+		return nil, errors.New("invalid input: DestinationPath must be an absolute path")
+	}
+	if input.Checksum != "" {
+		if decoded, err := hex.DecodeString(input.Checksum); err != nil || len(decoded) != sha256.Size {
+			return nil, fmt.Errorf("invalid input: Checksum %s is not a sha256 checksum", input.Checksum)
+		}
+	}
+	if _, err := parseFileMode(input.FileMode); err != nil {
+		return nil, fmt.Errorf("invalid input: %v", err)
+	}
+	if err := validateOwnership(input.Owner, input.Group); err != nil {
+		return nil, fmt.Errorf("invalid input: %v", err)
+	}
+	return &input, nil
+}
+
+// runCopyFile copies the source file or directory tree to the destination
+func (p *Plugin) runCopyFile(log log.T, input *CopyFilePluginInput, config contracts.Configuration, cancelFlag task.CancelFlag, output iohandler.IOHandler) error {
+	sourcePath := input.SourcePath
+	if !filepath.IsAbs(sourcePath) {
+		orchestrationDir := strings.TrimSuffix(config.OrchestrationDirectory, config.PluginID)
+		sourcePath = filepath.Join(orchestrationDir, downloadsDir, sourcePath)
+	}
+	sourceInfo, err := os.Stat(sourcePath)
+	if err != nil {
+		return fmt.Errorf("source %s is not accessible: %v", input.SourcePath, err)
+	}
+	mode, _ := parseFileMode(input.FileMode)
+
+	if !sourceInfo.IsDir() {
+		destinationPath := input.DestinationPath
+		if info, err := os.Stat(destinationPath); err == nil && info.IsDir() {
+			destinationPath = filepath.Join(destinationPath, filepath.Base(sourcePath))
+		}
+		checksum, err := copyFile(sourcePath, destinationPath, input, mode)
+		if err != nil {
+			return err
+		}
+		output.AppendInfof("Copied %v to %v (sha256:%v)", sourcePath, destinationPath, checksum)
+		return removeSource(input, sourcePath)
+	}
+
+	if input.Checksum != "" {
+		return fmt.Errorf("Checksum is only supported when SourcePath is a file, %s is a directory", input.SourcePath)
+	}
+	copied := 0
+	err = filepath.Walk(sourcePath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if cancelFlag.Canceled() {
+			return filepath.SkipDir
+		}
+		relativePath, err := filepath.Rel(sourcePath, path)
+		if err != nil {
+			return err
+		}
+		destinationPath := filepath.Join(input.DestinationPath, relativePath)
+		if info.IsDir() {
+			return makeDir(destinationPath, input)
+		}
+		if !info.Mode().IsRegular() {
+			log.Warnf("Skipping %s, only regular files are copied", path)
+			return nil
+		}
+
+		checksum, err := copyFile(path, destinationPath, input, mode)
+		if err != nil {
+			return err
+		}
+		log.Debugf("Copied %s to %s (sha256:%s)", path, destinationPath, checksum)
+		copied++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	output.AppendInfof("Copied %v files from %v to %v", copied, sourcePath, input.DestinationPath)
+	if cancelFlag.Canceled() {
+		return nil
+	}
+	return removeSource(input, sourcePath)
+}
+
+// copyFile copies a file through a temporary file next to the destination, verifying the checksum before it replaces the destination
+func copyFile(sourcePath, destinationPath string, input *CopyFilePluginInput, mode os.FileMode) (checksum string, err error) {
+	source, err := os.Open(sourcePath)
+	if err != nil {
+		return "", err
+	}
+	defer source.Close()
+	sourceInfo, err := source.Stat()
+	if err != nil {
+		return "", err
+	}
+	if mode == 0 {
+		mode = sourceInfo.Mode().Perm()
+	}
+
+	if err = os.MkdirAll(filepath.Dir(destinationPath), defaultDirMode); err != nil {
+		return "", fmt.Errorf("failed to create directory %s: %v", filepath.Dir(destinationPath), err)
+	}
+	temp, err := os.CreateTemp(filepath.Dir(destinationPath), "."+filepath.Base(destinationPath)+".copy")
+	if err != nil {
+		return "", fmt.Errorf("failed to create file in %s: %v", filepath.Dir(destinationPath), err)
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(temp.Name())
+		}
+	}()
+
+	digester := sha256.New()
+	_, err = io.Copy(io.MultiWriter(temp, digester), source)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to copy %s: %v", sourcePath, err)
+	}
+	checksum = hex.EncodeToString(digester.Sum(nil))
+	if input.Checksum != "" && checksum != input.Checksum {
+		return "", fmt.Errorf("checksum mismatch for %s, expected sha256:%s but got sha256:%s", sourcePath, input.Checksum, checksum)
+	}
+
+	if !input.Overwrite {
+		if existing, sumErr := fileChecksum(destinationPath); sumErr == nil && existing != checksum {
+			return "", fmt.Errorf("destination %s already exists with different content, set Overwrite to replace it", destinationPath)
+		}
+	}
+	if err = os.Chmod(temp.Name(), mode); err != nil {
+		return "", fmt.Errorf("failed to set mode of %s: %v", destinationPath, err)
+	}
+	if err = changeOwner(temp.Name(), input.Owner, input.Group); err != nil {
+		return "", fmt.Errorf("failed to set owner of %s: %v", destinationPath, err)
+	}
+	if err = os.Rename(temp.Name(), destinationPath); err != nil {
+		return "", fmt.Errorf("failed to replace %s: %v", destinationPath, err)
+	}
+	return checksum, nil
+}
+
+func makeDir(path string, input *CopyFilePluginInput) error {
+	if err := os.MkdirAll(path, defaultDirMode); err != nil {
+		return fmt.Errorf("failed to create directory %s: %v", path, err)
+	}
+	if err := changeOwner(path, input.Owner, input.Group); err != nil {
+		return fmt.Errorf("failed to set owner of %s: %v", path, err)
+	}
+	return nil
+}
+
+func removeSource(input *CopyFilePluginInput, sourcePath string) error {
+	if !input.Move {
+		return nil
+	}
+	if err := os.RemoveAll(sourcePath); err != nil {
+		return fmt.Errorf("copied but failed to remove source %s: %v", sourcePath, err)
+	}
+	return nil
+}
+
+func fileChecksum(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	digester := sha256.New()
+	if _, err = io.Copy(digester, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(digester.Sum(nil)), nil
+}
+
+// parseFileMode parses an octal file mode, an empty mode is returned as 0
+func parseFileMode(fileMode string) (os.FileMode, error) {
+	if fileMode == "" {
+		return 0, nil
+	}
+	mode, err := strconv.ParseUint(fileMode, 8, 32)
+	if err != nil || mode == 0 || mode > 0777 {
+		return 0, fmt.Errorf("FileMode %s is not an octal mode like 0644", fileMode)
+	}
+	return os.FileMode(mode), nil
+}
@@ -0,0 +1,171 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package copyfile
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/contracts"
+	"github.com/aws/amazon-ssm-agent/agent/framework/processor/executer/iohandler"
+	"github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	"github.com/aws/amazon-ssm-agent/agent/task"
+	"github.com/stretchr/testify/assert"
+)
+
+func checksumOf(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func runPlugin(t *testing.T, orchestrationDir string, properties map[string]interface{}) iohandler.IOHandler {
+	p, _ := NewPlugin(context.NewMockDefault())
+	output := iohandler.NewDefaultIOHandler(context.NewMockDefault(), contracts.IOConfiguration{})
+	config := contracts.Configuration{
+		Properties:             properties,
+		PluginID:               "copy",
+		OrchestrationDirectory: filepath.Join(orchestrationDir, "copy"),
+	}
+	p.Execute(config, task.NewChanneledCancelFlag(), output)
+	return output
+}
+
+func TestParseAndValidateInput(t *testing.T) {
+	destination := filepath.Join(t.TempDir(), "app.conf")
+	testCases := []struct {
+		properties map[string]interface{}
+		valid      bool
+	}{
+		{map[string]interface{}{"sourcePath": "app.conf", "destinationPath": destination}, true},
+		{map[string]interface{}{"sourcePath": "app.conf", "destinationPath": destination, "checksum": "SHA256:" + checksumOf("x"), "fileMode": "0640"}, true},
+		{map[string]interface{}{"destinationPath": destination}, false},
+		{map[string]interface{}{"sourcePath": "app.conf", "destinationPath": "relative/app.conf"}, false},
+		{map[string]interface{}{"sourcePath": "app.conf", "destinationPath": destination, "checksum": "abc"}, false},
+		{map[string]interface{}{"sourcePath": "app.conf", "destinationPath": destination, "fileMode": "0999"}, false},
+	}
+	for _, tc := range testCases {
+		_, err := parseAndValidateInput(tc.properties)
+		assert.Equal(t, tc.valid, err == nil, "%v: %v", tc.properties, err)
+	}
+}
+
+func TestExecute_CopiesDownloadedFileWithChecksumAndMode(t *testing.T) {
+	orchestrationDir := t.TempDir()
+	source := filepath.Join(orchestrationDir, downloadsDir, "app.conf")
+	os.MkdirAll(filepath.Dir(source), 0700)
+	os.WriteFile(source, []byte("port=80"), 0600)
+	destination := filepath.Join(t.TempDir(), "etc", "app.conf")
+
+	output := runPlugin(t, orchestrationDir, map[string]interface{}{
+		"sourcePath":      "app.conf",
+		"destinationPath": destination,
+		"checksum":        "sha256:" + checksumOf("port=80"),
+		"fileMode":        "0644",
+	})
+
+	assert.Equal(t, contracts.ResultStatusSuccess, output.GetStatus(), output.GetStderr())
+	assert.Contains(t, output.GetStdout(), checksumOf("port=80"))
+	content, _ := os.ReadFile(destination)
+	assert.Equal(t, "port=80", string(content))
+	assert.FileExists(t, source)
+	if runtime.GOOS != "windows" {
+		info, _ := os.Stat(destination)
+		assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+	}
+}
+
+func TestExecute_ChecksumMismatchKeepsDestination(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "app.conf")
+	destination := filepath.Join(dir, "installed.conf")
+	os.WriteFile(source, []byte("tampered"), 0600)
+	os.WriteFile(destination, []byte("previous"), 0600)
+
+	output := runPlugin(t, dir, map[string]interface{}{
+		"sourcePath":      source,
+		"destinationPath": destination,
+		"checksum":        checksumOf("expected"),
+		"overwrite":       true,
+	})
+
+	assert.Equal(t, contracts.ResultStatusFailed, output.GetStatus())
+	assert.Contains(t, output.GetStderr(), "checksum mismatch")
+	content, _ := os.ReadFile(destination)
+	assert.Equal(t, "previous", string(content))
+	entries, _ := os.ReadDir(dir)
+	assert.Len(t, entries, 2, "the temporary file is removed")
+}
+
+func TestExecute_RequiresOverwriteForDifferentContent(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "app.conf")
+	destination := filepath.Join(dir, "installed.conf")
+	os.WriteFile(source, []byte("new"), 0600)
+	os.WriteFile(destination, []byte("previous"), 0600)
+	properties := map[string]interface{}{"sourcePath": source, "destinationPath": destination}
+
+	output := runPlugin(t, dir, properties)
+	assert.Equal(t, contracts.ResultStatusFailed, output.GetStatus())
+	assert.Contains(t, output.GetStderr(), "set Overwrite")
+
+	properties["overwrite"] = true
+	output = runPlugin(t, dir, properties)
+	assert.Equal(t, contracts.ResultStatusSuccess, output.GetStatus(), output.GetStderr())
+	content, _ := os.ReadFile(destination)
+	assert.Equal(t, "new", string(content))
+
+	// copying identical content again succeeds without overwrite
+	delete(properties, "overwrite")
+	output = runPlugin(t, dir, properties)
+	assert.Equal(t, contracts.ResultStatusSuccess, output.GetStatus(), output.GetStderr())
+}
+
+func TestExecute_MovesDirectoryTree(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "release")
+	os.MkdirAll(filepath.Join(source, "bin"), 0700)
+	os.WriteFile(filepath.Join(source, "bin", "app"), []byte("binary"), 0700)
+	os.WriteFile(filepath.Join(source, "app.conf"), []byte("port=80"), 0600)
+	destination := filepath.Join(dir, "opt", "app")
+
+	output := runPlugin(t, dir, map[string]interface{}{
+		"sourcePath":      source,
+		"destinationPath": destination,
+		"move":            true,
+	})
+
+	assert.Equal(t, contracts.ResultStatusSuccess, output.GetStatus(), output.GetStderr())
+	assert.Contains(t, output.GetStdout(), "Copied 2 files")
+	content, _ := os.ReadFile(filepath.Join(destination, "bin", "app"))
+	assert.Equal(t, "binary", string(content))
+	assert.FileExists(t, filepath.Join(destination, "app.conf"))
+	assert.NoDirExists(t, source)
+}
+
+func TestExecute_ChecksumNotSupportedForDirectory(t *testing.T) {
+	dir := t.TempDir()
+
+	output := runPlugin(t, dir, map[string]interface{}{
+		"sourcePath":      dir,
+		"destinationPath": filepath.Join(t.TempDir(), "copy"),
+		"checksum":        checksumOf("x"),
+	})
+
+	assert.Equal(t, contracts.ResultStatusFailed, output.GetStatus())
+	assert.Contains(t, output.GetStderr(), "only supported when SourcePath is a file")
+}
@@ -0,0 +1,55 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build darwin || freebsd || linux || netbsd || openbsd
+// +build darwin freebsd linux netbsd openbsd
+
+package copyfile
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+)
+
+func validateOwnership(owner, group string) error {
+	return nil
+}
+
+// changeOwner sets the owner and group of the path by name or numeric id, an empty value is left unchanged
+func changeOwner(path, owner, group string) error {
+	if owner == "" && group == "" {
+		return nil
+	}
+	uid, gid := -1, -1
+	if owner != "" {
+		u, err := user.Lookup(owner)
+		if err != nil {
+			if u, err = user.LookupId(owner); err != nil {
+				return fmt.Errorf("unknown owner %s", owner)
+			}
+		}
+		uid, _ = strconv.Atoi(u.Uid)
+	}
+	if group != "" {
+		g, err := user.LookupGroup(group)
+		if err != nil {
+			if g, err = user.LookupGroupId(group); err != nil {
+				return fmt.Errorf("unknown group %s", group)
+			}
+		}
+		gid, _ = strconv.Atoi(g.Gid)
+	}
+	return os.Lchown(path, uid, gid)
+}
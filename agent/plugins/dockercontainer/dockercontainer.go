@@ -292,10 +292,11 @@ func (p *Plugin) runCommands(pluginID string, pluginInput DockerContainerPluginI
 	executionTimeout := pluginutil.ValidateExecutionTimeout(log, pluginInput.TimeoutSeconds)
 
 	// Execute Command
-	exitCode, err := p.CommandExecuter.NewExecute(p.context, pluginInput.WorkingDirectory, output.GetStdoutWriter(), output.GetStderrWriter(), cancelFlag, executionTimeout, commandName, commandArguments, make(map[string]string))
+	exitCode, accounting, err := p.CommandExecuter.NewExecute(p.context, pluginInput.WorkingDirectory, output.GetStdoutWriter(), output.GetStderrWriter(), cancelFlag, executionTimeout, commandName, commandArguments, make(map[string]string))
 
 	// Set output status
 	output.SetExitCode(exitCode)
+	output.AddProcessAccounting(accounting)
 	output.SetStatus(pluginutil.GetStatus(exitCode, cancelFlag))
 
 	if err != nil {
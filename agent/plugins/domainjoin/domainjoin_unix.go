@@ -66,6 +66,12 @@ const (
 	SetHostNameArg = " --set-hostname "
 	// SetHostNameNumAppendDigits is an optional argument to set hostname name after domain join
 	SetHostNameNumAppendDigitsArg = " --set-hostname-append-num-digits "
+	// ClientSoftwareArg represents the realmd client software used to join the domain
+	ClientSoftwareArg = " --client-software "
+	// ClientSoftwareWinbind joins the domain with Samba winbind, the default
+	ClientSoftwareWinbind = "winbind"
+	// ClientSoftwareSssd joins the domain with SSSD, templating krb5.conf and sssd.conf and validating the keytab
+	ClientSoftwareSssd = "sssd"
 )
 
 // Makes command as variables, so that we can mock this for unit tests
@@ -89,6 +95,9 @@ type DomainJoinPluginInput struct {
 	HostName                string
 	HostNameNumAppendDigits string
 	KeepHostName            bool
+	ClientSoftware          string
+	// OfflineDomainJoinBlob is only supported on Windows
+	OfflineDomainJoinBlob string
 }
 
 // NewPlugin returns a new instance of the plugin.
@@ -177,6 +186,10 @@ func (p *Plugin) runCommands(pluginID string, pluginInput DomainJoinPluginInput,
 	var err error
 	var scriptPath string
 	log := p.context.Log()
+	if len(pluginInput.OfflineDomainJoinBlob) != 0 {
+		out.MarkAsFailed(fmt.Errorf("offlineDomainJoinBlob is only supported on Windows"))
+		return
+	}
 	if scriptPath, err = createOrchesDir(log, orchestrationDirectory, pluginInput); err != nil {
 		out.MarkAsFailed(fmt.Errorf("Failed to create orchestration directory because : %v", err.Error()))
 		return
@@ -318,6 +331,15 @@ func makeArguments(context context.T, scriptPath string, pluginInput DomainJoinP
 		buffer.WriteString(" ")
 	}
 
+	switch pluginInput.ClientSoftware {
+	case "", ClientSoftwareWinbind:
+	case ClientSoftwareSssd:
+		buffer.WriteString(ClientSoftwareArg)
+		buffer.WriteString(ClientSoftwareSssd)
+	default:
+		return "", fmt.Errorf("clientSoftware must be %v or %v", ClientSoftwareWinbind, ClientSoftwareSssd)
+	}
+
 	if len(pluginInput.DnsIpAddresses) == 0 {
 		log.Debug("Do not provide dns addresses.")
 		return buffer.String(), nil
@@ -64,6 +64,9 @@ SET_HOSTNAME_APPEND_NUM_DIGITS=""
 MAX_APPEND_DIGITS=5
 RESOLVED_DNS_IP_ADDRESSES=""
 UNMUTATED_DNS_RESOLVE_STATUS=1
+# realmd client software, winbind or sssd
+CLIENT_SOFTWARE="winbind"
+KEYTAB_FILE=/etc/krb5.keytab
 
 # NetBIOS computer names consist of up to 15 bytes of OEM characters
 # https://docs.microsoft.com/en-us/windows/win32/sysinfo/computer-names?redirectedfrom=MSDN
@@ -857,9 +860,9 @@ do_domainjoin() {
     for i in $(seq 1 $MAX_RETRIES)
     do
         if [ -z "$DIRECTORY_OU" ]; then
-            LOG_MSG=$(echo $DOMAIN_PASSWORD | realm join --client-software=winbind -U ${DOMAIN_USERNAME} "$DIRECTORY_NAME" -v 2>&1)
+            LOG_MSG=$(echo $DOMAIN_PASSWORD | realm join --client-software=$CLIENT_SOFTWARE -U ${DOMAIN_USERNAME} "$DIRECTORY_NAME" -v 2>&1)
         else
-            LOG_MSG=$(echo $DOMAIN_PASSWORD | realm join --client-software=winbind -U ${DOMAIN_USERNAME} "$DIRECTORY_NAME" --computer-ou="$DIRECTORY_OU" -v 2>&1)
+            LOG_MSG=$(echo $DOMAIN_PASSWORD | realm join --client-software=$CLIENT_SOFTWARE -U ${DOMAIN_USERNAME} "$DIRECTORY_NAME" --computer-ou="$DIRECTORY_OU" -v 2>&1)
         fi
         if echo "$LOG_MSG" | grep -q "Already joined to this domain"; then
              echo "do_domainjoin(): Already joined to this domain : $LOG_MSG"
@@ -880,7 +883,7 @@ do_domainjoin() {
     fi
 
     id "$DOMAIN_USERNAME"
-    if [ $? -ne 0 ]; then
+    if [ $? -ne 0 ] && [ "$CLIENT_SOFTWARE" = "winbind" ]; then
        fix_idmap_ranges
     fi
 
@@ -964,6 +967,129 @@ fix_idmap_ranges() {
     fi
 }
 
+##################################################
+## Install SSSD components #######################
+##################################################
+install_sssd_components() {
+    if [ -x "$(command -v yum)" ]; then
+        check_for_write_protect yum
+        yum -y install sssd sssd-tools sssd-ad oddjob-mkhomedir krb5-workstation >/dev/null
+    elif [ -x "$(command -v apt-get)" ]; then
+        check_for_write_protect apt-get
+        DEBIAN_FRONTEND=noninteractive apt-get -yq install sssd sssd-tools libnss-sss libpam-sss krb5-user >/dev/null
+    elif [ -x "$(command -v zypper)" ]; then
+        check_for_write_protect zypper
+        zypper -n -q install sssd sssd-tools sssd-ad krb5-client
+    else
+        echo "install_sssd_components(): no supported package manager found"
+        return 1
+    fi
+}
+
+##################################################
+## Write krb5.conf for the directory #############
+##################################################
+config_krb5() {
+    if [ -f /etc/krb5.conf ]; then
+        cp /etc/krb5.conf /etc/krb5.conf.pre-join
+    fi
+
+    cat > /etc/krb5.conf <<EOF
+[libdefaults]
+    default_realm = ${REALM}
+    dns_lookup_realm = false
+    dns_lookup_kdc = true
+    rdns = false
+    ticket_lifetime = 24h
+    renew_lifetime = 7d
+    forwardable = true
+
+[realms]
+    ${REALM} = {
+    }
+
+[domain_realm]
+    .${DIRECTORY_NAME} = ${REALM}
+    ${DIRECTORY_NAME} = ${REALM}
+EOF
+    if [ $? -ne 0 ]; then echo "***Failed: config_krb5(): unable to write /etc/krb5.conf" && exit 1; fi
+}
+
+##################################################
+## Write sssd.conf for the joined directory ######
+##################################################
+config_sssd() {
+    SSSD_CONF_FILE=/etc/sssd/sssd.conf
+    if [ -f $SSSD_CONF_FILE ]; then
+        cp $SSSD_CONF_FILE $SSSD_CONF_FILE.pre-join
+    fi
+
+    mkdir -p /etc/sssd
+    cat > $SSSD_CONF_FILE <<EOF
+[sssd]
+domains = ${DIRECTORY_NAME}
+config_file_version = 2
+services = nss, pam
+
+[domain/${DIRECTORY_NAME}]
+id_provider = ad
+access_provider = ad
+ad_domain = ${DIRECTORY_NAME}
+krb5_realm = ${REALM}
+realmd_tags = manages-system joined-with-adcli
+cache_credentials = True
+krb5_store_password_if_offline = True
+default_shell = /bin/bash
+ldap_id_mapping = True
+use_fully_qualified_names = True
+fallback_homedir = /home/%u@%d
+krb5_keytab = ${KEYTAB_FILE}
+EOF
+    if [ $? -ne 0 ]; then echo "***Failed: config_sssd(): unable to write $SSSD_CONF_FILE" && exit 1; fi
+    # sssd refuses to start when its configuration is readable by others
+    chown root:root $SSSD_CONF_FILE
+    chmod 600 $SSSD_CONF_FILE
+
+    if [ -x "$(command -v authselect)" ]; then
+        authselect select sssd with-mkhomedir --force
+    elif [ -x "$(command -v pam-auth-update)" ]; then
+        check_for_write_protect pam-auth-update
+        pam-auth-update --enable mkhomedir
+    fi
+
+    check_for_write_protect systemctl
+    systemctl enable sssd
+    systemctl restart sssd
+    if [ $? -ne 0 ]; then
+        echo "***Failed: config_sssd(): unable to restart sssd" && exit 1
+    fi
+}
+
+##################################################
+## Validate the machine account keytab ###########
+##################################################
+validate_keytab() {
+    if [ ! -s $KEYTAB_FILE ]; then
+        echo "***Failed: validate_keytab(): $KEYTAB_FILE is missing or empty" && exit 1
+    fi
+
+    check_for_write_protect klist
+    check_for_write_protect kinit
+    COMPUTER_PRINCIPAL="$(echo $COMPUTER_NAME | tr [a-z] [A-Z])\$@${REALM}"
+    if ! klist -k $KEYTAB_FILE | grep -qi "$COMPUTER_PRINCIPAL"; then
+        echo "***Failed: validate_keytab(): $COMPUTER_PRINCIPAL not found in $KEYTAB_FILE" && exit 1
+    fi
+
+    # Request a ticket with the keytab to make sure the key matches the computer account
+    KRB5CCNAME="FILE:$AWS_CLI_INSTALL_DIR/krb5cc_validate" kinit -k -t $KEYTAB_FILE "$COMPUTER_PRINCIPAL"
+    STATUS=$?
+    rm -f "$AWS_CLI_INSTALL_DIR/krb5cc_validate"
+    if [ $STATUS -ne 0 ]; then
+        echo "***Failed: validate_keytab(): kinit with $KEYTAB_FILE failed for $COMPUTER_PRINCIPAL" && exit 1
+    fi
+    echo "Validated keytab $KEYTAB_FILE for $COMPUTER_PRINCIPAL"
+}
+
 ##################################################
 ## Main entry point ##############################
 ##################################################
@@ -1028,6 +1154,11 @@ for i in "$@"; do
             SET_HOSTNAME_APPEND_NUM_DIGITS="$1"
             continue
             ;;
+        --client-software)
+            shift;
+            CLIENT_SOFTWARE="$1"
+            continue
+            ;;
     esac
     shift
 done
@@ -1036,6 +1167,10 @@ if [ -z $REGION ]; then
     echo "***Failed: No Region found" && exit 1
 fi
 
+if [ "$CLIENT_SOFTWARE" != "winbind" ] && [ "$CLIENT_SOFTWARE" != "sssd" ]; then
+    echo "***Failed: Unsupported client software $CLIENT_SOFTWARE" && exit 1
+fi
+
 if [ ! -z $SET_HOSTNAME_APPEND_NUM_DIGITS ]; then
     if [ $SET_HOSTNAME_APPEND_NUM_DIGITS -le 0 -o \
          $SET_HOSTNAME_APPEND_NUM_DIGITS -gt $MAX_APPEND_DIGITS ]; then
@@ -1067,6 +1202,11 @@ do
     sleep 30
 done
 
+if [ "$CLIENT_SOFTWARE" = "sssd" ]; then
+    install_sssd_components
+    if [ $? -ne 0 ]; then echo "***Failed: unable to install SSSD components" && exit 1; fi
+fi
+
 resolve_name_to_ip $DIRECTORY_NAME
 
 # Modify DNS config only if DNS resolution does not already work
@@ -1113,14 +1253,19 @@ fi
 
 print_vars
 is_directory_reachable
-if [ $? -eq 0 ]; then
+if [ $? -ne 0 ]; then
+    echo "**Failed: Unable to reach DNS server"
+    exit 1
+elif [ "$CLIENT_SOFTWARE" = "sssd" ]; then
+    config_krb5
+    do_domainjoin
+    config_sssd
+    validate_keytab
+else
     config_nsswitch
     config_samba
     do_domainjoin
     reconfigure_samba
-else
-    echo "**Failed: Unable to reach DNS server"
-    exit 1
 fi
 
 echo "Success"
@@ -406,3 +406,37 @@ func TestMakeArgumentsAndCommandParts(t *testing.T) {
 	shellInjectionCheck = isShellInjection("echo abc ; rm *")
 	assert.Equal(t, shellInjectionCheck, true, "test failed for echo abc ; rm *")
 }
+
+func TestRunCommandsOfflineDomainJoinNotSupported(t *testing.T) {
+	mockIOHandler := new(iohandlermocks.MockIOHandler)
+	mockIOHandler.On("MarkAsFailed", mock.Anything).Return()
+	p := &Plugin{
+		context: contextmocks.NewMockDefault(),
+	}
+	pluginInput := generateDomainJoinPluginInput(testDirectoryId, testDirectoryName, "", nil)
+	pluginInput.OfflineDomainJoinBlob = "{{ ssm-secure:odj-blob }}"
+
+	p.runCommands("domainjoin", pluginInput, orchestrationDirectory, new(task.MockCancelFlag), mockIOHandler, utilExe)
+
+	mockIOHandler.AssertCalled(t, "MarkAsFailed", mock.Anything)
+	mockIOHandler.AssertNotCalled(t, "SetStatus", mock.Anything)
+}
+
+func TestMakeArgumentsClientSoftware(t *testing.T) {
+	context := contextmocks.NewMockDefault()
+	domainJoinInput := generateDomainJoinPluginInput(testDirectoryId, testDirectoryName, "", []string{})
+
+	domainJoinInput.ClientSoftware = ClientSoftwareWinbind
+	commandLine, err := makeArguments(context, "./aws_domainjoin.sh", domainJoinInput)
+	assert.NoError(t, err)
+	assert.Equal(t, "./aws_domainjoin.sh --directory-id d-0123456789 --directory-name corp.test.com --instance-region us-east-1", commandLine)
+
+	domainJoinInput.ClientSoftware = ClientSoftwareSssd
+	commandLine, err = makeArguments(context, "./aws_domainjoin.sh", domainJoinInput)
+	assert.NoError(t, err)
+	assert.Equal(t, "./aws_domainjoin.sh --directory-id d-0123456789 --directory-name corp.test.com --instance-region us-east-1 --client-software sssd", commandLine)
+
+	domainJoinInput.ClientSoftware = "sssd; reboot"
+	_, err = makeArguments(context, "./aws_domainjoin.sh", domainJoinInput)
+	assert.Error(t, err)
+}
@@ -19,9 +19,15 @@ package domainjoin
 
 import (
 	"bytes"
+	"encoding/base64"
+	"encoding/binary"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
+	"unicode/utf16"
 
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
 	"github.com/aws/amazon-ssm-agent/agent/context"
@@ -31,6 +37,7 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/jsonutil"
 	"github.com/aws/amazon-ssm-agent/agent/log"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/pluginutil"
+	"github.com/aws/amazon-ssm-agent/agent/ssm/ssmparameterresolver"
 	"github.com/aws/amazon-ssm-agent/agent/task"
 	"github.com/aws/amazon-ssm-agent/agent/updateutil"
 )
@@ -58,12 +65,20 @@ const (
 	// Default folder name for domain join plugin
 	DomainJoinFolderName = "awsDomainJoin"
 	SetHostNameArg       = " --set-hostname "
+	// OfflineDomainJoinBlobFileName is the name of the file the offline domain join blob is loaded from
+	OfflineDomainJoinBlobFileName = "odjblob.txt"
 )
 
 // Makes command as variables, so that we can mock this for unit tests
 var makeDir = fileutil.MakeDirs
 var makeArgs = makeArguments
 var utilExe convert
+var getSecureParameter = getSecureParameterValue
+var writeBlobFile = writeOfflineDomainJoinBlob
+
+// secureParameterPattern matches the {{ ssm-secure:parameter-name }} reference of the offline domain join blob,
+// secrets are referenced through the /aws/reference/secretsmanager/secret-id parameter name
+var secureParameterPattern = regexp.MustCompile(`^{{\s*ssm-secure:([\w-./]+)\s*}}$`)
 
 // Plugin is the type for the domain join plugin.
 type Plugin struct {
@@ -78,6 +93,8 @@ type DomainJoinPluginInput struct {
 	DirectoryOU    string
 	DnsIpAddresses []string
 	HostName       string
+	// OfflineDomainJoinBlob references the secure parameter holding a blob created by djoin.exe /provision
+	OfflineDomainJoinBlob string
 }
 
 // NewPlugin returns a new instance of the plugin.
@@ -152,6 +169,11 @@ func (p *Plugin) runCommands(pluginID string, pluginInput DomainJoinPluginInput,
 		return
 	}
 
+	if len(pluginInput.OfflineDomainJoinBlob) != 0 {
+		p.runOfflineDomainJoin(pluginInput, orchestrationDirectory, out, utilExe)
+		return
+	}
+
 	// Construct Command line with executable file name and parameters
 	var command string
 	if command, err = makeArgs(p.context, pluginInput); err != nil {
@@ -199,6 +221,85 @@ func (p *Plugin) runCommands(pluginID string, pluginInput DomainJoinPluginInput,
 	return
 }
 
+// runOfflineDomainJoin requests the offline domain join of the instance with a djoin.exe provisioning blob.
+// Windows completes the join on the next boot without contacting a domain controller.
+func (p *Plugin) runOfflineDomainJoin(pluginInput DomainJoinPluginInput, orchestrationDirectory string, out iohandler.IOHandler, utilExe convert) {
+	log := p.context.Log()
+	match := secureParameterPattern.FindStringSubmatch(strings.TrimSpace(pluginInput.OfflineDomainJoinBlob))
+	if match == nil {
+		out.MarkAsFailed(fmt.Errorf("Format of offlineDomainJoinBlob is incorrect. Please specify the blob as '{{ ssm-secure:parameter-name }}'"))
+		return
+	}
+
+	// NOTE: Do not log the blob, it contains the password of the computer account
+	blob, err := getSecureParameter(p.context, match[1])
+	if err != nil {
+		out.MarkAsFailed(fmt.Errorf("Failed to get the offline domain join blob because : %v", err.Error()))
+		return
+	}
+
+	blobPath := filepath.Join(orchestrationDirectory, OfflineDomainJoinBlobFileName)
+	if err = writeBlobFile(blobPath, blob); err != nil {
+		out.MarkAsFailed(fmt.Errorf("Failed to write the offline domain join blob because : %v", err.Error()))
+		return
+	}
+	defer func() {
+		if err := fileutil.DeleteFile(blobPath); err != nil {
+			log.Warnf("Failed to delete the offline domain join blob %v: %v", blobPath, err)
+		}
+	}()
+
+	windowsPath := os.Getenv("SystemRoot")
+	djoinPath := filepath.Join(windowsPath, "System32", "djoin.exe")
+	out.SetStatus(contracts.ResultStatusInProgress)
+	if _, err = utilExe(log,
+		djoinPath,
+		[]string{"/requestODJ", "/loadfile", blobPath, "/windowspath", windowsPath, "/localos"},
+		orchestrationDirectory,
+		orchestrationDirectory,
+		out.GetStdoutWriter(),
+		out.GetStderrWriter(),
+		true); err != nil {
+		out.MarkAsFailed(err)
+		return
+	}
+
+	out.AppendInfo("Offline domain join requested, the instance joins the domain after the reboot.")
+	out.MarkAsSuccessWithReboot()
+}
+
+// getSecureParameterValue returns the value of the secure string parameter with the given name
+func getSecureParameterValue(context context.T, parameterName string) (string, error) {
+	reference := "ssm-secure:" + parameterName
+	parameters, err := ssmparameterresolver.ResolveParameterReferenceList(
+		ssmparameterresolver.NewService(context),
+		context.Log(),
+		[]string{reference},
+		ssmparameterresolver.ResolveOptions{IgnoreSecureParameters: false})
+	if err != nil {
+		return "", err
+	}
+	parameter, found := parameters[reference]
+	if !found {
+		return "", fmt.Errorf("parameter %v cannot be resolved", parameterName)
+	}
+	return parameter.Value, nil
+}
+
+// writeOfflineDomainJoinBlob validates the base64 blob and writes it as UTF-16LE text, the encoding djoin.exe /provision uses
+func writeOfflineDomainJoinBlob(blobPath string, blob string) error {
+	blob = strings.TrimSpace(blob)
+	if _, err := base64.StdEncoding.DecodeString(blob); err != nil {
+		return fmt.Errorf("the blob is not base64 encoded")
+	}
+	encoded := utf16.Encode([]rune(blob + "\x00"))
+	content := make([]byte, 2*len(encoded))
+	for i, char := range encoded {
+		binary.LittleEndian.PutUint16(content[2*i:], char)
+	}
+	return os.WriteFile(blobPath, content, 0600)
+}
+
 // makeArguments Build the arguments for domain join plugin
 func makeArguments(context context.T, pluginInput DomainJoinPluginInput) (commandArguments string, err error) {
 	log := context.Log()
@@ -20,6 +20,8 @@ package domainjoin
 import (
 	"errors"
 	"io"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/aws/amazon-ssm-agent/agent/context"
@@ -232,3 +234,60 @@ func TestMakeArgumentsAndCommandParts(t *testing.T) {
 	shellInjectionCheck = isShellInjection("echo abc ; del /Q *")
 	assert.Equal(t, shellInjectionCheck, true, "test failed for echo abc ; del /Q *")
 }
+
+func TestRunCommandsOfflineDomainJoin(t *testing.T) {
+	var djoinArgs []string
+	utilExe = func(_ log.T, _ string, args []string, _ string, _ string, _ io.Writer, _ io.Writer, _ bool) (string, error) {
+		djoinArgs = args
+		return "", nil
+	}
+	makeDir = func(destinationDir string) (err error) {
+		return nil
+	}
+	getSecureParameter = func(_ context.T, parameterName string) (string, error) {
+		assert.Equal(t, "/aws/reference/secretsmanager/odj-blob", parameterName)
+		return "ARAIAMzMzMwIAAAAAAAAAAAAAgABAAAA", nil
+	}
+	defer func() { getSecureParameter = getSecureParameterValue }()
+
+	orchestrationDir := t.TempDir()
+	input := generateDomainJoinPluginInput(testDirectoryId, testDirectoryName, "", nil)
+	input.OfflineDomainJoinBlob = "{{ ssm-secure:/aws/reference/secretsmanager/odj-blob }}"
+	p := &Plugin{
+		context: contextmocks.NewMockDefault(),
+	}
+	output := iohandler.NewDefaultIOHandler(p.context, contracts.IOConfiguration{})
+
+	p.runCommands("-", input, orchestrationDir, new(task.MockCancelFlag), output, utilExe)
+
+	blobPath := filepath.Join(orchestrationDir, OfflineDomainJoinBlobFileName)
+	assert.Equal(t, []string{"/requestODJ", "/loadfile", blobPath, "/windowspath", os.Getenv("SystemRoot"), "/localos"}, djoinArgs)
+	assert.Equal(t, contracts.ResultStatusSuccessAndReboot, output.GetStatus())
+	assert.NoFileExists(t, blobPath)
+}
+
+func TestRunCommandsOfflineDomainJoinInvalidReference(t *testing.T) {
+	makeDir = func(destinationDir string) (err error) {
+		return nil
+	}
+	input := generateDomainJoinPluginInput(testDirectoryId, testDirectoryName, "", nil)
+	input.OfflineDomainJoinBlob = "ARAIAMzMzMwIAAAAAAAAAAAAAgABAAAA"
+	p := &Plugin{
+		context: contextmocks.NewMockDefault(),
+	}
+	output := iohandler.NewDefaultIOHandler(p.context, contracts.IOConfiguration{})
+
+	p.runCommands("-", input, t.TempDir(), new(task.MockCancelFlag), output, utilExe)
+
+	assert.Equal(t, contracts.ResultStatusFailed, output.GetStatus())
+}
+
+func TestWriteOfflineDomainJoinBlob(t *testing.T) {
+	blobPath := filepath.Join(t.TempDir(), OfflineDomainJoinBlobFileName)
+	assert.Error(t, writeOfflineDomainJoinBlob(blobPath, "not a blob"))
+
+	assert.NoError(t, writeOfflineDomainJoinBlob(blobPath, " QUJD\r\n"))
+	content, err := os.ReadFile(blobPath)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{'Q', 0, 'U', 0, 'J', 0, 'D', 0, 0, 0}, content)
+}
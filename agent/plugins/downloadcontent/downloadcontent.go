@@ -36,19 +36,23 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/plugins/downloadcontent/gitresource/github/privategithub"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/downloadcontent/gitresource/privategit"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/downloadcontent/httpresource"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/downloadcontent/ociresource"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/downloadcontent/remoteresource"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/downloadcontent/s3resource"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/downloadcontent/ssmdocresource"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/downloadcontent/ssmparameterresource"
 	"github.com/aws/amazon-ssm-agent/agent/ssm/ssmparameterresolver"
 	"github.com/aws/amazon-ssm-agent/agent/task"
 )
 
 const (
-	Git         = "Git"         //Git represents any arbitrary "Git" repository from where the resource can be downloaded
-	HTTP        = "HTTP"        //HTTP represents any arbitrary URL from where the resource can be downloaded
-	GitHub      = "GitHub"      //Github represents the source type "GitHub" from where the resource can be downloaded
-	S3          = "S3"          //S3 represents the source type "S3" from where the resource is being downloaded
-	SSMDocument = "SSMDocument" //SSMDocument represents the source type as SSM Document
+	Git          = "Git"          //Git represents any arbitrary "Git" repository from where the resource can be downloaded
+	HTTP         = "HTTP"         //HTTP represents any arbitrary URL from where the resource can be downloaded
+	GitHub       = "GitHub"       //Github represents the source type "GitHub" from where the resource can be downloaded
+	S3           = "S3"           //S3 represents the source type "S3" from where the resource is being downloaded
+	SSMDocument  = "SSMDocument"  //SSMDocument represents the source type as SSM Document
+	OCI          = "OCI"          //OCI represents an artifact in ECR or any other OCI registry
+	SSMParameter = "SSMParameter" //SSMParameter represents a parameter in Parameter Store saved to a file
 
 	downloadsDir = "downloads" //Directory under the orchestration directory where the downloaded resource resides
 
@@ -57,11 +61,13 @@ const (
 )
 
 var sourceTypes = map[string]bool{
-	Git:         true,
-	HTTP:        true,
-	GitHub:      true,
-	S3:          true,
-	SSMDocument: true,
+	Git:          true,
+	HTTP:         true,
+	GitHub:       true,
+	S3:           true,
+	SSMDocument:  true,
+	OCI:          true,
+	SSMParameter: true,
 }
 
 var SetPermission = SetFilePermissions
@@ -106,6 +112,10 @@ func newRemoteResource(context context.T, SourceType string, SourceInfo string)
 	case HTTP:
 		ssmParameterResolverBridge := ssmparameterresolver.NewSsmParameterResolverBridge(ssmparameterresolver.NewService(context))
 		return httpresource.NewHTTPResource(context, SourceInfo, ssmParameterResolverBridge)
+	case SSMParameter:
+		return ssmparameterresource.NewSSMParameterResource(context, SourceInfo)
+	case OCI:
+		return ociresource.NewOCIResource(context, SourceInfo)
 	case Git:
 		ssmParameterResolverBridge := ssmparameterresolver.NewSsmParameterResolverBridge(ssmparameterresolver.NewService(context))
 		return privategit.NewGitResource(context, SourceInfo, ssmParameterResolverBridge)
@@ -132,12 +142,12 @@ func (p *Plugin) execute(config contracts.Configuration, cancelFlag task.CancelF
 	} else if input, err := parseAndValidateInput(config.Properties); err != nil {
 		output.MarkAsFailed(err)
 	} else {
-		p.runCopyContent(log, input, config, output)
+		p.runCopyContent(log, input, config, cancelFlag, output)
 	}
 }
 
 // runCopyContent figures out the type of source, downloads the resource, saves it on disk and returns information required for it
-func (p *Plugin) runCopyContent(log log.T, input *DownloadContentPlugin, config contracts.Configuration, output iohandler.IOHandler) {
+func (p *Plugin) runCopyContent(log log.T, input *DownloadContentPlugin, config contracts.Configuration, cancelFlag task.CancelFlag, output iohandler.IOHandler) {
 
 	//Run aws:downloadContent plugin
 	log.Debug("Inside run downloadcontent function")
@@ -169,10 +179,21 @@ func (p *Plugin) runCopyContent(log log.T, input *DownloadContentPlugin, config
 		return
 	}
 
+	if cancelable, ok := remoteResource.(remoteresource.CancelableResource); ok {
+		// cancelling the command interrupts the download instead of waiting for it to complete
+		requestCtx, cancel := task.NewCancelContext(cancelFlag)
+		defer cancel()
+		cancelable.SetRequestContext(requestCtx)
+	}
+
 	var result *remoteresource.DownloadResult
 	log.Debug("Downloading resource")
 
 	if err, result = remoteResource.DownloadRemoteResource(p.filesys, destinationPath); err != nil {
+		if cancelFlag.ShutDown() || cancelFlag.Canceled() {
+			markAsInterrupted(log, cancelFlag, result, output)
+			return
+		}
 		output.MarkAsFailed(err)
 		return
 	}
@@ -183,11 +204,38 @@ func (p *Plugin) runCopyContent(log log.T, input *DownloadContentPlugin, config
 	}
 
 	output.AppendInfof("Content downloaded to %v", destinationPath)
+	if len(result.Metadata) > 0 {
+		// subsequent steps can branch on the metadata of the artifacts without calling the source again
+		if metadata, err := jsonutil.Marshal(result.Metadata); err != nil {
+			log.Warnf("Failed to marshal object metadata: %v", err)
+		} else {
+			output.AppendInfof("Object metadata: %v", metadata)
+		}
+	}
 	output.MarkAsSucceeded()
 	return
 }
 
+// markAsInterrupted reports the files downloaded before the command was cancelled or the agent shut down
+func markAsInterrupted(log log.T, cancelFlag task.CancelFlag, result *remoteresource.DownloadResult, output iohandler.IOHandler) {
+	if result != nil && len(result.Files) > 0 {
+		output.AppendInfof("Download interrupted after %v file(s): %v", len(result.Files), strings.Join(result.Files, ", "))
+	} else {
+		output.AppendInfo("Download interrupted before any file was downloaded")
+	}
+	if cancelFlag.ShutDown() {
+		log.Info("Download interrupted by agent shutdown")
+		output.MarkAsShutdown()
+	} else {
+		log.Info("Download interrupted by command cancellation")
+		output.MarkAsCancelled()
+	}
+}
+
 func setPermissions(log log.T, result *remoteresource.DownloadResult) error {
+	if result.PermissionsApplied {
+		return nil
+	}
 	for _, path := range result.Files {
 		log.Infof("Setting permission for file %v", path)
 		if fileutil.IsDirectory(path) {
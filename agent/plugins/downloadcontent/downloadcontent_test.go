@@ -16,6 +16,7 @@
 package downloadcontent
 
 import (
+	reqContext "context"
 	"encoding/json"
 	"errors"
 	"os"
@@ -25,6 +26,7 @@ import (
 
 	"github.com/aws/amazon-ssm-agent/agent/context"
 	"github.com/aws/amazon-ssm-agent/agent/contracts"
+	"github.com/aws/amazon-ssm-agent/agent/fileutil/filemanager"
 	filemock "github.com/aws/amazon-ssm-agent/agent/fileutil/filemanager/mock"
 	iohandlermocks "github.com/aws/amazon-ssm-agent/agent/framework/processor/executer/iohandler/mock"
 	"github.com/aws/amazon-ssm-agent/agent/log"
@@ -97,6 +99,31 @@ func TestNewRemoteResource_S3(t *testing.T) {
 
 }
 
+func TestNewRemoteResource_OCI(t *testing.T) {
+
+	locationInfo := `{
+		"reference" : "123456789012.dkr.ecr.us-east-1.amazonaws.com/bundles/config:1.2"
+		}`
+	remoteresource, err := newRemoteResource(contextMock, "OCI", locationInfo)
+
+	assert.NotNil(t, remoteresource)
+	assert.NoError(t, err)
+
+}
+
+func TestNewRemoteResource_SSMParameter(t *testing.T) {
+
+	locationInfo := `{
+		"name" : "/app/prod/tls-key",
+		"fileMode" : "0600"
+		}`
+	remoteresource, err := newRemoteResource(contextMock, "SSMParameter", locationInfo)
+
+	assert.NotNil(t, remoteresource)
+	assert.NoError(t, err)
+
+}
+
 func TestNewRemoteResource_SSMDocument(t *testing.T) {
 
 	locationInfo := `{
@@ -130,13 +157,57 @@ func TestNewPlugin_RunCopyContent(t *testing.T) {
 	mockIOHandler.On("MarkAsSucceeded").Return()
 
 	SetPermission = stubChmod
-	p.runCopyContent(logger, &input, config, mockIOHandler)
+	p.runCopyContent(logger, &input, config, task.NewChanneledCancelFlag(), mockIOHandler)
 
 	copyContentResourceMock.AssertExpectations(t)
 	fileMock.AssertExpectations(t)
 	mockIOHandler.AssertExpectations(t)
 }
 
+// cancelableResource is a remote resource whose download runs until its request context is cancelled
+type cancelableResource struct {
+	resourcemock.RemoteResourceMock
+	requestCtx reqContext.Context
+}
+
+func (resource *cancelableResource) SetRequestContext(requestCtx reqContext.Context) {
+	resource.requestCtx = requestCtx
+}
+
+func (resource *cancelableResource) DownloadRemoteResource(_ filemanager.FileSystem, _ string) (error, *remoteresource.DownloadResult) {
+	<-resource.requestCtx.Done()
+	return resource.requestCtx.Err(), resourcemock.NewDownloadResult([]string{"file1"})
+}
+
+func TestNewPlugin_RunCopyContent_Cancelled(t *testing.T) {
+	mockIOHandler := new(iohandlermocks.MockIOHandler)
+	resource := &cancelableResource{}
+	resource.On("ValidateLocationInfo").Return(true, nil)
+
+	input := DownloadContentPlugin{
+		SourceType:      "S3",
+		DestinationPath: "destination",
+	}
+	config := createStubConfiguration("orch", "bucket", "prefix", "1234-1234-1234", "directory")
+
+	p := Plugin{
+		context: contextmocks.NewMockDefault(),
+		remoteResourceCreator: func(context.T, string, string) (remoteresource.RemoteResource, error) {
+			return resource, nil
+		},
+		filesys: &filemock.FileSystemMock{},
+	}
+	mockIOHandler.On("AppendInfof", "Download interrupted after %v file(s): %v", []interface{}{1, "file1"}).Return()
+	mockIOHandler.On("MarkAsCancelled").Return()
+
+	cancelFlag := task.NewChanneledCancelFlag()
+	time.AfterFunc(10*time.Millisecond, func() { cancelFlag.Set(task.Canceled) })
+	p.runCopyContent(logger, &input, config, cancelFlag, mockIOHandler)
+
+	resource.AssertExpectations(t)
+	mockIOHandler.AssertExpectations(t)
+}
+
 func TestNewPlugin_RunCopyContent_absPathDestinationDir(t *testing.T) {
 	fileMock := filemock.FileSystemMock{}
 	mockIOHandler := new(iohandlermocks.MockIOHandler)
@@ -156,7 +227,7 @@ func TestNewPlugin_RunCopyContent_absPathDestinationDir(t *testing.T) {
 	mockIOHandler.On("MarkAsSucceeded").Return()
 
 	SetPermission = stubChmod
-	p.runCopyContent(logger, &input, config, mockIOHandler)
+	p.runCopyContent(logger, &input, config, task.NewChanneledCancelFlag(), mockIOHandler)
 
 	copyContentResourceMock.AssertExpectations(t)
 	fileMock.AssertExpectations(t)
@@ -182,13 +253,49 @@ func TestNewPlugin_RunCopyContent_relativeDirDestinationPath(t *testing.T) {
 	mockIOHandler.On("MarkAsSucceeded").Return()
 
 	SetPermission = stubChmod
-	p.runCopyContent(logger, &input, config, mockIOHandler)
+	p.runCopyContent(logger, &input, config, task.NewChanneledCancelFlag(), mockIOHandler)
 
 	copyContentResourceMock.AssertExpectations(t)
 	fileMock.AssertExpectations(t)
 	mockIOHandler.AssertExpectations(t)
 }
 
+func TestNewPlugin_RunCopyContent_AppendsObjectMetadata(t *testing.T) {
+	fileMock := &filemock.FileSystemMock{}
+	mockIOHandler := new(iohandlermocks.MockIOHandler)
+	resourceMock := &resourcemock.RemoteResourceMock{}
+
+	input := DownloadContentPlugin{
+		SourceType:      "S3",
+		DestinationPath: "destination",
+	}
+	config := createStubConfiguration("orch", "bucket", "prefix", "1234-1234-1234", "directory")
+	result := &remoteresource.DownloadResult{
+		Metadata: map[string]remoteresource.ObjectMetadata{
+			"file.sh": {ContentType: "text/x-sh", Metadata: map[string]string{"build-number": "42"}},
+		},
+	}
+
+	p := Plugin{
+		context: contextmocks.NewMockDefault(),
+		remoteResourceCreator: func(context context.T, locationtype, locationInfo string) (remoteresource.RemoteResource, error) {
+			resourceMock.On("ValidateLocationInfo").Return(true, nil).Once()
+			resourceMock.On("DownloadRemoteResource", fileMock, mock.Anything).Return(nil, result).Once()
+			return resourceMock, nil
+		},
+		filesys: fileMock,
+	}
+	mockIOHandler.On("AppendInfof", "Content downloaded to %v", mock.Anything).Return()
+	mockIOHandler.On("AppendInfof", "Object metadata: %v", []interface{}{`{"file.sh":{"contentType":"text/x-sh","metadata":{"build-number":"42"}}}`}).Return().Once()
+	mockIOHandler.On("MarkAsSucceeded").Return()
+
+	SetPermission = stubChmod
+	p.runCopyContent(logger, &input, config, task.NewChanneledCancelFlag(), mockIOHandler)
+
+	resourceMock.AssertExpectations(t)
+	mockIOHandler.AssertExpectations(t)
+}
+
 func Test_RunCopyContentBadLocationInfo(t *testing.T) {
 
 	fileMock := filemock.FileSystemMock{}
@@ -213,7 +320,7 @@ func Test_RunCopyContentBadLocationInfo(t *testing.T) {
 	}
 	mockIOHandler.On("MarkAsFailed", mock.Anything).Return()
 
-	p.runCopyContent(logger, &input, config, mockIOHandler)
+	p.runCopyContent(logger, &input, config, task.NewChanneledCancelFlag(), mockIOHandler)
 
 	fileMock.AssertExpectations(t)
 	mockIOHandler.AssertExpectations(t)
@@ -501,3 +608,16 @@ func createMockCancelFlag() task.CancelFlag {
 func stubChmod(log log.T, workingDir string) error {
 	return nil
 }
+
+func TestSetPermissions_KeepsPermissionsAppliedByResource(t *testing.T) {
+	originalSetPermission := SetPermission
+	defer func() { SetPermission = originalSetPermission }()
+	SetPermission = func(log log.T, workingDir string) error {
+		t.Fatal("unexpected permission change")
+		return nil
+	}
+
+	err := setPermissions(logger, &remoteresource.DownloadResult{Files: []string{"tls-key"}, PermissionsApplied: true})
+
+	assert.NoError(t, err)
+}
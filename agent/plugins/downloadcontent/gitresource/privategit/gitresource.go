@@ -49,6 +49,7 @@ type GitInfo struct {
 	Username            types.TrimmedString `json:"username"`
 	Password            types.TrimmedString `json:"password"`
 	GetOptions          string              `json:"getOptions"`
+	TrustedGPGKeys      []string            `json:"trustedGPGKeys"`
 }
 
 // NewGitResource creates a new git resource
@@ -72,7 +73,11 @@ func NewGitResource(context context.T, info string, bridge ssmparameterresolver.
 		Password:            gitInfo.Password,
 	}
 
-	gitHandler, err := handler.NewGitHandler(gitInfo.Repository, authConfig, *getOptions, bridge)
+	verification := handler.GitVerificationConfig{
+		TrustedGPGKeys: gitInfo.TrustedGPGKeys,
+	}
+
+	gitHandler, err := handler.NewGitHandler(gitInfo.Repository, authConfig, *getOptions, verification, bridge)
 	if err != nil {
 		return nil, err
 	}
@@ -120,7 +125,13 @@ func (resource *GitResource) DownloadRemoteResource(fileSystem filemanager.FileS
 		return err, nil
 	}
 
-	if err := resource.Handler.PerformCheckout(core.NewGitRepository(repository)); err != nil {
+	gitRepository := core.NewGitRepository(repository)
+	if err := resource.Handler.PerformCheckout(gitRepository); err != nil {
+		return err, nil
+	}
+
+	// Files are only collected once the checked out content is known to come from a trusted signer
+	if err := resource.Handler.VerifySignature(log, gitRepository); err != nil {
 		return err, nil
 	}
 
@@ -64,7 +64,7 @@ func TestNewGitResource(t *testing.T) {
 	}, gitresource.CheckoutOptions{
 		Branch:   "master",
 		CommitID: "",
-	}, bm)
+	}, handler.GitVerificationConfig{}, bm)
 
 	assert.NoError(t, err)
 
@@ -162,6 +162,19 @@ func TestNewGitResource_parseSourceInfo(t *testing.T) {
 			},
 			nil,
 		},
+		{
+			`{
+				"repository": "git://",
+				"getOptions": "branch:refs/tags/v1.0",
+				"trustedGPGKeys": ["--public-key--", "{{ssm-secure:gpgKey}}"]
+			}`,
+			GitInfo{
+				Repository:     "git://",
+				GetOptions:     "branch:refs/tags/v1.0",
+				TrustedGPGKeys: []string{"--public-key--", "{{ssm-secure:gpgKey}}"},
+			},
+			nil,
+		},
 		{
 			`{
 				"repository": "git://"
@@ -237,6 +250,7 @@ func TestGitResource_DownloadRemoteResource(t *testing.T) {
 	gitHandlerMock.On("GetAuthMethod", logMock).Return(authMethod, nil).Once()
 	gitHandlerMock.On("CloneRepository", logMock, authMethod, downloadRemoteResourceTempCloneDir).Return(repository, nil).Once()
 	gitHandlerMock.On("PerformCheckout", core.NewGitRepository(repository)).Return(nil).Once()
+	gitHandlerMock.On("VerifySignature", logMock, core.NewGitRepository(repository)).Return(nil).Once()
 
 	resource := GitResource{
 		context: contextMock,
@@ -18,6 +18,7 @@ package mock
 
 import (
 	"github.com/aws/amazon-ssm-agent/agent/plugins/downloadcontent/gitresource/privategit/handler/core"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -29,3 +30,19 @@ func (gitRepository *GitRepositoryMock) Worktree() (gitWorktree core.IGitWorktre
 	args := gitRepository.Called()
 	return args.Get(0).(core.IGitWorktree), args.Error(1)
 }
+
+func (gitRepository *GitRepositoryMock) HeadCommit() (commit *object.Commit, err error) {
+	args := gitRepository.Called()
+	if args.Get(0) != nil {
+		commit = args.Get(0).(*object.Commit)
+	}
+	return commit, args.Error(1)
+}
+
+func (gitRepository *GitRepositoryMock) TagObject(name string) (tag *object.Tag, err error) {
+	args := gitRepository.Called(name)
+	if args.Get(0) != nil {
+		tag = args.Get(0).(*object.Tag)
+	}
+	return tag, args.Error(1)
+}
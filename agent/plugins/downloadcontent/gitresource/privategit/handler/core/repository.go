@@ -18,11 +18,14 @@ package core
 
 import (
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
 )
 
 // IGitRepository defines a subset of git.Repository methods required to clone/checkout a git repository
 type IGitRepository interface {
 	Worktree() (gitWorktree IGitWorktree, err error)
+	HeadCommit() (commit *object.Commit, err error)
+	TagObject(name string) (tag *object.Tag, err error)
 }
 
 // Worktree returns the worktree of the repository
@@ -30,6 +33,24 @@ func (repository *GitRepository) Worktree() (gitWorktree IGitWorktree, err error
 	return NewGitWorktree(repository)
 }
 
+// HeadCommit returns the commit checked out in the worktree
+func (repository *GitRepository) HeadCommit() (commit *object.Commit, err error) {
+	head, err := repository.Repository.Head()
+	if err != nil {
+		return nil, err
+	}
+	return repository.Repository.CommitObject(head.Hash())
+}
+
+// TagObject returns the annotated tag with the given name, plumbing.ErrObjectNotFound is returned for a lightweight tag
+func (repository *GitRepository) TagObject(name string) (tag *object.Tag, err error) {
+	reference, err := repository.Repository.Tag(name)
+	if err != nil {
+		return nil, err
+	}
+	return repository.Repository.TagObject(reference.Hash())
+}
+
 // GitRepository is a wrapper for git.Repository and implements IGitRepository
 type GitRepository struct {
 	Repository *git.Repository
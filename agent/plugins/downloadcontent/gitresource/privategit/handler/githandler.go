@@ -20,7 +20,9 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/ProtonMail/go-crypto/openpgp"
 	"github.com/aws/amazon-ssm-agent/agent/log"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/downloadcontent/gitresource"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/downloadcontent/gitresource/privategit/handler/core"
@@ -34,6 +36,9 @@ import (
 	"golang.org/x/crypto/ssh"
 )
 
+// tagReferencePrefix is the prefix of the getOptions branch that checks out a tag
+const tagReferencePrefix = "refs/tags/"
+
 var plainCloneMethod = gogit.PlainClone
 var geteuid = os.Geteuid
 var getUserHomeDir = os.UserHomeDir
@@ -48,11 +53,18 @@ type GitAuthConfig struct {
 	Password            types.TrimmedString
 }
 
+// GitVerificationConfig defines the keys the checked out commit or tag must be signed with
+type GitVerificationConfig struct {
+	// TrustedGPGKeys are ASCII armored GPG public keys or references to them in Parameter Store
+	TrustedGPGKeys []string
+}
+
 // IGitHandler defines methods to interact with git repositories
 type IGitHandler interface {
 	GetAuthMethod(log log.T) (transport.AuthMethod, error)
 	CloneRepository(log log.T, authMethod transport.AuthMethod, destPath string) (repository *gogit.Repository, err error)
 	PerformCheckout(repository core.IGitRepository) error
+	VerifySignature(log log.T, repository core.IGitRepository) error
 	Validate() (bool, error)
 }
 
@@ -62,6 +74,7 @@ type gitHandler struct {
 	parsedRepositoryURL        transport.Endpoint
 	authConfig                 GitAuthConfig
 	getOptions                 gitresource.CheckoutOptions
+	verification               GitVerificationConfig
 	ssmParameterResolverBridge ssmparameterresolver.ISsmParameterResolverBridge
 }
 
@@ -70,6 +83,7 @@ func NewGitHandler(
 	repository string,
 	authConfig GitAuthConfig,
 	options gitresource.CheckoutOptions,
+	verification GitVerificationConfig,
 	bridge ssmparameterresolver.ISsmParameterResolverBridge) (IGitHandler, error) {
 	parsedURL, err := transport.NewEndpoint(repository)
 	if err != nil {
@@ -81,6 +95,7 @@ func NewGitHandler(
 		parsedRepositoryURL:        *parsedURL,
 		authConfig:                 authConfig,
 		getOptions:                 options,
+		verification:               verification,
 		ssmParameterResolverBridge: bridge,
 	}, nil
 }
@@ -159,6 +174,80 @@ func (handler *gitHandler) PerformCheckout(repository core.IGitRepository) error
 	return nil
 }
 
+// VerifySignature verifies that the checked out annotated tag, or the checked out commit otherwise,
+// is signed with one of the trusted GPG keys. Nothing is verified if no key is trusted.
+func (handler *gitHandler) VerifySignature(log log.T, repository core.IGitRepository) error {
+	if len(handler.verification.TrustedGPGKeys) == 0 {
+		return nil
+	}
+
+	keys, err := handler.getTrustedGPGKeys(log)
+	if err != nil {
+		return err
+	}
+
+	if tagName := strings.TrimPrefix(handler.getOptions.Branch.Val(), tagReferencePrefix); tagName != handler.getOptions.Branch.Val() {
+		tag, err := repository.TagObject(tagName)
+		if err == nil {
+			if tag.PGPSignature == "" {
+				return fmt.Errorf("Tag %s is not signed", tagName)
+			}
+			signer, err := verifySignedObject(tag, keys)
+			if err != nil {
+				return fmt.Errorf("Cannot verify signature of tag %s: %v", tagName, err)
+			}
+			log.Infof("Tag %s is signed with trusted key %s", tagName, signer)
+			return nil
+		} else if err != plumbing.ErrObjectNotFound {
+			return fmt.Errorf("Cannot retrieve tag %s: %v", tagName, err)
+		}
+		// a lightweight tag has no signature of its own, the commit it points to has to be signed
+	}
+
+	commit, err := repository.HeadCommit()
+	if err != nil {
+		return fmt.Errorf("Cannot retrieve checked out commit: %v", err)
+	}
+	if commit.PGPSignature == "" {
+		return fmt.Errorf("Commit %s is not signed", commit.Hash)
+	}
+	signer, err := verifySignedObject(commit, keys)
+	if err != nil {
+		return fmt.Errorf("Cannot verify signature of commit %s: %v", commit.Hash, err)
+	}
+	log.Infof("Commit %s is signed with trusted key %s", commit.Hash, signer)
+	return nil
+}
+
+// signedObject is a git object carrying a GPG signature, i.e. a commit or an annotated tag
+type signedObject interface {
+	Verify(armoredKeyRing string) (*openpgp.Entity, error)
+}
+
+// verifySignedObject returns the id of the trusted key the object is signed with
+func verifySignedObject(object signedObject, keys []string) (signer string, err error) {
+	for _, key := range keys {
+		var entity *openpgp.Entity
+		if entity, err = object.Verify(key); err == nil {
+			return entity.PrimaryKey.KeyIdString(), nil
+		}
+	}
+	return "", fmt.Errorf("not signed with any trusted key: %v", err)
+}
+
+// getTrustedGPGKeys resolves the trusted keys stored in Parameter Store
+func (handler *gitHandler) getTrustedGPGKeys(log log.T) (keys []string, err error) {
+	for _, key := range handler.verification.TrustedGPGKeys {
+		if handler.ssmParameterResolverBridge.IsValidParameterStoreReference(key) {
+			if key, err = handler.ssmParameterResolverBridge.GetParameterFromSsmParameterStore(log, key); err != nil {
+				return nil, err
+			}
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
 // Validate validates handler's attributes values
 func (handler *gitHandler) Validate() (bool, error) {
 	if handler.repositoryURL == "" {
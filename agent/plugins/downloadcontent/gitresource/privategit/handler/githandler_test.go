@@ -16,6 +16,7 @@
 package handler
 
 import (
+	"bytes"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
@@ -23,13 +24,17 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
 	"github.com/aws/amazon-ssm-agent/agent/mocks/log"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/downloadcontent/gitresource"
 	gitcoremock "github.com/aws/amazon-ssm-agent/agent/plugins/downloadcontent/gitresource/privategit/handler/core/mock"
 	bridgemock "github.com/aws/amazon-ssm-agent/agent/ssm/ssmparameterresolver/mock"
 	gogit "github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
 	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
@@ -144,6 +149,7 @@ func TestNewGitHandlerURLParsing(t *testing.T) {
 			test.repository,
 			GitAuthConfig{},
 			gitresource.CheckoutOptions{},
+			GitVerificationConfig{},
 			bridgemock.GetSsmParamResolverBridge(parameterStoreParameters),
 		)
 
@@ -799,3 +805,166 @@ func TestGitHandler_performCheckout_Branch(t *testing.T) {
 	assert.NoError(t, err)
 	gitWorktreeMock.AssertExpectations(t)
 }
+
+// newGPGKey returns a new signing key together with its armored public key
+func newGPGKey(t *testing.T, name string) (*openpgp.Entity, string) {
+	entity, err := openpgp.NewEntity(name, "", name+"@example.com", nil)
+	assert.NoError(t, err)
+
+	var publicKey bytes.Buffer
+	writer, err := armor.Encode(&publicKey, openpgp.PublicKeyType, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, entity.Serialize(writer))
+	assert.NoError(t, writer.Close())
+	return entity, publicKey.String()
+}
+
+// signObject returns the armored detached signature of the encoded object
+func signObject(t *testing.T, signer *openpgp.Entity, encode func(plumbing.EncodedObject) error) string {
+	encoded := &plumbing.MemoryObject{}
+	assert.NoError(t, encode(encoded))
+	reader, err := encoded.Reader()
+	assert.NoError(t, err)
+
+	var signature bytes.Buffer
+	assert.NoError(t, openpgp.ArmoredDetachSign(&signature, signer, reader, nil))
+	return signature.String()
+}
+
+func newCommit(t *testing.T, signer *openpgp.Entity) *object.Commit {
+	author := object.Signature{Name: "admin", Email: "admin@example.com", When: time.Unix(1714644904, 0)}
+	commit := &object.Commit{
+		Hash:      plumbing.NewHash("a1b2c3d4e5f60718293a4b5c6d7e8f9012345678"),
+		Author:    author,
+		Committer: author,
+		Message:   "Update configuration",
+		TreeHash:  plumbing.NewHash("0123456789abcdef0123456789abcdef01234567"),
+	}
+	if signer != nil {
+		commit.PGPSignature = signObject(t, signer, commit.EncodeWithoutSignature)
+	}
+	return commit
+}
+
+func newTag(t *testing.T, signer *openpgp.Entity) *object.Tag {
+	tag := &object.Tag{
+		Name:       "v1.0",
+		Tagger:     object.Signature{Name: "admin", Email: "admin@example.com", When: time.Unix(1714644904, 0)},
+		Message:    "Release v1.0",
+		TargetType: plumbing.CommitObject,
+		Target:     plumbing.NewHash("a1b2c3d4e5f60718293a4b5c6d7e8f9012345678"),
+	}
+	if signer != nil {
+		tag.PGPSignature = signObject(t, signer, tag.EncodeWithoutSignature)
+	}
+	return tag
+}
+
+func TestGitHandler_VerifySignature_NoTrustedKeys(t *testing.T) {
+	handler := gitHandler{}
+
+	gitRepoMock := new(gitcoremock.GitRepositoryMock)
+	assert.NoError(t, handler.VerifySignature(logMock, gitRepoMock))
+	gitRepoMock.AssertNotCalled(t, "HeadCommit")
+}
+
+func TestGitHandler_VerifySignature_Commit(t *testing.T) {
+	trusted, trustedKey := newGPGKey(t, "trusted")
+	other, otherKey := newGPGKey(t, "other")
+	untrusted, _ := newGPGKey(t, "untrusted")
+
+	tests := []struct {
+		commit *object.Commit
+		err    string
+	}{
+		{newCommit(t, trusted), ""},
+		{newCommit(t, other), ""},
+		{newCommit(t, untrusted), "Cannot verify signature of commit a1b2c3d4e5f60718293a4b5c6d7e8f9012345678: not signed with any trusted key"},
+		{newCommit(t, nil), "Commit a1b2c3d4e5f60718293a4b5c6d7e8f9012345678 is not signed"},
+	}
+
+	for _, test := range tests {
+		handler := gitHandler{
+			getOptions: gitresource.CheckoutOptions{
+				Branch: "master",
+			},
+			verification: GitVerificationConfig{
+				TrustedGPGKeys: []string{trustedKey, otherKey},
+			},
+			ssmParameterResolverBridge: bridgemock.GetSsmParamResolverBridge(parameterStoreParameters),
+		}
+
+		gitRepoMock := new(gitcoremock.GitRepositoryMock)
+		gitRepoMock.On("HeadCommit").Return(test.commit, nil)
+
+		err := handler.VerifySignature(logMock, gitRepoMock)
+		if test.err == "" {
+			assert.NoError(t, err)
+		} else {
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), test.err)
+		}
+		gitRepoMock.AssertExpectations(t)
+	}
+}
+
+func TestGitHandler_VerifySignature_TrustedKeyFromParameterStore(t *testing.T) {
+	trusted, trustedKey := newGPGKey(t, "trusted")
+
+	handler := gitHandler{
+		verification: GitVerificationConfig{
+			TrustedGPGKeys: []string{"{{ssm-secure:gpgKey}}"},
+		},
+		ssmParameterResolverBridge: bridgemock.GetSsmParamResolverBridge(map[string]string{"{{ssm-secure:gpgKey}}": trustedKey}),
+	}
+
+	gitRepoMock := new(gitcoremock.GitRepositoryMock)
+	gitRepoMock.On("HeadCommit").Return(newCommit(t, trusted), nil)
+
+	assert.NoError(t, handler.VerifySignature(logMock, gitRepoMock))
+	gitRepoMock.AssertExpectations(t)
+}
+
+func TestGitHandler_VerifySignature_Tag(t *testing.T) {
+	trusted, trustedKey := newGPGKey(t, "trusted")
+	untrusted, _ := newGPGKey(t, "untrusted")
+
+	newHandler := func() gitHandler {
+		return gitHandler{
+			getOptions: gitresource.CheckoutOptions{
+				Branch: "refs/tags/v1.0",
+			},
+			verification: GitVerificationConfig{
+				TrustedGPGKeys: []string{trustedKey},
+			},
+			ssmParameterResolverBridge: bridgemock.GetSsmParamResolverBridge(parameterStoreParameters),
+		}
+	}
+
+	// a signed annotated tag is sufficient, the commit it points to is not verified
+	handler := newHandler()
+	gitRepoMock := new(gitcoremock.GitRepositoryMock)
+	gitRepoMock.On("TagObject", "v1.0").Return(newTag(t, trusted), nil)
+	assert.NoError(t, handler.VerifySignature(logMock, gitRepoMock))
+	gitRepoMock.AssertNotCalled(t, "HeadCommit")
+
+	handler = newHandler()
+	gitRepoMock = new(gitcoremock.GitRepositoryMock)
+	gitRepoMock.On("TagObject", "v1.0").Return(newTag(t, untrusted), nil)
+	err := handler.VerifySignature(logMock, gitRepoMock)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Cannot verify signature of tag v1.0")
+
+	handler = newHandler()
+	gitRepoMock = new(gitcoremock.GitRepositoryMock)
+	gitRepoMock.On("TagObject", "v1.0").Return(newTag(t, nil), nil)
+	assert.EqualError(t, handler.VerifySignature(logMock, gitRepoMock), "Tag v1.0 is not signed")
+
+	// a lightweight tag falls back to the signature of the commit
+	handler = newHandler()
+	gitRepoMock = new(gitcoremock.GitRepositoryMock)
+	gitRepoMock.On("TagObject", "v1.0").Return(nil, plumbing.ErrObjectNotFound)
+	gitRepoMock.On("HeadCommit").Return(newCommit(t, trusted), nil)
+	assert.NoError(t, handler.VerifySignature(logMock, gitRepoMock))
+	gitRepoMock.AssertExpectations(t)
+}
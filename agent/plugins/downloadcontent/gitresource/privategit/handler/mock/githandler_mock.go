@@ -43,6 +43,10 @@ func (mock *GitHandlerMock) PerformCheckout(repository core.IGitRepository) erro
 	return args.Error(0)
 
 }
+func (mock *GitHandlerMock) VerifySignature(log log.T, repository core.IGitRepository) error {
+	args := mock.Called(log, repository)
+	return args.Error(0)
+}
 func (mock *GitHandlerMock) CloneRepository(log log.T, authMethod transport.AuthMethod, destPath string) (repository *gogit.Repository, err error) {
 	args := mock.Called(log, authMethod, destPath)
 	return args.Get(0).(*gogit.Repository), args.Error(1)
@@ -17,6 +17,7 @@
 package handler
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -55,7 +56,7 @@ type HTTPAuthConfig struct {
 
 // IHTTPHandler defines methods to interact with HTTP resources
 type IHTTPHandler interface {
-	Download(log log.T, fileSystem filemanager.FileSystem, downloadPath string) (string, error)
+	Download(requestCtx context.Context, log log.T, fileSystem filemanager.FileSystem, downloadPath string) (string, error)
 	Validate() (bool, error)
 }
 
@@ -85,8 +86,8 @@ func NewHTTPHandler(
 	}
 }
 
-// Download downloads a HTTP resource into a specific download path
-func (handler *httpHandler) Download(log log.T, fileSystem filemanager.FileSystem, downloadPath string) (string, error) {
+// Download downloads a HTTP resource into a specific download path, the transfer stops once requestCtx is cancelled
+func (handler *httpHandler) Download(requestCtx context.Context, log log.T, fileSystem filemanager.FileSystem, downloadPath string) (string, error) {
 	if !handler.isUsingSecureProtocol() && !handler.allowInsecureDownload {
 		log.Info("Non secure URL provided and insecure download is not allowed")
 		return "", fmt.Errorf("Non secure URL provided and insecure download is not allowed. " +
@@ -97,6 +98,7 @@ func (handler *httpHandler) Download(log log.T, fileSystem filemanager.FileSyste
 	if err != nil {
 		return "", fmt.Errorf("Failed to prepare the request: %s", err.Error())
 	}
+	request = request.WithContext(requestCtx)
 
 	out, err := fileSystem.CreateFile(downloadPath)
 	if err != nil {
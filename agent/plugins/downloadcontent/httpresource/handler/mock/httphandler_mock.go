@@ -16,6 +16,8 @@
 package mock
 
 import (
+	"context"
+
 	"github.com/aws/amazon-ssm-agent/agent/fileutil/filemanager"
 	"github.com/aws/amazon-ssm-agent/agent/log"
 	"github.com/stretchr/testify/mock"
@@ -25,8 +27,8 @@ type HTTPHandlerMock struct {
 	mock.Mock
 }
 
-func (mock *HTTPHandlerMock) Download(log log.T, fileSystem filemanager.FileSystem, destPath string) (string, error) {
-	args := mock.Called(log, fileSystem, destPath)
+func (mock *HTTPHandlerMock) Download(requestCtx context.Context, log log.T, fileSystem filemanager.FileSystem, destPath string) (string, error) {
+	args := mock.Called(requestCtx, log, fileSystem, destPath)
 	return args.String(0), args.Error(1)
 }
 
@@ -17,6 +17,7 @@
 package httpresource
 
 import (
+	reqContext "context"
 	"crypto/rand"
 	"fmt"
 	"math"
@@ -38,8 +39,9 @@ import (
 
 // HTTPResource represents an HTTP(s) resource
 type HTTPResource struct {
-	context context.T
-	Handler handler.IHTTPHandler
+	context        context.T
+	Handler        handler.IHTTPHandler
+	requestContext reqContext.Context
 }
 
 // HTTPInfo defines the accepted SourceInfo attributes and their json definition
@@ -92,7 +94,11 @@ func (resource *HTTPResource) DownloadRemoteResource(fileSystem filemanager.File
 
 	log.Debug("Destination path to download - ", downloadPath)
 
-	downloadedFilepath, err := resource.Handler.Download(log, fileSystem, downloadPath)
+	requestCtx := resource.requestContext
+	if requestCtx == nil {
+		requestCtx = reqContext.Background()
+	}
+	downloadedFilepath, err := resource.Handler.Download(requestCtx, log, fileSystem, downloadPath)
 	if err != nil {
 		return err, nil
 	}
@@ -102,6 +108,11 @@ func (resource *HTTPResource) DownloadRemoteResource(fileSystem filemanager.File
 	}
 }
 
+// SetRequestContext sets the context whose cancellation interrupts the download
+func (resource *HTTPResource) SetRequestContext(requestCtx reqContext.Context) {
+	resource.requestContext = requestCtx
+}
+
 // ValidateLocationInfo validates attribute values of an HTTP resource
 func (resource *HTTPResource) ValidateLocationInfo() (isValid bool, err error) {
 	return resource.Handler.Validate()
@@ -272,7 +272,7 @@ func TestHTTPResource_DownloadRemoteResource(t *testing.T) {
 	fileSystemMock.On("IsDirectory", destPath).Return(false)
 
 	httpHandlerMock := httpMock.HTTPHandlerMock{}
-	httpHandlerMock.On("Download", logMock, fileSystemMock, destPath).Return(destPath, nil).Once()
+	httpHandlerMock.On("Download", mock.Anything, logMock, fileSystemMock, destPath).Return(destPath, nil).Once()
 
 	resource := HTTPResource{
 		context: contextMock,
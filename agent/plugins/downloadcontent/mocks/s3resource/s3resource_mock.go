@@ -26,12 +26,23 @@ type S3DepMock struct {
 	mock.Mock
 }
 
-func (s3 *S3DepMock) ListS3Directory(context context.T, amazonS3URL s3util.AmazonS3URL) (folderNames []string, err error) {
+// ListS3DirectoryPages passes the pages given to Return to fn and then returns the error given to Return
+func (s3 *S3DepMock) ListS3DirectoryPages(context context.T, amazonS3URL s3util.AmazonS3URL, fn func(keys []string) bool) error {
 	args := s3.Called(context, amazonS3URL)
-	return args.Get(0).([]string), args.Error(1)
+	for _, page := range args.Get(0).([][]string) {
+		if !fn(page) {
+			break
+		}
+	}
+	return args.Error(1)
 }
 
 func (s3 *S3DepMock) Download(context context.T, input artifact.DownloadInput) (artifact.DownloadOutput, error) {
 	args := s3.Called(context, input)
 	return args.Get(0).(artifact.DownloadOutput), args.Error(1)
 }
+
+func (s3 *S3DepMock) GetS3ObjectMetadata(context context.T, amazonS3URL s3util.AmazonS3URL, expectedBucketOwner string) (artifact.S3ObjectMetadata, error) {
+	args := s3.Called(context, amazonS3URL, expectedBucketOwner)
+	return args.Get(0).(artifact.S3ObjectMetadata), args.Error(1)
+}
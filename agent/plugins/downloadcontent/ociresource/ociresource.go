@@ -0,0 +1,283 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package ociresource implements the methods to access artifacts in ECR and other OCI registries
+package ociresource
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/fileutil/filemanager"
+	"github.com/aws/amazon-ssm-agent/agent/jsonutil"
+	"github.com/aws/amazon-ssm-agent/agent/network"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/downloadcontent/remoteresource"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/downloadcontent/types"
+	"github.com/aws/amazon-ssm-agent/agent/sdkutil"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ecr"
+)
+
+const (
+	// titleAnnotation names the file a layer is pushed from by ORAS
+	titleAnnotation = "org.opencontainers.image.title"
+	defaultTag      = "latest"
+)
+
+var (
+	ecrRegistryRegex = regexp.MustCompile(`^\d{12}\.dkr\.ecr(?:-fips)?\.([a-z0-9-]+)\.amazonaws\.com(?:\.cn)?$`)
+	digestRegex      = regexp.MustCompile(`^[a-z0-9]+:[a-f0-9]+$`)
+
+	// ecrAuthorization returns the authorization header for ECR registries in a region
+	ecrAuthorization = getECRAuthorization
+)
+
+// OCIResource is a struct for the remote resource of type OCI
+type OCIResource struct {
+	context    context.T
+	Info       OCIInfo
+	httpClient *http.Client
+}
+
+// OCIInfo represents the sourceInfo type sent by runcommand
+type OCIInfo struct {
+	// Reference is the artifact to pull, e.g. 123456789012.dkr.ecr.us-east-1.amazonaws.com/bundles/config:1.2
+	Reference types.TrimmedString `json:"reference"`
+}
+
+// artifactReference is a parsed OCI artifact reference
+type artifactReference struct {
+	registry   string
+	repository string
+	// reference is the digest, or the tag when no digest is given
+	reference string
+}
+
+// NewOCIResource is a constructor of type OCIResource
+func NewOCIResource(context context.T, info string) (*OCIResource, error) {
+	var ociInfo OCIInfo
+	if err := jsonutil.Unmarshal(info, &ociInfo); err != nil {
+		return nil, fmt.Errorf("SourceInfo could not be unmarshalled for source type OCI: %v", err)
+	}
+
+	return &OCIResource{
+		context: context,
+		Info:    ociInfo,
+		httpClient: &http.Client{
+			Transport: network.GetDefaultTransport(context.Log(), context.AppConfig()),
+		},
+	}, nil
+}
+
+// ValidateLocationInfo ensures that the required parameters of SourceInfo are specified
+func (resource *OCIResource) ValidateLocationInfo() (bool, error) {
+	if resource.Info.Reference == "" {
+		return false, fmt.Errorf("OCI reference in SourceInfo must be specified")
+	}
+	if _, err := parseReference(resource.Info.Reference.Val()); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// DownloadRemoteResource pulls the layers of an OCI artifact, each layer is saved as a file
+func (resource *OCIResource) DownloadRemoteResource(filesys filemanager.FileSystem, destPath string) (err error, result *remoteresource.DownloadResult) {
+	log := resource.context.Log()
+	ref, err := parseReference(resource.Info.Reference.Val())
+	if err != nil {
+		return err, nil
+	}
+	log.Infof("Downloading OCI artifact %s", resource.Info.Reference.Val())
+
+	client := &registryClient{
+		httpClient: resource.httpClient,
+		registry:   ref.registry,
+		repository: ref.repository,
+	}
+	if match := ecrRegistryRegex.FindStringSubmatch(ref.registry); match != nil {
+		if client.authorization, err = ecrAuthorization(resource.context, match[1]); err != nil {
+			return fmt.Errorf("failed to get ECR authorization token: %v", err), nil
+		}
+	}
+
+	layers, err := resolveLayers(client, ref.reference)
+	if err != nil {
+		return err, nil
+	}
+
+	if destPath == "" {
+		destPath = appconfig.DownloadRoot
+	}
+	// Mimicking S3 download functionality, a single file is saved under the destination unless it is a directory
+	isDirTypeDownload := len(layers) > 1 ||
+		(filesys.Exists(destPath) && filesys.IsDirectory(destPath)) ||
+		os.IsPathSeparator(destPath[len(destPath)-1])
+
+	result = &remoteresource.DownloadResult{Metadata: map[string]remoteresource.ObjectMetadata{}}
+	for _, layer := range layers {
+		filePath := destPath
+		if isDirTypeDownload {
+			filePath = filepath.Join(destPath, layerFileName(layer))
+		}
+		if _, exists := result.Metadata[filePath]; exists {
+			return fmt.Errorf("multiple layers of the artifact are named %s", filepath.Base(filePath)), nil
+		}
+
+		log.Debugf("Downloading layer %s to %s", layer.Digest, filePath)
+		if err = downloadLayer(client, filesys, layer, filePath); err != nil {
+			return err, nil
+		}
+		result.Files = append(result.Files, filePath)
+		result.Metadata[filePath] = remoteresource.ObjectMetadata{
+			ContentType: layer.MediaType,
+			Metadata:    layer.Annotations,
+		}
+	}
+	return nil, result
+}
+
+// resolveLayers returns the layers of the artifact, resolving an image index to the manifest of this platform
+func resolveLayers(client *registryClient, reference string) ([]descriptor, error) {
+	m, mediaType, err := client.getManifest(reference)
+	if err != nil {
+		return nil, err
+	}
+
+	if mediaType == mediaTypeOCIIndex || mediaType == mediaTypeDockerManifestList {
+		platformManifest, err := selectPlatformManifest(m.Manifests)
+		if err != nil {
+			return nil, err
+		}
+		if m, _, err = client.getManifest(platformManifest.Digest); err != nil {
+			return nil, err
+		}
+	}
+
+	layers := m.Layers
+	if len(layers) == 0 {
+		layers = m.Blobs
+	}
+	if len(layers) == 0 {
+		return nil, fmt.Errorf("artifact %s has no layers", reference)
+	}
+	for _, layer := range layers {
+		if !digestRegex.MatchString(layer.Digest) {
+			return nil, fmt.Errorf("artifact %s has a layer with invalid digest %s", reference, layer.Digest)
+		}
+	}
+	return layers, nil
+}
+
+// selectPlatformManifest picks the manifest of the platform the agent runs on from an image index
+func selectPlatformManifest(manifests []descriptor) (descriptor, error) {
+	for _, m := range manifests {
+		if m.Platform != nil && m.Platform.OS == runtime.GOOS && m.Platform.Architecture == runtime.GOARCH {
+			return m, nil
+		}
+	}
+	if len(manifests) == 1 && digestRegex.MatchString(manifests[0].Digest) {
+		return manifests[0], nil
+	}
+	return descriptor{}, fmt.Errorf("image index has no manifest for platform %s/%s", runtime.GOOS, runtime.GOARCH)
+}
+
+// layerFileName returns the file name the layer was pushed from, or its digest when it is not annotated
+func layerFileName(layer descriptor) string {
+	if title := filepath.Base(filepath.Clean(layer.Annotations[titleAnnotation])); title != "." && title != ".." && title != string(filepath.Separator) {
+		return title
+	}
+	return strings.Replace(layer.Digest, ":", "-", 1)
+}
+
+func downloadLayer(client *registryClient, filesys filemanager.FileSystem, layer descriptor, filePath string) error {
+	if err := filesys.MakeDirs(filepath.Dir(filePath)); err != nil {
+		return fmt.Errorf("cannot create download path %s: %v", filepath.Dir(filePath), err)
+	}
+	file, err := filesys.CreateFile(filePath)
+	if err != nil {
+		return fmt.Errorf("cannot create file %s: %v", filePath, err)
+	}
+
+	err = client.getBlob(layer, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = filesys.DeleteFile(filePath)
+	}
+	return err
+}
+
+// parseReference splits an artifact reference of the form registry/repository[:tag][@digest]
+func parseReference(reference string) (ref artifactReference, err error) {
+	reference = strings.TrimPrefix(reference, "oci://")
+	slash := strings.Index(reference, "/")
+	if slash <= 0 {
+		return ref, fmt.Errorf("OCI reference %s must include the registry and the repository", reference)
+	}
+	ref.registry = reference[:slash]
+	if !strings.ContainsAny(ref.registry, ".:") && ref.registry != "localhost" {
+		return ref, fmt.Errorf("OCI reference %s must start with a registry host", reference)
+	}
+
+	name := reference[slash+1:]
+	if at := strings.Index(name, "@"); at >= 0 {
+		ref.reference = name[at+1:]
+		name = name[:at]
+		if !digestRegex.MatchString(ref.reference) {
+			return ref, fmt.Errorf("OCI reference %s has an invalid digest", reference)
+		}
+	}
+	if colon := strings.LastIndex(name, ":"); colon > strings.LastIndex(name, "/") {
+		if ref.reference == "" {
+			ref.reference = name[colon+1:]
+		}
+		name = name[:colon]
+	}
+	if ref.reference == "" {
+		ref.reference = defaultTag
+	}
+	if name == "" {
+		return ref, fmt.Errorf("OCI reference %s must include the repository", reference)
+	}
+	ref.repository = name
+	return ref, nil
+}
+
+// getECRAuthorization gets a registry authorization token with the agent credentials
+func getECRAuthorization(context context.T, region string) (string, error) {
+	appConfig := context.AppConfig()
+	sess, err := session.NewSession(sdkutil.AwsConfigForRegion(context, "api.ecr", region))
+	if err != nil {
+		return "", fmt.Errorf("error creating new aws sdk session: %v", err)
+	}
+	sess.Handlers.Build.PushBack(request.MakeAddToUserAgentHandler(appConfig.Agent.Name, appConfig.Agent.Version))
+
+	output, err := ecr.New(sess).GetAuthorizationToken(&ecr.GetAuthorizationTokenInput{})
+	if err != nil {
+		return "", err
+	}
+	if len(output.AuthorizationData) == 0 || aws.StringValue(output.AuthorizationData[0].AuthorizationToken) == "" {
+		return "", fmt.Errorf("ECR returned no authorization token")
+	}
+	return "Basic " + aws.StringValue(output.AuthorizationData[0].AuthorizationToken), nil
+}
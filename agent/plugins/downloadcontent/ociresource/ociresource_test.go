@@ -0,0 +1,237 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package ociresource
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/fileutil/filemanager"
+	contextmocks "github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/downloadcontent/remoteresource"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/downloadcontent/types"
+	"github.com/stretchr/testify/assert"
+)
+
+var contextMock = contextmocks.NewMockDefault()
+
+func digestOf(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// fakeRegistry serves manifests and blobs by tag or digest, requiring an anonymous bearer token when tokenRequired
+type fakeRegistry struct {
+	manifests     map[string][]byte
+	blobs         map[string][]byte
+	tokenRequired bool
+	authorization []string
+}
+
+func (r *fakeRegistry) pushManifest(tag string, m interface{}) string {
+	content, _ := json.Marshal(m)
+	digest := digestOf(content)
+	r.manifests[digest] = content
+	if tag != "" {
+		r.manifests[tag] = content
+	}
+	return digest
+}
+
+func (r *fakeRegistry) pushBlob(content []byte) string {
+	digest := digestOf(content)
+	r.blobs[digest] = content
+	return digest
+}
+
+func newFakeRegistry(t *testing.T) (*fakeRegistry, *httptest.Server) {
+	registry := &fakeRegistry{manifests: map[string][]byte{}, blobs: map[string][]byte{}}
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/token" {
+			assert.Equal(t, "repository:bundles/config:pull", req.URL.Query().Get("scope"))
+			w.Write([]byte(`{"token":"anonymous"}`))
+			return
+		}
+		registry.authorization = append(registry.authorization, req.Header.Get("Authorization"))
+		if registry.tokenRequired && req.Header.Get("Authorization") != "Bearer anonymous" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry",scope="repository:bundles/config:pull"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		parts := strings.SplitN(strings.TrimPrefix(req.URL.Path, "/v2/bundles/config/"), "/", 2)
+		var content []byte
+		switch parts[0] {
+		case "manifests":
+			content = registry.manifests[parts[1]]
+		case "blobs":
+			content = registry.blobs[parts[1]]
+		}
+		if content == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(content)
+	}))
+	t.Cleanup(server.Close)
+	return registry, server
+}
+
+func newTestResource(server *httptest.Server, reference string) *OCIResource {
+	return &OCIResource{
+		context:    contextMock,
+		Info:       OCIInfo{Reference: types.NewTrimmedString("oci://" + strings.TrimPrefix(server.URL, "https://") + "/bundles/config" + reference)},
+		httpClient: server.Client(),
+	}
+}
+
+func TestParseReference(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+	testCases := []struct {
+		reference string
+		expected  artifactReference
+	}{
+		{"123456789012.dkr.ecr.us-east-1.amazonaws.com/bundles/config:1.2", artifactReference{"123456789012.dkr.ecr.us-east-1.amazonaws.com", "bundles/config", "1.2"}},
+		{"oci://ghcr.io/org/bundle", artifactReference{"ghcr.io", "org/bundle", "latest"}},
+		{"localhost:5000/bundle:v1@" + digest, artifactReference{"localhost:5000", "bundle", digest}},
+		{"localhost/bundle@" + digest, artifactReference{"localhost", "bundle", digest}},
+	}
+	for _, tc := range testCases {
+		actual, err := parseReference(tc.reference)
+		assert.NoError(t, err, tc.reference)
+		assert.Equal(t, tc.expected, actual, tc.reference)
+	}
+
+	for _, invalid := range []string{"bundle:latest", "org/bundle", "ghcr.io/", "ghcr.io/bundle@sha256:../../etc", ""} {
+		_, err := parseReference(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestValidateLocationInfo(t *testing.T) {
+	resource, err := NewOCIResource(contextMock, `{"reference": " public.ecr.aws/org/bundle:1 "}`)
+	assert.NoError(t, err)
+	valid, err := resource.ValidateLocationInfo()
+	assert.True(t, valid)
+	assert.NoError(t, err)
+
+	resource, _ = NewOCIResource(contextMock, `{"reference": ""}`)
+	valid, err = resource.ValidateLocationInfo()
+	assert.False(t, valid)
+	assert.Error(t, err)
+}
+
+func TestDownloadRemoteResource_ArtifactLayers(t *testing.T) {
+	registry, server := newFakeRegistry(t)
+	registry.tokenRequired = true
+	config := []byte("key: value")
+	script := []byte("echo hello")
+	registry.pushManifest("1.2", manifest{
+		MediaType: mediaTypeOCIManifest,
+		Layers: []descriptor{
+			{MediaType: "application/yaml", Digest: registry.pushBlob(config), Annotations: map[string]string{titleAnnotation: "config.yaml", "build": "42"}},
+			{MediaType: "text/x-sh", Digest: registry.pushBlob(script), Annotations: map[string]string{titleAnnotation: "../../install.sh"}},
+		},
+	})
+	destination := t.TempDir()
+
+	err, result := newTestResource(server, ":1.2").DownloadRemoteResource(filemanager.FileSystemImpl{}, destination)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(destination, "config.yaml"), filepath.Join(destination, "install.sh")}, result.Files)
+	assert.Equal(t, remoteresource.ObjectMetadata{
+		ContentType: "application/yaml",
+		Metadata:    map[string]string{titleAnnotation: "config.yaml", "build": "42"},
+	}, result.Metadata[filepath.Join(destination, "config.yaml")])
+	content, _ := os.ReadFile(filepath.Join(destination, "install.sh"))
+	assert.Equal(t, script, content)
+	assert.Contains(t, registry.authorization, "Bearer anonymous")
+}
+
+func TestDownloadRemoteResource_ImageIndexSingleLayerToFile(t *testing.T) {
+	registry, server := newFakeRegistry(t)
+	layer := []byte("layer")
+	otherManifest := registry.pushManifest("", manifest{Layers: []descriptor{{Digest: registry.pushBlob([]byte("other"))}}})
+	platformManifest := registry.pushManifest("", manifest{Layers: []descriptor{{Digest: registry.pushBlob(layer)}}})
+	indexDigest := registry.pushManifest("", manifest{
+		MediaType: mediaTypeOCIIndex,
+		Manifests: []descriptor{
+			{Digest: otherManifest, Platform: &platform{OS: "plan9", Architecture: "mips"}},
+			{Digest: platformManifest, Platform: &platform{OS: runtime.GOOS, Architecture: runtime.GOARCH}},
+		},
+	})
+	destination := filepath.Join(t.TempDir(), "layer.tar.gz")
+
+	err, result := newTestResource(server, "@"+indexDigest).DownloadRemoteResource(filemanager.FileSystemImpl{}, destination)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{destination}, result.Files)
+	content, _ := os.ReadFile(destination)
+	assert.Equal(t, layer, content)
+}
+
+func TestDownloadRemoteResource_DigestMismatch(t *testing.T) {
+	registry, server := newFakeRegistry(t)
+	digest := registry.pushBlob([]byte("original"))
+	registry.blobs[digest] = []byte("tampered")
+	registry.pushManifest("latest", manifest{Layers: []descriptor{{Digest: digest}}})
+	destination := t.TempDir()
+
+	err, _ := newTestResource(server, "").DownloadRemoteResource(filemanager.FileSystemImpl{}, destination)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "digest mismatch")
+	assert.NoFileExists(t, filepath.Join(destination, strings.Replace(digest, ":", "-", 1)))
+}
+
+func TestDownloadRemoteResource_ECRUsesAgentCredentials(t *testing.T) {
+	originalECRAuthorization := ecrAuthorization
+	t.Cleanup(func() { ecrAuthorization = originalECRAuthorization })
+	var authorizedRegion string
+	ecrAuthorization = func(context context.T, region string) (string, error) {
+		authorizedRegion = region
+		return "", fmt.Errorf("AccessDeniedException")
+	}
+	resource, _ := NewOCIResource(contextMock, `{"reference": "123456789012.dkr.ecr.eu-west-1.amazonaws.com/bundles/config:1.2"}`)
+
+	err, _ := resource.DownloadRemoteResource(filemanager.FileSystemImpl{}, t.TempDir())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "AccessDeniedException")
+	assert.Equal(t, "eu-west-1", authorizedRegion)
+}
+
+func TestDownloadRemoteResource_RequiresCredentials(t *testing.T) {
+	_, server := newFakeRegistry(t)
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	err, _ := newTestResource(server, ":1.2").DownloadRemoteResource(filemanager.FileSystemImpl{}, t.TempDir())
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "requires credentials")
+}
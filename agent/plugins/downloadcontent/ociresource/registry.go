@@ -0,0 +1,254 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package ociresource
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	mediaTypeOCIManifest        = "application/vnd.oci.image.manifest.v1+json"
+	mediaTypeOCIIndex           = "application/vnd.oci.image.index.v1+json"
+	mediaTypeOCIArtifact        = "application/vnd.oci.artifact.manifest.v1+json"
+	mediaTypeDockerManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+	mediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+
+	// maxManifestSize bounds the manifests read into memory
+	maxManifestSize = 4 * 1024 * 1024
+)
+
+var manifestMediaTypes = []string{
+	mediaTypeOCIManifest,
+	mediaTypeOCIIndex,
+	mediaTypeOCIArtifact,
+	mediaTypeDockerManifest,
+	mediaTypeDockerManifestList,
+}
+
+// challengeParameterRegex matches the key="value" pairs of a WWW-Authenticate challenge
+var challengeParameterRegex = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// descriptor references a manifest or a blob in a registry
+type descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Platform    *platform         `json:"platform,omitempty"`
+}
+
+type platform struct {
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+}
+
+// manifest is the union of the image manifest, the artifact manifest and the image index
+type manifest struct {
+	MediaType string       `json:"mediaType"`
+	Layers    []descriptor `json:"layers"`
+	Blobs     []descriptor `json:"blobs"`
+	Manifests []descriptor `json:"manifests"`
+}
+
+// registryClient pulls manifests and blobs over the OCI distribution API
+type registryClient struct {
+	httpClient *http.Client
+	registry   string
+	repository string
+	// authorization is sent with every request once known, it is obtained from the registry challenge otherwise
+	authorization string
+}
+
+// getManifest returns the manifest of a tag or digest and the media type the registry served it as
+func (c *registryClient) getManifest(reference string) (m manifest, mediaType string, err error) {
+	resp, err := c.get(fmt.Sprintf("/v2/%s/manifests/%s", c.repository, reference), strings.Join(manifestMediaTypes, ", "))
+	if err != nil {
+		return m, "", err
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestSize))
+	if err != nil {
+		return m, "", fmt.Errorf("failed to read manifest %s: %v", reference, err)
+	}
+	if isDigest(reference) {
+		if err = verifyDigest(reference, content); err != nil {
+			return m, "", err
+		}
+	}
+	if err = json.Unmarshal(content, &m); err != nil {
+		return m, "", fmt.Errorf("failed to parse manifest %s: %v", reference, err)
+	}
+
+	mediaType = m.MediaType
+	if mediaType == "" {
+		mediaType = strings.TrimSpace(strings.Split(resp.Header.Get("Content-Type"), ";")[0])
+	}
+	return m, mediaType, nil
+}
+
+// getBlob writes the blob to writer and verifies its digest
+func (c *registryClient) getBlob(blob descriptor, writer io.Writer) error {
+	resp, err := c.get(fmt.Sprintf("/v2/%s/blobs/%s", c.repository, blob.Digest), "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	digester, err := newDigester(blob.Digest)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(io.MultiWriter(writer, digester), resp.Body); err != nil {
+		return fmt.Errorf("failed to download blob %s: %v", blob.Digest, err)
+	}
+	if actual := digestAlgorithm(blob.Digest) + ":" + hex.EncodeToString(digester.Sum(nil)); actual != blob.Digest {
+		return fmt.Errorf("digest mismatch for blob %s, got %s", blob.Digest, actual)
+	}
+	return nil
+}
+
+// get sends a GET request to the registry, answering a bearer challenge with an anonymous token once
+func (c *registryClient) get(path string, accept string) (*http.Response, error) {
+	resp, err := c.send(path, accept)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized && c.authorization == "" {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		if err = c.authorize(challenge); err != nil {
+			return nil, err
+		}
+		if resp, err = c.send(path, accept); err != nil {
+			return nil, err
+		}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("registry %s returned %s for %s", c.registry, resp.Status, path)
+	}
+	return resp, nil
+}
+
+func (c *registryClient) send(path string, accept string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, "https://"+c.registry+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	// the http client drops the header when the registry redirects blob downloads to another host
+	if c.authorization != "" {
+		req.Header.Set("Authorization", c.authorization)
+	}
+	return c.httpClient.Do(req)
+}
+
+// authorize obtains an anonymous pull token from the token service named in a bearer challenge
+func (c *registryClient) authorize(challenge string) error {
+	if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+		return fmt.Errorf("registry %s requires credentials", c.registry)
+	}
+	params := map[string]string{}
+	for _, match := range challengeParameterRegex.FindAllStringSubmatch(challenge, -1) {
+		params[strings.ToLower(match[1])] = match[2]
+	}
+	if params["realm"] == "" {
+		return fmt.Errorf("registry %s sent a bearer challenge without realm", c.registry)
+	}
+
+	tokenURL, err := url.Parse(params["realm"])
+	if err != nil {
+		return fmt.Errorf("invalid token realm %s: %v", params["realm"], err)
+	}
+	query := tokenURL.Query()
+	if params["service"] != "" {
+		query.Set("service", params["service"])
+	}
+	scope := params["scope"]
+	if scope == "" {
+		scope = fmt.Sprintf("repository:%s:pull", c.repository)
+	}
+	query.Set("scope", scope)
+	tokenURL.RawQuery = query.Encode()
+
+	resp, err := c.httpClient.Get(tokenURL.String())
+	if err != nil {
+		return fmt.Errorf("failed to get registry token: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("registry token service returned %s", resp.Status)
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err = json.NewDecoder(io.LimitReader(resp.Body, maxManifestSize)).Decode(&token); err != nil {
+		return fmt.Errorf("failed to parse registry token: %v", err)
+	}
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+	if token.Token == "" {
+		return fmt.Errorf("registry token service returned an empty token")
+	}
+	c.authorization = "Bearer " + token.Token
+	return nil
+}
+
+func isDigest(reference string) bool {
+	return strings.Contains(reference, ":")
+}
+
+func digestAlgorithm(digest string) string {
+	return strings.SplitN(digest, ":", 2)[0]
+}
+
+func newDigester(digest string) (hash.Hash, error) {
+	switch digestAlgorithm(digest) {
+	case "sha256":
+		return sha256.New(), nil
+	case "sha512":
+		return sha512.New(), nil
+	default:
+		return nil, fmt.Errorf("unsupported digest algorithm in %s", digest)
+	}
+}
+
+func verifyDigest(digest string, content []byte) error {
+	digester, err := newDigester(digest)
+	if err != nil {
+		return err
+	}
+	digester.Write(content)
+	if actual := digestAlgorithm(digest) + ":" + hex.EncodeToString(digester.Sum(nil)); actual != digest {
+		return fmt.Errorf("digest mismatch for manifest %s, got %s", digest, actual)
+	}
+	return nil
+}
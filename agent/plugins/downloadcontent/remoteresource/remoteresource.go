@@ -16,6 +16,8 @@
 package remoteresource
 
 import (
+	"context"
+
 	"github.com/aws/amazon-ssm-agent/agent/fileutil/filemanager"
 )
 
@@ -26,6 +28,17 @@ const (
 
 type DownloadResult struct {
 	Files []string
+	// Metadata holds the metadata of the remote objects keyed by the path of the downloaded file, for sources that provide it
+	Metadata map[string]ObjectMetadata
+	// PermissionsApplied is set by resources that apply the permissions of the files themselves
+	PermissionsApplied bool
+}
+
+// ObjectMetadata is the metadata of the remote object a file was downloaded from
+type ObjectMetadata struct {
+	ContentType string            `json:"contentType,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
 }
 
 // RemoteResource is an interface for accessing remote resources. Every type of remote resource is expected to implement RemoteResource interface
@@ -33,3 +46,9 @@ type RemoteResource interface {
 	DownloadRemoteResource(filesys filemanager.FileSystem, destinationDir string) (err error, result *DownloadResult)
 	ValidateLocationInfo() (bool, error)
 }
+
+// CancelableResource is implemented by remote resources whose download can be interrupted before it completes.
+// The files downloaded before the interruption are returned in the result along with the error.
+type CancelableResource interface {
+	SetRequestContext(requestCtx context.Context)
+}
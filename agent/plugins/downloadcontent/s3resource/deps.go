@@ -22,8 +22,9 @@ import (
 
 // dependency on S3 and downloaded artifacts
 type s3deps interface {
-	ListS3Directory(context context.T, amazonS3URL s3util.AmazonS3URL) (folderNames []string, err error)
+	ListS3DirectoryPages(context context.T, amazonS3URL s3util.AmazonS3URL, fn func(keys []string) bool) error
 	Download(context context.T, input artifact.DownloadInput) (artifact.DownloadOutput, error)
+	GetS3ObjectMetadata(context context.T, amazonS3URL s3util.AmazonS3URL, expectedBucketOwner string) (artifact.S3ObjectMetadata, error)
 }
 
 type s3DepImpl struct{}
@@ -31,10 +32,14 @@ type s3DepImpl struct{}
 var dep s3deps = &s3DepImpl{}
 
 // TODO: Refactor the code to merge the s3 capabilities to one package
-func (s3DepImpl) ListS3Directory(context context.T, amazonS3URL s3util.AmazonS3URL) (folderNames []string, err error) {
-	return artifact.ListS3Directory(context, amazonS3URL)
+func (s3DepImpl) ListS3DirectoryPages(context context.T, amazonS3URL s3util.AmazonS3URL, fn func(keys []string) bool) error {
+	return artifact.ListS3DirectoryPages(context, amazonS3URL, fn)
 }
 
 func (s3DepImpl) Download(context context.T, input artifact.DownloadInput) (artifact.DownloadOutput, error) {
 	return artifact.Download(context, input)
 }
+
+func (s3DepImpl) GetS3ObjectMetadata(context context.T, amazonS3URL s3util.AmazonS3URL, expectedBucketOwner string) (artifact.S3ObjectMetadata, error) {
+	return artifact.GetS3ObjectMetadata(context, amazonS3URL, expectedBucketOwner)
+}
@@ -16,6 +16,7 @@
 package s3resource
 
 import (
+	reqContext "context"
 	"errors"
 	"fmt"
 	"net/url"
@@ -35,11 +36,15 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/s3util"
 )
 
+// listedKeysBufferSize bounds the listed keys waiting to be downloaded, it is the size of a ListObjects page
+const listedKeysBufferSize = 1000
+
 // S3Resource is a struct for the remote resource of type git
 type S3Resource struct {
-	context  context.T
-	Info     S3Info
-	s3Object s3util.AmazonS3URL
+	context        context.T
+	Info           S3Info
+	s3Object       s3util.AmazonS3URL
+	requestContext reqContext.Context
 }
 
 // S3Info represents the sourceInfo type sent by runcommand
@@ -94,14 +99,10 @@ func validateSourceInfo(s3Info S3Info) (err error) {
 // DownloadRemoteResource calls download to pull down files or directory from s3
 func (s3 *S3Resource) DownloadRemoteResource(filesys filemanager.FileSystem, destPath string) (err error, result *remoteresource.DownloadResult) {
 	var fileURL *url.URL
-	var unescapedURL string
-	var folders []string
-	var localFilePath string
 
 	log := s3.context.Log()
 	result = &remoteresource.DownloadResult{}
 
-	isDirTypeDownloaded := true
 	if destPath == "" {
 		destPath = appconfig.DownloadRoot
 	}
@@ -126,87 +127,171 @@ func (s3 *S3Resource) DownloadRemoteResource(filesys filemanager.FileSystem, des
 		return fmt.Errorf("invalid S3 path parameter"), nil
 	}
 
-	// Create an object for the source URL. This can be used to list the objects in the folder
-	if folders, err = dep.ListS3Directory(s3.context, s3.s3Object); err != nil {
-		if isPathType(s3.s3Object.Key) {
+	// The objects of the folder are downloaded while the folder is still being listed
+	done := make(chan struct{})
+	defer close(done)
+	keys, listErr := s3.listS3Directory(done)
+
+	isDirTypeDownloaded := false
+	for key := range keys {
+		isDirTypeDownloaded = true
+		if err = s3.downloadObject(filesys, key, destPath, isDirTypeDownloaded, result); err != nil {
+			if s3.requestContext != nil && s3.requestContext.Err() != nil {
+				return fmt.Errorf("download interrupted after %v files: %w", len(result.Files), err), result
+			}
 			return err, nil
 		}
+	}
 
+	if err = <-listErr; err != nil {
+		if isDirTypeDownloaded || isPathType(s3.s3Object.Key) {
+			return err, nil
+		}
 		log.Infof("Attempting s3 download while assuming s3Object '%s' is a file", s3.s3Object.Key)
-		folders = []string{}
 	}
 
-	if len(folders) == 0 {
-		// In case of a file download, append the filename to folders
-		isDirTypeDownloaded = false
-		folders = append(folders, s3.s3Object.Key)
+	if !isDirTypeDownloaded {
+		// Nothing is listed under the key, so the key is a file
+		if err = s3.downloadObject(filesys, s3.s3Object.Key, destPath, isDirTypeDownloaded, result); err != nil {
+			if s3.requestContext != nil && s3.requestContext.Err() != nil {
+				return fmt.Errorf("download interrupted after %v files: %w", len(result.Files), err), result
+			}
+			return err, nil
+		}
 	}
+	return nil, result
+}
 
-	// The URL till the bucket name will be concatenated with the prefix in the loop
-	// responsible for download
-	for _, files := range folders {
-		log.Debug("Name of file - ", files)
-
-		if !isPathType(files) { //Only download in case the URL is a file
-			subFolderPath := strings.TrimPrefix(files, s3.s3Object.Key)
-			var bucketURL *url.URL
-			if bucketURL, err = s3.getS3BucketURLString(); err != nil {
-				return fmt.Errorf("error while obtaining URL parsing - %v", bucketURL), nil
+// listS3Directory lists the objects under the S3 object in the background. The keys are sent to the returned channel,
+// which holds at most listedKeysBufferSize keys, so the listing only runs ahead of the downloads by about one page.
+// The listing error is sent once the channel is closed. The listing stops early when done is closed.
+func (s3 *S3Resource) listS3Directory(done <-chan struct{}) (<-chan string, <-chan error) {
+	keys := make(chan string, listedKeysBufferSize)
+	listErr := make(chan error, 1)
+
+	go func() {
+		var err error
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("listing S3 directory panicked: %v", r)
 			}
-			if bucketURL == nil {
-				return errors.New("URL obtained is nil"), nil
-			}
-			log.Debug("S3 bucket URL -", bucketURL.String())
-			var input artifact.DownloadInput
-
-			// Obtain the full URL for the file before download
-
-			bucketURL.Path += "/" + files
-			input.SourceURL = bucketURL.String()
-
-			// When s3 object returns the Path, it has + for '+', and %20 for ' ', because of the workaround above.
-			// Since we are sending this URL for download, S3 manipulates the + to be a space.
-			// Change from '+' to '%2B' which is the encoding for '+' so that s3 has to interpret %20 to be a space and %2B
-			// to be a '+'
-			// https://s3.amazonaws.com/aws-executecommand-test/scripts/hello+world/spaces%20file.sh
-			// https://s3.amazonaws.com/aws-executecommand-test/scripts/hello%2Bworld/spaces%20file.sh
-			input.SourceURL = strings.Replace(input.SourceURL, "+", "%2B", -1)
-			log.Debug("SourceURL ", input.SourceURL)
-			if unescapedURL, err = url.QueryUnescape(input.SourceURL); err != nil {
-				return err, nil
-			}
-			log.Debug("UnescapedURL ", unescapedURL)
-			destinationFile := filepath.Base(unescapedURL)
-
-			//when the s3 key has sub-folders leading to files, those sub-folders need to be created as well
-			localFilePath = fileutil.BuildPath(destPath, filepath.Dir(subFolderPath))
-			if !isDirTypeDownloaded {
-				// if the file path provided exists as a directory or if it is in the format,
-				// that would be the localFilePath
-				if filesys.Exists(destPath) && filesys.IsDirectory(destPath) || isPathType(destPath) {
-					localFilePath = destPath
-				} else {
-					localFilePath = filepath.Dir(destPath)
-					destinationFile = filepath.Base(destPath)
+			close(keys)
+			listErr <- err
+		}()
+
+		err = dep.ListS3DirectoryPages(s3.context, s3.s3Object, func(page []string) bool {
+			for _, key := range page {
+				select {
+				case keys <- key:
+				case <-done:
+					return false
 				}
 			}
-			input.DestinationDirectory = localFilePath
-			input.ExpectedBucketOwner = s3.Info.ExpectedBucketOwner
-			downloadOutput, err := dep.Download(s3.context, input)
-			if err != nil {
-				return err, nil
-			}
+			return true
+		})
+	}()
+	return keys, listErr
+}
 
-			if err = system.RenameFile(log, filesys, downloadOutput.LocalFilePath, destinationFile); err != nil {
-				return fmt.Errorf("Something went wrong when trying to access downloaded content. It is "+
-						"possible that the content was not downloaded because the path provided is wrong. %v", err),
-					nil
-			}
+// downloadObject downloads a single object of the S3 resource and adds it to the result, folder keys are skipped
+func (s3 *S3Resource) downloadObject(filesys filemanager.FileSystem, key string, destPath string, isDirTypeDownloaded bool, result *remoteresource.DownloadResult) (err error) {
+	log := s3.context.Log()
+	log.Debug("Name of file - ", key)
 
-			result.Files = append(result.Files, filepath.Join(input.DestinationDirectory, destinationFile))
+	if isPathType(key) { //Only download in case the URL is a file
+		return nil
+	}
+	if s3.requestContext != nil && s3.requestContext.Err() != nil {
+		return s3.requestContext.Err()
+	}
+	subFolderPath := strings.TrimPrefix(key, s3.s3Object.Key)
+	var bucketURL *url.URL
+	if bucketURL, err = s3.getS3BucketURLString(); err != nil {
+		return fmt.Errorf("error while obtaining URL parsing - %v", bucketURL)
+	}
+	if bucketURL == nil {
+		return errors.New("URL obtained is nil")
+	}
+	log.Debug("S3 bucket URL -", bucketURL.String())
+	var input artifact.DownloadInput
+
+	// Obtain the full URL for the file before download
+
+	bucketURL.Path += "/" + key
+	input.SourceURL = bucketURL.String()
+
+	// When s3 object returns the Path, it has + for '+', and %20 for ' ', because of the workaround above.
+	// Since we are sending this URL for download, S3 manipulates the + to be a space.
+	// Change from '+' to '%2B' which is the encoding for '+' so that s3 has to interpret %20 to be a space and %2B
+	// to be a '+'
+	// https://s3.amazonaws.com/aws-executecommand-test/scripts/hello+world/spaces%20file.sh
+	// https://s3.amazonaws.com/aws-executecommand-test/scripts/hello%2Bworld/spaces%20file.sh
+	input.SourceURL = strings.Replace(input.SourceURL, "+", "%2B", -1)
+	log.Debug("SourceURL ", input.SourceURL)
+	var unescapedURL string
+	if unescapedURL, err = url.QueryUnescape(input.SourceURL); err != nil {
+		return err
+	}
+	log.Debug("UnescapedURL ", unescapedURL)
+	destinationFile := filepath.Base(unescapedURL)
+
+	//when the s3 key has sub-folders leading to files, those sub-folders need to be created as well
+	localFilePath := fileutil.BuildPath(destPath, filepath.Dir(subFolderPath))
+	if !isDirTypeDownloaded {
+		// if the file path provided exists as a directory or if it is in the format,
+		// that would be the localFilePath
+		if filesys.Exists(destPath) && filesys.IsDirectory(destPath) || isPathType(destPath) {
+			localFilePath = destPath
+		} else {
+			localFilePath = filepath.Dir(destPath)
+			destinationFile = filepath.Base(destPath)
 		}
 	}
-	return nil, result
+	input.DestinationDirectory = localFilePath
+	input.ExpectedBucketOwner = s3.Info.ExpectedBucketOwner
+	input.RequestContext = s3.requestContext
+	downloadOutput, err := dep.Download(s3.context, input)
+	if err != nil {
+		return err
+	}
+
+	if err = system.RenameFile(log, filesys, downloadOutput.LocalFilePath, destinationFile); err != nil {
+		return fmt.Errorf("Something went wrong when trying to access downloaded content. It is "+
+			"possible that the content was not downloaded because the path provided is wrong. %v", err)
+	}
+
+	localFile := filepath.Join(input.DestinationDirectory, destinationFile)
+	result.Files = append(result.Files, localFile)
+	s3.addObjectMetadata(result, key, localFile)
+	return nil
+}
+
+// addObjectMetadata adds the metadata of the downloaded object to the result, the download does not fail without it
+func (s3 *S3Resource) addObjectMetadata(result *remoteresource.DownloadResult, key string, localFile string) {
+	objectURL := s3util.AmazonS3URL{
+		Bucket: s3.s3Object.Bucket,
+		Key:    key,
+		Region: s3.s3Object.Region,
+	}
+	metadata, err := dep.GetS3ObjectMetadata(s3.context, objectURL, s3.Info.ExpectedBucketOwner)
+	if err != nil {
+		s3.context.Log().Warnf("Failed to get metadata of downloaded object: %v", err)
+		return
+	}
+
+	if result.Metadata == nil {
+		result.Metadata = make(map[string]remoteresource.ObjectMetadata)
+	}
+	result.Metadata[localFile] = remoteresource.ObjectMetadata{
+		ContentType: metadata.ContentType,
+		Metadata:    metadata.Metadata,
+		Tags:        metadata.Tags,
+	}
+}
+
+// SetRequestContext sets the context whose cancellation interrupts the download
+func (s3 *S3Resource) SetRequestContext(requestCtx reqContext.Context) {
+	s3.requestContext = requestCtx
 }
 
 // ValidateLocationInfo ensures that the required parameters of SourceInfo are specified
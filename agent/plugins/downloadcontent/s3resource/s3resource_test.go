@@ -18,6 +18,8 @@
 package s3resource
 
 import (
+	reqContext "context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -28,8 +30,10 @@ import (
 	filemock "github.com/aws/amazon-ssm-agent/agent/fileutil/filemanager/mock"
 	"github.com/aws/amazon-ssm-agent/agent/mocks/context"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/downloadcontent/mocks/s3resource"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/downloadcontent/remoteresource"
 	"github.com/aws/amazon-ssm-agent/agent/s3util"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 var contextMock = context.NewMockDefault()
@@ -201,7 +205,8 @@ func TestS3Resource_Download(t *testing.T) {
 	}
 	var folders []string
 	depMock.On("Download", contextMock, input).Return(output, nil)
-	depMock.On("ListS3Directory", contextMock, s3Object).Return(folders, nil)
+	depMock.On("ListS3DirectoryPages", contextMock, s3Object).Return([][]string{folders}, nil)
+	depMock.On("GetS3ObjectMetadata", contextMock, mock.Anything, "").Return(artifact.S3ObjectMetadata{}, nil)
 
 	fileMock.On("MoveAndRenameFile", ".", "destination", ".", "file.rb").Return(true, nil)
 
@@ -216,6 +221,81 @@ func TestS3Resource_Download(t *testing.T) {
 	assert.Equal(t, filepath.Join("destination", "file.rb"), result.Files[0])
 }
 
+func TestS3Resource_DownloadReturnsObjectMetadata(t *testing.T) {
+	depMock := new(s3resource.S3DepMock)
+	locationInfo := `{
+		"path" : "https://s3.amazonaws.com/ssm-test-agent-bucket/mydummyfolder/file.rb",
+		"expectedBucketOwner" : "123456789012"
+	}`
+	fileMock := &filemock.FileSystemMock{}
+
+	fileMock.On("IsDirectory", "destination").Return(true)
+	fileMock.On("Exists", "destination").Return(true)
+	resource, _ := NewS3Resource(contextMock, locationInfo)
+
+	input := artifact.DownloadInput{
+		DestinationDirectory: "destination",
+		SourceURL:            "https://s3.us-east-1.amazonaws.com/ssm-test-agent-bucket/mydummyfolder/file.rb",
+		ExpectedBucketOwner:  "123456789012",
+	}
+	output := artifact.DownloadOutput{
+		LocalFilePath: input.DestinationDirectory,
+	}
+	objectURL := s3util.AmazonS3URL{
+		Bucket: "ssm-test-agent-bucket",
+		Key:    "mydummyfolder/file.rb",
+		Region: "us-east-1",
+	}
+	metadata := artifact.S3ObjectMetadata{
+		ContentType: "text/x-ruby",
+		Metadata:    map[string]string{"build-number": "42"},
+		Tags:        map[string]string{"SignatureId": "sig-1"},
+	}
+	var folders []string
+	depMock.On("Download", contextMock, input).Return(output, nil)
+	depMock.On("ListS3DirectoryPages", contextMock, mock.Anything).Return([][]string{folders}, nil)
+	depMock.On("GetS3ObjectMetadata", contextMock, objectURL, "123456789012").Return(metadata, nil).Once()
+	fileMock.On("MoveAndRenameFile", ".", "destination", ".", "file.rb").Return(true, nil)
+
+	dep = depMock
+	err, result := resource.DownloadRemoteResource(fileMock, "destination")
+
+	assert.NoError(t, err)
+	depMock.AssertExpectations(t)
+	assert.Equal(t, map[string]remoteresource.ObjectMetadata{
+		filepath.Join("destination", "file.rb"): {
+			ContentType: "text/x-ruby",
+			Metadata:    map[string]string{"build-number": "42"},
+			Tags:        map[string]string{"SignatureId": "sig-1"},
+		},
+	}, result.Metadata)
+}
+
+func TestS3Resource_DownloadSucceedsWithoutObjectMetadata(t *testing.T) {
+	depMock := new(s3resource.S3DepMock)
+	locationInfo := `{
+		"path" : "https://s3.amazonaws.com/ssm-test-agent-bucket/mydummyfolder/file.rb"
+	}`
+	fileMock := &filemock.FileSystemMock{}
+
+	fileMock.On("IsDirectory", "destination").Return(true)
+	fileMock.On("Exists", "destination").Return(true)
+	resource, _ := NewS3Resource(contextMock, locationInfo)
+
+	var folders []string
+	depMock.On("Download", contextMock, mock.Anything).Return(artifact.DownloadOutput{LocalFilePath: "destination"}, nil)
+	depMock.On("ListS3DirectoryPages", contextMock, mock.Anything).Return([][]string{folders}, nil)
+	depMock.On("GetS3ObjectMetadata", contextMock, mock.Anything, "").Return(artifact.S3ObjectMetadata{}, fmt.Errorf("AccessDenied"))
+	fileMock.On("MoveAndRenameFile", ".", "destination", ".", "file.rb").Return(true, nil)
+
+	dep = depMock
+	err, result := resource.DownloadRemoteResource(fileMock, "destination")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join("destination", "file.rb")}, result.Files)
+	assert.Empty(t, result.Metadata)
+}
+
 func TestS3Resource_DownloadDirectory(t *testing.T) {
 	depMock := new(s3resource.S3DepMock)
 	locationInfo := `{
@@ -251,7 +331,8 @@ func TestS3Resource_DownloadDirectory(t *testing.T) {
 	folders = append(folders, "foldername/anotherfile.ps")
 	depMock.On("Download", contextMock, input1).Return(output1, nil).Once()
 	depMock.On("Download", contextMock, input2).Return(output2, nil).Once()
-	depMock.On("ListS3Directory", contextMock, s3Object).Return(folders, nil)
+	depMock.On("ListS3DirectoryPages", contextMock, s3Object).Return([][]string{folders}, nil)
+	depMock.On("GetS3ObjectMetadata", contextMock, mock.Anything, "").Return(artifact.S3ObjectMetadata{}, nil)
 
 	fileMock.On("MoveAndRenameFile", downloadsDirectory, "randomfilename", downloadsDirectory, "filename.ps").Return(true, nil)
 	fileMock.On("MoveAndRenameFile", downloadsDirectory, "anotherrandomfile", downloadsDirectory, "anotherfile.ps").Return(true, nil)
@@ -268,6 +349,99 @@ func TestS3Resource_DownloadDirectory(t *testing.T) {
 	assert.Equal(t, filepath.Join(downloadsDirectory, "anotherfile.ps"), result.Files[1])
 }
 
+func TestS3Resource_DownloadDirectoryPages(t *testing.T) {
+	depMock := new(s3resource.S3DepMock)
+	locationInfo := `{
+		"Path" : "https://s3.amazonaws.com/ssm-test-agent-bucket/foldername"
+	}`
+	downloadsDirectory := strings.TrimSuffix(appconfig.DownloadRoot, string(os.PathSeparator))
+	fileMock := &filemock.FileSystemMock{}
+	resource, _ := NewS3Resource(contextMock, locationInfo)
+
+	// more keys are listed than fit into the buffer of listed keys
+	var pages [][]string
+	var expectedFiles []string
+	for page := 0; page < 3; page++ {
+		var keys []string
+		for i := 0; i < listedKeysBufferSize; i++ {
+			name := fmt.Sprintf("file%v-%v.ps", page, i)
+			keys = append(keys, "foldername/"+name)
+			expectedFiles = append(expectedFiles, filepath.Join(downloadsDirectory, name))
+		}
+		pages = append(pages, keys)
+	}
+	depMock.On("ListS3DirectoryPages", contextMock, mock.Anything).Return(pages, nil)
+	depMock.On("Download", contextMock, mock.Anything).Return(artifact.DownloadOutput{LocalFilePath: filepath.Join(downloadsDirectory, "randomfilename")}, nil)
+	depMock.On("GetS3ObjectMetadata", contextMock, mock.Anything, "").Return(artifact.S3ObjectMetadata{}, nil)
+	fileMock.On("MoveAndRenameFile", downloadsDirectory, "randomfilename", downloadsDirectory, mock.Anything).Return(true, nil)
+
+	dep = depMock
+	err, result := resource.DownloadRemoteResource(fileMock, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedFiles, result.Files)
+	depMock.AssertNumberOfCalls(t, "Download", 3*listedKeysBufferSize)
+}
+
+func TestS3Resource_DownloadDirectoryListingFails(t *testing.T) {
+	depMock := new(s3resource.S3DepMock)
+	locationInfo := `{
+		"Path" : "https://s3.amazonaws.com/ssm-test-agent-bucket/foldername"
+	}`
+	downloadsDirectory := strings.TrimSuffix(appconfig.DownloadRoot, string(os.PathSeparator))
+	fileMock := &filemock.FileSystemMock{}
+	resource, _ := NewS3Resource(contextMock, locationInfo)
+
+	// the listing fails after the first page, the folder is not downloaded as a file
+	listErr := fmt.Errorf("connection reset")
+	depMock.On("ListS3DirectoryPages", contextMock, mock.Anything).Return([][]string{{"foldername/filename.ps"}}, listErr)
+	depMock.On("Download", contextMock, mock.Anything).Return(artifact.DownloadOutput{LocalFilePath: filepath.Join(downloadsDirectory, "randomfilename")}, nil).Once()
+	depMock.On("GetS3ObjectMetadata", contextMock, mock.Anything, "").Return(artifact.S3ObjectMetadata{}, nil)
+	fileMock.On("MoveAndRenameFile", downloadsDirectory, "randomfilename", downloadsDirectory, "filename.ps").Return(true, nil)
+
+	dep = depMock
+	err, result := resource.DownloadRemoteResource(fileMock, "")
+
+	assert.Equal(t, listErr, err)
+	assert.Nil(t, result)
+	depMock.AssertExpectations(t)
+}
+
+func TestS3Resource_DownloadDirectoryCancelled(t *testing.T) {
+	depMock := new(s3resource.S3DepMock)
+	locationInfo := `{
+		"Path" : "https://s3.amazonaws.com/ssm-test-agent-bucket/foldername"
+	}`
+	downloadsDirectory := strings.TrimSuffix(appconfig.DownloadRoot, string(os.PathSeparator))
+	fileMock := &filemock.FileSystemMock{}
+	resource, _ := NewS3Resource(contextMock, locationInfo)
+	requestCtx, cancel := reqContext.WithCancel(reqContext.Background())
+	defer cancel()
+	resource.SetRequestContext(requestCtx)
+
+	input1 := artifact.DownloadInput{
+		DestinationDirectory: downloadsDirectory,
+		SourceURL:            "https://s3.us-east-1.amazonaws.com/ssm-test-agent-bucket/foldername/filename.ps",
+		RequestContext:       requestCtx,
+	}
+	output1 := artifact.DownloadOutput{
+		LocalFilePath: filepath.Join(input1.DestinationDirectory, "randomfilename"),
+	}
+	folders := []string{"foldername/filename.ps", "foldername/anotherfile.ps", "foldername/thirdfile.ps"}
+	// the command is cancelled while the first file is downloaded
+	depMock.On("Download", contextMock, input1).Return(output1, nil).Run(func(mock.Arguments) { cancel() }).Once()
+	depMock.On("ListS3DirectoryPages", contextMock, mock.Anything).Return([][]string{folders}, nil)
+	depMock.On("GetS3ObjectMetadata", contextMock, mock.Anything, "").Return(artifact.S3ObjectMetadata{}, nil)
+	fileMock.On("MoveAndRenameFile", downloadsDirectory, "randomfilename", downloadsDirectory, "filename.ps").Return(true, nil)
+
+	dep = depMock
+	err, result := resource.DownloadRemoteResource(fileMock, "")
+
+	assert.ErrorIs(t, err, reqContext.Canceled)
+	depMock.AssertExpectations(t)
+	assert.Equal(t, []string{filepath.Join(downloadsDirectory, "filename.ps")}, result.Files)
+}
+
 func TestS3Resource_DownloadDirectoryWithSubFolders(t *testing.T) {
 	depMock := new(s3resource.S3DepMock)
 	locationInfo := `{
@@ -314,7 +488,8 @@ func TestS3Resource_DownloadDirectoryWithSubFolders(t *testing.T) {
 	depMock.On("Download", contextMock, input1).Return(output1, nil).Once()
 	depMock.On("Download", contextMock, input2).Return(output2, nil).Once()
 	depMock.On("Download", contextMock, input3).Return(output3, nil).Once()
-	depMock.On("ListS3Directory", contextMock, s3Object).Return(folders, nil)
+	depMock.On("ListS3DirectoryPages", contextMock, s3Object).Return([][]string{folders}, nil)
+	depMock.On("GetS3ObjectMetadata", contextMock, mock.Anything, "").Return(artifact.S3ObjectMetadata{}, nil)
 	fileMock.On("MoveAndRenameFile", downloadsDirectory, "randomfilename", downloadsDirectory, "filename.ps").Return(true, nil)
 	fileMock.On("MoveAndRenameFile", downloadsDirectory, "anotherrandomfile", downloadsDirectory, "anotherfile.ps").Return(true, nil)
 	fileMock.On("MoveAndRenameFile", filepath.Join(downloadsDirectory, "subfolder"), "justanumber", filepath.Join(downloadsDirectory, "subfolder"), "file.ps").Return(true, nil)
@@ -358,7 +533,8 @@ func TestS3Resource_DownloadAbsPath(t *testing.T) {
 
 	var folders []string
 
-	depMock.On("ListS3Directory", contextMock, resource.s3Object).Return(folders, nil).Once()
+	depMock.On("ListS3DirectoryPages", contextMock, resource.s3Object).Return([][]string{folders}, nil).Once()
+	depMock.On("GetS3ObjectMetadata", contextMock, mock.Anything, "").Return(artifact.S3ObjectMetadata{}, nil)
 	depMock.On("Download", contextMock, input).Return(output, nil).Once()
 
 	fileMock.On("MoveAndRenameFile", filepath.Join("/var", "tmp", "foldername"), "justanumber", filepath.Join("/var", "tmp", "foldername"), "filename.ps").Return(true, nil)
@@ -402,7 +578,8 @@ func TestS3Resource_DownloadRelativePathNameChange(t *testing.T) {
 	}
 	var folders []string
 	depMock.On("Download", contextMock, input).Return(output, nil)
-	depMock.On("ListS3Directory", contextMock, s3Object).Return(folders, nil)
+	depMock.On("ListS3DirectoryPages", contextMock, s3Object).Return([][]string{folders}, nil)
+	depMock.On("GetS3ObjectMetadata", contextMock, mock.Anything, "").Return(artifact.S3ObjectMetadata{}, nil)
 
 	fileMock.On("MoveAndRenameFile", ".", "random", ".", "destination").Return(true, nil)
 
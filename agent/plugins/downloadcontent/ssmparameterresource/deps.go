@@ -0,0 +1,49 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package ssmparameterresource
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	ssmsvc "github.com/aws/amazon-ssm-agent/agent/ssm"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ssm"
+)
+
+// dependency on SSM to get parameters
+type ssmdeps interface {
+	GetParameter(log log.T, name string, withDecryption bool) (*ssm.Parameter, error)
+}
+
+type ssmParameterDepImpl struct {
+	ssmSvc ssmsvc.Service
+}
+
+func (s ssmParameterDepImpl) GetParameter(log log.T, name string, withDecryption bool) (*ssm.Parameter, error) {
+	var response *ssm.GetParametersOutput
+	var err error
+	if withDecryption {
+		response, err = s.ssmSvc.GetDecryptedParameters(log, []string{name})
+	} else {
+		response, err = s.ssmSvc.GetParameters(log, []string{name})
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(response.Parameters) == 0 {
+		return nil, fmt.Errorf("parameter %s does not exist or is not accessible, invalid parameters: %v", name, aws.StringValueSlice(response.InvalidParameters))
+	}
+	return response.Parameters[0], nil
+}
@@ -0,0 +1,186 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package ssmparameterresource implements the methods to save parameters from Parameter Store to files
+package ssmparameterresource
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/fileutil/filemanager"
+	"github.com/aws/amazon-ssm-agent/agent/jsonutil"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/downloadcontent/remoteresource"
+	ssmsvc "github.com/aws/amazon-ssm-agent/agent/ssm"
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+const (
+	// base64Encoding decodes parameters holding binary content
+	base64Encoding = "base64"
+
+	defaultFileMode os.FileMode = appconfig.ReadWriteAccess
+)
+
+// SSMParameterResource is a struct for the remote resource of type SSMParameter
+type SSMParameterResource struct {
+	context context.T
+	Info    SSMParameterInfo
+	ssmdep  ssmdeps
+}
+
+// SSMParameterInfo represents the sourceInfo type sent by runcommand
+type SSMParameterInfo struct {
+	// Name of the parameter, optionally with a :version or :label selector
+	Name string `json:"name"`
+	// WithDecryption decrypts SecureString parameters with KMS, defaults to true
+	WithDecryption *bool `json:"withDecryption"`
+	// FileMode is the octal mode of the saved file, defaults to 0600
+	FileMode string `json:"fileMode"`
+	// Encoding of the parameter value, empty or base64
+	Encoding string `json:"encoding"`
+}
+
+// NewSSMParameterResource is a constructor of type SSMParameterResource
+func NewSSMParameterResource(context context.T, info string) (*SSMParameterResource, error) {
+	var parameterInfo SSMParameterInfo
+	if err := jsonutil.Unmarshal(info, &parameterInfo); err != nil {
+		return nil, errors.New("SourceInfo could not be unmarshalled for SourceType SSMParameter. Please check JSON format of SourceInfo")
+	}
+	parameterInfo.Name = strings.TrimSpace(parameterInfo.Name)
+	parameterInfo.FileMode = strings.TrimSpace(parameterInfo.FileMode)
+	parameterInfo.Encoding = strings.ToLower(strings.TrimSpace(parameterInfo.Encoding))
+
+	return &SSMParameterResource{
+		context: context,
+		Info:    parameterInfo,
+		ssmdep: &ssmParameterDepImpl{
+			ssmSvc: ssmsvc.NewService(context),
+		},
+	}, nil
+}
+
+// ValidateLocationInfo ensures that the required parameters of SourceInfo are specified
+func (resource *SSMParameterResource) ValidateLocationInfo() (valid bool, err error) {
+	if resource.Info.Name == "" {
+		return false, errors.New("SSM Parameter name in SourceInfo must be specified")
+	}
+	if _, err = resource.fileMode(); err != nil {
+		return false, err
+	}
+	if resource.Info.Encoding != "" && resource.Info.Encoding != base64Encoding {
+		return false, fmt.Errorf("unsupported encoding %s, only %s is supported", resource.Info.Encoding, base64Encoding)
+	}
+	return true, nil
+}
+
+// DownloadRemoteResource saves the value of the parameter to a file
+func (resource *SSMParameterResource) DownloadRemoteResource(filesys filemanager.FileSystem, destinationPath string) (err error, result *remoteresource.DownloadResult) {
+	log := resource.context.Log()
+	if destinationPath == "" {
+		destinationPath = appconfig.DownloadRoot
+	}
+	mode, err := resource.fileMode()
+	if err != nil {
+		return err, nil
+	}
+
+	withDecryption := resource.Info.WithDecryption == nil || *resource.Info.WithDecryption
+	log.Debugf("Getting parameter %s, with decryption %v", resource.Info.Name, withDecryption)
+	parameter, err := resource.ssmdep.GetParameter(log, resource.Info.Name, withDecryption)
+	if err != nil {
+		return err, nil
+	}
+
+	content := []byte(aws.StringValue(parameter.Value))
+	if resource.Info.Encoding == base64Encoding {
+		if content, err = base64.StdEncoding.DecodeString(aws.StringValue(parameter.Value)); err != nil {
+			return fmt.Errorf("value of parameter %s is not base64 encoded: %v", resource.Info.Name, err), nil
+		}
+	}
+
+	destinationFilePath := destinationPath
+	if filesys.Exists(destinationPath) && filesys.IsDirectory(destinationPath) || os.IsPathSeparator(destinationPath[len(destinationPath)-1]) {
+		destinationFilePath = filepath.Join(destinationPath, parameterFileName(aws.StringValue(parameter.Name)))
+	}
+	if err = filesys.MakeDirs(filepath.Dir(destinationFilePath)); err != nil {
+		return fmt.Errorf("cannot create download path %s: %v", filepath.Dir(destinationFilePath), err), nil
+	}
+	// the value is never logged, SecureString parameters hold secrets
+	if err = writeFile(destinationFilePath, content, mode); err != nil {
+		return fmt.Errorf("failed to save parameter %s to %s: %v", resource.Info.Name, destinationFilePath, err), nil
+	}
+	log.Infof("Saved parameter %s version %d to %s", resource.Info.Name, aws.Int64Value(parameter.Version), destinationFilePath)
+
+	return nil, &remoteresource.DownloadResult{
+		Files:              []string{destinationFilePath},
+		PermissionsApplied: true,
+		Metadata: map[string]remoteresource.ObjectMetadata{
+			destinationFilePath: {
+				ContentType: aws.StringValue(parameter.Type),
+				Metadata: map[string]string{
+					"arn":     aws.StringValue(parameter.ARN),
+					"version": strconv.FormatInt(aws.Int64Value(parameter.Version), 10),
+				},
+			},
+		},
+	}
+}
+
+// fileMode parses the octal file mode of the saved file
+func (resource *SSMParameterResource) fileMode() (os.FileMode, error) {
+	if resource.Info.FileMode == "" {
+		return defaultFileMode, nil
+	}
+	mode, err := strconv.ParseUint(resource.Info.FileMode, 8, 32)
+	if err != nil || mode > 0777 {
+		return 0, fmt.Errorf("invalid file mode %s, an octal mode like 0600 is expected", resource.Info.FileMode)
+	}
+	return os.FileMode(mode), nil
+}
+
+// parameterFileName returns the last segment of the parameter name without a version or label selector
+func parameterFileName(name string) string {
+	name = strings.SplitN(name, ":", 2)[0]
+	return filepath.Base(strings.ReplaceAll(name, "/", string(filepath.Separator)))
+}
+
+// writeFile creates the file with the mode before writing the content so it is never readable by others
+var writeFile = func(path string, content []byte, mode os.FileMode) (err error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	// the mode is not applied by OpenFile to a file that already exists
+	if err = file.Chmod(mode); err != nil {
+		return err
+	}
+	if err = restrictAccess(path, mode); err != nil {
+		return err
+	}
+	_, err = file.Write(content)
+	return err
+}
@@ -0,0 +1,157 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package ssmparameterresource
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/fileutil/filemanager"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	contextmocks "github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/downloadcontent/remoteresource"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var contextMock = contextmocks.NewMockDefault()
+
+type ssmDepMock struct {
+	mock.Mock
+}
+
+func (m *ssmDepMock) GetParameter(log log.T, name string, withDecryption bool) (*ssm.Parameter, error) {
+	args := m.Called(name, withDecryption)
+	return args.Get(0).(*ssm.Parameter), args.Error(1)
+}
+
+func newTestResource(t *testing.T, info string) (*SSMParameterResource, *ssmDepMock) {
+	resource, err := NewSSMParameterResource(contextMock, info)
+	assert.NoError(t, err)
+	depMock := &ssmDepMock{}
+	resource.ssmdep = depMock
+	return resource, depMock
+}
+
+func secureStringParameter(value string) *ssm.Parameter {
+	return &ssm.Parameter{
+		Name:    aws.String("/app/prod/tls-key"),
+		ARN:     aws.String("arn:aws:ssm:us-east-1:123456789012:parameter/app/prod/tls-key"),
+		Type:    aws.String(ssm.ParameterTypeSecureString),
+		Value:   aws.String(value),
+		Version: aws.Int64(3),
+	}
+}
+
+func TestValidateLocationInfo(t *testing.T) {
+	testCases := []struct {
+		info  string
+		valid bool
+	}{
+		{`{"name": " /app/prod/tls-key "}`, true},
+		{`{"name": "/app/prod/tls-key:3", "fileMode": "0644", "encoding": "BASE64"}`, true},
+		{`{"name": ""}`, false},
+		{`{"name": "/app/prod/tls-key", "fileMode": "rw-------"}`, false},
+		{`{"name": "/app/prod/tls-key", "fileMode": "01777"}`, false},
+		{`{"name": "/app/prod/tls-key", "encoding": "hex"}`, false},
+	}
+	for _, tc := range testCases {
+		resource, _ := newTestResource(t, tc.info)
+		valid, err := resource.ValidateLocationInfo()
+		assert.Equal(t, tc.valid, valid, tc.info)
+		assert.Equal(t, tc.valid, err == nil, tc.info)
+	}
+}
+
+func TestDownloadRemoteResource_SavesDecryptedValueToDirectory(t *testing.T) {
+	resource, depMock := newTestResource(t, `{"name": "/app/prod/tls-key:3"}`)
+	depMock.On("GetParameter", "/app/prod/tls-key:3", true).Return(secureStringParameter("secret"), nil).Once()
+	destination := t.TempDir()
+
+	err, result := resource.DownloadRemoteResource(filemanager.FileSystemImpl{}, destination)
+
+	assert.NoError(t, err)
+	depMock.AssertExpectations(t)
+	filePath := filepath.Join(destination, "tls-key")
+	assert.Equal(t, []string{filePath}, result.Files)
+	assert.True(t, result.PermissionsApplied)
+	assert.Equal(t, remoteresource.ObjectMetadata{
+		ContentType: ssm.ParameterTypeSecureString,
+		Metadata: map[string]string{
+			"arn":     "arn:aws:ssm:us-east-1:123456789012:parameter/app/prod/tls-key",
+			"version": "3",
+		},
+	}, result.Metadata[filePath])
+	content, _ := os.ReadFile(filePath)
+	assert.Equal(t, "secret", string(content))
+	if runtime.GOOS != "windows" {
+		info, _ := os.Stat(filePath)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	}
+}
+
+func TestDownloadRemoteResource_DecodesBase64ToFileWithMode(t *testing.T) {
+	resource, depMock := newTestResource(t, `{"name": "/app/prod/tls-key", "withDecryption": false, "fileMode": "0640", "encoding": "base64"}`)
+	blob := []byte{0x00, 0xff, 0x10}
+	depMock.On("GetParameter", "/app/prod/tls-key", false).Return(secureStringParameter(base64.StdEncoding.EncodeToString(blob)), nil).Once()
+	filePath := filepath.Join(t.TempDir(), "certs", "key.der")
+	// an existing file is overwritten and gets the requested mode
+	os.MkdirAll(filepath.Dir(filePath), 0700)
+	os.WriteFile(filePath, []byte("previous content"), 0666)
+
+	err, result := resource.DownloadRemoteResource(filemanager.FileSystemImpl{}, filePath)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filePath}, result.Files)
+	content, _ := os.ReadFile(filePath)
+	assert.Equal(t, blob, content)
+	if runtime.GOOS != "windows" {
+		info, _ := os.Stat(filePath)
+		assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+	}
+}
+
+func TestDownloadRemoteResource_InvalidBase64(t *testing.T) {
+	resource, depMock := newTestResource(t, `{"name": "/app/prod/tls-key", "encoding": "base64"}`)
+	depMock.On("GetParameter", "/app/prod/tls-key", true).Return(secureStringParameter("not base64!"), nil).Once()
+	destination := t.TempDir()
+
+	err, _ := resource.DownloadRemoteResource(filemanager.FileSystemImpl{}, destination)
+
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "not base64!")
+	assert.NoFileExists(t, filepath.Join(destination, "tls-key"))
+}
+
+func TestDownloadRemoteResource_GetParameterError(t *testing.T) {
+	resource, depMock := newTestResource(t, `{"name": "/app/prod/missing"}`)
+	depMock.On("GetParameter", "/app/prod/missing", true).Return((*ssm.Parameter)(nil), fmt.Errorf("parameter /app/prod/missing does not exist")).Once()
+
+	err, result := resource.DownloadRemoteResource(filemanager.FileSystemImpl{}, t.TempDir())
+
+	assert.Error(t, err)
+	assert.Nil(t, result)
+}
+
+func TestParameterFileName(t *testing.T) {
+	assert.Equal(t, "tls-key", parameterFileName("/app/prod/tls-key"))
+	assert.Equal(t, "tls-key", parameterFileName("/app/prod/tls-key:prod-label"))
+	assert.Equal(t, "config", parameterFileName("config"))
+}
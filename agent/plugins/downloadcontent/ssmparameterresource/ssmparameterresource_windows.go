@@ -0,0 +1,32 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build windows
+// +build windows
+
+package ssmparameterresource
+
+import (
+	"os"
+
+	"github.com/aws/amazon-ssm-agent/agent/fileutil"
+)
+
+// restrictAccess limits the file to administrators and SYSTEM when the mode grants no access to others,
+// windows ignores the permission bits of the file mode
+func restrictAccess(path string, mode os.FileMode) error {
+	if mode&0077 != 0 {
+		return nil
+	}
+	return fileutil.Harden(path)
+}
@@ -18,7 +18,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
 	"github.com/aws/amazon-ssm-agent/agent/context"
@@ -37,12 +39,15 @@ var (
 type Optimizer interface {
 	UpdateContentHash(inventoryItemName, hash string) (err error)
 	GetContentHash(inventoryItemName string) (hash string)
+	UpdateLastFullUploadTime(uploadTime time.Time) (err error)
+	GetLastFullUploadTime() (uploadTime time.Time)
 }
 
 // Impl implements content hash optimizations for inventory plugin
 type Impl struct {
-	log      log.T
-	location string //where the content hash data is persisted in file-systems
+	log                log.T
+	location           string //where the content hash data is persisted in file-systems
+	fullUploadLocation string //where the time of the last upload of all inventory types is persisted
 }
 
 func NewOptimizerImpl(context context.T) (*Impl, error) {
@@ -67,6 +72,7 @@ func NewOptimizerImplWithLocation(context context.T, rootDir string, fileName st
 		machineID,
 		rootDir,
 		fileName)
+	optimizer.fullUploadLocation = filepath.Join(filepath.Dir(optimizer.location), appconfig.InventoryFullUploadFileName)
 
 	contentHashStore = make(map[string]string)
 
@@ -119,3 +125,34 @@ func (i *Impl) GetContentHash(inventoryItemName string) (hash string) {
 
 	return
 }
+
+// UpdateLastFullUploadTime persists the time all inventory types were uploaded to SSM
+func (i *Impl) UpdateLastFullUploadTime(uploadTime time.Time) (err error) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	if _, err = fileutil.WriteIntoFileWithPermissions(i.fullUploadLocation, uploadTime.UTC().Format(time.RFC3339), appconfig.ReadWriteAccess); err != nil {
+		err = fmt.Errorf("Unable to update last full upload time in file - %v because - %v", i.fullUploadLocation, err.Error())
+	}
+	return
+}
+
+// GetLastFullUploadTime returns the time all inventory types were last uploaded to SSM, zero time if unknown
+func (i *Impl) GetLastFullUploadTime() (uploadTime time.Time) {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	if !fileutil.Exists(i.fullUploadLocation) {
+		return
+	}
+	content, err := fileutil.ReadAllText(i.fullUploadLocation)
+	if err != nil {
+		i.log.Debugf("Unable to read last full upload time of inventory plugin - %v", err)
+		return
+	}
+	if uploadTime, err = time.Parse(time.RFC3339, strings.TrimSpace(content)); err != nil {
+		i.log.Debugf("Unable to parse last full upload time of inventory plugin - %v", err)
+		uploadTime = time.Time{}
+	}
+	return
+}
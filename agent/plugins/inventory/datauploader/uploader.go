@@ -28,6 +28,7 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/context"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/model"
 	"github.com/aws/amazon-ssm-agent/agent/sdkutil"
+	"github.com/aws/amazon-ssm-agent/agent/storeforward"
 	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ssm"
@@ -45,6 +46,8 @@ type T interface {
 	SendDataToSSM(items []*ssm.InventoryItem) (err error)
 	ConvertToSsmInventoryItems(items []model.Item) (optimizedInventoryItems, nonOptimizedInventoryItems []*ssm.InventoryItem, err error)
 	GetDirtySsmInventoryItems(items []model.Item) (dirtyInventoryItems []*ssm.InventoryItem, err error)
+	IsFullUploadDue(interval time.Duration) bool
+	RecordFullUpload()
 }
 
 type SSMCaller interface {
@@ -55,7 +58,8 @@ type SSMCaller interface {
 type InventoryUploader struct {
 	context   context.T
 	ssm       SSMCaller
-	optimizer Optimizer //helps inventory plugin to optimize PutInventory calls
+	optimizer Optimizer           //helps inventory plugin to optimize PutInventory calls
+	queue     *storeforward.Queue //PutInventory calls that failed because the service could not be reached
 }
 
 // NewInventoryUploader creates a new InventoryUploader (which sends data to SSM Inventory)
@@ -69,8 +73,25 @@ func NewInventoryUploader(context context.T) (*InventoryUploader, error) {
 	log := c.Log()
 	appCfg = c.AppConfig()
 
+	uploader.ssm = newSSMCaller(c)
+	if appCfg.StoreAndForward.Enabled {
+		uploader.queue = storeforward.NewQueue(c.Identity(), storeforward.InventoryQueueName, appCfg.StoreAndForward, 0)
+	}
+
+	if uploader.optimizer, err = NewOptimizerImpl(context); err != nil {
+		log.Errorf("Unable to load optimizer for inventory uploader because - %v", err.Error())
+		return &uploader, err
+	}
+
+	return &uploader, nil
+}
+
+// newSSMCaller creates the ssm client used to call PutInventory
+func newSSMCaller(context context.T) SSMCaller {
+	appCfg := context.AppConfig()
+
 	// setting ssm client config
-	cfg := sdkutil.AwsConfig(c, "ssm")
+	cfg := sdkutil.AwsConfig(context, "ssm")
 
 	if appCfg.Ssm.Endpoint != "" {
 		cfg.Endpoint = &appCfg.Ssm.Endpoint
@@ -82,14 +103,24 @@ func NewInventoryUploader(context context.T) (*InventoryUploader, error) {
 	sess := session.New(cfg)
 	sess.Handlers.Build.PushBack(request.MakeAddToUserAgentHandler(appCfg.Agent.Name, appCfg.Agent.Version))
 
-	uploader.ssm = ssm.New(sess)
+	return ssm.New(sess)
+}
 
-	if uploader.optimizer, err = NewOptimizerImpl(context); err != nil {
-		log.Errorf("Unable to load optimizer for inventory uploader because - %v", err.Error())
-		return &uploader, err
+// NewQueuedInventorySender returns the sender of the inventory data queued while the service could not be reached
+func NewQueuedInventorySender(context context.T) storeforward.Sender {
+	var caller SSMCaller
+	return func(content []byte) (err error) {
+		var params ssm.PutInventoryInput
+		if err = json.Unmarshal(content, &params); err != nil {
+			context.Log().Warnf("Dropping invalid queued inventory data: %v", err)
+			return nil
+		}
+		if caller == nil {
+			caller = newSSMCaller(context)
+		}
+		_, err = caller.PutInventory(&params)
+		return err
 	}
-
-	return &uploader, nil
 }
 
 // SendDataToSSM uploads given inventory items to SSM
@@ -122,15 +153,33 @@ func (u *InventoryUploader) SendDataToSSM(items []*ssm.InventoryItem) (err error
 
 		if err != nil {
 			log.Errorf("the following error occured while calling PutInventory API: %v", err)
+			u.queueData(params, err)
 		} else {
 			log.Debugf("PutInventory was called successfully with response - %v", resp)
 			u.updateContentHash(items)
+			if u.queue != nil {
+				// the data queued before is outdated now
+				u.queue.Clear(log)
+			}
 		}
 	}
 
 	return
 }
 
+// queueData keeps the inventory data on disk if the service could not be reached
+func (u *InventoryUploader) queueData(params *ssm.PutInventoryInput, err error) {
+	if u.queue == nil || !storeforward.IsConnectivityError(err) {
+		return
+	}
+	log := u.context.Log()
+	if err = u.queue.Enqueue(log, params); err != nil {
+		log.Warnf("Failed to queue inventory data: %v", err)
+		return
+	}
+	log.Infof("Inventory data will be sent once the service can be reached")
+}
+
 // Get one random jitter time before calling PutInventory API to prevent huge number of request come to
 // the backend service in the same time.
 // Use current Time stamp + Hashcode of instance ID as random key
@@ -290,3 +339,17 @@ func (u InventoryUploader) GetDirtySsmInventoryItems(items []model.Item) (dirtyI
 
 	return
 }
+
+// IsFullUploadDue returns true if the last upload of all inventory types is older than the given interval.
+func (u *InventoryUploader) IsFullUploadDue(interval time.Duration) bool {
+	lastFullUpload := u.optimizer.GetLastFullUploadTime()
+	u.context.Log().Debugf("Last full inventory upload at %v", lastFullUpload)
+	return time.Since(lastFullUpload) >= interval
+}
+
+// RecordFullUpload remembers that all inventory types have been uploaded successfully.
+func (u *InventoryUploader) RecordFullUpload() {
+	if err := u.optimizer.UpdateLastFullUploadTime(time.Now()); err != nil {
+		u.context.Log().Error(err.Error())
+	}
+}
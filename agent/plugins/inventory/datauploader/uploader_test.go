@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/aws/amazon-ssm-agent/agent/mocks/context"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/mocks/datauploader"
@@ -232,3 +233,24 @@ func testSendData(t *testing.T, putInventorySucceeds bool) {
 	mockSSM.AssertExpectations(t)
 	mockOptimizer.AssertExpectations(t)
 }
+
+func TestIsFullUploadDue(t *testing.T) {
+	u := MockInventoryUploader()
+	optimizer := datauploader.NewMockDefault()
+	optimizer.On("GetLastFullUploadTime").Return(time.Now().Add(-2 * time.Hour)).Once()
+	optimizer.On("GetLastFullUploadTime").Return(time.Time{}).Once()
+	u.optimizer = optimizer
+
+	assert.False(t, u.IsFullUploadDue(24*time.Hour), "full upload should not be due within the interval")
+	assert.True(t, u.IsFullUploadDue(24*time.Hour), "full upload should be due if there was none before")
+}
+
+func TestRecordFullUpload(t *testing.T) {
+	u := MockInventoryUploader()
+	optimizer := datauploader.NewMockDefault()
+	optimizer.On("UpdateLastFullUploadTime", mock.AnythingOfType("time.Time")).Return(nil)
+	u.optimizer = optimizer
+
+	u.RecordFullUpload()
+	optimizer.AssertExpectations(t)
+}
@@ -56,12 +56,14 @@ func componentType(applicationName string) model.ComponentType {
 	return compType
 }
 
-// CollectApplicationData collects all application data from the system using platform specific queries and merges in applications installed via configurePackage
+// CollectApplicationData collects all application data from the system using platform specific queries and
+// appends packages installed through the language package managers enabled in appconfig
 func CollectApplicationData(context context.T) (appData []model.ApplicationData) {
 	if len(ApplicationData) > 0 {
 		return ApplicationData
 	}
 	ApplicationData = collectPlatformDependentApplicationData(context)
+	ApplicationData = append(ApplicationData, collectLanguagePackageData(context)...)
 	return ApplicationData
 }
 
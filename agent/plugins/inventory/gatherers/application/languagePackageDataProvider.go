@@ -0,0 +1,166 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package application
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/model"
+)
+
+const (
+	// application types reported for packages installed through language package managers
+	pipApplicationType = "Python Package (pip)"
+	npmApplicationType = "Node.js Package (npm)"
+	gemApplicationType = "Ruby Gem"
+)
+
+var (
+	pipCommands = []string{"pip3", "pip"}
+	pipArgs     = []string{"list", "--format=json", "--disable-pip-version-check"}
+	npmCommands = []string{"npm"}
+	npmArgs     = []string{"ls", "--global", "--json", "--depth=0"}
+	gemCommands = []string{"gem"}
+	gemArgs     = []string{"list", "--local"}
+
+	// gemListLine matches lines like: rake (13.0.6, default: 12.3.3)
+	gemListLine = regexp.MustCompile(`^([^\s()]+) \(([^)]*)\)$`)
+)
+
+// languagePackageManager describes how to query one language package manager for globally installed packages
+type languagePackageManager struct {
+	commands        []string
+	args            []string
+	applicationType string
+	parse           func(output []byte) ([]model.ApplicationData, error)
+}
+
+var languagePackageManagers = map[string]languagePackageManager{
+	appconfig.LanguagePackageManagerPip: {commands: pipCommands, args: pipArgs, applicationType: pipApplicationType, parse: parsePipOutput},
+	appconfig.LanguagePackageManagerNpm: {commands: npmCommands, args: npmArgs, applicationType: npmApplicationType, parse: parseNpmOutput},
+	appconfig.LanguagePackageManagerGem: {commands: gemCommands, args: gemArgs, applicationType: gemApplicationType, parse: parseGemOutput},
+}
+
+// decoupling for easy testability
+var lookPath = exec.LookPath
+var languagePackageCmdExecutor = executeCommand
+
+// collectLanguagePackageData collects globally installed packages of the language package managers enabled in appconfig
+func collectLanguagePackageData(context context.T) (appData []model.ApplicationData) {
+	log := context.Log()
+	for _, name := range context.AppConfig().Inventory.LanguagePackageManagers {
+		manager, found := languagePackageManagers[name]
+		if !found {
+			log.Warnf("Unsupported language package manager %v", name)
+			continue
+		}
+		data, err := manager.collect(log)
+		if err != nil {
+			log.Infof("Unable to collect %v packages - %v", name, err)
+			continue
+		}
+		log.Infof("Collected %d %v packages", len(data), name)
+		appData = append(appData, data...)
+	}
+	return
+}
+
+// collect runs the first available command of the package manager and parses its output
+func (m languagePackageManager) collect(log log.T) (data []model.ApplicationData, err error) {
+	for _, command := range m.commands {
+		if _, lookErr := lookPath(command); lookErr != nil {
+			continue
+		}
+		var output []byte
+		if output, err = languagePackageCmdExecutor(command, m.args...); err != nil {
+			log.Debugf("Command %v failed with output: %v", command, string(output))
+			return nil, fmt.Errorf("%v failed: %v", command, err)
+		}
+		if data, err = m.parse(output); err != nil {
+			return nil, err
+		}
+		for i := range data {
+			data[i].ApplicationType = m.applicationType
+		}
+		sort.Sort(model.ByNamePublisherVersion(data))
+		return data, nil
+	}
+	return nil, fmt.Errorf("none of %v found", m.commands)
+}
+
+// parsePipOutput parses the output of pip list --format=json
+func parsePipOutput(output []byte) (data []model.ApplicationData, err error) {
+	var packages []struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+	if err = json.Unmarshal(extractJSON(output, "["), &packages); err != nil {
+		return nil, fmt.Errorf("unable to parse pip output - %v", err)
+	}
+	for _, p := range packages {
+		data = append(data, model.ApplicationData{Name: p.Name, Version: p.Version})
+	}
+	return
+}
+
+// parseNpmOutput parses the output of npm ls --global --json --depth=0
+func parseNpmOutput(output []byte) (data []model.ApplicationData, err error) {
+	var tree struct {
+		Dependencies map[string]struct {
+			Version string `json:"version"`
+		} `json:"dependencies"`
+	}
+	if err = json.Unmarshal(extractJSON(output, "{"), &tree); err != nil {
+		return nil, fmt.Errorf("unable to parse npm output - %v", err)
+	}
+	for name, dependency := range tree.Dependencies {
+		data = append(data, model.ApplicationData{Name: name, Version: dependency.Version})
+	}
+	return
+}
+
+// parseGemOutput parses the output of gem list --local, reporting one entry per installed version
+func parseGemOutput(output []byte) (data []model.ApplicationData, err error) {
+	for _, line := range strings.Split(string(output), "\n") {
+		matches := gemListLine.FindStringSubmatch(strings.TrimSpace(line))
+		if matches == nil {
+			continue
+		}
+		for _, version := range strings.Split(matches[2], ",") {
+			version = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(version), "default:"))
+			if version == "" {
+				continue
+			}
+			data = append(data, model.ApplicationData{Name: matches[1], Version: version})
+		}
+	}
+	return
+}
+
+// extractJSON drops anything printed before the JSON document, e.g. deprecation warnings
+func extractJSON(output []byte, start string) []byte {
+	str := string(output)
+	if index := strings.Index(str, start); index > 0 {
+		str = str[index:]
+	}
+	return []byte(str)
+}
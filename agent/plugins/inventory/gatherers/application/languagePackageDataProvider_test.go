@@ -0,0 +1,110 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package application
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	contextmocks "github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/model"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	samplePipOutput = `WARNING: pip is being invoked by an old script wrapper.
+[{"name": "requests", "version": "2.31.0"}, {"name": "boto3", "version": "1.28.0"}]`
+	sampleNpmOutput = `{
+  "dependencies": {
+    "npm": {"version": "9.8.1", "overridden": false},
+    "typescript": {"version": "5.2.2", "overridden": false}
+  }
+}`
+	sampleGemOutput = `
+*** LOCAL GEMS ***
+
+bigdecimal (default: 3.1.1)
+rake (13.0.6, 12.3.3)
+nokogiri (1.15.4 x86_64-linux)
+`
+)
+
+func TestParsePipOutput(t *testing.T) {
+	data, err := parsePipOutput([]byte(samplePipOutput))
+	assert.NoError(t, err)
+	assert.Equal(t, []model.ApplicationData{
+		{Name: "requests", Version: "2.31.0"},
+		{Name: "boto3", Version: "1.28.0"},
+	}, data)
+
+	_, err = parsePipOutput([]byte("not json"))
+	assert.Error(t, err)
+}
+
+func TestParseNpmOutput(t *testing.T) {
+	data, err := parseNpmOutput([]byte(sampleNpmOutput))
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []model.ApplicationData{
+		{Name: "npm", Version: "9.8.1"},
+		{Name: "typescript", Version: "5.2.2"},
+	}, data)
+}
+
+func TestParseGemOutput(t *testing.T) {
+	data, err := parseGemOutput([]byte(sampleGemOutput))
+	assert.NoError(t, err)
+	assert.Equal(t, []model.ApplicationData{
+		{Name: "bigdecimal", Version: "3.1.1"},
+		{Name: "rake", Version: "13.0.6"},
+		{Name: "rake", Version: "12.3.3"},
+		{Name: "nokogiri", Version: "1.15.4 x86_64-linux"},
+	}, data)
+}
+
+func TestCollectLanguagePackageData(t *testing.T) {
+	lookPathBackup, executorBackup := lookPath, languagePackageCmdExecutor
+	defer func() { lookPath, languagePackageCmdExecutor = lookPathBackup, executorBackup }()
+
+	lookPath = func(file string) (string, error) {
+		if file == "pip" || file == "npm" {
+			return "/usr/bin/" + file, nil
+		}
+		return "", errors.New("not found")
+	}
+	languagePackageCmdExecutor = func(command string, args ...string) ([]byte, error) {
+		switch command {
+		case "pip":
+			return []byte(samplePipOutput), nil
+		case "npm":
+			return nil, errors.New("exit status 1")
+		}
+		return nil, errors.New("unexpected command " + command)
+	}
+
+	config := appconfig.DefaultConfig()
+	config.Inventory.LanguagePackageManagers = []string{appconfig.LanguagePackageManagerPip, appconfig.LanguagePackageManagerNpm, appconfig.LanguagePackageManagerGem}
+	contextMock := contextmocks.NewMockDefaultWithConfig(config)
+
+	data := collectLanguagePackageData(contextMock)
+	assert.Equal(t, []model.ApplicationData{
+		{Name: "boto3", Version: "1.28.0", ApplicationType: pipApplicationType},
+		{Name: "requests", Version: "2.31.0", ApplicationType: pipApplicationType},
+	}, data)
+}
+
+func TestCollectLanguagePackageData_Disabled(t *testing.T) {
+	contextMock := contextmocks.NewMockDefault()
+	assert.Empty(t, collectLanguagePackageData(contextMock))
+}
@@ -0,0 +1,52 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	contextmocks "github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/model"
+	"github.com/stretchr/testify/assert"
+)
+
+var testCertificates = []model.CertificateData{
+	{
+		Subject:                 "CN=internal.example.com,O=Example",
+		Issuer:                  "CN=Example Internal CA,O=Example",
+		SerialNumber:            "1A2B",
+		SubjectAlternativeNames: "internal.example.com,10.0.0.1",
+		NotBefore:               "2024-01-01T00:00:00Z",
+		NotAfter:                "2025-01-01T00:00:00Z",
+		Thumbprint:              "0123456789ABCDEF0123456789ABCDEF01234567",
+		Location:                "/etc/ssl/certs/internal.pem",
+	},
+}
+
+func testCollectCertificateData(context context.T, config model.Config) (data []model.CertificateData, err error) {
+	return testCertificates, nil
+}
+
+func TestGatherer(t *testing.T) {
+	contextMock := contextmocks.NewMockDefault()
+	gatherer := Gatherer(contextMock)
+	collectData = testCollectCertificateData
+	item, err := gatherer.Run(contextMock, model.Config{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(item))
+	assert.Equal(t, GathererName, item[0].Name)
+	assert.Equal(t, SchemaVersionOfCertificateGatherer, item[0].SchemaVersion)
+	assert.Equal(t, testCertificates, item[0].Content)
+}
@@ -0,0 +1,210 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/model"
+)
+
+// filterObj describes one certificate location to scan.
+// Path is either a directory or file on disk or, on Windows, a certificate store such as Cert:\LocalMachine\My
+type filterObj struct {
+	Path      string
+	Recursive bool
+}
+
+// Limits to help keep certificate information under item size limit and prevent long scanning.
+const CertificateCountLimit = 1000
+const CertificateCountLimitExceeded = "Certificate Count Limit Exceeded"
+
+// maxCertificateFileSize skips files that are too large to be certificate bundles
+const maxCertificateFileSize = 4 * 1024 * 1024
+
+var CertificateCountLimitError = errors.New(CertificateCountLimitExceeded)
+
+// decoupling for easy testability
+var readFile = os.ReadFile
+var filepathWalk = filepath.Walk
+var readCertificateStoreFunc = readCertificateStore
+
+// LogError is a wrapper on log.Error for easy testability
+func LogError(log log.T, err error) {
+	// To debug unit test, please uncomment following line
+	// fmt.Println(err)
+	log.Error(err)
+}
+
+// parseFilters returns the locations to scan. Collection value Enabled scans the platform default locations.
+func parseFilters(config model.Config) (filters []filterObj, err error) {
+	if config.Filters == "" || config.Filters == model.Enabled {
+		for _, path := range defaultCertificateLocations {
+			filters = append(filters, filterObj{Path: path})
+		}
+		return
+	}
+	err = json.Unmarshal([]byte(strings.Replace(config.Filters, `\`, `/`, -1)), &filters)
+	return
+}
+
+// parseCertificates decodes all PEM blocks in raw, falling back to a single DER encoded certificate
+func parseCertificates(raw []byte) (certs []*x509.Certificate) {
+	rest := raw
+	for {
+		var block *pem.Block
+		if block, rest = pem.Decode(rest); block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
+			certs = append(certs, cert)
+		}
+	}
+	if len(certs) == 0 {
+		if cert, err := x509.ParseCertificate(raw); err == nil {
+			certs = append(certs, cert)
+		}
+	}
+	return
+}
+
+// subjectAlternativeNames flattens all SAN entries of a certificate into a comma separated list
+func subjectAlternativeNames(cert *x509.Certificate) string {
+	var names []string
+	names = append(names, cert.DNSNames...)
+	names = append(names, cert.EmailAddresses...)
+	for _, ip := range cert.IPAddresses {
+		names = append(names, ip.String())
+	}
+	for _, uri := range cert.URIs {
+		names = append(names, uri.String())
+	}
+	return strings.Join(names, ",")
+}
+
+// toCertificateData converts a parsed certificate into the inventory representation
+func toCertificateData(cert *x509.Certificate, location string) model.CertificateData {
+	thumbprint := sha1.Sum(cert.Raw)
+	return model.CertificateData{
+		Subject:                 cert.Subject.String(),
+		Issuer:                  cert.Issuer.String(),
+		SerialNumber:            strings.ToUpper(cert.SerialNumber.Text(16)),
+		SubjectAlternativeNames: subjectAlternativeNames(cert),
+		NotBefore:               cert.NotBefore.UTC().Format(time.RFC3339),
+		NotAfter:                cert.NotAfter.UTC().Format(time.RFC3339),
+		Thumbprint:              strings.ToUpper(hex.EncodeToString(thumbprint[:])),
+		Location:                location,
+	}
+}
+
+// getCertificatesFromPath scans a file or directory for certificate files
+func getCertificatesFromPath(log log.T, root string, recursive bool) (data []model.CertificateData, err error) {
+	err = filepathWalk(root, func(path string, fi os.FileInfo, walkErr error) error {
+		if walkErr != nil {
+			log.Debugf("Skipping %v - %v", path, walkErr)
+			return nil
+		}
+		if fi.IsDir() {
+			if path != root && !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !fi.Mode().IsRegular() && fi.Mode()&os.ModeSymlink == 0 {
+			return nil
+		}
+		if fi.Size() > maxCertificateFileSize {
+			return nil
+		}
+		raw, readErr := readFile(path)
+		if readErr != nil {
+			log.Debugf("Unable to read %v - %v", path, readErr)
+			return nil
+		}
+		for _, cert := range parseCertificates(raw) {
+			if len(data) >= CertificateCountLimit {
+				return CertificateCountLimitError
+			}
+			data = append(data, toCertificateData(cert, path))
+		}
+		return nil
+	})
+	return
+}
+
+// removeDuplicates keeps the first location a certificate was found at, since
+// bundles and hash links usually contain the same certificate many times
+func removeDuplicates(elements []model.CertificateData) (result []model.CertificateData) {
+	encountered := map[string]bool{}
+	for _, element := range elements {
+		if !encountered[element.Thumbprint] {
+			encountered[element.Thumbprint] = true
+			result = append(result, element)
+		}
+	}
+	return
+}
+
+// collectCertificateData returns a list of certificates found in the configured locations
+func collectCertificateData(context context.T, config model.Config) (data []model.CertificateData, err error) {
+	log := context.Log()
+	var filters []filterObj
+	if filters, err = parseFilters(config); err != nil {
+		LogError(log, err)
+		return
+	}
+
+	for _, filter := range filters {
+		var found []model.CertificateData
+		var scanErr error
+		if isCertificateStore(filter.Path) {
+			found, scanErr = readCertificateStoreFunc(log, filter.Path)
+		} else {
+			found, scanErr = getCertificatesFromPath(log, os.ExpandEnv(filepath.FromSlash(filter.Path)), filter.Recursive)
+		}
+		if scanErr != nil {
+			LogError(log, scanErr)
+			if scanErr == CertificateCountLimitError {
+				return nil, CertificateCountLimitError
+			}
+		}
+		// filters that each stay under the limit may still add up to more certificates than the limit
+		data = removeDuplicates(append(data, found...))
+		if len(data) > CertificateCountLimit {
+			LogError(log, CertificateCountLimitError)
+			return nil, CertificateCountLimitError
+		}
+	}
+
+	sort.SliceStable(data, func(i, j int) bool {
+		return data[i].NotAfter < data[j].NotAfter
+	})
+	log.Infof("Collected Certificates %d", len(data))
+	return
+}
@@ -0,0 +1,146 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package certificate
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	contextmocks "github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestCertificate(t *testing.T, commonName string, notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(0x1a2b),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     []string{commonName},
+		IPAddresses:  []net.IP{net.ParseIP("10.0.0.1")},
+		NotBefore:    notAfter.Add(-24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	return der
+}
+
+func TestParseCertificates_PEMBundle(t *testing.T) {
+	notAfter := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	first := createTestCertificate(t, "first.example.com", notAfter)
+	second := createTestCertificate(t, "second.example.com", notAfter)
+	bundle := append(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: first}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("ignored")})...)
+	bundle = append(bundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: second})...)
+
+	certs := parseCertificates(bundle)
+	assert.Equal(t, 2, len(certs))
+	assert.Equal(t, "first.example.com", certs[0].Subject.CommonName)
+	assert.Equal(t, "second.example.com", certs[1].Subject.CommonName)
+}
+
+func TestParseCertificates_DER(t *testing.T) {
+	der := createTestCertificate(t, "der.example.com", time.Now().Add(time.Hour))
+	certs := parseCertificates(der)
+	assert.Equal(t, 1, len(certs))
+	assert.Empty(t, parseCertificates([]byte("not a certificate")))
+}
+
+func TestToCertificateData(t *testing.T) {
+	notAfter := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	cert, err := x509.ParseCertificate(createTestCertificate(t, "internal.example.com", notAfter))
+	assert.NoError(t, err)
+
+	data := toCertificateData(cert, "/etc/ssl/certs/internal.pem")
+	assert.Equal(t, "CN=internal.example.com", data.Subject)
+	assert.Equal(t, "CN=internal.example.com", data.Issuer)
+	assert.Equal(t, "1A2B", data.SerialNumber)
+	assert.Equal(t, "internal.example.com,10.0.0.1", data.SubjectAlternativeNames)
+	assert.Equal(t, "2030-01-01T00:00:00Z", data.NotAfter)
+	assert.Equal(t, "2029-12-31T00:00:00Z", data.NotBefore)
+	assert.Equal(t, 40, len(data.Thumbprint))
+	assert.Equal(t, "/etc/ssl/certs/internal.pem", data.Location)
+}
+
+func TestCollectCertificateData(t *testing.T) {
+	dir := t.TempDir()
+	nested := filepath.Join(dir, "nested")
+	assert.NoError(t, os.Mkdir(nested, 0700))
+
+	later := createTestCertificate(t, "later.example.com", time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC))
+	sooner := createTestCertificate(t, "sooner.example.com", time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "later.pem"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: later}), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "later-copy.crt"), later, 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("hello"), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(nested, "sooner.pem"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: sooner}), 0600))
+
+	contextMock := contextmocks.NewMockDefault()
+
+	filters := `[{"Path": "` + filepath.ToSlash(dir) + `", "Recursive": false}]`
+	data, err := collectCertificateData(contextMock, model.Config{Filters: filters})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(data))
+	assert.Equal(t, "CN=later.example.com", data[0].Subject)
+
+	filters = `[{"Path": "` + filepath.ToSlash(dir) + `", "Recursive": true}]`
+	data, err = collectCertificateData(contextMock, model.Config{Filters: filters})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(data))
+	assert.Equal(t, "CN=sooner.example.com", data[0].Subject)
+	assert.Equal(t, "CN=later.example.com", data[1].Subject)
+}
+
+func TestCollectCertificateData_CountLimitAcrossFilters(t *testing.T) {
+	notAfter := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	var dirs []string
+	for d := 0; d < 2; d++ {
+		dir := t.TempDir()
+		for i := 0; i < CertificateCountLimit/2+1; i++ {
+			cert := createTestCertificate(t, fmt.Sprintf("host%v-%v.example.com", d, i), notAfter)
+			assert.NoError(t, os.WriteFile(filepath.Join(dir, fmt.Sprintf("%v.crt", i)), cert, 0600))
+		}
+		dirs = append(dirs, filepath.ToSlash(dir))
+	}
+
+	contextMock := contextmocks.NewMockDefault()
+	filters := `[{"Path": "` + dirs[0] + `", "Recursive": false}, {"Path": "` + dirs[1] + `", "Recursive": false}]`
+	data, err := collectCertificateData(contextMock, model.Config{Filters: filters})
+	assert.Equal(t, CertificateCountLimitError, err)
+	assert.Nil(t, data)
+
+	// the same certificates found by overlapping filters are only counted once
+	filters = `[{"Path": "` + dirs[0] + `", "Recursive": false}, {"Path": "` + dirs[0] + `", "Recursive": false}]`
+	data, err = collectCertificateData(contextMock, model.Config{Filters: filters})
+	assert.NoError(t, err)
+	assert.Equal(t, CertificateCountLimit/2+1, len(data))
+}
+
+func TestCollectCertificateData_InvalidFilters(t *testing.T) {
+	contextMock := contextmocks.NewMockDefault()
+	_, err := collectCertificateData(contextMock, model.Config{Filters: "[{invalid"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,40 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build darwin || freebsd || linux || netbsd || openbsd
+// +build darwin freebsd linux netbsd openbsd
+
+package certificate
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/model"
+)
+
+// defaultCertificateLocations are scanned when the gatherer is enabled without filters
+var defaultCertificateLocations = []string{
+	"/etc/ssl/certs",
+	"/etc/pki/tls/certs",
+	"/usr/local/share/ca-certificates",
+}
+
+// isCertificateStore returns false as certificate stores only exist on Windows
+func isCertificateStore(path string) bool {
+	return false
+}
+
+func readCertificateStore(log log.T, store string) (data []model.CertificateData, err error) {
+	return nil, fmt.Errorf("certificate stores are not supported on this platform")
+}
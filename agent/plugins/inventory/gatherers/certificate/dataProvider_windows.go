@@ -0,0 +1,88 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build windows
+// +build windows
+
+package certificate
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/model"
+)
+
+const certificateStorePrefix = "cert:/"
+
+var (
+	PowershellCmd = appconfig.PowerShellPluginCommandName
+
+	// certificateStoreScript prints the raw data of every certificate in a store as one base64 string per line
+	certificateStoreScript = `
+[Console]::OutputEncoding = [System.Text.Encoding]::UTF8
+Get-ChildItem -Path '%s' | Where-Object { $_ -is [System.Security.Cryptography.X509Certificates.X509Certificate2] } | ForEach-Object { [Console]::WriteLine([Convert]::ToBase64String($_.RawData)) }
+`
+)
+
+// defaultCertificateLocations are scanned when the gatherer is enabled without filters
+var defaultCertificateLocations = []string{
+	"Cert:/LocalMachine/My",
+	"Cert:/LocalMachine/WebHosting",
+	"Cert:/LocalMachine/Remote Desktop",
+}
+
+var cmdExecutor = executeCommand
+
+func executeCommand(command string, args ...string) ([]byte, error) {
+	return exec.Command(command, args...).CombinedOutput()
+}
+
+// isCertificateStore returns true if path refers to the PowerShell certificate provider
+func isCertificateStore(path string) bool {
+	return strings.HasPrefix(strings.ToLower(path), certificateStorePrefix)
+}
+
+// readCertificateStore reads all certificates of a Windows certificate store through PowerShell
+func readCertificateStore(log log.T, store string) (data []model.CertificateData, err error) {
+	store = strings.Replace(store, `/`, `\`, -1)
+	script := fmt.Sprintf(certificateStoreScript, strings.Replace(store, `'`, `''`, -1))
+	var output []byte
+	if output, err = cmdExecutor(PowershellCmd, script); err != nil {
+		log.Debugf("Command Stderr: %v", string(output))
+		return nil, fmt.Errorf("unable to read certificate store %v: %v", store, err)
+	}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		raw, decodeErr := base64.StdEncoding.DecodeString(line)
+		if decodeErr != nil {
+			log.Debugf("Skipping unexpected output line from certificate store %v", store)
+			continue
+		}
+		for _, cert := range parseCertificates(raw) {
+			if len(data) >= CertificateCountLimit {
+				return data, CertificateCountLimitError
+			}
+			data = append(data, toCertificateData(cert, store))
+		}
+	}
+	return
+}
@@ -140,6 +140,17 @@ func (t *T) Run(context context.T, configuration model.Config) (items []model.It
 		}
 	}
 
+	// Get custom inventory items from script gatherers configured in appconfig
+	for _, scriptItem := range getItemsFromScripts(log, context.AppConfig().Inventory.ScriptGatherers) {
+		if _, ok := setTypeName[scriptItem.Name]; ok {
+			LogError(log, fmt.Errorf("Custom inventory typeName (%v) from script gatherer already exists,"+
+				" please use a different typeName for the script gatherer.", scriptItem.Name))
+			continue
+		}
+		setTypeName[scriptItem.Name] = true
+		items = append(items, scriptItem)
+	}
+
 	count := len(items)
 	log.Debugf("Count of custom inventory items : %v.", count)
 	if count == 0 {
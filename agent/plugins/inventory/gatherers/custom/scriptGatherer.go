@@ -0,0 +1,151 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package custom
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/model"
+)
+
+const (
+	// stderrLimitBytes limits how much of a script gatherer's standard error is kept for logging
+	stderrLimitBytes = 4096
+	// scriptWaitDelay is how long to wait for output pipes to close once the script was killed
+	scriptWaitDelay = time.Second
+)
+
+var errOutputLimitExceeded = errors.New("output size limit exceeded")
+
+// decoupling for easy testability
+var runScriptFunc = runScript
+var validateScriptFileFunc = validateScriptFile
+
+// limitedBuffer is an io.Writer that fails once more than limit bytes are written
+type limitedBuffer struct {
+	buffer   bytes.Buffer
+	limit    int
+	exceeded bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.buffer.Len()+len(p) > b.limit {
+		b.buffer.Write(p[:b.limit-b.buffer.Len()])
+		b.exceeded = true
+		return 0, errOutputLimitExceeded
+	}
+	return b.buffer.Write(p)
+}
+
+// getItemsFromScripts runs the script gatherers configured in appconfig and converts their output to inventory items
+func getItemsFromScripts(log log.T, scriptGatherers []*appconfig.ScriptGathererCfg) (items []model.Item) {
+	for _, config := range scriptGatherers {
+		item, err := getItemFromScript(log, config)
+		if err != nil {
+			LogError(log, fmt.Errorf("Failed to get item from script gatherer %v, error %v. continue...", config.Path, err))
+			continue
+		}
+		items = append(items, item)
+	}
+	return
+}
+
+// RunScript validates and runs an operator provided executable with the same restrictions as script gatherers
+// and returns its standard output
+func RunScript(log log.T, config *appconfig.ScriptGathererCfg) (output []byte, err error) {
+	if err = validateScriptFileFunc(config.Path); err != nil {
+		return
+	}
+	return runScriptFunc(log, config)
+}
+
+// getItemFromScript runs one script gatherer and validates its output like a custom inventory file
+func getItemFromScript(log log.T, config *appconfig.ScriptGathererCfg) (item model.Item, err error) {
+	if err = validateScriptFileFunc(config.Path); err != nil {
+		return
+	}
+
+	var output []byte
+	start := time.Now()
+	if output, err = runScriptFunc(log, config); err != nil {
+		return
+	}
+	log.Infof("execution time for script gatherer - %v: %s", config.Path, time.Since(start))
+
+	// scripts only print the content, type name and schema version come from the agent configuration
+	var content []byte
+	if content, err = json.Marshal(struct {
+		TypeName      string
+		SchemaVersion string
+		Content       json.RawMessage
+	}{config.TypeName, config.SchemaVersion, json.RawMessage(bytes.TrimSpace(output))}); err != nil {
+		return item, fmt.Errorf("script output is not valid json: %v", err)
+	}
+	return convertToItem(log, content)
+}
+
+// runScript executes the script with a minimal environment, a private working directory,
+// a timeout and a limit on the size of its output
+func runScript(log log.T, config *appconfig.ScriptGathererCfg) (output []byte, err error) {
+	var workingDir string
+	if workingDir, err = os.MkdirTemp("", "ssm-inventory-script"); err != nil {
+		return
+	}
+	defer os.RemoveAll(workingDir)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(config.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	stdout := &limitedBuffer{limit: config.MaxOutputSizeKB * 1024}
+	stderr := &limitedBuffer{limit: stderrLimitBytes}
+	cmd := exec.CommandContext(ctx, config.Path)
+	cmd.Dir = workingDir
+	cmd.Env = scriptEnvironment(workingDir)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	// child processes inheriting the output pipes must not keep the gatherer waiting after a timeout
+	cmd.WaitDelay = scriptWaitDelay
+
+	err = cmd.Run()
+	if stderr.buffer.Len() > 0 {
+		log.Debugf("Script gatherer %v stderr: %v", config.Path, stderr.buffer.String())
+	}
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		return nil, fmt.Errorf("script timed out after %v seconds", config.TimeoutSeconds)
+	case stdout.exceeded:
+		return nil, fmt.Errorf("script output exceeded the limit of %v KB", config.MaxOutputSizeKB)
+	case err != nil:
+		return nil, fmt.Errorf("script failed: %v", err)
+	}
+	return stdout.buffer.Bytes(), nil
+}
+
+// validateScriptPath rejects relative paths so the executed file does not depend on the agent's working directory
+func validateScriptPath(path string) error {
+	if !filepath.IsAbs(path) {
+		return fmt.Errorf("script gatherer path %v must be absolute", path)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build darwin || freebsd || linux || netbsd || openbsd
+// +build darwin freebsd linux netbsd openbsd
+
+package custom
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// scriptEnvironment returns the environment script gatherers are started with
+func scriptEnvironment(workingDir string) []string {
+	return []string{
+		"PATH=/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin:/sbin:/bin",
+		"HOME=" + workingDir,
+		"TMPDIR=" + workingDir,
+		"LANG=C",
+	}
+}
+
+// validateScriptFile makes sure the script can not be replaced by unprivileged users,
+// as it is executed with the privileges of the agent
+func validateScriptFile(path string) error {
+	if err := validateScriptPath(path); err != nil {
+		return err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("script gatherer %v is not a regular file", path)
+	}
+	if info.Mode().Perm()&0022 != 0 {
+		return fmt.Errorf("script gatherer %v must not be writable by group or others", path)
+	}
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok && stat.Uid != 0 && int(stat.Uid) != os.Geteuid() {
+		return fmt.Errorf("script gatherer %v must be owned by root or the agent user", path)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build darwin || freebsd || linux || netbsd || openbsd
+// +build darwin freebsd linux netbsd openbsd
+
+package custom
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestScript(t *testing.T, body string, mode os.FileMode) string {
+	path := filepath.Join(t.TempDir(), "gather.sh")
+	assert.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), mode))
+	assert.NoError(t, os.Chmod(path, mode))
+	return path
+}
+
+func testScriptConfig(path string) *appconfig.ScriptGathererCfg {
+	return &appconfig.ScriptGathererCfg{
+		Path:            path,
+		TypeName:        "Custom:Proprietary",
+		SchemaVersion:   "1.0",
+		TimeoutSeconds:  5,
+		MaxOutputSizeKB: 1,
+	}
+}
+
+func TestGetItemFromScript(t *testing.T) {
+	log := context.NewMockDefault().Log()
+	path := writeTestScript(t, `echo '[{"Name": "app", "Version": "1.0"}]'`, 0700)
+
+	item, err := getItemFromScript(log, testScriptConfig(path))
+	assert.NoError(t, err)
+	assert.Equal(t, "Custom:Proprietary", item.Name)
+	assert.Equal(t, "1.0", item.SchemaVersion)
+	assert.Equal(t, []map[string]interface{}{{"Name": "app", "Version": "1.0"}}, item.Content)
+}
+
+func TestGetItemFromScript_MinimalEnvironment(t *testing.T) {
+	log := context.NewMockDefault().Log()
+	os.Setenv("SSM_TEST_SECRET", "secret")
+	defer os.Unsetenv("SSM_TEST_SECRET")
+	path := writeTestScript(t, `echo "[{\"Secret\": \"$SSM_TEST_SECRET\", \"Dir\": \"$(pwd)\"}]"`, 0700)
+
+	item, err := getItemFromScript(log, testScriptConfig(path))
+	assert.NoError(t, err)
+	content := item.Content.([]map[string]interface{})
+	assert.Equal(t, "", content[0]["Secret"])
+	assert.NotEqual(t, "", content[0]["Dir"])
+	_, statErr := os.Stat(content[0]["Dir"].(string))
+	assert.True(t, os.IsNotExist(statErr), "working directory should be removed")
+}
+
+func TestGetItemFromScript_InvalidOutput(t *testing.T) {
+	log := context.NewMockDefault().Log()
+	path := writeTestScript(t, `echo 'not json'`, 0700)
+	_, err := getItemFromScript(log, testScriptConfig(path))
+	assert.Error(t, err)
+
+	path = writeTestScript(t, `echo '[{"Count": 1}]'`, 0700)
+	_, err = getItemFromScript(log, testScriptConfig(path))
+	assert.Error(t, err, "only string attribute values are supported")
+}
+
+func TestGetItemFromScript_InvalidTypeName(t *testing.T) {
+	log := context.NewMockDefault().Log()
+	path := writeTestScript(t, `echo '[{"Name": "app"}]'`, 0700)
+	config := testScriptConfig(path)
+	config.TypeName = "AWS:Application"
+	_, err := getItemFromScript(log, config)
+	assert.Error(t, err)
+}
+
+func TestGetItemFromScript_Limits(t *testing.T) {
+	log := context.NewMockDefault().Log()
+	path := writeTestScript(t, `head -c 4096 /dev/zero`, 0700)
+	_, err := getItemFromScript(log, testScriptConfig(path))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeded the limit")
+
+	path = writeTestScript(t, `sleep 5`, 0700)
+	config := testScriptConfig(path)
+	config.TimeoutSeconds = 1
+	_, err = getItemFromScript(log, config)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out")
+}
+
+func TestValidateScriptFile(t *testing.T) {
+	assert.Error(t, validateScriptFile("relative/gather.sh"))
+	assert.Error(t, validateScriptFile(filepath.Join(t.TempDir(), "missing.sh")))
+	assert.Error(t, validateScriptFile(writeTestScript(t, "true", 0777)))
+	assert.NoError(t, validateScriptFile(writeTestScript(t, "true", 0700)))
+}
+
+func TestGetItemsFromScripts_SkipsFailures(t *testing.T) {
+	log := context.NewMockDefault().Log()
+	valid := writeTestScript(t, `echo '{"Name": "app"}'`, 0700)
+	failing := writeTestScript(t, `exit 1`, 0700)
+
+	items := getItemsFromScripts(log, []*appconfig.ScriptGathererCfg{testScriptConfig(failing), testScriptConfig(valid)})
+	assert.Equal(t, []model.Item{items[0]}, items)
+	assert.Equal(t, "Custom:Proprietary", items[0].Name)
+}
@@ -0,0 +1,49 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build windows
+// +build windows
+
+package custom
+
+import (
+	"fmt"
+	"os"
+)
+
+// scriptEnvironment returns the environment script gatherers are started with
+func scriptEnvironment(workingDir string) []string {
+	return []string{
+		"SystemRoot=" + os.Getenv("SystemRoot"),
+		"PATH=" + os.Getenv("SystemRoot") + `\System32;` + os.Getenv("SystemRoot") + `\System32\WindowsPowerShell\v1.0`,
+		"PATHEXT=" + os.Getenv("PATHEXT"),
+		"TEMP=" + workingDir,
+		"TMP=" + workingDir,
+	}
+}
+
+// validateScriptFile makes sure the script is an existing regular file.
+// Access to the file is expected to be restricted through its ACL.
+func validateScriptFile(path string) error {
+	if err := validateScriptPath(path); err != nil {
+		return err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("script gatherer %v is not a regular file", path)
+	}
+	return nil
+}
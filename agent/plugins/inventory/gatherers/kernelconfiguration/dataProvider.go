@@ -0,0 +1,228 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kernelconfiguration
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/model"
+)
+
+const (
+	// SysctlCountLimit keeps the sysctl data under the item size limit
+	SysctlCountLimit = 1000
+)
+
+var (
+	procModulesPath = "/proc/modules"
+	procSysPath     = "/proc/sys"
+
+	// modprobeConfigDirs are searched for modules disabled through blacklist or install directives
+	modprobeConfigDirs = []string{"/etc/modprobe.d", "/run/modprobe.d", "/usr/lib/modprobe.d", "/lib/modprobe.d"}
+
+	// disabledInstallCommands are install directive commands that prevent a module from being loaded
+	disabledInstallCommands = map[string]bool{"/bin/true": true, "/bin/false": true, "/usr/bin/true": true, "/usr/bin/false": true}
+
+	// defaultSysctlAllowlist is reported when the gatherer is enabled without a custom allowlist
+	defaultSysctlAllowlist = []string{
+		"fs.suid_dumpable",
+		"kernel.dmesg_restrict",
+		"kernel.kptr_restrict",
+		"kernel.randomize_va_space",
+		"kernel.yama.ptrace_scope",
+		"net.ipv4.conf.all.accept_redirects",
+		"net.ipv4.conf.all.accept_source_route",
+		"net.ipv4.conf.all.log_martians",
+		"net.ipv4.conf.all.rp_filter",
+		"net.ipv4.conf.all.send_redirects",
+		"net.ipv4.icmp_echo_ignore_broadcasts",
+		"net.ipv4.ip_forward",
+		"net.ipv4.tcp_syncookies",
+		"net.ipv6.conf.all.accept_ra",
+		"net.ipv6.conf.all.disable_ipv6",
+		"net.ipv6.conf.all.forwarding",
+	}
+)
+
+// decoupling for easy testability
+var openFile = os.Open
+var readFile = os.ReadFile
+var globFiles = filepath.Glob
+
+// LogError is a wrapper on log.Error for easy testability
+func LogError(log log.T, err error) {
+	// To debug unit test, please uncomment following line
+	// fmt.Println(err)
+	log.Error(err)
+}
+
+// normalizeModuleName converts a module name to the form used in /proc/modules, where dashes are underscores
+func normalizeModuleName(name string) string {
+	return strings.Replace(name, "-", "_", -1)
+}
+
+// collectKernelModuleData returns the loaded kernel modules and the modules disabled through modprobe configuration
+func collectKernelModuleData(context context.T) (data []model.KernelModuleData, err error) {
+	log := context.Log()
+
+	disabled := readDisabledModules(log)
+	loaded := map[string]bool{}
+
+	var file *os.File
+	if file, err = openFile(procModulesPath); err != nil {
+		if os.IsNotExist(err) {
+			log.Infof("%v is not available - no kernel module data to report", procModulesPath)
+			err = nil
+		}
+		return
+	}
+	defer file.Close()
+
+	// format: name size refcount dependencies state address
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 {
+			continue
+		}
+		loaded[fields[0]] = true
+		data = append(data, model.KernelModuleData{
+			Name:     fields[0],
+			Loaded:   "true",
+			Disabled: fmt.Sprint(disabled[fields[0]]),
+			Size:     fields[1],
+			UsedBy:   strings.Trim(strings.Replace(fields[3], "-", "", -1), ","),
+			State:    fields[4],
+		})
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for name := range disabled {
+		if !loaded[name] {
+			data = append(data, model.KernelModuleData{Name: name, Loaded: "false", Disabled: "true"})
+		}
+	}
+	sort.Slice(data, func(i, j int) bool { return data[i].Name < data[j].Name })
+	log.Infof("Collected %d kernel modules", len(data))
+	return
+}
+
+// readDisabledModules returns the modules blacklisted or replaced by a no-op install command in modprobe configuration
+func readDisabledModules(log log.T) map[string]bool {
+	disabled := map[string]bool{}
+	for _, dir := range modprobeConfigDirs {
+		files, _ := globFiles(filepath.Join(dir, "*.conf"))
+		for _, configFile := range files {
+			content, err := readFile(configFile)
+			if err != nil {
+				log.Debugf("Unable to read %v - %v", configFile, err)
+				continue
+			}
+			for _, line := range strings.Split(string(content), "\n") {
+				fields := strings.Fields(line)
+				if len(fields) < 2 {
+					continue
+				}
+				switch {
+				case fields[0] == "blacklist":
+					disabled[normalizeModuleName(fields[1])] = true
+				case fields[0] == "install" && len(fields) >= 3 && disabledInstallCommands[fields[2]]:
+					disabled[normalizeModuleName(fields[1])] = true
+				}
+			}
+		}
+	}
+	return disabled
+}
+
+// parseSysctlAllowlist returns the sysctl names or patterns to report. Collection value Enabled reports the default allowlist.
+func parseSysctlAllowlist(config model.Config) (allowlist []string, err error) {
+	if config.Filters == "" || config.Filters == model.Enabled {
+		return defaultSysctlAllowlist, nil
+	}
+	if err = json.Unmarshal([]byte(config.Filters), &allowlist); err != nil {
+		err = fmt.Errorf("sysctl allowlist must be a json array of names - %v", err)
+	}
+	return
+}
+
+// resolveAllowlist returns the sysctl names matching the allowlist, e.g. net.ipv4.conf.*.rp_filter, sorted by name.
+// Only the paths matching an entry are visited, /proc/sys is never walked as a whole.
+func resolveAllowlist(allowlist []string) (names []string, err error) {
+	found := map[string]bool{}
+	for _, pattern := range allowlist {
+		var matches []string
+		if matches, err = globFiles(filepath.Join(procSysPath, filepath.FromSlash(strings.Replace(pattern, ".", "/", -1)))); err != nil {
+			return nil, fmt.Errorf("invalid sysctl allowlist entry %v - %v", pattern, err)
+		}
+		for _, match := range matches {
+			relative, relErr := filepath.Rel(procSysPath, match)
+			if relErr != nil {
+				continue
+			}
+			found[strings.Replace(filepath.ToSlash(relative), "/", ".", -1)] = true
+		}
+		if len(found) > SysctlCountLimit {
+			return nil, fmt.Errorf("sysctl count limit of %v exceeded", SysctlCountLimit)
+		}
+	}
+	for name := range found {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return
+}
+
+// collectSysctlData reads the allowlisted sysctl values from /proc/sys
+func collectSysctlData(context context.T, config model.Config) (data []model.SysctlData, err error) {
+	log := context.Log()
+
+	var allowlist []string
+	if allowlist, err = parseSysctlAllowlist(config); err != nil {
+		LogError(log, err)
+		return
+	}
+
+	if _, statErr := os.Stat(procSysPath); statErr != nil {
+		log.Infof("%v is not available - no sysctl data to report", procSysPath)
+		return
+	}
+
+	var names []string
+	if names, err = resolveAllowlist(allowlist); err != nil {
+		LogError(log, err)
+		return
+	}
+	for _, name := range names {
+		value, readErr := readFile(filepath.Join(procSysPath, filepath.FromSlash(strings.Replace(name, ".", "/", -1))))
+		if readErr != nil {
+			// directories, write-only or restricted parameters
+			continue
+		}
+		data = append(data, model.SysctlData{Name: name, Value: strings.Join(strings.Fields(string(value)), " ")})
+	}
+
+	log.Infof("Collected %d sysctl values", len(data))
+	return
+}
@@ -0,0 +1,141 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kernelconfiguration
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	contextmocks "github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/model"
+	"github.com/stretchr/testify/assert"
+)
+
+const sampleProcModules = `ext4 745472 1 - Live 0xffffffffc0a1b000
+mbcache 16384 1 ext4, Live 0xffffffffc0a16000
+jbd2 131072 1 ext4, Live 0xffffffffc09f1000
+cramfs 40960 0 - Loading 0xffffffffc09e0000
+`
+
+const sampleModprobeConfig = `# disable removable storage
+blacklist cramfs
+install usb-storage /bin/true
+install dccp /sbin/modprobe --ignore-install dccp
+`
+
+func writeTestFile(t *testing.T, path, content string) {
+	assert.Nil(t, os.MkdirAll(filepath.Dir(path), 0700))
+	assert.Nil(t, os.WriteFile(path, []byte(content), 0600))
+}
+
+func setupKernelModuleTest(t *testing.T) {
+	dir := t.TempDir()
+	origModulesPath, origConfigDirs := procModulesPath, modprobeConfigDirs
+	t.Cleanup(func() {
+		procModulesPath, modprobeConfigDirs = origModulesPath, origConfigDirs
+	})
+	procModulesPath = filepath.Join(dir, "modules")
+	modprobeConfigDirs = []string{filepath.Join(dir, "modprobe.d")}
+	writeTestFile(t, procModulesPath, sampleProcModules)
+	writeTestFile(t, filepath.Join(dir, "modprobe.d", "cis.conf"), sampleModprobeConfig)
+	writeTestFile(t, filepath.Join(dir, "modprobe.d", "ignored.txt"), "blacklist ext4\n")
+}
+
+func TestCollectKernelModuleData(t *testing.T) {
+	setupKernelModuleTest(t)
+
+	data, err := collectKernelModuleData(contextmocks.NewMockDefault())
+
+	assert.Nil(t, err)
+	assert.Equal(t, []model.KernelModuleData{
+		{Name: "cramfs", Loaded: "true", Disabled: "true", Size: "40960", State: "Loading"},
+		{Name: "ext4", Loaded: "true", Disabled: "false", Size: "745472", State: "Live"},
+		{Name: "jbd2", Loaded: "true", Disabled: "false", Size: "131072", UsedBy: "ext4", State: "Live"},
+		{Name: "mbcache", Loaded: "true", Disabled: "false", Size: "16384", UsedBy: "ext4", State: "Live"},
+		{Name: "usb_storage", Loaded: "false", Disabled: "true"},
+	}, data)
+}
+
+func TestCollectKernelModuleDataNotAvailable(t *testing.T) {
+	setupKernelModuleTest(t)
+	procModulesPath = filepath.Join(t.TempDir(), "missing")
+
+	data, err := collectKernelModuleData(contextmocks.NewMockDefault())
+
+	assert.Nil(t, err)
+	assert.Empty(t, data)
+}
+
+func setupSysctlTest(t *testing.T) {
+	dir := t.TempDir()
+	origSysPath := procSysPath
+	t.Cleanup(func() { procSysPath = origSysPath })
+	procSysPath = dir
+	writeTestFile(t, filepath.Join(dir, "kernel", "randomize_va_space"), "2\n")
+	writeTestFile(t, filepath.Join(dir, "kernel", "hostname"), "test-host\n")
+	writeTestFile(t, filepath.Join(dir, "net", "ipv4", "ip_forward"), "0\n")
+	writeTestFile(t, filepath.Join(dir, "net", "ipv4", "ip_local_port_range"), "32768\t60999\n")
+	writeTestFile(t, filepath.Join(dir, "net", "ipv4", "conf", "all", "rp_filter"), "1\n")
+	writeTestFile(t, filepath.Join(dir, "net", "ipv4", "conf", "eth0", "rp_filter"), "2\n")
+}
+
+func TestCollectSysctlDataDefaultAllowlist(t *testing.T) {
+	setupSysctlTest(t)
+
+	data, err := collectSysctlData(contextmocks.NewMockDefault(), model.Config{Collection: model.Enabled})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []model.SysctlData{
+		{Name: "kernel.randomize_va_space", Value: "2"},
+		{Name: "net.ipv4.conf.all.rp_filter", Value: "1"},
+		{Name: "net.ipv4.ip_forward", Value: "0"},
+	}, data)
+}
+
+func TestCollectSysctlDataCustomAllowlist(t *testing.T) {
+	setupSysctlTest(t)
+	config := model.Config{Collection: model.Enabled, Filters: `["net.ipv4.conf.*.rp_filter", "net.ipv4.ip_local_port_range"]`}
+
+	data, err := collectSysctlData(contextmocks.NewMockDefault(), config)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []model.SysctlData{
+		{Name: "net.ipv4.conf.all.rp_filter", Value: "1"},
+		{Name: "net.ipv4.conf.eth0.rp_filter", Value: "2"},
+		{Name: "net.ipv4.ip_local_port_range", Value: "32768 60999"},
+	}, data)
+}
+
+func TestCollectSysctlDataCountLimit(t *testing.T) {
+	setupSysctlTest(t)
+	for i := 0; i < SysctlCountLimit; i++ {
+		writeTestFile(t, filepath.Join(procSysPath, "net", "ipv4", "conf", fmt.Sprintf("veth%v", i), "rp_filter"), "1\n")
+	}
+	config := model.Config{Collection: model.Enabled, Filters: `["net.ipv4.conf.*.rp_filter"]`}
+
+	data, err := collectSysctlData(contextmocks.NewMockDefault(), config)
+
+	assert.NotNil(t, err)
+	assert.Nil(t, data)
+}
+
+func TestCollectSysctlDataInvalidAllowlist(t *testing.T) {
+	setupSysctlTest(t)
+
+	_, err := collectSysctlData(contextmocks.NewMockDefault(), model.Config{Filters: "net.ipv4.ip_forward"})
+
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,84 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package kernelconfiguration contains a gatherer for loaded kernel modules and sysctl values.
+package kernelconfiguration
+
+import (
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/model"
+)
+
+const (
+	// GathererName captures name of kernel configuration gatherer
+	GathererName = "Custom:KernelConfiguration"
+	// KernelModuleTypeName is the inventory type reporting kernel modules
+	KernelModuleTypeName = "Custom:KernelModule"
+	// SysctlTypeName is the inventory type reporting sysctl values
+	SysctlTypeName = "Custom:Sysctl"
+	// SchemaVersionOfKernelConfigurationGatherer represents schema version of kernel configuration gatherer
+	SchemaVersionOfKernelConfigurationGatherer = "1.0"
+)
+
+type T struct{}
+
+// Gatherer returns new kernel configuration gatherer
+func Gatherer(context context.T) *T {
+	return new(T)
+}
+
+var collectModuleData = collectKernelModuleData
+var collectSysctl = collectSysctlData
+
+// Name returns name of kernel configuration gatherer
+func (t *T) Name() string {
+	return GathererName
+}
+
+// Run executes kernel configuration gatherer and returns one inventory.Item for kernel modules and one for sysctl values
+func (t *T) Run(context context.T, configuration model.Config) (items []model.Item, err error) {
+	//CaptureTime must comply with format: 2016-07-30T18:15:37Z to comply with regex at SSM.
+	currentTime := time.Now().UTC()
+	captureTime := currentTime.Format(time.RFC3339)
+
+	var modules []model.KernelModuleData
+	if modules, err = collectModuleData(context); err != nil {
+		return
+	}
+	var sysctl []model.SysctlData
+	if sysctl, err = collectSysctl(context, configuration); err != nil {
+		return
+	}
+
+	items = append(items,
+		model.Item{
+			Name:          KernelModuleTypeName,
+			SchemaVersion: SchemaVersionOfKernelConfigurationGatherer,
+			Content:       modules,
+			CaptureTime:   captureTime,
+		},
+		model.Item{
+			Name:          SysctlTypeName,
+			SchemaVersion: SchemaVersionOfKernelConfigurationGatherer,
+			Content:       sysctl,
+			CaptureTime:   captureTime,
+		})
+	return
+}
+
+// RequestStop stops the execution of kernel configuration gatherer.
+func (t *T) RequestStop() error {
+	return nil
+}
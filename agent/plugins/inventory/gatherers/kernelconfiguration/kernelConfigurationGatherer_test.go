@@ -0,0 +1,68 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package kernelconfiguration
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	contextmocks "github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/model"
+	"github.com/stretchr/testify/assert"
+)
+
+var testModules = []model.KernelModuleData{
+	{Name: "ext4", Loaded: "true", Disabled: "false", Size: "745472", UsedBy: "", State: "Live"},
+	{Name: "usb_storage", Loaded: "false", Disabled: "true"},
+}
+
+var testSysctl = []model.SysctlData{
+	{Name: "net.ipv4.ip_forward", Value: "0"},
+}
+
+func testCollectKernelModuleData(context context.T) ([]model.KernelModuleData, error) {
+	return testModules, nil
+}
+
+func testCollectSysctlData(context context.T, config model.Config) ([]model.SysctlData, error) {
+	return testSysctl, nil
+}
+
+func TestGatherer(t *testing.T) {
+	contextMock := contextmocks.NewMockDefault()
+	gatherer := Gatherer(contextMock)
+	collectModuleData = testCollectKernelModuleData
+	collectSysctl = testCollectSysctlData
+	items, err := gatherer.Run(contextMock, model.Config{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(items))
+	assert.Equal(t, KernelModuleTypeName, items[0].Name)
+	assert.Equal(t, SchemaVersionOfKernelConfigurationGatherer, items[0].SchemaVersion)
+	assert.Equal(t, testModules, items[0].Content)
+	assert.Equal(t, SysctlTypeName, items[1].Name)
+	assert.Equal(t, testSysctl, items[1].Content)
+}
+
+func TestGathererError(t *testing.T) {
+	contextMock := contextmocks.NewMockDefault()
+	gatherer := Gatherer(contextMock)
+	collectModuleData = testCollectKernelModuleData
+	collectSysctl = func(context context.T, config model.Config) ([]model.SysctlData, error) {
+		return nil, errors.New("invalid allowlist")
+	}
+	items, err := gatherer.Run(contextMock, model.Config{})
+	assert.NotNil(t, err)
+	assert.Nil(t, items)
+}
@@ -19,12 +19,16 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/application"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/awscomponent"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/billinginfo"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/certificate"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/custom"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/file"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/instancedetailedinformation"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/kernelconfiguration"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/network"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/pendingreboot"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/registry"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/role"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/scheduledtask"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/service"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/windowsUpdate"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/model"
@@ -59,10 +63,14 @@ func InitializeGatherers(context context.T) (SupportedGatherer, InstalledGathere
 		custom.GathererName:                      custom.Gatherer(context),
 		network.GathererName:                     network.Gatherer(context),
 		billinginfo.GathererName:                 billinginfo.Gatherer(context),
+		certificate.GathererName:                 certificate.Gatherer(context),
 		windowsUpdate.GathererName:               windowsUpdate.Gatherer(context),
 		file.GathererName:                        file.Gatherer(context),
 		instancedetailedinformation.GathererName: instancedetailedinformation.Gatherer(context),
+		kernelconfiguration.GathererName:         kernelconfiguration.Gatherer(context),
+		pendingreboot.GathererName:               pendingreboot.Gatherer(context),
 		role.GathererName:                        role.Gatherer(context),
+		scheduledtask.GathererName:               scheduledtask.Gatherer(context),
 		service.GathererName:                     service.Gatherer(context),
 		registry.GathererName:                    registry.Gatherer(context),
 	}
@@ -21,10 +21,14 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/application"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/awscomponent"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/billinginfo"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/certificate"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/custom"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/file"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/instancedetailedinformation"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/kernelconfiguration"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/network"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/scheduledtask"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/service"
 )
 
 var supportedGathererNames = []string{
@@ -32,7 +36,11 @@ var supportedGathererNames = []string{
 	awscomponent.GathererName,
 	custom.GathererName,
 	billinginfo.GathererName,
+	certificate.GathererName,
 	network.GathererName,
 	file.GathererName,
 	instancedetailedinformation.GathererName,
+	kernelconfiguration.GathererName,
+	service.GathererName,
+	scheduledtask.GathererName,
 }
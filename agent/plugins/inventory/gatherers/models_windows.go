@@ -22,12 +22,15 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/application"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/awscomponent"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/billinginfo"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/certificate"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/custom"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/file"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/instancedetailedinformation"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/network"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/pendingreboot"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/registry"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/role"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/scheduledtask"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/service"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/windowsUpdate"
 )
@@ -38,10 +41,13 @@ var supportedGathererNames = []string{
 	custom.GathererName,
 	network.GathererName,
 	billinginfo.GathererName,
+	certificate.GathererName,
 	windowsUpdate.GathererName,
+	pendingreboot.GathererName,
 	file.GathererName,
 	instancedetailedinformation.GathererName,
 	role.GathererName,
+	scheduledtask.GathererName,
 	service.GathererName,
 	registry.GathererName,
 }
@@ -0,0 +1,133 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package pendingreboot contains a gatherer reporting whether a Windows instance waits for a reboot to complete
+// the installation of updates, so patched but not rebooted instances can be told apart.
+package pendingreboot
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/model"
+)
+
+const (
+	// GathererName captures name of pending reboot gatherer
+	GathererName = "Custom:PendingReboot"
+	// SchemaVersionOfPendingRebootGatherer represents schema version of pending reboot gatherer
+	SchemaVersionOfPendingRebootGatherer = "1.0"
+
+	// pendingRebootQueryCmd checks the registry keys set by Component Based Servicing and Windows Update when a
+	// reboot is required, the file renames scheduled for the next boot and the last boot time of the instance
+	pendingRebootQueryCmd = `
+  [Console]::OutputEncoding = [System.Text.Encoding]::UTF8
+  $ComponentBasedServicing = Test-Path 'HKLM:\SOFTWARE\Microsoft\Windows\CurrentVersion\Component Based Servicing\RebootPending'
+  $WindowsUpdate = Test-Path 'HKLM:\SOFTWARE\Microsoft\Windows\CurrentVersion\WindowsUpdate\Auto Update\RebootRequired'
+  $PendingFileRenameOperations = [bool](Get-ItemProperty -Path 'HKLM:\SYSTEM\CurrentControlSet\Control\Session Manager' -Name PendingFileRenameOperations -ErrorAction SilentlyContinue).PendingFileRenameOperations
+  $os = Get-WmiObject -Class Win32_OperatingSystem
+  $LastBootUpTime = $os.ConvertToDateTime($os.LastBootUpTime).ToUniversalTime().ToString("yyyy-MM-ddTHH:mm:ssZ")
+  @{ComponentBasedServicing=$ComponentBasedServicing; WindowsUpdate=$WindowsUpdate; PendingFileRenameOperations=$PendingFileRenameOperations; LastBootUpTime=$LastBootUpTime} | ConvertTo-Json`
+)
+
+var (
+	cmd = appconfig.PowerShellPluginCommandName
+
+	// decouple for unit test
+	cmdExecutor = executeCommand
+)
+
+// rebootSignals is the output of pendingRebootQueryCmd
+type rebootSignals struct {
+	ComponentBasedServicing     bool
+	WindowsUpdate               bool
+	PendingFileRenameOperations bool
+	LastBootUpTime              string
+}
+
+// T represents pending reboot gatherer
+type T struct{}
+
+// Gatherer returns new pending reboot gatherer
+func Gatherer(context context.T) *T {
+	return new(T)
+}
+
+// Name returns name of pending reboot gatherer
+func (t *T) Name() string {
+	return GathererName
+}
+
+// Run executes pending reboot gatherer and returns list of inventory.Item comprising of pending reboot data
+func (t *T) Run(context context.T, configuration model.Config) (items []model.Item, err error) {
+	log := context.Log()
+
+	var data []model.PendingRebootData
+	if data, err = collectPendingRebootData(); err != nil {
+		log.Errorf("Unable to fetch pending reboot signals - %v", err)
+		return
+	}
+
+	//CaptureTime must comply with format: 2016-07-30T18:15:37Z or else it will throw error
+	currentTime := time.Now().UTC()
+	captureTime := currentTime.Format(time.RFC3339)
+
+	result := model.Item{
+		Name:          t.Name(),
+		SchemaVersion: SchemaVersionOfPendingRebootGatherer,
+		Content:       data,
+		CaptureTime:   captureTime,
+	}
+	log.Infof("Reboot pending: %v", data[0].RebootPending)
+	log.Debugf("pending reboot info = %+v", result)
+
+	items = append(items, result)
+	return
+}
+
+// RequestStop stops the execution of pending reboot gatherer
+func (t *T) RequestStop() error {
+	return nil
+}
+
+// collectPendingRebootData runs pendingRebootQueryCmd and reports a reboot as pending when any of the signals is set
+func collectPendingRebootData() (data []model.PendingRebootData, err error) {
+	out, err := cmdExecutor(cmd, pendingRebootQueryCmd)
+	if err != nil {
+		return nil, fmt.Errorf("%v %v", err, string(out))
+	}
+
+	var signals rebootSignals
+	if err = json.Unmarshal(out, &signals); err != nil {
+		return nil, fmt.Errorf("unable to parse command output - %v", err)
+	}
+
+	rebootPending := signals.ComponentBasedServicing || signals.WindowsUpdate || signals.PendingFileRenameOperations
+	data = append(data, model.PendingRebootData{
+		RebootPending:               strconv.FormatBool(rebootPending),
+		ComponentBasedServicing:     strconv.FormatBool(signals.ComponentBasedServicing),
+		WindowsUpdate:               strconv.FormatBool(signals.WindowsUpdate),
+		PendingFileRenameOperations: strconv.FormatBool(signals.PendingFileRenameOperations),
+		LastBootUpTime:              signals.LastBootUpTime,
+	})
+	return data, nil
+}
+
+func executeCommand(command string, args ...string) ([]byte, error) {
+	return exec.Command(command, args...).CombinedOutput()
+}
@@ -0,0 +1,86 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package pendingreboot
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func stubCommandOutput(output string, err error) func(command string, args ...string) ([]byte, error) {
+	return func(command string, args ...string) ([]byte, error) {
+		return []byte(output), err
+	}
+}
+
+func TestGatherer(t *testing.T) {
+	contextMock := context.NewMockDefault()
+	gatherer := Gatherer(contextMock)
+	cmdExecutor = stubCommandOutput(`{
+    "WindowsUpdate":  true,
+    "ComponentBasedServicing":  false,
+    "PendingFileRenameOperations":  false,
+    "LastBootUpTime":  "2024-05-02T08:15:37Z"
+}`, nil)
+
+	items, err := gatherer.Run(contextMock, model.Config{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(items))
+	assert.Equal(t, GathererName, items[0].Name)
+	assert.Equal(t, SchemaVersionOfPendingRebootGatherer, items[0].SchemaVersion)
+	assert.Equal(t, []model.PendingRebootData{
+		{
+			RebootPending:               "true",
+			ComponentBasedServicing:     "false",
+			WindowsUpdate:               "true",
+			PendingFileRenameOperations: "false",
+			LastBootUpTime:              "2024-05-02T08:15:37Z",
+		},
+	}, items[0].Content)
+}
+
+func TestGathererNoRebootPending(t *testing.T) {
+	contextMock := context.NewMockDefault()
+	gatherer := Gatherer(contextMock)
+	cmdExecutor = stubCommandOutput(`{"WindowsUpdate": false, "ComponentBasedServicing": false, "PendingFileRenameOperations": false, "LastBootUpTime": "2024-05-02T08:15:37Z"}`, nil)
+
+	items, err := gatherer.Run(contextMock, model.Config{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(items))
+	assert.Equal(t, "false", items[0].Content.([]model.PendingRebootData)[0].RebootPending)
+}
+
+func TestGathererCommandFailed(t *testing.T) {
+	contextMock := context.NewMockDefault()
+	gatherer := Gatherer(contextMock)
+	cmdExecutor = stubCommandOutput("Access is denied", errors.New("exit status 1"))
+
+	items, err := gatherer.Run(contextMock, model.Config{})
+	assert.EqualError(t, err, "exit status 1 Access is denied")
+	assert.Equal(t, 0, len(items))
+}
+
+func TestGathererInvalidOutput(t *testing.T) {
+	contextMock := context.NewMockDefault()
+	gatherer := Gatherer(contextMock)
+	cmdExecutor = stubCommandOutput("not json", nil)
+
+	items, err := gatherer.Run(contextMock, model.Config{})
+	assert.Error(t, err)
+	assert.Equal(t, 0, len(items))
+}
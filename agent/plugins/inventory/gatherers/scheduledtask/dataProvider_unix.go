@@ -0,0 +1,192 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build darwin || freebsd || linux || netbsd || openbsd
+// +build darwin freebsd linux netbsd openbsd
+
+package scheduledtask
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/service"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/model"
+)
+
+const (
+	systemCrontab   = "/etc/crontab"
+	systemCronDir   = "/etc/cron.d"
+	cronTaskState   = "enabled"
+	cronFieldsCount = 5
+)
+
+var (
+	systemctlListTimersArgs = []string{"list-units", "--type=timer", "--all", "--no-legend", "--no-pager", "--plain"}
+	timerProperties         = []string{"Id", "ActiveState", "Unit", "TimersCalendar", "TimersMonotonic", "NextElapseUSecRealtime"}
+	timerServiceProperties  = []string{"Id", "ExecStart", "User"}
+)
+
+// decoupling for easy testability
+var checkCommandExists = commandExists
+var openFile = os.Open
+var globFiles = filepath.Glob
+
+// returns true if the command is available on the instance
+func commandExists(cmd string) bool {
+	_, err := exec.LookPath(cmd)
+	return err == nil
+}
+
+// collectScheduledTaskData collects systemd timers and system crontab entries
+func collectScheduledTaskData(context context.T, config model.Config) (data []model.ScheduledTaskData, err error) {
+	log := context.Log()
+	log.Infof("collectScheduledTaskData called")
+
+	if checkCommandExists(service.SystemctlCmd) {
+		var timers []model.ScheduledTaskData
+		if timers, err = collectSystemdTimers(); err != nil {
+			LogError(log, err)
+			return nil, err
+		}
+		data = append(data, timers...)
+	}
+
+	cronFiles := []string{systemCrontab}
+	if files, globErr := globFiles(filepath.Join(systemCronDir, "*")); globErr == nil {
+		cronFiles = append(cronFiles, files...)
+	}
+	for _, cronFile := range cronFiles {
+		data = append(data, readCronFile(log, cronFile)...)
+	}
+
+	log.Infof("Collected %d scheduled tasks", len(data))
+	return
+}
+
+// collectSystemdTimers returns all systemd timers together with the exec path and user of the unit they trigger
+func collectSystemdTimers() (data []model.ScheduledTaskData, err error) {
+	var output []byte
+	if output, err = cmdExecutor(service.SystemctlCmd, systemctlListTimersArgs...); err != nil {
+		return nil, fmt.Errorf("unable to list systemd timers - %v", err)
+	}
+
+	var timers []map[string]string
+	if timers, err = service.SystemctlShow(cmdExecutor, service.ParseSystemctlListOutput(string(output)), timerProperties); err != nil {
+		return nil, fmt.Errorf("unable to query systemd timers - %v", err)
+	}
+
+	var units []string
+	for _, timer := range timers {
+		units = append(units, timer["Unit"])
+	}
+	var services []map[string]string
+	if services, err = service.SystemctlShow(cmdExecutor, units, timerServiceProperties); err != nil {
+		return nil, fmt.Errorf("unable to query systemd timer units - %v", err)
+	}
+	servicesByName := map[string]map[string]string{}
+	for _, unit := range services {
+		servicesByName[unit["Id"]] = unit
+	}
+
+	for _, timer := range timers {
+		unit := servicesByName[timer["Unit"]]
+		schedule := timerSchedule(timer["TimersCalendar"])
+		if schedule == "" {
+			schedule = timerSchedule(timer["TimersMonotonic"])
+		}
+		data = append(data, model.ScheduledTaskData{
+			Name:        timer["Id"],
+			State:       timer["ActiveState"],
+			Schedule:    schedule,
+			ExecPath:    service.ExecPathFromExecStart(unit["ExecStart"]),
+			RunAs:       service.RunAsFromUser(unit["User"]),
+			NextRunTime: timer["NextElapseUSecRealtime"],
+		})
+	}
+	return
+}
+
+// timerSchedule extracts the trigger definitions from a TimersCalendar or TimersMonotonic property,
+// e.g. { OnCalendar=*-*-* 06:00:00 ; next_elapse=... } becomes OnCalendar=*-*-* 06:00:00
+func timerSchedule(timers string) string {
+	var schedules []string
+	for _, timer := range strings.Split(timers, "}") {
+		timer = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(timer), "{"))
+		if timer == "" {
+			continue
+		}
+		if end := strings.Index(timer, " ;"); end >= 0 {
+			timer = timer[:end]
+		}
+		schedules = append(schedules, strings.TrimSpace(timer))
+	}
+	return strings.Join(schedules, ",")
+}
+
+// readCronFile parses a system crontab which, unlike user crontabs, contains the user to run as
+func readCronFile(log log.T, path string) (data []model.ScheduledTaskData) {
+	file, err := openFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Debugf("Unable to read %v - %v", path, err)
+		}
+		return
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		if task, ok := parseCronLine(scanner.Text()); ok {
+			task.Name = fmt.Sprintf("%v:%d", path, lineNumber)
+			data = append(data, task)
+		}
+	}
+	return
+}
+
+// parseCronLine parses one system crontab line, ignoring comments and environment assignments
+func parseCronLine(line string) (task model.ScheduledTaskData, ok bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return
+	}
+	fields := strings.Fields(line)
+	if strings.Contains(fields[0], "=") {
+		return
+	}
+
+	scheduleFields := cronFieldsCount
+	if strings.HasPrefix(fields[0], "@") {
+		scheduleFields = 1
+	}
+	// schedule, user and command are required
+	if len(fields) < scheduleFields+2 {
+		return
+	}
+
+	return model.ScheduledTaskData{
+		State:    cronTaskState,
+		Schedule: strings.Join(fields[:scheduleFields], " "),
+		RunAs:    fields[scheduleFields],
+		ExecPath: fields[scheduleFields+1],
+	}, true
+}
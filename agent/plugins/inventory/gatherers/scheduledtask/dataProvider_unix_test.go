@@ -0,0 +1,123 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build darwin || freebsd || linux || netbsd || openbsd
+// +build darwin freebsd linux netbsd openbsd
+
+package scheduledtask
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/model"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	sampleTimerList = "logrotate.timer loaded active waiting Daily rotation of log files\n"
+	sampleTimerShow = `Id=logrotate.timer
+ActiveState=active
+Unit=logrotate.service
+TimersCalendar={ OnCalendar=*-*-* 00:00:00 ; next_elapse=Fri 2024-05-03 00:00:00 UTC }
+TimersMonotonic=
+NextElapseUSecRealtime=Fri 2024-05-03 00:00:00 UTC
+`
+	sampleTimerServiceShow = `Id=logrotate.service
+ExecStart={ path=/usr/sbin/logrotate ; argv[]=/usr/sbin/logrotate /etc/logrotate.conf ; ignore_errors=no }
+User=
+`
+	sampleCronFile = `# system crontab
+SHELL=/bin/sh
+17 *	* * *	root    cd / && run-parts --report /etc/cron.hourly
+@reboot backup /opt/backup/run.sh --full
+*/5 * * * *
+`
+)
+
+func TestParseCronLine(t *testing.T) {
+	task, ok := parseCronLine("17 * * * * root /usr/bin/run-parts /etc/cron.hourly")
+	assert.True(t, ok)
+	assert.Equal(t, model.ScheduledTaskData{State: "enabled", Schedule: "17 * * * *", RunAs: "root", ExecPath: "/usr/bin/run-parts"}, task)
+
+	task, ok = parseCronLine("@daily backup /opt/backup/run.sh")
+	assert.True(t, ok)
+	assert.Equal(t, "@daily", task.Schedule)
+	assert.Equal(t, "backup", task.RunAs)
+
+	for _, line := range []string{"", "# comment", "MAILTO=root", "*/5 * * * * root"} {
+		_, ok = parseCronLine(line)
+		assert.False(t, ok, line)
+	}
+}
+
+func TestTimerSchedule(t *testing.T) {
+	assert.Equal(t, "OnCalendar=*-*-* 00:00:00", timerSchedule("{ OnCalendar=*-*-* 00:00:00 ; next_elapse=n/a }"))
+	assert.Equal(t, "OnBootUSec=15min,OnUnitActiveUSec=1d", timerSchedule("{ OnBootUSec=15min ; next_elapse=0 } { OnUnitActiveUSec=1d ; next_elapse=0 }"))
+	assert.Equal(t, "", timerSchedule(""))
+}
+
+func TestCollectScheduledTaskData(t *testing.T) {
+	dir := t.TempDir()
+	cronFile := filepath.Join(dir, "backup")
+	assert.NoError(t, os.WriteFile(cronFile, []byte(sampleCronFile), 0600))
+
+	checkCommandExists = func(string) bool { return true }
+	globFiles = func(string) ([]string, error) { return []string{cronFile}, nil }
+	cmdExecutor = func(command string, args ...string) ([]byte, error) {
+		switch {
+		case args[0] == "list-units":
+			return []byte(sampleTimerList), nil
+		case args[len(args)-1] == "logrotate.timer":
+			return []byte(sampleTimerShow), nil
+		case args[len(args)-1] == "logrotate.service":
+			return []byte(sampleTimerServiceShow), nil
+		}
+		return nil, errors.New("unexpected command")
+	}
+
+	data, err := collectScheduledTaskData(context.NewMockDefault(), model.Config{})
+	assert.NoError(t, err)
+	assert.Equal(t, model.ScheduledTaskData{
+		Name:        "logrotate.timer",
+		State:       "active",
+		Schedule:    "OnCalendar=*-*-* 00:00:00",
+		ExecPath:    "/usr/sbin/logrotate",
+		RunAs:       "root",
+		NextRunTime: "Fri 2024-05-03 00:00:00 UTC",
+	}, data[0])
+
+	var cronTasks []model.ScheduledTaskData
+	for _, task := range data[1:] {
+		if filepath.Dir(task.Name) == dir {
+			cronTasks = append(cronTasks, task)
+		}
+	}
+	assert.Equal(t, []model.ScheduledTaskData{
+		{Name: cronFile + ":3", State: "enabled", Schedule: "17 * * * *", RunAs: "root", ExecPath: "cd"},
+		{Name: cronFile + ":4", State: "enabled", Schedule: "@reboot", RunAs: "backup", ExecPath: "/opt/backup/run.sh"},
+	}, cronTasks)
+}
+
+func TestCollectScheduledTaskData_SystemctlError(t *testing.T) {
+	checkCommandExists = func(string) bool { return true }
+	cmdExecutor = func(command string, args ...string) ([]byte, error) {
+		return nil, errors.New("error")
+	}
+	data, err := collectScheduledTaskData(context.NewMockDefault(), model.Config{})
+	assert.Error(t, err)
+	assert.Nil(t, data)
+}
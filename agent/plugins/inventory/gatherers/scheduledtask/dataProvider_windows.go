@@ -0,0 +1,91 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build windows
+// +build windows
+
+package scheduledtask
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/model"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/pluginutil"
+	"github.com/twinj/uuid"
+)
+
+var (
+	PowershellCmd = appconfig.PowerShellPluginCommandName
+
+	startMarker             = "<start" + randomString(8) + ">"
+	endMarker               = "<end" + randomString(8) + ">"
+	scheduledTaskInfoScript = `
+[Console]::OutputEncoding = [System.Text.Encoding]::UTF8
+$jsonObj = @()
+foreach($t in Get-ScheduledTask) {
+$Name = $t.TaskPath + $t.TaskName
+$State = $t.State
+$Schedule = ($t.Triggers | ForEach-Object { $_.CimClass.CimClassName -replace '^MSFT_Task','' -replace 'Trigger$','' }) -join ','
+$ExecPath = ($t.Actions | Where-Object { $_.Execute } | ForEach-Object { $_.Execute }) -join ','
+$RunAs = $t.Principal.UserId
+if (-not $RunAs) { $RunAs = $t.Principal.GroupId }
+$NextRunTime = ''
+$info = $t | Get-ScheduledTaskInfo -ErrorAction SilentlyContinue
+if ($info -and $info.NextRunTime) { $NextRunTime = $info.NextRunTime.ToUniversalTime().ToString('yyyy-MM-ddTHH:mm:ssZ') }
+$jsonObj += @"
+{"Name": "` + mark(`$Name`) + `", "State": "$State", "Schedule": "` + mark(`$Schedule`) + `", "ExecPath": "` + mark(`$ExecPath`) + `", "RunAs": "` + mark(`$RunAs`) + `", "NextRunTime": "$NextRunTime"}
+"@
+}
+$result = $jsonObj -join ","
+$result = "[" + $result + "]"
+[Console]::WriteLine($result)
+`
+)
+
+func randomString(length int) string {
+	return uuid.NewV4().String()[:length]
+}
+
+func mark(s string) string {
+	return startMarker + s + endMarker
+}
+
+// collectScheduledTaskData collects Windows scheduled tasks through powershell
+func collectScheduledTaskData(context context.T, config model.Config) (data []model.ScheduledTaskData, err error) {
+	log := context.Log()
+	log.Infof("collectScheduledTaskData called")
+
+	var output []byte
+	if output, err = cmdExecutor(PowershellCmd, scheduledTaskInfoScript); err != nil {
+		log.Debugf("Command Stderr: %v", string(output))
+		err = fmt.Errorf("Command failed with error: %v", string(output))
+		LogError(log, err)
+		return
+	}
+
+	var cleanOutput string
+	if cleanOutput, err = pluginutil.ReplaceMarkedFields(pluginutil.CleanupNewLines(string(output)), startMarker, endMarker, pluginutil.CleanupJSONField); err != nil {
+		LogError(log, err)
+		return
+	}
+
+	if err = json.Unmarshal([]byte(cleanOutput), &data); err != nil {
+		err = fmt.Errorf("Unable to parse command output - %v", err.Error())
+		LogError(log, err)
+		data = nil
+	}
+	return
+}
@@ -0,0 +1,50 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package scheduledtask
+
+import (
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	contextmocks "github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/model"
+	"github.com/stretchr/testify/assert"
+)
+
+var testScheduledTasks = []model.ScheduledTaskData{
+	{
+		Name:        "logrotate.timer",
+		State:       "active",
+		Schedule:    "OnCalendar=*-*-* 00:00:00",
+		ExecPath:    "/usr/sbin/logrotate",
+		RunAs:       "root",
+		NextRunTime: "Fri 2024-05-03 00:00:00 UTC",
+	},
+}
+
+func testCollectScheduledTaskData(context context.T, config model.Config) (data []model.ScheduledTaskData, err error) {
+	return testScheduledTasks, nil
+}
+
+func TestGatherer(t *testing.T) {
+	contextMock := contextmocks.NewMockDefault()
+	gatherer := Gatherer(contextMock)
+	collectData = testCollectScheduledTaskData
+	item, err := gatherer.Run(contextMock, model.Config{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(item))
+	assert.Equal(t, GathererName, item[0].Name)
+	assert.Equal(t, SchemaVersionOfScheduledTaskGatherer, item[0].SchemaVersion)
+	assert.Equal(t, testScheduledTasks, item[0].Content)
+}
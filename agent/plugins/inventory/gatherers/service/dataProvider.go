@@ -22,7 +22,6 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
 	"github.com/aws/amazon-ssm-agent/agent/context"
 	"github.com/aws/amazon-ssm-agent/agent/log"
-	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/model"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/pluginutil"
 	"github.com/twinj/uuid"
 )
@@ -35,6 +34,8 @@ var (
 	serviceInfoScript = `
 [Console]::OutputEncoding = [System.Text.Encoding]::UTF8
 $serviceInfo = Get-Service | Select-Object Name, DisplayName, Status, DependentServices, ServicesDependedOn, ServiceType, StartType
+$wmiServices = @{}
+Get-WmiObject -Class Win32_Service -ErrorAction SilentlyContinue | ForEach-Object { $wmiServices[$_.Name] = $_ }
 $jsonObj = @()
 foreach($s in $serviceInfo) {
 $Name = $s.Name
@@ -44,9 +45,11 @@ $DependentServices = $s.DependentServices
 $ServicesDependedOn = $s.ServicesDependedOn
 $ServiceType = $s.ServiceType
 $StartType = $s.StartType
+$ExecPath = $wmiServices[$s.Name].PathName
+$RunAs = $wmiServices[$s.Name].StartName
 $jsonObj += @"
 {"Name": "` + mark(`$Name`) + `", "DisplayName": "` + mark(`$DisplayName`) + `", "Status": "$Status", "DependentServices": "` + mark(`$DependentServices`) + `",
-"ServicesDependedOn": "` + mark(`$ServicesDependedOn`) + `", "ServiceType": "$ServiceType", "StartType": "$StartType"}
+"ServicesDependedOn": "` + mark(`$ServicesDependedOn`) + `", "ServiceType": "$ServiceType", "StartType": "$StartType", "ExecPath": "` + mark(`$ExecPath`) + `", "RunAs": "` + mark(`$RunAs`) + `"}
 "@
 }
 $result = $jsonObj -join ","
@@ -90,7 +93,7 @@ func executePowershellCommands(log log.T, command, args string) (output []byte,
 	return
 }
 
-func collectDataFromPowershell(log log.T, powershellCommand string, serviceInfo *[]model.ServiceData) (err error) {
+func collectDataFromPowershell(log log.T, powershellCommand string, serviceInfo *[]serviceRecord) (err error) {
 	var output []byte
 	var cleanOutput string
 	log.Infof("Executing command: %v", powershellCommand)
@@ -116,9 +119,10 @@ func collectDataFromPowershell(log log.T, powershellCommand string, serviceInfo
 	return
 }
 
-func collectServiceData(context context.T, config model.Config) (data []model.ServiceData, err error) {
+// collectWindowsServiceData collects Windows service information through powershell
+func collectWindowsServiceData(context context.T) (data []serviceRecord, err error) {
 	log := context.Log()
-	log.Infof("collectServiceData called")
+	log.Infof("collectWindowsServiceData called")
 	err = collectDataFromPowershell(log, serviceInfoScript, &data)
 	return
 }
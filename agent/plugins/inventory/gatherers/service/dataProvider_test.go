@@ -59,10 +59,13 @@ func TestServiceData(t *testing.T) {
 	contextMock := context.NewMockDefault()
 	cmdExecutor = createMockTestExecuteCommand(testServiceOutput, nil)
 
-	data, err := collectServiceData(contextMock, model.Config{})
+	data, err := collectWindowsServiceData(contextMock)
 
 	assert.Nil(t, err)
-	assert.Equal(t, data, testServiceOutputData)
+	assert.Equal(t, len(testServiceOutputData), len(data))
+	for i, record := range data {
+		assert.Equal(t, testServiceOutputData[i], record.ServiceData)
+	}
 }
 
 func TestServiceDataCmdErr(t *testing.T) {
@@ -70,7 +73,7 @@ func TestServiceDataCmdErr(t *testing.T) {
 	contextMock := context.NewMockDefault()
 	cmdExecutor = createMockTestExecuteCommand("", errors.New("error"))
 
-	data, err := collectServiceData(contextMock, model.Config{})
+	data, err := collectWindowsServiceData(contextMock)
 
 	assert.NotNil(t, err)
 	assert.Nil(t, data)
@@ -81,7 +84,7 @@ func TestServiceDataInvalidOutput(t *testing.T) {
 	contextMock := context.NewMockDefault()
 	cmdExecutor = createMockTestExecuteCommand("Invalid", nil)
 
-	data, err := collectServiceData(contextMock, model.Config{})
+	data, err := collectWindowsServiceData(contextMock)
 
 	assert.NotNil(t, err)
 	assert.Nil(t, data)
@@ -93,7 +96,7 @@ func TestServiceDataInvalidMarker(t *testing.T) {
 	contextMock := context.NewMockDefault()
 	cmdExecutor = createMockTestExecuteCommand(testServiceOutputIncorrect, nil)
 
-	data, err := collectServiceData(contextMock, model.Config{})
+	data, err := collectWindowsServiceData(contextMock)
 
 	assert.NotNil(t, err)
 	assert.Nil(t, data)
@@ -0,0 +1,91 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build darwin || freebsd || linux || netbsd || openbsd
+// +build darwin freebsd linux netbsd openbsd
+
+package service
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/model"
+)
+
+var (
+	systemctlListEnabledServicesArgs = []string{"list-unit-files", "--type=service", "--state=enabled", "--no-legend", "--no-pager"}
+	systemctlServiceProperties       = []string{"Id", "Description", "ActiveState", "SubState", "Type", "UnitFileState", "ExecStart", "User", "Requires", "RequiredBy"}
+)
+
+// decoupling for easy testability
+var checkCommandExists = commandExists
+
+// returns true if the command is available on the instance
+func commandExists(cmd string) bool {
+	_, err := exec.LookPath(cmd)
+	return err == nil
+}
+
+// collectServiceData collects the enabled systemd services.
+// Instances without systemd report no services instead of failing the whole inventory policy.
+func collectServiceData(context context.T, config model.Config) (data []serviceRecord, err error) {
+	log := context.Log()
+	log.Infof("collectServiceData called")
+
+	if !checkCommandExists(SystemctlCmd) {
+		log.Infof("%v is not available - no service data to report", SystemctlCmd)
+		return
+	}
+
+	var output []byte
+	if output, err = cmdExecutor(SystemctlCmd, systemctlListEnabledServicesArgs...); err != nil {
+		log.Debugf("Command Stderr: %v", string(output))
+		err = fmt.Errorf("unable to list systemd services - %v", err)
+		return
+	}
+
+	var units []string
+	for _, unit := range ParseSystemctlListOutput(string(output)) {
+		// template units like getty@.service can not be queried without an instance name
+		if !strings.HasSuffix(unit, "@.service") {
+			units = append(units, unit)
+		}
+	}
+
+	var properties []map[string]string
+	if properties, err = SystemctlShow(cmdExecutor, units, systemctlServiceProperties); err != nil {
+		err = fmt.Errorf("unable to query systemd services - %v", err)
+		return
+	}
+
+	for _, unit := range properties {
+		data = append(data, serviceRecord{
+			ServiceData: model.ServiceData{
+				Name:               unit["Id"],
+				DisplayName:        unit["Description"],
+				Status:             fmt.Sprintf("%v (%v)", unit["ActiveState"], unit["SubState"]),
+				DependentServices:  unit["RequiredBy"],
+				ServicesDependedOn: unit["Requires"],
+				ServiceType:        unit["Type"],
+				StartType:          unit["UnitFileState"],
+			},
+			ExecPath: ExecPathFromExecStart(unit["ExecStart"]),
+			RunAs:    RunAsFromUser(unit["User"]),
+		})
+	}
+	log.Infof("Collected %d systemd services", len(data))
+	return
+}
@@ -0,0 +1,106 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build darwin || freebsd || linux || netbsd || openbsd
+// +build darwin freebsd linux netbsd openbsd
+
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/model"
+	"github.com/stretchr/testify/assert"
+)
+
+const sampleSystemctlListOutput = `amazon-ssm-agent.service enabled enabled
+getty@.service           enabled enabled
+sshd.service             enabled enabled
+`
+
+func TestCollectServiceData_Systemd(t *testing.T) {
+	checkCommandExists = func(string) bool { return true }
+	cmdExecutor = func(command string, args ...string) ([]byte, error) {
+		if args[0] == "list-unit-files" {
+			return []byte(sampleSystemctlListOutput), nil
+		}
+		assert.Equal(t, []string{"amazon-ssm-agent.service", "sshd.service"}, args[3:])
+		return []byte(`Id=amazon-ssm-agent.service
+Description=amazon-ssm-agent
+ActiveState=active
+SubState=running
+Type=simple
+UnitFileState=enabled
+ExecStart={ path=/usr/bin/amazon-ssm-agent ; argv[]=/usr/bin/amazon-ssm-agent ; ignore_errors=no }
+User=
+Requires=sysinit.target
+RequiredBy=
+
+Id=sshd.service
+Description=OpenSSH server daemon
+ActiveState=inactive
+SubState=dead
+Type=notify
+UnitFileState=enabled
+ExecStart={ path=/usr/sbin/sshd ; argv[]=/usr/sbin/sshd -D ; ignore_errors=no }
+User=sshd
+Requires=
+RequiredBy=
+`), nil
+	}
+
+	data, err := collectServiceData(context.NewMockDefault(), model.Config{})
+	assert.NoError(t, err)
+	assert.Equal(t, []serviceRecord{
+		{
+			ServiceData: model.ServiceData{
+				Name:               "amazon-ssm-agent.service",
+				DisplayName:        "amazon-ssm-agent",
+				Status:             "active (running)",
+				ServicesDependedOn: "sysinit.target",
+				ServiceType:        "simple",
+				StartType:          "enabled",
+			},
+			ExecPath: "/usr/bin/amazon-ssm-agent",
+			RunAs:    "root",
+		},
+		{
+			ServiceData: model.ServiceData{
+				Name:        "sshd.service",
+				DisplayName: "OpenSSH server daemon",
+				Status:      "inactive (dead)",
+				ServiceType: "notify",
+				StartType:   "enabled",
+			},
+			ExecPath: "/usr/sbin/sshd",
+			RunAs:    "sshd",
+		},
+	}, data)
+}
+
+func TestCollectServiceData_NoSystemd(t *testing.T) {
+	checkCommandExists = func(string) bool { return false }
+	data, err := collectServiceData(context.NewMockDefault(), model.Config{})
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+}
+
+func TestCollectServiceData_CommandError(t *testing.T) {
+	checkCommandExists = func(string) bool { return true }
+	cmdExecutor = createMockTestExecuteCommand("", errors.New("error"))
+	data, err := collectServiceData(context.NewMockDefault(), model.Config{})
+	assert.Error(t, err)
+	assert.Nil(t, data)
+}
@@ -24,12 +24,22 @@ import (
 const (
 	// GathererName captures name of Service gatherer
 	GathererName = "AWS:Service"
+	// ServiceDetailTypeName is the inventory type reporting the executable and account of each service
+	ServiceDetailTypeName = "Custom:ServiceDetail"
 	// SchemaVersionOfServiceGatherer represents schema version of Service gatherer
 	SchemaVersionOfServiceGatherer = "1.0"
 )
 
 type T struct{}
 
+// serviceRecord is a service as collected on the instance. ExecPath and RunAs are not part of the AWS:Service
+// schema and are reported under ServiceDetailTypeName.
+type serviceRecord struct {
+	model.ServiceData
+	ExecPath string
+	RunAs    string
+}
+
 // Gatherer returns new Process gatherer
 func Gatherer(context context.T) *T {
 	return new(T)
@@ -42,24 +52,34 @@ func (t *T) Name() string {
 	return GathererName
 }
 
-// Run executes Service gatherer and returns list of inventory.Item comprising of service data
+// Run executes Service gatherer and returns one inventory.Item for service data and one for service details
 func (t *T) Run(context context.T, configuration model.Config) (items []model.Item, err error) {
-	var result model.Item
-
 	//CaptureTime must comply with format: 2016-07-30T18:15:37Z to comply with regex at SSM.
 	currentTime := time.Now().UTC()
 	captureTime := currentTime.Format(time.RFC3339)
-	var data []model.ServiceData
-	data, err = collectData(context, configuration)
+	var records []serviceRecord
+	records, err = collectData(context, configuration)
 
-	result = model.Item{
-		Name:          t.Name(),
-		SchemaVersion: SchemaVersionOfServiceGatherer,
-		Content:       data,
-		CaptureTime:   captureTime,
+	var data []model.ServiceData
+	var details []model.ServiceDetailData
+	for _, record := range records {
+		data = append(data, record.ServiceData)
+		details = append(details, model.ServiceDetailData{Name: record.Name, ExecPath: record.ExecPath, RunAs: record.RunAs})
 	}
 
-	items = append(items, result)
+	items = append(items,
+		model.Item{
+			Name:          t.Name(),
+			SchemaVersion: SchemaVersionOfServiceGatherer,
+			Content:       data,
+			CaptureTime:   captureTime,
+		},
+		model.Item{
+			Name:          ServiceDetailTypeName,
+			SchemaVersion: SchemaVersionOfServiceGatherer,
+			Content:       details,
+			CaptureTime:   captureTime,
+		})
 	return
 }
 
@@ -43,8 +43,11 @@ var testService = []model.ServiceData{
 	},
 }
 
-func testCollectServiceData(context context.T, config model.Config) (data []model.ServiceData, err error) {
-	return testService, nil
+func testCollectServiceData(context context.T, config model.Config) (data []serviceRecord, err error) {
+	for _, service := range testService {
+		data = append(data, serviceRecord{ServiceData: service, ExecPath: `C:\Windows\system32\svchost.exe -k DcomLaunch`, RunAs: "LocalSystem"})
+	}
+	return data, nil
 }
 
 func TestGatherer(t *testing.T) {
@@ -53,8 +56,13 @@ func TestGatherer(t *testing.T) {
 	collectData = testCollectServiceData
 	item, err := gatherer.Run(contextMock, model.Config{})
 	assert.Nil(t, err)
-	assert.Equal(t, 1, len(item))
+	assert.Equal(t, 2, len(item))
 	assert.Equal(t, GathererName, item[0].Name)
 	assert.Equal(t, SchemaVersionOfServiceGatherer, item[0].SchemaVersion)
 	assert.Equal(t, testService, item[0].Content)
+	assert.Equal(t, ServiceDetailTypeName, item[1].Name)
+	assert.Equal(t, []model.ServiceDetailData{
+		{Name: "BrokerInfrastructure", ExecPath: `C:\Windows\system32\svchost.exe -k DcomLaunch`, RunAs: "LocalSystem"},
+		{Name: "embeddedmode", ExecPath: `C:\Windows\system32\svchost.exe -k DcomLaunch`, RunAs: "LocalSystem"},
+	}, item[1].Content)
 }
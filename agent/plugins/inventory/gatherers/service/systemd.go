@@ -0,0 +1,108 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package service
+
+import (
+	"strings"
+)
+
+const (
+	// SystemctlCmd is the systemd service manager command
+	SystemctlCmd = "systemctl"
+	// systemctlShowBatchSize limits the number of units passed to one systemctl show call
+	systemctlShowBatchSize = 100
+	// defaultRunAsUser is reported when a unit does not configure a user
+	defaultRunAsUser = "root"
+)
+
+// ParseSystemctlListOutput returns the unit names (first column) of systemctl list-* output run with --no-legend
+func ParseSystemctlListOutput(output string) (units []string) {
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		// systemctl marks failed units with a leading bullet when --plain is not supported
+		if fields[0] == "●" || fields[0] == "*" {
+			fields = fields[1:]
+		}
+		if len(fields) > 0 {
+			units = append(units, fields[0])
+		}
+	}
+	return
+}
+
+// ParseSystemctlShowOutput parses the Key=Value blocks printed by systemctl show for multiple units.
+// Blocks are separated by empty lines.
+func ParseSystemctlShowOutput(output string) (units []map[string]string) {
+	current := map[string]string{}
+	for _, line := range strings.Split(strings.Replace(output, "\r", "", -1), "\n") {
+		if strings.TrimSpace(line) == "" {
+			if len(current) > 0 {
+				units = append(units, current)
+				current = map[string]string{}
+			}
+			continue
+		}
+		if index := strings.Index(line, "="); index > 0 {
+			current[line[:index]] = line[index+1:]
+		}
+	}
+	if len(current) > 0 {
+		units = append(units, current)
+	}
+	return
+}
+
+// ExecPathFromExecStart extracts the executable path from a systemctl show ExecStart property,
+// e.g. { path=/usr/sbin/sshd ; argv[]=/usr/sbin/sshd -D ; ... }
+func ExecPathFromExecStart(execStart string) string {
+	const pathPrefix = "path="
+	index := strings.Index(execStart, pathPrefix)
+	if index < 0 {
+		return strings.TrimSpace(execStart)
+	}
+	path := execStart[index+len(pathPrefix):]
+	if end := strings.Index(path, " ;"); end >= 0 {
+		path = path[:end]
+	}
+	return strings.TrimSpace(path)
+}
+
+// RunAsFromUser returns the user a unit runs as, defaulting to root for system units
+func RunAsFromUser(user string) string {
+	if user == "" {
+		return defaultRunAsUser
+	}
+	return user
+}
+
+// SystemctlShow runs systemctl show for the given units in batches and returns the parsed properties
+func SystemctlShow(executor func(string, ...string) ([]byte, error), units []string, properties []string) (result []map[string]string, err error) {
+	for start := 0; start < len(units); start += systemctlShowBatchSize {
+		end := start + systemctlShowBatchSize
+		if end > len(units) {
+			end = len(units)
+		}
+		args := []string{"show", "--no-pager", "--property=" + strings.Join(properties, ",")}
+		args = append(args, units[start:end]...)
+		var output []byte
+		if output, err = executor(SystemctlCmd, args...); err != nil {
+			return
+		}
+		result = append(result, ParseSystemctlShowOutput(string(output))...)
+	}
+	return
+}
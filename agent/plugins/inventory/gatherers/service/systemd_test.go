@@ -0,0 +1,69 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const sampleSystemctlShowOutput = `Id=sshd.service
+Description=OpenSSH server daemon
+ExecStart={ path=/usr/sbin/sshd ; argv[]=/usr/sbin/sshd -D $OPTIONS ; ignore_errors=no ; start_time=[n/a] ; stop_time=[n/a] ; pid=0 ; code=(null) ; status=0/0 }
+User=
+
+Id=amazon-ssm-agent.service
+Description=amazon-ssm-agent
+User=ssm-user
+`
+
+func TestParseSystemctlListOutput(t *testing.T) {
+	output := "sshd.service      enabled enabled\n● failed.service  enabled disabled\n\n"
+	assert.Equal(t, []string{"sshd.service", "failed.service"}, ParseSystemctlListOutput(output))
+}
+
+func TestParseSystemctlShowOutput(t *testing.T) {
+	units := ParseSystemctlShowOutput(sampleSystemctlShowOutput)
+	assert.Equal(t, 2, len(units))
+	assert.Equal(t, "sshd.service", units[0]["Id"])
+	assert.Equal(t, "", units[0]["User"])
+	assert.Equal(t, "amazon-ssm-agent.service", units[1]["Id"])
+	assert.Equal(t, "ssm-user", units[1]["User"])
+}
+
+func TestExecPathFromExecStart(t *testing.T) {
+	units := ParseSystemctlShowOutput(sampleSystemctlShowOutput)
+	assert.Equal(t, "/usr/sbin/sshd", ExecPathFromExecStart(units[0]["ExecStart"]))
+	assert.Equal(t, "", ExecPathFromExecStart(""))
+}
+
+func TestRunAsFromUser(t *testing.T) {
+	assert.Equal(t, "root", RunAsFromUser(""))
+	assert.Equal(t, "ssm-user", RunAsFromUser("ssm-user"))
+}
+
+func TestSystemctlShow_Batches(t *testing.T) {
+	var calls [][]string
+	executor := func(command string, args ...string) ([]byte, error) {
+		calls = append(calls, args)
+		return []byte("Id=unit.service\n"), nil
+	}
+	units := make([]string, systemctlShowBatchSize+1)
+	result, err := SystemctlShow(executor, units, []string{"Id"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(calls))
+	assert.Equal(t, 2, len(result))
+	assert.Equal(t, "--property=Id", calls[0][2])
+}
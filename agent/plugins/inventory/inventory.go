@@ -33,12 +33,16 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/application"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/awscomponent"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/billinginfo"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/certificate"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/custom"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/file"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/instancedetailedinformation"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/kernelconfiguration"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/network"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/pendingreboot"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/registry"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/role"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/scheduledtask"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/service"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/gatherers/windowsUpdate"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/model"
@@ -72,12 +76,16 @@ type PluginInput struct {
 	AWSComponents               string
 	NetworkConfig               string
 	BillingInfo                 string
+	Certificates                string
 	Files                       string
 	WindowsRoles                string
 	Services                    string
+	ScheduledTasks              string
 	WindowsRegistry             string
 	WindowsUpdates              string
+	PendingReboot               string
 	InstanceDetailedInformation string
+	KernelConfiguration         string
 	CustomInventory             string
 	CustomInventoryDirectory    string
 }
@@ -174,6 +182,17 @@ func (p *Plugin) ApplyInventoryPolicy(inventoryInput PluginInput, output iohandl
 	d, _ := json.Marshal(items)
 	log.Debugf("Collected Inventory data: %v", string(d))
 
+	//with delta reporting only changed inventory types are uploaded until the next full refresh is due
+	inventoryConfig := p.context.AppConfig().Inventory
+	fullRefreshInterval := time.Duration(inventoryConfig.FullRefreshIntervalHours) * time.Hour
+	if inventoryConfig.DeltaReporting && !p.uploader.IsFullUploadDue(fullRefreshInterval) {
+		log.Infof("Full inventory refresh is not due yet - uploading changed inventory types only")
+		if p.uploadDirtyItemsToSSM(items, output) {
+			log.Infof("%v uploaded changed inventory data to SSM", Name())
+		}
+		return
+	}
+
 	if optimizedInventoryItems, nonOptimizedInventoryItems, err = p.uploader.ConvertToSsmInventoryItems(items); err != nil {
 		log.Infof("Encountered error in converting data to SSM InventoryItems - %v. Skipping upload to SSM", err.Error())
 		output.SetExitCode(1)
@@ -192,6 +211,7 @@ func (p *Plugin) ApplyInventoryPolicy(inventoryInput PluginInput, output iohandl
 		return
 	}
 
+	p.uploader.RecordFullUpload()
 	log.Infof("%v uploaded inventory data to SSM", Name())
 	output.SetExitCode(0)
 	output.AppendInfo(successfulMsgForInventoryPlugin)
@@ -341,7 +361,6 @@ func (p *Plugin) getLargeItemIndex(nonOptimizedInventoryItems []*ssm.InventoryIt
 func (p Plugin) ApplyInventoryFrequentCollector(gatherers map[gatherers.T]model.Config, output iohandler.IOHandler) {
 	log := p.context.Log()
 
-	var dirtyItems []*ssm.InventoryItem
 	var items []model.Item
 	var err error
 
@@ -363,11 +382,26 @@ func (p Plugin) ApplyInventoryFrequentCollector(gatherers map[gatherers.T]model.
 		return
 	}
 
+	if p.uploadDirtyItemsToSSM(items, output) {
+		log.Infof("%v uploaded inventory data from frequent collector to SSM", Name())
+	}
+
+	return
+}
+
+// uploadDirtyItemsToSSM uploads only the inventory types whose content changed since the last successful upload.
+// It returns true if changed data was uploaded.
+func (p Plugin) uploadDirtyItemsToSSM(items []model.Item, output iohandler.IOHandler) bool {
+	log := p.context.Log()
+
+	var dirtyItems []*ssm.InventoryItem
+	var err error
+
 	if dirtyItems, err = p.uploader.GetDirtySsmInventoryItems(items); err != nil {
 		log.Debugf("Encountered error in collecting dirty Inventory items - %#v. Skipping upload to SSM", err.Error())
 		output.SetExitCode(1)
 		output.AppendError(err.Error())
-		return
+		return false
 	}
 
 	if len(dirtyItems) == 0 {
@@ -375,21 +409,19 @@ func (p Plugin) ApplyInventoryFrequentCollector(gatherers map[gatherers.T]model.
 		log.Info(msgWhenNoDataToReturnForInventoryPlugin)
 		output.SetExitCode(0)
 		output.AppendInfo(msgWhenNoDataToReturnForInventoryPlugin)
-		return
+		return false
 	}
 
 	if err = p.uploader.SendDataToSSM(dirtyItems); err != nil {
 		//some other error happened for which there is no need to retry - upload failed
 		log.Debugf(" Error happened while p.uploader.SendDataToSSM")
 		propagateSSMError(output, err, log)
-		return
+		return false
 	}
 
-	log.Infof("%v uploaded inventory data from frequent collector to SSM", Name())
 	output.SetExitCode(0)
 	output.AppendInfo(successfulMsgForInventoryPlugin)
-
-	return
+	return true
 }
 
 // shouldRetryWithNonOptimizedData will return true if the Exception occurred is one of ItemContentMismatchException
@@ -505,15 +537,19 @@ func (p *Plugin) ValidateInventoryInput(context context.T, input PluginInput) (c
 		awscomponent.GathererName:                input.AWSComponents,
 		role.GathererName:                        input.WindowsRoles,
 		service.GathererName:                     input.Services,
+		scheduledtask.GathererName:               input.ScheduledTasks,
 		network.GathererName:                     input.NetworkConfig,
 		billinginfo.GathererName:                 input.BillingInfo,
 		windowsUpdate.GathererName:               input.WindowsUpdates,
+		pendingreboot.GathererName:               input.PendingReboot,
 		instancedetailedinformation.GathererName: input.InstanceDetailedInformation,
 	}
 
 	predefinedGatherersWithFilters := map[string]string{
-		file.GathererName:     input.Files,
-		registry.GathererName: input.WindowsRegistry,
+		file.GathererName:                input.Files,
+		registry.GathererName:            input.WindowsRegistry,
+		certificate.GathererName:         input.Certificates,
+		kernelconfiguration.GathererName: input.KernelConfiguration,
 	}
 
 	//NOTE:
@@ -130,6 +130,23 @@ type ServiceData struct {
 	StartType          string
 }
 
+// ServiceDetailData captures all attributes present in Custom:ServiceDetail inventory type
+type ServiceDetailData struct {
+	Name     string
+	ExecPath string
+	RunAs    string
+}
+
+// ScheduledTaskData captures all attributes present in Custom:ScheduledTask inventory type
+type ScheduledTaskData struct {
+	Name        string
+	State       string
+	Schedule    string
+	ExecPath    string
+	RunAs       string
+	NextRunTime string `json:",omitempty"`
+}
+
 type RegistryData struct {
 	ValueName string
 	ValueType string
@@ -163,6 +180,15 @@ type WindowsUpdateData struct {
 	InstalledBy   string
 }
 
+// PendingRebootData captures all attributes present in Custom:PendingReboot inventory type
+type PendingRebootData struct {
+	RebootPending               string
+	ComponentBasedServicing     string
+	WindowsUpdate               string
+	PendingFileRenameOperations string
+	LastBootUpTime              string
+}
+
 // InstanceDetailedInformation captures all attributes present in AWS:InstanceDetailedInformation inventory type
 type InstanceDetailedInformation struct {
 	CPUModel              string
@@ -175,6 +201,34 @@ type InstanceDetailedInformation struct {
 	KernelVersion         string
 }
 
+// CertificateData captures all attributes present in Custom:Certificate inventory type
+type CertificateData struct {
+	Subject                 string
+	Issuer                  string
+	SerialNumber            string
+	SubjectAlternativeNames string `json:",omitempty"`
+	NotBefore               string
+	NotAfter                string
+	Thumbprint              string
+	Location                string
+}
+
+// KernelModuleData captures all attributes present in Custom:KernelModule inventory type
+type KernelModuleData struct {
+	Name     string
+	Loaded   string
+	Disabled string
+	Size     string `json:",omitempty"`
+	UsedBy   string `json:",omitempty"`
+	State    string `json:",omitempty"`
+}
+
+// SysctlData captures all attributes present in Custom:Sysctl inventory type
+type SysctlData struct {
+	Name  string
+	Value string
+}
+
 // Config captures all various properties (including optional) that can be supplied to a gatherer.
 // NOTE: Not all properties will be applicable to all gatherers.
 // E.g: Applications gatherer uses Collection, Files use Filters, Custom uses Collection & Location.
@@ -0,0 +1,215 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package manageservice implements the aws:manageService plugin
+package manageservice
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/contracts"
+	"github.com/aws/amazon-ssm-agent/agent/framework/processor/executer/iohandler"
+	"github.com/aws/amazon-ssm-agent/agent/jsonutil"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/pluginutil"
+	"github.com/aws/amazon-ssm-agent/agent/task"
+)
+
+const (
+	actionStart   = "start"
+	actionStop    = "stop"
+	actionRestart = "restart"
+	actionEnable  = "enable"
+	actionDisable = "disable"
+
+	// defaultTimeoutSeconds bounds the wait for the service to reach the state of the action
+	defaultTimeoutSeconds = 60
+)
+
+var (
+	// newServiceController returns the controller of the service manager of the platform
+	newServiceController = newPlatformServiceController
+
+	// pollInterval is the interval the service status is checked at after the action
+	pollInterval = time.Second
+
+	timeNow = time.Now
+
+	// runCommand runs a service manager command and returns its combined output
+	runCommand = func(name string, args ...string) (string, error) {
+		output, err := exec.Command(name, args...).CombinedOutput()
+		return strings.TrimSpace(string(output)), err
+	}
+)
+
+// serviceStatus is the state of a service as reported by the service manager
+type serviceStatus struct {
+	Running bool
+	Enabled bool
+}
+
+// serviceController performs actions on services of the platform service manager
+type serviceController interface {
+	Start(name string) error
+	Stop(name string) error
+	Restart(name string) error
+	Enable(name string) error
+	Disable(name string) error
+	Status(name string) (serviceStatus, error)
+}
+
+// Plugin is the type for the aws:manageService plugin.
+type Plugin struct {
+	context context.T
+}
+
+// ManageServicePluginInput represents the input of the aws:manageService plugin
+type ManageServicePluginInput struct {
+	contracts.PluginInput
+	// ServiceName is the systemd unit, launchd label or Windows service name
+	ServiceName string `json:"serviceName"`
+	// Action is one of start, stop, restart, enable or disable
+	Action string `json:"action"`
+	// TimeoutSeconds bounds the wait for the service to reach the state of the action
+	TimeoutSeconds interface{} `json:"timeoutSeconds"`
+}
+
+// NewPlugin returns a new instance of the plugin.
+func NewPlugin(context context.T) (*Plugin, error) {
+	return &Plugin{
+		context: context,
+	}, nil
+}
+
+// Name returns the name of the plugin
+func Name() string {
+	return appconfig.PluginManageService
+}
+
+// Execute performs the action on the service and verifies the service reached the expected state
+func (p *Plugin) Execute(config contracts.Configuration, cancelFlag task.CancelFlag, output iohandler.IOHandler) {
+	log := p.context.Log()
+	log.Infof("%v started with configuration %v", Name(), config)
+
+	if cancelFlag.ShutDown() {
+		output.MarkAsShutdown()
+	} else if cancelFlag.Canceled() {
+		output.MarkAsCancelled()
+	} else if input, err := parseAndValidateInput(config.Properties); err != nil {
+		output.MarkAsFailed(err)
+	} else if err = p.runManageService(log, input, cancelFlag, output); err != nil {
+		output.MarkAsFailed(err)
+	} else if cancelFlag.Canceled() {
+		output.MarkAsCancelled()
+	} else {
+		output.MarkAsSucceeded()
+	}
+}
+
+// parseAndValidateInput parses the plugin properties and validates them
+func parseAndValidateInput(rawPluginInput interface{}) (*ManageServicePluginInput, error) {
+	var input ManageServicePluginInput
+	if err := jsonutil.Remarshal(rawPluginInput, &input); err != nil {
+		return nil, fmt.Errorf("invalid format in plugin properties %v; \nerror %v", rawPluginInput, err)
+	}
+	input.ServiceName = strings.TrimSpace(input.ServiceName)
+	input.Action = strings.ToLower(strings.TrimSpace(input.Action))
+
+	if input.ServiceName == "" {
+		return nil, errors.New("invalid input: ServiceName must be specified")
+	}
+	if strings.HasPrefix(input.ServiceName, "-") {
+		return nil, fmt.Errorf("invalid input: ServiceName %s must not start with -", input.ServiceName)
+	}
+	switch input.Action {
+	case actionStart, actionStop, actionRestart, actionEnable, actionDisable:
+	default:
+		return nil, fmt.Errorf("invalid input: Action %s is not one of start, stop, restart, enable or disable", input.Action)
+	}
+	return &input, nil
+}
+
+// runManageService performs the action and waits until the service reaches the expected state
+func (p *Plugin) runManageService(log log.T, input *ManageServicePluginInput, cancelFlag task.CancelFlag, output iohandler.IOHandler) error {
+	timeoutSeconds := defaultTimeoutSeconds
+	if input.TimeoutSeconds != nil {
+		timeoutSeconds = pluginutil.ValidateExecutionTimeout(log, input.TimeoutSeconds)
+	}
+	controller, err := newServiceController()
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Performing %s on service %s", input.Action, input.ServiceName)
+	var expected func(serviceStatus) bool
+	switch input.Action {
+	case actionStart:
+		err = controller.Start(input.ServiceName)
+		expected = func(status serviceStatus) bool { return status.Running }
+	case actionStop:
+		err = controller.Stop(input.ServiceName)
+		expected = func(status serviceStatus) bool { return !status.Running }
+	case actionRestart:
+		err = controller.Restart(input.ServiceName)
+		expected = func(status serviceStatus) bool { return status.Running }
+	case actionEnable:
+		err = controller.Enable(input.ServiceName)
+		expected = func(status serviceStatus) bool { return status.Enabled }
+	case actionDisable:
+		err = controller.Disable(input.ServiceName)
+		expected = func(status serviceStatus) bool { return !status.Enabled }
+	}
+	if err != nil {
+		return fmt.Errorf("failed to %s service %s: %v", input.Action, input.ServiceName, err)
+	}
+
+	deadline := timeNow().Add(time.Duration(timeoutSeconds) * time.Second)
+	for {
+		status, err := controller.Status(input.ServiceName)
+		if err != nil {
+			return fmt.Errorf("failed to get status of service %s: %v", input.ServiceName, err)
+		}
+		if expected(status) {
+			output.AppendInfof("Service %v %v succeeded, service is %v and %v", input.ServiceName, input.Action, runningString(status), enabledString(status))
+			return nil
+		}
+		if cancelFlag.Canceled() {
+			return nil
+		}
+		if timeNow().After(deadline) {
+			return fmt.Errorf("service %s did not reach the expected state within %d seconds after %s, service is %s and %s",
+				input.ServiceName, timeoutSeconds, input.Action, runningString(status), enabledString(status))
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+func runningString(status serviceStatus) string {
+	if status.Running {
+		return "running"
+	}
+	return "stopped"
+}
+
+func enabledString(status serviceStatus) string {
+	if status.Enabled {
+		return "enabled"
+	}
+	return "disabled"
+}
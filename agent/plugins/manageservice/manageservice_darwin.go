@@ -0,0 +1,102 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build darwin
+// +build darwin
+
+package manageservice
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	launchctl        = "launchctl"
+	launchDaemonsDir = "/Library/LaunchDaemons"
+	launchdDomain    = "system"
+)
+
+// launchdController manages system launch daemons with launchctl
+type launchdController struct{}
+
+func newPlatformServiceController() (serviceController, error) {
+	return launchdController{}, nil
+}
+
+// Start loads the daemon from its plist when it is not loaded yet and starts it
+func (launchdController) Start(name string) error {
+	if !isLoaded(name) {
+		plistPath := filepath.Join(launchDaemonsDir, name+".plist")
+		if output, err := runCommand(launchctl, "bootstrap", launchdDomain, plistPath); err != nil {
+			return errors.New(output)
+		}
+	}
+	return runLaunchctl("kickstart", serviceTarget(name))
+}
+
+func (launchdController) Stop(name string) error {
+	if !isLoaded(name) {
+		return nil
+	}
+	return runLaunchctl("bootout", serviceTarget(name))
+}
+
+func (c launchdController) Restart(name string) error {
+	if !isLoaded(name) {
+		return c.Start(name)
+	}
+	return runLaunchctl("kickstart", "-k", serviceTarget(name))
+}
+
+func (launchdController) Enable(name string) error {
+	return runLaunchctl("enable", serviceTarget(name))
+}
+
+func (launchdController) Disable(name string) error {
+	return runLaunchctl("disable", serviceTarget(name))
+}
+
+func (launchdController) Status(name string) (serviceStatus, error) {
+	status := serviceStatus{Enabled: true}
+	if output, err := runCommand(launchctl, "print", serviceTarget(name)); err == nil {
+		status.Running = strings.Contains(output, "state = running")
+	}
+
+	output, err := runCommand(launchctl, "print-disabled", launchdDomain)
+	if err != nil {
+		return status, fmt.Errorf("failed to read disabled services: %v", output)
+	}
+	disabled := regexp.MustCompile(`"` + regexp.QuoteMeta(name) + `"\s*=>\s*(true|disabled)`)
+	status.Enabled = !disabled.MatchString(output)
+	return status, nil
+}
+
+func isLoaded(name string) bool {
+	_, err := runCommand(launchctl, "print", serviceTarget(name))
+	return err == nil
+}
+
+func serviceTarget(name string) string {
+	return launchdDomain + "/" + name
+}
+
+func runLaunchctl(args ...string) error {
+	if output, err := runCommand(launchctl, args...); err != nil {
+		return errors.New(output)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package manageservice
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/contracts"
+	"github.com/aws/amazon-ssm-agent/agent/framework/processor/executer/iohandler"
+	"github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	"github.com/aws/amazon-ssm-agent/agent/task"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeController records the actions and reports the status after a number of status calls
+type fakeController struct {
+	actions      []string
+	actionErr    error
+	status       serviceStatus
+	target       serviceStatus
+	pendingPolls int
+}
+
+func (f *fakeController) do(action string, target serviceStatus) error {
+	f.actions = append(f.actions, action)
+	f.target = target
+	return f.actionErr
+}
+
+func (f *fakeController) Start(name string) error {
+	return f.do(actionStart, serviceStatus{Running: true, Enabled: f.status.Enabled})
+}
+
+func (f *fakeController) Stop(name string) error {
+	return f.do(actionStop, serviceStatus{Running: false, Enabled: f.status.Enabled})
+}
+
+func (f *fakeController) Restart(name string) error {
+	return f.do(actionRestart, serviceStatus{Running: true, Enabled: f.status.Enabled})
+}
+
+func (f *fakeController) Enable(name string) error {
+	return f.do(actionEnable, serviceStatus{Running: f.status.Running, Enabled: true})
+}
+
+func (f *fakeController) Disable(name string) error {
+	return f.do(actionDisable, serviceStatus{Running: f.status.Running, Enabled: false})
+}
+
+func (f *fakeController) Status(name string) (serviceStatus, error) {
+	if f.pendingPolls > 0 {
+		f.pendingPolls--
+		return f.status, nil
+	}
+	return f.target, nil
+}
+
+func setupController(t *testing.T, controller *fakeController) {
+	originalController, originalInterval := newServiceController, pollInterval
+	newServiceController = func() (serviceController, error) { return controller, nil }
+	pollInterval = time.Millisecond
+	t.Cleanup(func() {
+		newServiceController, pollInterval = originalController, originalInterval
+	})
+}
+
+func runPlugin(properties map[string]interface{}) iohandler.IOHandler {
+	p, _ := NewPlugin(context.NewMockDefault())
+	output := iohandler.NewDefaultIOHandler(context.NewMockDefault(), contracts.IOConfiguration{})
+	p.Execute(contracts.Configuration{Properties: properties, PluginID: "service"}, task.NewChanneledCancelFlag(), output)
+	return output
+}
+
+func TestParseAndValidateInput(t *testing.T) {
+	testCases := []struct {
+		properties map[string]interface{}
+		valid      bool
+	}{
+		{map[string]interface{}{"serviceName": "nginx", "action": "start"}, true},
+		{map[string]interface{}{"serviceName": "nginx", "action": "Restart", "timeoutSeconds": "30"}, true},
+		{map[string]interface{}{"action": "start"}, false},
+		{map[string]interface{}{"serviceName": "--all", "action": "stop"}, false},
+		{map[string]interface{}{"serviceName": "nginx", "action": "reload"}, false},
+	}
+	for _, tc := range testCases {
+		_, err := parseAndValidateInput(tc.properties)
+		assert.Equal(t, tc.valid, err == nil, "%v: %v", tc.properties, err)
+	}
+}
+
+func TestExecute_VerifiesServiceState(t *testing.T) {
+	controller := &fakeController{pendingPolls: 2}
+	setupController(t, controller)
+
+	output := runPlugin(map[string]interface{}{"serviceName": "nginx", "action": "start"})
+
+	assert.Equal(t, contracts.ResultStatusSuccess, output.GetStatus())
+	assert.Equal(t, []string{actionStart}, controller.actions)
+	assert.Contains(t, output.GetStdout(), "service is running and disabled")
+}
+
+func TestExecute_FailsWhenStateIsNotReachedInTime(t *testing.T) {
+	controller := &fakeController{status: serviceStatus{Running: true, Enabled: true}, pendingPolls: 1 << 30}
+	setupController(t, controller)
+	clock := time.Now()
+	timeNow = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+	t.Cleanup(func() { timeNow = time.Now })
+
+	output := runPlugin(map[string]interface{}{"serviceName": "nginx", "action": "disable", "timeoutSeconds": 10})
+
+	assert.Equal(t, contracts.ResultStatusFailed, output.GetStatus())
+	assert.Contains(t, output.GetStderr(), "did not reach the expected state within 10 seconds")
+}
+
+func TestExecute_FailsWhenActionFails(t *testing.T) {
+	controller := &fakeController{actionErr: errors.New("unit nginx.service not found")}
+	setupController(t, controller)
+
+	output := runPlugin(map[string]interface{}{"serviceName": "nginx", "action": "restart"})
+
+	assert.Equal(t, contracts.ResultStatusFailed, output.GetStatus())
+	assert.Contains(t, output.GetStderr(), "failed to restart service nginx")
+}
@@ -0,0 +1,71 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build freebsd || linux || netbsd || openbsd
+// +build freebsd linux netbsd openbsd
+
+package manageservice
+
+import (
+	"errors"
+	"os/exec"
+)
+
+const systemctl = "systemctl"
+
+// systemdController manages systemd units with systemctl
+type systemdController struct{}
+
+func newPlatformServiceController() (serviceController, error) {
+	if _, err := exec.LookPath(systemctl); err != nil {
+		return nil, errors.New("systemd is required to manage services, systemctl was not found")
+	}
+	return systemdController{}, nil
+}
+
+func (systemdController) Start(name string) error {
+	return runSystemctl("start", name)
+}
+
+func (systemdController) Stop(name string) error {
+	return runSystemctl("stop", name)
+}
+
+func (systemdController) Restart(name string) error {
+	return runSystemctl("restart", name)
+}
+
+func (systemdController) Enable(name string) error {
+	return runSystemctl("enable", name)
+}
+
+func (systemdController) Disable(name string) error {
+	return runSystemctl("disable", name)
+}
+
+// Status reads the active and enabled state, both commands exit non-zero for the negative answer
+func (systemdController) Status(name string) (serviceStatus, error) {
+	active, _ := runCommand(systemctl, "is-active", "--", name)
+	enabled, _ := runCommand(systemctl, "is-enabled", "--", name)
+	return serviceStatus{
+		Running: active == "active",
+		Enabled: enabled == "enabled" || enabled == "enabled-runtime",
+	}, nil
+}
+
+func runSystemctl(action string, name string) error {
+	if output, err := runCommand(systemctl, action, "--", name); err != nil {
+		return errors.New(output)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build windows
+// +build windows
+
+package manageservice
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// stopWaitTimeout bounds the wait for the service to stop before it is started again on restart
+const stopWaitTimeout = 60 * time.Second
+
+// scmController manages services with the Windows service control manager
+type scmController struct{}
+
+func newPlatformServiceController() (serviceController, error) {
+	return scmController{}, nil
+}
+
+func (scmController) Start(name string) error {
+	return withService(name, func(service *mgr.Service) error {
+		if err := service.Start(); err != nil && err != windows.ERROR_SERVICE_ALREADY_RUNNING {
+			return err
+		}
+		return nil
+	})
+}
+
+func (scmController) Stop(name string) error {
+	return withService(name, stopService)
+}
+
+// Restart stops the service, waits for it to stop and starts it again
+func (scmController) Restart(name string) error {
+	return withService(name, func(service *mgr.Service) error {
+		if err := stopService(service); err != nil {
+			return err
+		}
+		deadline := time.Now().Add(stopWaitTimeout)
+		for {
+			status, err := service.Query()
+			if err != nil {
+				return err
+			}
+			if status.State == svc.Stopped {
+				break
+			}
+			if time.Now().After(deadline) {
+				return fmt.Errorf("service did not stop within %v", stopWaitTimeout)
+			}
+			time.Sleep(pollInterval)
+		}
+		return service.Start()
+	})
+}
+
+func (scmController) Enable(name string) error {
+	return withService(name, func(service *mgr.Service) error {
+		return setStartType(service, mgr.StartAutomatic)
+	})
+}
+
+func (scmController) Disable(name string) error {
+	return withService(name, func(service *mgr.Service) error {
+		return setStartType(service, mgr.StartDisabled)
+	})
+}
+
+func (scmController) Status(name string) (status serviceStatus, err error) {
+	err = withService(name, func(service *mgr.Service) error {
+		state, err := service.Query()
+		if err != nil {
+			return err
+		}
+		config, err := service.Config()
+		if err != nil {
+			return err
+		}
+		status.Running = state.State == svc.Running
+		status.Enabled = config.StartType != mgr.StartDisabled
+		return nil
+	})
+	return status, err
+}
+
+func stopService(service *mgr.Service) error {
+	if _, err := service.Control(svc.Stop); err != nil && err != windows.ERROR_SERVICE_NOT_ACTIVE {
+		return err
+	}
+	return nil
+}
+
+func setStartType(service *mgr.Service, startType uint32) error {
+	config, err := service.Config()
+	if err != nil {
+		return err
+	}
+	if config.StartType == startType {
+		return nil
+	}
+	config.StartType = startType
+	return service.UpdateConfig(config)
+}
+
+// withService opens the service in the service control manager and runs action on it
+func withService(name string, action func(service *mgr.Service) error) error {
+	manager, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("failed to connect to service control manager: %v", err)
+	}
+	defer manager.Disconnect()
+
+	service, err := manager.OpenService(name)
+	if err != nil {
+		return fmt.Errorf("failed to open service: %v", err)
+	}
+	defer service.Close()
+	return action(service)
+}
@@ -213,13 +213,14 @@ func testExecution(t *testing.T, commandtester CommandTester) {
 
 func setExecuterExpectations(mockExecuter *executers.MockCommandExecuter, t TestCase, cancelFlag task.CancelFlag, p *Plugin) {
 	mockExecuter.On("NewExecute", mock.Anything, t.Input.WorkingDirectory, t.Output.StdoutWriter, t.Output.StderrWriter, cancelFlag, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
-		t.Output.ExitCode, t.ExecuterErrors)
+		t.Output.ExitCode, (*contracts.ProcessAccounting)(nil), t.ExecuterErrors)
 }
 
 func setIOHandlerExpectations(mockIOHandler *iohandlermocks.MockIOHandler, t TestCase) {
 	mockIOHandler.On("GetStdoutWriter").Return(t.Output.StdoutWriter)
 	mockIOHandler.On("GetStderrWriter").Return(t.Output.StderrWriter)
 	mockIOHandler.On("SetExitCode", t.Output.ExitCode).Return()
+	mockIOHandler.On("AddProcessAccounting", mock.Anything).Return()
 	mockIOHandler.On("SetStatus", t.Output.Status).Return()
 	if t.ExecuterErrors != nil {
 		mockIOHandler.On("GetStatus").Return(t.Output.Status)
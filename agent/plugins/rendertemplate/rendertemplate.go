@@ -0,0 +1,360 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package rendertemplate implements the aws:renderTemplate plugin
+package rendertemplate
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/contracts"
+	"github.com/aws/amazon-ssm-agent/agent/executers"
+	"github.com/aws/amazon-ssm-agent/agent/framework/processor/executer/iohandler"
+	"github.com/aws/amazon-ssm-agent/agent/jsonutil"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/pluginutil"
+	ssmsvc "github.com/aws/amazon-ssm-agent/agent/ssm"
+	"github.com/aws/amazon-ssm-agent/agent/task"
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+const (
+	// downloadsDir is the directory under the orchestration directory aws:downloadContent saves relative paths to
+	downloadsDir = "downloads"
+
+	// backupSuffix is appended to the destination path to name the backup of the replaced file
+	backupSuffix = ".bak"
+
+	// validationPathPlaceholder is replaced with the path of the rendered file in the validation command
+	validationPathPlaceholder = "%s"
+
+	// defaultValidationTimeoutSeconds bounds the validation command when no timeout is given
+	defaultValidationTimeoutSeconds = 60
+
+	defaultFileMode os.FileMode = 0644
+	defaultDirMode  os.FileMode = 0755
+)
+
+var (
+	// getParameter returns the decrypted value of a Parameter Store parameter
+	getParameter = func(context context.T, name string) (string, error) {
+		log := context.Log()
+		response, err := ssmsvc.NewService(context).GetDecryptedParameters(log, []string{name})
+		if err != nil {
+			return "", err
+		}
+		if len(response.Parameters) == 0 {
+			return "", fmt.Errorf("parameter %s does not exist or is not accessible", name)
+		}
+		return aws.StringValue(response.Parameters[0].Value), nil
+	}
+)
+
+// Plugin is the type for the aws:renderTemplate plugin.
+type Plugin struct {
+	context context.T
+	// CommandExecuter runs the validation command
+	CommandExecuter executers.T
+}
+
+// RenderTemplatePluginInput represents the input of the aws:renderTemplate plugin
+type RenderTemplatePluginInput struct {
+	contracts.PluginInput
+	// TemplatePath is the template file, relative paths are resolved against the aws:downloadContent downloads
+	TemplatePath string `json:"templatePath"`
+	// Template is an inline template, used when TemplatePath is not set
+	Template string `json:"template"`
+	// DestinationPath is the absolute path of the rendered file
+	DestinationPath string `json:"destinationPath"`
+	// Values are available to the template as fields, e.g. {{ .port }}
+	Values map[string]interface{} `json:"values"`
+	// FileMode is the octal mode of the rendered file, the mode of the replaced file or 0644 when empty
+	FileMode string `json:"fileMode"`
+	// Backup keeps the replaced file next to the destination with a .bak suffix, defaults to true
+	Backup *bool `json:"backup"`
+	// ValidationCommand is run before the destination is replaced, %s is replaced with the path of the rendered file
+	ValidationCommand string `json:"validationCommand"`
+	// TimeoutSeconds bounds the validation command
+	TimeoutSeconds interface{} `json:"timeoutSeconds"`
+}
+
+// NewPlugin returns a new instance of the plugin.
+func NewPlugin(context context.T) (*Plugin, error) {
+	return &Plugin{
+		context:         context,
+		CommandExecuter: executers.ShellCommandExecuter{},
+	}, nil
+}
+
+// Name returns the name of the plugin
+func Name() string {
+	return appconfig.PluginRenderTemplate
+}
+
+// Execute renders the template to the destination
+func (p *Plugin) Execute(config contracts.Configuration, cancelFlag task.CancelFlag, output iohandler.IOHandler) {
+	log := p.context.Log()
+	log.Infof("%v started", Name())
+
+	if cancelFlag.ShutDown() {
+		output.MarkAsShutdown()
+	} else if cancelFlag.Canceled() {
+		output.MarkAsCancelled()
+	} else if input, err := parseAndValidateInput(config.Properties); err != nil {
+		output.MarkAsFailed(err)
+	} else if err = p.runRenderTemplate(log, input, config, cancelFlag, output); err != nil && cancelFlag.Canceled() {
+		output.MarkAsCancelled()
+	} else if err != nil {
+		output.MarkAsFailed(err)
+	} else if cancelFlag.Canceled() {
+		output.MarkAsCancelled()
+	} else {
+		output.MarkAsSucceeded()
+	}
+}
+
+// parseAndValidateInput parses the plugin properties and validates them
+func parseAndValidateInput(rawPluginInput interface{}) (*RenderTemplatePluginInput, error) {
+	var input RenderTemplatePluginInput
+	if err := jsonutil.Remarshal(rawPluginInput, &input); err != nil {
+		return nil, fmt.Errorf("invalid format in plugin properties; \nerror %v", err)
+	}
+	input.TemplatePath = strings.TrimSpace(input.TemplatePath)
+	input.DestinationPath = strings.TrimSpace(input.DestinationPath)
+	input.FileMode = strings.TrimSpace(input.FileMode)
+	input.ValidationCommand = strings.TrimSpace(input.ValidationCommand)
+
+	if (input.TemplatePath == "") == (input.Template == "") {
+		return nil, errors.New("invalid input: exactly one of TemplatePath and Template must be specified")
+	}
+	if input.DestinationPath == "" || !filepath.IsAbs(input.DestinationPath) {
+		return nil, errors.New("invalid input: DestinationPath must be an absolute path")
+	}
+	if _, err := parseFileMode(input.FileMode); err != nil {
+		return nil, fmt.Errorf("invalid input: %v", err)
+	}
+	if input.ValidationCommand != "" && !strings.Contains(input.ValidationCommand, validationPathPlaceholder) {
+		return nil, fmt.Errorf("invalid input: ValidationCommand must contain %s for the path of the rendered file", validationPathPlaceholder)
+	}
+	return &input, nil
+}
+
+// runRenderTemplate renders the template, validates the result and replaces the destination with it
+func (p *Plugin) runRenderTemplate(log log.T, input *RenderTemplatePluginInput, config contracts.Configuration, cancelFlag task.CancelFlag, output iohandler.IOHandler) error {
+	text := input.Template
+	if input.TemplatePath != "" {
+		templatePath := input.TemplatePath
+		if !filepath.IsAbs(templatePath) {
+			orchestrationDir := strings.TrimSuffix(config.OrchestrationDirectory, config.PluginID)
+			templatePath = filepath.Join(orchestrationDir, downloadsDir, templatePath)
+		}
+		content, err := os.ReadFile(templatePath)
+		if err != nil {
+			return fmt.Errorf("template %s is not accessible: %v", input.TemplatePath, err)
+		}
+		text = string(content)
+	}
+
+	// the rendered content is never logged, it may hold SecureString parameters
+	rendered, err := p.render(text, input.Values)
+	if err != nil {
+		return err
+	}
+	checksum := sha256.Sum256(rendered)
+	renderedChecksum := hex.EncodeToString(checksum[:])
+
+	existingInfo, statErr := os.Stat(input.DestinationPath)
+	if statErr == nil && existingInfo.IsDir() {
+		return fmt.Errorf("destination %s is a directory", input.DestinationPath)
+	}
+	mode, _ := parseFileMode(input.FileMode)
+	if mode == 0 {
+		mode = defaultFileMode
+		if statErr == nil {
+			mode = existingInfo.Mode().Perm()
+		}
+	}
+	if statErr == nil {
+		if existing, err := fileChecksum(input.DestinationPath); err == nil && existing == renderedChecksum && existingInfo.Mode().Perm() == mode {
+			output.AppendInfof("%v is up to date (sha256:%v)", input.DestinationPath, renderedChecksum)
+			return nil
+		}
+	}
+
+	if err = os.MkdirAll(filepath.Dir(input.DestinationPath), defaultDirMode); err != nil {
+		return fmt.Errorf("failed to create directory %s: %v", filepath.Dir(input.DestinationPath), err)
+	}
+	temp, err := os.CreateTemp(filepath.Dir(input.DestinationPath), "."+filepath.Base(input.DestinationPath)+".render")
+	if err != nil {
+		return fmt.Errorf("failed to create file in %s: %v", filepath.Dir(input.DestinationPath), err)
+	}
+	tempPath := temp.Name()
+	defer func() {
+		_ = os.Remove(tempPath)
+	}()
+	_, err = temp.Write(rendered)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write rendered template: %v", err)
+	}
+
+	if input.ValidationCommand != "" {
+		if err = p.validate(log, input, tempPath, cancelFlag, output); err != nil {
+			return err
+		}
+	}
+
+	if statErr == nil && (input.Backup == nil || *input.Backup) {
+		backupPath := input.DestinationPath + backupSuffix
+		if err = copyFile(input.DestinationPath, backupPath, existingInfo.Mode().Perm()); err != nil {
+			return fmt.Errorf("failed to back up %s: %v", input.DestinationPath, err)
+		}
+		output.AppendInfof("Backed up %v to %v", input.DestinationPath, backupPath)
+	}
+	if err = os.Chmod(tempPath, mode); err != nil {
+		return fmt.Errorf("failed to set mode of %s: %v", input.DestinationPath, err)
+	}
+	if err = os.Rename(tempPath, input.DestinationPath); err != nil {
+		return fmt.Errorf("failed to replace %s: %v", input.DestinationPath, err)
+	}
+	output.AppendInfof("Rendered template to %v (sha256:%v)", input.DestinationPath, renderedChecksum)
+	return nil
+}
+
+// render executes the template, missing values fail the rendering instead of rendering as <no value>
+func (p *Plugin) render(text string, values map[string]interface{}) ([]byte, error) {
+	parameters := map[string]string{}
+	functions := template.FuncMap{
+		"ssm": func(name string) (string, error) {
+			if value, found := parameters[name]; found {
+				return value, nil
+			}
+			value, err := getParameter(p.context, name)
+			if err != nil {
+				return "", err
+			}
+			parameters[name] = value
+			return value, nil
+		},
+		"default": func(fallback interface{}, value interface{}) interface{} {
+			if value == nil || value == "" {
+				return fallback
+			}
+			return value
+		},
+		"env":   os.Getenv,
+		"lower": strings.ToLower,
+		"upper": strings.ToUpper,
+		"trim":  strings.TrimSpace,
+		"quote": strconv.Quote,
+		"join": func(separator string, items []interface{}) string {
+			parts := make([]string, len(items))
+			for i, item := range items {
+				parts[i] = fmt.Sprint(item)
+			}
+			return strings.Join(parts, separator)
+		},
+	}
+
+	parsed, err := template.New(Name()).Funcs(functions).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %v", err)
+	}
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	var rendered bytes.Buffer
+	if err = parsed.Execute(&rendered, values); err != nil {
+		return nil, fmt.Errorf("failed to render template: %v", err)
+	}
+	return rendered.Bytes(), nil
+}
+
+// validate runs the validation command on the rendered file.
+// The output of the command is not reported, it may echo the rendered content including SecureString parameters.
+func (p *Plugin) validate(log log.T, input *RenderTemplatePluginInput, renderedPath string, cancelFlag task.CancelFlag, output iohandler.IOHandler) error {
+	timeoutSeconds := defaultValidationTimeoutSeconds
+	if input.TimeoutSeconds != nil {
+		timeoutSeconds = pluginutil.ValidateExecutionTimeout(log, input.TimeoutSeconds)
+	}
+	command := strings.Replace(input.ValidationCommand, validationPathPlaceholder, quotePath(renderedPath), -1)
+	log.Infof("Validating rendered template with %s", command)
+
+	exitCode, _, err := p.CommandExecuter.NewExecute(p.context, filepath.Dir(renderedPath), ioutil.Discard, ioutil.Discard, cancelFlag,
+		timeoutSeconds, validationShell[0], append(validationShell[1:], command), make(map[string]string))
+	if err != nil || exitCode != 0 {
+		return fmt.Errorf("validation of the rendered template failed with exit code %d, %s was not replaced", exitCode, input.DestinationPath)
+	}
+	output.AppendInfof("Validated rendered template with %v", input.ValidationCommand)
+	return nil
+}
+
+func copyFile(sourcePath, destinationPath string, mode os.FileMode) error {
+	source, err := os.Open(sourcePath)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+	destination, err := os.OpenFile(destinationPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(destination, source)
+	if closeErr := destination.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Chmod(destinationPath, mode)
+}
+
+func fileChecksum(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	digester := sha256.New()
+	if _, err = io.Copy(digester, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(digester.Sum(nil)), nil
+}
+
+// parseFileMode parses an octal file mode, an empty mode is returned as 0
+func parseFileMode(fileMode string) (os.FileMode, error) {
+	if fileMode == "" {
+		return 0, nil
+	}
+	mode, err := strconv.ParseUint(fileMode, 8, 32)
+	if err != nil || mode == 0 || mode > 0777 {
+		return 0, fmt.Errorf("FileMode %s is not an octal mode like 0644", fileMode)
+	}
+	return os.FileMode(mode), nil
+}
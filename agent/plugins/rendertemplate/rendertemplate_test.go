@@ -0,0 +1,216 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package rendertemplate
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/contracts"
+	"github.com/aws/amazon-ssm-agent/agent/executers"
+	"github.com/aws/amazon-ssm-agent/agent/framework/processor/executer/iohandler"
+	contextmocks "github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	executermocks "github.com/aws/amazon-ssm-agent/agent/mocks/executers"
+	"github.com/aws/amazon-ssm-agent/agent/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func runPlugin(orchestrationDir string, properties map[string]interface{}) iohandler.IOHandler {
+	return runPluginWithExecuter(orchestrationDir, properties, executers.ShellCommandExecuter{}, task.NewChanneledCancelFlag())
+}
+
+func runPluginWithExecuter(orchestrationDir string, properties map[string]interface{}, executer executers.T, cancelFlag task.CancelFlag) iohandler.IOHandler {
+	p, _ := NewPlugin(contextmocks.NewMockDefault())
+	p.CommandExecuter = executer
+	output := iohandler.NewDefaultIOHandler(contextmocks.NewMockDefault(), contracts.IOConfiguration{})
+	config := contracts.Configuration{
+		Properties:             properties,
+		PluginID:               "render",
+		OrchestrationDirectory: filepath.Join(orchestrationDir, "render"),
+	}
+	p.Execute(config, cancelFlag, output)
+	return output
+}
+
+func setupParameters(t *testing.T, parameters map[string]string) {
+	original := getParameter
+	getParameter = func(context context.T, name string) (string, error) {
+		if value, found := parameters[name]; found {
+			return value, nil
+		}
+		return "", errors.New("parameter not found")
+	}
+	t.Cleanup(func() { getParameter = original })
+}
+
+func TestParseAndValidateInput(t *testing.T) {
+	destination := filepath.Join(t.TempDir(), "app.conf")
+	testCases := []struct {
+		properties map[string]interface{}
+		valid      bool
+	}{
+		{map[string]interface{}{"templatePath": "app.conf.tmpl", "destinationPath": destination}, true},
+		{map[string]interface{}{"template": "port={{ .port }}", "destinationPath": destination, "validationCommand": "nginx -t -c %s"}, true},
+		{map[string]interface{}{"destinationPath": destination}, false},
+		{map[string]interface{}{"template": "x", "templatePath": "app.conf.tmpl", "destinationPath": destination}, false},
+		{map[string]interface{}{"template": "x", "destinationPath": "relative/app.conf"}, false},
+		{map[string]interface{}{"template": "x", "destinationPath": destination, "fileMode": "0999"}, false},
+		{map[string]interface{}{"template": "x", "destinationPath": destination, "validationCommand": "nginx -t"}, false},
+	}
+	for _, tc := range testCases {
+		_, err := parseAndValidateInput(tc.properties)
+		assert.Equal(t, tc.valid, err == nil, "%v: %v", tc.properties, err)
+	}
+}
+
+func TestExecute_RendersDownloadedTemplateWithValuesAndParameters(t *testing.T) {
+	setupParameters(t, map[string]string{"/app/db/password": "s3cret"})
+	orchestrationDir := t.TempDir()
+	templatePath := filepath.Join(orchestrationDir, downloadsDir, "app.conf.tmpl")
+	os.MkdirAll(filepath.Dir(templatePath), 0700)
+	os.WriteFile(templatePath, []byte(`port={{ .port }}
+hosts={{ join "," .hosts }}
+password={{ ssm "/app/db/password" }}
+`), 0600)
+	destination := filepath.Join(t.TempDir(), "etc", "app.conf")
+
+	output := runPlugin(orchestrationDir, map[string]interface{}{
+		"templatePath":    "app.conf.tmpl",
+		"destinationPath": destination,
+		"values":          map[string]interface{}{"port": 8080, "hosts": []interface{}{"a", "b"}},
+		"fileMode":        "0640",
+	})
+
+	assert.Equal(t, contracts.ResultStatusSuccess, output.GetStatus(), output.GetStderr())
+	content, _ := os.ReadFile(destination)
+	assert.Equal(t, "port=8080\nhosts=a,b\npassword=s3cret\n", string(content))
+	assert.NotContains(t, output.GetStdout(), "s3cret")
+	if runtime.GOOS != "windows" {
+		info, _ := os.Stat(destination)
+		assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+	}
+}
+
+func TestExecute_BacksUpReplacedFileAndSkipsUnchangedContent(t *testing.T) {
+	destination := filepath.Join(t.TempDir(), "app.conf")
+	os.WriteFile(destination, []byte("port=80"), 0644)
+	properties := map[string]interface{}{
+		"template":        "port={{ .port }}",
+		"destinationPath": destination,
+		"values":          map[string]interface{}{"port": 8080},
+	}
+
+	output := runPlugin(t.TempDir(), properties)
+	assert.Equal(t, contracts.ResultStatusSuccess, output.GetStatus(), output.GetStderr())
+	backup, _ := os.ReadFile(destination + backupSuffix)
+	assert.Equal(t, "port=80", string(backup))
+
+	output = runPlugin(t.TempDir(), properties)
+	assert.Equal(t, contracts.ResultStatusSuccess, output.GetStatus(), output.GetStderr())
+	assert.Contains(t, output.GetStdout(), "is up to date")
+}
+
+func TestExecute_MissingValueFailsRendering(t *testing.T) {
+	destination := filepath.Join(t.TempDir(), "app.conf")
+
+	output := runPlugin(t.TempDir(), map[string]interface{}{
+		"template":        "port={{ .port }}",
+		"destinationPath": destination,
+	})
+
+	assert.Equal(t, contracts.ResultStatusFailed, output.GetStatus())
+	assert.Contains(t, output.GetStderr(), "failed to render template")
+	assert.NoFileExists(t, destination)
+}
+
+func TestExecute_FailedValidationKeepsDestination(t *testing.T) {
+	setupParameters(t, map[string]string{"/app/db/password": "s3cret"})
+	var validatedCommand []string
+	cancelFlag := task.NewChanneledCancelFlag()
+	executer := new(executermocks.MockCommandExecuter)
+	executer.On("NewExecute", mock.Anything, mock.Anything, mock.Anything, mock.Anything, cancelFlag, 30, validationShell[0], mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			validatedCommand = args.Get(7).([]string)
+			args.Get(3).(io.Writer).Write([]byte("syntax error near password=s3cret"))
+		}).
+		Return(1, (*contracts.ProcessAccounting)(nil), errors.New("exit status 1"))
+	destination := filepath.Join(t.TempDir(), "app.conf")
+	os.WriteFile(destination, []byte("port=80"), 0644)
+
+	output := runPluginWithExecuter(t.TempDir(), map[string]interface{}{
+		"template":          `password={{ ssm "/app/db/password" }}`,
+		"destinationPath":   destination,
+		"validationCommand": "check %s",
+		"timeoutSeconds":    30,
+	}, executer, cancelFlag)
+
+	executer.AssertExpectations(t)
+	assert.Equal(t, contracts.ResultStatusFailed, output.GetStatus())
+	assert.Contains(t, output.GetStderr(), "exit code 1")
+	assert.NotContains(t, output.GetStderr(), "s3cret")
+	assert.Contains(t, validatedCommand[len(validatedCommand)-1], ".app.conf.render")
+	content, _ := os.ReadFile(destination)
+	assert.Equal(t, "port=80", string(content))
+	assert.NoFileExists(t, destination+backupSuffix)
+}
+
+func TestExecute_CancelledValidationKeepsDestination(t *testing.T) {
+	cancelFlag := task.NewChanneledCancelFlag()
+	executer := new(executermocks.MockCommandExecuter)
+	executer.On("NewExecute", mock.Anything, mock.Anything, mock.Anything, mock.Anything, cancelFlag, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Run(func(mock.Arguments) { cancelFlag.Set(task.Canceled) }).
+		Return(appconfig.CommandStoppedPreemptivelyExitCode, (*contracts.ProcessAccounting)(nil), errors.New("Cancelled process"))
+	destination := filepath.Join(t.TempDir(), "app.conf")
+	os.WriteFile(destination, []byte("port=80"), 0644)
+
+	output := runPluginWithExecuter(t.TempDir(), map[string]interface{}{
+		"template":          "port=8080",
+		"destinationPath":   destination,
+		"validationCommand": "check %s",
+	}, executer, cancelFlag)
+
+	assert.Equal(t, contracts.ResultStatusCancelled, output.GetStatus())
+	content, _ := os.ReadFile(destination)
+	assert.Equal(t, "port=80", string(content))
+}
+
+func TestExecute_ValidationRunsOnRenderedFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("validation command uses sh")
+	}
+	destination := filepath.Join(t.TempDir(), "app.conf")
+	properties := map[string]interface{}{
+		"template":          "port={{ .port }}",
+		"destinationPath":   destination,
+		"values":            map[string]interface{}{"port": 8080},
+		"validationCommand": "grep -q port=8080 %s",
+	}
+
+	output := runPlugin(t.TempDir(), properties)
+	assert.Equal(t, contracts.ResultStatusSuccess, output.GetStatus(), output.GetStderr())
+
+	properties["validationCommand"] = "grep -q port=80$ %s"
+	properties["values"] = map[string]interface{}{"port": 8081}
+	output = runPlugin(t.TempDir(), properties)
+	assert.Equal(t, contracts.ResultStatusFailed, output.GetStatus())
+	content, _ := os.ReadFile(destination)
+	assert.Equal(t, "port=8080", string(content))
+}
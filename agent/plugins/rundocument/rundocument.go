@@ -27,12 +27,16 @@ import (
 	ssmsvc "github.com/aws/amazon-ssm-agent/agent/ssm"
 	"github.com/aws/amazon-ssm-agent/agent/task"
 	"github.com/aws/amazon-ssm-agent/agent/times"
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ssm"
 
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"path/filepath"
+	"strconv"
 	"time"
 
 	"strings"
@@ -78,6 +82,10 @@ type RunDocumentPluginInput struct {
 	DocumentType       string      `json:"documentType"`
 	DocumentPath       string      `json:"documentPath"`
 	DocumentParameters interface{} `json:"documentParameters"`
+	// DocumentVersion pins the version of the SSM document to execute
+	DocumentVersion string `json:"documentVersion"`
+	// DocumentHash is the hex encoded SHA-256 hash the content of the document must match to be executed
+	DocumentHash string `json:"documentHash"`
 }
 
 // ExecutePluginDepth is the struct that is sent through to the sub-documents to maintain the depth of execution
@@ -148,6 +156,7 @@ func (p *Plugin) runDocument(input *RunDocumentPluginInput, config contracts.Con
 	if input.DocumentType == SSMDocumentType {
 		if documentPath, err = p.downloadDocumentFromSSM(log, config, input); err != nil {
 			output.MarkAsFailed(err)
+			return
 		}
 	} else {
 		if filepath.IsAbs(input.DocumentPath) {
@@ -158,13 +167,14 @@ func (p *Plugin) runDocument(input *RunDocumentPluginInput, config contracts.Con
 			documentPath = filepath.Join(orchestrationDir, downloadsDir, input.DocumentPath)
 		}
 	}
-	if pluginsInfo, err = p.prepareDocumentForExecution(log, documentPath, config, input.DocumentParameters); err != nil {
+	if pluginsInfo, err = p.prepareDocumentForExecution(log, documentPath, config, input.DocumentParameters, input.DocumentHash); err != nil {
 		output.MarkAsFailed(fmt.Errorf("There was an error while preparing documents - %v", err.Error()))
 		return
 	}
-	// Sending execution depth in Configuration.Settings to the sub-documents
+	// Sending execution depth in Configuration.Settings and the detect-only mode to the sub-documents
 	for i, plugins := range pluginsInfo {
 		plugins.Configuration.Settings = &ExecutePluginDepth{executeCommandDepth: execDepth}
+		plugins.Configuration.DetectOnly = config.DetectOnly
 		pluginsInfo[i] = plugins
 	}
 
@@ -204,11 +214,19 @@ func (p *Plugin) downloadDocumentFromSSM(log log.T, config contracts.Configurati
 	destination := filepath.Join(config.OrchestrationDirectory, downloadsDir)
 
 	docName, docVersion := docparser.ParseDocumentNameAndVersion(input.DocumentPath)
+	if input.DocumentVersion != "" {
+		docVersion = input.DocumentVersion
+	}
 	var docResponse *ssm.GetDocumentOutput
 	if docResponse, err = p.ssmSvc.GetDocument(log, docName, docVersion); err != nil {
 		log.Errorf("Unable to get ssm document. %v", err)
 		return "", err
 	}
+	// the version returned by the service is checked as well, a pinned document must not be replaced by another version
+	if input.DocumentVersion != "" && (docResponse.DocumentVersion == nil || *docResponse.DocumentVersion != input.DocumentVersion) {
+		return "", fmt.Errorf("document %v was returned with version %v when version %v is pinned",
+			docName, aws.StringValue(docResponse.DocumentVersion), input.DocumentVersion)
+	}
 
 	log.Debugf("Destination is %v ", destination)
 	// create directory to download github resources
@@ -228,7 +246,8 @@ func (p *Plugin) downloadDocumentFromSSM(log log.T, config contracts.Configurati
 }
 
 // PrepareDocumentForExecution parses the raw content of the document, validates it and returns a PluginState that can be executed.
-func (p *Plugin) prepareDocumentForExecution(log log.T, pathToFile string, config contracts.Configuration, params interface{}) (pluginsInfo []contracts.PluginState, err error) {
+// The content of the document is verified against documentHash when a hash is pinned.
+func (p *Plugin) prepareDocumentForExecution(log log.T, pathToFile string, config contracts.Configuration, params interface{}, documentHash string) (pluginsInfo []contracts.PluginState, err error) {
 	parameters := make(map[string]interface{})
 	if params != nil {
 		switch params := params.(type) {
@@ -265,6 +284,10 @@ func (p *Plugin) prepareDocumentForExecution(log log.T, pathToFile string, confi
 		log.Error("Could not read document from remote resource - ", err)
 		return nil, err
 	}
+	if err = verifyDocumentHash(rawDocument, documentHash); err != nil {
+		log.Error("Document verification failed - ", err)
+		return nil, err
+	}
 	log.Infof("Sending the document received for parsing - %v", string(rawDocument))
 
 	return p.execDoc.ParseDocument(p.context, rawDocument, config.OrchestrationDirectory, config.OutputS3BucketName, config.OutputS3KeyPrefix, config.MessageId, config.PluginID, config.DefaultWorkingDirectory, parameters)
@@ -302,9 +325,38 @@ func validateInput(input *RunDocumentPluginInput) (valid bool, err error) {
 	if input.DocumentPath == "" {
 		return false, errors.New("Document Path must be provided")
 	}
+	if input.DocumentVersion != "" {
+		if input.DocumentType != SSMDocumentType {
+			return false, errors.New("Document version can only be pinned for documents of type SSMDocument")
+		}
+		// a pinned version has to identify a single version, $LATEST and $DEFAULT may change before the execution
+		if version, err := strconv.Atoi(input.DocumentVersion); err != nil || version < 1 {
+			return false, fmt.Errorf("Document version %v is invalid, the version must be a positive number", input.DocumentVersion)
+		}
+		if _, pathVersion := docparser.ParseDocumentNameAndVersion(input.DocumentPath); pathVersion != "" && pathVersion != input.DocumentVersion {
+			return false, fmt.Errorf("Document version %v does not match the version %v in the document path", input.DocumentVersion, pathVersion)
+		}
+	}
+	if input.DocumentHash != "" {
+		if hash, err := hex.DecodeString(input.DocumentHash); err != nil || len(hash) != sha256.Size {
+			return false, errors.New("Document hash must be a hex encoded SHA-256 hash")
+		}
+	}
 	return true, nil
 }
 
+// verifyDocumentHash compares the SHA-256 hash of the document content with the pinned hash, an empty hash is not verified
+func verifyDocumentHash(rawDocument []byte, documentHash string) error {
+	if documentHash == "" {
+		return nil
+	}
+	hash := sha256.Sum256(rawDocument)
+	if actual := hex.EncodeToString(hash[:]); !strings.EqualFold(actual, documentHash) {
+		return fmt.Errorf("document hash %v does not match the pinned hash %v", actual, documentHash)
+	}
+	return nil
+}
+
 // readFileContents is a method to read the contents of a give file path
 func readFileContents(log log.T, filesysdep filemanager.FileSystem, destinationPath string) (fileContent []byte, err error) {
 
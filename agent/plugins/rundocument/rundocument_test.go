@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -173,7 +174,7 @@ func TestExecutePlugin_PrepareDocumentForExecution(t *testing.T) {
 		execDoc: &execMock,
 	}
 
-	_, err := p.prepareDocumentForExecution(logMock, "document/name.json", conf, "")
+	_, err := p.prepareDocumentForExecution(logMock, "document/name.json", conf, "", "")
 
 	assert.NoError(t, err)
 	fileMock.AssertExpectations(t)
@@ -195,7 +196,7 @@ func TestExecutePlugin_PrepareDocumentForExecutionFail(t *testing.T) {
 		execDoc: &execMock,
 	}
 
-	_, err := p.prepareDocumentForExecution(logMock, "document/name.json", conf, "")
+	_, err := p.prepareDocumentForExecution(logMock, "document/name.json", conf, "", "")
 
 	assert.Error(t, err)
 	assert.Equal(t, fmt.Errorf("File is empty!"), err)
@@ -228,7 +229,7 @@ param2: world`
 		execDoc: &execMock,
 	}
 
-	_, err := p.prepareDocumentForExecution(logMock, "document/doc-name.json", conf, params)
+	_, err := p.prepareDocumentForExecution(logMock, "document/doc-name.json", conf, params, "")
 
 	assert.NoError(t, err)
 	fileMock.AssertExpectations(t)
@@ -260,7 +261,7 @@ func TestExecuteImpl_PrepareDocumentForExecutionParametersJSON(t *testing.T) {
 		execDoc: &execMock,
 	}
 
-	_, err := p.prepareDocumentForExecution(logMock, "document/doc-name.yaml", conf, params)
+	_, err := p.prepareDocumentForExecution(logMock, "document/doc-name.yaml", conf, params, "")
 
 	assert.NoError(t, err)
 	fileMock.AssertExpectations(t)
@@ -613,6 +614,128 @@ func TestDownloadDocumentFromSSM_ARNName(t *testing.T) {
 
 }
 
+func TestValidateInput_PinnedDocument(t *testing.T) {
+	hash := "ed7002b439e9ac845f22357d822bac1444730fbdb6016d3ec9432297b9ec9f73"
+	testCases := []struct {
+		name  string
+		input RunDocumentPluginInput
+		valid bool
+	}{
+		{"version and hash", RunDocumentPluginInput{DocumentType: SSMDocumentType, DocumentPath: "mySharedDocument", DocumentVersion: "3", DocumentHash: hash}, true},
+		{"version matches path", RunDocumentPluginInput{DocumentType: SSMDocumentType, DocumentPath: "mySharedDocument:3", DocumentVersion: "3"}, true},
+		{"hash of local document", RunDocumentPluginInput{DocumentType: LocalPathType, DocumentPath: "doc.json", DocumentHash: strings.ToUpper(hash)}, true},
+		{"version differs from path", RunDocumentPluginInput{DocumentType: SSMDocumentType, DocumentPath: "mySharedDocument:2", DocumentVersion: "3"}, false},
+		{"latest version", RunDocumentPluginInput{DocumentType: SSMDocumentType, DocumentPath: "mySharedDocument", DocumentVersion: "$LATEST"}, false},
+		{"version of local document", RunDocumentPluginInput{DocumentType: LocalPathType, DocumentPath: "doc.json", DocumentVersion: "3"}, false},
+		{"hash not sha256", RunDocumentPluginInput{DocumentType: SSMDocumentType, DocumentPath: "mySharedDocument", DocumentHash: "abc"}, false},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			valid, err := validateInput(&testCase.input)
+			assert.Equal(t, testCase.valid, valid)
+			assert.Equal(t, testCase.valid, err == nil)
+		})
+	}
+}
+
+func TestVerifyDocumentHash(t *testing.T) {
+	// sha256 of "content"
+	hash := "ed7002b439e9ac845f22357d822bac1444730fbdb6016d3ec9432297b9ec9f73"
+
+	assert.NoError(t, verifyDocumentHash([]byte("content"), hash))
+	assert.NoError(t, verifyDocumentHash([]byte("content"), strings.ToUpper(hash)))
+	assert.NoError(t, verifyDocumentHash([]byte("modified content"), ""))
+	assert.Error(t, verifyDocumentHash([]byte("modified content"), hash))
+}
+
+func TestExecutePlugin_PrepareDocumentForExecutionHashMismatch(t *testing.T) {
+	execMock := rundocument.NewExecMock()
+	fileMock := filemock.FileSystemMock{}
+	conf := createStubConfiguration("orch", "bucket", "prefix", "1234-1234-1234", "directory")
+
+	fileMock.On("ReadFile", "document/name.json").Return("modified content", nil)
+
+	p := Plugin{
+		context: contextMock,
+		filesys: &fileMock,
+		execDoc: &execMock,
+	}
+
+	_, err := p.prepareDocumentForExecution(logMock, "document/name.json", conf, "", "ed7002b439e9ac845f22357d822bac1444730fbdb6016d3ec9432297b9ec9f73")
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "does not match the pinned hash")
+	fileMock.AssertExpectations(t)
+	// the document must not be parsed
+	execMock.AssertExpectations(t)
+}
+
+func TestDownloadDocumentFromSSM_PinnedVersion(t *testing.T) {
+	conf := createStubConfiguration("orch", "bucket", "prefix", "1234-1234-1234", "directory")
+	input := RunDocumentPluginInput{}
+	input.DocumentType = SSMDocumentType
+	input.DocumentPath = "arn:aws:ssm:us-east-1:1234567890:document/mySharedDocument"
+	input.DocumentVersion = "4"
+
+	fileMock := filemock.FileSystemMock{}
+	ssmMock := ssmsvc.NewMockDefault()
+
+	content := "content"
+	version := "4"
+	docResponse := ssm.GetDocumentOutput{
+		Content:         &content,
+		DocumentVersion: &version,
+	}
+
+	ssmMock.On("GetDocument", contextMock.Log(), "arn:aws:ssm:us-east-1:1234567890:document/mySharedDocument", "4").Return(&docResponse, nil)
+	fileMock.On("MakeDirs", filepath.Join("orch", "downloads")).Return(nil)
+	fileMock.On("WriteFile", filepath.Join("orch", "downloads", "mySharedDocument.json"), content).Return(nil)
+	p := Plugin{
+		context: contextMock,
+		filesys: &fileMock,
+		ssmSvc:  ssmMock,
+	}
+
+	pathToFile, err := p.downloadDocumentFromSSM(contextMock.Log(), conf, &input)
+
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join("orch", "downloads", "mySharedDocument.json"), pathToFile)
+	ssmMock.AssertExpectations(t)
+	fileMock.AssertExpectations(t)
+}
+
+func TestDownloadDocumentFromSSM_PinnedVersionMismatch(t *testing.T) {
+	conf := createStubConfiguration("orch", "bucket", "prefix", "1234-1234-1234", "directory")
+	input := RunDocumentPluginInput{}
+	input.DocumentType = SSMDocumentType
+	input.DocumentPath = "mySharedDocument"
+	input.DocumentVersion = "4"
+
+	fileMock := filemock.FileSystemMock{}
+	ssmMock := ssmsvc.NewMockDefault()
+
+	content := "content"
+	version := "5"
+	docResponse := ssm.GetDocumentOutput{
+		Content:         &content,
+		DocumentVersion: &version,
+	}
+
+	ssmMock.On("GetDocument", contextMock.Log(), "mySharedDocument", "4").Return(&docResponse, nil)
+	p := Plugin{
+		context: contextMock,
+		filesys: &fileMock,
+		ssmSvc:  ssmMock,
+	}
+
+	_, err := p.downloadDocumentFromSSM(contextMock.Log(), conf, &input)
+
+	assert.Error(t, err)
+	ssmMock.AssertExpectations(t)
+	// the document must not be written to disk
+	fileMock.AssertExpectations(t)
+}
+
 func createStubExecutionDepth(depth int) *ExecutePluginDepth {
 	currentDepth := ExecutePluginDepth{}
 	currentDepth.executeCommandDepth = depth
@@ -0,0 +1,45 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build linux
+// +build linux
+
+// Package runscript implements the runscript plugin.
+package runscript
+
+import (
+	"os"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/executers"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/session/utility"
+)
+
+// leaseIsolatedUser is decoupled for unit tests
+var leaseIsolatedUser = utility.LeaseIsolatedUser
+
+// leaseCommandUser leases a user of the isolated user pool when the agent configuration isolates commands,
+// it returns nil when the commands run as the agent user
+func leaseCommandUser(log log.T, appConfig appconfig.SsmagentConfig) (*utility.IsolatedUserLease, error) {
+	config := appConfig.Mgs.IsolatedUsers
+	if !config.Enabled || !config.RunCommand || os.Geteuid() != 0 {
+		return nil, nil
+	}
+	return leaseIsolatedUser(log, config)
+}
+
+// commandUserExecuter returns the executer running the commands as the leased user
+func commandUserExecuter(lease *utility.IsolatedUserLease, pseudoTerminal bool) executers.T {
+	return executers.UserCommandExecuter{Uid: lease.Uid, Gid: lease.Gid, PseudoTerminal: pseudoTerminal}
+}
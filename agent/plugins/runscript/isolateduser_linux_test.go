@@ -0,0 +1,109 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build linux
+// +build linux
+
+package runscript
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/contracts"
+	"github.com/aws/amazon-ssm-agent/agent/executers"
+	"github.com/aws/amazon-ssm-agent/agent/fileutil"
+	"github.com/aws/amazon-ssm-agent/agent/framework/processor/executer/iohandler"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	logmocks "github.com/aws/amazon-ssm-agent/agent/mocks/log"
+	"github.com/aws/amazon-ssm-agent/agent/session/utility"
+	"github.com/aws/amazon-ssm-agent/agent/task"
+	"github.com/stretchr/testify/assert"
+)
+
+const nobodyID = 65534
+
+// isolatedUserHome creates a home and temp directory owned by nobody, reachable by nobody
+func isolatedUserHome(t *testing.T) (homeDir string, tempDir string) {
+	root, err := os.MkdirTemp("", "isolateduser")
+	assert.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(root) })
+	assert.NoError(t, os.Chmod(root, 0755))
+	homeDir = filepath.Join(root, "home")
+	tempDir = filepath.Join(homeDir, ".tmp")
+	assert.NoError(t, os.MkdirAll(tempDir, 0700))
+	assert.NoError(t, os.Chown(homeDir, nobodyID, nobodyID))
+	assert.NoError(t, os.Chown(tempDir, nobodyID, nobodyID))
+	return homeDir, tempDir
+}
+
+func TestRunCommandsAsIsolatedUser(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("isolated users require root")
+	}
+	homeDir, tempDir := isolatedUserHome(t)
+	origLease := leaseIsolatedUser
+	leases := 0
+	leaseIsolatedUser = func(log log.T, config appconfig.IsolatedUsersCfg) (*utility.IsolatedUserLease, error) {
+		leases++
+		return &utility.IsolatedUserLease{UserName: "ssm-session-01", Uid: nobodyID, Gid: nobodyID, HomeDir: homeDir, TempDir: tempDir}, nil
+	}
+	t.Cleanup(func() { leaseIsolatedUser = origLease })
+
+	config := appconfig.DefaultConfig()
+	config.Mgs.IsolatedUsers.Enabled = true
+	config.Mgs.IsolatedUsers.RunCommand = true
+	ctx := context.NewMockDefaultWithConfig(config)
+	p := &Plugin{
+		Context:         ctx,
+		CommandExecuter: executers.ShellCommandExecuter{},
+		Name:            appconfig.PluginNameAwsRunShellScript,
+		ScriptName:      shellScriptName,
+		ShellCommand:    shellCommand,
+		ShellArguments:  shellArgs,
+		ByteOrderMark:   fileutil.ByteOrderMarkSkip,
+	}
+	orchestrationDir := t.TempDir()
+	output := iohandler.NewDefaultIOHandler(ctx, contracts.IOConfiguration{OrchestrationDirectory: orchestrationDir})
+	output.Init(pluginID)
+
+	p.runCommands(pluginID, RunScriptPluginInput{
+		RunCommand:  []string{"id -u", "pwd", "echo $TMPDIR", "ls " + orchestrationDir + " || echo denied"},
+		Environment: map[string]string{},
+	}, orchestrationDir, defaultWorkingDirectory, task.NewChanneledCancelFlag(), output)
+	output.Close()
+
+	assert.Equal(t, 1, leases)
+	assert.Equal(t, contracts.ResultStatusSuccess, output.GetStatus(), output.GetStderr())
+	assert.Equal(t, []string{"65534", tempDir, tempDir, "denied"}, strings.Fields(output.GetStdout()))
+	assert.FileExists(t, filepath.Join(tempDir, shellScriptName))
+}
+
+func TestLeaseCommandUserDisabled(t *testing.T) {
+	origLease := leaseIsolatedUser
+	leaseIsolatedUser = func(log log.T, config appconfig.IsolatedUsersCfg) (*utility.IsolatedUserLease, error) {
+		t.Fatal("commands are not isolated")
+		return nil, nil
+	}
+	t.Cleanup(func() { leaseIsolatedUser = origLease })
+
+	config := appconfig.DefaultConfig()
+	config.Mgs.IsolatedUsers.Enabled = true
+	lease, err := leaseCommandUser(logmocks.NewMockLog(), config)
+	assert.NoError(t, err)
+	assert.Nil(t, lease)
+}
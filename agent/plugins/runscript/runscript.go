@@ -15,8 +15,12 @@
 package runscript
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/aws/amazon-ssm-agent/agent/context"
@@ -27,22 +31,29 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/jsonutil"
 	"github.com/aws/amazon-ssm-agent/agent/plugins/pluginutil"
 	messageContracts "github.com/aws/amazon-ssm-agent/agent/runcommand/contracts"
+	"github.com/aws/amazon-ssm-agent/agent/sdkutil"
 	"github.com/aws/amazon-ssm-agent/agent/task"
 	"github.com/aws/amazon-ssm-agent/common/identity/identity"
 	"github.com/aws/amazon-ssm-agent/common/runtimeconfig"
+	"github.com/aws/aws-sdk-go/aws"
 )
 
 const (
 	downloadsDir = "downloads" //Directory under the orchestration directory where the downloaded resource resides
 )
 
-var getRemoteProvider = identity.GetRemoteProvider
+var (
+	getRemoteProvider = identity.GetRemoteProvider
+	scopedCredentials = sdkutil.ScopedCredentials
+)
 
 // Plugin is the type for the runscript plugin.
 type Plugin struct {
 	Context context.T
 	// ExecuteCommand is an object that can execute commands.
 	CommandExecuter executers.T
+	// PseudoTerminalCommandExecuter executes commands attached to a pseudo-terminal, nil when not supported by the plugin
+	PseudoTerminalCommandExecuter executers.T
 	// Name is the plugin name (PowerShellScript or ShellScript)
 	Name                  string
 	ScriptName            string
@@ -60,6 +71,31 @@ type RunScriptPluginInput struct {
 	ID               string
 	WorkingDirectory string
 	TimeoutSeconds   interface{}
+	// AllocatePseudoTerminal runs the commands attached to a pseudo-terminal, as a boolean or "true"/"false"
+	AllocatePseudoTerminal interface{}
+	// ScopedCredentials vends temporary credentials of the scoped credentials role to the commands, nil when not requested
+	ScopedCredentials *ScopedCredentialsInput
+	// CredentialsEnvironment holds the scoped credentials added to the environment of the commands, it is never logged
+	CredentialsEnvironment credentialsEnvironment `json:"-"`
+}
+
+// ScopedCredentialsInput requests scoped credentials for the commands
+type ScopedCredentialsInput struct {
+	// Policy is an optional session policy as a JSON string or object, restricting the permissions of the role further
+	Policy interface{}
+}
+
+// credentialsEnvironment is the environment holding credentials, printed without the values
+type credentialsEnvironment map[string]string
+
+// String redacts the credentials when the plugin input is logged
+func (env credentialsEnvironment) String() string {
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name+"=<redacted>")
+	}
+	sort.Strings(names)
+	return fmt.Sprintf("%v", names)
 }
 
 // Execute runs multiple sets of commands and returns their outputs.
@@ -81,7 +117,7 @@ func (p *Plugin) Execute(config contracts.Configuration, cancelFlag task.CancelF
 	} else if cancelFlag.Canceled() {
 		output.MarkAsCancelled()
 	} else {
-		p.runCommandsRawInput(config.PluginID, config.Properties, config.OrchestrationDirectory, config.DefaultWorkingDirectory, cancelFlag, output, runCommandID)
+		p.runCommandsRawInput(config.PluginID, config.Properties, config.OrchestrationDirectory, config.DefaultWorkingDirectory, cancelFlag, output, runCommandID, config.DocumentName)
 	}
 }
 
@@ -122,9 +158,44 @@ func (p *Plugin) setCommandIdEnvironment(pluginInput RunScriptPluginInput, runCo
 	}
 }
 
+// setScopedCredentialsEnvironment vends the scoped credentials requested by the plugin input. The credentials replace
+// the shared credentials of the agent, the commands fail instead of falling back to the agent permissions.
+func (p *Plugin) setScopedCredentialsEnvironment(pluginInput *RunScriptPluginInput, runCommandID string, documentName string) error {
+	if pluginInput.ScopedCredentials == nil {
+		return nil
+	}
+
+	request := sdkutil.ScopedCredentialsRequest{DocumentName: documentName, CommandID: runCommandID}
+	switch policy := pluginInput.ScopedCredentials.Policy.(type) {
+	case nil:
+	case string:
+		request.Policy = strings.TrimSpace(policy)
+	default:
+		policyJSON, err := json.Marshal(policy)
+		if err != nil {
+			return fmt.Errorf("invalid scoped credentials policy: %v", err)
+		}
+		request.Policy = string(policyJSON)
+	}
+
+	creds, err := scopedCredentials(p.Context, request)
+	if err != nil {
+		return fmt.Errorf("failed to get scoped credentials: %v", err)
+	}
+
+	delete(pluginInput.Environment, "AWS_PROFILE")
+	delete(pluginInput.Environment, "AWS_SHARED_CREDENTIALS_FILE")
+	pluginInput.CredentialsEnvironment = credentialsEnvironment{
+		"AWS_ACCESS_KEY_ID":     aws.StringValue(creds.AccessKeyId),
+		"AWS_SECRET_ACCESS_KEY": aws.StringValue(creds.SecretAccessKey),
+		"AWS_SESSION_TOKEN":     aws.StringValue(creds.SessionToken),
+	}
+	return nil
+}
+
 // runCommandsRawInput executes one set of commands and returns their output.
 // The input is in the default json unmarshal format (e.g. map[string]interface{}).
-func (p *Plugin) runCommandsRawInput(pluginID string, rawPluginInput interface{}, orchestrationDirectory string, defaultWorkingDirectory string, cancelFlag task.CancelFlag, output iohandler.IOHandler, runCommandID string) {
+func (p *Plugin) runCommandsRawInput(pluginID string, rawPluginInput interface{}, orchestrationDirectory string, defaultWorkingDirectory string, cancelFlag task.CancelFlag, output iohandler.IOHandler, runCommandID string, documentName string) {
 	var pluginInput RunScriptPluginInput
 	err := jsonutil.Remarshal(rawPluginInput, &pluginInput)
 	if err != nil {
@@ -139,6 +210,10 @@ func (p *Plugin) runCommandsRawInput(pluginID string, rawPluginInput interface{}
 
 	p.setCommandIdEnvironment(pluginInput, runCommandID)
 	p.setShareCredsEnvironment(pluginInput)
+	if err = p.setScopedCredentialsEnvironment(&pluginInput, runCommandID, documentName); err != nil {
+		output.MarkAsFailed(err)
+		return
+	}
 
 	p.runCommands(pluginID, pluginInput, orchestrationDirectory, defaultWorkingDirectory, cancelFlag, output)
 }
@@ -174,8 +249,47 @@ func (p *Plugin) runCommands(pluginID string, pluginInput RunScriptPluginInput,
 		return
 	}
 
+	// Select the command executer
+	commandExecuter := p.CommandExecuter
+	allocatePseudoTerminal, err := parseAllocatePseudoTerminal(pluginInput.AllocatePseudoTerminal)
+	if err != nil {
+		output.MarkAsFailed(err)
+		return
+	}
+	if allocatePseudoTerminal {
+		if p.PseudoTerminalCommandExecuter == nil {
+			output.MarkAsFailed(fmt.Errorf("pseudo-terminal allocation is not supported by %v", p.Name))
+			return
+		}
+		log.Debug("Running commands attached to a pseudo-terminal")
+		commandExecuter = p.PseudoTerminalCommandExecuter
+	}
+
+	// Run the commands as a user of the isolated user pool when configured,
+	// the script and relative working directories are then placed in the private temp directory of the user
+	scriptDir := orchestrationDir
+	isolatedUser, err := leaseCommandUser(log, p.Context.AppConfig())
+	if err != nil {
+		output.MarkAsFailed(fmt.Errorf("failed to lease an isolated user: %v", err))
+		return
+	}
+	if isolatedUser != nil {
+		defer isolatedUser.Release(log)
+		log.Infof("Running commands as isolated user %v", isolatedUser.UserName)
+		commandExecuter = commandUserExecuter(isolatedUser, allocatePseudoTerminal)
+		scriptDir = isolatedUser.TempDir
+		if !filepath.IsAbs(pluginInput.WorkingDirectory) {
+			workingDir = isolatedUser.TempDir
+		}
+		if pluginInput.Environment == nil {
+			pluginInput.Environment = make(map[string]string)
+		}
+		pluginInput.Environment["HOME"] = isolatedUser.HomeDir
+		pluginInput.Environment["TMPDIR"] = isolatedUser.TempDir
+	}
+
 	// Create script file path
-	scriptPath := filepath.Join(orchestrationDir, p.ScriptName)
+	scriptPath := filepath.Join(scriptDir, p.ScriptName)
 	log.Debugf("Writing commands %v to file %v", pluginInput, scriptPath)
 
 	// Create script file
@@ -183,6 +297,12 @@ func (p *Plugin) runCommands(pluginID string, pluginInput RunScriptPluginInput,
 		output.MarkAsFailed(fmt.Errorf("failed to create script file. %v", err))
 		return
 	}
+	if isolatedUser != nil {
+		if err = os.Chown(scriptPath, int(isolatedUser.Uid), int(isolatedUser.Gid)); err != nil {
+			output.MarkAsFailed(fmt.Errorf("failed to hand the script file to isolated user %v. %v", isolatedUser.UserName, err))
+			return
+		}
+	}
 
 	// Set execution time
 	executionTimeout := pluginutil.ValidateExecutionTimeout(log, pluginInput.TimeoutSeconds)
@@ -191,11 +311,24 @@ func (p *Plugin) runCommands(pluginID string, pluginInput RunScriptPluginInput,
 	commandName := p.ShellCommand
 	commandArguments := append(p.ShellArguments, scriptPath)
 
+	// Add the credentials only now, the environment above is logged
+	environment := pluginInput.Environment
+	if len(pluginInput.CredentialsEnvironment) > 0 {
+		environment = make(map[string]string, len(pluginInput.Environment)+len(pluginInput.CredentialsEnvironment))
+		for name, value := range pluginInput.Environment {
+			environment[name] = value
+		}
+		for name, value := range pluginInput.CredentialsEnvironment {
+			environment[name] = value
+		}
+	}
+
 	// Execute Command
-	exitCode, err := p.CommandExecuter.NewExecute(p.Context, workingDir, output.GetStdoutWriter(), output.GetStderrWriter(), cancelFlag, executionTimeout, commandName, commandArguments, pluginInput.Environment)
+	exitCode, accounting, err := commandExecuter.NewExecute(p.Context, workingDir, output.GetStdoutWriter(), output.GetStderrWriter(), cancelFlag, executionTimeout, commandName, commandArguments, environment)
 
 	// Set output status
 	output.SetExitCode(exitCode)
+	output.AddProcessAccounting(accounting)
 	output.SetStatus(pluginutil.GetStatus(exitCode, cancelFlag))
 
 	if err != nil {
@@ -207,3 +340,24 @@ func (p *Plugin) runCommands(pluginID string, pluginInput RunScriptPluginInput,
 		}
 	}
 }
+
+// parseAllocatePseudoTerminal reads the AllocatePseudoTerminal input, which is not set by default
+func parseAllocatePseudoTerminal(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return v, nil
+	case string:
+		if strings.TrimSpace(v) == "" {
+			return false, nil
+		}
+		allocate, err := strconv.ParseBool(strings.TrimSpace(v))
+		if err != nil {
+			return false, fmt.Errorf("invalid value %q for AllocatePseudoTerminal, expected true or false", v)
+		}
+		return allocate, nil
+	default:
+		return false, fmt.Errorf("invalid value %v for AllocatePseudoTerminal, expected true or false", v)
+	}
+}
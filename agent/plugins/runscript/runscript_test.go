@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"testing"
 
+	contextpkg "github.com/aws/amazon-ssm-agent/agent/context"
 	"github.com/aws/amazon-ssm-agent/agent/mocks/context"
 	"github.com/aws/amazon-ssm-agent/agent/mocks/executers"
 	"github.com/aws/amazon-ssm-agent/agent/mocks/log"
@@ -29,10 +30,13 @@ import (
 	iohandlermocks "github.com/aws/amazon-ssm-agent/agent/framework/processor/executer/iohandler/mock"
 	multiwritermock "github.com/aws/amazon-ssm-agent/agent/framework/processor/executer/iohandler/multiwriter/mock"
 	"github.com/aws/amazon-ssm-agent/agent/jsonutil"
+	"github.com/aws/amazon-ssm-agent/agent/sdkutil"
 	"github.com/aws/amazon-ssm-agent/agent/task"
 	"github.com/aws/amazon-ssm-agent/common/identity"
 	"github.com/aws/amazon-ssm-agent/common/runtimeconfig"
 	"github.com/aws/amazon-ssm-agent/common/runtimeconfig/mocks"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/twinj/uuid"
@@ -50,7 +54,6 @@ type TestCase struct {
 type CommandTester func(p *Plugin, mockCancelFlag *taskmocks.MockCancelFlag, mockExecuter *executers.MockCommandExecuter, mockIOHandler *iohandlermocks.MockIOHandler)
 
 const (
-	orchestrationDirectory  = "OrchesDir"
 	defaultWorkingDirectory = ""
 	s3BucketName            = "bucket"
 	s3KeyPrefix             = "key"
@@ -158,9 +161,9 @@ func testRunScripts(t *testing.T, testCase TestCase, rawInput bool) {
 			err := jsonutil.Remarshal(testCase.Input, &rawPluginInput)
 			assert.Nil(t, err)
 
-			p.runCommandsRawInput(pluginID, rawPluginInput, orchestrationDirectory, defaultWorkingDirectory, mockCancelFlag, mockIOHandler, runCommandID)
+			p.runCommandsRawInput(pluginID, rawPluginInput, t.TempDir(), defaultWorkingDirectory, mockCancelFlag, mockIOHandler, runCommandID, "")
 		} else {
-			p.runCommands(pluginID, testCase.Input, orchestrationDirectory, defaultWorkingDirectory, mockCancelFlag, mockIOHandler)
+			p.runCommands(pluginID, testCase.Input, t.TempDir(), defaultWorkingDirectory, mockCancelFlag, mockIOHandler)
 		}
 	}
 
@@ -257,6 +260,61 @@ func TestSetCommandIdEnvironment(t *testing.T) {
 	assert.Len(t, pluginInput.Environment, 1)
 }
 
+func TestSetScopedCredentialsEnvironment(t *testing.T) {
+	oldFunc := scopedCredentials
+	defer func() { scopedCredentials = oldFunc }()
+
+	var requests []sdkutil.ScopedCredentialsRequest
+	scopedCredentials = func(context contextpkg.T, request sdkutil.ScopedCredentialsRequest) (*sts.Credentials, error) {
+		requests = append(requests, request)
+		return &sts.Credentials{AccessKeyId: aws.String("AKID"), SecretAccessKey: aws.String("secret"), SessionToken: aws.String("token")}, nil
+	}
+	p := &Plugin{Context: context.NewMockDefault()}
+
+	// Test scoped credentials not requested
+	pluginInput := RunScriptPluginInput{Environment: map[string]string{"AWS_PROFILE": "SomeProfile"}}
+	assert.NoError(t, p.setScopedCredentialsEnvironment(&pluginInput, "SomeCommandId", "SomeDocument"))
+	assert.Empty(t, requests)
+	assert.Empty(t, pluginInput.CredentialsEnvironment)
+
+	// Test scoped credentials with a policy object replace the shared credentials
+	pluginInput = RunScriptPluginInput{
+		Environment: map[string]string{
+			"AWS_PROFILE":                 "SomeProfile",
+			"AWS_SHARED_CREDENTIALS_FILE": "SomeFile",
+			"SSM_COMMAND_ID":              "SomeCommandId",
+		},
+		ScopedCredentials: &ScopedCredentialsInput{Policy: map[string]interface{}{"Version": "2012-10-17"}},
+	}
+	assert.NoError(t, p.setScopedCredentialsEnvironment(&pluginInput, "SomeCommandId", "SomeDocument"))
+	assert.Equal(t, []sdkutil.ScopedCredentialsRequest{
+		{DocumentName: "SomeDocument", CommandID: "SomeCommandId", Policy: `{"Version":"2012-10-17"}`},
+	}, requests)
+	assert.Equal(t, map[string]string{"SSM_COMMAND_ID": "SomeCommandId"}, pluginInput.Environment)
+	assert.Equal(t, credentialsEnvironment{
+		"AWS_ACCESS_KEY_ID":     "AKID",
+		"AWS_SECRET_ACCESS_KEY": "secret",
+		"AWS_SESSION_TOKEN":     "token",
+	}, pluginInput.CredentialsEnvironment)
+
+	// Test the credentials are not printed with the plugin input
+	printed := fmt.Sprintf("%v", pluginInput)
+	assert.NotContains(t, printed, "secret")
+	assert.NotContains(t, printed, "token")
+	assert.Contains(t, printed, "AWS_SECRET_ACCESS_KEY=<redacted>")
+
+	// Test failing to get scoped credentials
+	scopedCredentials = func(context contextpkg.T, request sdkutil.ScopedCredentialsRequest) (*sts.Credentials, error) {
+		return nil, fmt.Errorf("AccessDenied")
+	}
+	pluginInput = RunScriptPluginInput{
+		Environment:       map[string]string{},
+		ScopedCredentials: &ScopedCredentialsInput{Policy: `{"Version":"2012-10-17"}`},
+	}
+	assert.EqualError(t, p.setScopedCredentialsEnvironment(&pluginInput, "SomeCommandId", "SomeDocument"), "failed to get scoped credentials: AccessDenied")
+	assert.Empty(t, pluginInput.CredentialsEnvironment)
+}
+
 // TestBucketsInDifferentRegions tests runScripts when S3Buckets are present in IAD and PDX region.
 func TestBucketsInDifferentRegions(t *testing.T) {
 	for _, testCase := range TestCases {
@@ -275,7 +333,7 @@ func testBucketsInDifferentRegions(t *testing.T, testCase TestCase, testingBucke
 		setIOHandlerExpectations(mockIOHandler, testCase)
 
 		// call method under test
-		p.runCommands(pluginID, testCase.Input, orchestrationDirectory, defaultWorkingDirectory, mockCancelFlag, mockIOHandler)
+		p.runCommands(pluginID, testCase.Input, t.TempDir(), defaultWorkingDirectory, mockCancelFlag, mockIOHandler)
 	}
 
 	testExecution(t, runScriptTester)
@@ -362,7 +420,7 @@ func testExecuteMultiInput(t *testing.T, testCases []TestCase) {
 				Properties:             pluginProperties,
 				OutputS3BucketName:     s3BucketName,
 				OutputS3KeyPrefix:      s3KeyPrefix,
-				OrchestrationDirectory: orchestrationDirectory,
+				OrchestrationDirectory: t.TempDir(),
 				BookKeepingFileName:    commandID,
 				PluginID:               pluginID,
 				MessageId:              "aws.ssm.21cd24ac-4aca-4d53-bd65-c06d64b7b343.i-03b9244a8137e5bac",
@@ -394,7 +452,7 @@ func testExecute(t *testing.T, testCase TestCase) {
 				Properties:             pluginProperties,
 				OutputS3BucketName:     s3BucketName,
 				OutputS3KeyPrefix:      s3KeyPrefix,
-				OrchestrationDirectory: orchestrationDirectory,
+				OrchestrationDirectory: t.TempDir(),
 				BookKeepingFileName:    commandID,
 				PluginID:               pluginID,
 				MessageId:              "aws.ssm.21cd24ac-4aca-4d53-bd65-c06d64b7b343.i-03b9244a8137e5bac",
@@ -411,7 +469,7 @@ func testExecuteWithEnvironment(t *testing.T, testCase TestCase) {
 
 		// set expectations
 		setCancelFlagExpectations(mockCancelFlag, 1)
-		mockExecuter.On("NewExecute", mock.Anything, testCase.Input.WorkingDirectory, testCase.Output.StdoutWriter, testCase.Output.StderrWriter, mockCancelFlag, mock.Anything, mock.Anything, mock.Anything, envVars).Return(testCase.Output.ExitCode, testCase.ExecuterError)
+		mockExecuter.On("NewExecute", mock.Anything, testCase.Input.WorkingDirectory, testCase.Output.StdoutWriter, testCase.Output.StderrWriter, mockCancelFlag, mock.Anything, mock.Anything, mock.Anything, envVars).Return(testCase.Output.ExitCode, (*contracts.ProcessAccounting)(nil), testCase.ExecuterError)
 		setIOHandlerExpectations(mockIOHandler, testCase)
 
 		// prepare plugin input
@@ -426,7 +484,7 @@ func testExecuteWithEnvironment(t *testing.T, testCase TestCase) {
 				Properties:             pluginProperties,
 				OutputS3BucketName:     s3BucketName,
 				OutputS3KeyPrefix:      s3KeyPrefix,
-				OrchestrationDirectory: orchestrationDirectory,
+				OrchestrationDirectory: t.TempDir(),
 				BookKeepingFileName:    commandID,
 				PluginID:               pluginID,
 				MessageId:              "aws.ssm.21cd24ac-4aca-4d53-bd65-c06d64b7b343.i-03b9244a8137e5bac",
@@ -466,13 +524,14 @@ func testExecution(t *testing.T, commandtester CommandTester) {
 
 func setExecuterExpectations(mockExecuter *executers.MockCommandExecuter, t TestCase, cancelFlag task.CancelFlag, p *Plugin) {
 	mockExecuter.On("NewExecute", mock.Anything, t.Input.WorkingDirectory, t.Output.StdoutWriter, t.Output.StderrWriter, cancelFlag, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
-		t.Output.ExitCode, t.ExecuterError)
+		t.Output.ExitCode, (*contracts.ProcessAccounting)(nil), t.ExecuterError)
 }
 
 func setIOHandlerExpectations(mockIOHandler *iohandlermocks.MockIOHandler, t TestCase) {
 	mockIOHandler.On("GetStdoutWriter").Return(t.Output.StdoutWriter)
 	mockIOHandler.On("GetStderrWriter").Return(t.Output.StderrWriter)
 	mockIOHandler.On("SetExitCode", t.Output.ExitCode).Return()
+	mockIOHandler.On("AddProcessAccounting", mock.Anything).Return()
 	mockIOHandler.On("SetStatus", t.Output.Status).Return()
 	if t.ExecuterError != nil {
 		mockIOHandler.On("GetStatus").Return(t.Output.Status)
@@ -485,3 +544,61 @@ func setCancelFlagExpectations(mockCancelFlag *taskmocks.MockCancelFlag, times i
 	mockCancelFlag.On("Canceled").Return(false).Times(times)
 	mockCancelFlag.On("ShutDown").Return(false).Times(times)
 }
+
+// TestRunScriptsWithPseudoTerminal tests that commands requesting a pseudo-terminal run with the pseudo-terminal executer.
+func TestRunScriptsWithPseudoTerminal(t *testing.T) {
+	for _, allocate := range []interface{}{true, "true"} {
+		testCase := generateTestCaseOk("0", envVars)
+		testCase.Input.AllocatePseudoTerminal = allocate
+		runScriptTester := func(p *Plugin, mockCancelFlag *taskmocks.MockCancelFlag, mockExecuter *executers.MockCommandExecuter, mockIOHandler *iohandlermocks.MockIOHandler) {
+			mockPseudoTerminalExecuter := new(executers.MockCommandExecuter)
+			p.PseudoTerminalCommandExecuter = mockPseudoTerminalExecuter
+			setExecuterExpectations(mockPseudoTerminalExecuter, testCase, mockCancelFlag, p)
+			setIOHandlerExpectations(mockIOHandler, testCase)
+
+			p.runCommands(pluginID, testCase.Input, t.TempDir(), defaultWorkingDirectory, mockCancelFlag, mockIOHandler)
+
+			mockPseudoTerminalExecuter.AssertExpectations(t)
+			mockExecuter.AssertNotCalled(t, "NewExecute", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		}
+
+		testExecution(t, runScriptTester)
+	}
+}
+
+// TestRunScriptsWithPseudoTerminalNotSupported tests that the plugin fails when it does not support pseudo-terminals.
+func TestRunScriptsWithPseudoTerminalNotSupported(t *testing.T) {
+	testCase := generateTestCaseOk("0", envVars)
+	testCase.Input.AllocatePseudoTerminal = true
+	runScriptTester := func(p *Plugin, mockCancelFlag *taskmocks.MockCancelFlag, mockExecuter *executers.MockCommandExecuter, mockIOHandler *iohandlermocks.MockIOHandler) {
+		mockIOHandler.On("MarkAsFailed", fmt.Errorf("pseudo-terminal allocation is not supported by %v", p.Name)).Return()
+
+		p.runCommands(pluginID, testCase.Input, t.TempDir(), defaultWorkingDirectory, mockCancelFlag, mockIOHandler)
+	}
+
+	testExecution(t, runScriptTester)
+}
+
+func TestParseAllocatePseudoTerminal(t *testing.T) {
+	testCases := []struct {
+		value    interface{}
+		expected bool
+		hasError bool
+	}{
+		{nil, false, false},
+		{"", false, false},
+		{false, false, false},
+		{true, true, false},
+		{"true", true, false},
+		{" True ", true, false},
+		{"false", false, false},
+		{"yes", false, true},
+		{1, false, true},
+	}
+
+	for _, testCase := range testCases {
+		allocate, err := parseAllocatePseudoTerminal(testCase.value)
+		assert.Equal(t, testCase.expected, allocate, "value %v", testCase.value)
+		assert.Equal(t, testCase.hasError, err != nil, "value %v", testCase.value)
+	}
+}
@@ -38,14 +38,15 @@ func NewRunShellPlugin(context context.T) (*runShellPlugin, error) {
 	shplugin := runShellPlugin{
 		context: context,
 		Plugin: Plugin{
-			Context:               context,
-			Name:                  appconfig.PluginNameAwsRunShellScript,
-			ScriptName:            shellScriptName,
-			ShellCommand:          shellCommand,
-			ShellArguments:        shellArgs,
-			ByteOrderMark:         fileutil.ByteOrderMarkSkip,
-			CommandExecuter:       executers.ShellCommandExecuter{},
-			IdentityRuntimeClient: runtimeconfig.NewIdentityRuntimeConfigClient(),
+			Context:                       context,
+			Name:                          appconfig.PluginNameAwsRunShellScript,
+			ScriptName:                    shellScriptName,
+			ShellCommand:                  shellCommand,
+			ShellArguments:                shellArgs,
+			ByteOrderMark:                 fileutil.ByteOrderMarkSkip,
+			CommandExecuter:               executers.ShellCommandExecuter{},
+			PseudoTerminalCommandExecuter: executers.PseudoTerminalCommandExecuter{},
+			IdentityRuntimeClient:         runtimeconfig.NewIdentityRuntimeConfigClient(),
 		},
 	}
 
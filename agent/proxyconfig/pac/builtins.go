@@ -0,0 +1,194 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package pac
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Assign functions to variables to allow unittest to override
+var lookupIP = net.LookupIP
+var interfaceAddrs = net.InterfaceAddrs
+var timeNow = time.Now
+
+var weekdays = []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+
+// globals returns the predefined functions available to proxy auto-config scripts
+func globals() map[string]value {
+	return map[string]value{
+		"isPlainHostName": builtin(func(args []value) (value, error) {
+			return !strings.Contains(toString(arg(args, 0)), "."), nil
+		}),
+		"dnsDomainIs": builtin(func(args []value) (value, error) {
+			return strings.HasSuffix(strings.ToLower(toString(arg(args, 0))), strings.ToLower(toString(arg(args, 1)))), nil
+		}),
+		"localHostOrDomainIs": builtin(func(args []value) (value, error) {
+			host := strings.ToLower(toString(arg(args, 0)))
+			hostDomain := strings.ToLower(toString(arg(args, 1)))
+			if host == hostDomain {
+				return true, nil
+			}
+			return !strings.Contains(host, ".") && strings.HasPrefix(hostDomain, host+"."), nil
+		}),
+		"isResolvable": builtin(func(args []value) (value, error) {
+			return resolveIPv4(toString(arg(args, 0))) != nil, nil
+		}),
+		"dnsResolve": builtin(func(args []value) (value, error) {
+			if ip := resolveIPv4(toString(arg(args, 0))); ip != nil {
+				return ip.String(), nil
+			}
+			return nil, nil
+		}),
+		"isInNet": builtin(func(args []value) (value, error) {
+			ip := resolveIPv4(toString(arg(args, 0)))
+			pattern := net.ParseIP(toString(arg(args, 1))).To4()
+			mask := net.ParseIP(toString(arg(args, 2))).To4()
+			if ip == nil || pattern == nil || mask == nil {
+				return false, nil
+			}
+			return ip.Mask(net.IPMask(mask)).Equal(pattern.Mask(net.IPMask(mask))), nil
+		}),
+		"convert_addr": builtin(func(args []value) (value, error) {
+			ip := net.ParseIP(toString(arg(args, 0))).To4()
+			if ip == nil {
+				return float64(0), nil
+			}
+			return float64(binary.BigEndian.Uint32(ip)), nil
+		}),
+		"myIpAddress": builtin(func(args []value) (value, error) {
+			return myIPAddress(), nil
+		}),
+		"dnsDomainLevels": builtin(func(args []value) (value, error) {
+			return float64(strings.Count(toString(arg(args, 0)), ".")), nil
+		}),
+		"shExpMatch": builtin(func(args []value) (value, error) {
+			return shellExpressionMatch(toString(arg(args, 0)), toString(arg(args, 1))), nil
+		}),
+		"weekdayRange": builtin(weekdayRange),
+		"timeRange":    builtin(timeRange),
+		"dateRange": builtin(func(args []value) (value, error) {
+			return nil, fmt.Errorf("dateRange is not supported")
+		}),
+		"alert": builtin(func(args []value) (value, error) {
+			return undefined{}, nil
+		}),
+	}
+}
+
+// resolveIPv4 returns the IPv4 address of a host name or literal, or nil if it cannot be resolved
+func resolveIPv4(host string) net.IP {
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.To4()
+	}
+	ips, err := lookupIP(host)
+	if err != nil {
+		return nil
+	}
+	for _, ip := range ips {
+		if ip4 := ip.To4(); ip4 != nil {
+			return ip4
+		}
+	}
+	return nil
+}
+
+func myIPAddress() string {
+	addrs, err := interfaceAddrs()
+	if err == nil {
+		for _, addr := range addrs {
+			if ipNet, ok := addr.(*net.IPNet); ok && !ipNet.IP.IsLoopback() && ipNet.IP.To4() != nil {
+				return ipNet.IP.String()
+			}
+		}
+	}
+	return "127.0.0.1"
+}
+
+// shellExpressionMatch matches str against a shell expression where * matches any run of characters and ? one character
+func shellExpressionMatch(str string, expression string) bool {
+	pattern := regexp.QuoteMeta(expression)
+	pattern = strings.ReplaceAll(pattern, `\*`, ".*")
+	pattern = strings.ReplaceAll(pattern, `\?`, ".")
+	matched, err := regexp.MatchString("^"+pattern+"$", str)
+	return err == nil && matched
+}
+
+// useGMT drops a trailing "GMT" argument and reports whether it was present
+func useGMT(args []value) ([]value, bool) {
+	if len(args) > 0 && toString(args[len(args)-1]) == "GMT" {
+		return args[:len(args)-1], true
+	}
+	return args, false
+}
+
+func now(gmt bool) time.Time {
+	if gmt {
+		return timeNow().UTC()
+	}
+	return timeNow()
+}
+
+func weekdayRange(args []value) (value, error) {
+	args, gmt := useGMT(args)
+	if len(args) == 0 || len(args) > 2 {
+		return false, nil
+	}
+	day := func(v value) int {
+		name := strings.ToUpper(toString(v))
+		for i, weekday := range weekdays {
+			if weekday == name {
+				return i
+			}
+		}
+		return -1
+	}
+	from, to := day(args[0]), day(arg(args, len(args)-1))
+	if from < 0 || to < 0 {
+		return false, nil
+	}
+	today := int(now(gmt).Weekday())
+	if from <= to {
+		return today >= from && today <= to, nil
+	}
+	return today >= from || today <= to, nil
+}
+
+func timeRange(args []value) (value, error) {
+	args, gmt := useGMT(args)
+	t := now(gmt)
+	seconds := t.Hour()*3600 + t.Minute()*60 + t.Second()
+	number := func(i int) int { return int(toNumber(args[i])) }
+	var from, to int
+	switch len(args) {
+	case 1:
+		return t.Hour() == number(0), nil
+	case 2:
+		from, to = number(0)*3600, number(1)*3600+3599
+	case 4:
+		from, to = number(0)*3600+number(1)*60, number(2)*3600+number(3)*60+59
+	case 6:
+		from, to = number(0)*3600+number(1)*60+number(2), number(3)*3600+number(4)*60+number(5)
+	default:
+		return false, nil
+	}
+	if from <= to {
+		return seconds >= from && seconds <= to, nil
+	}
+	return seconds >= from || seconds <= to, nil
+}
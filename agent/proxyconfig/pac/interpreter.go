@@ -0,0 +1,625 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package pac
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// maxSteps bounds the work done by one evaluation so a faulty script cannot hang the agent
+const maxSteps = 1000000
+
+// value is a script value: string, float64, bool, nil (null), undefined, []value, *function or builtin
+type value interface{}
+
+type undefined struct{}
+
+type function struct {
+	decl    *funcDecl
+	closure *scope
+}
+
+type builtin func(args []value) (value, error)
+
+var errTooManySteps = errors.New("script exceeded the evaluation step limit")
+
+type scope struct {
+	vars   map[string]value
+	parent *scope
+}
+
+func newScope(parent *scope) *scope {
+	return &scope{vars: map[string]value{}, parent: parent}
+}
+
+func (s *scope) lookup(name string) (value, bool) {
+	for current := s; current != nil; current = current.parent {
+		if v, ok := current.vars[name]; ok {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// set updates the innermost scope declaring the variable, or defines it globally like sloppy mode scripts do
+func (s *scope) set(name string, v value) {
+	current := s
+	for {
+		if _, ok := current.vars[name]; ok || current.parent == nil {
+			current.vars[name] = v
+			return
+		}
+		current = current.parent
+	}
+}
+
+type interpreter struct {
+	steps int
+}
+
+func (in *interpreter) step() error {
+	in.steps++
+	if in.steps > maxSteps {
+		return errTooManySteps
+	}
+	return nil
+}
+
+// hoist declares the functions of a statement list before it runs
+func hoist(body []stmt, s *scope) {
+	for _, st := range body {
+		if f, ok := st.(*funcDecl); ok {
+			s.vars[f.name] = &function{decl: f, closure: s}
+		}
+	}
+}
+
+// execBlock runs statements and reports whether a return statement was hit
+func (in *interpreter) execBlock(body []stmt, s *scope) (value, bool, error) {
+	for _, st := range body {
+		if result, returned, err := in.exec(st, s); err != nil || returned {
+			return result, returned, err
+		}
+	}
+	return undefined{}, false, nil
+}
+
+func (in *interpreter) exec(st stmt, s *scope) (value, bool, error) {
+	if err := in.step(); err != nil {
+		return nil, false, err
+	}
+	switch st := st.(type) {
+	case *funcDecl:
+		// hoisted already
+		return undefined{}, false, nil
+	case *varDecl:
+		for i, name := range st.names {
+			var v value = undefined{}
+			if st.inits[i] != nil {
+				var err error
+				if v, err = in.eval(st.inits[i], s); err != nil {
+					return nil, false, err
+				}
+			}
+			s.vars[name] = v
+		}
+		return undefined{}, false, nil
+	case *ifStmt:
+		cond, err := in.eval(st.cond, s)
+		if err != nil {
+			return nil, false, err
+		}
+		if truthy(cond) {
+			return in.exec(st.then, s)
+		} else if st.els != nil {
+			return in.exec(st.els, s)
+		}
+		return undefined{}, false, nil
+	case *forStmt:
+		if st.init != nil {
+			if _, _, err := in.exec(st.init, s); err != nil {
+				return nil, false, err
+			}
+		}
+		for {
+			if st.cond != nil {
+				cond, err := in.eval(st.cond, s)
+				if err != nil {
+					return nil, false, err
+				}
+				if !truthy(cond) {
+					return undefined{}, false, nil
+				}
+			}
+			if result, returned, err := in.exec(st.body, s); err != nil || returned {
+				return result, returned, err
+			}
+			if st.post != nil {
+				if _, err := in.eval(st.post, s); err != nil {
+					return nil, false, err
+				}
+			}
+		}
+	case *returnStmt:
+		if st.value == nil {
+			return undefined{}, true, nil
+		}
+		result, err := in.eval(st.value, s)
+		return result, err == nil, err
+	case *blockStmt:
+		hoist(st.body, s)
+		return in.execBlock(st.body, s)
+	case *exprStmt:
+		_, err := in.eval(st.value, s)
+		return undefined{}, false, err
+	}
+	return nil, false, fmt.Errorf("unsupported statement %T", st)
+}
+
+func (in *interpreter) eval(e expr, s *scope) (value, error) {
+	if err := in.step(); err != nil {
+		return nil, err
+	}
+	switch e := e.(type) {
+	case *literal:
+		return e.value, nil
+	case *identifier:
+		v, ok := s.lookup(e.name)
+		if !ok {
+			return nil, fmt.Errorf("%s is not defined", e.name)
+		}
+		return v, nil
+	case *arrayLiteral:
+		elements := make([]value, 0, len(e.elements))
+		for _, element := range e.elements {
+			v, err := in.eval(element, s)
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, v)
+		}
+		return elements, nil
+	case *unaryExpr:
+		operand, err := in.eval(e.operand, s)
+		if err != nil {
+			return nil, err
+		}
+		switch e.op {
+		case "!":
+			return !truthy(operand), nil
+		case "-":
+			return -toNumber(operand), nil
+		case "+":
+			return toNumber(operand), nil
+		default:
+			return typeOf(operand), nil
+		}
+	case *binaryExpr:
+		return in.evalBinary(e, s)
+	case *conditionalExpr:
+		cond, err := in.eval(e.cond, s)
+		if err != nil {
+			return nil, err
+		}
+		if truthy(cond) {
+			return in.eval(e.then, s)
+		}
+		return in.eval(e.els, s)
+	case *assignExpr:
+		v, err := in.eval(e.value, s)
+		if err != nil {
+			return nil, err
+		}
+		if e.op != "=" {
+			current, ok := s.lookup(e.target)
+			if !ok {
+				return nil, fmt.Errorf("%s is not defined", e.target)
+			}
+			if e.op == "+=" {
+				v = add(current, v)
+			} else {
+				v = toNumber(current) - toNumber(v)
+			}
+		}
+		s.set(e.target, v)
+		return v, nil
+	case *updateExpr:
+		current, ok := s.lookup(e.target)
+		if !ok {
+			return nil, fmt.Errorf("%s is not defined", e.target)
+		}
+		old := toNumber(current)
+		s.set(e.target, old+e.delta)
+		if e.prefix {
+			return old + e.delta, nil
+		}
+		return old, nil
+	case *memberExpr:
+		object, err := in.eval(e.object, s)
+		if err != nil {
+			return nil, err
+		}
+		property, err := in.eval(e.property, s)
+		if err != nil {
+			return nil, err
+		}
+		return member(object, property)
+	case *callExpr:
+		callee, err := in.eval(e.callee, s)
+		if err != nil {
+			return nil, err
+		}
+		args := make([]value, 0, len(e.args))
+		for _, arg := range e.args {
+			v, err := in.eval(arg, s)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, v)
+		}
+		return in.call(callee, args)
+	}
+	return nil, fmt.Errorf("unsupported expression %T", e)
+}
+
+func (in *interpreter) evalBinary(e *binaryExpr, s *scope) (value, error) {
+	left, err := in.eval(e.left, s)
+	if err != nil {
+		return nil, err
+	}
+	// logical operators short circuit and return one of their operands
+	switch e.op {
+	case "&&":
+		if !truthy(left) {
+			return left, nil
+		}
+		return in.eval(e.right, s)
+	case "||":
+		if truthy(left) {
+			return left, nil
+		}
+		return in.eval(e.right, s)
+	}
+	right, err := in.eval(e.right, s)
+	if err != nil {
+		return nil, err
+	}
+	switch e.op {
+	case "==":
+		return looseEquals(left, right), nil
+	case "!=":
+		return !looseEquals(left, right), nil
+	case "===":
+		return strictEquals(left, right), nil
+	case "!==":
+		return !strictEquals(left, right), nil
+	case "<", ">", "<=", ">=":
+		return compare(e.op, left, right), nil
+	case "+":
+		return add(left, right), nil
+	case "-":
+		return toNumber(left) - toNumber(right), nil
+	case "*":
+		return toNumber(left) * toNumber(right), nil
+	case "/":
+		return toNumber(left) / toNumber(right), nil
+	case "%":
+		return math.Mod(toNumber(left), toNumber(right)), nil
+	}
+	return nil, fmt.Errorf("unsupported operator %s", e.op)
+}
+
+func (in *interpreter) call(callee value, args []value) (value, error) {
+	switch f := callee.(type) {
+	case builtin:
+		return f(args)
+	case *function:
+		s := newScope(f.closure)
+		for i, param := range f.decl.params {
+			if i < len(args) {
+				s.vars[param] = args[i]
+			} else {
+				s.vars[param] = undefined{}
+			}
+		}
+		hoist(f.decl.body, s)
+		result, _, err := in.execBlock(f.decl.body, s)
+		return result, err
+	}
+	return nil, fmt.Errorf("%s is not a function", toString(callee))
+}
+
+// member resolves the properties and methods of strings and arrays used by proxy scripts
+func member(object value, property value) (value, error) {
+	name := toString(property)
+	switch o := object.(type) {
+	case string:
+		return stringMember(o, property, name)
+	case []value:
+		switch name {
+		case "length":
+			return float64(len(o)), nil
+		case "indexOf":
+			return builtin(func(args []value) (value, error) {
+				for i, element := range o {
+					if strictEquals(element, arg(args, 0)) {
+						return float64(i), nil
+					}
+				}
+				return float64(-1), nil
+			}), nil
+		case "join":
+			return builtin(func(args []value) (value, error) {
+				separator := ","
+				if len(args) > 0 {
+					separator = toString(args[0])
+				}
+				parts := make([]string, len(o))
+				for i, element := range o {
+					parts[i] = toString(element)
+				}
+				return strings.Join(parts, separator), nil
+			}), nil
+		}
+		if index, ok := property.(float64); ok {
+			if index >= 0 && int(index) < len(o) {
+				return o[int(index)], nil
+			}
+			return undefined{}, nil
+		}
+	case nil, undefined:
+		return nil, fmt.Errorf("cannot read property %s of %s", name, toString(object))
+	}
+	return undefined{}, nil
+}
+
+func stringMember(str string, property value, name string) (value, error) {
+	switch name {
+	case "length":
+		return float64(len(str)), nil
+	case "toLowerCase":
+		return builtin(func(args []value) (value, error) { return strings.ToLower(str), nil }), nil
+	case "toUpperCase":
+		return builtin(func(args []value) (value, error) { return strings.ToUpper(str), nil }), nil
+	case "trim":
+		return builtin(func(args []value) (value, error) { return strings.TrimSpace(str), nil }), nil
+	case "indexOf":
+		return builtin(func(args []value) (value, error) {
+			return float64(strings.Index(str, toString(arg(args, 0)))), nil
+		}), nil
+	case "lastIndexOf":
+		return builtin(func(args []value) (value, error) {
+			return float64(strings.LastIndex(str, toString(arg(args, 0)))), nil
+		}), nil
+	case "startsWith":
+		return builtin(func(args []value) (value, error) {
+			return strings.HasPrefix(str, toString(arg(args, 0))), nil
+		}), nil
+	case "endsWith":
+		return builtin(func(args []value) (value, error) {
+			return strings.HasSuffix(str, toString(arg(args, 0))), nil
+		}), nil
+	case "charAt":
+		return builtin(func(args []value) (value, error) {
+			i := int(toNumber(arg(args, 0)))
+			if i < 0 || i >= len(str) {
+				return "", nil
+			}
+			return str[i : i+1], nil
+		}), nil
+	case "substring":
+		return builtin(func(args []value) (value, error) {
+			start := clamp(int(toNumber(arg(args, 0))), len(str))
+			end := len(str)
+			if len(args) > 1 {
+				end = clamp(int(toNumber(args[1])), len(str))
+			}
+			if start > end {
+				start, end = end, start
+			}
+			return str[start:end], nil
+		}), nil
+	case "substr":
+		return builtin(func(args []value) (value, error) {
+			start := int(toNumber(arg(args, 0)))
+			if start < 0 {
+				start += len(str)
+			}
+			start = clamp(start, len(str))
+			end := len(str)
+			if len(args) > 1 {
+				end = clamp(start+int(toNumber(args[1])), len(str))
+			}
+			if end < start {
+				return "", nil
+			}
+			return str[start:end], nil
+		}), nil
+	case "split":
+		return builtin(func(args []value) (value, error) {
+			parts := strings.Split(str, toString(arg(args, 0)))
+			result := make([]value, len(parts))
+			for i, part := range parts {
+				result[i] = part
+			}
+			return result, nil
+		}), nil
+	}
+	if index, ok := property.(float64); ok && index >= 0 && int(index) < len(str) {
+		return str[int(index) : int(index)+1], nil
+	}
+	return undefined{}, nil
+}
+
+func clamp(i int, max int) int {
+	if i < 0 {
+		return 0
+	}
+	if i > max {
+		return max
+	}
+	return i
+}
+
+// arg returns the i-th argument, or undefined when it was not passed
+func arg(args []value, i int) value {
+	if i < len(args) {
+		return args[i]
+	}
+	return undefined{}
+}
+
+func truthy(v value) bool {
+	switch v := v.(type) {
+	case bool:
+		return v
+	case float64:
+		return v != 0 && !math.IsNaN(v)
+	case string:
+		return v != ""
+	case nil, undefined:
+		return false
+	}
+	return true
+}
+
+func toNumber(v value) float64 {
+	switch v := v.(type) {
+	case float64:
+		return v
+	case bool:
+		if v {
+			return 1
+		}
+		return 0
+	case string:
+		trimmed := strings.TrimSpace(v)
+		if trimmed == "" {
+			return 0
+		}
+		if n, err := parseNumber(trimmed); err == nil {
+			return n
+		}
+		return math.NaN()
+	case nil:
+		return 0
+	}
+	return math.NaN()
+}
+
+func toString(v value) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		if math.IsNaN(v) {
+			return "NaN"
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case nil:
+		return "null"
+	case undefined:
+		return "undefined"
+	case []value:
+		parts := make([]string, len(v))
+		for i, element := range v {
+			parts[i] = toString(element)
+		}
+		return strings.Join(parts, ",")
+	}
+	return "function"
+}
+
+func typeOf(v value) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case undefined:
+		return "undefined"
+	case builtin, *function:
+		return "function"
+	}
+	return "object"
+}
+
+func add(left, right value) value {
+	_, leftString := left.(string)
+	_, rightString := right.(string)
+	if leftString || rightString {
+		return toString(left) + toString(right)
+	}
+	return toNumber(left) + toNumber(right)
+}
+
+func compare(op string, left, right value) bool {
+	leftString, leftOk := left.(string)
+	rightString, rightOk := right.(string)
+	var cmp int
+	if leftOk && rightOk {
+		cmp = strings.Compare(leftString, rightString)
+	} else {
+		l, r := toNumber(left), toNumber(right)
+		if math.IsNaN(l) || math.IsNaN(r) {
+			return false
+		}
+		switch {
+		case l < r:
+			cmp = -1
+		case l > r:
+			cmp = 1
+		}
+	}
+	switch op {
+	case "<":
+		return cmp < 0
+	case ">":
+		return cmp > 0
+	case "<=":
+		return cmp <= 0
+	}
+	return cmp >= 0
+}
+
+func strictEquals(left, right value) bool {
+	switch l := left.(type) {
+	case string, float64, bool, nil, undefined:
+		return typeOf(left) == typeOf(right) && left == right
+	case []value:
+		r, ok := right.([]value)
+		return ok && len(l) > 0 && len(r) > 0 && &l[0] == &r[0]
+	}
+	return false
+}
+
+func looseEquals(left, right value) bool {
+	if typeOf(left) == typeOf(right) {
+		return strictEquals(left, right)
+	}
+	leftNullish := left == nil || left == value(undefined{})
+	rightNullish := right == nil || right == value(undefined{})
+	if leftNullish || rightNullish {
+		return leftNullish && rightNullish
+	}
+	return toNumber(left) == toNumber(right)
+}
@@ -0,0 +1,97 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package pac evaluates proxy auto-config (PAC) scripts.
+//
+// Scripts are run by a small interpreter supporting the subset of JavaScript PAC files are written in:
+// functions, var declarations, if/else, for loops, string and array helpers and the standard PAC functions.
+package pac
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Script is a parsed proxy auto-config script
+type Script struct {
+	program []stmt
+}
+
+// Proxy is one entry of the FindProxyForURL result
+type Proxy struct {
+	// Direct is true when the request should not go through a proxy
+	Direct bool
+	// Scheme is http, or https for proxies reached over TLS
+	Scheme string
+	// Host is the host:port address of the proxy
+	Host string
+}
+
+// Parse parses the source of a proxy auto-config script
+func Parse(source string) (*Script, error) {
+	program, err := parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse proxy auto-config script: %v", err)
+	}
+	return &Script{program: program}, nil
+}
+
+// FindProxyForURL runs the FindProxyForURL function of the script and returns its raw result
+func (script *Script) FindProxyForURL(url string, host string) (string, error) {
+	global := newScope(nil)
+	for name, v := range globals() {
+		global.vars[name] = v
+	}
+	in := &interpreter{}
+	hoist(script.program, global)
+	if _, _, err := in.execBlock(script.program, global); err != nil {
+		return "", fmt.Errorf("failed to run proxy auto-config script: %v", err)
+	}
+	findProxy, ok := global.lookup("FindProxyForURL")
+	if !ok {
+		return "", fmt.Errorf("proxy auto-config script does not define FindProxyForURL")
+	}
+	result, err := in.call(findProxy, []value{url, host})
+	if err != nil {
+		return "", fmt.Errorf("failed to run FindProxyForURL: %v", err)
+	}
+	if _, ok := result.(string); !ok {
+		return "", fmt.Errorf("FindProxyForURL returned %s instead of a string", typeOf(result))
+	}
+	return result.(string), nil
+}
+
+// ParseResult parses a FindProxyForURL result such as "PROXY proxy:8080; DIRECT".
+// Entries the agent cannot use, like SOCKS proxies, are skipped.
+func ParseResult(result string) []Proxy {
+	var proxies []Proxy
+	for _, entry := range strings.Split(result, ";") {
+		fields := strings.Fields(entry)
+		if len(fields) == 0 {
+			continue
+		}
+		switch strings.ToUpper(fields[0]) {
+		case "DIRECT":
+			proxies = append(proxies, Proxy{Direct: true})
+		case "PROXY", "HTTP":
+			if len(fields) > 1 {
+				proxies = append(proxies, Proxy{Scheme: "http", Host: fields[1]})
+			}
+		case "HTTPS":
+			if len(fields) > 1 {
+				proxies = append(proxies, Proxy{Scheme: "https", Host: fields[1]})
+			}
+		}
+	}
+	return proxies
+}
@@ -0,0 +1,227 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package pac
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const enterpriseScript = `
+// Proxy auto-config used by the corporate network
+var internalDomains = [".corp.example.com", ".intranet.example.com"];
+
+function isInternal(host) {
+	for (var i = 0; i < internalDomains.length; i++) {
+		if (dnsDomainIs(host, internalDomains[i])) {
+			return true;
+		}
+	}
+	return false;
+}
+
+function FindProxyForURL(url, host) {
+	host = host.toLowerCase();
+	if (isPlainHostName(host) || isInternal(host)) return "DIRECT";
+	if (isInNet(dnsResolve(host), "10.0.0.0", "255.0.0.0")) {
+		return "DIRECT";
+	}
+	/* AWS endpoints go through the dedicated egress proxy */
+	if (shExpMatch(host, "*.amazonaws.com") && url.substring(0, 6) == "https:") {
+		return "PROXY egress.example.com:3128; DIRECT";
+	}
+	return myIpAddress() === "192.168.1.10" ? "PROXY lab.example.com:8080" : "PROXY proxy.example.com:8080";
+}
+`
+
+func setNetworkMocks(t *testing.T) {
+	origLookupIP, origInterfaceAddrs := lookupIP, interfaceAddrs
+	lookupIP = func(host string) ([]net.IP, error) {
+		switch host {
+		case "db.internal":
+			return []net.IP{net.ParseIP("10.1.2.3")}, nil
+		case "ssm.us-east-1.amazonaws.com":
+			return []net.IP{net.ParseIP("52.94.1.1")}, nil
+		}
+		return nil, fmt.Errorf("no such host")
+	}
+	interfaceAddrs = func() ([]net.Addr, error) {
+		return []net.Addr{
+			&net.IPNet{IP: net.ParseIP("127.0.0.1"), Mask: net.CIDRMask(8, 32)},
+			&net.IPNet{IP: net.ParseIP("172.16.0.5"), Mask: net.CIDRMask(16, 32)},
+		}, nil
+	}
+	t.Cleanup(func() {
+		lookupIP, interfaceAddrs = origLookupIP, origInterfaceAddrs
+	})
+}
+
+func TestFindProxyForURL(t *testing.T) {
+	setNetworkMocks(t)
+	script, err := Parse(enterpriseScript)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		url      string
+		host     string
+		expected string
+	}{
+		{"https://fileserver/", "fileserver", "DIRECT"},
+		{"https://wiki.corp.example.com/", "WIKI.corp.example.com", "DIRECT"},
+		{"https://db.internal/", "db.internal", "DIRECT"},
+		{"https://ssm.us-east-1.amazonaws.com/", "ssm.us-east-1.amazonaws.com", "PROXY egress.example.com:3128; DIRECT"},
+		{"http://ssm.us-east-1.amazonaws.com/", "ssm.us-east-1.amazonaws.com", "PROXY proxy.example.com:8080"},
+		{"https://example.org/", "example.org", "PROXY proxy.example.com:8080"},
+	}
+	for _, testCase := range testCases {
+		result, err := script.FindProxyForURL(testCase.url, testCase.host)
+		assert.NoError(t, err, testCase.host)
+		assert.Equal(t, testCase.expected, result, testCase.host)
+	}
+}
+
+func TestFindProxyForURLErrors(t *testing.T) {
+	setNetworkMocks(t)
+	testCases := map[string]string{
+		"function other() { return 'DIRECT'; }":                   "does not define FindProxyForURL",
+		"function FindProxyForURL(url, host) { return unknown; }": "unknown is not defined",
+		"function FindProxyForURL(url, host) { return 1; }":       "returned number",
+		"function FindProxyForURL(url, host) { for (;;) {} }":     "step limit",
+	}
+	for source, expected := range testCases {
+		script, err := Parse(source)
+		assert.NoError(t, err, source)
+		_, err = script.FindProxyForURL("https://example.org/", "example.org")
+		assert.ErrorContains(t, err, expected, source)
+	}
+
+	_, err := Parse("function FindProxyForURL(url, host) { return 'DIRECT'")
+	assert.ErrorContains(t, err, "line 1")
+	_, err = Parse("function FindProxyForURL(url, host) { return /re/.test(host); }")
+	assert.Error(t, err)
+}
+
+func TestParseErrors(t *testing.T) {
+	testCases := map[string]string{
+		"function FindProxyForURL(url, host) { return 'DIRECT'; } /* open":  "line 1: unterminated comment",
+		"function FindProxyForURL(url, host) {\n return \"DIRECT; }":        "line 2: unterminated string",
+		"function FindProxyForURL(url, host) {\n\n return 1.2.3; }":         "line 3: invalid number",
+		"function FindProxyForURL(url, host) { return host # 1; }":          `unexpected character '#'`,
+		"function FindProxyForURL(url, host) { return 'DIRECT'":             "found end of script",
+		"function FindProxyForURL(url, host) { if host return 'DIRECT'; }":  "line 1",
+		"function FindProxyForURL(url host) { return 'DIRECT'; }":           "line 1",
+		"function FindProxyForURL(url, host) { var = 1; return 'DIRECT'; }": "line 1",
+		"function FindProxyForURL(url, host) { return 'DIRECT';; } }":       "line 1",
+		"function FindProxyForURL(url, host) { return (host == 'a'; }":      "line 1",
+		"function FindProxyForURL(url, host) { return ['a', 'b'.length; }":  "line 1",
+		"function FindProxyForURL(url, host) { for (var i = 0; i < 1) {} }": "line 1",
+		"function FindProxyForURL(url, host) { return host ? 'DIRECT'; }":   "line 1",
+		"function FindProxyForURL(url, host) { return 'DIRECT'; }\n}":       "line 2",
+		"function (url, host) { return 'DIRECT'; }":                         "line 1",
+		"function FindProxyForURL(url, host) { return 'DIRECT'; }\nelse {}": "line 2",
+		"function FindProxyForURL(url, host) { return 'DIRECT'; }\n@":       "line 2: unexpected character '@'",
+		"function FindProxyForURL(url, host) { return host.; }":             "line 1",
+		"function FindProxyForURL(url, host) { return 'a' + ; }":            "line 1",
+		"function FindProxyForURL(url, host) { return dnsResolve(host,; }":  "line 1",
+	}
+	for source, expected := range testCases {
+		script, err := Parse(source)
+		assert.Nil(t, script, source)
+		assert.ErrorContains(t, err, "failed to parse proxy auto-config script", source)
+		assert.ErrorContains(t, err, expected, source)
+	}
+}
+
+func TestPACFunctions(t *testing.T) {
+	setNetworkMocks(t)
+	origTimeNow := timeNow
+	// Wednesday 14:30 UTC
+	timeNow = func() time.Time { return time.Date(2024, 5, 15, 14, 30, 0, 0, time.UTC) }
+	t.Cleanup(func() { timeNow = origTimeNow })
+
+	testCases := map[string]bool{
+		`isPlainHostName("fileserver")`:                                          true,
+		`isPlainHostName("fileserver.corp.example.com")`:                         false,
+		`dnsDomainIs("WWW.Example.com", ".example.com")`:                         true,
+		`dnsDomainIs("www.example.com", ".example.org")`:                         false,
+		`dnsDomainIs("www", ".example.com")`:                                     false,
+		`localHostOrDomainIs("www.example.com", "www.example.com")`:              true,
+		`localHostOrDomainIs("home", "www.example.com")`:                         false,
+		`localHostOrDomainIs("www", "www.example.com")`:                          true,
+		`localHostOrDomainIs("www.example.org", "www.example.com")`:              false,
+		`isResolvable("db.internal")`:                                            true,
+		`isResolvable("missing.example.com")`:                                    false,
+		`dnsDomainLevels("a.b.example.com") == 3`:                                true,
+		`shExpMatch("http://host/path?q", "http://*/path?q")`:                    true,
+		`shExpMatch("host.example.com", "*.example.org")`:                        false,
+		`shExpMatch("host1.example.com", "host?.example.com")`:                   true,
+		`shExpMatch("host10.example.com", "host?.example.com")`:                  false,
+		`shExpMatch("hostXexample.com", "host.example.com")`:                     false,
+		`shExpMatch("a+b(c)", "a+b(*)")`:                                         true,
+		`isInNet("db.internal", "10.0.0.0", "255.0.0.0")`:                        true,
+		`isInNet("10.1.2.3", "10.1.0.0", "255.255.0.0")`:                         true,
+		`isInNet("10.2.2.3", "10.1.0.0", "255.255.0.0")`:                         false,
+		`isInNet("ssm.us-east-1.amazonaws.com", "52.94.1.1", "255.255.255.255")`: true,
+		`isInNet("missing.example.com", "10.0.0.0", "255.0.0.0")`:                false,
+		`isInNet("10.1.2.3", "not an address", "255.0.0.0")`:                     false,
+		`dnsResolve("db.internal") == "10.1.2.3"`:                                true,
+		`dnsResolve("192.0.2.1") == "192.0.2.1"`:                                 true,
+		`convert_addr("not an address") == 0`:                                    true,
+		`dnsDomainLevels("www") == 0`:                                            true,
+		`weekdayRange("WED", "GMT")`:                                             true,
+		`weekdayRange("FRI", "TUE", "GMT")`:                                      false,
+		`weekdayRange("SAT", "WED", "GMT")`:                                      true,
+		`weekdayRange("NOTADAY")`:                                                false,
+		`timeRange(14, "GMT")`:                                                   true,
+		`timeRange(14, 0, 14, 29, "GMT")`:                                        false,
+		`timeRange(14, 30, 0, 14, 30, 59, "GMT")`:                                true,
+		`timeRange(13, 0, 14, 30, "GMT")`:                                        true,
+		`convert_addr("10.0.0.1") == 167772161`:                                  true,
+		`myIpAddress() == "172.16.0.5"`:                                          true,
+		`weekdayRange("MON", "FRI", "GMT")`:                                      true,
+		`weekdayRange("SAT", "SUN")`:                                             false,
+		`timeRange(9, 17, "GMT")`:                                                true,
+		`timeRange(22, 6, "GMT")`:                                                false,
+		`"a,b,c".split(",").length == 3 && "abc".indexOf("c") == 2`:              true,
+		`typeof dnsResolve("missing.example.com") == "object"`:                   true,
+		`"10" == 10 && "10" !== 10 && null == undefined && !(0 == null)`:         true,
+	}
+	for expression, expected := range testCases {
+		script, err := Parse("function FindProxyForURL(url, host) { return " + expression + " ? 'yes' : 'no'; }")
+		assert.NoError(t, err, expression)
+		result, err := script.FindProxyForURL("http://example.com/", "example.com")
+		assert.NoError(t, err, expression)
+		assert.Equal(t, expected, result == "yes", expression)
+	}
+}
+
+func TestDateRangeIsNotSupported(t *testing.T) {
+	script, err := Parse("function FindProxyForURL(url, host) { return dateRange('JAN', 'MAR') ? 'DIRECT' : 'PROXY p:8080'; }")
+	assert.NoError(t, err)
+	_, err = script.FindProxyForURL("http://example.com/", "example.com")
+	assert.ErrorContains(t, err, "dateRange is not supported")
+}
+
+func TestParseResult(t *testing.T) {
+	assert.Equal(t, []Proxy{
+		{Scheme: "http", Host: "proxy.example.com:8080"},
+		{Scheme: "https", Host: "secure.example.com:443"},
+		{Direct: true},
+	}, ParseResult("PROXY proxy.example.com:8080; SOCKS socks.example.com:1080;HTTPS secure.example.com:443 ; DIRECT"))
+	assert.Empty(t, ParseResult(""))
+}
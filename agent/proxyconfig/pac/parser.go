@@ -0,0 +1,717 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package pac
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenPunct
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	num  float64
+	line int
+}
+
+// punctuators are ordered longest first so the lexer picks the longest match
+var punctuators = []string{
+	"===", "!==",
+	"==", "!=", "<=", ">=", "&&", "||", "++", "--", "+=", "-=",
+	"{", "}", "(", ")", "[", "]", ";", ",", ".", "!", "=", "<", ">", "+", "-", "*", "/", "%", "?", ":",
+}
+
+// reservedWords cannot be used as variable names
+var reservedWords = map[string]bool{
+	"break": true, "case": true, "continue": true, "default": true, "do": true, "else": true, "for": true,
+	"function": true, "if": true, "in": true, "new": true, "return": true, "switch": true, "typeof": true,
+	"var": true, "let": true, "const": true, "while": true,
+}
+
+// tokenize splits the script source into tokens, skipping whitespace and comments
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	line := 1
+	i := 0
+	for i < len(source) {
+		c := source[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r' || c == '\f' || c == '\v':
+			i++
+		case strings.HasPrefix(source[i:], "//"):
+			for i < len(source) && source[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(source[i:], "/*"):
+			end := strings.Index(source[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated comment", line)
+			}
+			line += strings.Count(source[i:i+2+end], "\n")
+			i += end + 4
+		case isIdentStart(c):
+			start := i
+			for i < len(source) && isIdentPart(source[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: source[start:i], line: line})
+		case c >= '0' && c <= '9':
+			start := i
+			for i < len(source) && (isIdentPart(source[i]) || source[i] == '.') {
+				i++
+			}
+			num, err := parseNumber(source[start:i])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid number %q", line, source[start:i])
+			}
+			tokens = append(tokens, token{kind: tokenNumber, num: num, text: source[start:i], line: line})
+		case c == '"' || c == '\'':
+			text, n, err := readString(source[i:])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text, line: line})
+			i += n
+		default:
+			matched := false
+			for _, p := range punctuators {
+				if strings.HasPrefix(source[i:], p) {
+					tokens = append(tokens, token{kind: tokenPunct, text: p, line: line})
+					i += len(p)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("line %d: unexpected character %q", line, c)
+			}
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, line: line}), nil
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9')
+}
+
+func parseNumber(text string) (float64, error) {
+	if strings.HasPrefix(text, "0x") || strings.HasPrefix(text, "0X") {
+		n, err := strconv.ParseUint(text[2:], 16, 64)
+		return float64(n), err
+	}
+	return strconv.ParseFloat(text, 64)
+}
+
+// readString reads a quoted string literal and returns its value and the number of bytes consumed
+func readString(source string) (string, int, error) {
+	quote := source[0]
+	var sb strings.Builder
+	for i := 1; i < len(source); i++ {
+		c := source[i]
+		switch {
+		case c == quote:
+			return sb.String(), i + 1, nil
+		case c == '\n':
+			return "", 0, fmt.Errorf("unterminated string")
+		case c == '\\' && i+1 < len(source):
+			i++
+			switch source[i] {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			case 'r':
+				sb.WriteByte('\r')
+			case '0':
+				sb.WriteByte(0)
+			default:
+				sb.WriteByte(source[i])
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string")
+}
+
+type stmt interface{}
+type expr interface{}
+
+type funcDecl struct {
+	name   string
+	params []string
+	body   []stmt
+}
+
+type varDecl struct {
+	names []string
+	inits []expr
+}
+
+type ifStmt struct {
+	cond expr
+	then stmt
+	els  stmt
+}
+
+type forStmt struct {
+	init stmt
+	cond expr
+	post expr
+	body stmt
+}
+
+type returnStmt struct {
+	value expr
+}
+
+type blockStmt struct {
+	body []stmt
+}
+
+type exprStmt struct {
+	value expr
+}
+
+type literal struct {
+	value value
+}
+
+type identifier struct {
+	name string
+}
+
+type arrayLiteral struct {
+	elements []expr
+}
+
+type unaryExpr struct {
+	op      string
+	operand expr
+}
+
+type binaryExpr struct {
+	op          string
+	left, right expr
+}
+
+type conditionalExpr struct {
+	cond, then, els expr
+}
+
+type callExpr struct {
+	callee expr
+	args   []expr
+}
+
+type memberExpr struct {
+	object   expr
+	property expr
+}
+
+type assignExpr struct {
+	target string
+	op     string
+	value  expr
+}
+
+type updateExpr struct {
+	target string
+	delta  float64
+	prefix bool
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// parse builds the statement list of a script
+func parse(source string) ([]stmt, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	var program []stmt
+	for p.peek().kind != tokenEOF {
+		s, err := p.statement()
+		if err != nil {
+			return nil, err
+		}
+		program = append(program, s)
+	}
+	return program, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// is reports whether the next token is the given punctuator or keyword
+func (p *parser) is(text string) bool {
+	t := p.peek()
+	return (t.kind == tokenPunct || t.kind == tokenIdent) && t.text == text
+}
+
+func (p *parser) accept(text string) bool {
+	if p.is(text) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(text string) error {
+	if !p.accept(text) {
+		return p.unexpected(fmt.Sprintf("expected %q", text))
+	}
+	return nil
+}
+
+func (p *parser) unexpected(reason string) error {
+	t := p.peek()
+	if t.kind == tokenEOF {
+		return fmt.Errorf("line %d: %s, found end of script", t.line, reason)
+	}
+	return fmt.Errorf("line %d: %s, found %q", t.line, reason, t.text)
+}
+
+func (p *parser) identifier() (string, error) {
+	t := p.peek()
+	if t.kind != tokenIdent {
+		return "", p.unexpected("expected identifier")
+	}
+	p.pos++
+	return t.text, nil
+}
+
+func (p *parser) statement() (stmt, error) {
+	switch {
+	case p.accept(";"):
+		return &blockStmt{}, nil
+	case p.is("{"):
+		return p.block()
+	case p.accept("function"):
+		return p.function()
+	case p.is("var") || p.is("let") || p.is("const"):
+		p.next()
+		s, err := p.varDeclaration()
+		if err != nil {
+			return nil, err
+		}
+		p.accept(";")
+		return s, nil
+	case p.accept("if"):
+		return p.ifStatement()
+	case p.accept("for"):
+		return p.forStatement()
+	case p.accept("return"):
+		s := &returnStmt{}
+		if !p.is(";") && !p.is("}") && p.peek().kind != tokenEOF {
+			value, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			s.value = value
+		}
+		p.accept(";")
+		return s, nil
+	}
+	value, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	p.accept(";")
+	return &exprStmt{value: value}, nil
+}
+
+func (p *parser) block() (*blockStmt, error) {
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	b := &blockStmt{}
+	for !p.accept("}") {
+		if p.peek().kind == tokenEOF {
+			return nil, p.unexpected(`expected "}"`)
+		}
+		s, err := p.statement()
+		if err != nil {
+			return nil, err
+		}
+		b.body = append(b.body, s)
+	}
+	return b, nil
+}
+
+func (p *parser) function() (stmt, error) {
+	name, err := p.identifier()
+	if err != nil {
+		return nil, err
+	}
+	if err = p.expect("("); err != nil {
+		return nil, err
+	}
+	f := &funcDecl{name: name}
+	for !p.accept(")") {
+		if len(f.params) > 0 {
+			if err = p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		param, err := p.identifier()
+		if err != nil {
+			return nil, err
+		}
+		f.params = append(f.params, param)
+	}
+	body, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+	f.body = body.body
+	return f, nil
+}
+
+func (p *parser) varDeclaration() (stmt, error) {
+	d := &varDecl{}
+	for {
+		name, err := p.identifier()
+		if err != nil {
+			return nil, err
+		}
+		var init expr
+		if p.accept("=") {
+			if init, err = p.assignment(); err != nil {
+				return nil, err
+			}
+		}
+		d.names = append(d.names, name)
+		d.inits = append(d.inits, init)
+		if !p.accept(",") {
+			return d, nil
+		}
+	}
+}
+
+func (p *parser) ifStatement() (stmt, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	cond, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	if err = p.expect(")"); err != nil {
+		return nil, err
+	}
+	s := &ifStmt{cond: cond}
+	if s.then, err = p.statement(); err != nil {
+		return nil, err
+	}
+	if p.accept("else") {
+		if s.els, err = p.statement(); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func (p *parser) forStatement() (stmt, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	s := &forStmt{}
+	var err error
+	if p.is("var") || p.is("let") {
+		p.next()
+		if s.init, err = p.varDeclaration(); err != nil {
+			return nil, err
+		}
+	} else if !p.is(";") {
+		value, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		s.init = &exprStmt{value: value}
+	}
+	if err = p.expect(";"); err != nil {
+		return nil, err
+	}
+	if !p.is(";") {
+		if s.cond, err = p.expression(); err != nil {
+			return nil, err
+		}
+	}
+	if err = p.expect(";"); err != nil {
+		return nil, err
+	}
+	if !p.is(")") {
+		if s.post, err = p.expression(); err != nil {
+			return nil, err
+		}
+	}
+	if err = p.expect(")"); err != nil {
+		return nil, err
+	}
+	if s.body, err = p.statement(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (p *parser) expression() (expr, error) {
+	return p.assignment()
+}
+
+func (p *parser) assignment() (expr, error) {
+	if p.peek().kind == tokenIdent {
+		if op := p.tokens[p.pos+1]; op.kind == tokenPunct && (op.text == "=" || op.text == "+=" || op.text == "-=") {
+			target := p.next().text
+			p.next()
+			value, err := p.assignment()
+			if err != nil {
+				return nil, err
+			}
+			return &assignExpr{target: target, op: op.text, value: value}, nil
+		}
+	}
+	return p.conditional()
+}
+
+func (p *parser) conditional() (expr, error) {
+	cond, err := p.binary(0)
+	if err != nil {
+		return nil, err
+	}
+	if !p.accept("?") {
+		return cond, nil
+	}
+	then, err := p.assignment()
+	if err != nil {
+		return nil, err
+	}
+	if err = p.expect(":"); err != nil {
+		return nil, err
+	}
+	els, err := p.assignment()
+	if err != nil {
+		return nil, err
+	}
+	return &conditionalExpr{cond: cond, then: then, els: els}, nil
+}
+
+// binaryLevels lists binary operators from the lowest to the highest precedence
+var binaryLevels = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!=", "===", "!=="},
+	{"<", ">", "<=", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *parser) binary(level int) (expr, error) {
+	if level == len(binaryLevels) {
+		return p.unary()
+	}
+	left, err := p.binary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		t := p.peek()
+		matched := false
+		if t.kind == tokenPunct {
+			for _, op := range binaryLevels[level] {
+				if t.text == op {
+					matched = true
+					break
+				}
+			}
+		}
+		if !matched {
+			return left, nil
+		}
+		p.next()
+		right, err := p.binary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: t.text, left: left, right: right}
+	}
+}
+
+func (p *parser) unary() (expr, error) {
+	for _, op := range []string{"!", "-", "+", "typeof"} {
+		if p.accept(op) {
+			operand, err := p.unary()
+			if err != nil {
+				return nil, err
+			}
+			return &unaryExpr{op: op, operand: operand}, nil
+		}
+	}
+	for _, op := range []string{"++", "--"} {
+		if p.accept(op) {
+			target, err := p.identifier()
+			if err != nil {
+				return nil, err
+			}
+			return &updateExpr{target: target, delta: updateDelta(op), prefix: true}, nil
+		}
+	}
+	return p.postfix()
+}
+
+func updateDelta(op string) float64 {
+	if op == "--" {
+		return -1
+	}
+	return 1
+}
+
+func (p *parser) postfix() (expr, error) {
+	if p.peek().kind == tokenIdent {
+		if op := p.tokens[p.pos+1]; op.kind == tokenPunct && (op.text == "++" || op.text == "--") {
+			target := p.next().text
+			p.next()
+			return &updateExpr{target: target, delta: updateDelta(op.text)}, nil
+		}
+	}
+	e, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.accept("."):
+			name, err := p.identifier()
+			if err != nil {
+				return nil, err
+			}
+			e = &memberExpr{object: e, property: &literal{value: name}}
+		case p.accept("["):
+			property, err := p.expression()
+			if err != nil {
+				return nil, err
+			}
+			if err = p.expect("]"); err != nil {
+				return nil, err
+			}
+			e = &memberExpr{object: e, property: property}
+		case p.accept("("):
+			c := &callExpr{callee: e}
+			for !p.accept(")") {
+				if len(c.args) > 0 {
+					if err := p.expect(","); err != nil {
+						return nil, err
+					}
+				}
+				arg, err := p.assignment()
+				if err != nil {
+					return nil, err
+				}
+				c.args = append(c.args, arg)
+			}
+			e = c
+		default:
+			return e, nil
+		}
+	}
+}
+
+func (p *parser) primary() (expr, error) {
+	t := p.peek()
+	switch t.kind {
+	case tokenNumber:
+		p.next()
+		return &literal{value: t.num}, nil
+	case tokenString:
+		p.next()
+		return &literal{value: t.text}, nil
+	case tokenIdent:
+		if reservedWords[t.text] {
+			return nil, p.unexpected("unexpected keyword")
+		}
+		p.next()
+		switch t.text {
+		case "true":
+			return &literal{value: true}, nil
+		case "false":
+			return &literal{value: false}, nil
+		case "null":
+			return &literal{value: nil}, nil
+		case "undefined":
+			return &literal{value: undefined{}}, nil
+		}
+		return &identifier{name: t.text}, nil
+	}
+	switch {
+	case p.accept("("):
+		e, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		if err = p.expect(")"); err != nil {
+			return nil, err
+		}
+		return e, nil
+	case p.accept("["):
+		a := &arrayLiteral{}
+		for !p.accept("]") {
+			if len(a.elements) > 0 {
+				if err := p.expect(","); err != nil {
+					return nil, err
+				}
+				if p.accept("]") {
+					break
+				}
+			}
+			element, err := p.assignment()
+			if err != nil {
+				return nil, err
+			}
+			a.elements = append(a.elements, element)
+		}
+		return a, nil
+	}
+	return nil, p.unexpected("unsupported expression")
+}
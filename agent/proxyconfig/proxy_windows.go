@@ -135,7 +135,7 @@ func SetProxyConfig(log log.T) (proxySettings map[string]string) {
 		}
 
 		if len(ie.config) > 0 {
-			log.Debugf("IE option 'Use automatic configuration script' is not supported")
+			log.Debugf("IE option 'Use automatic configuration script' is not read, set Proxy.AutoConfigURL in amazon-ssm-agent.json to %v to use it", ie.config)
 		}
 	} else {
 		if df, err = GetDefaultProxySettings(log); len(df.proxy) > 0 && err == nil {
@@ -0,0 +1,207 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package reachability implements the core module probing the service endpoints the agent depends on
+package reachability
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/metrics"
+	"github.com/aws/amazon-ssm-agent/agent/network"
+)
+
+// ModuleName is the name of the network reachability prober core module
+const ModuleName = "NetworkReachabilityProber"
+
+// Services are the services the agent requires to be reachable
+var Services = []string{"ssm", "ssmmessages", "ec2messages", "s3", "kms"}
+
+// Result is the outcome of the last probe of a service endpoint
+type Result struct {
+	Service   string
+	Endpoint  string
+	Reachable bool
+	LatencyMs int64
+	// Error of the last probe, empty if the endpoint was reachable
+	Error               string
+	ConsecutiveFailures int
+	LastProbe           time.Time
+}
+
+var (
+	resultsMtx sync.RWMutex
+	results    = map[string]Result{}
+
+	probeEndpoint = headRequest
+	timeNow       = time.Now
+)
+
+// Latest returns the last probe result of every service sorted by service name, the result is empty
+// until the prober ran once
+func Latest() []Result {
+	resultsMtx.RLock()
+	defer resultsMtx.RUnlock()
+
+	latest := make([]Result, 0, len(results))
+	for _, result := range results {
+		latest = append(latest, result)
+	}
+	sort.Slice(latest, func(i, j int) bool { return latest[i].Service < latest[j].Service })
+	return latest
+}
+
+// Prober periodically probes the service endpoints through the configured proxy
+type Prober struct {
+	context context.T
+	config  appconfig.ReachabilityCfg
+	client  *http.Client
+
+	mtx      sync.Mutex
+	stopChan chan struct{}
+	doneChan chan struct{}
+}
+
+// NewProber returns a network reachability prober core module
+func NewProber(context context.T) *Prober {
+	return &Prober{
+		context: context.With("[" + ModuleName + "]"),
+		config:  context.AppConfig().Reachability,
+	}
+}
+
+// ModuleName returns the module name
+func (p *Prober) ModuleName() string {
+	return ModuleName
+}
+
+// ModuleExecute starts the probe loop
+func (p *Prober) ModuleExecute() (err error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if p.client == nil {
+		p.client = &http.Client{
+			Transport: network.GetDefaultTransport(p.context.Log(), p.context.AppConfig()),
+			Timeout:   time.Duration(p.config.ProbeTimeoutSeconds) * time.Second,
+			// a redirect still proves the endpoint is reachable
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		}
+	}
+	p.stopChan = make(chan struct{})
+	p.doneChan = make(chan struct{})
+	go p.probeLoop(time.Duration(p.config.ProbeIntervalSeconds)*time.Second, p.stopChan, p.doneChan)
+	return nil
+}
+
+// ModuleStop stops the probe loop
+func (p *Prober) ModuleStop() (err error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if p.stopChan != nil {
+		close(p.stopChan)
+		<-p.doneChan
+		p.stopChan = nil
+	}
+	return nil
+}
+
+func (p *Prober) probeLoop(interval time.Duration, stopChan chan struct{}, doneChan chan struct{}) {
+	log := p.context.Log()
+	defer func() {
+		if msg := recover(); msg != nil {
+			log.Errorf("Network reachability prober panic: %v", msg)
+			log.Errorf("Stacktrace:\n%s", debug.Stack())
+		}
+		close(doneChan)
+	}()
+
+	p.probe()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+			p.probe()
+		}
+	}
+}
+
+// probe probes the endpoint of every service and records the results
+func (p *Prober) probe() {
+	for _, service := range Services {
+		p.record(p.probeService(service))
+	}
+}
+
+func (p *Prober) probeService(service string) Result {
+	result := Result{Service: service, Endpoint: p.context.Identity().GetServiceEndpoint(service), LastProbe: timeNow()}
+	if result.Endpoint == "" {
+		result.Error = "endpoint could not be resolved"
+		return result
+	}
+
+	start := time.Now()
+	err := probeEndpoint(p.client, result.Endpoint)
+	result.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Reachable = true
+	return result
+}
+
+// record stores the result and logs the changes of the reachability of the service
+func (p *Prober) record(result Result) {
+	log := p.context.Log()
+	resultsMtx.Lock()
+	defer resultsMtx.Unlock()
+
+	previous, probed := results[result.Service]
+	if result.Reachable {
+		if probed && !previous.Reachable {
+			log.Infof("Endpoint %v of %v is reachable again after %v failed probes", result.Endpoint, result.Service, previous.ConsecutiveFailures)
+		}
+	} else {
+		result.ConsecutiveFailures = previous.ConsecutiveFailures + 1
+		metrics.ReachabilityProbeFailures.Inc(result.Service)
+		if !probed || previous.Reachable {
+			log.Warnf("Endpoint %v of %v is not reachable: %v", result.Endpoint, result.Service, result.Error)
+		}
+	}
+	results[result.Service] = result
+}
+
+// headRequest sends a HEAD request to the endpoint, any response proves the endpoint is reachable
+func headRequest(client *http.Client, endpoint string) error {
+	request, err := http.NewRequest(http.MethodHead, fmt.Sprintf("https://%v/", endpoint), nil)
+	if err != nil {
+		return err
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	return response.Body.Close()
+}
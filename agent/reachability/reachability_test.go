@@ -0,0 +1,96 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package reachability
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	"github.com/stretchr/testify/assert"
+)
+
+func setProbeEndpoint(t *testing.T, probe func(client *http.Client, endpoint string) error) {
+	probeEndpoint = probe
+	t.Cleanup(func() {
+		probeEndpoint = headRequest
+		resultsMtx.Lock()
+		results = map[string]Result{}
+		resultsMtx.Unlock()
+	})
+}
+
+func TestProbeRecordsResults(t *testing.T) {
+	setProbeEndpoint(t, func(client *http.Client, endpoint string) error {
+		if strings.HasPrefix(endpoint, "kms.") {
+			return fmt.Errorf("proxyconnect tcp: connection refused")
+		}
+		return nil
+	})
+	prober := NewProber(context.NewMockDefault())
+
+	prober.probe()
+	prober.probe()
+
+	latest := Latest()
+	assert.Len(t, latest, len(Services))
+	assert.Equal(t, "ec2messages", latest[0].Service)
+	for _, result := range latest {
+		assert.True(t, strings.HasPrefix(result.Endpoint, result.Service+"."))
+		if result.Service == "kms" {
+			assert.False(t, result.Reachable)
+			assert.Equal(t, 2, result.ConsecutiveFailures)
+			assert.Contains(t, result.Error, "connection refused")
+		} else {
+			assert.True(t, result.Reachable)
+			assert.Zero(t, result.ConsecutiveFailures)
+			assert.Empty(t, result.Error)
+		}
+	}
+}
+
+func TestProbeResetsFailuresOnRecovery(t *testing.T) {
+	reachable := false
+	setProbeEndpoint(t, func(client *http.Client, endpoint string) error {
+		if !reachable {
+			return fmt.Errorf("i/o timeout")
+		}
+		return nil
+	})
+	prober := NewProber(context.NewMockDefault())
+
+	prober.probe()
+	assert.Equal(t, 1, Latest()[0].ConsecutiveFailures)
+
+	reachable = true
+	prober.probe()
+	assert.True(t, Latest()[0].Reachable)
+	assert.Zero(t, Latest()[0].ConsecutiveFailures)
+}
+
+func TestHeadRequestAcceptsAnyResponse(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodHead, r.Method)
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	assert.NoError(t, headRequest(server.Client(), strings.TrimPrefix(server.URL, "https://")))
+
+	server.Close()
+	assert.Error(t, headRequest(server.Client(), strings.TrimPrefix(server.URL, "https://")))
+}
@@ -30,6 +30,7 @@ const (
 	submittedCommands = "testdata/new/submitted"
 	invalidCommands   = "testdata/new/invalid"
 	completeDir       = "testdata/new/completed"
+	placeholderFile   = "dummy"
 )
 
 func TestValid(t *testing.T) {
@@ -106,28 +107,26 @@ func SubmitTestDoc(name string) error {
 	}
 }
 
+// CleanTestDirs removes the files written by the tests, the placeholder keeping each directory in source control stays
 func CleanTestDirs() {
-	var files []string
-	files, _ = fileutil.GetFileNames(submittedCommands)
-	for _, file := range files {
-		fileutil.DeleteFile(filepath.Join(submittedCommands, file))
-	}
-	files, _ = fileutil.GetFileNames(invalidCommands)
-	for _, file := range files {
-		fileutil.DeleteFile(filepath.Join(invalidCommands, file))
-	}
-	files, _ = fileutil.GetFileNames(newCommands)
-	for _, file := range files {
-		fileutil.DeleteFile(filepath.Join(newCommands, file))
-	}
-	files, _ = fileutil.GetFileNames(completeDir)
-	for _, file := range files {
-		fileutil.DeleteFile(filepath.Join(completeDir, file))
+	for _, dir := range []string{submittedCommands, invalidCommands, newCommands, completeDir} {
+		files, _ := fileutil.GetFileNames(dir)
+		for _, file := range files {
+			if file != placeholderFile {
+				fileutil.DeleteFile(filepath.Join(dir, file))
+			}
+		}
 	}
 }
 
 func FileCount(path string) int {
 	var files []string
 	files, _ = fileutil.GetFileNames(path)
-	return len(files)
+	count := 0
+	for _, file := range files {
+		if file != placeholderFile {
+			count++
+		}
+	}
+	return count
 }
@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/clockskew"
 	"github.com/aws/amazon-ssm-agent/agent/context"
 	"github.com/aws/amazon-ssm-agent/agent/fileutil"
 	"github.com/aws/amazon-ssm-agent/agent/jsonutil"
@@ -102,7 +103,6 @@ func NewService(context context.T, connectionTimeout time.Duration) Service {
 
 	// capture Transport so we can use it to cancel requests
 	tr := &http.Transport{
-		Proxy: http.ProxyFromEnvironment,
 		Dial: (&net.Dialer{
 			Timeout:   connectionTimeout,
 			KeepAlive: 0,
@@ -110,12 +110,17 @@ func NewService(context context.T, connectionTimeout time.Duration) Service {
 		TLSHandshakeTimeout: 10 * time.Second,
 		TLSClientConfig:     network.GetDefaultTLSConfig(context.Log(), context.AppConfig()),
 	}
+	tr.Proxy = network.GetProxyFunc(context.Log(), context.AppConfig())
+	if dial := network.GetProxyDialer(context.Log(), context.AppConfig()); dial != nil {
+		tr.DialContext = dial
+	}
 	config.HTTPClient = &http.Client{Transport: tr, Timeout: connectionTimeout}
 
 	sess := session.New(config)
 	sess.Handlers.Build.PushBack(request.MakeAddToUserAgentHandler(agentConfig.Agent.Name, agentConfig.Agent.Version))
 
 	msgSvc := ssmmds.New(sess)
+	clockskew.AddHandlers(context.Log(), agentConfig, &msgSvc.Handlers)
 
 	//adding server based expected error messages
 	serverBasedErrorMessages = make([]string, 2)
@@ -0,0 +1,117 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package s3util
+
+import (
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/context"
+)
+
+const (
+	// discoveredRegionTTL is how long a discovered bucket region is reused by new sessions
+	discoveredRegionTTL = time.Hour
+	// undiscoveredRegionTTL is how long a bucket whose region could not be discovered falls back to the instance region
+	// without repeating the HEAD requests, shorter so a transient failure is retried soon
+	undiscoveredRegionTTL = 5 * time.Minute
+)
+
+var (
+	regionDiscoveryCacheInstance = newRegionDiscoveryCache(bucketRegionCacheItemCountMax)
+	timeNow                      = time.Now
+)
+
+// regionDiscoveryCache caches the outcome of the bucket region discovery for the whole process,
+// including the buckets whose region could not be discovered.
+// This is thread-safe.
+type regionDiscoveryCache struct {
+	entries    map[string]regionDiscoveryEntry
+	maxEntries int
+	mutex      sync.Mutex
+}
+
+type regionDiscoveryEntry struct {
+	region  string
+	expires time.Time
+}
+
+func newRegionDiscoveryCache(maxEntries int) *regionDiscoveryCache {
+	return &regionDiscoveryCache{
+		entries:    make(map[string]regionDiscoveryEntry),
+		maxEntries: maxEntries,
+	}
+}
+
+// Get returns the cached region of the bucket, an empty region means it could not be discovered
+func (c *regionDiscoveryCache) Get(key string) (region string, ok bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	if !timeNow().Before(entry.expires) {
+		delete(c.entries, key)
+		return "", false
+	}
+	return entry.region, true
+}
+
+// Put caches the discovered region of the bucket, an empty region is cached for a shorter time
+func (c *regionDiscoveryCache) Put(key, region string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	now := timeNow()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxEntries {
+		c.evict(now)
+	}
+	ttl := discoveredRegionTTL
+	if region == "" {
+		ttl = undiscoveredRegionTTL
+	}
+	c.entries[key] = regionDiscoveryEntry{region: region, expires: now.Add(ttl)}
+}
+
+// evict removes the expired entries, or the entry expiring first when none expired
+func (c *regionDiscoveryCache) evict(now time.Time) {
+	var firstKey string
+	var first time.Time
+	for key, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, key)
+			continue
+		}
+		if firstKey == "" || entry.expires.Before(first) {
+			firstKey, first = key, entry.expires
+		}
+	}
+	if len(c.entries) >= c.maxEntries {
+		delete(c.entries, firstKey)
+	}
+}
+
+// discoverBucketRegion returns the region of the bucket, reusing the outcome of a previous discovery
+// so documents downloading many objects do not repeat the HEAD requests for each of them
+func discoverBucketRegion(context context.T, instanceRegion, bucketName string, httpProvider HttpProvider) string {
+	// the outcome depends on the endpoints tried, which depend on the instance region and the configured endpoint
+	key := instanceRegion + "|" + context.AppConfig().S3.Endpoint + "|" + bucketName
+	if region, ok := regionDiscoveryCacheInstance.Get(key); ok {
+		context.Log().Debugf("using cached region %q for bucket %v", region, bucketName)
+		return region
+	}
+	region := getBucketRegion(context, instanceRegion, bucketName, httpProvider)
+	regionDiscoveryCacheInstance.Put(key, region)
+	return region
+}
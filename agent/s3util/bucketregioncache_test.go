@@ -0,0 +1,92 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package s3util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	contextmocks "github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	"github.com/stretchr/testify/assert"
+)
+
+func setTestRegionDiscoveryCache(t *testing.T, now *time.Time) {
+	regionDiscoveryCacheInstance = newRegionDiscoveryCache(2)
+	timeNow = func() time.Time { return *now }
+	t.Cleanup(func() {
+		regionDiscoveryCacheInstance = newRegionDiscoveryCache(bucketRegionCacheItemCountMax)
+		timeNow = time.Now
+	})
+}
+
+func TestDiscoverBucketRegion_CachesRegion(t *testing.T) {
+	now := time.Now()
+	setTestRegionDiscoveryCache(t, &now)
+	calls := 0
+	getBucketRegionFromSignedHeadBucketRequestFunc = func(context context.T, region, regionalEndpoint, bucketName string) string {
+		calls++
+		return "eu-west-1"
+	}
+	setS3Endpoint("us-east-1", "s3.us-east-1.amazonaws.com", nil)
+	ctx := contextmocks.NewMockDefault()
+
+	assert.Equal(t, "eu-west-1", discoverBucketRegion(ctx, "us-east-1", "bucket-1", &MockedHttpProvider{}))
+	assert.Equal(t, "eu-west-1", discoverBucketRegion(ctx, "us-east-1", "bucket-1", &MockedHttpProvider{}))
+	assert.Equal(t, 1, calls)
+
+	now = now.Add(discoveredRegionTTL)
+	assert.Equal(t, "eu-west-1", discoverBucketRegion(ctx, "us-east-1", "bucket-1", &MockedHttpProvider{}))
+	assert.Equal(t, 2, calls)
+}
+
+func TestDiscoverBucketRegion_CachesUndiscoveredRegion(t *testing.T) {
+	now := time.Now()
+	setTestRegionDiscoveryCache(t, &now)
+	calls := 0
+	getBucketRegionFromSignedHeadBucketRequestFunc = func(context context.T, region, regionalEndpoint, bucketName string) string {
+		calls++
+		return ""
+	}
+	setS3Endpoint("us-east-1", "", nil)
+	setS3FallbackEndpoint("us-east-1", "")
+	ctx := contextmocks.NewMockDefault()
+
+	assert.Equal(t, "", discoverBucketRegion(ctx, "us-east-1", "bucket-1", &MockedHttpProvider{}))
+	now = now.Add(undiscoveredRegionTTL - time.Second)
+	assert.Equal(t, "", discoverBucketRegion(ctx, "us-east-1", "bucket-1", &MockedHttpProvider{}))
+	assert.Equal(t, 1, calls)
+
+	now = now.Add(time.Second)
+	assert.Equal(t, "", discoverBucketRegion(ctx, "us-east-1", "bucket-1", &MockedHttpProvider{}))
+	assert.Equal(t, 2, calls)
+}
+
+func TestRegionDiscoveryCache_Eviction(t *testing.T) {
+	now := time.Now()
+	setTestRegionDiscoveryCache(t, &now)
+	cache := regionDiscoveryCacheInstance
+
+	cache.Put("a", "")
+	cache.Put("b", "us-east-1")
+	cache.Put("c", "us-west-2")
+	_, ok := cache.Get("a")
+	assert.False(t, ok, "the entry expiring first is evicted")
+	region, ok := cache.Get("b")
+	assert.True(t, ok)
+	assert.Equal(t, "us-east-1", region)
+	region, ok = cache.Get("c")
+	assert.True(t, ok)
+	assert.Equal(t, "us-west-2", region)
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/context"
 	"github.com/aws/amazon-ssm-agent/agent/log"
 	"github.com/aws/amazon-ssm-agent/agent/network"
+	"github.com/aws/amazon-ssm-agent/agent/sdkutil"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/endpoints"
 	"github.com/aws/aws-sdk-go/aws/request"
@@ -75,7 +76,7 @@ func GetS3CrossRegionCapableSession(context context.T, bucketName string) (*sess
 		return nil, err
 	}
 
-	guessedBucketRegion := getBucketRegion(context, initialRegion, bucketName, getHttpProvider(log, context.AppConfig()))
+	guessedBucketRegion := discoverBucketRegion(context, initialRegion, bucketName, getHttpProvider(log, context.AppConfig()))
 	if guessedBucketRegion != "" {
 		initialRegion = guessedBucketRegion
 	} else {
@@ -122,7 +123,7 @@ func getBucketRegionFromSignedHeadBucketRequest(context context.T, instanceRegio
 	var bucketRegion = ""
 	log := context.Log()
 
-	credentials := context.Identity().Credentials()
+	credentials := sdkutil.Credentials(context, "s3")
 	ctx := aws.BackgroundContext()
 
 	config := &aws.Config{
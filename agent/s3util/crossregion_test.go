@@ -271,6 +271,7 @@ func TestGetS3CrossRegionCapableSession_noRegionFromHead_withConfigOverrides(t *
 }
 
 func setupMocksForGetS3CrossRegionCapableSession(instanceRegion, bucketName, headBucketResponse string) {
+	regionDiscoveryCacheInstance = newRegionDiscoveryCache(bucketRegionCacheItemCountMax)
 	setBucketRegionFromSignedHeadBucketRequest("")
 	setupMockHeadBucketResponse(bucketName, instanceRegion, headBucketResponse)
 	makeAwsConfig = func(context context.T, service, region string) *aws.Config {
@@ -24,7 +24,10 @@ import (
 	"github.com/aws/amazon-ssm-agent/common/identity/endpoint"
 )
 
-const defaultGlobalEndpoint = "s3.amazonaws.com"
+const (
+	defaultGlobalEndpoint = "s3.amazonaws.com"
+	defaultGlobalRegion   = "us-east-1"
+)
 
 /*
 	This function returns the s3 endpoint specified by the user in appconfig.
@@ -68,6 +71,9 @@ func getFallbackS3Endpoint(context context.T, region string) (s3Endpoint string)
 		} else {
 			s3Endpoint, _ = GetS3Endpoint(context, "cn-north-1")
 		}
+	} else if endpoint.UseDualStack(context.AppConfig()) {
+		// the global endpoint is only reachable over IPv4
+		s3Endpoint, _ = GetS3Endpoint(context, defaultGlobalRegion)
 	} else {
 		s3Endpoint = defaultGlobalEndpoint
 	}
@@ -16,6 +16,7 @@ package s3util
 import (
 	"testing"
 
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
 	"github.com/aws/amazon-ssm-agent/agent/mocks/context"
 	"github.com/stretchr/testify/assert"
 )
@@ -41,3 +42,12 @@ func TestGetFallbackS3Endpoint(t *testing.T) {
 		assert.Equal(t, test.output, output, "The two urls should be the same")
 	}
 }
+
+func TestGetFallbackS3Endpoint_DualStack(t *testing.T) {
+	config := appconfig.DefaultConfig()
+	config.Agent.NetworkStack = appconfig.NetworkStackIPv6
+	ctx := context.NewMockDefaultWithConfig(config)
+
+	assert.Equal(t, "s3.dualstack.us-east-1.amazonaws.com", getFallbackS3Endpoint(ctx, "eu-west-1"))
+	assert.Equal(t, "s3.dualstack.us-gov-east-1.amazonaws.com", getFallbackS3Endpoint(ctx, "us-gov-west-1"))
+}
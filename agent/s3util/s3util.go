@@ -15,6 +15,8 @@
 package s3util
 
 import (
+	"compress/gzip"
+	"io"
 	"os"
 
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
@@ -47,6 +49,14 @@ type IAmazonS3Util interface {
 	IsBucketEncrypted(log log.T, bucketName string) (bool, error)
 }
 
+// UploadOptions changes how an object is stored by S3UploadWithOptions
+type UploadOptions struct {
+	// Compress gzip compresses the object before uploading it
+	Compress bool
+	// KmsKeyId encrypts the object with SSE-KMS using the given key instead of the bucket default encryption
+	KmsKeyId string
+}
+
 type AmazonS3Util struct {
 	myUploader *s3manager.Uploader
 }
@@ -86,7 +96,21 @@ func NewAmazonS3Util(context context.T, bucketName string) (res *AmazonS3Util, e
 
 // S3Upload uploads a file to s3.
 func (u *AmazonS3Util) S3Upload(log log.T, bucketName string, objectKey string, filePath string) (err error) {
-	file, err := os.Open(filePath)
+	return u.S3UploadWithOptions(log, bucketName, objectKey, filePath, UploadOptions{})
+}
+
+// S3UploadWithOptions uploads a file to s3, optionally gzip compressing it and encrypting it with a specific KMS key.
+func (u *AmazonS3Util) S3UploadWithOptions(log log.T, bucketName string, objectKey string, filePath string, options UploadOptions) (err error) {
+	uploadPath := filePath
+	if options.Compress {
+		if uploadPath, err = compressFile(filePath); err != nil {
+			log.Errorf("Failed to compress file %v: %v", filePath, err)
+			return err
+		}
+		defer os.Remove(uploadPath)
+	}
+
+	file, err := os.Open(uploadPath)
 	if err != nil {
 		log.Errorf("Failed to open file %v", err)
 		return err
@@ -102,7 +126,14 @@ func (u *AmazonS3Util) S3Upload(log log.T, bucketName string, objectKey string,
 		ACL:         aws.String("bucket-owner-full-control"),
 	}
 
-	if bucketEncrypted, sseAlgortihm, encryptionKey := getSSEAlgorithm(log, u, bucketName); bucketEncrypted == true {
+	if options.Compress {
+		params.ContentEncoding = aws.String("gzip")
+	}
+
+	if options.KmsKeyId != "" {
+		params.ServerSideEncryption = aws.String(s3.ServerSideEncryptionAwsKms)
+		params.SSEKMSKeyId = aws.String(options.KmsKeyId)
+	} else if bucketEncrypted, sseAlgortihm, encryptionKey := getSSEAlgorithm(log, u, bucketName); bucketEncrypted == true {
 		switch sseAlgortihm {
 		case s3.ServerSideEncryptionAes256:
 			params.ServerSideEncryption = aws.String(sseAlgortihm)
@@ -139,6 +170,37 @@ func (u *AmazonS3Util) S3Upload(log log.T, bucketName string, objectKey string,
 	return nil
 }
 
+// compressFile writes a gzip compressed copy of the file next to it and returns the path of the copy
+func compressFile(filePath string) (compressedPath string, err error) {
+	source, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer source.Close()
+
+	compressedPath = filePath + ".gz"
+	target, err := os.OpenFile(compressedPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, appconfig.ReadWriteAccess)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if closeErr := target.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(compressedPath)
+			compressedPath = ""
+		}
+	}()
+
+	writer := gzip.NewWriter(target)
+	if _, err = io.Copy(writer, source); err != nil {
+		return
+	}
+	err = writer.Close()
+	return
+}
+
 // IsBucketEncrypted checks if the bucket is encrypted
 func (u *AmazonS3Util) IsBucketEncrypted(log log.T, bucketName string) (bool, error) {
 	input := &s3.GetBucketEncryptionInput{
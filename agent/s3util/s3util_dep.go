@@ -37,7 +37,7 @@ type HttpProviderImpl struct {
 }
 
 var getHeadBucketTransportDelegate = func(log log.T, appConfig appconfig.SsmagentConfig) http.RoundTripper {
-	return network.GetDefaultTransport(log, appConfig)
+	return network.GetSharedTransport(log, appConfig)
 }
 
 func (p HttpProviderImpl) Head(url string) (resp *http.Response, err error) {
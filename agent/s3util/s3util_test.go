@@ -0,0 +1,48 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package s3util
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompressFile(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "stdout")
+	assert.NoError(t, os.WriteFile(filePath, []byte("command output\n"), 0600))
+
+	compressedPath, err := compressFile(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, filePath+".gz", compressedPath)
+
+	compressed, err := os.Open(compressedPath)
+	assert.NoError(t, err)
+	defer compressed.Close()
+	reader, err := gzip.NewReader(compressed)
+	assert.NoError(t, err)
+	content, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "command output\n", string(content))
+}
+
+func TestCompressFileMissingSource(t *testing.T) {
+	compressedPath, err := compressFile(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+	assert.Empty(t, compressedPath)
+}
@@ -30,14 +30,14 @@ import (
 func AwsConfig(context context.T, service string) (awsConfig *aws.Config) {
 	region, _ := context.Identity().Region()
 	endpoint := context.Identity().GetServiceEndpoint(service)
-	return AwsConfigForEndpoint(context, endpoint, region)
+	return AwsConfigForEndpoint(context, endpoint, region).WithCredentials(Credentials(context, service))
 }
 
 // AwsConfigForRegion returns the default aws.Config object with the appropriate
 // credentials and endpoint.
 func AwsConfigForRegion(context context.T, service, region string) (awsConfig *aws.Config) {
 	endpointHelper := endpoint.NewEndpointHelper(context.Log(), context.AppConfig())
-	return AwsConfigForEndpoint(context, endpointHelper.GetServiceEndpoint(service, region), region)
+	return AwsConfigForEndpoint(context, endpointHelper.GetServiceEndpoint(service, region), region).WithCredentials(Credentials(context, service))
 }
 
 // AwsConfigForEndpoint returns the default aws.Config object with the appropriate
@@ -50,7 +50,7 @@ func AwsConfigForEndpoint(context context.T, endpoint, region string) (awsConfig
 		Region:     aws.String(region),
 		Endpoint:   aws.String(endpoint),
 		HTTPClient: &http.Client{
-			Transport: network.GetDefaultTransport(context.Log(), context.AppConfig()),
+			Transport: network.GetSharedTransport(context.Log(), context.AppConfig()),
 		},
 		Credentials: context.Identity().Credentials(),
 	}
@@ -0,0 +1,105 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sdkutil
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/network"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+const resourceAccessSessionNamePrefix = "ssm-agent-"
+
+// resourceAccessServices are the services plugins access customer resources with,
+// their clients use the resource access role when one is configured
+var resourceAccessServices = map[string]bool{
+	"s3":   true,
+	"kms":  true,
+	"logs": true,
+}
+
+var (
+	resourceCredentialsLock sync.Mutex
+	// assumed role credentials are cached so the role is only assumed again when the credentials expire
+	resourceCredentials = map[string]*credentials.Credentials{}
+
+	newAssumeRoleCredentials = assumeRoleCredentials
+)
+
+// Credentials returns the credentials for clients of the service. Clients of resource access services use
+// the resource access role, assumed with the agent identity, when one is configured, all other clients
+// use the agent identity.
+func Credentials(context context.T, service string) *credentials.Credentials {
+	identityConfig := context.AppConfig().Identity
+	roleArn := strings.TrimSpace(identityConfig.ResourceAccessRoleArn)
+	if roleArn == "" || !resourceAccessServices[service] {
+		return context.Identity().Credentials()
+	}
+
+	resourceCredentialsLock.Lock()
+	defer resourceCredentialsLock.Unlock()
+
+	if creds, ok := resourceCredentials[roleArn]; ok {
+		return creds
+	}
+	context.Log().Infof("Using resource access role %v for %v", roleArn, service)
+	creds := newAssumeRoleCredentials(context, roleArn, identityConfig.ResourceAccessRoleExternalId)
+	resourceCredentials[roleArn] = creds
+	return creds
+}
+
+// assumeRoleCredentials returns credentials of the role assumed with the agent identity through the regional STS endpoint
+func assumeRoleCredentials(context context.T, roleArn, externalID string) *credentials.Credentials {
+	sess := newSTSSession(context)
+
+	instanceID, _ := context.Identity().InstanceID()
+	return stscreds.NewCredentials(sess, roleArn, func(provider *stscreds.AssumeRoleProvider) {
+		// the session name shows the instance in the CloudTrail events of the role
+		provider.RoleSessionName = resourceAccessSessionName(instanceID)
+		if externalID != "" {
+			provider.ExternalID = aws.String(externalID)
+		}
+	})
+}
+
+// newSTSSession returns a session for the regional STS endpoint with the agent identity
+func newSTSSession(context context.T) *session.Session {
+	region, _ := context.Identity().Region()
+	return session.Must(session.NewSession(&aws.Config{
+		Retryer:    newRetryer(),
+		SleepDelay: sleepDelay,
+		Region:     aws.String(region),
+		Endpoint:   aws.String(context.Identity().GetServiceEndpoint("sts")),
+		HTTPClient: &http.Client{
+			Transport: network.GetSharedTransport(context.Log(), context.AppConfig()),
+		},
+		Credentials: context.Identity().Credentials(),
+	}))
+}
+
+// resourceAccessSessionName returns a role session name within the 64 character limit of STS
+func resourceAccessSessionName(instanceID string) string {
+	name := resourceAccessSessionNamePrefix + instanceID
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
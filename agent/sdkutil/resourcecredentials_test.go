@@ -0,0 +1,73 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sdkutil
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	contextmocks "github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/stretchr/testify/assert"
+)
+
+func stubAssumeRoleCredentials(t *testing.T) (assumed *int) {
+	assumed = new(int)
+	newAssumeRoleCredentials = func(context context.T, roleArn, externalID string) *credentials.Credentials {
+		*assumed++
+		return credentials.NewStaticCredentials(roleArn, externalID, "")
+	}
+	resourceCredentials = map[string]*credentials.Credentials{}
+	t.Cleanup(func() {
+		newAssumeRoleCredentials = assumeRoleCredentials
+		resourceCredentials = map[string]*credentials.Credentials{}
+	})
+	return assumed
+}
+
+func TestCredentials_NoResourceAccessRole(t *testing.T) {
+	assumed := stubAssumeRoleCredentials(t)
+	ctx := contextmocks.NewMockDefault()
+
+	assert.Equal(t, ctx.Identity().Credentials(), Credentials(ctx, "s3"))
+	assert.Equal(t, 0, *assumed)
+}
+
+func TestCredentials_ResourceAccessRole(t *testing.T) {
+	assumed := stubAssumeRoleCredentials(t)
+	config := appconfig.SsmagentConfig{}
+	config.Identity.ResourceAccessRoleArn = "arn:aws:iam::123456789012:role/resources"
+	config.Identity.ResourceAccessRoleExternalId = "external"
+	ctx := contextmocks.NewMockDefaultWithConfig(config)
+
+	for _, service := range []string{"s3", "kms", "logs"} {
+		creds := Credentials(ctx, service)
+		value, err := creds.Get()
+		assert.NoError(t, err)
+		assert.Equal(t, "arn:aws:iam::123456789012:role/resources", value.AccessKeyID, service)
+		assert.Equal(t, "external", value.SecretAccessKey, service)
+	}
+	assert.Equal(t, 1, *assumed, "the role is assumed once and the credentials are shared")
+
+	// control plane services keep the agent identity
+	assert.Equal(t, ctx.Identity().Credentials(), Credentials(ctx, "ssm"))
+	assert.Equal(t, ctx.Identity().Credentials(), Credentials(ctx, "ec2messages"))
+}
+
+func TestResourceAccessSessionName(t *testing.T) {
+	assert.Equal(t, "ssm-agent-i-1234567890abcdef0", resourceAccessSessionName("i-1234567890abcdef0"))
+	assert.Len(t, resourceAccessSessionName(strings.Repeat("a", 100)), 64)
+}
@@ -0,0 +1,110 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sdkutil
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sts"
+)
+
+// Session tags of the scoped credentials, role policies match them with aws:PrincipalTag conditions
+const (
+	DocumentNameSessionTag = "SSMDocumentName"
+	CommandIDSessionTag    = "SSMCommandId"
+	InstanceIDSessionTag   = "SSMInstanceId"
+)
+
+const (
+	scopedCredentialsSessionNamePrefix = "ssm-document-"
+	maxRoleSessionNameLength           = 64
+	maxSessionTagValueLength           = 256
+)
+
+var (
+	// characters not allowed in role session names and session tag values
+	invalidRoleSessionNameCharacters = regexp.MustCompile(`[^\w+=,.@-]`)
+	invalidSessionTagValueCharacters = regexp.MustCompile(`[^\p{L}\p{Z}\p{N}_.:/=+\-@]`)
+
+	assumeScopedRole = func(context context.T, input *sts.AssumeRoleInput) (*sts.Credentials, error) {
+		output, err := sts.New(newSTSSession(context)).AssumeRole(input)
+		if err != nil {
+			return nil, err
+		}
+		return output.Credentials, nil
+	}
+)
+
+// ScopedCredentialsRequest describes the document step scoped credentials are vended to
+type ScopedCredentialsRequest struct {
+	DocumentName string
+	CommandID    string
+	// Policy is an optional session policy, the credentials only get the permissions allowed by both the
+	// role policies and the session policy
+	Policy string
+}
+
+// ScopedCredentials assumes the scoped credentials role configured in appconfig for a document step. The role session
+// is tagged with the document name, command id and instance id, so the permissions of a document can be granted by
+// the role policies instead of every script inheriting the permissions of the agent identity.
+func ScopedCredentials(context context.T, request ScopedCredentialsRequest) (*sts.Credentials, error) {
+	identityConfig := context.AppConfig().Identity
+	if identityConfig.ScopedCredentialsRoleArn == "" {
+		return nil, errors.New("scoped credentials are not enabled on this instance, Identity.ScopedCredentialsRoleArn is not configured")
+	}
+
+	instanceID, _ := context.Identity().InstanceID()
+	input := &sts.AssumeRoleInput{
+		RoleArn:         aws.String(identityConfig.ScopedCredentialsRoleArn),
+		RoleSessionName: aws.String(scopedCredentialsSessionName(request.CommandID)),
+		DurationSeconds: aws.Int64(int64(identityConfig.ScopedCredentialsDurationSeconds)),
+		Tags: []*sts.Tag{
+			{Key: aws.String(DocumentNameSessionTag), Value: aws.String(sessionTagValue(request.DocumentName))},
+			{Key: aws.String(CommandIDSessionTag), Value: aws.String(sessionTagValue(request.CommandID))},
+			{Key: aws.String(InstanceIDSessionTag), Value: aws.String(sessionTagValue(instanceID))},
+		},
+	}
+	if request.Policy != "" {
+		input.Policy = aws.String(request.Policy)
+	}
+
+	context.Log().Infof("Assuming scoped credentials role %v for document %v", identityConfig.ScopedCredentialsRoleArn, request.DocumentName)
+	creds, err := assumeScopedRole(context, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to assume scoped credentials role %v: %v", identityConfig.ScopedCredentialsRoleArn, err)
+	}
+	return creds, nil
+}
+
+// scopedCredentialsSessionName returns a role session name showing the command in the CloudTrail events of the role
+func scopedCredentialsSessionName(commandID string) string {
+	name := invalidRoleSessionNameCharacters.ReplaceAllString(scopedCredentialsSessionNamePrefix+commandID, "-")
+	if len(name) > maxRoleSessionNameLength {
+		name = name[:maxRoleSessionNameLength]
+	}
+	return name
+}
+
+// sessionTagValue replaces the characters STS does not allow in session tag values
+func sessionTagValue(value string) string {
+	value = invalidSessionTagValueCharacters.ReplaceAllString(value, "_")
+	if len(value) > maxSessionTagValueLength {
+		value = value[:maxSessionTagValueLength]
+	}
+	return value
+}
@@ -0,0 +1,100 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package sdkutil
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	contextmocks "github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/stretchr/testify/assert"
+)
+
+func stubAssumeScopedRole(t *testing.T, err error) (inputs *[]*sts.AssumeRoleInput) {
+	inputs = &[]*sts.AssumeRoleInput{}
+	assumeScopedRoleOrig := assumeScopedRole
+	assumeScopedRole = func(context context.T, input *sts.AssumeRoleInput) (*sts.Credentials, error) {
+		*inputs = append(*inputs, input)
+		if err != nil {
+			return nil, err
+		}
+		return &sts.Credentials{AccessKeyId: aws.String("AKID"), SecretAccessKey: aws.String("secret"), SessionToken: aws.String("token")}, nil
+	}
+	t.Cleanup(func() { assumeScopedRole = assumeScopedRoleOrig })
+	return inputs
+}
+
+func scopedCredentialsContext() context.T {
+	config := appconfig.SsmagentConfig{}
+	config.Identity.ScopedCredentialsRoleArn = "arn:aws:iam::123456789012:role/documents"
+	config.Identity.ScopedCredentialsDurationSeconds = 900
+	return contextmocks.NewMockDefaultWithConfig(config)
+}
+
+func TestScopedCredentials(t *testing.T) {
+	inputs := stubAssumeScopedRole(t, nil)
+	ctx := scopedCredentialsContext()
+	instanceID, _ := ctx.Identity().InstanceID()
+
+	creds, err := ScopedCredentials(ctx, ScopedCredentialsRequest{
+		DocumentName: "arn:aws:ssm:us-east-1:123456789012:document/Deploy App",
+		CommandID:    "0e5a8c6d-2e15-4a28-9b2e-2f1a3d1c9a10",
+		Policy:       `{"Version":"2012-10-17","Statement":[]}`,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "AKID", *creds.AccessKeyId)
+
+	assert.Len(t, *inputs, 1)
+	input := (*inputs)[0]
+	assert.Equal(t, "arn:aws:iam::123456789012:role/documents", *input.RoleArn)
+	assert.Equal(t, "ssm-document-0e5a8c6d-2e15-4a28-9b2e-2f1a3d1c9a10", *input.RoleSessionName)
+	assert.Equal(t, int64(900), *input.DurationSeconds)
+	assert.Equal(t, `{"Version":"2012-10-17","Statement":[]}`, *input.Policy)
+	assert.Equal(t, []*sts.Tag{
+		{Key: aws.String(DocumentNameSessionTag), Value: aws.String("arn:aws:ssm:us-east-1:123456789012:document/Deploy App")},
+		{Key: aws.String(CommandIDSessionTag), Value: aws.String("0e5a8c6d-2e15-4a28-9b2e-2f1a3d1c9a10")},
+		{Key: aws.String(InstanceIDSessionTag), Value: aws.String(instanceID)},
+	}, input.Tags)
+}
+
+func TestScopedCredentials_NotConfigured(t *testing.T) {
+	inputs := stubAssumeScopedRole(t, nil)
+
+	_, err := ScopedCredentials(contextmocks.NewMockDefault(), ScopedCredentialsRequest{DocumentName: "AWS-RunShellScript", CommandID: "command"})
+	assert.Error(t, err)
+	assert.Empty(t, *inputs)
+}
+
+func TestScopedCredentials_AssumeRoleFails(t *testing.T) {
+	inputs := stubAssumeScopedRole(t, fmt.Errorf("AccessDenied"))
+
+	_, err := ScopedCredentials(scopedCredentialsContext(), ScopedCredentialsRequest{DocumentName: "AWS-RunShellScript", CommandID: "command"})
+	assert.EqualError(t, err, "failed to assume scoped credentials role arn:aws:iam::123456789012:role/documents: AccessDenied")
+	assert.Nil(t, (*inputs)[0].Policy)
+}
+
+func TestScopedCredentialsSessionName(t *testing.T) {
+	assert.Equal(t, "ssm-document-command-1", scopedCredentialsSessionName("command 1"))
+	assert.Len(t, scopedCredentialsSessionName(strings.Repeat("a", 100)), 64)
+}
+
+func TestSessionTagValue(t *testing.T) {
+	assert.Equal(t, "my-doc_v1_", sessionTagValue("my-doc$v1*"))
+	assert.Len(t, sessionTagValue(strings.Repeat("a", 300)), 256)
+}
@@ -0,0 +1,104 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package servicerecovery configures the agent service to restart after failures and tracks the unexpected exits of the agent
+package servicerecovery
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/fileutil"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+)
+
+const (
+	// RepeatedFailureThreshold is the number of unexpected exits within the failure window reported as repeated failures
+	RepeatedFailureThreshold = 3
+
+	failureWindow = time.Hour
+	stateFileName = "servicerecovery.json"
+)
+
+// state is persisted across agent runs, Running is still set at startup when the previous run did not stop cleanly
+type state struct {
+	Running  bool
+	Failures []time.Time
+}
+
+var (
+	statePath = func() string {
+		return filepath.Join(appconfig.DefaultDataStorePath, stateFileName)
+	}
+	timeNow = time.Now
+)
+
+// RecordStart records that the agent service started and returns the number of unexpected exits within the failure window,
+// the previous run exited unexpectedly when it did not record its stop
+func RecordStart(log log.T) int {
+	current := readState(log)
+	now := timeNow()
+	if current.Running {
+		log.Warnf("Agent service did not stop cleanly during its previous run")
+		current.Failures = append(current.Failures, now)
+	}
+
+	failures := make([]time.Time, 0, len(current.Failures))
+	for _, failure := range current.Failures {
+		if now.Sub(failure) <= failureWindow {
+			failures = append(failures, failure)
+		}
+	}
+	current.Failures = failures
+	current.Running = true
+	writeState(log, current)
+	return len(current.Failures)
+}
+
+// RecordStop records that the agent service stopped cleanly
+func RecordStop(log log.T) {
+	current := readState(log)
+	current.Running = false
+	writeState(log, current)
+}
+
+func readState(log log.T) (current state) {
+	path := statePath()
+	if !fileutil.Exists(path) {
+		return
+	}
+	content, err := fileutil.ReadAllText(path)
+	if err == nil {
+		err = json.Unmarshal([]byte(content), &current)
+	}
+	if err != nil {
+		log.Warnf("Failed to read service recovery state %v: %v", path, err)
+		return state{}
+	}
+	return
+}
+
+func writeState(log log.T, current state) {
+	path := statePath()
+	content, err := json.Marshal(current)
+	if err == nil {
+		if err = fileutil.MakeDirs(filepath.Dir(path)); err == nil {
+			err = fileutil.WriteAllText(path, string(content))
+		}
+	}
+	if err != nil {
+		log.Warnf("Failed to write service recovery state %v: %v", path, err)
+	}
+}
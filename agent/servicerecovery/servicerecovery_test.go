@@ -0,0 +1,66 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package servicerecovery
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	logmocks "github.com/aws/amazon-ssm-agent/agent/mocks/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupStatePath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), stateFileName)
+	originalStatePath, originalTimeNow := statePath, timeNow
+	statePath = func() string { return path }
+	t.Cleanup(func() {
+		statePath, timeNow = originalStatePath, originalTimeNow
+	})
+}
+
+func TestRecordStartAfterCleanStop(t *testing.T) {
+	setupStatePath(t)
+	log := logmocks.NewMockLog()
+
+	assert.Equal(t, 0, RecordStart(log))
+	RecordStop(log)
+	assert.Equal(t, 0, RecordStart(log))
+}
+
+func TestRecordStartCountsUnexpectedExits(t *testing.T) {
+	setupStatePath(t)
+	log := logmocks.NewMockLog()
+
+	assert.Equal(t, 0, RecordStart(log))
+	assert.Equal(t, 1, RecordStart(log))
+	assert.Equal(t, 2, RecordStart(log))
+	RecordStop(log)
+	assert.Equal(t, 2, RecordStart(log))
+}
+
+func TestRecordStartForgetsFailuresOutsideWindow(t *testing.T) {
+	setupStatePath(t)
+	log := logmocks.NewMockLog()
+	now := time.Now()
+	timeNow = func() time.Time { return now }
+
+	RecordStart(log)
+	RecordStart(log)
+	assert.Equal(t, 2, RecordStart(log))
+
+	now = now.Add(failureWindow + time.Minute)
+	assert.Equal(t, 1, RecordStart(log))
+}
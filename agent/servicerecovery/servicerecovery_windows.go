@@ -0,0 +1,118 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build windows
+// +build windows
+
+package servicerecovery
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+const (
+	// the failure count of the service manager is reset after a day without failures
+	resetPeriodSeconds = 24 * 60 * 60
+
+	repeatedFailuresEventID = 1001
+)
+
+// recoveryActions restart the service with an increasing delay, the last action is repeated for subsequent failures
+var recoveryActions = []mgr.RecoveryAction{
+	{Type: mgr.ServiceRestart, Delay: 10 * time.Second},
+	{Type: mgr.ServiceRestart, Delay: time.Minute},
+	{Type: mgr.ServiceRestart, Delay: 5 * time.Minute},
+}
+
+// ConfigureRecoveryActions makes the service manager restart the service when it crashes or exits with an error,
+// the actions are only updated when they differ from the expected ones
+func ConfigureRecoveryActions(serviceName string) error {
+	manager, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("failed to connect to service manager: %v", err)
+	}
+	defer manager.Disconnect()
+
+	service, err := manager.OpenService(serviceName)
+	if err != nil {
+		return fmt.Errorf("failed to open service %v: %v", serviceName, err)
+	}
+	defer service.Close()
+
+	current, err := service.RecoveryActions()
+	if err != nil {
+		return fmt.Errorf("failed to read recovery actions: %v", err)
+	}
+	resetPeriod, err := service.ResetPeriod()
+	if err != nil {
+		return fmt.Errorf("failed to read recovery reset period: %v", err)
+	}
+	if !isExpectedRecovery(current, resetPeriod) {
+		if err = service.SetRecoveryActions(recoveryActions, resetPeriodSeconds); err != nil {
+			return fmt.Errorf("failed to set recovery actions: %v", err)
+		}
+	}
+
+	onNonCrashFailures, err := service.RecoveryActionsOnNonCrashFailures()
+	if err == nil && !onNonCrashFailures {
+		// the agent exits with an error code instead of crashing on most failures
+		err = service.SetRecoveryActionsOnNonCrashFailures(true)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to apply recovery actions to non crash failures: %v", err)
+	}
+	return nil
+}
+
+func isExpectedRecovery(current []mgr.RecoveryAction, resetPeriod uint32) bool {
+	if resetPeriod != resetPeriodSeconds || len(current) != len(recoveryActions) {
+		return false
+	}
+	for i, action := range current {
+		if action.Type != recoveryActions[i].Type || action.Delay != recoveryActions[i].Delay {
+			return false
+		}
+	}
+	return true
+}
+
+// ReportRepeatedFailures writes a warning to the Application event log, so monitoring of the host notices a crashing agent
+func ReportRepeatedFailures(log log.T, serviceName string, failures int) {
+	message := fmt.Sprintf("%v stopped unexpectedly %v times within the last %v and was restarted by the service manager.", serviceName, failures, failureWindow)
+	log.Warn(message)
+
+	sourceName, err := windows.UTF16PtrFromString(serviceName)
+	if err != nil {
+		return
+	}
+	handle, err := windows.RegisterEventSource(nil, sourceName)
+	if err != nil {
+		log.Warnf("Failed to open the event log: %v", err)
+		return
+	}
+	defer windows.DeregisterEventSource(handle)
+
+	messagePtr, err := windows.UTF16PtrFromString(message)
+	if err != nil {
+		return
+	}
+	strings := []*uint16{messagePtr}
+	if err = windows.ReportEvent(handle, windows.EVENTLOG_WARNING_TYPE, 0, repeatedFailuresEventID, 0, 1, 0, &strings[0], nil); err != nil {
+		log.Warnf("Failed to write repeated failures event: %v", err)
+	}
+}
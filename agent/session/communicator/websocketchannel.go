@@ -23,8 +23,10 @@ import (
 	"path"
 	"runtime/debug"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/aws/amazon-ssm-agent/agent/clockskew"
 	"github.com/aws/amazon-ssm-agent/agent/context"
 	"github.com/aws/amazon-ssm-agent/agent/log"
 	"github.com/aws/amazon-ssm-agent/agent/session/communicator/websocketutil"
@@ -58,6 +60,7 @@ type IWebSocketChannel interface {
 type WebSocketChannel struct {
 	OnMessage    func([]byte)
 	OnError      func(error)
+	OnPong       func(rtt time.Duration)
 	Context      context.T
 	ChannelToken string
 	Connection   *websocket.Conn
@@ -68,6 +71,7 @@ type WebSocketChannel struct {
 	IsOpen       bool
 	writeLock    *sync.Mutex
 	stopPinging  chan bool
+	lastPingNano atomic.Int64
 }
 
 // Initialize a WebSocketChannel object.
@@ -132,7 +136,7 @@ func (webSocketChannel *WebSocketChannel) getV4SignatureHeader(log log.T, Url st
 	request, err := http.NewRequest("GET", Url, nil)
 
 	if webSocketChannel.Signer != nil {
-		_, err = webSocketChannel.Signer.Sign(request, nil, mgsconfig.ServiceName, webSocketChannel.Region, time.Now())
+		_, err = webSocketChannel.Signer.Sign(request, nil, mgsconfig.ServiceName, webSocketChannel.Region, clockskew.SigningTime(webSocketChannel.Context.AppConfig().ClockSkew))
 	}
 	return request.Header, err
 }
@@ -185,6 +189,9 @@ func (webSocketChannel *WebSocketChannel) Open(log log.T, dialer *websocket.Dial
 		webSocketChannel.Connection.SetPongHandler(func(string) error {
 			webSocketChannel.Connection.SetReadDeadline(time.Now().Add(mgsconfig.WebSocketPongWaitTimeout))
 			log.Debug("WebsocketChannel: received pong, extend timeout to be ", time.Now().Add(mgsconfig.WebSocketPongWaitTimeout))
+			if sent := webSocketChannel.lastPingNano.Load(); sent != 0 && webSocketChannel.OnPong != nil {
+				webSocketChannel.OnPong(time.Since(time.Unix(0, sent)))
+			}
 			return nil
 		})
 		for {
@@ -249,6 +256,7 @@ func (webSocketChannel *WebSocketChannel) StartPings(log log.T, pingInterval tim
 
 			case <-ticker.C:
 				log.Debug("WebsocketChannel: Send ping. Message.")
+				webSocketChannel.lastPingNano.Store(time.Now().UnixNano())
 				err := webSocketChannel.SendMessage(log, []byte("keepalive"), websocket.PingMessage)
 				if err != nil {
 					log.Warnf("Error while sending websocket ping: %v", err)
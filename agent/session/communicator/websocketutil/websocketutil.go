@@ -16,7 +16,9 @@ package websocketutil
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
 	"github.com/aws/amazon-ssm-agent/agent/log"
@@ -32,8 +34,9 @@ type IWebsocketUtil interface {
 
 // WebsocketUtil struct provides functionality around creating and maintaining websockets.
 type WebsocketUtil struct {
-	dialer *websocket.Dialer
-	log    log.T
+	dialer           *websocket.Dialer
+	log              log.T
+	compressionLevel int
 }
 
 // NewWebsocketUtil is the factory function for websocketutil.
@@ -44,7 +47,11 @@ func NewWebsocketUtil(logger log.T, appConfig appconfig.SsmagentConfig, dialerIn
 	if dialerInput == nil {
 		d := &websocket.Dialer{
 			TLSClientConfig: network.GetDefaultTLSConfig(logger, appConfig),
-			Proxy:           http.ProxyFromEnvironment,
+			Proxy:           network.GetProxyFunc(logger, appConfig),
+			NetDialContext:  network.GetProxyDialer(logger, appConfig),
+			// permessage-deflate is offered in the handshake and only used when the service accepts it,
+			// a dialer passed in by the caller keeps its own setting
+			EnableCompression: appConfig.Mgs.WebSocketCompression,
 		}
 		websocketUtil = &WebsocketUtil{
 			dialer: d,
@@ -57,6 +64,10 @@ func NewWebsocketUtil(logger log.T, appConfig appconfig.SsmagentConfig, dialerIn
 		}
 	}
 
+	if appConfig.Mgs.WebSocketCompression {
+		websocketUtil.compressionLevel = appConfig.Mgs.WebSocketCompressionLevel
+	}
+
 	return websocketUtil
 }
 
@@ -69,6 +80,7 @@ func (u *WebsocketUtil) OpenConnection(url string, requestHeader http.Header) (*
 	if err != nil {
 		if resp != nil {
 			u.log.Warnf("Failed to dial websocket, status: %s, err: %s", resp.Status, err)
+			return nil, fmt.Errorf("%w (status %d)", err, resp.StatusCode)
 		} else {
 			u.log.Warnf("Failed to dial websocket: %s", err)
 		}
@@ -77,9 +89,27 @@ func (u *WebsocketUtil) OpenConnection(url string, requestHeader http.Header) (*
 
 	u.log.Infof("Successfully opened websocket connection to: %s", conn.RemoteAddr())
 
+	if u.dialer.EnableCompression {
+		u.configureCompression(conn, resp)
+	}
+
 	return conn, err
 }
 
+// configureCompression applies the compression level when the service accepted permessage-deflate
+func (u *WebsocketUtil) configureCompression(conn *websocket.Conn, resp *http.Response) {
+	if resp == nil || !strings.Contains(resp.Header.Get("Sec-WebSocket-Extensions"), "permessage-deflate") {
+		u.log.Infof("Websocket compression was not accepted by the service, messages are sent uncompressed")
+		return
+	}
+	if u.compressionLevel != 0 {
+		if err := conn.SetCompressionLevel(u.compressionLevel); err != nil {
+			u.log.Warnf("Failed to set websocket compression level %d: %v", u.compressionLevel, err)
+		}
+	}
+	u.log.Infof("Websocket compression negotiated with %s", conn.RemoteAddr())
+}
+
 // CloseConnection closes a websocket connection given the Conn object as input.
 func (u *WebsocketUtil) CloseConnection(ws *websocket.Conn) error {
 	if ws == nil {
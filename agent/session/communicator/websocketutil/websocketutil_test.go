@@ -19,6 +19,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
@@ -120,3 +121,54 @@ func TestSendMessageWithWriteBuffer(t *testing.T) {
 	err := conn.WriteMessage(websocket.TextMessage, []byte("testing testing"))
 	assert.Nil(t, err)
 }
+
+func TestSendMessageWithCompression(t *testing.T) {
+	for _, serverAccepts := range []bool{true, false} {
+		compressionUpgrader := websocket.Upgrader{EnableCompression: serverAccepts}
+		var offered bool
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			conn, err := compressionUpgrader.Upgrade(w, req, nil)
+			if err != nil {
+				return
+			}
+			offered = strings.Contains(req.Header.Get("Sec-WebSocket-Extensions"), "permessage-deflate")
+			mt, p, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			conn.WriteMessage(mt, []byte("hello "+string(p)))
+		}))
+		u, _ := url.Parse(srv.URL)
+		u.Scheme = "ws"
+
+		appConfig := appconfig.DefaultConfig()
+		appConfig.Mgs.WebSocketCompression = true
+		appConfig.Mgs.WebSocketCompressionLevel = 6
+		var ws = NewWebsocketUtil(log.NewMockLog(), appConfig, nil)
+		conn, err := ws.OpenConnection(u.String(), http.Header{})
+		assert.Nil(t, err)
+		assert.NotNil(t, conn, "Open connection failed.")
+
+		err = conn.WriteMessage(websocket.TextMessage, []byte("testing testing"))
+		assert.Nil(t, err)
+		_, reply, err := conn.ReadMessage()
+		assert.Nil(t, err)
+		assert.Equal(t, "hello testing testing", string(reply))
+		assert.True(t, offered)
+
+		conn.Close()
+		srv.Close()
+	}
+}
+
+func TestNewWebsocketUtilKeepsCallerDialerCompression(t *testing.T) {
+	appConfig := appconfig.DefaultConfig()
+	appConfig.Mgs.WebSocketCompression = true
+
+	dialer := &websocket.Dialer{}
+	NewWebsocketUtil(log.NewMockLog(), appConfig, dialer)
+	assert.False(t, dialer.EnableCompression)
+
+	ws := NewWebsocketUtil(log.NewMockLog(), appConfig, nil)
+	assert.True(t, ws.dialer.EnableCompression)
+}
@@ -66,10 +66,16 @@ const (
 	ControlChannelNumMaxRetries           = -1 //forever retries for control channel
 	ControlChannelRetryInitialDelayMillis = 5000
 	ControlChannelRetryMaxIntervalMillis  = 1000 * 60 * 40 // 40 mins
+	// ControlChannelServiceErrorBackoffMultiplier scales the control channel retry delay while the service is failing or throttling
+	ControlChannelServiceErrorBackoffMultiplier = 4.0
 
 	DataChannelNumMaxAttempts          = 5
 	DataChannelRetryInitialDelayMillis = 100
 	DataChannelRetryMaxIntervalMillis  = 5000
+	// DataChannelReconnectNumMaxAttempts and DataChannelReconnectTimeout keep a session worker reconnecting a broken data channel
+	// long enough to outlast a restart or self-update of the agent, the worker refreshes its credentials on its own meanwhile
+	DataChannelReconnectNumMaxAttempts = -1
+	DataChannelReconnectTimeout        = 10 * time.Minute
 
 	// MGS Errors
 	SessionAlreadyTerminatedError = "Session is already terminated"
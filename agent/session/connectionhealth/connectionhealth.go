@@ -0,0 +1,189 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package connectionhealth tracks the health of the message gateway service control channel.
+package connectionhealth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/metrics"
+)
+
+// StatsFileName is the file under the agent data directory the statistics are persisted to.
+const StatsFileName = "mgs_connection_health.json"
+
+// rttSmoothingFactor is the weight of the newest sample in the average round trip time.
+const rttSmoothingFactor = 0.2
+
+// Stats describes the control channel connection since the agent started.
+type Stats struct {
+	Connected               bool
+	LastConnectedTime       time.Time `json:",omitempty"`
+	LastDisconnectedTime    time.Time `json:",omitempty"`
+	Reconnects              int64
+	FailedReconnectAttempts int64
+	LastError               string `json:",omitempty"`
+	RoundTripMillis         int64
+	AverageRoundTripMillis  float64
+	DroppedIncomingMessages int64
+	DroppedOutgoingMessages int64
+	UpdatedTime             time.Time
+}
+
+var (
+	lock  sync.Mutex
+	stats Stats
+
+	statsFilePath = func() string {
+		return filepath.Join(appconfig.DefaultDataStorePath, StatsFileName)
+	}
+	timeNow = time.Now
+)
+
+// RecordConnected records that the control channel was opened.
+func RecordConnected() {
+	update(func(s *Stats) {
+		s.Connected = true
+		s.LastConnectedTime = timeNow()
+	})
+}
+
+// RecordDisconnected records that the control channel was lost with the given error.
+func RecordDisconnected(err error) {
+	update(func(s *Stats) {
+		s.Connected = false
+		s.LastDisconnectedTime = timeNow()
+		if err != nil {
+			s.LastError = err.Error()
+		}
+	})
+}
+
+// RecordReconnect records a successful reconnect of the control channel.
+func RecordReconnect() {
+	metrics.MGSReconnects.Inc("")
+	update(func(s *Stats) {
+		s.Reconnects++
+	})
+}
+
+// RecordReconnectFailure records a failed attempt to reconnect the control channel.
+func RecordReconnectFailure(err error) {
+	metrics.MGSReconnectFailures.Inc("")
+	update(func(s *Stats) {
+		s.FailedReconnectAttempts++
+		if err != nil {
+			s.LastError = err.Error()
+		}
+	})
+}
+
+// RecordRoundTrip records the time between a keepalive ping and its pong.
+func RecordRoundTrip(rtt time.Duration) {
+	metrics.MGSRoundTrip.Set(rtt.Seconds())
+	update(func(s *Stats) {
+		millis := float64(rtt) / float64(time.Millisecond)
+		s.RoundTripMillis = rtt.Milliseconds()
+		if s.AverageRoundTripMillis == 0 {
+			s.AverageRoundTripMillis = millis
+		} else {
+			s.AverageRoundTripMillis += rttSmoothingFactor * (millis - s.AverageRoundTripMillis)
+		}
+	})
+}
+
+// RecordDroppedMessage records a control channel message that could not be processed or sent.
+func RecordDroppedMessage(incoming bool) {
+	direction := "outgoing"
+	if incoming {
+		direction = "incoming"
+	}
+	metrics.MGSDroppedMessages.Inc(direction)
+	update(func(s *Stats) {
+		if incoming {
+			s.DroppedIncomingMessages++
+		} else {
+			s.DroppedOutgoingMessages++
+		}
+	})
+}
+
+// Snapshot returns the statistics recorded by this process.
+func Snapshot() Stats {
+	lock.Lock()
+	defer lock.Unlock()
+	return stats
+}
+
+// Load reads the statistics last persisted by the agent.
+func Load() (Stats, error) {
+	var persisted Stats
+	content, err := os.ReadFile(statsFilePath())
+	if err != nil {
+		return persisted, err
+	}
+	err = json.Unmarshal(content, &persisted)
+	return persisted, err
+}
+
+// ErrNotConnected is returned by ConnectedSince when the agent has not opened the control channel
+var ErrNotConnected = errors.New("control channel is not connected")
+
+// ConnectedSince returns nil when the persisted statistics show a control channel opened after since,
+// statistics left behind by an earlier agent process do not count.
+func ConnectedSince(since time.Time) error {
+	persisted, err := Load()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ErrNotConnected
+		}
+		return fmt.Errorf("%w: %v", ErrNotConnected, err)
+	}
+	if !persisted.Connected || persisted.LastConnectedTime.Before(since) {
+		if persisted.LastError != "" {
+			return fmt.Errorf("%w: %v", ErrNotConnected, persisted.LastError)
+		}
+		return ErrNotConnected
+	}
+	return nil
+}
+
+// update applies the change and persists the result so that ssm-cli can report it.
+// Persisting is best effort, the statistics are diagnostic only.
+func update(change func(s *Stats)) {
+	lock.Lock()
+	defer lock.Unlock()
+	change(&stats)
+	stats.UpdatedTime = timeNow()
+
+	content, err := json.Marshal(stats)
+	if err != nil {
+		return
+	}
+	path := statsFilePath()
+	tmpPath := path + ".tmp"
+	if err = os.WriteFile(tmpPath, content, appconfig.ReadWriteAccess); err != nil {
+		return
+	}
+	if err = os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package connectionhealth
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setup(t *testing.T) {
+	original := statsFilePath
+	path := filepath.Join(t.TempDir(), StatsFileName)
+	statsFilePath = func() string { return path }
+	stats = Stats{}
+	t.Cleanup(func() { statsFilePath = original })
+}
+
+func TestRecordConnectionLifecycle(t *testing.T) {
+	setup(t)
+
+	RecordConnected()
+	RecordDisconnected(fmt.Errorf("i/o timeout"))
+	RecordReconnectFailure(fmt.Errorf("unexpected response from the service (status 503)"))
+	RecordReconnect()
+	RecordConnected()
+
+	current := Snapshot()
+	assert.True(t, current.Connected)
+	assert.Equal(t, int64(1), current.Reconnects)
+	assert.Equal(t, int64(1), current.FailedReconnectAttempts)
+	assert.Equal(t, "unexpected response from the service (status 503)", current.LastError)
+	assert.False(t, current.LastDisconnectedTime.IsZero())
+
+	persisted, err := Load()
+	assert.NoError(t, err)
+	assert.Equal(t, current.Reconnects, persisted.Reconnects)
+	assert.Equal(t, current.Connected, persisted.Connected)
+	assert.Equal(t, current.LastError, persisted.LastError)
+}
+
+func TestRecordRoundTrip(t *testing.T) {
+	setup(t)
+
+	RecordRoundTrip(100 * time.Millisecond)
+	assert.Equal(t, int64(100), Snapshot().RoundTripMillis)
+	assert.Equal(t, float64(100), Snapshot().AverageRoundTripMillis)
+
+	RecordRoundTrip(200 * time.Millisecond)
+	assert.Equal(t, int64(200), Snapshot().RoundTripMillis)
+	assert.InDelta(t, 120, Snapshot().AverageRoundTripMillis, 0.001)
+}
+
+func TestRecordDroppedMessage(t *testing.T) {
+	setup(t)
+
+	RecordDroppedMessage(true)
+	RecordDroppedMessage(false)
+	RecordDroppedMessage(false)
+
+	assert.Equal(t, int64(1), Snapshot().DroppedIncomingMessages)
+	assert.Equal(t, int64(2), Snapshot().DroppedOutgoingMessages)
+}
+
+func TestLoadWithoutFile(t *testing.T) {
+	setup(t)
+
+	_, err := Load()
+	assert.Error(t, err)
+}
+
+func TestConnectedSince(t *testing.T) {
+	setup(t)
+	originalTimeNow := timeNow
+	defer func() { timeNow = originalTimeNow }()
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.ErrorIs(t, ConnectedSince(start), ErrNotConnected)
+
+	timeNow = func() time.Time { return start.Add(-time.Minute) }
+	RecordConnected()
+	assert.ErrorIs(t, ConnectedSince(start), ErrNotConnected)
+
+	timeNow = func() time.Time { return start.Add(time.Second) }
+	RecordDisconnected(fmt.Errorf("i/o timeout"))
+	err := ConnectedSince(start)
+	assert.ErrorIs(t, err, ErrNotConnected)
+	assert.Contains(t, err.Error(), "i/o timeout")
+
+	RecordConnected()
+	assert.NoError(t, ConnectedSince(start))
+}
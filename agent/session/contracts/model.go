@@ -69,6 +69,7 @@ type ShellConfig struct {
 	SeparateOutputStream  interface{} `json:"separateOutputStream" yaml:"separateOutputStream"`
 	StdOutSeparatorPrefix string      `json:"stdOutSeparatorPrefix" yaml:"stdOutSeparatorPrefix"`
 	StdErrSeparatorPrefix string      `json:"stdErrSeparatorPrefix" yaml:"stdErrSeparatorPrefix"`
+	PipeStdin             interface{} `json:"pipeStdin,omitempty" yaml:"pipeStdin,omitempty"`
 }
 
 type IMessage interface {
@@ -333,6 +334,8 @@ const (
 	DisconnectToPort   PayloadTypeFlag = 1
 	TerminateSession   PayloadTypeFlag = 2
 	ConnectToPortError PayloadTypeFlag = 3
+	// CloseStdin is sent by the client once its input reached the end, the agent then closes the stdin of the command
+	CloseStdin PayloadTypeFlag = 4
 )
 
 type SessionStatus string
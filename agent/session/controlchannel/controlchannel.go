@@ -18,7 +18,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/rand"
-	"net/http"
+	"regexp"
+	"strconv"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -29,17 +30,24 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/platform"
 	"github.com/aws/amazon-ssm-agent/agent/session/communicator"
 	mgsConfig "github.com/aws/amazon-ssm-agent/agent/session/config"
+	"github.com/aws/amazon-ssm-agent/agent/session/connectionhealth"
 	mgsContracts "github.com/aws/amazon-ssm-agent/agent/session/contracts"
 	"github.com/aws/amazon-ssm-agent/agent/session/retry"
 	"github.com/aws/amazon-ssm-agent/agent/session/service"
 	"github.com/aws/amazon-ssm-agent/agent/session/telemetry"
 	"github.com/aws/amazon-ssm-agent/agent/ssmconnectionchannel"
 	"github.com/aws/amazon-ssm-agent/agent/version"
+	"github.com/aws/amazon-ssm-agent/common/networkready"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/gorilla/websocket"
 	"github.com/twinj/uuid"
 )
 
+var (
+	serviceErrorIndicators = []string{"Throttl", "TooManyRequests", "ServiceUnavailable", "InternalServerError", "InternalFailure"}
+	statusCodePattern      = regexp.MustCompile(`\(status (\d{3})\)`)
+)
+
 type IControlChannel interface {
 	Initialize(context context.T, mgsService service.Service, instanceId string, agentMessageIncomingMessageChan chan mgsContracts.AgentMessage)
 	SetWebSocket(context context.T, mgsService service.Service, ableToOpenMGSConnection *uint32) error
@@ -70,7 +78,7 @@ func (controlChannel *ControlChannel) Initialize(context context.T,
 	controlChannel.Service = mgsService
 	controlChannel.ChannelId = instanceId
 	controlChannel.channelType = mgsConfig.RoleSubscribe
-	controlChannel.wsChannel = &communicator.WebSocketChannel{}
+	controlChannel.wsChannel = &communicator.WebSocketChannel{OnPong: connectionhealth.RecordRoundTrip}
 	controlChannel.AuditLogScheduler = telemetry.GetAuditLogTelemetryInstance(context, controlChannel.wsChannel)
 	controlChannel.agentMessageIncomingMessageChan = agentMessageIncomingMessageChan
 	controlChannel.context = context
@@ -93,10 +101,18 @@ func (controlChannel *ControlChannel) SetWebSocket(context context.T,
 	}
 
 	onMessageHandler := func(input []byte) {
-		controlChannelIncomingMessageHandler(context, input, controlChannel.agentMessageIncomingMessageChan)
+		if err := controlChannelIncomingMessageHandler(context, input, controlChannel.agentMessageIncomingMessageChan); err != nil {
+			connectionhealth.RecordDroppedMessage(true)
+		}
 	}
 	onErrorHandler := func(err error) {
+		connectionhealth.RecordDisconnected(err)
 		callable := func() (channel interface{}, err error) {
+			defer func() {
+				if err != nil {
+					connectionhealth.RecordReconnectFailure(err)
+				}
+			}()
 			uuid.SwitchFormat(uuid.CleanHyphen)
 			requestId := uuid.NewV4().String()
 			tokenValue, err := getControlChannelToken(context, mgsService, controlChannel.ChannelId, requestId, ableToOpenMGSConnection)
@@ -117,6 +133,8 @@ func (controlChannel *ControlChannel) SetWebSocket(context context.T,
 			MaxDelayInMilli:     mgsConfig.ControlChannelRetryMaxIntervalMillis,
 			MaxAttempts:         mgsConfig.ControlChannelNumMaxRetries,
 			NonRetryableErrors:  getNonRetryableControlChannelErrors(),
+			WakeUp:              func() <-chan struct{} { return networkready.Changed(context.Log()) },
+			BackoffMultiplier:   controlChannelBackoffMultiplier,
 		}
 
 		// add a jitter to the first control-channel call
@@ -160,7 +178,11 @@ func (controlChannel *ControlChannel) SendMessage(log log.T, input []byte, input
 	if controlChannel.wsChannel == nil {
 		return fmt.Errorf("ws not initialized still")
 	}
-	return controlChannel.wsChannel.SendMessage(log, input, inputType)
+	if err := controlChannel.wsChannel.SendMessage(log, input, inputType); err != nil {
+		connectionhealth.RecordDroppedMessage(false)
+		return err
+	}
+	return nil
 }
 
 // Reconnect reconnects a controlchannel.
@@ -180,6 +202,7 @@ func (controlChannel *ControlChannel) Reconnect(context context.T, ableToOpenMGS
 		atomic.StoreUint32(ableToOpenMGSConnection, 1)
 	}
 	ssmconnectionchannel.SetConnectionChannel(context, ssmconnectionchannel.MGSSuccess)
+	connectionhealth.RecordReconnect()
 	log.Debugf("Successfully reconnected with controlchannel with type %s", controlChannel.channelType)
 	return nil
 }
@@ -201,7 +224,8 @@ func (controlChannel *ControlChannel) Open(context context.T, ableToOpenMGSConne
 	log := context.Log()
 	controlChannelDialerInput := &websocket.Dialer{
 		TLSClientConfig: network.GetDefaultTLSConfig(log, controlChannel.context.AppConfig()),
-		Proxy:           http.ProxyFromEnvironment,
+		Proxy:           network.GetProxyFunc(log, controlChannel.context.AppConfig()),
+		NetDialContext:  network.GetProxyDialer(log, controlChannel.context.AppConfig()),
 		WriteBufferSize: mgsConfig.ControlChannelWriteBufferSizeLimit,
 	}
 	if err := controlChannel.wsChannel.Open(log, controlChannelDialerInput); err != nil {
@@ -231,6 +255,7 @@ func (controlChannel *ControlChannel) Open(context context.T, ableToOpenMGSConne
 	}
 
 	if err = controlChannel.SendMessage(log, jsonValue, websocket.TextMessage); err == nil {
+		connectionhealth.RecordConnected()
 		controlChannel.AuditLogScheduler.SendAuditMessage()
 	}
 	return err
@@ -298,6 +323,26 @@ func delayWithJitter(maxDelayMillis int64) {
 	time.Sleep(time.Duration(jitter) * time.Millisecond)
 }
 
+// controlChannelBackoffMultiplier stretches the retry delay when the service itself is failing or throttling,
+// so that agents in a region recovering from an incident do not all reconnect at once.
+func controlChannelBackoffMultiplier(err error) float64 {
+	if err == nil {
+		return 1
+	}
+	message := err.Error()
+	for _, indicator := range serviceErrorIndicators {
+		if strings.Contains(message, indicator) {
+			return mgsConfig.ControlChannelServiceErrorBackoffMultiplier
+		}
+	}
+	if match := statusCodePattern.FindStringSubmatch(message); match != nil {
+		if code, _ := strconv.Atoi(match[1]); code >= 500 || code == 429 {
+			return mgsConfig.ControlChannelServiceErrorBackoffMultiplier
+		}
+	}
+	return 1
+}
+
 // getNonRetryableControlChannelErrors returns list of non retryable errors for control channel retry strategy
 func getNonRetryableControlChannelErrors() []string {
 	return []string{}
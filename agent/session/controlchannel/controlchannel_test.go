@@ -544,6 +544,27 @@ func TestInitializeForContainer(t *testing.T) {
 	assert.NotNil(t, controlChannel.wsChannel)
 }
 
+func TestControlChannelBackoffMultiplier(t *testing.T) {
+	serviceErrors := []error{
+		fmt.Errorf("CreateControlChannel failed with error: unexpected response from the service (status 503) "),
+		fmt.Errorf("failed to connect controlchannel with error: websocket: bad handshake (status 502)"),
+		fmt.Errorf("CreateControlChannel failed with error: ThrottlingException: Rate exceeded"),
+		fmt.Errorf("CreateControlChannel failed with error: unexpected response from the service (status 429) "),
+	}
+	for _, err := range serviceErrors {
+		assert.Equal(t, mgsConfig.ControlChannelServiceErrorBackoffMultiplier, controlChannelBackoffMultiplier(err), err.Error())
+	}
+
+	clientErrors := []error{
+		nil,
+		fmt.Errorf("CreateControlChannel failed with error: unexpected response from the service (status 403) <AccessDeniedException>"),
+		fmt.Errorf("failed to connect controlchannel with error: dial tcp: i/o timeout"),
+	}
+	for _, err := range clientErrors {
+		assert.Equal(t, float64(1), controlChannelBackoffMultiplier(err))
+	}
+}
+
 func getControlChannel() *ControlChannel {
 	return &ControlChannel{
 		wsChannel:                       mockWsChannel,
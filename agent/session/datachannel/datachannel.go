@@ -68,6 +68,7 @@ type IDataChannel interface {
 	SendAgentSessionStateMessage(log log.T, sessionStatus mgsContracts.SessionStatus) error
 	AddDataToOutgoingMessageBuffer(streamMessage StreamingMessage)
 	RemoveDataFromOutgoingMessageBuffer(streamMessageElement *list.Element)
+	OutgoingMessageBufferLength() int
 	AddDataToIncomingMessageBuffer(streamMessage StreamingMessage)
 	RemoveDataFromIncomingMessageBuffer(sequenceNumber int64)
 	SkipHandshake(log log.T)
@@ -271,6 +272,10 @@ func (dataChannel *DataChannel) Initialize(context context.T,
 	}
 }
 
+// reconnectTimeout bounds how long a broken data channel is reconnected before the session is terminated,
+// assigned to a variable to allow unittest to override
+var reconnectTimeout = mgsConfig.DataChannelReconnectTimeout
+
 func (dataChannel *DataChannel) getWsChannelOnErrorHandler(mgsService service.Service,
 	sessionId string,
 	clientId string,
@@ -300,11 +305,13 @@ func (dataChannel *DataChannel) getWsChannelOnErrorHandler(mgsService service.Se
 			GeometricRatio:      mgsConfig.RetryGeometricRatio,
 			InitialDelayInMilli: rand.Intn(mgsConfig.DataChannelRetryInitialDelayMillis) + mgsConfig.DataChannelRetryInitialDelayMillis,
 			MaxDelayInMilli:     mgsConfig.DataChannelRetryMaxIntervalMillis,
-			MaxAttempts:         mgsConfig.DataChannelNumMaxAttempts,
+			MaxAttempts:         mgsConfig.DataChannelReconnectNumMaxAttempts,
 			NonRetryableErrors:  getNonRetryableDataChannelErrors(),
+			Timeout:             reconnectTimeout,
 		}
 		if _, err := retryer.Call(); err != nil {
-			log.Error(err)
+			log.Errorf("Failed to reconnect datachannel, terminating the session: %v", err)
+			dataChannel.cancelFlag.Set(task.Canceled)
 		}
 	}
 }
@@ -620,6 +627,13 @@ func (dataChannel *DataChannel) RemoveDataFromOutgoingMessageBuffer(streamMessag
 	dataChannel.OutgoingMessageBuffer.Mutex.Unlock()
 }
 
+// OutgoingMessageBufferLength returns the number of stream messages that are not acknowledged yet.
+func (dataChannel *DataChannel) OutgoingMessageBufferLength() int {
+	dataChannel.OutgoingMessageBuffer.Mutex.Lock()
+	defer dataChannel.OutgoingMessageBuffer.Mutex.Unlock()
+	return dataChannel.OutgoingMessageBuffer.Messages.Len()
+}
+
 // AddDataToIncomingMessageBuffer adds given message to IncomingMessageBuffer if it has capacity.
 func (dataChannel *DataChannel) AddDataToIncomingMessageBuffer(streamMessage StreamingMessage) {
 	if len(dataChannel.IncomingMessageBuffer.Messages) == dataChannel.IncomingMessageBuffer.Capacity {
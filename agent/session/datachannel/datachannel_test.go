@@ -149,13 +149,43 @@ func TestCancelTaskOnSessionTermination(t *testing.T) {
 func TestNotCancelTaskOnOtherErrors(t *testing.T) {
 	dataChannel := getDataChannel()
 	testMockService := &serviceMock.Service{}
+	testWsChannel := &communicatorMocks.IWebSocketChannel{}
 	dataChannel.Service = testMockService
-	testMockService.On("CreateDataChannel", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("Random Error From Session"))
+	dataChannel.wsChannel = testWsChannel
+	testMockService.On("CreateDataChannel", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("Random Error From Session")).Once()
+	testMockService.On("CreateDataChannel", mock.Anything, mock.Anything, mock.Anything).Return(&service.CreateDataChannelOutput{TokenValue: &token}, nil).Once()
+	testWsChannel.On("SetChannelToken", token).Return()
+	testWsChannel.On("Close", mock.Anything).Return(nil)
+	testWsChannel.On("Open", mock.Anything, mock.Anything).Return(nil)
+	testWsChannel.On("GetChannelToken").Return(token)
+	testWsChannel.On("SendMessage", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	// By not having a mockCancelFlag defined, if it is invoked, this test will panic and fail.
 
 	onErrorHandler := dataChannel.getWsChannelOnErrorHandler(testMockService, sessionId, clientId, mockLog)
 	onErrorHandler(errors.New("Unexpected EOF"))
 	testMockService.AssertExpectations(t)
+	testWsChannel.AssertExpectations(t)
+	assert.False(t, dataChannel.Pause)
+}
+
+func TestCancelTaskWhenReconnectTimesOut(t *testing.T) {
+	defer func(timeout time.Duration) { reconnectTimeout = timeout }(reconnectTimeout)
+	reconnectTimeout = 500 * time.Millisecond
+
+	dataChannel := getDataChannel()
+	testMockService := &serviceMock.Service{}
+	testCancelFlag := &taskmocks.MockCancelFlag{}
+	dataChannel.Service = testMockService
+	dataChannel.cancelFlag = testCancelFlag
+	testMockService.On("CreateDataChannel", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("Random Error From Session"))
+	testCancelFlag.On("Set", task.Canceled).Return()
+
+	onErrorHandler := dataChannel.getWsChannelOnErrorHandler(testMockService, sessionId, clientId, mockLog)
+	onErrorHandler(errors.New("Unexpected EOF"))
+
+	testCancelFlag.AssertExpectations(t)
+	// the handler keeps retrying instead of giving up after a fixed number of attempts
+	assert.True(t, len(testMockService.Calls) > 1)
 }
 
 func TestOpen(t *testing.T) {
@@ -144,6 +144,20 @@ func (_m *IDataChannel) Open(_a0 log.T) error {
 	return r0
 }
 
+// OutgoingMessageBufferLength provides a mock function with given fields:
+func (_m *IDataChannel) OutgoingMessageBufferLength() int {
+	ret := _m.Called()
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func() int); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	return r0
+}
+
 // PerformHandshake provides a mock function with given fields: _a0, kmsKeyId, encryptionEnabled, sessionTypeRequest
 func (_m *IDataChannel) PerformHandshake(_a0 log.T, kmsKeyId string, encryptionEnabled bool, sessionTypeRequest contracts.SessionTypeRequest) error {
 	ret := _m.Called(_a0, kmsKeyId, encryptionEnabled, sessionTypeRequest)
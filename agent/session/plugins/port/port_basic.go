@@ -125,10 +125,11 @@ func (p *BasicPortSession) WritePump(dataChannel datachannel.IDataChannel) (erro
 	}()
 
 	packet := make([]byte, mgsConfig.StreamDataPayloadSize)
+	reader := &batchReader{}
 
 	for {
-		if dataChannel.IsActive() {
-			numBytes, err := p.conn.Read(packet)
+		if canSend(dataChannel) {
+			numBytes, err := reader.read(p.conn, packet)
 			if err != nil {
 				var exitCode int
 				if exitCode = p.handleTCPReadError(err); exitCode == mgsConfig.ResumeReadExitCode {
@@ -142,8 +143,9 @@ func (p *BasicPortSession) WritePump(dataChannel datachannel.IDataChannel) (erro
 				log.Errorf("Unable to send stream data message: %v", err)
 				return appconfig.ErrorExitCode
 			}
+			continue
 		}
-		// Wait for TCP to process more data
+		// Wait for the data channel to become active or for acknowledgements to open the window
 		time.Sleep(time.Millisecond)
 	}
 }
@@ -184,6 +184,7 @@ func (suite *BasicPortTestSuite) TestHandleTCPReadErrorWhenReconnectionToPortFai
 // Testing writepump
 func (suite *BasicPortTestSuite) TestWritePump() {
 	suite.mockDataChannel.On("IsActive").Return(true)
+	suite.mockDataChannel.On("OutgoingMessageBufferLength").Return(0)
 	suite.mockDataChannel.On("SendStreamDataMessage", suite.mockContext.Log(), mgsContracts.Output, payload).Return(nil)
 
 	out, in := net.Pipe()
@@ -0,0 +1,72 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package port
+
+import (
+	"errors"
+	"net"
+	"os"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/session/datachannel"
+)
+
+const (
+	// streamBatchDelay bounds how long a read keeps waiting for more data to fill a stream data message
+	streamBatchDelay = time.Millisecond
+
+	// outgoingMessageWindow is the number of unacknowledged stream data messages at which reading from the port pauses.
+	// With 1024 byte messages it allows 4MB in flight, enough to keep the data channel busy at round trip times of a few
+	// hundred milliseconds while staying far below the capacity of the outgoing message buffer, which drops messages when full.
+	outgoingMessageWindow = 4096
+)
+
+// batchReader coalesces the data that arrives on a connection within streamBatchDelay into one packet,
+// so that many small writes to the port are sent as one stream data message instead of one message each.
+type batchReader struct {
+	// pendingErr is the error that ended a batch, returned by the next read
+	pendingErr error
+}
+
+// read blocks until data is available and then reads until packet is full or streamBatchDelay has passed
+func (r *batchReader) read(conn net.Conn, packet []byte) (numBytes int, err error) {
+	if r.pendingErr != nil {
+		err, r.pendingErr = r.pendingErr, nil
+		return 0, err
+	}
+	if numBytes, err = conn.Read(packet); err != nil || numBytes == len(packet) {
+		return numBytes, err
+	}
+
+	defer conn.SetReadDeadline(time.Time{})
+	if conn.SetReadDeadline(time.Now().Add(streamBatchDelay)) != nil {
+		return numBytes, nil
+	}
+	for numBytes < len(packet) {
+		n, err := conn.Read(packet[numBytes:])
+		numBytes += n
+		if err != nil {
+			if !errors.Is(err, os.ErrDeadlineExceeded) {
+				r.pendingErr = err
+			}
+			break
+		}
+	}
+	return numBytes, nil
+}
+
+// canSend reports whether the data channel is active and has room for more unacknowledged messages
+func canSend(dataChannel datachannel.IDataChannel) bool {
+	return dataChannel.IsActive() && dataChannel.OutgoingMessageBufferLength() < outgoingMessageWindow
+}
@@ -0,0 +1,128 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package port
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+
+	mgsConfig "github.com/aws/amazon-ssm-agent/agent/session/config"
+	dataChannelMock "github.com/aws/amazon-ssm-agent/agent/session/datachannel/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchReaderCoalescesSmallWrites(t *testing.T) {
+	out, in := net.Pipe()
+	defer out.Close()
+	chunk := bytes.Repeat([]byte("x"), 16)
+	writes := mgsConfig.StreamDataPayloadSize / len(chunk) * 4
+	go func() {
+		for i := 0; i < writes; i++ {
+			in.Write(chunk)
+		}
+		in.Close()
+	}()
+
+	reader := &batchReader{}
+	packet := make([]byte, mgsConfig.StreamDataPayloadSize)
+	total, reads := 0, 0
+	for {
+		numBytes, err := reader.read(out, packet)
+		if err != nil {
+			assert.Equal(t, io.EOF, err)
+			break
+		}
+		total += numBytes
+		reads++
+	}
+
+	assert.Equal(t, writes*len(chunk), total)
+	assert.Less(t, reads, writes)
+}
+
+func TestBatchReaderReturnsPartialPacketAndDefersError(t *testing.T) {
+	out, in := net.Pipe()
+	defer out.Close()
+	go func() {
+		in.Write(payload)
+		in.Close()
+	}()
+
+	reader := &batchReader{}
+	packet := make([]byte, mgsConfig.StreamDataPayloadSize)
+	numBytes, err := reader.read(out, packet)
+	assert.Nil(t, err)
+	assert.Equal(t, payload, packet[:numBytes])
+
+	numBytes, err = reader.read(out, packet)
+	assert.Equal(t, 0, numBytes)
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestCanSendPausesWhenWindowIsFull(t *testing.T) {
+	dataChannel := &dataChannelMock.IDataChannel{}
+	dataChannel.On("IsActive").Return(true)
+	dataChannel.On("OutgoingMessageBufferLength").Return(outgoingMessageWindow).Once()
+	dataChannel.On("OutgoingMessageBufferLength").Return(outgoingMessageWindow - 1).Once()
+
+	assert.False(t, canSend(dataChannel))
+	assert.True(t, canSend(dataChannel))
+}
+
+// BenchmarkBatchReader measures the bytes sent per stream data message for a stream of small writes, as
+// produced by database dumps, which were previously sent as one message per write.
+func BenchmarkBatchReader(b *testing.B) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		b.Skip(err)
+	}
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		chunk := bytes.Repeat([]byte("x"), 64)
+		for i := 0; i < b.N; i++ {
+			if _, err = conn.Write(chunk); err != nil {
+				return
+			}
+		}
+	}()
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer conn.Close()
+
+	b.ResetTimer()
+	reader := &batchReader{}
+	packet := make([]byte, mgsConfig.StreamDataPayloadSize)
+	total, messages := 0, 0
+	for {
+		numBytes, err := reader.read(conn, packet)
+		if err != nil {
+			break
+		}
+		total += numBytes
+		messages++
+	}
+	b.SetBytes(64)
+	if messages > 0 {
+		b.ReportMetric(float64(total)/float64(messages), "bytes/message")
+	}
+}
@@ -230,14 +230,15 @@ func (p *MuxPortSession) transferDataToMgs(ctx context.Context, dataChannel data
 			log.Errorf("Transfer data to mgs crashed with message: %v", r)
 		}
 	}()
+	packet := make([]byte, mgsConfig.StreamDataPayloadSize)
+	reader := &batchReader{}
 	for {
-		if dataChannel.IsActive() {
-			packet := make([]byte, mgsConfig.StreamDataPayloadSize)
+		if canSend(dataChannel) {
 			select {
 			case <-ctx.Done():
 				return ctx.Err()
 			default:
-				numBytes, err := p.mgsConn.conn.Read(packet)
+				numBytes, err := reader.read(p.mgsConn.conn, packet)
 				if err != nil {
 					log.Errorf("Unable to read from connection: %v", err)
 					return err
@@ -248,6 +249,7 @@ func (p *MuxPortSession) transferDataToMgs(ctx context.Context, dataChannel data
 					return err
 				}
 			}
+			continue
 		}
 		time.Sleep(time.Millisecond)
 	}
@@ -103,6 +103,7 @@ func (suite *MuxPortTestSuite) TestHandleStreamMessageWhenTerminateSessionFlagIs
 // Test WritePump
 func (suite *MuxPortTestSuite) TestWritePumpFailsToRead() {
 	suite.mockDataChannel.On("IsActive").Return(true)
+	suite.mockDataChannel.On("OutgoingMessageBufferLength").Return(0)
 
 	out, in := net.Pipe()
 	smuxConfig := smux.DefaultConfig()
@@ -147,6 +148,7 @@ func (suite *MuxPortTestSuite) TestWritePumpWhenDatachannelIsNotActive() {
 
 func (suite *MuxPortTestSuite) TestWritePump() {
 	suite.mockDataChannel.On("IsActive").Return(true)
+	suite.mockDataChannel.On("OutgoingMessageBufferLength").Return(0)
 	suite.mockDataChannel.On("SendStreamDataMessage", suite.mockContext.Log(), mgsContracts.Output, payload).Return(nil)
 
 	out, in := net.Pipe()
@@ -170,6 +172,7 @@ func (suite *MuxPortTestSuite) TestWritePump() {
 
 func (suite *MuxPortTestSuite) TestWritePumpWithSmuxKeepDisabledOnClientSide() {
 	suite.mockDataChannel.On("IsActive").Return(true)
+	suite.mockDataChannel.On("OutgoingMessageBufferLength").Return(0)
 	suite.mockDataChannel.On("SendStreamDataMessage", suite.mockContext.Log(), mgsContracts.Output, payload).Return(nil)
 
 	suite.session.clientVersion = "1.2.332.0"
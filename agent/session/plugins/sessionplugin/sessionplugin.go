@@ -17,6 +17,10 @@ package sessionplugin
 import (
 	"fmt"
 	"math/rand"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
 	"github.com/aws/amazon-ssm-agent/agent/context"
@@ -32,6 +36,13 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/task"
 )
 
+// terminationGracePeriod is the time given to a session plugin to wind down once the session worker is asked to terminate
+const terminationGracePeriod = 5 * time.Second
+
+var signalNotify = signal.Notify
+var signalStop = signal.Stop
+var osExit = os.Exit
+
 type NewPluginFunc func(context.T) (ISessionPlugin, error)
 
 // ISessionPlugin interface represents functions that need to be implemented by all session manager plugins
@@ -76,6 +87,10 @@ func (p *SessionPlugin) Execute(
 		dataChannel.Close(log)
 	}()
 
+	done := make(chan struct{})
+	defer close(done)
+	p.notifyOnTermination(dataChannel, cancelFlag, done)
+
 	if err = dataChannel.SendAgentSessionStateMessage(p.context.Log(), mgsContracts.Connected); err != nil {
 		log.Errorf("Unable to send AgentSessionState message with session status %s. %s", mgsContracts.Connected, err)
 	}
@@ -118,6 +133,35 @@ func (p *SessionPlugin) Execute(
 	p.sessionPlugin.Execute(config, cancelFlag, output, dataChannel)
 }
 
+// notifyOnTermination tells the connected client that the session is going away when the session worker
+// is terminated, e.g. on host shutdown, instead of dropping the connection without notice
+func (p *SessionPlugin) notifyOnTermination(dataChannel datachannel.IDataChannel, cancelFlag task.CancelFlag, done chan struct{}) {
+	log := p.context.Log()
+	signals := make(chan os.Signal, 1)
+	signalNotify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		defer signalStop(signals)
+		select {
+		case <-done:
+			return
+		case sig := <-signals:
+			log.Infof("Session worker received %v, terminating session", sig)
+			if err := dataChannel.SendAgentSessionStateMessage(log, mgsContracts.Terminating); err != nil {
+				log.Errorf("Unable to send AgentSessionState message with session status %s. %s", mgsContracts.Terminating, err)
+			}
+			cancelFlag.Set(task.Canceled)
+			select {
+			case <-done:
+			case <-time.After(terminationGracePeriod):
+				log.Warnf("Session plugin did not stop within %v", terminationGracePeriod)
+				dataChannel.PrepareToCloseChannel(log)
+			}
+			osExit(0)
+		}
+	}()
+}
+
 // isEncryptionEnabled checks kmsKeyId and pluginName to determine if encryption is enabled for this session
 // TODO: make encryption configurable for port plugin
 func (p *SessionPlugin) isEncryptionEnabled(kmsKeyId string, pluginName string) bool {
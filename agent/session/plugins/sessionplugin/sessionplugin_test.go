@@ -16,7 +16,10 @@ package sessionplugin
 
 import (
 	"errors"
+	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"testing"
 
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
@@ -89,6 +92,30 @@ func (suite *SessionPluginTestSuite) TestExecute() {
 	suite.mockSessionPlugin.AssertExpectations(suite.T())
 }
 
+func (suite *SessionPluginTestSuite) TestNotifyOnTermination() {
+	signals := make(chan chan<- os.Signal, 1)
+	signalNotify = func(c chan<- os.Signal, sig ...os.Signal) { signals <- c }
+	signalStop = func(c chan<- os.Signal) {}
+	exitCodes := make(chan int, 1)
+	osExit = func(code int) { exitCodes <- code }
+	defer func() {
+		signalNotify = signal.Notify
+		signalStop = signal.Stop
+		osExit = os.Exit
+	}()
+
+	done := make(chan struct{})
+	suite.mockDataChannel.On("SendAgentSessionStateMessage", suite.mockContext.Log(), mgsContracts.Terminating).Return(nil).Once()
+	suite.mockCancelFlag.On("Set", task.Canceled).Run(func(mock.Arguments) { close(done) }).Return().Once()
+
+	suite.sessionPlugin.notifyOnTermination(suite.mockDataChannel, suite.mockCancelFlag, done)
+	(<-signals) <- syscall.SIGTERM
+
+	suite.Equal(0, <-exitCodes)
+	suite.mockDataChannel.AssertExpectations(suite.T())
+	suite.mockCancelFlag.AssertExpectations(suite.T())
+}
+
 func (suite *SessionPluginTestSuite) TestExecuteHandshakeEncryptionDisabled() {
 	sessionProperties := map[string]interface{}{"portNumber": "22"}
 	config := contracts.Configuration{PluginName: appconfig.PluginNamePort, Properties: sessionProperties}
@@ -36,6 +36,14 @@ type ExponentialRetryer struct {
 	MaxDelayInMilli     int
 	MaxAttempts         int
 	NonRetryableErrors  []string
+	// WakeUp optionally returns a channel that cuts the current back off short and resets the delay when closed,
+	// e.g. when the network comes back. It is called before every attempt so events raised mid-call are not lost.
+	WakeUp func() <-chan struct{}
+	// BackoffMultiplier optionally scales the delay after a failed attempt based on the error, e.g. to back off
+	// harder while the service reports it is unavailable. The result never exceeds MaxDelayInMilli and values below 1 are ignored.
+	BackoffMultiplier func(err error) float64
+	// Timeout optionally stops retrying once it has elapsed since the first attempt, whatever MaxAttempts is.
+	Timeout time.Duration
 }
 
 // Init initializes the retryer
@@ -63,20 +71,44 @@ func (retryer *ExponentialRetryer) NextSleepTime(attempt int) (time.Duration, bo
 func (retryer *ExponentialRetryer) Call() (channel interface{}, err error) {
 	attempt := 0
 	failedAttemptsSoFar := 0
+	start := time.Now()
 	for {
+		var wakeUp <-chan struct{}
+		if retryer.WakeUp != nil {
+			wakeUp = retryer.WakeUp()
+		}
 		channel, err := retryer.CallableFunc()
-		if err == nil || failedAttemptsSoFar == retryer.MaxAttempts || retryer.isNonRetryableError(err) {
+		if err == nil || failedAttemptsSoFar == retryer.MaxAttempts || retryer.isNonRetryableError(err) || retryer.timedOut(start) {
 			return channel, err
 		}
 		sleep, exceedMaxDelay := retryer.NextSleepTime(attempt)
 		if !exceedMaxDelay {
 			attempt++
 		}
-		time.Sleep(sleep)
+		if retryer.BackoffMultiplier != nil {
+			if multiplier := retryer.BackoffMultiplier(err); multiplier > 1 {
+				sleep = time.Duration(float64(sleep) * multiplier)
+				if maxDelay := time.Duration(retryer.MaxDelayInMilli) * time.Millisecond; sleep > maxDelay {
+					sleep = maxDelay
+				}
+			}
+		}
+		timer := time.NewTimer(sleep)
+		select {
+		case <-timer.C:
+		case <-wakeUp:
+			timer.Stop()
+			attempt = 0
+		}
 		failedAttemptsSoFar++
 	}
 }
 
+// timedOut returns true if the Timeout is set and has elapsed since start
+func (retryer *ExponentialRetryer) timedOut(start time.Time) bool {
+	return retryer.Timeout > 0 && time.Since(start) >= retryer.Timeout
+}
+
 // isNonRetryableError returns true if passed error is in the list of NonRetryableErrors
 func (retryer *ExponentialRetryer) isNonRetryableError(err error) bool {
 	for _, nonRetryableError := range retryer.NonRetryableErrors {
@@ -50,6 +50,9 @@ func TestRepeatableExponentialRetryerRetriesForGivenNumberOfMaxAttempts(t *testi
 		maxDelayInMilli,
 		maxAttempts,
 		[]string{},
+		nil,
+		nil,
+		0,
 	}
 
 	retryCounterInterface, err := retryer.Call()
@@ -69,6 +72,9 @@ func TestExponentialRetryerWithJitter(t *testing.T) {
 		maxDelayInMilli,
 		1,
 		[]string{},
+		nil,
+		nil,
+		0,
 	}
 	minDelay := int64(initialDelayInMilli) * time.Millisecond.Nanoseconds()
 	maxDelay := int64(float64(minDelay) * (1.0 + jitterRatio))
@@ -91,6 +97,9 @@ func TestRepeatableExponentialRetryerDoesNotRetryInCaseOfNoError(t *testing.T) {
 		maxDelayInMilli,
 		maxAttempts,
 		[]string{nonRetryableError},
+		nil,
+		nil,
+		0,
 	}
 
 	retryCounterInterface, err := retryer.Call()
@@ -115,6 +124,9 @@ func TestRepeatableExponentialRetryerDoesNotRetryInCaseOfNonRetryableError(t *te
 		maxDelayInMilli,
 		maxAttempts,
 		[]string{nonRetryableError},
+		nil,
+		nil,
+		0,
 	}
 
 	retryCounterInterface, err := retryer.Call()
@@ -139,6 +151,9 @@ func TestRepeatableExponentialRetryerRetriesInCaseOfRetryableError(t *testing.T)
 		maxDelayInMilli,
 		maxAttempts,
 		[]string{nonRetryableError},
+		nil,
+		nil,
+		0,
 	}
 
 	retryCounterInterface, err := retryer.Call()
@@ -147,3 +162,97 @@ func TestRepeatableExponentialRetryerRetriesInCaseOfRetryableError(t *testing.T)
 	assert.NotNil(t, err)
 	assert.Equal(t, retryCounter.TotalAttempts, maxAttempts+1)
 }
+
+func TestExponentialRetryerWakeUpSkipsBackOff(t *testing.T) {
+	totalAttempts := 0
+	callableFunc := func() (interface{}, error) {
+		totalAttempts = totalAttempts + 1
+		if totalAttempts < 3 {
+			return RetryCounter{TotalAttempts: totalAttempts}, errors.New(retryableError)
+		}
+		return RetryCounter{TotalAttempts: totalAttempts}, nil
+	}
+	wakeUp := make(chan struct{})
+	close(wakeUp)
+
+	retryer := ExponentialRetryer{
+		CallableFunc:        callableFunc,
+		GeometricRatio:      retryGeometricRatio,
+		InitialDelayInMilli: int(time.Hour / time.Millisecond),
+		MaxDelayInMilli:     int(time.Hour / time.Millisecond),
+		MaxAttempts:         maxAttempts,
+		WakeUp:              func() <-chan struct{} { return wakeUp },
+	}
+
+	start := time.Now()
+	retryCounterInterface, err := retryer.Call()
+
+	retryCounter := retryCounterInterface.(RetryCounter)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, retryCounter.TotalAttempts)
+	assert.True(t, time.Since(start) < time.Minute)
+}
+
+func TestExponentialRetryerBackoffMultiplier(t *testing.T) {
+	attempts := 0
+	callableFunc := func() (interface{}, error) {
+		attempts++
+		if attempts == 1 {
+			return nil, errors.New("service unavailable")
+		}
+		if attempts == 2 {
+			return nil, errors.New(retryableError)
+		}
+		return nil, nil
+	}
+	var multiplierErrors []string
+	retryer := ExponentialRetryer{
+		CallableFunc:        callableFunc,
+		GeometricRatio:      1,
+		InitialDelayInMilli: 20,
+		MaxDelayInMilli:     100,
+		MaxAttempts:         maxAttempts,
+		BackoffMultiplier: func(err error) float64 {
+			multiplierErrors = append(multiplierErrors, err.Error())
+			if err.Error() == "service unavailable" {
+				return 10
+			}
+			return 0.5
+		},
+	}
+
+	start := time.Now()
+	_, err := retryer.Call()
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"service unavailable", retryableError}, multiplierErrors)
+	// the multiplied delay is capped at 100ms, then 20ms as multipliers below 1 are ignored
+	elapsed := time.Since(start)
+	assert.True(t, elapsed >= 120*time.Millisecond)
+	assert.True(t, elapsed < 200*time.Millisecond)
+}
+
+func TestExponentialRetryerStopsAfterTimeout(t *testing.T) {
+	attempts := 0
+	callableFunc := func() (interface{}, error) {
+		attempts++
+		return nil, errors.New(retryableError)
+	}
+	retryer := ExponentialRetryer{
+		CallableFunc:        callableFunc,
+		GeometricRatio:      1,
+		InitialDelayInMilli: 20,
+		MaxDelayInMilli:     20,
+		MaxAttempts:         -1,
+		Timeout:             100 * time.Millisecond,
+	}
+
+	start := time.Now()
+	_, err := retryer.Call()
+
+	assert.EqualError(t, err, retryableError)
+	elapsed := time.Since(start)
+	assert.True(t, elapsed >= 100*time.Millisecond)
+	assert.True(t, elapsed < time.Second)
+	assert.True(t, attempts > 1)
+}
@@ -28,6 +28,7 @@ import (
 	"time"
 
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/clockskew"
 	"github.com/aws/amazon-ssm-agent/agent/context"
 	"github.com/aws/amazon-ssm-agent/agent/log"
 	"github.com/aws/amazon-ssm-agent/agent/network"
@@ -98,7 +99,7 @@ var makeRestcall = func(log log.T, appConfig appconfig.SsmagentConfig, request [
 	}
 
 	httpRequest.Header.Set("Content-Type", "application/json")
-	_, err = signer.Sign(httpRequest, bytes.NewReader(request), mgsconfig.ServiceName, region, time.Now())
+	_, err = signer.Sign(httpRequest, bytes.NewReader(request), mgsconfig.ServiceName, region, clockskew.SigningTime(appConfig.ClockSkew))
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign the request: %s", err)
 	}
@@ -106,7 +107,7 @@ var makeRestcall = func(log log.T, appConfig appconfig.SsmagentConfig, request [
 	tr := network.GetDefaultTransport(log, appConfig)
 	client := &http.Client{
 		Timeout:   mgsClientTimeout,
-		Transport: tr,
+		Transport: clockskew.NewTransport(log, appConfig, tr),
 	}
 
 	resp, err := client.Do(httpRequest)
@@ -123,7 +124,7 @@ var makeRestcall = func(log log.T, appConfig appconfig.SsmagentConfig, request [
 	if resp.StatusCode == httpStatusCodeCreated {
 		return body, nil
 	} else {
-		return nil, fmt.Errorf("unexpected response from the service %s", body)
+		return nil, fmt.Errorf("unexpected response from the service (status %d) %s", resp.StatusCode, body)
 	}
 }
 
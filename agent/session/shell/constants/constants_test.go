@@ -128,3 +128,19 @@ func TestGetStdErrSeparatorPrefix(t *testing.T) {
 	stdOutSeparatorPrefix := GetStdErrSeparatorPrefix(shellProps)
 	assert.Equal(t, stdOutSeparatorPrefix, "STD_ERR:")
 }
+
+// Testing GetPipeStdin
+func TestGetPipeStdin(t *testing.T) {
+	pipeStdin, err := GetPipeStdin(shellProps)
+	assert.Nil(t, err)
+	assert.False(t, pipeStdin)
+
+	pipeShellConfig := mgsContracts.ShellConfig{Commands: "rsync --server -logDtpre.iLsfxC . /tmp", PipeStdin: "true"}
+	pipeStdin, err = GetPipeStdin(mgsContracts.ShellProperties{Linux: pipeShellConfig, Windows: pipeShellConfig, MacOS: pipeShellConfig})
+	assert.Nil(t, err)
+	assert.True(t, pipeStdin)
+
+	errShellConfig := mgsContracts.ShellConfig{Commands: "ls", PipeStdin: "error"}
+	_, err = GetPipeStdin(mgsContracts.ShellProperties{Linux: errShellConfig, Windows: errShellConfig, MacOS: errShellConfig})
+	assert.True(t, strings.Contains(err.Error(), "unable to convert pipeStdin:"))
+}
@@ -56,3 +56,12 @@ func GetStdOutSeparatorPrefix(shellProps mgsContracts.ShellProperties) string {
 func GetStdErrSeparatorPrefix(shellProps mgsContracts.ShellProperties) string {
 	return shellProps.Linux.StdErrSeparatorPrefix
 }
+
+// GetPipeStdin return whether the session input is piped to the stdin of the non-interactive command.
+func GetPipeStdin(shellProps mgsContracts.ShellProperties) (bool, error) {
+	pipeStdin, err := parameters.ConvertToBool(shellProps.Linux.PipeStdin)
+	if err != nil {
+		err = fmt.Errorf("unable to convert pipeStdin: %v", err)
+	}
+	return pipeStdin, err
+}
@@ -0,0 +1,134 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+//
+//go:build darwin || freebsd || linux || netbsd || openbsd
+// +build darwin freebsd linux netbsd openbsd
+
+// Package shell implements session shell plugin.
+package shell
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	agentContracts "github.com/aws/amazon-ssm-agent/agent/contracts"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	mgsContracts "github.com/aws/amazon-ssm-agent/agent/session/contracts"
+	"github.com/creack/pty"
+)
+
+const (
+	// environment variables describing the session to the challenge executable
+	challengeSessionIdEnvVariableKey    = "SSM_SESSION_ID"
+	challengeSessionOwnerEnvVariableKey = "SSM_SESSION_OWNER"
+	challengeSessionUserEnvVariableKey  = "SSM_SESSION_USER"
+
+	// challengeOutputDrainTimeout bounds the wait for the output of the challenge after it exited
+	challengeOutputDrainTimeout = time.Second
+	challengeOutputBufferSize   = 1024
+
+	// challengeFailedMessage is shown to the user, the reason is only logged
+	challengeFailedMessage = "\r\nSession challenge failed, the session is terminated.\r\n"
+)
+
+// runSessionChallenge runs the challenge executable in a pseudo terminal connected to the session, so the user answers
+// the challenge like a login prompt. The shell must only be started when no error is returned.
+func (p *ShellPlugin) runSessionChallenge(log log.T, challenge appconfig.SessionChallengeCfg, config agentContracts.Configuration, sessionUser string) (err error) {
+	defer func() {
+		if err != nil {
+			log.Warnf("Session challenge not passed: %v", err)
+			if sendErr := p.dataChannel.SendStreamDataMessage(log, mgsContracts.Output, []byte(challengeFailedMessage)); sendErr != nil {
+				log.Warnf("Unable to send session challenge result to data channel: %v", sendErr)
+			}
+		}
+	}()
+
+	if !filepath.IsAbs(challenge.Executable) {
+		return fmt.Errorf("session challenge executable %s is not an absolute path", challenge.Executable)
+	}
+	if appconfig.PluginNameNonInteractiveCommands == p.name {
+		return errors.New("session challenge requires an interactive session")
+	}
+
+	timeout := time.Duration(challenge.TimeoutSeconds) * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, challenge.Executable)
+	cmd.Env = append(os.Environ(),
+		termEnvVariable,
+		challengeSessionIdEnvVariableKey+"="+config.SessionId,
+		challengeSessionOwnerEnvVariableKey+"="+config.SessionOwner,
+		challengeSessionUserEnvVariableKey+"="+sessionUser)
+
+	log.Infof("Running session challenge %s", challenge.Executable)
+	challengePty, err := pty.Start(cmd)
+	if err != nil {
+		return fmt.Errorf("failed to start session challenge: %v", err)
+	}
+
+	// session input and terminal resizes go to the challenge until the shell is started
+	ptyFile = challengePty
+	p.stdin = challengePty
+	p.stdout = challengePty
+	defer func() {
+		p.stdin = nil
+		p.stdout = nil
+		ptyFile = nil
+		challengePty.Close()
+	}()
+
+	outputDone := make(chan struct{})
+	go func() {
+		defer close(outputDone)
+		p.forwardChallengeOutput(log, challengePty)
+	}()
+
+	err = cmd.Wait()
+	select {
+	case <-outputDone:
+	case <-time.After(challengeOutputDrainTimeout):
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("session challenge was not answered within %v", timeout)
+	}
+	if err != nil {
+		return fmt.Errorf("session challenge failed: %v", err)
+	}
+	log.Info("Session challenge passed")
+	return nil
+}
+
+// forwardChallengeOutput sends the challenge prompts to the data channel until the pseudo terminal is closed
+func (p *ShellPlugin) forwardChallengeOutput(log log.T, challengePty *os.File) {
+	buffer := make([]byte, challengeOutputBufferSize)
+	for {
+		n, err := challengePty.Read(buffer)
+		if n > 0 {
+			if sendErr := p.dataChannel.SendStreamDataMessage(log, mgsContracts.Output, append([]byte{}, buffer[:n]...)); sendErr != nil {
+				log.Warnf("Unable to send session challenge output to data channel: %v", sendErr)
+			}
+		}
+		if err != nil {
+			// reading the pseudo terminal fails once the challenge and its children exited
+			return
+		}
+	}
+}
@@ -17,6 +17,7 @@ package shell
 import (
 	"bufio"
 	"bytes"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
@@ -46,6 +47,7 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/session/datachannel"
 	"github.com/aws/amazon-ssm-agent/agent/session/shell/constants"
 	"github.com/aws/amazon-ssm-agent/agent/session/shell/execcmd"
+	"github.com/aws/amazon-ssm-agent/agent/session/utility"
 	"github.com/aws/amazon-ssm-agent/agent/task"
 	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
 )
@@ -60,11 +62,15 @@ type ShellPlugin struct {
 	stderrPipe     io.Reader
 	execCmd        execcmd.IExecCmd
 	runAsUser      string
+	isolatedUser   *utility.IsolatedUserLease
 	dataChannel    datachannel.IDataChannel
 	logger         logger
 	separateOutput bool
 	stdoutPrefix   string
 	stderrPrefix   string
+	// pipeStdin passes the session input unchanged to stdinPipe of the non-interactive command, e.g. for scp or rsync
+	pipeStdin bool
+	stdinPipe io.WriteCloser
 }
 
 // logger is used for storing the information related to logging of session data to S3/CW
@@ -88,6 +94,11 @@ type IShellPlugin interface {
 
 const separateOutputStreamPrefixRegex = "^[0-9a-zA-Z\r\n_:-]{0,30}$"
 
+// shellProfileAuditTagPrefix starts the comment that records the shell profile in the session log
+const shellProfileAuditTagPrefix = "# ssm-shell-profile:"
+
+var shellProfileNamePattern = regexp.MustCompile("^[0-9a-zA-Z_.-]{1,128}$")
+
 // NewPlugin returns a new instance of the Shell Plugin
 func NewPlugin(context context.T, name string) (*ShellPlugin, error) {
 	var plugin = ShellPlugin{
@@ -168,6 +179,16 @@ func (p *ShellPlugin) setSeparateOutputStreamProperties(shellProps mgsContracts.
 		p.stderrPrefix = stderrPrefix
 	}
 
+	pipeStdin, err := constants.GetPipeStdin(shellProps)
+	if err != nil {
+		return fmt.Errorf("fail to get pipeStdin property: %v", err)
+	}
+	// only the separated output streams are sent unchanged and end with the exit code of the command
+	if pipeStdin && !p.separateOutput {
+		return errors.New("pipeStdin requires separateOutputStream")
+	}
+	p.pipeStdin = pipeStdin
+
 	return nil
 }
 
@@ -187,6 +208,7 @@ func (p *ShellPlugin) Execute(
 		if err := p.stop(log); err != nil {
 			log.Errorf("Error occurred while closing pty: %v", err)
 		}
+		p.isolatedUser.Release(log)
 		if err := recover(); err != nil {
 			log.Errorf("Error occurred while executing plugin %s: \n%v", p.name, err)
 			log.Errorf("Stacktrace:\n%s", debug.Stack())
@@ -317,6 +339,33 @@ func (p *ShellPlugin) createIpcFile() (*os.File, error) {
 	return ipcFile, err
 }
 
+// runAgentShellProfile executes the shell profile of the agent configuration selected by the session document,
+// or the default shell profile. The profile is preceded by a comment naming it, which tags the session log for auditing.
+func (p *ShellPlugin) runAgentShellProfile(log log.T, config agentContracts.Configuration) error {
+	sessionConfig := p.context.AppConfig().Mgs
+	profileName := config.ShellProfileName
+	if profileName == "" {
+		profileName = sessionConfig.DefaultShellProfile
+	}
+	if profileName == "" {
+		return nil
+	}
+	if !shellProfileNamePattern.MatchString(profileName) {
+		return fmt.Errorf("invalid shell profile name %s", profileName)
+	}
+	profile, found := sessionConfig.ShellProfiles[profileName]
+	if !found {
+		return fmt.Errorf("shell profile %s is not configured on the instance", profileName)
+	}
+
+	log.Infof("Executing shell profile %s", profileName)
+	auditTag := fmt.Sprintf("%s %s", shellProfileAuditTagPrefix, profileName)
+	return p.runShellProfile(log, agentContracts.ShellProfileConfig{
+		Linux:   auditTag + "\n" + profile.Linux,
+		Windows: auditTag + "\n" + profile.Windows,
+	})
+}
+
 // Executes command in pseudo terminal with pty
 func (p *ShellPlugin) executeCommandsWithPty(config agentContracts.Configuration,
 	cancelled chan bool,
@@ -330,9 +379,15 @@ func (p *ShellPlugin) executeCommandsWithPty(config agentContracts.Configuration
 
 	log.Infof("Plugin %s started", p.name)
 
-	// Execute shell profile
+	// Execute shell profiles, the one configured on the instance runs first
 	if appconfig.PluginNameStandardStream == p.name {
-		if err := p.runShellProfile(log, config); err != nil {
+		if err := p.runAgentShellProfile(log, config); err != nil {
+			errorString := fmt.Errorf("Encountered an error while executing shell profile: %s", err)
+			log.Error(errorString)
+			output.MarkAsFailed(errorString)
+			return
+		}
+		if err := p.runShellProfile(log, config.ShellProfile); err != nil {
 			errorString := fmt.Errorf("Encountered an error while executing shell profile: %s", err)
 			log.Error(errorString)
 			output.MarkAsFailed(errorString)
@@ -756,6 +811,38 @@ func (p *ShellPlugin) setupRoutineToWriteCmdPipelineOutput(log log.T, ipcFile *o
 	return done
 }
 
+// writeStdinPipe writes the session input to the stdin of the non-interactive command. The input is passed unchanged
+// as it may be binary, control characters do not signal the command.
+func (p *ShellPlugin) writeStdinPipe(log log.T, streamDataMessage mgsContracts.AgentMessage) error {
+	if p.stdinPipe == nil {
+		log.Tracef("Command stdin unavailable. Reject incoming message packet")
+		return mgsContracts.ErrHandlerNotReady
+	}
+
+	switch mgsContracts.PayloadType(streamDataMessage.PayloadType) {
+	case mgsContracts.Output:
+		log.Tracef("Stdin message received: %d", streamDataMessage.SequenceNumber)
+		if _, err := p.stdinPipe.Write(streamDataMessage.Payload); err != nil {
+			// the command may exit without reading its whole input
+			log.Warnf("Unable to write to command stdin, err: %v.", err)
+		}
+	case mgsContracts.Flag:
+		var flag mgsContracts.PayloadTypeFlag
+		buf := bytes.NewBuffer(streamDataMessage.Payload)
+		binary.Read(buf, binary.BigEndian, &flag)
+
+		if flag == mgsContracts.CloseStdin {
+			log.Debugf("CloseStdin flag received: %d", streamDataMessage.SequenceNumber)
+			if err := p.stdinPipe.Close(); err != nil {
+				log.Debugf("Unable to close command stdin, err: %v.", err)
+			}
+		}
+	case mgsContracts.Size:
+		log.Debug("Terminal resize message is ignored in NonInteractiveCommands plugin")
+	}
+	return nil
+}
+
 // Write command exit code to data channel
 func (p *ShellPlugin) sendExitCode(log log.T, ipcFile *os.File, exitCode int) error {
 	var unprocessedBuf bytes.Buffer
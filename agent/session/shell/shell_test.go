@@ -140,7 +140,7 @@ func (suite *ShellTestSuite) TestSetSeparateOutputStreamProperties() {
 		execCmd:     suite.mockCmd,
 	}
 	shellConfig := mgsContracts.ShellConfig{
-		"ls", false, "true", "STD_OUT:\n", "STD_ERR:\n"}
+		"ls", false, "true", "STD_OUT:\n", "STD_ERR:\n", false}
 	shellProperties := mgsContracts.ShellProperties{shellConfig, shellConfig, shellConfig}
 
 	plugin.setSeparateOutputStreamProperties(shellProperties)
@@ -158,7 +158,7 @@ func (suite *ShellTestSuite) TestSetSeparateOutputStreamPropertiesWithInvalidSep
 		execCmd:     suite.mockCmd,
 	}
 	shellConfig := mgsContracts.ShellConfig{
-		"ls", false, "error", "STD_OUT:\n", "STD$ERR:\n"}
+		"ls", false, "error", "STD_OUT:\n", "STD$ERR:\n", false}
 	shellProperties := mgsContracts.ShellProperties{shellConfig, shellConfig, shellConfig}
 
 	err := plugin.setSeparateOutputStreamProperties(shellProperties)
@@ -175,7 +175,7 @@ func (suite *ShellTestSuite) TestSetSeparateOutputStreamPropertiesWithInvalidStd
 		execCmd:     suite.mockCmd,
 	}
 	shellConfig := mgsContracts.ShellConfig{
-		"ls", false, "true", "STD@OUT:\n", "STD_ERR:\n"}
+		"ls", false, "true", "STD@OUT:\n", "STD_ERR:\n", false}
 	shellProperties := mgsContracts.ShellProperties{shellConfig, shellConfig, shellConfig}
 
 	err := plugin.setSeparateOutputStreamProperties(shellProperties)
@@ -193,7 +193,7 @@ func (suite *ShellTestSuite) TestSetSeparateOutputStreamPropertiesWithInvalidStd
 		execCmd:     suite.mockCmd,
 	}
 	shellConfig := mgsContracts.ShellConfig{
-		"ls", false, "true", "STD_OUT:\n", "STD$ERR:\n"}
+		"ls", false, "true", "STD_OUT:\n", "STD$ERR:\n", false}
 	shellProperties := mgsContracts.ShellProperties{shellConfig, shellConfig, shellConfig}
 
 	err := plugin.setSeparateOutputStreamProperties(shellProperties)
@@ -202,6 +202,26 @@ func (suite *ShellTestSuite) TestSetSeparateOutputStreamPropertiesWithInvalidStd
 	suite.True(plugin.separateOutput)
 }
 
+// Testing setSeparateOutputStreamProperties with pipeStdin but without separateOutPutStream
+func (suite *ShellTestSuite) TestSetSeparateOutputStreamPropertiesWithPipeStdin() {
+	plugin := &ShellPlugin{
+		context:     suite.mockContext,
+		name:        appconfig.PluginNameNonInteractiveCommands,
+		dataChannel: suite.mockDataChannel,
+		execCmd:     suite.mockCmd,
+	}
+	shellConfig := mgsContracts.ShellConfig{Commands: "scp -t /tmp", SeparateOutputStream: "false", PipeStdin: "true"}
+	shellProperties := mgsContracts.ShellProperties{Windows: shellConfig, Linux: shellConfig, MacOS: shellConfig}
+
+	err := plugin.setSeparateOutputStreamProperties(shellProperties)
+	suite.True(strings.Contains(err.Error(), "pipeStdin requires separateOutputStream"))
+
+	shellConfig.SeparateOutputStream = "true"
+	shellProperties = mgsContracts.ShellProperties{Windows: shellConfig, Linux: shellConfig, MacOS: shellConfig}
+	suite.Nil(plugin.setSeparateOutputStreamProperties(shellProperties))
+	suite.True(plugin.pipeStdin)
+}
+
 // Testing sendExitCode for NonInteractiveCommands plugin
 func (suite *ShellTestSuite) TestSendExitCode() {
 	plugin := &ShellPlugin{
@@ -271,7 +291,7 @@ func (suite *ShellTestSuite) TestExecuteForNonInteractiveCommandsWithSeparateOut
 	stderrPipeinput.Write(payload)
 
 	shellConfig := mgsContracts.ShellConfig{
-		"ls", false, "true", "STD_OUT:\n", "STD_ERR:\n"}
+		"ls", false, "true", "STD_OUT:\n", "STD_ERR:\n", false}
 	shellProperties := mgsContracts.ShellProperties{shellConfig, shellConfig, shellConfig}
 
 	getCommandExecutor = func(log log.T, shellProps mgsContracts.ShellProperties, isSessionLogger bool, config contracts.Configuration, plugin *ShellPlugin) (err error) {
@@ -335,7 +355,7 @@ func (suite *ShellTestSuite) TestExecuteForNonInteractiveCommandsWithSeparateOut
 	stderrPipeinput.Write(payload)
 
 	shellConfig := mgsContracts.ShellConfig{
-		"ls", false, "true", "STD_OUT:\n", "STD_ERR:\n"}
+		"ls", false, "true", "STD_OUT:\n", "STD_ERR:\n", false}
 	shellProperties := mgsContracts.ShellProperties{shellConfig, shellConfig, shellConfig}
 
 	getCommandExecutor = func(log log.T, shellProps mgsContracts.ShellProperties, isSessionLogger bool, config contracts.Configuration, plugin *ShellPlugin) (err error) {
@@ -50,6 +50,7 @@ const (
 	termEnvVariable       = "TERM=xterm-256color"
 	langEnvVariable       = "LANG=C.UTF-8"
 	langEnvVariableKey    = "LANG"
+	tmpDirEnvVariableKey  = "TMPDIR"
 	startRecordSessionCmd = "script"
 	newLineCharacter      = "\n"
 	catCmd                = "cat"
@@ -131,7 +132,14 @@ func StartCommandExecutor(
 
 			sessionUser = config.RunAsUser
 		} else {
-			if os.Geteuid() == 0 {
+			if os.Geteuid() == 0 && appConfig.Mgs.IsolatedUsers.Enabled {
+				// Start as a user of the isolated user pool, concurrent sessions do not share a user
+				if plugin.isolatedUser, err = utility.LeaseIsolatedUser(log, appConfig.Mgs.IsolatedUsers); err != nil {
+					return fmt.Errorf("failed to lease an isolated user: %v", err)
+				}
+				sessionUser = plugin.isolatedUser.UserName
+				cmd.Env = append(cmd.Env, tmpDirEnvVariableKey+"="+plugin.isolatedUser.TempDir)
+			} else if os.Geteuid() == 0 {
 				// Start as ssm-user
 				// Create ssm-user before starting a session.
 				u.CreateLocalAdminUser(log)
@@ -163,6 +171,12 @@ func StartCommandExecutor(
 		cmd.Env = append(cmd.Env, constants.RootHomeEnvVariable)
 	}
 
+	if challenge := appConfig.Mgs.SessionChallenge; challenge.Executable != "" && !isSessionLogger {
+		if err = plugin.runSessionChallenge(log, challenge, config, sessionUser); err != nil {
+			return err
+		}
+	}
+
 	if appconfig.PluginNameNonInteractiveCommands == plugin.name {
 		if plugin.separateOutput {
 			//Open pipeline for reading only
@@ -174,6 +188,11 @@ func StartCommandExecutor(
 			if err != nil {
 				return fmt.Errorf("Failed to create command err pipe, error: %s\n", err)
 			}
+			if plugin.pipeStdin {
+				if plugin.stdinPipe, err = cmd.StdinPipe(); err != nil {
+					return fmt.Errorf("Failed to create command input pipe, error: %s\n", err)
+				}
+			}
 			plugin.stdin = nil
 			plugin.stdout = nil
 			plugin.stderrPipe = errorPipe
@@ -320,14 +339,14 @@ func extractGroupId(value string) (int, error) {
 }
 
 // runShellProfile executes the shell profile config
-func (p *ShellPlugin) runShellProfile(log log.T, config agentContracts.Configuration) error {
-	if strings.TrimSpace(config.ShellProfile.Linux) == "" {
+func (p *ShellPlugin) runShellProfile(log log.T, shellProfile agentContracts.ShellProfileConfig) error {
+	if strings.TrimSpace(shellProfile.Linux) == "" {
 		return nil
 	}
 	if p.stdin == nil {
 		return nil
 	}
-	if _, err := p.stdin.Write([]byte(config.ShellProfile.Linux + newLineCharacter)); err != nil {
+	if _, err := p.stdin.Write([]byte(shellProfile.Linux + newLineCharacter)); err != nil {
 		log.Errorf("Unable to write to stdin, err: %v.", err)
 		return err
 	}
@@ -425,6 +444,9 @@ func (p *ShellPlugin) cleanupLogFile(log log.T, ipcFile *os.File) {
 // InputStreamMessageHandler passes payload byte stream to shell stdin
 func (p *ShellPlugin) InputStreamMessageHandler(log log.T, streamDataMessage mgsContracts.AgentMessage) error {
 	var isPluginNonInteractive = appconfig.PluginNameNonInteractiveCommands == p.name
+	if isPluginNonInteractive && p.pipeStdin {
+		return p.writeStdinPipe(log, streamDataMessage)
+	}
 	if !isPluginNonInteractive && (p.stdin == nil || p.stdout == nil) {
 		// This is to handle scenario when cli/console starts sending size data but pty has not been started yet
 		// Since packets are rejected, cli/console will resend these packets until pty starts successfully in separate thread
@@ -20,15 +20,19 @@ package shell
 
 import (
 	"bytes"
+	"encoding/binary"
 	"errors"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
 	"github.com/aws/amazon-ssm-agent/agent/contracts"
 	"github.com/aws/amazon-ssm-agent/agent/log"
+	contextmocks "github.com/aws/amazon-ssm-agent/agent/mocks/context"
 	mgsConfig "github.com/aws/amazon-ssm-agent/agent/session/config"
 	mgsContracts "github.com/aws/amazon-ssm-agent/agent/session/contracts"
 	"github.com/aws/amazon-ssm-agent/agent/task"
@@ -909,6 +913,45 @@ func (suite *ShellTestSuite) TestNonInteractiveInputStreamMessageHandlerWithTerm
 	assert.Nil(suite.T(), err)
 }
 
+// Testing InputStreamMessageHandler in NonInteractiveCommands plugin with stdin piped to the command
+func (suite *ShellTestSuite) TestNonInteractiveInputStreamMessageHandlerWithPipedStdin() {
+	reader, writer, _ := os.Pipe()
+	defer reader.Close()
+	plugin := &ShellPlugin{
+		context:     suite.mockContext,
+		name:        appconfig.PluginNameNonInteractiveCommands,
+		dataChannel: suite.mockDataChannel,
+		execCmd:     suite.mockCmd,
+		pipeStdin:   true,
+		stdinPipe:   writer,
+	}
+	flagBuf := new(bytes.Buffer)
+	binary.Write(flagBuf, binary.BigEndian, mgsContracts.CloseStdin)
+
+	input := []byte{'C', '\003', 0, 0xff}
+	assert.Nil(suite.T(), plugin.InputStreamMessageHandler(suite.mockLog, buildAgentMessage(uint32(mgsContracts.Output), input)))
+	assert.Nil(suite.T(), plugin.InputStreamMessageHandler(suite.mockLog, buildAgentMessage(uint32(mgsContracts.Flag), flagBuf.Bytes())))
+
+	received, err := ioutil.ReadAll(reader)
+	assert.Nil(suite.T(), err)
+	assert.Equal(suite.T(), input, received)
+	suite.mockCmd.AssertNotCalled(suite.T(), "Signal", mock.Anything)
+}
+
+// Testing InputStreamMessageHandler in NonInteractiveCommands plugin before the command stdin is piped
+func (suite *ShellTestSuite) TestNonInteractiveInputStreamMessageHandlerWithPipedStdinNotReady() {
+	plugin := &ShellPlugin{
+		context:     suite.mockContext,
+		name:        appconfig.PluginNameNonInteractiveCommands,
+		dataChannel: suite.mockDataChannel,
+		pipeStdin:   true,
+	}
+
+	err := plugin.InputStreamMessageHandler(suite.mockLog, buildAgentMessage(uint32(mgsContracts.Output), payload))
+
+	assert.Equal(suite.T(), mgsContracts.ErrHandlerNotReady, err)
+}
+
 // buildAgentMessage constructs and returns AgentMessage with payload type and payload
 func buildAgentMessage(payloadType uint32, payload []byte) mgsContracts.AgentMessage {
 	agentMessage := mgsContracts.AgentMessage{
@@ -938,7 +981,7 @@ func (suite *ShellTestSuite) TestStartCommandExecutorWithNonInteractiveCommand()
 	config := contracts.Configuration{PluginName: appconfig.PluginNameNonInteractiveCommands, RunAsEnabled: false}
 
 	shellConfig := mgsContracts.ShellConfig{
-		"ls", false, "true", "STD_OUT:\n", "STD_ERR:\n"}
+		"ls", false, "true", "STD_OUT:\n", "STD_ERR:\n", false}
 	shellProperties := mgsContracts.ShellProperties{shellConfig, shellConfig, shellConfig}
 	suite.plugin.name = appconfig.PluginNameNonInteractiveCommands
 	suite.plugin.separateOutput = true
@@ -959,6 +1002,34 @@ func (suite *ShellTestSuite) TestStartCommandExecutorWithNonInteractiveCommand()
 	suite.plugin.execCmd.Kill()
 }
 
+// Test StartCommandExecutor for NonInteractiveCommand session with stdin piped to the command
+func (suite *ShellTestSuite) TestStartCommandExecutorWithPipedStdin() {
+	config := contracts.Configuration{PluginName: appconfig.PluginNameNonInteractiveCommands, RunAsEnabled: false}
+
+	shellConfig := mgsContracts.ShellConfig{Commands: "cat", RunAsElevated: true, SeparateOutputStream: true, PipeStdin: true}
+	shellProperties := mgsContracts.ShellProperties{Windows: shellConfig, Linux: shellConfig, MacOS: shellConfig}
+	suite.plugin.name = appconfig.PluginNameNonInteractiveCommands
+	suite.plugin.separateOutput = true
+	suite.plugin.pipeStdin = true
+	suite.plugin.stdinPipe = nil
+
+	err := StartCommandExecutor(
+		suite.mockLog,
+		shellProperties,
+		false,
+		config,
+		suite.plugin)
+	assert.Nil(suite.T(), err)
+	assert.NotNil(suite.T(), suite.plugin.stdinPipe)
+
+	assert.Nil(suite.T(), suite.plugin.execCmd.Start())
+	suite.plugin.stdinPipe.Write([]byte{0, 1, 2, 0xff})
+	suite.plugin.stdinPipe.Close()
+	output, _ := ioutil.ReadAll(suite.plugin.stdoutPipe)
+	assert.Equal(suite.T(), []byte{0, 1, 2, 0xff}, output)
+	assert.Nil(suite.T(), suite.plugin.execCmd.Wait())
+}
+
 func (suite *ShellTestSuite) TestExecuteForNonInteractiveCommandSession() {
 	suite.mockCancelFlag.On("Canceled").Return(false)
 	suite.mockCancelFlag.On("ShutDown").Return(false)
@@ -973,7 +1044,7 @@ func (suite *ShellTestSuite) TestExecuteForNonInteractiveCommandSession() {
 	suite.mockDataChannel.On("SendStreamDataMessage", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
 	shellConfig := mgsContracts.ShellConfig{
-		"ls", false, "true", "STD_OUT:\n", "STD_ERR:\n"}
+		"ls", false, "true", "STD_OUT:\n", "STD_ERR:\n", false}
 	shellProperties := mgsContracts.ShellProperties{shellConfig, shellConfig, shellConfig}
 
 	getCommandExecutor = func(log log.T, shellProps mgsContracts.ShellProperties, isSessionLogger bool, config contracts.Configuration, plugin *ShellPlugin) (err error) {
@@ -1001,3 +1072,119 @@ func (suite *ShellTestSuite) TestExecuteForNonInteractiveCommandSession() {
 	suite.mockIohandler.AssertExpectations(suite.T())
 	suite.mockDataChannel.AssertExpectations(suite.T())
 }
+
+func (suite *ShellTestSuite) TestRunAgentShellProfileSelectedByDocument() {
+	var config appconfig.SsmagentConfig
+	config.Mgs.DefaultShellProfile = "default"
+	config.Mgs.ShellProfiles = map[string]appconfig.ShellProfileCfg{
+		"default": {Linux: "echo default"},
+		"banner":  {Linux: "cat /etc/motd\nexport AWS_REGION=us-east-1"},
+	}
+	suite.plugin.context = contextmocks.NewMockDefaultWithConfig(config)
+
+	err := suite.plugin.runAgentShellProfile(suite.mockLog, contracts.Configuration{ShellProfileName: "banner"})
+	suite.stdin.Close()
+
+	assert.Nil(suite.T(), err)
+	written, _ := ioutil.ReadAll(suite.stdout)
+	assert.Equal(suite.T(), "# ssm-shell-profile: banner\ncat /etc/motd\nexport AWS_REGION=us-east-1\n", string(written))
+}
+
+func (suite *ShellTestSuite) TestRunAgentShellProfileFallsBackToDefault() {
+	var config appconfig.SsmagentConfig
+	config.Mgs.DefaultShellProfile = "default"
+	config.Mgs.ShellProfiles = map[string]appconfig.ShellProfileCfg{"default": {Linux: "echo default"}}
+	suite.plugin.context = contextmocks.NewMockDefaultWithConfig(config)
+
+	err := suite.plugin.runAgentShellProfile(suite.mockLog, contracts.Configuration{})
+	suite.stdin.Close()
+
+	assert.Nil(suite.T(), err)
+	written, _ := ioutil.ReadAll(suite.stdout)
+	assert.Equal(suite.T(), "# ssm-shell-profile: default\necho default\n", string(written))
+}
+
+func (suite *ShellTestSuite) TestRunAgentShellProfileFailsForUnknownProfile() {
+	suite.plugin.context = contextmocks.NewMockDefaultWithConfig(appconfig.SsmagentConfig{})
+
+	err := suite.plugin.runAgentShellProfile(suite.mockLog, contracts.Configuration{ShellProfileName: "banner"})
+	assert.EqualError(suite.T(), err, "shell profile banner is not configured on the instance")
+
+	err = suite.plugin.runAgentShellProfile(suite.mockLog, contracts.Configuration{ShellProfileName: "banner\nrm -rf /"})
+	assert.EqualError(suite.T(), err, "invalid shell profile name banner\nrm -rf /")
+}
+
+// writeChallengeScript writes an executable challenge script and returns its path
+func (suite *ShellTestSuite) writeChallengeScript(script string) string {
+	path := filepath.Join(suite.T().TempDir(), "challenge.sh")
+	assert.Nil(suite.T(), os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0700))
+	return path
+}
+
+// challengeOutput collects the output sent to the data channel
+func (suite *ShellTestSuite) challengeOutput() *bytes.Buffer {
+	output := &bytes.Buffer{}
+	suite.mockDataChannel.On("SendStreamDataMessage", mock.Anything, mgsContracts.Output, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		output.Write(args.Get(2).([]byte))
+	})
+	return output
+}
+
+func (suite *ShellTestSuite) TestRunSessionChallengePassed() {
+	output := suite.challengeOutput()
+	suite.plugin.name = appconfig.PluginNameStandardStream
+	challenge := appconfig.SessionChallengeCfg{
+		Executable:     suite.writeChallengeScript(`echo "challenge for $SSM_SESSION_OWNER as $SSM_SESSION_USER in $SSM_SESSION_ID"`),
+		TimeoutSeconds: 10,
+	}
+	config := contracts.Configuration{SessionId: "session-id", SessionOwner: "arn:aws:iam::123456789012:user/admin"}
+
+	err := suite.plugin.runSessionChallenge(suite.mockLog, challenge, config, "ssm-user")
+	assert.Nil(suite.T(), err)
+	assert.Contains(suite.T(), output.String(), "challenge for arn:aws:iam::123456789012:user/admin as ssm-user in session-id")
+	// the session input is not passed to the challenge anymore
+	assert.Nil(suite.T(), suite.plugin.stdin)
+	assert.Nil(suite.T(), ptyFile)
+}
+
+func (suite *ShellTestSuite) TestRunSessionChallengeFailed() {
+	output := suite.challengeOutput()
+	suite.plugin.name = appconfig.PluginNameStandardStream
+	challenge := appconfig.SessionChallengeCfg{
+		Executable:     suite.writeChallengeScript("echo 'Invalid code'\nexit 1"),
+		TimeoutSeconds: 10,
+	}
+
+	err := suite.plugin.runSessionChallenge(suite.mockLog, challenge, contracts.Configuration{}, "ssm-user")
+	assert.NotNil(suite.T(), err)
+	assert.Contains(suite.T(), err.Error(), "session challenge failed")
+	assert.Contains(suite.T(), output.String(), "Invalid code")
+	assert.True(suite.T(), strings.HasSuffix(output.String(), challengeFailedMessage))
+}
+
+func (suite *ShellTestSuite) TestRunSessionChallengeTimeout() {
+	suite.challengeOutput()
+	suite.plugin.name = appconfig.PluginNameStandardStream
+	challenge := appconfig.SessionChallengeCfg{
+		Executable:     suite.writeChallengeScript("read code"),
+		TimeoutSeconds: 1,
+	}
+
+	err := suite.plugin.runSessionChallenge(suite.mockLog, challenge, contracts.Configuration{}, "ssm-user")
+	assert.EqualError(suite.T(), err, "session challenge was not answered within 1s")
+}
+
+func (suite *ShellTestSuite) TestRunSessionChallengeNotRunnable() {
+	output := suite.challengeOutput()
+
+	suite.plugin.name = appconfig.PluginNameStandardStream
+	err := suite.plugin.runSessionChallenge(suite.mockLog, appconfig.SessionChallengeCfg{Executable: "challenge.sh", TimeoutSeconds: 10}, contracts.Configuration{}, "ssm-user")
+	assert.EqualError(suite.T(), err, "session challenge executable challenge.sh is not an absolute path")
+
+	// sessions without a terminal cannot answer the challenge
+	suite.plugin.name = appconfig.PluginNameNonInteractiveCommands
+	challenge := appconfig.SessionChallengeCfg{Executable: suite.writeChallengeScript("exit 0"), TimeoutSeconds: 10}
+	err = suite.plugin.runSessionChallenge(suite.mockLog, challenge, contracts.Configuration{}, "ssm-user")
+	assert.EqualError(suite.T(), err, "session challenge requires an interactive session")
+	assert.Equal(suite.T(), challengeFailedMessage+challengeFailedMessage, output.String())
+}
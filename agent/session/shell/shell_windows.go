@@ -86,6 +86,10 @@ func StartCommandExecutor(
 	plugin *ShellPlugin) (err error) {
 
 	log.Info("Starting command executor")
+	if plugin.context.AppConfig().Mgs.SessionChallenge.Executable != "" && !isSessionLogger {
+		// fail closed, sessions must not bypass the challenge configured for the instance
+		return errors.New("session challenge is not supported on Windows")
+	}
 	if _, err := os.Stat(winptyDllFilePath); os.IsNotExist(err) {
 		return fmt.Errorf("Missing %s file.", winptyDllFilePath)
 	}
@@ -183,6 +187,11 @@ func (p *ShellPlugin) startExecCmd(finalCmd string, log log.T, config agentContr
 		if err != nil {
 			return fmt.Errorf("Failed to create command err pipe, error: %s\n", err)
 		}
+		if p.pipeStdin {
+			if p.stdinPipe, err = cmd.StdinPipe(); err != nil {
+				return fmt.Errorf("Failed to create command input pipe, error: %s\n", err)
+			}
+		}
 		p.stdin = nil
 		p.stdout = nil
 		p.stderrPipe = errorPipe
@@ -291,14 +300,14 @@ func (p *ShellPlugin) startPtyAsUser(log log.T, config agentContracts.Configurat
 }
 
 // runShellProfile executes the shell profile config
-func (p *ShellPlugin) runShellProfile(log log.T, config agentContracts.Configuration) error {
-	if strings.TrimSpace(config.ShellProfile.Windows) == "" {
+func (p *ShellPlugin) runShellProfile(log log.T, shellProfile agentContracts.ShellProfileConfig) error {
+	if strings.TrimSpace(shellProfile.Windows) == "" {
 		return nil
 	}
 	if p.stdin == nil {
 		return nil
 	}
-	commands := strings.Split(config.ShellProfile.Windows, "\n")
+	commands := strings.Split(shellProfile.Windows, "\n")
 
 	for _, command := range commands {
 		if _, err := p.stdin.Write([]byte(command + shellProfileNewLineCharacter)); err != nil {
@@ -556,6 +565,9 @@ func (p *ShellPlugin) cleanupLogFile(log log.T, ipcFile *os.File) {
 // InputStreamMessageHandler passes payload byte stream to shell command executor
 func (p *ShellPlugin) InputStreamMessageHandler(log log.T, streamDataMessage mgsContracts.AgentMessage) error {
 	var isPluginNonInteractive = appconfig.PluginNameNonInteractiveCommands == p.name
+	if isPluginNonInteractive && p.pipeStdin {
+		return p.writeStdinPipe(log, streamDataMessage)
+	}
 
 	if !isPluginNonInteractive && (p.stdin == nil || p.stdout == nil) {
 		// This is to handle scenario when cli/console starts sending size data but pty has not been started yet
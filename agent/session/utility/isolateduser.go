@@ -0,0 +1,49 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// utility package implements all the shared methods between clients.
+package utility
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+)
+
+// isolatedUserTempDirName is the private temp directory created in the home of an isolated user
+const isolatedUserTempDirName = ".tmp"
+
+// isolatedUsersLockDir holds one lock file per pool user, a user is leased while its file is locked
+var isolatedUsersLockDir = filepath.Join(appconfig.DefaultDataStorePath, "isolatedusers")
+
+// IsolatedUserLease is a user of the isolated user pool reserved for a single session or command
+type IsolatedUserLease struct {
+	// UserName is the user the session runs as
+	UserName string
+	// Uid and Gid are the ids of the user
+	Uid uint32
+	Gid uint32
+	// HomeDir is the private home of the user
+	HomeDir string
+	// TempDir is the private temp directory of the session, to be used as TMPDIR
+	TempDir string
+
+	lockFile *os.File
+}
+
+// IsolatedUserName returns the name of the pool user with the given index, starting at 1
+func IsolatedUserName(prefix string, index int) string {
+	return fmt.Sprintf("%s%02d", prefix, index)
+}
@@ -0,0 +1,191 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build linux
+// +build linux
+
+// utility package implements all the shared methods between clients.
+package utility
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"syscall"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+)
+
+// addIsolatedUserCommand creates a pool user with its fixed UID
+const addIsolatedUserCommand = "useradd -m -u %d %s"
+
+// isolatedUserSharedDirs are the world writable directories a previous session of a pool user can leave files in
+var isolatedUserSharedDirs = []string{"/tmp", "/var/tmp", "/dev/shm"}
+
+// The main purpose of these delegates is to easily test the isolated user pool
+var (
+	runUserCommand = func(name string, arg ...string) error {
+		return execCommand(name, arg...).Run()
+	}
+	lookupUser   = user.Lookup
+	lookupUserID = user.LookupId
+	flock        = syscall.Flock
+	chown        = os.Chown
+)
+
+// LeaseIsolatedUser reserves the first free user of the pool for the session and recreates it, so the session starts
+// with an empty private home and temp directory and no process or file of the previous session of the user survives.
+// Every pool user keeps the same UID from the configured range, a UID is never handed to another user.
+// The lease is held by the session worker process and ends when the lease is released or the process exits.
+func LeaseIsolatedUser(log log.T, config appconfig.IsolatedUsersCfg) (*IsolatedUserLease, error) {
+	if err := os.MkdirAll(isolatedUsersLockDir, appconfig.ReadWriteExecuteAccess); err != nil {
+		return nil, fmt.Errorf("failed to create isolated user lock directory: %v", err)
+	}
+
+	for index := 1; index <= config.PoolSize; index++ {
+		userName := IsolatedUserName(config.UserPrefix, index)
+		lockFile, err := os.OpenFile(filepath.Join(isolatedUsersLockDir, userName), os.O_CREATE|os.O_RDWR, appconfig.ReadWriteAccess)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open lock file of isolated user %s: %v", userName, err)
+		}
+		if err = flock(int(lockFile.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+			// another session runs as this user
+			lockFile.Close()
+			continue
+		}
+
+		lease := &IsolatedUserLease{UserName: userName, Uid: uint32(config.FirstUID + index - 1), lockFile: lockFile}
+		if err = recreateIsolatedUser(log, lease); err != nil {
+			lease.Release(log)
+			return nil, err
+		}
+		log.Infof("Leased isolated user %s", userName)
+		return lease, nil
+	}
+	return nil, fmt.Errorf("all %d isolated users are in use", config.PoolSize)
+}
+
+// Release terminates the processes left behind by the session and returns the user to the pool
+func (l *IsolatedUserLease) Release(log log.T) {
+	if l == nil || l.lockFile == nil {
+		return
+	}
+	if err := killUserProcesses(l.UserName); err != nil {
+		log.Warnf("Failed to terminate the processes of isolated user %s: %v", l.UserName, err)
+	}
+	if err := l.lockFile.Close(); err != nil {
+		log.Warnf("Failed to release isolated user %s: %v", l.UserName, err)
+	}
+	l.lockFile = nil
+	log.Infof("Released isolated user %s", l.UserName)
+}
+
+// recreateIsolatedUser deletes the user with its home and the files it left in shared directories
+// and creates it again with the UID of the lease, it sets the ids and directories of the lease
+func recreateIsolatedUser(log log.T, lease *IsolatedUserLease) (err error) {
+	userName := lease.UserName
+	if owner, err := lookupUserID(strconv.FormatUint(uint64(lease.Uid), 10)); err == nil && owner.Username != userName {
+		return fmt.Errorf("UID %d of isolated user %s is used by %s", lease.Uid, userName, owner.Username)
+	}
+	if _, err = lookupUser(userName); err == nil {
+		// processes started by the previous session could still read the files of this session
+		if err = killUserProcesses(userName); err != nil {
+			return fmt.Errorf("failed to terminate the processes of isolated user %s: %v", userName, err)
+		}
+		if err = runUserCommand("userdel", "-r", userName); err != nil {
+			return fmt.Errorf("failed to delete isolated user %s: %v", userName, err)
+		}
+	}
+	for _, dir := range isolatedUserSharedDirs {
+		if err = removeFilesOwnedBy(dir, lease.Uid); err != nil {
+			return fmt.Errorf("failed to remove the files of isolated user %s: %v", userName, err)
+		}
+	}
+	if err = runUserCommand(ShellPluginCommandName, append(ShellPluginCommandArgs, fmt.Sprintf(addIsolatedUserCommand, lease.Uid, userName))...); err != nil {
+		return fmt.Errorf("failed to create isolated user %s: %v", userName, err)
+	}
+
+	sessionUser, err := lookupUser(userName)
+	if err != nil {
+		return fmt.Errorf("failed to look up isolated user %s: %v", userName, err)
+	}
+	if sessionUser.Uid != strconv.FormatUint(uint64(lease.Uid), 10) {
+		return fmt.Errorf("isolated user %s was created with UID %s instead of %d", userName, sessionUser.Uid, lease.Uid)
+	}
+	gid, err := strconv.ParseUint(sessionUser.Gid, 10, 32)
+	if err != nil {
+		return fmt.Errorf("invalid group of isolated user %s: %v", userName, err)
+	}
+	lease.Gid = uint32(gid)
+	lease.HomeDir = sessionUser.HomeDir
+
+	// the home mode depends on the distribution defaults, the home of an isolated user is private in any case
+	if err = os.Chmod(lease.HomeDir, appconfig.ReadWriteExecuteAccess); err != nil {
+		return fmt.Errorf("failed to restrict the home of isolated user %s: %v", userName, err)
+	}
+	lease.TempDir = filepath.Join(lease.HomeDir, isolatedUserTempDirName)
+	if err = os.MkdirAll(lease.TempDir, appconfig.ReadWriteExecuteAccess); err != nil {
+		return fmt.Errorf("failed to create the temp directory of isolated user %s: %v", userName, err)
+	}
+	if err = chown(lease.TempDir, int(lease.Uid), int(lease.Gid)); err != nil {
+		return fmt.Errorf("failed to create the temp directory of isolated user %s: %v", userName, err)
+	}
+	log.Debugf("Recreated isolated user %s with UID %d", userName, lease.Uid)
+	return nil
+}
+
+// removeFilesOwnedBy removes the files and directories owned by the UID below dir, symbolic links are not followed.
+// Entries vanishing or becoming unreadable during the walk are skipped.
+func removeFilesOwnedBy(dir string, uid uint32) error {
+	return filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if path == dir {
+			if os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if err != nil {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil
+		}
+		if stat, ok := info.Sys().(*syscall.Stat_t); !ok || stat.Uid != uid {
+			return nil
+		}
+		if err = os.RemoveAll(path); err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+}
+
+// killUserProcesses terminates all processes of the user, pkill exits with 1 when no process matched
+func killUserProcesses(userName string) error {
+	err := runUserCommand("pkill", "-KILL", "-u", userName)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return nil
+	}
+	return err
+}
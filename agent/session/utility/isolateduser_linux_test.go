@@ -0,0 +1,199 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build linux
+// +build linux
+
+// utility package implements all the shared methods between clients.
+package utility
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	logger "github.com/aws/amazon-ssm-agent/agent/mocks/log"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubIsolatedUsers fakes the user database, the given users exist already with the UIDs of the map
+func stubIsolatedUsers(t *testing.T, existingUsers map[string]string) (commands *[]string) {
+	lockDirOrig, runUserCommandOrig, lookupUserOrig, lookupUserIDOrig, chownOrig, sharedDirsOrig :=
+		isolatedUsersLockDir, runUserCommand, lookupUser, lookupUserID, chown, isolatedUserSharedDirs
+	t.Cleanup(func() {
+		isolatedUsersLockDir, runUserCommand, lookupUser, lookupUserID, chown, isolatedUserSharedDirs =
+			lockDirOrig, runUserCommandOrig, lookupUserOrig, lookupUserIDOrig, chownOrig, sharedDirsOrig
+	})
+
+	homes := t.TempDir()
+	users := map[string]string{}
+	for userName, uid := range existingUsers {
+		users[userName] = uid
+	}
+	commands = &[]string{}
+
+	isolatedUsersLockDir = filepath.Join(t.TempDir(), "isolatedusers")
+	isolatedUserSharedDirs = []string{filepath.Join(t.TempDir(), "missing")}
+	chown = func(string, int, int) error { return nil }
+	lookupUser = func(userName string) (*user.User, error) {
+		uid, found := users[userName]
+		if !found {
+			return nil, user.UnknownUserError(userName)
+		}
+		return &user.User{Username: userName, Uid: uid, Gid: uid, HomeDir: filepath.Join(homes, userName)}, nil
+	}
+	lookupUserID = func(uid string) (*user.User, error) {
+		for userName, userUID := range users {
+			if userUID == uid {
+				return lookupUser(userName)
+			}
+		}
+		return nil, user.UnknownUserIdError(0)
+	}
+	runUserCommand = func(name string, arg ...string) error {
+		command := strings.Join(append([]string{name}, arg...), " ")
+		*commands = append(*commands, command)
+		fields := strings.Fields(command)
+		userName := fields[len(fields)-1]
+		switch {
+		case strings.Contains(command, "useradd"):
+			users[userName] = fields[len(fields)-2]
+			return os.MkdirAll(filepath.Join(homes, userName), 0755)
+		case name == "userdel":
+			delete(users, userName)
+			return os.RemoveAll(filepath.Join(homes, userName))
+		}
+		return nil
+	}
+	return commands
+}
+
+func TestLeaseIsolatedUser(t *testing.T) {
+	commands := stubIsolatedUsers(t, nil)
+	config := appconfig.IsolatedUsersCfg{Enabled: true, UserPrefix: "ssm-session-", PoolSize: 2, FirstUID: 61000}
+	log := logger.NewMockLog()
+
+	first, err := LeaseIsolatedUser(log, config)
+	assert.NoError(t, err)
+	assert.Equal(t, "ssm-session-01", first.UserName)
+	assert.Equal(t, uint32(61000), first.Uid)
+	assert.Equal(t, uint32(61000), first.Gid)
+	assert.Equal(t, filepath.Dir(first.TempDir), first.HomeDir)
+	assert.Equal(t, []string{"sh -c useradd -m -u 61000 ssm-session-01"}, *commands)
+
+	// the home and the temp directory are private to the session user
+	info, err := os.Stat(filepath.Dir(first.TempDir))
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+	info, err = os.Stat(first.TempDir)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0700), info.Mode().Perm())
+
+	second, err := LeaseIsolatedUser(log, config)
+	assert.NoError(t, err)
+	assert.Equal(t, "ssm-session-02", second.UserName)
+	assert.Equal(t, uint32(61001), second.Uid)
+
+	_, err = LeaseIsolatedUser(log, config)
+	assert.EqualError(t, err, "all 2 isolated users are in use")
+
+	// a released user is leased again and recreated for the next session
+	first.Release(log)
+	*commands = nil
+	third, err := LeaseIsolatedUser(log, config)
+	assert.NoError(t, err)
+	assert.Equal(t, "ssm-session-01", third.UserName)
+	assert.Equal(t, uint32(61000), third.Uid)
+	assert.Equal(t, []string{
+		"pkill -KILL -u ssm-session-01",
+		"userdel -r ssm-session-01",
+		"sh -c useradd -m -u 61000 ssm-session-01",
+	}, *commands)
+
+	second.Release(log)
+	third.Release(log)
+	// releasing twice does nothing
+	third.Release(log)
+}
+
+func TestLeaseIsolatedUser_UIDUsedByOtherUser(t *testing.T) {
+	commands := stubIsolatedUsers(t, map[string]string{"alice": "61000"})
+	config := appconfig.IsolatedUsersCfg{Enabled: true, UserPrefix: "ssm-session-", PoolSize: 1, FirstUID: 61000}
+
+	_, err := LeaseIsolatedUser(logger.NewMockLog(), config)
+	assert.EqualError(t, err, "UID 61000 of isolated user ssm-session-01 is used by alice")
+	assert.Equal(t, []string{"pkill -KILL -u ssm-session-01"}, *commands)
+}
+
+func TestLeaseIsolatedUser_RemovesFilesOfPreviousSession(t *testing.T) {
+	stubIsolatedUsers(t, map[string]string{"ssm-session-01": strconv.Itoa(os.Getuid())})
+	sharedDir := t.TempDir()
+	isolatedUserSharedDirs = []string{sharedDir}
+	os.MkdirAll(filepath.Join(sharedDir, "session", "nested"), 0700)
+	os.WriteFile(filepath.Join(sharedDir, "session", "nested", "secret"), []byte("secret"), 0600)
+	os.WriteFile(filepath.Join(sharedDir, "secret"), []byte("secret"), 0600)
+	config := appconfig.IsolatedUsersCfg{Enabled: true, UserPrefix: "ssm-session-", PoolSize: 1, FirstUID: os.Getuid()}
+
+	lease, err := LeaseIsolatedUser(logger.NewMockLog(), config)
+	assert.NoError(t, err)
+	defer lease.Release(logger.NewMockLog())
+	entries, err := os.ReadDir(sharedDir)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestRemoveFilesOwnedBy(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "file"), []byte("data"), 0600)
+
+	// files of other users are kept
+	assert.NoError(t, removeFilesOwnedBy(dir, uint32(os.Getuid()+1)))
+	assert.FileExists(t, filepath.Join(dir, "file"))
+
+	assert.NoError(t, removeFilesOwnedBy(dir, uint32(os.Getuid())))
+	assert.NoFileExists(t, filepath.Join(dir, "file"))
+	assert.DirExists(t, dir)
+
+	assert.NoError(t, removeFilesOwnedBy(filepath.Join(dir, "missing"), uint32(os.Getuid())))
+}
+
+func TestLeaseIsolatedUser_CreateFails(t *testing.T) {
+	stubIsolatedUsers(t, nil)
+	runUserCommand = func(name string, arg ...string) error {
+		if strings.Contains(strings.Join(arg, " "), "useradd") {
+			return fmt.Errorf("useradd: cannot lock /etc/passwd")
+		}
+		return nil
+	}
+	config := appconfig.IsolatedUsersCfg{Enabled: true, UserPrefix: "ssm-session-", PoolSize: 1, FirstUID: 61000}
+	log := logger.NewMockLog()
+
+	_, err := LeaseIsolatedUser(log, config)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot lock /etc/passwd")
+
+	// the failed lease does not keep the user reserved
+	runUserCommand = func(name string, arg ...string) error { return fmt.Errorf("still failing") }
+	_, err = LeaseIsolatedUser(log, config)
+	assert.Contains(t, err.Error(), "still failing")
+}
+
+func TestIsolatedUserName(t *testing.T) {
+	assert.Equal(t, "ssm-session-01", IsolatedUserName("ssm-session-", 1))
+	assert.Equal(t, "bastion-42", IsolatedUserName("bastion-", 42))
+}
@@ -0,0 +1,33 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build !linux
+// +build !linux
+
+// utility package implements all the shared methods between clients.
+package utility
+
+import (
+	"errors"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+)
+
+// LeaseIsolatedUser is only supported on Linux
+func LeaseIsolatedUser(log log.T, config appconfig.IsolatedUsersCfg) (*IsolatedUserLease, error) {
+	return nil, errors.New("isolated users are only supported on Linux")
+}
+
+// Release does nothing, isolated users are only supported on Linux
+func (l *IsolatedUserLease) Release(log log.T) {}
@@ -0,0 +1,191 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build !darwin
+// +build !darwin
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// bootstrapUserData reads the bootstrap from the cloud-init user-data instead of a file
+	bootstrapUserData = "user-data"
+	// cloudConfigHeader starts the cloud-config user-data, the bootstrap is then read from the ssm_agent key
+	cloudConfigHeader = "#cloud-config"
+)
+
+var (
+	cloudInitUserDataPath = "/var/lib/cloud/instance/user-data.txt"
+	osReadFile            = os.ReadFile
+	osSetenv              = os.Setenv
+)
+
+// bootstrapConfig holds the setup parameters read from a bootstrap file, JSON being a subset of YAML both formats are accepted
+type bootstrapConfig struct {
+	Environment             string          `yaml:"env"`
+	Region                  string          `yaml:"region"`
+	ActivationCode          string          `yaml:"activationCode"`
+	ActivationId            string          `yaml:"activationId"`
+	Role                    string          `yaml:"role"`
+	Tags                    string          `yaml:"tags"`
+	ArtifactsDir            string          `yaml:"artifactsDir"`
+	Version                 string          `yaml:"version"`
+	ManifestUrl             string          `yaml:"manifestUrl"`
+	Install                 bool            `yaml:"install"`
+	Register                *bool           `yaml:"register"`
+	Override                bool            `yaml:"override"`
+	Downgrade               bool            `yaml:"downgrade"`
+	Hardened                bool            `yaml:"hardened"`
+	SkipSignatureValidation bool            `yaml:"skipSignatureValidation"`
+	Proxy                   *bootstrapProxy `yaml:"proxy"`
+}
+
+type bootstrapProxy struct {
+	HttpProxy  string `yaml:"http"`
+	HttpsProxy string `yaml:"https"`
+	NoProxy    string `yaml:"noProxy"`
+}
+
+type cloudConfig struct {
+	SsmAgent yaml.MapSlice `yaml:"ssm_agent"`
+}
+
+// applyBootstrap sets the parameters of the bootstrap file which were not passed on the command line and
+// exports its proxy settings, the cli parameter verification then runs as for command line parameters
+func applyBootstrap(source string) error {
+	config, err := loadBootstrap(source)
+	if err != nil {
+		return err
+	}
+	if err = config.validate(); err != nil {
+		return fmt.Errorf("invalid bootstrap %v: %v", source, err)
+	}
+
+	passed := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) {
+		passed[f.Name] = true
+	})
+	setString := func(name string, target *string, value string) {
+		if !passed[name] && value != "" {
+			*target = value
+		}
+	}
+	setBool := func(name string, target *bool, value bool) {
+		if !passed[name] && value {
+			*target = value
+		}
+	}
+
+	setString("env", &environment, config.Environment)
+	setString("region", &region, config.Region)
+	setString("activation-code", &activationCode, config.ActivationCode)
+	setString("activation-id", &activationId, config.ActivationId)
+	setString("role", &role, config.Role)
+	setString("tags", &tags, config.Tags)
+	setString("artifacts-dir", &artifactsDir, config.ArtifactsDir)
+	setString("version", &version, config.Version)
+	setString("manifest-url", &manifestUrl, config.ManifestUrl)
+	setBool("install", &install, config.Install)
+	setBool("override", &override, config.Override)
+	setBool("downgrade", &downgrade, config.Downgrade)
+	setBool("hardened", &hardened, config.Hardened)
+	setBool("skip-signature-validation", &skipSignatureValidation, config.SkipSignatureValidation)
+	// a bootstrap carrying credentials registers unless told otherwise
+	if config.Register != nil {
+		setBool("register", &register, *config.Register)
+	} else {
+		setBool("register", &register, config.ActivationCode != "" || config.Role != "")
+	}
+
+	if config.Proxy != nil {
+		for name, value := range map[string]string{
+			"http_proxy":  config.Proxy.HttpProxy,
+			"https_proxy": config.Proxy.HttpsProxy,
+			"no_proxy":    config.Proxy.NoProxy,
+		} {
+			if value == "" {
+				continue
+			}
+			if err = osSetenv(name, value); err != nil {
+				return fmt.Errorf("failed to set %v: %v", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// loadBootstrap reads the bootstrap from the file or from the cloud-init user-data
+func loadBootstrap(source string) (*bootstrapConfig, error) {
+	path := source
+	if source == bootstrapUserData {
+		path = cloudInitUserDataPath
+	}
+	content, err := osReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bootstrap: %v", err)
+	}
+	return parseBootstrap(content)
+}
+
+// parseBootstrap parses the bootstrap, unknown keys are rejected to catch misspelled parameters
+func parseBootstrap(content []byte) (*bootstrapConfig, error) {
+	if strings.HasPrefix(strings.TrimSpace(string(content)), cloudConfigHeader) {
+		var userData cloudConfig
+		if err := yaml.Unmarshal(content, &userData); err != nil {
+			return nil, fmt.Errorf("failed to parse cloud-config user-data: %v", err)
+		}
+		if userData.SsmAgent == nil {
+			return nil, fmt.Errorf("cloud-config user-data has no ssm_agent section")
+		}
+		// the section is parsed strictly below
+		section, err := yaml.Marshal(userData.SsmAgent)
+		if err != nil {
+			return nil, err
+		}
+		content = section
+	}
+
+	var config bootstrapConfig
+	if err := yaml.UnmarshalStrict(content, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse bootstrap: %v", err)
+	}
+	return &config, nil
+}
+
+// validate checks the values which the cli parameter verification does not
+func (config *bootstrapConfig) validate() error {
+	if (config.ActivationCode == "") != (config.ActivationId == "") {
+		return fmt.Errorf("activationCode and activationId must be set together")
+	}
+	if config.Proxy != nil {
+		for _, proxy := range []string{config.Proxy.HttpProxy, config.Proxy.HttpsProxy} {
+			if proxy == "" {
+				continue
+			}
+			proxyURL, err := url.Parse(proxy)
+			if err != nil || proxyURL.Host == "" || (proxyURL.Scheme != "http" && proxyURL.Scheme != "https") {
+				return fmt.Errorf("invalid proxy url %v", proxy)
+			}
+		}
+	}
+	return nil
+}
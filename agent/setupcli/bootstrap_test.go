@@ -0,0 +1,108 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build freebsd || linux || netbsd || openbsd
+// +build freebsd linux netbsd openbsd
+
+package main
+
+import (
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setBootstrapAndRestore(t *testing.T, path, content string, args ...string) map[string]string {
+	environment, region, activationCode, activationId, version = "", "", "", "", ""
+	register, install, hardened = false, false, false
+	defer setArgsAndRestoreOnprem(append([]string{"ssm-setup-cli"}, args...)...)()
+	setParams()
+
+	exported := map[string]string{}
+	osReadFile = func(name string) ([]byte, error) {
+		assert.Equal(t, path, name)
+		return []byte(content), nil
+	}
+	osSetenv = func(key, value string) error {
+		exported[key] = value
+		return nil
+	}
+	t.Cleanup(func() {
+		osReadFile = os.ReadFile
+		osSetenv = os.Setenv
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	})
+	return exported
+}
+
+func TestApplyBootstrap_Json(t *testing.T) {
+	exported := setBootstrapAndRestore(t, "bootstrap.json", `{
+		"region": "us-west-2",
+		"activationCode": "code",
+		"activationId": "id",
+		"version": "latest",
+		"hardened": true,
+		"proxy": {"https": "http://proxy.example.com:3128", "noProxy": "169.254.169.254"}
+	}`, "-bootstrap", "bootstrap.json")
+
+	assert.NoError(t, applyBootstrap(bootstrap))
+	assert.Equal(t, "us-west-2", region)
+	assert.Equal(t, "code", activationCode)
+	assert.Equal(t, "id", activationId)
+	assert.Equal(t, "latest", version)
+	assert.True(t, hardened)
+	assert.True(t, register, "a bootstrap with an activation registers")
+	assert.Equal(t, map[string]string{"https_proxy": "http://proxy.example.com:3128", "no_proxy": "169.254.169.254"}, exported)
+	assert.Empty(t, onPremParamVerification())
+}
+
+func TestApplyBootstrap_FlagsTakePrecedence(t *testing.T) {
+	setBootstrapAndRestore(t, "bootstrap.yaml", "region: us-west-2\ninstall: true\nregister: false\n",
+		"-bootstrap", "bootstrap.yaml", "-region", "eu-west-1")
+
+	assert.NoError(t, applyBootstrap(bootstrap))
+	assert.Equal(t, "eu-west-1", region)
+	assert.True(t, install)
+	assert.False(t, register)
+}
+
+func TestApplyBootstrap_CloudInitUserData(t *testing.T) {
+	setBootstrapAndRestore(t, cloudInitUserDataPath, `#cloud-config
+packages:
+  - curl
+ssm_agent:
+  region: us-east-1
+  activationCode: code
+  activationId: id
+`, "-bootstrap", "user-data")
+
+	assert.NoError(t, applyBootstrap(bootstrap))
+	assert.Equal(t, "us-east-1", region)
+	assert.True(t, register)
+}
+
+func TestApplyBootstrap_Invalid(t *testing.T) {
+	for _, content := range []string{
+		"region: us-east-1\nactivationcode: code\n",
+		"region: us-east-1\nactivationCode: code\n",
+		"region: us-east-1\nproxy:\n  https: proxy.example.com\n",
+		"#cloud-config\npackages:\n  - curl\n",
+		"#cloud-config\nssm_agent:\n  regoin: us-east-1\n",
+	} {
+		setBootstrapAndRestore(t, "bootstrap.yaml", content, "-bootstrap", "bootstrap.yaml")
+
+		assert.Error(t, applyBootstrap(bootstrap), content)
+	}
+}
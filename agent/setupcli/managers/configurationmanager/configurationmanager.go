@@ -23,6 +23,7 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
 	"github.com/aws/amazon-ssm-agent/agent/fileutil"
 	"github.com/aws/amazon-ssm-agent/agent/jsonutil"
+	"github.com/aws/amazon-ssm-agent/agent/log/logger"
 	"github.com/aws/amazon-ssm-agent/common/identity/availableidentities/onprem"
 )
 
@@ -38,6 +39,9 @@ var (
 	fileWrite     = fileutil.WriteIntoFileWithPermissions
 	readAllText   = fileutil.ReadAllText
 	unmarshalFile = jsonutil.UnmarshalFile
+
+	loadConfigFile    = appconfig.LoadConfigFile
+	writeSeelogConfig = logger.WriteSeelogConfig
 )
 
 // configurationManager contains functions for handling agent configurations
@@ -144,6 +148,32 @@ func (m *configurationManager) CreateUpdateAgentConfigWithOnPremIdentity() error
 	return fmt.Errorf("error while writing config file with Onprem identity: %v", err)
 }
 
+// ConfigureLogging generates seelog.xml from the Logging section of the agent config. The seelog.xml installed with
+// the agent is kept when the agent config has no Logging section.
+func (m *configurationManager) ConfigureLogging() (bool, error) {
+	agentConfigPath := filepath.Join(agentConfigFolderPath, agentConfigFile)
+	if !fileExists(agentConfigPath) {
+		return false, nil
+	}
+	configJsonData, err := getExistingAgentConfigData(agentConfigPath)
+	if err != nil {
+		return false, err
+	}
+	if _, found := configJsonData["Logging"]; !found {
+		return false, nil
+	}
+
+	// the parsed config applies the defaults and limits of the logging options
+	agentConfig, err := loadConfigFile(agentConfigPath)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse config at path %s: %w", agentConfigPath, err)
+	}
+	if _, err = writeSeelogConfig(filepath.Join(agentConfigFolderPath, appconfig.SeelogConfigFileName), agentConfig.Logging); err != nil {
+		return false, fmt.Errorf("error while writing seelog config: %w", err)
+	}
+	return true, nil
+}
+
 // getExistingAgentConfigData gets the agent config data and store it in a map
 func getExistingAgentConfigData(agentConfigPath string) (map[string]interface{}, error) {
 	var configJsonData map[string]interface{}
@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -300,6 +301,65 @@ func (suite *ConfigManagerTestSuite) TestConfigManager_CreateUpdateAgentConfigWi
 	assert.Nil(suite.T(), err)
 }
 
+func (suite *ConfigManagerTestSuite) TestConfigManager_ConfigureLogging() {
+	fileExists = func(filePath string) bool {
+		return true
+	}
+	readAllText = func(filePath string) (text string, err error) {
+		return `{"Logging": {"Level": "debug"}}`, nil
+	}
+	loadConfigFile = func(path string) (appconfig.SsmagentConfig, error) {
+		assert.Equal(suite.T(), filepath.Join(agentConfigFolderPath, agentConfigFile), path)
+		config := appconfig.DefaultConfig()
+		config.Logging.Level = "debug"
+		return config, nil
+	}
+	var writtenPath string
+	var writtenConfig appconfig.LoggingCfg
+	writeSeelogConfig = func(path string, config appconfig.LoggingCfg) (string, error) {
+		writtenPath, writtenConfig = path, config
+		return "", nil
+	}
+
+	configured, err := New().ConfigureLogging()
+	assert.Nil(suite.T(), err)
+	assert.True(suite.T(), configured)
+	assert.Equal(suite.T(), filepath.Join(agentConfigFolderPath, appconfig.SeelogConfigFileName), writtenPath)
+	assert.Equal(suite.T(), "debug", writtenConfig.Level)
+	assert.Equal(suite.T(), appconfig.DefaultLoggingMaxRolls, writtenConfig.MaxRolls)
+
+	writeSeelogConfig = func(path string, config appconfig.LoggingCfg) (string, error) {
+		return "", fmt.Errorf("permission denied")
+	}
+	configured, err = New().ConfigureLogging()
+	assert.False(suite.T(), configured)
+	assert.EqualError(suite.T(), err, "error while writing seelog config: permission denied")
+}
+
+func (suite *ConfigManagerTestSuite) TestConfigManager_ConfigureLogging_NoLoggingSection() {
+	fileExists = func(filePath string) bool {
+		return true
+	}
+	readAllText = func(filePath string) (text string, err error) {
+		return `{"Agent": {"Region": "us-east-1"}}`, nil
+	}
+	writeSeelogConfig = func(path string, config appconfig.LoggingCfg) (string, error) {
+		assert.FailNow(suite.T(), "seelog config written without Logging section")
+		return "", nil
+	}
+
+	configured, err := New().ConfigureLogging()
+	assert.Nil(suite.T(), err)
+	assert.False(suite.T(), configured)
+
+	fileExists = func(filePath string) bool {
+		return false
+	}
+	configured, err = New().ConfigureLogging()
+	assert.Nil(suite.T(), err)
+	assert.False(suite.T(), configured)
+}
+
 func TestConfigManagerTestSuite(t *testing.T) {
 	suite.Run(t, new(ConfigManagerTestSuite))
 }
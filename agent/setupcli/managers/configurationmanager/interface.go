@@ -21,4 +21,6 @@ type IConfigurationManager interface {
 	ConfigureAgent(folderPath string) error
 	// CreateUpdateAgentConfigWithOnPremIdentity copies the config in the folder to the applicable location to configure the agent
 	CreateUpdateAgentConfigWithOnPremIdentity() error
+	// ConfigureLogging generates seelog.xml from the Logging section of the agent config, if present
+	ConfigureLogging() (bool, error)
 }
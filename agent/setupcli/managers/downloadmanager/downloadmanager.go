@@ -65,10 +65,12 @@ type downloadManager struct {
 	manifestInfo  updatemanifest.T
 	isNano        bool
 	artifactsPath string
+	// skipSignatureValidation trusts the version manifests without verifying their signature and age
+	skipSignatureValidation bool
 }
 
 // New returns a new instance of DownloadManager
-func New(log log.T, region string, manifestURL string, updateInfo updateinfo.T, setupCLIArtifactsPath string, isNano bool) IDownloadManager {
+func New(log log.T, region string, manifestURL string, updateInfo updateinfo.T, setupCLIArtifactsPath string, isNano bool, skipSignatureValidation bool) IDownloadManager {
 	downloadMgrLog := log.WithContext("[DownloadManager]")
 	var err error
 	ctx := context.Default(downloadMgrLog, appconfig.DefaultConfig(), nil)
@@ -94,6 +96,8 @@ func New(log log.T, region string, manifestURL string, updateInfo updateinfo.T,
 		manifestURL:   manifestURL, // field is optional
 		isNano:        isNano,
 		artifactsPath: setupCLIArtifactsPath,
+
+		skipSignatureValidation: skipSignatureValidation,
 	}
 	err = downloadManagerRef.Init()
 	if err != nil {
@@ -112,6 +116,13 @@ func (d *downloadManager) Init() error {
 		return fmt.Errorf("error while downloading manifest: %v", err)
 	}
 
+	// the versions are resolved from the manifest, a tampered or stale manifest must not be loaded
+	if d.skipSignatureValidation {
+		d.log.Warnf("Skipping signature validation of manifest %v", s3Url)
+	} else if err = d.verifyManifest(s3Url, manifestFilePath); err != nil {
+		return err
+	}
+
 	updateManifestObj := updateManifestNew(d.ctx, d.updateInfo, d.region)
 	d.manifestInfo = updateManifestObj
 
@@ -245,6 +256,7 @@ func (d *downloadManager) readVersionFromURL(versionURL string) (string, error)
 	}
 
 	var content string
+	var signature []byte
 	err = backOffRetry(func() error {
 		httpTimeout := 30 * time.Second
 		tr := network.GetDefaultTransport(d.log, appconfig.DefaultConfig())
@@ -252,6 +264,13 @@ func (d *downloadManager) readVersionFromURL(versionURL string) (string, error)
 			Transport: tr,
 			Timeout:   httpTimeout,
 		}
+		if !d.skipSignatureValidation {
+			signatureURL := versionURL + signatureExtension
+			var readErr error
+			if signature, readErr = fileUtilityReadContent(signatureURL, client); readErr != nil {
+				return fmt.Errorf("failed to read response from %s: %v", signatureURL, readErr)
+			}
+		}
 		// use http client to download
 		contentBytes, readErr := fileUtilityReadContent(versionURL, client)
 		if readErr != nil {
@@ -267,6 +286,11 @@ func (d *downloadManager) readVersionFromURL(versionURL string) (string, error)
 	if err != nil {
 		return "", fmt.Errorf("failed to get version from %s: %v", versionURL, err)
 	}
+	if !d.skipSignatureValidation {
+		if err = verifySignatureFunc([]byte(content), signature); err != nil {
+			return "", fmt.Errorf("version file %s: %v", versionURL, err)
+		}
+	}
 	version := strings.TrimSpace(content)
 	if !regexp.MustCompile(`^\d+.\d+.\d+.\d+$`).Match([]byte(version)) {
 		return "", fmt.Errorf("invalid version format returned from %s: %s", versionURL, version)
@@ -47,6 +47,12 @@ func (suite *DownloadManagerTestSuite) SetupTest() {
 	hasLowerKernelVersionFunc = func() bool {
 		return false
 	}
+	fileReadAll = func(name string) ([]byte, error) {
+		return []byte(name), nil
+	}
+	verifySignatureFunc = func(content []byte, signature []byte) error {
+		return nil
+	}
 	logMock := logmocks.NewMockLog()
 	suite.logMock = logMock
 }
@@ -61,7 +67,7 @@ func (suite *DownloadManagerTestSuite) TestDownloadManager_GetStableVersion_Succ
 		updateManifestMock.On("LoadManifest", path).Return(nil).Once()
 		return updateManifestMock
 	}
-	downloadMgr := New(suite.logMock, "us-east-1", "https://s3.amazonaws.com/"+updateconstants.ManifestFile, nil, "path1", true)
+	downloadMgr := New(suite.logMock, "us-east-1", "https://s3.amazonaws.com/"+updateconstants.ManifestFile, nil, "path1", true, false)
 	versionUrl := ""
 	expectedVersionNumber := "3.2.1377.0"
 	expectedStableVersionURL := "https://s3.amazonaws.com/stable/VERSION"
@@ -73,7 +79,7 @@ func (suite *DownloadManagerTestSuite) TestDownloadManager_GetStableVersion_Succ
 	assert.Equal(suite.T(), expectedVersionNumber, versionNum, "mismatched version number")
 	assert.Nil(suite.T(), err, "unexpected error")
 	assert.Equal(suite.T(), expectedStableVersionURL, versionUrl, "mismatched version URL")
-	downloadMgr = New(suite.logMock, "us-east-1", "https://s3.amazonaws.com/"+updateconstants.ManifestFile+" ", nil, path, true)
+	downloadMgr = New(suite.logMock, "us-east-1", "https://s3.amazonaws.com/"+updateconstants.ManifestFile+" ", nil, path, true, false)
 
 	versionUrl = ""
 	expectedVersionNumber = "3.2.1377.0"
@@ -87,7 +93,7 @@ func (suite *DownloadManagerTestSuite) TestDownloadManager_GetStableVersion_Succ
 	assert.Nil(suite.T(), err, "unexpected error")
 	assert.Equal(suite.T(), expectedStableVersionURL, versionUrl, "mismatched version URL")
 
-	downloadMgr = New(suite.logMock, "us-east-1", "", nil, "path1", true)
+	downloadMgr = New(suite.logMock, "us-east-1", "", nil, "path1", true, false)
 	versionUrl = ""
 	expectedVersionNumber = "3.2.1377.0"
 	expectedStableVersionURL = "https://s3.us-east-1.amazonaws.com/amazon-ssm-us-east-1/stable/VERSION"
@@ -111,7 +117,7 @@ func (suite *DownloadManagerTestSuite) TestDownloadManager_GetStableVersion_Fail
 		updateManifestMock.On("LoadManifest", path).Return(nil).Once()
 		return updateManifestMock
 	}
-	downloadMgr := New(suite.logMock, "us-east-1", "https://s3.amazonaws.com/"+updateconstants.ManifestFile, nil, "path1", true)
+	downloadMgr := New(suite.logMock, "us-east-1", "https://s3.amazonaws.com/"+updateconstants.ManifestFile, nil, "path1", true, false)
 	versionUrl := ""
 	expectedStableVersionURL := "https://s3.amazonaws.com/stable/VERSION"
 	fileUtilityReadContent = func(stableVersionUrl string, client *http.Client) ([]byte, error) {
@@ -130,6 +136,36 @@ func (suite *DownloadManagerTestSuite) TestDownloadManager_GetStableVersion_Fail
 	assert.NotNil(suite.T(), err, "should throw error")
 }
 
+func (suite *DownloadManagerTestSuite) TestDownloadManager_GetStableVersion_InvalidSignature() {
+	path := "path1"
+	utilHttpDownload = func(log log.T, fileURL string, destinationPath string) (string, error) {
+		return destinationPath, nil
+	}
+	updateManifestNew = func(context context.T, info updateinfo.T, region string) updatemanifest.T {
+		updateManifestMock := &updatemanifestmocks.T{}
+		updateManifestMock.On("LoadManifest", path).Return(nil).Once()
+		return updateManifestMock
+	}
+	downloadMgr := New(suite.logMock, "us-east-1", "https://s3.amazonaws.com/"+updateconstants.ManifestFile, nil, path, true, false)
+	readURLs := []string{}
+	fileUtilityReadContent = func(url string, client *http.Client) ([]byte, error) {
+		readURLs = append(readURLs, url)
+		return []byte("3.2.1377.0"), nil
+	}
+	verifySignatureFunc = func(content []byte, signature []byte) error {
+		return fmt.Errorf("signature is older than 720h0m0s")
+	}
+
+	versionNum, err := downloadMgr.GetStableVersion()
+	assert.Equal(suite.T(), "", versionNum, "mismatched version number")
+	assert.NotNil(suite.T(), err, "should throw error")
+	assert.Equal(suite.T(), []string{"https://s3.amazonaws.com/stable/VERSION.sig", "https://s3.amazonaws.com/stable/VERSION"}, readURLs)
+
+	// the manifest is not loaded when its signature is invalid
+	assert.Nil(suite.T(), New(suite.logMock, "us-east-1", "https://s3.amazonaws.com/"+updateconstants.ManifestFile, nil, path, true, false))
+	assert.NotNil(suite.T(), New(suite.logMock, "us-east-1", "https://s3.amazonaws.com/"+updateconstants.ManifestFile, nil, path, true, true))
+}
+
 func (suite *DownloadManagerTestSuite) TestDownloadManager_GetLatestVersion_Success() {
 	path := "path1"
 	expectedVersionNumber := "3.2.1377.0"
@@ -143,7 +179,7 @@ func (suite *DownloadManagerTestSuite) TestDownloadManager_GetLatestVersion_Succ
 		updateManifestMock.On("GetLatestActiveVersion", appconfig.DefaultAgentName).Return(expectedVersionNumber, nil).Once()
 		return updateManifestMock
 	}
-	downloadMgr := New(suite.logMock, "us-east-1", "https://s3.amazonaws.com/"+updateconstants.ManifestFile, nil, path, true)
+	downloadMgr := New(suite.logMock, "us-east-1", "https://s3.amazonaws.com/"+updateconstants.ManifestFile, nil, path, true, false)
 	versionNum, err := downloadMgr.GetLatestVersion()
 	assert.Equal(suite.T(), expectedVersionNumber, versionNum, "mismatched version number")
 	assert.Nil(suite.T(), err, "unexpected error")
@@ -160,7 +196,7 @@ func (suite *DownloadManagerTestSuite) TestDownloadManager_GetLatestVersion_Fail
 		updateManifestMock.On("GetLatestActiveVersion", appconfig.DefaultAgentName).Return("", fmt.Errorf("err1")).Once()
 		return updateManifestMock
 	}
-	downloadMgr := New(suite.logMock, "us-east-1", "https://s3.amazonaws.com/"+updateconstants.ManifestFile, nil, "path1", true)
+	downloadMgr := New(suite.logMock, "us-east-1", "https://s3.amazonaws.com/"+updateconstants.ManifestFile, nil, "path1", true, false)
 	versionNum, err := downloadMgr.GetLatestVersion()
 	assert.Equal(suite.T(), "", versionNum, "mismatched version number")
 	assert.NotNil(suite.T(), err, "should throw error")
@@ -185,7 +221,7 @@ func (suite *DownloadManagerTestSuite) TestDownloadManager_DownloadLatestSSMSetu
 		updateManifestMock.On("LoadManifest", path).Return(nil).Once()
 		return updateManifestMock
 	}
-	downloadMgr := New(suite.logMock, "us-east-1", "", info, "path1", true)
+	downloadMgr := New(suite.logMock, "us-east-1", "", info, "path1", true, false)
 	actualSSMSetupCLIURL := ""
 	utilHttpDownload = func(log log.T, fileURL string, destinationPath string) (string, error) {
 		actualSSMSetupCLIURL = fileURL
@@ -215,7 +251,7 @@ func (suite *DownloadManagerTestSuite) TestDownloadManager_DownloadLatestSSMSetu
 		updateManifestMock.On("LoadManifest", path).Return(nil).Once()
 		return updateManifestMock
 	}
-	downloadMgr := New(suite.logMock, "us-east-1", "", info, "path1", true)
+	downloadMgr := New(suite.logMock, "us-east-1", "", info, "path1", true, false)
 	actualSSMSetupCLIURL := ""
 	utilHttpDownload = func(log log.T, fileURL string, destinationPath string) (string, error) {
 		actualSSMSetupCLIURL = fileURL
@@ -248,7 +284,7 @@ func (suite *DownloadManagerTestSuite) TestDownloadManager_DownloadLatestSSMSetu
 		updateManifestMock.On("LoadManifest", path).Return(nil).Once()
 		return updateManifestMock
 	}
-	downloadMgr := New(suite.logMock, "us-east-1", "", info, "path1", true)
+	downloadMgr := New(suite.logMock, "us-east-1", "", info, "path1", true, false)
 	actualSSMSetupCLIURL := ""
 	utilHttpDownload = func(log log.T, fileURL string, destinationPath string) (string, error) {
 		actualSSMSetupCLIURL = fileURL
@@ -284,7 +320,7 @@ func (suite *DownloadManagerTestSuite) TestDownloadManager_DownloadArtifacts_Suc
 		updateManifestMock.On("GetDownloadURLAndHash", appconfig.DefaultAgentName, version).Return(expectedLatestSSMSetupCLIURL, checkSum, nil).Once()
 		return updateManifestMock
 	}
-	downloadMgr := New(suite.logMock, "us-east-1", "", info, path, true)
+	downloadMgr := New(suite.logMock, "us-east-1", "", info, path, true, false)
 	actualSSMSetupCLIURL := ""
 
 	utilHttpDownload = func(log log.T, fileURL string, destinationPath string) (string, error) {
@@ -0,0 +1,96 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package downloadmanager
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/aws/amazon-ssm-agent/agent/setupcli/managers/verificationmanagers"
+)
+
+const (
+	// signatureExtension is the suffix of the detached signatures published next to the version manifests
+	signatureExtension = ".sig"
+	// manifestMaxAge is the age of the signature above which a version manifest is stale,
+	// the manifests are signed again with every release and at least weekly
+	manifestMaxAge = 30 * 24 * time.Hour
+	// maxClockSkew tolerates signatures created slightly ahead of the local clock
+	maxClockSkew = 15 * time.Minute
+)
+
+var (
+	getSigningPublicKey = verificationmanagers.GetLinuxPublicKey
+	fileReadAll         = os.ReadFile
+	timeNow             = time.Now
+	verifySignatureFunc = verifySignature
+)
+
+// verifyManifest downloads the detached signature of the manifest downloaded from manifestURL and verifies it
+func (d *downloadManager) verifyManifest(manifestURL string, manifestPath string) error {
+	signaturePath, err := utilHttpDownload(d.log, manifestURL+signatureExtension, d.artifactsPath)
+	if err != nil || signaturePath == "" {
+		return fmt.Errorf("error while downloading manifest signature: %v", err)
+	}
+	manifest, err := fileReadAll(manifestPath)
+	if err != nil {
+		return fmt.Errorf("failed to read manifest: %v", err)
+	}
+	signature, err := fileReadAll(signaturePath)
+	if err != nil {
+		return fmt.Errorf("failed to read manifest signature: %v", err)
+	}
+	if err = verifySignatureFunc(manifest, signature); err != nil {
+		return fmt.Errorf("manifest %v: %v", manifestURL, err)
+	}
+	d.log.Infof("Verified signature of manifest %v", manifestURL)
+	return nil
+}
+
+// verifySignature verifies the binary or armored detached signature of the content with the agent signing key.
+// Signatures older than manifestMaxAge are rejected so a stale mirror can not pin the version.
+func verifySignature(content []byte, signature []byte) error {
+	keyring, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(getSigningPublicKey()))
+	if err != nil {
+		return fmt.Errorf("failed to read signing key: %v", err)
+	}
+
+	var signatureReader io.Reader = bytes.NewReader(signature)
+	if bytes.HasPrefix(bytes.TrimSpace(signature), []byte("-----BEGIN")) {
+		block, err := armor.Decode(bytes.NewReader(signature))
+		if err != nil {
+			return fmt.Errorf("invalid signature: %v", err)
+		}
+		signatureReader = block.Body
+	}
+
+	now := timeNow()
+	sig, _, err := openpgp.VerifyDetachedSignature(keyring, bytes.NewReader(content), signatureReader, &packet.Config{Time: func() time.Time { return now }})
+	if err != nil {
+		return fmt.Errorf("signature verification failed: %v", err)
+	}
+	if sig.CreationTime.After(now.Add(maxClockSkew)) {
+		return fmt.Errorf("signature is created in the future at %v", sig.CreationTime.UTC())
+	}
+	if age := now.Sub(sig.CreationTime); age > manifestMaxAge {
+		return fmt.Errorf("signature created at %v is older than %v", sig.CreationTime.UTC(), manifestMaxAge)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package downloadmanager
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	logmocks "github.com/aws/amazon-ssm-agent/agent/mocks/log"
+	"github.com/aws/amazon-ssm-agent/agent/setupcli/managers/verificationmanagers"
+	"github.com/stretchr/testify/assert"
+)
+
+var signingTime = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// newSigningKey replaces the agent signing key with a generated key for the duration of the test
+func newSigningKey(t *testing.T) *openpgp.Entity {
+	config := &packet.Config{Algorithm: packet.PubKeyAlgoEdDSA, Time: func() time.Time { return signingTime }}
+	entity, err := openpgp.NewEntity("SSM Agent", "", "ssm-agent-signer@amazon.com", config)
+	assert.NoError(t, err)
+
+	var publicKey bytes.Buffer
+	writer, err := armor.Encode(&publicKey, openpgp.PublicKeyType, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, entity.Serialize(writer))
+	assert.NoError(t, writer.Close())
+
+	getSigningPublicKey = func() []byte { return publicKey.Bytes() }
+	t.Cleanup(func() {
+		getSigningPublicKey = verificationmanagers.GetLinuxPublicKey
+		timeNow = time.Now
+	})
+	return entity
+}
+
+func sign(t *testing.T, entity *openpgp.Entity, content []byte, armored bool, at time.Time) []byte {
+	var signature bytes.Buffer
+	config := &packet.Config{Time: func() time.Time { return at }}
+	if armored {
+		assert.NoError(t, openpgp.ArmoredDetachSign(&signature, entity, bytes.NewReader(content), config))
+	} else {
+		assert.NoError(t, openpgp.DetachSign(&signature, entity, bytes.NewReader(content), config))
+	}
+	return signature.Bytes()
+}
+
+func TestVerifySignature(t *testing.T) {
+	entity := newSigningKey(t)
+	manifest := []byte(`{"SchemaVersion":"2.0"}`)
+	timeNow = func() time.Time { return signingTime.Add(24 * time.Hour) }
+
+	assert.NoError(t, verifySignature(manifest, sign(t, entity, manifest, false, signingTime)))
+	assert.NoError(t, verifySignature(manifest, sign(t, entity, manifest, true, signingTime)))
+}
+
+func TestVerifySignature_Tampered(t *testing.T) {
+	entity := newSigningKey(t)
+	manifest := []byte(`{"SchemaVersion":"2.0"}`)
+	timeNow = func() time.Time { return signingTime }
+
+	signature := sign(t, entity, manifest, false, signingTime)
+	assert.Error(t, verifySignature([]byte(`{"SchemaVersion":"2.1"}`), signature))
+	assert.Error(t, verifySignature(manifest, nil))
+	assert.Error(t, verifySignature(manifest, []byte("-----BEGIN PGP SIGNATURE-----\ninvalid")))
+
+	// signed with another key
+	newSigningKey(t)
+	assert.Error(t, verifySignature(manifest, signature))
+}
+
+func TestVerifySignature_Stale(t *testing.T) {
+	entity := newSigningKey(t)
+	manifest := []byte(`{"SchemaVersion":"2.0"}`)
+	signature := sign(t, entity, manifest, false, signingTime)
+
+	timeNow = func() time.Time { return signingTime.Add(manifestMaxAge + time.Hour) }
+	err := verifySignature(manifest, signature)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "is older than")
+
+	timeNow = func() time.Time { return signingTime.Add(-time.Hour) }
+	assert.Error(t, verifySignature(manifest, signature), "signature from the future")
+}
+
+func TestVerifyManifest(t *testing.T) {
+	originalDownload, originalReadAll := utilHttpDownload, fileReadAll
+	defer func() {
+		utilHttpDownload, fileReadAll, verifySignatureFunc = originalDownload, originalReadAll, verifySignature
+	}()
+	d := &downloadManager{log: logmocks.NewMockLog(), artifactsPath: "artifacts"}
+	downloaded := ""
+	utilHttpDownload = func(log log.T, fileURL string, destinationPath string) (string, error) {
+		downloaded = fileURL
+		return "manifest.sig", nil
+	}
+	fileReadAll = func(name string) ([]byte, error) {
+		return []byte(name), nil
+	}
+	verifySignatureFunc = func(content []byte, signature []byte) error {
+		assert.Equal(t, "manifest.json", string(content))
+		assert.Equal(t, "manifest.sig", string(signature))
+		return nil
+	}
+	assert.NoError(t, d.verifyManifest("https://bucket/manifest.json", "manifest.json"))
+	assert.Equal(t, "https://bucket/manifest.json.sig", downloaded)
+
+	verifySignatureFunc = func(content []byte, signature []byte) error {
+		return fmt.Errorf("signature verification failed")
+	}
+	assert.Error(t, d.verifyManifest("https://bucket/manifest.json", "manifest.json"))
+
+	utilHttpDownload = func(log log.T, fileURL string, destinationPath string) (string, error) {
+		return "", fmt.Errorf("not found")
+	}
+	assert.Error(t, d.verifyManifest("https://bucket/manifest.json", "manifest.json"), "fails closed without signature")
+}
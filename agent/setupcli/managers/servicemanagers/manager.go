@@ -15,9 +15,11 @@
 package servicemanagers
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/session/connectionhealth"
 	"github.com/aws/amazon-ssm-agent/agent/setupcli/managers/common"
 )
 
@@ -36,6 +38,12 @@ const (
 
 var serviceManagers = map[ServiceManager]IServiceManager{}
 
+// hardenableServiceManager is implemented by the service managers that can harden the agent service
+type hardenableServiceManager interface {
+	// ApplyHardening restricts the agent service and reloads the service manager configuration
+	ApplyHardening() error
+}
+
 func registerServiceManager(managerType ServiceManager, manager IServiceManager) {
 	serviceManagers[managerType] = manager
 }
@@ -71,27 +79,39 @@ func StopAgent(manager IServiceManager, log log.T) error {
 	return fmt.Errorf("retries exhausted")
 }
 
-// StartAgent attempts to start the agent and verifies it is running using retries
-func StartAgent(manager IServiceManager, log log.T) error {
-	var status common.AgentStatus
+// StartAgent attempts to start the agent and waits for it to become ready using retries.
+// The start is not retried once the service runs but the agent does not open the control channel.
+func StartAgent(manager IServiceManager, log log.T, readiness ReadinessConfig) error {
 	var err error
 	log.Infof("Starting agent using %s service manager", manager.GetName())
 	for i := 1; i <= numRetries; i++ {
+		startTime := timeNow()
 		if err = manager.StartAgent(); err != nil {
 			log.Warnf("attempt %v/%v failed to start agent: %v", i, numRetries, err)
 			continue
 		}
 
-		if status, err = manager.GetAgentStatus(); err != nil {
-			log.Warnf("attempt %v/%v failed to get agent status after starting: %v", i, numRetries, err)
-			continue
-		} else if status == common.Running {
-			log.Info("Agent is running")
+		if err = WaitForAgentReady(manager, log, startTime, readiness); err == nil {
+			log.Info("Agent is ready")
 			return nil
+		} else if errors.Is(err, connectionhealth.ErrNotConnected) {
+			return fmt.Errorf("agent is running but not ready: %v", err)
 		}
 
-		log.Infof("attempt %v/%v: agent status was %v when expected status was %v", i, numRetries, status, common.Running)
+		log.Infof("attempt %v/%v: %v", i, numRetries, err)
 	}
 
 	return fmt.Errorf("retries exhausted")
 }
+
+// ApplyHardening applies the hardening options of the service manager to the agent service.
+// The options take effect the next time the agent is started.
+func ApplyHardening(manager IServiceManager, log log.T) error {
+	hardenable, ok := manager.(hardenableServiceManager)
+	if !ok {
+		return fmt.Errorf("%s service manager does not support hardening", manager.GetName())
+	}
+
+	log.Infof("Hardening agent service using %s service manager", manager.GetName())
+	return hardenable.ApplyHardening()
+}
@@ -67,7 +67,7 @@ func TestStartAgent_ErrorStartAgent(t *testing.T) {
 
 	managerMock.On("GetName").Return("MockName").Once()
 	managerMock.On("StartAgent").Return(fmt.Errorf("FailedStartAgent")).Times(4)
-	err := servicemanagers.StartAgent(managerMock, logger)
+	err := servicemanagers.StartAgent(managerMock, logger, servicemanagers.ReadinessConfig{})
 	assert.Error(t, err)
 	assert.Contains(t, "retries exhausted", err.Error())
 }
@@ -78,7 +78,7 @@ func TestStartAgent_ErrorVerifyStartAgent(t *testing.T) {
 	managerMock.On("GetName").Return("MockName").Once()
 	managerMock.On("StartAgent").Return(nil).Times(4)
 	managerMock.On("GetAgentStatus").Return(common.UndefinedStatus, fmt.Errorf("FailedGetStatus")).Times(4)
-	err := servicemanagers.StartAgent(managerMock, logger)
+	err := servicemanagers.StartAgent(managerMock, logger, servicemanagers.ReadinessConfig{})
 	assert.Error(t, err)
 	assert.Contains(t, "retries exhausted", err.Error())
 }
@@ -93,6 +93,15 @@ func TestStartAgent_ErrorStartAgentOnce_ErrorVerifyStartAgentOnce_Success(t *tes
 	managerMock.On("GetAgentStatus").Return(common.UndefinedStatus, fmt.Errorf("FailedGetStatus")).Once()
 	managerMock.On("GetAgentStatus").Return(common.Running, nil).Once()
 
-	err := servicemanagers.StartAgent(managerMock, logger)
+	err := servicemanagers.StartAgent(managerMock, logger, servicemanagers.ReadinessConfig{})
 	assert.NoError(t, err)
 }
+
+func TestApplyHardening_NotSupported(t *testing.T) {
+	managerMock := &mocks.IServiceManager{}
+
+	managerMock.On("GetName").Return("MockName")
+	err := servicemanagers.ApplyHardening(managerMock, logger)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "does not support hardening")
+}
@@ -0,0 +1,89 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package servicemanagers contains functions related to service manager
+package servicemanagers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/session/connectionhealth"
+	"github.com/aws/amazon-ssm-agent/agent/setupcli/managers/common"
+)
+
+const (
+	defaultReadinessTimeout      = 30 * time.Second
+	defaultReadinessPollInterval = 2 * time.Second
+)
+
+// The main purpose of these delegates is to easily test the readiness probes
+var (
+	controlChannelConnectedSince = connectionhealth.ConnectedSince
+	timeNow                      = time.Now
+	timeSleep                    = time.Sleep
+)
+
+// ReadinessConfig describes when a started agent is considered functional
+type ReadinessConfig struct {
+	// RequireControlChannel requires the agent to open the control channel in addition to the service running
+	RequireControlChannel bool
+	// Timeout is how long to wait for the agent to become ready, zero checks the readiness once
+	Timeout time.Duration
+	// PollInterval is the time between two readiness checks
+	PollInterval time.Duration
+}
+
+// DefaultReadinessConfig returns the readiness config that only requires the agent service to run
+func DefaultReadinessConfig() ReadinessConfig {
+	return ReadinessConfig{
+		Timeout:      defaultReadinessTimeout,
+		PollInterval: defaultReadinessPollInterval,
+	}
+}
+
+// WaitForAgentReady polls the readiness probes until they all pass or the timeout expires.
+// The control channel has to be opened after since, the agent status reported by an earlier agent process does not count.
+func WaitForAgentReady(manager IServiceManager, log log.T, since time.Time, config ReadinessConfig) error {
+	deadline := timeNow().Add(config.Timeout)
+	for {
+		err := checkAgentReady(manager, since, config)
+		if err == nil {
+			return nil
+		}
+		if !timeNow().Before(deadline) {
+			return err
+		}
+		log.Debugf("Agent is not ready yet: %v", err)
+		timeSleep(config.PollInterval)
+	}
+}
+
+// checkAgentReady runs the readiness probes once
+func checkAgentReady(manager IServiceManager, since time.Time, config ReadinessConfig) error {
+	status, err := manager.GetAgentStatus()
+	if err != nil {
+		return fmt.Errorf("failed to get agent status: %v", err)
+	}
+	if status != common.Running {
+		return fmt.Errorf("agent status was %v when expected status was %v", status, common.Running)
+	}
+
+	if config.RequireControlChannel {
+		if err = controlChannelConnectedSince(since); err != nil {
+			return err
+		}
+	}
+	return nil
+}
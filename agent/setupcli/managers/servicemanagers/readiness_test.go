@@ -0,0 +1,108 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package servicemanagers contains functions related to service manager
+package servicemanagers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/mocks/log"
+	"github.com/aws/amazon-ssm-agent/agent/session/connectionhealth"
+	"github.com/aws/amazon-ssm-agent/agent/setupcli/managers/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeServiceManager reports the statuses in order, the last status is repeated
+type fakeServiceManager struct {
+	statuses []common.AgentStatus
+	starts   int
+}
+
+func (f *fakeServiceManager) StartAgent() error          { f.starts++; return nil }
+func (f *fakeServiceManager) StopAgent() error           { return nil }
+func (f *fakeServiceManager) ReloadManager() error       { return nil }
+func (f *fakeServiceManager) IsManagerEnvironment() bool { return true }
+func (f *fakeServiceManager) GetName() string            { return "fake" }
+func (f *fakeServiceManager) GetType() ServiceManager    { return Undefined }
+func (f *fakeServiceManager) GetAgentStatus() (common.AgentStatus, error) {
+	status := f.statuses[0]
+	if len(f.statuses) > 1 {
+		f.statuses = f.statuses[1:]
+	}
+	return status, nil
+}
+
+// stubReadinessClock advances a fake clock by every sleep
+func stubReadinessClock(t *testing.T, connectedSince func(time.Time) error) {
+	connectedSinceOrig, timeNowOrig, timeSleepOrig := controlChannelConnectedSince, timeNow, timeSleep
+	t.Cleanup(func() {
+		controlChannelConnectedSince, timeNow, timeSleep = connectedSinceOrig, timeNowOrig, timeSleepOrig
+	})
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	timeSleep = func(d time.Duration) { now = now.Add(d) }
+	controlChannelConnectedSince = connectedSince
+}
+
+func TestWaitForAgentReady_ServiceOnly(t *testing.T) {
+	stubReadinessClock(t, func(time.Time) error { return connectionhealth.ErrNotConnected })
+	manager := &fakeServiceManager{statuses: []common.AgentStatus{common.Stopped, common.Stopped, common.Running}}
+
+	err := WaitForAgentReady(manager, log.NewMockLog(), timeNow(), DefaultReadinessConfig())
+	assert.NoError(t, err)
+}
+
+func TestWaitForAgentReady_Timeout(t *testing.T) {
+	stubReadinessClock(t, nil)
+	manager := &fakeServiceManager{statuses: []common.AgentStatus{common.Stopped}}
+	start := timeNow()
+
+	err := WaitForAgentReady(manager, log.NewMockLog(), start, DefaultReadinessConfig())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "expected status was Running")
+	assert.Equal(t, start.Add(defaultReadinessTimeout), timeNow())
+}
+
+func TestWaitForAgentReady_ControlChannel(t *testing.T) {
+	checks := 0
+	stubReadinessClock(t, func(since time.Time) error {
+		checks++
+		if checks < 3 {
+			return connectionhealth.ErrNotConnected
+		}
+		return nil
+	})
+	manager := &fakeServiceManager{statuses: []common.AgentStatus{common.Running}}
+	config := DefaultReadinessConfig()
+	config.RequireControlChannel = true
+
+	assert.NoError(t, WaitForAgentReady(manager, log.NewMockLog(), timeNow(), config))
+	assert.Equal(t, 3, checks)
+}
+
+func TestStartAgent_ControlChannelNotConnected(t *testing.T) {
+	stubReadinessClock(t, func(time.Time) error {
+		return fmt.Errorf("%w: handshake timed out", connectionhealth.ErrNotConnected)
+	})
+	manager := &fakeServiceManager{statuses: []common.AgentStatus{common.Running}}
+	config := DefaultReadinessConfig()
+	config.RequireControlChannel = true
+
+	err := StartAgent(manager, log.NewMockLog(), config)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "handshake timed out")
+	assert.Equal(t, 1, manager.starts)
+}
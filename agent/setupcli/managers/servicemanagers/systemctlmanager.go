@@ -19,10 +19,34 @@ package servicemanagers
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 
 	"github.com/aws/amazon-ssm-agent/agent/setupcli/managers/common"
 )
 
+const hardeningDropInFileName = "hardening.conf"
+
+// hardeningDropIn makes /usr, /boot and /etc read-only for the agent and the commands it runs, except for the directories
+// installed by the agent package so that agent updates keep working. It also denies changes to kernel tunables, kernel
+// modules and cgroups. Patching and installing system packages through the agent is not possible in a hardened service,
+// and worker resource limits, which need to create cgroups, are not applied.
+const hardeningDropIn = `# Written by ssm-setup-cli -hardened.
+# /usr, /boot and /etc are read-only for the agent and the commands it runs, patching and package installs through the agent fail.
+# Remove this file and run 'systemctl daemon-reload' to revert.
+[Service]
+PrivateTmp=yes
+ProtectSystem=full
+ReadWritePaths=/etc/amazon/ssm /usr/bin -/etc/init -/etc/systemd/system
+ProtectKernelTunables=yes
+ProtectKernelModules=yes
+ProtectControlGroups=yes
+LockPersonality=yes
+RestrictRealtime=yes
+`
+
+var systemdDropInRoot = "/etc/systemd/system"
+
 type systemCtlManager struct {
 	managerHelper common.IManagerHelper
 	serviceName   string
@@ -88,6 +112,21 @@ func (m *systemCtlManager) ReloadManager() error {
 	return nil
 }
 
+// ApplyHardening writes a drop-in with the hardening directives for the agent unit and reloads systemd
+func (m *systemCtlManager) ApplyHardening() error {
+	dropInDir := filepath.Join(systemdDropInRoot, m.serviceName+".d")
+	if err := os.MkdirAll(dropInDir, 0755); err != nil {
+		return fmt.Errorf("systemctl hardening: Failed to create drop-in directory '%s': %v", dropInDir, err)
+	}
+
+	dropInPath := filepath.Join(dropInDir, hardeningDropInFileName)
+	if err := os.WriteFile(dropInPath, []byte(hardeningDropIn), 0644); err != nil {
+		return fmt.Errorf("systemctl hardening: Failed to write drop-in '%s': %v", dropInPath, err)
+	}
+
+	return m.ReloadManager()
+}
+
 func (m *systemCtlManager) IsManagerEnvironment() bool {
 	for _, cmd := range m.dependentBinaries {
 		if !m.managerHelper.IsCommandAvailable(cmd) {
@@ -19,6 +19,9 @@ package servicemanagers
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/aws/amazon-ssm-agent/agent/setupcli/managers/common"
@@ -119,6 +122,38 @@ func TestSystemctlManager_ReloadManager(t *testing.T) {
 	assert.NoError(t, u.ReloadManager())
 }
 
+func TestSystemctlManager_ApplyHardening(t *testing.T) {
+	originalDropInRoot := systemdDropInRoot
+	defer func() { systemdDropInRoot = originalDropInRoot }()
+	systemdDropInRoot = t.TempDir()
+	u, helperMock := createSystemCtlManager()
+
+	helperMock.On("RunCommand", "systemctl", "daemon-reload").Return("success", nil).Once()
+	assert.NoError(t, u.ApplyHardening())
+
+	content, err := os.ReadFile(filepath.Join(systemdDropInRoot, u.serviceName+".d", hardeningDropInFileName))
+	assert.NoError(t, err)
+	assert.Equal(t, hardeningDropIn, string(content))
+	var directives []string
+	for _, line := range strings.Split(string(content), "\n") {
+		if line != "" && !strings.HasPrefix(line, "#") {
+			directives = append(directives, line)
+		}
+	}
+	assert.Equal(t, []string{
+		"[Service]",
+		"PrivateTmp=yes",
+		"ProtectSystem=full",
+		"ReadWritePaths=/etc/amazon/ssm /usr/bin -/etc/init -/etc/systemd/system",
+		"ProtectKernelTunables=yes",
+		"ProtectKernelModules=yes",
+		"ProtectControlGroups=yes",
+		"LockPersonality=yes",
+		"RestrictRealtime=yes",
+	}, directives)
+	helperMock.AssertExpectations(t)
+}
+
 func TestSystemctlManager_IsManagerEnvironment(t *testing.T) {
 	u, helperMock := createSystemCtlManager()
 
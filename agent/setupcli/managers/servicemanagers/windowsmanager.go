@@ -22,6 +22,7 @@ import (
 	"os/exec"
 	"strings"
 
+	"github.com/aws/amazon-ssm-agent/agent/servicerecovery"
 	"github.com/aws/amazon-ssm-agent/agent/setupcli/managers/common"
 	"golang.org/x/sys/windows/svc"
 	"golang.org/x/sys/windows/svc/mgr"
@@ -32,12 +33,17 @@ const (
 	serviceName = "AmazonSSMAgent"
 )
 
+var configureServiceRecovery = servicerecovery.ConfigureRecoveryActions
+
 type windowsManager struct {
 	managerHelper common.IManagerHelper
 }
 
 // StartAgent starts the agent
 func (m *windowsManager) StartAgent() error {
+	// best effort, the agent configures the recovery actions again when the service starts
+	_ = configureServiceRecovery(serviceName)
+
 	output, err := m.managerHelper.RunCommand(netExecPath, "start", serviceName)
 	if err != nil {
 		if exitError, ok := err.(*exec.ExitError); ok {
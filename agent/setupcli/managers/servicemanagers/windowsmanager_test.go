@@ -25,6 +25,10 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+func init() {
+	configureServiceRecovery = func(string) error { return nil }
+}
+
 func TestWindowsManager_StartAgent_Success(t *testing.T) {
 	helperMock := &mhMock.IManagerHelper{}
 	helperMock.On("RunCommand", netExecPath, "start", serviceName).Return("", nil)
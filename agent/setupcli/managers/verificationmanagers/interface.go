@@ -19,4 +19,7 @@ import "github.com/aws/amazon-ssm-agent/agent/log"
 type IVerificationManager interface {
 	// VerifySignature verifies the agent binary signature
 	VerifySignature(log log.T, signaturePath string, artifactsPath string, fileExtension string) error
+	// VerifyDetachedSignature verifies the detached signature of an artifact such as a zip or tar archive.
+	// The signature is expected next to the artifact with a .sig suffix when signaturePath is empty.
+	VerifyDetachedSignature(log log.T, signaturePath string, artifactPath string) error
 }
@@ -11,13 +11,10 @@
 // either express or implied. See the License for the specific language governing
 // permissions and limitations under the License.
 
-//go:build freebsd || linux || netbsd || openbsd
-// +build freebsd linux netbsd openbsd
-
 // Package verificationmanagers is used to verify the agent packages
 package verificationmanagers
 
-// GetLinuxPublicKey returns the public key used to verify agent linux package
+// GetLinuxPublicKey returns the public key used to verify agent linux package and the version manifests on all platforms
 func GetLinuxPublicKey() []byte {
 	// public key similar to our public documentation
 	// https://docs.aws.amazon.com/systems-manager/latest/userguide/verify-agent-signature.html
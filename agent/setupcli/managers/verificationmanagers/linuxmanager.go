@@ -46,50 +46,64 @@ func (l *linuxManager) createPublicKeyFile(publicKeyPath string) error {
 // VerifySignature verifies the agent binary signature
 func (l *linuxManager) VerifySignature(log log.T, signaturePath string, artifactsPath string, fileExtension string) error {
 	binaryPath := filepath.Join(artifactsPath, appconfig.DefaultAgentName+fileExtension)
+	return l.VerifyDetachedSignature(log, signaturePath, binaryPath)
+}
+
+// VerifyDetachedSignature verifies the detached signature of an artifact such as a zip or tar archive
+func (l *linuxManager) VerifyDetachedSignature(log log.T, signaturePath string, artifactPath string) error {
+	if signaturePath == "" {
+		signaturePath = artifactPath + signatureExtension
+	}
+
+	keyringPath, err := l.createTrustStore(log, filepath.Dir(artifactPath))
+	if err != nil {
+		return err
+	}
 
+	log.Infof("Verifying signature of %s", artifactPath)
+	output, err := l.managerHelper.RunCommand("gpg", "--no-default-keyring", "--keyring", keyringPath, "--verify", signaturePath, artifactPath)
+	if err != nil {
+		if l.managerHelper.IsTimeoutError(err) {
+			return fmt.Errorf("gpg verify: command timed out")
+		}
+		return fmt.Errorf("gpg verify: failed to verify signature using gpg with output '%v' and error: %v", output, err)
+	}
+	goodSignatureText := "Good signature from \"SSM Agent <ssm-agent-signer@amazon.com>\""
+	if !strings.Contains(output, goodSignatureText) {
+		return fmt.Errorf("signature verification failed %v", output)
+	}
+	log.Infof("Successfully verified signature")
+	return nil
+}
+
+// createTrustStore imports the agent public key into a keyring created in the given directory
+func (l *linuxManager) createTrustStore(log log.T, directory string) (keyringPath string, err error) {
 	gpgExtension := ".gpg"
-	amazonSSMAgentGPGKey := filepath.Join(artifactsPath, appconfig.DefaultAgentName+gpgExtension)
+	amazonSSMAgentGPGKey := filepath.Join(directory, appconfig.DefaultAgentName+gpgExtension)
 
 	//create public key file
 	log.Infof("Creating public key file at: %s", amazonSSMAgentGPGKey)
-	if err := l.createPublicKeyFile(amazonSSMAgentGPGKey); err != nil {
-		return fmt.Errorf("failed to create amazon-ssm-agent.gpg file: %v", err)
+	if err = l.createPublicKeyFile(amazonSSMAgentGPGKey); err != nil {
+		return "", fmt.Errorf("failed to create amazon-ssm-agent.gpg file: %v", err)
 	}
 
 	//check to see if gpg is installed
 	log.Infof("Checking to see if gpg is installed")
 	if !l.managerHelper.IsCommandAvailable("gpg") {
-		return fmt.Errorf("gpg is not installed. Please install gpg to validate the signature of binaries or pass -skip-signature-validation flag")
+		return "", fmt.Errorf("gpg is not installed. Please install gpg to validate the signature of binaries or pass -skip-signature-validation flag")
 	}
 
 	tempKeyRing := "keyring"
-	keyringPath := filepath.Join(artifactsPath, tempKeyRing)
-	err := fileUtilMakeDirs(keyringPath)
-	if err != nil {
-		return fmt.Errorf("keyring directory creation failed: %v", err)
+	keyringPath = filepath.Join(directory, tempKeyRing)
+	if err = fileUtilMakeDirs(keyringPath); err != nil {
+		return "", fmt.Errorf("keyring directory creation failed: %v", err)
 	}
 
 	log.Debugf("Importing public key: gpg --import %s", amazonSSMAgentGPGKey)
 	output, err := l.managerHelper.RunCommand("gpg", "--no-default-keyring", "--keyring", keyringPath, "--import", amazonSSMAgentGPGKey)
-	if err != nil {
-		if l.managerHelper.IsTimeoutError(err) {
-			return fmt.Errorf("gpg command timed out")
-		}
+	if err != nil && l.managerHelper.IsTimeoutError(err) {
+		return "", fmt.Errorf("gpg command timed out")
 	}
 	log.Infof("Successfully imported keyring: %v", output)
-
-	log.Info("Verifying agent signature")
-	output, err = l.managerHelper.RunCommand("gpg", "--no-default-keyring", "--keyring", keyringPath, "--verify", signaturePath, binaryPath)
-	if err != nil {
-		if l.managerHelper.IsTimeoutError(err) {
-			return fmt.Errorf("gpg verify: command timed out")
-		}
-		return fmt.Errorf("gpg verify: failed to verify signature using gpg with output '%v' and error: %v", output, err)
-	}
-	goodSignatureText := "Good signature from \"SSM Agent <ssm-agent-signer@amazon.com>\""
-	if !strings.Contains(output, goodSignatureText) {
-		return fmt.Errorf("signature verification failed %v", output)
-	}
-	log.Infof("Successfully verified signature")
-	return nil
+	return keyringPath, nil
 }
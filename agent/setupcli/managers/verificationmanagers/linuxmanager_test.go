@@ -100,6 +100,47 @@ func (suite *VerificationManagerLinuxTestSuite) TestVerificationManager_Failure(
 	mgrHelper.AssertExpectations(suite.T())
 }
 
+// Test function for Verification Manager - detached signature of a tarball next to the artifact
+func (suite *VerificationManagerLinuxTestSuite) TestVerificationManager_DetachedSignature_Success() {
+	artifactsPath := "temp2"
+	artifactPath := filepath.Join(artifactsPath, "amazon-ssm-agent.tar.gz")
+	keyringPath := filepath.Join(artifactsPath, "keyring")
+
+	mgrHelper := &mhMock.IManagerHelper{}
+	fileUtilMakeDirs = func(destinationDir string) (err error) {
+		return nil
+	}
+	ioWriteUtil = func(filename string, data []byte, perm fs.FileMode) error {
+		return nil
+	}
+	amazonSSMAgentGPGKey := filepath.Join(artifactsPath, appconfig.DefaultAgentName+".gpg")
+	mgrHelper.On("IsCommandAvailable", "gpg").Return(true)
+	mgrHelper.On("RunCommand", "gpg", "--no-default-keyring", "--keyring", keyringPath, "--import", amazonSSMAgentGPGKey).Return("status: accepted sample output", nil).Once()
+	mgrHelper.On("RunCommand", "gpg", "--no-default-keyring", "--keyring", keyringPath, "--verify", artifactPath+".sig", artifactPath).Return("Good signature from \"SSM Agent <ssm-agent-signer@amazon.com>\"", nil).Once()
+
+	pkgManagerRef := linuxManager{managerHelper: mgrHelper}
+	err := pkgManagerRef.VerifyDetachedSignature(suite.logMock, "", artifactPath)
+
+	assert.Nil(suite.T(), err)
+	mgrHelper.AssertExpectations(suite.T())
+}
+
+// Test function for Verification Manager - detached signature verification without gpg
+func (suite *VerificationManagerLinuxTestSuite) TestVerificationManager_DetachedSignature_NoGpg() {
+	mgrHelper := &mhMock.IManagerHelper{}
+	ioWriteUtil = func(filename string, data []byte, perm fs.FileMode) error {
+		return nil
+	}
+	mgrHelper.On("IsCommandAvailable", "gpg").Return(false)
+
+	pkgManagerRef := linuxManager{managerHelper: mgrHelper}
+	err := pkgManagerRef.VerifyDetachedSignature(suite.logMock, "sig1", filepath.Join("temp2", "amazon-ssm-agent.zip"))
+
+	assert.NotNil(suite.T(), err)
+	assert.Contains(suite.T(), err.Error(), "gpg is not installed")
+	mgrHelper.AssertExpectations(suite.T())
+}
+
 func TestVerificationManagerLinuxTestSuite(t *testing.T) {
 	suite.Run(t, new(VerificationManagerLinuxTestSuite))
 }
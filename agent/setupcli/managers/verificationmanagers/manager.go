@@ -14,6 +14,9 @@
 // Package verificationmanagers is used to verify the agent packages
 package verificationmanagers
 
+// signatureExtension is the suffix of detached signature files
+const signatureExtension = ".sig"
+
 // VerificationManager denotes the type of verification manager
 type VerificationManager int
 
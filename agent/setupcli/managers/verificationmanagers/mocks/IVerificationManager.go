@@ -25,3 +25,17 @@ func (_m *IVerificationManager) VerifySignature(_a0 log.T, signaturePath string,
 
 	return r0
 }
+
+// VerifyDetachedSignature provides a mock function with given fields: _a0, signaturePath, artifactPath
+func (_m *IVerificationManager) VerifyDetachedSignature(_a0 log.T, signaturePath string, artifactPath string) error {
+	ret := _m.Called(_a0, signaturePath, artifactPath)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(log.T, string, string) error); ok {
+		r0 = rf(_a0, signaturePath, artifactPath)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
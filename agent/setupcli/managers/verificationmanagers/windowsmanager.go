@@ -78,6 +78,11 @@ func (w *windowsManager) VerifySignature(log log.T, signaturePath string, artifa
 	return fmt.Errorf("invalid signing: output - %v, err - %v", output, err)
 }
 
+// VerifyDetachedSignature is not supported on windows, the installers carry an Authenticode signature instead
+func (w *windowsManager) VerifyDetachedSignature(log log.T, signaturePath string, artifactPath string) error {
+	return fmt.Errorf("detached signature verification is not supported on windows")
+}
+
 func parseSubjectName(subjectName string) string {
 	commonNamePrefix := "CN="
 	for _, val := range strings.Split(subjectName, ", ") {
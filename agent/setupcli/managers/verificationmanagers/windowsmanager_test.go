@@ -114,6 +114,15 @@ func (suite *VerificationManagerWindowsTestSuite) TestVerificationManager_Failur
 	mgrHelper.AssertExpectations(suite.T())
 }
 
+// Test function for Verification Manager - detached signatures are not supported
+func (suite *VerificationManagerWindowsTestSuite) TestVerificationManager_DetachedSignature_NotSupported() {
+	pkgManagerRef := windowsManager{managerHelper: &mhMock.IManagerHelper{}}
+	err := pkgManagerRef.VerifyDetachedSignature(suite.logMock, "", filepath.Join("temp1", "amazon-ssm-agent.zip"))
+
+	assert.NotNil(suite.T(), err)
+	assert.Contains(suite.T(), err.Error(), "not supported")
+}
+
 func TestVerificationManagerWindowsTestSuite(t *testing.T) {
 	suite.Run(t, new(VerificationManagerWindowsTestSuite))
 }
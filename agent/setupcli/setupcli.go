@@ -20,12 +20,15 @@ package main
 import (
 	"flag"
 	"fmt"
+	"html"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/context"
 	"github.com/aws/amazon-ssm-agent/agent/fileutil"
 	"github.com/aws/amazon-ssm-agent/agent/log"
 	"github.com/aws/amazon-ssm-agent/agent/log/logger"
@@ -41,6 +44,8 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/setupcli/managers/servicemanagers"
 	"github.com/aws/amazon-ssm-agent/agent/setupcli/managers/verificationmanagers"
 	"github.com/aws/amazon-ssm-agent/agent/setupcli/utility"
+	"github.com/aws/amazon-ssm-agent/agent/updateutil/updateinfo"
+	"github.com/aws/amazon-ssm-agent/agent/updateutil/updatelock"
 	agentVersioning "github.com/aws/amazon-ssm-agent/agent/version"
 	"github.com/aws/amazon-ssm-agent/agent/versionutil"
 	utilityCmn "github.com/aws/amazon-ssm-agent/common/utility"
@@ -68,8 +73,17 @@ var (
 	version                 string
 	downgrade               bool
 	manifestUrl             string
+	hardened                bool
+	bootstrap               string
+	logFile                 string
+	logLevel                string
+	readinessTimeout        int
+	skipControlChannelCheck bool
 )
 
+// updateLock is held while the agent is installed, so the agent updates and Distributor do not replace the agent meanwhile
+var updateLock *updatelock.Lock
+
 var (
 	getPackageManager       = managers.GetPackageManager
 	getConfigurationManager = managers.GetConfigurationManager
@@ -78,7 +92,9 @@ var (
 	getRegistrationInfo     = registration.NewOnpremRegistrationInfo
 	getVerificationManager  = managers.GetVerificationManager
 	getDownloadManager      = managers.GetDownloadManager
-	startAgent              = servicemanagers.StartAgent
+	startAgent              = startAgentService
+	startRegisteredAgent    = startRegisteredAgentService
+	getReadinessConfig      = readinessConfig
 	hasElevatedPermissions  = utilityCmn.IsRunningElevatedPermissions
 
 	osExecutable         = os.Executable
@@ -90,9 +106,13 @@ var (
 	utilityCheckSum      = utility.ComputeCheckSum
 	newProcessExecutor   = executor.NewProcessExecutor
 	svcMgrStopAgent      = servicemanagers.StopAgent
+	svcMgrApplyHardening = servicemanagers.ApplyHardening
 	helperInstallAgent   = helpers.InstallAgent
 	helperUnInstallAgent = helpers.UninstallAgent
 	timeSleep            = time.Sleep
+	acquireUpdateLock    = updatelock.Acquire
+
+	checkPlatformCompatibility = platformCompatibility
 )
 
 var osExit = func(exitCode int, log log.T, message string, messageArgs ...interface{}) {
@@ -103,6 +123,7 @@ var osExit = func(exitCode int, log log.T, message string, messageArgs ...interf
 			log.Errorf(message, messageArgs...)
 		}
 	}
+	unlockAgentUpdates(log)
 	log.Flush()
 	log.Close()
 	os.Exit(exitCode)
@@ -119,6 +140,22 @@ func main() {
 	// set parameters passed
 	setParams()
 
+	// the log parameters are verified first, every later failure is logged with them
+	if err = verifyLogParams(); err != nil {
+		logFile, logLevel = "", ""
+		log := initializeLogger()
+		flagUsage()
+		osExit(1, log, "Invalid log parameters: %v", err)
+	}
+
+	// parameters not passed on the command line are read from the bootstrap
+	if bootstrap != "" {
+		if err = applyBootstrap(bootstrap); err != nil {
+			log := initializeLogger()
+			osExit(1, log, "Failed to apply bootstrap: %v", err)
+		}
+	}
+
 	if strings.ToLower(environment) == string(common.GreengrassEnv) {
 		// initialize logger
 		log := initializeLogger()
@@ -132,10 +169,10 @@ func main() {
 
 		// initialize
 		if packageManager, err = getPackageManager(log); err != nil {
-			osExit(1, log, "Failed to determine package manager: %v", err)
+			osExit(1, log, "Failed to determine package manager: %v", withCompatibilityReasons(log, err))
 		}
 		if serviceManager, err = getServiceManager(log); err != nil {
-			osExit(1, log, "Failed to determine service manager: %v", err)
+			osExit(1, log, "Failed to determine service manager: %v", withCompatibilityReasons(log, err))
 		}
 		// performs greengrass related based on arguments
 		performGreengrassSteps(log, packageManager, serviceManager)
@@ -163,10 +200,10 @@ func main() {
 
 		// Initialization
 		if packageManager, err = getPackageManager(log); err != nil {
-			osExit(1, log, "Failed to determine package manager: %v", err)
+			osExit(1, log, "Failed to determine package manager: %v", withCompatibilityReasons(log, err))
 		}
 		if serviceManager, err = getServiceManager(log); err != nil {
-			osExit(1, log, "Failed to determine service manager: %v", err)
+			osExit(1, log, "Failed to determine service manager: %v", withCompatibilityReasons(log, err))
 		}
 		// verification manager will be used only by On-prem devices
 		if verificationManager, err = getVerificationManager(); err != nil {
@@ -185,6 +222,24 @@ func main() {
 	}
 }
 
+// platformCompatibility returns the reasons the platform does not support the agent, nil when it does or they can not be determined
+func platformCompatibility(log log.T) error {
+	info, err := updateinfo.New(context.Default(log, appconfig.DefaultConfig(), nil))
+	if err != nil {
+		log.Debugf("Failed to determine platform compatibility: %v", err)
+		return nil
+	}
+	return info.CheckCompatibility()
+}
+
+// withCompatibilityReasons adds the reasons the platform does not support the agent to the error
+func withCompatibilityReasons(log log.T, err error) error {
+	if compatibilityErr := checkPlatformCompatibility(log); compatibilityErr != nil {
+		return fmt.Errorf("%v, %v", err, compatibilityErr)
+	}
+	return err
+}
+
 func performGreengrassSteps(log log.T, packageManager packagemanagers.IPackageManager, serviceManager servicemanagers.IServiceManager) {
 	var err error
 
@@ -207,12 +262,16 @@ func performGreengrassSteps(log log.T, packageManager packagemanagers.IPackageMa
 		if err = configManager.CreateUpdateAgentConfigWithOnPremIdentity(); err != nil {
 			log.Warnf("Failed to configure agent with On-prem identity: %v", err)
 		}
+		configureLogging(log, configManager)
 
+		if err = lockAgentUpdates(log); err != nil {
+			osExit(1, log, "Failed to install agent: %v", err)
+		}
 		log.Info("Starting amazon-ssm-agent install")
 		var isInstalled bool
 		var reInstallAgent bool
 		if isInstalled, err = packageManager.IsAgentInstalled(); err != nil {
-			osExit(1, log, "Failed to determine if agent is installed: %w", err)
+			osExit(1, log, "Failed to determine if agent is installed: %v", err)
 		} else if isInstalled {
 			log.Infof("Agent already installed, checking version")
 			if version, err := packageManager.GetInstalledAgentVersion(); err != nil {
@@ -238,16 +297,17 @@ func performGreengrassSteps(log log.T, packageManager packagemanagers.IPackageMa
 
 			log.Infof("Starting agent installation")
 			if err := helperInstallAgent(log, packageManager, serviceManager, artifactsDir); err != nil {
-				osExit(1, log, "Failed to install agent: %v", err)
+				osExit(1, log, "Failed to install agent: %v", withCompatibilityReasons(log, err))
 			}
 			log.Infof("Agent installed successfully")
 		} else {
 			log.Infof("Agent is not installed on the system, Starting agent installation")
 			if err := helperInstallAgent(log, packageManager, serviceManager, artifactsDir); err != nil {
-				osExit(1, log, "Failed to install agent: %v", err)
+				osExit(1, log, "Failed to install agent: %v", withCompatibilityReasons(log, err))
 			}
 			log.Infof("Agent installed successfully")
 		}
+		unlockAgentUpdates(log)
 	}
 
 	// register
@@ -272,7 +332,7 @@ func performGreengrassSteps(log log.T, packageManager packagemanagers.IPackageMa
 
 		if instanceId != "" && !override {
 			log.Info("skipping registration because override flag is not set, just starting agent")
-			if err = startAgent(serviceManager, log); err != nil {
+			if err = startRegisteredAgent(serviceManager, log); err != nil {
 				osExit(1, log, "Failed to start agent: %v", err)
 			}
 			return
@@ -289,7 +349,7 @@ func performGreengrassSteps(log log.T, packageManager packagemanagers.IPackageMa
 		}
 
 		log.Infof("Successfully registered the agent, starting agent")
-		if err = startAgent(serviceManager, log); err != nil {
+		if err = startRegisteredAgent(serviceManager, log); err != nil {
 			osExit(1, log, "Failed to start agent: %v", err)
 		}
 
@@ -342,7 +402,7 @@ func performOnpremSteps(log log.T, packageManager packagemanagers.IPackageManage
 
 	// Initialize download manager
 	log.Infof("Initialize download manager")
-	downloadManager := getDownloadManager(log, region, manifestUrl, nil, setupCLIArtifactsPath, isNano)
+	downloadManager := getDownloadManager(log, region, manifestUrl, nil, setupCLIArtifactsPath, isNano, skipSignatureValidation)
 	if downloadManager == nil {
 		return fmt.Errorf("failed to intialize download manager")
 	}
@@ -380,8 +440,6 @@ func performOnpremSteps(log log.T, packageManager packagemanagers.IPackageManage
 		}
 	}
 
-	// sleeping for 5 seconds for the process to launch
-	timeSleep(5 * time.Second)
 	present, err := checkForSingleAgentProcesses(log)
 	if err != nil {
 		return fmt.Errorf("error while checking agent process count: %v", err)
@@ -413,6 +471,12 @@ func installAndVerifyAgent(log log.T,
 		targetAgentVersion = latestVersion
 	}
 
+	// the installed version must not change between the version check and the installation
+	if err = lockAgentUpdates(log); err != nil {
+		return err
+	}
+	defer unlockAgentUpdates(log)
+
 	var isAgentInstalled bool // This bool says that an agent is already installed
 
 	// Check whether the requested target version is installed
@@ -514,12 +578,13 @@ func installAndVerifyAgent(log log.T,
 	if err = configManager.CreateUpdateAgentConfigWithOnPremIdentity(); err != nil {
 		return fmt.Errorf("return failed to update agent config %v", err)
 	}
+	configureLogging(log, configManager)
 	log.Infof("Agent is configured successfully")
 
 	if !isTargetAgentInstalled {
 		log.Infof("Starting agent installation")
 		if err := helperInstallAgent(log, packageManager, serviceManager, targetVersionFilePaths); err != nil {
-			return fmt.Errorf("installation failed %v", err)
+			return fmt.Errorf("installation failed %v", withCompatibilityReasons(log, err))
 		}
 		if isNano {
 			if err = startAgent(serviceManager, log); err != nil {
@@ -528,9 +593,31 @@ func installAndVerifyAgent(log log.T,
 		}
 		log.Infof("Agent installed successfully")
 	}
+
+	if hardened {
+		if err = hardenAgentService(log, serviceManager); err != nil {
+			return fmt.Errorf("failed to harden agent service: %v", err)
+		}
+	}
 	return nil
 }
 
+// lockAgentUpdates takes the update lock, it fails when an agent update or another installation is in progress
+func lockAgentUpdates(log log.T) error {
+	lock, err := acquireUpdateLock(log)
+	if err != nil {
+		return err
+	}
+	updateLock = lock
+	return nil
+}
+
+// unlockAgentUpdates releases the update lock if it is held
+func unlockAgentUpdates(log log.T) {
+	updateLock.Release(log)
+	updateLock = nil
+}
+
 func registerOnPrem(log log.T, packageManager packagemanagers.IPackageManager, serviceManager servicemanagers.IServiceManager) error {
 	var err error
 	log.Info("Verifying agent is installed before attempting to register")
@@ -552,7 +639,7 @@ func registerOnPrem(log log.T, packageManager packagemanagers.IPackageManager, s
 
 	if instanceId != "" && !override {
 		log.Info("skipping registration because override flag is not set, just starting agent back")
-		if err = startAgent(serviceManager, log); err != nil {
+		if err = startRegisteredAgent(serviceManager, log); err != nil {
 			return fmt.Errorf("%v", err)
 		}
 	} else {
@@ -567,7 +654,7 @@ func registerOnPrem(log log.T, packageManager packagemanagers.IPackageManager, s
 		}
 
 		log.Infof("Successfully registered the agent, starting agent")
-		if err = startAgent(serviceManager, log); err != nil {
+		if err = startRegisteredAgent(serviceManager, log); err != nil {
 			return fmt.Errorf("failed to start agent: %v", err)
 		}
 
@@ -676,12 +763,50 @@ func setParams() {
 	flag.StringVar(&version, "version", "", "")
 	flag.StringVar(&manifestUrl, "manifest-url", "", "")
 	flag.BoolVar(&downgrade, "downgrade", false, "")
+	flag.BoolVar(&hardened, "hardened", false, "")
 
 	flag.BoolVar(&skipSignatureValidation, "skip-signature-validation", false, "")
+	flag.StringVar(&bootstrap, "bootstrap", "", "")
+
+	// readiness flags for both Onprem and greengrass
+	flag.IntVar(&readinessTimeout, "readiness-timeout", 0, "")
+	flag.BoolVar(&skipControlChannelCheck, "skip-control-channel-check", false, "")
+
+	// log flags for both Onprem and greengrass
+	flag.StringVar(&logFile, "log-file", "", "")
+	flag.StringVar(&logLevel, "log-level", "", "")
 
 	flag.Parse()
 }
 
+// configureLogging generates the agent seelog.xml from the Logging section of the agent config,
+// a failure keeps the installed seelog.xml and does not stop the setup
+func configureLogging(log log.T, configManager configurationmanager.IConfigurationManager) {
+	if configured, err := configManager.ConfigureLogging(); err != nil {
+		log.Warnf("Failed to configure agent logging: %v", err)
+	} else if configured {
+		log.Info("Configured agent logging from the Logging section of the agent config")
+	}
+}
+
+// verifyLogParams normalizes the log file path and log level, an unknown log level is an error
+func verifyLogParams() error {
+	if logLevel != "" {
+		logLevel = strings.ToLower(strings.TrimSpace(logLevel))
+		if _, ok := seelog.LogLevelFromString(logLevel); !ok {
+			return fmt.Errorf("unknown log level %v, expected one of trace, debug, info, warn, error, critical", logLevel)
+		}
+	}
+	if logFile != "" {
+		absLogFile, err := filepath.Abs(logFile)
+		if err != nil {
+			return fmt.Errorf("invalid log file %v: %v", logFile, err)
+		}
+		logFile = absLogFile
+	}
+	return nil
+}
+
 func hasAgentAlreadyInstalled(versionStr string) (bool, error) {
 	val, err := versionutil.VersionCompare(versionStr, agentVersioning.Version)
 	if err != nil {
@@ -726,6 +851,10 @@ func verifyParams(log log.T, additionalVerifier func() string) {
 	log.Infof("manifest-url=%v", manifestUrl)
 	log.Infof("artifactsDir=%v", artifactsDir)
 	log.Infof("skip-signature-validation=%v", skipSignatureValidation)
+	log.Infof("hardened=%v", hardened)
+	log.Infof("bootstrap=%v", bootstrap)
+	log.Infof("readiness-timeout=%v", readinessTimeout)
+	log.Infof("skip-control-channel-check=%v", skipControlChannelCheck)
 
 	var errMessage string
 	errMessage += additionalVerifier()
@@ -734,12 +863,51 @@ func verifyParams(log log.T, additionalVerifier func() string) {
 		errMessage += "Region required. "
 	}
 
+	if readinessTimeout < 0 {
+		errMessage += "Readiness timeout must not be negative. "
+	}
+
 	if errMessage != "" {
 		flagUsage()
 		osExit(1, log, "Invalid parameters - %v", errMessage)
 	}
 }
 
+// hardenAgentService applies the service hardening and restarts a running agent so that it takes effect
+func hardenAgentService(log log.T, serviceManager servicemanagers.IServiceManager) error {
+	if err := svcMgrApplyHardening(serviceManager, log); err != nil {
+		return err
+	}
+
+	if status, err := serviceManager.GetAgentStatus(); err != nil || status != common.Running {
+		return nil
+	}
+	if err := svcMgrStopAgent(serviceManager, log); err != nil {
+		return fmt.Errorf("failed to stop agent: %v", err)
+	}
+	return startAgent(serviceManager, log)
+}
+
+// readinessConfig returns the readiness criteria of a started agent selected by the parameters
+func readinessConfig(requireControlChannel bool) servicemanagers.ReadinessConfig {
+	config := servicemanagers.DefaultReadinessConfig()
+	if readinessTimeout > 0 {
+		config.Timeout = time.Duration(readinessTimeout) * time.Second
+	}
+	config.RequireControlChannel = requireControlChannel && !skipControlChannelCheck
+	return config
+}
+
+// startAgentService starts the agent and waits for the agent service to run
+func startAgentService(serviceManager servicemanagers.IServiceManager, log log.T) error {
+	return servicemanagers.StartAgent(serviceManager, log, getReadinessConfig(false))
+}
+
+// startRegisteredAgentService starts the registered agent and waits for it to open the control channel
+func startRegisteredAgentService(serviceManager servicemanagers.IServiceManager, log log.T) error {
+	return servicemanagers.StartAgent(serviceManager, log, getReadinessConfig(true))
+}
+
 func isAgentInstallationOnly() bool {
 	if !register && install {
 		return true
@@ -788,13 +956,21 @@ func greengrassParamVerification() string {
 
 func flagUsage() {
 
+	fmt.Fprintln(os.Stderr, "\n-bootstrap\tRead the parameters not passed on the command line from a JSON/YAML file, or from the ssm_agent section of the cloud-init user-data when set to 'user-data' \t(OPTIONAL)")
+	fmt.Fprintln(os.Stderr, "\t\tThe file keys are env, region, activationCode, activationId, role, tags, artifactsDir, version, manifestUrl, install, register, override, downgrade, hardened, skipSignatureValidation and proxy (http, https, noProxy)")
+
 	fmt.Fprintln(os.Stderr, "\n-env   \tInstruct cli what environment you are installing to ('greengrass'/'onprem'). Default set to 'onprem'  \t(OPTIONAL)")
+	fmt.Fprintln(os.Stderr, "\n-log-file\tWrite the log to this file instead of the default ssm-setup-cli.log in the agent log folder \t(OPTIONAL)")
+	fmt.Fprintln(os.Stderr, "-log-level\tMinimum level of the logged messages ('trace', 'debug', 'info', 'warn', 'error', 'critical'), 'trace' and 'debug' log the caller of each message \t(OPTIONAL)")
+	fmt.Fprintln(os.Stderr, "\n-readiness-timeout\tSeconds to wait for a started agent to become ready. Default set to 30 \t(OPTIONAL)")
+	fmt.Fprintln(os.Stderr, "-skip-control-channel-check\tConsider a registered agent ready once its service runs, without waiting for it to open the control channel \t(OPTIONAL)")
 
 	fmt.Fprintln(os.Stderr, "\nCommand-line Usage for ONPREM environment:")
 	fmt.Fprintln(os.Stderr, "\t-region        \tRegion used for ssm agent download location and registration \t(REQUIRED)")
 	fmt.Fprintln(os.Stderr, "\t-version\tVersion of the ssm agent to download and install ('stable' or 'latest'). Default set to 'stable' if agent is not already installed; otherwise, skip the installation \t(OPTIONAL)")
 	fmt.Fprintln(os.Stderr, "\t-downgrade\tSet when the agent needs to be downgraded \t(OPTIONAL but REQUIRED during downgrade)")
-	fmt.Fprintln(os.Stderr, "\t-skip-signature-validation\tSkip signature validation \t(OPTIONAL)")
+	fmt.Fprintln(os.Stderr, "\t-skip-signature-validation\tSkip signature validation of the agent package and the version manifests \t(OPTIONAL)")
+	fmt.Fprintln(os.Stderr, "\t-hardened\tApply systemd hardening options to the agent service. /usr, /boot and /etc become read-only for the commands the agent runs, so patching and package installs through the agent fail \t(OPTIONAL)")
 	fmt.Fprintln(os.Stderr, "\t-register      \tRegister ssm agent if unregistered or override is set \t(REQUIRED)")
 	fmt.Fprintln(os.Stderr, "\t\t-activation-code  \tSSM Activation Code for Onprem environment \t(REQUIRED and paired with activation-id)")
 	fmt.Fprintln(os.Stderr, "\t\t-activation-id  \tSSM Activation ID for Onprem environment \t(REQUIRED and paired with Activation code)")
@@ -805,7 +981,8 @@ func flagUsage() {
 	fmt.Fprintln(os.Stderr, "\t\t-region        \tRegion used for ssm agent download location and registration \t(REQUIRED)")
 	fmt.Fprintln(os.Stderr, "\t\t-version\tVersion of the ssm agent to download and install ('stable' or 'latest'). Default set to 'stable' if agent is not already installed; otherwise, skip the installation. \t(OPTIONAL)")
 	fmt.Fprintln(os.Stderr, "\t\t-downgrade\tSet when the agent needs to be downgraded \t(OPTIONAL but REQUIRED during downgrade)")
-	fmt.Fprintln(os.Stderr, "\t\t-skip-signature-validation\tSkip signature validation \t(OPTIONAL)")
+	fmt.Fprintln(os.Stderr, "\t\t-skip-signature-validation\tSkip signature validation of the agent package and the version manifests \t(OPTIONAL)")
+	fmt.Fprintln(os.Stderr, "\t\t-hardened\tApply systemd hardening options to the agent service. /usr, /boot and /etc become read-only for the commands the agent runs, so patching and package installs through the agent fail \t(OPTIONAL)")
 
 	fmt.Fprintln(os.Stderr, "\nCommand-line Usage for GREENGRASS environment:")
 	fmt.Fprintln(os.Stderr, "\t-artifacts-dir \tDirectory for ssm agent install package and install/register scripts")
@@ -823,35 +1000,41 @@ func flagUsage() {
 
 }
 func initializeLogger() log.T {
-	// log to console
+	// log to console, and to the log file when set
+	outputs := `<console formatid="fmtconsole"/>`
+	if logFile != "" {
+		outputs += `
+        <rollingfile type="size" formatid="` + logFormatID() + `" filename="` + html.EscapeString(logFile) + `" maxsize="30000000" maxrolls="5"/>`
+	}
 	seelogConfig := `
-<seelog type="sync">
+<seelog type="sync"` + logMinLevel() + `>
 	<outputs>
-		<console formatid="fmtconsole"/>
+		` + outputs + `
 	</outputs>
 	<formats>
         <format id="fmtconsole" format="%LEVEL %Msg%n"/>
+        <format id="fmtdebug" format="%Date %Time %LEVEL [%FuncShort @ %File.%Line] %Msg%n"/>
+        <format id="fmtinfo" format="%Date %Time %LEVEL %Msg%n"/>
     </formats>
 </seelog>
 `
-	seelogger, _ := seelog.LoggerFromConfigAsBytes([]byte(seelogConfig))
-	loggerInstance := &logger.DelegateLogger{}
-	loggerInstance.BaseLoggerInstance = seelogger
-	formatFilter := &logger.ContextFormatFilter{Context: []string{}}
-	return &logger.Wrapper{Format: formatFilter, M: LogMutex, Delegate: loggerInstance}
+	return newSetupCLILogger(seelogConfig)
 }
 
 func initializeLoggerForOnprem() log.T {
 	logFileName := filepath.Join(logger.DefaultLogDir, "ssm-setup-cli.log")
+	if logFile != "" {
+		logFileName = logFile
+	}
 	// log to console
 	seelogConfig := `
-<seelog type="sync">
+<seelog type="sync"` + logMinLevel() + `>
     <exceptions>
         <exception filepattern="test*" minlevel="error"/>
     </exceptions>
-    <outputs formatid="fmtinfo">
-        <console formatid="fmtinfo"/>
-        <rollingfile type="size" filename="` + logFileName + `" maxsize="30000000" maxrolls="5"/>
+    <outputs formatid="` + logFormatID() + `">
+        <console formatid="` + logFormatID() + `"/>
+        <rollingfile type="size" filename="` + html.EscapeString(logFileName) + `" maxsize="30000000" maxrolls="5"/>
     </outputs>
     <formats>
         <format id="fmterror" format="%Date %Time %LEVEL [%FuncShort @ %File.%Line] %Msg%n"/>
@@ -860,7 +1043,29 @@ func initializeLoggerForOnprem() log.T {
     </formats>
 </seelog>
 `
+	return newSetupCLILogger(seelogConfig)
+}
+
+// logMinLevel returns the seelog minlevel attribute of the -log-level flag, all levels are logged by default
+func logMinLevel() string {
+	if logLevel == "" {
+		return ""
+	}
+	return ` minlevel="` + logLevel + `"`
+}
+
+// logFormatID returns the format of the log file, the verbose levels log the caller of each message
+func logFormatID() string {
+	if logLevel == seelog.TraceStr || logLevel == seelog.DebugStr {
+		return "fmtdebug"
+	}
+	return "fmtinfo"
+}
+
+func newSetupCLILogger(seelogConfig string) log.T {
 	seelogger, _ := seelog.LoggerFromConfigAsBytes([]byte(seelogConfig))
+	// the caller of the wrapper is logged by the verbose format
+	seelogger.SetAdditionalStackDepth(1)
 	loggerInstance := &logger.DelegateLogger{}
 	loggerInstance.BaseLoggerInstance = seelogger
 	formatFilter := &logger.ContextFormatFilter{Context: []string{}}
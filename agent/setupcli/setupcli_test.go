@@ -19,6 +19,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -41,6 +42,7 @@ import (
 	vmMock "github.com/aws/amazon-ssm-agent/agent/setupcli/managers/verificationmanagers/mocks"
 	"github.com/aws/amazon-ssm-agent/agent/setupcli/utility"
 	"github.com/aws/amazon-ssm-agent/agent/updateutil/updateinfo"
+	"github.com/aws/amazon-ssm-agent/agent/updateutil/updatelock"
 	agentVersioning "github.com/aws/amazon-ssm-agent/agent/version"
 	"github.com/aws/amazon-ssm-agent/core/executor"
 	"github.com/aws/amazon-ssm-agent/core/executor/mocks"
@@ -51,6 +53,9 @@ import (
 const breakOutWithPanicMessageOnprem = "BREAKOUT_WITH_PANIC"
 
 func storeMockedFunctionsOnprem() func() {
+	acquireUpdateLock = func(log.T) (*updatelock.Lock, error) {
+		return &updatelock.Lock{}, nil
+	}
 	getPackageManagerStorage := getPackageManager
 	getConfigurationManagerStorage := getConfigurationManager
 	getServiceManagerStorage := getServiceManager
@@ -107,6 +112,50 @@ func TestMain_ErrorGetPackageManager(t *testing.T) {
 	assert.True(t, false, "Should never reach here because of exit")
 }
 
+func TestMain_ErrorGetPackageManager_ReportsCompatibilityReasons(t *testing.T) {
+	initializeArgs()
+	defer storeMockedFunctions()()
+
+	defer setArgsAndRestore("/some/path/setupcli", "-shutdown", "-env", "greengrass")()
+
+	getPackageManager = func(log.T) (packagemanagers.IPackageManager, error) {
+		return nil, fmt.Errorf("no supported package manager found")
+	}
+	checkPlatformCompatibilityStorage := checkPlatformCompatibility
+	defer func() { checkPlatformCompatibility = checkPlatformCompatibilityStorage }()
+	checkPlatformCompatibility = func(log.T) error {
+		return &updateinfo.CompatibilityError{
+			Platform:        "debian",
+			PlatformVersion: "7",
+			Reasons:         []updateinfo.CompatibilityReason{{Code: updateinfo.ReasonMissingDependency, Message: "dpkg required to install the agent is not found"}},
+		}
+	}
+
+	osExit = func(exitCode int, log log.T, message string, args ...interface{}) {
+		assert.Equal(t, 1, exitCode)
+		assert.Contains(t, fmt.Sprintf(message, args...), "no supported package manager found, platform debian 7 is not supported: dpkg required to install the agent is not found")
+
+		panic(breakOutWithPanicMessage)
+	}
+
+	defer func() {
+		if errInterface := recover(); errInterface != nil {
+			assert.Equal(t, breakOutWithPanicMessage, errInterface)
+		}
+	}()
+	main()
+	assert.True(t, false, "Should never reach here because of exit")
+}
+
+func TestWithCompatibilityReasons_Compatible(t *testing.T) {
+	checkPlatformCompatibilityStorage := checkPlatformCompatibility
+	defer func() { checkPlatformCompatibility = checkPlatformCompatibilityStorage }()
+	checkPlatformCompatibility = func(log.T) error { return nil }
+
+	err := fmt.Errorf("installation error")
+	assert.Equal(t, err, withCompatibilityReasons(logmocks.NewMockLog(), err))
+}
+
 func TestMain_OnPrem_GetExecutingDirectoryPath_Failed(t *testing.T) {
 	evalSymLinks = func(path string) (string, error) {
 		return "", nil
@@ -177,7 +226,7 @@ func TestMain_OnPrem_Register_Success(t *testing.T) {
 		return false, nil
 	}
 
-	getDownloadManager = func(log log.T, region string, manifestUrl string, updateInfo updateinfo.T, setupCLIArtifactsPath string, isNano bool) downloadmanager.IDownloadManager {
+	getDownloadManager = func(log log.T, region string, manifestUrl string, updateInfo updateinfo.T, setupCLIArtifactsPath string, isNano bool, skipSignatureValidation bool) downloadmanager.IDownloadManager {
 		managerMock := &dmMock.IDownloadManager{}
 		managerMock.On("DownloadLatestSSMSetupCLI", mock.Anything, mock.Anything).Return(nil).Once()
 		managerMock.On("GetLatestVersion").Return(agentVersioning.Version, nil).Once()
@@ -188,6 +237,7 @@ func TestMain_OnPrem_Register_Success(t *testing.T) {
 	getConfigurationManager = func() configurationmanager.IConfigurationManager {
 		managerMock := &cmMock.IConfigurationManager{}
 		managerMock.On("CreateUpdateAgentConfigWithOnPremIdentity").Return(nil)
+		managerMock.On("ConfigureLogging").Return(false, nil)
 		return managerMock
 	}
 
@@ -373,12 +423,13 @@ func TestMain_InstallAgent_StableVersion_Onprem_Success(t *testing.T) {
 	getConfigurationManager = func() configurationmanager.IConfigurationManager {
 		cfgManagerMock := &cmMock.IConfigurationManager{}
 		cfgManagerMock.On("CreateUpdateAgentConfigWithOnPremIdentity").Return(nil)
+		cfgManagerMock.On("ConfigureLogging").Return(false, nil)
 		cfgManagerMock.On("ConfigureAgent", mock.Anything).Return(nil)
 		cfgManagerMock.On("IsConfigAvailable", mock.Anything).Return(nil)
 		return cfgManagerMock
 	}
 	stableVersion := "3.2.0.0"
-	getDownloadManager = func(log log.T, region string, manifestUrl string, updateInfo updateinfo.T, setupCLIArtifactsPath string, isNano bool) downloadmanager.IDownloadManager {
+	getDownloadManager = func(log log.T, region string, manifestUrl string, updateInfo updateinfo.T, setupCLIArtifactsPath string, isNano bool, skipSignatureValidation bool) downloadmanager.IDownloadManager {
 		managerMock := &dmMock.IDownloadManager{}
 		managerMock.On("DownloadLatestSSMSetupCLI", mock.Anything, mock.Anything).Return(nil).Once()
 		managerMock.On("GetStableVersion").Return(stableVersion, nil).Once()
@@ -443,12 +494,13 @@ func TestMain_InstallAgent_LatestVersion_Onprem_Success(t *testing.T) {
 	getConfigurationManager = func() configurationmanager.IConfigurationManager {
 		cfgManagerMock := &cmMock.IConfigurationManager{}
 		cfgManagerMock.On("CreateUpdateAgentConfigWithOnPremIdentity").Return(nil)
+		cfgManagerMock.On("ConfigureLogging").Return(false, nil)
 		cfgManagerMock.On("ConfigureAgent", mock.Anything).Return(nil)
 		cfgManagerMock.On("IsConfigAvailable", mock.Anything).Return(nil)
 		return cfgManagerMock
 	}
 	latestVersion := "3.0.0.0"
-	getDownloadManager = func(log log.T, region string, manifestUrl string, updateInfo updateinfo.T, setupCLIArtifactsPath string, isNano bool) downloadmanager.IDownloadManager {
+	getDownloadManager = func(log log.T, region string, manifestUrl string, updateInfo updateinfo.T, setupCLIArtifactsPath string, isNano bool, skipSignatureValidation bool) downloadmanager.IDownloadManager {
 		managerMock := &dmMock.IDownloadManager{}
 		managerMock.On("DownloadLatestSSMSetupCLI", mock.Anything, mock.Anything).Return(nil).Once()
 		managerMock.On("GetLatestVersion").Return(latestVersion, nil).Once()
@@ -475,6 +527,74 @@ func TestMain_InstallAgent_LatestVersion_Onprem_Success(t *testing.T) {
 	assert.Equal(t, utility.LatestVersionString, version) // version should be set as latest
 }
 
+func TestMain_InstallAgent_Hardened_Onprem_Success(t *testing.T) {
+	defer storeMockedFunctionsOnprem()()
+	defer setArgsAndRestoreOnprem("/some/path/setupcli", "-env", "onprem", "-install", "-hardened", "--region", "us-east-1")()
+	originalApplyHardening, originalStopAgent, originalStartAgent := svcMgrApplyHardening, svcMgrStopAgent, startAgent
+	defer func() {
+		svcMgrApplyHardening, svcMgrStopAgent, startAgent = originalApplyHardening, originalStopAgent, originalStartAgent
+	}()
+
+	getPackageManager = func(log.T) (packagemanagers.IPackageManager, error) {
+		managerMock := &pmMock.IPackageManager{}
+		managerMock.On("GetInstalledAgentVersion").Return("2.1.2.2", nil)
+		managerMock.On("IsAgentInstalled").Return(true, nil)
+		managerMock.On("GetFileExtension").Return("test")
+		return managerMock, nil
+	}
+
+	getServiceManager = func(log.T) (servicemanagers.IServiceManager, error) {
+		managerMock := &smMock.IServiceManager{}
+		managerMock.On("GetName").Return("ServiceManagerName")
+		managerMock.On("GetAgentStatus").Return(common.Running, nil)
+		return managerMock, nil
+	}
+	hardeningApplied, agentStopped, agentStarted := false, false, false
+	svcMgrApplyHardening = func(manager servicemanagers.IServiceManager, log log.T) error {
+		hardeningApplied = true
+		return nil
+	}
+	svcMgrStopAgent = func(manager servicemanagers.IServiceManager, log log.T) error {
+		agentStopped = true
+		return nil
+	}
+	startAgent = func(manager servicemanagers.IServiceManager, log log.T) error {
+		agentStarted = true
+		return nil
+	}
+	utilityCheckSum = func(filePath string) (hash string, err error) {
+		return "", nil
+	}
+	getConfigurationManager = func() configurationmanager.IConfigurationManager {
+		cfgManagerMock := &cmMock.IConfigurationManager{}
+		cfgManagerMock.On("CreateUpdateAgentConfigWithOnPremIdentity").Return(nil)
+		cfgManagerMock.On("ConfigureLogging").Return(false, nil)
+		return cfgManagerMock
+	}
+	getDownloadManager = func(log log.T, region string, manifestUrl string, updateInfo updateinfo.T, setupCLIArtifactsPath string, isNano bool, skipSignatureValidation bool) downloadmanager.IDownloadManager {
+		managerMock := &dmMock.IDownloadManager{}
+		managerMock.On("DownloadLatestSSMSetupCLI", mock.Anything, mock.Anything).Return(nil).Once()
+		managerMock.On("GetLatestVersion").Return("3.0.0.0", nil).Once()
+		return managerMock
+	}
+	getVerificationManager = func() (verificationmanagers.IVerificationManager, error) {
+		return &vmMock.IVerificationManager{}, nil
+	}
+	osExit = func(exitCode int, log log.T, message string, args ...interface{}) {
+		if exitCode == 0 {
+			return
+		}
+		panic(fmt.Sprintf("should not receive non zero exit code. Err: %v; Msg: %v; Args: %v", exitCode, message, args))
+	}
+
+	main()
+	assert.True(t, hardened)
+	assert.True(t, hardeningApplied)
+	// a running agent is restarted so that the hardening takes effect
+	assert.True(t, agentStopped)
+	assert.True(t, agentStarted)
+}
+
 func TestMain_InstallAgent_AlreadyInstalledVersion_Onprem_Success(t *testing.T) {
 	defer storeMockedFunctionsOnprem()()
 	defer setArgsAndRestoreOnprem("/some/path/setupcli", "-env", "onprem", "-install", "--region", "us-east-1")()
@@ -514,12 +634,13 @@ func TestMain_InstallAgent_AlreadyInstalledVersion_Onprem_Success(t *testing.T)
 	getConfigurationManager = func() configurationmanager.IConfigurationManager {
 		cfgManagerMock := &cmMock.IConfigurationManager{}
 		cfgManagerMock.On("CreateUpdateAgentConfigWithOnPremIdentity").Return(nil)
+		cfgManagerMock.On("ConfigureLogging").Return(false, nil)
 		cfgManagerMock.On("ConfigureAgent", mock.Anything).Return(nil)
 		cfgManagerMock.On("IsConfigAvailable", mock.Anything).Return(nil)
 		return cfgManagerMock
 	}
 	latestVersion := "3.0.0.0"
-	getDownloadManager = func(log log.T, region string, manifestUrl string, updateInfo updateinfo.T, setupCLIArtifactsPath string, isNano bool) downloadmanager.IDownloadManager {
+	getDownloadManager = func(log log.T, region string, manifestUrl string, updateInfo updateinfo.T, setupCLIArtifactsPath string, isNano bool, skipSignatureValidation bool) downloadmanager.IDownloadManager {
 		managerMock := &dmMock.IDownloadManager{}
 		managerMock.On("DownloadLatestSSMSetupCLI", mock.Anything, mock.Anything).Return(nil).Once()
 		managerMock.On("GetLatestVersion").Return(latestVersion, nil).Once()
@@ -548,3 +669,85 @@ func TestMain_InstallAgent_AlreadyInstalledVersion_Onprem_Success(t *testing.T)
 	// version should be blank when version flag not passed
 	assert.Equal(t, "", version)
 }
+
+func TestInstallAndVerifyAgent_UpdateInProgress(t *testing.T) {
+	defer storeMockedFunctionsOnprem()()
+	acquireUpdateLock = func(log.T) (*updatelock.Lock, error) {
+		return nil, fmt.Errorf("%w, the update lock is held by process 42", updatelock.ErrUpdateInProgress)
+	}
+	downloadManager := &dmMock.IDownloadManager{}
+	downloadManager.On("GetLatestVersion").Return("3.2.0.0", nil).Once()
+	packageManager := &pmMock.IPackageManager{}
+
+	err := installAndVerifyAgent(logmocks.NewMockLog(), packageManager, nil, nil, downloadManager, t.TempDir(), false)
+	assert.ErrorIs(t, err, updatelock.ErrUpdateInProgress)
+	// the installed agent is not inspected while another update is in progress
+	packageManager.AssertNotCalled(t, "IsAgentInstalled")
+	assert.Nil(t, updateLock)
+}
+
+func TestVerifyLogParams(t *testing.T) {
+	defer func() { logFile, logLevel = "", "" }()
+
+	logFile, logLevel = "setup.log", " DEBUG "
+	assert.NoError(t, verifyLogParams())
+	assert.Equal(t, "debug", logLevel)
+	assert.True(t, filepath.IsAbs(logFile))
+	assert.Equal(t, "setup.log", filepath.Base(logFile))
+
+	logFile, logLevel = "", "verbose"
+	err := verifyLogParams()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown log level verbose")
+}
+
+func TestInitializeLoggerForOnprem_LogFileAndLevel(t *testing.T) {
+	defer func() { logFile, logLevel = "", "" }()
+	logFile = filepath.Join(t.TempDir(), "support", "ssm-setup-cli.log")
+	logLevel = "debug"
+
+	logger := initializeLoggerForOnprem()
+	logger.Tracef("trace message")
+	logger.Debugf("debug message")
+	logger.Flush()
+	logger.Close()
+
+	content, err := os.ReadFile(logFile)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "trace message")
+	assert.Contains(t, string(content), "debug message")
+	assert.Contains(t, string(content), "setupcli_test.go")
+}
+
+func TestInitializeLogger_LogFile(t *testing.T) {
+	defer func() { logFile, logLevel = "", "" }()
+	logFile = filepath.Join(t.TempDir(), "ssm-setup-cli.log")
+	logLevel = "warn"
+
+	logger := initializeLogger()
+	logger.Infof("info message")
+	logger.Warnf("warn message")
+	logger.Flush()
+	logger.Close()
+
+	content, err := os.ReadFile(logFile)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "info message")
+	assert.Contains(t, string(content), "WARN warn message")
+}
+
+func TestReadinessConfig(t *testing.T) {
+	defer func() { readinessTimeout, skipControlChannelCheck = 0, false }()
+
+	config := readinessConfig(false)
+	assert.Equal(t, servicemanagers.DefaultReadinessConfig(), config)
+
+	config = readinessConfig(true)
+	assert.True(t, config.RequireControlChannel)
+
+	readinessTimeout = 90
+	skipControlChannelCheck = true
+	config = readinessConfig(true)
+	assert.False(t, config.RequireControlChannel)
+	assert.Equal(t, 90*time.Second, config.Timeout)
+}
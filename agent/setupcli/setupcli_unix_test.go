@@ -39,6 +39,7 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/setupcli/managers/verificationmanagers"
 	vmMock "github.com/aws/amazon-ssm-agent/agent/setupcli/managers/verificationmanagers/mocks"
 	"github.com/aws/amazon-ssm-agent/agent/updateutil/updateinfo"
+	"github.com/aws/amazon-ssm-agent/agent/updateutil/updatelock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -47,20 +48,33 @@ const breakOutWithPanicMessage = "BREAKOUT_WITH_PANIC"
 const testArtifactsPath = "SomeArtifacts"
 
 func storeMockedFunctions() func() {
+	acquireUpdateLock = func(log.T) (*updatelock.Lock, error) {
+		return &updatelock.Lock{}, nil
+	}
 	getPackageManagerStorage := getPackageManager
 	getConfigurationManagerStorage := getConfigurationManager
 	getServiceManagerStorage := getServiceManager
 	getRegisterManagerStorage := getRegisterManager
 	getRegistrationInfoStorage := getRegistrationInfo
+	checkPlatformCompatibilityStorage := checkPlatformCompatibility
+	getReadinessConfigStorage := getReadinessConfig
 	hasElevatedPermissions = func() error {
 		return nil
 	}
+	checkPlatformCompatibility = func(log.T) error {
+		return nil
+	}
+	getReadinessConfig = func(bool) servicemanagers.ReadinessConfig {
+		return servicemanagers.ReadinessConfig{}
+	}
 	return func() {
 		getPackageManager = getPackageManagerStorage
 		getConfigurationManager = getConfigurationManagerStorage
 		getServiceManager = getServiceManagerStorage
 		getRegisterManager = getRegisterManagerStorage
 		getRegistrationInfo = getRegistrationInfoStorage
+		checkPlatformCompatibility = checkPlatformCompatibilityStorage
+		getReadinessConfig = getReadinessConfigStorage
 	}
 }
 
@@ -89,7 +103,8 @@ func initializeArgs() {
 	svcMgrStopAgent = servicemanagers.StopAgent
 	helperInstallAgent = helpers.InstallAgent
 	helperUnInstallAgent = helpers.UninstallAgent
-	startAgent = servicemanagers.StartAgent
+	startAgent = startAgentService
+	startRegisteredAgent = startRegisteredAgentService
 	svcMgrStopAgent = servicemanagers.StopAgent
 }
 
@@ -219,6 +234,7 @@ func TestMain_Install_FailedCheckAgentInstalled(t *testing.T) {
 		managerMock := &cmMock.IConfigurationManager{}
 		managerMock.On("IsConfigAvailable", "").Return(true, nil)
 		managerMock.On("CreateUpdateAgentConfigWithOnPremIdentity").Return(nil)
+		managerMock.On("ConfigureLogging").Return(false, nil)
 
 		return managerMock
 	}
@@ -229,7 +245,7 @@ func TestMain_Install_FailedCheckAgentInstalled(t *testing.T) {
 		return managerMock, nil
 	}
 
-	getDownloadManager = func(log log.T, region string, manifestUrl string, updateInfo updateinfo.T, setupCLIArtifactsPath string, isNano bool) downloadmanager.IDownloadManager {
+	getDownloadManager = func(log log.T, region string, manifestUrl string, updateInfo updateinfo.T, setupCLIArtifactsPath string, isNano bool, skipSignatureValidation bool) downloadmanager.IDownloadManager {
 		managerMock := &dmMock.IDownloadManager{}
 		managerMock.On("DownloadArtifacts", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 		return managerMock
@@ -272,6 +288,7 @@ func TestMain_Install_AgentConfigured_ConfigurationNotLoadable_Fail(t *testing.T
 		managerMock := &cmMock.IConfigurationManager{}
 		managerMock.On("IsConfigAvailable", "").Return(false, fmt.Errorf("failed to configure agent"))
 		managerMock.On("CreateUpdateAgentConfigWithOnPremIdentity").Return(nil)
+		managerMock.On("ConfigureLogging").Return(false, nil)
 		return managerMock
 	}
 
@@ -313,6 +330,7 @@ func TestMain_Install_AgentNotConfigured_DefaultConfigurationMissing_Fail(t *tes
 		managerMock.On("IsConfigAvailable", "").Return(false, nil)
 		managerMock.On("IsConfigAvailable", testArtifactsPath).Return(false, fmt.Errorf("failed to configure agent"))
 		managerMock.On("CreateUpdateAgentConfigWithOnPremIdentity").Return(nil)
+		managerMock.On("ConfigureLogging").Return(false, nil)
 		return managerMock
 	}
 
@@ -356,6 +374,7 @@ func TestMain_Install_AgentIsInstalled_UninstallAgentFailed(t *testing.T) {
 		managerMock := &cmMock.IConfigurationManager{}
 		managerMock.On("IsConfigAvailable", "").Return(true, nil)
 		managerMock.On("CreateUpdateAgentConfigWithOnPremIdentity").Return(nil)
+		managerMock.On("ConfigureLogging").Return(false, nil)
 		return managerMock
 	}
 
@@ -365,7 +384,7 @@ func TestMain_Install_AgentIsInstalled_UninstallAgentFailed(t *testing.T) {
 		return managerMock, nil
 	}
 
-	getDownloadManager = func(log log.T, region string, manifestUrl string, updateInfo updateinfo.T, setupCLIArtifactsPath string, isNano bool) downloadmanager.IDownloadManager {
+	getDownloadManager = func(log log.T, region string, manifestUrl string, updateInfo updateinfo.T, setupCLIArtifactsPath string, isNano bool, skipSignatureValidation bool) downloadmanager.IDownloadManager {
 		managerMock := &dmMock.IDownloadManager{}
 		managerMock.On("DownloadArtifacts", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 		return managerMock
@@ -413,6 +432,7 @@ func TestMain_Install_AgentIsInstalled_UninstallSuccess_InstallFailed(t *testing
 		managerMock := &cmMock.IConfigurationManager{}
 		managerMock.On("IsConfigAvailable", "").Return(true, nil)
 		managerMock.On("CreateUpdateAgentConfigWithOnPremIdentity").Return(nil)
+		managerMock.On("ConfigureLogging").Return(false, nil)
 		return managerMock
 	}
 
@@ -422,7 +442,7 @@ func TestMain_Install_AgentIsInstalled_UninstallSuccess_InstallFailed(t *testing
 		return managerMock, nil
 	}
 
-	getDownloadManager = func(log log.T, region string, manifestUrl string, updateInfo updateinfo.T, setupCLIArtifactsPath string, isNano bool) downloadmanager.IDownloadManager {
+	getDownloadManager = func(log log.T, region string, manifestUrl string, updateInfo updateinfo.T, setupCLIArtifactsPath string, isNano bool, skipSignatureValidation bool) downloadmanager.IDownloadManager {
 		managerMock := &dmMock.IDownloadManager{}
 		managerMock.On("DownloadArtifacts", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 		return managerMock
@@ -472,6 +492,7 @@ func TestMain_Install_AgentIsInstalled_UninstallSuccess_InstallSuccess_ReloadSer
 		managerMock := &cmMock.IConfigurationManager{}
 		managerMock.On("IsConfigAvailable", "").Return(true, nil)
 		managerMock.On("CreateUpdateAgentConfigWithOnPremIdentity").Return(nil)
+		managerMock.On("ConfigureLogging").Return(false, nil)
 		return managerMock
 	}
 
@@ -481,7 +502,7 @@ func TestMain_Install_AgentIsInstalled_UninstallSuccess_InstallSuccess_ReloadSer
 		return managerMock, nil
 	}
 
-	getDownloadManager = func(log log.T, region string, manifestUrl string, updateInfo updateinfo.T, setupCLIArtifactsPath string, isNano bool) downloadmanager.IDownloadManager {
+	getDownloadManager = func(log log.T, region string, manifestUrl string, updateInfo updateinfo.T, setupCLIArtifactsPath string, isNano bool, skipSignatureValidation bool) downloadmanager.IDownloadManager {
 		managerMock := &dmMock.IDownloadManager{}
 		managerMock.On("DownloadArtifacts", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 		return managerMock
@@ -531,6 +552,7 @@ func TestMain_Install_AgentNotInstalled_InstallSuccess(t *testing.T) {
 		managerMock := &cmMock.IConfigurationManager{}
 		managerMock.On("IsConfigAvailable", "").Return(true, nil)
 		managerMock.On("CreateUpdateAgentConfigWithOnPremIdentity").Return(nil)
+		managerMock.On("ConfigureLogging").Return(false, nil)
 		return managerMock
 	}
 
@@ -540,7 +562,7 @@ func TestMain_Install_AgentNotInstalled_InstallSuccess(t *testing.T) {
 		return managerMock, nil
 	}
 
-	getDownloadManager = func(log log.T, region string, manifestUrl string, updateInfo updateinfo.T, setupCLIArtifactsPath string, isNano bool) downloadmanager.IDownloadManager {
+	getDownloadManager = func(log log.T, region string, manifestUrl string, updateInfo updateinfo.T, setupCLIArtifactsPath string, isNano bool, skipSignatureValidation bool) downloadmanager.IDownloadManager {
 		managerMock := &dmMock.IDownloadManager{}
 		managerMock.On("DownloadArtifacts", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 		return managerMock
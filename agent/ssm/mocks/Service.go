@@ -302,6 +302,29 @@ func (_m *Service) PutComplianceItems(_a0 log.T, executionTime *time.Time, execu
 	return r0, r1
 }
 
+// PutInventory provides a mock function with given fields: _a0, instanceID, items
+func (_m *Service) PutInventory(_a0 log.T, instanceID string, items []*ssm.InventoryItem) (*ssm.PutInventoryOutput, error) {
+	ret := _m.Called(_a0, instanceID, items)
+
+	var r0 *ssm.PutInventoryOutput
+	if rf, ok := ret.Get(0).(func(log.T, string, []*ssm.InventoryItem) *ssm.PutInventoryOutput); ok {
+		r0 = rf(_a0, instanceID, items)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ssm.PutInventoryOutput)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(log.T, string, []*ssm.InventoryItem) error); ok {
+		r1 = rf(_a0, instanceID, items)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SendCommand provides a mock function with given fields: _a0, documentName, instanceIDs, parameters, timeoutSeconds, outputS3BucketName, outputS3KeyPrefix
 func (_m *Service) SendCommand(_a0 log.T, documentName string, instanceIDs []string, parameters map[string][]*string, timeoutSeconds *int64, outputS3BucketName *string, outputS3KeyPrefix *string) (*ssm.SendCommandOutput, error) {
 	ret := _m.Called(_a0, documentName, instanceIDs, parameters, timeoutSeconds, outputS3BucketName, outputS3KeyPrefix)
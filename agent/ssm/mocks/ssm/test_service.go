@@ -160,3 +160,9 @@ func (m *Mock) PutComplianceItems(
 	return args.Get(0).(*ssm.PutComplianceItemsOutput), args.Error(1)
 
 }
+
+// PutInventory mocks the PutInventory function.
+func (m *Mock) PutInventory(log log.T, instanceID string, items []*ssm.InventoryItem) (response *ssm.PutInventoryOutput, err error) {
+	args := m.Called(log, instanceID, items)
+	return args.Get(0).(*ssm.PutInventoryOutput), args.Error(1)
+}
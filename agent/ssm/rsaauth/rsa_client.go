@@ -16,6 +16,7 @@ package rsaauth
 
 import (
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/clockskew"
 	"github.com/aws/amazon-ssm-agent/agent/log"
 	"github.com/aws/amazon-ssm-agent/agent/ssm/authtokenrequest"
 	"github.com/aws/amazon-ssm-agent/common/identity/credentialproviders/iirprovider"
@@ -42,6 +43,7 @@ func NewRsaClient(log log.T, appConfig *appconfig.SsmagentConfig, serverId, regi
 	// whenever we update sdk, we need to make sure it's using Beagle's RSA signing protocol
 	ssmSdk.Handlers.Sign.Clear()
 	ssmSdk.Handlers.Sign.PushBack(SignRsa)
+	clockskew.AddHandlers(log, *appConfig, &ssmSdk.Handlers)
 	return deps.NewAuthTokenClient(ssmSdk)
 }
 
@@ -70,6 +72,7 @@ func NewIirRsaClient(log log.T, appConfig *appconfig.SsmagentConfig, imdsClient
 		Name: "SignIirRsa",
 		Fn:   MakeSignRsaHandler(encodedPrivateKey),
 	})
+	clockskew.AddHandlers(log, *appConfig, &ssmSdk.Handlers)
 
 	return deps.NewAuthTokenClient(ssmSdk)
 }
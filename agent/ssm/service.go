@@ -18,6 +18,7 @@ import (
 	"runtime"
 	"time"
 
+	"github.com/aws/amazon-ssm-agent/agent/clockskew"
 	"github.com/aws/amazon-ssm-agent/agent/context"
 	"github.com/aws/amazon-ssm-agent/agent/log"
 	"github.com/aws/amazon-ssm-agent/agent/platform"
@@ -52,6 +53,7 @@ type Service interface {
 		complianceType string,
 		itemContentHash string,
 		items []*ssm.ComplianceItemEntry) (response *ssm.PutComplianceItemsOutput, err error)
+	PutInventory(log log.T, instanceID string, items []*ssm.InventoryItem) (response *ssm.PutInventoryOutput, err error)
 	SendCommand(log log.T,
 		documentName string,
 		instanceIDs []string,
@@ -102,6 +104,7 @@ func NewService(context context.T) Service {
 	sess.Handlers.Build.PushBack(request.MakeAddToUserAgentHandler(appConfig.Agent.Name, appConfig.Agent.Version))
 
 	ssmService := ssm.New(sess)
+	clockskew.AddHandlers(context.Log(), appConfig, &ssmService.Handlers)
 	return NewSSMService(context, ssmService)
 }
 
@@ -193,6 +196,22 @@ func (svc *sdkService) PutComplianceItems(
 	return
 }
 
+// PutInventory calls the PutInventory SSM API.
+func (svc *sdkService) PutInventory(log log.T, instanceID string, items []*ssm.InventoryItem) (response *ssm.PutInventoryOutput, err error) {
+	params := &ssm.PutInventoryInput{
+		InstanceId: aws.String(instanceID),
+		Items:      items,
+	}
+	log.Debug("Calling PutInventory with params", params)
+	response, err = svc.sdk.PutInventory(params)
+	if err != nil {
+		sdkutil.HandleAwsError(log, err, ssmStopPolicy)
+		return
+	}
+	log.Debug("PutInventory Response", response)
+	return
+}
+
 // UpdateInstanceAssociationStatus calls the ListAssociations SSM API.
 func (svc *sdkService) UpdateInstanceAssociationStatus(log log.T, associationID string, instanceID string, executionResult *ssm.InstanceAssociationExecutionResult) (response *ssm.UpdateInstanceAssociationStatusOutput, err error) {
 	params := ssm.UpdateInstanceAssociationStatusInput{
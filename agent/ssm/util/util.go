@@ -34,7 +34,7 @@ func AwsConfig(logger log.T, appConfig appconfig.SsmagentConfig, service, region
 		Retryer:    newRetryer(),
 		SleepDelay: sleepDelay,
 		HTTPClient: &http.Client{
-			Transport:     network.GetDefaultTransport(logger, appConfig),
+			Transport:     network.GetSharedTransport(logger, appConfig),
 			CheckRedirect: disableHTTPDowngrade,
 			Timeout:       60 * time.Second,
 		},
@@ -16,6 +16,7 @@ package ssmconnectionchannel
 
 import (
 	"sync"
+	"time"
 
 	"github.com/aws/amazon-ssm-agent/agent/context"
 	"github.com/aws/amazon-ssm-agent/agent/contracts"
@@ -33,6 +34,9 @@ const (
 
 	// MGSFailedDueToAccessDenied denotes that the current MGS connection state threw access denied
 	MGSFailedDueToAccessDenied MGSState = "MGSFailedDueToAccessDenied"
+
+	// ControlChannelWaitTimeout is the longest time the modules started lazily wait for the control channel setup
+	ControlChannelWaitTimeout = 30 * time.Second
 )
 
 var (
@@ -41,11 +45,16 @@ var (
 
 	// mdsSwitchChannel is used by MDSInteractor to switch ON/OFF MDS
 	mdsSwitchChannel = make(chan bool)
+
+	// controlChannelSettled is closed once the first attempt to set up the control channel completed
+	controlChannelSettled     = make(chan struct{})
+	controlChannelSettledOnce sync.Once
 )
 
 // SetConnectionChannel sets the Upstream SSM connection channel(MDS or MGS)
 func SetConnectionChannel(context context.T, state MGSState) {
 	connectionChannelMutex.Lock()
+	setControlChannelSettled()
 	defer func() {
 		log := context.Log()
 		log.Infof("SSM Connection channel status is set to %v", connectionChannel.SSMConnectionChannel)
@@ -113,3 +122,31 @@ func GetMDSSwitchChannel() chan bool {
 func CloseMDSSwitchChannel() {
 	close(mdsSwitchChannel)
 }
+
+// SetControlChannelUnavailable releases the modules waiting for the control channel when no control channel is set up
+func SetControlChannelUnavailable() {
+	setControlChannelSettled()
+}
+
+// WaitForControlChannel blocks until the first attempt to set up the control channel completed
+// or the timeout expired, it returns false when the timeout expired
+func WaitForControlChannel(timeout time.Duration) bool {
+	select {
+	case <-GetControlChannelSettledChannel():
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// GetControlChannelSettledChannel returns the golang channel closed once the first attempt to set up the control channel completed
+func GetControlChannelSettledChannel() <-chan struct{} {
+	return controlChannelSettled
+}
+
+// setControlChannelSettled releases the modules waiting for the control channel
+func setControlChannelSettled() {
+	controlChannelSettledOnce.Do(func() {
+		close(controlChannelSettled)
+	})
+}
@@ -15,6 +15,7 @@
 package ssmconnectionchannel
 
 import (
+	"sync"
 	"testing"
 	"time"
 
@@ -109,6 +110,27 @@ func TestGetConnectionChannelReturnsEmptyStringIfConnectionHasNotBeenSet(t *test
 	assert.Equal(t, string(messagingService), "")
 }
 
+func TestWaitForControlChannel(t *testing.T) {
+	resetControlChannelSettled()
+	assert.False(t, WaitForControlChannel(time.Millisecond))
+
+	SetControlChannelUnavailable()
+	assert.True(t, WaitForControlChannel(time.Millisecond))
+	SetControlChannelUnavailable()
+	assert.True(t, WaitForControlChannel(time.Millisecond))
+}
+
+func TestWaitForControlChannel_SetConnectionChannel(t *testing.T) {
+	resetControlChannelSettled()
+	SetConnectionChannel(contextmocks.NewMockDefault(), MGSFailed)
+	assert.True(t, WaitForControlChannel(time.Millisecond))
+}
+
+func resetControlChannelSettled() {
+	controlChannelSettled = make(chan struct{})
+	controlChannelSettledOnce = sync.Once{}
+}
+
 func resetConnectionChannel() {
 	go func() {
 		contextMock := contextmocks.NewMockDefault()
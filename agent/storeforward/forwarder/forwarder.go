@@ -0,0 +1,153 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package forwarder implements the core module sending the results queued while the service could not be reached
+package forwarder
+
+import (
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	compliance "github.com/aws/amazon-ssm-agent/agent/association/compliance/uploader"
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/health"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/plugins/inventory/datauploader"
+	"github.com/aws/amazon-ssm-agent/agent/storeforward"
+)
+
+const (
+	// ModuleName is the name of the store and forward core module
+	ModuleName = "StoreAndForward"
+)
+
+type queue interface {
+	Drain(log log.T, send storeforward.Sender) (sent int, err error)
+	Len() int
+}
+
+// Forwarder periodically sends the queued results, oldest first
+type Forwarder struct {
+	context context.T
+	config  appconfig.StoreAndForwardCfg
+	// queue names in the order they are drained
+	queueNames []string
+	queues     map[string]queue
+	senders    map[string]storeforward.Sender
+
+	mtx      sync.Mutex
+	stopChan chan struct{}
+	doneChan chan struct{}
+}
+
+// NewForwarder returns a store and forward core module
+func NewForwarder(context context.T) *Forwarder {
+	forwarderContext := context.With("[" + ModuleName + "]")
+	config := context.AppConfig().StoreAndForward
+	return &Forwarder{
+		context:    forwarderContext,
+		config:     config,
+		queueNames: []string{storeforward.HealthQueueName, storeforward.InventoryQueueName, storeforward.ComplianceQueueName},
+		queues: map[string]queue{
+			storeforward.HealthQueueName:     storeforward.NewQueue(context.Identity(), storeforward.HealthQueueName, config, 1),
+			storeforward.InventoryQueueName:  storeforward.NewQueue(context.Identity(), storeforward.InventoryQueueName, config, 0),
+			storeforward.ComplianceQueueName: storeforward.NewQueue(context.Identity(), storeforward.ComplianceQueueName, config, 1),
+		},
+		senders: map[string]storeforward.Sender{
+			storeforward.HealthQueueName:     health.NewQueuedPingSender(forwarderContext),
+			storeforward.InventoryQueueName:  datauploader.NewQueuedInventorySender(forwarderContext),
+			storeforward.ComplianceQueueName: compliance.NewQueuedComplianceSender(forwarderContext),
+		},
+	}
+}
+
+// ModuleName returns the module name
+func (f *Forwarder) ModuleName() string {
+	return ModuleName
+}
+
+// ModuleExecute starts the forward loop
+func (f *Forwarder) ModuleExecute() (err error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	f.stopChan = make(chan struct{})
+	f.doneChan = make(chan struct{})
+	go f.forwardLoop(time.Duration(f.config.DrainIntervalSeconds)*time.Second, f.stopChan, f.doneChan)
+	return nil
+}
+
+// ModuleStop stops the forward loop, the queued results stay on disk
+func (f *Forwarder) ModuleStop() (err error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	if f.stopChan != nil {
+		close(f.stopChan)
+		<-f.doneChan
+		f.stopChan = nil
+	}
+	return nil
+}
+
+func (f *Forwarder) forwardLoop(interval time.Duration, stopChan chan struct{}, doneChan chan struct{}) {
+	log := f.context.Log()
+	defer func() {
+		if msg := recover(); msg != nil {
+			log.Errorf("Store and forward panic: %v", msg)
+			log.Errorf("Stacktrace:\n%s", debug.Stack())
+		}
+		close(doneChan)
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+			f.forward()
+		}
+	}
+}
+
+// forward drains the queues until the service cannot be reached anymore.
+// Items the service rejects are dropped, sending them again would fail the same way.
+func (f *Forwarder) forward() {
+	log := f.context.Log()
+	for _, name := range f.queueNames {
+		queue := f.queues[name]
+		if queue.Len() == 0 {
+			continue
+		}
+		send := f.senders[name]
+		sent, err := queue.Drain(log, func(content []byte) error {
+			err := send(content)
+			if err != nil && !storeforward.IsConnectivityError(err) {
+				log.Warnf("Dropping queued %v item rejected by the service: %v", name, err)
+				return nil
+			}
+			return err
+		})
+		if sent > 0 {
+			log.Infof("Sent %d queued %v items", sent, name)
+		}
+		if err != nil {
+			log.Debugf("Service still cannot be reached, %d %v items remain queued: %v", queue.Len(), name, err)
+			return
+		}
+	}
+}
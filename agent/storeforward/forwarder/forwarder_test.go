@@ -0,0 +1,120 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package forwarder
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	contextmocks "github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	"github.com/aws/amazon-ssm-agent/agent/storeforward"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeQueue struct {
+	items [][]byte
+}
+
+func (q *fakeQueue) Drain(log log.T, send storeforward.Sender) (sent int, err error) {
+	for len(q.items) > 0 {
+		if err = send(q.items[0]); err != nil {
+			return sent, err
+		}
+		q.items = q.items[1:]
+		sent++
+	}
+	return sent, nil
+}
+
+func (q *fakeQueue) Len() int {
+	return len(q.items)
+}
+
+var offlineErr = awserr.New(request.ErrCodeRequestError, "send request failed", errors.New("no such host"))
+
+func newTestForwarder(healthSender, inventorySender storeforward.Sender) (*Forwarder, *fakeQueue, *fakeQueue) {
+	healthQueue := &fakeQueue{items: [][]byte{[]byte("ping")}}
+	inventoryQueue := &fakeQueue{items: [][]byte{[]byte("inventory1"), []byte("inventory2")}}
+	return &Forwarder{
+		context:    contextmocks.NewMockDefault(),
+		config:     appconfig.StoreAndForwardCfg{Enabled: true, DrainIntervalSeconds: 60},
+		queueNames: []string{storeforward.HealthQueueName, storeforward.InventoryQueueName},
+		queues: map[string]queue{
+			storeforward.HealthQueueName:    healthQueue,
+			storeforward.InventoryQueueName: inventoryQueue,
+		},
+		senders: map[string]storeforward.Sender{
+			storeforward.HealthQueueName:    healthSender,
+			storeforward.InventoryQueueName: inventorySender,
+		},
+	}, healthQueue, inventoryQueue
+}
+
+func TestForwardDrainsAllQueues(t *testing.T) {
+	var sent []string
+	record := func(content []byte) error {
+		sent = append(sent, string(content))
+		return nil
+	}
+	forwarder, healthQueue, inventoryQueue := newTestForwarder(record, record)
+
+	forwarder.forward()
+
+	assert.Equal(t, []string{"ping", "inventory1", "inventory2"}, sent)
+	assert.Equal(t, 0, healthQueue.Len())
+	assert.Equal(t, 0, inventoryQueue.Len())
+}
+
+func TestForwardStopsWhileOffline(t *testing.T) {
+	inventoryCalls := 0
+	forwarder, healthQueue, inventoryQueue := newTestForwarder(
+		func(content []byte) error { return offlineErr },
+		func(content []byte) error {
+			inventoryCalls++
+			return nil
+		})
+
+	forwarder.forward()
+
+	assert.Equal(t, 1, healthQueue.Len())
+	assert.Equal(t, 2, inventoryQueue.Len())
+	assert.Equal(t, 0, inventoryCalls)
+}
+
+func TestForwardDropsRejectedItems(t *testing.T) {
+	forwarder, _, inventoryQueue := newTestForwarder(
+		func(content []byte) error { return nil },
+		func(content []byte) error {
+			if string(content) == "inventory1" {
+				return awserr.New("ValidationException", "invalid", nil)
+			}
+			return nil
+		})
+
+	forwarder.forward()
+
+	assert.Equal(t, 0, inventoryQueue.Len())
+}
+
+func TestModuleExecuteAndStop(t *testing.T) {
+	forwarder, _, _ := newTestForwarder(nil, nil)
+	assert.Equal(t, ModuleName, forwarder.ModuleName())
+	assert.Nil(t, forwarder.ModuleExecute())
+	assert.Nil(t, forwarder.ModuleStop())
+	assert.Nil(t, forwarder.ModuleStop())
+}
@@ -0,0 +1,239 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package storeforward keeps results on disk while the service cannot be reached, so they can be sent later
+package storeforward
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/jsonutil"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/common/identity"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+const (
+	// HealthQueueName is the queue of health pings
+	HealthQueueName = "health"
+	// InventoryQueueName is the queue of PutInventory requests
+	InventoryQueueName = "inventory"
+	// ComplianceQueueName is the queue of PutComplianceItems requests of association compliance
+	ComplianceQueueName = "compliance"
+
+	itemFileExtension = ".json"
+	tempFileExtension = ".tmp"
+)
+
+// Sender sends a queued item to the service
+type Sender func(content []byte) error
+
+// Queue is a first in, first out queue of json documents persisted on disk.
+// Items are dropped once they are older than the configured maximum age or the queue grows above its size limit.
+type Queue struct {
+	name     string
+	dir      string
+	maxAge   time.Duration
+	maxBytes int64
+	maxItems int
+
+	mtx sync.Mutex
+}
+
+type queueItem struct {
+	path       string
+	enqueuedAt time.Time
+	size       int64
+}
+
+var timeNow = time.Now
+
+// NewQueue returns the queue with the given name, maxItems of 0 does not limit the number of items
+func NewQueue(identity identity.IAgentIdentity, name string, config appconfig.StoreAndForwardCfg, maxItems int) *Queue {
+	return &Queue{
+		name:     name,
+		dir:      QueueDirectory(identity, name),
+		maxAge:   time.Duration(config.MaxAgeHours) * time.Hour,
+		maxBytes: int64(config.MaxSizeMB) * 1024 * 1024,
+		maxItems: maxItems,
+	}
+}
+
+// QueueDirectory returns the directory the items of the queue are stored in
+func QueueDirectory(identity identity.IAgentIdentity, name string) string {
+	shortInstanceID, _ := identity.ShortInstanceID()
+	return filepath.Join(appconfig.DefaultDataStorePath,
+		shortInstanceID,
+		appconfig.StoreAndForwardRootDirName,
+		name)
+}
+
+// IsConnectivityError returns true if the request failed because the service could not be reached
+func IsConnectivityError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	var requestFailure interface{ Code() string }
+	if errors.As(err, &requestFailure) {
+		switch requestFailure.Code() {
+		case request.ErrCodeRequestError, request.ErrCodeResponseTimeout:
+			return true
+		}
+	}
+	return false
+}
+
+// Enqueue persists the item at the end of the queue
+func (q *Queue) Enqueue(log log.T, item interface{}) (err error) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	var content string
+	if content, err = jsonutil.Marshal(item); err != nil {
+		return err
+	}
+	if err = os.MkdirAll(q.dir, appconfig.ReadWriteExecuteAccess); err != nil {
+		return err
+	}
+
+	fileName := fmt.Sprintf("%020d-%d", timeNow().UnixNano(), os.Getpid())
+	tempFile := filepath.Join(q.dir, fileName+tempFileExtension)
+	if err = os.WriteFile(tempFile, []byte(content), appconfig.ReadWriteAccess); err != nil {
+		return err
+	}
+	if err = os.Rename(tempFile, filepath.Join(q.dir, fileName+itemFileExtension)); err != nil {
+		os.Remove(tempFile)
+		return err
+	}
+	log.Debugf("Queued %v item %v until the service can be reached", q.name, fileName)
+	q.enforceLimits(log)
+	return nil
+}
+
+// Drain sends the queued items in order and removes the ones that were sent.
+// It stops at the first item that cannot be sent and returns the number of items sent.
+func (q *Queue) Drain(log log.T, send Sender) (sent int, err error) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	for _, item := range q.expire(log, q.items()) {
+		var content []byte
+		if content, err = os.ReadFile(item.path); err != nil {
+			log.Warnf("Dropping unreadable %v item %v: %v", q.name, item.path, err)
+			os.Remove(item.path)
+			continue
+		}
+		if err = send(content); err != nil {
+			return sent, err
+		}
+		if removeErr := os.Remove(item.path); removeErr != nil {
+			log.Warnf("Failed to remove sent %v item %v: %v", q.name, item.path, removeErr)
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// Clear removes all queued items, e.g. because newer results superseded them
+func (q *Queue) Clear(log log.T) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	for _, item := range q.items() {
+		if err := os.Remove(item.path); err != nil {
+			log.Debugf("Failed to remove %v item %v: %v", q.name, item.path, err)
+		}
+	}
+}
+
+// Len returns the number of queued items
+func (q *Queue) Len() int {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	return len(q.items())
+}
+
+// items returns the queued items, oldest first
+func (q *Queue) items() (items []queueItem) {
+	entries, err := os.ReadDir(q.dir)
+	if err != nil {
+		return nil
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, itemFileExtension) {
+			continue
+		}
+		timestamp, err := strconv.ParseInt(strings.SplitN(name, "-", 2)[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		items = append(items, queueItem{
+			path:       filepath.Join(q.dir, name),
+			enqueuedAt: time.Unix(0, timestamp),
+			size:       info.Size(),
+		})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].path < items[j].path
+	})
+	return items
+}
+
+// expire removes the items older than the maximum age and returns the remaining ones
+func (q *Queue) expire(log log.T, items []queueItem) (remaining []queueItem) {
+	oldest := timeNow().Add(-q.maxAge)
+	for _, item := range items {
+		if item.enqueuedAt.Before(oldest) {
+			log.Infof("Dropping %v item %v queued at %v", q.name, filepath.Base(item.path), item.enqueuedAt.UTC().Format(time.RFC3339))
+			os.Remove(item.path)
+			continue
+		}
+		remaining = append(remaining, item)
+	}
+	return remaining
+}
+
+// enforceLimits drops expired items and the oldest items above the size limits
+func (q *Queue) enforceLimits(log log.T) {
+	items := q.expire(log, q.items())
+	var totalBytes int64
+	for _, item := range items {
+		totalBytes += item.size
+	}
+	for len(items) > 0 && ((q.maxItems > 0 && len(items) > q.maxItems) || totalBytes > q.maxBytes) {
+		log.Infof("Dropping %v item %v, the queue is full", q.name, filepath.Base(items[0].path))
+		os.Remove(items[0].path)
+		totalBytes -= items[0].size
+		items = items[1:]
+	}
+}
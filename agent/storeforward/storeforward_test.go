@@ -0,0 +1,149 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package storeforward
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	logmocks "github.com/aws/amazon-ssm-agent/agent/mocks/log"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/stretchr/testify/assert"
+)
+
+type testItem struct {
+	Value int
+}
+
+func newTestQueue(t *testing.T, maxItems int) (*Queue, *time.Time) {
+	originalTimeNow := timeNow
+	t.Cleanup(func() { timeNow = originalTimeNow })
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+
+	return &Queue{
+		name:     "test",
+		dir:      filepath.Join(t.TempDir(), "test"),
+		maxAge:   24 * time.Hour,
+		maxBytes: 1024,
+		maxItems: maxItems,
+	}, &now
+}
+
+func drainValues(t *testing.T, queue *Queue) (values []int) {
+	sent, err := queue.Drain(logmocks.NewMockLog(), func(content []byte) error {
+		var item testItem
+		assert.Nil(t, json.Unmarshal(content, &item))
+		values = append(values, item.Value)
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, len(values), sent)
+	return values
+}
+
+func TestQueueDrainsInOrder(t *testing.T) {
+	queue, now := newTestQueue(t, 0)
+	log := logmocks.NewMockLog()
+	for i := 1; i <= 3; i++ {
+		*now = now.Add(time.Second)
+		assert.Nil(t, queue.Enqueue(log, testItem{Value: i}))
+	}
+	assert.Equal(t, 3, queue.Len())
+
+	assert.Equal(t, []int{1, 2, 3}, drainValues(t, queue))
+	assert.Equal(t, 0, queue.Len())
+}
+
+func TestQueueDrainStopsAtFailure(t *testing.T) {
+	queue, now := newTestQueue(t, 0)
+	log := logmocks.NewMockLog()
+	for i := 1; i <= 3; i++ {
+		*now = now.Add(time.Second)
+		queue.Enqueue(log, testItem{Value: i})
+	}
+
+	calls := 0
+	sent, err := queue.Drain(log, func(content []byte) error {
+		calls++
+		if calls == 2 {
+			return errors.New("offline")
+		}
+		return nil
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, []int{2, 3}, drainValues(t, queue))
+}
+
+func TestQueueDropsExpiredItems(t *testing.T) {
+	queue, now := newTestQueue(t, 0)
+	log := logmocks.NewMockLog()
+	queue.Enqueue(log, testItem{Value: 1})
+	*now = now.Add(23 * time.Hour)
+	queue.Enqueue(log, testItem{Value: 2})
+	*now = now.Add(2 * time.Hour)
+
+	assert.Equal(t, []int{2}, drainValues(t, queue))
+}
+
+func TestQueueLimits(t *testing.T) {
+	queue, now := newTestQueue(t, 2)
+	log := logmocks.NewMockLog()
+	for i := 1; i <= 3; i++ {
+		*now = now.Add(time.Second)
+		queue.Enqueue(log, testItem{Value: i})
+	}
+	assert.Equal(t, []int{2, 3}, drainValues(t, queue))
+
+	// every item takes about 20 bytes, the oldest are dropped above 1024 bytes
+	queue.maxItems = 0
+	for i := 1; i <= 100; i++ {
+		*now = now.Add(time.Second)
+		queue.Enqueue(log, testItem{Value: i})
+	}
+	values := drainValues(t, queue)
+	assert.True(t, len(values) < 100)
+	assert.Equal(t, 100, values[len(values)-1])
+}
+
+func TestQueueClearIgnoresTempFiles(t *testing.T) {
+	queue, _ := newTestQueue(t, 0)
+	log := logmocks.NewMockLog()
+	queue.Enqueue(log, testItem{Value: 1})
+	tempFile := filepath.Join(queue.dir, fmt.Sprintf("%020d-1%v", time.Now().UnixNano(), tempFileExtension))
+	os.WriteFile(tempFile, []byte("{}"), appconfig.ReadWriteAccess)
+
+	assert.Equal(t, 1, queue.Len())
+	queue.Clear(log)
+	assert.Equal(t, 0, queue.Len())
+	_, err := os.Stat(tempFile)
+	assert.Nil(t, err)
+}
+
+func TestIsConnectivityError(t *testing.T) {
+	assert.False(t, IsConnectivityError(nil))
+	assert.False(t, IsConnectivityError(errors.New("failed")))
+	assert.False(t, IsConnectivityError(awserr.New("ValidationException", "invalid", nil)))
+	assert.True(t, IsConnectivityError(awserr.New(request.ErrCodeRequestError, "send request failed", errors.New("dial tcp: i/o timeout"))))
+	assert.True(t, IsConnectivityError(fmt.Errorf("wrapped: %w", &net.OpError{Op: "dial", Err: errors.New("connection refused")})))
+}
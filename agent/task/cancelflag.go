@@ -15,7 +15,9 @@
 package task
 
 import (
+	"context"
 	"sync"
+	"time"
 )
 
 // State represents the state of a job.
@@ -60,6 +62,49 @@ type CancelFlag interface {
 	Wait() (state State)
 }
 
+// cancelPollInterval is how often NewCancelContext checks flags that cannot be waited on without blocking.
+var cancelPollInterval = 100 * time.Millisecond
+
+// NewCancelContext returns a context that is cancelled as soon as the flag is set to Canceled or ShutDown,
+// so that long-running operations such as downloads can be interrupted. The returned CancelFunc must be
+// called once the operation finishes to release the resources watching the flag.
+func NewCancelContext(flag CancelFlag) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if flag.Canceled() || flag.ShutDown() {
+		cancel()
+		return ctx, cancel
+	}
+
+	go func() {
+		// Wait blocks until the flag is set, so it can only be used when the wait can also end with the context
+		if channeled, ok := flag.(*ChanneledCancelFlag); ok {
+			select {
+			case <-channeled.ch:
+				if channeled.Canceled() || channeled.ShutDown() {
+					cancel()
+				}
+			case <-ctx.Done():
+			}
+			return
+		}
+
+		ticker := time.NewTicker(cancelPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if flag.Canceled() || flag.ShutDown() {
+					cancel()
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ctx, cancel
+}
+
 // ChanneledCancelFlag is a default implementation of the task.CancelFlag interface.
 type ChanneledCancelFlag struct {
 	state  State
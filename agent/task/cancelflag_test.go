@@ -15,6 +15,7 @@ package task
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -67,3 +68,40 @@ func testWait(t *testing.T, state State) {
 	assert.Equal(t, state, <-ch)
 	assert.Equal(t, flag.Canceled(), state == Canceled)
 }
+
+// TestNewCancelContext tests that the context is cancelled once the flag is canceled or shut down
+func TestNewCancelContext(t *testing.T) {
+	for _, state := range []State{Canceled, ShutDown} {
+		cancelFlag := NewChanneledCancelFlag()
+		ctx, cancel := NewCancelContext(cancelFlag)
+		assert.NoError(t, ctx.Err())
+
+		cancelFlag.Set(state)
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Second):
+			assert.Fail(t, "context was not cancelled", "state %v", state)
+		}
+		cancel()
+	}
+}
+
+// TestNewCancelContextCompleted tests that completing the flag does not cancel the context
+func TestNewCancelContextCompleted(t *testing.T) {
+	cancelFlag := NewChanneledCancelFlag()
+	ctx, cancel := NewCancelContext(cancelFlag)
+	defer cancel()
+
+	cancelFlag.Set(Completed)
+	time.Sleep(10 * time.Millisecond)
+	assert.NoError(t, ctx.Err())
+}
+
+// TestNewCancelContextAlreadyCanceled tests that an already canceled flag returns a cancelled context
+func TestNewCancelContextAlreadyCanceled(t *testing.T) {
+	cancelFlag := NewChanneledCancelFlag()
+	cancelFlag.Set(Canceled)
+	ctx, cancel := NewCancelContext(cancelFlag)
+	defer cancel()
+	assert.Error(t, ctx.Err())
+}
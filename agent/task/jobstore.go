@@ -61,6 +61,13 @@ func (t *JobStore) DeleteJob(jobID string) {
 	delete(t.jobs, jobID)
 }
 
+// JobCount returns the number of jobs of this task.
+func (t *JobStore) JobCount() int {
+	t.m.RLock()
+	defer t.m.RUnlock()
+	return len(t.jobs)
+}
+
 // DeleteAllJobs deletes all the jobs of this task.
 // Returns the deleted jobs.
 func (t *JobStore) DeleteAllJobs() map[string]*JobToken {
@@ -67,6 +67,9 @@ type Pool interface {
 	// HasJob returns if jobStore has specified job
 	HasJob(jobID string) bool
 
+	// JobCount returns the number of jobs submitted and not completed yet
+	JobCount() int
+
 	// BufferTokensIssued returns the current buffer token size
 	BufferTokensIssued() int
 
@@ -319,6 +322,11 @@ func (p *pool) HasJob(jobID string) bool {
 	return found
 }
 
+// JobCount returns the number of jobs submitted and not completed yet
+func (p *pool) JobCount() int {
+	return p.jobStore.JobCount()
+}
+
 // Cancel cancels the job with the given id.
 func (p *pool) Cancel(jobID string) (canceled bool) {
 	jobToken, found := p.jobStore.GetJob(jobID)
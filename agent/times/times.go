@@ -84,3 +84,55 @@ func ParseIsoDashUTC(t string) (time.Time, error) {
 	}
 	return time.Date(y, m, d, h, min, s, ms*1000000, time.UTC), nil
 }
+
+// DailyTimeLayout is the layout of the start and end time of a daily time window, e.g. 22:00.
+const DailyTimeLayout = "15:04"
+
+// DailyWindowEnd checks whether t falls in the daily window from start to end, evaluated in the location of t,
+// and returns the end of that occurrence of the window. A window that ends before it starts spans midnight and
+// belongs to the day it starts on. The window applies on the given days, Mon to Sun, or every day when empty.
+func DailyWindowEnd(t time.Time, start string, end string, days []string) (time.Time, bool) {
+	startTime, err := time.Parse(DailyTimeLayout, start)
+	if err != nil {
+		return time.Time{}, false
+	}
+	endTime, err := time.Parse(DailyTimeLayout, end)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	minute := t.Hour()*60 + t.Minute()
+	startMinute := startTime.Hour()*60 + startTime.Minute()
+	endMinute := endTime.Hour()*60 + endTime.Minute()
+	startDay := t
+	if startMinute <= endMinute {
+		if minute < startMinute || minute >= endMinute {
+			return time.Time{}, false
+		}
+	} else if minute < endMinute {
+		startDay = t.AddDate(0, 0, -1)
+	} else if minute < startMinute {
+		return time.Time{}, false
+	}
+
+	if len(days) > 0 && !isWeekdayInList(startDay.Weekday(), days) {
+		return time.Time{}, false
+	}
+
+	windowEnd := time.Date(t.Year(), t.Month(), t.Day(), endTime.Hour(), endTime.Minute(), 0, 0, t.Location())
+	if !windowEnd.After(t) {
+		windowEnd = windowEnd.AddDate(0, 0, 1)
+	}
+	return windowEnd, true
+}
+
+// isWeekdayInList checks whether the abbreviated name of the weekday, e.g. Mon, is in the list.
+func isWeekdayInList(weekday time.Weekday, days []string) bool {
+	name := weekday.String()[:3]
+	for _, day := range days {
+		if day == name {
+			return true
+		}
+	}
+	return false
+}
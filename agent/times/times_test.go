@@ -55,3 +55,36 @@ func TestToIsoDashUTC(t *testing.T) {
 	found := ToIsoDashUTC(tm0)
 	assert.Equal(t, expected, found)
 }
+
+func TestDailyWindowEnd(t *testing.T) {
+	// 2024-01-05 is a Friday
+	friday := func(hour, minute int) time.Time {
+		return time.Date(2024, 1, 5, hour, minute, 0, 0, time.UTC)
+	}
+
+	end, ok := DailyWindowEnd(friday(23, 0), "22:00", "06:00", nil)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2024, 1, 6, 6, 0, 0, 0, time.UTC), end)
+	end, ok = DailyWindowEnd(friday(5, 59), "22:00", "06:00", nil)
+	assert.True(t, ok)
+	assert.Equal(t, friday(6, 0), end)
+	_, ok = DailyWindowEnd(friday(6, 0), "22:00", "06:00", nil)
+	assert.False(t, ok)
+	_, ok = DailyWindowEnd(friday(21, 59), "22:00", "06:00", nil)
+	assert.False(t, ok)
+
+	end, ok = DailyWindowEnd(friday(12, 0), "12:00", "13:30", nil)
+	assert.True(t, ok)
+	assert.Equal(t, friday(13, 30), end)
+	_, ok = DailyWindowEnd(friday(13, 30), "12:00", "13:30", nil)
+	assert.False(t, ok)
+
+	_, ok = DailyWindowEnd(friday(23, 0), "22:00", "06:00", []string{"Fri", "Sat"})
+	assert.True(t, ok)
+	// the early morning part belongs to the window started the day before, Thursday
+	_, ok = DailyWindowEnd(friday(3, 0), "22:00", "06:00", []string{"Fri", "Sat"})
+	assert.False(t, ok)
+
+	_, ok = DailyWindowEnd(friday(23, 0), "10pm", "06:00", nil)
+	assert.False(t, ok)
+}
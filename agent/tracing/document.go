@@ -0,0 +1,70 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tracing
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/contracts"
+)
+
+// StartDocumentTrace starts the trace of a received document and records the span parsing the message.
+// The trace is ended right away if the message could not be parsed.
+func StartDocumentTrace(messageID string, upstream contracts.UpstreamServiceName, documentType contracts.DocumentType, receiveTime, parseStart time.Time, parseErr error) {
+	StartTrace(messageID, receiveTime, map[string]string{
+		"ssm.upstream":      string(upstream),
+		"ssm.document_type": string(documentType),
+	})
+	RecordSpan(messageID, SpanParse, parseStart, time.Now(), parseErr, nil)
+	if parseErr != nil {
+		EndTrace(messageID, parseErr, nil)
+	}
+}
+
+// RecordExecution records the execution of a document and one span per executed plugin
+func RecordExecution(messageID string, start time.Time, result *contracts.DocumentResult) {
+	if !IsTraced(messageID) {
+		return
+	}
+	RecordSpan(messageID, SpanExecute, start, time.Now(), statusError(result.Status), map[string]string{
+		"ssm.document_name": result.DocumentName,
+		"ssm.status":        string(result.Status),
+	})
+	for pluginID, pluginResult := range result.PluginResults {
+		if pluginResult == nil || pluginResult.StartDateTime.IsZero() || pluginResult.EndDateTime.IsZero() {
+			continue
+		}
+		RecordSpan(messageID, SpanPlugin, pluginResult.StartDateTime, pluginResult.EndDateTime, statusError(pluginResult.Status), map[string]string{
+			"ssm.plugin":    pluginResult.PluginName,
+			"ssm.plugin_id": pluginID,
+			"ssm.status":    string(pluginResult.Status),
+		})
+	}
+}
+
+// EndDocumentTrace records the span sending the final reply and ends the trace with the document status
+func EndDocumentTrace(messageID string, status contracts.ResultStatus, replyStart time.Time, replyErr error) {
+	RecordSpan(messageID, SpanReply, replyStart, time.Now(), replyErr, nil)
+	EndTrace(messageID, statusError(status), map[string]string{"ssm.status": string(status)})
+}
+
+// statusError returns an error for unsuccessful document and plugin statuses
+func statusError(status contracts.ResultStatus) error {
+	switch status {
+	case contracts.ResultStatusFailed, contracts.ResultStatusTimedOut, contracts.ResultStatusCancelled, contracts.ResultStatusTestFailure:
+		return fmt.Errorf("finished with status %v", status)
+	}
+	return nil
+}
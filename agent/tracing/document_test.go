@@ -0,0 +1,75 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tracing
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/contracts"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocumentTrace(t *testing.T) {
+	defaultTracer.setEnabled(true)
+	defer defaultTracer.setEnabled(false)
+	start := time.Now()
+
+	StartDocumentTrace("message1", contracts.MessageGatewayService, contracts.SendCommand, start, start, nil)
+	RecordExecution("message1", start, &contracts.DocumentResult{
+		Status:       contracts.ResultStatusFailed,
+		DocumentName: "AWS-RunShellScript",
+		PluginResults: map[string]*contracts.PluginResult{
+			"step1": {PluginName: "aws:runShellScript", Status: contracts.ResultStatusFailed, StartDateTime: start, EndDateTime: start.Add(time.Second)},
+			"step2": {PluginName: "aws:runShellScript", Status: contracts.ResultStatusNotStarted},
+		},
+	})
+	EndDocumentTrace("message1", contracts.ResultStatusFailed, start, nil)
+
+	spans, _ := defaultTracer.drain()
+	assert.Equal(t, []string{SpanParse, SpanExecute, SpanPlugin, SpanReply, SpanDocument}, spanNames(spans))
+	assert.Equal(t, "AWS-RunShellScript", spans[1].Attributes["ssm.document_name"])
+	assert.Equal(t, "step1", spans[2].Attributes["ssm.plugin_id"])
+	assert.NotNil(t, spans[2].Err)
+	assert.Equal(t, string(contracts.MessageGatewayService), spans[4].Attributes["ssm.upstream"])
+	assert.Equal(t, string(contracts.ResultStatusFailed), spans[4].Attributes["ssm.status"])
+	assert.NotNil(t, spans[4].Err)
+	assert.False(t, IsTraced("message1"))
+}
+
+func TestDocumentTraceParseError(t *testing.T) {
+	defaultTracer.setEnabled(true)
+	defer defaultTracer.setEnabled(false)
+
+	StartDocumentTrace("message1", contracts.MessageDeliveryService, contracts.SendCommand, time.Now(), time.Now(), errors.New("invalid payload"))
+
+	spans, _ := defaultTracer.drain()
+	assert.Equal(t, []string{SpanParse, SpanDocument}, spanNames(spans))
+	assert.Equal(t, "invalid payload", spans[1].Err.Error())
+	assert.False(t, IsTraced("message1"))
+}
+
+func TestStatusError(t *testing.T) {
+	assert.Nil(t, statusError(contracts.ResultStatusSuccess))
+	assert.Nil(t, statusError(contracts.ResultStatusSkipped))
+	assert.NotNil(t, statusError(contracts.ResultStatusTimedOut))
+}
+
+func spanNames(spans []Span) (names []string) {
+	for _, span := range spans {
+		names = append(names, span.Name)
+	}
+	return
+}
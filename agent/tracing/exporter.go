@@ -0,0 +1,272 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/context"
+	"github.com/aws/amazon-ssm-agent/agent/network"
+	"github.com/aws/amazon-ssm-agent/agent/version"
+)
+
+const (
+	// ModuleName is the name of the tracing core module
+	ModuleName = "TracingExporter"
+
+	scopeName          = "github.com/aws/amazon-ssm-agent/agent/tracing"
+	exportTimeout      = 10 * time.Second
+	maxSpansPerRequest = 512
+
+	// OTLP span kind and status codes
+	spanKindInternal = 1
+	statusCodeOk     = 1
+	statusCodeError  = 2
+)
+
+// Exporter periodically sends the finished spans to the OTLP/HTTP endpoint configured in appconfig
+type Exporter struct {
+	context    context.T
+	tracer     *tracer
+	config     appconfig.TracingCfg
+	httpClient *http.Client
+	resource   otlpResource
+
+	mtx      sync.Mutex
+	stopChan chan struct{}
+	doneChan chan struct{}
+}
+
+// NewExporter returns a tracing core module
+func NewExporter(context context.T) *Exporter {
+	appConfig := context.AppConfig()
+	exporterContext := context.With("[" + ModuleName + "]")
+	instanceID, _ := context.Identity().InstanceID()
+	return &Exporter{
+		context: exporterContext,
+		tracer:  defaultTracer,
+		config:  appConfig.Tracing,
+		httpClient: &http.Client{
+			Transport: network.GetSharedTransport(exporterContext.Log(), appConfig),
+			Timeout:   exportTimeout,
+		},
+		resource: otlpResource{Attributes: toOtlpAttributes(map[string]string{
+			"service.name":    appConfig.Tracing.ServiceName,
+			"service.version": version.Version,
+			"host.id":         instanceID,
+		})},
+	}
+}
+
+// ModuleName returns the module name
+func (e *Exporter) ModuleName() string {
+	return ModuleName
+}
+
+// ModuleExecute enables tracing and starts the export loop
+func (e *Exporter) ModuleExecute() (err error) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	e.stopChan = make(chan struct{})
+	e.doneChan = make(chan struct{})
+	e.tracer.setEnabled(true)
+	go e.exportLoop(time.Duration(e.config.ExportIntervalSeconds)*time.Second, e.stopChan, e.doneChan)
+	e.context.Log().Infof("Exporting traces to %s every %d seconds", e.config.OtlpEndpoint, e.config.ExportIntervalSeconds)
+	return nil
+}
+
+// ModuleStop disables tracing and exports the remaining spans
+func (e *Exporter) ModuleStop() (err error) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	e.tracer.setEnabled(false)
+	if e.stopChan != nil {
+		close(e.stopChan)
+		<-e.doneChan
+		e.stopChan = nil
+	}
+	return nil
+}
+
+func (e *Exporter) exportLoop(interval time.Duration, stopChan chan struct{}, doneChan chan struct{}) {
+	log := e.context.Log()
+	defer func() {
+		if msg := recover(); msg != nil {
+			log.Errorf("Tracing exporter panic: %v", msg)
+			log.Errorf("Stacktrace:\n%s", debug.Stack())
+		}
+		close(doneChan)
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopChan:
+			e.export()
+			return
+		case <-ticker.C:
+			e.export()
+		}
+	}
+}
+
+// export sends the finished spans, spans are dropped if the collector cannot be reached
+func (e *Exporter) export() {
+	log := e.context.Log()
+	spans, dropped := e.tracer.drain()
+	if dropped > 0 {
+		log.Warnf("Dropped %d spans because the export queue was full", dropped)
+	}
+	for len(spans) > 0 {
+		batch := spans
+		if len(batch) > maxSpansPerRequest {
+			batch = spans[:maxSpansPerRequest]
+		}
+		spans = spans[len(batch):]
+		if err := e.send(batch); err != nil {
+			log.Warnf("Failed to export %d spans: %v", len(batch)+len(spans), err)
+			return
+		}
+	}
+}
+
+func (e *Exporter) send(spans []Span) (err error) {
+	var body []byte
+	if body, err = json.Marshal(e.buildRequest(spans)); err != nil {
+		return
+	}
+	var request *http.Request
+	if request, err = http.NewRequest(http.MethodPost, e.config.OtlpEndpoint, bytes.NewReader(body)); err != nil {
+		return
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for key, value := range e.config.OtlpHeaders {
+		request.Header.Set(key, value)
+	}
+
+	var response *http.Response
+	if response, err = e.httpClient.Do(request); err != nil {
+		return
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, response.Body)
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("collector responded with status %s", response.Status)
+	}
+	return nil
+}
+
+// OTLP/HTTP JSON encoding of ExportTraceServiceRequest
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpAttribute struct {
+	Key   string         `json:"key"`
+	Value otlpStringAttr `json:"value"`
+}
+
+type otlpStringAttr struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+func (e *Exporter) buildRequest(spans []Span) otlpRequest {
+	otlpSpans := make([]otlpSpan, 0, len(spans))
+	for _, span := range spans {
+		status := otlpStatus{Code: statusCodeOk}
+		if span.Err != nil {
+			status = otlpStatus{Code: statusCodeError, Message: span.Err.Error()}
+		}
+		otlpSpans = append(otlpSpans, otlpSpan{
+			TraceID:           span.TraceID,
+			SpanID:            span.SpanID,
+			ParentSpanID:      span.ParentSpanID,
+			Name:              span.Name,
+			Kind:              spanKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(span.StartTime.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.EndTime.UnixNano(), 10),
+			Attributes:        toOtlpAttributes(span.Attributes),
+			Status:            status,
+		})
+	}
+	return otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource: e.resource,
+		ScopeSpans: []otlpScopeSpans{{
+			Scope: otlpScope{Name: scopeName, Version: version.Version},
+			Spans: otlpSpans,
+		}},
+	}}}
+}
+
+func toOtlpAttributes(attributes map[string]string) []otlpAttribute {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	result := make([]otlpAttribute, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, otlpAttribute{Key: key, Value: otlpStringAttr{StringValue: attributes[key]}})
+	}
+	return result
+}
@@ -0,0 +1,106 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tracing
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	contextmocks "github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestExporter(endpoint string) *Exporter {
+	return &Exporter{
+		context: contextmocks.NewMockDefault(),
+		tracer:  newTestTracer(),
+		config: appconfig.TracingCfg{
+			OtlpEndpoint:          endpoint,
+			OtlpHeaders:           map[string]string{"Authorization": "Bearer token"},
+			ExportIntervalSeconds: 60,
+		},
+		httpClient: &http.Client{Timeout: time.Second},
+		resource:   otlpResource{Attributes: toOtlpAttributes(map[string]string{"service.name": "amazon-ssm-agent"})},
+	}
+}
+
+func TestExport(t *testing.T) {
+	requests := make(chan otlpRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		var request otlpRequest
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&request))
+		requests <- request
+	}))
+	defer server.Close()
+
+	exporter := newTestExporter(server.URL)
+	start := time.Unix(1700000000, 0)
+	origTimeNow := timeNow
+	timeNow = func() time.Time { return start.Add(time.Minute) }
+	t.Cleanup(func() { timeNow = origTimeNow })
+	exporter.tracer.startTrace("message1", start, nil)
+	exporter.tracer.recordSpan("message1", SpanExecute, start, start.Add(time.Second), errors.New("failed"), nil)
+	exporter.export()
+
+	request := <-requests
+	assert.Equal(t, 1, len(request.ResourceSpans))
+	assert.Equal(t, "service.name", request.ResourceSpans[0].Resource.Attributes[0].Key)
+	spans := request.ResourceSpans[0].ScopeSpans[0].Spans
+	assert.Equal(t, 1, len(spans))
+	assert.Equal(t, SpanExecute, spans[0].Name)
+	assert.Equal(t, "1700000000000000000", spans[0].StartTimeUnixNano)
+	assert.Equal(t, "1700000001000000000", spans[0].EndTimeUnixNano)
+	assert.Equal(t, otlpStatus{Code: statusCodeError, Message: "failed"}, spans[0].Status)
+	assert.Equal(t, []otlpAttribute{{Key: "ssm.message_id", Value: otlpStringAttr{StringValue: "message1"}}}, spans[0].Attributes)
+}
+
+func TestSendError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	exporter := newTestExporter(server.URL)
+	err := exporter.send([]Span{{Name: SpanReply}})
+	assert.NotNil(t, err)
+}
+
+func TestModuleStopExportsRemainingSpans(t *testing.T) {
+	requests := make(chan otlpRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request otlpRequest
+		json.NewDecoder(r.Body).Decode(&request)
+		requests <- request
+	}))
+	defer server.Close()
+
+	exporter := newTestExporter(server.URL)
+	exporter.tracer.setEnabled(false)
+	assert.Equal(t, ModuleName, exporter.ModuleName())
+	assert.Nil(t, exporter.ModuleExecute())
+	exporter.tracer.startTrace("message1", time.Now(), nil)
+	exporter.tracer.endTrace("message1", nil, nil)
+	assert.Nil(t, exporter.ModuleStop())
+
+	request := <-requests
+	assert.Equal(t, SpanDocument, request.ResourceSpans[0].ScopeSpans[0].Spans[0].Name)
+	assert.Nil(t, exporter.ModuleStop())
+}
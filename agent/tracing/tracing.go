@@ -0,0 +1,230 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package tracing records spans for the document and session lifecycle (receive, parse, plugin execution, reply)
+// and exports them to an OpenTelemetry collector over OTLP/HTTP.
+package tracing
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	// maxActiveTraces bounds the traces kept in memory, the oldest trace is abandoned to start a new one
+	maxActiveTraces = 1000
+	// maxTraceAge abandons the traces of documents which never reply, e.g. after a crash,
+	// it exceeds the longest execution timeout of a document
+	maxTraceAge = 49 * time.Hour
+	// maxQueuedSpans bounds the finished spans waiting for export when the collector is unreachable
+	maxQueuedSpans = 4096
+)
+
+// Span names of the document lifecycle
+const (
+	SpanDocument = "ssm.document"
+	SpanReceive  = "ssm.receive"
+	SpanParse    = "ssm.parse"
+	SpanExecute  = "ssm.execute"
+	SpanPlugin   = "ssm.plugin"
+	SpanReply    = "ssm.reply"
+)
+
+// Span is a finished or in progress operation of a trace
+type Span struct {
+	TraceID      string
+	SpanID       string
+	ParentSpanID string
+	Name         string
+	StartTime    time.Time
+	EndTime      time.Time
+	Attributes   map[string]string
+	Err          error
+}
+
+// tracer keeps the root span of every document in progress and the finished spans waiting for export
+type tracer struct {
+	mtx      sync.Mutex
+	enabled  bool
+	active   map[string]*Span
+	finished []Span
+	dropped  int
+}
+
+var defaultTracer = &tracer{active: map[string]*Span{}}
+
+// errTraceAbandoned ends the root span of a trace which was evicted before the document replied
+var errTraceAbandoned = errors.New("trace abandoned before the document completed")
+
+// Assign functions to variables to allow unittest to override
+var timeNow = time.Now
+
+// StartTrace starts the root span of a document identified by its message id
+func StartTrace(messageID string, start time.Time, attributes map[string]string) {
+	defaultTracer.startTrace(messageID, start, attributes)
+}
+
+// RecordSpan records a finished child span of the document's trace, it is a no-op if the document is not traced
+func RecordSpan(messageID, name string, start, end time.Time, err error, attributes map[string]string) {
+	defaultTracer.recordSpan(messageID, name, start, end, err, attributes)
+}
+
+// EndTrace ends the root span of the document's trace
+func EndTrace(messageID string, err error, attributes map[string]string) {
+	defaultTracer.endTrace(messageID, err, attributes)
+}
+
+// IsTraced returns true if the document has a trace in progress
+func IsTraced(messageID string) bool {
+	defaultTracer.mtx.Lock()
+	defer defaultTracer.mtx.Unlock()
+	_, found := defaultTracer.active[messageID]
+	return found
+}
+
+func (t *tracer) setEnabled(enabled bool) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.enabled = enabled
+	if !enabled {
+		t.active = map[string]*Span{}
+	}
+}
+
+func (t *tracer) startTrace(messageID string, start time.Time, attributes map[string]string) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if !t.enabled || messageID == "" {
+		return
+	}
+	if _, found := t.active[messageID]; found {
+		return
+	}
+	if len(t.active) >= maxActiveTraces {
+		t.evictExpired()
+	}
+	if len(t.active) >= maxActiveTraces {
+		t.evictOldest()
+	}
+	t.active[messageID] = &Span{
+		TraceID:    newID(16),
+		SpanID:     newID(8),
+		Name:       SpanDocument,
+		StartTime:  start,
+		Attributes: withMessageID(attributes, messageID),
+	}
+}
+
+func (t *tracer) recordSpan(messageID, name string, start, end time.Time, err error, attributes map[string]string) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	root, found := t.active[messageID]
+	if !t.enabled || !found {
+		return
+	}
+	t.queue(Span{
+		TraceID:      root.TraceID,
+		SpanID:       newID(8),
+		ParentSpanID: root.SpanID,
+		Name:         name,
+		StartTime:    start,
+		EndTime:      end,
+		Attributes:   withMessageID(attributes, messageID),
+		Err:          err,
+	})
+}
+
+func (t *tracer) endTrace(messageID string, err error, attributes map[string]string) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	root, found := t.active[messageID]
+	if !found {
+		return
+	}
+	delete(t.active, messageID)
+	root.EndTime = timeNow()
+	root.Err = err
+	for key, value := range attributes {
+		root.Attributes[key] = value
+	}
+	t.queue(*root)
+}
+
+// evictExpired abandons the traces older than maxTraceAge, it must be called with the lock held
+func (t *tracer) evictExpired() {
+	expiry := timeNow().Add(-maxTraceAge)
+	for messageID, root := range t.active {
+		if root.StartTime.Before(expiry) {
+			t.abandon(messageID, root)
+		}
+	}
+}
+
+// evictOldest abandons the trace started first, it must be called with the lock held
+func (t *tracer) evictOldest() {
+	var oldestID string
+	var oldest *Span
+	for messageID, root := range t.active {
+		if oldest == nil || root.StartTime.Before(oldest.StartTime) {
+			oldestID, oldest = messageID, root
+		}
+	}
+	if oldest != nil {
+		t.abandon(oldestID, oldest)
+	}
+}
+
+// abandon removes the trace and exports its root span as failed, it must be called with the lock held
+func (t *tracer) abandon(messageID string, root *Span) {
+	delete(t.active, messageID)
+	root.EndTime = timeNow()
+	root.Err = errTraceAbandoned
+	t.queue(*root)
+}
+
+// queue must be called with the lock held
+func (t *tracer) queue(span Span) {
+	if len(t.finished) >= maxQueuedSpans {
+		t.dropped++
+		return
+	}
+	t.finished = append(t.finished, span)
+}
+
+// drain returns the finished spans and the number of spans dropped since the last call,
+// the expired traces are abandoned first so their root spans are exported
+func (t *tracer) drain() (spans []Span, dropped int) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.evictExpired()
+	spans, dropped = t.finished, t.dropped
+	t.finished, t.dropped = nil, 0
+	return
+}
+
+func withMessageID(attributes map[string]string, messageID string) map[string]string {
+	result := map[string]string{"ssm.message_id": messageID}
+	for key, value := range attributes {
+		result[key] = value
+	}
+	return result
+}
+
+func newID(size int) string {
+	id := make([]byte, size)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
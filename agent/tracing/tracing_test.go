@@ -0,0 +1,130 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package tracing
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestTracer() *tracer {
+	testTracer := &tracer{active: map[string]*Span{}}
+	testTracer.setEnabled(true)
+	return testTracer
+}
+
+func TestTraceLifecycle(t *testing.T) {
+	testTracer := newTestTracer()
+	start := time.Now()
+
+	testTracer.startTrace("message1", start, map[string]string{"ssm.upstream": "MessageGatewayService"})
+	testTracer.recordSpan("message1", SpanParse, start, start.Add(time.Millisecond), nil, nil)
+	testTracer.recordSpan("message1", SpanPlugin, start, start.Add(time.Second), errors.New("exit status 1"), map[string]string{"ssm.plugin": "aws:runShellScript"})
+	testTracer.endTrace("message1", nil, map[string]string{"ssm.status": "Failed"})
+
+	spans, dropped := testTracer.drain()
+	assert.Equal(t, 0, dropped)
+	assert.Equal(t, 3, len(spans))
+	root := spans[2]
+	assert.Equal(t, SpanDocument, root.Name)
+	assert.Equal(t, 32, len(root.TraceID))
+	assert.Equal(t, 16, len(root.SpanID))
+	assert.Empty(t, root.ParentSpanID)
+	assert.Equal(t, map[string]string{"ssm.message_id": "message1", "ssm.upstream": "MessageGatewayService", "ssm.status": "Failed"}, root.Attributes)
+	for _, child := range spans[:2] {
+		assert.Equal(t, root.TraceID, child.TraceID)
+		assert.Equal(t, root.SpanID, child.ParentSpanID)
+	}
+	assert.Equal(t, SpanParse, spans[0].Name)
+	assert.Nil(t, spans[0].Err)
+	assert.Equal(t, "exit status 1", spans[1].Err.Error())
+	assert.Equal(t, "aws:runShellScript", spans[1].Attributes["ssm.plugin"])
+
+	// the trace is removed once ended
+	testTracer.recordSpan("message1", SpanReply, start, start, nil, nil)
+	spans, _ = testTracer.drain()
+	assert.Empty(t, spans)
+}
+
+func TestTracerDisabled(t *testing.T) {
+	testTracer := &tracer{active: map[string]*Span{}}
+
+	testTracer.startTrace("message1", time.Now(), nil)
+	testTracer.recordSpan("message1", SpanParse, time.Now(), time.Now(), nil, nil)
+	testTracer.endTrace("message1", nil, nil)
+
+	spans, _ := testTracer.drain()
+	assert.Empty(t, spans)
+}
+
+func TestTracerLimits(t *testing.T) {
+	testTracer := newTestTracer()
+	for i := 0; i < maxActiveTraces+1; i++ {
+		testTracer.startTrace(newID(4), time.Now(), nil)
+	}
+	assert.Equal(t, maxActiveTraces, len(testTracer.active))
+	spans, dropped := testTracer.drain()
+	assert.Equal(t, 1, len(spans))
+	assert.Equal(t, errTraceAbandoned, spans[0].Err)
+	assert.Equal(t, 0, dropped)
+
+	for messageID := range testTracer.active {
+		for i := 0; i < 5; i++ {
+			testTracer.recordSpan(messageID, SpanPlugin, time.Now(), time.Now(), nil, nil)
+		}
+	}
+	spans, dropped = testTracer.drain()
+	assert.Equal(t, maxQueuedSpans, len(spans))
+	assert.Equal(t, 5*maxActiveTraces-maxQueuedSpans, dropped)
+}
+
+func TestTracerEvictsOldestTrace(t *testing.T) {
+	testTracer := newTestTracer()
+	start := time.Now()
+	testTracer.startTrace("oldest", start.Add(-time.Hour), nil)
+	for i := 1; i < maxActiveTraces; i++ {
+		testTracer.startTrace(newID(4), start.Add(time.Duration(i)*time.Millisecond), nil)
+	}
+
+	testTracer.startTrace("message1", start, nil)
+	assert.Equal(t, maxActiveTraces, len(testTracer.active))
+	assert.Contains(t, testTracer.active, "message1")
+	assert.NotContains(t, testTracer.active, "oldest")
+
+	spans, _ := testTracer.drain()
+	assert.Equal(t, 1, len(spans))
+	assert.Equal(t, "oldest", spans[0].Attributes["ssm.message_id"])
+	assert.Equal(t, errTraceAbandoned, spans[0].Err)
+}
+
+func TestTracerEvictsExpiredTraces(t *testing.T) {
+	now := time.Now()
+	origTimeNow := timeNow
+	timeNow = func() time.Time { return now }
+	t.Cleanup(func() { timeNow = origTimeNow })
+	testTracer := newTestTracer()
+
+	testTracer.startTrace("abandoned", now.Add(-maxTraceAge-time.Minute), nil)
+	testTracer.startTrace("message1", now.Add(-maxTraceAge+time.Minute), nil)
+	spans, _ := testTracer.drain()
+	assert.Equal(t, 1, len(spans))
+	assert.Equal(t, "abandoned", spans[0].Attributes["ssm.message_id"])
+	assert.Equal(t, errTraceAbandoned, spans[0].Err)
+	assert.Equal(t, now, spans[0].EndTime)
+	assert.NotContains(t, testTracer.active, "abandoned")
+	assert.Contains(t, testTracer.active, "message1")
+}
@@ -193,6 +193,14 @@ func validateUpdateVersion(log log.T, detail *UpdateDetail, info updateinfo.T) e
 	return nil
 }
 
+// unsupportedPlatformMessage adds the reasons the platform is not supported to the message of a failed install script
+func unsupportedPlatformMessage(info updateinfo.T, message string) string {
+	if err := info.CheckCompatibility(); err != nil {
+		return fmt.Sprintf("%s: %v", message, err)
+	}
+	return message
+}
+
 func validateInactiveVersion(context context.T, info updateinfo.T, detail *UpdateDetail) (err error) {
 	context.Log().Info("Validating inactive version for amazon ssm agent")
 	var isActive bool
@@ -410,6 +418,11 @@ func validateUpdateParam(mgr *updateManager, logger log.T, updateDetail *UpdateD
 		}
 	}
 
+	// Checking the platform supports the install scripts, reporting each reason it does not
+	if err = mgr.Info.CheckCompatibility(); err != nil {
+		return mgr.failed(updateDetail, logger, updateconstants.ErrorUnsupportedPlatform, err.Error(), true)
+	}
+
 	return mgr.populateUrlHash(mgr, logger, updateDetail)
 }
 
@@ -502,6 +515,7 @@ func proceedUpdate(mgr *updateManager, log log.T, updateDetail *UpdateDetail) (e
 				updateDetail.SourceVersion)
 			mgr.subStatus = updateconstants.Downgrade
 			if exitCode == updateconstants.ExitCodeUnsupportedPlatform {
+				message = unsupportedPlatformMessage(mgr.Info, message)
 				return mgr.failed(updateDetail, log, updateconstants.ErrorUnsupportedServiceManager, message, true)
 			}
 			return mgr.failed(updateDetail, log, updateconstants.ErrorUninstallFailed, message, true)
@@ -524,6 +538,7 @@ func proceedUpdate(mgr *updateManager, log log.T, updateDetail *UpdateDetail) (e
 		updateDetail.AppendError(log, message)
 
 		if exitCode == updateconstants.ExitCodeUnsupportedPlatform {
+			message = unsupportedPlatformMessage(mgr.Info, message)
 			return mgr.failed(updateDetail, log, updateconstants.ErrorUnsupportedServiceManager, message, true)
 		}
 		if exitCode == updateconstants.ExitCodeUpdateFailedDueToSnapd {
@@ -623,6 +638,7 @@ func rollbackInstallation(mgr *updateManager, log log.T, updateDetail *UpdateDet
 		// this case is not possible at all as we would have caught it in the earlier uninstall/install
 		// if this happens, something else is wrong so it is better to have this code for differentiation
 		if exitCode == updateconstants.ExitCodeUnsupportedPlatform {
+			message = unsupportedPlatformMessage(mgr.Info, message)
 			return mgr.failed(updateDetail, log, updateconstants.ErrorUnsupportedServiceManager, message, true)
 		}
 		return mgr.failed(updateDetail, log, updateconstants.ErrorUninstallFailed, message, false)
@@ -639,6 +655,7 @@ func rollbackInstallation(mgr *updateManager, log log.T, updateDetail *UpdateDet
 		// this case is not possible at all as we would have caught it in the earlier uninstall/install
 		// if this happens, something else is wrong and it is better to have this code for differentiation
 		if exitCode == updateconstants.ExitCodeUnsupportedPlatform {
+			message = unsupportedPlatformMessage(mgr.Info, message)
 			return mgr.failed(updateDetail, log, updateconstants.ErrorUnsupportedServiceManager, message, true)
 		}
 		return mgr.failed(updateDetail, log, updateconstants.ErrorInstallFailed, message, false)
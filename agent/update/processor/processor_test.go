@@ -1108,6 +1108,69 @@ func TestValidateUpdateParam_FailedPrecondition(t *testing.T) {
 	assert.Equal(t, "", updateDetail.StandardError)
 }
 
+func TestValidateUpdateParam_FailedUnsupportedPlatform(t *testing.T) {
+	// setup
+	var logger = logmocks.NewMockLog()
+	updater := createDefaultUpdaterStub()
+	info := &updateinfomocks.T{}
+	info.On("GetPlatform").Return(updateconstants.PlatformCentOS)
+	info.On("GetPlatformVersion").Return("6.10")
+	info.On("CheckCompatibility").Return(&updateinfo.CompatibilityError{
+		Platform:        updateconstants.PlatformCentOS,
+		PlatformVersion: "6.10",
+		Reasons: []updateinfo.CompatibilityReason{
+			{Code: updateinfo.ReasonUnsupportedInit, Message: "no supported init system found"},
+			{Code: updateinfo.ReasonMissingDependency, Message: "rpm required to install the agent is not found"},
+		},
+	})
+	updater.mgr.Info = info
+
+	updateDetail := createUpdateDetail(Initialized)
+	updateDetail.AllowDowngrade = false
+
+	manifest := &updatemanifestmocks.T{}
+	manifest.On("HasVersion", mock.Anything, updateDetail.SourceVersion).Return(true)
+	manifest.On("HasVersion", mock.Anything, updateDetail.TargetVersion).Return(true)
+	manifest.On("IsVersionActive", mock.Anything, mock.Anything).Return(true, nil)
+	updateDetail.Manifest = manifest
+	updater.mgr.preconditions = []updateprecondition.T{}
+
+	finalizeCode := ""
+	updater.mgr.finalize = func(mgr *updateManager, updateDetail *UpdateDetail, code string) (err error) {
+		finalizeCode = code
+		return nil
+	}
+
+	called := false
+	updater.mgr.populateUrlHash = func(mgr *updateManager, log log.T, updateDetail *UpdateDetail) (err error) {
+		called = true
+		return nil
+	}
+
+	// action
+	err := validateUpdateParam(updater.mgr, logger, updateDetail)
+
+	// assert
+	assert.NoError(t, err)
+	assert.False(t, called)
+	assert.Equal(t, string(updateconstants.ErrorUnsupportedPlatform), finalizeCode)
+	assert.Equal(t, contracts.ResultStatusFailed, updateDetail.Result)
+	assert.Contains(t, updateDetail.StandardOut, "platform centos 6.10 is not supported: no supported init system found; rpm required to install the agent is not found")
+}
+
+func TestUnsupportedPlatformMessage(t *testing.T) {
+	info := &updateinfomocks.T{}
+	info.On("CheckCompatibility").Return(nil).Once()
+	assert.Equal(t, "failed to install", unsupportedPlatformMessage(info, "failed to install"))
+
+	info.On("CheckCompatibility").Return(&updateinfo.CompatibilityError{
+		Platform:        updateconstants.PlatformLinux,
+		PlatformVersion: "2",
+		Reasons:         []updateinfo.CompatibilityReason{{Code: updateinfo.ReasonUnsupportedInit, Message: "no supported init system found"}},
+	}).Once()
+	assert.Equal(t, "failed to install: platform linux 2 is not supported: no supported init system found", unsupportedPlatformMessage(info, "failed to install"))
+}
+
 func TestValidateUpdateParam_FailedIncompatibleVersion(t *testing.T) {
 	// setup
 	var logger = logmocks.NewMockLog()
@@ -2139,6 +2202,7 @@ func createUpdaterStubs(control *stubControl) *Updater {
 	info.On("GetPlatformVersion").Return("10.0.22100")
 	info.On("GetUninstallScriptName").Return(updateconstants.UninstallScript)
 	info.On("GetInstallScriptName").Return(updateconstants.InstallScript)
+	info.On("CheckCompatibility").Return(nil)
 
 	updater := NewUpdater(context, info, &updateutil.Utility{})
 	updater.mgr.svc = &serviceStub{}
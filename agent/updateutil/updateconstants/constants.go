@@ -63,6 +63,15 @@ const (
 	// ArchHolder represents Place holder for Arch
 	ArchHolder = "{Arch}"
 
+	// ArchArm represents 32-bit ARM artifacts built for ARMv6 and below
+	ArchArm = "arm"
+
+	// ArchArmV7 represents 32-bit ARM artifacts built for ARMv7
+	ArchArmV7 = "armv7"
+
+	// ArchRiscv64 represents 64-bit RISC-V artifacts
+	ArchRiscv64 = "riscv64"
+
 	// CompressedHolder represents Place holder for compress format
 	CompressedHolder = "{Compressed}"
 
@@ -271,6 +280,9 @@ const (
 	// ErrorUnsupportedServiceManager represents unsupported service manager
 	ErrorUnsupportedServiceManager ErrorCode = "ErrorUnsupportedServiceManager"
 
+	// ErrorUnsupportedPlatform represents a platform the install scripts do not support
+	ErrorUnsupportedPlatform ErrorCode = "ErrorUnsupportedPlatform"
+
 	// ErrorInstallFailureDueToSnapd represents snapd child process bug failure
 	ErrorInstallFailureDueToSnapd ErrorCode = "ErrorInstallFailedDueToSnapd"
 
@@ -324,6 +336,7 @@ const (
 // NonAlarmingErrors contains error codes which are not important.
 var NonAlarmingErrors = map[ErrorCode]struct{}{
 	ErrorUnsupportedServiceManager: {},
+	ErrorUnsupportedPlatform:       {},
 	ErrorAttemptToDowngrade:        {},
 	ErrorFailedPrecondition:        {},
 	ErrorFailedLinksCheck:          {},
@@ -0,0 +1,178 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package updateinfo
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/aws/amazon-ssm-agent/agent/updateutil/updateconstants"
+	"github.com/aws/amazon-ssm-agent/agent/versionutil"
+)
+
+// ReasonCode identifies why the platform is not supported for agent installation and update
+type ReasonCode string
+
+const (
+	// ReasonKernelTooOld represents a kernel older than the oldest kernel any agent version runs on
+	ReasonKernelTooOld ReasonCode = "KernelTooOld"
+	// ReasonUnsupportedInit represents an init system the install scripts can not register the agent service with
+	ReasonUnsupportedInit ReasonCode = "UnsupportedInitSystem"
+	// ReasonMissingDependency represents a tool missing that the install scripts depend on
+	ReasonMissingDependency ReasonCode = "MissingDependency"
+)
+
+// minimumKernelVersion is the oldest linux kernel supported by any agent version,
+// newer agent versions may require a newer kernel which is checked by the update preconditions
+const minimumKernelVersion = "2.6.32"
+
+var kernelVersionPattern = regexp.MustCompile(`^\d+(\.\d+)*`)
+
+var getGoOS = func() string { return runtime.GOOS }
+var lookPath = exec.LookPath
+
+var getKernelRelease = func() (string, error) {
+	output, err := execCommand("uname", "-r").Output()
+	return strings.TrimSpace(string(output)), err
+}
+
+var isUsingUpstart = func() bool {
+	output, err := execCommand("/sbin/init", "--version").Output()
+	return err == nil && strings.Contains(string(output), "upstart")
+}
+
+// CompatibilityReason describes why the platform is not supported for agent installation and update
+type CompatibilityReason struct {
+	Code    ReasonCode
+	Message string
+}
+
+// CompatibilityError is returned when the platform is not supported for agent installation and update
+type CompatibilityError struct {
+	Platform        string
+	PlatformVersion string
+	Reasons         []CompatibilityReason
+}
+
+// Error lists the human-readable reasons the platform is not supported
+func (e *CompatibilityError) Error() string {
+	messages := make([]string, 0, len(e.Reasons))
+	for _, reason := range e.Reasons {
+		messages = append(messages, reason.Message)
+	}
+	return fmt.Sprintf("platform %s %s is not supported: %s", e.Platform, e.PlatformVersion, strings.Join(messages, "; "))
+}
+
+// CheckCompatibility returns a *CompatibilityError with the reasons the platform is not supported
+// for agent installation and update, nil when the platform is supported
+func (i *updateInfoImpl) CheckCompatibility() error {
+	reasons := i.GetCompatibilityReasons()
+	if len(reasons) == 0 {
+		return nil
+	}
+	return &CompatibilityError{
+		Platform:        i.platform,
+		PlatformVersion: i.platformVersion,
+		Reasons:         reasons,
+	}
+}
+
+// GetCompatibilityReasons returns the reasons the platform is not supported for agent installation and update
+func (i *updateInfoImpl) GetCompatibilityReasons() (reasons []CompatibilityReason) {
+	// the install scripts of windows and macOS are not sensitive to the environment
+	if getGoOS() != "linux" {
+		return nil
+	}
+
+	if reason := checkKernelVersion(); reason != nil {
+		reasons = append(reasons, *reason)
+	}
+
+	isSnap := i.installScriptName == updateconstants.SnapInstaller
+	// snap registers the agent service itself
+	if !isSnap {
+		if reason := i.checkInitSystem(); reason != nil {
+			reasons = append(reasons, *reason)
+		}
+	}
+
+	for _, dependency := range i.getInstallDependencies(isSnap) {
+		if _, err := lookPath(dependency); err != nil {
+			reasons = append(reasons, CompatibilityReason{
+				Code:    ReasonMissingDependency,
+				Message: fmt.Sprintf("%s required to install the agent is not found", dependency),
+			})
+		}
+	}
+	return reasons
+}
+
+// checkKernelVersion returns a reason when the kernel is older than the minimum kernel version,
+// a kernel version that can not be determined is not reported
+func checkKernelVersion() *CompatibilityReason {
+	release, err := getKernelRelease()
+	if err != nil {
+		return nil
+	}
+	kernelVersion := kernelVersionPattern.FindString(release)
+	if kernelVersion == "" {
+		return nil
+	}
+	if comp, err := versionutil.VersionCompare(kernelVersion, minimumKernelVersion); err != nil || comp >= 0 {
+		return nil
+	}
+	return &CompatibilityReason{
+		Code:    ReasonKernelTooOld,
+		Message: fmt.Sprintf("kernel version %s is older than the minimum supported kernel version %s", kernelVersion, minimumKernelVersion),
+	}
+}
+
+// checkInitSystem returns a reason when neither systemd nor upstart manage the services
+func (i *updateInfoImpl) checkInitSystem() *CompatibilityReason {
+	if isSystemD, err := i.IsPlatformUsingSystemD(); err == nil && isSystemD {
+		return nil
+	}
+	if _, err := lookPath("systemctl"); err == nil {
+		return nil
+	}
+	if isUsingUpstart() {
+		return nil
+	}
+	return &CompatibilityReason{
+		Code:    ReasonUnsupportedInit,
+		Message: "no supported init system found, the agent service requires systemd or upstart",
+	}
+}
+
+// getInstallDependencies returns the tools the install script of the platform depends on
+func (i *updateInfoImpl) getInstallDependencies(isSnap bool) []string {
+	if isSnap {
+		return []string{"snap"}
+	}
+	downloadPlatform := i.platform
+	if i.downloadPlatformOverride != "" {
+		downloadPlatform = i.downloadPlatformOverride
+	}
+	switch downloadPlatform {
+	case updateconstants.PlatformLinux:
+		return []string{"rpm"}
+	case updateconstants.PlatformUbuntu:
+		return []string{"dpkg"}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package updateinfo
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	"github.com/aws/amazon-ssm-agent/agent/updateutil/updateconstants"
+	"github.com/stretchr/testify/assert"
+)
+
+type compatibilityEnv struct {
+	goOS          string
+	kernelRelease string
+	upstart       bool
+	tools         map[string]bool
+}
+
+func stubCompatibilityEnv(t *testing.T, env compatibilityEnv) {
+	goOSOrig, kernelOrig, upstartOrig, lookPathOrig := getGoOS, getKernelRelease, isUsingUpstart, lookPath
+	t.Cleanup(func() {
+		getGoOS, getKernelRelease, isUsingUpstart, lookPath = goOSOrig, kernelOrig, upstartOrig, lookPathOrig
+	})
+	getGoOS = func() string { return env.goOS }
+	getKernelRelease = func() (string, error) { return env.kernelRelease, nil }
+	isUsingUpstart = func() bool { return env.upstart }
+	lookPath = func(file string) (string, error) {
+		if env.tools[file] {
+			return "/usr/bin/" + file, nil
+		}
+		return "", fmt.Errorf("%s not found", file)
+	}
+}
+
+func reasonCodes(reasons []CompatibilityReason) (codes []ReasonCode) {
+	for _, reason := range reasons {
+		codes = append(codes, reason.Code)
+	}
+	return codes
+}
+
+func TestGetCompatibilityReasons_Compatible(t *testing.T) {
+	stubCompatibilityEnv(t, compatibilityEnv{
+		goOS:          "linux",
+		kernelRelease: "5.10.210-201.852.amzn2.x86_64",
+		tools:         map[string]bool{"systemctl": true, "rpm": true},
+	})
+	info := &updateInfoImpl{context.NewMockDefault(), updateconstants.PlatformCentOS, "7", updateconstants.PlatformLinux, "amd64", "tar.gz", updateconstants.InstallScript, updateconstants.UninstallScript}
+
+	assert.Empty(t, info.GetCompatibilityReasons())
+	assert.NoError(t, info.CheckCompatibility())
+}
+
+func TestGetCompatibilityReasons_Upstart(t *testing.T) {
+	stubCompatibilityEnv(t, compatibilityEnv{
+		goOS:          "linux",
+		kernelRelease: "2.6.32-754.el6.x86_64",
+		upstart:       true,
+		tools:         map[string]bool{"rpm": true},
+	})
+	info := &updateInfoImpl{context.NewMockDefault(), updateconstants.PlatformCentOS, "6.10", updateconstants.PlatformLinux, "amd64", "tar.gz", updateconstants.InstallScript, updateconstants.UninstallScript}
+
+	assert.Empty(t, info.GetCompatibilityReasons())
+}
+
+func TestGetCompatibilityReasons_AllReasons(t *testing.T) {
+	stubCompatibilityEnv(t, compatibilityEnv{
+		goOS:          "linux",
+		kernelRelease: "2.6.18-419.el5",
+		tools:         map[string]bool{},
+	})
+	info := &updateInfoImpl{context.NewMockDefault(), updateconstants.PlatformDebian, "7", updateconstants.PlatformUbuntu, "amd64", "tar.gz", updateconstants.InstallScript, updateconstants.UninstallScript}
+
+	reasons := info.GetCompatibilityReasons()
+	assert.Equal(t, []ReasonCode{ReasonKernelTooOld, ReasonUnsupportedInit, ReasonMissingDependency}, reasonCodes(reasons))
+
+	err := info.CheckCompatibility()
+	var compatibilityErr *CompatibilityError
+	assert.True(t, errors.As(err, &compatibilityErr))
+	assert.Equal(t, reasons, compatibilityErr.Reasons)
+	assert.Contains(t, err.Error(), "platform debian 7 is not supported")
+	assert.Contains(t, err.Error(), "kernel version 2.6.18 is older than the minimum supported kernel version 2.6.32")
+	assert.Contains(t, err.Error(), "the agent service requires systemd or upstart")
+	assert.Contains(t, err.Error(), "dpkg required to install the agent is not found")
+}
+
+func TestGetCompatibilityReasons_Snap(t *testing.T) {
+	stubCompatibilityEnv(t, compatibilityEnv{
+		goOS:          "linux",
+		kernelRelease: "6.8.0-1008-aws",
+		tools:         map[string]bool{},
+	})
+	info := &updateInfoImpl{context.NewMockDefault(), updateconstants.PlatformUbuntu, "24.04", "", "amd64", "tar.gz", updateconstants.SnapInstaller, updateconstants.SnapUnInstaller}
+
+	reasons := info.GetCompatibilityReasons()
+	assert.Equal(t, []ReasonCode{ReasonMissingDependency}, reasonCodes(reasons))
+	assert.Contains(t, reasons[0].Message, "snap")
+}
+
+func TestGetCompatibilityReasons_UnknownKernelVersion(t *testing.T) {
+	stubCompatibilityEnv(t, compatibilityEnv{
+		goOS:          "linux",
+		kernelRelease: "unknown",
+		tools:         map[string]bool{"systemctl": true, "rpm": true},
+	})
+	info := &updateInfoImpl{context.NewMockDefault(), updateconstants.PlatformLinux, "2023", "", "amd64", "tar.gz", updateconstants.InstallScript, updateconstants.UninstallScript}
+
+	assert.Empty(t, info.GetCompatibilityReasons())
+}
+
+func TestGetCompatibilityReasons_NotLinux(t *testing.T) {
+	stubCompatibilityEnv(t, compatibilityEnv{goOS: "windows", kernelRelease: "1.0"})
+	info := &updateInfoImpl{context.NewMockDefault(), updateconstants.PlatformWindows, "10.0.20348", "", "amd64", "zip", updateconstants.InstallScript, updateconstants.UninstallScript}
+
+	assert.Empty(t, info.GetCompatibilityReasons())
+	assert.NoError(t, info.CheckCompatibility())
+}
@@ -2,13 +2,34 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	updateinfo "github.com/aws/amazon-ssm-agent/agent/updateutil/updateinfo"
+	mock "github.com/stretchr/testify/mock"
+)
 
 // T is an autogenerated mock type for the T type
 type T struct {
 	mock.Mock
 }
 
+// CheckCompatibility provides a mock function with given fields:
+func (_m *T) CheckCompatibility() error {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckCompatibility")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GenerateCompressedFileName provides a mock function with given fields: _a0
 func (_m *T) GenerateCompressedFileName(_a0 string) string {
 	ret := _m.Called(_a0)
@@ -45,6 +66,26 @@ func (_m *T) GeneratePlatformBasedFolderName() string {
 	return r0
 }
 
+// GetCompatibilityReasons provides a mock function with given fields:
+func (_m *T) GetCompatibilityReasons() []updateinfo.CompatibilityReason {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCompatibilityReasons")
+	}
+
+	var r0 []updateinfo.CompatibilityReason
+	if rf, ok := ret.Get(0).(func() []updateinfo.CompatibilityReason); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]updateinfo.CompatibilityReason)
+		}
+	}
+
+	return r0
+}
+
 // GetInstallScriptName provides a mock function with given fields:
 func (_m *T) GetInstallScriptName() string {
 	ret := _m.Called()
@@ -16,6 +16,7 @@ package updateinfo
 import (
 	"os/exec"
 	"runtime"
+	"runtime/debug"
 	"strings"
 	"sync"
 
@@ -144,6 +145,40 @@ func (i *updateInfoImpl) GeneratePlatformBasedFolderName() string {
 
 var getPlatformName = platform.PlatformName
 var getPlatformVersion = platform.PlatformVersion
+var getGoArch = func() string { return runtime.GOARCH }
+var getGoArm = goArmFromBuildInfo
+
+// goArmFromBuildInfo returns the GOARM value the running binary was built with
+func goArmFromBuildInfo() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "GOARM" {
+				return setting.Value
+			}
+		}
+	}
+	return ""
+}
+
+// resolveArch maps the architecture of the running binary to the arch used in manifest entries
+func resolveArch(log log.T) string {
+	goArch := getGoArch()
+	switch goArch {
+	case "arm":
+		// GOARM may carry a float ABI suffix such as 7,softfloat
+		goArm := strings.SplitN(getGoArm(), ",", 2)[0]
+		if goArm == "7" {
+			log.Info("Detected architecture ARMv7")
+			return updateconstants.ArchArmV7
+		}
+		return updateconstants.ArchArm
+	case "riscv64":
+		log.Info("Detected architecture RISC-V 64")
+		return updateconstants.ArchRiscv64
+	default:
+		return goArch
+	}
+}
 
 // New create instance related information such as region, platform and arch
 func New(context context.T) (T, error) {
@@ -245,7 +280,7 @@ func newInner(context context.T) (updateInfo *updateInfoImpl, err error) {
 		platform:                 platformName,
 		platformVersion:          platformVersion,
 		downloadPlatformOverride: downloadPlatformOverride,
-		arch:                     runtime.GOARCH,
+		arch:                     resolveArch(log),
 		compressFormat:           updateconstants.CompressFormat,
 		installScriptName:        installScriptName,
 		uninstallScriptName:      uninstallScriptName,
@@ -26,6 +26,8 @@ type T interface {
 	GetUninstallScriptName() string
 	GetPlatform() string
 	GetPlatformVersion() string
+	CheckCompatibility() error
+	GetCompatibilityReasons() []CompatibilityReason
 }
 
 // updateInfoImpl holds information for the instance
@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"runtime"
 	"testing"
 
 	"github.com/aws/amazon-ssm-agent/agent/log"
@@ -118,6 +119,8 @@ func TestFileNameConstruction(t *testing.T) {
 		{updateInfoImpl{contextMock, "linux", "2015.9", "linux", "386", "tar.gz", "installer", "uninstaller"}, "amazon-ssm-agent-linux-386.tar.gz"},
 		{updateInfoImpl{contextMock, "ubuntu", "12", "ubuntu", "386", "tar.gz", "installer", "uninstaller"}, "amazon-ssm-agent-ubuntu-386.tar.gz"},
 		{updateInfoImpl{contextMock, "mac os x", "10.14.2", "darwin", "amd64", "tar.gz", "installer", "uninstaller"}, "amazon-ssm-agent-darwin-amd64.tar.gz"},
+		{updateInfoImpl{contextMock, "raspbian", "11", "ubuntu", "armv7", "tar.gz", "installer", "uninstaller"}, "amazon-ssm-agent-ubuntu-armv7.tar.gz"},
+		{updateInfoImpl{contextMock, "linux", "2023", "linux", "riscv64", "tar.gz", "installer", "uninstaller"}, "amazon-ssm-agent-linux-riscv64.tar.gz"},
 	}
 
 	for _, test := range testCases {
@@ -126,6 +129,34 @@ func TestFileNameConstruction(t *testing.T) {
 	}
 }
 
+func TestResolveArch(t *testing.T) {
+	defer func() {
+		getGoArch = func() string { return runtime.GOARCH }
+		getGoArm = goArmFromBuildInfo
+	}()
+
+	testCases := []struct {
+		goArch   string
+		goArm    string
+		expected string
+	}{
+		{"amd64", "", "amd64"},
+		{"arm64", "", "arm64"},
+		{"arm", "6", updateconstants.ArchArm},
+		{"arm", "", updateconstants.ArchArm},
+		{"arm", "7", updateconstants.ArchArmV7},
+		{"arm", "7,softfloat", updateconstants.ArchArmV7},
+		{"riscv64", "", updateconstants.ArchRiscv64},
+	}
+
+	for _, test := range testCases {
+		goArch, goArm := test.goArch, test.goArm
+		getGoArch = func() string { return goArch }
+		getGoArm = func() string { return goArm }
+		assert.Equal(t, test.expected, resolveArch(logmocks.NewMockLog()), "GOARCH=%s GOARM=%s", goArch, goArm)
+	}
+}
+
 func TestFolderNameConstruction(t *testing.T) {
 	contextMock := &context.Mock{}
 
@@ -0,0 +1,80 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package updatelock implements the machine-wide lock serializing the installations and updates of the agent.
+//
+// The lock is the updater pid lockfile, also held by the self update, the aws:updateSsmAgent plugin and the updater,
+// so ssm-setup-cli, Distributor and the agent updates can not replace the agent at the same time. The lock is held
+// while the process whose pid is recorded in the lockfile runs and until the lock expires, a lock left behind by a
+// process that exited is taken over.
+package updatelock
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/updateutil/updateconstants"
+	"github.com/nightlyone/lockfile"
+)
+
+// ErrUpdateInProgress is returned when another running process holds the update lock
+var ErrUpdateInProgress = errors.New("another installation or update of amazon-ssm-agent is in progress")
+
+// lockPath is the lockfile shared with the agent updates, overridden in tests
+var lockPath = appconfig.UpdaterPidLockfile
+
+// Lock is the update lock held by the current process
+type Lock struct {
+	lockfile lockfile.Lockfile
+}
+
+// Acquire takes the update lock for the current process, it fails with ErrUpdateInProgress when another running process
+// holds the lock. Failures to create the lockfile do not block the update, in line with the agent updates.
+func Acquire(log log.T) (*Lock, error) {
+	if err := os.MkdirAll(filepath.Dir(lockPath), appconfig.ReadWriteExecuteAccess); err != nil {
+		log.Warnf("Failed to create the update lock directory: %v", err)
+	}
+	lock, err := lockfile.New(lockPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create update lock %v: %v", lockPath, err)
+	}
+
+	err = lock.TryLockExpireWithRetry(updateconstants.UpdateLockFileMinutes)
+	if err == lockfile.ErrBusy {
+		if owner, ownerErr := lock.GetOwner(); ownerErr == nil {
+			return nil, fmt.Errorf("%w, the update lock %v is held by process %v", ErrUpdateInProgress, lockPath, owner.Pid)
+		}
+		return nil, ErrUpdateInProgress
+	} else if err != nil {
+		log.Warnf("Proceeding without the update lock, failed to lock %v: %v", lockPath, err)
+	} else {
+		log.Debugf("Acquired update lock %v", lockPath)
+	}
+	return &Lock{lockfile: lock}, nil
+}
+
+// Release releases the update lock if the current process still holds it
+func (l *Lock) Release(log log.T) {
+	if l == nil || l.lockfile == nil {
+		return
+	}
+	if err := l.lockfile.Unlock(); err != nil {
+		log.Debugf("Update lock %v not released: %v", lockPath, err)
+		return
+	}
+	log.Debugf("Released update lock %v", lockPath)
+}
@@ -0,0 +1,73 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package updatelock
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	logger "github.com/aws/amazon-ssm-agent/agent/mocks/log"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubLockPath places the lockfile in a directory that does not exist yet
+func stubLockPath(t *testing.T) string {
+	lockPathOrig := lockPath
+	t.Cleanup(func() { lockPath = lockPathOrig })
+	lockPath = filepath.Join(t.TempDir(), "ssm", "update.lock")
+	return lockPath
+}
+
+func TestAcquire(t *testing.T) {
+	path := stubLockPath(t)
+	log := logger.NewMockLog()
+
+	lock, err := Acquire(log)
+	assert.NoError(t, err)
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, strconv.Itoa(os.Getpid())+"\n", string(content))
+
+	lock.Release(log)
+	assert.NoFileExists(t, path)
+	// releasing twice or releasing no lock does nothing
+	lock.Release(log)
+	(*Lock)(nil).Release(log)
+}
+
+func TestAcquire_HeldByRunningProcess(t *testing.T) {
+	path := stubLockPath(t)
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+	assert.NoError(t, os.WriteFile(path, []byte(fmt.Sprintf("%d\n", os.Getppid())), 0600))
+
+	_, err := Acquire(logger.NewMockLog())
+	assert.True(t, errors.Is(err, ErrUpdateInProgress))
+	assert.Contains(t, err.Error(), fmt.Sprintf("held by process %d", os.Getppid()))
+}
+
+func TestAcquire_HeldByExitedProcess(t *testing.T) {
+	path := stubLockPath(t)
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+	assert.NoError(t, os.WriteFile(path, []byte("2147483646\n"), 0600))
+
+	lock, err := Acquire(logger.NewMockLog())
+	assert.NoError(t, err)
+	content, _ := os.ReadFile(path)
+	assert.Equal(t, strconv.Itoa(os.Getpid())+"\n", string(content))
+	lock.Release(logger.NewMockLog())
+}
@@ -36,6 +36,7 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/fileutil"
 	"github.com/aws/amazon-ssm-agent/agent/log"
 	"github.com/aws/amazon-ssm-agent/agent/s3util"
+	"github.com/aws/amazon-ssm-agent/agent/session/connectionhealth"
 	"github.com/aws/amazon-ssm-agent/agent/updateutil/updateconstants"
 	"github.com/aws/amazon-ssm-agent/agent/updateutil/updateinfo"
 	"github.com/aws/amazon-ssm-agent/agent/versionutil"
@@ -88,6 +89,11 @@ var mkDirAll = os.MkdirAll
 var openFile = os.OpenFile
 var execCommand = exec.Command
 var cmdStart = (*exec.Cmd).Start
+var timeSleep = time.Sleep
+var controlChannelConnectedSince = connectionhealth.ConnectedSince
+
+// updaterStartTime is when the update process started
+var updaterStartTime = time.Now()
 var cmdOutput = (*exec.Cmd).Output
 
 const (
@@ -461,19 +467,28 @@ func (util *Utility) VerifyInstalledVersion(log log.T, targetVersion string) upd
 	return verifyVersion(log, targetVersion)
 }
 
-// WaitForServiceToStart wait for service to start and returns is service started
+// WaitForServiceToStart wait for service to start and returns is service started.
+// The control channel is only checked when Agent.UpdateHealthCheckRequireControlChannel is set.
 func (util *Utility) WaitForServiceToStart(log log.T, i updateinfo.T, targetVersion string) (result bool, svcRunningErr error) {
-	const (
-		verifyAttemptCount              = 36
-		verifyRetryIntervalMilliseconds = 5000
-	)
+	const verifyRetryIntervalMilliseconds = 5000
+	timeoutSeconds := appconfig.DefaultUpdateHealthCheckTimeoutSeconds
+	requireControlChannel := false
+	if util.Context != nil {
+		agentConfig := util.Context.AppConfig().Agent
+		if agentConfig.UpdateHealthCheckTimeoutSeconds > 0 {
+			timeoutSeconds = agentConfig.UpdateHealthCheckTimeoutSeconds
+		}
+		requireControlChannel = agentConfig.UpdateHealthCheckRequireControlChannel
+	}
+	verifyAttemptCount := timeoutSeconds * 1000 / verifyRetryIntervalMilliseconds
+
 	isRunning := false
 	isWorkerRunning := true
-	var workRunningErr error
+	var workRunningErr, controlChannelErr error
 	for attempt := 0; attempt < verifyAttemptCount; attempt++ {
 		if attempt > 0 {
 			log.Infof("Retrying update health check %v out of %v", attempt+1, verifyAttemptCount)
-			time.Sleep(time.Duration(verifyRetryIntervalMilliseconds) * time.Millisecond)
+			timeSleep(time.Duration(verifyRetryIntervalMilliseconds) * time.Millisecond)
 		}
 
 		isRunning, svcRunningErr = util.IsServiceRunning(log, i)
@@ -497,7 +512,16 @@ func (util *Utility) WaitForServiceToStart(log log.T, i updateinfo.T, targetVers
 			}
 		}
 
-		if svcRunningErr == nil && workRunningErr == nil && isRunning && isWorkerRunning {
+		if requireControlChannel {
+			// the control channel has to be opened by the updated agent, the updater starts after the agent it updates connected
+			if controlChannelErr = controlChannelConnectedSince(updaterStartTime); controlChannelErr == nil {
+				log.Infof("health check: control channel is connected")
+			} else {
+				log.Infof("health check: %v", controlChannelErr)
+			}
+		}
+
+		if svcRunningErr == nil && workRunningErr == nil && controlChannelErr == nil && isRunning && isWorkerRunning {
 			return true, nil
 		}
 
@@ -510,6 +534,9 @@ func (util *Utility) WaitForServiceToStart(log log.T, i updateinfo.T, targetVers
 	if workRunningErr != nil {
 		errorMessage += ", " + workRunningErr.Error()
 	}
+	if controlChannelErr != nil {
+		errorMessage += ", " + controlChannelErr.Error()
+	}
 
 	return false, fmt.Errorf(errorMessage)
 }
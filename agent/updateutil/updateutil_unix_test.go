@@ -19,13 +19,17 @@ package updateutil
 import (
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
 	"github.com/aws/amazon-ssm-agent/agent/mocks/context"
+	"github.com/aws/amazon-ssm-agent/agent/session/connectionhealth"
 	"github.com/aws/amazon-ssm-agent/agent/updateutil/updateconstants"
 	"github.com/aws/amazon-ssm-agent/agent/updateutil/updateinfo"
 	updateinfomocks "github.com/aws/amazon-ssm-agent/agent/updateutil/updateinfo/mocks"
 	"github.com/aws/amazon-ssm-agent/core/executor"
 	executormocks "github.com/aws/amazon-ssm-agent/core/executor/mocks"
+	"github.com/aws/amazon-ssm-agent/core/workerprovider/longrunningprovider/model"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -73,3 +77,72 @@ func TestIsServiceRunning(t *testing.T) {
 		assert.Equal(t, result, test.result)
 	}
 }
+
+func newDarwinHealthCheckUtility(config appconfig.SsmagentConfig) (Utility, updateinfo.T) {
+	infoDarwin := &updateinfomocks.T{}
+	infoDarwin.On("IsPlatformUsingSystemD").Return(false, nil)
+	infoDarwin.On("IsPlatformDarwin").Return(true)
+
+	mock := &executormocks.IExecutor{}
+	mock.On("Processes").Return([]executor.OsProcess{
+		{Executable: updateconstants.DarwinBinaryPath},
+		{Executable: model.SSMAgentWorkerBinaryName},
+	}, nil)
+
+	return Utility{
+		ProcessExecutor: mock,
+		Context:         context.NewMockDefaultWithConfig(config),
+	}, infoDarwin
+}
+
+func stubHealthCheck(t *testing.T, connectedSince func(time.Time) error) *int {
+	timeSleepOrig, connectedSinceOrig := timeSleep, controlChannelConnectedSince
+	t.Cleanup(func() { timeSleep, controlChannelConnectedSince = timeSleepOrig, connectedSinceOrig })
+	sleeps := 0
+	timeSleep = func(time.Duration) { sleeps++ }
+	controlChannelConnectedSince = connectedSince
+	return &sleeps
+}
+
+func TestWaitForServiceToStart_ControlChannelNotRequired(t *testing.T) {
+	sleeps := stubHealthCheck(t, func(time.Time) error { return connectionhealth.ErrNotConnected })
+	util, info := newDarwinHealthCheckUtility(appconfig.DefaultConfig())
+
+	result, err := util.WaitForServiceToStart(logger, info, "3.3.0.0")
+	assert.True(t, result)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, *sleeps)
+}
+
+func TestWaitForServiceToStart_WaitsForControlChannel(t *testing.T) {
+	checks := 0
+	sleeps := stubHealthCheck(t, func(time.Time) error {
+		checks++
+		if checks < 3 {
+			return connectionhealth.ErrNotConnected
+		}
+		return nil
+	})
+	config := appconfig.DefaultConfig()
+	config.Agent.UpdateHealthCheckRequireControlChannel = true
+	util, info := newDarwinHealthCheckUtility(config)
+
+	result, err := util.WaitForServiceToStart(logger, info, "3.3.0.0")
+	assert.True(t, result)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, *sleeps)
+}
+
+func TestWaitForServiceToStart_ControlChannelTimeout(t *testing.T) {
+	sleeps := stubHealthCheck(t, func(time.Time) error { return connectionhealth.ErrNotConnected })
+	config := appconfig.DefaultConfig()
+	config.Agent.UpdateHealthCheckRequireControlChannel = true
+	config.Agent.UpdateHealthCheckTimeoutSeconds = 30
+	util, info := newDarwinHealthCheckUtility(config)
+
+	result, err := util.WaitForServiceToStart(logger, info, "3.3.0.0")
+	assert.False(t, result)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), connectionhealth.ErrNotConnected.Error())
+	assert.Equal(t, 5, *sleeps)
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/credentials"
 
 	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/metrics"
 	"github.com/aws/amazon-ssm-agent/common/runtimeconfig"
 )
 
@@ -75,7 +76,11 @@ func (s *sharedCredentialsProvider) Retrieve() (credentials.Value, error) {
 // RetrieveWithContext retrieves credentials from the shared credentials file
 // Error will be returned if the request fails, or unable to extract
 // the desired credentials.
-func (s *sharedCredentialsProvider) RetrieveWithContext(ctx context.Context) (credentials.Value, error) {
+func (s *sharedCredentialsProvider) RetrieveWithContext(ctx context.Context) (creds credentials.Value, err error) {
+	defer func() {
+		metrics.CredentialRefreshes.Inc(metrics.ResultLabel(err))
+	}()
+
 	runtimeConfigClient := newRuntimeConfig()
 	// before sharedCredentialsProvider is initialized, we check if the runtime config exists
 	config, err := runtimeConfigClient.GetConfig()
@@ -93,7 +98,7 @@ func (s *sharedCredentialsProvider) RetrieveWithContext(ctx context.Context) (cr
 	}
 
 	credsProvider := newSharedCredentials(config.ShareFile, config.ShareProfile)
-	creds, err := credsProvider.Get()
+	creds, err = credsProvider.Get()
 	if err != nil {
 		return emptyCredential, err
 	}
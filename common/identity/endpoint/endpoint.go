@@ -19,6 +19,7 @@ import (
 	"sync"
 
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/fips"
 	"github.com/aws/amazon-ssm-agent/agent/log"
 )
 
@@ -32,9 +33,20 @@ var regionPrefixServiceDomain = map[string]string{
 	"eusc-de-": "amazonaws.eu",
 }
 
+// Map defining region prefixes and the dual-stack service domain for prefix, partitions without
+// dual-stack endpoints are not listed and keep their default service domain
+var regionPrefixDualStackServiceDomain = map[string]string{
+	"cn-": "api.amazonwebservices.com.cn",
+}
+
 // default service domain if prefix does not exist in awsFallbackServiceDomain map
 const (
-	defaultServiceDomain = "amazonaws.com"
+	defaultServiceDomain          = "amazonaws.com"
+	defaultDualStackServiceDomain = "api.aws"
+
+	// s3 keeps its service domain for dual-stack endpoints, they are marked with a label after the service name instead
+	s3Service        = "s3"
+	s3DualStackLabel = "dualstack"
 
 	regionMaxLength = 100
 )
@@ -57,6 +69,27 @@ func GetServiceDomainByPrefix(region string) string {
 	return defaultServiceDomain
 }
 
+// GetDualStackServiceDomainByPrefix returns the dual-stack service domain of the region and
+// false if the partition of the region has no dual-stack endpoints
+func GetDualStackServiceDomainByPrefix(region string) (string, bool) {
+	for regionPrefix, serviceDomain := range regionPrefixDualStackServiceDomain {
+		if strings.HasPrefix(region, regionPrefix) {
+			return serviceDomain, true
+		}
+	}
+	for regionPrefix := range regionPrefixServiceDomain {
+		if strings.HasPrefix(region, regionPrefix) {
+			return "", false
+		}
+	}
+	return defaultDualStackServiceDomain, true
+}
+
+// UseDualStack returns whether the network stack of the agent requires dual-stack endpoints
+func UseDualStack(config appconfig.SsmagentConfig) bool {
+	return config.Agent.NetworkStack == appconfig.NetworkStackDualStack || config.Agent.NetworkStack == appconfig.NetworkStackIPv6
+}
+
 type endpointImpl struct {
 	log    log.T
 	config appconfig.SsmagentConfig
@@ -114,8 +147,24 @@ func (e *endpointImpl) GetServiceEndpoint(service, region string) string {
 		serviceDomain = GetServiceDomainByPrefix(region)
 	}
 
+	endpointService := service
+	if fips.Enabled(e.config) {
+		endpointService = fips.EndpointService(service, region)
+	}
+
+	// Dual-stack endpoints are only derived for the default service domains, a configured service domain is used as is
+	if UseDualStack(e.config) && e.config.Agent.ServiceDomain == "" {
+		if dualStackServiceDomain, ok := GetDualStackServiceDomainByPrefix(region); !ok {
+			e.log.Warnf("No dual-stack endpoint for service %s in region %s, using the IPv4 endpoint", service, region)
+		} else if service == s3Service {
+			endpointService += "." + s3DualStackLabel
+		} else {
+			serviceDomain = dualStackServiceDomain
+		}
+	}
+
 	// Build the full endpoint for the service in the region
-	endpoint := service + "." + region + "." + serviceDomain
+	endpoint := endpointService + "." + region + "." + serviceDomain
 	e.setEndpointCache(service, region, endpoint)
 	return endpoint
 }
@@ -127,6 +127,17 @@ func TestGetServiceEndpoint_ServiceDomainConfigSet(t *testing.T) {
 	}
 }
 
+func TestGetServiceEndpoint_FipsEnabled(t *testing.T) {
+	config := appconfig.SsmagentConfig{}
+	config.Fips.Enabled = true
+	e := NewEndpointHelper(logMock, config)
+
+	assert.Equal(t, "ssm-fips.us-east-1.amazonaws.com", e.GetServiceEndpoint("ssm", "us-east-1"))
+	assert.Equal(t, "ssmmessages-fips.us-gov-west-1.amazonaws.com", e.GetServiceEndpoint("ssmmessages", "us-gov-west-1"))
+	assert.Equal(t, "ssm.eu-west-1.amazonaws.com", e.GetServiceEndpoint("ssm", "eu-west-1"))
+	assert.Equal(t, "ssm-fips.us-east-1.amazonaws.com", e.endpointCacheLookup("ssm", "us-east-1"))
+}
+
 func TestGetServiceEndpoint_RegionNotInPrefixMap(t *testing.T) {
 	oldMap := regionPrefixServiceDomain
 	regionPrefixServiceDomain = map[string]string{}
@@ -250,3 +261,37 @@ func TestIsRegionValid(t *testing.T) {
 	maxLengthRegion := "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa-aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa-1"
 	assert.True(t, e.isRegionValid(maxLengthRegion))
 }
+
+func TestGetServiceEndpoint_DualStack(t *testing.T) {
+	for _, networkStack := range []string{appconfig.NetworkStackDualStack, appconfig.NetworkStackIPv6} {
+		config := appconfig.SsmagentConfig{}
+		config.Agent.NetworkStack = networkStack
+		e := NewEndpointHelper(logMock, config)
+
+		assert.Equal(t, "ssm.us-east-1.api.aws", e.GetServiceEndpoint("ssm", "us-east-1"))
+		assert.Equal(t, "ssmmessages.us-gov-west-1.api.aws", e.GetServiceEndpoint("ssmmessages", "us-gov-west-1"))
+		assert.Equal(t, "ec2messages.cn-north-1.api.amazonwebservices.com.cn", e.GetServiceEndpoint("ec2messages", "cn-north-1"))
+		assert.Equal(t, "s3.dualstack.eu-west-1.amazonaws.com", e.GetServiceEndpoint("s3", "eu-west-1"))
+		assert.Equal(t, "s3.dualstack.cn-north-1.amazonaws.com.cn", e.GetServiceEndpoint("s3", "cn-north-1"))
+		assert.Equal(t, "ssm.us-iso-east-1.c2s.ic.gov", e.GetServiceEndpoint("ssm", "us-iso-east-1"), "partition without dual-stack endpoints")
+	}
+}
+
+func TestGetServiceEndpoint_DualStackWithFips(t *testing.T) {
+	config := appconfig.SsmagentConfig{}
+	config.Agent.NetworkStack = appconfig.NetworkStackIPv6
+	config.Fips.Enabled = true
+	e := NewEndpointHelper(logMock, config)
+
+	assert.Equal(t, "ssm-fips.us-east-1.api.aws", e.GetServiceEndpoint("ssm", "us-east-1"))
+	assert.Equal(t, "s3-fips.dualstack.us-east-1.amazonaws.com", e.GetServiceEndpoint("s3", "us-east-1"))
+}
+
+func TestGetServiceEndpoint_DualStackServiceDomainConfigSet(t *testing.T) {
+	config := appconfig.SsmagentConfig{}
+	config.Agent.NetworkStack = appconfig.NetworkStackDualStack
+	config.Agent.ServiceDomain = "some.service.domain"
+	e := NewEndpointHelper(logMock, config)
+
+	assert.Equal(t, "ssm.us-east-1.some.service.domain", e.GetServiceEndpoint("ssm", "us-east-1"))
+}
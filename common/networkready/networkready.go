@@ -0,0 +1,127 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package networkready detects when the host network comes up, so that startup does not have to wait for the next retry
+package networkready
+
+import (
+	"net"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/log"
+)
+
+// settleDelay coalesces the burst of events raised while an interface is configured
+const settleDelay = time.Second
+
+var (
+	startMtx    sync.Mutex
+	started     bool
+	readyMtx    sync.Mutex
+	readyChan   = make(chan struct{})
+	newWatcher  = newOSWatcher
+	addressesOf = net.InterfaceAddrs
+)
+
+// watcher blocks until the network configuration of the host changes
+type watcher interface {
+	// Wait returns once routes or addresses changed, or with an error if the watcher failed
+	Wait() error
+	Close()
+}
+
+// IsReady returns true if the host has an address that can route outside of the host
+func IsReady() bool {
+	addresses, err := addressesOf()
+	if err != nil {
+		return false
+	}
+	for _, address := range addresses {
+		if ipNet, ok := address.(*net.IPNet); ok && ipNet.IP.IsGlobalUnicast() {
+			return true
+		}
+	}
+	return false
+}
+
+// Changed returns a channel that is closed the next time the network comes up after a change of routes or addresses.
+// The detector is started with the first call, callers should get a new channel after each notification.
+func Changed(log log.T) <-chan struct{} {
+	startMtx.Lock()
+	if !started {
+		started = true
+		go watch(log)
+	}
+	startMtx.Unlock()
+
+	readyMtx.Lock()
+	defer readyMtx.Unlock()
+	return readyChan
+}
+
+// WaitUntilReady waits until the host has a routable address or the timeout expired, it returns whether the network is ready
+func WaitUntilReady(log log.T, timeout time.Duration) bool {
+	changed := Changed(log)
+	if IsReady() {
+		return true
+	}
+	log.Infof("Waiting up to %v for the network to come up", timeout)
+	select {
+	case <-changed:
+		log.Info("Network is ready")
+		return true
+	case <-time.After(timeout):
+		ready := IsReady()
+		if !ready {
+			log.Warnf("Network is not ready after %v, continuing startup", timeout)
+		}
+		return ready
+	}
+}
+
+func notifyReady() {
+	readyMtx.Lock()
+	defer readyMtx.Unlock()
+	close(readyChan)
+	readyChan = make(chan struct{})
+}
+
+func watch(log log.T) {
+	defer func() {
+		if msg := recover(); msg != nil {
+			log.Errorf("Network ready detector panic: %v", msg)
+			log.Errorf("Stacktrace:\n%s", debug.Stack())
+		}
+	}()
+
+	osWatcher, err := newWatcher()
+	if err != nil {
+		log.Warnf("Network changes cannot be detected, retries rely on backoff only: %v", err)
+		return
+	}
+	defer osWatcher.Close()
+
+	for {
+		if err = osWatcher.Wait(); err != nil {
+			log.Warnf("Stopped detecting network changes: %v", err)
+			return
+		}
+		time.Sleep(settleDelay)
+		if IsReady() {
+			log.Debug("Network changed and has a routable address, waking up pending retries")
+			notifyReady()
+		}
+	}
+}
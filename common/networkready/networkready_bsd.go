@@ -0,0 +1,57 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build darwin || freebsd || netbsd || openbsd
+// +build darwin freebsd netbsd openbsd
+
+package networkready
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// routeSocketWatcher listens to the messages of the routing socket, which include address changes
+type routeSocketWatcher struct {
+	fd     int
+	buffer []byte
+}
+
+func newOSWatcher() (watcher, error) {
+	fd, err := unix.Socket(unix.AF_ROUTE, unix.SOCK_RAW, unix.AF_UNSPEC)
+	if err != nil {
+		return nil, os.NewSyscallError("socket", err)
+	}
+	unix.CloseOnExec(fd)
+	return &routeSocketWatcher{fd: fd, buffer: make([]byte, os.Getpagesize())}, nil
+}
+
+func (w *routeSocketWatcher) Wait() error {
+	for {
+		n, err := unix.Read(w.fd, w.buffer)
+		if err == unix.EINTR {
+			continue
+		}
+		if err != nil {
+			return os.NewSyscallError("read", err)
+		}
+		if n > 0 {
+			return nil
+		}
+	}
+}
+
+func (w *routeSocketWatcher) Close() {
+	unix.Close(w.fd)
+}
@@ -0,0 +1,64 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build linux
+// +build linux
+
+package networkready
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// netlinkWatcher listens to the route and address notifications of the kernel
+type netlinkWatcher struct {
+	fd     int
+	buffer []byte
+}
+
+func newOSWatcher() (watcher, error) {
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_RAW|unix.SOCK_CLOEXEC, unix.NETLINK_ROUTE)
+	if err != nil {
+		return nil, os.NewSyscallError("socket", err)
+	}
+	address := &unix.SockaddrNetlink{
+		Family: unix.AF_NETLINK,
+		Groups: unix.RTMGRP_LINK | unix.RTMGRP_IPV4_IFADDR | unix.RTMGRP_IPV6_IFADDR | unix.RTMGRP_IPV4_ROUTE | unix.RTMGRP_IPV6_ROUTE,
+	}
+	if err = unix.Bind(fd, address); err != nil {
+		unix.Close(fd)
+		return nil, os.NewSyscallError("bind", err)
+	}
+	return &netlinkWatcher{fd: fd, buffer: make([]byte, os.Getpagesize())}, nil
+}
+
+func (w *netlinkWatcher) Wait() error {
+	for {
+		n, _, err := unix.Recvfrom(w.fd, w.buffer, 0)
+		if err == unix.EINTR {
+			continue
+		}
+		if err != nil {
+			return os.NewSyscallError("recvfrom", err)
+		}
+		if n > 0 {
+			return nil
+		}
+	}
+}
+
+func (w *netlinkWatcher) Close() {
+	unix.Close(w.fd)
+}
@@ -0,0 +1,119 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package networkready
+
+import (
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	logmocks "github.com/aws/amazon-ssm-agent/agent/mocks/log"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeWatcher struct {
+	events chan error
+}
+
+func (w *fakeWatcher) Wait() error {
+	return <-w.events
+}
+
+func (w *fakeWatcher) Close() {}
+
+// fakeAddresses is read by the detector goroutine while tests change it
+var fakeAddresses atomic.Value
+
+func setAddresses(addresses ...string) {
+	var result []net.Addr
+	for _, address := range addresses {
+		_, ipNet, _ := net.ParseCIDR(address)
+		result = append(result, ipNet)
+	}
+	fakeAddresses.Store(result)
+}
+
+func setupTest(t *testing.T) *fakeWatcher {
+	originalNewWatcher, originalAddressesOf := newWatcher, addressesOf
+	t.Cleanup(func() {
+		newWatcher, addressesOf = originalNewWatcher, originalAddressesOf
+		started = false
+	})
+
+	fake := &fakeWatcher{events: make(chan error)}
+	newWatcher = func() (watcher, error) {
+		return fake, nil
+	}
+	started = false
+	addressesOf = func() ([]net.Addr, error) {
+		return fakeAddresses.Load().([]net.Addr), nil
+	}
+	return fake
+}
+
+func TestIsReady(t *testing.T) {
+	setupTest(t)
+
+	setAddresses("127.0.0.1/8", "::1/128", "fe80::1/64")
+	assert.False(t, IsReady())
+	setAddresses("127.0.0.1/8", "10.0.0.5/24")
+	assert.True(t, IsReady())
+
+	addressesOf = func() ([]net.Addr, error) {
+		return nil, errors.New("failed")
+	}
+	assert.False(t, IsReady())
+}
+
+func TestChangedNotifiesOnceNetworkIsUp(t *testing.T) {
+	fake := setupTest(t)
+	log := logmocks.NewMockLog()
+	setAddresses("127.0.0.1/8")
+
+	changed := Changed(log)
+	// a change without a routable address does not wake up waiters
+	fake.events <- nil
+	select {
+	case <-changed:
+		assert.Fail(t, "notified without a routable address")
+	case <-time.After(settleDelay + 500*time.Millisecond):
+	}
+
+	setAddresses("127.0.0.1/8", "192.168.1.10/24")
+	fake.events <- nil
+	select {
+	case <-changed:
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "not notified after the network came up")
+	}
+	assert.NotEqual(t, changed, Changed(log))
+
+	// stop the detector
+	fake.events <- errors.New("closed")
+}
+
+func TestWaitUntilReady(t *testing.T) {
+	fake := setupTest(t)
+	log := logmocks.NewMockLog()
+
+	setAddresses("10.0.0.5/24")
+	assert.True(t, WaitUntilReady(log, time.Hour))
+
+	setAddresses()
+	assert.False(t, WaitUntilReady(log, 10*time.Millisecond))
+
+	fake.events <- errors.New("closed")
+}
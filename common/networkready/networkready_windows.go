@@ -0,0 +1,95 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+//go:build windows
+// +build windows
+
+package networkready
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	iphlpapi                 = windows.NewLazySystemDLL("iphlpapi.dll")
+	procNotifyAddrChange     = iphlpapi.NewProc("NotifyAddrChange")
+	procNotifyRouteChange    = iphlpapi.NewProc("NotifyRouteChange")
+	procCancelIPChangeNotify = iphlpapi.NewProc("CancelIPChangeNotify")
+)
+
+// ipChangeNotification is a pending NotifyAddrChange or NotifyRouteChange request of the IP helper API,
+// the same notifications Network Location Awareness reacts to
+type ipChangeNotification struct {
+	proc       *windows.LazyProc
+	overlapped windows.Overlapped
+	pending    bool
+}
+
+type ipHelperWatcher struct {
+	notifications []*ipChangeNotification
+}
+
+func newOSWatcher() (watcher, error) {
+	w := &ipHelperWatcher{}
+	for _, proc := range []*windows.LazyProc{procNotifyAddrChange, procNotifyRouteChange} {
+		if err := proc.Find(); err != nil {
+			w.Close()
+			return nil, err
+		}
+		event, err := windows.CreateEvent(nil, 0, 0, nil)
+		if err != nil {
+			w.Close()
+			return nil, err
+		}
+		notification := &ipChangeNotification{proc: proc}
+		notification.overlapped.HEvent = event
+		w.notifications = append(w.notifications, notification)
+	}
+	return w, nil
+}
+
+func (w *ipHelperWatcher) Wait() error {
+	events := make([]windows.Handle, 0, len(w.notifications))
+	for _, notification := range w.notifications {
+		if !notification.pending {
+			var handle windows.Handle
+			ret, _, _ := notification.proc.Call(uintptr(unsafe.Pointer(&handle)), uintptr(unsafe.Pointer(&notification.overlapped)))
+			if ret != uintptr(windows.ERROR_IO_PENDING) && ret != 0 {
+				return fmt.Errorf("%v failed: %v", notification.proc.Name, windows.Errno(ret))
+			}
+			notification.pending = true
+		}
+		events = append(events, notification.overlapped.HEvent)
+	}
+
+	index, err := windows.WaitForMultipleObjects(events, false, windows.INFINITE)
+	if err != nil {
+		return err
+	}
+	if index < uint32(len(w.notifications)) {
+		w.notifications[index].pending = false
+	}
+	return nil
+}
+
+func (w *ipHelperWatcher) Close() {
+	for _, notification := range w.notifications {
+		if notification.pending {
+			procCancelIPChangeNotify.Call(uintptr(unsafe.Pointer(&notification.overlapped)))
+		}
+		windows.CloseHandle(notification.overlapped.HEvent)
+	}
+}
@@ -25,8 +25,11 @@ import (
 
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
 	"github.com/aws/amazon-ssm-agent/agent/contracts"
+	"github.com/aws/amazon-ssm-agent/agent/crashdump"
+	"github.com/aws/amazon-ssm-agent/agent/fips"
 	"github.com/aws/amazon-ssm-agent/agent/log"
 	"github.com/aws/amazon-ssm-agent/agent/log/logger"
+	"github.com/aws/amazon-ssm-agent/agent/network"
 	"github.com/aws/amazon-ssm-agent/agent/proxyconfig"
 	"github.com/aws/amazon-ssm-agent/core/app"
 	"github.com/aws/amazon-ssm-agent/core/app/bootstrap"
@@ -49,11 +52,12 @@ const (
 	toolFlag                    = "tools"
 	winOnFirstInstallChecksFlag = "winOnFirstInstallChecks"
 	allowLinkDeletionsFlag      = "allowLinkDeletions"
+	secondaryFlag               = "secondary"
 )
 
 var (
 	activationCode, activationID, region, role, tagsJson string
-	register, clear, force, fpFlag, tool                 bool
+	register, clear, force, fpFlag, tool, secondary      bool
 	agentVersionFlag                                     bool
 	disableSimilarityCheck                               bool
 	winOnFirstInstallChecks                              bool
@@ -72,11 +76,23 @@ func initializeBasicModules(log log.T) (app.CoreAgent, log.T, error) {
 		log.Infof(key + ": " + value)
 	}
 
+	// the instance metadata service is reached while the identity is initialized, before the context exists
+	if config, err := appconfig.Config(true); err == nil {
+		network.ApplyNetworkStack(log, config)
+	}
+
 	bs := bootstrap.NewBootstrap(log, filesystem.NewFileSystem())
 	context, err := bs.Init()
 	if err != nil {
 		return nil, log, err
 	}
+	if err = fips.Check(context.Log(), *context.AppConfig()); err != nil {
+		return nil, context.Log(), err
+	}
+	if memoryLimitMB := context.AppConfig().Agent.MemoryLimitMB; memoryLimitMB > 0 {
+		debug.SetMemoryLimit(int64(memoryLimitMB) << 20)
+		log.Infof("Memory limit of amazon-ssm-agent set to %v MB", memoryLimitMB)
+	}
 
 	// Initialize runtime configs
 	rci := runtimeconfiginit.New(context.Log(), context.Identity())
@@ -113,7 +129,8 @@ func startCoreAgent(log log.T, ssmAgentCore app.CoreAgent, statusChan *contracts
 	time.Sleep(200 * time.Millisecond)
 }
 
-func blockUntilSignaled(log log.T, statusChan *contracts.StatusComm) {
+// blockUntilSignaled returns true when the core agent requested a restart of the agent
+func blockUntilSignaled(log log.T, statusChan *contracts.StatusComm) (restart bool) {
 	// Below channel will handle all machine initiated shutdown/reboot requests.
 
 	// Set up channel on which to receive signal notifications.
@@ -132,7 +149,11 @@ func blockUntilSignaled(log log.T, statusChan *contracts.StatusComm) {
 		<-statusChan.DoneChan
 	case <-coreAgentStartupErrChan:
 		log.Error("Failed to start core agent startup module")
+	case <-statusChan.RestartChan:
+		log.Info("amazon-ssm-agent restart requested")
+		return true
 	}
+	return false
 }
 
 // Run as a single process. Used by Unix systems and when running agent from console.
@@ -141,8 +162,10 @@ func run(log log.T) {
 		// recover in case the agent panics
 		// this should handle some kind of seg fault errors.
 		if msg := recover(); msg != nil {
+			stack := debug.Stack()
 			log.Errorf("Core Agent crashed with message %v!", msg)
-			log.Errorf("%s: %s", msg, debug.Stack())
+			log.Errorf("%s: %s", msg, stack)
+			crashdump.RecordPanic(log, appconfig.DefaultAgentName, msg, stack)
 		}
 	}()
 
@@ -155,8 +178,14 @@ func run(log log.T) {
 	statusChannels := &contracts.StatusComm{
 		TerminationChan: make(chan struct{}, 1),
 		DoneChan:        make(chan struct{}, 1),
+		RestartChan:     make(chan struct{}, 1),
 	}
 	startCoreAgent(contextLog, coreAgent, statusChannels)
-	blockUntilSignaled(contextLog, statusChannels)
+	restart := blockUntilSignaled(contextLog, statusChannels)
 	coreAgent.Stop()
+	if restart {
+		// exit with an error for the service manager to start the agent again
+		log.Flush()
+		os.Exit(appconfig.ErrorExitCode)
+	}
 }
@@ -48,6 +48,7 @@ func parseFlags() {
 	flag.BoolVar(&agentVersionFlag, versionFlag, false, "")
 	flag.StringVar(&role, roleFlag, "", "")
 	flag.StringVar(&tagsJson, tagsFlag, "", "")
+	flag.BoolVar(&secondary, secondaryFlag, false, "")
 
 	// clear registration
 	flag.BoolVar(&clear, "clear", false, "")
@@ -140,7 +141,8 @@ func flagUsage() {
 	fmt.Fprintln(os.Stderr, "\t\t-tags                  \tSSM tags for greengrass registration                                                       \t(OPTIONAL with greengrass registration)")
 	fmt.Fprintln(os.Stderr, "\t\t-region                \tSSM region                                                                                 \t(REQUIRED with registration)")
 	fmt.Fprintln(os.Stderr, "\t\t-disableSimilarityCheck\tDisable the agent hardware/fingerprint similarity check (similarity threshold is set to -1)\t(OPTIONAL)")
-	fmt.Fprintln(os.Stderr, "\n\t\t-clear\tClears the previously saved SSM registration")
+	fmt.Fprintln(os.Stderr, "\t\t-secondary             \tSave as the secondary registration of the region used for region failover                  \t(OPTIONAL)")
+	fmt.Fprintln(os.Stderr, "\n\t\t-clear\tClears the previously saved SSM registration, the secondary registration of the region with -secondary -region")
 	fmt.Fprintln(os.Stderr, "\t-fingerprint\tWhether to update the machine fingerprint similarity threshold\t(OPTIONAL)")
 	fmt.Fprintln(os.Stderr, "\t\t-similarityThreshold\tThe new required percentage of matching hardware values (-1 disables hardware check)\t(OPTIONAL)")
 	fmt.Fprintln(os.Stderr, "\n\t-y\tAnswer yes for all questions")
@@ -148,9 +150,17 @@ func flagUsage() {
 
 // processRegistration handles flags related to the registration category
 func processRegistration(log logger.T) (exitCode int) {
+	if secondary && region == "" {
+		flagUsage()
+		return 1
+	}
+
 	if ((activationCode == "" || activationID == "") && role == "") || region == "" {
 		// clear registration
 		if clear {
+			if secondary {
+				return clearSecondaryRegistration(log)
+			}
 			fingerprint.ClearStoredHardwareInfo(log)
 			return clearRegistration(log)
 		}
@@ -159,7 +169,7 @@ func processRegistration(log logger.T) (exitCode int) {
 	}
 
 	// check if previously registered
-	if !force && registration.InstanceID(log, "", registration.RegVaultKey) != "" {
+	if !force && !secondary && registration.InstanceID(log, "", registration.RegVaultKey) != "" {
 		confirmation, err := askForConfirmation()
 		if err != nil {
 			log.Errorf("Registration failed due to %v", err)
@@ -177,6 +187,10 @@ func processRegistration(log logger.T) (exitCode int) {
 		log.Errorf("Registration failed due to %v", err)
 		return 1
 	}
+	if secondary {
+		log.Infof("Successfully registered the instance with AWS SSM in secondary region %s using Managed instance-id: %s", region, managedInstanceID)
+		return 0
+	}
 	log.Infof("Successfully registered the instance with AWS SSM using Managed instance-id: %s", managedInstanceID)
 	return 0
 }
@@ -193,6 +207,12 @@ func processFingerprint(log logger.T) (exitCode int) {
 
 // registerManagedInstance checks for activation credentials and performs managed instance registration when present
 func registerManagedInstance(log logger.T) (managedInstanceID string, err error) {
+	// secondary registrations are kept aside until the agent fails over to their region
+	vaultKey := registration.RegVaultKey
+	if secondary {
+		vaultKey = registration.RegionalRegVaultKey(region)
+	}
+
 	// try to activate the instance with the activation credentials
 	publicKey, privateKey, keyType, err := registration.GenerateKeyPair()
 	if err != nil {
@@ -200,7 +220,7 @@ func registerManagedInstance(log logger.T) (managedInstanceID string, err error)
 	}
 
 	// checking write access before registering
-	err = registration.UpdateServerInfo("", "", "", privateKey, keyType, "", registration.RegVaultKey)
+	err = registration.UpdateServerInfo("", "", "", privateKey, keyType, "", vaultKey)
 	if err != nil {
 		return "",
 			fmt.Errorf("Unable to save registration information. %v\nTry running as sudo/administrator.", err)
@@ -217,7 +237,11 @@ func registerManagedInstance(log logger.T) (managedInstanceID string, err error)
 		log.Debugf("disableSimilarityCheck is set to true, setting similarity threshold to -1")
 		if err = fingerprint.SetSimilarityThreshold(log, -1); err != nil {
 			fingerprint.ClearStoredHardwareInfo(log)
-			clearRegistration(log)
+			if secondary {
+				clearSecondaryRegistration(log)
+			} else {
+				clearRegistration(log)
+			}
 			return "", fmt.Errorf("failed to set SimilarityThreshold: %v", err)
 		}
 	}
@@ -247,11 +271,16 @@ func registerManagedInstance(log logger.T) (managedInstanceID string, err error)
 		return managedInstanceID, fmt.Errorf("error registering the instance with AWS SSM. %v", err)
 	}
 
-	err = registration.UpdateServerInfo(managedInstanceID, region, "", privateKey, keyType, "", registration.RegVaultKey)
+	err = registration.UpdateServerInfo(managedInstanceID, region, "", privateKey, keyType, "", vaultKey)
 	if err != nil {
 		return managedInstanceID, fmt.Errorf("error persisting the instance registration information. %v", err)
 	}
 
+	// the registration file only reflects the active registration
+	if secondary {
+		return managedInstanceID, nil
+	}
+
 	// saving registration information to the registration file
 	reg := map[string]string{
 		"ManagedInstanceID": managedInstanceID,
@@ -281,6 +310,17 @@ func clearRegistration(log logger.T) (exitCode int) {
 	return 1
 }
 
+// clearSecondaryRegistration clears the secondary registration of the region
+func clearSecondaryRegistration(log logger.T) (exitCode int) {
+	err := registration.UpdateServerInfo("", "", "", "", "", "", registration.RegionalRegVaultKey(region))
+	if err == nil {
+		log.Infof("Secondary registration information of region %s has been removed from the instance.", region)
+		return 0
+	}
+	log.Errorf("error clearing the secondary registration information of region %s. %v\nTry running as sudo/administrator.", region, err)
+	return 1
+}
+
 // askForConfirmation will ask user for confirmation if they want to proceed.
 func askForConfirmation() (result bool, err error) {
 	var response string
@@ -10,8 +10,10 @@ import (
 
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
 	"github.com/aws/amazon-ssm-agent/agent/contracts"
+	"github.com/aws/amazon-ssm-agent/agent/crashdump"
 	logger "github.com/aws/amazon-ssm-agent/agent/log"
 	"github.com/aws/amazon-ssm-agent/agent/log/ssmlog"
+	"github.com/aws/amazon-ssm-agent/agent/servicerecovery"
 	"golang.org/x/sys/windows/registry"
 	"golang.org/x/sys/windows/svc"
 	"golang.org/x/sys/windows/svc/mgr"
@@ -169,8 +171,10 @@ func (a *amazonSSMAgentService) Execute(args []string, r <-chan svc.ChangeReques
 		// recover in case the agent panics
 		// this should handle some kind of seg fault errors.
 		if msg := recover(); msg != nil {
+			stack := debug.Stack()
 			log.Errorf("core Agent service crashed with message %v!", msg)
-			log.Errorf("Stacktrace:\n%s", debug.Stack())
+			log.Errorf("Stacktrace:\n%s", stack)
+			crashdump.RecordPanic(log, appconfig.DefaultAgentName, msg, stack)
 		}
 	}()
 
@@ -183,6 +187,14 @@ func (a *amazonSSMAgentService) Execute(args []string, r <-chan svc.ChangeReques
 	}
 	log.Info("System is ready")
 
+	// restart the agent through the service manager when it crashes, the actions may be missing on older installations
+	if err := servicerecovery.ConfigureRecoveryActions(serviceName); err != nil {
+		log.Warnf("Failed to configure service recovery actions: %v", err)
+	}
+	if failures := servicerecovery.RecordStart(log); failures >= servicerecovery.RepeatedFailureThreshold {
+		servicerecovery.ReportRepeatedFailures(log, serviceName, failures)
+	}
+
 	// notify service controller status is now StartPending
 	s <- svc.Status{State: svc.StartPending}
 
@@ -197,6 +209,7 @@ func (a *amazonSSMAgentService) Execute(args []string, r <-chan svc.ChangeReques
 	statusChannels := &contracts.StatusComm{
 		TerminationChan: make(chan struct{}, 1),
 		DoneChan:        make(chan struct{}, 1),
+		RestartChan:     make(chan struct{}, 1),
 	}
 
 	startCoreAgent(contextLog, agent, statusChannels)
@@ -210,6 +223,7 @@ func (a *amazonSSMAgentService) Execute(args []string, r <-chan svc.ChangeReques
 		incomingServiceReq         svc.ChangeRequest
 		incomingServiceReqChan     = make(chan svc.ChangeRequest, 1)
 		terminateIncomingReqThread = make(chan bool, 1)
+		restartRequested           = false
 	)
 
 	go func() {
@@ -220,6 +234,10 @@ func (a *amazonSSMAgentService) Execute(args []string, r <-chan svc.ChangeReques
 				contextLog.Error("Failed to start core agent startup module")
 				statusChannels.DoneChan <- struct{}{}
 				incomingServiceReqChan <- svc.ChangeRequest{Cmd: svc.Stop}
+			case <-statusChannels.RestartChan:
+				contextLog.Info("Agent restart requested")
+				restartRequested = true
+				incomingServiceReqChan <- svc.ChangeRequest{Cmd: svc.Stop}
 			case incomingServiceReq = <-r:
 				incomingServiceReqChan <- incomingServiceReq
 			case <-terminateIncomingReqThread:
@@ -259,5 +277,10 @@ loop:
 	terminateIncomingReqThread <- true
 	<-statusChannels.DoneChan
 	agent.Stop()
+	servicerecovery.RecordStop(contextLog)
+	if restartRequested {
+		// exit with a service specific error for the recovery actions to start the agent again
+		return true, appconfig.ErrorExitCode
+	}
 	return false, appconfig.SuccessExitCode
 }
@@ -23,6 +23,7 @@ import (
 	"github.com/aws/amazon-ssm-agent/core/app/context"
 	"github.com/aws/amazon-ssm-agent/core/app/credentialrefresher"
 	reboot "github.com/aws/amazon-ssm-agent/core/app/reboot/model"
+	"github.com/aws/amazon-ssm-agent/core/app/regionfailover"
 	"github.com/aws/amazon-ssm-agent/core/app/registrar"
 	"github.com/aws/amazon-ssm-agent/core/app/selfupdate"
 	"github.com/aws/amazon-ssm-agent/core/ipc/messagebus"
@@ -41,6 +42,7 @@ type SSMCoreAgent struct {
 	selfupdate     selfupdate.ISelfUpdate
 	credsRefresher credentialrefresher.ICredentialRefresher
 	registrar      registrar.IRetryableRegistrar
+	regionFailover regionfailover.IRegionFailover
 }
 
 // NewSSMCoreAgent creates and returns and object of type CoreAgent interface
@@ -56,6 +58,10 @@ func NewSSMCoreAgent(context context.ICoreAgentContext, messageBus messagebus.IM
 		coreAgent.registrar = registrar
 	}
 
+	if regionFailover := regionfailover.NewRegionFailover(context); regionFailover != nil {
+		coreAgent.regionFailover = regionFailover
+	}
+
 	return coreAgent
 }
 
@@ -96,6 +102,9 @@ func (agent *SSMCoreAgent) Start(statusChan *agentcontracts.StatusComm) error {
 		agent.container.Start()
 		go agent.container.Monitor()
 		agent.selfupdate.Start()
+		if agent.regionFailover != nil {
+			agent.regionFailover.Start(statusChan.RestartChan)
+		}
 		// removing the below wait time will cause the agent worker to run orphaned when
 		// agent is stopped immediately after start
 		time.Sleep(3 * time.Second)
@@ -116,6 +125,9 @@ func (agent *SSMCoreAgent) Stop() {
 	log.Flush()
 
 	agent.selfupdate.Stop()
+	if agent.regionFailover != nil {
+		agent.regionFailover.Stop()
+	}
 	agent.container.Stop(reboot.StopTypeHardStop)
 	agent.credsRefresher.Stop()
 	if agent.registrar != nil {
@@ -23,6 +23,7 @@ import (
 	"github.com/aws/amazon-ssm-agent/agent/log"
 	logger "github.com/aws/amazon-ssm-agent/agent/log"
 	"github.com/aws/amazon-ssm-agent/common/identity/identity"
+	"github.com/aws/amazon-ssm-agent/common/networkready"
 	"github.com/aws/amazon-ssm-agent/core/app/context"
 	"github.com/aws/amazon-ssm-agent/core/workerprovider/longrunningprovider/datastore/filesystem"
 )
@@ -30,10 +31,14 @@ import (
 const (
 	bootstrapRetryInterval = 2
 	defaultFileCreateMode  = 0750
+
+	// networkReadyTimeout bounds how long identity lookup is held back waiting for a routable address at boot
+	networkReadyTimeout = 2 * time.Minute
 )
 
 var newAgentIdentitySelector = identity.NewDefaultAgentIdentitySelector
 var newAgentIdentity = identity.NewAgentIdentity
+var waitUntilNetworkReady = networkready.WaitUntilReady
 
 // IBootstrap is the interface for initializing the system for core agent
 type IBootstrap interface {
@@ -69,6 +74,11 @@ func (bs *Bootstrap) Init() (context.ICoreAgentContext, error) {
 		return nil, fmt.Errorf("app config could not be loaded - %v", err)
 	}
 
+	// Identity detection probes IMDS and other endpoints with short retries, so give the network a chance to come up first
+	if !waitUntilNetworkReady(logger, networkReadyTimeout) {
+		logger.Warnf("No routable network address after %v, continuing identity detection", networkReadyTimeout)
+	}
+
 	selector := newAgentIdentitySelector(logger)
 	agentIdentity, err := newAgentIdentity(logger, &config, selector)
 	if err != nil {
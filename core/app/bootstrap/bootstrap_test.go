@@ -15,6 +15,7 @@ package bootstrap
 
 import (
 	"testing"
+	"time"
 
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
 	"github.com/aws/amazon-ssm-agent/agent/log"
@@ -43,6 +44,12 @@ func TestBootstrap(t *testing.T) {
 		return agentIdentity, nil
 	}
 
+	networkWaited := false
+	waitUntilNetworkReady = func(log log.T, timeout time.Duration) bool {
+		networkWaited = true
+		return true
+	}
+
 	fileSystem.On("Stat", mock.Anything).Return(nil, nil)
 	fileSystem.On("IsNotExist", mock.Anything).Return(true)
 	fileSystem.On("MkdirAll", mock.Anything, mock.Anything).Return(nil)
@@ -55,4 +62,5 @@ func TestBootstrap(t *testing.T) {
 
 	assert.Equal(t, context.Log(), logger)
 	assert.Equal(t, context.Identity(), agentIdentity)
+	assert.True(t, networkWaited)
 }
@@ -209,6 +209,8 @@ func (c *credentialsRefresher) retrieveCredsWithRetry(ctx context.Context) (cred
 		return credentials.Value{}, true
 	}
 	for {
+		// subscribe before the call so a network-up event during the request is not missed
+		networkUp := networkChanged(c.log)
 		creds, err := c.provider.RemoteRetrieve(ctx)
 		if err == nil {
 			return creds, false
@@ -229,6 +231,8 @@ func (c *credentialsRefresher) retrieveCredsWithRetry(ctx context.Context) (cred
 		// override sleep duration for aws errors
 		var awsErr awserr.Error
 		if isAwsErr := errors.As(err, &awsErr); isAwsErr {
+			// the service answered, so a network change will not make the next attempt any different
+			networkUp = nil
 			// Check if error is a non-retryable error if fingerprint changes or response is access denied exception
 			if awsErr.Code() == ssm.ErrCodeMachineFingerprintDoesNotMatch || awsErr.Code() == ErrCodeAccessDeniedException {
 				if sleepDurationFunc, ok := identityGetDurationMap[awsErr.Code()]; ok {
@@ -263,6 +267,9 @@ func (c *credentialsRefresher) retrieveCredsWithRetry(ctx context.Context) (cred
 		case <-c.stopCredentialRefresherChan:
 			return creds, true
 		case <-c.timeAfterFunc(sleepDuration):
+		case <-networkUp:
+			c.log.Info("Network became available, retrying retrieve credentials immediately")
+			retryCount = 0
 		}
 	}
 }
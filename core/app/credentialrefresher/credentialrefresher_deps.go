@@ -7,6 +7,7 @@ import (
 
 	"github.com/aws/amazon-ssm-agent/common/identity/availableidentities/ec2"
 	"github.com/aws/amazon-ssm-agent/common/identity/availableidentities/onprem"
+	"github.com/aws/amazon-ssm-agent/common/networkready"
 	"github.com/aws/aws-sdk-go/service/ssm"
 )
 
@@ -20,6 +21,9 @@ const (
 	maxSleepDurationJitterSeconds         = 300  // 5 minutes jitter on max sleep
 )
 
+// networkChanged returns a channel that is closed once the host gains a routable address again
+var networkChanged = networkready.Changed
+
 type getBackoffDurationFunc func(attemptNumber int) time.Duration
 
 var identityGetDurationMaps = map[string]map[string]getBackoffDurationFunc{
@@ -765,6 +765,37 @@ func Test_credentialsRefresher_retrieveCredsWithRetry_ValidateSleepDuration(t *t
 	}
 }
 
+func Test_credentialsRefresher_retrieveCredsWithRetry_NetworkUpSkipsSleep(t *testing.T) {
+	provider := &credentialmocks.IRemoteProvider{}
+	provider.On("RemoteRetrieve", mock.Anything).Return(credentials.Value{}, fmt.Errorf("dial tcp: network is unreachable")).Once()
+	provider.On("RemoteRetrieve", mock.Anything).Return(credentials.Value{}, nil).Once()
+	mockAgentIdentity := &identityMock.IAgentIdentity{}
+	mockAgentIdentity.On("IdentityType").Return(ec2.IdentityType)
+
+	networkUp := make(chan struct{})
+	close(networkUp)
+	origNetworkChanged := networkChanged
+	networkChanged = func(log log.T) <-chan struct{} { return networkUp }
+	t.Cleanup(func() { networkChanged = origNetworkChanged })
+
+	c := &credentialsRefresher{
+		log:                         logmocks.NewMockLog(),
+		agentIdentity:               mockAgentIdentity,
+		provider:                    provider,
+		stopCredentialRefresherChan: make(chan struct{}),
+		timeAfterFunc: func(duration time.Duration) <-chan time.Time {
+			// never fires, only the network event can wake the retry loop
+			return make(chan time.Time)
+		},
+		appConfig: &appconfig.SsmagentConfig{Agent: appconfig.AgentInfo{}},
+	}
+
+	_, stopped := c.retrieveCredsWithRetry(nil)
+
+	provider.AssertExpectations(t)
+	assert.False(t, stopped, "expected retrieve to not have been stopped by channel message")
+}
+
 func TestCredUtilityFunctions_sleepRetry_minMaxTesting(t *testing.T) {
 	minSeconds := getDefaultBackoffRetryJitterSleepDuration(0).Seconds()
 	for i := 0; i < 17; i++ {
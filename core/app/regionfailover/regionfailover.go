@@ -0,0 +1,221 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package regionfailover fails a hybrid instance over to the secondary registration
+// of the preferred healthy region when the control plane of the active region is unreachable
+package regionfailover
+
+import (
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	"github.com/aws/amazon-ssm-agent/agent/managedInstances/registration"
+	"github.com/aws/amazon-ssm-agent/agent/network"
+	"github.com/aws/amazon-ssm-agent/common/identity/availableidentities/onprem"
+	"github.com/aws/amazon-ssm-agent/common/identity/endpoint"
+	"github.com/aws/amazon-ssm-agent/core/app/context"
+)
+
+const (
+	healthCheckTimeout = 30 * time.Second
+)
+
+// controlPlaneServices are the services the agent can not be managed without
+var controlPlaneServices = []string{"ssm", "ec2messages", "ssmmessages"}
+
+// The main purpose of these delegates is to easily test the failover
+var (
+	hasRegionalRegistration = registration.HasRegionalRegistration
+	activateRegistration    = registration.ActivateRegionalRegistration
+	timeAfterFunc           = time.After
+)
+
+// IRegionFailover is the interface to start/stop the region failover module
+type IRegionFailover interface {
+	Start(restartChan chan struct{})
+	Stop()
+}
+
+// RegionFailover checks the control plane health of the configured regions and
+// activates the registration of the first healthy region in the configured order
+type RegionFailover struct {
+	log              log.T
+	appConfig        appconfig.SsmagentConfig
+	activeRegion     string
+	regions          []string
+	interval         time.Duration
+	failureThreshold int
+	failures         int
+	checkHealth      func(region string) bool
+	stopChan         chan struct{}
+	stopOnce         sync.Once
+}
+
+// NewRegionFailover returns the region failover module, nil when the agent does not use
+// the on-premises identity or no failover regions are configured
+func NewRegionFailover(agentCtx context.ICoreAgentContext) *RegionFailover {
+	log := agentCtx.Log().WithContext("[RegionFailover]")
+	config := agentCtx.AppConfig().Identity.RegionFailover
+	if len(config.Regions) == 0 {
+		return nil
+	}
+	agentIdentity := agentCtx.Identity()
+	if agentIdentity.IdentityType() != onprem.IdentityType {
+		log.Debugf("Region failover is only supported for the %v identity", onprem.IdentityType)
+		return nil
+	}
+	activeRegion, err := agentIdentity.Region()
+	if err != nil || activeRegion == "" {
+		log.Warnf("Region failover disabled, unable to determine the active region: %v", err)
+		return nil
+	}
+
+	failover := &RegionFailover{
+		log:              log,
+		appConfig:        *agentCtx.AppConfig(),
+		activeRegion:     activeRegion,
+		regions:          config.Regions,
+		interval:         time.Duration(config.HealthCheckIntervalSeconds) * time.Second,
+		failureThreshold: config.FailureThreshold,
+		stopChan:         make(chan struct{}),
+	}
+	failover.checkHealth = failover.isRegionHealthy
+	return failover
+}
+
+// Start starts the health checks, restartChan is notified once another registration was activated
+func (r *RegionFailover) Start(restartChan chan struct{}) {
+	r.log.Infof("Starting region failover module, active region %v, failover regions %v", r.activeRegion, r.regions)
+	go r.monitor(restartChan)
+}
+
+// Stop stops the health checks
+func (r *RegionFailover) Stop() {
+	r.log.Info("Stopping region failover module")
+	r.stopOnce.Do(func() { close(r.stopChan) })
+}
+
+func (r *RegionFailover) monitor(restartChan chan struct{}) {
+	defer func() {
+		if err := recover(); err != nil {
+			r.log.Errorf("Region failover panic: %v", err)
+			r.log.Errorf("Stacktrace:\n%s", debug.Stack())
+		}
+	}()
+
+	for {
+		select {
+		case <-r.stopChan:
+			return
+		case <-timeAfterFunc(r.interval):
+		}
+
+		if r.evaluate() {
+			select {
+			case restartChan <- struct{}{}:
+			default:
+			}
+			return
+		}
+	}
+}
+
+// evaluate runs one round of health checks and returns true when another registration was activated
+func (r *RegionFailover) evaluate() bool {
+	preferredRegion := r.preferredRegion()
+	if preferredRegion == "" || preferredRegion == r.activeRegion {
+		r.failures = 0
+		return false
+	}
+
+	r.failures++
+	r.log.Warnf("Region %v is preferred over active region %v (%v/%v)", preferredRegion, r.activeRegion, r.failures, r.failureThreshold)
+	if r.failures < r.failureThreshold {
+		return false
+	}
+	r.failures = 0
+
+	if err := activateRegistration(r.log, preferredRegion); err != nil {
+		r.log.Errorf("Failed to fail over to region %v: %v", preferredRegion, err)
+		return false
+	}
+	r.log.Infof("Failed over from region %v to region %v, restarting agent", r.activeRegion, preferredRegion)
+	return true
+}
+
+// preferredRegion returns the first healthy region in the configured order that the instance holds a registration for.
+// The active region is preferred when it is not part of the configured order.
+func (r *RegionFailover) preferredRegion() string {
+	regions := r.regions
+	if !containsRegion(regions, r.activeRegion) {
+		regions = append([]string{r.activeRegion}, regions...)
+	}
+	for _, region := range regions {
+		if region != r.activeRegion && !hasRegionalRegistration(region) {
+			continue
+		}
+		if r.checkHealth(region) {
+			return region
+		}
+		r.log.Debugf("Control plane of region %v is unhealthy", region)
+	}
+	return ""
+}
+
+// isRegionHealthy returns true when the endpoints of all control plane services of the region are healthy
+func (r *RegionFailover) isRegionHealthy(region string) bool {
+	client := &http.Client{
+		Transport: network.GetDefaultTransport(r.log, r.appConfig),
+		Timeout:   healthCheckTimeout,
+	}
+	endpointHelper := endpoint.NewEndpointHelper(r.log, r.appConfig)
+	for _, service := range controlPlaneServices {
+		serviceEndpoint := endpointHelper.GetServiceEndpoint(service, region)
+		if serviceEndpoint == "" {
+			return false
+		}
+		if !r.isEndpointHealthy(client, "https://"+serviceEndpoint) {
+			return false
+		}
+	}
+	return true
+}
+
+// isEndpointHealthy returns true when the endpoint answers. Client errors like the rejection of the
+// unauthenticated request prove the endpoint is up, server errors and throttling mean it can not serve the agent.
+func (r *RegionFailover) isEndpointHealthy(client *http.Client, url string) bool {
+	resp, err := client.Get(url)
+	if err != nil {
+		r.log.Debugf("Health check of %v failed: %v", url, err)
+		return false
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+		r.log.Debugf("Health check of %v failed with status %v", url, resp.StatusCode)
+		return false
+	}
+	return true
+}
+
+func containsRegion(regions []string, region string) bool {
+	for _, r := range regions {
+		if r == region {
+			return true
+		}
+	}
+	return false
+}
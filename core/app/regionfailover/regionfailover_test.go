@@ -0,0 +1,209 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package regionfailover
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+	"github.com/aws/amazon-ssm-agent/agent/log"
+	logmocks "github.com/aws/amazon-ssm-agent/agent/mocks/log"
+	"github.com/aws/amazon-ssm-agent/common/identity/availableidentities/onprem"
+	identitymocks "github.com/aws/amazon-ssm-agent/common/identity/mocks"
+	contextmocks "github.com/aws/amazon-ssm-agent/core/app/context/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestFailover(activeRegion string, regions []string, healthy map[string]bool) *RegionFailover {
+	return &RegionFailover{
+		log:              logmocks.NewMockLog(),
+		activeRegion:     activeRegion,
+		regions:          regions,
+		interval:         time.Minute,
+		failureThreshold: 2,
+		checkHealth:      func(region string) bool { return healthy[region] },
+		stopChan:         make(chan struct{}),
+	}
+}
+
+func stubRegistrations(t *testing.T, registered map[string]bool, activated *[]string) {
+	hasRegistrationOrig, activateOrig := hasRegionalRegistration, activateRegistration
+	t.Cleanup(func() {
+		hasRegionalRegistration, activateRegistration = hasRegistrationOrig, activateOrig
+	})
+	hasRegionalRegistration = func(region string) bool { return registered[region] }
+	activateRegistration = func(_ log.T, region string) error {
+		*activated = append(*activated, region)
+		return nil
+	}
+}
+
+func newTestContext(identityType string, regions []string) *contextmocks.ICoreAgentContext {
+	config := appconfig.DefaultConfig()
+	config.Identity.RegionFailover.Regions = regions
+	agentCtx := &contextmocks.ICoreAgentContext{}
+	agentCtx.On("Log").Return(logmocks.NewMockLog())
+	agentCtx.On("AppConfig").Return(&config)
+	agentCtx.On("Identity").Return(identitymocks.NewMockAgentIdentity("mi-123", "us-east-1", "", "", identityType))
+	return agentCtx
+}
+
+func TestNewRegionFailover(t *testing.T) {
+	assert.Nil(t, NewRegionFailover(newTestContext(onprem.IdentityType, nil)))
+	assert.Nil(t, NewRegionFailover(newTestContext("EC2", []string{"us-east-1", "us-west-2"})))
+
+	failover := NewRegionFailover(newTestContext(onprem.IdentityType, []string{"us-east-1", "us-west-2"}))
+	assert.NotNil(t, failover)
+	assert.Equal(t, "us-east-1", failover.activeRegion)
+	assert.Equal(t, time.Duration(appconfig.DefaultRegionFailoverHealthCheckIntervalSeconds)*time.Second, failover.interval)
+	assert.Equal(t, appconfig.DefaultRegionFailoverThreshold, failover.failureThreshold)
+}
+
+func TestEvaluate_ActiveRegionHealthy(t *testing.T) {
+	var activated []string
+	stubRegistrations(t, map[string]bool{"us-west-2": true}, &activated)
+	failover := newTestFailover("us-east-1", []string{"us-east-1", "us-west-2"}, map[string]bool{"us-east-1": true, "us-west-2": true})
+
+	for i := 0; i < 5; i++ {
+		assert.False(t, failover.evaluate())
+	}
+	assert.Empty(t, activated)
+}
+
+func TestEvaluate_FailsOverAfterThreshold(t *testing.T) {
+	var activated []string
+	stubRegistrations(t, map[string]bool{"us-west-2": true}, &activated)
+	failover := newTestFailover("us-east-1", []string{"us-east-1", "us-west-2"}, map[string]bool{"us-west-2": true})
+
+	assert.False(t, failover.evaluate())
+	assert.Empty(t, activated)
+	assert.True(t, failover.evaluate())
+	assert.Equal(t, []string{"us-west-2"}, activated)
+}
+
+func TestEvaluate_ResetsFailuresWhenActiveRegionRecovers(t *testing.T) {
+	var activated []string
+	stubRegistrations(t, map[string]bool{"us-west-2": true}, &activated)
+	healthy := map[string]bool{"us-west-2": true}
+	failover := newTestFailover("us-east-1", []string{"us-east-1", "us-west-2"}, healthy)
+
+	assert.False(t, failover.evaluate())
+	healthy["us-east-1"] = true
+	assert.False(t, failover.evaluate())
+	healthy["us-east-1"] = false
+	assert.False(t, failover.evaluate())
+	assert.Empty(t, activated)
+}
+
+func TestEvaluate_FailsBackToPreferredRegion(t *testing.T) {
+	var activated []string
+	stubRegistrations(t, map[string]bool{"us-east-1": true}, &activated)
+	failover := newTestFailover("us-west-2", []string{"us-east-1", "us-west-2"}, map[string]bool{"us-east-1": true, "us-west-2": true})
+
+	assert.False(t, failover.evaluate())
+	assert.True(t, failover.evaluate())
+	assert.Equal(t, []string{"us-east-1"}, activated)
+}
+
+func TestEvaluate_SkipsRegionsWithoutRegistration(t *testing.T) {
+	var activated []string
+	stubRegistrations(t, map[string]bool{"eu-west-1": true}, &activated)
+	failover := newTestFailover("us-east-1", []string{"us-east-1", "us-west-2", "eu-west-1"}, map[string]bool{"us-west-2": true, "eu-west-1": true})
+
+	assert.False(t, failover.evaluate())
+	assert.True(t, failover.evaluate())
+	assert.Equal(t, []string{"eu-west-1"}, activated)
+}
+
+func TestEvaluate_NoHealthyRegion(t *testing.T) {
+	var activated []string
+	stubRegistrations(t, map[string]bool{"us-west-2": true}, &activated)
+	failover := newTestFailover("us-east-1", []string{"us-east-1", "us-west-2"}, map[string]bool{})
+
+	for i := 0; i < 5; i++ {
+		assert.False(t, failover.evaluate())
+	}
+	assert.Empty(t, activated)
+}
+
+func TestEvaluate_ActivationFailure(t *testing.T) {
+	var activated []string
+	stubRegistrations(t, map[string]bool{"us-west-2": true}, &activated)
+	activateRegistration = func(log.T, string) error { return fmt.Errorf("vault error") }
+	failover := newTestFailover("us-east-1", []string{"us-east-1", "us-west-2"}, map[string]bool{"us-west-2": true})
+
+	assert.False(t, failover.evaluate())
+	assert.False(t, failover.evaluate())
+}
+
+func TestIsEndpointHealthy(t *testing.T) {
+	failover := newTestFailover("us-east-1", nil, nil)
+	for status, healthy := range map[int]bool{
+		http.StatusOK:                  true,
+		http.StatusForbidden:           true,
+		http.StatusNotFound:            true,
+		http.StatusTooManyRequests:     false,
+		http.StatusInternalServerError: false,
+		http.StatusServiceUnavailable:  false,
+	} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(status)
+		}))
+		assert.Equal(t, healthy, failover.isEndpointHealthy(server.Client(), server.URL), "status %v", status)
+		server.Close()
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	server.Close()
+	assert.False(t, failover.isEndpointHealthy(server.Client(), server.URL))
+}
+
+func TestMonitor_RequestsRestartAfterFailover(t *testing.T) {
+	var activated []string
+	stubRegistrations(t, map[string]bool{"us-west-2": true}, &activated)
+	timeAfterOrig := timeAfterFunc
+	defer func() { timeAfterFunc = timeAfterOrig }()
+	timeAfterFunc = func(time.Duration) <-chan time.Time {
+		c := make(chan time.Time, 1)
+		c <- time.Now()
+		return c
+	}
+	failover := newTestFailover("us-east-1", []string{"us-east-1", "us-west-2"}, map[string]bool{"us-west-2": true})
+
+	restartChan := make(chan struct{}, 1)
+	failover.monitor(restartChan)
+	assert.Len(t, restartChan, 1)
+	assert.Equal(t, []string{"us-west-2"}, activated)
+}
+
+func TestMonitor_Stop(t *testing.T) {
+	failover := newTestFailover("us-east-1", []string{"us-east-1"}, map[string]bool{})
+	done := make(chan struct{})
+	go func() {
+		failover.monitor(nil)
+		close(done)
+	}()
+	failover.Stop()
+	failover.Stop()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		assert.Fail(t, "monitor did not stop")
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+// Package executor contains general purpose command executing objects.
+package executor
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
+)
+
+// ProcessKind identifies the role of a process spawned by the agent
+type ProcessKind string
+
+const (
+	// DocumentWorker is a worker executing a command or association document
+	DocumentWorker ProcessKind = "DocumentWorker"
+	// SessionWorker is a worker running a Session Manager session
+	SessionWorker ProcessKind = "SessionWorker"
+	// WorkerChild is a descendant of a worker, such as a script of a plugin or the shell of a session
+	WorkerChild ProcessKind = "WorkerChild"
+)
+
+// AgentProcess describes a worker process spawned by the agent or a descendant of such a worker
+type AgentProcess struct {
+	Pid         int
+	PPid        int
+	Kind        ProcessKind
+	CommandID   string
+	WorkerPid   int
+	Age         time.Duration
+	CommandLine string
+}
+
+// processDetail is a process with the details required to identify the processes of the agent
+type processDetail struct {
+	Pid         int
+	PPid        int
+	Age         time.Duration
+	CommandLine string
+}
+
+// agentProcesses returns the workers found in the process list, each followed by its descendants.
+// The command id of a worker is the command, association or session id it was started for.
+func agentProcesses(details []processDetail) []AgentProcess {
+	children := make(map[int][]processDetail)
+	for _, detail := range details {
+		children[detail.PPid] = append(children[detail.PPid], detail)
+	}
+
+	results := make([]AgentProcess, 0)
+	for _, detail := range details {
+		args := splitCommandLine(detail.CommandLine)
+		if len(args) == 0 {
+			continue
+		}
+		kind := workerKind(args[0])
+		if kind == "" {
+			continue
+		}
+		commandID := ""
+		if len(args) > 1 {
+			commandID = args[1]
+		}
+		results = append(results, newAgentProcess(detail, kind, commandID, detail.Pid))
+
+		// walk the descendants breadth first, a reused pid must not make the walk loop
+		visited := map[int]bool{detail.Pid: true}
+		queue := children[detail.Pid]
+		for len(queue) > 0 {
+			child := queue[0]
+			queue = queue[1:]
+			if visited[child.Pid] {
+				continue
+			}
+			visited[child.Pid] = true
+			results = append(results, newAgentProcess(child, WorkerChild, commandID, detail.Pid))
+			queue = append(queue, children[child.Pid]...)
+		}
+	}
+	return results
+}
+
+func newAgentProcess(detail processDetail, kind ProcessKind, commandID string, workerPid int) AgentProcess {
+	return AgentProcess{
+		Pid:         detail.Pid,
+		PPid:        detail.PPid,
+		Kind:        kind,
+		CommandID:   commandID,
+		WorkerPid:   workerPid,
+		Age:         detail.Age,
+		CommandLine: detail.CommandLine,
+	}
+}
+
+// workerKind returns the kind of the worker started from the executable, empty when it is no worker
+func workerKind(executable string) ProcessKind {
+	name := strings.TrimSuffix(strings.ToLower(filepath.Base(executable)), ".exe")
+	switch name {
+	case appconfig.SSMDocumentWorkerName:
+		return DocumentWorker
+	case appconfig.SSMSessionWorkerName:
+		return SessionWorker
+	default:
+		return ""
+	}
+}
+
+// splitCommandLine splits the command line into arguments, a quoted executable path may contain spaces
+func splitCommandLine(commandLine string) []string {
+	commandLine = strings.TrimSpace(commandLine)
+	if strings.HasPrefix(commandLine, `"`) {
+		if end := strings.Index(commandLine[1:], `"`); end >= 0 {
+			return append([]string{commandLine[1 : end+1]}, strings.Fields(commandLine[end+2:])...)
+		}
+	}
+	return strings.Fields(commandLine)
+}
+
+// parseProcessDetailsPs parses the output of ps with the columns pid, ppid, etime and args
+func parseProcessDetailsPs(output []byte) []processDetail {
+	var results []processDetail
+	procList := strings.Split(string(output), "\n")
+	for i := 1; i < len(procList); i++ {
+		parts := strings.Fields(procList[i])
+		if len(parts) < 4 {
+			continue
+		}
+		pid, err := strconv.Atoi(parts[0])
+		if err != nil {
+			continue
+		}
+		ppid, err := strconv.Atoi(parts[1])
+		if err != nil {
+			continue
+		}
+		age, err := parseElapsedTime(parts[2])
+		if err != nil {
+			continue
+		}
+		results = append(results, processDetail{Pid: pid, PPid: ppid, Age: age, CommandLine: strings.Join(parts[3:], " ")})
+	}
+	return results
+}
+
+// parseElapsedTime parses the elapsed time of a process reported by ps in the format [[dd-]hh:]mm:ss
+func parseElapsedTime(elapsed string) (time.Duration, error) {
+	days := 0
+	if index := strings.Index(elapsed, "-"); index >= 0 {
+		var err error
+		if days, err = strconv.Atoi(elapsed[:index]); err != nil {
+			return 0, fmt.Errorf("invalid elapsed time %v", elapsed)
+		}
+		elapsed = elapsed[index+1:]
+	}
+
+	parts := strings.Split(elapsed, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid elapsed time %v", elapsed)
+	}
+	seconds := 0
+	for _, part := range parts {
+		value, err := strconv.Atoi(part)
+		if err != nil {
+			return 0, fmt.Errorf("invalid elapsed time %v", elapsed)
+		}
+		seconds = seconds*60 + value
+	}
+	return time.Duration(days*24*3600+seconds) * time.Second, nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2024 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License"). You may not
+// use this file except in compliance with the License. A copy of the
+// License is located at
+//
+// http://aws.amazon.com/apache2.0/
+//
+// or in the "license" file accompanying this file. This file is distributed
+// on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND,
+// either express or implied. See the License for the specific language governing
+// permissions and limitations under the License.
+
+package executor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-ssm-agent/agent/mocks/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAgentProcesses(t *testing.T) {
+	details := []processDetail{
+		{Pid: 1, PPid: 0, Age: time.Hour, CommandLine: "/sbin/init"},
+		{Pid: 10, PPid: 1, Age: time.Hour, CommandLine: "/usr/bin/amazon-ssm-agent"},
+		{Pid: 11, PPid: 10, Age: time.Hour, CommandLine: "/usr/bin/ssm-agent-worker"},
+		{Pid: 20, PPid: 11, Age: time.Minute, CommandLine: "/usr/bin/ssm-document-worker 0123-abcd"},
+		{Pid: 21, PPid: 20, Age: time.Minute, CommandLine: "sh -c /var/lib/amazon/ssm/_script.sh"},
+		{Pid: 22, PPid: 21, Age: time.Minute, CommandLine: "sleep 600"},
+		{Pid: 30, PPid: 1, Age: 2 * time.Hour, CommandLine: "/usr/bin/ssm-session-worker user-0a1b2c instance-id"},
+		{Pid: 31, PPid: 30, Age: 2 * time.Hour, CommandLine: "/usr/bin/ssm-session-logger"},
+		{Pid: 40, PPid: 1, Age: time.Hour, CommandLine: "sleep 600"},
+	}
+
+	processes := agentProcesses(details)
+	assert.Equal(t, []AgentProcess{
+		{Pid: 20, PPid: 11, Kind: DocumentWorker, CommandID: "0123-abcd", WorkerPid: 20, Age: time.Minute, CommandLine: "/usr/bin/ssm-document-worker 0123-abcd"},
+		{Pid: 21, PPid: 20, Kind: WorkerChild, CommandID: "0123-abcd", WorkerPid: 20, Age: time.Minute, CommandLine: "sh -c /var/lib/amazon/ssm/_script.sh"},
+		{Pid: 22, PPid: 21, Kind: WorkerChild, CommandID: "0123-abcd", WorkerPid: 20, Age: time.Minute, CommandLine: "sleep 600"},
+		{Pid: 30, PPid: 1, Kind: SessionWorker, CommandID: "user-0a1b2c", WorkerPid: 30, Age: 2 * time.Hour, CommandLine: "/usr/bin/ssm-session-worker user-0a1b2c instance-id"},
+		{Pid: 31, PPid: 30, Kind: WorkerChild, CommandID: "user-0a1b2c", WorkerPid: 30, Age: 2 * time.Hour, CommandLine: "/usr/bin/ssm-session-logger"},
+	}, processes)
+}
+
+func TestAgentProcesses_PidCycle(t *testing.T) {
+	details := []processDetail{
+		{Pid: 20, PPid: 21, CommandLine: "ssm-document-worker 0123-abcd"},
+		{Pid: 21, PPid: 20, CommandLine: "sh"},
+	}
+
+	processes := agentProcesses(details)
+	assert.Len(t, processes, 2)
+}
+
+func TestSplitCommandLine(t *testing.T) {
+	assert.Equal(t, []string{`C:\Program Files\Amazon\SSM\ssm-document-worker.exe`, "0123-abcd"},
+		splitCommandLine(`"C:\Program Files\Amazon\SSM\ssm-document-worker.exe" 0123-abcd`))
+	assert.Equal(t, []string{"/usr/bin/ssm-document-worker", "0123-abcd"}, splitCommandLine(" /usr/bin/ssm-document-worker  0123-abcd "))
+	assert.Empty(t, splitCommandLine(""))
+}
+
+func TestWorkerKind(t *testing.T) {
+	assert.Equal(t, DocumentWorker, workerKind("/usr/bin/ssm-document-worker"))
+	assert.Equal(t, SessionWorker, workerKind("ssm-session-worker"))
+	assert.Equal(t, ProcessKind(""), workerKind("/usr/bin/ssm-agent-worker"))
+}
+
+func TestParseElapsedTime(t *testing.T) {
+	testCases := []struct {
+		elapsed  string
+		expected time.Duration
+	}{
+		{"00:05", 5 * time.Second},
+		{"12:34", 12*time.Minute + 34*time.Second},
+		{"01:02:03", time.Hour + 2*time.Minute + 3*time.Second},
+		{"2-01:00:00", 49 * time.Hour},
+	}
+	for _, testCase := range testCases {
+		age, err := parseElapsedTime(testCase.elapsed)
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.expected, age, testCase.elapsed)
+	}
+
+	for _, invalid := range []string{"", "5", "a:b", "x-01:00:00", "1:2:3:4"} {
+		_, err := parseElapsedTime(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestParseProcessDetailsPs(t *testing.T) {
+	output := []byte("  PID  PPID     ELAPSED COMMAND\n" +
+		"    1     0  3-00:00:00 /sbin/init splash\n" +
+		"  420     1       01:30 /usr/bin/ssm-document-worker 0123-abcd\n" +
+		"  bad     1       01:30 ignored\n")
+
+	details := parseProcessDetailsPs(output)
+	assert.Equal(t, []processDetail{
+		{Pid: 1, PPid: 0, Age: 72 * time.Hour, CommandLine: "/sbin/init splash"},
+		{Pid: 420, PPid: 1, Age: 90 * time.Second, CommandLine: "/usr/bin/ssm-document-worker 0123-abcd"},
+	}, details)
+}
+
+func TestProcessExecutorAgentProcesses(t *testing.T) {
+	oldGetProcessDetails := getProcessDetails
+	getProcessDetails = func() ([]processDetail, error) {
+		return []processDetail{{Pid: 20, PPid: 1, CommandLine: "ssm-session-worker session-id"}}, nil
+	}
+	defer func() { getProcessDetails = oldGetProcessDetails }()
+
+	processes, err := NewProcessExecutor(log.NewMockLog()).AgentProcesses()
+	assert.NoError(t, err)
+	assert.Len(t, processes, 1)
+	assert.Equal(t, SessionWorker, processes[0].Kind)
+	assert.Equal(t, "session-id", processes[0].CommandID)
+}
@@ -37,6 +37,7 @@ type IExecutor interface {
 	Processes() ([]OsProcess, error)
 	IsPidRunning(pid int) (bool, error)
 	Kill(pid int) error
+	AgentProcesses() ([]AgentProcess, error)
 }
 
 // ProcessExecutor is specially added for testing purposes
@@ -103,6 +104,15 @@ func (exc *ProcessExecutor) IsPidRunning(pid int) (bool, error) {
 	return false, nil
 }
 
+// AgentProcesses returns the document and session workers running on the instance, each followed by its descendants
+func (exc *ProcessExecutor) AgentProcesses() ([]AgentProcess, error) {
+	details, err := getProcessDetails()
+	if err != nil {
+		return nil, err
+	}
+	return agentProcesses(details), nil
+}
+
 func (exc *ProcessExecutor) Kill(pid int) error {
 	osProcess, err := os.FindProcess(pid)
 	if err != nil {
@@ -108,3 +108,16 @@ var getProcess = func() ([]OsProcess, error) {
 	}
 	return results, nil
 }
+
+// listProcessDetailsPs returns the elapsed time and the complete command line of every process
+var listProcessDetailsPs = func() ([]byte, error) {
+	return exec.Command("ps", "-e", "-o", "pid,ppid,etime,args").CombinedOutput()
+}
+
+var getProcessDetails = func() ([]processDetail, error) {
+	output, err := listProcessDetailsPs()
+	if err != nil {
+		return nil, err
+	}
+	return parseProcessDetailsPs(output), nil
+}
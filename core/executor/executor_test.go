@@ -15,6 +15,7 @@ package executor
 
 import (
 	"fmt"
+	"os"
 	"testing"
 
 	"github.com/aws/amazon-ssm-agent/agent/mocks/log"
@@ -22,7 +23,7 @@ import (
 )
 
 func TestMain(m *testing.M) {
-
+	os.Exit(m.Run())
 }
 
 func TestIsPidRunning(t *testing.T) {
@@ -18,6 +18,7 @@ package executor
 
 import (
 	"bytes"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"os/exec"
@@ -25,6 +26,7 @@ import (
 	"strconv"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/aws/amazon-ssm-agent/agent/appconfig"
 )
@@ -193,3 +195,95 @@ var getProcess = func() ([]OsProcess, error) {
 	}
 	return results, nil
 }
+
+// listProcessDetailsPs returns the elapsed time and the complete command line of every process
+var listProcessDetailsPs = func() ([]byte, error) {
+	return exec.Command("ps", "-e", "-o", "pid,ppid,etime,args").CombinedOutput()
+}
+
+// clockTicksPerSecond is the unit of the process start time in /proc/<pid>/stat, USER_HZ is 100 on all supported kernels
+const clockTicksPerSecond = 100
+
+// listProcessDetailsProc is a fallback function for when listProcessDetailsPs fails, it reads the /proc folder for process details
+var listProcessDetailsProc = func() ([]processDetail, error) {
+	var procFolder = "/proc"
+	var results []processDetail
+
+	bootTime, err := readBootTime(path.Join(procFolder, "stat"))
+	if err != nil {
+		return results, err
+	}
+	uptime := time.Since(bootTime)
+
+	files, err := ioutil.ReadDir(procFolder)
+	if err != nil {
+		return results, err
+	}
+
+	for _, f := range files {
+		pid, err := strconv.Atoi(f.Name())
+		if !f.IsDir() || err != nil {
+			continue
+		}
+		cmd, err := ioutil.ReadFile(path.Join(procFolder, f.Name(), "cmdline"))
+		if err != nil || len(cmd) == 0 {
+			continue
+		}
+		stat, err := ioutil.ReadFile(path.Join(procFolder, f.Name(), "stat"))
+		if err != nil {
+			continue
+		}
+
+		// the command name in parentheses may contain spaces, the fields after it are space separated
+		statString := string(stat)
+		fields := strings.Fields(statString[strings.LastIndex(statString, ")")+1:])
+		// fields start at the state, the parent pid is the second field and the start time the twentieth
+		if len(fields) < 20 {
+			continue
+		}
+		ppid, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue
+		}
+		startTicks, err := strconv.ParseInt(fields[19], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		results = append(results, processDetail{
+			Pid:         pid,
+			PPid:        ppid,
+			Age:         uptime - time.Duration(startTicks)*time.Second/clockTicksPerSecond,
+			CommandLine: strings.TrimSpace(string(bytes.ReplaceAll(cmd, []byte{0}, []byte{' '}))),
+		})
+	}
+	return results, nil
+}
+
+// readBootTime reads the boot time from the btime line of /proc/stat
+func readBootTime(statPath string) (time.Time, error) {
+	content, err := ioutil.ReadFile(statPath)
+	if err != nil {
+		return time.Time{}, err
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "btime" {
+			seconds, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return time.Time{}, err
+			}
+			return time.Unix(seconds, 0), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("boot time not found in %v", statPath)
+}
+
+var getProcessDetails = func() ([]processDetail, error) {
+	output, err := listProcessDetailsPs()
+	if err != nil {
+		// Default to Proc if ps fails
+		return listProcessDetailsProc()
+	}
+	return parseProcessDetailsPs(output), nil
+}
@@ -22,6 +22,7 @@ import (
 	"os"
 	"os/exec"
 	"testing"
+	"time"
 
 	"github.com/aws/amazon-ssm-agent/agent/mocks/log"
 	"github.com/aws/amazon-ssm-agent/core/workerprovider/longrunningprovider/model"
@@ -112,3 +113,32 @@ func TestKillProcess(t *testing.T) {
 	err = executor.Kill(process.Pid)
 	assert.Nil(t, err)
 }
+
+func TestGetProcessDetails(t *testing.T) {
+	cmd := exec.Command("sleep", "5")
+	assert.NoError(t, cmd.Start())
+	defer cmd.Wait()
+	defer cmd.Process.Kill()
+	pid := cmd.Process.Pid
+
+	oldListProcessDetailsPs := listProcessDetailsPs
+	defer func() { listProcessDetailsPs = oldListProcessDetailsPs }()
+	for _, psFails := range []bool{false, true} {
+		if psFails {
+			listProcessDetailsPs = func() ([]byte, error) {
+				return nil, fmt.Errorf("SomeRandomError")
+			}
+		}
+		// the child only reports "sleep 5" as command line once it has exec'd
+		assert.Eventually(t, func() bool {
+			details, err := getProcessDetails()
+			assert.NoError(t, err)
+			for _, detail := range details {
+				if detail.Pid == pid && detail.PPid == os.Getpid() && detail.CommandLine == "sleep 5" {
+					return detail.Age >= 0 && detail.Age < time.Minute
+				}
+			}
+			return false
+		}, 3*time.Second, 10*time.Millisecond, "ps fails: %v", psFails)
+	}
+}
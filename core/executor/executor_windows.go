@@ -19,7 +19,11 @@ package executor
 import (
 	"os"
 	"os/exec"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/aws/amazon-ssm-agent/agent/appconfig"
 	ps "github.com/mitchellh/go-ps"
 )
 
@@ -60,3 +64,42 @@ var getProcess = func() ([]OsProcess, error) {
 
 	return results, nil
 }
+
+// getProcessDetailsCmd prints one line per process with the pid, parent pid, age in seconds and command line separated by |,
+// the command line is last as it may contain the separator
+const getProcessDetailsCmd = "Get-CimInstance Win32_Process | ForEach-Object { " +
+	"$age = 0; if ($_.CreationDate) { $age = [int64]((Get-Date) - $_.CreationDate).TotalSeconds }; " +
+	"'{0}|{1}|{2}|{3}' -f $_.ProcessId, $_.ParentProcessId, $age, $_.CommandLine }"
+
+var listProcessDetails = func() ([]byte, error) {
+	return exec.Command(appconfig.PowerShellPluginCommandName, "-NoProfile", "-NonInteractive", "-Command", getProcessDetailsCmd).Output()
+}
+
+var getProcessDetails = func() ([]processDetail, error) {
+	output, err := listProcessDetails()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []processDetail
+	for _, line := range strings.Split(string(output), "\n") {
+		parts := strings.SplitN(strings.TrimSpace(line), "|", 4)
+		if len(parts) < 4 {
+			continue
+		}
+		pid, err := strconv.Atoi(parts[0])
+		if err != nil {
+			continue
+		}
+		ppid, err := strconv.Atoi(parts[1])
+		if err != nil {
+			continue
+		}
+		seconds, err := strconv.ParseInt(parts[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		results = append(results, processDetail{Pid: pid, PPid: ppid, Age: time.Duration(seconds) * time.Second, CommandLine: parts[3]})
+	}
+	return results, nil
+}
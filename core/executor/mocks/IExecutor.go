@@ -13,6 +13,29 @@ type IExecutor struct {
 	mock.Mock
 }
 
+// AgentProcesses provides a mock function with given fields:
+func (_m *IExecutor) AgentProcesses() ([]executor.AgentProcess, error) {
+	ret := _m.Called()
+
+	var r0 []executor.AgentProcess
+	if rf, ok := ret.Get(0).(func() []executor.AgentProcess); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]executor.AgentProcess)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // IsPidRunning provides a mock function with given fields: pid
 func (_m *IExecutor) IsPidRunning(pid int) (bool, error) {
 	ret := _m.Called(pid)
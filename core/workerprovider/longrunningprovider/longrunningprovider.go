@@ -152,7 +152,10 @@ func (container *WorkerContainer) Stop(stopType reboot.StopType) {
 	}
 
 	container.messageBus.Stop()
-	sleep(reboot.HardStopTimeout)
+
+	// workers drain their in-flight work before stopping, give them the configured drain period on top of the hard stop timeout
+	drainPeriod := time.Duration(container.context.AppConfig().Shutdown.DrainPeriodSeconds) * time.Second
+	sleep(reboot.HardStopTimeout + drainPeriod)
 
 	// If agent parent is 0, force terminate and clean up all worker processes
 	if getPpid() == 0 {
@@ -170,6 +170,23 @@ func (suite *LongRunningProviderTestSuite) TestStopWorker_ParentProcessZero() {
 
 }
 
+func (suite *LongRunningProviderTestSuite) TestStopWorker_WaitsForDrainPeriod() {
+	getPpid = func() int {
+		return 1
+	}
+	var slept time.Duration
+	sleep = func(duration time.Duration) { slept = duration }
+	suite.appConfig.Shutdown.DrainPeriodSeconds = 30
+
+	suite.messageBus.On("SendSurveyMessage", mock.Anything).Return([]*message.Message{}, nil).Once()
+	suite.messageBus.On("Stop").Return().Once()
+
+	suite.container.Stop(reboot.StopTypeHardStop)
+
+	assert.Equal(suite.T(), reboot.HardStopTimeout+30*time.Second, slept)
+	assert.True(suite.T(), <-suite.container.stopWorkerMonitor)
+}
+
 func createStandardSSMAgentWorkers() map[string]*model.WorkerConfig {
 	worker := model.WorkerConfig{
 		Name: model.SSMAgentWorkerName,
@@ -8,6 +8,7 @@ replace github.com/nightlyone/lockfile => ./extra/lockfile
 
 require (
 	github.com/Jeffail/gabs v1.0.0
+	github.com/ProtonMail/go-crypto v1.0.0
 	github.com/Workiva/go-datastructures v1.0.53
 	github.com/aws/aws-sdk-go v1.51.20
 	github.com/carlescere/scheduler v0.0.0-20150615230211-9b78eac89dfb
@@ -43,7 +44,6 @@ require (
 require (
 	dario.cat/mergo v1.0.0 // indirect
 	github.com/Microsoft/go-winio v0.6.1 // indirect
-	github.com/ProtonMail/go-crypto v1.0.0 // indirect
 	github.com/cloudflare/circl v1.3.7 // indirect
 	github.com/cyphar/filepath-securejoin v0.2.4 // indirect
 	github.com/davecgh/go-spew v1.1.1 // indirect